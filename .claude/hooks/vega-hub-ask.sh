@@ -3,7 +3,7 @@
 # vega-hub-ask.sh - PreToolUse hook for AskUserQuestion
 #
 # This hook intercepts AskUserQuestion tool calls and routes them to vega-hub.
-# It blocks until a human answers via the vega-hub web UI.
+# It waits until a human answers via the vega-hub web UI.
 #
 # Input: JSON from Claude Code PreToolUse hook (via stdin)
 # Output: JSON with permissionDecision: deny and answer in permissionDecisionReason
@@ -54,19 +54,29 @@ REQUEST=$(jq -n \
         goal_id: $goal_id,
         session_id: $session_id,
         question: $question,
-        options: $options
+        options: $options,
+        max_wait: 240
     }')
 
-# POST to vega-hub (blocks until answered)
-RESPONSE=$(curl -s -X POST \
-    -H "Content-Type: application/json" \
-    -d "$REQUEST" \
-    "http://${VEGA_HUB_HOST}:${VEGA_HUB_PORT}/api/ask" \
-    2>/dev/null) || {
-    # vega-hub not available, let tool proceed normally
-    echo "Warning: vega-hub not available at $VEGA_HUB_HOST:$VEGA_HUB_PORT" >&2
-    exit 0
-}
+# POST to vega-hub. It answers within max_wait seconds, so proxies don't cut
+# the request; an unanswered question comes back pending with its ID, and
+# asking again with that ID keeps waiting for the same question.
+while true; do
+    RESPONSE=$(curl -s -X POST \
+        -H "Content-Type: application/json" \
+        -d "$REQUEST" \
+        "http://${VEGA_HUB_HOST}:${VEGA_HUB_PORT}/api/ask" \
+        2>/dev/null) || {
+        # vega-hub not available, let tool proceed normally
+        echo "Warning: vega-hub not available at $VEGA_HUB_HOST:$VEGA_HUB_PORT" >&2
+        exit 0
+    }
+    QUESTION_ID=$(echo "$RESPONSE" | jq -r 'select(.pending == true) | .question_id // empty' 2>/dev/null || true)
+    if [[ -z "$QUESTION_ID" ]]; then
+        break
+    fi
+    REQUEST=$(echo "$REQUEST" | jq -c --arg id "$QUESTION_ID" '. + {question_id: $id}')
+done
 
 # Extract answer
 ANSWER=$(echo "$RESPONSE" | jq -r '.answer // empty')
@@ -0,0 +1,102 @@
+// Command vega-hub-bench load tests the hub API. It generates a temporary
+// vega-missile directory with the requested number of goals and history,
+// serves the API from it in-process, and reports latencies for the goal
+// list, detail and chat paths and the ask/answer round trip while simulated
+// executors and SSE clients are connected.
+//
+//	vega-hub-bench -goals 500 -executors 20 -sse 50 -duration 30s
+//
+// With -max-p95 it exits non-zero when any path's p95 latency exceeds the
+// limit, so it can guard against regressions in CI.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/api"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/loadtest"
+)
+
+func main() {
+	numGoals := flag.Int("goals", 200, "goals in the generated vega directory")
+	history := flag.Int("history", 200, "session history entries per goal")
+	cfg := loadtest.Config{}
+	flag.IntVar(&cfg.Executors, "executors", 10, "simulated executors asking questions")
+	flag.IntVar(&cfg.SSEClients, "sse", 20, "SSE clients subscribed to /api/events")
+	flag.IntVar(&cfg.Readers, "readers", 8, "clients polling the goal list, detail and chat")
+	flag.DurationVar(&cfg.Duration, "duration", 10*time.Second, "how long to apply load")
+	flag.IntVar(&cfg.Cycles, "cycles", 0, "questions each executor asks before the run ends (0 runs for -duration, which is then a timeout)")
+	maxP95 := flag.Duration("max-p95", 0, "fail when any path's p95 latency exceeds this (0 disables)")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	keep := flag.Bool("keep", false, "keep the generated vega directory")
+	flag.Parse()
+
+	dir, err := os.MkdirTemp("", "vega-hub-bench-")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *keep {
+		fmt.Fprintf(os.Stderr, "Vega directory: %s\n", dir)
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	fmt.Fprintf(os.Stderr, "Generating %d goals with %d history entries each...\n", *numGoals, *history)
+	goalIDs, err := loadtest.WriteFixture(dir, *numGoals, *history)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep the hub's own logging out of the report
+	log.SetOutput(io.Discard)
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux, hub.New(dir), goals.NewParser(dir))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fmt.Fprintf(os.Stderr, "Running %d executors, %d SSE clients and %d readers for %s...\n",
+		cfg.Executors, cfg.SSEClients, cfg.Readers, cfg.Duration)
+	report, err := loadtest.Run(context.Background(), server.URL, goalIDs, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		report.Print(os.Stdout)
+	}
+
+	failed := false
+	for _, p := range report.Paths {
+		if p.Errors > 0 {
+			fmt.Fprintf(os.Stderr, "%s: %d failed request(s)\n", p.Path, p.Errors)
+			failed = true
+		}
+		if *maxP95 > 0 && p.P95 > *maxP95 {
+			fmt.Fprintf(os.Stderr, "%s: p95 %s exceeds %s\n", p.Path, p.P95, *maxP95)
+			failed = true
+		}
+	}
+	if failed {
+		server.Close()
+		if !*keep {
+			os.RemoveAll(dir)
+		}
+		os.Exit(1)
+	}
+}
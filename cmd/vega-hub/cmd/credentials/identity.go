@@ -0,0 +1,153 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+var (
+	identityUser   string
+	identityName   string
+	identityEmail  string
+	identitySSHKey string
+)
+
+var identityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Show the git identity your executors use",
+	Long: `Show, set or remove the git identity executors spawned by a user commit
+and push with. Spawned executors get GIT_AUTHOR_*/GIT_COMMITTER_* set, and the
+goal worktree gets user.name, user.email and core.sshCommand in its own config.
+Without an identity, executors use the server's global git config.
+
+Examples:
+  vega-hub credentials identity
+  vega-hub credentials identity set --name "Alex Doe" --email alex@example.com
+  vega-hub credentials identity set --name "Alex Doe" --email alex@example.com --ssh-key ~/.ssh/id_work
+  vega-hub credentials identity unset`,
+	Args: cobra.NoArgs,
+	Run:  runIdentityShow,
+}
+
+var identitySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the git identity your executors use",
+	Args:  cobra.NoArgs,
+	Run:   runIdentitySet,
+}
+
+var identityUnsetCmd = &cobra.Command{
+	Use:   "unset",
+	Short: "Remove your git identity (executors fall back to the global git config)",
+	Args:  cobra.NoArgs,
+	Run:   runIdentityUnset,
+}
+
+func init() {
+	CredentialsCmd.AddCommand(identityCmd)
+	identityCmd.AddCommand(identitySetCmd)
+	identityCmd.AddCommand(identityUnsetCmd)
+	identityCmd.PersistentFlags().StringVar(&identityUser, "user", "", "User to manage (default: current user)")
+	identitySetCmd.Flags().StringVar(&identityName, "name", "", "Git author name (required)")
+	identitySetCmd.Flags().StringVar(&identityEmail, "email", "", "Git author email (required)")
+	identitySetCmd.Flags().StringVar(&identitySSHKey, "ssh-key", "", "Private SSH key to push with")
+	identitySetCmd.MarkFlagRequired("name")
+	identitySetCmd.MarkFlagRequired("email")
+}
+
+// identityStore returns the store and the user to manage
+func identityStore() (*credentials.IdentityStore, string) {
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+
+	user := identityUser
+	if user == "" {
+		u, err := credentials.GetCurrentUser()
+		if err != nil {
+			cli.OutputError(cli.ExitInternalError, "user_detection_failed",
+				"Failed to detect current user",
+				map[string]string{"error": err.Error()},
+				nil)
+		}
+		user = u.Username
+	}
+	return credentials.NewIdentityStore(vegaDir), user
+}
+
+func runIdentityShow(c *cobra.Command, args []string) {
+	store, user := identityStore()
+	identity, err := store.Get(user)
+	if err != nil {
+		if errors.Is(err, credentials.ErrNoIdentity) {
+			cli.OutputError(cli.ExitNotFound, "no_identity",
+				fmt.Sprintf("No git identity configured for %s", user),
+				map[string]string{"user": user},
+				[]cli.ErrorOption{
+					{Action: "set", Description: "Run: vega-hub credentials identity set --name <name> --email <email>"},
+				})
+		}
+		cli.OutputError(cli.ExitInternalError, "identity_read_failed", err.Error(), nil, nil)
+	}
+
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "credentials_identity",
+		Message: fmt.Sprintf("%s commits as %s <%s>", user, identity.Name, identity.Email),
+		Data:    identity,
+	})
+	if !cli.JSONOutput && identity.SSHKey != "" {
+		fmt.Printf("  SSH key: %s\n", identity.SSHKey)
+	}
+}
+
+func runIdentitySet(c *cobra.Command, args []string) {
+	store, user := identityStore()
+
+	sshKey := identitySSHKey
+	if sshKey != "" {
+		abs, err := filepath.Abs(sshKey)
+		if err != nil {
+			cli.OutputError(cli.ExitValidationError, "invalid_ssh_key", err.Error(), nil, nil)
+		}
+		sshKey = abs
+	}
+
+	identity := &credentials.Identity{User: user, Name: identityName, Email: identityEmail, SSHKey: sshKey}
+	if err := store.Set(identity); err != nil {
+		cli.OutputError(cli.ExitValidationError, "invalid_identity", err.Error(),
+			map[string]string{"user": user}, nil)
+	}
+
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "credentials_identity_set",
+		Message: fmt.Sprintf("Executors spawned by %s will commit as %s <%s>", user, identity.Name, identity.Email),
+		Data:    identity,
+	})
+}
+
+func runIdentityUnset(c *cobra.Command, args []string) {
+	store, user := identityStore()
+	if err := store.Delete(user); err != nil {
+		code := cli.ExitInternalError
+		if errors.Is(err, credentials.ErrNoIdentity) {
+			code = cli.ExitNotFound
+		}
+		cli.OutputError(code, "identity_unset_failed", err.Error(), map[string]string{"user": user}, nil)
+	}
+
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "credentials_identity_unset",
+		Message: fmt.Sprintf("Removed git identity for %s", user),
+	})
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment and vega-missile directory",
+	Long: `Check that everything vega-hub depends on is in place, and suggest a
+fix for every problem found.
+
+Checks:
+  - git is installed and recent enough (2.25+)
+  - gh / glab are installed and logged in for projects hosted on GitHub / GitLab
+  - The directory has the expected structure (goals/, projects/, workspaces/)
+  - goals/registry.jsonl matches the goal files
+  - A port is free for vega-hub (or the running hub answers)
+  - The project-init hook templates exist and are executable
+  - No stale locks are left behind
+
+Exits 0 when nothing needs fixing or only warnings were found, and 2 when
+a check failed.
+
+Example:
+  vega-hub doctor
+  vega-hub doctor --dir /path/to/vega-missile --json`,
+	Run: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	// A missing directory is one of the things doctor reports on
+	dir, _ := cli.GetVegaDir()
+	report := doctor.Run(doctor.Options{VegaDir: dir})
+
+	if !cli.JSONOutput {
+		for _, check := range report.Checks {
+			icon := "✓"
+			if check.Status == doctor.StatusWarn {
+				icon = "⚠"
+			} else if check.Status == doctor.StatusFail {
+				icon = "✗"
+			}
+			fmt.Printf("%s %s: %s\n", icon, check.Name, check.Message)
+			if check.Status != doctor.StatusOK {
+				for _, detail := range check.Details {
+					fmt.Printf("    %s\n", detail)
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	problems := report.Problems()
+	if len(problems) == 0 {
+		cli.OutputSuccess("doctor", "No problems found", report)
+		return
+	}
+
+	result := cli.Result{
+		Success: report.Status != doctor.StatusFail,
+		Action:  "doctor",
+		Message: fmt.Sprintf("%d check(s) need attention", len(problems)),
+		Data:    report,
+	}
+	if report.Status == doctor.StatusFail {
+		result.Error = &cli.ErrorInfo{
+			Code:    "doctor_failed",
+			Message: result.Message,
+			Details: map[string]string{},
+		}
+		seen := map[doctor.Fix]bool{}
+		for _, check := range problems {
+			result.Error.Details[check.Name] = check.Message
+			for _, fix := range check.Fixes {
+				if !seen[fix] {
+					seen[fix] = true
+					result.Error.Options = append(result.Error.Options, fixOption(fix))
+				}
+			}
+		}
+		cli.Output(result)
+		os.Exit(cli.ExitStateError)
+	}
+
+	for _, check := range problems {
+		for _, fix := range check.Fixes {
+			if fix.Command != "" {
+				result.NextSteps = append(result.NextSteps, fmt.Sprintf("%s  (%s)", fix.Command, fix.Description))
+			} else {
+				result.NextSteps = append(result.NextSteps, fix.Description)
+			}
+		}
+	}
+	cli.Output(result)
+}
+
+// fixOption turns a doctor fix into a Result error option
+func fixOption(fix doctor.Fix) cli.ErrorOption {
+	if fix.Command != "" {
+		return cli.ErrorOption{Action: fix.Command, Description: fix.Description}
+	}
+	return cli.ErrorOption{Action: "manual", Description: fix.Description}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +21,13 @@ var (
 	spawnMode    string
 	spawnMeta    bool
 	spawnProject string
+	spawnWorker  string
+	spawnResume  string
+
+	spawnContainer       bool
+	spawnContainerImage  string
+	spawnContainerCPUs   string
+	spawnContainerMemory string
 )
 
 // ValidModes defines the allowed executor modes
@@ -41,6 +49,16 @@ type SpawnResult struct {
 	Message      string `json:"message"`
 	User         string `json:"user,omitempty"`         // Username who spawned this executor
 	ExecutorType string `json:"executor_type,omitempty"` // "meta" or "project"
+	Worker       string `json:"worker,omitempty"`        // Remote worker the executor runs on
+	Container    string `json:"container,omitempty"`     // Container name for containerized executors
+	ResumedFrom  string `json:"resumed_from,omitempty"`  // Session whose Claude session the executor continues
+}
+
+// ContainerOptions configures a containerized executor
+type ContainerOptions struct {
+	Image  string `json:"image,omitempty"`
+	CPUs   string `json:"cpus,omitempty"`
+	Memory string `json:"memory,omitempty"`
 }
 
 // SpawnRequest is the request body for the spawn API
@@ -50,6 +68,10 @@ type SpawnRequest struct {
 	Mode    string `json:"mode,omitempty"`    // Executor mode: plan, implement, review, test, security, quick
 	Meta    bool   `json:"meta,omitempty"`    // If true, spawn as meta-executor in goal folder
 	Project string `json:"project,omitempty"` // Project name for project executor
+	Worker  string `json:"worker,omitempty"`  // Remote worker to run the executor on
+	Resume  string `json:"resume,omitempty"`  // Session to continue: a session ID or "last"
+
+	Container *ContainerOptions `json:"container,omitempty"` // Run in a container if set
 }
 
 // SpawnResponse is the response from the spawn API
@@ -61,6 +83,9 @@ type SpawnResponse struct {
 	SessionID    string `json:"session_id,omitempty"`
 	User         string `json:"user,omitempty"`
 	ExecutorType string `json:"executor_type,omitempty"` // "meta" or "project"
+	Worker       string `json:"worker,omitempty"`
+	Container    string `json:"container,omitempty"`
+	ResumedFrom  string `json:"resumed_from,omitempty"`
 	Error        string `json:"error,omitempty"`
 }
 
@@ -84,6 +109,19 @@ Examples:
   vega-hub executor spawn f3a8b2c --project my-api --mode plan
   vega-hub executor spawn f3a8b2c --meta --prompt "Orchestrate this multi-project goal"
 
+  # Spawn on a remote worker over SSH (see .vega-hub-workers.json)
+  vega-hub executor spawn f3a8b2c --project my-api --worker build-box
+
+  # Continue the last session's Claude conversation (claude --resume)
+  vega-hub executor spawn f3a8b2c --project my-api --resume
+  vega-hub executor spawn f3a8b2c --project my-api --resume=<session-id>
+
+  # Spawn isolated in a Docker/Podman container with resource limits
+  vega-hub executor spawn f3a8b2c --project my-api --container --cpus 2 --memory 4g
+
+Container image and limits default to the project's settings in
+projects/<name>.md (**Container Image**, **Container CPUs**, **Container Memory**).
+
 Available modes:
   plan      - Create implementation plan (task_plan.md, findings.md)
   implement - Write code and tests (default behavior)
@@ -98,6 +136,8 @@ Environment variables set for executor:
   VEGA_GOAL_ID        - The goal ID
   VEGA_PROJECT        - Project name (project executors only)
   VEGA_HUB_PORT       - Port for vega-hub communication
+  VEGA_HUB_HOST       - Host for vega-hub communication (remote workers only)
+  VEGA_WORKER         - Worker name (remote workers only)
 
 The executor will:
   1. Start in the goal folder (meta) or worktree (project)
@@ -117,6 +157,13 @@ func init() {
 	spawnCmd.Flags().StringVarP(&spawnMode, "mode", "m", "", "Executor mode: plan, implement, review, test, security, quick")
 	spawnCmd.Flags().BoolVar(&spawnMeta, "meta", false, "Spawn as meta-executor in goal folder (not worktree)")
 	spawnCmd.Flags().StringVar(&spawnProject, "project", "", "Project name for project executor (required if not --meta)")
+	spawnCmd.Flags().StringVar(&spawnWorker, "worker", "", "Run the executor on a remote worker over SSH")
+	spawnCmd.Flags().StringVar(&spawnResume, "resume", "", "Continue a stopped session's Claude conversation: --resume=<session-id>, or the last one")
+	spawnCmd.Flags().Lookup("resume").NoOptDefVal = "last"
+	spawnCmd.Flags().BoolVar(&spawnContainer, "container", false, "Run the executor in a Docker/Podman container")
+	spawnCmd.Flags().StringVar(&spawnContainerImage, "image", "", "Container image (implies --container)")
+	spawnCmd.Flags().StringVar(&spawnContainerCPUs, "cpus", "", "Container CPU limit, e.g. 2 (implies --container)")
+	spawnCmd.Flags().StringVar(&spawnContainerMemory, "memory", "", "Container memory limit, e.g. 4g (implies --container)")
 }
 
 func runSpawn(c *cobra.Command, args []string) {
@@ -129,6 +176,7 @@ func runSpawn(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Get vega-hub port
 	port, err := getVegaHubPort(vegaDir)
@@ -174,6 +222,15 @@ func runSpawn(c *cobra.Command, args []string) {
 		Mode:    spawnMode,
 		Meta:    spawnMeta,
 		Project: spawnProject,
+		Worker:  spawnWorker,
+		Resume:  spawnResume,
+	}
+	if spawnContainer || spawnContainerImage != "" || spawnContainerCPUs != "" || spawnContainerMemory != "" {
+		reqBody.Container = &ContainerOptions{
+			Image:  spawnContainerImage,
+			CPUs:   spawnContainerCPUs,
+			Memory: spawnContainerMemory,
+		}
 	}
 	if reqBody.Context == "" {
 		reqBody.Context = "Continue working on your assigned goal."
@@ -252,6 +309,9 @@ func runSpawn(c *cobra.Command, args []string) {
 		Message:      spawnResp.Message,
 		User:         spawnResp.User,
 		ExecutorType: spawnResp.ExecutorType,
+		Worker:       spawnResp.Worker,
+		Container:    spawnResp.Container,
+		ResumedFrom:  spawnResp.ResumedFrom,
 	}
 
 	executorTypeLabel := "project"
@@ -274,6 +334,15 @@ func runSpawn(c *cobra.Command, args []string) {
 	if !cli.JSONOutput {
 		fmt.Printf("\n  Session: %s\n", spawnResp.SessionID)
 		fmt.Printf("  Type: %s\n", spawnResp.ExecutorType)
+		if spawnResp.Worker != "" {
+			fmt.Printf("  Worker: %s\n", spawnResp.Worker)
+		}
+		if spawnResp.Container != "" {
+			fmt.Printf("  Container: %s\n", spawnResp.Container)
+		}
+		if spawnResp.ResumedFrom != "" {
+			fmt.Printf("  Resumed from: %s\n", spawnResp.ResumedFrom)
+		}
 		if spawnResp.Worktree != "" {
 			fmt.Printf("  Worktree: %s\n", spawnResp.Worktree)
 		}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/spf13/cobra"
 )
 
@@ -70,6 +71,7 @@ func runStop(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Get vega-hub port
 	port, err := getVegaHubPort(vegaDir)
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/api"
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a static snapshot of the dashboard",
+	Long: `Write a point-in-time snapshot of the dashboard for archiving or sharing,
+viewable without running vega-hub.
+
+The snapshot holds every goal (with its chat, sessions and timeline), the
+projects and the completed goals history as JSON under <out>/api, and the
+web UI, set up to read those files instead of the API. Serve the directory
+with any static file server, at its root:
+
+  python3 -m http.server -d <out>
+
+The snapshot is read-only: actions in the UI are refused. Questions and
+executors of a running hub are in its memory, not on disk, so they aren't
+included.
+
+Example:
+  vega-hub export --out ./snapshot-2024-06-01
+  vega-hub export --out ./snapshot --force --json`,
+	Run: runExport,
+}
+
+var (
+	exportOut   string
+	exportForce bool
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "Directory to write the snapshot to (required)")
+	exportCmd.Flags().BoolVar(&exportForce, "force", false, "Write into a directory that isn't empty")
+	exportCmd.MarkFlagRequired("out")
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_vega_dir",
+			"No vega-missile directory found",
+			map[string]string{"error": err.Error()},
+			[]cli.ErrorOption{{Flag: "dir", Description: "Point at the vega-missile directory"}})
+	}
+
+	if entries, err := os.ReadDir(exportOut); err == nil && len(entries) > 0 && !exportForce {
+		cli.OutputError(cli.ExitConflict, "out_not_empty",
+			fmt.Sprintf("%s is not empty", exportOut),
+			map[string]string{"out": exportOut},
+			[]cli.ErrorOption{
+				{Flag: "force", Description: "Write into it anyway, replacing files of an earlier snapshot"},
+				{Flag: "out", Description: "Pick a new directory"},
+			})
+	}
+	if err := os.MkdirAll(exportOut, 0755); err != nil {
+		cli.OutputError(cli.ExitInternalError, "export_failed", fmt.Sprintf("Could not create %s: %v", exportOut, err), nil, nil)
+	}
+
+	manifest, err := api.ExportSnapshot(hub.New(vegaDir), goals.NewParser(vegaDir), exportOut)
+	if err != nil {
+		cli.OutputError(cli.ExitInternalError, "export_failed", err.Error(), nil, nil)
+	}
+	if err := exportWebAssets(exportOut); err != nil {
+		cli.OutputError(cli.ExitInternalError, "export_failed", fmt.Sprintf("Could not write the web UI: %v", err), nil, nil)
+	}
+
+	if !cli.JSONOutput {
+		for _, s := range manifest.Skipped {
+			cli.Warn("skipped %s", s)
+		}
+	}
+	cli.OutputSuccess("export",
+		fmt.Sprintf("Exported %d goal(s) and %d project(s) to %s", manifest.Goals, manifest.Projects, exportOut),
+		manifest)
+}
+
+// exportWebAssets copies the embedded web UI to out, marking its index.html
+// as a snapshot so it reads the exported files
+func exportWebAssets(out string) error {
+	webContent, err := fs.Sub(WebFS, "web")
+	if err != nil {
+		return err
+	}
+	return fs.WalkDir(webContent, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(out, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := fs.ReadFile(webContent, path)
+		if err != nil {
+			return err
+		}
+		if path == "index.html" {
+			data = []byte(markSnapshot(string(data)))
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+// markSnapshot sets the snapshot marker before any of the page's scripts run
+func markSnapshot(html string) string {
+	marker := "<script>window." + api.SnapshotMarker + " = true</script>"
+	for _, tag := range []string{"<script", "</head>"} {
+		if i := strings.Index(html, tag); i >= 0 {
+			return html[:i] + marker + html[i:]
+		}
+	}
+	return marker + html
+}
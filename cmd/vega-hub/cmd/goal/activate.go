@@ -0,0 +1,91 @@
+package goal
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	activateBaseBranch string
+	activateNoWorktree bool
+)
+
+var activateCmd = &cobra.Command{
+	Use:   "activate <goal-id>",
+	Short: "Activate a draft goal from the backlog",
+	Long: `Take a draft goal out of the backlog: move its file to goals/active, mark it
+active and create its branch and worktree.
+
+Examples:
+  vega-hub goal activate f3a8b2c
+  vega-hub goal activate f3a8b2c --base-branch dev
+  vega-hub goal list --status draft   # The backlog`,
+	Args: cobra.ExactArgs(1),
+	Run:  runActivate,
+}
+
+func init() {
+	GoalCmd.AddCommand(activateCmd)
+	activateCmd.Flags().StringVar(&activateBaseBranch, "base-branch", "", "Base branch (default: from project config)")
+	activateCmd.Flags().BoolVar(&activateNoWorktree, "no-worktree", false, "Activate without creating the worktree")
+}
+
+func runActivate(c *cobra.Command, args []string) {
+	goalID := args[0]
+
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
+
+	result, data := operations.ActivateGoal(operations.ActivateOptions{
+		GoalID:       goalID,
+		BaseBranch:   activateBaseBranch,
+		User:         currentUsername(),
+		VegaDir:      vegaDir,
+		SkipWorktree: activateNoWorktree,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		switch result.Error.Code {
+		case "goal_not_found":
+			exitCode = cli.ExitNotFound
+		case "not_draft":
+			exitCode = cli.ExitStateError
+		case "invalid_input", "project_required", "project_not_found":
+			exitCode = cli.ExitValidationError
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, nil)
+	}
+
+	for _, warning := range data.Warnings {
+		cli.Warn("%s", warning)
+	}
+
+	nextSteps := []string{fmt.Sprintf("Edit goal file: %s", data.GoalFile)}
+	if data.WorktreePath != "" {
+		nextSteps = append(nextSteps, fmt.Sprintf("Spawn executor: vega-hub executor spawn %s", goalID))
+	}
+	cli.Output(cli.Result{
+		Success:   true,
+		Action:    "goal_activate",
+		Message:   fmt.Sprintf("Activated goal %s: %s", goalID, data.Title),
+		Data:      data,
+		NextSteps: nextSteps,
+	})
+	if !cli.JSONOutput {
+		fmt.Printf("  Project:     %s\n", data.Project)
+		fmt.Printf("  Base branch: %s\n", data.BaseBranch)
+		fmt.Printf("  Goal branch: %s\n", data.GoalBranch)
+		if data.WorktreePath != "" {
+			fmt.Printf("  Worktree:    %s\n", data.WorktreePath)
+		}
+	}
+}
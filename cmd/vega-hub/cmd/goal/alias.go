@@ -0,0 +1,76 @@
+package goal
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var aliasClear bool
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias <goal-id> [alias]",
+	Short: "Give a goal a memorable name",
+	Long: `Set the alias of a goal: a unique slug (letters, digits and dashes,
+starting with a letter) that any command or API endpoint taking a goal ID
+accepts in its place. Aliases that look like goal IDs are refused.
+
+Examples:
+  vega-hub goal alias f3a8b2c oauth-login
+  vega-hub goal complete oauth-login my-api
+  vega-hub goal alias oauth-login --clear`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runAlias,
+}
+
+func init() {
+	GoalCmd.AddCommand(aliasCmd)
+	aliasCmd.Flags().BoolVar(&aliasClear, "clear", false, "Remove the goal's alias")
+}
+
+func runAlias(c *cobra.Command, args []string) {
+	goalID := args[0]
+	alias := ""
+	if len(args) > 1 {
+		alias = args[1]
+	}
+	if (alias == "") != aliasClear {
+		cli.OutputError(cli.ExitValidationError, "invalid_input",
+			"Give an alias, or --clear to remove the goal's alias",
+			map[string]string{"goal_id": goalID}, nil)
+	}
+
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
+
+	result, stored := operations.SetGoalAlias(vegaDir, goalID, alias)
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		switch result.Error.Code {
+		case "goal_not_found":
+			exitCode = cli.ExitNotFound
+		case "invalid_input":
+			exitCode = cli.ExitValidationError
+		case "alias_taken":
+			exitCode = cli.ExitConflict
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, nil)
+	}
+
+	message := fmt.Sprintf("Goal %s is now also %s", goalID, stored)
+	if stored == "" {
+		message = fmt.Sprintf("Removed the alias of goal %s", goalID)
+	}
+	cli.OutputSuccess("goal_alias", message, map[string]interface{}{
+		"goal_id": goalID,
+		"alias":   stored,
+	})
+}
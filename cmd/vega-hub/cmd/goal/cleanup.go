@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
 	"github.com/spf13/cobra"
 )
@@ -43,6 +44,7 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	manager := hub.NewCleanupManager(vegaDir)
 	if err := manager.CleanupGoal(goalID, project, cleanupDeleteBranch, cleanupForce); err != nil {
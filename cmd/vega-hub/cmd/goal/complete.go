@@ -2,6 +2,7 @@ package goal
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,12 +14,15 @@ import (
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+	"github.com/lasmarois/vega-hub/internal/operations"
 	"github.com/spf13/cobra"
 )
 
 var (
-	completeNoMerge bool
-	completeForce   bool
+	completeNoMerge  bool
+	completeForce    bool
+	completeStrategy string
 )
 
 // CompleteResult contains the result of completing a goal
@@ -44,6 +48,7 @@ Examples:
   vega-hub goal complete f3a8b2c my-api
   vega-hub goal complete f3a8b2c my-api --no-merge
   vega-hub goal complete f3a8b2c my-api --force
+  vega-hub goal complete f3a8b2c my-api --strategy squash
 
 This command will:
   1. Merge the goal branch to the project's base branch (unless --no-merge)
@@ -53,6 +58,16 @@ This command will:
   5. Update REGISTRY.md (Active -> Completed)
   6. Update the project config
 
+Projects can protect branches or require MRs (see **Protected Branches** and
+**Require MR** in projects/<name>.md); direct merges to them are refused, use
+--no-merge after opening an MR. The merge strategy defaults to the project's
+**Merge Strategy** (merge, squash, rebase or ff-only).
+
+Projects can also require signed commits or Conventional Commits subjects
+(see **Require Signed Commits** and **Commit Convention**); goals whose
+branch breaks the policy are not completed and each offending commit is
+listed.
+
 NOTE: Executor should archive planning files before running this command.`,
 	Args: cobra.ExactArgs(2),
 	Run:  runComplete,
@@ -61,7 +76,8 @@ NOTE: Executor should archive planning files before running this command.`,
 func init() {
 	GoalCmd.AddCommand(completeCmd)
 	completeCmd.Flags().BoolVar(&completeNoMerge, "no-merge", false, "Skip merging (use when creating MR/PR instead)")
-	completeCmd.Flags().BoolVarP(&completeForce, "force", "f", false, "Skip safety checks for uncommitted changes")
+	completeCmd.Flags().BoolVarP(&completeForce, "force", "f", false, "Skip safety checks for uncommitted changes and active child goals")
+	completeCmd.Flags().StringVar(&completeStrategy, "strategy", "", "Merge strategy: merge, squash, rebase, ff-only (default: project setting)")
 }
 
 func runComplete(c *cobra.Command, args []string) {
@@ -75,14 +91,15 @@ func runComplete(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Validate goal exists and is active
-	goalFile := filepath.Join(vegaDir, "goals", "active", goalID+".md")
-	if _, err := os.Stat(goalFile); os.IsNotExist(err) {
+	goalFile := layout.New(vegaDir).FindGoalFileIn(layout.ActiveDir, goalID)
+	if goalFile == "" {
 		cli.OutputError(cli.ExitNotFound, "goal_not_found",
 			fmt.Sprintf("Active goal '%s' not found", goalID),
 			map[string]string{
-				"expected_path": goalFile,
+				"expected_path": layout.New(vegaDir).GoalFile(layout.ActiveDir, goalID),
 				"goal_id":       goalID,
 			},
 			[]cli.ErrorOption{
@@ -94,7 +111,7 @@ func runComplete(c *cobra.Command, args []string) {
 	goalTitle := getGoalTitle(goalFile, goalID)
 
 	// Get project base folder and branch
-	projectBase := filepath.Join(vegaDir, "workspaces", project, "worktree-base")
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
 	if _, err := os.Stat(projectBase); os.IsNotExist(err) {
 		cli.OutputError(cli.ExitNotFound, "project_not_found",
 			fmt.Sprintf("Project '%s' not found", project),
@@ -113,8 +130,79 @@ func runComplete(c *cobra.Command, args []string) {
 			nil)
 	}
 
+	backend, err := operations.ProjectBackend(vegaDir, project)
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "invalid_vcs",
+			fmt.Sprintf("Project '%s' has an invalid VCS", project),
+			map[string]string{
+				"project": project,
+				"error":   err.Error(),
+			},
+			nil)
+	}
+
+	// Enforce the project's merge policy
+	var policy goals.MergePolicy
+	if proj, err := goals.ParseProject(vegaDir, project); err == nil {
+		policy = proj.MergePolicy
+	}
+	if !completeNoMerge && policy.RequiresMR(baseBranch) {
+		cli.OutputError(cli.ExitValidationError, "mr_required",
+			fmt.Sprintf("Project '%s' does not allow direct merges to '%s'", project, baseBranch),
+			map[string]string{
+				"project": project,
+				"target":  baseBranch,
+			},
+			[]cli.ErrorOption{
+				{Action: "create-mr", Description: "Open an MR/PR for the goal branch"},
+				{Flag: "no-merge", Description: "Complete without merging once the MR/PR is open"},
+			})
+	}
+	// Reviews gate completion (see goals.CheckReview)
+	if state, err := goals.NewStateManager(vegaDir).GetState(goalID); err == nil {
+		if err := goals.CheckReview(state, policy); err != nil {
+			code, option := "review_required", cli.ErrorOption{Action: "review", Description: "Approve the goal in the UI or with POST /api/goals/<id>/review"}
+			if errors.Is(err, goals.ErrChangesRequested) {
+				code, option = "changes_requested", cli.ErrorOption{Action: "address", Description: "Address the review comments, then ask for another review"}
+			}
+			cli.OutputError(cli.ExitStateError, code,
+				fmt.Sprintf("Goal '%s' can't be completed: %v", goalID, err),
+				map[string]string{
+					"goal_id": goalID,
+					"state":   string(state),
+				},
+				[]cli.ErrorOption{option})
+		}
+	}
+	// An umbrella goal completes after its children, unless forced
+	if !completeForce {
+		var active *goals.ActiveChildrenError
+		if err := goals.NewHierarchyManager(vegaDir).CheckChildren(goalID); errors.As(err, &active) {
+			cli.OutputError(cli.ExitStateError, "children_active",
+				fmt.Sprintf("Goal '%s' has active child goals", goalID),
+				map[string]string{
+					"goal_id":  goalID,
+					"children": strings.Join(active.Children, ", "),
+				},
+				[]cli.ErrorOption{
+					{Action: "complete", Description: "Complete or ice the child goals first"},
+					{Flag: "force", Description: "Complete the goal anyway"},
+				})
+		}
+	}
+	strategy := completeStrategy
+	if strategy == "" {
+		strategy = policy.Strategy()
+	}
+	if !goals.IsValidMergeStrategy(strategy) {
+		cli.OutputError(cli.ExitValidationError, "invalid_merge_strategy",
+			fmt.Sprintf("Invalid merge strategy: %s", strategy),
+			map[string]string{"valid": strings.Join(goals.MergeStrategies(), ", ")},
+			nil)
+	}
+
 	// Find the worktree directory (pattern: goal-<id>-*)
-	worktreeDir, err := findWorktreeDir(vegaDir, project, goalID)
+	worktreeDir, err := operations.FindGoalWorktree(vegaDir, project, goalID)
 	if err != nil {
 		cli.OutputError(cli.ExitNotFound, "worktree_not_found",
 			err.Error(),
@@ -141,7 +229,7 @@ func runComplete(c *cobra.Command, args []string) {
 
 	// Safety check: verify worktree is clean (unless --force)
 	if !completeForce {
-		if err := checkWorktreeClean(worktreeDir); err != nil {
+		if err := backend.CheckClean(worktreeDir, nil); err != nil {
 			cli.OutputError(cli.ExitStateError, "uncommitted_changes",
 				"Worktree has uncommitted changes",
 				map[string]string{
@@ -155,6 +243,33 @@ func runComplete(c *cobra.Command, args []string) {
 		}
 	}
 
+	// Enforce the project's commit policy (signatures, message convention)
+	var commitPolicy goals.CommitPolicy
+	if proj, err := goals.ParseProject(vegaDir, project); err == nil {
+		commitPolicy = proj.CommitPolicy
+	}
+	if commitPolicy.Enabled() {
+		violations, err := commitPolicy.CheckCommits(worktreeDir, baseBranch)
+		if err != nil {
+			cli.OutputError(cli.ExitInternalError, "commit_policy_check_failed",
+				"Could not check commits against the project's commit policy",
+				map[string]string{"branch": branchName, "error": err.Error()},
+				nil)
+		}
+		if len(violations) > 0 {
+			cli.OutputError(cli.ExitValidationError, "commit_policy_violation",
+				fmt.Sprintf("%d commit policy violation(s) on branch '%s'", len(violations), branchName),
+				map[string]string{
+					"project":    project,
+					"branch":     branchName,
+					"violations": goals.SummarizeViolations(violations),
+				},
+				[]cli.ErrorOption{
+					{Action: "rewrite", Description: fmt.Sprintf("Reword or re-sign the commits: git rebase -i %s", baseBranch)},
+				})
+		}
+	}
+
 	cli.Info("Completing goal %s: %s", goalID, goalTitle)
 	cli.Info("  Project: %s", project)
 	cli.Info("  Worktree: %s", worktreeDir)
@@ -162,6 +277,7 @@ func runComplete(c *cobra.Command, args []string) {
 
 	// Initialize state manager
 	sm := goals.NewStateManager(vegaDir)
+	user := currentUsername()
 
 	result := CompleteResult{
 		GoalID:  goalID,
@@ -204,8 +320,16 @@ func runComplete(c *cobra.Command, args []string) {
 		}
 		defer worktreeLock.Release()
 
+		// Merge into the latest base from origin
+		if proj, err := goals.ParseProject(vegaDir, project); err != nil || !proj.NoBaseFetch {
+			cli.Info("Fetching %s from origin...", baseBranch)
+			if err := operations.FetchBaseBranch(projectBase, baseBranch); err != nil {
+				outputBaseSyncError(project, projectBase, err)
+			}
+		}
+
 		// Transition to pushing state
-		if err := sm.Transition(goalID, goals.StatePushing, "Starting completion", map[string]string{
+		if err := sm.TransitionWithUser(goalID, goals.StatePushing, "Starting completion", user, map[string]string{
 			"branch":      branchName,
 			"base_branch": baseBranch,
 		}); err != nil {
@@ -213,14 +337,15 @@ func runComplete(c *cobra.Command, args []string) {
 		}
 
 		// Transition to merging state
-		cli.Info("Merging %s to %s...", branchName, baseBranch)
-		if err := sm.Transition(goalID, goals.StateMerging, "Merging to base branch", nil); err != nil {
+		cli.Info("Merging %s to %s (%s)...", branchName, baseBranch, strategy)
+		if err := sm.TransitionWithUser(goalID, goals.StateMerging, "Merging to base branch", user, nil); err != nil {
 			cli.Warn("Failed to transition to merging state: %v", err)
 		}
 
-		if err := mergeBranch(projectBase, branchName, baseBranch, goalID, goalTitle); err != nil {
+		mergeMsg := fmt.Sprintf("Merge goal %s: %s", goalID, goalTitle)
+		if err := backend.Merge(projectBase, worktreeDir, branchName, baseBranch, strategy, mergeMsg); err != nil {
 			// Transition to conflict state on merge failure
-			sm.Transition(goalID, goals.StateConflict, "Merge conflict detected", map[string]string{
+			sm.TransitionWithUser(goalID, goals.StateConflict, "Merge conflict detected", user, map[string]string{
 				"error": err.Error(),
 			})
 			cli.OutputError(cli.ExitConflict, "merge_failed",
@@ -242,7 +367,7 @@ func runComplete(c *cobra.Command, args []string) {
 		cli.Info("Skipping merge (--no-merge specified)")
 		cli.Info("Remember to create MR/PR for branch: %s", branchName)
 		// Transition to pushing state (waiting for MR/PR)
-		if err := sm.Transition(goalID, goals.StatePushing, "Awaiting MR/PR merge", map[string]string{
+		if err := sm.TransitionWithUser(goalID, goals.StatePushing, "Awaiting MR/PR merge", user, map[string]string{
 			"branch": branchName,
 		}); err != nil {
 			cli.Warn("Failed to transition to pushing state: %v", err)
@@ -251,7 +376,7 @@ func runComplete(c *cobra.Command, args []string) {
 
 	// Step 2: Remove worktree
 	cli.Info("Removing worktree...")
-	if err := removeWorktreeComplete(projectBase, worktreeDir); err != nil {
+	if err := backend.RemoveWorkspace(projectBase, worktreeDir); err != nil {
 		cli.Warn("Could not remove worktree cleanly: %v", err)
 	}
 	result.WorktreeRemoved = true
@@ -259,7 +384,7 @@ func runComplete(c *cobra.Command, args []string) {
 	// Step 3: Delete branch (unless --no-merge)
 	if !completeNoMerge {
 		cli.Info("Deleting branch %s...", branchName)
-		if err := deleteBranch(projectBase, branchName); err != nil {
+		if err := backend.DeleteBranch(projectBase, branchName); err != nil {
 			cli.Warn("Could not delete branch: %v", err)
 		} else {
 			result.BranchDeleted = true
@@ -268,12 +393,7 @@ func runComplete(c *cobra.Command, args []string) {
 
 	// Step 4: Move goal file to history
 	cli.Info("Moving goal to history...")
-	historyDir := filepath.Join(vegaDir, "goals", "history")
-	if err := os.MkdirAll(historyDir, 0755); err != nil {
-		cli.Warn("Could not create history directory: %v", err)
-	}
-	historyFile := filepath.Join(historyDir, goalID+".md")
-	if err := os.Rename(goalFile, historyFile); err != nil {
+	if historyFile, err := layout.New(vegaDir).MoveGoal(goalID, layout.ActiveDir, layout.HistoryDir); err != nil {
 		cli.Warn("Could not move goal to history: %v", err)
 	} else {
 		result.GoalArchived = true
@@ -281,26 +401,26 @@ func runComplete(c *cobra.Command, args []string) {
 	}
 
 	// Step 5: Update registry
-	registryPath := filepath.Join(vegaDir, "goals", "REGISTRY.md")
+	registryPath := layout.New(vegaDir).RegistryMarkdown()
 	if err := completeGoalInRegistry(registryPath, goalID, goalTitle, project); err != nil {
 		cli.Warn("Could not update registry: %v", err)
 	}
 
-	// Step 6: Update project config
-	projectConfig := filepath.Join(vegaDir, "projects", project+".md")
-	if err := completeGoalInProjectConfig(projectConfig, goalID, goalTitle); err != nil {
-		cli.Warn("Could not update project config: %v", err)
+	// Step 6: Update the goal's registry entry and its project's goal lists
+	if err := markGoalCompleted(vegaDir, goalID, user); err != nil {
+		cli.Warn("Could not update registry: %v", err)
 	}
+	operations.SyncProjectGoals(vegaDir, project)
 
 	// Step 7: Transition state to done (only if we actually merged)
 	if !completeNoMerge {
-		if err := sm.Transition(goalID, goals.StateDone, "Goal completed", map[string]string{
+		if err := sm.TransitionWithUser(goalID, goals.StateDone, "Goal completed", user, map[string]string{
 			"merged_to": baseBranch,
 		}); err != nil {
 			cli.Warn("Failed to transition to done state: %v", err)
 		}
 		// Move state file to history
-		if err := sm.MoveStateFile(goalID, "active", "history"); err != nil {
+		if err := sm.MoveStateFile(goalID, layout.ActiveDir, layout.HistoryDir); err != nil {
 			cli.Warn("Failed to move state file to history: %v", err)
 		}
 	}
@@ -341,32 +461,6 @@ func getGoalTitle(goalFile, goalID string) string {
 	return "goal-" + goalID
 }
 
-// findWorktreeDir finds the worktree directory for a goal
-func findWorktreeDir(vegaDir, project, goalID string) (string, error) {
-	pattern := filepath.Join(vegaDir, "workspaces", project, fmt.Sprintf("goal-%s-*", goalID))
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		return "", err
-	}
-
-	// Filter to only directories
-	var dirs []string
-	for _, m := range matches {
-		if info, err := os.Stat(m); err == nil && info.IsDir() {
-			dirs = append(dirs, m)
-		}
-	}
-
-	if len(dirs) == 0 {
-		return "", fmt.Errorf("no worktree found matching pattern: goal-%s-*", goalID)
-	}
-	if len(dirs) > 1 {
-		return "", fmt.Errorf("multiple worktrees found for goal %s: %v", goalID, dirs)
-	}
-
-	return dirs[0], nil
-}
-
 // getWorktreeBranch gets the current branch name from a worktree
 func getWorktreeBranch(worktreeDir string) (string, error) {
 	cmd := exec.Command("git", "-C", worktreeDir, "branch", "--show-current")
@@ -382,146 +476,47 @@ func getWorktreeBranch(worktreeDir string) (string, error) {
 	return branch, nil
 }
 
-// checkWorktreeClean verifies the worktree has no uncommitted changes
-func checkWorktreeClean(worktreeDir string) error {
-	cmd := exec.Command("git", "-C", worktreeDir, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("git status failed: %w", err)
-	}
-	if len(strings.TrimSpace(string(output))) > 0 {
-		return fmt.Errorf("uncommitted changes:\n%s", string(output))
-	}
-	return nil
-}
-
-// mergeBranch merges the source branch to target branch
-func mergeBranch(projectBase, sourceBranch, targetBranch, goalID, goalTitle string) error {
-	// Checkout target branch
-	cmd := exec.Command("git", "-C", projectBase, "checkout", targetBranch)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("checkout %s: %s", targetBranch, string(output))
-	}
-
-	// Merge source branch
-	mergeMsg := fmt.Sprintf("Merge goal %s: %s", goalID, goalTitle)
-	cmd = exec.Command("git", "-C", projectBase, "merge", sourceBranch, "-m", mergeMsg)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("merge: %s", string(output))
-	}
-
-	return nil
-}
-
-// removeWorktreeComplete removes a worktree, with fallback to force removal
-func removeWorktreeComplete(projectBase, worktreeDir string) error {
-	// Try normal removal first
-	cmd := exec.Command("git", "-C", projectBase, "worktree", "remove", worktreeDir, "--force")
-	if err := cmd.Run(); err != nil {
-		// Fallback: remove directory and prune
-		os.RemoveAll(worktreeDir)
-		exec.Command("git", "-C", projectBase, "worktree", "prune").Run()
-	}
-	return nil
-}
-
-// deleteBranch deletes a branch (tries -d first, then -D)
-func deleteBranch(projectBase, branchName string) error {
-	// Try safe delete first
-	cmd := exec.Command("git", "-C", projectBase, "branch", "-d", branchName)
-	if err := cmd.Run(); err != nil {
-		// Try force delete
-		cmd = exec.Command("git", "-C", projectBase, "branch", "-D", branchName)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("could not delete branch: %w", err)
-		}
-	}
-	return nil
-}
-
 // completeGoalInRegistry updates REGISTRY.md: remove from Active, add to Completed
 func completeGoalInRegistry(registryPath, goalID, goalTitle, project string) error {
-	content, err := os.ReadFile(registryPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	today := time.Now().Format("2006-01-02")
-	addedToCompleted := false
-
-	// First pass: remove from Active Goals
-	activeGoalPattern := regexp.MustCompile(fmt.Sprintf(`^\| %s \|.*\| Active \|`, regexp.QuoteMeta(goalID)))
-	for _, line := range lines {
-		if activeGoalPattern.MatchString(line) {
-			continue // Skip this line (remove from active)
-		}
-		newLines = append(newLines, line)
-	}
-
-	// Second pass: add to Completed Goals section
-	var finalLines []string
-	for i, line := range newLines {
-		finalLines = append(finalLines, line)
-
-		// Look for Completed Goals table header
-		if !addedToCompleted && strings.Contains(line, "| ID | Title | Project(s) | Completed |") {
-			// Next line should be the separator
-			if i+1 < len(newLines) && strings.Contains(newLines[i+1], "|---") {
-				finalLines = append(finalLines, newLines[i+1])
-				// Add our completed goal entry
-				completedRow := fmt.Sprintf("| %s | %s | %s | %s |", goalID, goalTitle, project, today)
-				finalLines = append(finalLines, completedRow)
-				addedToCompleted = true
-				// Skip the separator in the main loop since we already added it
-				newLines = append(newLines[:i+1], newLines[i+2:]...)
-			}
-		}
-	}
-
-	if !addedToCompleted {
-		finalLines = newLines // Fall back to just removing from active
-	}
-
-	return os.WriteFile(registryPath, []byte(strings.Join(finalLines, "\n")), 0644)
+	return goals.UpdateMarkdownFile(registryPath, func(doc *goals.MarkdownDoc) error {
+		return doc.SetRegistryGoal(goals.Goal{
+			ID:          goalID,
+			Title:       goalTitle,
+			Projects:    []string{project},
+			Status:      "completed",
+			CompletedAt: time.Now().Format("2006-01-02"),
+		})
+	})
 }
 
-// completeGoalInProjectConfig updates project config: remove from Active, add to Completed
-func completeGoalInProjectConfig(configPath, goalID, goalTitle string) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	today := time.Now().Format("2006-01-02")
-	addedToCompleted := false
-
-	// Pattern to match active goal entries: "- <id>: <title>" or "- #<id>: <title>"
-	activePattern := regexp.MustCompile(fmt.Sprintf(`^- #?%s:`, regexp.QuoteMeta(goalID)))
-
-	for i, line := range lines {
-		// Skip active goal entries for this goal
-		if activePattern.MatchString(line) {
-			continue
-		}
-		newLines = append(newLines, line)
-
-		// Look for Completed Goals table header in project config
-		if !addedToCompleted && strings.Contains(line, "| ID | Title | Completed |") {
-			// Next line should be the separator
-			if i+1 < len(lines) && strings.Contains(lines[i+1], "|---") {
-				newLines = append(newLines, lines[i+1])
-				// Add our completed goal entry
-				completedRow := fmt.Sprintf("| %s | %s | %s |", goalID, goalTitle, today)
-				newLines = append(newLines, completedRow)
-				addedToCompleted = true
-				// We need to handle this carefully in the iteration
-			}
-		}
+// outputBaseSyncError reports a base branch that couldn't be updated from origin
+func outputBaseSyncError(project, projectBase string, err error) {
+	var syncErr *operations.BaseSyncError
+	if errors.As(err, &syncErr) && syncErr.Diverged {
+		cli.OutputError(cli.ExitConflict, "base_diverged",
+			fmt.Sprintf("Local %s has diverged from origin/%s", syncErr.Branch, syncErr.Branch),
+			map[string]string{"project": project, "branch": syncErr.Branch},
+			[]cli.ErrorOption{
+				{Action: "reconcile", Description: fmt.Sprintf("Run: git -C %s pull --rebase origin %s", projectBase, syncErr.Branch)},
+			})
 	}
+	cli.OutputError(cli.ExitStateError, "base_fetch_failed",
+		"Could not fetch the base branch from origin; is the remote reachable?",
+		map[string]string{"project": project, "error": err.Error()},
+		[]cli.ErrorOption{
+			{Action: "retry", Description: fmt.Sprintf("Check the network and credentials, then run: git -C %s fetch origin", projectBase)},
+			{Action: "disable", Description: "Set **Fetch Base**: `false` in the project config"},
+		})
+}
 
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
+// markGoalCompleted records the completion by user in the JSONL registry
+func markGoalCompleted(vegaDir, goalID, user string) error {
+	return hub.NewLockManager(vegaDir).WithRegistryLock("complete-goal", func() error {
+		return goals.NewRegistry(vegaDir).Update(goalID, func(e *goals.RegistryEntry) {
+			e.Status = "completed"
+			e.CompletedAt = time.Now().Format("2006-01-02")
+			e.CompletedBy = user
+			e.UpdatedAt = time.Now().Format(time.RFC3339)
+		})
+	})
 }
@@ -121,6 +121,8 @@ func runDepAdd(c *cobra.Command, args []string) {
 	if err != nil {
 		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, nil)
 	}
+	goalID = goals.ResolveGoalID(dir, goalID)
+	dependsOnID = goals.ResolveGoalID(dir, dependsOnID)
 
 	dm := goals.NewDependencyManager(dir)
 
@@ -175,6 +177,8 @@ func runDepRemove(c *cobra.Command, args []string) {
 	if err != nil {
 		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, nil)
 	}
+	goalID = goals.ResolveGoalID(dir, goalID)
+	dependsOnID = goals.ResolveGoalID(dir, dependsOnID)
 
 	dm := goals.NewDependencyManager(dir)
 
@@ -208,6 +212,7 @@ func runDepTree(c *cobra.Command, args []string) {
 	if err != nil {
 		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, nil)
 	}
+	goalID = goals.ResolveGoalID(dir, goalID)
 
 	dm := goals.NewDependencyManager(dir)
 
@@ -296,6 +301,7 @@ func runDepShow(c *cobra.Command, args []string) {
 	if err != nil {
 		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, nil)
 	}
+	goalID = goals.ResolveGoalID(dir, goalID)
 
 	dm := goals.NewDependencyManager(dir)
 
@@ -0,0 +1,102 @@
+package goal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut     string
+	exportPatch   bool
+	exportProject string
+)
+
+// errExportFailed keeps a failed export's partial bundle from being saved
+var errExportFailed = errors.New("export failed")
+
+var exportCmd = &cobra.Command{
+	Use:   "export <goal-id>",
+	Short: "Write a goal to a portable bundle",
+	Long: `Write a goal to a gzipped tarball that "vega-hub goal import" loads into
+another vega-missile directory, e.g. when a goal moves to another team's hub.
+
+The bundle holds the goal file with its state, dependency and hierarchy
+metadata (the whole folder of folder-style goals), its registry entry and its
+session history. With --patch it also holds the commits of the goal branch,
+as "vega-hub goal patch" would export them. Child goals are not included.
+
+Examples:
+  vega-hub goal export f3a8b2c
+  vega-hub goal export f3a8b2c --patch --out /tmp/oauth-login.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	Run:  runExport,
+}
+
+func init() {
+	GoalCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Bundle file to write (default: goal-<id>.tar.gz)")
+	exportCmd.Flags().BoolVar(&exportPatch, "patch", false, "Include the goal branch's commits")
+	exportCmd.Flags().StringVarP(&exportProject, "project", "p", "", "Project whose branch --patch includes (default: the goal's project with a worktree)")
+}
+
+func runExport(c *cobra.Command, args []string) {
+	goalID := args[0]
+
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
+	out := exportOut
+	if out == "" {
+		out = fmt.Sprintf("goal-%s.tar.gz", goalID)
+	}
+
+	// Written next to the bundle and renamed, so a failed export leaves nothing
+	var result *operations.Result
+	var manifest *operations.GoalBundleManifest
+	writeErr := atomicfile.Write(out, 0644, func(f *os.File) error {
+		result, manifest = operations.ExportGoal(operations.ExportGoalOptions{
+			GoalID:       goalID,
+			IncludePatch: exportPatch,
+			Project:      exportProject,
+			VegaDir:      vegaDir,
+		}, f)
+		if !result.Success {
+			return errExportFailed
+		}
+		return nil
+	})
+	if result != nil && !result.Success {
+		exitCode := cli.ExitInternalError
+		switch result.Error.Code {
+		case "goal_not_found", "worktree_not_found":
+			exitCode = cli.ExitNotFound
+		case "invalid_input", "plain_project", "patch_unsupported":
+			exitCode = cli.ExitValidationError
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, nil)
+	}
+	if writeErr != nil {
+		cli.OutputError(cli.ExitInternalError, "write_failed", fmt.Sprintf("Could not write %s", out),
+			map[string]string{"error": writeErr.Error()}, nil)
+	}
+
+	message := fmt.Sprintf("Exported goal %s (%d file(s), %d history entries) to %s", goalID, len(manifest.Files), manifest.History, out)
+	if manifest.Patch != nil {
+		message += fmt.Sprintf(" with %d commit(s) of %s", manifest.Patch.Commits, manifest.Patch.Branch)
+	}
+	cli.OutputSuccess("goal_export", message, map[string]interface{}{
+		"file":     out,
+		"manifest": manifest,
+	})
+}
@@ -13,9 +13,12 @@ var GoalCmd = &cobra.Command{
 Available subcommands:
   list      List all goals
   create    Create a new goal with worktree
+  alias     Give a goal a memorable name
   complete  Complete a goal (merge, cleanup)
   ice       Pause a goal for later
-  cleanup   Delete branch after MR/PR merged`,
+  cleanup   Delete branch after MR/PR merged
+  export    Write a goal to a portable bundle
+  import    Load a goal bundle from another vega-missile directory`,
 }
 
 func init() {
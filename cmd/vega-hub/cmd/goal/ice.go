@@ -3,13 +3,15 @@ package goal
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+	"github.com/lasmarois/vega-hub/internal/operations"
 	"github.com/spf13/cobra"
 )
 
@@ -71,14 +73,15 @@ func runIce(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Validate goal exists and is active
-	goalFile := filepath.Join(vegaDir, "goals", "active", goalID+".md")
-	if _, err := os.Stat(goalFile); os.IsNotExist(err) {
+	goalFile := layout.New(vegaDir).FindGoalFileIn(layout.ActiveDir, goalID)
+	if goalFile == "" {
 		cli.OutputError(cli.ExitNotFound, "goal_not_found",
 			fmt.Sprintf("Active goal '%s' not found", goalID),
 			map[string]string{
-				"expected_path": goalFile,
+				"expected_path": layout.New(vegaDir).GoalFile(layout.ActiveDir, goalID),
 				"goal_id":       goalID,
 			},
 			[]cli.ErrorOption{
@@ -90,7 +93,7 @@ func runIce(c *cobra.Command, args []string) {
 	goalTitle := getGoalTitle(goalFile, goalID)
 
 	// Get project base folder
-	projectBase := filepath.Join(vegaDir, "workspaces", project, "worktree-base")
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
 	if _, err := os.Stat(projectBase); os.IsNotExist(err) {
 		cli.OutputError(cli.ExitNotFound, "project_not_found",
 			fmt.Sprintf("Project '%s' not found", project),
@@ -102,7 +105,7 @@ func runIce(c *cobra.Command, args []string) {
 	}
 
 	// Find the worktree directory
-	worktreeDir, err := findWorktreeDir(vegaDir, project, goalID)
+	worktreeDir, err := operations.FindGoalWorktree(vegaDir, project, goalID)
 	if err != nil {
 		cli.OutputError(cli.ExitNotFound, "worktree_not_found",
 			err.Error(),
@@ -115,6 +118,17 @@ func runIce(c *cobra.Command, args []string) {
 			})
 	}
 
+	backend, err := operations.ProjectBackend(vegaDir, project)
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "invalid_vcs",
+			fmt.Sprintf("Project '%s' has an invalid VCS", project),
+			map[string]string{
+				"project": project,
+				"error":   err.Error(),
+			},
+			nil)
+	}
+
 	// Get the branch name from the worktree
 	branchName, err := getWorktreeBranch(worktreeDir)
 	if err != nil {
@@ -129,7 +143,7 @@ func runIce(c *cobra.Command, args []string) {
 
 	// Safety check: verify worktree is clean (unless --force)
 	if !iceForce {
-		if err := checkWorktreeClean(worktreeDir); err != nil {
+		if err := backend.CheckClean(worktreeDir, nil); err != nil {
 			cli.OutputError(cli.ExitStateError, "uncommitted_changes",
 				"Worktree has uncommitted changes",
 				map[string]string{
@@ -150,6 +164,7 @@ func runIce(c *cobra.Command, args []string) {
 
 	// Initialize state manager
 	sm := goals.NewStateManager(vegaDir)
+	user := currentUsername()
 
 	result := IceResult{
 		GoalID:          goalID,
@@ -161,7 +176,7 @@ func runIce(c *cobra.Command, args []string) {
 
 	// Step 1: Remove worktree (keep branch)
 	cli.Info("Removing worktree (keeping branch)...")
-	if err := removeWorktreeComplete(projectBase, worktreeDir); err != nil {
+	if err := backend.RemoveWorkspace(projectBase, worktreeDir); err != nil {
 		cli.Warn("Could not remove worktree cleanly: %v", err)
 	}
 	result.WorktreeRemoved = true
@@ -173,19 +188,19 @@ func runIce(c *cobra.Command, args []string) {
 	}
 
 	// Step 3: Update registry (Active -> Iced)
-	registryPath := filepath.Join(vegaDir, "goals", "REGISTRY.md")
+	registryPath := layout.New(vegaDir).RegistryMarkdown()
 	if err := iceGoalInRegistry(registryPath, goalID, goalTitle, project, reason); err != nil {
 		cli.Warn("Could not update registry: %v", err)
 	}
 
-	// Step 4: Update project config
-	projectConfig := filepath.Join(vegaDir, "projects", project+".md")
-	if err := removeGoalFromProjectConfig(projectConfig, goalID); err != nil {
-		cli.Warn("Could not update project config: %v", err)
+	// Step 4: Update the goal's registry entry and its project's goal lists
+	if err := markGoalIced(vegaDir, goalID, reason, user); err != nil {
+		cli.Warn("Could not update registry: %v", err)
 	}
+	operations.SyncProjectGoals(vegaDir, project)
 
 	// Step 5: Transition state to iced
-	if err := sm.Transition(goalID, goals.StateIced, reason, map[string]string{
+	if err := sm.TransitionWithUser(goalID, goals.StateIced, reason, user, map[string]string{
 		"branch": branchName,
 	}); err != nil {
 		cli.Warn("Failed to transition to iced state: %v", err)
@@ -230,70 +245,25 @@ func updateGoalFileToIced(goalFile, reason string) error {
 
 // iceGoalInRegistry updates REGISTRY.md: remove from Active, add to Iced
 func iceGoalInRegistry(registryPath, goalID, goalTitle, project, reason string) error {
-	content, err := os.ReadFile(registryPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	addedToIced := false
-
-	// First pass: remove from Active Goals
-	activeGoalPattern := regexp.MustCompile(fmt.Sprintf(`^\| %s \|.*\| Active \|`, regexp.QuoteMeta(goalID)))
-	for _, line := range lines {
-		if activeGoalPattern.MatchString(line) {
-			continue // Skip this line (remove from active)
-		}
-		newLines = append(newLines, line)
-	}
-
-	// Second pass: add to Iced Goals section
-	var finalLines []string
-	for i, line := range newLines {
-		finalLines = append(finalLines, line)
-
-		// Look for Iced Goals table header
-		if !addedToIced && strings.Contains(line, "| ID | Title | Project(s) | Reason |") {
-			// Next line should be the separator
-			if i+1 < len(newLines) && strings.Contains(newLines[i+1], "|---") {
-				finalLines = append(finalLines, newLines[i+1])
-				// Add our iced goal entry
-				icedRow := fmt.Sprintf("| %s | %s | %s | %s |", goalID, goalTitle, project, reason)
-				finalLines = append(finalLines, icedRow)
-				addedToIced = true
-				// Skip the separator in the main loop since we already added it
-				newLines = append(newLines[:i+1], newLines[i+2:]...)
-			}
-		}
-	}
-
-	if !addedToIced {
-		finalLines = newLines // Fall back to just removing from active
-	}
-
-	return os.WriteFile(registryPath, []byte(strings.Join(finalLines, "\n")), 0644)
+	return goals.UpdateMarkdownFile(registryPath, func(doc *goals.MarkdownDoc) error {
+		return doc.SetRegistryGoal(goals.Goal{
+			ID:       goalID,
+			Title:    goalTitle,
+			Projects: []string{project},
+			Status:   "iced",
+			Reason:   reason,
+		})
+	})
 }
 
-// removeGoalFromProjectConfig removes a goal from the project's Active Goals section
-func removeGoalFromProjectConfig(configPath, goalID string) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-
-	// Pattern to match active goal entries: "- <id>: <title>" or "- #<id>: <title>"
-	activePattern := regexp.MustCompile(fmt.Sprintf(`^- #?%s:`, regexp.QuoteMeta(goalID)))
-
-	for _, line := range lines {
-		if activePattern.MatchString(line) {
-			continue // Skip this line
-		}
-		newLines = append(newLines, line)
-	}
-
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
+// markGoalIced records the goal as iced by user in the JSONL registry
+func markGoalIced(vegaDir, goalID, reason, user string) error {
+	return hub.NewLockManager(vegaDir).WithRegistryLock("ice-goal", func() error {
+		return goals.NewRegistry(vegaDir).Update(goalID, func(e *goals.RegistryEntry) {
+			e.Status = "iced"
+			e.Reason = reason
+			e.IcedBy = user
+			e.UpdatedAt = time.Now().Format(time.RFC3339)
+		})
+	})
 }
@@ -0,0 +1,91 @@
+package goal
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importID     string
+	importRename bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Load a goal exported from another vega-missile directory",
+	Long: `Load a bundle written by "vega-hub goal export" into this vega-missile
+directory, in the status the goal had (active, iced, completed or draft).
+
+If the goal's ID is already used here, the import fails unless --rename
+picks a new ID or --id gives one; the goal file and history then use the new
+ID. A parent goal, dependencies or an alias that don't exist here (or are
+taken) are dropped with a warning.
+
+The goal gets no worktree. A branch patch in the bundle is saved next to the
+goal file; apply it with POST /api/goals/<id>/apply-patch once the goal has a
+worktree.
+
+Examples:
+  vega-hub goal import goal-f3a8b2c.tar.gz
+  vega-hub goal import goal-f3a8b2c.tar.gz --rename`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImport,
+}
+
+func init() {
+	GoalCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importID, "id", "", "Import under this goal ID")
+	importCmd.Flags().BoolVar(&importRename, "rename", false, "Pick a new goal ID if the goal's ID is already used")
+}
+
+func runImport(c *cobra.Command, args []string) {
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		cli.OutputError(cli.ExitNotFound, "bundle_not_found", fmt.Sprintf("Could not open %s", args[0]),
+			map[string]string{"error": err.Error()}, nil)
+	}
+	defer f.Close()
+
+	result, imported := operations.ImportGoal(operations.ImportGoalOptions{
+		Bundle:  f,
+		NewID:   importID,
+		Rename:  importRename,
+		VegaDir: vegaDir,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		var options []cli.ErrorOption
+		switch result.Error.Code {
+		case "goal_exists":
+			exitCode = cli.ExitConflict
+			options = []cli.ErrorOption{
+				{Flag: "rename", Description: "Import under a new goal ID"},
+				{Flag: "id", Description: "Import under a goal ID of your choice"},
+			}
+		case "invalid_input", "invalid_bundle":
+			exitCode = cli.ExitValidationError
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, options)
+	}
+
+	if !cli.JSONOutput {
+		for _, w := range imported.Warnings {
+			cli.Warn("%s", w)
+		}
+	}
+	message := fmt.Sprintf("Imported goal %s (%s) as %s", imported.GoalID, imported.Title, imported.Status)
+	if imported.Renamed {
+		message = fmt.Sprintf("Imported goal %s as %s (%s), %s", imported.OriginalID, imported.GoalID, imported.Title, imported.Status)
+	}
+	cli.OutputSuccess("goal_import", message, imported)
+}
@@ -7,7 +7,9 @@ import (
 	"text/tabwriter"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/credentials"
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +17,8 @@ var (
 	listProject string
 	listStatus  string
 	listTree    bool
+	listView    string
+	listUser    string
 )
 
 // ListResult contains the result of listing goals
@@ -31,6 +35,7 @@ var listCmd = &cobra.Command{
 Filter by project or status:
   vega-hub goal list --project my-api
   vega-hub goal list --status active
+  vega-hub goal list --status draft
   vega-hub goal list --status iced
   vega-hub goal list --status completed
 
@@ -38,6 +43,10 @@ Display as tree (shows parent-child relationships):
   vega-hub goal list --tree
   vega-hub goal list --tree --project my-api
 
+Apply a saved view (shared with the web UI):
+  vega-hub goal list --view "my active backend goals"
+  vega-hub goal list --view default
+
 Use --json for structured output.`,
 	Run: runList,
 }
@@ -45,8 +54,10 @@ Use --json for structured output.`,
 func init() {
 	GoalCmd.AddCommand(listCmd)
 	listCmd.Flags().StringVarP(&listProject, "project", "p", "", "Filter by project name")
-	listCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Filter by status (active, iced, completed)")
+	listCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Filter by status (draft, active, iced, completed)")
 	listCmd.Flags().BoolVarP(&listTree, "tree", "t", false, "Display goals as tree (shows hierarchy)")
+	listCmd.Flags().StringVar(&listView, "view", "", "Apply a saved view by name or ID (\"default\" for your default view)")
+	listCmd.Flags().StringVar(&listUser, "user", "", "User whose saved views to use (default: current user)")
 }
 
 func runList(c *cobra.Command, args []string) {
@@ -74,6 +85,9 @@ func runList(c *cobra.Command, args []string) {
 
 	// Filter goals
 	filtered := filterGoals(allGoals, listProject, listStatus)
+	if listView != "" {
+		filtered = applyView(dir, filtered)
+	}
 
 	result := ListResult{
 		Goals: filtered,
@@ -189,14 +203,51 @@ func filterGoals(allGoals []goals.Goal, project, status string) []goals.Goal {
 	return filtered
 }
 
+// applyView filters and sorts goals with the --view saved view
+func applyView(dir string, goalList []goals.Goal) []goals.Goal {
+	user := listUser
+	if user == "" {
+		if u, err := credentials.GetCurrentUser(); err == nil {
+			user = u.Username
+		}
+	}
+
+	views := hub.NewSavedViews(dir)
+	var view *hub.SavedView
+	var err error
+	if listView == "default" {
+		view, err = views.Default(user)
+	} else {
+		view, err = views.Get(user, listView)
+	}
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "view_not_found", err.Error(),
+			map[string]string{"view": listView, "user": user}, nil)
+	}
+
+	var filtered []goals.Goal
+	for _, g := range goalList {
+		if view.Filter.Matches(g) {
+			filtered = append(filtered, g)
+		}
+	}
+	// Other sort orders need runtime state only the hub has; keep registry order
+	if view.Sort == "newest" {
+		for i, j := 0, len(filtered)-1; i < j; i, j = i+1, j-1 {
+			filtered[i], filtered[j] = filtered[j], filtered[i]
+		}
+	}
+	return filtered
+}
+
 func printGoalTable(goalList []goals.Goal) {
 	if len(goalList) == 0 {
 		return
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "\nID\tTITLE\tPROJECT(S)\tSTATUS\tPHASE")
-	fmt.Fprintln(w, "--\t-----\t----------\t------\t-----")
+	fmt.Fprintln(w, "\nID\tALIAS\tTITLE\tPROJECT(S)\tSTATUS\tPHASE")
+	fmt.Fprintln(w, "--\t-----\t-----\t----------\t------\t-----")
 
 	for _, g := range goalList {
 		projects := strings.Join(g.Projects, ", ")
@@ -204,8 +255,13 @@ func printGoalTable(goalList []goals.Goal) {
 		if phase == "" {
 			phase = "-"
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		alias := g.Alias
+		if alias == "" {
+			alias = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
 			g.ID,
+			alias,
 			truncate(g.Title, 40),
 			truncate(projects, 20),
 			g.Status,
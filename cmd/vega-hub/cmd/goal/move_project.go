@@ -0,0 +1,101 @@
+package goal
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveBaseBranch string
+	moveTransplant bool
+	moveForce      bool
+)
+
+var moveProjectCmd = &cobra.Command{
+	Use:   "move-project <goal-id> <project>",
+	Short: "Move a goal to another project",
+	Long: `Migrate an active goal to another project: it gets a new branch and worktree
+there and leaves the old project's config. The old worktree is removed; its
+branch is kept in the old project.
+
+With --transplant the goal's commits are applied to the new branch as
+patches (git projects only). If they don't apply, nothing changes.
+
+Examples:
+  vega-hub goal move-project f3a8b2c web-app
+  vega-hub goal move-project f3a8b2c web-app --transplant --base-branch dev`,
+	Args: cobra.ExactArgs(2),
+	Run:  runMoveProject,
+}
+
+func init() {
+	GoalCmd.AddCommand(moveProjectCmd)
+	moveProjectCmd.Flags().StringVar(&moveBaseBranch, "base-branch", "", "Base branch in the target project (default: from its config)")
+	moveProjectCmd.Flags().BoolVar(&moveTransplant, "transplant", false, "Carry the goal's commits over as patches")
+	moveProjectCmd.Flags().BoolVar(&moveForce, "force", false, "Remove the old worktree even with uncommitted changes")
+}
+
+func runMoveProject(c *cobra.Command, args []string) {
+	goalID := args[0]
+
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
+
+	result, data := operations.MoveGoalProject(operations.MoveProjectOptions{
+		GoalID:     goalID,
+		Project:    args[1],
+		BaseBranch: moveBaseBranch,
+		Transplant: moveTransplant,
+		Force:      moveForce,
+		VegaDir:    vegaDir,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		var options []cli.ErrorOption
+		switch result.Error.Code {
+		case "goal_not_found", "project_not_found":
+			exitCode = cli.ExitNotFound
+		case "not_active":
+			exitCode = cli.ExitStateError
+		case "uncommitted_changes":
+			exitCode = cli.ExitStateError
+			options = []cli.ErrorOption{{Flag: "force", Description: "Remove the old worktree anyway"}}
+		case "patch_conflict":
+			exitCode = cli.ExitConflict
+		case "invalid_input", "patch_unsupported":
+			exitCode = cli.ExitValidationError
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, options)
+	}
+
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "goal_move_project",
+		Message: fmt.Sprintf("Moved goal %s from %s to %s", goalID, data.FromProject, data.Project),
+		Data:    data,
+		NextSteps: []string{
+			fmt.Sprintf("Spawn executor: vega-hub executor spawn %s", goalID),
+		},
+	})
+	if !cli.JSONOutput {
+		if data.Branch != "" {
+			fmt.Printf("  Branch:       %s\n", data.Branch)
+		}
+		fmt.Printf("  Worktree:     %s\n", data.WorktreePath)
+		if moveTransplant {
+			fmt.Printf("  Transplanted: %d commit(s)\n", data.Transplanted)
+		}
+		if data.ArchivedBranch != "" {
+			fmt.Printf("  Old branch:   %s (kept in %s)\n", data.ArchivedBranch, data.FromProject)
+		}
+	}
+}
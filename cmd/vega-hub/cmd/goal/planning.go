@@ -108,6 +108,7 @@ func runPlanningList(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-hub directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	mgr := goals.NewPlanningFilesManager(vegaDir)
 	files, err := mgr.ListPlanningFiles(goalID)
@@ -172,6 +173,7 @@ func runPlanningGet(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-hub directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	mgr := goals.NewPlanningFilesManager(vegaDir)
 	content, err := mgr.GetPlanningFile(goalID, project, filename)
@@ -246,6 +248,7 @@ func runPlanningSet(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-hub directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	mgr := goals.NewPlanningFilesManager(vegaDir)
 	if err := mgr.SavePlanningFile(goalID, project, filename, content); err != nil {
@@ -0,0 +1,83 @@
+package goal
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renameBranch   bool
+	renameWorktree bool
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <goal-id> <title>",
+	Short: "Change a goal's title",
+	Long: `Change a goal's title in its goal file, the registry and the project config.
+With --branch the goal branch is renamed to match the title (local only; the
+remote branch keeps its name), and with --worktree the worktree directory is
+moved to match. Stop the goal's executor before moving its worktree.
+
+Examples:
+  vega-hub goal rename f3a8b2c "Add OAuth login"
+  vega-hub goal rename f3a8b2c "Add OAuth login" --branch --worktree`,
+	Args: cobra.ExactArgs(2),
+	Run:  runRename,
+}
+
+func init() {
+	GoalCmd.AddCommand(renameCmd)
+	renameCmd.Flags().BoolVar(&renameBranch, "branch", false, "Rename the goal branch to match")
+	renameCmd.Flags().BoolVar(&renameWorktree, "worktree", false, "Move the worktree directory to match")
+}
+
+func runRename(c *cobra.Command, args []string) {
+	goalID := args[0]
+
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
+
+	result, data := operations.RenameGoal(operations.RenameOptions{
+		GoalID:       goalID,
+		Title:        args[1],
+		RenameBranch: renameBranch,
+		MoveWorktree: renameWorktree,
+		VegaDir:      vegaDir,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		switch result.Error.Code {
+		case "goal_not_found":
+			exitCode = cli.ExitNotFound
+		case "worktree_exists":
+			exitCode = cli.ExitConflict
+		case "invalid_input", "rename_unsupported":
+			exitCode = cli.ExitValidationError
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, nil)
+	}
+
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "goal_rename",
+		Message: fmt.Sprintf("Renamed goal %s: %s", goalID, data.Title),
+		Data:    data,
+	})
+	if !cli.JSONOutput {
+		if data.BranchRenamed {
+			fmt.Printf("  Branch:   %s -> %s\n", data.OldBranch, data.Branch)
+		}
+		if data.WorktreeMoved {
+			fmt.Printf("  Worktree: %s -> %s\n", data.OldWorktree, data.Worktree)
+		}
+	}
+}
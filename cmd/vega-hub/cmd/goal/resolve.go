@@ -9,6 +9,7 @@ import (
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
 	"github.com/spf13/cobra"
 )
 
@@ -52,9 +53,10 @@ func runResolve(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Find worktree path
-	workspacesDir := filepath.Join(vegaDir, "workspaces", project)
+	workspacesDir := layout.New(vegaDir).ProjectWorkspace(project)
 	entries, err := os.ReadDir(workspacesDir)
 	if err != nil {
 		cli.OutputError(cli.ExitNotFound, "project_not_found",
@@ -82,6 +84,7 @@ func runResolve(cmd *cobra.Command, args []string) error {
 
 	checker := hub.NewConflictChecker(worktreePath)
 	stateManager := goals.NewStateManager(vegaDir)
+	user := currentUsername()
 
 	if resolveAbort {
 		// Abort the merge
@@ -94,7 +97,7 @@ func runResolve(cmd *cobra.Command, args []string) error {
 		}
 
 		// Update state back to working
-		stateManager.Transition(goalID, goals.StateWorking, "Merge aborted by user", nil)
+		stateManager.TransitionWithUser(goalID, goals.StateWorking, "Merge aborted by user", user, nil)
 
 		cli.Output(cli.Result{
 			Success: true,
@@ -151,7 +154,7 @@ func runResolve(cmd *cobra.Command, args []string) error {
 	// Update state from CONFLICT to WORKING (or MERGING if we're in completion flow)
 	currentState, _ := stateManager.GetState(goalID)
 	if currentState == goals.StateConflict {
-		stateManager.Transition(goalID, goals.StateWorking, "Conflicts resolved", nil)
+		stateManager.TransitionWithUser(goalID, goals.StateWorking, "Conflicts resolved", user, nil)
 	}
 
 	cli.Output(cli.Result{
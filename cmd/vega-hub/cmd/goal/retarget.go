@@ -0,0 +1,83 @@
+package goal
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	retargetProject  string
+	retargetStrategy string
+)
+
+var retargetCmd = &cobra.Command{
+	Use:   "retarget <goal-id> <base-branch>",
+	Short: "Change a goal's base branch",
+	Long: `Move a goal onto another base branch, e.g. from main to a release branch.
+
+The goal branch is rebased onto the new base (only the goal's own commits
+move), or with --strategy merge the new base is merged into it. The worktree
+must be clean; on a conflict the branch is left as it was. The goal then
+diffs against and completes into the new base.
+
+Examples:
+  vega-hub goal retarget f3a8b2c release/2.4
+  vega-hub goal retarget f3a8b2c release/2.4 --strategy merge`,
+	Args: cobra.ExactArgs(2),
+	Run:  runRetarget,
+}
+
+func init() {
+	GoalCmd.AddCommand(retargetCmd)
+	retargetCmd.Flags().StringVarP(&retargetProject, "project", "p", "", "Project (default: the goal's project with a worktree)")
+	retargetCmd.Flags().StringVar(&retargetStrategy, "strategy", "rebase", "How to move the branch: rebase or merge")
+}
+
+func runRetarget(c *cobra.Command, args []string) {
+	goalID := args[0]
+
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
+
+	result, data := operations.RetargetGoal(operations.RetargetOptions{
+		GoalID:     goalID,
+		Project:    retargetProject,
+		BaseBranch: args[1],
+		Strategy:   retargetStrategy,
+		VegaDir:    vegaDir,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		switch result.Error.Code {
+		case "goal_not_found", "worktree_not_found", "branch_not_found":
+			exitCode = cli.ExitNotFound
+		case "uncommitted_changes":
+			exitCode = cli.ExitStateError
+		case "retarget_failed":
+			exitCode = cli.ExitConflict
+		case "invalid_input", "plain_project", "retarget_unsupported":
+			exitCode = cli.ExitValidationError
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, nil)
+	}
+
+	message := fmt.Sprintf("Goal %s already targets %s", goalID, data.BaseBranch)
+	if data.Strategy != "" {
+		message = fmt.Sprintf("Moved goal %s from %s to %s", goalID, data.OldBaseBranch, data.BaseBranch)
+	}
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "goal_retarget",
+		Message: message,
+		Data:    data,
+	})
+}
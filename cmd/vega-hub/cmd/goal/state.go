@@ -90,6 +90,7 @@ func runState(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	sm := goals.NewStateManager(vegaDir)
 
@@ -232,6 +233,7 @@ func runSetState(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	sm := goals.NewStateManager(vegaDir)
 
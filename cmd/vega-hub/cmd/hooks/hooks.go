@@ -0,0 +1,25 @@
+package hooks
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// HooksCmd is the parent command for executor hook management
+var HooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage executor hooks in goal worktrees",
+	Long: `Manage the executor hooks, rules and settings copied into goal worktrees.
+
+Every worktree gets a copy of templates/project-init/.claude when it is
+created, and a manifest of the template version it received. Fixes to the
+templates don't reach existing worktrees until they are upgraded.
+
+Examples:
+  vega-hub hooks upgrade --dry-run --diff
+  vega-hub hooks upgrade
+  vega-hub hooks upgrade --goal abc1234 --force`,
+}
+
+func init() {
+	// Subcommands are added in their respective files
+}
@@ -0,0 +1,132 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeGoal   string
+	upgradeDryRun bool
+	upgradeForce  bool
+	upgradeDiff   bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Re-sync hook templates into existing worktrees",
+	Long: `Bring the .claude hooks, rules and settings.local.json of existing goal
+worktrees (and warm pooled worktrees) in line with templates/project-init.
+
+For each worktree:
+  - Template files missing from the worktree are added
+  - Outdated files are updated
+  - Files dropped from the templates are removed
+  - Files edited in the worktree since the last sync are kept (--force
+    overwrites them)
+
+Worktrees created before hook manifests existed are brought fully in line
+with the templates.
+
+Examples:
+  vega-hub hooks upgrade --dry-run          # Show what would change
+  vega-hub hooks upgrade --dry-run --diff   # ...with unified diffs
+  vega-hub hooks upgrade                    # Upgrade every worktree
+  vega-hub hooks upgrade --goal abc1234     # Upgrade one goal's worktree`,
+	Run: runUpgrade,
+}
+
+func init() {
+	HooksCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().StringVar(&upgradeGoal, "goal", "", "Only upgrade this goal's worktree")
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Show what would change without writing")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Overwrite hook files edited in a worktree")
+	upgradeCmd.Flags().BoolVar(&upgradeDiff, "diff", false, "Show a diff for each changed file")
+}
+
+func runUpgrade(c *cobra.Command, args []string) {
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+
+	result, data := operations.UpgradeHooks(operations.UpgradeHooksOptions{
+		GoalID:  upgradeGoal,
+		DryRun:  upgradeDryRun,
+		Force:   upgradeForce,
+		Diff:    upgradeDiff,
+		VegaDir: vegaDir,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		var options []cli.ErrorOption
+		switch result.Error.Code {
+		case "worktree_not_found":
+			exitCode = cli.ExitNotFound
+		case "no_templates":
+			exitCode = cli.ExitStateError
+			options = []cli.ErrorOption{{Action: "vega-hub init " + vegaDir, Description: "Restore the default hook templates"}}
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, options)
+	}
+
+	if !cli.JSONOutput {
+		for _, wt := range data.Worktrees {
+			name := wt.Project + "/" + wt.GoalID
+			if wt.GoalID == "" {
+				name = wt.Project + " (pool)"
+			}
+			from := wt.FromVersion
+			if from == "" {
+				from = "unversioned"
+			}
+			switch wt.Status {
+			case "up_to_date":
+				cli.Info("  = %s: up to date", name)
+			case "failed":
+				cli.Info("  ✗ %s: %s", name, wt.Error)
+			default:
+				cli.Info("  ↑ %s: %s → %s", name, from, data.TemplateVersion)
+			}
+			for _, change := range wt.Changes {
+				if change.Action == "skip" {
+					cli.Info("      skip   %s (%s)", change.Path, change.Reason)
+				} else {
+					cli.Info("      %-6s %s", change.Action, change.Path)
+				}
+				if change.Diff != "" {
+					fmt.Println(change.Diff)
+				}
+			}
+		}
+	}
+
+	verb := "Upgraded"
+	if data.DryRun {
+		verb = "Would upgrade"
+	}
+	message := fmt.Sprintf("%s %d of %d worktree(s) to hooks %s", verb, data.Upgraded, len(data.Worktrees), data.TemplateVersion)
+	var nextSteps []string
+	if data.Skipped > 0 {
+		nextSteps = append(nextSteps, fmt.Sprintf("%d locally edited file(s) kept; re-run with --force to overwrite them", data.Skipped))
+	}
+	if data.DryRun && data.Upgraded > 0 {
+		nextSteps = append(nextSteps, "Re-run without --dry-run to apply")
+	}
+	if data.Failed > 0 {
+		cli.OutputError(cli.ExitInternalError, "upgrade_failed",
+			fmt.Sprintf("%s; %d failed", message, data.Failed), nil, nil)
+	}
+	cli.Output(cli.Result{
+		Success:   true,
+		Action:    "hooks_upgrade",
+		Message:   message,
+		Data:      data,
+		NextSteps: nextSteps,
+	})
+}
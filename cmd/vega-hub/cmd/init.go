@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initProject string
+	initURL     string
+	initBranch  string
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [dir]",
+	Short: "Bootstrap a new vega-missile directory",
+	Long: `Create the directory layout vega-hub manages (default: current directory).
+
+This command will:
+  1. Check that git is available
+  2. Create goals/active, goals/iced and goals/history with an empty registry
+  3. Create projects/index.md and workspaces/
+  4. Create templates/project-init/.claude with the default executor hooks
+     (questions, session start and stop) that every project gets
+  5. Optionally add a first project
+
+Existing files are left untouched, so init can be re-run to restore
+anything missing from an existing directory.
+
+Examples:
+  vega-hub init ~/vega-missile
+  vega-hub init ~/vega-missile --project my-api --url https://github.com/user/my-api.git`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runInit,
+}
+
+func init() {
+	initCmd.Flags().StringVar(&initProject, "project", "", "Name of a first project to add")
+	initCmd.Flags().StringVar(&initURL, "url", "", "Git URL of the first project")
+	initCmd.Flags().StringVarP(&initBranch, "branch", "b", "", "Branch to check out for the first project (default: repo's default)")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	result, data := operations.Init(operations.InitOptions{
+		Dir:           dir,
+		ProjectName:   initProject,
+		ProjectURL:    initURL,
+		ProjectBranch: initBranch,
+	})
+	if !result.Success {
+		exitCode := cli.ExitInternalError
+		switch result.Error.Code {
+		case "invalid_project", "invalid_name", "not_a_directory":
+			exitCode = cli.ExitValidationError
+		case "git_not_found":
+			exitCode = cli.ExitStateError
+		case "project_exists", "workspace_exists":
+			exitCode = cli.ExitConflict
+		}
+		if data != nil && len(data.Created) > 0 {
+			cli.Warn("Created %s, but the first project could not be added", data.Dir)
+		}
+		cli.OutputError(exitCode, result.Error.Code, result.Error.Message, result.Error.Details, nil)
+	}
+
+	if !cli.JSONOutput {
+		for _, path := range data.Created {
+			cli.Info("  + %s", path)
+		}
+		if data.Project != nil {
+			cli.Info("  + project %s (%s)", data.Project.Name, data.Project.BaseBranch)
+		}
+	}
+	for _, w := range data.Warnings {
+		cli.Warn("%s", w)
+	}
+
+	message := fmt.Sprintf("Initialized vega-missile directory at %s", data.Dir)
+	if len(data.Created) == 0 {
+		message = fmt.Sprintf("%s is already initialized", data.Dir)
+	}
+	nextSteps := []string{fmt.Sprintf("vega-hub start --dir %s", data.Dir)}
+	if data.Project == nil {
+		nextSteps = append([]string{fmt.Sprintf("vega-hub project add <name> <git-url> --dir %s", data.Dir)}, nextSteps...)
+	}
+	cli.Output(cli.Result{
+		Success:   true,
+		Action:    "init",
+		Message:   message,
+		Data:      data,
+		NextSteps: nextSteps,
+	})
+}
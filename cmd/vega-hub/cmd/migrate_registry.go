@@ -1,16 +1,14 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
 	"github.com/spf13/cobra"
 )
 
@@ -34,96 +32,18 @@ func runMigrateRegistry(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect vega-missile directory: %w", err)
 	}
 	
-	registryPath := filepath.Join(vegaDir, "goals", "REGISTRY.md")
+	registryPath := layout.New(vegaDir).RegistryMarkdown()
 	
-	file, err := os.Open(registryPath)
+	content, err := os.ReadFile(registryPath)
 	if err != nil {
-		return fmt.Errorf("failed to open REGISTRY.md: %w", err)
+		return fmt.Errorf("failed to read REGISTRY.md: %w", err)
 	}
-	defer file.Close()
 
-	var entries []goals.RegistryEntry
-	scanner := bufio.NewScanner(file)
-	section := ""
+	entries := goals.ParseRegistryMarkdown(string(content))
 	now := time.Now().Format(time.RFC3339)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Detect sections
-		if strings.HasPrefix(line, "## Active Goals") {
-			section = "active"
-			continue
-		} else if strings.HasPrefix(line, "## Iced Goals") {
-			section = "iced"
-			continue
-		} else if strings.HasPrefix(line, "## Completed Goals") {
-			section = "completed"
-			continue
-		} else if strings.HasPrefix(line, "## ") || strings.HasPrefix(line, "---") {
-			section = ""
-			continue
-		}
-
-		// Skip non-table lines
-		if !strings.HasPrefix(line, "|") || strings.Contains(line, "---") || strings.Contains(line, "| ID |") {
-			continue
-		}
-
-		// Parse table row
-		parts := strings.Split(line, "|")
-		if len(parts) < 4 {
-			continue
-		}
-
-		// Clean parts
-		cleanParts := make([]string, 0)
-		for _, p := range parts {
-			p = strings.TrimSpace(p)
-			if p != "" {
-				cleanParts = append(cleanParts, p)
-			}
-		}
-
-		if len(cleanParts) < 3 || cleanParts[0] == "" {
-			continue
-		}
-
-		entry := goals.RegistryEntry{
-			ID:        cleanParts[0],
-			Title:     cleanParts[1],
-			Projects:  parseProjectList(cleanParts[2]),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-
-		switch section {
-		case "active":
-			entry.Status = "active"
-			if len(cleanParts) >= 5 {
-				entry.Phase = cleanParts[4]
-			} else {
-				entry.Phase = "1/?"
-			}
-		case "iced":
-			entry.Status = "iced"
-			if len(cleanParts) >= 4 {
-				entry.Reason = cleanParts[3]
-			}
-		case "completed":
-			entry.Status = "completed"
-			if len(cleanParts) >= 4 {
-				entry.CompletedAt = cleanParts[3]
-			}
-		default:
-			continue
-		}
-
-		entries = append(entries, entry)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read REGISTRY.md: %w", err)
+	for i := range entries {
+		entries[i].CreatedAt = now
+		entries[i].UpdatedAt = now
 	}
 
 	// Count by status
@@ -161,15 +81,3 @@ func runMigrateRegistry(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
-
-func parseProjectList(s string) []string {
-	projects := strings.Split(s, ",")
-	result := make([]string, 0, len(projects))
-	for _, p := range projects {
-		p = strings.TrimSpace(p)
-		if p != "" {
-			result = append(result, p)
-		}
-	}
-	return result
-}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate vega-missile data to newer formats",
+}
+
+var migrateStatesCmd = &cobra.Command{
+	Use:   "states [goal-id...]",
+	Short: "Backfill state files for goals created before the state machine",
+	Long: `Infers an initial state for legacy goals and writes their .state.jsonl files.
+
+Goals without state history report "working" regardless of reality. This
+command infers their state from:
+  - registry status (completed -> done, iced -> iced)
+  - worktree existence (active with worktree -> working)
+  - branch existence (active, branch but no worktree -> failed)
+  - executor history (active, sessions but no branch -> failed, otherwise pending)
+
+Goals that already have state history are left alone.
+
+Examples:
+  vega-hub migrate states --dry-run     # Show what would be inferred
+  vega-hub migrate states               # Write state files for all legacy goals
+  vega-hub migrate states f3a8b2c       # Migrate a single goal`,
+	Run: runMigrateStates,
+}
+
+var migrateStatesDryRun bool
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateStatesCmd)
+	migrateStatesCmd.Flags().BoolVar(&migrateStatesDryRun, "dry-run", false, "Show inferred states without writing")
+}
+
+// MigrateStatesResult contains the report of a state migration
+type MigrateStatesResult struct {
+	DryRun   bool                   `json:"dry_run"`
+	Migrated int                    `json:"migrated"`
+	Skipped  int                    `json:"skipped"`
+	Goals    []goals.StateMigration `json:"goals"`
+}
+
+func runMigrateStates(c *cobra.Command, args []string) {
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_directory", err.Error(), nil, []cli.ErrorOption{
+			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
+		})
+	}
+
+	migrations, err := goals.MigrateStates(vegaDir, goals.MigrateStatesOptions{
+		GoalIDs: args,
+		DryRun:  migrateStatesDryRun,
+	})
+	if err != nil {
+		cli.OutputError(cli.ExitInternalError, "migration_failed", err.Error(), nil, nil)
+	}
+
+	result := MigrateStatesResult{DryRun: migrateStatesDryRun, Goals: migrations}
+	for _, m := range migrations {
+		if m.Skipped != "" {
+			result.Skipped++
+		} else {
+			result.Migrated++
+		}
+	}
+
+	verb := "Migrated"
+	if migrateStatesDryRun {
+		verb = "Would migrate"
+	}
+	cli.Output(cli.Result{
+		Success: true,
+		Action:  "migrate_states",
+		Message: fmt.Sprintf("%s %d goal(s), skipped %d", verb, result.Migrated, result.Skipped),
+		Data:    result,
+	})
+
+	// Human-readable report
+	if !cli.JSONOutput {
+		for _, m := range migrations {
+			if m.Skipped != "" {
+				fmt.Printf("\n  %s  skipped (%s)\n", m.GoalID, m.Skipped)
+				continue
+			}
+			fmt.Printf("\n  %s  %-8s %s\n", m.GoalID, m.State, m.Title)
+			fmt.Printf("           %s\n", strings.Join(m.Evidence, "; "))
+		}
+	}
+}
@@ -9,11 +9,17 @@ import (
 	"strings"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+	"github.com/lasmarois/vega-hub/internal/operations"
 	"github.com/spf13/cobra"
 )
 
 var (
 	addBranch string
+	addDepth  int
+	addFilter string
+	addSparse []string
 )
 
 // AddResult contains the result of adding a project
@@ -33,6 +39,12 @@ var addCmd = &cobra.Command{
 Examples:
   vega-hub project add my-api https://github.com/user/my-api.git
   vega-hub project add my-api https://github.com/user/my-api.git --branch dev
+  vega-hub project add mono https://github.com/user/mono.git --filter blob:none --sparse services/api
+
+Large repositories:
+  --depth N            Shallow clone with N commits of history
+  --filter blob:none   Partial clone: fetch file contents on demand
+  --sparse DIR         Only check out DIR (repeatable); applied to goal worktrees too
 
 This command will:
   1. Clone the repository into workspaces/<name>/worktree-base/
@@ -47,6 +59,9 @@ This command will:
 func init() {
 	ProjectCmd.AddCommand(addCmd)
 	addCmd.Flags().StringVarP(&addBranch, "branch", "b", "", "Branch to check out (default: repo's default)")
+	addCmd.Flags().IntVar(&addDepth, "depth", 0, "Shallow clone depth (default: full history)")
+	addCmd.Flags().StringVar(&addFilter, "filter", "", "Partial clone filter, e.g. blob:none")
+	addCmd.Flags().StringSliceVar(&addSparse, "sparse", nil, "Sparse-checkout directories (repeatable)")
 }
 
 func runAdd(c *cobra.Command, args []string) {
@@ -64,6 +79,11 @@ func runAdd(c *cobra.Command, args []string) {
 			nil)
 	}
 
+	clone := goals.CloneOptions{CloneDepth: addDepth, CloneFilter: addFilter, SparsePaths: addSparse}
+	if err := clone.Validate(); err != nil {
+		cli.OutputError(cli.ExitValidationError, "invalid_clone_options", err.Error(), nil, nil)
+	}
+
 	// Get vega-missile directory
 	vegaDir, err := cli.GetVegaDir()
 	if err != nil {
@@ -73,7 +93,7 @@ func runAdd(c *cobra.Command, args []string) {
 	}
 
 	// Check if project already exists
-	projectBase := filepath.Join(vegaDir, "workspaces", name, "worktree-base")
+	projectBase := layout.New(vegaDir).WorktreeBase(name)
 	if _, err := os.Stat(projectBase); err == nil {
 		cli.OutputError(cli.ExitConflict, "project_exists",
 			fmt.Sprintf("Project '%s' already exists", name),
@@ -95,7 +115,7 @@ func runAdd(c *cobra.Command, args []string) {
 	}
 
 	// Step 1: Create workspaces directory and clone
-	workspacesDir := filepath.Join(vegaDir, "workspaces", name)
+	workspacesDir := layout.New(vegaDir).ProjectWorkspace(name)
 	if err := os.MkdirAll(workspacesDir, 0755); err != nil {
 		cli.OutputError(cli.ExitInternalError, "mkdir_failed",
 			"Failed to create workspaces directory",
@@ -105,7 +125,7 @@ func runAdd(c *cobra.Command, args []string) {
 	rollback = append(rollback, func() { os.RemoveAll(workspacesDir) })
 
 	cli.Info("Cloning repository...")
-	if err := gitClone(gitURL, projectBase); err != nil {
+	if err := gitClone(gitURL, projectBase, clone); err != nil {
 		doRollback()
 		cli.OutputError(cli.ExitStateError, "clone_failed",
 			"Failed to clone repository",
@@ -141,11 +161,15 @@ func runAdd(c *cobra.Command, args []string) {
 
 	// Step 3: Set up .claude/ structure
 	cli.Info("Setting up .claude/ structure...")
-	templateDir := filepath.Join(vegaDir, "templates", "project-init", ".claude")
+	templateDir := filepath.Join(layout.New(vegaDir).ProjectInitTemplate(), ".claude")
 	destDir := filepath.Join(projectBase, ".claude")
 	if err := copyDir(templateDir, destDir); err != nil {
 		cli.Warn("Could not copy .claude template: %v", err)
 	}
+	// Fill in the hub address in the hooks
+	if err := operations.CopyHooksToWorktree(vegaDir, projectBase); err != nil {
+		cli.Warn("Could not install hooks: %v", err)
+	}
 
 	// Create docs/planning directories
 	planningDirs := []string{
@@ -158,14 +182,14 @@ func runAdd(c *cobra.Command, args []string) {
 
 	// Step 4: Create project config file
 	cli.Info("Creating project config...")
-	configFile := filepath.Join(vegaDir, "projects", name+".md")
-	if err := createProjectConfig(configFile, name, gitURL, branch); err != nil {
+	configFile := layout.New(vegaDir).ProjectConfig(name)
+	if err := createProjectConfig(configFile, name, gitURL, branch, clone); err != nil {
 		cli.Warn("Could not create project config: %v", err)
 	}
 
 	// Step 5: Update projects/index.md
 	cli.Info("Updating project index...")
-	indexFile := filepath.Join(vegaDir, "projects", "index.md")
+	indexFile := layout.New(vegaDir).ProjectIndex()
 	if err := addProjectToIndex(indexFile, name); err != nil {
 		cli.Warn("Could not update project index: %v", err)
 	}
@@ -204,14 +228,15 @@ func isValidProjectName(name string) bool {
 	return re.MatchString(name)
 }
 
-// gitClone clones a repository
-func gitClone(url, dest string) error {
-	cmd := exec.Command("git", "clone", url, dest)
+// gitClone clones a repository, shallow, partial or sparse per clone
+func gitClone(url, dest string, clone goals.CloneOptions) error {
+	args := append([]string{"clone"}, clone.CloneArgs()...)
+	cmd := exec.Command("git", append(args, url, dest)...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("%s: %s", err, string(output))
 	}
-	return nil
+	return operations.ApplySparseCheckout(dest, clone)
 }
 
 // gitCheckout checks out a branch
@@ -235,7 +260,7 @@ func getCurrentBranch(repoPath string) (string, error) {
 }
 
 // createProjectConfig creates a project config file from template
-func createProjectConfig(path, name, gitURL, branch string) error {
+func createProjectConfig(path, name, gitURL, branch string, clone goals.CloneOptions) error {
 	content := fmt.Sprintf(`# Project: %s
 
 ## Overview
@@ -247,17 +272,8 @@ _Brief description of the project_
 - **Workspace**: %s
 - **Base Branch**: %s
 - **Upstream**: %s
-
-## Active Goals
-
-_None currently active_
-
-## Completed Goals
-
-| ID | Title | Completed |
-|----|-------|-----------|
-| | | |
-
+%s
+%s
 ## Project Context
 
 ### Key Directories
@@ -276,6 +292,8 @@ When working on this project:
 		fmt.Sprintf("`workspaces/%s/worktree-base/`", name),
 		fmt.Sprintf("`%s`", branch),
 		fmt.Sprintf("`%s`", gitURL),
+		formatCloneConfig(clone),
+		(&goals.ProjectGoals{}).Markdown(),
 		"`src/`",
 		"`planning-with-files`",
 		"`Goal: <id>`")
@@ -283,34 +301,26 @@ When working on this project:
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
-// addProjectToIndex adds a project to the index.md file
-func addProjectToIndex(indexPath, name string) error {
-	content, err := os.ReadFile(indexPath)
-	if err != nil {
-		return err
+// formatCloneConfig renders the clone option lines of the Configuration list
+func formatCloneConfig(clone goals.CloneOptions) string {
+	var b strings.Builder
+	if clone.CloneDepth > 0 {
+		fmt.Fprintf(&b, "- **Clone Depth**: `%d`\n", clone.CloneDepth)
 	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	added := false
-
-	for i, line := range lines {
-		newLines = append(newLines, line)
-
-		// Find the table and add after the header separator
-		if !added && strings.Contains(line, "|---") {
-			// Check if this is in the projects table (has Project header)
-			if i > 0 && strings.Contains(lines[i-1], "| Project |") {
-				// Add new project row
-				projectRow := fmt.Sprintf("| [%s](%s.md) | `workspaces/%s/worktree-base/` | - | _Add description_ |",
-					name, name, name)
-				newLines = append(newLines, projectRow)
-				added = true
-			}
-		}
+	if clone.CloneFilter != "" {
+		fmt.Fprintf(&b, "- **Clone Filter**: `%s`\n", clone.CloneFilter)
 	}
+	if clone.IsSparse() {
+		fmt.Fprintf(&b, "- **Sparse Paths**: `%s`\n", strings.Join(clone.SparsePaths, "`, `"))
+	}
+	return b.String()
+}
 
-	return os.WriteFile(indexPath, []byte(strings.Join(newLines, "\n")), 0644)
+// addProjectToIndex adds a project to the index.md file
+func addProjectToIndex(indexPath, name string) error {
+	return goals.UpdateMarkdownFile(indexPath, func(doc *goals.MarkdownDoc) error {
+		return doc.AddIndexProject(name)
+	})
 }
 
 // copyDir recursively copies a directory (reused from goal/create.go)
@@ -6,6 +6,7 @@ import (
 	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/credentials"
 	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/executor"
 	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/goal"
+	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/hooks"
 	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/lock"
 	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/project"
 	"github.com/lasmarois/vega-hub/cmd/vega-hub/cmd/worktree"
@@ -43,6 +44,8 @@ Run 'vega-hub serve' to start the server, or use subcommands for other operation
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
+	// Version is set by main after this package's init has run
+	rootCmd.Version = Version
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -64,4 +67,5 @@ func init() {
 	rootCmd.AddCommand(credentials.CredentialsCmd)
 	rootCmd.AddCommand(worktree.WorktreeCmd)
 	rootCmd.AddCommand(lock.LockCmd)
+	rootCmd.AddCommand(hooks.HooksCmd)
 }
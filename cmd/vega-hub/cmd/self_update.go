@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+// SelfUpdateResult contains the result of a self-update
+type SelfUpdateResult struct {
+	Previous string `json:"previous"`
+	Version  string `json:"version"`
+	Path     string `json:"path,omitempty"`
+	Updated  bool   `json:"updated"`
+}
+
+var (
+	selfUpdateVersion string
+	selfUpdateForce   bool
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update vega-hub to the latest release",
+	Long: `Download the latest vega-hub release for this platform and replace
+the running binary with it.
+
+The binary is verified against the release's checksums.txt before it is
+installed; releases without checksums are refused. The new binary is
+staged next to the old one and renamed over it, so a failed update leaves
+the current binary untouched. A running server keeps the old binary until
+it is restarted.
+
+Set GITHUB_TOKEN to avoid API rate limits, and VEGA_HUB_RELEASES_API to
+use a GitHub Enterprise mirror.
+
+Example:
+  vega-hub self-update
+  vega-hub self-update --version 0.4.1   # Install a specific release
+  vega-hub version --check               # Only check for updates`,
+	Run: runSelfUpdate,
+}
+
+func init() {
+	selfUpdateCmd.Flags().StringVar(&selfUpdateVersion, "version", "", "Install this release instead of the latest")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Install even if the release isn't newer")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) {
+	exe, err := os.Executable()
+	if err != nil {
+		cli.OutputError(cli.ExitInternalError, "no_executable", fmt.Sprintf("Failed to locate the vega-hub binary: %v", err), nil, nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	client := selfupdate.NewClient()
+
+	var release *selfupdate.Release
+	if selfUpdateVersion != "" {
+		release, err = client.Release(ctx, selfUpdateVersion)
+	} else {
+		release, err = client.Latest(ctx)
+	}
+	if err != nil {
+		cli.OutputError(cli.ExitInternalError, "release_failed", err.Error(), nil, nil)
+	}
+
+	result := SelfUpdateResult{Previous: Version, Version: release.TagName, Path: exe}
+
+	// A pinned version is installed even when older, to allow rollbacks
+	if selfUpdateVersion == "" && !selfUpdateForce && !selfupdate.Newer(release.TagName, Version) {
+		result.Version = Version
+		cli.OutputSuccess("self-update", fmt.Sprintf("vega-hub %s is already up to date", Version), result)
+		return
+	}
+
+	if Version == "dev" && !selfUpdateForce {
+		cli.OutputError(cli.ExitStateError, "dev_build",
+			"This is a development build; updating would replace it with a release", nil,
+			[]cli.ErrorOption{{Flag: "force", Description: "Replace the development build anyway"}})
+	}
+
+	cli.Info("Downloading vega-hub %s for %s/%s...", release.TagName, runtime.GOOS, runtime.GOARCH)
+	data, err := client.Download(ctx, release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		cli.OutputError(cli.ExitInternalError, "download_failed", err.Error(), map[string]string{
+			"release": release.HTMLURL,
+		}, nil)
+	}
+
+	if err := selfupdate.Replace(exe, data); err != nil {
+		cli.OutputError(cli.ExitInternalError, "replace_failed", err.Error(), map[string]string{
+			"path": exe,
+		}, []cli.ErrorOption{{Action: "permissions", Description: "Re-run with write access to the binary's directory"}})
+	}
+
+	result.Updated = true
+	cli.OutputSuccess("self-update", fmt.Sprintf("Updated vega-hub %s → %s", Version, release.TagName), result)
+}
@@ -5,18 +5,33 @@ import (
 	"fmt"
 	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/lasmarois/vega-hub/internal/api"
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/lasmarois/vega-hub/internal/slack"
 	"github.com/spf13/cobra"
 )
 
 var (
-	servePort int
+	servePort            int
+	serveCompressMinSize int
+	serveWatchIgnore     []string
+	serveWebhooks        []string
+	serveWebhookEvents   []string
+	serveAnswerGrace     time.Duration
+	serveSocket          bool
+	serveExecutorAuth    string
+	serveExecutorTTL     time.Duration
+	serveBaseCheck       time.Duration
+	serveCICheck         time.Duration
 )
 
 // WebFS is set by main.go to provide embedded web files
@@ -40,6 +55,16 @@ Use 'vega-hub start' for daemon mode with automatic port management.`,
 func init() {
 	rootCmd.AddCommand(serveCmd)
 	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringSliceVar(&serveWatchIgnore, "watch-ignore", hub.DefaultWatchIgnore, "Directory name patterns the file watcher skips")
+	serveCmd.Flags().StringArrayVar(&serveWebhooks, "webhook", nil, "URL to POST hub events to as JSON (repeatable)")
+	serveCmd.Flags().StringSliceVar(&serveWebhookEvents, "webhook-events", nil, "Event types sent to webhooks (default: all)")
+	serveCmd.Flags().DurationVar(&serveAnswerGrace, "answer-grace", 0, "How long answers can be edited or retracted before executors receive them (0 delivers immediately)")
+	serveCmd.Flags().BoolVar(&serveSocket, "socket", false, "Also listen on a unix socket ("+operations.HubSocketFile+" in the vega-missile directory), which executor hooks then use")
+	serveCmd.Flags().StringVar(&serveExecutorAuth, "executor-auth", string(hub.ExecutorAuthSpawned), "Which requests to the executor endpoints (ask, stop, pending messages) need the token vega-hub issues to spawned executors: spawned (for goals with a spawned executor), all, or off (for legacy executors)")
+	serveCmd.Flags().DurationVar(&serveExecutorTTL, "executor-token-ttl", hub.DefaultExecutorTokenTTL, "How long an unused executor token stays valid")
+	serveCmd.Flags().DurationVar(&serveBaseCheck, "base-check-interval", operations.DefaultBaseCheckInterval, "How often to fetch origin and check how far project base branches are behind (0 disables)")
+	serveCmd.Flags().DurationVar(&serveCICheck, "ci-check-interval", operations.DefaultCICheckInterval, "How often to poll GitHub checks or GitLab pipelines of active goal branches (0 disables)")
+	serveCmd.Flags().IntVar(&serveCompressMinSize, "compress-min-size", api.DefaultCompressMinSize, "Minimum response size in bytes to compress (negative disables compression)")
 }
 
 // spaHandler serves static files and falls back to index.html for SPA routes
@@ -77,11 +102,24 @@ func runServe(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if !hub.ValidExecutorAuthModes[hub.ExecutorAuthMode(serveExecutorAuth)] {
+		cli.OutputError(cli.ExitValidationError, "invalid_executor_auth", "--executor-auth must be spawned, all or off", nil, nil)
+	}
+
 	// Initialize the hub and goals parser
 	h := hub.New(dir)
 	h.SetPort(servePort) // Store port for executor env injection
+	h.SetAnswerGrace(serveAnswerGrace)
+	h.SetExecutorAuth(hub.ExecutorAuthMode(serveExecutorAuth))
+	h.SetExecutorTokenTTL(serveExecutorTTL)
 	p := goals.NewParser(dir)
 
+	// Forward hub events to webhooks
+	for _, url := range serveWebhooks {
+		h.Bus().Subscribe(hub.NewWebhook(url, serveWebhookEvents))
+		log.Printf("Sending events to webhook %s", url)
+	}
+
 	// Check for stuck goals on startup (recovery logic)
 	log.Println("Checking for stuck goals...")
 	stuckInfo := h.RecoverStuckGoals()
@@ -91,15 +129,47 @@ func runServe(cmd *cobra.Command, args []string) {
 
 	// Start file watcher for real-time updates
 	if dir != "" {
+		h.SetWatchIgnore(serveWatchIgnore)
 		if err := h.StartFileWatcher(); err != nil {
 			log.Printf("Warning: could not start file watcher: %v", err)
 		}
 	}
 
+	// Keep prewarmed worktrees ready for projects with a worktree pool
+	if dir != "" {
+		operations.StartWorktreePoolMaintainer(dir, operations.DefaultPoolInterval, nil)
+	}
+
+	// Track how far project base branches are behind origin
+	if dir != "" && serveBaseCheck > 0 {
+		operations.StartBaseFreshnessMonitor(dir, serveBaseCheck, nil)
+	}
+
+	// Surface CI of goal branches pushed to GitHub or GitLab
+	if dir != "" && serveCICheck > 0 {
+		operations.StartCIMonitor(dir, serveCICheck, func(s operations.CIStatus) { api.EmitCIStatus(h, s) }, nil)
+	}
+
+	// Email digests to users who opted in via .vega-hub-digest.json
+	if dir != "" {
+		h.StartDigestScheduler(nil)
+	}
+
+	// Compress and prune old session data per .vega-hub-retention.json
+	if dir != "" {
+		h.StartRetentionCompactor(nil)
+	}
+
 	// Set up API routes
 	mux := http.NewServeMux()
 	api.RegisterRoutes(mux, h, p)
 
+	// Answer questions from Slack when VEGA_HUB_SLACK_SIGNING_SECRET is set
+	if slackCfg := slack.ConfigFromEnv(); slackCfg.Enabled() {
+		api.RegisterSlackRoutes(mux, h, slackCfg)
+		log.Println("Slack integration enabled: /api/slack/interactions, /api/slack/commands")
+	}
+
 	// Serve static files from embedded filesystem
 	if os.Getenv("VEGA_HUB_DEV") == "true" {
 		log.Println("Development mode: frontend at http://localhost:5173")
@@ -119,7 +189,39 @@ func runServe(cmd *cobra.Command, args []string) {
 		log.Printf("Managing directory: %s", dir)
 	}
 
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	// A panic fails only its request, with an incident ID to find it in the log
+	var handler http.Handler = api.RecoverHandler(mux, h)
+
+	// Compress API responses and static assets when the client supports it
+	if serveCompressMinSize >= 0 {
+		handler = api.CompressHandler(handler, serveCompressMinSize)
+	}
+
+	// Hooks installed in new worktrees are generated with this hub's address
+	if dir != "" {
+		if err := writePortFile(dir, servePort); err != nil {
+			log.Printf("Warning: could not write port file: %v", err)
+		}
+	}
+	if serveSocket {
+		if dir == "" {
+			cli.OutputError(cli.ExitValidationError, "no_directory", "--socket needs a vega-missile directory", nil, nil)
+		}
+		socketPath := filepath.Join(dir, operations.HubSocketFile)
+		os.Remove(socketPath) // Left over from a previous run
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			cli.OutputError(cli.ExitInternalError, "server_failed", fmt.Sprintf("Could not listen on %s: %v", socketPath, err), nil, nil)
+		}
+		log.Printf("Listening on unix socket %s", socketPath)
+		go func() {
+			if err := http.Serve(listener, handler); err != nil {
+				log.Printf("Warning: unix socket server stopped: %v", err)
+			}
+		}()
+	}
+
+	if err := http.ListenAndServe(addr, handler); err != nil {
 		cli.OutputError(cli.ExitInternalError, "server_failed", fmt.Sprintf("Server failed: %v", err), nil, nil)
 	}
 }
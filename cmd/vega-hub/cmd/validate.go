@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the goals, registry and state files of the vega-missile directory",
+	Long: `Check the integrity of the vega-missile directory without changing it,
+for running in CI on every change to the directory.
+
+Checks:
+  - goal_files: every goal file has a "# Goal #<id>: <title>" heading for its ID
+  - goal_ids:   goal IDs are well-formed and each goal has one goal file
+  - registry:   registry rows match the goal files and their folders
+  - hierarchy:  parent links exist, are acyclic and at most 3 levels deep
+  - states:     state files hold only known states and valid transitions
+
+Exits 0 when the directory is valid and 2 when a finding is an error (or a
+warning, with --strict). Use --json for machine-readable findings.
+
+Example:
+  vega-hub validate
+  vega-hub validate --dir /path/to/vega-missile --json --strict`,
+	Run: runValidate,
+}
+
+var validateStrict bool
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Fail on warnings too")
+}
+
+func runValidate(cmd *cobra.Command, args []string) {
+	vegaDir, err := cli.GetVegaDir()
+	if err != nil {
+		cli.OutputError(cli.ExitValidationError, "no_vega_dir",
+			"No vega-missile directory found",
+			map[string]string{"error": err.Error()},
+			[]cli.ErrorOption{{Flag: "dir", Description: "Point at the vega-missile directory"}})
+	}
+
+	report := goals.Validate(vegaDir)
+	if !cli.JSONOutput {
+		for _, f := range report.Findings {
+			icon := "⚠"
+			if f.Severity == goals.SeverityError {
+				icon = "✗"
+			}
+			location := f.Path
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d", f.Path, f.Line)
+			}
+			if location != "" {
+				fmt.Printf("%s %s: %s: %s\n", icon, f.Check, location, f.Message)
+			} else {
+				fmt.Printf("%s %s: %s\n", icon, f.Check, f.Message)
+			}
+		}
+		if len(report.Findings) > 0 {
+			fmt.Println()
+		}
+	}
+
+	failed := !report.Valid || (validateStrict && report.Warnings > 0)
+	message := fmt.Sprintf("%d goal(s) checked: %d error(s), %d warning(s)", report.Goals, report.Errors, report.Warnings)
+	if !failed {
+		cli.OutputSuccess("validate", message, report)
+		return
+	}
+
+	cli.Output(cli.Result{
+		Success: false,
+		Action:  "validate",
+		Message: message,
+		Data:    report,
+		Error: &cli.ErrorInfo{
+			Code:    "validation_failed",
+			Message: message,
+			Details: map[string]string{
+				"errors":   fmt.Sprint(report.Errors),
+				"warnings": fmt.Sprint(report.Warnings),
+			},
+		},
+	})
+	os.Exit(cli.ExitStateError)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+// VersionResult describes the running binary
+type VersionResult struct {
+	Version  string                  `json:"version"`
+	Platform string                  `json:"platform"`
+	Check    *selfupdate.CheckResult `json:"check,omitempty"`
+}
+
+var versionCheck bool
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the vega-hub version",
+	Long: `Show the version and platform of this vega-hub binary.
+
+With --check, also ask GitHub for the latest release and report whether
+an update is available. Run 'vega-hub self-update' to install it.
+
+Example:
+  vega-hub version
+  vega-hub version --check --json`,
+	Run: runVersion,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check for a newer release")
+	rootCmd.AddCommand(versionCmd)
+}
+
+func runVersion(cmd *cobra.Command, args []string) {
+	result := VersionResult{
+		Version:  Version,
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if !versionCheck {
+		cli.OutputSuccess("version", fmt.Sprintf("vega-hub %s (%s)", result.Version, result.Platform), result)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	check, _, err := selfupdate.NewClient().Check(ctx, Version)
+	if err != nil {
+		cli.OutputError(cli.ExitInternalError, "check_failed", err.Error(), nil, nil)
+	}
+	result.Check = check
+
+	output := cli.Result{
+		Success: true,
+		Action:  "version",
+		Message: fmt.Sprintf("vega-hub %s is up to date", Version),
+		Data:    result,
+	}
+	if check.UpdateAvailable {
+		output.Message = fmt.Sprintf("vega-hub %s is available (running %s)", check.Latest, Version)
+		output.NextSteps = []string{"Run 'vega-hub self-update' to install it", check.URL}
+	}
+	cli.Output(output)
+}
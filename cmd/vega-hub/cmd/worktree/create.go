@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+	"github.com/lasmarois/vega-hub/internal/operations"
 	"github.com/spf13/cobra"
 )
 
@@ -62,6 +63,7 @@ func runCreate(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Check if worktree already exists
 	existingPath, _, _ := findWorktreeForGoal(vegaDir, goalID)
@@ -115,7 +117,7 @@ func runCreate(c *cobra.Command, args []string) {
 	project := goal.Projects[0]
 
 	// Validate project exists
-	projectBase := filepath.Join(vegaDir, "workspaces", project, "worktree-base")
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
 	if _, err := os.Stat(projectBase); os.IsNotExist(err) {
 		cli.OutputError(cli.ExitNotFound, "project_not_found",
 			fmt.Sprintf("Project '%s' not found", project),
@@ -166,7 +168,7 @@ func runCreate(c *cobra.Command, args []string) {
 	branchExists := verifyBranchExists(projectBase, goalBranch) == nil
 
 	// Create worktree path
-	worktreePath := filepath.Join(vegaDir, "workspaces", project, fmt.Sprintf("goal-%s-%s", goalID, slug))
+	worktreePath := layout.New(vegaDir).Workspace(project, fmt.Sprintf("goal-%s-%s", goalID, slug))
 
 	// Create the worktree
 	var cmd *exec.Cmd
@@ -192,8 +194,13 @@ func runCreate(c *cobra.Command, args []string) {
 			})
 	}
 
+	// Large repositories only check out the project's sparse paths
+	if err := operations.SparseCheckoutWorktree(vegaDir, project, worktreePath); err != nil {
+		cli.Warn("Failed to apply sparse checkout: %v", err)
+	}
+
 	// Copy hooks and rules to worktree
-	if err := setupWorktreeEnvironment(vegaDir, worktreePath); err != nil {
+	if err := operations.CopyHooksToWorktree(vegaDir, worktreePath); err != nil {
 		cli.Warn("Failed to copy hooks to worktree: %v", err)
 	}
 
@@ -257,96 +264,16 @@ func slugify(title string) string {
 	return slug
 }
 
-// setupWorktreeEnvironment copies hooks and rules to the worktree
-func setupWorktreeEnvironment(vegaDir, worktreePath string) error {
-	templateDir := filepath.Join(vegaDir, "templates", "project-init", ".claude")
-	destDir := filepath.Join(worktreePath, ".claude")
-
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return err
-	}
-
-	// Copy hooks directory
-	hooksSource := filepath.Join(templateDir, "hooks")
-	hooksDest := filepath.Join(destDir, "hooks")
-	if err := copyDir(hooksSource, hooksDest); err != nil {
-		return fmt.Errorf("copying hooks: %w", err)
-	}
-
-	// Copy settings.local.json if exists
-	settingsSource := filepath.Join(templateDir, "settings.local.json")
-	settingsDest := filepath.Join(destDir, "settings.local.json")
-	if _, err := os.Stat(settingsSource); err == nil {
-		if err := copyFile(settingsSource, settingsDest); err != nil {
-			return fmt.Errorf("copying settings: %w", err)
-		}
-	}
-
-	// Copy rules/vega-missile/ if exists
-	rulesSource := filepath.Join(templateDir, "rules", "vega-missile")
-	rulesDest := filepath.Join(destDir, "rules", "vega-missile")
-	if _, err := os.Stat(rulesSource); err == nil {
-		if err := copyDir(rulesSource, rulesDest); err != nil {
-			return fmt.Errorf("copying rules: %w", err)
-		}
-	}
-
-	return nil
-}
-
-// copyDir recursively copies a directory
-func copyDir(src, dst string) error {
-	if err := os.MkdirAll(dst, 0755); err != nil {
-		return err
-	}
-
-	entries, err := os.ReadDir(src)
-	if err != nil {
-		return err
-	}
-
-	for _, entry := range entries {
-		srcPath := filepath.Join(src, entry.Name())
-		dstPath := filepath.Join(dst, entry.Name())
-
-		if entry.IsDir() {
-			if err := copyDir(srcPath, dstPath); err != nil {
-				return err
-			}
-		} else {
-			if err := copyFile(srcPath, dstPath); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
-// copyFile copies a single file
-func copyFile(src, dst string) error {
-	content, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-
-	info, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(dst, content, info.Mode())
-}
-
 // writeWorktreeToGoalFile appends or updates the Worktree section in the goal markdown file
 func writeWorktreeToGoalFile(vegaDir, goalID, project, worktreePath, branch, baseBranch string) error {
 	// Find goal file - check active, then iced
-	goalPath := filepath.Join(vegaDir, "goals", "active", goalID+".md")
-	if _, err := os.Stat(goalPath); os.IsNotExist(err) {
-		goalPath = filepath.Join(vegaDir, "goals", "iced", goalID+".md")
-		if _, err := os.Stat(goalPath); os.IsNotExist(err) {
-			return fmt.Errorf("goal file not found for %s", goalID)
-		}
+	l := layout.New(vegaDir)
+	goalPath := l.FindGoalFileIn(layout.ActiveDir, goalID)
+	if goalPath == "" {
+		goalPath = l.FindGoalFileIn(layout.IcedDir, goalID)
+	}
+	if goalPath == "" {
+		return fmt.Errorf("goal file not found for %s", goalID)
 	}
 
 	// Read the current file
@@ -441,16 +368,15 @@ func insertWorktreeSection(lines []string, worktreeSection string) []string {
 // This can be called when removing a worktree to clear the metadata
 func removeWorktreeFromGoalFile(vegaDir, goalID string) error {
 	// Find goal file - check active, then iced, then history
-	goalPath := filepath.Join(vegaDir, "goals", "active", goalID+".md")
-	if _, err := os.Stat(goalPath); os.IsNotExist(err) {
-		goalPath = filepath.Join(vegaDir, "goals", "iced", goalID+".md")
-		if _, err := os.Stat(goalPath); os.IsNotExist(err) {
-			goalPath = filepath.Join(vegaDir, "goals", "history", goalID+".md")
-			if _, err := os.Stat(goalPath); os.IsNotExist(err) {
-				return nil // Goal file doesn't exist, nothing to do
-			}
+	var goalPath string
+	for _, dir := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+		if goalPath = layout.New(vegaDir).FindGoalFileIn(dir, goalID); goalPath != "" {
+			break
 		}
 	}
+	if goalPath == "" {
+		return nil // Goal file doesn't exist, nothing to do
+	}
 
 	file, err := os.Open(goalPath)
 	if err != nil {
@@ -3,9 +3,10 @@ package worktree
 import (
 	"fmt"
 	"os/exec"
-	"path/filepath"
 
 	"github.com/lasmarois/vega-hub/internal/cli"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
 	"github.com/spf13/cobra"
 )
 
@@ -59,6 +60,7 @@ func runRemove(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Find the worktree
 	worktreePath, project, err := findWorktreeForGoal(vegaDir, goalID)
@@ -93,7 +95,7 @@ func runRemove(c *cobra.Command, args []string) {
 	}
 
 	// Get project base for git operations
-	projectBase := filepath.Join(vegaDir, "workspaces", project, "worktree-base")
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
 
 	// Remove the worktree
 	var removeArgs []string
@@ -9,6 +9,7 @@ import (
 
 	"github.com/lasmarois/vega-hub/internal/cli"
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
 	"github.com/spf13/cobra"
 )
 
@@ -57,6 +58,7 @@ func runStatus(c *cobra.Command, args []string) {
 			{Flag: "dir", Description: "Specify vega-missile directory explicitly"},
 		})
 	}
+	goalID = goals.ResolveGoalID(vegaDir, goalID)
 
 	// Find the worktree for this goal
 	worktreePath, project, err := findWorktreeForGoal(vegaDir, goalID)
@@ -133,23 +135,12 @@ func findWorktreeForGoal(vegaDir, goalID string) (string, string, error) {
 		return "", "", fmt.Errorf("failed to read workspaces: %w", err)
 	}
 
-	goalPrefix := fmt.Sprintf("goal-%s-", goalID)
-
 	for _, project := range projects {
 		if !project.IsDir() {
 			continue
 		}
-
-		projectPath := filepath.Join(workspacesDir, project.Name())
-		entries, err := os.ReadDir(projectPath)
-		if err != nil {
-			continue
-		}
-
-		for _, entry := range entries {
-			if entry.IsDir() && strings.HasPrefix(entry.Name(), goalPrefix) {
-				return filepath.Join(projectPath, entry.Name()), project.Name(), nil
-			}
+		if path, err := operations.FindGoalWorktree(vegaDir, project.Name(), goalID); err == nil {
+			return path, project.Name(), nil
 		}
 	}
 
@@ -2,12 +2,14 @@ module github.com/lasmarois/vega-hub
 
 go 1.23
 
-require github.com/fsnotify/fsnotify v1.7.0
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/spf13/cobra v1.10.2
+)
 
 require (
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
 	golang.org/x/sys v0.4.0 // indirect
 )
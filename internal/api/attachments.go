@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// maxAttachmentsPerUpload bounds the files of one upload request
+const maxAttachmentsPerUpload = 10
+
+// handleGoalAttachments handles /api/goals/:id/attachments
+// POST - upload files (multipart, one or more "file" parts) to reference in
+// answers and messages as {"type": "file", "id": ...}
+// GET /api/goals/:id/attachments/:aid - download an uploaded file
+func handleGoalAttachments(h *hub.Hub, goalID, attachmentID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case attachmentID == "" && r.Method == http.MethodPost:
+			uploadAttachments(h, goalID, w, r)
+		case attachmentID != "" && r.Method == http.MethodGet:
+			serveAttachment(h, goalID, attachmentID, w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func uploadAttachments(h *hub.Hub, goalID string, w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentsPerUpload*(hub.MaxAttachmentBytes+64*1024))
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart/form-data upload", http.StatusBadRequest)
+		return
+	}
+
+	user := requestUser(r)
+	var saved []*hub.Attachment
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				http.Error(w, "Invalid upload: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			break
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+		if len(saved) == maxAttachmentsPerUpload {
+			part.Close()
+			http.Error(w, "Too many files in one upload", http.StatusRequestEntityTooLarge)
+			return
+		}
+		att, err := h.SaveAttachment(goalID, part.FileName(), user, part)
+		part.Close()
+		if err != nil {
+			if errors.Is(err, hub.ErrAttachmentTooLarge) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Failed to save attachment: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		saved = append(saved, att)
+	}
+	if len(saved) == 0 {
+		http.Error(w, "No file in upload", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[ATTACH] %d file(s) uploaded to goal %s by %s", len(saved), goalID, user)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+func serveAttachment(h *hub.Hub, goalID, attachmentID string, w http.ResponseWriter, r *http.Request) {
+	att, err := h.GetAttachment(goalID, attachmentID)
+	if err != nil {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	f, err := os.Open(att.Path)
+	if err != nil {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "Failed to read attachment", http.StatusInternalServerError)
+		return
+	}
+
+	// Images are shown inline in the chat; anything else (HTML included)
+	// is downloaded so an upload can't run script in the UI's origin
+	disposition := "attachment"
+	if strings.HasPrefix(att.ContentType, "image/") && att.ContentType != "image/svg+xml" {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", att.ContentType)
+	w.Header().Set("Content-Disposition", disposition+`; filename="`+att.Name+`"`)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, att.Name, info.ModTime(), f)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/credentials"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+func TestAttribution(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	systemUser := ""
+	if u, err := credentials.GetCurrentUser(); err == nil {
+		systemUser = u.Username
+	}
+
+	done := make(chan string, 2)
+	for _, id := range []string{"q-alice", "q-anon"} {
+		go func(id string) {
+			done <- h.Ask(&hub.Question{ID: id, GoalID: "abc1234", SessionID: "s1", Question: "Proceed with " + id + "?"})
+		}(id)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(h.GetPendingQuestions()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	answer := func(id, user string) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/answer/"+id, bytes.NewBufferString(`{"answer": "yes"}`))
+		if user != "" {
+			req.Header.Set("X-Vega-User", user)
+		}
+		w := httptest.NewRecorder()
+		handleAnswer(h)(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("answer %s: %d %s", id, w.Code, w.Body.String())
+		}
+		<-done
+	}
+	// Without X-Vega-User or a user field the answer is the system user's
+	answer("q-alice", "alice")
+	answer("q-anon", "")
+
+	h.RecordGoalAction("abc1234", "goal_iced", "bob", map[string]interface{}{"reason": "blocked on infra"})
+
+	w := httptest.NewRecorder()
+	handleGoalChat(h, "abc1234")(w, httptest.NewRequest("GET", "/api/goals/abc1234/chat", nil))
+	var messages []ChatMessage
+	if err := json.NewDecoder(w.Body).Decode(&messages); err != nil {
+		t.Fatal(err)
+	}
+	answeredBy := map[string]string{}
+	var iced *ChatMessage
+	for i, m := range messages {
+		switch m.Type {
+		case "question":
+			answeredBy[m.Content] = m.AnsweredBy
+		case "goal_iced":
+			iced = &messages[i]
+		}
+	}
+	if answeredBy["Proceed with q-alice?"] != "alice" || answeredBy["Proceed with q-anon?"] != systemUser {
+		t.Errorf("unexpected answered_by %v", answeredBy)
+	}
+	if iced == nil || iced.User != "bob" || iced.Content != "Goal iced by bob: blocked on infra" {
+		t.Errorf("unexpected goal_iced message %+v", iced)
+	}
+
+	// Comments and notes fall back the same way
+	w = httptest.NewRecorder()
+	handleGoalComments(h, "abc1234")(w, httptest.NewRequest("POST", "/api/goals/abc1234/comments", bytes.NewBufferString(`{"content": "Looks good"}`)))
+	var comment hub.Comment
+	json.Unmarshal(w.Body.Bytes(), &comment)
+	if w.Code != http.StatusCreated || comment.User != systemUser {
+		t.Errorf("expected the comment by %q, got %d %s", systemUser, w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	handleGoalNotes(h, "abc1234", "")(w, httptest.NewRequest("PUT", "/api/goals/abc1234/notes", bytes.NewBufferString(`{"content": "Notes", "user": "carol"}`)))
+	var notes hub.GoalNotes
+	json.Unmarshal(w.Body.Bytes(), &notes)
+	if w.Code != http.StatusOK || notes.User != "carol" {
+		t.Errorf("expected the notes saved by carol, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// BacklogResponse is the response for GET /api/goals/backlog
+type BacklogResponse struct {
+	Goals []goals.RegistryEntry `json:"goals"` // Highest priority first
+	Total int                   `json:"total"`
+}
+
+// ActivateGoalRequest is the body for POST /api/goals/:id/activate
+type ActivateGoalRequest struct {
+	BaseBranch string `json:"base_branch,omitempty"` // Defaults to the project's
+	Wait       bool   `json:"wait,omitempty"`        // Create the worktree before responding
+}
+
+// handleBacklog handles GET /api/goals/backlog - the draft goals waiting to be
+// activated (?project= filters)
+func handleBacklog(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		entries, err := goals.NewRegistry(h.Dir()).Backlog()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		backlog := []goals.RegistryEntry{}
+		project := r.URL.Query().Get("project")
+		for _, e := range entries {
+			if project == "" || (len(e.Projects) > 0 && e.Projects[0] == project) {
+				backlog = append(backlog, e)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BacklogResponse{Goals: backlog, Total: len(backlog)})
+	}
+}
+
+// handleGoalActivate handles POST /api/goals/:id/activate - takes a draft goal
+// out of the backlog. Like goal creation, it responds 202 with the job
+// provisioning the worktree unless the request waits.
+func handleGoalActivate(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req ActivateGoalRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+
+		result, data := operations.ActivateGoal(operations.ActivateOptions{
+			GoalID:     goalID,
+			BaseBranch: req.BaseBranch,
+			NoWorktree: !req.Wait,
+			User:       requestUser(r),
+			VegaDir:    h.Dir(),
+		})
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "not_draft":
+				w.WriteHeader(http.StatusConflict)
+			case "invalid_input", "project_required", "project_not_found":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		log.Printf("[ACTIVATE] Goal %s activated: branch=%s", goalID, data.GoalBranch)
+		h.EmitEvent("goal_activated", map[string]interface{}{
+			"goal_id": data.GoalID,
+			"title":   data.Title,
+			"project": data.Project,
+		})
+
+		if req.Wait {
+			json.NewEncoder(w).Encode(CreateGoalResponse{Success: true, Data: data})
+			return
+		}
+		job := provisionGoalWorktree(h, data, operations.ProvisionOptions{User: requestUser(r)})
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(CreateGoalResponse{Success: true, Data: data, Job: job})
+	}
+}
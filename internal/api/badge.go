@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// Badge colors (the shields.io palette, so badges sit well next to others)
+const (
+	badgeGreen  = "#4c1"
+	badgeBlue   = "#007ec6"
+	badgeYellow = "#dfb317"
+	badgeOrange = "#fe7d37"
+	badgeRed    = "#e05d44"
+	badgeGrey   = "#9f9f9f"
+)
+
+// badgeStateColors colors goal states; states not listed are blue
+var badgeStateColors = map[goals.GoalState]string{
+	goals.StateDone:             badgeGreen,
+	goals.StateApproved:         badgeGreen,
+	goals.StateChangesRequested: badgeYellow,
+	goals.StateFailed:           badgeRed,
+	goals.StateConflict:         badgeRed,
+	goals.StateIced:             badgeGrey,
+	goals.StatePaused:           badgeGrey,
+}
+
+// handleGoalBadge handles GET /api/goals/:id/badge.svg - the goal's state
+// and phase progress as an SVG badge for READMEs and wikis. ?label= replaces
+// the "goal <id>" label.
+func handleGoalBadge(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		label := r.URL.Query().Get("label")
+		if label == "" {
+			label = "goal " + goalID
+		}
+
+		entry, err := goals.NewRegistry(h.Dir()).Get(goalID)
+		if errors.Is(err, goals.ErrNotFound) {
+			writeBadge(w, http.StatusNotFound, label, "not found", badgeGrey)
+			return
+		} else if err != nil {
+			writeBadge(w, http.StatusInternalServerError, label, "error", badgeGrey)
+			return
+		}
+
+		state := goals.StateWorking
+		if s, err := h.StateManager().GetState(goalID); err == nil {
+			state = s
+		}
+		// Goals completed without a state file would otherwise read as working
+		if entry.Status == "completed" && !state.IsTerminal() {
+			state = goals.StateDone
+		}
+		message, color := string(state), badgeBlue
+		if c, ok := badgeStateColors[state]; ok {
+			color = c
+		}
+		for _, q := range h.GetPendingQuestions() {
+			if q.GoalID == goalID {
+				message, color = "waiting for answer", badgeOrange
+				break
+			}
+		}
+		if state != goals.StateDone {
+			if status, err := h.Completion().CheckGoal(goalID); err == nil && status.TotalPhases > 0 {
+				message += fmt.Sprintf(" · %d/%d", status.CompletedPhases, status.TotalPhases)
+			}
+		}
+		writeBadge(w, http.StatusOK, label, message, color)
+	}
+}
+
+// handleProjectBadge handles GET /api/projects/:name/badge.svg - the
+// project's active, waiting and completed goal counts as an SVG badge.
+// ?label= replaces the project name.
+func handleProjectBadge(h *hub.Hub, p *goals.Parser, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		label := r.URL.Query().Get("label")
+		if label == "" {
+			label = name
+		}
+		if _, err := p.ParseProject(name); err != nil {
+			writeBadge(w, http.StatusNotFound, label, "not found", badgeGrey)
+			return
+		}
+
+		entries, err := goals.NewRegistry(h.Dir()).List(func(e goals.RegistryEntry) bool {
+			return containsProject(e.Projects, name)
+		})
+		if err != nil {
+			writeBadge(w, http.StatusInternalServerError, label, "error", badgeGrey)
+			return
+		}
+		waitingGoals := make(map[string]bool)
+		for _, q := range h.GetPendingQuestions() {
+			waitingGoals[q.GoalID] = true
+		}
+		var active, waiting, completed int
+		for _, e := range entries {
+			switch e.Status {
+			case "active":
+				active++
+				if waitingGoals[e.ID] {
+					waiting++
+				}
+			case "completed":
+				completed++
+			}
+		}
+
+		parts := []string{fmt.Sprintf("%d active", active)}
+		color := badgeBlue
+		if waiting > 0 {
+			parts = append(parts, fmt.Sprintf("%d waiting", waiting))
+			color = badgeOrange
+		}
+		parts = append(parts, fmt.Sprintf("%d done", completed))
+		if active == 0 && completed > 0 {
+			color = badgeGreen
+		}
+		writeBadge(w, http.StatusOK, label, strings.Join(parts, " · "), color)
+	}
+}
+
+// writeBadge writes a flat two-part badge. Badges aren't cached so embeds
+// stay live.
+func writeBadge(w http.ResponseWriter, status int, label, message, color string) {
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	w.Write(renderBadge(label, message, color))
+}
+
+// renderBadge draws the badge. Text widths are estimated from the character
+// count, which is close enough for 11px Verdana.
+func renderBadge(label, message, color string) []byte {
+	lw, mw := badgeTextWidth(label), badgeTextWidth(message)
+	label, message = html.EscapeString(label), html.EscapeString(message)
+	width := lw + mw
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, message)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, label, message)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		lw, lw, mw, color, width)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	for _, text := range []struct {
+		x    int
+		text string
+	}{{lw / 2, label}, {lw + mw/2, message}} {
+		fmt.Fprintf(&b, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, text.x, text.text, text.x, text.text)
+	}
+	b.WriteString(`</g></svg>`)
+	return []byte(b.String())
+}
+
+// badgeTextWidth is the width of a badge part: the text plus 5px padding on
+// each side
+func badgeTextWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestBadges(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(dir, "projects", "test-project.md"), []byte("# Project: test-project\n\n**Base Branch**: `main`\n"), 0644)
+	registry := goals.NewRegistry(dir)
+	for _, e := range []goals.RegistryEntry{
+		{ID: "abc1234", Title: "Test goal", Projects: []string{"test-project"}, Status: "active"},
+		{ID: "bbb2222", Title: "Shipped", Projects: []string{"test-project"}, Status: "completed", CompletedAt: "2024-05-01"},
+		{ID: "ccc3333", Title: "Elsewhere", Projects: []string{"other"}, Status: "active"},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.StateManager().Transition("abc1234", goals.StateWorking, "start", nil)
+
+	badge := func(handler http.HandlerFunc, url string) (int, string) {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("GET", url, nil))
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "image/svg+xml") {
+			t.Errorf("%s: unexpected Content-Type %q", url, ct)
+		}
+		return w.Code, w.Body.String()
+	}
+
+	code, body := badge(handleGoalBadge(h, "abc1234"), "/api/goals/abc1234/badge.svg")
+	if code != http.StatusOK || !strings.Contains(body, `aria-label="goal abc1234: working · 0/1"`) || !strings.Contains(body, badgeBlue) {
+		t.Errorf("unexpected goal badge %d:\n%s", code, body)
+	}
+	code, body = badge(handleGoalBadge(h, "bbb2222"), "/api/goals/bbb2222/badge.svg?label=<release>")
+	if code != http.StatusOK || !strings.Contains(body, `aria-label="&lt;release&gt;: done"`) || !strings.Contains(body, badgeGreen) {
+		t.Errorf("unexpected completed goal badge %d:\n%s", code, body)
+	}
+	if code, _ := badge(handleGoalBadge(h, "fff9999"), "/api/goals/fff9999/badge.svg"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown goal, got %d", code)
+	}
+
+	code, body = badge(handleProjectBadge(h, p, "test-project"), "/api/projects/test-project/badge.svg")
+	if code != http.StatusOK || !strings.Contains(body, `aria-label="test-project: 1 active · 1 done"`) {
+		t.Errorf("unexpected project badge %d:\n%s", code, body)
+	}
+	if code, _ := badge(handleProjectBadge(h, p, "nope"), "/api/projects/nope/badge.svg"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown project, got %d", code)
+	}
+}
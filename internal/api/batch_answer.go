@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// maxBatchAnswers caps the answers of one POST /api/answers request
+const maxBatchAnswers = 100
+
+// BatchAnswerRequest is the request body for POST /api/answers
+type BatchAnswerRequest struct {
+	Answers []BatchAnswerItem `json:"answers"`
+	User    string            `json:"user,omitempty"` // Fallback when X-Vega-User is not set
+}
+
+// BatchAnswerItem is one answer of a batch: the question and its answer as
+// POST /api/answer/:id takes it
+type BatchAnswerItem struct {
+	QuestionID string `json:"question_id"`
+	AnswerRequest
+}
+
+// BatchAnswerResult is the outcome of one answer of a batch
+type BatchAnswerResult struct {
+	QuestionID string            `json:"question_id"`
+	OK         bool              `json:"ok"`
+	Status     int               `json:"status"` // What POST /api/answer/:id would have returned
+	Error      string            `json:"error,omitempty"`
+	Conflict   *ConflictResponse `json:"conflict,omitempty"` // Who claimed or answered it first
+}
+
+// BatchAnswerResponse is the response for POST /api/answers
+type BatchAnswerResponse struct {
+	Results  []BatchAnswerResult `json:"results"` // In request order
+	Answered int                 `json:"answered"`
+	Failed   int                 `json:"failed"`
+}
+
+// handleBatchAnswer handles POST /api/answers - answers several questions in
+// one request. Each answer succeeds or fails on its own, like a separate
+// POST /api/answer/:id: a failed answer doesn't undo the others.
+func handleBatchAnswer(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req BatchAnswerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if len(req.Answers) == 0 {
+			http.Error(w, "answers is required", http.StatusBadRequest)
+			return
+		}
+		if len(req.Answers) > maxBatchAnswers {
+			http.Error(w, "Too many answers (max 100)", http.StatusBadRequest)
+			return
+		}
+
+		resp := BatchAnswerResponse{Results: make([]BatchAnswerResult, 0, len(req.Answers))}
+		for _, item := range req.Answers {
+			result := BatchAnswerResult{QuestionID: item.QuestionID, OK: true, Status: http.StatusOK}
+			given := req.User
+			if given == "" {
+				given = item.User
+			}
+			user := actingUser(r, given)
+
+			var err error
+			if item.QuestionID == "" {
+				err = errMissingQuestionID
+			} else {
+				err = h.AnswerStructuredAs(item.QuestionID, user, item.toStructured())
+			}
+			if err != nil {
+				result.OK = false
+				result.Error = err.Error()
+				result.Status = answerErrorStatus(err)
+				var conflict *hub.ConflictError
+				if errors.As(err, &conflict) {
+					c := newConflictResponse(conflict)
+					result.Conflict = &c
+				}
+				resp.Failed++
+			} else {
+				resp.Answered++
+			}
+			resp.Results = append(resp.Results, result)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// errMissingQuestionID fails a batch answer without a question ID
+var errMissingQuestionID = fmt.Errorf("%w: question_id is required", hub.ErrInvalidAnswer)
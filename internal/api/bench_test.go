@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/loadtest"
+)
+
+// setupBenchEnv serves the API from a generated vega dir
+func setupBenchEnv(b *testing.B, numGoals, history int) (http.Handler, []string) {
+	b.Helper()
+	dir := b.TempDir()
+	ids, err := loadtest.WriteFixture(dir, numGoals, history)
+	if err != nil {
+		b.Fatalf("failed to write fixture: %v", err)
+	}
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, hub.New(dir), goals.NewParser(dir))
+	return mux, ids
+}
+
+func benchmarkGet(b *testing.B, handler http.Handler, url func(i int) string) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", url(i), nil))
+		if w.Code != http.StatusOK {
+			b.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+}
+
+func BenchmarkHandleGoals(b *testing.B) {
+	handler, _ := setupBenchEnv(b, 200, 0)
+	benchmarkGet(b, handler, func(int) string { return "/api/goals" })
+}
+
+func BenchmarkHandleGoalDetail(b *testing.B) {
+	handler, ids := setupBenchEnv(b, 50, 0)
+	benchmarkGet(b, handler, func(i int) string { return "/api/goals/" + ids[i%len(ids)] })
+}
+
+func BenchmarkHandleGoalChat(b *testing.B) {
+	handler, ids := setupBenchEnv(b, 10, 500)
+	benchmarkGet(b, handler, func(i int) string { return "/api/goals/" + ids[i%len(ids)] + "/chat" })
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+const (
+	defaultCalendarDays = 30  // How far back completions go by default
+	maxCalendarDays     = 365 // Upper bound for ?days=
+)
+
+// calendarEvent is one entry in the ICS feed. All-day events have a Date
+// and no Start/End.
+type calendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Date        time.Time // All-day event
+	Start, End  time.Time // Timed event
+}
+
+// calendarEvents collects goal completions from the registry. Goals that
+// completed before cutoff are left out. Goals have no due dates and executor
+// runs aren't scheduled yet, so completions are the only dated milestones.
+func calendarEvents(h *hub.Hub, project string, cutoff time.Time, baseURL string) ([]calendarEvent, error) {
+	cutoffDay := cutoff.Format("2006-01-02")
+	completed, err := goals.NewRegistry(h.Dir()).List(func(e goals.RegistryEntry) bool {
+		if e.Status != "completed" || e.CompletedAt < cutoffDay {
+			return false
+		}
+		return project == "" || containsProject(e.Projects, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []calendarEvent
+	for _, e := range completed {
+		day, err := time.Parse("2006-01-02", e.CompletedAt)
+		if err != nil {
+			continue
+		}
+		events = append(events, calendarEvent{
+			UID:         "goal-" + e.ID + "-completed@vega-hub",
+			Summary:     fmt.Sprintf("Completed: %s", e.Title),
+			Description: fmt.Sprintf("Goal %s (%s) completed", e.ID, strings.Join(e.Projects, ", ")),
+			URL:         baseURL + "/goals/" + e.ID,
+			Date:        day,
+		})
+	}
+	return events, nil
+}
+
+func containsProject(projects []string, project string) bool {
+	for _, p := range projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+// writeICS renders events as an iCalendar (RFC 5545) document
+func writeICS(w *strings.Builder, events []calendarEvent, now time.Time) {
+	line := func(s string) {
+		// Lines longer than 75 octets are folded with CRLF + space
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && s[cut]&0xC0 == 0x80 { // Don't split UTF-8 sequences
+				cut--
+			}
+			w.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		w.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//vega-hub//Goals//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:vega-hub goals")
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp)
+		if e.Start.IsZero() {
+			line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			line("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+			line("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+		}
+		line("SUMMARY:" + escapeICS(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeICS(e.Description))
+		}
+		if e.URL != "" {
+			line("URL:" + e.URL)
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeICS escapes text values per RFC 5545 section 3.3.11
+func escapeICS(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// handleCalendar handles GET /api/calendar.ics - goal completions as an
+// iCalendar feed for team calendars. Supports ?project= and ?days= (how far
+// back completions go, default 30).
+func handleCalendar(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		days := defaultCalendarDays
+		if d := r.URL.Query().Get("days"); d != "" {
+			n, err := strconv.Atoi(d)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid days", http.StatusBadRequest)
+				return
+			}
+			if n > maxCalendarDays {
+				n = maxCalendarDays
+			}
+			days = n
+		}
+
+		now := time.Now()
+		events, err := calendarEvents(h, r.URL.Query().Get("project"), now.AddDate(0, 0, -days), requestBaseURL(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var b strings.Builder
+		writeICS(&b, events, now)
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write([]byte(b.String()))
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestHandleCalendar(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+	registry := goals.NewRegistry(dir)
+	today := time.Now().Format("2006-01-02")
+	for _, e := range []goals.RegistryEntry{
+		{ID: "aaa1111", Title: "Fix login; add tests", Projects: []string{"web"}, Status: "completed", CompletedAt: today},
+		{ID: "bbb2222", Title: "Refactor API", Projects: []string{"api"}, Status: "completed", CompletedAt: today},
+		{ID: "ccc3333", Title: "Ancient", Projects: []string{"web"}, Status: "completed", CompletedAt: "2020-01-01"},
+		{ID: "ddd4444", Title: "Still going", Projects: []string{"web"}, Status: "active"},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handleCalendar(h)(w, httptest.NewRequest("GET", "http://hub.lan/api/calendar.ics?project=web", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("unexpected response %d (%s)", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+		t.Errorf("expected a CRLF-terminated calendar, got:\n%s", body)
+	}
+	if n := strings.Count(body, "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("expected 1 event, got %d:\n%s", n, body)
+	}
+	for _, want := range []string{
+		"UID:goal-aaa1111-completed@vega-hub",
+		"DTSTART;VALUE=DATE:" + time.Now().Format("20060102"),
+		`SUMMARY:Completed: Fix login\; add tests`,
+		"URL:http://hub.lan/goals/aaa1111",
+	} {
+		if !strings.Contains(body, want+"\r\n") {
+			t.Errorf("expected %q in:\n%s", want, body)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	handleCalendar(h)(w, httptest.NewRequest("GET", "/api/calendar.ics?days=x", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestWriteICSFoldsLongLines(t *testing.T) {
+	var b strings.Builder
+	writeICS(&b, []calendarEvent{{UID: "x", Summary: strings.Repeat("é", 60), Date: time.Now()}}, time.Now())
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	if !strings.Contains(b.String(), "\r\n ") {
+		t.Error("expected a folded continuation line")
+	}
+}
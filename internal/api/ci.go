@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// handleGoalCI handles /api/goals/:id/ci
+// GET  - the goal branch's CI state at the last check (404 if never checked)
+// POST - check the provider now
+func handleGoalCI(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var status *operations.CIStatus
+		switch r.Method {
+		case http.MethodGet:
+			if status = operations.GetCIStatus(h.Dir(), goalID); status == nil {
+				http.Error(w, "No CI status recorded for this goal", http.StatusNotFound)
+				return
+			}
+		case http.MethodPost:
+			project := r.URL.Query().Get("project")
+			if project == "" {
+				detail, err := goals.NewParser(h.Dir()).ParseGoalDetail(goalID)
+				if err != nil || len(detail.Projects) == 0 {
+					http.Error(w, "Goal not found", http.StatusNotFound)
+					return
+				}
+				project = detail.Projects[0]
+			}
+			if !validIDs(w, "project", project) {
+				return
+			}
+			var changed bool
+			var err error
+			status, changed, err = operations.CheckGoalCI(h.Dir(), goalID, project)
+			if status == nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if changed {
+				EmitCIStatus(h, *status)
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+// EmitCIStatus broadcasts a goal's new CI state as a goal_ci_changed event
+func EmitCIStatus(h *hub.Hub, status operations.CIStatus) {
+	h.EmitEvent("goal_ci_changed", map[string]interface{}{
+		"goal_id": status.GoalID,
+		"project": status.Project,
+		"branch":  status.Branch,
+		"state":   status.State,
+		"url":     status.URL,
+	})
+}
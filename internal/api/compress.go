@@ -0,0 +1,296 @@
+package api
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultCompressMinSize is the response size (in bytes) below which
+// responses are sent uncompressed - tiny payloads aren't worth the CPU
+const DefaultCompressMinSize = 1024
+
+// compressibleTypes lists content type prefixes worth compressing.
+// Images/archives are already compressed and SSE must stream unbuffered.
+var compressibleTypes = []string{
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/css",
+	"text/html",
+	"text/javascript",
+	"text/plain",
+	"text/xml",
+}
+
+var gzipPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	},
+}
+
+var flatePool = sync.Pool{
+	New: func() interface{} {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	},
+}
+
+// Brotli at level 5 compresses JSON better than gzip at a similar CPU cost;
+// its default (11) is meant for precompressed assets
+var brotliPool = sync.Pool{
+	New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, 5)
+	},
+}
+
+// CompressHandler wraps a handler with transparent response compression.
+// The encoding is negotiated via Accept-Encoding (brotli preferred, then gzip,
+// then deflate). Responses smaller than
+// minSize, non-compressible content types and SSE streams pass through as-is.
+func CompressHandler(next http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the best supported encoding from an Accept-Encoding header
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		rejected := false
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == "q=0" || param == "q=0.0" || param == "q=0.00" || param == "q=0.000" {
+				rejected = true
+			}
+		}
+		if !rejected {
+			accepted[name] = true
+		}
+	}
+
+	switch {
+	case accepted["br"]:
+		return "br"
+	case accepted["gzip"]:
+		return "gzip"
+	case accepted["deflate"]:
+		return "deflate"
+	case accepted["*"]:
+		return "gzip"
+	}
+	return ""
+}
+
+// isCompressible checks if a content type should be compressed
+func isCompressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it knows whether
+// the response is large enough (and of the right type) to compress
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool // WriteHeader called by the handler
+	decided     bool // compression decision made and headers sent
+	passthrough bool // decided not to compress
+	buf         []byte
+	encoder     io.WriteCloser
+}
+
+// WriteHeader records the status; headers are sent once the decision is made
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+
+	// Informational and bodiless responses never get compressed
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.startPassthrough()
+	}
+}
+
+// Write buffers data until minSize is reached, then starts compressing
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.decided {
+		if cw.passthrough {
+			return cw.ResponseWriter.Write(p)
+		}
+		return cw.encoder.Write(p)
+	}
+
+	h := cw.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(append(cw.buf, p...)))
+	}
+	if h.Get("Content-Encoding") != "" || !isCompressible(h.Get("Content-Type")) {
+		cw.startPassthrough()
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) < cw.minSize {
+		return len(p), nil
+	}
+
+	if err := cw.startCompression(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// startPassthrough sends headers and any buffered data uncompressed
+func (cw *compressWriter) startPassthrough() {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+	cw.passthrough = true
+
+	status := cw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	cw.ResponseWriter.WriteHeader(status)
+	if len(cw.buf) > 0 {
+		cw.ResponseWriter.Write(cw.buf)
+		cw.buf = nil
+	}
+}
+
+// startCompression sends compressed headers and flushes the buffer through the encoder
+func (cw *compressWriter) startCompression() error {
+	cw.decided = true
+
+	h := cw.Header()
+	h.Set("Content-Encoding", cw.encoding)
+	h.Del("Content-Length")
+	h.Del("Accept-Ranges")
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.encoding {
+	case "br":
+		br := brotliPool.Get().(*brotli.Writer)
+		br.Reset(cw.ResponseWriter)
+		cw.encoder = br
+	case "gzip":
+		gz := gzipPool.Get().(*gzip.Writer)
+		gz.Reset(cw.ResponseWriter)
+		cw.encoder = gz
+	case "deflate":
+		fl := flatePool.Get().(*flate.Writer)
+		fl.Reset(cw.ResponseWriter)
+		cw.encoder = fl
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+	_, err := cw.encoder.Write(buf)
+	return err
+}
+
+// Close finishes the response - flushing small buffered bodies uncompressed
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// Handler wrote nothing at all; let net/http send its default 200
+			return nil
+		}
+		cw.startPassthrough()
+		return nil
+	}
+
+	if cw.encoder == nil {
+		return nil
+	}
+
+	err := cw.encoder.Close()
+	switch enc := cw.encoder.(type) {
+	case *gzip.Writer:
+		gzipPool.Put(enc)
+	case *flate.Writer:
+		flatePool.Put(enc)
+	case *brotli.Writer:
+		brotliPool.Put(enc)
+	}
+	cw.encoder = nil
+	return err
+}
+
+// Flush implements http.Flusher. Flushing before the decision is made means the
+// handler is streaming, so we give up on compression for this response.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		cw.startPassthrough()
+	}
+
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok && !cw.passthrough {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for handlers that need the raw connection
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestCompressHandler_LargeJSON(t *testing.T) {
+	body := `{"data":"` + strings.Repeat("x", 4096) + `"}`
+	handler := CompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}), DefaultCompressMinSize)
+
+	req := httptest.NewRequest("GET", "/api/goals", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("invalid gzip body: %v", err)
+	}
+	decoded, _ := io.ReadAll(gz)
+	if string(decoded) != body {
+		t.Errorf("decoded body mismatch: got %d bytes, want %d", len(decoded), len(body))
+	}
+}
+
+func TestCompressHandler_Brotli(t *testing.T) {
+	body := `{"data":"` + strings.Repeat("x", 4096) + `"}`
+	handler := CompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}), DefaultCompressMinSize)
+
+	req := httptest.NewRequest("GET", "/api/goals", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "br" {
+		t.Fatalf("expected br encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	decoded, err := io.ReadAll(brotli.NewReader(w.Body))
+	if err != nil {
+		t.Fatalf("invalid brotli body: %v", err)
+	}
+	if string(decoded) != body {
+		t.Errorf("decoded body mismatch: got %d bytes, want %d", len(decoded), len(body))
+	}
+}
+
+func TestCompressHandler_BelowThreshold(t *testing.T) {
+	handler := CompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	}), DefaultCompressMinSize)
+
+	req := httptest.NewRequest("GET", "/api/health", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected no encoding for small body, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", w.Code)
+	}
+	if w.Body.String() != `{"ok":true}` {
+		t.Errorf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestCompressHandler_NotAccepted(t *testing.T) {
+	body := strings.Repeat("a", 4096)
+	handler := CompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(body))
+	}), DefaultCompressMinSize)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected no encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Body.Len() != len(body) {
+		t.Errorf("expected %d bytes, got %d", len(body), w.Body.Len())
+	}
+}
+
+func TestCompressHandler_SSEPassthrough(t *testing.T) {
+	handler := CompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: connected\ndata: {}\n\n"))
+		w.(http.Flusher).Flush()
+	}), 0)
+
+	req := httptest.NewRequest("GET", "/api/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("SSE stream must not be compressed, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if !strings.Contains(w.Body.String(), "event: connected") {
+		t.Errorf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"br", "br"},
+		{"gzip, br", "br"},
+		{"br;q=0, gzip", "gzip"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"*", "gzip"},
+		{"identity", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.expected {
+			t.Errorf("negotiateEncoding(%q): expected %q, got %q", tt.header, tt.expected, got)
+		}
+	}
+}
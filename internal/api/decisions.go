@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// handleDecisions handles GET /api/decisions - answered questions across all
+// goals, newest first. Filters: ?project=, ?goal_id=, ?q= (text in the
+// question or answer), ?since= (RFC3339) and ?limit=.
+func handleDecisions(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		filter := hub.DecisionFilter{
+			Project: query.Get("project"),
+			GoalID:  query.Get("goal_id"),
+			Query:   query.Get("q"),
+		}
+		if v := query.Get("since"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "Invalid since (expected RFC3339)", http.StatusBadRequest)
+				return
+			}
+			filter.Since = t
+		}
+		if v := query.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			filter.Limit = n
+		}
+
+		decisions, err := h.GetDecisions(filter)
+		if err != nil {
+			http.Error(w, "Failed to read decisions: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if decisions == nil {
+			decisions = []hub.Decision{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(decisions)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// maxExecutorRequestBytes caps the body of a request to an executor endpoint
+const maxExecutorRequestBytes = 1 << 20
+
+// executorAuth guards an endpoint executors call (ask, stop, pending
+// messages) with the token vega-hub issued to the executor. scopeOf returns
+// the goal and session the request acts on. A valid token for another goal
+// or session is always rejected; a missing or unknown token when the hub
+// requires a token for the goal. With executor auth off, tokens are ignored.
+// The body is limited to maxExecutorRequestBytes, for scopeOf and the handler.
+func executorAuth(h *hub.Hub, scopeOf func(r *http.Request) (goalID, sessionID string, err error), next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxExecutorRequestBytes)
+		}
+		if h.ExecutorAuth() == hub.ExecutorAuthOff {
+			next(w, r)
+			return
+		}
+		goalID, sessionID, err := scopeOf(r)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			} else {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+			}
+			return
+		}
+		_, err = h.ValidateExecutorToken(bearerToken(r), goalID, sessionID)
+		switch {
+		case err == nil:
+		case errors.Is(err, hub.ErrTokenScope):
+			log.Printf("[AUTH] Rejected %s %s: token for another goal or session", r.Method, r.URL.Path)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case h.ExecutorAuthRequired(goalID):
+			log.Printf("[AUTH] Rejected %s %s: no valid executor token", r.Method, r.URL.Path)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// bearerToken returns the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// bodyScope reads goal_id and session_id from a JSON request body, leaving
+// the body intact for the handler. Malformed JSON is left to the handler.
+func bodyScope(r *http.Request) (string, string, error) {
+	if r.Body == nil {
+		return "", "", nil
+	}
+	data, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return "", "", err
+	}
+	var req struct {
+		GoalID    string `json:"goal_id"`
+		SessionID string `json:"session_id"`
+	}
+	json.Unmarshal(data, &req)
+	return req.GoalID, req.SessionID, nil
+}
+
+// pathScope is the scopeOf for routes with the goal ID in the path and the
+// session in ?session_id=
+func pathScope(goalID string) func(r *http.Request) (string, string, error) {
+	return func(r *http.Request) (string, string, error) {
+		return goalID, r.URL.Query().Get("session_id"), nil
+	}
+}
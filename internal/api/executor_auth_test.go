@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+func TestExecutorAuth(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	own := h.IssueExecutorToken("abc1234", "session-1").Token
+	other := h.IssueExecutorToken("def5678", "session-2").Token
+
+	stop := func(token, sessionID string) int {
+		body := `{"goal_id": "abc1234", "session_id": "` + sessionID + `", "reason": "completed"}`
+		req := httptest.NewRequest("POST", "/api/executor/stop", bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+	pending := func(token, sessionID string) int {
+		req := httptest.NewRequest("GET", "/api/goals/abc1234/messages/pending?session_id="+sessionID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// While the hub's executor works on the goal, only its token is accepted
+	if code := stop("", "session-1"); code != http.StatusUnauthorized {
+		t.Errorf("expected a tokenless stop refused, got %d", code)
+	}
+	if code := stop("forged", "session-1"); code != http.StatusUnauthorized {
+		t.Errorf("expected an unknown token refused, got %d", code)
+	}
+	if code := stop(other, "session-1"); code != http.StatusForbidden {
+		t.Errorf("expected another goal's token refused, got %d", code)
+	}
+	if code := stop(own, "session-3"); code != http.StatusForbidden {
+		t.Errorf("expected the token refused for another session of the goal, got %d", code)
+	}
+	if code := pending(own, ""); code != http.StatusForbidden {
+		t.Errorf("expected the token refused for messages without its session, got %d", code)
+	}
+	if code := pending(other, "session-2"); code != http.StatusForbidden {
+		t.Errorf("expected another goal's token refused for messages, got %d", code)
+	}
+	if code := pending(own, "session-1"); code != http.StatusOK {
+		t.Errorf("expected the executor's own token accepted for messages, got %d", code)
+	}
+	if code := stop(own, "session-1"); code != http.StatusOK {
+		t.Errorf("expected the executor's own token accepted, got %d", code)
+	}
+
+	// Goals without a spawned executor need a token only with executor auth all
+	h.RevokeExecutorTokens("session-1")
+	if code := stop("", "session-3"); code != http.StatusOK {
+		t.Errorf("expected a tokenless stop allowed, got %d", code)
+	}
+	h.SetExecutorAuth(hub.ExecutorAuthAll)
+	if code := stop("", "session-3"); code != http.StatusUnauthorized {
+		t.Errorf("expected a tokenless stop refused with executor auth all, got %d", code)
+	}
+
+	// Legacy executors: tokens are ignored
+	h.SetExecutorAuth(hub.ExecutorAuthOff)
+	if code := stop(other, "session-3"); code != http.StatusOK {
+		t.Errorf("expected tokens ignored with executor auth off, got %d", code)
+	}
+
+	// Oversized bodies are refused before they are buffered
+	h.SetExecutorAuth(hub.ExecutorAuthSpawned)
+	big := `{"goal_id": "abc1234", "question": "` + strings.Repeat("x", maxExecutorRequestBytes) + `"}`
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/ask", strings.NewReader(big)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected an oversized ask refused with 413, got %d", w.Code)
+	}
+
+	// The goal's executor routes have no unauthenticated stop
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/executors/session-1/stop", bytes.NewBufferString(`{"reason": "stale"}`))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected no stop control, got %d: %s", w.Code, w.Body.String())
+	}
+}
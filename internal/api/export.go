@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// SnapshotMarker is set on window in the index.html of an exported snapshot.
+// The web UI then reads each API path from <path>/index.json and refuses
+// changes.
+const SnapshotMarker = "__VEGA_HUB_SNAPSHOT__"
+
+// snapshotChatLimit fits a goal's whole chat in its one exported page
+const snapshotChatLimit = 1000000
+
+// Endpoints exported once, and per goal and per project (relative to
+// /api/goals/:id and /api/projects/:name; "" is the detail)
+var (
+	snapshotRoots        = []string{"/api/goals", "/api/projects", "/api/questions", "/api/executors", "/api/decisions"}
+	snapshotGoalViews    = []string{"", "chat", "sessions", "timeline", "comments", "notes", "dependencies", "children", "planning-files"}
+	snapshotProjectViews = []string{"", "goals"}
+)
+
+// SnapshotManifest describes an exported snapshot; it is written as
+// snapshot.json next to the API files
+type SnapshotManifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	Dir       string    `json:"dir"` // vega-missile directory it was taken from
+	Goals     int       `json:"goals"`
+	Projects  int       `json:"projects"`
+	Files     int       `json:"files"`             // API files written
+	Skipped   []string  `json:"skipped,omitempty"` // Endpoints that failed, with their status
+}
+
+// snapshotResponse captures a handler's response in memory
+type snapshotResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (s *snapshotResponse) Header() http.Header         { return s.header }
+func (s *snapshotResponse) Write(b []byte) (int, error) { return s.body.Write(b) }
+func (s *snapshotResponse) WriteHeader(status int)      { s.status = status }
+
+// ExportSnapshot writes what the dashboard reads - every goal (active,
+// iced and completed) with its chat, sessions and timeline, the projects and
+// the completed goals history - as static JSON under outDir/api. Each API
+// path is written to <path>/index.json, exactly as GET returned it. Only
+// state on disk is included: questions and executors of a running hub live
+// in its memory.
+func ExportSnapshot(h *hub.Hub, p *goals.Parser, outDir string) (*SnapshotManifest, error) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+	manifest := &SnapshotManifest{CreatedAt: time.Now(), Dir: h.Dir()}
+
+	get := func(apiPath, query string) ([]byte, bool) {
+		req, err := http.NewRequest(http.MethodGet, apiPath+query, nil)
+		if err != nil {
+			manifest.Skipped = append(manifest.Skipped, apiPath+": "+err.Error())
+			return nil, false
+		}
+		resp := &snapshotResponse{header: make(http.Header), status: http.StatusOK}
+		mux.ServeHTTP(resp, req)
+		if resp.status != http.StatusOK {
+			manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("%s: %d %s", apiPath, resp.status, strings.TrimSpace(resp.body.String())))
+			return nil, false
+		}
+		return resp.body.Bytes(), true
+	}
+	export := func(apiPath, query string) ([]byte, error) {
+		data, ok := get(apiPath, query)
+		if !ok {
+			return nil, nil
+		}
+		return data, writeSnapshotFile(outDir, apiPath, data, manifest)
+	}
+
+	var goalIDs, projectNames []string
+	seen := make(map[string]bool)
+	for _, root := range snapshotRoots {
+		data, err := export(root, "")
+		if err != nil {
+			return nil, err
+		}
+		var items []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		json.Unmarshal(data, &items)
+		for _, item := range items {
+			switch {
+			case root == "/api/goals" && item.ID != "" && !seen[item.ID]:
+				seen[item.ID] = true
+				goalIDs = append(goalIDs, item.ID)
+			case root == "/api/projects" && item.Name != "":
+				projectNames = append(projectNames, item.Name)
+			}
+		}
+	}
+
+	// Completed and archived goals, all on one page
+	history := CompletedGoalsResponse{Goals: []goals.CompletedGoal{}}
+	for {
+		data, ok := get("/api/history/goals", fmt.Sprintf("?limit=500&offset=%d", len(history.Goals)))
+		if !ok {
+			break
+		}
+		var page CompletedGoalsResponse
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse completed goals: %w", err)
+		}
+		history.Goals = append(history.Goals, page.Goals...)
+		history.Total = page.Total
+		if !page.HasMore || len(page.Goals) == 0 {
+			break
+		}
+	}
+	history.Limit = len(history.Goals)
+	for _, g := range history.Goals {
+		if !seen[g.ID] {
+			seen[g.ID] = true
+			goalIDs = append(goalIDs, g.ID)
+		}
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeSnapshotFile(outDir, "/api/history/goals", data, manifest); err != nil {
+		return nil, err
+	}
+
+	for _, id := range goalIDs {
+		for _, view := range snapshotGoalViews {
+			query := ""
+			if view == "chat" {
+				query = fmt.Sprintf("?limit=%d", snapshotChatLimit)
+			}
+			if _, err := export(path.Join("/api/goals", id, view), query); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := export("/api/history/"+id, ""); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range projectNames {
+		for _, view := range snapshotProjectViews {
+			if _, err := export(path.Join("/api/projects", name, view), ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	manifest.Goals = len(goalIDs)
+	manifest.Projects = len(projectNames)
+
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "snapshot.json"), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeSnapshotFile writes an API path's response to <outDir><path>/index.json
+func writeSnapshotFile(outDir, apiPath string, data []byte, manifest *SnapshotManifest) error {
+	file := filepath.Join(outDir, filepath.FromSlash(apiPath), "index.json")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(file), err)
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	manifest.Files++
+	return nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestExportSnapshot(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(dir, "projects", "index.md"), []byte("| Project |\n|---|\n| [test-project](test-project.md) |\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "projects", "test-project.md"), []byte("# Project: test-project\n\n**Base Branch**: `main`\n"), 0644)
+	goals.NewRegistry(dir).Add(goals.RegistryEntry{ID: "abc1234", Title: "Test goal", Projects: []string{"test-project"}, Status: "active"})
+
+	out := t.TempDir()
+	manifest, err := ExportSnapshot(h, p, out)
+	if err != nil {
+		t.Fatalf("ExportSnapshot: %v", err)
+	}
+	if manifest.Goals != 1 || manifest.Projects != 1 || len(manifest.Skipped) != 0 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+
+	// Each API path is at <path>/index.json, as the API returned it
+	read := func(apiPath string, v interface{}) {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(apiPath), "index.json"))
+		if err != nil {
+			t.Fatalf("missing %s: %v", apiPath, err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatalf("%s: %v", apiPath, err)
+		}
+	}
+	var list []GoalSummary
+	read("/api/goals", &list)
+	if len(list) != 1 || list[0].ID != "abc1234" {
+		t.Errorf("unexpected goal list %+v", list)
+	}
+	var detail GoalDetailResponse
+	read("/api/goals/abc1234", &detail)
+	if detail.GoalDetail == nil || detail.Title != "Test goal" {
+		t.Errorf("unexpected goal detail %+v", detail)
+	}
+	var chat []ChatMessage
+	read("/api/goals/abc1234/chat", &chat)
+	var projects []ProjectSummary
+	read("/api/projects", &projects)
+	if len(projects) != 1 || projects[0].Name != "test-project" {
+		t.Errorf("unexpected projects %+v", projects)
+	}
+	var history CompletedGoalsResponse
+	read("/api/history/goals", &history)
+	if _, err := os.Stat(filepath.Join(out, "snapshot.json")); err != nil {
+		t.Errorf("missing snapshot.json: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// FocusRequest is the request body for POST and PUT /api/goals/:id/focus
+type FocusRequest struct {
+	Text     string          `json:"text,omitempty"`     // POST: the instruction to queue
+	Items    []hub.FocusEdit `json:"items,omitempty"`    // PUT: the open instructions, in order
+	Revision *int            `json:"revision,omitempty"` // PUT: revision the edit started from; a newer one gives 409
+	User     string          `json:"user,omitempty"`     // Fallback when X-Vega-User is not set
+}
+
+// handleGoalFocus handles /api/goals/:id/focus - the goal's instruction queue
+// for its next executor sessions:
+//   - GET    /focus           - the queue
+//   - POST   /focus           - queue an instruction after the open ones
+//   - PUT    /focus           - reorder, edit, add or drop open instructions
+//   - POST   /focus/:fid/done - mark an instruction done
+//   - DELETE /focus/:fid      - remove an instruction
+func handleGoalFocus(h *hub.Hub, goalID, itemID, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req FocusRequest
+		if r.Method == http.MethodPost || r.Method == http.MethodPut {
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "Invalid JSON", http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		user := actingUser(r, req.User)
+
+		var focus *hub.GoalFocus
+		var err error
+		switch {
+		case itemID == "" && r.Method == http.MethodGet:
+			focus, err = h.GetGoalFocus(goalID)
+		case itemID == "" && r.Method == http.MethodPost:
+			focus, err = h.AddFocus(goalID, req.Text, user)
+		case itemID == "" && r.Method == http.MethodPut:
+			base := -1
+			if req.Revision != nil {
+				base = *req.Revision
+			}
+			focus, err = h.SetFocus(goalID, req.Items, user, base)
+		case itemID != "" && action == "done" && r.Method == http.MethodPost:
+			focus, err = h.CompleteFocus(goalID, []string{itemID}, user)
+		case itemID != "" && action == "" && r.Method == http.MethodDelete:
+			focus, err = h.RemoveFocus(goalID, itemID, user)
+		case itemID != "" && action != "" && action != "done":
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		switch {
+		case errors.Is(err, hub.ErrFocusInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, hub.ErrFocusNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case errors.Is(err, hub.ErrFocusConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, "Failed to update focus queue: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(focus)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// goalOperation runs a mutating goal endpoint (complete, ice, delete...)
+// holding the goal's operation lock, so two users clicking Complete at once
+// don't both merge. The second request gets a 409 naming the running
+// operation and who started it. Only POSTs take the lock.
+func goalOperation(h *hub.Hub, goalID, operation string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+
+		release, err := h.BeginGoalOperation(goalID, operation, requestUser(r))
+		if err != nil {
+			var busy *hub.OperationInProgressError
+			if !errors.As(err, &busy) {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			log.Printf("[OPS] Rejected %s of goal %s: %s already running", operation, goalID, busy.Current.Operation)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(operations.Result{
+				Success: false,
+				Error: &operations.ErrorInfo{
+					Code:    "operation_in_progress",
+					Message: err.Error(),
+					Details: map[string]string{
+						"operation":  busy.Current.Operation,
+						"holder":     busy.Current.Holder,
+						"started_at": busy.Current.StartedAt.Format(time.RFC3339),
+					},
+				},
+			})
+			return
+		}
+		held := &heldOperation{release: release}
+		defer held.done()
+		next(w, r.WithContext(context.WithValue(r.Context(), heldOperationKey{}, held)))
+	}
+}
+
+// heldOperationKey is the request context key of the goal operation lock
+// goalOperation holds
+type heldOperationKey struct{}
+
+// heldOperation is a goal operation lock that a handler can hand off to a
+// background job, which then releases it when the work is done
+type heldOperation struct {
+	mu        sync.Mutex
+	release   func()
+	handedOff bool
+}
+
+// handOff transfers the request's goal operation lock, if any, to the
+// caller. The returned release is a no-op without one.
+func handOff(r *http.Request) func() {
+	held, ok := r.Context().Value(heldOperationKey{}).(*heldOperation)
+	if !ok {
+		return func() {}
+	}
+	held.mu.Lock()
+	defer held.mu.Unlock()
+	held.handedOff = true
+	return held.release
+}
+
+// done releases the lock unless it was handed off
+func (o *heldOperation) done() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.handedOff {
+		o.release()
+	}
+}
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
@@ -11,24 +17,62 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lasmarois/vega-hub/internal/credentials"
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
 	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/jobs"
+	"github.com/lasmarois/vega-hub/internal/layout"
 	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/lasmarois/vega-hub/internal/pathguard"
 )
 
 // RegisterRoutes sets up all API routes
 func RegisterRoutes(mux *http.ServeMux, h *hub.Hub, p *goals.Parser) {
-	mux.HandleFunc("/api/ask", corsMiddleware(handleAsk(h)))
+	h.SetGoalSummarizer(func(goalID string) (interface{}, error) {
+		return summarizeGoal(h, p, goalID)
+	})
+
+	mux.HandleFunc("/api/ask", corsMiddleware(executorAuth(h, bodyScope, handleAsk(h))))
 	mux.HandleFunc("/api/answer/", corsMiddleware(handleAnswer(h)))
+	mux.HandleFunc("/api/answers", corsMiddleware(handleBatchAnswer(h)))
+	mux.HandleFunc("/api/answers/", corsMiddleware(handleAnswers(h)))
 	mux.HandleFunc("/api/questions", corsMiddleware(handleQuestions(h)))
+	mux.HandleFunc("/api/questions/", corsMiddleware(handleQuestionRoutes(h)))
+	mux.HandleFunc("/api/questions/groups/answer", corsMiddleware(handleAnswerGroup(h)))
+	mux.HandleFunc("/api/question-rules", corsMiddleware(handleQuestionRules(h)))
+	mux.HandleFunc("/api/question-rules/", corsMiddleware(handleQuestionRule(h)))
+	mux.HandleFunc("/api/views", corsMiddleware(handleViews(h)))
+	mux.HandleFunc("/api/views/", corsMiddleware(handleView(h)))
+	mux.HandleFunc("/api/digest/", corsMiddleware(handleDigestRoutes(h)))
+	mux.HandleFunc("/api/state-hooks", corsMiddleware(handleStateHooks(h)))
+	mux.HandleFunc("/api/state-hooks/", corsMiddleware(handleStateHook(h)))
 	mux.HandleFunc("/api/executors", corsMiddleware(handleExecutors(h)))
-	mux.HandleFunc("/api/executor/register", corsMiddleware(handleExecutorRegister(h)))
-	mux.HandleFunc("/api/executor/stop", corsMiddleware(handleExecutorStop(h)))
+	mux.HandleFunc("/api/workers", corsMiddleware(handleWorkers(h)))
+	mux.HandleFunc("/api/executor/register", corsMiddleware(executorAuth(h, bodyScope, handleExecutorRegister(h))))
+	mux.HandleFunc("/api/executor/stop", corsMiddleware(executorAuth(h, bodyScope, handleExecutorStop(h))))
+	mux.HandleFunc("/api/executor/progress", corsMiddleware(executorAuth(h, bodyScope, handleExecutorProgress(h))))
 	mux.HandleFunc("/api/events", handleSSE(h))
+	mux.HandleFunc("/api/events/", corsMiddleware(handleEventRoutes(h)))
+	mux.HandleFunc("/api/presence", corsMiddleware(handlePresence(h)))
 	mux.HandleFunc("/api/health", handleHealth(h))
+	mux.HandleFunc("/api/health/live", handleHealthLive(h))
+	mux.HandleFunc("/api/health/ready", corsMiddleware(handleHealthReady(h)))
+	mux.HandleFunc("/api/calendar.ics", corsMiddleware(handleCalendar(h)))
+	mux.HandleFunc("/api/watcher", corsMiddleware(handleWatcherStatus(h)))
+	mux.HandleFunc("/api/storage", corsMiddleware(handleStorage(h)))
+	mux.HandleFunc("/api/storage/compact", corsMiddleware(handleStorageCompact(h)))
+	mux.HandleFunc("/api/validate", corsMiddleware(handleValidate(h)))
+	mux.HandleFunc("/api/inbox", corsMiddleware(handleInbox(h)))
+	mux.HandleFunc("/api/inbox/", corsMiddleware(handleInbox(h)))
+	mux.HandleFunc("/api/share/", corsMiddleware(handleShare(h, p)))
+	mux.HandleFunc("/api/worktrees/upgrade-hooks", corsMiddleware(handleUpgradeHooks(h)))
+	mux.HandleFunc("/api/decisions", corsMiddleware(handleDecisions(h)))
+	mux.HandleFunc("/api/jobs", corsMiddleware(handleJobs(h)))
+	mux.HandleFunc("/api/jobs/", corsMiddleware(handleJobRoutes(h)))
 	mux.HandleFunc("/api/goals", corsMiddleware(handleGoalsRoot(h, p)))
 	mux.HandleFunc("/api/goals/", corsMiddleware(handleGoalRoutes(h, p)))
 	mux.HandleFunc("/api/projects", corsMiddleware(handleProjectsRoot(h, p)))
@@ -37,28 +81,86 @@ func RegisterRoutes(mux *http.ServeMux, h *hub.Hub, p *goals.Parser) {
 	mux.HandleFunc("/api/history/", corsMiddleware(handleHistoryRoutes(h)))
 	// User identity and credentials routes
 	mux.HandleFunc("/api/user", corsMiddleware(handleGetUser()))
-	mux.HandleFunc("/api/user/", corsMiddleware(handleUserRoutes(p)))
+	mux.HandleFunc("/api/user/", corsMiddleware(handleUserRoutes(h, p)))
 }
 
 // AskRequest is the request body for POST /api/ask
 type AskRequest struct {
-	GoalID    string       `json:"goal_id"`
-	SessionID string       `json:"session_id"`
-	Question  string       `json:"question"`
-	Options   []hub.Option `json:"options,omitempty"`
+	GoalID      string       `json:"goal_id"`
+	SessionID   string       `json:"session_id"`
+	Question    string       `json:"question"`
+	Options     []hub.Option `json:"options,omitempty"`
+	MultiSelect bool         `json:"multi_select,omitempty"`
+	Priority    string       `json:"priority,omitempty"`    // "low", "normal" or "high"; question rules override it
+	Group       string       `json:"group,omitempty"`       // Groups related questions of the goal
+	MaxWait     int          `json:"max_wait,omitempty"`    // Seconds to wait for the answer (default: until answered)
+	QuestionID  string       `json:"question_id,omitempty"` // Wait again for a question still pending from an earlier ask
 }
 
-// AskResponse is the response for POST /api/ask
+// AskResponse is the response for POST /api/ask. When max_wait passes first
+// it is 202 Accepted with pending set: ask again with question_id.
 type AskResponse struct {
-	Answer string `json:"answer"`
+	Answer     string                `json:"answer"`               // Answer rendered as text
+	Structured *hub.StructuredAnswer `json:"structured,omitempty"` // Selected options and attachments
+	QuestionID string                `json:"question_id,omitempty"`
+	Pending    bool                  `json:"pending,omitempty"` // Not answered within max_wait
+}
+
+// AnswerGroupRequest is the request body for POST /api/questions/groups/answer.
+// Each question gets its entry in Answers, or the answer in the embedded
+// AnswerRequest when it has none.
+type AnswerGroupRequest struct {
+	GoalID string `json:"goal_id"`
+	Group  string `json:"group"`
+	AnswerRequest
+	Answers map[string]AnswerRequest `json:"answers,omitempty"` // By question ID
+}
+
+// AnswerGroupResponse is the response for POST /api/questions/groups/answer
+type AnswerGroupResponse struct {
+	Answered []string `json:"answered"` // Question IDs, in order
+	Error    string   `json:"error,omitempty"`
 }
 
 // AnswerRequest is the request body for POST /api/answer/:id
+// Answer is a plain-text answer; the other fields form a structured answer.
 type AnswerRequest struct {
-	Answer string `json:"answer"`
+	Answer      string           `json:"answer,omitempty"`
+	OptionIDs   []string         `json:"option_ids,omitempty"`
+	Text        string           `json:"text,omitempty"`
+	Attachments []hub.Attachment `json:"attachments,omitempty"`
+	User        string           `json:"user,omitempty"` // Fallback when X-Vega-User is not set
+}
+
+// ClaimRequest is the request body for POST /api/answer/:id/claim
+type ClaimRequest struct {
+	User  string `json:"user,omitempty"`  // Fallback when X-Vega-User is not set
+	Draft string `json:"draft,omitempty"` // Answer being typed
+}
+
+// ConflictResponse is returned with 409 when an answer or claim loses to another user
+type ConflictResponse struct {
+	Error  string    `json:"error"`
+	Reason string    `json:"reason"` // "claimed" or "answered"
+	User   string    `json:"user,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// toStructured converts the request into a structured answer
+func (req AnswerRequest) toStructured() *hub.StructuredAnswer {
+	text := req.Text
+	if text == "" {
+		text = req.Answer
+	}
+	return &hub.StructuredAnswer{
+		OptionIDs:   req.OptionIDs,
+		Text:        text,
+		Attachments: req.Attachments,
+	}
 }
 
-// handleAsk handles POST /api/ask - blocks until question is answered
+// handleAsk handles POST /api/ask - blocks until question is answered, the
+// request's max_wait passes or the client disconnects
 func handleAsk(h *hub.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -71,75 +173,794 @@ func handleAsk(h *hub.Hub) http.HandlerFunc {
 			http.Error(w, "Invalid JSON", http.StatusBadRequest)
 			return
 		}
+		if !validIDs(w, "goal ID", req.GoalID, "session ID", req.SessionID, "question ID", req.QuestionID, "group", req.Group) {
+			return
+		}
+		if req.Priority != "" && !hub.ValidPriorities[req.Priority] {
+			http.Error(w, "invalid priority: "+req.Priority+" (valid: low, normal, high)", http.StatusBadRequest)
+			return
+		}
+		if req.MaxWait < 0 {
+			http.Error(w, "max_wait must not be negative", http.StatusBadRequest)
+			return
+		}
 
-		// Generate question ID
-		id := generateID()
+		// Stop waiting when the executor goes away, so abandoned asks don't
+		// hold a goroutine until the question is answered
+		ctx := r.Context()
+		if req.MaxWait > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(req.MaxWait)*time.Second)
+			defer cancel()
+		}
 
-		q := &hub.Question{
-			ID:        id,
-			GoalID:    req.GoalID,
-			SessionID: req.SessionID,
-			Question:  req.Question,
-			Options:   req.Options,
+		var answer string
+		var structured *hub.StructuredAnswer
+		var err error
+		if req.QuestionID != "" {
+			answer, structured, err = h.WaitForAnswer(ctx, req.GoalID, req.QuestionID)
+		} else {
+			answer, structured, err = h.AskContext(ctx, &hub.Question{
+				ID:          generateID(),
+				GoalID:      req.GoalID,
+				SessionID:   req.SessionID,
+				Question:    req.Question,
+				Options:     req.Options,
+				MultiSelect: req.MultiSelect,
+				Priority:    req.Priority,
+				Group:       req.Group,
+			})
+		}
+
+		var pending *hub.PendingAnswerError
+		switch {
+		case errors.As(err, &pending):
+			if r.Context().Err() != nil {
+				return // Client is gone
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(AskResponse{QuestionID: pending.QuestionID, Pending: true})
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
 		}
 
-		// This blocks until answer is received
-		answer := h.Ask(q)
+		response := AskResponse{Answer: answer}
+		if structured != nil && !structured.IsPlainText() {
+			response.Structured = structured
+		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(AskResponse{Answer: answer})
+		json.NewEncoder(w).Encode(response)
 	}
 }
 
-// handleAnswer handles POST /api/answer/:id
+// handleAnswer handles POST /api/answer/:id and /api/answer/:id/claim
 func handleAnswer(h *hub.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
 		// Extract ID from path
-		id := strings.TrimPrefix(r.URL.Path, "/api/answer/")
+		path := strings.TrimPrefix(r.URL.Path, "/api/answer/")
+		id, action, _ := strings.Cut(path, "/")
 		if id == "" {
 			http.Error(w, "Missing question ID", http.StatusBadRequest)
 			return
 		}
 
+		switch action {
+		case "":
+			handleAnswerQuestion(h, id)(w, r)
+		case "claim":
+			handleClaimQuestion(h, id)(w, r)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}
+}
+
+// handleAnswerQuestion handles POST /api/answer/:id
+func handleAnswerQuestion(h *hub.Hub, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
 		var req AnswerRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "Invalid JSON", http.StatusBadRequest)
 			return
 		}
 
-		if !h.Answer(id, req.Answer) {
+		user := actingUser(r, req.User)
+		if err := h.AnswerStructuredAs(id, user, req.toStructured()); err != nil {
+			writeAnswerError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+	}
+}
+
+// handleClaimQuestion handles POST/DELETE /api/answer/:id/claim - claim or release a question.
+// Re-posting an existing claim renews it and updates the shared draft.
+func handleClaimQuestion(h *hub.Hub, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req ClaimRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+
+		user := actingUser(r, req.User)
+		if user == "" {
+			http.Error(w, "User is required (X-Vega-User header or user field)", http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			if err := h.ReleaseClaim(id, user); err != nil {
+				writeAnswerError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+			return
+		}
+
+		claim, err := h.ClaimQuestion(id, user, req.Draft)
+		if err != nil {
+			writeAnswerError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(claim)
+	}
+}
+
+// handleAnswers handles /api/answers/:id - an answer's delivery status and drafts.
+// GET shows it, PUT/PATCH edits a scheduled answer, DELETE retracts it.
+func handleAnswers(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/answers/")
+		if id == "" || strings.Contains(id, "/") {
+			http.Error(w, "Question ID required", http.StatusBadRequest)
+			return
+		}
+
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			rec, err := h.GetAnswer(id)
+			if err != nil {
+				http.Error(w, "Question not found", http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(rec)
+			return
+		case http.MethodPut, http.MethodPatch:
+			var req AnswerRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			err = h.EditAnswer(id, actingUser(r, req.User), req.toStructured())
+		case http.MethodDelete:
+			err = h.RetractAnswer(id, requestUser(r))
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			writeAnswerError(w, err)
+			return
+		}
+
+		rec, err := h.GetAnswer(id)
+		if err != nil {
 			http.Error(w, "Question not found", http.StatusNotFound)
 			return
 		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rec)
+	}
+}
+
+// writeAnswerError maps hub answer/claim errors to HTTP responses
+func writeAnswerError(w http.ResponseWriter, err error) {
+	status := answerErrorStatus(err)
+	var conflict *hub.ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(newConflictResponse(conflict))
+	case status == http.StatusNotFound:
+		http.Error(w, "Question not found", status)
+	default:
+		http.Error(w, err.Error(), status)
+	}
+}
+
+// answerErrorStatus returns the HTTP status of a hub answer/claim error
+func answerErrorStatus(err error) int {
+	var conflict *hub.ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return http.StatusConflict
+	case errors.Is(err, hub.ErrInvalidAnswer):
+		return http.StatusBadRequest
+	case errors.Is(err, hub.ErrAnswerDelivered), errors.Is(err, hub.ErrNoAnswer):
+		return http.StatusConflict
+	default:
+		return http.StatusNotFound
+	}
+}
+
+func newConflictResponse(conflict *hub.ConflictError) ConflictResponse {
+	return ConflictResponse{
+		Error:  conflict.Error(),
+		Reason: conflict.Reason,
+		User:   conflict.User,
+		At:     conflict.At,
+	}
+}
+
+// handleQuestions handles GET /api/questions
+func handleQuestions(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		questions := h.GetPendingQuestions()
+
+		// Optional filters set by question rules
+		assignee := r.URL.Query().Get("assignee")
+		priority := r.URL.Query().Get("priority")
+		if assignee != "" || priority != "" {
+			filtered := make([]*hub.Question, 0, len(questions))
+			for _, q := range questions {
+				if assignee != "" && !q.IsAssignedTo(assignee) {
+					continue
+				}
+				if priority != "" && q.Priority != priority {
+					continue
+				}
+				filtered = append(filtered, q)
+			}
+			questions = filtered
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("grouped") == "true" {
+			json.NewEncoder(w).Encode(hub.GroupQuestions(questions))
+			return
+		}
+		json.NewEncoder(w).Encode(questions)
+	}
+}
+
+// handleAnswerGroup handles POST /api/questions/groups/answer - answers all
+// pending questions of a goal's group
+func handleAnswerGroup(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req AnswerGroupRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if req.Group == "" {
+			http.Error(w, "group is required", http.StatusBadRequest)
+			return
+		}
+		if !validIDs(w, "goal ID", req.GoalID, "group", req.Group) {
+			return
+		}
+
+		user := actingUser(r, req.User)
+		answers := make(map[string]*hub.StructuredAnswer, len(req.Answers))
+		for id, answer := range req.Answers {
+			answers[id] = answer.toStructured()
+		}
+		var fallback *hub.StructuredAnswer
+		if fallbackAnswer := req.AnswerRequest.toStructured(); !fallbackAnswer.IsEmpty() {
+			fallback = fallbackAnswer
+		}
+
+		answered, err := h.AnswerGroupAs(req.GoalID, req.Group, user, answers, fallback)
+		var partial *hub.GroupAnswerError
+		if errors.As(err, &partial) && len(partial.Answered) > 0 {
+			// Some questions were answered: report which before the failure
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(AnswerGroupResponse{Answered: partial.Answered, Error: err.Error()})
+			return
+		}
+		if err != nil {
+			writeAnswerError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AnswerGroupResponse{Answered: answered})
+	}
+}
+
+// handleQuestionRoutes handles /api/questions/:id/* routes
+func handleQuestionRoutes(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/questions/")
+		parts := strings.SplitN(path, "/", 2)
+		if parts[0] == "" {
+			http.Error(w, "Missing question ID", http.StatusBadRequest)
+			return
+		}
+		if len(parts) < 2 {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		switch parts[1] {
+		case "suggestions":
+			handleQuestionSuggestions(h, parts[0])(w, r)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}
+}
+
+// handleQuestionSuggestions handles GET /api/questions/:id/suggestions - past answers to similar questions
+func handleQuestionSuggestions(h *hub.Hub, questionID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit := hub.DefaultSuggestionLimit
+		if l := r.URL.Query().Get("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n <= 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		suggestions, err := h.SuggestAnswers(questionID, limit)
+		if err != nil {
+			if errors.Is(err, hub.ErrQuestionNotFound) {
+				http.Error(w, "Question not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(suggestions)
+	}
+}
+
+// handleQuestionRules handles GET/POST /api/question-rules - list or add question rules
+func handleQuestionRules(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			rules, err := h.Rules().List()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(rules)
+
+		case http.MethodPost:
+			var rule hub.QuestionRule
+			if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			if err := h.Rules().Add(&rule); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(rule)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// handleQuestionRule handles DELETE /api/question-rules/:id
+func handleQuestionRule(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/question-rules/")
+		if id == "" {
+			http.Error(w, "Missing rule ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.Rules().Delete(id); err != nil {
+			if errors.Is(err, hub.ErrRuleNotFound) {
+				http.Error(w, "Rule not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
 	}
 }
 
-// handleQuestions handles GET /api/questions
-func handleQuestions(h *hub.Hub) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// requestUser returns the user whose views and preferences a request works
+// on: the X-Vega-User header, then ?user=, then the user vega-hub runs as
+// (the CLI's default too)
+func requestUser(r *http.Request) string {
+	if user := r.Header.Get("X-Vega-User"); user != "" {
+		return user
+	}
+	if user := r.URL.Query().Get("user"); user != "" {
+		return user
+	}
+	if u, err := credentials.GetCurrentUser(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// actingUser returns the user a change is attributed to: the X-Vega-User
+// header, then the user given in the request body, then requestUser
+func actingUser(r *http.Request, given string) string {
+	if user := r.Header.Get("X-Vega-User"); user != "" {
+		return user
+	}
+	if given != "" {
+		return given
+	}
+	return requestUser(r)
+}
+
+// requestBaseURL returns the scheme and host the request reached vega-hub at
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// handleViews handles GET/POST /api/views - list or save the user's views
+func handleViews(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := requestUser(r)
+		switch r.Method {
+		case http.MethodGet:
+			views, err := h.Views().List(user)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(views)
+
+		case http.MethodPost:
+			var view hub.SavedView
+			if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			view.User = user
+			if err := h.Views().Add(&view); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(view)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// handleView handles /api/views/:id - GET, PATCH (replace) or DELETE one of
+// the user's views. GET /api/views/default returns the user's default view.
+func handleView(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/views/")
+		if id == "" {
+			http.Error(w, "Missing view ID", http.StatusBadRequest)
+			return
+		}
+		user := requestUser(r)
+
+		var view *hub.SavedView
+		var err error
+		switch {
+		case r.Method == http.MethodGet && id == "default":
+			view, err = h.Views().Default(user)
+		case r.Method == http.MethodGet:
+			view, err = h.Views().Get(user, id)
+		case r.Method == http.MethodPatch || r.Method == http.MethodPut:
+			var update hub.SavedView
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			view, err = h.Views().Update(user, id, &update)
+		case r.Method == http.MethodDelete:
+			err = h.Views().Delete(user, id)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			if errors.Is(err, hub.ErrViewNotFound) {
+				http.Error(w, "View not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if view == nil {
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+			return
+		}
+		json.NewEncoder(w).Encode(view)
+	}
+}
+
+// handleStorage handles GET /api/storage: disk usage per category (history,
+// transcripts, events, outputs) with the retention rules and last compaction
+func handleStorage(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report, err := h.GetStorage()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// handleStorageCompact handles POST /api/storage/compact: apply the retention
+// config now instead of waiting for the next scheduled run
+func handleStorageCompact(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		result, err := h.CompactStorage()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// DigestSubscriptionRequest is the request body for POST /api/digest/subscription
+type DigestSubscriptionRequest struct {
+	User       string `json:"user,omitempty"`  // Fallback when X-Vega-User is not set
+	Email      string `json:"email,omitempty"` // Required when subscribing for the first time
+	Subscribed bool   `json:"subscribed"`
+}
+
+// handleDigestRoutes handles /api/digest/* routes:
+//   - GET  /api/digest/preview?user=...       - digest the user would get now
+//   - POST /api/digest/send[?user=...]        - send digests to subscribed users now
+//   - POST /api/digest/subscription           - opt in or out of digest emails
+//
+// The user defaults to the X-Vega-User header, then the user vega-hub runs as.
+// since= (RFC3339) overrides the start of the period, which defaults to one
+// digest interval ago.
+func handleDigestRoutes(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since := h.DigestPeriodStart()
+		if s := r.URL.Query().Get("since"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				http.Error(w, "Invalid since (expected RFC3339)", http.StatusBadRequest)
+				return
+			}
+			since = t
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/api/digest/") {
+		case "preview":
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			user := requestUser(r)
+			if user == "" {
+				http.Error(w, "User is required (X-Vega-User header or user parameter)", http.StatusBadRequest)
+				return
+			}
+			digest, err := h.BuildDigest(user, since)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(digest)
+
+		case "send":
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			results, err := h.SendDigests(r.URL.Query().Get("user"), since)
+			if err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, hub.ErrDigestNotConfigured) {
+					status = http.StatusConflict
+				}
+				http.Error(w, err.Error(), status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(results)
+
+		case "subscription":
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			var req DigestSubscriptionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			user := actingUser(r, req.User)
+			if user == "" {
+				http.Error(w, "User is required (X-Vega-User header or user field)", http.StatusBadRequest)
+				return
+			}
+			sub, err := h.SetDigestSubscription(user, req.Email, req.Subscribed)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(sub)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+// handleStateHooks handles GET/POST /api/state-hooks - list or add state transition hooks.
+// Only HTTP callback hooks can be added here: command hooks run shell commands
+// on the hub's host, so they are configured in .vega-hub-state-hooks.json.
+func handleStateHooks(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks := h.StateManager().Hooks()
+		switch r.Method {
+		case http.MethodGet:
+			list, err := hooks.List()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if list == nil {
+				list = []*goals.TransitionHook{}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(list)
+
+		case http.MethodPost:
+			var hook goals.TransitionHook
+			if err := json.NewDecoder(r.Body).Decode(&hook); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			if hook.Command != "" {
+				http.Error(w, "command hooks can only be configured in .vega-hub-state-hooks.json; use url for an HTTP callback", http.StatusForbidden)
+				return
+			}
+			if err := hooks.Add(&hook); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(hook)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// handleStateHook handles DELETE /api/state-hooks/:id. Like adding them,
+// removing command hooks is left to .vega-hub-state-hooks.json.
+func handleStateHook(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/state-hooks/")
+		if id == "" {
+			http.Error(w, "Missing hook ID", http.StatusBadRequest)
+			return
+		}
+
+		hooks := h.StateManager().Hooks()
+		list, err := hooks.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, hook := range list {
+			if hook.ID == id && hook.Command != "" {
+				http.Error(w, "command hooks can only be removed from .vega-hub-state-hooks.json", http.StatusForbidden)
+				return
+			}
+		}
+
+		if err := hooks.Delete(id); err != nil {
+			if errors.Is(err, goals.ErrHookNotFound) {
+				http.Error(w, "Hook not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		questions := h.GetPendingQuestions()
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(questions)
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
 	}
 }
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status     string             `json:"status"`              // "ok" or "degraded"
-	StuckGoals *StuckGoalsHealth  `json:"stuck_goals,omitempty"`
+	Status          string            `json:"status"` // "ok" or "degraded"
+	StuckThresholds map[string]string `json:"stuck_thresholds"` // How long goals may stay in each transient state
+	StuckGoals      *StuckGoalsHealth `json:"stuck_goals,omitempty"`
+	Errors          hub.ErrorStats    `json:"errors"` // Requests that failed with a panic
 }
 
 // StuckGoalsHealth contains stuck goal info for health response
@@ -150,31 +971,53 @@ type StuckGoalsHealth struct {
 
 // StuckGoalSummary is a summary of a stuck goal for health response
 type StuckGoalSummary struct {
-	GoalID   string `json:"goal_id"`
-	State    string `json:"state"`
-	Since    string `json:"since"`
-	Duration string `json:"duration"`
+	GoalID    string `json:"goal_id"`
+	State     string `json:"state"`
+	Since     string `json:"since"`
+	Duration  string `json:"duration"`
+	Threshold string `json:"threshold"`
 }
 
-// handleHealth handles GET /api/health - includes stuck goals check
+// handleHealth handles GET /api/health - includes stuck goals check.
+// Goals are stuck after the configured threshold of their state (see
+// hub.LoadStuckConfig); ?threshold= (a Go duration) applies one threshold to
+// every state instead.
 func handleHealth(h *hub.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		thresholds := h.StuckThresholds()
+		if value := r.URL.Query().Get("threshold"); value != "" {
+			threshold, err := time.ParseDuration(value)
+			if err != nil || threshold <= 0 {
+				http.Error(w, "Invalid threshold (expected a duration such as 30m)", http.StatusBadRequest)
+				return
+			}
+			for state := range thresholds {
+				thresholds[state] = threshold
+			}
+		}
+
 		response := HealthResponse{
-			Status: "ok",
+			Status:          "ok",
+			StuckThresholds: make(map[string]string, len(thresholds)),
+			Errors:          h.ErrorStats(),
+		}
+		for state, threshold := range thresholds {
+			response.StuckThresholds[string(state)] = threshold.String()
 		}
 
-		// Check for stuck goals (goals in transient state for >1 hour)
-		stuckGoals, err := h.GetStuckGoals(1 * time.Hour)
+		// Check for stuck goals (goals in transient state for longer than its threshold)
+		stuckGoals, err := h.GetStuckGoalsByState(thresholds)
 		if err == nil && len(stuckGoals) > 0 {
 			response.Status = "degraded"
 
 			summaries := make([]StuckGoalSummary, 0, len(stuckGoals))
 			for _, sg := range stuckGoals {
 				summaries = append(summaries, StuckGoalSummary{
-					GoalID:   sg.GoalID,
-					State:    string(sg.State),
-					Since:    sg.Since.Format(time.RFC3339),
-					Duration: sg.Duration.Round(time.Minute).String(),
+					GoalID:    sg.GoalID,
+					State:     string(sg.State),
+					Since:     sg.Since.Format(time.RFC3339),
+					Duration:  sg.Duration.Round(time.Minute).String(),
+					Threshold: sg.Threshold.String(),
 				})
 			}
 
@@ -189,6 +1032,40 @@ func handleHealth(h *hub.Hub) http.HandlerFunc {
 	}
 }
 
+// LivenessResponse is the response for GET /api/health/live
+type LivenessResponse struct {
+	Status    string    `json:"status"` // Always "ok": the process answers
+	StartedAt time.Time `json:"started_at"`
+	Uptime    string    `json:"uptime"`
+}
+
+// handleHealthLive handles GET /api/health/live - the process is up and
+// serving requests, whatever the state of its dependencies
+func handleHealthLive(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(LivenessResponse{
+			Status:    "ok",
+			StartedAt: h.StartedAt(),
+			Uptime:    time.Since(h.StartedAt()).Round(time.Second).String(),
+		})
+	}
+}
+
+// handleHealthReady handles GET /api/health/ready - the status of each
+// dependency (vega dir, git, goal registry, file watcher), with 503 when any
+// of them is down
+func handleHealthReady(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := h.Readiness()
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
 // ExecutorRegisterRequest is the request body for POST /api/executor/register
 type ExecutorRegisterRequest struct {
 	GoalID    string `json:"goal_id"`
@@ -211,6 +1088,16 @@ type ExecutorStopRequest struct {
 	Reason          string `json:"reason,omitempty"`
 }
 
+// ExecutorProgressRequest is the request body for POST /api/executor/progress
+type ExecutorProgressRequest struct {
+	GoalID    string `json:"goal_id"`
+	SessionID string `json:"session_id"`
+	Status    string `json:"status"` // e.g. "running tests"; empty clears it
+
+	// Focus instructions the executor finished (see hub.GoalFocus)
+	FocusDone []string `json:"focus_done,omitempty"`
+}
+
 // handleExecutorRegister handles POST /api/executor/register
 func handleExecutorRegister(h *hub.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -224,6 +1111,9 @@ func handleExecutorRegister(h *hub.Hub) http.HandlerFunc {
 			http.Error(w, "Invalid JSON", http.StatusBadRequest)
 			return
 		}
+		if !validIDs(w, "goal ID", req.GoalID, "session ID", req.SessionID) {
+			return
+		}
 
 		// Register the executor and get context
 		// Note: This is the legacy hook-based registration path, user is unknown
@@ -250,6 +1140,9 @@ func handleExecutorStop(h *hub.Hub) http.HandlerFunc {
 			http.Error(w, "Invalid JSON", http.StatusBadRequest)
 			return
 		}
+		if !validIDs(w, "goal ID", req.GoalID, "session ID", req.SessionID) {
+			return
+		}
 
 		// Stop the executor with Claude session info
 		h.StopExecutorWithClaudeInfo(hub.StopExecutorRequest{
@@ -265,6 +1158,45 @@ func handleExecutorStop(h *hub.Hub) http.HandlerFunc {
 	}
 }
 
+// handleExecutorProgress handles POST /api/executor/progress - an executor
+// reports what it is doing
+func handleExecutorProgress(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req ExecutorProgressRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if !validIDs(w, "goal ID", req.GoalID, "session ID", req.SessionID) {
+			return
+		}
+
+		progress, err := h.ReportProgress(req.GoalID, req.SessionID, req.Status)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if len(req.FocusDone) > 0 {
+			if _, err := h.CompleteFocus(req.GoalID, req.FocusDone, req.SessionID); err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, hub.ErrFocusNotFound) {
+					status = http.StatusNotFound
+				}
+				http.Error(w, err.Error(), status)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "progress": progress})
+	}
+}
+
 // handleExecutors handles GET /api/executors
 func handleExecutors(h *hub.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -279,12 +1211,45 @@ func handleExecutors(h *hub.Hub) http.HandlerFunc {
 	}
 }
 
+// handleWorkers handles GET /api/workers - lists remote workers executors can be spawned on
+func handleWorkers(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		workers, err := h.GetWorkers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if workers == nil {
+			workers = []*hub.Worker{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(workers)
+	}
+}
+
+// handleWatcherStatus handles GET /api/watcher - file watcher state for debugging
+func handleWatcherStatus(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.WatcherStatus())
+	}
+}
+
 // corsMiddleware adds CORS headers for development
 func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Vega-User, Authorization")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -295,14 +1260,32 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// generateID creates a simple unique ID
+// validIDs checks IDs taken from a request path or body before they reach a
+// file path or a git command, writing a 400 for the first unsafe one. ids
+// alternates names and values; empty values are skipped.
+func validIDs(w http.ResponseWriter, ids ...string) bool {
+	for i := 0; i+1 < len(ids); i += 2 {
+		if ids[i+1] == "" {
+			continue
+		}
+		if err := pathguard.ValidateID(ids[i], ids[i+1]); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+// generateID creates a unique ID: a timestamp plus a random suffix, so
+// questions asked within the same millisecond don't replace each other
 func generateID() string {
-	// Simple timestamp-based ID for MVP
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
 	return strings.ReplaceAll(
 		strings.ReplaceAll(
 			time.Now().Format("20060102-150405.000"),
 			".", "-"),
-		":", "")
+		":", "") + "-" + hex.EncodeToString(suffix)
 }
 
 // GoalSummary combines registry goal with runtime status
@@ -314,6 +1297,9 @@ type GoalSummary struct {
 	WorkspaceStatus  string                  `json:"workspace_status,omitempty"` // "ready", "missing", "error" (from project)
 	WorkspaceError   string                  `json:"workspace_error,omitempty"`  // Error message if workspace not ready
 	CompletionStatus *goals.CompletionStatus `json:"completion_status,omitempty"`
+	Review           *goals.Review           `json:"review,omitempty"` // Latest reviewer decision
+	CI               *operations.CIStatus    `json:"ci,omitempty"`     // Goal branch CI at the last check
+	ExecutorProgress *hub.ExecutorProgress   `json:"executor_progress,omitempty"` // Latest status its executors reported
 	// Hierarchy fields
 	ParentID    string   `json:"parent_id,omitempty"`
 	Children    []string `json:"children,omitempty"`
@@ -321,21 +1307,59 @@ type GoalSummary struct {
 	Depth       int      `json:"depth"`
 	IsBlocked   bool     `json:"is_blocked,omitempty"`
 	Blockers    []string `json:"blockers,omitempty"` // IDs of blocking goals
+	Position    int      `json:"position"`           // Order within its status column on the board
 }
 
 // CreateGoalRequest is the request body for POST /api/goals
 type CreateGoalRequest struct {
-	Title      string `json:"title"`
-	Project    string `json:"project"`
-	BaseBranch string `json:"base_branch,omitempty"`
-	ParentID   string `json:"parent_id,omitempty"` // Parent goal ID for hierarchical goals
+	Title      string   `json:"title"`
+	Project    string   `json:"project"`
+	BaseBranch string   `json:"base_branch,omitempty"`
+	ParentID   string   `json:"parent_id,omitempty"` // Parent goal ID for hierarchical goals
+	IssueURL   string   `json:"issue_url,omitempty"`
+	TrackerID  string   `json:"tracker_id,omitempty"` // Defaults to the ID in issue_url; prefixes the branch
+	Wait       bool     `json:"wait,omitempty"`       // Create the worktree before responding
+	Draft      bool     `json:"draft,omitempty"`      // Add to the backlog without a branch or worktree
+	Priority   int      `json:"priority,omitempty"`   // Backlog order of drafts, highest first
+	Tags       []string `json:"tags,omitempty"`
+	Alias      string   `json:"alias,omitempty"` // Unique name usable in place of the goal ID
+
+	// SkipPreflight skips the pre-flight checks on the project checkout
+	SkipPreflight bool `json:"skip_preflight,omitempty"`
+}
+
+// CreateGoalResponse is the response for POST /api/goals. Unless the request
+// waits, the worktree is still being provisioned by Job.
+type CreateGoalResponse struct {
+	Success bool                     `json:"success"`
+	Data    *operations.CreateResult `json:"data"`
+	Job     *jobs.Job                `json:"job,omitempty"`
+
+	// Existing goals that look like the new one, so it can be linked to or
+	// resumed instead of duplicated
+	SimilarGoals []hub.SimilarGoal `json:"similar_goals,omitempty"`
+}
+
+// CloneGoalRequest is the request body for POST /api/goals/:id/clone
+type CloneGoalRequest struct {
+	Title      string `json:"title,omitempty"`       // Default: the source goal's title
+	Project    string `json:"project,omitempty"`     // Default: the source goal's first project
+	BaseBranch string `json:"base_branch,omitempty"` // Default: the project's base branch
+	FromBranch bool   `json:"from_branch,omitempty"` // Start from the source goal's branch
+	NoWorktree bool   `json:"no_worktree,omitempty"`
+	Async      bool   `json:"async,omitempty"` // Respond 202 with the job instead of waiting
 }
 
 // CompleteGoalRequest is the request body for POST /api/goals/:id/complete
 type CompleteGoalRequest struct {
-	Project string `json:"project"`
-	NoMerge bool   `json:"no_merge,omitempty"`
-	Force   bool   `json:"force,omitempty"`
+	Project       string `json:"project"`
+	NoMerge       bool   `json:"no_merge,omitempty"`
+	Force         bool   `json:"force,omitempty"`
+	MergeStrategy string   `json:"merge_strategy,omitempty"` // Defaults to the project's merge strategy
+	Commits       []string `json:"commits,omitempty"`        // Cherry-pick only these goal branch commits instead of merging
+	CommentIssue  bool     `json:"comment_issue,omitempty"`  // Comment on the goal's linked issue
+	Async         bool     `json:"async,omitempty"`          // Respond 202 with the job instead of waiting
+	User          string   `json:"user,omitempty"`           // Fallback when X-Vega-User is not set
 }
 
 // IceGoalRequest is the request body for POST /api/goals/:id/ice
@@ -344,22 +1368,26 @@ type IceGoalRequest struct {
 	Reason         string `json:"reason"`
 	RemoveWorktree bool   `json:"remove_worktree,omitempty"` // If true, remove worktree (default: keep)
 	Force          bool   `json:"force,omitempty"`           // If true, ignore uncommitted changes
+	User           string `json:"user,omitempty"`            // Fallback when X-Vega-User is not set
 }
 
 // CleanupGoalRequest is the request body for POST /api/goals/:id/cleanup
 type CleanupGoalRequest struct {
 	Project string `json:"project"`
+	User    string `json:"user,omitempty"` // Fallback when X-Vega-User is not set
 }
 
 // ResumeGoalRequest is the request body for POST /api/goals/:id/resume
 type ResumeGoalRequest struct {
 	Project string `json:"project"`
+	User    string `json:"user,omitempty"` // Fallback when X-Vega-User is not set
 }
 
 // DeleteGoalRequest is the request body for POST /api/goals/:id/delete
 type DeleteGoalRequest struct {
-	Force        bool `json:"force"`         // Skip uncommitted/unpushed warnings
-	DeleteBranch bool `json:"delete_branch"` // Also delete git branch after worktree removal
+	Force        bool   `json:"force"`          // Skip uncommitted/unpushed warnings
+	DeleteBranch bool   `json:"delete_branch"`  // Also delete git branch after worktree removal
+	User         string `json:"user,omitempty"` // Fallback when X-Vega-User is not set
 }
 
 // DeleteWarning represents a warning during pre-flight checks
@@ -395,7 +1423,13 @@ func handleGoalsRoot(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 	}
 }
 
-// handleGoals handles GET /api/goals - lists all goals with runtime status
+// goalSummaryWorkers bounds how many goal summaries handleGoals builds at once
+const goalSummaryWorkers = 8
+
+// handleGoals handles GET /api/goals - lists all goals with runtime status.
+// Goals are listed in registry order, except that within each status they
+// follow the kanban board order; ?project= lists one project's goals in its
+// board order.
 func handleGoals(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -409,101 +1443,233 @@ func handleGoals(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 			http.Error(w, "Failed to parse registry: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
+		project := r.URL.Query().Get("project")
+		if project != "" {
+			registryGoals = filterGoalsByProject(registryGoals, project)
+		}
+		if tag := r.URL.Query().Get("tag"); tag != "" {
+			registryGoals = filterGoalsByTag(registryGoals, tag)
+		}
 
-		// Get runtime state
-		executors := h.GetActiveExecutors()
-		questions := h.GetPendingQuestions()
+		summarizer := newGoalSummarizer(h, p, registryGoals)
 
-		// Build executor and question maps by goal ID
-		executorsByGoal := make(map[string]int)
-		for _, e := range executors {
-			executorsByGoal[e.GoalID]++
+		// Build summaries concurrently; each worker fills its own slots so the
+		// registry order is kept
+		summaries := make([]GoalSummary, len(registryGoals))
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for i := 0; i < goalSummaryWorkers && i < len(registryGoals); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for idx := range jobs {
+					summaries[idx] = summarizer.summarize(registryGoals[idx])
+				}
+			}()
 		}
+		for idx := range registryGoals {
+			jobs <- idx
+		}
+		close(jobs)
+		wg.Wait()
 
-		questionsByGoal := make(map[string]int)
-		for _, q := range questions {
-			questionsByGoal[q.GoalID]++
+		if err := applyBoardOrder(p.Dir(), project, summaries); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
-		// Cache project workspace status
-		projectStatus := make(map[string]struct {
-			status string
-			error  string
-		})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summaries)
+	}
+}
 
-		// Initialize hierarchy manager
-		hm := goals.NewHierarchyManager(p.Dir())
-		dm := goals.NewDependencyManager(p.Dir())
+// goalSummarizer builds GoalSummaries from the runtime state, workspace and
+// CI status gathered once for a set of goals
+type goalSummarizer struct {
+	h               *hub.Hub
+	hm              *goals.HierarchyManager
+	dm              *goals.DependencyManager
+	executorsByGoal map[string]int
+	questionsByGoal map[string]int
+	progressByGoal  map[string]hub.ExecutorProgress
+	projectStatus   map[string]workspaceStatus
+	ciStatus        map[string]operations.CIStatus
+}
 
-		// Build summaries
-		summaries := make([]GoalSummary, 0, len(registryGoals))
-		for _, g := range registryGoals {
-			// Get hierarchy info
-			parentID, _ := hm.GetParentID(g.ID)
-			children, _ := hm.GetChildren(g.ID)
-			isBlocked := dm.IsBlocked(g.ID)
-			blockerIDs := dm.GetBlockerIDs(g.ID)
-
-			summary := GoalSummary{
-				Goal:             g,
-				PendingQuestions: questionsByGoal[g.ID],
-				ActiveExecutors:  executorsByGoal[g.ID],
-				ParentID:         parentID,
-				Children:         children,
-				HasChildren:      len(children) > 0,
-				Depth:            hm.GetHierarchyDepth(g.ID),
-				IsBlocked:        isBlocked,
-				Blockers:         blockerIDs,
-			}
-
-			// Determine executor status
-			if questionsByGoal[g.ID] > 0 {
-				summary.ExecutorStatus = "waiting"
-			} else if executorsByGoal[g.ID] > 0 {
-				summary.ExecutorStatus = "running"
-			} else if g.Status == "active" {
-				summary.ExecutorStatus = "stopped"
-			} else {
-				summary.ExecutorStatus = "none"
-			}
+// workspaceStatus is a project's workspace status as shown on its goals
+type workspaceStatus struct {
+	status string
+	error  string
+}
 
-			// Get workspace status from first project
-			if len(g.Projects) > 0 {
-				projectName := g.Projects[0]
-				if cached, ok := projectStatus[projectName]; ok {
-					summary.WorkspaceStatus = cached.status
-					summary.WorkspaceError = cached.error
-				} else {
-					// Parse project to get workspace status
-					if proj, err := p.ParseProject(projectName); err == nil {
-						summary.WorkspaceStatus = proj.WorkspaceStatus
-						summary.WorkspaceError = proj.WorkspaceError
-						projectStatus[projectName] = struct {
-							status string
-							error  string
-						}{proj.WorkspaceStatus, proj.WorkspaceError}
-					} else {
-						summary.WorkspaceStatus = "error"
-						summary.WorkspaceError = "Project config not found"
-						projectStatus[projectName] = struct {
-							status string
-							error  string
-						}{"error", "Project config not found"}
-					}
-				}
-			}
+// newGoalSummarizer gathers what summarizing the goals in list needs
+func newGoalSummarizer(h *hub.Hub, p *goals.Parser, list []goals.Goal) *goalSummarizer {
+	s := &goalSummarizer{
+		h:               h,
+		hm:              goals.NewHierarchyManager(p.Dir()),
+		dm:              goals.NewDependencyManager(p.Dir()),
+		executorsByGoal: make(map[string]int),
+		questionsByGoal: make(map[string]int),
+		progressByGoal:  h.GoalProgress(),
+		projectStatus:   make(map[string]workspaceStatus),
+	}
+
+	// Build executor and question maps by goal ID
+	for _, e := range h.GetActiveExecutors() {
+		s.executorsByGoal[e.GoalID]++
+	}
+	for _, q := range h.GetPendingQuestions() {
+		s.questionsByGoal[q.GoalID]++
+	}
+
+	// Resolve workspace status once per project
+	for _, g := range list {
+		if len(g.Projects) == 0 {
+			continue
+		}
+		projectName := g.Projects[0]
+		if _, ok := s.projectStatus[projectName]; ok {
+			continue
+		}
+		if proj, err := p.ParseProject(projectName); err == nil {
+			s.projectStatus[projectName] = workspaceStatus{proj.WorkspaceStatus, proj.WorkspaceError}
+		} else {
+			s.projectStatus[projectName] = workspaceStatus{"error", "Project config not found"}
+		}
+	}
+
+	// CI of goal branches, from the CI monitor
+	var err error
+	if s.ciStatus, err = operations.LoadCIStatus(p.Dir()); err != nil {
+		log.Printf("[GOALS] %v", err)
+	}
+	return s
+}
+
+// summarize builds one goal's summary; safe for concurrent use
+func (s *goalSummarizer) summarize(g goals.Goal) GoalSummary {
+	// Get hierarchy info
+	parentID, _ := s.hm.GetParentID(g.ID)
+	children, _ := s.hm.GetChildren(g.ID)
+
+	summary := GoalSummary{
+		Goal:             g,
+		PendingQuestions: s.questionsByGoal[g.ID],
+		ActiveExecutors:  s.executorsByGoal[g.ID],
+		ParentID:         parentID,
+		Children:         children,
+		HasChildren:      len(children) > 0,
+		Depth:            s.hm.GetHierarchyDepth(g.ID),
+		IsBlocked:        s.dm.IsBlocked(g.ID),
+		Blockers:         s.dm.GetBlockerIDs(g.ID),
+	}
+	if ci, ok := s.ciStatus[g.ID]; ok {
+		summary.CI = &ci
+	}
+	if progress, ok := s.progressByGoal[g.ID]; ok {
+		summary.ExecutorProgress = &progress
+	}
+
+	// Determine executor status
+	if s.questionsByGoal[g.ID] > 0 {
+		summary.ExecutorStatus = "waiting"
+	} else if s.executorsByGoal[g.ID] > 0 {
+		summary.ExecutorStatus = "running"
+	} else if g.Status == "active" {
+		summary.ExecutorStatus = "stopped"
+	} else {
+		summary.ExecutorStatus = "none"
+	}
+
+	// Get workspace status from first project
+	if len(g.Projects) > 0 {
+		ws := s.projectStatus[g.Projects[0]]
+		summary.WorkspaceStatus = ws.status
+		summary.WorkspaceError = ws.error
+	}
+
+	// Get completion status (ignore errors gracefully)
+	if status, err := s.h.Completion().CheckGoal(g.ID); err == nil {
+		summary.CompletionStatus = status
+	}
+	summary.Review, _ = s.h.GetReview(g.ID)
+	return summary
+}
+
+// summarizeGoal returns one goal's summary as the goal list has it (without
+// its board position), for goal_updated events
+func summarizeGoal(h *hub.Hub, p *goals.Parser, goalID string) (*GoalSummary, error) {
+	registryGoals, err := p.ParseRegistry()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range registryGoals {
+		if g.ID == goalID {
+			summary := newGoalSummarizer(h, p, []goals.Goal{g}).summarize(g)
+			return &summary, nil
+		}
+	}
+	return nil, fmt.Errorf("goal %s not in registry", goalID)
+}
 
-			// Get completion status (ignore errors gracefully)
-			if status, err := goals.IsGoalComplete(g.ID, p.Dir()); err == nil {
-				summary.CompletionStatus = status
+// filterGoalsByProject returns the goals that belong to project
+func filterGoalsByProject(list []goals.Goal, project string) []goals.Goal {
+	var result []goals.Goal
+	for _, g := range list {
+		for _, name := range g.Projects {
+			if name == project {
+				result = append(result, g)
+				break
 			}
+		}
+	}
+	return result
+}
 
-			summaries = append(summaries, summary)
+// filterGoalsByTag returns the goals tagged with tag
+func filterGoalsByTag(list []goals.Goal, tag string) []goals.Goal {
+	var result []goals.Goal
+	for _, g := range list {
+		if g.HasTag(tag) {
+			result = append(result, g)
 		}
+	}
+	return result
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(summaries)
+// applyBoardOrder reorders summaries within each status to follow the
+// project's kanban board and sets their positions. Goals keep the slots their
+// status occupies, so statuses stay where the registry puts them.
+func applyBoardOrder(vegaDir, project string, summaries []GoalSummary) error {
+	columns, err := goals.NewBoard(vegaDir).Columns(project)
+	if err != nil {
+		return err
+	}
+
+	slots := make(map[string][]int) // status -> indexes into summaries
+	var statuses []string
+	for i, s := range summaries {
+		if _, ok := slots[s.Status]; !ok {
+			statuses = append(statuses, s.Status)
+		}
+		slots[s.Status] = append(slots[s.Status], i)
 	}
+
+	for _, status := range statuses {
+		idx := slots[status]
+		byID := make(map[string]GoalSummary, len(idx))
+		members := make([]string, len(idx))
+		for j, i := range idx {
+			members[j] = summaries[i].ID
+			byID[summaries[i].ID] = summaries[i]
+		}
+		for j, id := range goals.SortColumn(columns[status], members) {
+			summary := byID[id]
+			summary.Position = j
+			summaries[idx[j]] = summary
+		}
+	}
+	return nil
 }
 
 // BranchInfo contains git branch information for a goal's worktree
@@ -527,20 +1693,24 @@ type GoalDetailResponse struct {
 	WorkspaceStatus  string          `json:"workspace_status,omitempty"` // "ready", "missing", "error"
 	WorkspaceError   string          `json:"workspace_error,omitempty"`  // Error message if not ready
 	BranchInfo       *BranchInfo     `json:"branch_info,omitempty"`      // Git branch info for worktree
-	WorktreeStatus   string          `json:"worktree_status,omitempty"`  // "exists", "missing", "never_created"
+	WorktreeStatus   string          `json:"worktree_status,omitempty"`  // "exists", "missing", "removed" (completed goals), "never_created"
 	BranchStatus     string          `json:"branch_status,omitempty"`    // "local", "remote_only", "missing"
 	CanRecreate      bool            `json:"can_recreate,omitempty"`     // true if branch exists somewhere
 	// State machine fields
-	State        string     `json:"state,omitempty"`         // Current state from StateManager
-	StateSince   *time.Time `json:"state_since,omitempty"`   // Timestamp of last state change
-	StateHistory []goals.StateEvent `json:"state_history,omitempty"` // Full history (if requested via ?history=true)
+	State        string               `json:"state,omitempty"`         // Current state from StateManager
+	StateSince   *time.Time           `json:"state_since,omitempty"`   // Timestamp of last state change
+	StateHistory []goals.StateEvent   `json:"state_history,omitempty"` // Full history (if requested via ?history=true)
+	Review       *goals.Review        `json:"review,omitempty"`        // Latest reviewer decision
+	CI           *operations.CIStatus `json:"ci,omitempty"`            // Goal branch CI at the last check
 	// Completion status from task_plan.md
 	CompletionStatus *goals.CompletionStatus `json:"completion_status,omitempty"`
 	// Hierarchy fields
-	ParentID  string   `json:"parent_id,omitempty"`
-	Children  []string `json:"children,omitempty"`
-	Depth     int      `json:"depth"`
-	IsBlocked bool     `json:"is_blocked,omitempty"`
+	ParentID       string                `json:"parent_id,omitempty"`
+	Children       []string              `json:"children,omitempty"`
+	ChildrenStatus *goals.ChildrenStatus `json:"children_status,omitempty"` // Aggregated progress of the children
+	Depth          int                   `json:"depth"`
+	IsBlocked      bool                  `json:"is_blocked,omitempty"`
+	Watchers       []string              `json:"watchers,omitempty"` // Users online with the goal open
 }
 
 // GoalStateResponse is the response for GET /api/goals/:id/state
@@ -552,6 +1722,26 @@ type GoalStateResponse struct {
 	StateHistory []goals.StateEvent  `json:"state_history,omitempty"` // Only if ?history=true
 }
 
+// GoalStateTransitionRequest is the request body for POST /api/goals/:id/state
+type GoalStateTransitionRequest struct {
+	State   goals.GoalState   `json:"state"`
+	Reason  string            `json:"reason,omitempty"`
+	Force   bool              `json:"force,omitempty"` // Skip transition validation and veto hooks
+	User    string            `json:"user,omitempty"`  // Fallback when X-Vega-User is not set
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// StateTransitionErrorResponse is returned with 409 when a state change is rejected
+type StateTransitionErrorResponse struct {
+	Error   string            `json:"error"`
+	GoalID  string            `json:"goal_id"`
+	From    goals.GoalState   `json:"from"`
+	To      goals.GoalState   `json:"to"`
+	Allowed []goals.GoalState `json:"allowed,omitempty"` // States reachable from From
+	HookID  string            `json:"hook_id,omitempty"` // Set when a hook vetoed the change
+	Reason  string            `json:"reason,omitempty"`  // Veto reason
+}
+
 // SpawnRequest is the request body for POST /api/goals/:id/spawn
 type SpawnRequest struct {
 	Context string `json:"context,omitempty"`
@@ -559,6 +1749,11 @@ type SpawnRequest struct {
 	Mode    string `json:"mode,omitempty"`    // Executor mode: plan, implement, review, test, security, quick
 	Meta    bool   `json:"meta,omitempty"`    // If true, spawn as meta-executor in goal folder
 	Project string `json:"project,omitempty"` // Project name for project executor (mutually exclusive with meta)
+	Worker  string `json:"worker,omitempty"`  // Remote worker to run the executor on
+	Resume  string `json:"resume,omitempty"`  // Session to continue (claude --resume): a session ID or "last"
+
+	// Run the executor in a container (image/limits default to the project's settings)
+	Container *hub.ContainerOptions `json:"container,omitempty"`
 }
 
 // CreateMRRequest is the request body for POST /api/goals/:id/create-mr
@@ -567,6 +1762,7 @@ type CreateMRRequest struct {
 	Description  string `json:"description,omitempty"`
 	TargetBranch string `json:"target_branch,omitempty"` // Defaults to base branch
 	Draft        bool   `json:"draft,omitempty"`
+	Async        bool   `json:"async,omitempty"` // Respond 202 with the job instead of waiting
 }
 
 // CreateMRResponse is the response for POST /api/goals/:id/create-mr
@@ -591,16 +1787,32 @@ func handleGoalRoutes(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 		}
 
 		id := parts[0] // Goal ID can be numeric ("10") or hash ("4fd584d")
+		if !validIDs(w, "goal ID", id) {
+			return
+		}
 
 		// Handle special routes that aren't goal IDs
 		if id == "ready" {
 			handleReadyGoals(p)(w, r)
 			return
 		}
+		if id == "preflight" && len(parts) == 1 {
+			handlePreflight(h)(w, r)
+			return
+		}
+		if id == "backlog" && len(parts) == 1 {
+			handleBacklog(h)(w, r)
+			return
+		}
+		id = p.ResolveGoalID(id) // Aliases work wherever the ID does
 
 		// Route to appropriate handler
 		if len(parts) == 1 {
-			// GET /api/goals/:id
+			// GET /api/goals/:id, PATCH to edit the goal's fields
+			if r.Method == http.MethodPatch {
+				handleGoalUpdate(h, id)(w, r)
+				return
+			}
 			handleGoalDetail(h, p, id)(w, r)
 			return
 		}
@@ -619,38 +1831,126 @@ func handleGoalRoutes(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 		case "output":
 			handleGoalOutput(h, id)(w, r)
 		case "complete":
-			handleGoalComplete(h, id)(w, r)
+			goalOperation(h, id, "complete", handleGoalComplete(h, id))(w, r)
+		case "clone":
+			handleGoalClone(h, id)(w, r)
+		case "split":
+			goalOperation(h, id, "split", handleGoalSplit(h, id))(w, r)
 		case "ice":
-			handleGoalIce(h, id)(w, r)
+			goalOperation(h, id, "ice", handleGoalIce(h, id))(w, r)
 		case "cleanup":
-			handleGoalCleanup(h, id)(w, r)
+			goalOperation(h, id, "cleanup", handleGoalCleanup(h, id))(w, r)
 		case "resume":
-			handleGoalResume(h, id)(w, r)
+			goalOperation(h, id, "resume", handleGoalResume(h, id))(w, r)
 		case "sessions":
-			handleGoalSessions(h, id)(w, r)
+			// Handle nested paths like "sessions/:sid/transcript"
+			if len(actionParts) > 1 {
+				sessionID, sub, _ := strings.Cut(actionParts[1], "/")
+				if !validIDs(w, "session ID", sessionID) {
+					return
+				}
+				if sub != "transcript" {
+					http.Error(w, "Not found", http.StatusNotFound)
+					return
+				}
+				handleSessionTranscript(h, id, sessionID)(w, r)
+			} else {
+				handleGoalSessions(h, id)(w, r)
+			}
 		case "history":
 			handleGoalHistoryEntries(h, id)(w, r)
 		case "chat":
 			handleGoalChat(h, id)(w, r)
+		case "comments":
+			handleGoalComments(h, id)(w, r)
+		case "review":
+			goalOperation(h, id, "review", handleGoalReview(h, p, id))(w, r)
+		case "notes":
+			sub := ""
+			if len(actionParts) > 1 {
+				sub = actionParts[1]
+			}
+			handleGoalNotes(h, id, sub)(w, r)
+		case "focus":
+			itemID, action := "", ""
+			if len(actionParts) > 1 {
+				itemID, action, _ = strings.Cut(actionParts[1], "/")
+			}
+			handleGoalFocus(h, id, itemID, action)(w, r)
+		case "badge.svg":
+			handleGoalBadge(h, id)(w, r)
+		case "shares":
+			linkID, action := "", ""
+			if len(actionParts) > 1 {
+				linkID, action, _ = strings.Cut(actionParts[1], "/")
+			}
+			handleGoalShares(h, id, linkID, action)(w, r)
+		case "attachments":
+			attachmentID := ""
+			if len(actionParts) > 1 {
+				attachmentID = actionParts[1]
+			}
+			handleGoalAttachments(h, id, attachmentID)(w, r)
+		case "activity":
+			handleGoalActivity(h, id)(w, r)
+		case "timeline":
+			handleGoalTimeline(h, id)(w, r)
+		case "context":
+			handleGoalContext(h, id)(w, r)
+		case "changed-files":
+			handleGoalChanges(h, id, false)(w, r)
+		case "diff":
+			handleGoalChanges(h, id, true)(w, r)
+		case "patch":
+			handleGoalPatch(h, id)(w, r)
+		case "apply-patch":
+			goalOperation(h, id, "apply-patch", handleGoalApplyPatch(h, id))(w, r)
+		case "message-preview":
+			handleMessagePreview(h, id)(w, r)
+		case "activate":
+			handleGoalActivate(h, id)(w, r)
+		case "rename":
+			goalOperation(h, id, "rename", handleGoalRename(h, id))(w, r)
+		case "retarget":
+			goalOperation(h, id, "retarget", handleGoalRetarget(h, id))(w, r)
+		case "move-project":
+			goalOperation(h, id, "move-project", handleGoalMoveProject(h, id))(w, r)
+		case "executors":
+			// Handle nested paths like "executors/:sid/kill"
+			if len(actionParts) < 2 {
+				http.Error(w, "Not found", http.StatusNotFound)
+				return
+			}
+			sessionID, control, _ := strings.Cut(actionParts[1], "/")
+			if !validIDs(w, "session ID", sessionID) {
+				return
+			}
+			handleExecutorControl(h, id, sessionID, control)(w, r)
 		case "messages":
-			// Check for nested path like "messages/pending"
+			// Check for nested paths like "messages/pending" and "messages/ack"
 			if len(actionParts) > 1 && actionParts[1] == "pending" {
-				handleGetPendingMessages(h, id)(w, r)
+				executorAuth(h, pathScope(id), handleGetPendingMessages(h, id))(w, r)
+			} else if len(actionParts) > 1 && actionParts[1] == "ack" {
+				executorAuth(h, pathScope(id), handleAckMessages(h, id))(w, r)
 			} else {
 				handleGoalMessages(h, id)(w, r)
 			}
 		case "create-mr":
 			handleCreateMR(h, p, id)(w, r)
 		case "recreate-worktree":
-			handleRecreateWorktree(h, p, id)(w, r)
+			goalOperation(h, id, "recreate-worktree", handleRecreateWorktree(h, p, id))(w, r)
 		case "create-worktree":
-			handleCreateWorktree(h, p, id)(w, r)
+			goalOperation(h, id, "create-worktree", handleCreateWorktree(h, p, id))(w, r)
 		case "delete":
-			handleDeleteGoal(h, p, id)(w, r)
+			goalOperation(h, id, "delete", handleDeleteGoal(h, p, id))(w, r)
 		case "state":
-			handleGoalState(h, id)(w, r)
+			handleGoalState(h, p, id)(w, r)
 		case "completion-status":
 			handleGoalCompletionStatus(h, p, id)(w, r)
+		case "commit-policy":
+			handleGoalCommitPolicy(h, id)(w, r)
+		case "ci":
+			handleGoalCI(h, id)(w, r)
 		case "dependencies":
 			// Handle nested paths like "dependencies/:dep_id"
 			if len(actionParts) > 1 {
@@ -659,22 +1959,126 @@ func handleGoalRoutes(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 			} else {
 				handleGoalDependencies(p, id)(w, r)
 			}
-		case "children":
-			handleGoalChildren(p, id)(w, r)
-		case "hierarchy":
-			handleGoalHierarchy(p, id)(w, r)
-		case "planning-files":
-			// Handle nested paths like "planning-files/:project/:filename"
-			if len(actionParts) > 1 {
-				handleGoalPlanningFileAction(h, p, id, actionParts[1])(w, r)
-			} else {
-				handleGoalPlanningFiles(p, id)(w, r)
+		case "children":
+			handleGoalChildren(p, id)(w, r)
+		case "hierarchy":
+			handleGoalHierarchy(p, id)(w, r)
+		case "planning-files":
+			// Handle nested paths like "planning-files/:project/:filename"
+			if len(actionParts) > 1 {
+				handleGoalPlanningFileAction(h, p, id, actionParts[1])(w, r)
+			} else {
+				handleGoalPlanningFiles(p, id)(w, r)
+			}
+		case "phases":
+			handleGoalPhases(h, p, id)(w, r)
+		case "position":
+			handleGoalPosition(h, p, id)(w, r)
+		default:
+			http.Error(w, "Unknown action: "+action, http.StatusNotFound)
+		}
+	}
+}
+
+// GoalUpdateRequest is the request body for PATCH /api/goals/:id. Omitted
+// fields are left unchanged; "" clears one.
+type GoalUpdateRequest struct {
+	IssueURL  *string   `json:"issue_url"`
+	TrackerID *string   `json:"tracker_id"` // Derived from a new issue_url when omitted
+	Priority  *int      `json:"priority"`   // Drafts only
+	Tags      *[]string `json:"tags"`       // Replaces the goal's tags
+	Alias     *string   `json:"alias"`      // Sets the goal's alias; "" removes it
+}
+
+// GoalUpdateResponse is the response for PATCH /api/goals/:id: the updated
+// goal, with the issue link and priority always present as before
+type GoalUpdateResponse struct {
+	goals.Goal
+	IssueURL  string `json:"issue_url"`
+	TrackerID string `json:"tracker_id"`
+	Priority  int    `json:"priority"`
+}
+
+// handleGoalUpdate handles PATCH /api/goals/:id - links the goal to an
+// external issue, moves a draft in the backlog, retags the goal or sets its
+// alias
+func handleGoalUpdate(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req GoalUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		registry := goals.NewRegistry(h.Dir())
+		var link goals.IssueLink
+		if entry, err := registry.Get(goalID); err == nil {
+			link = goals.IssueLink{URL: entry.IssueURL, TrackerID: entry.TrackerID}
+		}
+		if req.IssueURL != nil {
+			link.URL = *req.IssueURL
+			if req.TrackerID == nil {
+				link.TrackerID = ""
+			}
+		}
+		if req.TrackerID != nil {
+			link.TrackerID = *req.TrackerID
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		writeError := func(result *operations.Result) {
+			switch result.Error.Code {
+			case "goal_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "not_draft", "alias_taken":
+				w.WriteHeader(http.StatusConflict)
+			case "invalid_issue", "invalid_input":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(result)
+		}
+		if req.Priority != nil {
+			if result := operations.SetGoalPriority(h.Dir(), goalID, *req.Priority); !result.Success {
+				writeError(result)
+				return
+			}
+		}
+		if req.Tags != nil {
+			if result, _ := operations.SetGoalTags(h.Dir(), goalID, *req.Tags); !result.Success {
+				writeError(result)
+				return
 			}
-		case "phases":
-			handleGoalPhases(h, p, id)(w, r)
-		default:
-			http.Error(w, "Unknown action: "+action, http.StatusNotFound)
 		}
+		if req.Alias != nil {
+			if result, _ := operations.SetGoalAlias(h.Dir(), goalID, *req.Alias); !result.Success {
+				writeError(result)
+				return
+			}
+		}
+		if (req.Priority == nil && req.Tags == nil && req.Alias == nil) || req.IssueURL != nil || req.TrackerID != nil {
+			result, data := operations.SetGoalIssue(h.Dir(), goalID, link)
+			if !result.Success {
+				writeError(result)
+				return
+			}
+			link = *data
+		}
+
+		response := GoalUpdateResponse{IssueURL: link.URL, TrackerID: link.TrackerID}
+		if entry, err := registry.Get(goalID); err == nil {
+			response.Goal = *entry
+			response.Priority = entry.Priority
+		}
+		h.EmitGoalUpdated(goalID, "updated", map[string]interface{}{
+			"issue_url":  response.IssueURL,
+			"tracker_id": response.TrackerID,
+			"priority":   response.Priority,
+			"tags":       response.Tags,
+			"alias":      response.Alias,
+		})
+		json.NewEncoder(w).Encode(response)
 	}
 }
 
@@ -727,6 +2131,8 @@ func handleGoalDetail(h *hub.Hub, p *goals.Parser, id string) http.HandlerFunc {
 			ExecutorStatus:   status,
 			PendingQuestions: goalQuestions,
 			ActiveExecutors:  goalExecutors,
+			CI:               operations.GetCIStatus(h.Dir(), id),
+			Watchers:         h.Watchers(id),
 		}
 
 		// Get workspace status from first project
@@ -740,9 +2146,10 @@ func handleGoalDetail(h *hub.Hub, p *goals.Parser, id string) http.HandlerFunc {
 			}
 		}
 
-		// Get branch info for active goals with worktrees
-		if detail.Status == "active" && len(detail.Projects) > 0 {
-			response.BranchInfo = getBranchInfo(p.Dir(), id, detail.Projects)
+		// Get branch info: live for worktrees on disk, from the goal's worktree
+		// metadata for iced and completed goals whose worktree was removed
+		if len(detail.Projects) > 0 {
+			response.BranchInfo = getBranchInfo(h.Git(), p.Dir(), id, detail.Projects)
 		}
 
 		// Compute worktree status and branch status
@@ -753,14 +2160,19 @@ func handleGoalDetail(h *hub.Hub, p *goals.Parser, id string) http.HandlerFunc {
 				// Worktree directory exists
 				response.WorktreeStatus = "exists"
 			} else {
-				// Worktree metadata exists but directory is missing
+				// Worktree metadata exists but directory is missing (completed
+				// goals have theirs removed on purpose)
 				response.WorktreeStatus = "missing"
+				if detail.Status == "completed" {
+					response.WorktreeStatus = "removed"
+				}
 
 				// Check if branch exists (local or remote)
 				if len(detail.Projects) > 0 {
-					projectBase := filepath.Join(p.Dir(), "workspaces", detail.Projects[0], "worktree-base")
-					response.BranchStatus = checkBranchExists(projectBase, detail.Worktree.Branch)
-					response.CanRecreate = response.BranchStatus == "local" || response.BranchStatus == "remote_only"
+					projectBase := p.Layout().WorktreeBase(detail.Projects[0])
+					response.BranchStatus = h.Git().BranchExists(projectBase, detail.Worktree.Branch)
+					response.CanRecreate = detail.Status != "completed" &&
+						(response.BranchStatus == "local" || response.BranchStatus == "remote_only")
 				}
 			}
 		} else {
@@ -773,10 +2185,16 @@ func handleGoalDetail(h *hub.Hub, p *goals.Parser, id string) http.HandlerFunc {
 		if sm != nil {
 			if state, err := sm.GetState(id); err == nil {
 				response.State = string(state)
+				// Goals completed without a state file (or before it recorded
+				// completion) would otherwise read as still working
+				if detail.Status == "completed" && !state.IsTerminal() {
+					response.State = string(goals.StateDone)
+				}
 			}
 			if lastEvent, err := sm.GetLastEvent(id); err == nil && lastEvent != nil {
 				response.StateSince = &lastEvent.Timestamp
 			}
+			response.Review, _ = sm.LatestReview(id)
 			// Include full history if requested
 			if r.URL.Query().Get("history") == "true" {
 				if history, err := sm.GetHistory(id); err == nil {
@@ -795,6 +2213,7 @@ func handleGoalDetail(h *hub.Hub, p *goals.Parser, id string) http.HandlerFunc {
 		dm := goals.NewDependencyManager(p.Dir())
 		response.ParentID, _ = hm.GetParentID(id)
 		response.Children, _ = hm.GetChildren(id)
+		response.ChildrenStatus, _ = hm.ChildrenStatus(id)
 		response.Depth = hm.GetHierarchyDepth(id)
 		response.IsBlocked = dm.IsBlocked(id)
 
@@ -803,10 +2222,11 @@ func handleGoalDetail(h *hub.Hub, p *goals.Parser, id string) http.HandlerFunc {
 	}
 }
 
-// handleGoalState handles GET /api/goals/:id/state - returns goal state info
-func handleGoalState(h *hub.Hub, goalID string) http.HandlerFunc {
+// handleGoalState handles GET /api/goals/:id/state - returns goal state info.
+// POST changes the state (body: state, reason, force) and returns the updated info.
+func handleGoalState(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
@@ -817,6 +2237,12 @@ func handleGoalState(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
+		if r.Method == http.MethodPost {
+			if !transitionGoalState(w, r, h, p, sm, goalID) {
+				return
+			}
+		}
+
 		// Get current state
 		state, err := sm.GetState(goalID)
 		if err != nil {
@@ -847,6 +2273,74 @@ func handleGoalState(h *hub.Hub, goalID string) http.HandlerFunc {
 	}
 }
 
+// transitionGoalState applies a POST /api/goals/:id/state request. It writes the
+// error response and returns false if the transition was rejected.
+func transitionGoalState(w http.ResponseWriter, r *http.Request, h *hub.Hub, p *goals.Parser, sm *goals.StateManager, goalID string) bool {
+	var req GoalStateTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return false
+	}
+	if !req.State.IsValid() {
+		http.Error(w, fmt.Sprintf("Invalid state: %q", req.State), http.StatusBadRequest)
+		return false
+	}
+	if _, err := p.ParseGoalDetail(goalID); err != nil {
+		http.Error(w, "Goal not found", http.StatusNotFound)
+		return false
+	}
+
+	user := actingUser(r, req.User)
+
+	from, _ := sm.GetState(goalID)
+
+	var err error
+	if req.Force {
+		err = sm.ForceStateWithUser(goalID, req.State, req.Reason, user)
+	} else {
+		err = sm.TransitionWithUser(goalID, req.State, req.Reason, user, req.Details)
+	}
+	if err != nil {
+		var invalid *goals.InvalidTransitionError
+		var vetoed *goals.TransitionVetoedError
+		resp := StateTransitionErrorResponse{
+			Error:  err.Error(),
+			GoalID: goalID,
+			From:   from,
+			To:     req.State,
+		}
+		switch {
+		case errors.As(err, &invalid):
+			resp.From = invalid.From
+			resp.Allowed = goals.AllowedTransitions(invalid.From)
+		case errors.As(err, &vetoed):
+			resp.HookID = vetoed.HookID
+			resp.Reason = vetoed.Reason
+		case errors.Is(err, goals.ErrInvalidInitialState):
+			resp.Allowed = []goals.GoalState{goals.StatePending, goals.StateWorking}
+		default:
+			http.Error(w, "Failed to change state: "+err.Error(), http.StatusInternalServerError)
+			return false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(resp)
+		return false
+	}
+
+	log.Printf("[STATE] Goal %s: %s -> %s (user %q, force %v)", goalID, from, req.State, user, req.Force)
+
+	h.EmitEvent("goal_state_changed", map[string]interface{}{
+		"goal_id": goalID,
+		"from":    from,
+		"to":      req.State,
+		"reason":  req.Reason,
+		"user":    user,
+		"forced":  req.Force,
+	})
+	return true
+}
+
 // GoalCompletionStatusResponse is the response for GET /api/goals/:id/completion-status
 type GoalCompletionStatusResponse struct {
 	GoalID string                  `json:"goal_id"`
@@ -889,6 +2383,102 @@ func handleGoalCompletionStatus(h *hub.Hub, p *goals.Parser, goalID string) http
 	}
 }
 
+// PreflightRequest is the request body for POST /api/goals/preflight
+type PreflightRequest struct {
+	Project    string   `json:"project"`
+	GoalID     string   `json:"goal_id,omitempty"`     // Check against this goal's base branch
+	BaseBranch string   `json:"base_branch,omitempty"` // Defaults to the goal's, then the project's base branch
+	Branch     string   `json:"branch,omitempty"`      // Goal branch to check for availability
+	Checks     []string `json:"checks,omitempty"`      // Defaults to every check
+}
+
+// handlePreflight handles POST /api/goals/preflight - runs the pre-flight
+// checks for creating a goal in a project and returns the results with fix
+// commands. Failing checks are reported with status 200; ready is false.
+func handlePreflight(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req PreflightRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if req.Project == "" {
+			http.Error(w, "Project is required", http.StatusBadRequest)
+			return
+		}
+		if !validIDs(w, "goal ID", req.GoalID) {
+			return
+		}
+		checks := hub.CreateChecks
+		if len(req.Checks) > 0 {
+			for _, c := range req.Checks {
+				if !hub.IsPreflightCheck(c) {
+					http.Error(w, fmt.Sprintf("Unknown check: %s", c), http.StatusBadRequest)
+					return
+				}
+			}
+			checks = req.Checks
+		}
+
+		project, err := goals.ParseProject(h.Dir(), req.Project)
+		if err != nil {
+			http.Error(w, "Project not found: "+req.Project, http.StatusNotFound)
+			return
+		}
+		worktreeBase := h.Layout().WorktreeBase(req.Project)
+		if _, err := os.Stat(worktreeBase); err != nil {
+			http.Error(w, fmt.Sprintf("worktree-base not found for project %s", req.Project), http.StatusNotFound)
+			return
+		}
+		baseBranch := req.BaseBranch
+		if baseBranch == "" && req.GoalID != "" {
+			baseBranch, _ = operations.GoalBaseBranch(h.Dir(), req.GoalID, req.Project)
+		}
+		if baseBranch == "" {
+			baseBranch = project.BaseBranch
+		}
+		if baseBranch == "" {
+			baseBranch = "main"
+		}
+
+		result := hub.NewPreflightChecker(worktreeBase, baseBranch, req.Branch).RunChecks(checks)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// handleGoalCommitPolicy handles GET /api/goals/:id/commit-policy?project=<name> -
+// checks the goal branch against the project's commit policy before completing
+func handleGoalCommitPolicy(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		project := r.URL.Query().Get("project")
+		if project == "" {
+			http.Error(w, "project is required", http.StatusBadRequest)
+			return
+		}
+
+		report, err := operations.CheckCommitPolicy(h.Dir(), project, goalID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
 // handleGoalSpawn handles POST /api/goals/:id/spawn - spawns an executor
 func handleGoalSpawn(h *hub.Hub, goalID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -914,13 +2504,15 @@ func handleGoalSpawn(h *hub.Hub, goalID string) http.HandlerFunc {
 		}
 
 		// Get user from X-Vega-User header, or from request body, or auto-detect
-		user := r.Header.Get("X-Vega-User")
-		if user == "" {
-			user = req.User
-		}
+		user := actingUser(r, req.User)
 
-		// Validate mode if specified
+		// Validate mode if specified, else use the user's preferred mode
 		mode := req.Mode
+		if mode == "" && user != "" {
+			if prefs, err := h.Preferences().Get(user); err == nil {
+				mode = prefs.DefaultExecutorMode
+			}
+		}
 		if mode != "" && !hub.ValidModes[mode] {
 			http.Error(w, fmt.Sprintf("Invalid mode: %s. Valid modes: plan, implement, review, test, security, quick", mode), http.StatusBadRequest)
 			return
@@ -931,8 +2523,8 @@ func handleGoalSpawn(h *hub.Hub, goalID string) http.HandlerFunc {
 			executorType = "meta"
 		}
 
-		log.Printf("[SPAWN] Processing spawn for Goal #%s, type: %s, context: %q, user: %q, mode: %q, project: %q",
-			goalID, executorType, req.Context, user, mode, req.Project)
+		log.Printf("[SPAWN] Processing spawn for Goal #%s, type: %s, context: %q, user: %q, mode: %q, project: %q, worker: %q, resume: %q",
+			goalID, executorType, req.Context, user, mode, req.Project, req.Worker, req.Resume)
 
 		result := h.SpawnExecutor(hub.SpawnRequest{
 			GoalID:  goalID,
@@ -941,6 +2533,9 @@ func handleGoalSpawn(h *hub.Hub, goalID string) http.HandlerFunc {
 			Mode:    mode,
 			Meta:    req.Meta,
 			Project: req.Project,
+			Worker:    req.Worker,
+			Resume:    req.Resume,
+			Container: req.Container,
 		})
 
 		log.Printf("[SPAWN] Result for Goal #%s: success=%v, message=%s", goalID, result.Success, result.Message)
@@ -1034,29 +2629,159 @@ func handleCreateGoal(h *hub.Hub) http.HandlerFunc {
 
 		log.Printf("[CREATE] Creating goal: title=%q, project=%q, base_branch=%q, parent_id=%q", req.Title, req.Project, req.BaseBranch, req.ParentID)
 
+		req.ParentID = goals.ResolveGoalID(h.Dir(), req.ParentID)
+
+		// Look for duplicates before the new goal is registered
+		similar, err := h.FindSimilarGoals(req.Title, hub.DefaultSimilarGoalLimit, req.ParentID)
+		if err != nil {
+			log.Printf("[CREATE] Similar goal check failed: %v", err)
+		}
+
 		result, data := operations.CreateGoal(operations.CreateOptions{
 			Title:      req.Title,
 			Project:    req.Project,
 			BaseBranch: req.BaseBranch,
 			ParentID:   req.ParentID,
+			Issue:      goals.IssueLink{URL: req.IssueURL, TrackerID: req.TrackerID},
+			NoWorktree: !req.Wait,
+			Draft:      req.Draft,
+			Priority:   req.Priority,
+			CreatedBy:  requestUser(r),
+			Tags:       req.Tags,
+			Alias:      req.Alias,
+			Preflight:  !req.SkipPreflight,
 			VegaDir:    h.Dir(),
 		})
 
 		w.Header().Set("Content-Type", "application/json")
 		if !result.Success {
-			w.WriteHeader(http.StatusBadRequest)
+			if result.Error.Code == "alias_taken" {
+				w.WriteHeader(http.StatusConflict)
+			} else {
+				w.WriteHeader(http.StatusBadRequest)
+			}
 			json.NewEncoder(w).Encode(result)
 			return
 		}
 
-		log.Printf("[CREATE] Goal created: id=%s, branch=%s", data.GoalID, data.GoalBranch)
+		log.Printf("[CREATE] Goal created: id=%s, branch=%s, draft=%v", data.GoalID, data.GoalBranch, data.Draft)
 
 		// Emit SSE event for goal created
 		h.EmitEvent("goal_created", map[string]interface{}{
 			"goal_id": data.GoalID,
 			"title":   data.Title,
 			"project": data.Project,
+			"draft":   data.Draft,
+		})
+
+		// Drafts have nothing to provision until they're activated
+		if req.Wait || data.Draft {
+			json.NewEncoder(w).Encode(CreateGoalResponse{Success: true, Data: data, SimilarGoals: similar})
+			return
+		}
+
+		job := provisionGoalWorktree(h, data, operations.ProvisionOptions{Preflight: !req.SkipPreflight, User: requestUser(r)})
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(CreateGoalResponse{Success: true, Data: data, Job: job, SimilarGoals: similar})
+	}
+}
+
+// provisionGoalWorktree runs operations.ProvisionGoalWorktree for a new or
+// activated goal in a background job. The goal's operation lock is held
+// until the worktree is ready, so it can't be completed or deleted halfway.
+func provisionGoalWorktree(h *hub.Hub, data *operations.CreateResult, opts operations.ProvisionOptions) *jobs.Job {
+	user := opts.User
+	release, err := h.BeginGoalOperation(data.GoalID, "provision", user)
+	if err != nil {
+		// A brand-new ID can't be busy; carry on unguarded rather than fail
+		log.Printf("[CREATE] Goal %s: %v", data.GoalID, err)
+		release = func() {}
+	}
+
+	spec := jobs.Spec{Type: "provision_worktree", GoalID: data.GoalID, User: user}
+	return h.Jobs().Start(spec, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+		defer release()
+		opts.Context, opts.Progress = ctx, progress
+		if errResult := operations.ProvisionGoalWorktree(h.Dir(), data, opts); errResult != nil {
+			return errResult, resultError(errResult)
+		}
+
+		log.Printf("[CREATE] Goal %s worktree ready at %s (from_pool=%v)", data.GoalID, data.WorktreePath, data.FromPool)
+		h.EmitEvent("goal_provisioned", map[string]interface{}{
+			"goal_id":       data.GoalID,
+			"project":       data.Project,
+			"worktree_path": data.WorktreePath,
+			"from_pool":     data.FromPool,
 		})
+		return data, nil
+	})
+}
+
+// handleGoalClone handles POST /api/goals/:id/clone - creates a new goal from an existing one
+func handleGoalClone(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req CloneGoalRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+
+		log.Printf("[CLONE] Cloning goal %s: title=%q, project=%q, from_branch=%v", goalID, req.Title, req.Project, req.FromBranch)
+
+		var result *operations.Result
+		var data *operations.CreateResult
+		spec := jobs.Spec{Type: "clone_goal", GoalID: goalID, User: requestUser(r)}
+		if !runAsJob(w, r, h, spec, req.Async, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			progress("creating goal and worktree")
+			result, data = operations.CloneGoal(operations.CloneGoalOptions{
+				SourceID:   goalID,
+				Title:      req.Title,
+				Project:    req.Project,
+				BaseBranch: req.BaseBranch,
+				FromBranch: req.FromBranch,
+				NoWorktree: req.NoWorktree,
+				VegaDir:    h.Dir(),
+			})
+			if !result.Success {
+				return result, resultError(result)
+			}
+
+			log.Printf("[CLONE] Goal %s cloned as %s (branch %s)", goalID, data.GoalID, data.GoalBranch)
+
+			h.EmitEvent("goal_created", map[string]interface{}{
+				"goal_id":     data.GoalID,
+				"title":       data.Title,
+				"project":     data.Project,
+				"cloned_from": goalID,
+			})
+			return data, nil
+		}) {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if result == nil {
+			result = canceledResult()
+		}
+		if !result.Success {
+			status := http.StatusBadRequest
+			if result.Error.Code == "goal_not_found" {
+				status = http.StatusNotFound
+			}
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(result)
+			return
+		}
 
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": true,
@@ -1086,33 +2811,61 @@ func handleGoalComplete(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
-		log.Printf("[COMPLETE] Completing goal %s in project %s (no_merge=%v, force=%v)", goalID, req.Project, req.NoMerge, req.Force)
+		user := actingUser(r, req.User)
+		log.Printf("[COMPLETE] Completing goal %s in project %s for %s (no_merge=%v, force=%v)", goalID, req.Project, user, req.NoMerge, req.Force)
 
-		result, data := operations.CompleteGoal(operations.CompleteOptions{
-			GoalID:  goalID,
-			Project: req.Project,
-			NoMerge: req.NoMerge,
-			Force:   req.Force,
-			VegaDir: h.Dir(),
-		})
+		var result *operations.Result
+		var data *operations.CompleteResult
+		spec := jobs.Spec{Type: "complete_goal", GoalID: goalID, User: user}
+		if !runAsJob(w, r, h, spec, req.Async, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			result, data = operations.CompleteGoal(operations.CompleteOptions{
+				GoalID:        goalID,
+				Project:       req.Project,
+				NoMerge:       req.NoMerge,
+				Force:         req.Force,
+				MergeStrategy: req.MergeStrategy,
+				Commits:       req.Commits,
+				CommentIssue:  req.CommentIssue,
+				User:          user,
+				VegaDir:       h.Dir(),
+				Progress:      progress,
+			})
+			if !result.Success {
+				return result, resultError(result)
+			}
+
+			log.Printf("[COMPLETE] Goal %s completed successfully", goalID)
+
+			// Record in the goal's history and emit SSE event for goal completed
+			h.RecordGoalAction(goalID, "goal_completed", user, map[string]interface{}{
+				"title":   data.Title,
+				"project": data.Project,
+				"merged":  data.Merged,
+			})
+			return data, nil
+		}) {
+			return
+		}
 
 		w.Header().Set("Content-Type", "application/json")
+		if result == nil {
+			result = canceledResult()
+		}
 		if !result.Success {
-			w.WriteHeader(http.StatusBadRequest)
+			if result.Error != nil && (result.Error.Code == "mr_required" || result.Error.Code == "canceled" || result.Error.Code == "commit_policy_violation" || result.Error.Code == "preflight_failed" || result.Error.Code == "changes_requested" || result.Error.Code == "review_required" || result.Error.Code == "children_active" || result.Error.Code == "base_diverged" || result.Error.Code == "cherry_pick_conflict") {
+				w.WriteHeader(http.StatusConflict)
+			} else if result.Error != nil && result.Error.Code == "base_fetch_failed" {
+				w.WriteHeader(http.StatusBadGateway)
+			} else {
+				w.WriteHeader(http.StatusBadRequest)
+			}
 			json.NewEncoder(w).Encode(result)
 			return
 		}
 
-		log.Printf("[COMPLETE] Goal %s completed successfully", goalID)
-
-		// Emit SSE event for goal completed
-		h.EmitEvent("goal_completed", map[string]interface{}{
-			"goal_id": goalID,
-			"title":   data.Title,
-			"project": data.Project,
-			"merged":  data.Merged,
-		})
-
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": true,
 			"data":    data,
@@ -1145,7 +2898,8 @@ func handleGoalIce(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
-		log.Printf("[ICE] Icing goal %s in project %s (reason=%q, remove_worktree=%v, force=%v)", goalID, req.Project, req.Reason, req.RemoveWorktree, req.Force)
+		user := actingUser(r, req.User)
+		log.Printf("[ICE] Icing goal %s in project %s for %s (reason=%q, remove_worktree=%v, force=%v)", goalID, req.Project, user, req.Reason, req.RemoveWorktree, req.Force)
 
 		result, data := operations.IceGoal(operations.IceOptions{
 			GoalID:         goalID,
@@ -1153,6 +2907,7 @@ func handleGoalIce(h *hub.Hub, goalID string) http.HandlerFunc {
 			Reason:         req.Reason,
 			RemoveWorktree: req.RemoveWorktree,
 			Force:          req.Force,
+			User:           user,
 			VegaDir:        h.Dir(),
 		})
 
@@ -1165,9 +2920,8 @@ func handleGoalIce(h *hub.Hub, goalID string) http.HandlerFunc {
 
 		log.Printf("[ICE] Goal %s iced successfully", goalID)
 
-		// Emit SSE event for goal iced
-		h.EmitEvent("goal_iced", map[string]interface{}{
-			"goal_id": goalID,
+		// Record in the goal's history and emit SSE event for goal iced
+		h.RecordGoalAction(goalID, "goal_iced", user, map[string]interface{}{
 			"title":   data.Title,
 			"project": data.Project,
 			"reason":  data.Reason,
@@ -1201,7 +2955,8 @@ func handleGoalCleanup(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
-		log.Printf("[CLEANUP] Cleaning up goal %s in project %s", goalID, req.Project)
+		user := actingUser(r, req.User)
+		log.Printf("[CLEANUP] Cleaning up goal %s in project %s for %s", goalID, req.Project, user)
 
 		result, data := operations.CleanupGoal(operations.CleanupOptions{
 			GoalID:  goalID,
@@ -1218,9 +2973,8 @@ func handleGoalCleanup(h *hub.Hub, goalID string) http.HandlerFunc {
 
 		log.Printf("[CLEANUP] Goal %s branch cleaned up successfully", goalID)
 
-		// Emit SSE event for cleanup
-		h.EmitEvent("goal_cleanup", map[string]interface{}{
-			"goal_id": goalID,
+		// Record in the goal's history and emit SSE event for cleanup
+		h.RecordGoalAction(goalID, "goal_cleanup", user, map[string]interface{}{
 			"project": data.Project,
 			"branch":  data.Branch,
 		})
@@ -1253,7 +3007,8 @@ func handleGoalResume(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
-		log.Printf("[RESUME] Resuming goal %s in project %s", goalID, req.Project)
+		user := actingUser(r, req.User)
+		log.Printf("[RESUME] Resuming goal %s in project %s for %s", goalID, req.Project, user)
 
 		result, data := operations.ResumeGoal(operations.ResumeOptions{
 			GoalID:  goalID,
@@ -1270,9 +3025,8 @@ func handleGoalResume(h *hub.Hub, goalID string) http.HandlerFunc {
 
 		log.Printf("[RESUME] Goal %s resumed successfully", goalID)
 
-		// Emit SSE event for goal resumed
-		h.EmitEvent("goal_resumed", map[string]interface{}{
-			"goal_id":          goalID,
+		// Record in the goal's history and emit SSE event for goal resumed
+		h.RecordGoalAction(goalID, "goal_resumed", user, map[string]interface{}{
 			"title":            data.Title,
 			"project":          data.Project,
 			"worktree_created": data.WorktreeCreated,
@@ -1316,14 +3070,11 @@ func handleDeleteGoal(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFu
 		}
 
 		// Determine goal location (active, iced, or history)
-		goalFolder := "active"
-		goalFile := filepath.Join(p.Dir(), "goals", "active", goalID+".md")
-		if _, err := os.Stat(goalFile); os.IsNotExist(err) {
-			goalFile = filepath.Join(p.Dir(), "goals", "iced", goalID+".md")
-			goalFolder = "iced"
-			if _, err := os.Stat(goalFile); os.IsNotExist(err) {
-				goalFile = filepath.Join(p.Dir(), "goals", "history", goalID+".md")
-				goalFolder = "history"
+		goalFile := ""
+		for _, dir := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+			if path := p.Layout().FindGoalFileIn(dir, goalID); path != "" {
+				goalFile = path
+				break
 			}
 		}
 
@@ -1337,7 +3088,7 @@ func handleDeleteGoal(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFu
 			worktreePath = filepath.Join(p.Dir(), detail.Worktree.Path)
 			branchName = detail.Worktree.Branch
 			if len(detail.Projects) > 0 {
-				projectBase = filepath.Join(p.Dir(), "workspaces", detail.Projects[0], "worktree-base")
+				projectBase = p.Layout().WorktreeBase(detail.Projects[0])
 			}
 			if _, statErr := os.Stat(worktreePath); statErr == nil {
 				worktreeExists = true
@@ -1347,16 +3098,17 @@ func handleDeleteGoal(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFu
 			worktreePath, _ = findWorktreeForGoal(p.Dir(), goalID, detail.Projects)
 			if worktreePath != "" {
 				worktreeExists = true
-				projectBase = filepath.Join(p.Dir(), "workspaces", detail.Projects[0], "worktree-base")
-				branchName = getCurrentBranch(worktreePath)
+				projectBase = p.Layout().WorktreeBase(detail.Projects[0])
+				branchName = gitsvc.NewExec().CurrentBranch(worktreePath)
 			}
 		}
 
-		// Pre-flight checks (if not force)
+		// Pre-flight checks (if not force); uncached since they guard the deletion
 		var warnings []DeleteWarning
 		if !req.Force && worktreeExists {
+			git := gitsvc.NewExec()
 			// Check for uncommitted changes
-			uncommittedFiles := getUncommittedFiles(worktreePath)
+			uncommittedFiles := git.UncommittedFiles(worktreePath)
 			if len(uncommittedFiles) > 0 {
 				warnings = append(warnings, DeleteWarning{
 					Level:   "error",
@@ -1371,7 +3123,7 @@ func handleDeleteGoal(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFu
 				if detail.Worktree != nil && detail.Worktree.BaseBranch != "" {
 					baseBranch = detail.Worktree.BaseBranch
 				}
-				ahead, _ := getAheadBehind(worktreePath, baseBranch)
+				ahead, _ := git.AheadBehind(worktreePath, baseBranch)
 				if ahead > 0 {
 					warnings = append(warnings, DeleteWarning{
 						Level:   "warning",
@@ -1430,24 +3182,25 @@ func handleDeleteGoal(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFu
 		}
 
 		// Step 5: Update REGISTRY.md
-		registryPath := filepath.Join(p.Dir(), "goals", "REGISTRY.md")
-		if err := removeGoalFromRegistry(registryPath, goalID, goalFolder); err != nil {
+		registryPath := p.Layout().RegistryMarkdown()
+		if err := removeGoalFromRegistry(registryPath, goalID); err != nil {
 			log.Printf("[DELETE] Failed to update registry: %v", err)
 		}
 
-		// Step 6: Update project config
-		if len(detail.Projects) > 0 {
-			projectConfig := filepath.Join(p.Dir(), "projects", detail.Projects[0]+".md")
-			if err := removeGoalFromProjectConfig(projectConfig, goalID); err != nil {
-				log.Printf("[DELETE] Failed to update project config: %v", err)
-			}
+		// Step 6: Drop the registry entry and regenerate the project goal lists
+		if err := hub.NewLockManager(h.Dir()).WithRegistryLock("delete-goal", func() error {
+			return goals.NewRegistry(h.Dir()).Delete(goalID)
+		}); err != nil && !errors.Is(err, goals.ErrNotFound) {
+			log.Printf("[DELETE] Failed to update registry: %v", err)
 		}
+		operations.SyncProjectGoals(h.Dir(), detail.Projects...)
 
-		log.Printf("[DELETE] Goal %s deleted successfully", goalID)
+		user := actingUser(r, req.User)
+		log.Printf("[DELETE] Goal %s deleted successfully by %s", goalID, user)
 
-		// Emit SSE event
-		h.EmitEvent("goal_deleted", map[string]interface{}{
-			"goal_id":          goalID,
+		// Record in the goal's history, which outlives it, and emit SSE event
+		h.RecordGoalAction(goalID, "goal_deleted", user, map[string]interface{}{
+			"title":            detail.Title,
 			"worktree_removed": response.WorktreeRemoved,
 			"branch_deleted":   response.BranchDeleted,
 		})
@@ -1465,34 +3218,13 @@ func removeWorktreeForGoal(projectBase, worktreeDir string) {
 		relPath = worktreeDir
 	}
 	cmd := exec.Command("git", "-C", projectBase, "worktree", "remove", relPath, "--force")
-	if err := cmd.Run(); err != nil {
+	// Worktrees live next to worktree-base; never delete anything else
+	if err := cmd.Run(); err != nil && pathguard.Within(filepath.Dir(projectBase), worktreeDir) == nil {
 		os.RemoveAll(worktreeDir)
 		exec.Command("git", "-C", projectBase, "worktree", "prune").Run()
 	}
 }
 
-// getUncommittedFiles returns a list of uncommitted files in a worktree
-func getUncommittedFiles(repoPath string) []string {
-	cmd := exec.Command("git", "-C", repoPath, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return nil
-	}
-
-	var files []string
-	for _, line := range lines {
-		if len(line) > 3 {
-			files = append(files, strings.TrimSpace(line[3:]))
-		}
-	}
-	return files
-}
-
 // deleteBranchForce forcefully deletes a branch
 func deleteBranchForce(projectBase, branchName string) error {
 	cmd := exec.Command("git", "-C", projectBase, "branch", "-D", branchName)
@@ -1503,50 +3235,11 @@ func deleteBranchForce(projectBase, branchName string) error {
 }
 
 // removeGoalFromRegistry removes a goal from REGISTRY.md
-func removeGoalFromRegistry(registryPath, goalID, folder string) error {
-	content, err := os.ReadFile(registryPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-
-	// Pattern to match goal row in any table
-	goalPattern := regexp.MustCompile(fmt.Sprintf(`^\| %s \|`, regexp.QuoteMeta(goalID)))
-
-	for _, line := range lines {
-		if goalPattern.MatchString(line) {
-			continue // Skip this line (remove from registry)
-		}
-		newLines = append(newLines, line)
-	}
-
-	return os.WriteFile(registryPath, []byte(strings.Join(newLines, "\n")), 0644)
-}
-
-// removeGoalFromProjectConfig removes a goal from a project's config file
-func removeGoalFromProjectConfig(configPath, goalID string) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-
-	// Patterns to match goal references
-	listPattern := regexp.MustCompile(fmt.Sprintf(`^- #?%s:`, regexp.QuoteMeta(goalID)))
-	tablePattern := regexp.MustCompile(fmt.Sprintf(`^\| %s \|`, regexp.QuoteMeta(goalID)))
-
-	for _, line := range lines {
-		if listPattern.MatchString(line) || tablePattern.MatchString(line) {
-			continue // Skip this line (remove from config)
-		}
-		newLines = append(newLines, line)
-	}
-
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
+func removeGoalFromRegistry(registryPath, goalID string) error {
+	return goals.UpdateMarkdownFile(registryPath, func(doc *goals.MarkdownDoc) error {
+		doc.RemoveRegistryGoal(goalID)
+		return nil
+	})
 }
 
 // handleCreateMR handles POST /api/goals/:id/create-mr - creates a merge request
@@ -1636,19 +3329,29 @@ func handleCreateMR(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFunc
 			}
 		}
 
+		// Without a description, render the project's MR description template
+		description := req.Description
+		if description == "" {
+			if description, err = operations.GoalMRDescription(p.Dir(), goalID, project, worktreePath, targetBranch); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(CreateMRResponse{
+					Success: false,
+					Error:   "MR description template failed to render: " + err.Error(),
+				})
+				return
+			}
+		}
+
 		// Detect git service from remote URL
 		service := detectGitService(proj.GitRemote)
 		log.Printf("[CREATE-MR] Detected service: %s for remote: %s", service, proj.GitRemote)
 
-		// Create MR/PR
-		var mrURL string
-		var mrNumber int
-
+		create := createGitHubPR
 		switch service {
 		case "github":
-			mrURL, mrNumber, err = createGitHubPR(worktreePath, req.Title, req.Description, targetBranch, req.Draft)
 		case "gitlab":
-			mrURL, mrNumber, err = createGitLabMR(worktreePath, req.Title, req.Description, targetBranch, req.Draft)
+			create = createGitLabMR
 		default:
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusBadRequest)
@@ -1659,6 +3362,26 @@ func handleCreateMR(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFunc
 			return
 		}
 
+		// Create MR/PR (pushes the branch first, which can take a while)
+		var mrURL string
+		var mrNumber int
+		err = context.Canceled
+		spec := jobs.Spec{Type: "create_mr", GoalID: goalID, User: requestUser(r)}
+		if !runAsJob(w, r, h, spec, req.Async, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			progress("pushing and creating " + service + " MR")
+			mrURL, mrNumber, err = create(worktreePath, req.Title, issueDescription(detail.Goal, description), targetBranch, req.Draft)
+			if err != nil {
+				return nil, err
+			}
+			log.Printf("[CREATE-MR] Created %s MR #%d: %s", service, mrNumber, mrURL)
+			return CreateMRResponse{Success: true, MRURL: mrURL, MRNumber: mrNumber, Service: service}, nil
+		}) {
+			return
+		}
+
 		if err != nil {
 			log.Printf("[CREATE-MR] Failed to create MR: %v", err)
 			w.Header().Set("Content-Type", "application/json")
@@ -1671,8 +3394,6 @@ func handleCreateMR(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFunc
 			return
 		}
 
-		log.Printf("[CREATE-MR] Created %s MR #%d: %s", service, mrNumber, mrURL)
-
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(CreateMRResponse{
 			Success:  true,
@@ -1695,6 +3416,24 @@ func detectGitService(remoteURL string) string {
 	return "unknown"
 }
 
+// issueDescription prefixes an MR description with the goal's linked issue
+func issueDescription(goal goals.Goal, description string) string {
+	if goal.IssueURL == "" && goal.TrackerID == "" {
+		return description
+	}
+	link := goal.TrackerID
+	switch {
+	case goal.IssueURL != "" && link != "":
+		link = fmt.Sprintf("[%s](%s)", link, goal.IssueURL)
+	case goal.IssueURL != "":
+		link = goal.IssueURL
+	}
+	if description == "" {
+		return "Issue: " + link
+	}
+	return "Issue: " + link + "\n\n" + description
+}
+
 // createGitHubPR creates a pull request using gh CLI
 func createGitHubPR(repoPath, title, description, targetBranch string, draft bool) (string, int, error) {
 	args := []string{"pr", "create", "--title", title, "--base", targetBranch}
@@ -1781,19 +3520,32 @@ func createGitLabMR(repoPath, title, description, targetBranch string, draft boo
 // ProjectSummary is a simplified project info for the API
 type ProjectSummary struct {
 	Name            string `json:"name"`
+	Type            string `json:"type"`          // "git" or "plain"
+	VCS             string `json:"vcs,omitempty"` // Backend for git projects: "git" (default) or "jj"
 	BaseBranch      string `json:"base_branch"`
 	Workspace       string `json:"workspace,omitempty"`
 	Upstream        string `json:"upstream,omitempty"`
 	WorkspaceStatus string `json:"workspace_status"`          // "ready", "missing", "error"
 	WorkspaceError  string `json:"workspace_error,omitempty"` // Error message if not ready
+
+	// How far the base branch is behind origin, from the freshness monitor
+	BaseFreshness *operations.BaseFreshness `json:"base_freshness,omitempty"`
 }
 
 // AddProjectRequest is the request body for POST /api/projects
 type AddProjectRequest struct {
 	Name       string `json:"name"`
-	Path       string `json:"path,omitempty"` // Local path to existing repository
-	URL        string `json:"url,omitempty"`  // Remote URL to clone from
-	BaseBranch string `json:"base_branch"`    // Optional, will auto-detect
+	Path       string `json:"path,omitempty"`  // Local path to existing repository
+	Type       string `json:"type,omitempty"`  // "git" (default) or "plain" (path projects without git)
+	URL        string `json:"url,omitempty"`   // Remote URL to clone from
+	BaseBranch string `json:"base_branch"`     // Optional, will auto-detect
+	Async      bool   `json:"async,omitempty"` // url projects: respond 202 with the clone job
+
+	// Optional branch protection and merge settings
+	goals.MergePolicy
+
+	// Optional shallow, partial and sparse clone settings (url projects)
+	goals.CloneOptions
 }
 
 // AddProjectResponse is the response for POST /api/projects
@@ -1806,6 +3558,8 @@ type AddProjectResponse struct {
 	ConfigFile   string `json:"config_file,omitempty"`
 	WorktreePath string `json:"worktree_path,omitempty"`
 	Error        string `json:"error,omitempty"`
+	goals.MergePolicy
+	goals.CloneOptions
 }
 
 // RemoveProjectResponse is the response for DELETE /api/projects/:name
@@ -1844,6 +3598,27 @@ func handleProjectRoutes(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 			return
 		}
 
+		name, action, ok := strings.Cut(path, "/")
+		if !validIDs(w, "project", name) {
+			return
+		}
+
+		// Handle POST /api/projects/:name/repair, /api/projects/:name/goals and
+		// GET /api/projects/:name/badge.svg
+		if ok {
+			switch action {
+			case "repair":
+				handleRepairProject(h, name)(w, r)
+			case "goals":
+				handleProjectGoals(h, p, name)(w, r)
+			case "badge.svg":
+				handleProjectBadge(h, p, name)(w, r)
+			default:
+				http.Error(w, "Not found", http.StatusNotFound)
+			}
+			return
+		}
+
 		// Handle DELETE /api/projects/:name
 		if r.Method == http.MethodDelete {
 			handleRemoveProject(h, p, path)(w, r)
@@ -1869,17 +3644,28 @@ func handleListProjects(h *hub.Hub) http.HandlerFunc {
 			return
 		}
 
+		freshness, err := operations.LoadBaseFreshness(h.Dir())
+		if err != nil {
+			log.Printf("[PROJECT] %v", err)
+		}
+
 		// Convert to summaries
 		summaries := make([]ProjectSummary, 0, len(projects))
 		for _, p := range projects {
-			summaries = append(summaries, ProjectSummary{
+			summary := ProjectSummary{
 				Name:            p.Name,
+				Type:            p.Type,
+				VCS:             p.VCS,
 				BaseBranch:      p.BaseBranch,
 				Workspace:       p.Workspace,
 				Upstream:        p.Upstream,
 				WorkspaceStatus: p.WorkspaceStatus,
 				WorkspaceError:  p.WorkspaceError,
-			})
+			}
+			if f, ok := freshness[p.Name]; ok {
+				summary.BaseFreshness = &f
+			}
+			summaries = append(summaries, summary)
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -1927,21 +3713,42 @@ func handleAddProject(h *hub.Hub) http.HandlerFunc {
 		var data *operations.AddProjectResult
 
 		if req.URL != "" {
-			// Clone from remote URL
+			// Clone from remote URL, which can take minutes on large repos
 			log.Printf("[PROJECT] Cloning project: name=%q, url=%q, base_branch=%q", req.Name, req.URL, req.BaseBranch)
-			result, data = operations.AddProjectFromURL(operations.AddProjectURLOptions{
-				Name:       req.Name,
-				URL:        req.URL,
-				BaseBranch: req.BaseBranch,
-				VegaDir:    h.Dir(),
-			})
+			spec := jobs.Spec{Type: "clone_project", User: requestUser(r)}
+			if !runAsJob(w, r, h, spec, req.Async, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				progress("cloning " + req.URL)
+				result, data = operations.AddProjectFromURL(operations.AddProjectURLOptions{
+					Name:       req.Name,
+					URL:        req.URL,
+					BaseBranch: req.BaseBranch,
+					Policy:     req.MergePolicy,
+					Clone:      req.CloneOptions,
+					VegaDir:    h.Dir(),
+				})
+				if !result.Success {
+					return result, resultError(result)
+				}
+				emitProjectAdded(h, data)
+				return data, nil
+			}) {
+				return
+			}
+			if result == nil {
+				result = canceledResult()
+			}
 		} else {
 			// Link existing local path
 			log.Printf("[PROJECT] Adding project: name=%q, path=%q, base_branch=%q", req.Name, req.Path, req.BaseBranch)
 			result, data = operations.AddProjectFromPath(operations.AddProjectOptions{
 				Name:       req.Name,
 				Path:       req.Path,
+				Type:       req.Type,
 				BaseBranch: req.BaseBranch,
+				Policy:     req.MergePolicy,
 				VegaDir:    h.Dir(),
 			})
 		}
@@ -1956,134 +3763,606 @@ func handleAddProject(h *hub.Hub) http.HandlerFunc {
 			return
 		}
 
-		log.Printf("[PROJECT] Project added: name=%s, path=%s", data.Name, data.Path)
+		if req.URL == "" {
+			emitProjectAdded(h, data)
+		}
+
+		json.NewEncoder(w).Encode(AddProjectResponse{
+			Success:      true,
+			Name:         data.Name,
+			Path:         data.Path,
+			BaseBranch:   data.BaseBranch,
+			GitRemote:    data.GitRemote,
+			ConfigFile:   data.ConfigFile,
+			WorktreePath: data.WorktreePath,
+			MergePolicy:  data.MergePolicy,
+			CloneOptions: data.CloneOptions,
+		})
+	}
+}
+
+// emitProjectAdded logs and broadcasts a newly added project
+func emitProjectAdded(h *hub.Hub, data *operations.AddProjectResult) {
+	log.Printf("[PROJECT] Project added: name=%s, path=%s", data.Name, data.Path)
+
+	// Emit SSE event
+	h.EmitEvent("project_added", map[string]interface{}{
+		"name":        data.Name,
+		"path":        data.Path,
+		"base_branch": data.BaseBranch,
+	})
+}
+
+// handleRemoveProject handles DELETE /api/projects/:name - removes a project
+func handleRemoveProject(h *hub.Hub, p *goals.Parser, projectName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("[PROJECT] Received remove project request for %s from %s", projectName, r.RemoteAddr)
+
+		// Check for force query param
+		force := r.URL.Query().Get("force") == "true"
+
+		result, data := operations.RemoveProject(operations.RemoveProjectOptions{
+			Name:    projectName,
+			Force:   force,
+			VegaDir: h.Dir(),
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			statusCode := http.StatusBadRequest
+			if result.Error.Code == "project_not_found" {
+				statusCode = http.StatusNotFound
+			} else if result.Error.Code == "has_active_goals" {
+				statusCode = http.StatusConflict
+			}
+
+			response := RemoveProjectResponse{
+				Success: false,
+				Name:    projectName,
+				Error:   result.Error.Message,
+			}
+
+			// Include active goals info if that's the error
+			if result.Error.Code == "has_active_goals" {
+				if goalsStr, ok := result.Error.Details["goals"]; ok {
+					response.ActiveGoals = strings.Split(goalsStr, ", ")
+				}
+			}
+
+			w.WriteHeader(statusCode)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		log.Printf("[PROJECT] Project removed: name=%s", projectName)
+
+		// Emit SSE event
+		h.EmitEvent("project_removed", map[string]interface{}{
+			"name": projectName,
+		})
+
+		json.NewEncoder(w).Encode(RemoveProjectResponse{
+			Success:          true,
+			Name:             data.Name,
+			ConfigRemoved:    data.ConfigRemoved,
+			IndexUpdated:     data.IndexUpdated,
+			WorkspaceRemoved: data.WorkspaceRemoved,
+			GoalsWarning:     data.GoalsWarning,
+		})
+	}
+}
+
+// RepairProjectRequest is the optional request body for POST /api/projects/:name/repair
+type RepairProjectRequest struct {
+	URL string `json:"url,omitempty"` // Clone source if the project config doesn't record one
+}
+
+// handleRepairProject handles POST /api/projects/:name/repair - re-syncs worktree-base
+// and re-registers goal worktrees
+func handleRepairProject(h *hub.Hub, projectName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req RepairProjectRequest
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+
+		log.Printf("[PROJECT] Repairing project %s", projectName)
+
+		result, data := operations.RepairProject(operations.RepairProjectOptions{
+			Name:    projectName,
+			URL:     req.URL,
+			VegaDir: h.Dir(),
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			switch result.Error.Code {
+			case "project_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "lock_failed":
+				w.WriteHeader(http.StatusConflict)
+			case "no_upstream":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   result.Error,
+				"data":    data,
+			})
+			return
+		}
+
+		log.Printf("[PROJECT] Repaired project %s: fixed=%v problems=%v", projectName, data.Fixed, data.Problems)
+
+		h.EmitEvent("project_repaired", map[string]interface{}{
+			"name":     projectName,
+			"fixed":    data.Fixed,
+			"problems": data.Problems,
+		})
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+		})
+	}
+}
+
+// handleGetProject handles GET /api/projects/:name - returns project details
+func handleGetProject(h *hub.Hub, p *goals.Parser, projectName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		proj, err := p.ParseProject(projectName)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{
+					"code":    "project_not_found",
+					"message": fmt.Sprintf("Project '%s' not found: %v", projectName, err),
+				},
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ProjectSummary{
+			Name:            proj.Name,
+			Type:            proj.Type,
+			VCS:             proj.VCS,
+			BaseBranch:      proj.BaseBranch,
+			Workspace:       proj.Workspace,
+			Upstream:        proj.Upstream,
+			WorkspaceStatus: proj.WorkspaceStatus,
+			WorkspaceError:  proj.WorkspaceError,
+			BaseFreshness:   operations.GetBaseFreshness(h.Dir(), proj.Name),
+		})
+	}
+}
+
+// handleProjectGoals handles /api/projects/:name/goals: GET returns the
+// project's goals by status from the registry, POST also regenerates the goal
+// lists in the project config from it
+func handleProjectGoals(h *hub.Hub, p *goals.Parser, projectName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if _, err := p.ParseProject(projectName); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{
+					"code":    "project_not_found",
+					"message": fmt.Sprintf("Project '%s' not found: %v", projectName, err),
+				},
+			})
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			operations.SyncProjectGoals(h.Dir(), projectName)
+		}
+		view, err := goals.LoadProjectGoals(h.Dir(), projectName)
+		if err != nil {
+			http.Error(w, "Failed to load registry: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(view)
+	}
+}
+
+// handleGoalSessions handles GET /api/goals/:id/sessions - returns session history for a goal
+func handleGoalSessions(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		sessions, err := h.GetGoalSessions(goalID)
+		if err != nil {
+			http.Error(w, "Failed to get sessions: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if sessions == nil {
+			sessions = []*hub.ExecutorSession{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sessions)
+	}
+}
+
+// handleExecutorControl handles POST /api/goals/:id/executors/:sid/{kill,pause,resume}
+func handleExecutorControl(h *hub.Hub, goalID, sessionID, control string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		user := requestUser(r)
+
+		var err error
+		switch control {
+		case "kill":
+			err = h.KillExecutor(goalID, sessionID, user)
+		case "pause":
+			err = h.PauseExecutor(goalID, sessionID, user)
+		case "resume":
+			err = h.ResumeExecutor(goalID, sessionID, user)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			switch {
+			case errors.Is(err, hub.ErrExecutorNotFound):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, hub.ErrNoProcess), errors.Is(err, hub.ErrRemoteExecutor),
+				errors.Is(err, hub.ErrExecutorPaused), errors.Is(err, hub.ErrExecutorNotPaused),
+				errors.Is(err, hub.ErrExecutorSwitching):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		log.Printf("[EXECUTOR] %s requested for session %s (goal %s, user %s)", control, sessionID, goalID, user)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"ok":         true,
+			"session_id": sessionID,
+			"action":     control,
+		})
+	}
+}
+
+// handleGoalActivity handles GET /api/goals/:id/activity - files edited, commands and test runs.
+// Query params: session (session ID), kind (file_edit, command, test_run).
+func handleGoalActivity(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		activities, err := h.GetGoalActivity(goalID)
+		if err != nil {
+			http.Error(w, "Failed to get activity: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		// Emit SSE event
-		h.EmitEvent("project_added", map[string]interface{}{
-			"name":        data.Name,
-			"path":        data.Path,
-			"base_branch": data.BaseBranch,
-		})
+		session := r.URL.Query().Get("session")
+		kind := r.URL.Query().Get("kind")
+		result := make([]hub.SessionActivity, 0, len(activities))
+		for _, a := range activities {
+			if session != "" && a.SessionID != session {
+				continue
+			}
+			if kind != "" && a.Kind != kind {
+				continue
+			}
+			result = append(result, a)
+		}
 
-		json.NewEncoder(w).Encode(AddProjectResponse{
-			Success:      true,
-			Name:         data.Name,
-			Path:         data.Path,
-			BaseBranch:   data.BaseBranch,
-			GitRemote:    data.GitRemote,
-			ConfigFile:   data.ConfigFile,
-			WorktreePath: data.WorktreePath,
-		})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
 	}
 }
 
-// handleRemoveProject handles DELETE /api/projects/:name - removes a project
-func handleRemoveProject(h *hub.Hub, p *goals.Parser, projectName string) http.HandlerFunc {
+// handleGoalContext handles the executor context pack for a goal:
+// GET previews it, POST sets per-goal overrides, DELETE removes them
+func handleGoalContext(h *hub.Hub, goalID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("[PROJECT] Received remove project request for %s from %s", projectName, r.RemoteAddr)
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var overrides hub.ContextOverrides
+			if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			if err := h.SetContextOverrides(goalID, &overrides); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		case http.MethodDelete:
+			if err := h.SetContextOverrides(goalID, nil); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-		// Check for force query param
-		force := r.URL.Query().Get("force") == "true"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.PreviewContextPack(goalID))
+	}
+}
 
-		result, data := operations.RemoveProject(operations.RemoveProjectOptions{
-			Name:    projectName,
-			Force:   force,
-			VegaDir: h.Dir(),
-		})
+// GoalPositionRequest is the request body for PATCH /api/goals/:id/position
+type GoalPositionRequest struct {
+	Project string `json:"project,omitempty"` // Board to reorder ("" = all goals)
+	Status  string `json:"status,omitempty"`  // Column; defaults to the goal's status
+	Index   int    `json:"index"`             // 0-based position in the column
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		if !result.Success {
-			statusCode := http.StatusBadRequest
-			if result.Error.Code == "project_not_found" {
-				statusCode = http.StatusNotFound
-			} else if result.Error.Code == "has_active_goals" {
-				statusCode = http.StatusConflict
-			}
+// handleGoalPosition moves a goal within a kanban board column.
+// PATCH /api/goals/:id/position
+func handleGoalPosition(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-			response := RemoveProjectResponse{
-				Success: false,
-				Name:    projectName,
-				Error:   result.Error.Message,
-			}
+		var req GoalPositionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
 
-			// Include active goals info if that's the error
-			if result.Error.Code == "has_active_goals" {
-				if goalsStr, ok := result.Error.Details["goals"]; ok {
-					response.ActiveGoals = strings.Split(goalsStr, ", ")
-				}
+		registryGoals, err := p.ParseRegistry()
+		if err != nil {
+			http.Error(w, "Failed to parse registry: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if req.Project != "" {
+			registryGoals = filterGoalsByProject(registryGoals, req.Project)
+		}
+		var goal *goals.Goal
+		for i := range registryGoals {
+			if registryGoals[i].ID == goalID {
+				goal = &registryGoals[i]
+				break
 			}
+		}
+		if goal == nil {
+			http.Error(w, "Goal not found", http.StatusNotFound)
+			return
+		}
 
-			w.WriteHeader(statusCode)
-			json.NewEncoder(w).Encode(response)
+		status := req.Status
+		if status == "" {
+			status = goal.Status
+		}
+		switch status {
+		case "active", "iced", "completed":
+		default:
+			http.Error(w, "Invalid status: "+status, http.StatusBadRequest)
 			return
 		}
 
-		log.Printf("[PROJECT] Project removed: name=%s", projectName)
+		var members []string
+		for _, g := range registryGoals {
+			if g.Status == status {
+				members = append(members, g.ID)
+			}
+		}
+		order, err := goals.NewBoard(p.Dir()).Move(req.Project, status, goalID, req.Index, members)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		// Emit SSE event
-		h.EmitEvent("project_removed", map[string]interface{}{
-			"name": projectName,
+		// Let other users' boards follow along
+		h.EmitEvent("goal_position_changed", map[string]interface{}{
+			"goal_id": goalID,
+			"project": req.Project,
+			"status":  status,
+			"order":   order,
 		})
 
-		json.NewEncoder(w).Encode(RemoveProjectResponse{
-			Success:          true,
-			Name:             data.Name,
-			ConfigRemoved:    data.ConfigRemoved,
-			IndexUpdated:     data.IndexUpdated,
-			WorkspaceRemoved: data.WorkspaceRemoved,
-			GoalsWarning:     data.GoalsWarning,
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"goal_id": goalID,
+			"project": req.Project,
+			"status":  status,
+			"order":   order,
 		})
 	}
 }
 
-// handleGetProject handles GET /api/projects/:name - returns project details
-func handleGetProject(h *hub.Hub, p *goals.Parser, projectName string) http.HandlerFunc {
+// handleGoalChanges returns the files a goal changed (and optionally the diff)
+// against its base branch, limited to the project's subdirectory for monorepos.
+// GET /api/goals/:id/changed-files?project=name
+// GET /api/goals/:id/diff?project=name
+func handleGoalChanges(h *hub.Hub, goalID string, withDiff bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		proj, err := p.ParseProject(projectName)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		opts := operations.GoalDiffOptions{
+			GoalID:  goalID,
+			Project: r.URL.Query().Get("project"),
+			VegaDir: h.Dir(),
+		}
+		var result *operations.Result
+		var data *operations.GoalDiffResult
+		if withDiff {
+			result, data = operations.GoalDiff(opts)
+		} else {
+			result, data = operations.GoalChangedFiles(opts)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found", "worktree_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": map[string]string{
-					"code":    "project_not_found",
-					"message": fmt.Sprintf("Project '%s' not found: %v", projectName, err),
-				},
+				"success": false,
+				"error":   result.Error,
 			})
 			return
 		}
+		json.NewEncoder(w).Encode(data)
+	}
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(ProjectSummary{
-			Name:            proj.Name,
-			BaseBranch:      proj.BaseBranch,
-			Workspace:       proj.Workspace,
-			Upstream:        proj.Upstream,
-			WorkspaceStatus: proj.WorkspaceStatus,
-			WorkspaceError:  proj.WorkspaceError,
-		})
+// describeActivity formats a transcript-derived activity as a one-line chat message
+func describeActivity(kind string, data map[string]interface{}) string {
+	str := func(key string) string {
+		v, _ := data[key].(string)
+		return v
 	}
+
+	switch kind {
+	case hub.ActivityFileEdit:
+		if edits, ok := data["edits"].(float64); ok && edits > 1 {
+			return fmt.Sprintf("Edited %s (%d edits)", str("file"), int(edits))
+		}
+		return "Edited " + str("file")
+	case hub.ActivityTestRun:
+		switch str("status") {
+		case hub.ActivityOK:
+			return "Tests passed: " + str("command")
+		case hub.ActivityFailed:
+			return "Tests failed: " + str("command")
+		}
+		return "Ran tests: " + str("command")
+	case hub.ActivityCommand:
+		if str("status") == hub.ActivityFailed {
+			return "Command failed: " + str("command")
+		}
+		return "Ran: " + str("command")
+	}
+	return kind
 }
 
-// handleGoalSessions handles GET /api/goals/:id/sessions - returns session history for a goal
-func handleGoalSessions(h *hub.Hub, goalID string) http.HandlerFunc {
+// TranscriptResponse is the response for GET /api/goals/:id/sessions/:sid/transcript
+type TranscriptResponse struct {
+	GoalID    string                 `json:"goal_id"`
+	SessionID string                 `json:"session_id"`
+	Total     int                    `json:"total"`  // Entries matching the kind filter
+	Offset    int                    `json:"offset"` // Index into the filtered entries
+	Limit     int                    `json:"limit"`
+	HasMore   bool                   `json:"has_more"`
+	Entries   []hub.TranscriptEntry  `json:"entries"`
+	Summary   *hub.TranscriptSummary `json:"summary"` // Always covers the whole transcript
+}
+
+// handleSessionTranscript handles GET /api/goals/:id/sessions/:sid/transcript - paginated session transcript.
+// Query params: offset, limit (default 100, max 1000), kind (text, tool_call, tool_result).
+func handleSessionTranscript(h *hub.Hub, goalID, sessionID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
-		sessions, err := h.GetGoalSessions(goalID)
+		query := r.URL.Query()
+		offset, limit := 0, 100
+		if v := query.Get("offset"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid offset", http.StatusBadRequest)
+				return
+			}
+			offset = n
+		}
+		if v := query.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		if limit > 1000 {
+			limit = 1000
+		}
+		kind := query.Get("kind")
+		if kind != "" && kind != hub.TranscriptText && kind != hub.TranscriptToolCall && kind != hub.TranscriptToolResult {
+			http.Error(w, "Invalid kind: "+kind+" (valid: text, tool_call, tool_result)", http.StatusBadRequest)
+			return
+		}
+
+		entries, err := h.GetSessionTranscript(goalID, sessionID)
 		if err != nil {
-			http.Error(w, "Failed to get sessions: "+err.Error(), http.StatusInternalServerError)
+			if errors.Is(err, hub.ErrTranscriptNotFound) {
+				http.Error(w, "Transcript not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Failed to get transcript: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		if sessions == nil {
-			sessions = []*hub.ExecutorSession{}
+		summary := hub.SummarizeTranscript(entries)
+
+		if kind != "" {
+			filtered := make([]hub.TranscriptEntry, 0, len(entries))
+			for _, e := range entries {
+				if e.Kind == kind {
+					filtered = append(filtered, e)
+				}
+			}
+			entries = filtered
+		}
+
+		total := len(entries)
+		page := []hub.TranscriptEntry{}
+		if offset < total {
+			end := offset + limit
+			if end > total {
+				end = total
+			}
+			page = entries[offset:end]
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(sessions)
+		json.NewEncoder(w).Encode(TranscriptResponse{
+			GoalID:    goalID,
+			SessionID: sessionID,
+			Total:     total,
+			Offset:    offset,
+			Limit:     limit,
+			HasMore:   offset+len(page) < total,
+			Entries:   page,
+			Summary:   summary,
+		})
 	}
 }
 
@@ -2131,6 +4410,15 @@ func handleHistoryRoutes(h *hub.Hub) http.HandlerFunc {
 		}
 
 		goalID := parts[0]
+		if !validIDs(w, "goal ID", goalID) {
+			return
+		}
+
+		if goalID == "goals" && len(parts) == 1 {
+			handleCompletedGoals(goals.NewParser(h.Dir()))(w, r)
+			return
+		}
+		goalID = goals.ResolveGoalID(h.Dir(), goalID)
 
 		if len(parts) == 1 {
 			// GET /api/history/:goal_id - returns all history for a goal
@@ -2139,16 +4427,89 @@ func handleHistoryRoutes(h *hub.Hub) http.HandlerFunc {
 		}
 
 		sessionID := parts[1]
+		if !validIDs(w, "session ID", sessionID) {
+			return
+		}
 		// GET /api/history/:goal_id/:session_id - returns history for a specific session
 		handleSessionHistory(h, goalID, sessionID)(w, r)
 	}
 }
 
+// CompletedGoalsResponse is the response for GET /api/history/goals
+type CompletedGoalsResponse struct {
+	Total   int                   `json:"total"` // Goals matching the filters
+	Offset  int                   `json:"offset"`
+	Limit   int                   `json:"limit"`
+	HasMore bool                  `json:"has_more"`
+	Goals   []goals.CompletedGoal `json:"goals"`
+}
+
+// handleCompletedGoals handles GET /api/history/goals - paginated completed and archived goals,
+// most recently completed first. Query params: offset, limit (default 50, max 500), project, q.
+func handleCompletedGoals(p *goals.Parser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		offset, limit := 0, 50
+		if v := query.Get("offset"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid offset", http.StatusBadRequest)
+				return
+			}
+			offset = n
+		}
+		if v := query.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		if limit > 500 {
+			limit = 500
+		}
+
+		completed, err := p.ListCompletedGoals(goals.CompletedGoalFilter{
+			Project: query.Get("project"),
+			Query:   query.Get("q"),
+		})
+		if err != nil {
+			http.Error(w, "Failed to list completed goals: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		total := len(completed)
+		page := []goals.CompletedGoal{}
+		if offset < total {
+			end := offset + limit
+			if end > total {
+				end = total
+			}
+			page = completed[offset:end]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CompletedGoalsResponse{
+			Total:   total,
+			Offset:  offset,
+			Limit:   limit,
+			HasMore: offset+len(page) < total,
+			Goals:   page,
+		})
+	}
+}
+
 // ChatMessage represents a message in the chat thread
 // It transforms HistoryEntry to a format optimized for the chat UI
 type ChatMessage struct {
 	ID           string                 `json:"id"`
-	Type         string                 `json:"type"` // "session_start", "session_stop", "question", "answer", "user_message", "activity"
+	Type         string                 `json:"type"` // "session_start", "session_stop", "question", "answer", "user_message", "comment", "activity", "goal_iced", ...
 	Timestamp    string                 `json:"timestamp"`
 	SessionID    string                 `json:"session_id"`
 	GoalID       string                 `json:"goal_id"`
@@ -2159,13 +4520,57 @@ type ChatMessage struct {
 	Pending      bool                   `json:"pending,omitempty"`       // true for unanswered questions
 	Options      []hub.Option           `json:"options,omitempty"`       // for questions with predefined choices
 	User         string                 `json:"user,omitempty"`          // who sent (executor user, answering user)
+	AnsweredBy   string                 `json:"answered_by,omitempty"`   // for answered questions
 	StopReason   string                 `json:"stop_reason,omitempty"`   // for session_stop
+	MessageID    string                 `json:"message_id,omitempty"`    // for user_message
+	Delivery     string                 `json:"delivery,omitempty"`      // user_message status: pending, delivered, processed, undelivered
+	Format       string                 `json:"format,omitempty"`        // "markdown" when Content follows the chat content contract
+	CodeBlocks   []hub.CodeBlock        `json:"code_blocks,omitempty"`   // fenced code blocks of Content, in order
+	Truncated    bool                   `json:"truncated,omitempty"`     // Content was cut at hub.MaxContentBytes
+}
+
+// goalActionMessages describe the goal changes recorded with hub.RecordGoalAction
+var goalActionMessages = map[string]string{
+	"goal_completed": "Goal completed",
+	"goal_iced":      "Goal iced",
+	"goal_resumed":   "Goal resumed",
+	"goal_cleanup":   "Goal branch cleaned up",
+	"goal_deleted":   "Goal deleted",
+}
+
+// normalizeChatMessage applies the chat content contract (see
+// hub.NormalizeContent) to user- and executor-written text
+func normalizeChatMessage(msg *ChatMessage) {
+	switch msg.Type {
+	case "question", "answer", "user_message", "user_message_delivered", "comment":
+	default:
+		return
+	}
+	content := hub.NormalizeContent(msg.Content)
+	msg.Content = content.Text
+	msg.CodeBlocks = content.CodeBlocks
+	msg.Truncated = content.Truncated
+	msg.Format = hub.ContentFormat
+	if msg.Answer != "" {
+		answer := hub.NormalizeContent(msg.Answer)
+		msg.Answer = answer.Text
+		msg.Truncated = msg.Truncated || answer.Truncated
+	}
 }
 
 // handleGoalChat handles GET /api/goals/:id/chat - returns chat history as ChatMessage[]
 // Query params:
 //   - session: filter to specific session ID
 //   - limit: max number of messages (default: 100)
+//   - before: only messages older than this message ID (page backwards)
+//   - after: only messages newer than this message ID (page forwards)
+//   - since: only messages with a timestamp after this RFC3339 time
+//
+// History is read only as far as the page needs: up to a before cursor, or
+// until the page after an after cursor (or since) is full. Response header
+// X-Has-More says whether messages were left out of the page in the direction
+// being paged; X-Total-Count, the number of messages, is only sent when the
+// whole history was read.
 func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -2174,34 +4579,53 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 		}
 
 		// Parse query params
-		sessionFilter := r.URL.Query().Get("session")
+		query := r.URL.Query()
+		sessionFilter := query.Get("session")
 		limit := 100
-		if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if limitParam := query.Get("limit"); limitParam != "" {
 			if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
 				limit = l
 			}
 		}
 
-		// Get history entries
-		var entries []hub.HistoryEntry
-		var err error
-		if sessionFilter != "" {
-			entries, err = h.GetSessionHistory(goalID, sessionFilter)
-		} else {
-			entries, err = h.GetGoalHistory(goalID, 0) // Get all, we'll limit after merging pending
+		page := ChatPageOptions{
+			Before: query.Get("before"),
+			After:  query.Get("after"),
+			Limit:  limit,
 		}
-		if err != nil {
-			http.Error(w, "Failed to get chat history: "+err.Error(), http.StatusInternalServerError)
+		if page.Before != "" && page.After != "" {
+			http.Error(w, "before and after are mutually exclusive", http.StatusBadRequest)
 			return
 		}
-
-		// Convert history entries to chat messages
-		messages := make([]ChatMessage, 0, len(entries))
-		for i, entry := range entries {
+		if sinceParam := query.Get("since"); sinceParam != "" {
+			since, err := time.Parse(time.RFC3339Nano, sinceParam)
+			if err != nil {
+				http.Error(w, "Invalid since timestamp (expected RFC3339): "+sinceParam, http.StatusBadRequest)
+				return
+			}
+			page.Since = &since
+		}
+
+		// Convert history entries to chat messages until the page is full
+		var messages []ChatMessage
+		userMessages := make(map[string]int) // message ID -> index of its bubble
+		filled := newChatPageFill(page)
+		var lastStamp int64
+		seq := 0
+		err := h.ScanGoalHistory(goalID, func(entry hub.HistoryEntry) bool {
+			if sessionFilter != "" && entry.SessionID != sessionFilter {
+				return true
+			}
+			// Entries sharing a timestamp are told apart by their order
+			if stamp := entry.Timestamp.UnixNano(); stamp == lastStamp {
+				seq++
+			} else {
+				lastStamp, seq = stamp, 0
+			}
 			msg := ChatMessage{
-				ID:        fmt.Sprintf("hist-%d", i),
+				ID:        historyCursor(entry.Timestamp, seq),
 				Type:      entry.Type,
-				Timestamp: entry.Timestamp.Format(time.RFC3339),
+				Timestamp: entry.Timestamp.Format(time.RFC3339Nano),
 				SessionID: entry.SessionID,
 				GoalID:    entry.GoalID,
 				User:      entry.User,
@@ -2210,6 +4634,14 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 			switch entry.Type {
 			case "session_start":
 				msg.Content = fmt.Sprintf("Executor started in %s", entry.CWD)
+			case "session_resumed":
+				msg.Content = "Executor continues an earlier conversation"
+				if dataMap, ok := entry.Data.(map[string]interface{}); ok {
+					msg.Data = dataMap
+					if from, ok := dataMap["resumed_from"].(string); ok && from != "" {
+						msg.Content = fmt.Sprintf("Executor resumed session %s", from)
+					}
+				}
 			case "session_stop":
 				msg.StopReason = entry.StopReason
 				if entry.StopReason != "" {
@@ -2229,7 +4661,26 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 				msg.Content = entry.Question
 				msg.Answer = entry.Answer
 				msg.Pending = entry.Answer == "" // Pending if no answer recorded
-			case "user_message", "user_message_delivered":
+				if !msg.Pending {
+					msg.AnsweredBy = entry.User
+				}
+				// Offered options and structured answer (if any)
+				if dataMap, ok := entry.Data.(map[string]interface{}); ok {
+					msg.Data = dataMap
+				}
+			case "user_message_delivered", "user_message_processed", "user_message_undelivered":
+				// Status changes of a tracked message update its bubble
+				if dataMap, ok := entry.Data.(map[string]interface{}); ok {
+					if id, _ := dataMap["message_id"].(string); id != "" {
+						if idx, ok := userMessages[id]; ok {
+							messages[idx].Delivery = strings.TrimPrefix(entry.Type, "user_message_")
+							messages[idx].Pending = messages[idx].Delivery == hub.MessagePending || messages[idx].Delivery == hub.MessageUndelivered
+						}
+						return true
+					}
+				}
+				fallthrough
+			case "user_message":
 				// Extract content and user from Data field
 				if entry.Data != nil {
 					if dataMap, ok := entry.Data.(map[string]interface{}); ok {
@@ -2243,6 +4694,40 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 						if pending, ok := dataMap["pending"].(bool); ok {
 							msg.Pending = pending
 						}
+						if id, ok := dataMap["message_id"].(string); ok && entry.Type == "user_message" {
+							msg.MessageID = id
+							msg.Delivery = hub.MessagePending
+							userMessages[id] = len(messages)
+						}
+					}
+				}
+			case "executor_killed", "executor_paused", "executor_resumed":
+				verb := strings.TrimPrefix(entry.Type, "executor_")
+				msg.Content = "Executor " + verb
+				if dataMap, ok := entry.Data.(map[string]interface{}); ok {
+					msg.Data = dataMap
+					if user, ok := dataMap["user"].(string); ok && user != "" {
+						msg.User = user
+						msg.Content += " by " + user
+					}
+				}
+			case "goal_completed", "goal_iced", "goal_resumed", "goal_cleanup", "goal_deleted":
+				msg.Content = goalActionMessages[entry.Type]
+				if entry.User != "" {
+					msg.Content += " by " + entry.User
+				}
+				if dataMap, ok := entry.Data.(map[string]interface{}); ok {
+					msg.Data = dataMap
+					if reason, ok := dataMap["reason"].(string); ok && reason != "" {
+						msg.Content += ": " + reason
+					}
+				}
+			case "comment":
+				// User-to-user discussion (comment_id, parent_id, mentions in Data)
+				if dataMap, ok := entry.Data.(map[string]interface{}); ok {
+					msg.Data = dataMap
+					if content, ok := dataMap["content"].(string); ok {
+						msg.Content = content
 					}
 				}
 			case "activity":
@@ -2250,6 +4735,11 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 				if entry.Data != nil {
 					if dataMap, ok := entry.Data.(map[string]interface{}); ok {
 						msg.Data = dataMap
+						// Activities derived from transcripts carry their own kind
+						if kind, ok := dataMap["kind"].(string); ok {
+							msg.ActivityType = kind
+							msg.Content = describeActivity(kind, dataMap)
+						}
 					}
 				}
 			default:
@@ -2257,10 +4747,18 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 			}
 
 			messages = append(messages, msg)
+			return !filled.add(entry.Timestamp, seq)
+		})
+		if err != nil {
+			http.Error(w, "Failed to get chat history: "+err.Error(), http.StatusInternalServerError)
+			return
 		}
 
-		// Add pending questions that aren't in history yet
-		pendingQuestions := h.GetPendingQuestions()
+		// Add pending questions that aren't in history yet (they follow all of it)
+		var pendingQuestions []*hub.Question
+		if !filled.done {
+			pendingQuestions = h.GetPendingQuestions()
+		}
 		for _, q := range pendingQuestions {
 			if q.GoalID == goalID && (sessionFilter == "" || q.SessionID == sessionFilter) {
 				// Check if this question is already in messages (by matching question text and session)
@@ -2275,7 +4773,7 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 					msg := ChatMessage{
 						ID:        "pending-" + q.ID,
 						Type:      "question",
-						Timestamp: q.CreatedAt.Format(time.RFC3339),
+						Timestamp: q.CreatedAt.Format(time.RFC3339Nano),
 						SessionID: q.SessionID,
 						GoalID:    q.GoalID,
 						Content:   q.Question,
@@ -2287,16 +4785,181 @@ func handleGoalChat(h *hub.Hub, goalID string) http.HandlerFunc {
 			}
 		}
 
-		// Apply limit (take last N messages)
-		if len(messages) > limit {
-			messages = messages[len(messages)-limit:]
+		total := len(messages)
+		messages, hasMore, err := paginateChatMessages(messages, page)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if messages == nil {
+			messages = []ChatMessage{}
+		}
+		for i := range messages {
+			normalizeChatMessage(&messages[i])
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		if !filled.done {
+			w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		}
+		w.Header().Set("X-Has-More", strconv.FormatBool(hasMore))
 		json.NewEncoder(w).Encode(messages)
 	}
 }
 
+// historyCursor is the ID of a chat message from session history: its
+// timestamp and its position among entries with the same timestamp. Unlike a
+// position in the history file, it survives retention pruning older entries.
+func historyCursor(ts time.Time, seq int) string {
+	return fmt.Sprintf("hist-%d-%d", ts.UnixNano(), seq)
+}
+
+// parseHistoryCursor reverses historyCursor
+func parseHistoryCursor(id string) (stamp int64, seq int, ok bool) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || parts[0] != "hist" {
+		return 0, 0, false
+	}
+	stamp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return stamp, seq, true
+}
+
+// ChatPageOptions selects a window of chat messages
+type ChatPageOptions struct {
+	Before string     // Message ID cursor: return messages older than this one
+	After  string     // Message ID cursor: return messages newer than this one
+	Since  *time.Time // Return messages with a timestamp after this time
+	Limit  int        // Max messages to return
+}
+
+// chatPageFill tells handleGoalChat when the history read so far holds the
+// whole page, so the rest needn't be read: past the before cursor, or one
+// message past a full page after the after cursor (or since). Pending
+// question cursors aren't positions in history; those pages read it all.
+type chatPageFill struct {
+	opts      ChatPageOptions
+	before    bool // Stop past the before cursor
+	forward   bool // Stop once the page after the after cursor (or since) is full
+	stamp     int64
+	seq       int
+	collected int  // Messages after the after cursor (and since) read so far
+	done      bool // Reading stopped before the end of history
+}
+
+func newChatPageFill(opts ChatPageOptions) *chatPageFill {
+	f := &chatPageFill{opts: opts}
+	switch {
+	case opts.Before != "":
+		f.stamp, f.seq, f.before = parseHistoryCursor(opts.Before)
+	case opts.After != "":
+		f.stamp, f.seq, f.forward = parseHistoryCursor(opts.After)
+	case opts.Since != nil:
+		f.forward = true
+		f.stamp, f.seq = math.MinInt64, 0
+	}
+	return f
+}
+
+// add records the message just read from history (its timestamp and order
+// among entries sharing it) and reports whether reading can stop
+func (f *chatPageFill) add(ts time.Time, seq int) bool {
+	stamp := ts.UnixNano()
+	atOrPast := stamp > f.stamp || stamp == f.stamp && seq >= f.seq
+	switch {
+	case f.before:
+		// The cursor's message, or the first one after it, ends the window
+		f.done = atOrPast
+	case f.forward:
+		if atOrPast && !(stamp == f.stamp && seq == f.seq) && (f.opts.Since == nil || ts.After(*f.opts.Since)) {
+			f.collected++
+		}
+		f.done = f.opts.Limit > 0 && f.collected > f.opts.Limit
+	}
+	return f.done
+}
+
+// paginateChatMessages returns one page of messages (in chronological order) and
+// whether more messages exist beyond the page in the direction being paged.
+// Without a forward cursor (after/since) the newest messages are returned, which
+// is what the UI wants on first load and when scrolling back through history.
+// A history cursor whose message is gone (pruned by retention) still marks its
+// place in time.
+func paginateChatMessages(messages []ChatMessage, opts ChatPageOptions) ([]ChatMessage, bool, error) {
+	// indexOf returns the index of the cursor's message, or of the first
+	// message after it when the message itself is gone; exact reports which
+	indexOf := func(id string) (idx int, exact bool) {
+		for i, m := range messages {
+			if m.ID == id {
+				return i, true
+			}
+		}
+		stamp, seq, ok := parseHistoryCursor(id)
+		if !ok {
+			return -1, false
+		}
+		for i, m := range messages {
+			if s, q, ok := parseHistoryCursor(m.ID); ok && (s > stamp || s == stamp && q > seq) {
+				return i, false
+			}
+		}
+		for i, m := range messages {
+			if _, _, ok := parseHistoryCursor(m.ID); !ok {
+				return i, false // Pending questions follow history
+			}
+		}
+		return len(messages), false
+	}
+
+	start, end := 0, len(messages)
+	if opts.Before != "" {
+		idx, _ := indexOf(opts.Before)
+		if idx == -1 {
+			return nil, false, fmt.Errorf("unknown message cursor: %s", opts.Before)
+		}
+		end = idx
+	}
+	if opts.After != "" {
+		idx, exact := indexOf(opts.After)
+		if idx == -1 {
+			return nil, false, fmt.Errorf("unknown message cursor: %s", opts.After)
+		}
+		start = idx
+		if exact {
+			start++
+		}
+	}
+
+	window := messages[start:end]
+	if opts.Since != nil {
+		filtered := make([]ChatMessage, 0, len(window))
+		for _, m := range window {
+			ts, err := time.Parse(time.RFC3339Nano, m.Timestamp)
+			if err != nil || ts.After(*opts.Since) {
+				filtered = append(filtered, m)
+			}
+		}
+		window = filtered
+	}
+
+	if opts.Limit <= 0 || len(window) <= opts.Limit {
+		return window, false, nil
+	}
+
+	// Paging forwards: oldest first, so the client can keep following with after=
+	if opts.After != "" || opts.Since != nil {
+		return window[:opts.Limit], true, nil
+	}
+	// Default / paging backwards: the newest messages in the window
+	return window[len(window)-opts.Limit:], true, nil
+}
+
 // handleSessionHistory handles GET /api/history/:goal_id/:session_id - returns history for a specific session
 func handleSessionHistory(h *hub.Hub, goalID, sessionID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -2322,8 +4985,20 @@ func handleSessionHistory(h *hub.Hub, goalID, sessionID string) http.HandlerFunc
 
 // SendMessageRequest is the request body for POST /api/goals/:id/messages
 type SendMessageRequest struct {
-	Content string `json:"content"`
-	User    string `json:"user,omitempty"`
+	Content     string           `json:"content"`
+	Attachments []hub.Attachment `json:"attachments,omitempty"` // Uploaded files as {"type": "file", "id"}, links, snippets
+	User        string           `json:"user,omitempty"`
+}
+
+// MessagesStatusResponse is the response for GET /api/goals/:id/messages
+type MessagesStatusResponse struct {
+	HasPending bool               `json:"has_pending"`
+	Messages   []*hub.UserMessage `json:"messages"` // Recent messages with their delivery status
+}
+
+// AckMessagesRequest is the request body for POST /api/goals/:id/messages/ack
+type AckMessagesRequest struct {
+	IDs []string `json:"ids,omitempty"` // Empty acknowledges every delivered message
 }
 
 // PendingMessagesResponse is the response for GET /api/goals/:id/messages/pending
@@ -2361,18 +5036,19 @@ func handleSendMessage(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
-		if req.Content == "" {
+		if req.Content == "" && len(req.Attachments) == 0 {
 			http.Error(w, "Content is required", http.StatusBadRequest)
 			return
 		}
 
 		// Get user from header or request
-		user := r.Header.Get("X-Vega-User")
-		if user == "" {
-			user = req.User
-		}
+		user := actingUser(r, req.User)
 
-		msg := h.SendUserMessage(goalID, req.Content, user)
+		msg, err := h.SendUserMessageWithAttachments(goalID, req.Content, user, req.Attachments)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
 		log.Printf("[MESSAGE] User message sent to goal %s: %q (user: %s)", goalID, req.Content, user)
 
@@ -2384,20 +5060,104 @@ func handleSendMessage(h *hub.Hub, goalID string) http.HandlerFunc {
 	}
 }
 
-// handleCheckPendingMessages handles GET /api/goals/:id/messages - check pending message count
+// AddCommentRequest is the request body for POST /api/goals/:id/comments
+type AddCommentRequest struct {
+	Content  string `json:"content"`
+	ParentID string `json:"parent_id,omitempty"` // Reply to this comment
+	User     string `json:"user,omitempty"`      // Fallback when X-Vega-User is not set
+}
+
+// handleGoalComments handles GET/POST /api/goals/:id/comments - threaded user discussion
+func handleGoalComments(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			threads, err := h.GetCommentThreads(goalID)
+			if err != nil {
+				http.Error(w, "Failed to get comments: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(threads)
+
+		case http.MethodPost:
+			var req AddCommentRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			if strings.TrimSpace(req.Content) == "" {
+				http.Error(w, "Content is required", http.StatusBadRequest)
+				return
+			}
+
+			user := actingUser(r, req.User)
+
+			comment, err := h.AddComment(goalID, req.ParentID, user, req.Content)
+			if err != nil {
+				if errors.Is(err, hub.ErrCommentNotFound) {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				http.Error(w, "Failed to add comment: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			log.Printf("[COMMENT] Comment added to goal %s by %s (mentions: %v)", goalID, user, comment.Mentions)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(comment)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// handleCheckPendingMessages handles GET /api/goals/:id/messages - check for
+// pending messages and list recent ones with their delivery status
 func handleCheckPendingMessages(h *hub.Hub, goalID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		hasPending := h.HasPendingUserMessages(goalID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MessagesStatusResponse{
+			HasPending: h.HasPendingUserMessages(goalID),
+			Messages:   h.GetUserMessages(goalID),
+		})
+	}
+}
+
+// handleAckMessages handles POST /api/goals/:id/messages/ack - the executor
+// confirms it processed delivered messages
+func handleAckMessages(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req AckMessagesRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+
+		processed := h.AckUserMessages(goalID, req.IDs)
+		log.Printf("[MESSAGE] Executor of goal %s acknowledged %d messages", goalID, len(processed))
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"has_pending": hasPending,
+			"ok":       true,
+			"messages": processed,
 		})
 	}
 }
 
 // handleGetPendingMessages handles GET /api/goals/:id/messages/pending
-// Called by Stop hook to retrieve and clear pending messages
+// Called by Stop hook to retrieve pending messages and mark them delivered
+// (?session_id= names the executor session receiving them)
 // Returns decision for the Stop hook
 func handleGetPendingMessages(h *hub.Hub, goalID string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -2406,8 +5166,8 @@ func handleGetPendingMessages(h *hub.Hub, goalID string) http.HandlerFunc {
 			return
 		}
 
-		// Get and clear pending messages
-		messages := h.GetPendingUserMessages(goalID)
+		// Deliver pending messages
+		messages := h.DeliverUserMessages(goalID, r.URL.Query().Get("session_id"))
 
 		response := PendingMessagesResponse{
 			HasMessages: len(messages) > 0,
@@ -2423,6 +5183,10 @@ func handleGetPendingMessages(h *hub.Hub, goalID string) http.HandlerFunc {
 				} else {
 					contextParts = append(contextParts, fmt.Sprintf("[User]: %s", msg.Content))
 				}
+				// Uploaded files are passed by path for the executor to read
+				for _, att := range msg.Attachments {
+					contextParts = append(contextParts, att.Render())
+				}
 			}
 			context := strings.Join(contextParts, "\n")
 
@@ -2466,10 +5230,18 @@ func handleGetUser() http.HandlerFunc {
 }
 
 // handleUserRoutes handles /api/user/* routes
-func handleUserRoutes(p *goals.Parser) http.HandlerFunc {
+func handleUserRoutes(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Parse path: /api/user/credentials/:project
+		// Parse path: /api/user/credentials/:project, /api/user/preferences or /api/user/identity
 		path := strings.TrimPrefix(r.URL.Path, "/api/user/")
+		switch path {
+		case "preferences":
+			handleUserPreferences(h)(w, r)
+			return
+		case "identity":
+			handleUserIdentity(h)(w, r)
+			return
+		}
 		parts := strings.Split(path, "/")
 
 		if len(parts) < 2 || parts[0] != "credentials" {
@@ -2482,6 +5254,105 @@ func handleUserRoutes(p *goals.Parser) http.HandlerFunc {
 	}
 }
 
+// handleUserPreferences handles /api/user/preferences:
+//   - GET    - the user's preferences (defaults if none are saved)
+//   - PATCH  - update the fields present in the body
+//   - PUT    - replace all preferences with the body
+//   - DELETE - reset to the defaults
+func handleUserPreferences(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := requestUser(r)
+		store := h.Preferences()
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPatch, http.MethodPut:
+			body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				http.Error(w, "Failed to read body", http.StatusBadRequest)
+				return
+			}
+			update := store.Update
+			if r.Method == http.MethodPut {
+				update = store.Replace
+			}
+			if _, err := update(user, body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		case http.MethodDelete:
+			if err := store.Reset(user); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		prefs, err := store.Get(user)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(prefs)
+	}
+}
+
+// handleUserIdentity handles /api/user/identity - the git author and SSH key
+// the user's executors commit and push with (GET, PUT or DELETE)
+func handleUserIdentity(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := requestUser(r)
+		store := credentials.NewIdentityStore(h.Dir())
+
+		switch r.Method {
+		case http.MethodGet:
+			identity, err := store.Get(user)
+			if err != nil {
+				if errors.Is(err, credentials.ErrNoIdentity) {
+					http.Error(w, err.Error(), http.StatusNotFound)
+					return
+				}
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(identity)
+
+		case http.MethodPut:
+			var identity credentials.Identity
+			if err := json.NewDecoder(r.Body).Decode(&identity); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			identity.User = user
+			if err := store.Set(&identity); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(identity)
+
+		case http.MethodDelete:
+			if err := store.Delete(user); err != nil {
+				if errors.Is(err, credentials.ErrNoIdentity) {
+					http.Error(w, err.Error(), http.StatusNotFound)
+					return
+				}
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
 // handleGetCredentials handles GET /api/user/credentials/:project
 func handleGetCredentials(p *goals.Parser, project string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -2555,7 +5426,7 @@ func handleGetCredentials(p *goals.Parser, project string) http.HandlerFunc {
 // getBranchInfo returns git branch information for a goal's worktree
 // It first tries to read from goal metadata (stored in goal markdown file),
 // then falls back to filesystem scan if metadata is missing.
-func getBranchInfo(vegaDir, goalID string, projects []string) *BranchInfo {
+func getBranchInfo(git gitsvc.Service, vegaDir, goalID string, projects []string) *BranchInfo {
 	if len(projects) == 0 {
 		return nil
 	}
@@ -2566,6 +5437,14 @@ func getBranchInfo(vegaDir, goalID string, projects []string) *BranchInfo {
 		wt := detail.Worktree
 		worktreePath := filepath.Join(vegaDir, wt.Path)
 
+		// A worktree moved since it was recorded is looked up again, which
+		// also updates the metadata
+		if _, statErr := os.Stat(worktreePath); statErr != nil {
+			if moved, _ := findWorktreeForGoal(vegaDir, goalID, projects); moved != "" {
+				worktreePath = moved
+			}
+		}
+
 		// Check if the worktree actually exists on disk for live data
 		if _, statErr := os.Stat(worktreePath); statErr == nil {
 			info := &BranchInfo{
@@ -2575,9 +5454,9 @@ func getBranchInfo(vegaDir, goalID string, projects []string) *BranchInfo {
 			}
 
 			// Get live git data from the worktree
-			info.Ahead, info.Behind = getAheadBehind(worktreePath, info.BaseBranch)
-			info.UncommittedFiles = countUncommittedFiles(worktreePath)
-			info.LastCommit, info.LastCommitMsg = getLastCommit(worktreePath)
+			info.Ahead, info.Behind = git.AheadBehind(worktreePath, info.BaseBranch)
+			info.UncommittedFiles = len(git.UncommittedFiles(worktreePath))
+			info.LastCommit, info.LastCommitMsg = git.LastCommit(worktreePath)
 
 			return info
 		}
@@ -2602,10 +5481,10 @@ func getBranchInfo(vegaDir, goalID string, projects []string) *BranchInfo {
 	}
 
 	// Get current branch
-	info.Branch = getCurrentBranch(worktreePath)
+	info.Branch = git.CurrentBranch(worktreePath)
 
 	// Get base branch from project config
-	projectConfigPath := filepath.Join(vegaDir, "projects", project+".md")
+	projectConfigPath := layout.New(vegaDir).ProjectConfig(project)
 	if content, err := os.ReadFile(projectConfigPath); err == nil {
 		for _, line := range strings.Split(string(content), "\n") {
 			// Handle markdown formats: "Base Branch:", "**Base Branch**:", etc.
@@ -2627,122 +5506,28 @@ func getBranchInfo(vegaDir, goalID string, projects []string) *BranchInfo {
 	}
 
 	// Get ahead/behind counts
-	info.Ahead, info.Behind = getAheadBehind(worktreePath, info.BaseBranch)
+	info.Ahead, info.Behind = git.AheadBehind(worktreePath, info.BaseBranch)
 
 	// Count uncommitted files
-	info.UncommittedFiles = countUncommittedFiles(worktreePath)
+	info.UncommittedFiles = len(git.UncommittedFiles(worktreePath))
 
 	// Get last commit
-	info.LastCommit, info.LastCommitMsg = getLastCommit(worktreePath)
+	info.LastCommit, info.LastCommitMsg = git.LastCommit(worktreePath)
 
 	return info
 }
 
-// findWorktreeForGoal searches for a worktree matching the goal ID
+// findWorktreeForGoal returns the goal's worktree and its project, trying
+// each project in turn (see operations.FindGoalWorktree)
 func findWorktreeForGoal(vegaDir, goalID string, projects []string) (string, string) {
-	workspacesDir := filepath.Join(vegaDir, "workspaces")
-	goalPrefix := fmt.Sprintf("goal-%s-", goalID)
-
-	// Check each project
 	for _, project := range projects {
-		projectPath := filepath.Join(workspacesDir, project)
-		entries, err := os.ReadDir(projectPath)
-		if err != nil {
-			continue
-		}
-
-		for _, entry := range entries {
-			if entry.IsDir() && strings.HasPrefix(entry.Name(), goalPrefix) {
-				return filepath.Join(projectPath, entry.Name()), project
-			}
+		if path, err := operations.FindGoalWorktree(vegaDir, project, goalID); err == nil {
+			return path, project
 		}
 	}
-
-	return "", ""
-}
-
-// getCurrentBranch returns the current branch name
-func getCurrentBranch(repoPath string) string {
-	cmd := exec.Command("git", "-C", repoPath, "branch", "--show-current")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
-}
-
-// getAheadBehind returns ahead and behind counts relative to base branch
-func getAheadBehind(repoPath, baseBranch string) (int, int) {
-	// Try with origin/ prefix first
-	cmd := exec.Command("git", "-C", repoPath, "rev-list", "--left-right", "--count", baseBranch+"...HEAD")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, 0
-	}
-
-	parts := strings.Fields(strings.TrimSpace(string(output)))
-	if len(parts) != 2 {
-		return 0, 0
-	}
-
-	behind := 0
-	ahead := 0
-	fmt.Sscanf(parts[0], "%d", &behind)
-	fmt.Sscanf(parts[1], "%d", &ahead)
-
-	return ahead, behind
-}
-
-// countUncommittedFiles returns the number of uncommitted files
-func countUncommittedFiles(repoPath string) int {
-	cmd := exec.Command("git", "-C", repoPath, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return 0
-	}
-	return len(lines)
-}
-
-// getLastCommit returns the last commit hash and message
-func getLastCommit(repoPath string) (string, string) {
-	cmd := exec.Command("git", "-C", repoPath, "log", "-1", "--format=%H|%s")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", ""
-	}
-
-	parts := strings.SplitN(strings.TrimSpace(string(output)), "|", 2)
-	if len(parts) == 2 {
-		return parts[0], parts[1]
-	}
 	return "", ""
 }
 
-// checkBranchExists checks if a branch exists locally, remotely, or is missing
-// Returns: "local", "remote_only", "missing"
-func checkBranchExists(repoPath, branchName string) string {
-	// Check local branch
-	cmd := exec.Command("git", "-C", repoPath, "branch", "--list", branchName)
-	output, err := cmd.Output()
-	if err == nil && strings.TrimSpace(string(output)) != "" {
-		return "local"
-	}
-
-	// Check remote branch
-	cmd = exec.Command("git", "-C", repoPath, "ls-remote", "--heads", "origin", branchName)
-	output, err = cmd.Output()
-	if err == nil && strings.TrimSpace(string(output)) != "" {
-		return "remote_only"
-	}
-
-	return "missing"
-}
-
 // RecreateWorktreeRequest is the request body for POST /api/goals/:id/recreate-worktree
 type RecreateWorktreeRequest struct {
 	Project string `json:"project,omitempty"` // Optional, defaults to goal's first project
@@ -2812,7 +5597,7 @@ func handleRecreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Han
 		}
 
 		// Get paths
-		projectBase := filepath.Join(p.Dir(), "workspaces", project, "worktree-base")
+		projectBase := p.Layout().WorktreeBase(project)
 		worktreePath := filepath.Join(p.Dir(), detail.Worktree.Path)
 		branchName := detail.Worktree.Branch
 
@@ -2839,7 +5624,8 @@ func handleRecreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Han
 		}
 
 		// Check if branch exists
-		branchStatus := checkBranchExists(projectBase, branchName)
+		// Uncached: the worktree is recreated based on the answer
+		branchStatus := gitsvc.NewExec().BranchExists(projectBase, branchName)
 		if branchStatus == "missing" {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusBadRequest)
@@ -2890,8 +5676,13 @@ func handleRecreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Han
 			return
 		}
 
+		// Large repositories only check out the project's sparse paths
+		if err := operations.SparseCheckoutWorktree(p.Dir(), project, worktreePath); err != nil {
+			log.Printf("[RECREATE-WORKTREE] Warning: %v", err)
+		}
+
 		// Copy hooks to the new worktree
-		copyHooksToWorktree(p.Dir(), worktreePath)
+		operations.CopyHooksToWorktree(p.Dir(), worktreePath)
 
 		log.Printf("[RECREATE-WORKTREE] Successfully recreated worktree for goal %s at %s", goalID, worktreePath)
 
@@ -2961,7 +5752,7 @@ func handleCreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Handl
 		}
 
 		// Get project base path
-		projectBase := filepath.Join(p.Dir(), "workspaces", project, "worktree-base")
+		projectBase := p.Layout().WorktreeBase(project)
 
 		// Verify projectBase exists
 		if _, err := os.Stat(projectBase); os.IsNotExist(err) {
@@ -2994,8 +5785,9 @@ func handleCreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Handl
 		if slug == "" {
 			slug = "work"
 		}
-		branchName := fmt.Sprintf("goal-%s-%s", goalID, slug)
-		worktreePath := filepath.Join(p.Dir(), "workspaces", project, branchName)
+		worktreeName := fmt.Sprintf("goal-%s-%s", goalID, slug)
+		branchName := goals.IssueBranch(detail.TrackerID, worktreeName)
+		worktreePath := p.Layout().Workspace(project, worktreeName)
 
 		// Check if worktree path already exists
 		if _, err := os.Stat(worktreePath); err == nil {
@@ -3010,7 +5802,7 @@ func handleCreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Handl
 
 		// Get base branch from project config
 		baseBranch := "main"
-		projectConfigPath := filepath.Join(p.Dir(), "projects", project+".md")
+		projectConfigPath := p.Layout().ProjectConfig(project)
 		if content, err := os.ReadFile(projectConfigPath); err == nil {
 			for _, line := range strings.Split(string(content), "\n") {
 				if strings.Contains(strings.ToLower(line), "base branch") {
@@ -3045,10 +5837,15 @@ func handleCreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Handl
 			return
 		}
 
+		// Large repositories only check out the project's sparse paths
+		if err := operations.SparseCheckoutWorktree(p.Dir(), project, worktreePath); err != nil {
+			log.Printf("[CREATE-WORKTREE] Warning: %v", err)
+		}
+
 		// Write worktree metadata to goal file
-		goalFilePath := filepath.Join(p.Dir(), "goals", "active", goalID+".md")
+		goalFilePath := p.Layout().FindGoalFileIn(layout.ActiveDir, goalID)
 		worktreeSection := fmt.Sprintf("\n## Worktree\n- **Branch**: %s\n- **Project**: %s\n- **Path**: workspaces/%s/%s\n- **Base Branch**: %s\n- **Created**: %s\n",
-			branchName, project, project, branchName, baseBranch, time.Now().Format("2006-01-02"))
+			branchName, project, project, worktreeName, baseBranch, time.Now().Format("2006-01-02"))
 
 		// Read existing content and insert before Status section
 		content, err := os.ReadFile(goalFilePath)
@@ -3063,7 +5860,7 @@ func handleCreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Handl
 		}
 
 		// Copy hooks to the new worktree
-		copyHooksToWorktree(p.Dir(), worktreePath)
+		operations.CopyHooksToWorktree(p.Dir(), worktreePath)
 
 		log.Printf("[CREATE-WORKTREE] Successfully created worktree for goal %s at %s", goalID, worktreePath)
 
@@ -3083,48 +5880,69 @@ func handleCreateWorktree(h *hub.Hub, p *goals.Parser, goalID string) http.Handl
 	}
 }
 
-// copyHooksToWorktree copies vega-missile hooks to a worktree
-func copyHooksToWorktree(vegaDir, worktreePath string) {
-	// Copy hooks from template
-	templateHooks := filepath.Join(vegaDir, "templates", "project-init", ".claude", "hooks")
-	destHooks := filepath.Join(worktreePath, ".claude", "hooks")
+// UpgradeHooksRequest is the optional request body for POST /api/worktrees/upgrade-hooks
+type UpgradeHooksRequest struct {
+	GoalID string `json:"goal_id,omitempty"` // Only this goal's worktree
+	DryRun bool   `json:"dry_run,omitempty"`
+	Force  bool   `json:"force,omitempty"` // Overwrite locally edited hook files
+	Diff   bool   `json:"diff,omitempty"`
+}
 
-	os.MkdirAll(destHooks, 0755)
+// handleUpgradeHooks handles POST /api/worktrees/upgrade-hooks - re-syncs the
+// project-init hooks, rules and settings into existing worktrees
+func handleUpgradeHooks(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-	// Copy each hook file
-	files, _ := os.ReadDir(templateHooks)
-	for _, f := range files {
-		src := filepath.Join(templateHooks, f.Name())
-		dst := filepath.Join(destHooks, f.Name())
-		if content, err := os.ReadFile(src); err == nil {
-			os.WriteFile(dst, content, 0755)
+		var req UpgradeHooksRequest
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
 		}
-	}
 
-	// Also copy rules
-	templateRules := filepath.Join(vegaDir, "templates", "project-init", ".claude", "rules")
-	destRules := filepath.Join(worktreePath, ".claude", "rules")
-	os.MkdirAll(destRules, 0755)
+		result, data := operations.UpgradeHooks(operations.UpgradeHooksOptions{
+			GoalID:  req.GoalID,
+			DryRun:  req.DryRun,
+			Force:   req.Force,
+			Diff:    req.Diff,
+			VegaDir: h.Dir(),
+		})
 
-	// Recursively copy rules
-	filepath.Walk(templateRules, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			switch result.Error.Code {
+			case "worktree_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "no_templates":
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   result.Error,
+			})
+			return
 		}
-		rel, _ := filepath.Rel(templateRules, path)
-		dst := filepath.Join(destRules, rel)
-		os.MkdirAll(filepath.Dir(dst), 0755)
-		if content, err := os.ReadFile(path); err == nil {
-			os.WriteFile(dst, content, 0644)
+
+		if !data.DryRun && data.Upgraded > 0 {
+			log.Printf("[HOOKS] Upgraded %d worktree(s) to hooks %s", data.Upgraded, data.TemplateVersion)
+			h.EmitEvent("hooks_upgraded", map[string]interface{}{
+				"version":  data.TemplateVersion,
+				"upgraded": data.Upgraded,
+				"failed":   data.Failed,
+			})
 		}
-		return nil
-	})
 
-	// Copy settings.local.json
-	templateSettings := filepath.Join(vegaDir, "templates", "project-init", ".claude", "settings.local.json")
-	destSettings := filepath.Join(worktreePath, ".claude", "settings.local.json")
-	if content, err := os.ReadFile(templateSettings); err == nil {
-		os.WriteFile(destSettings, content, 0644)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+		})
 	}
 }
 
@@ -3648,17 +6466,14 @@ func handleGoalPhases(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFu
 				return
 			}
 
-			// Get goal file path (construct it)
-			goalFilePath := filepath.Join(p.Dir(), "goals", "active", goalID, goalID+".md")
-			
-			// Check if file exists, try alternative locations
-			if _, err := os.Stat(goalFilePath); os.IsNotExist(err) {
-				// Try iced location
-				goalFilePath = filepath.Join(p.Dir(), "goals", "iced", goalID, goalID+".md")
-				if _, err := os.Stat(goalFilePath); os.IsNotExist(err) {
-					http.Error(w, "Goal file not found", http.StatusNotFound)
-					return
-				}
+			// Get goal file path, active or iced
+			goalFilePath := p.Layout().FindGoalFileIn(layout.ActiveDir, goalID)
+			if goalFilePath == "" {
+				goalFilePath = p.Layout().FindGoalFileIn(layout.IcedDir, goalID)
+			}
+			if goalFilePath == "" {
+				http.Error(w, "Goal file not found", http.StatusNotFound)
+				return
 			}
 
 			// Update the phase in goal.md
@@ -2,16 +2,24 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/lasmarois/vega-hub/internal/credentials"
 	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/jobs"
+	"github.com/lasmarois/vega-hub/internal/operations"
 )
 
 func setupTestEnv(t *testing.T) (*hub.Hub, *goals.Parser, string) {
@@ -148,6 +156,84 @@ func TestHandleHealth_WithStuckGoals(t *testing.T) {
 	}
 }
 
+func TestHandleHealth_StuckThresholds(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+
+	// Branching for 2 hours, with branching allowed 3
+	event := goals.StateEvent{Timestamp: time.Now().UTC().Add(-2 * time.Hour), State: goals.StateBranching}
+	data, _ := json.Marshal(event)
+	os.WriteFile(filepath.Join(dir, "goals", "active", "abc1234.state.jsonl"), append(data, '\n'), 0644)
+	os.WriteFile(filepath.Join(dir, ".vega-hub-stuck.json"), []byte(`{"default": "30m", "states": {"branching": "3h"}}`), 0644)
+
+	health := func(query string) (int, HealthResponse) {
+		w := httptest.NewRecorder()
+		handleHealth(h)(w, httptest.NewRequest("GET", "/api/health"+query, nil))
+		var response HealthResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	_, response := health("")
+	if response.Status != "ok" || response.StuckGoals != nil {
+		t.Errorf("expected no stuck goals within the branching threshold, got %+v", response)
+	}
+	if response.StuckThresholds["branching"] != "3h0m0s" || response.StuckThresholds["merging"] != "30m0s" {
+		t.Errorf("unexpected thresholds %v", response.StuckThresholds)
+	}
+
+	_, response = health("?threshold=1h")
+	if response.Status != "degraded" || response.StuckGoals == nil || response.StuckGoals.Goals[0].Threshold != "1h0m0s" {
+		t.Errorf("expected the goal stuck past the 1h override, got %+v", response)
+	}
+	if code, _ := health("?threshold=soon"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid threshold, got %d", code)
+	}
+
+	// Only transient states take a threshold
+	os.WriteFile(filepath.Join(dir, ".vega-hub-stuck.json"), []byte(`{"states": {"working": "1h"}}`), 0644)
+	if _, err := hub.LoadStuckConfig(dir); err == nil {
+		t.Error("expected an error for a threshold on working")
+	}
+}
+
+func TestHandleHealth_LiveAndReady(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+
+	ready := func() (int, map[string]hub.DependencyStatus) {
+		w := httptest.NewRecorder()
+		handleHealthReady(h)(w, httptest.NewRequest("GET", "/api/health/ready", nil))
+		var report hub.ReadinessReport
+		json.Unmarshal(w.Body.Bytes(), &report)
+		deps := make(map[string]hub.DependencyStatus)
+		for _, d := range report.Dependencies {
+			deps[d.Name] = d
+		}
+		return w.Code, deps
+	}
+
+	// The file watcher isn't started in tests, so the hub is never ready
+	code, deps := ready()
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without the file watcher, got %d", code)
+	}
+	if deps[hub.DependencyWatcher].Status != hub.DependencyDown || deps[hub.DependencyVegaDir].Status != hub.DependencyOK || deps[hub.DependencyStore].Status != hub.DependencyOK {
+		t.Errorf("unexpected dependencies %+v", deps)
+	}
+
+	// A missing goals directory is reported as such, while the process stays live
+	os.RemoveAll(filepath.Join(dir, "goals"))
+	if _, deps = ready(); deps[hub.DependencyVegaDir].Status != hub.DependencyDown || deps[hub.DependencyVegaDir].Error == "" {
+		t.Errorf("expected vega_dir down, got %+v", deps[hub.DependencyVegaDir])
+	}
+	w := httptest.NewRecorder()
+	handleHealthLive(h)(w, httptest.NewRequest("GET", "/api/health/live", nil))
+	var live LivenessResponse
+	json.Unmarshal(w.Body.Bytes(), &live)
+	if w.Code != http.StatusOK || live.Status != "ok" || live.StartedAt.IsZero() {
+		t.Errorf("expected the hub live, got %d %+v", w.Code, live)
+	}
+}
+
 func TestHandleQuestions_Empty(t *testing.T) {
 	h, _, _ := setupTestEnv(t)
 
@@ -202,6 +288,157 @@ func TestHandleExecutors_Empty(t *testing.T) {
 	}
 }
 
+func TestHandleStateHooks(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	// Shell commands can't be configured over the API
+	body := `{"id":"freeze","to":["merging"],"command":"exit 1","veto":true}`
+	req := httptest.NewRequest("POST", "/api/state-hooks", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	handleStateHooks(h)(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 for a command hook, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body = `{"id":"freeze","to":["merging"],"url":"http://localhost:9/check","veto":true}`
+	req = httptest.NewRequest("POST", "/api/state-hooks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+	handleStateHooks(h)(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/api/state-hooks", bytes.NewBufferString(`{"to":["merging"]}`))
+	w = httptest.NewRecorder()
+	handleStateHooks(h)(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for hook without action, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/state-hooks", nil)
+	w = httptest.NewRecorder()
+	handleStateHooks(h)(w, req)
+	var hooks []*goals.TransitionHook
+	json.Unmarshal(w.Body.Bytes(), &hooks)
+	if len(hooks) != 1 || hooks[0].ID != "freeze" || !hooks[0].Veto {
+		t.Errorf("unexpected hooks: %+v", hooks)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/state-hooks/freeze", nil)
+	w = httptest.NewRecorder()
+	handleStateHook(h)(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handleStateHook(h)(w, httptest.NewRequest("DELETE", "/api/state-hooks/freeze", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+
+	// Command hooks from the hooks file stay put
+	h.StateManager().Hooks().Add(&goals.TransitionHook{ID: "lint", Command: "make lint", Veto: true})
+	w = httptest.NewRecorder()
+	handleStateHook(h)(w, httptest.NewRequest("DELETE", "/api/state-hooks/lint", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 for a command hook, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalStateTransition(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/goals/abc1234/state", bytes.NewBufferString(body))
+		req.Header.Set("X-Vega-User", "alice")
+		w := httptest.NewRecorder()
+		handleGoalState(h, p, "abc1234")(w, req)
+		return w
+	}
+
+	w := post(`{"state":"working","reason":"start"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp GoalStateResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.State != goals.StateWorking || resp.LastEvent == nil || resp.LastEvent.User != "alice" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	w = post(`{"state":"done"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	var conflict StateTransitionErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &conflict)
+	if conflict.From != goals.StateWorking || conflict.To != goals.StateDone || len(conflict.Allowed) == 0 {
+		t.Errorf("unexpected conflict: %+v", conflict)
+	}
+
+	if w := post(`{"state":"done","reason":"merged by hand","force":true}`); w.Code != http.StatusOK {
+		t.Errorf("expected forced transition to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if state, _ := h.StateManager().GetState("abc1234"); state != goals.StateDone {
+		t.Errorf("expected done, got %s", state)
+	}
+
+	if w := post(`{"state":"bogus"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown state, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/api/goals/zzz9999/state", bytes.NewBufferString(`{"state":"working"}`))
+	w = httptest.NewRecorder()
+	handleGoalState(h, p, "zzz9999")(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown goal, got %d", w.Code)
+	}
+}
+
+func TestHandleRepairProject(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	w := httptest.NewRecorder()
+	handleProjectRoutes(h, p)(w, httptest.NewRequest("GET", "/api/projects/test-project/repair", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleProjectRoutes(h, p)(w, httptest.NewRequest("POST", "/api/projects/nope/repair", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handleProjectRoutes(h, p)(w, httptest.NewRequest("POST", "/api/projects/nope/explode", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown action, got %d", w.Code)
+	}
+}
+
+func TestHandleWorkers(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+
+	config := `{"workers":[{"name":"build","host":"build.lan","vega_dir":"/srv/vega"}]}`
+	if err := os.WriteFile(filepath.Join(dir, ".vega-hub-workers.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/workers", nil)
+	w := httptest.NewRecorder()
+	handleWorkers(h)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var workers []*hub.Worker
+	json.Unmarshal(w.Body.Bytes(), &workers)
+	if len(workers) != 1 || workers[0].Name != "build" || workers[0].VegaDir != "/srv/vega" {
+		t.Errorf("unexpected workers: %+v", workers)
+	}
+}
+
 func TestHandleExecutorRegister(t *testing.T) {
 	h, _, _ := setupTestEnv(t)
 
@@ -259,6 +496,30 @@ func TestHandleExecutorStop(t *testing.T) {
 	}
 }
 
+func TestHandleWatcherStatus(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	w := httptest.NewRecorder()
+	handleWatcherStatus(h)(w, httptest.NewRequest("GET", "/api/watcher", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var status hub.WatcherStatus
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if status.Running {
+		t.Error("expected watcher not to be running")
+	}
+	if len(status.Ignore) == 0 || status.Debounce == "" {
+		t.Errorf("expected ignore patterns and debounce, got %+v", status)
+	}
+
+	w = httptest.NewRecorder()
+	handleWatcherStatus(h)(w, httptest.NewRequest("POST", "/api/watcher", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
 func TestHandleGoals(t *testing.T) {
 	h, p, _ := setupTestEnv(t)
 
@@ -311,6 +572,40 @@ func TestHandleGoals_WithExecutor(t *testing.T) {
 	}
 }
 
+func TestHandleGoals_ManyGoalsKeepRegistryOrder(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+
+	var registry strings.Builder
+	var ids []string
+	for i := 0; i < 40; i++ {
+		id := fmt.Sprintf("a%06x", i)
+		ids = append(ids, id)
+		fmt.Fprintf(&registry, `{"id":%q,"title":"Goal %d","projects":["test-project"],"status":"active","phase":"1/1"}`+"\n", id, i)
+		goal := fmt.Sprintf("# Goal #%s: Goal %d\n\n## Phases\n\n### Phase 1: Work\n- [x] Done\n- **Status:** complete\n", id, i)
+		os.WriteFile(filepath.Join(dir, "goals", "active", id+".md"), []byte(goal), 0644)
+	}
+	os.WriteFile(filepath.Join(dir, "goals", "registry.jsonl"), []byte(registry.String()), 0644)
+
+	for round := 0; round < 2; round++ {
+		w := httptest.NewRecorder()
+		handleGoals(h, p)(w, httptest.NewRequest("GET", "/api/goals", nil))
+
+		var summaries []GoalSummary
+		json.Unmarshal(w.Body.Bytes(), &summaries)
+		if len(summaries) != len(ids) {
+			t.Fatalf("expected %d goals, got %d", len(ids), len(summaries))
+		}
+		for i, s := range summaries {
+			if s.ID != ids[i] {
+				t.Fatalf("summary %d: expected %s, got %s", i, ids[i], s.ID)
+			}
+			if s.CompletionStatus == nil || !s.CompletionStatus.Complete {
+				t.Errorf("goal %s: expected complete status, got %+v", s.ID, s.CompletionStatus)
+			}
+		}
+	}
+}
+
 func TestHandleAnswer_NotFound(t *testing.T) {
 	h, _, _ := setupTestEnv(t)
 
@@ -342,6 +637,286 @@ func TestHandleAnswer_MissingID(t *testing.T) {
 	}
 }
 
+func TestHandleAnswer_ClaimConflict(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	done := make(chan string)
+	go func() {
+		done <- h.Ask(&hub.Question{ID: "q-claim", GoalID: "abc1234", SessionID: "s1", Question: "Proceed?"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	post := func(path, user, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("X-Vega-User", user)
+		w := httptest.NewRecorder()
+		handleAnswer(h)(w, req)
+		return w
+	}
+
+	if w := post("/api/answer/q-claim/claim", "alice", `{"draft": "yes"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected claim to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := post("/api/answer/q-claim/claim", "bob", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for competing claim, got %d", w.Code)
+	}
+	var conflict ConflictResponse
+	json.NewDecoder(w.Body).Decode(&conflict)
+	if conflict.Reason != "claimed" || conflict.User != "alice" {
+		t.Errorf("unexpected conflict response: %+v", conflict)
+	}
+
+	if w := post("/api/answer/q-claim", "bob", `{"answer": "no"}`); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for answer while claimed, got %d", w.Code)
+	}
+	if w := post("/api/answer/q-claim", "alice", `{"answer": "yes"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected claimant's answer to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if answer := <-done; answer != "yes" {
+		t.Errorf("expected answer 'yes', got %q", answer)
+	}
+
+	// Late answers get a conflict naming who answered
+	w = post("/api/answer/q-claim", "bob", `{"answer": "no"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for late answer, got %d", w.Code)
+	}
+	json.NewDecoder(w.Body).Decode(&conflict)
+	if conflict.Reason != "answered" || conflict.User != "alice" {
+		t.Errorf("unexpected late-answer response: %+v", conflict)
+	}
+}
+
+func TestHandleAnswers(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	h.SetAnswerGrace(time.Minute)
+
+	done := make(chan string, 1)
+	go func() {
+		done <- h.Ask(&hub.Question{ID: "q-edit", GoalID: "abc1234", SessionID: "s1", Question: "Proceed?"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	do := func(method, path, user, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("X-Vega-User", user)
+		w := httptest.NewRecorder()
+		if strings.HasPrefix(path, "/api/answers/") {
+			handleAnswers(h)(w, req)
+		} else {
+			handleAnswer(h)(w, req)
+		}
+		return w
+	}
+	status := func(w *httptest.ResponseRecorder) hub.AnswerRecord {
+		var rec hub.AnswerRecord
+		if err := json.NewDecoder(w.Body).Decode(&rec); err != nil {
+			t.Fatalf("failed to decode answer record: %v", err)
+		}
+		return rec
+	}
+
+	if rec := status(do("GET", "/api/answers/q-edit", "", "")); rec.Status != hub.AnswerUnanswered {
+		t.Errorf("expected unanswered, got %q", rec.Status)
+	}
+	if w := do("POST", "/api/answer/q-edit", "alice", `{"answer": "yes"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected answer to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("PATCH", "/api/answers/q-edit", "bob", `{"answer": "no"}`); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 editing another user's answer, got %d", w.Code)
+	}
+
+	w := do("PATCH", "/api/answers/q-edit", "alice", `{"answer": "yes, carefully"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected edit to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	rec := status(w)
+	if rec.Status != hub.AnswerScheduled || rec.Answer != "yes, carefully" || len(rec.Drafts) != 1 {
+		t.Errorf("unexpected record after edit: %+v", rec)
+	}
+
+	w = do("DELETE", "/api/answers/q-edit", "alice", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected retract to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if rec := status(w); rec.Status != hub.AnswerUnanswered || len(rec.Drafts) != 2 {
+		t.Errorf("unexpected record after retract: %+v", rec)
+	}
+	if w := do("DELETE", "/api/answers/q-edit", "alice", ""); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 retracting with no answer, got %d", w.Code)
+	}
+	if w := do("GET", "/api/answers/missing", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown question, got %d", w.Code)
+	}
+
+	// Without a grace period answers are delivered at once and can't be changed
+	h.SetAnswerGrace(0)
+	if w := do("POST", "/api/answer/q-edit", "alice", `{"answer": "no"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected answer to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if answer := <-done; answer != "no" {
+		t.Errorf("expected answer 'no', got %q", answer)
+	}
+	if rec := status(do("GET", "/api/answers/q-edit", "", "")); rec.Status != hub.AnswerDelivered || rec.DeliveredAt == nil {
+		t.Errorf("expected delivered status, got %+v", rec)
+	}
+	if w := do("PATCH", "/api/answers/q-edit", "alice", `{"answer": "yes"}`); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 editing a delivered answer, got %d", w.Code)
+	}
+}
+
+func TestHandleBatchAnswer(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	done := make(chan string, 2)
+	for _, id := range []string{"q-batch-1", "q-batch-2"} {
+		go func(id string) {
+			done <- h.Ask(&hub.Question{ID: id, GoalID: "abc1234", SessionID: "s1", Question: "Proceed with " + id + "?"})
+		}(id)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(h.GetPendingQuestions()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := h.ClaimQuestion("q-batch-2", "bob", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"answers": [
+		{"question_id": "q-batch-1", "answer": "yes"},
+		{"question_id": "q-batch-2", "answer": "no"},
+		{"question_id": "missing", "answer": "maybe"},
+		{"answer": "no ID"}
+	]}`
+	req := httptest.NewRequest("POST", "/api/answers", bytes.NewBufferString(body))
+	req.Header.Set("X-Vega-User", "alice")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp BatchAnswerResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Answered != 1 || resp.Failed != 3 || len(resp.Results) != 4 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	wantStatus := []int{http.StatusOK, http.StatusConflict, http.StatusNotFound, http.StatusBadRequest}
+	for i, result := range resp.Results {
+		if result.Status != wantStatus[i] || result.OK != (wantStatus[i] == http.StatusOK) {
+			t.Errorf("result %d: %+v, want status %d", i, result, wantStatus[i])
+		}
+	}
+	if c := resp.Results[1].Conflict; c == nil || c.Reason != "claimed" || c.User != "bob" {
+		t.Errorf("expected the claim conflict to name bob: %+v", resp.Results[1])
+	}
+	if answer := <-done; answer != "yes" {
+		t.Errorf("expected answer 'yes', got %q", answer)
+	}
+
+	for _, body := range []string{`{"answers": []}`, `not json`} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/answers", bytes.NewBufferString(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/answers", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalClone(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(dir, "projects", "test-project.md"), []byte("# Project: test-project\n\n**Base Branch**: `main`\n"), 0644)
+
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/clone", bytes.NewBufferString(`{"title": "Test goal again", "no_worktree": true}`))
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, goals.NewParser(dir))(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data operations.CreateResult `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Data.ClonedFrom != "abc1234" || resp.Data.Project != "test-project" {
+		t.Errorf("unexpected clone result: %+v", resp.Data)
+	}
+	content, err := os.ReadFile(resp.Data.GoalFile)
+	if err != nil {
+		t.Fatalf("cloned goal file missing: %v", err)
+	}
+	if !strings.Contains(string(content), "Test goal again") || !strings.Contains(string(content), "- [ ] Task one") {
+		t.Errorf("unexpected cloned goal file:\n%s", content)
+	}
+
+	req = httptest.NewRequest("POST", "/api/goals/fffffff/clone", nil)
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, goals.NewParser(dir))(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown goal, got %d", w.Code)
+	}
+}
+
+func TestHandleCompletedGoals(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+	os.MkdirAll(filepath.Join(dir, "goals", "history", "archive"), 0755)
+	goal := "# Goal #%s: %s\n\n## Project(s)\n\n- **test-project**: work\n\n## Worktree\n- **Branch**: goal-%s-x\n- **Path**: workspaces/test-project/goal-%s-x\n- **Base Branch**: main\n"
+	os.WriteFile(filepath.Join(dir, "goals", "history", "def5678.md"), []byte(fmt.Sprintf(goal, "def5678", "Done recently", "def5678", "def5678")), 0644)
+	os.WriteFile(filepath.Join(dir, "goals", "history", "archive", "0ld0001.md"), []byte(fmt.Sprintf(goal, "0ld0001", "Done long ago", "0ld0001", "0ld0001")), 0644)
+	old := time.Now().AddDate(-1, 0, 0)
+	os.Chtimes(filepath.Join(dir, "goals", "history", "archive", "0ld0001.md"), old, old)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handleHistoryRoutes(h)(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	var resp CompletedGoalsResponse
+	w := get("/api/history/goals?limit=1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Total != 2 || !resp.HasMore || len(resp.Goals) != 1 || resp.Goals[0].ID != "def5678" {
+		t.Fatalf("unexpected first page: %+v", resp)
+	}
+	resp = CompletedGoalsResponse{}
+	json.NewDecoder(get("/api/history/goals?limit=1&offset=1").Body).Decode(&resp)
+	if resp.HasMore || len(resp.Goals) != 1 || resp.Goals[0].ID != "0ld0001" || !resp.Goals[0].Archived {
+		t.Errorf("unexpected second page: %+v", resp)
+	}
+	if w := get("/api/history/goals?offset=-1"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for negative offset, got %d", w.Code)
+	}
+
+	// Archived goals have full detail: branch from metadata, done state
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, goals.NewParser(dir))(w, httptest.NewRequest("GET", "/api/goals/0ld0001", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected archived goal detail, got %d: %s", w.Code, w.Body.String())
+	}
+	var detail GoalDetailResponse
+	json.NewDecoder(w.Body).Decode(&detail)
+	if detail.Status != "completed" || !detail.Archived || detail.State != "done" || detail.WorktreeStatus != "removed" {
+		t.Errorf("unexpected archived goal detail: status=%q archived=%v state=%q worktree=%q",
+			detail.Status, detail.Archived, detail.State, detail.WorktreeStatus)
+	}
+	if detail.BranchInfo == nil || detail.BranchInfo.Branch != "goal-0ld0001-x" || detail.CanRecreate {
+		t.Errorf("unexpected branch info: %+v (can_recreate=%v)", detail.BranchInfo, detail.CanRecreate)
+	}
+}
+
 func TestCorsMiddleware(t *testing.T) {
 	innerHandler := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -382,6 +957,16 @@ func TestGenerateID(t *testing.T) {
 	if len(id1) < 15 {
 		t.Errorf("expected ID length >= 15, got %d", len(id1))
 	}
+
+	// IDs generated in the same millisecond must differ
+	seen := map[string]bool{id1: true}
+	for i := 0; i < 100; i++ {
+		id := generateID()
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
 }
 
 func TestHandleGoalOutput(t *testing.T) {
@@ -534,33 +1119,329 @@ func TestHandleGoalChat_Limit(t *testing.T) {
 	}
 }
 
-func TestHandleGoalChat_MethodNotAllowed(t *testing.T) {
+func TestHandleGoalChat_CursorPagination(t *testing.T) {
 	h, _, _ := setupTestEnv(t)
 
-	req := httptest.NewRequest("POST", "/api/goals/abc1234/chat", nil)
-	w := httptest.NewRecorder()
+	// Create 3 sessions = 6 history messages
+	for _, sid := range []string{"session-001", "session-002", "session-003"} {
+		h.RegisterExecutor("abc1234", sid, "/path", "user")
+		h.StopExecutor("abc1234", sid, "done")
+	}
 
-	handler := handleGoalChat(h, "abc1234")
-	handler(w, req)
+	fetch := func(query string) ([]ChatMessage, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest("GET", "/api/goals/abc1234/chat?"+query, nil)
+		w := httptest.NewRecorder()
+		handleGoalChat(h, "abc1234")(w, req)
+		var messages []ChatMessage
+		json.Unmarshal(w.Body.Bytes(), &messages)
+		return messages, w
+	}
+	all, _ := fetch("")
+	if len(all) != 6 {
+		t.Fatalf("expected 6 messages, got %d", len(all))
+	}
+	ids := make([]string, len(all))
+	for i, m := range all {
+		ids[i] = m.ID
+	}
+	expect := func(messages []ChatMessage, want ...string) {
+		t.Helper()
+		got := make([]string, len(messages))
+		for i, m := range messages {
+			got[i] = m.ID
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
 
-	if w.Code != http.StatusMethodNotAllowed {
-		t.Errorf("expected status 405, got %d", w.Code)
+	// Latest page
+	messages, w := fetch("limit=2")
+	expect(messages, ids[4], ids[5])
+	if w.Header().Get("X-Has-More") != "true" {
+		t.Errorf("expected X-Has-More true, got %q", w.Header().Get("X-Has-More"))
+	}
+	if w.Header().Get("X-Total-Count") != "6" {
+		t.Errorf("expected X-Total-Count 6, got %q", w.Header().Get("X-Total-Count"))
 	}
-}
 
-func TestHandleSendMessage(t *testing.T) {
-	h, _, _ := setupTestEnv(t)
+	// Page backwards
+	messages, _ = fetch("limit=2&before=" + ids[4])
+	expect(messages, ids[2], ids[3])
 
-	body := `{"content": "Hello executor!", "user": "testuser"}`
-	req := httptest.NewRequest("POST", "/api/goals/abc1234/messages", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	// Page forwards
+	messages, w = fetch("limit=2&after=" + ids[1])
+	expect(messages, ids[2], ids[3])
+	if w.Header().Get("X-Has-More") != "true" {
+		t.Errorf("expected more messages after %s", ids[3])
+	}
 
-	handler := handleGoalMessages(h, "abc1234")
-	handler(w, req)
+	// Nothing new after the last message
+	messages, w = fetch("after=" + ids[5])
+	if len(messages) != 0 {
+		t.Errorf("expected no messages after %s, got %d", ids[5], len(messages))
+	}
+	if w.Header().Get("X-Has-More") != "false" {
+		t.Errorf("expected X-Has-More false, got %q", w.Header().Get("X-Has-More"))
+	}
 
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status 200, got %d", w.Code)
+	// Pages read only as much history as they need, and match the full list
+	for i := range ids {
+		for limit := 1; limit <= 3; limit++ {
+			messages, w = fetch(fmt.Sprintf("limit=%d&after=%s", limit, ids[i]))
+			expect(messages, ids[i+1:min(i+1+limit, len(ids))]...)
+			if i+1+limit < len(ids) && w.Header().Get("X-Total-Count") != "" {
+				t.Errorf("after=%s&limit=%d read the whole history", ids[i], limit)
+			}
+			messages, _ = fetch(fmt.Sprintf("limit=%d&before=%s", limit, ids[i]))
+			expect(messages, ids[max(i-limit, 0):i]...)
+		}
+	}
+
+	// Cursors of messages pruned since still mark their place in time
+	messages, _ = fetch("limit=2&after=hist-1-0")
+	expect(messages, ids[0], ids[1])
+	messages, _ = fetch("limit=2&before=hist-1-0")
+	expect(messages)
+
+	// Unknown cursor
+	_, w = fetch("before=hist-99")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown cursor, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalChat_Since(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	h.RegisterExecutor("abc1234", "session-001", "/path", "user")
+	h.StopExecutor("abc1234", "session-001", "done")
+	cutoff := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	h.RegisterExecutor("abc1234", "session-002", "/path", "user")
+
+	req := httptest.NewRequest("GET", "/api/goals/abc1234/chat?since="+cutoff.UTC().Format(time.RFC3339Nano), nil)
+	w := httptest.NewRecorder()
+	handleGoalChat(h, "abc1234")(w, req)
+
+	var messages []ChatMessage
+	json.Unmarshal(w.Body.Bytes(), &messages)
+	if len(messages) != 1 || messages[0].SessionID != "session-002" {
+		t.Errorf("expected only the session-002 start message, got %v", messages)
+	}
+
+	req = httptest.NewRequest("GET", "/api/goals/abc1234/chat?since=yesterday", nil)
+	w = httptest.NewRecorder()
+	handleGoalChat(h, "abc1234")(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid since, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalComments(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/comments", bytes.NewBufferString(`{"content": "Looks good @bob"}`))
+	req.Header.Set("X-Vega-User", "alice")
+	w := httptest.NewRecorder()
+	handleGoalComments(h, "abc1234")(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var comment hub.Comment
+	json.Unmarshal(w.Body.Bytes(), &comment)
+
+	req = httptest.NewRequest("POST", "/api/goals/abc1234/comments", bytes.NewBufferString(`{"content": "x", "parent_id": "nope"}`))
+	w = httptest.NewRecorder()
+	handleGoalComments(h, "abc1234")(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown parent, got %d", w.Code)
+	}
+
+	// Comments appear in the chat stream
+	req = httptest.NewRequest("GET", "/api/goals/abc1234/chat", nil)
+	w = httptest.NewRecorder()
+	handleGoalChat(h, "abc1234")(w, req)
+
+	var messages []ChatMessage
+	json.Unmarshal(w.Body.Bytes(), &messages)
+	if len(messages) != 1 || messages[0].Type != "comment" || messages[0].Content != "Looks good @bob" || messages[0].User != "alice" {
+		t.Fatalf("expected comment in chat, got %+v", messages)
+	}
+	if messages[0].Data["comment_id"] != comment.ID {
+		t.Errorf("expected comment_id %s in chat data, got %v", comment.ID, messages[0].Data)
+	}
+}
+
+func TestHandleSessionTranscript(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	transcript := `{"type":"user","message":{"role":"user","content":"Start"}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":"a.go"}},{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"b.go"}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+`
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	os.WriteFile(path, []byte(transcript), 0644)
+
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+	h.StopExecutorWithClaudeInfo(hub.StopExecutorRequest{GoalID: "abc1234", SessionID: "session-001", TranscriptPath: path, Reason: "done"})
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+
+	w := get("/api/goals/abc1234/sessions/session-001/transcript?kind=tool_call&limit=1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp TranscriptResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Total != 2 || len(resp.Entries) != 1 || !resp.HasMore || resp.Entries[0].ToolName != "Write" {
+		t.Errorf("unexpected first page: %+v", resp)
+	}
+	if resp.Summary == nil || len(resp.Summary.FilesEdited) != 2 || resp.Summary.Entries != 4 {
+		t.Errorf("expected summary of whole transcript, got %+v", resp.Summary)
+	}
+
+	json.Unmarshal(get("/api/goals/abc1234/sessions/session-001/transcript?kind=tool_call&offset=1").Body.Bytes(), &resp)
+	if len(resp.Entries) != 1 || resp.HasMore || resp.Entries[0].ToolName != "Edit" {
+		t.Errorf("unexpected second page: %+v", resp)
+	}
+
+	if w := get("/api/goals/abc1234/sessions/missing/transcript"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for missing transcript, got %d", w.Code)
+	}
+	if w := get("/api/goals/abc1234/sessions/session-001/transcript?kind=bogus"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid kind, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalActivity(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	transcript := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":"main.go"}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"--- FAIL: TestX","is_error":true}]}}
+`
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	os.WriteFile(path, []byte(transcript), 0644)
+
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+	h.StopExecutorWithClaudeInfo(hub.StopExecutorRequest{GoalID: "abc1234", SessionID: "session-001", TranscriptPath: path, Reason: "done"})
+
+	req := httptest.NewRequest("GET", "/api/goals/abc1234/activity?kind=test_run", nil)
+	w := httptest.NewRecorder()
+	handleGoalActivity(h, "abc1234")(w, req)
+
+	var activities []hub.SessionActivity
+	json.Unmarshal(w.Body.Bytes(), &activities)
+	if len(activities) != 1 || activities[0].Status != hub.ActivityFailed {
+		t.Fatalf("expected one failed test run, got %+v", activities)
+	}
+
+	// Activities show up in chat with a readable summary
+	req = httptest.NewRequest("GET", "/api/goals/abc1234/chat", nil)
+	w = httptest.NewRecorder()
+	handleGoalChat(h, "abc1234")(w, req)
+
+	var messages []ChatMessage
+	json.Unmarshal(w.Body.Bytes(), &messages)
+	var contents []string
+	for _, m := range messages {
+		if m.Type == "activity" {
+			contents = append(contents, m.ActivityType+": "+m.Content)
+		}
+	}
+	if len(contents) != 2 || contents[0] != "file_edit: Edited main.go" || contents[1] != "test_run: Tests failed: go test ./..." {
+		t.Errorf("unexpected activity chat messages: %v", contents)
+	}
+}
+
+func TestHandleGoalContext(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/context", bytes.NewBufferString(`{"notes":"Keep the API stable.","exclude":["tasks"]}`))
+	w := httptest.NewRecorder()
+	handleGoalContext(h, "abc1234")(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/goals/abc1234/context", nil)
+	w = httptest.NewRecorder()
+	handleGoalContext(h, "abc1234")(w, req)
+
+	var pack hub.ContextPack
+	json.Unmarshal(w.Body.Bytes(), &pack)
+	if !strings.Contains(pack.Text, "Working on Goal #abc1234") || !strings.Contains(pack.Text, "Keep the API stable.") {
+		t.Errorf("unexpected context preview:\n%s", pack.Text)
+	}
+	for _, s := range pack.Sections {
+		if s.Name == hub.ContextTasks {
+			t.Error("expected tasks section excluded")
+		}
+	}
+
+	req = httptest.NewRequest("POST", "/api/goals/abc1234/context", bytes.NewBufferString(`{"exclude":["nope"]}`))
+	w = httptest.NewRecorder()
+	handleGoalContext(h, "abc1234")(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown section, got %d", w.Code)
+	}
+}
+
+func TestHandleExecutorControl(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{"POST", "/api/goals/abc1234/executors/missing/kill", http.StatusNotFound},
+		{"POST", "/api/goals/abc1234/executors/session-001/pause", http.StatusConflict}, // Not spawned by the hub
+		{"POST", "/api/goals/abc1234/executors/session-001/explode", http.StatusNotFound},
+		{"GET", "/api/goals/abc1234/executors/session-001/kill", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handleGoalRoutes(h, p)(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.code, w.Code)
+		}
+	}
+}
+
+func TestHandleGoalChat_MethodNotAllowed(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/chat", nil)
+	w := httptest.NewRecorder()
+
+	handler := handleGoalChat(h, "abc1234")
+	handler(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
+func TestHandleSendMessage(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	body := `{"content": "Hello executor!", "user": "testuser"}`
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/messages", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler := handleGoalMessages(h, "abc1234")
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
 	}
 
 	var response map[string]interface{}
@@ -674,3 +1555,1024 @@ func TestHandleGetPendingMessages_NoMessages(t *testing.T) {
 		t.Errorf("expected decision 'allow', got '%s'", response.Decision)
 	}
 }
+
+func TestMessageDeliveryStatus(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	msg := h.SendUserMessage("abc1234", "Use the v2 API", "alice")
+
+	w := httptest.NewRecorder()
+	handleGetPendingMessages(h, "abc1234")(w, httptest.NewRequest("GET", "/api/goals/abc1234/messages/pending?session_id=s1", nil))
+	var pending PendingMessagesResponse
+	json.Unmarshal(w.Body.Bytes(), &pending)
+	if len(pending.Messages) != 1 || pending.Messages[0].Status != hub.MessageDelivered || pending.Messages[0].SessionID != "s1" {
+		t.Fatalf("expected the message delivered to s1, got %+v", pending.Messages)
+	}
+
+	// The chat shows one bubble carrying the delivery status
+	chat := func() []ChatMessage {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handleGoalChat(h, "abc1234")(w, httptest.NewRequest("GET", "/api/goals/abc1234/chat", nil))
+		var messages []ChatMessage
+		json.Unmarshal(w.Body.Bytes(), &messages)
+		var bubbles []ChatMessage
+		for _, m := range messages {
+			if strings.HasPrefix(m.Type, "user_message") {
+				bubbles = append(bubbles, m)
+			}
+		}
+		return bubbles
+	}
+	if bubbles := chat(); len(bubbles) != 1 || bubbles[0].MessageID != msg.ID || bubbles[0].Delivery != hub.MessageDelivered || bubbles[0].Pending {
+		t.Errorf("expected one delivered bubble, got %+v", bubbles)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/abc1234/messages/ack", bytes.NewBufferString(`{"ids": ["`+msg.ID+`"]}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if bubbles := chat(); len(bubbles) != 1 || bubbles[0].Delivery != hub.MessageProcessed {
+		t.Errorf("expected the bubble marked processed, got %+v", bubbles)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalMessages(h, "abc1234")(w, httptest.NewRequest("GET", "/api/goals/abc1234/messages", nil))
+	var status MessagesStatusResponse
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if status.HasPending || len(status.Messages) != 1 || status.Messages[0].ProcessedAt == nil {
+		t.Errorf("expected the processed message listed, got %+v", status)
+	}
+}
+
+func TestGoalAttachments(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "screenshot.png")
+	fw.Write([]byte("\x89PNG\r\n\x1a\nimage"))
+	mw.Close()
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/attachments", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var uploaded []hub.Attachment
+	json.Unmarshal(w.Body.Bytes(), &uploaded)
+	if len(uploaded) != 1 || uploaded[0].ID == "" || uploaded[0].ContentType != "image/png" {
+		t.Fatalf("expected the uploaded file, got %+v", uploaded)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", uploaded[0].URL, nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Disposition") != `inline; filename="screenshot.png"` || !strings.HasSuffix(w.Body.String(), "image") {
+		t.Errorf("expected the image served inline, got %d %v", w.Code, w.Header())
+	}
+
+	// Messages reference uploads by ID; the executor gets the file path
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/abc1234/messages", bytes.NewBufferString(`{"content": "see the screenshot", "attachments": [{"type": "file", "id": "`+uploaded[0].ID+`"}]}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	handleGetPendingMessages(h, "abc1234")(w, httptest.NewRequest("GET", "/api/goals/abc1234/messages/pending", nil))
+	var pending PendingMessagesResponse
+	json.Unmarshal(w.Body.Bytes(), &pending)
+	if !strings.Contains(pending.Reason, uploaded[0].Path) {
+		t.Errorf("expected the file path in the Stop hook reason, got %q", pending.Reason)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/abc1234/messages", bytes.NewBufferString(`{"content": "x", "attachments": [{"type": "file", "id": "nope"}]}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown upload, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", "/api/goals/abc1234/attachments/nope", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestGoalChatNormalizesContent(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	h.SendUserMessage("abc1234", "Try <script>alert(1)</script>this:\n```sh\nmake test\n```", "alice")
+
+	w := httptest.NewRecorder()
+	handleGoalChat(h, "abc1234")(w, httptest.NewRequest("GET", "/api/goals/abc1234/chat", nil))
+	var messages []ChatMessage
+	json.Unmarshal(w.Body.Bytes(), &messages)
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %+v", messages)
+	}
+	msg := messages[0]
+	if msg.Format != hub.ContentFormat || msg.Content != "Try this:\n```sh\nmake test\n```" {
+		t.Errorf("expected sanitized markdown, got %q (%s)", msg.Content, msg.Format)
+	}
+	if len(msg.CodeBlocks) != 1 || msg.CodeBlocks[0].Language != "sh" || msg.CodeBlocks[0].Code != "make test" {
+		t.Errorf("expected the code block extracted, got %+v", msg.CodeBlocks)
+	}
+}
+
+func TestGoalNotesRoutes(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("PUT", "/api/goals/abc1234/notes", bytes.NewBufferString(body))
+		req.Header.Set("X-Vega-User", "alice")
+		handleGoalRoutes(h, p)(w, req)
+		return w
+	}
+	if w := put(`{"content": "Use the staging cluster", "revision": 0}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := put(`{"content": "Use prod", "revision": 0}`); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 saving over a newer revision, got %d", w.Code)
+	}
+	if w := put(`{"content": "Use the staging cluster, not prod"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", "/api/goals/abc1234/notes", nil))
+	var notes hub.GoalNotes
+	json.Unmarshal(w.Body.Bytes(), &notes)
+	if notes.Revision != 2 || notes.User != "alice" || notes.Content != "Use the staging cluster, not prod" || notes.Revisions != nil {
+		t.Errorf("expected the latest notes without revisions, got %+v", notes)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", "/api/goals/abc1234/notes/revisions", nil))
+	json.Unmarshal(w.Body.Bytes(), &notes)
+	if len(notes.Revisions) != 1 || notes.Revisions[0].Content != "Use the staging cluster" {
+		t.Errorf("expected the earlier revision, got %+v", notes.Revisions)
+	}
+}
+
+func TestGoalReviewRoutes(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	h.StateManager().Transition("abc1234", goals.StateWorking, "Goal ready", nil)
+
+	review := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/api/goals/abc1234/review", bytes.NewBufferString(body))
+		req.Header.Set("X-Vega-User", "bob")
+		handleGoalRoutes(h, p)(w, req)
+		return w
+	}
+	if w := review(`{"decision": "lgtm"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown decision, got %d", w.Code)
+	}
+	if w := review(`{"decision": "request_changes"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 requesting changes without a comment, got %d", w.Code)
+	}
+	w := review(`{"decision": "request_changes", "comment": "Cover the error paths"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ReviewResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.State != "changes_requested" || resp.Review == nil || resp.Review.Reviewer != "bob" || resp.Review.Comment != "Cover the error paths" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	// The review is part of the goal details
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", "/api/goals/abc1234", nil))
+	var detail GoalDetailResponse
+	json.Unmarshal(w.Body.Bytes(), &detail)
+	if detail.Review == nil || detail.Review.State != goals.StateChangesRequested {
+		t.Errorf("expected the review in the goal details, got %+v", detail.Review)
+	}
+
+	h.StateManager().Transition("abc1234", goals.StateIced, "Parked", nil)
+	if w := review(`{"decision": "approve"}`); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 reviewing an iced goal, got %d", w.Code)
+	}
+}
+
+func TestGoalDetailChildrenStatus(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	os.WriteFile(filepath.Join(dir, "goals", "active", "abc1234.1.md"), []byte("# Goal #abc1234.1: Child goal\n"), 0644)
+	if err := goals.NewHierarchyManager(dir).CreateChildGoal("abc1234.1", "abc1234"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", "/api/goals/abc1234", nil))
+	var detail GoalDetailResponse
+	json.Unmarshal(w.Body.Bytes(), &detail)
+	cs := detail.ChildrenStatus
+	if cs == nil || cs.Total != 1 || cs.Active != 1 || cs.AllDone || cs.Children[0].Title != "Child goal" {
+		t.Errorf("expected the child's progress in the goal details, got %+v", cs)
+	}
+}
+
+func TestGoalSplitPreview(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	split := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/abc1234/split", bytes.NewBufferString(body)))
+		return w
+	}
+	w := split(`{"preview": true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data operations.SplitResult `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Data.Children) != 1 || resp.Data.Children[0].Title != "Setup" || len(resp.Data.Children[0].Tasks) != 1 || resp.Data.Children[0].Tasks[0] != "Task two" {
+		t.Errorf("unexpected preview %+v", resp.Data)
+	}
+
+	if w := split(`{"mode": "files", "preview": true}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown mode, got %d", w.Code)
+	}
+}
+
+func TestHandleDecisions(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	h.RecordQuestionHistory("abc1234", "s1", "Which logger?", "slog")
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handleDecisions(h)(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+	w := get("/api/decisions?project=test-project")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var decisions []hub.Decision
+	json.Unmarshal(w.Body.Bytes(), &decisions)
+	if len(decisions) != 1 || decisions[0].GoalID != "abc1234" || decisions[0].Answer != "slog" {
+		t.Errorf("unexpected decisions %+v", decisions)
+	}
+
+	if w := get("/api/decisions?project=other"); strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("expected an empty list, got %s", w.Body.String())
+	}
+	if w := get("/api/decisions?since=yesterday"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid since, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalChanges(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/abc1234/diff", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("GET", "/api/goals/abc1234/changed-files?project=nope", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleStorage(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+	os.WriteFile(filepath.Join(dir, ".vega-hub-retention.json"), []byte(`{"events":{"max_age":"24h"}}`), 0644)
+
+	w := httptest.NewRecorder()
+	handleStorageCompact(h)(w, httptest.NewRequest("POST", "/api/storage/compact", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handleStorage(h)(w, httptest.NewRequest("GET", "/api/storage", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var report hub.StorageReport
+	json.Unmarshal(w.Body.Bytes(), &report)
+	if len(report.Categories) != len(hub.StorageCategories) || report.Interval == "" || report.LastCompaction == nil {
+		t.Errorf("unexpected storage report: %s", w.Body.String())
+	}
+	if report.Categories[2].Retention.MaxAge != "24h" {
+		t.Errorf("expected the events rule in the report, got %+v", report.Categories[2])
+	}
+
+	os.WriteFile(filepath.Join(dir, ".vega-hub-retention.json"), []byte(`{"interval":"soon"}`), 0644)
+	w = httptest.NewRecorder()
+	handleStorage(h)(w, httptest.NewRequest("GET", "/api/storage", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 for an invalid config, got %d", w.Code)
+	}
+}
+
+func TestHandleDigestRoutes(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	req := httptest.NewRequest("POST", "/api/digest/subscription", strings.NewReader(`{"email":"alex@lan","subscribed":true}`))
+	req.Header.Set("X-Vega-User", "alex")
+	w := httptest.NewRecorder()
+	handleDigestRoutes(h)(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	cfg, err := hub.LoadDigestConfig(h.Dir())
+	if err != nil || len(cfg.Users) != 1 || !cfg.Users[0].Subscribed {
+		t.Errorf("expected alex to be subscribed, got %+v (err: %v)", cfg, err)
+	}
+
+	w = httptest.NewRecorder()
+	handleDigestRoutes(h)(w, httptest.NewRequest("GET", "/api/digest/preview?user=alex", nil))
+	var digest hub.Digest
+	json.Unmarshal(w.Body.Bytes(), &digest)
+	if w.Code != http.StatusOK || digest.User != "alex" {
+		t.Errorf("unexpected preview (%d): %s", w.Code, w.Body.String())
+	}
+
+	// Without a user, the digest of the user vega-hub runs as
+	if me, err := credentials.GetCurrentUser(); err == nil {
+		w = httptest.NewRecorder()
+		handleDigestRoutes(h)(w, httptest.NewRequest("GET", "/api/digest/preview", nil))
+		digest = hub.Digest{}
+		json.Unmarshal(w.Body.Bytes(), &digest)
+		if w.Code != http.StatusOK || digest.User != me.Username {
+			t.Errorf("expected the digest of %s without a user, got %d %s", me.Username, w.Code, w.Body.String())
+		}
+	}
+
+	// No SMTP server configured
+	w = httptest.NewRecorder()
+	handleDigestRoutes(h)(w, httptest.NewRequest("POST", "/api/digest/send", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalPosition(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	registry := goals.NewRegistry(dir)
+	for _, e := range []goals.RegistryEntry{
+		{ID: "aaa1111", Title: "A", Projects: []string{"web"}, Status: "active"},
+		{ID: "bbb2222", Title: "B", Projects: []string{"api"}, Status: "iced"},
+		{ID: "ccc3333", Title: "C", Projects: []string{"web"}, Status: "active"},
+		{ID: "ddd4444", Title: "D", Projects: []string{"web"}, Status: "active"},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listIDs := func(query string) []string {
+		w := httptest.NewRecorder()
+		handleGoals(h, p)(w, httptest.NewRequest("GET", "/api/goals"+query, nil))
+		var summaries []GoalSummary
+		json.Unmarshal(w.Body.Bytes(), &summaries)
+		var ids []string
+		for _, s := range summaries {
+			ids = append(ids, fmt.Sprintf("%s:%d", s.ID, s.Position))
+		}
+		return ids
+	}
+
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("PATCH", "/api/goals/ddd4444/position", strings.NewReader(`{"index":0}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The iced goal keeps its slot; active goals follow the board
+	got := strings.Join(listIDs(""), ",")
+	if want := "ddd4444:0,bbb2222:0,aaa1111:1,ccc3333:2"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Project boards are independent of the all-goals board
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("PATCH", "/api/goals/aaa1111/position", strings.NewReader(`{"project":"web","index":2}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got, want := strings.Join(listIDs("?project=web"), ","), "ccc3333:0,ddd4444:1,aaa1111:2"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("PATCH", "/api/goals/bbb2222/position", strings.NewReader(`{"project":"web","index":0}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a goal outside the project, got %d", w.Code)
+	}
+}
+
+func TestHandleViews(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+
+	req := httptest.NewRequest("POST", "/api/views", strings.NewReader(`{"name":"My active","filter":{"statuses":["active"]},"default":true}`))
+	req.Header.Set("X-Vega-User", "alex")
+	w := httptest.NewRecorder()
+	handleViews(h)(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created hub.SavedView
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.ID == "" || created.User != "alex" {
+		t.Errorf("unexpected view: %+v", created)
+	}
+
+	w = httptest.NewRecorder()
+	handleView(h)(w, httptest.NewRequest("GET", "/api/views/default?user=alex", nil))
+	var def hub.SavedView
+	json.Unmarshal(w.Body.Bytes(), &def)
+	if w.Code != http.StatusOK || def.ID != created.ID {
+		t.Errorf("expected default view (%d): %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/views/"+created.ID, strings.NewReader(`{"name":"Renamed","sort":"questions"}`))
+	req.Header.Set("X-Vega-User", "alex")
+	w = httptest.NewRecorder()
+	handleView(h)(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Renamed") {
+		t.Errorf("unexpected update (%d): %s", w.Code, w.Body.String())
+	}
+
+	// Views are private to their user
+	w = httptest.NewRecorder()
+	handleView(h)(w, httptest.NewRequest("DELETE", "/api/views/"+created.ID+"?user=sam", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for another user, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleView(h)(w, httptest.NewRequest("DELETE", "/api/views/"+created.ID+"?user=alex", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+}
+
+func TestHandleUserPreferences(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	req := httptest.NewRequest("PATCH", "/api/user/preferences", strings.NewReader(`{"theme":"light","default_executor_mode":"review"}`))
+	req.Header.Set("X-Vega-User", "alex")
+	w := httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, httptest.NewRequest("GET", "/api/user/preferences?user=alex", nil))
+	var prefs hub.Preferences
+	json.Unmarshal(w.Body.Bytes(), &prefs)
+	if prefs.Theme != "light" || prefs.DefaultExecutorMode != "review" {
+		t.Errorf("unexpected preferences: %+v", prefs)
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/user/preferences?user=alex", strings.NewReader(`{"theme":"neon"}`))
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, httptest.NewRequest("DELETE", "/api/user/preferences?user=alex", nil))
+	json.Unmarshal(w.Body.Bytes(), &prefs)
+	if w.Code != http.StatusOK || prefs.Theme != "system" {
+		t.Errorf("expected defaults after reset (%d): %+v", w.Code, prefs)
+	}
+}
+
+func TestHandleUserIdentity(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+
+	w := httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, httptest.NewRequest("GET", "/api/user/identity?user=alex", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 without identity, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest("PUT", "/api/user/identity?user=alex", strings.NewReader(`{"name":"Alex Doe","email":"alex@example.com"}`))
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("PUT", "/api/user/identity?user=alex", strings.NewReader(`{"name":"Alex","email":"alex@example.com","ssh_key":"/nonexistent/id_work"}`))
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for missing key, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, httptest.NewRequest("GET", "/api/user/identity?user=alex", nil))
+	var identity credentials.Identity
+	json.Unmarshal(w.Body.Bytes(), &identity)
+	if identity.User != "alex" || identity.Email != "alex@example.com" {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+
+	w = httptest.NewRecorder()
+	handleUserRoutes(h, p)(w, httptest.NewRequest("DELETE", "/api/user/identity?user=alex", nil))
+	if w.Code != http.StatusOK && w.Code != http.StatusNoContent {
+		t.Errorf("expected delete to succeed, got %d", w.Code)
+	}
+}
+
+func TestHandlePreflight(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	base := filepath.Join(dir, "workspaces", "test-project", "worktree-base")
+	os.MkdirAll(base, 0755)
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(dir, "projects", "test-project.md"), []byte("# Project: test-project\n\n**Base Branch**: `main`\n"), 0644)
+	exec.Command("git", "-C", base, "init", "-q", "-b", "main").Run()
+	exec.Command("git", "-C", base, "-c", "user.email=t@t", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init").Run()
+	os.WriteFile(filepath.Join(base, "dirty.txt"), []byte("x"), 0644)
+
+	body := `{"project":"test-project","branch":"goal-new","checks":["worktree_clean","branch_available"]}`
+	w := httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/preflight", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result hub.PreflightResult
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if result.Ready || len(result.Checks) != 2 || result.Checks["worktree_clean"].Passed || !result.Checks["branch_available"].Passed {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.FixCommands) == 0 {
+		t.Error("expected fix commands")
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/preflight", strings.NewReader(`{"project":"test-project","checks":["bogus"]}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown check, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleGoalRoutes(h, p)(w, httptest.NewRequest("POST", "/api/goals/preflight", strings.NewReader(`{"project":"missing"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown project, got %d", w.Code)
+	}
+}
+
+func TestHandleUpgradeHooks(t *testing.T) {
+	h, _, dir := setupTestEnv(t)
+
+	// No templates yet
+	w := httptest.NewRecorder()
+	handleUpgradeHooks(h)(w, httptest.NewRequest("POST", "/api/worktrees/upgrade-hooks", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 without templates, got %d: %s", w.Code, w.Body.String())
+	}
+
+	hooks := filepath.Join(dir, "templates", "project-init", ".claude", "hooks")
+	os.MkdirAll(hooks, 0755)
+	os.WriteFile(filepath.Join(hooks, "on-stop.sh"), []byte("#!/bin/sh\n"), 0755)
+	worktree := filepath.Join(dir, "workspaces", "test-project", "goal-abc1234-test")
+
+	w = httptest.NewRecorder()
+	handleUpgradeHooks(h)(w, httptest.NewRequest("POST", "/api/worktrees/upgrade-hooks", strings.NewReader(`{"dry_run":true}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Success bool                          `json:"success"`
+		Data    operations.UpgradeHooksResult `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if !resp.Data.DryRun || resp.Data.Upgraded != 1 || resp.Data.Worktrees[0].Changes[0].Action != "add" {
+		t.Errorf("unexpected dry run: %s", w.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(worktree, ".claude", "hooks", "on-stop.sh")); !os.IsNotExist(err) {
+		t.Error("expected a dry run not to write")
+	}
+
+	w = httptest.NewRecorder()
+	handleUpgradeHooks(h)(w, httptest.NewRequest("POST", "/api/worktrees/upgrade-hooks", strings.NewReader(`{"goal_id":"fff0000"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown goal, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleUpgradeHooks(h)(w, httptest.NewRequest("GET", "/api/worktrees/upgrade-hooks", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
+func TestUnsafeIDsRejected(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	requests := []struct {
+		method, path, body string
+	}{
+		{"GET", "/api/goals/..%2F..%2Fetc/status", ""},
+		{"GET", "/api/goals/abc1234/sessions/..%2Fx/transcript", ""},
+		{"POST", "/api/goals/abc1234/executors/-x/kill", ""},
+		{"DELETE", "/api/projects/.hidden", ""},
+		{"GET", "/api/history/abc1234/..%2F..%2Fsecrets", ""},
+		{"POST", "/api/executor/stop", `{"goal_id": "../abc1234", "session_id": "s1"}`},
+		{"POST", "/api/ask", `{"goal_id": "abc1234", "session_id": "../../s1", "question": "?"}`},
+	}
+	for _, tt := range requests {
+		req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected 400, got %d", tt.method, tt.path, w.Code)
+		}
+	}
+}
+
+func TestGoalOperationInProgress(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	release, err := h.BeginGoalOperation("abc1234", "complete", "alice")
+	if err != nil {
+		t.Fatalf("BeginGoalOperation: %v", err)
+	}
+
+	for _, action := range []string{"complete", "ice", "delete"} {
+		req := httptest.NewRequest("POST", "/api/goals/abc1234/"+action, bytes.NewBufferString(`{"project": "test-project", "reason": "x"}`))
+		req.Header.Set("X-Vega-User", "bob")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("%s: expected 409, got %d: %s", action, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Success bool `json:"success"`
+			Error   struct {
+				Code    string            `json:"code"`
+				Details map[string]string `json:"details"`
+			} `json:"error"`
+		}
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.Error.Code != "operation_in_progress" || resp.Error.Details["operation"] != "complete" || resp.Error.Details["holder"] != "alice" {
+			t.Errorf("%s: expected the running complete by alice reported, got %+v", action, resp.Error)
+		}
+	}
+
+	// Another goal isn't blocked
+	req := httptest.NewRequest("POST", "/api/goals/def5678/ice", bytes.NewBufferString(`{"project": "test-project", "reason": "x"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code == http.StatusConflict {
+		t.Errorf("expected another goal not blocked, got 409: %s", w.Body.String())
+	}
+
+	release()
+	if op := h.GoalOperationInProgress("abc1234"); op != nil {
+		t.Errorf("expected the operation released, got %+v", op)
+	}
+}
+
+func TestJobRoutes(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	started := make(chan struct{})
+	job := h.Jobs().Start(jobs.Spec{Type: "clone_project", GoalID: "abc1234"}, func(ctx context.Context, progress func(string)) (interface{}, error) {
+		progress("cloning")
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	var list []jobs.Job
+	json.NewDecoder(do("GET", "/api/jobs?goal_id=abc1234").Body).Decode(&list)
+	if len(list) != 1 || list[0].ID != job.ID || list[0].Step != "cloning" {
+		t.Fatalf("expected the running job listed, got %+v", list)
+	}
+
+	if w := do("POST", "/api/jobs/"+job.ID+"/cancel"); w.Code != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	h.Jobs().Wait(context.Background(), job.ID)
+	var got jobs.Job
+	json.NewDecoder(do("GET", "/api/jobs/"+job.ID).Body).Decode(&got)
+	if got.Status != jobs.StatusCanceled {
+		t.Errorf("expected the job canceled, got %+v", got)
+	}
+
+	if w := do("POST", "/api/jobs/"+job.ID+"/cancel"); w.Code != http.StatusConflict {
+		t.Errorf("cancel finished job: expected 409, got %d", w.Code)
+	}
+	if w := do("GET", "/api/jobs/unknown"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown job: expected 404, got %d", w.Code)
+	}
+}
+
+func TestCompleteGoalAsync(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	req := httptest.NewRequest("POST", "/api/goals/abc1234/complete", bytes.NewBufferString(`{"project": "test-project", "async": true}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp JobAcceptedResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Job == nil || resp.Job.Type != "complete_goal" || resp.Job.GoalID != "abc1234" {
+		t.Fatalf("expected a complete_goal job, got %+v", resp.Job)
+	}
+
+	// The test project has no git checkout, so the job fails, and the goal's
+	// operation lock is held until it does
+	job, err := h.Jobs().Wait(context.Background(), resp.Job.ID)
+	if err != nil || job.Status != jobs.StatusFailed || job.Error == "" {
+		t.Errorf("expected the job to fail with the operation's error, got %+v (%v)", job, err)
+	}
+	if op := h.GoalOperationInProgress("abc1234"); op != nil {
+		t.Errorf("expected the operation lock released with the job, got %+v", op)
+	}
+}
+
+func TestHandleAskMaxWait(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	ask := func(body string) (int, AskResponse) {
+		req := httptest.NewRequest("POST", "/api/ask", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var resp AskResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	code, resp := ask(`{"goal_id": "abc1234", "session_id": "s1", "question": "Proceed?", "max_wait": 1}`)
+	if code != http.StatusAccepted || !resp.Pending || resp.QuestionID == "" {
+		t.Fatalf("expected 202 with the pending question ID, got %d %+v", code, resp)
+	}
+	if pending := h.GetPendingQuestions(); len(pending) != 1 || pending[0].ID != resp.QuestionID {
+		t.Fatalf("question not left pending: %+v", pending)
+	}
+
+	if err := h.AnswerStructured(resp.QuestionID, &hub.StructuredAnswer{Text: "Yes"}); err != nil {
+		t.Fatalf("answer failed: %v", err)
+	}
+	code, resp = ask(`{"goal_id": "abc1234", "session_id": "s1", "question_id": "` + resp.QuestionID + `", "max_wait": 1}`)
+	if code != http.StatusOK || resp.Answer != "Yes" {
+		t.Errorf("retry with the question ID: %d %+v", code, resp)
+	}
+
+	if code, _ := ask(`{"goal_id": "abc1234", "session_id": "s1", "question_id": "20260101-000000-000-abcdef"}`); code != http.StatusNotFound {
+		t.Errorf("unknown question ID: expected 404, got %d", code)
+	}
+	if code, _ := ask(`{"goal_id": "abc1234", "session_id": "s1", "question": "?", "max_wait": -1}`); code != http.StatusBadRequest {
+		t.Errorf("negative max_wait: expected 400, got %d", code)
+	}
+}
+
+func TestHandleExecutorProgress(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+	h.RegisterExecutor("abc1234", "s1", "/tmp", "testuser")
+
+	report := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/executor/progress", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := report(`{"goal_id": "abc1234", "session_id": "s1", "status": "running tests"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Progress *hub.ExecutorProgress `json:"progress"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Progress == nil || resp.Progress.Status != "running tests" {
+		t.Errorf("unexpected response: %+v", resp.Progress)
+	}
+	if got := h.GoalProgress()["abc1234"]; got.Status != "running tests" {
+		t.Errorf("progress not recorded: %+v", got)
+	}
+
+	if w := report(`{"goal_id": "abc1234", "session_id": "unknown", "status": "x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown session: expected 404, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalTimeline(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+	if err := goals.NewRegistry(dir).Add(goals.RegistryEntry{ID: "abc1234", Title: "Test", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	h.RecordQuestionHistory("abc1234", "s1", "Proceed?", "Yes")
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+
+	w := get("/api/goals/abc1234/timeline?bucket=hour&tz=Europe/Paris&days=7")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var timeline operations.GoalTimelineResult
+	json.NewDecoder(w.Body).Decode(&timeline)
+	if timeline.Bucket != "hour" || timeline.Totals.Questions != 1 || len(timeline.Buckets) != 1 {
+		t.Errorf("unexpected timeline: %+v", timeline)
+	}
+
+	for url, code := range map[string]int{
+		"/api/goals/fff0000/timeline":            http.StatusNotFound,
+		"/api/goals/abc1234/timeline?bucket=min": http.StatusBadRequest,
+		"/api/goals/abc1234/timeline?tz=Nowhere": http.StatusBadRequest,
+		"/api/goals/abc1234/timeline?days=0":     http.StatusBadRequest,
+	} {
+		if w := get(url); w.Code != code {
+			t.Errorf("%s: expected %d, got %d", url, code, w.Code)
+		}
+	}
+}
+
+func TestHandleProjectGoals(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	configPath := filepath.Join(dir, "projects", "test-project.md")
+	os.WriteFile(configPath, []byte("# Project: test-project\n\n## Active Goals\n\n_None currently active_\n"), 0644)
+	goals.NewRegistry(dir).Add(goals.RegistryEntry{ID: "abc1234", Title: "Test goal", Projects: []string{"test-project"}, Status: "active"})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/projects/test-project/goals", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var view goals.ProjectGoals
+	json.NewDecoder(w.Body).Decode(&view)
+	if len(view.Active) != 1 || view.Active[0].ID != "abc1234" || view.Active[0].File != "goals/active/abc1234.md" {
+		t.Errorf("unexpected view: %+v", view)
+	}
+	if data, _ := os.ReadFile(configPath); strings.Contains(string(data), "abc1234") {
+		t.Error("GET should not touch the project config")
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/projects/test-project/goals", nil))
+	if data, _ := os.ReadFile(configPath); w.Code != http.StatusOK || !strings.Contains(string(data), "- abc1234: Test goal") {
+		t.Errorf("POST should regenerate the goal lists: %d\n%s", w.Code, data)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/projects/missing/goals", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown project: expected 404, got %d", w.Code)
+	}
+}
+
+func TestHandleAnswerGroup(t *testing.T) {
+	h, p, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("X-Vega-User", "alice")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve("POST", "/api/ask", `{"goal_id": "abc1234", "session_id": "s1", "question": "?", "priority": "urgent"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid priority: expected 400, got %d", w.Code)
+	}
+
+	done := make(chan *httptest.ResponseRecorder, 2)
+	for _, question := range []string{"Schema?", "Index?"} {
+		go func(question string) {
+			done <- serve("POST", "/api/ask", `{"goal_id": "abc1234", "session_id": "s1", "group": "db", "priority": "high", "question": "`+question+`"}`)
+		}(question)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(h.GetPendingQuestions()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var groups []hub.QuestionGroup
+	json.NewDecoder(serve("GET", "/api/questions?grouped=true", "").Body).Decode(&groups)
+	if len(groups) != 1 || groups[0].Group != "db" || groups[0].Priority != "high" || len(groups[0].Questions) != 2 {
+		t.Fatalf("grouped questions = %+v", groups)
+	}
+
+	w := serve("POST", "/api/questions/groups/answer", `{"goal_id": "abc1234", "group": "db", "answer": "Go ahead"}`)
+	var resp AnswerGroupResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || len(resp.Answered) != 2 {
+		t.Fatalf("answer group: %d %+v", w.Code, resp)
+	}
+	for i := 0; i < 2; i++ {
+		var ask AskResponse
+		json.NewDecoder((<-done).Body).Decode(&ask)
+		if ask.Answer != "Go ahead" {
+			t.Errorf("executor got %q", ask.Answer)
+		}
+	}
+
+	if w := serve("POST", "/api/questions/groups/answer", `{"goal_id": "abc1234", "group": "db", "answer": "Again"}`); w.Code != http.StatusNotFound {
+		t.Errorf("answered group: expected 404, got %d", w.Code)
+	}
+}
+
+func TestHandleValidate(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+	if err := goals.NewRegistry(dir).Add(goals.RegistryEntry{ID: "fff0000", Title: "No file", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/validate", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report goals.ValidationReport
+	json.NewDecoder(w.Body).Decode(&report)
+	if report.Valid || report.Errors == 0 {
+		t.Errorf("expected the registered goal without a file to be an error: %+v", report)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/validate", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleGoalAlias(t *testing.T) {
+	h, p, dir := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, h, p)
+	for _, id := range []string{"abc1234", "def5678"} {
+		if err := goals.NewRegistry(dir).Add(goals.RegistryEntry{ID: id, Title: "Test " + id, Status: "active"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("PATCH", "/api/goals/abc1234", `{"alias": "OAuth-Login"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var updated GoalUpdateResponse
+	json.NewDecoder(w.Body).Decode(&updated)
+	if updated.Alias != "oauth-login" {
+		t.Errorf("expected normalized alias, got %q", updated.Alias)
+	}
+
+	// The alias works in place of the ID
+	if w := do("GET", "/api/goals/oauth-login/timeline", ""); w.Code != http.StatusOK {
+		t.Errorf("expected the alias to resolve, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for body, code := range map[string]int{
+		`{"alias": "oauth-login"}`: http.StatusConflict,
+		`{"alias": "abc9999"}`:     http.StatusBadRequest,
+	} {
+		if w := do("PATCH", "/api/goals/def5678", body); w.Code != code {
+			t.Errorf("%s: expected %d, got %d: %s", body, code, w.Code, w.Body.String())
+		}
+	}
+}
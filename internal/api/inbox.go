@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// InboxReadRequest is the request body for POST /api/inbox/read
+type InboxReadRequest struct {
+	At *time.Time `json:"at,omitempty"` // Mark mentions up to here read (default: now)
+}
+
+// handleInbox handles /api/inbox routes for the calling user
+// (X-Vega-User header or user parameter):
+//   - GET  /api/inbox      - pending questions, unread mentions, goals waiting
+//     for review and stuck goals the user created
+//   - POST /api/inbox/read - mark the user's mentions read
+func handleInbox(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := requestUser(r)
+
+		switch strings.TrimPrefix(r.URL.Path, "/api/inbox") {
+		case "", "/":
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			inbox, err := h.BuildInbox(user)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(inbox)
+
+		case "/read":
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			var req InboxReadRequest
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "Invalid JSON", http.StatusBadRequest)
+					return
+				}
+			}
+			if user == "" {
+				http.Error(w, "User is required (X-Vega-User header or user parameter)", http.StatusBadRequest)
+				return
+			}
+			at := time.Now()
+			if req.At != nil {
+				at = *req.At
+			}
+			if err := h.MarkMentionsRead(user, at); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			inbox, err := h.BuildInbox(user)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(inbox)
+
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}
+}
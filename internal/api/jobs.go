@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/jobs"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// JobAcceptedResponse is returned with 202 when an operation runs as a job
+type JobAcceptedResponse struct {
+	Success bool      `json:"success"`
+	Job     *jobs.Job `json:"job"`
+}
+
+// runAsJob runs a long operation (complete, clone, MR creation) as a
+// background job, handing it the request's goal operation lock. With async
+// it answers 202 with the job and returns false. Otherwise it waits for the
+// job and returns true, and the handler responds as it always has from what
+// run captured; if the client goes away meanwhile, the job carries on.
+func runAsJob(w http.ResponseWriter, r *http.Request, h *hub.Hub, spec jobs.Spec, async bool, run jobs.Func) bool {
+	release := handOff(r)
+	job := h.Jobs().Start(spec, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+		defer release()
+		return run(ctx, progress)
+	})
+
+	if async {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(JobAcceptedResponse{Success: true, Job: job})
+		return false
+	}
+	if _, err := h.Jobs().Wait(r.Context(), job.ID); err != nil {
+		log.Printf("[JOBS] Stopped waiting for %s job %s: %v", spec.Type, job.ID, err)
+		return false
+	}
+	return true
+}
+
+// resultError turns a failed operation result into a job error
+func resultError(result *operations.Result) error {
+	if result.Error == nil {
+		return errors.New("operation failed")
+	}
+	msg := result.Error.Message
+	if detail := result.Error.Details["error"]; detail != "" {
+		msg += ": " + detail
+	}
+	return errors.New(msg)
+}
+
+// canceledResult is the response of an operation whose job was canceled
+// before it started
+func canceledResult() *operations.Result {
+	return &operations.Result{
+		Success: false,
+		Error:   &operations.ErrorInfo{Code: "canceled", Message: "The job was canceled before it started"},
+	}
+}
+
+// handleJobs handles GET /api/jobs - running and recently finished jobs
+// (?goal_id=, ?type=, ?status=)
+func handleJobs(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		list := h.Jobs().List(jobs.Filter{
+			GoalID: q.Get("goal_id"),
+			Type:   q.Get("type"),
+			Status: q.Get("status"),
+		})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	}
+}
+
+// handleJobRoutes handles /api/jobs/:id (GET, a job's status and step) and
+// /api/jobs/:id/cancel (POST)
+func handleJobRoutes(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/")
+
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			job, err := h.Jobs().Get(id)
+			if err != nil {
+				http.Error(w, "Job not found", http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(job)
+		case action == "cancel" && r.Method == http.MethodPost:
+			switch err := h.Jobs().Cancel(id); {
+			case errors.Is(err, jobs.ErrNotFound):
+				http.Error(w, "Job not found", http.StatusNotFound)
+				return
+			case errors.Is(err, jobs.ErrFinished):
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			log.Printf("[JOBS] Job %s canceled by %s", id, requestUser(r))
+			job, _ := h.Jobs().Get(id)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(job)
+		case action == "" || action == "cancel":
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/jobs"
+	"github.com/lasmarois/vega-hub/internal/operations"
+	"github.com/lasmarois/vega-hub/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	// Spawned executors re-run this binary as the fake executor
+	testutil.RunFakeExecutor()
+	os.Exit(m.Run())
+}
+
+// createLifecycleGoal adds a project cloned from a fresh remote and creates
+// a goal in it through the API
+func createLifecycleGoal(t *testing.T, env *testutil.Env) *operations.CreateResult {
+	t.Helper()
+	remote := env.NewRemote("my-api", map[string]string{"README.md": "# my-api\n"})
+
+	var added AddProjectResponse
+	if status := env.Do("POST", "/api/projects", AddProjectRequest{Name: "my-api", URL: remote}, &added); status != http.StatusOK {
+		t.Fatalf("add project: status %d: %+v", status, added)
+	}
+
+	var created CreateGoalResponse
+	if status := env.Do("POST", "/api/goals", CreateGoalRequest{Title: "Pick a database", Project: "my-api"}, &created); status != http.StatusAccepted || created.Data == nil || created.Job == nil {
+		t.Fatalf("create goal: status %d: %+v", status, created)
+	}
+
+	// The worktree is provisioned in the background
+	var job struct {
+		jobs.Job
+		Result *operations.CreateResult `json:"result"`
+	}
+	env.WaitFor("the worktree to be provisioned", func() bool {
+		env.Do("GET", "/api/jobs/"+created.Job.ID, nil, &job)
+		return job.Status == jobs.StatusSucceeded || job.Status == jobs.StatusFailed
+	})
+	if job.Status != jobs.StatusSucceeded || job.Result == nil {
+		t.Fatalf("provisioning failed: %+v", job)
+	}
+	return job.Result
+}
+
+// answerNextQuestion waits for a pending question and answers it
+func answerNextQuestion(t *testing.T, env *testutil.Env, answer string) hub.Question {
+	t.Helper()
+	var questions []hub.Question
+	env.WaitFor("a pending question", func() bool {
+		questions = nil
+		env.Do("GET", "/api/questions", nil, &questions)
+		return len(questions) > 0
+	})
+	q := questions[0]
+	if status := env.Do("POST", "/api/answer/"+q.ID, AnswerRequest{Answer: answer, User: "alice"}, nil); status != http.StatusOK {
+		t.Fatalf("answer: status %d", status)
+	}
+	return q
+}
+
+func TestGoalLifecycle(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.Serve(RegisterRoutes)
+	// The spawned executor reaches the hub with the token it was issued
+	env.Hub.SetExecutorAuth(hub.ExecutorAuthAll)
+	goal := createLifecycleGoal(t, env)
+
+	if _, err := os.Stat(goal.WorktreePath); err != nil {
+		t.Fatalf("expected a worktree at %s: %v", goal.WorktreePath, err)
+	}
+
+	env.InstallFakeExecutor(testutil.ExecutorScript{Steps: []testutil.ExecutorStep{
+		{Print: "starting"},
+		{Ask: "Which database?", Options: []string{"PostgreSQL", "SQLite"}},
+		{Write: "db.txt", Content: "{{answer}}\n"},
+		{Commit: "Pick the database"},
+	}})
+
+	var spawned hub.SpawnResult
+	if status := env.Do("POST", "/api/goals/"+goal.GoalID+"/spawn", SpawnRequest{Project: "my-api", User: "alice"}, &spawned); status != http.StatusOK || !spawned.Success {
+		t.Fatalf("spawn: status %d: %+v", status, spawned)
+	}
+
+	q := answerNextQuestion(t, env, "PostgreSQL")
+	if q.GoalID != goal.GoalID || q.Question != "Which database?" || len(q.Options) != 2 {
+		t.Errorf("unexpected question: %+v", q)
+	}
+
+	env.WaitFor("the executor to exit", func() bool {
+		return len(env.Hub.GetActiveExecutors()) == 0
+	})
+
+	var output OutputResponse
+	env.Do("GET", "/api/goals/"+goal.GoalID+"/output", nil, &output)
+	if !strings.Contains(output.Output, "starting") || !strings.Contains(output.Output, "answer: PostgreSQL") {
+		t.Errorf("unexpected executor output: %q", output.Output)
+	}
+	if log := env.Git(goal.WorktreePath, "log", "-1", "--format=%s"); log != "Pick the database" {
+		t.Errorf("expected the executor's commit on the goal branch, got %q", log)
+	}
+
+	var chat []ChatMessage
+	env.Do("GET", "/api/goals/"+goal.GoalID+"/chat", nil, &chat)
+	var types []string
+	for _, m := range chat {
+		types = append(types, m.Type)
+		if m.Type == "question" && m.Answer != "PostgreSQL" {
+			t.Errorf("expected the answer in chat, got %+v", m)
+		}
+	}
+	if got := strings.Join(types, ","); !strings.Contains(got, "session_start") || !strings.Contains(got, "question") || !strings.Contains(got, "session_stop") {
+		t.Errorf("expected session start, question and stop in chat, got %s", got)
+	}
+
+	var completed struct {
+		Success bool                       `json:"success"`
+		Data    *operations.CompleteResult `json:"data"`
+		Error   *operations.ErrorInfo      `json:"error"`
+	}
+	if status := env.Do("POST", "/api/goals/"+goal.GoalID+"/complete", CompleteGoalRequest{Project: "my-api"}, &completed); status != http.StatusOK || completed.Data == nil {
+		t.Fatalf("complete: status %d: %+v", status, completed.Error)
+	}
+	if !completed.Data.Merged || !completed.Data.WorktreeRemoved {
+		t.Errorf("expected a merge and worktree removal, got %+v", completed.Data)
+	}
+
+	base := filepath.Join(env.Dir, "workspaces", "my-api", "worktree-base")
+	if data, err := os.ReadFile(filepath.Join(base, "db.txt")); err != nil || string(data) != "PostgreSQL\n" {
+		t.Errorf("expected db.txt merged into main, got %q (%v)", data, err)
+	}
+
+	var detail GoalDetailResponse
+	env.Do("GET", "/api/goals/"+goal.GoalID, nil, &detail)
+	if detail.Status != "completed" {
+		t.Errorf("expected the goal completed, got %q", detail.Status)
+	}
+}
+
+func TestGoalLifecycle_HookRegisteredExecutor(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.Serve(RegisterRoutes)
+	goal := createLifecycleGoal(t, env)
+
+	// An executor started outside vega-hub reports itself through the hooks
+	done := make(chan error, 1)
+	go func() {
+		_, err := testutil.RunExecutor(env.URL, goal.GoalID, "hook-session", goal.WorktreePath, testutil.ExecutorScript{
+			Register: true,
+			Steps:    []testutil.ExecutorStep{{Ask: "Ship it?"}},
+		}, &strings.Builder{})
+		done <- err
+	}()
+
+	env.WaitFor("the executor to register", func() bool {
+		return len(env.Hub.GetActiveExecutors()) == 1
+	})
+	answerNextQuestion(t, env, "yes")
+	if err := <-done; err != nil {
+		t.Fatalf("executor failed: %v", err)
+	}
+	if active := env.Hub.GetActiveExecutors(); len(active) != 0 {
+		t.Errorf("expected the executor stopped, got %+v", active)
+	}
+}
+
+func TestCreateGoalProvisionsInBackground(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.Serve(RegisterRoutes)
+	goal := createLifecycleGoal(t, env)
+
+	if _, err := os.Stat(goal.WorktreePath); err != nil {
+		t.Fatalf("expected a worktree at %s: %v", goal.WorktreePath, err)
+	}
+	history, err := env.Hub.StateManager().GetHistory(goal.GoalID)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	var states []string
+	for _, e := range history {
+		states = append(states, string(e.State))
+	}
+	if got := strings.Join(states, ","); got != "pending,branching,working" {
+		t.Errorf("expected pending,branching,working, got %s", got)
+	}
+	if op := env.Hub.GoalOperationInProgress(goal.GoalID); op != nil {
+		t.Errorf("expected the provisioning lock released, got %+v", op)
+	}
+
+	// wait keeps the old blocking behaviour
+	var created CreateGoalResponse
+	if status := env.Do("POST", "/api/goals", CreateGoalRequest{Title: "Pick a cache", Project: "my-api", Wait: true}, &created); status != http.StatusOK || created.Job != nil {
+		t.Fatalf("create goal with wait: status %d: %+v", status, created)
+	}
+	if _, err := os.Stat(created.Data.WorktreePath); err != nil {
+		t.Errorf("expected the worktree created before responding: %v", err)
+	}
+}
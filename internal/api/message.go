@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// MessagePreviewRequest is the body for POST /api/goals/:id/message-preview
+type MessagePreviewRequest struct {
+	Project  string `json:"project,omitempty"`
+	Kind     string `json:"kind"`     // "merge_message" or "mr_description"
+	Template string `json:"template"` // Rendered instead of the project's template for kind
+}
+
+// handleMessagePreview handles /api/goals/:id/message-preview
+// GET  - the goal's merge message and MR description from the project's templates
+// POST - the same with an unsaved template for one kind
+func handleMessagePreview(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts := operations.MessagePreviewOptions{
+			GoalID:  goalID,
+			Project: r.URL.Query().Get("project"),
+			VegaDir: h.Dir(),
+		}
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req MessagePreviewRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Project != "" {
+				opts.Project = req.Project
+			}
+			opts.Kind, opts.Template = req.Kind, req.Template
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		result, data := operations.PreviewGoalMessages(opts)
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found", "worktree_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "invalid_template", "invalid_input":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   result.Error,
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(data)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// MoveProjectRequest is the body for POST /api/goals/:id/move-project
+type MoveProjectRequest struct {
+	Project    string `json:"project"`
+	BaseBranch string `json:"base_branch,omitempty"` // Defaults to the target project's
+	Transplant bool   `json:"transplant,omitempty"`  // Carry the goal's commits over as patches
+	Force      bool   `json:"force,omitempty"`       // Archive the old worktree despite uncommitted changes
+}
+
+// handleGoalMoveProject handles POST /api/goals/:id/move-project - migrates
+// the goal to another project. Refused while an executor is working in the
+// old worktree.
+func handleGoalMoveProject(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req MoveProjectRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if req.Project == "" {
+			http.Error(w, "Project is required", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		for _, e := range h.GetActiveExecutors() {
+			if e.GoalID == goalID {
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(operations.Result{
+					Success: false,
+					Error: &operations.ErrorInfo{
+						Code:    "executor_running",
+						Message: fmt.Sprintf("Cannot move goal %s while an executor is running", goalID),
+						Details: map[string]string{"goal_id": goalID, "session_id": e.SessionID},
+					},
+				})
+				return
+			}
+		}
+
+		result, data := operations.MoveGoalProject(operations.MoveProjectOptions{
+			GoalID:     goalID,
+			Project:    req.Project,
+			BaseBranch: req.BaseBranch,
+			Transplant: req.Transplant,
+			Force:      req.Force,
+			VegaDir:    h.Dir(),
+		})
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found", "project_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "not_active", "uncommitted_changes", "patch_conflict":
+				w.WriteHeader(http.StatusConflict)
+			case "invalid_input", "patch_unsupported":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		log.Printf("[MOVE-PROJECT] Goal %s moved from %s to %s (%d commits transplanted)", goalID, data.FromProject, data.Project, data.Transplanted)
+		h.EmitEvent("goal_moved", map[string]interface{}{
+			"goal_id":      goalID,
+			"from_project": data.FromProject,
+			"project":      data.Project,
+			"branch":       data.Branch,
+		})
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// SaveNotesRequest is the request body for PUT /api/goals/:id/notes
+type SaveNotesRequest struct {
+	Content  string `json:"content"`
+	Revision *int   `json:"revision,omitempty"` // Revision the edit started from; a newer one on the server gives 409
+	User     string `json:"user,omitempty"`     // Fallback when X-Vega-User is not set
+}
+
+// handleGoalNotes handles /api/goals/:id/notes
+// GET - the goal's notes (without earlier revisions)
+// PUT - replace the notes
+// GET /api/goals/:id/notes/revisions - the notes with their earlier revisions
+func handleGoalNotes(h *hub.Hub, goalID, sub string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case sub == "" && r.Method == http.MethodGet, sub == "revisions" && r.Method == http.MethodGet:
+			notes, err := h.GetGoalNotes(goalID)
+			if err != nil {
+				http.Error(w, "Failed to get notes: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if sub == "" {
+				notes.Revisions = nil
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(notes)
+
+		case sub == "" && r.Method == http.MethodPut:
+			var req SaveNotesRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*hub.MaxNotesBytes)).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			user := actingUser(r, req.User)
+			base := -1
+			if req.Revision != nil {
+				base = *req.Revision
+			}
+
+			notes, err := h.SaveGoalNotes(goalID, req.Content, user, base)
+			switch {
+			case errors.Is(err, hub.ErrNotesConflict):
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			case errors.Is(err, hub.ErrNotesTooLarge):
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			case err != nil:
+				http.Error(w, "Failed to save notes: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			log.Printf("[NOTES] Notes of goal %s saved by %s (revision %d)", goalID, user, notes.Revision)
+			notes.Revisions = nil
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(notes)
+
+		case sub == "" || sub == "revisions":
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// maxPatchBytes bounds an uploaded patch series
+const maxPatchBytes = 10 << 20
+
+// handleGoalPatch handles GET /api/goals/:id/patch - downloads the goal
+// branch's commits as a git format-patch mbox
+func handleGoalPatch(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		result, data := operations.GoalPatch(operations.GoalDiffOptions{
+			GoalID:  goalID,
+			Project: r.URL.Query().Get("project"),
+			VegaDir: h.Dir(),
+		})
+		if !result.Success {
+			writePatchError(w, result)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/mbox")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "goal-"+goalID+".patch"))
+		w.Header().Set("X-Vega-Patch-Commits", strconv.Itoa(data.Commits))
+		w.Header().Set("X-Vega-Merge-Base", data.MergeBase)
+		w.Write(data.Patch)
+	}
+}
+
+// handleGoalApplyPatch handles POST /api/goals/:id/apply-patch - applies a
+// patch series (the raw mbox, or multipart "file" parts in order) to the
+// goal's worktree
+func handleGoalApplyPatch(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxPatchBytes)
+		patch, err := readPatchUpload(r)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, fmt.Sprintf("Patch too large (max %d bytes)", maxPatchBytes), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Invalid upload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("[PATCH] Applying %d byte patch to goal %s", len(patch), goalID)
+		result, data := operations.ApplyGoalPatch(operations.ApplyPatchOptions{
+			GoalID:  goalID,
+			Project: r.URL.Query().Get("project"),
+			Patch:   patch,
+			VegaDir: h.Dir(),
+		})
+		if !result.Success {
+			writePatchError(w, result)
+			return
+		}
+		log.Printf("[PATCH] Applied %d commit(s) to goal %s", data.Applied, goalID)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(data)
+	}
+}
+
+// readPatchUpload returns the patch of a raw body or the concatenated "file"
+// parts of a multipart upload
+func readPatchUpload(r *http.Request) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return io.ReadAll(r.Body)
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	var patch bytes.Buffer
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			_, err = io.Copy(&patch, part)
+			if err == nil && patch.Len() > 0 && patch.Bytes()[patch.Len()-1] != '\n' {
+				patch.WriteByte('\n')
+			}
+		}
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return patch.Bytes(), nil
+}
+
+func writePatchError(w http.ResponseWriter, result *operations.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	switch result.Error.Code {
+	case "goal_not_found", "worktree_not_found":
+		w.WriteHeader(http.StatusNotFound)
+	case "patch_conflict", "uncommitted_changes":
+		w.WriteHeader(http.StatusConflict)
+	case "invalid_patch", "patch_unsupported", "invalid_input":
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error":   result.Error,
+	})
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// PresenceRequest is the body for PUT /api/presence
+type PresenceRequest struct {
+	GoalID string `json:"goal_id"` // Goal the user has open; "" when none
+}
+
+// handlePresence handles /api/presence. GET lists the users with an open
+// event stream (?goal_id= only those with that goal open); PUT records the
+// goal the requesting user has open, which requires an open stream.
+func handlePresence(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			users := h.Presence()
+			if goalID := r.URL.Query().Get("goal_id"); goalID != "" {
+				watching := make([]hub.UserPresence, 0, len(users))
+				for _, p := range users {
+					if p.Watching == goalID {
+						watching = append(watching, p)
+					}
+				}
+				users = watching
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(users)
+
+		case http.MethodPut:
+			var req PresenceRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			presence, online := h.SetWatching(requestUser(r), req.GoalID)
+			if !online {
+				http.Error(w, "User has no open event stream", http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(presence)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
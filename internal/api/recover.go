@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// IncidentHeader carries the incident ID of a request that failed with a panic
+const IncidentHeader = "X-Vega-Incident"
+
+// InternalErrorResponse is the body of a 500 returned for a panic
+type InternalErrorResponse struct {
+	Error      string `json:"error"` // Always "internal_error"
+	Message    string `json:"message"`
+	IncidentID string `json:"incident_id"`
+}
+
+// RecoverHandler wraps a handler so a panic fails only its own request: the
+// stack is logged with an incident ID, the hub's error count goes up and the
+// client gets a 500 naming the incident. If the handler already started its
+// response, the 500 can't be sent and the response is cut short instead.
+func RecoverHandler(next http.Handler, h *hub.Hub) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recoverWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				// Deliberate abort, handled by net/http
+				panic(v)
+			}
+
+			incident := hub.Incident{
+				ID:     newIncidentID(),
+				Time:   time.Now(),
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Error:  fmt.Sprint(v),
+			}
+			log.Printf("[PANIC] incident %s: %s %s: %v\n%s", incident.ID, r.Method, r.URL.Path, v, debug.Stack())
+			h.RecordIncident(incident)
+
+			if rw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			w.Header().Set(IncidentHeader, incident.ID)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(InternalErrorResponse{
+				Error:      "internal_error",
+				Message:    "Internal server error; report incident " + incident.ID,
+				IncidentID: incident.ID,
+			})
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// newIncidentID returns a short random ID, e.g. "inc-3f9a1c2b7d4e"
+func newIncidentID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "inc-" + hex.EncodeToString(b)
+}
+
+// recoverWriter notes whether the response has started
+type recoverWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoverWriter) WriteHeader(status int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recoverWriter) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher for streaming handlers such as SSE
+func (rw *recoverWriter) Flush() {
+	rw.wroteHeader = true
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for handlers that need the raw connection
+func (rw *recoverWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := rw.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *recoverWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoverHandler(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("fine")) })
+	mux.HandleFunc("/boom", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+	mux.HandleFunc("/late", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		panic("late")
+	})
+	handler := CompressHandler(RecoverHandler(mux, h), DefaultCompressMinSize)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/ok", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "fine" {
+		t.Errorf("expected the response through, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+	var body InternalErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusInternalServerError || body.Error != "internal_error" || body.IncidentID == "" {
+		t.Fatalf("expected a structured 500, got %d %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get(IncidentHeader) != body.IncidentID {
+		t.Errorf("expected the incident in %s, got %q", IncidentHeader, w.Header().Get(IncidentHeader))
+	}
+	stats := h.ErrorStats()
+	if stats.Panics != 1 || len(stats.Recent) != 1 || stats.Recent[0].ID != body.IncidentID || stats.Recent[0].Path != "/boom" {
+		t.Errorf("unexpected error stats %+v", stats)
+	}
+
+	// Once the response has started, it can only be aborted
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler, got %v", v)
+		}
+		if h.ErrorStats().Panics != 2 {
+			t.Errorf("expected the late panic counted, got %+v", h.ErrorStats())
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/late", nil))
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// RenameGoalRequest is the body for POST /api/goals/:id/rename
+type RenameGoalRequest struct {
+	Title        string `json:"title"`
+	RenameBranch bool   `json:"rename_branch,omitempty"` // git branch -m to match the title
+	MoveWorktree bool   `json:"move_worktree,omitempty"` // Move the worktree directory to match
+}
+
+// handleGoalRename handles POST /api/goals/:id/rename. Moving the worktree
+// is refused while an executor runs in it.
+func handleGoalRename(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req RenameGoalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.MoveWorktree {
+			for _, e := range h.GetActiveExecutors() {
+				if e.GoalID == goalID {
+					w.WriteHeader(http.StatusConflict)
+					json.NewEncoder(w).Encode(operations.Result{
+						Success: false,
+						Error: &operations.ErrorInfo{
+							Code:    "executor_running",
+							Message: fmt.Sprintf("Cannot move the worktree of goal %s while an executor is running", goalID),
+							Details: map[string]string{"goal_id": goalID, "session_id": e.SessionID},
+						},
+					})
+					return
+				}
+			}
+		}
+
+		result, data := operations.RenameGoal(operations.RenameOptions{
+			GoalID:       goalID,
+			Title:        req.Title,
+			RenameBranch: req.RenameBranch,
+			MoveWorktree: req.MoveWorktree,
+			VegaDir:      h.Dir(),
+		})
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "worktree_exists":
+				w.WriteHeader(http.StatusConflict)
+			case "invalid_input", "rename_unsupported":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		log.Printf("[RENAME] Goal %s renamed: %q -> %q", goalID, data.OldTitle, data.Title)
+		h.EmitEvent("goal_renamed", map[string]interface{}{
+			"goal_id":   goalID,
+			"old_title": data.OldTitle,
+			"title":     data.Title,
+			"branch":    data.Branch,
+			"worktree":  data.Worktree,
+		})
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// RetargetGoalRequest is the body for POST /api/goals/:id/retarget
+type RetargetGoalRequest struct {
+	BaseBranch string `json:"base_branch"`
+	Project    string `json:"project,omitempty"`  // Defaults to the goal's project with a worktree
+	Strategy   string `json:"strategy,omitempty"` // "rebase" (default) or "merge"
+}
+
+// handleGoalRetarget handles POST /api/goals/:id/retarget - moves the goal
+// branch onto another base branch. Refused while an executor is working in
+// the worktree.
+func handleGoalRetarget(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req RetargetGoalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		for _, e := range h.GetActiveExecutors() {
+			if e.GoalID == goalID {
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(operations.Result{
+					Success: false,
+					Error: &operations.ErrorInfo{
+						Code:    "executor_running",
+						Message: fmt.Sprintf("Cannot retarget goal %s while an executor is running", goalID),
+						Details: map[string]string{"goal_id": goalID, "session_id": e.SessionID},
+					},
+				})
+				return
+			}
+		}
+
+		result, data := operations.RetargetGoal(operations.RetargetOptions{
+			GoalID:     goalID,
+			Project:    req.Project,
+			BaseBranch: req.BaseBranch,
+			Strategy:   req.Strategy,
+			VegaDir:    h.Dir(),
+		})
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found", "worktree_not_found", "branch_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "uncommitted_changes", "retarget_failed":
+				w.WriteHeader(http.StatusConflict)
+			case "invalid_input", "plain_project", "retarget_unsupported":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		if data.Strategy != "" {
+			log.Printf("[RETARGET] Goal %s moved from %s to %s (%s)", goalID, data.OldBaseBranch, data.BaseBranch, data.Strategy)
+			h.EmitEvent("goal_retargeted", map[string]interface{}{
+				"goal_id":         goalID,
+				"project":         data.Project,
+				"old_base_branch": data.OldBaseBranch,
+				"base_branch":     data.BaseBranch,
+				"strategy":        data.Strategy,
+			})
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// ReviewGoalRequest is the request body for POST /api/goals/:id/review
+type ReviewGoalRequest struct {
+	Decision string `json:"decision"`          // "approve" or "request_changes"
+	Comment  string `json:"comment,omitempty"` // Required when requesting changes
+	User     string `json:"user,omitempty"`    // Fallback when X-Vega-User is not set
+}
+
+// ReviewResponse is the response for /api/goals/:id/review
+type ReviewResponse struct {
+	GoalID string        `json:"goal_id"`
+	State  string        `json:"state"`
+	Review *goals.Review `json:"review,omitempty"` // Latest review, if any
+}
+
+// handleGoalReview handles /api/goals/:id/review
+// GET - the goal's latest review
+// POST - approve the goal or request changes
+func handleGoalReview(h *hub.Hub, p *goals.Parser, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if _, err := p.ParseGoalDetail(goalID); err != nil {
+			http.Error(w, "Goal not found", http.StatusNotFound)
+			return
+		}
+		sm := h.StateManager()
+
+		if r.Method == http.MethodPost {
+			var req ReviewGoalRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+			to, err := goals.ReviewState(req.Decision)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			user := actingUser(r, req.User)
+
+			from, _ := sm.GetState(goalID)
+			if _, err := h.ReviewGoal(goalID, req.Decision, req.Comment, user); err != nil {
+				var invalid *goals.InvalidTransitionError
+				var vetoed *goals.TransitionVetoedError
+				resp := StateTransitionErrorResponse{Error: err.Error(), GoalID: goalID, From: from, To: to}
+				switch {
+				case errors.As(err, &invalid):
+					resp.Allowed = goals.AllowedTransitions(invalid.From)
+				case errors.As(err, &vetoed):
+					resp.HookID = vetoed.HookID
+					resp.Reason = vetoed.Reason
+				default:
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(resp)
+				return
+			}
+
+			log.Printf("[REVIEW] Goal %s: %s -> %s (reviewer %q)", goalID, from, to, user)
+			h.EmitEvent("goal_state_changed", map[string]interface{}{
+				"goal_id": goalID,
+				"from":    from,
+				"to":      to,
+				"reason":  req.Decision,
+				"user":    user,
+			})
+		}
+
+		state, err := sm.GetState(goalID)
+		if err != nil {
+			http.Error(w, "Failed to get state: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		review, err := h.GetReview(goalID)
+		if err != nil {
+			http.Error(w, "Failed to get review: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ReviewResponse{GoalID: goalID, State: string(state), Review: review})
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// ShareRequest is the request body for POST /api/goals/:id/shares
+type ShareRequest struct {
+	Label          string `json:"label,omitempty"`            // Who or what the link is for
+	ExpiresInHours int    `json:"expires_in_hours,omitempty"` // Default 168 (7 days), at most 2160 (90 days)
+	User           string `json:"user,omitempty"`             // Fallback when X-Vega-User is not set
+}
+
+// ShareResponse is a newly created share link. Token and URL are only
+// returned here.
+type ShareResponse struct {
+	hub.ShareLink
+	Token string `json:"token"`
+	URL   string `json:"url"` // Read-only goal detail; append /chat for the chat
+}
+
+// handleGoalShares handles /api/goals/:id/shares - read-only links to the goal
+// for people without hub access:
+//   - GET    /shares             - the goal's links, newest first
+//   - POST   /shares             - create a link
+//   - DELETE /shares/:sid        - revoke a link
+//   - GET    /shares/:sid/access - requests made with a link, newest first
+//     (?limit=, default 100)
+func handleGoalShares(h *hub.Hub, goalID, linkID, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case linkID == "" && r.Method == http.MethodGet:
+			links, err := h.GetShareLinks(goalID)
+			if err != nil {
+				http.Error(w, "Failed to read share links: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(links)
+
+		case linkID == "" && r.Method == http.MethodPost:
+			var req ShareRequest
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "Invalid JSON", http.StatusBadRequest)
+					return
+				}
+			}
+			user := actingUser(r, req.User)
+			link, token, err := h.CreateShareLink(goalID, req.Label, user, time.Duration(req.ExpiresInHours)*time.Hour)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(ShareResponse{
+				ShareLink: *link,
+				Token:     token,
+				URL:       requestBaseURL(r) + "/api/share/" + token,
+			})
+
+		case linkID != "" && action == "" && r.Method == http.MethodDelete:
+			link, err := h.RevokeShareLink(goalID, linkID, requestUser(r))
+			if errors.Is(err, hub.ErrShareNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			} else if err != nil {
+				http.Error(w, "Failed to revoke share link: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(link)
+
+		case linkID != "" && action == "access" && r.Method == http.MethodGet:
+			limit := 100
+			if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+				limit = l
+			}
+			accesses, err := h.GetShareAccessLog(goalID, linkID, limit)
+			if err != nil {
+				http.Error(w, "Failed to read share access log: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(accesses)
+
+		case linkID != "" && action != "" && action != "access":
+			http.Error(w, "Not found", http.StatusNotFound)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// handleShare handles /api/share/:token - what a share link grants, without
+// an account: GET the goal's detail, or GET /api/share/:token/chat for its
+// chat (same query parameters as /api/goals/:id/chat). Every request is
+// recorded in the access log.
+func handleShare(h *hub.Hub, p *goals.Parser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, view, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/share/"), "/")
+		if r.Method != http.MethodGet {
+			http.Error(w, "Share links are read-only", http.StatusMethodNotAllowed)
+			return
+		}
+		if view != "" && view != "chat" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		link, err := h.ResolveShareToken(token)
+		switch {
+		case errors.Is(err, hub.ErrShareExpired), errors.Is(err, hub.ErrShareRevoked):
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		case errors.Is(err, hub.ErrShareInvalid):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, "Failed to check share link: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if view == "" {
+			view = "detail"
+		}
+		if err := h.RecordShareAccess(link, view, r.RemoteAddr, r.UserAgent()); err != nil {
+			log.Printf("[SHARE] Failed to log access to %s: %v", link.ID, err)
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Robots-Tag", "noindex")
+		if view == "chat" {
+			handleGoalChat(h, link.GoalID)(w, r)
+			return
+		}
+		handleGoalDetail(h, p, link.GoalID)(w, r)
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/slack"
+)
+
+// maxSlackBody caps the size of an inbound Slack request
+const maxSlackBody = 1 << 20
+
+// slackClient posts follow-ups to Slack response URLs
+var slackClient = &http.Client{Timeout: 10 * time.Second}
+
+// RegisterSlackRoutes sets up the Slack interactivity and slash command
+// endpoints and, if a webhook URL is configured, posts new questions to Slack.
+// Nothing is registered without a signing secret, since requests can't be verified.
+func RegisterSlackRoutes(mux *http.ServeMux, h *hub.Hub, cfg slack.Config) {
+	if !cfg.Enabled() {
+		return
+	}
+	mux.HandleFunc("/api/slack/interactions", handleSlackInteraction(h, cfg))
+	mux.HandleFunc("/api/slack/commands", handleSlackCommand(h, cfg))
+	if cfg.WebhookURL != "" {
+		h.Bus().Subscribe(slack.NewNotifier(cfg))
+	}
+}
+
+// readSlackRequest verifies the request signature and returns the parsed form
+func readSlackRequest(w http.ResponseWriter, r *http.Request, cfg slack.Config) (url.Values, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := slack.Verify(cfg.SigningSecret, r.Header, body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Invalid form body", http.StatusBadRequest)
+		return nil, false
+	}
+	return form, true
+}
+
+// writeSlackMessage writes a message as the immediate response to Slack
+func writeSlackMessage(w http.ResponseWriter, msg slack.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
+
+// handleSlackInteraction handles POST /api/slack/interactions - button clicks
+// on posted questions answer them as the clicking Slack user
+func handleSlackInteraction(h *hub.Hub, cfg slack.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form, ok := readSlackRequest(w, r, cfg)
+		if !ok {
+			return
+		}
+
+		var payload slack.InteractionPayload
+		if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+			http.Error(w, "Invalid payload", http.StatusBadRequest)
+			return
+		}
+		// Slack expects a 200 for every interaction, including ones we ignore
+		w.WriteHeader(http.StatusOK)
+		if payload.Type != "block_actions" {
+			return
+		}
+
+		for _, action := range payload.Actions {
+			questionID, optionID, ok := slack.ParseAnswerAction(action.ActionID, action.Value)
+			if !ok {
+				continue
+			}
+			reply := answerFromSlack(h, questionID, payload.UserName(), &hub.StructuredAnswer{OptionIDs: []string{optionID}})
+			if payload.ResponseURL != "" {
+				go func() {
+					if err := slack.Post(slackClient, payload.ResponseURL, reply); err != nil {
+						log.Printf("[SLACK] Failed to update message for question %s: %v", questionID, err)
+					}
+				}()
+			}
+		}
+	}
+}
+
+// answerFromSlack answers a question and returns the message to show in Slack
+func answerFromSlack(h *hub.Hub, questionID, user string, answer *hub.StructuredAnswer) slack.Message {
+	q, _ := h.GetQuestion(questionID)
+	if err := h.AnswerStructuredAs(questionID, user, answer); err != nil {
+		var conflict *hub.ConflictError
+		if errors.As(err, &conflict) || errors.Is(err, hub.ErrInvalidAnswer) {
+			return slack.EphemeralMessage("Not answered: " + err.Error())
+		}
+		return slack.EphemeralMessage(fmt.Sprintf("Question %s is no longer pending.", questionID))
+	}
+	return slack.AnsweredMessage(q, user, answer.Render(q))
+}
+
+// handleSlackCommand handles POST /api/slack/commands - the /vega slash command:
+//
+//	/vega [list]              pending questions
+//	/vega answer <id> <text>  answer a question with free text
+func handleSlackCommand(h *hub.Hub, cfg slack.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form, ok := readSlackRequest(w, r, cfg)
+		if !ok {
+			return
+		}
+		user := "slack:" + form.Get("user_name")
+		fields := strings.Fields(form.Get("text"))
+
+		sub := "list"
+		if len(fields) > 0 {
+			sub = fields[0]
+		}
+		switch sub {
+		case "list":
+			writeSlackMessage(w, slack.QuestionList(h.GetPendingQuestions()))
+		case "answer":
+			if len(fields) < 3 {
+				writeSlackMessage(w, slack.EphemeralMessage("Usage: /vega answer <question-id> <answer>"))
+				return
+			}
+			text := strings.Join(fields[2:], " ")
+			msg := answerFromSlack(h, fields[1], user, &hub.StructuredAnswer{Text: text})
+			if msg.ReplaceOriginal {
+				// Slash commands have no original message: show the result in the channel
+				msg.ReplaceOriginal = false
+				msg.ResponseType = "in_channel"
+			}
+			writeSlackMessage(w, msg)
+		default:
+			writeSlackMessage(w, slack.EphemeralMessage("Usage: /vega [list] | /vega answer <question-id> <answer>"))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/slack"
+)
+
+const testSlackSecret = "test-signing-secret"
+
+// slackRequest builds a signed Slack form POST
+func slackRequest(path string, form url.Values) *http.Request {
+	body := form.Encode()
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slack.Sign(testSlackSecret, timestamp, []byte(body)))
+	return req
+}
+
+// askAsync registers a question and returns it once pending
+func askAsync(t *testing.T, h *hub.Hub, q *hub.Question) chan *hub.StructuredAnswer {
+	t.Helper()
+	answered := make(chan *hub.StructuredAnswer, 1)
+	go func() {
+		_, a := h.AskStructured(q)
+		answered <- a
+	}()
+	for i := 0; i < 100; i++ {
+		if _, ok := h.GetQuestion(q.ID); ok {
+			return answered
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("question never became pending")
+	return nil
+}
+
+func TestHandleSlackInteraction(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	cfg := slack.Config{SigningSecret: testSlackSecret}
+
+	// Capture the follow-up posted to response_url
+	updates := make(chan slack.Message, 1)
+	responseSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.Message
+		json.NewDecoder(r.Body).Decode(&msg)
+		updates <- msg
+	}))
+	defer responseSrv.Close()
+
+	answered := askAsync(t, h, &hub.Question{
+		ID:       "q1",
+		GoalID:   "abc1234",
+		Question: "Which database?",
+		Options:  []hub.Option{{ID: "pg", Label: "Postgres"}, {ID: "sqlite", Label: "SQLite"}},
+	})
+
+	payload := `{"type":"block_actions","user":{"id":"U1","username":"alex"},` +
+		`"actions":[{"action_id":"answer:sqlite","value":"q1:sqlite"}],"response_url":"` + responseSrv.URL + `"}`
+	w := httptest.NewRecorder()
+	handleSlackInteraction(h, cfg)(w, slackRequest("/api/slack/interactions", url.Values{"payload": {payload}}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	select {
+	case a := <-answered:
+		if len(a.OptionIDs) != 1 || a.OptionIDs[0] != "sqlite" {
+			t.Errorf("expected the sqlite option, got %+v", a)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("question was not answered")
+	}
+	select {
+	case msg := <-updates:
+		if !msg.ReplaceOriginal || !strings.Contains(msg.Text, "slack:alex") {
+			t.Errorf("unexpected follow-up: %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no follow-up posted to response_url")
+	}
+}
+
+func TestHandleSlackInteraction_BadSignature(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	req := slackRequest("/api/slack/interactions", url.Values{"payload": {"{}"}})
+	req.Header.Set("X-Slack-Signature", "v0=deadbeef")
+
+	w := httptest.NewRecorder()
+	handleSlackInteraction(h, slack.Config{SigningSecret: testSlackSecret})(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", w.Code)
+	}
+}
+
+func TestHandleSlackCommand(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	cfg := slack.Config{SigningSecret: testSlackSecret}
+	answered := askAsync(t, h, &hub.Question{ID: "q2", GoalID: "abc1234", Question: "Ship it?"})
+
+	w := httptest.NewRecorder()
+	handleSlackCommand(h, cfg)(w, slackRequest("/api/slack/commands", url.Values{"command": {"/vega"}, "user_name": {"sam"}}))
+	var msg slack.Message
+	json.Unmarshal(w.Body.Bytes(), &msg)
+	if msg.ResponseType != "ephemeral" || len(msg.Blocks) == 0 || !strings.Contains(msg.Blocks[0].Text.Text, "q2") {
+		t.Errorf("expected q2 in the question list, got %+v", msg)
+	}
+
+	w = httptest.NewRecorder()
+	handleSlackCommand(h, cfg)(w, slackRequest("/api/slack/commands", url.Values{
+		"command": {"/vega"}, "user_name": {"sam"}, "text": {"answer q2 yes, ship it"},
+	}))
+	select {
+	case a := <-answered:
+		if a.Text != "yes, ship it" {
+			t.Errorf("expected text answer, got %+v", a)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("question was not answered")
+	}
+
+	// Answering again reports the conflict
+	w = httptest.NewRecorder()
+	handleSlackCommand(h, cfg)(w, slackRequest("/api/slack/commands", url.Values{
+		"command": {"/vega"}, "user_name": {"kim"}, "text": {"answer q2 no"},
+	}))
+	json.Unmarshal(w.Body.Bytes(), &msg)
+	if !strings.Contains(msg.Text, "already answered by slack:sam") {
+		t.Errorf("expected conflict message, got %q", msg.Text)
+	}
+}
+
+func TestRegisterSlackRoutes_Disabled(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	mux := http.NewServeMux()
+	RegisterSlackRoutes(mux, h, slack.Config{})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/slack/interactions", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 without a signing secret, got %d", w.Code)
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/jobs"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// SplitGoalRequest is the request body for POST /api/goals/:id/split
+type SplitGoalRequest struct {
+	Mode       string   `json:"mode,omitempty"`   // "phases" (default) or "tasks"
+	Phases     []int    `json:"phases,omitempty"` // Phases to split (default: all with open tasks)
+	Tasks      []string `json:"tasks,omitempty"`  // "<phase>.<task>" refs for mode "tasks"
+	Preview    bool     `json:"preview,omitempty"`
+	NoWorktree bool     `json:"no_worktree,omitempty"`
+	Async      bool     `json:"async,omitempty"` // Respond 202 with the job instead of waiting
+}
+
+// handleGoalSplit handles POST /api/goals/:id/split - creates child goals
+// from the goal's phases or tasks. With preview the children are only planned.
+func handleGoalSplit(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req SplitGoalRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+		opts := operations.SplitGoalOptions{
+			GoalID:     goalID,
+			Mode:       req.Mode,
+			Phases:     req.Phases,
+			Tasks:      req.Tasks,
+			Preview:    req.Preview,
+			NoWorktree: req.NoWorktree,
+			VegaDir:    h.Dir(),
+		}
+
+		var result *operations.Result
+		var data *operations.SplitResult
+		if req.Preview {
+			result, data = operations.SplitGoal(opts)
+		} else {
+			log.Printf("[SPLIT] Splitting goal %s (mode=%q, phases=%v, tasks=%v)", goalID, req.Mode, req.Phases, req.Tasks)
+			spec := jobs.Spec{Type: "split_goal", GoalID: goalID, User: requestUser(r)}
+			if !runAsJob(w, r, h, spec, req.Async, func(ctx context.Context, progress func(step string)) (interface{}, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				opts.Progress = progress
+				result, data = operations.SplitGoal(opts)
+				if data != nil {
+					for _, child := range data.Children {
+						if child.GoalID == "" {
+							continue
+						}
+						h.EmitEvent("goal_created", map[string]interface{}{
+							"goal_id":   child.GoalID,
+							"title":     child.Title,
+							"project":   data.Project,
+							"parent_id": goalID,
+						})
+					}
+				}
+				if !result.Success {
+					return result, resultError(result)
+				}
+				log.Printf("[SPLIT] Goal %s split into %d child goals", goalID, len(data.Children))
+				return data, nil
+			}) {
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if result == nil {
+			result = canceledResult()
+		}
+		if !result.Success {
+			status := http.StatusBadRequest
+			switch result.Error.Code {
+			case "goal_not_found":
+				status = http.StatusNotFound
+			case "goal_not_active", "canceled":
+				status = http.StatusConflict
+			}
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+		})
+	}
+}
@@ -4,11 +4,100 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lasmarois/vega-hub/internal/hub"
 )
 
-// handleSSE handles GET /api/events - Server-Sent Events stream
+// sseHeartbeatInterval is how often an idle stream gets a comment line so
+// proxies don't close it
+var sseHeartbeatInterval = 15 * time.Second
+
+// sseFilter selects which events a client receives. Empty fields match everything.
+type sseFilter struct {
+	goalIDs map[string]bool
+	types   map[string]bool
+}
+
+// parseSSEFilter reads ?goal_id=a,b and ?types=question,goal_updated
+func parseSSEFilter(r *http.Request) sseFilter {
+	return sseFilter{
+		goalIDs: splitSet(r.URL.Query().Get("goal_id")),
+		types:   splitSet(r.URL.Query().Get("types")),
+	}
+}
+
+func splitSet(value string) map[string]bool {
+	if value == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// match reports whether an event with the given type and JSON data passes the
+// filter. With a goal filter, only events naming one of the goals (goal_id,
+// or goal_ids for batched changes) are sent.
+func (f sseFilter) match(eventType string, data []byte) bool {
+	if f.types != nil && !f.types[eventType] {
+		return false
+	}
+	if f.goalIDs == nil {
+		return true
+	}
+	var ids struct {
+		GoalID  string   `json:"goal_id"`
+		GoalIDs []string `json:"goal_ids"`
+	}
+	json.Unmarshal(data, &ids)
+	if f.goalIDs[ids.GoalID] {
+		return true
+	}
+	for _, id := range ids.GoalIDs {
+		if f.goalIDs[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// lastEventID reads the Last-Event-ID header, or ?last_event_id= for clients
+// that can't set headers
+func lastEventID(r *http.Request) (uint64, bool) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	return id, err == nil
+}
+
+// writeSSE writes one event if it passes the filter
+func writeSSE(w http.ResponseWriter, filter sseFilter, event hub.Event) {
+	data, err := json.Marshal(event.Data)
+	if err != nil || !filter.match(event.Type, data) {
+		return
+	}
+	if event.ID > 0 {
+		fmt.Fprintf(w, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
+
+// handleSSE handles GET /api/events - Server-Sent Events stream.
+// Query params: goal_id and types (comma-separated) filter events;
+// Last-Event-ID (header or last_event_id param) replays missed events;
+// user (or X-Vega-User) names who is online (see presence.go).
 func handleSSE(h *hub.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set SSE headers
@@ -24,21 +113,38 @@ func handleSSE(h *hub.Hub) http.HandlerFunc {
 			return
 		}
 
-		// Subscribe to events
-		events := h.Subscribe()
+		filter := parseSSEFilter(r)
+		lastID, resuming := lastEventID(r)
+
+		// The stream counts as the user being online until it closes
+		defer h.Connect(requestUser(r))()
+
+		// Subscribe to events, collecting anything missed since the client's last event
+		events, missed, complete := h.SubscribeSince(lastID)
 		defer h.Unsubscribe(events)
 
 		// Send initial connection event
 		fmt.Fprintf(w, "event: connected\ndata: {\"status\":\"connected\"}\n\n")
-		flusher.Flush()
 
-		// Send current pending questions
-		for _, q := range h.GetPendingQuestions() {
-			data, _ := json.Marshal(q)
-			fmt.Fprintf(w, "event: question\ndata: %s\n\n", data)
+		if resuming && complete {
+			for _, event := range missed {
+				writeSSE(w, filter, event)
+			}
+		} else {
+			if resuming {
+				// Missed events fell out of the buffer: the client should reload
+				fmt.Fprintf(w, "event: resync\ndata: {\"reason\":\"events_dropped\"}\n\n")
+			}
+			// Send current pending questions
+			for _, q := range h.GetPendingQuestions() {
+				writeSSE(w, filter, hub.Event{Type: "question", Data: q})
+			}
 		}
 		flusher.Flush()
 
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+
 		// Stream events
 		for {
 			select {
@@ -46,11 +152,11 @@ func handleSSE(h *hub.Hub) http.HandlerFunc {
 				if !ok {
 					return
 				}
-				data, err := json.Marshal(event.Data)
-				if err != nil {
-					continue
-				}
-				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+				writeSSE(w, filter, event)
+				flusher.Flush()
+
+			case <-heartbeat.C:
+				fmt.Fprintf(w, ": heartbeat\n\n")
 				flusher.Flush()
 
 			case <-r.Context().Done():
@@ -59,3 +165,82 @@ func handleSSE(h *hub.Hub) http.HandlerFunc {
 		}
 	}
 }
+
+// handleEventRoutes handles /api/events/log and /api/events/stats
+func handleEventRoutes(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/api/events/") {
+		case "log":
+			handleEventLog(h)(w, r)
+		case "stats":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"last_event_id": h.LastEventID(),
+				"consumers":     h.Bus().Consumers(),
+				"types":         h.EventStats(),
+			})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}
+}
+
+// handleEventLog handles GET /api/events/log - events from the persistent log.
+// Query params: since (event ID; pages forward from it, otherwise the newest
+// events are returned), limit (default 100, max 1000), and the goal_id and
+// types filters of the SSE stream.
+func handleEventLog(h *hub.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var since uint64
+		sinceParam := r.URL.Query().Get("since")
+		if sinceParam != "" {
+			n, err := strconv.ParseUint(sinceParam, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid since", http.StatusBadRequest)
+				return
+			}
+			since = n
+		}
+		limit := 100
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		if limit > 1000 {
+			limit = 1000
+		}
+
+		events, err := hub.ReadEventLog(hub.EventLogPath(h.Dir()), since, 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		filter := parseSSEFilter(r)
+		matched := []hub.Event{}
+		for _, e := range events {
+			data, _ := json.Marshal(e.Data)
+			if filter.match(e.Type, data) {
+				matched = append(matched, e)
+			}
+		}
+		if len(matched) > limit {
+			if sinceParam != "" {
+				matched = matched[:limit]
+			} else {
+				matched = matched[len(matched)-limit:]
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(matched)
+	}
+}
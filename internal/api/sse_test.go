@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+func TestSSEFilterMatch(t *testing.T) {
+	tests := []struct {
+		query     string
+		eventType string
+		data      string
+		expected  bool
+	}{
+		{"", "goal_updated", `{"goal_id":"abc"}`, true},
+		{"goal_id=abc", "goal_updated", `{"goal_id":"abc"}`, true},
+		{"goal_id=abc", "goal_updated", `{"goal_id":"def"}`, false},
+		{"goal_id=abc", "registry_updated", `{}`, false},
+		{"goal_id=def,abc", "files_changed", `{"goal_ids":["xyz","abc"]}`, true},
+		{"types=question,answered", "question", `{"goal_id":"abc"}`, true},
+		{"types=question,answered", "goal_updated", `{"goal_id":"abc"}`, false},
+		{"types=question&goal_id=abc", "question", `{"goal_id":"def"}`, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/events?"+tt.query, nil)
+		if got := parseSSEFilter(r).match(tt.eventType, []byte(tt.data)); got != tt.expected {
+			t.Errorf("filter %q on %s %s = %v, want %v", tt.query, tt.eventType, tt.data, got, tt.expected)
+		}
+	}
+}
+
+// readSSE connects to the stream and returns its raw lines until one
+// containing until is seen
+func readSSE(t *testing.T, url string, header http.Header, until string) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if strings.Contains(scanner.Text(), until) {
+			return lines
+		}
+	}
+	t.Fatalf("stream ended before %q; got %v", until, lines)
+	return nil
+}
+
+func TestHandleSSE_ReplayAndFilter(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	srv := httptest.NewServer(handleSSE(h))
+	defer srv.Close()
+
+	// Broadcast a few events through a registered executor
+	h.RegisterExecutor("abc1234", "s1", "/tmp", "")
+	h.RegisterExecutor("def5678", "s2", "/tmp", "")
+	h.StopExecutor("abc1234", "s1", "done")
+
+	// Replay everything after event 1, filtered to one goal
+	header := http.Header{"Last-Event-Id": {"1"}}
+	lines := readSSE(t, srv.URL+"?goal_id=abc1234", header, "executor_stopped")
+	joined := strings.Join(lines, "\n")
+	if strings.Contains(joined, "def5678") {
+		t.Errorf("filtered stream contains another goal's events:\n%s", joined)
+	}
+	if strings.Contains(joined, "id: 1\n") {
+		t.Errorf("replay should start after the last event ID:\n%s", joined)
+	}
+	// Starting and stopping an executor also publish goal_updated, so
+	// executor_stopped is event 5
+	if !strings.Contains(joined, "id: 5\nevent: executor_stopped") {
+		t.Errorf("expected replayed event 5:\n%s", joined)
+	}
+
+	// A stale ID asks the client to resync
+	header = http.Header{"Last-Event-Id": {"9999"}}
+	readSSE(t, srv.URL, header, "event: resync")
+}
+
+func TestHandleSSE_Heartbeat(t *testing.T) {
+	old := sseHeartbeatInterval
+	sseHeartbeatInterval = 20 * time.Millisecond
+	defer func() { sseHeartbeatInterval = old }()
+
+	srv := httptest.NewServer(handleSSE(hub.New(t.TempDir())))
+	defer srv.Close()
+	readSSE(t, srv.URL, nil, ": heartbeat")
+}
+
+func TestHandleEventRoutes(t *testing.T) {
+	h, _, _ := setupTestEnv(t)
+	for i, goal := range []string{"abc1234", "def5678", "abc1234"} {
+		h.EmitEvent("goal_updated", map[string]interface{}{"goal_id": goal, "n": i})
+	}
+
+	w := httptest.NewRecorder()
+	handleEventRoutes(h)(w, httptest.NewRequest("GET", "/api/events/log?goal_id=abc1234", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var events []hub.Event
+	json.Unmarshal(w.Body.Bytes(), &events)
+	if len(events) != 2 || events[0].ID != 1 || events[1].ID != 3 {
+		t.Errorf("expected events 1 and 3, got %+v", events)
+	}
+
+	w = httptest.NewRecorder()
+	handleEventRoutes(h)(w, httptest.NewRequest("GET", "/api/events/log?since=1&limit=1", nil))
+	json.Unmarshal(w.Body.Bytes(), &events)
+	if len(events) != 1 || events[0].ID != 2 {
+		t.Errorf("expected event 2, got %+v", events)
+	}
+
+	w = httptest.NewRecorder()
+	handleEventRoutes(h)(w, httptest.NewRequest("GET", "/api/events/log?limit=x", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handleEventRoutes(h)(w, httptest.NewRequest("GET", "/api/events/stats", nil))
+	var stats struct {
+		LastEventID uint64                    `json:"last_event_id"`
+		Types       map[string]hub.EventStats `json:"types"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if stats.LastEventID != 3 || stats.Types["goal_updated"].Count != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// handleGoalTimeline handles GET /api/goals/:id/timeline - the goal's activity
+// bucketed per hour or day (?bucket=), optionally only the last ?days= and in
+// the ?tz= time zone
+func handleGoalTimeline(h *hub.Hub, goalID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		opts := operations.GoalTimelineOptions{
+			GoalID:  goalID,
+			VegaDir: h.Dir(),
+			Bucket:  query.Get("bucket"),
+		}
+		if d := query.Get("days"); d != "" {
+			n, err := strconv.Atoi(d)
+			if err != nil || n <= 0 {
+				http.Error(w, "Invalid days", http.StatusBadRequest)
+				return
+			}
+			opts.Since = time.Now().AddDate(0, 0, -n)
+		}
+		if tz := query.Get("tz"); tz != "" {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				http.Error(w, "Invalid tz", http.StatusBadRequest)
+				return
+			}
+			opts.Loc = loc
+		}
+
+		result, data := operations.GoalTimeline(opts)
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Success {
+			switch result.Error.Code {
+			case "goal_not_found":
+				w.WriteHeader(http.StatusNotFound)
+			case "invalid_bucket", "invalid_input":
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   result.Error,
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(data)
+	}
+}
@@ -0,0 +1,52 @@
+// Package atomicfile replaces files atomically. The new content is written
+// to a uniquely named temporary file next to the target and renamed over it,
+// so readers see the old file or the new one, never a partial write, and
+// concurrent writers don't share (and clobber) one temporary file.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to path atomically, like os.WriteFile
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return Write(path, perm, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+// Write replaces path with what write puts in the temporary file it is given.
+// If write fails, path is left as it was and the temporary file is removed.
+func Write(path string, perm os.FileMode, write func(f *os.File) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	done := false
+	defer func() {
+		if !done {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	done = true
+	return nil
+}
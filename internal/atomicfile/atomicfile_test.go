@@ -0,0 +1,69 @@
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+
+	if err := WriteFile(path, []byte("one"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := WriteFile(path, []byte("two"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	info, _ := os.Stat(path)
+	if string(data) != "two" || info.Mode().Perm() != 0600 {
+		t.Errorf("got %q with mode %v", data, info.Mode().Perm())
+	}
+
+	// A failed write leaves the file and no temporary file behind
+	err := Write(path, 0644, func(f *os.File) error {
+		f.WriteString("partial")
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected the write error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "two" {
+		t.Errorf("file changed by a failed write: %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only the file, got %d entries", len(entries))
+	}
+}
+
+func TestWriteFileConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	contents := []string{"aaaaaaaa", "bbbbbbbb", "cccccccc", "dddddddd"}
+
+	var wg sync.WaitGroup
+	for _, content := range contents {
+		wg.Add(1)
+		go func(content string) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if err := WriteFile(path, []byte(content), 0644); err != nil {
+					t.Errorf("WriteFile failed: %v", err)
+				}
+			}
+		}(content)
+	}
+	wg.Wait()
+
+	data, _ := os.ReadFile(path)
+	found := false
+	for _, content := range contents {
+		found = found || string(data) == content
+	}
+	if !found {
+		t.Errorf("file holds a mix of writes: %q", data)
+	}
+}
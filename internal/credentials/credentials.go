@@ -237,6 +237,12 @@ func validateGeneric(u *User, service *GitService) []CredentialStatus {
 	return statuses
 }
 
+// CheckCLIAuth reports whether the GitHub (gh) and GitLab (glab) CLIs are
+// installed and logged in
+func CheckCLIAuth() []CredentialStatus {
+	return []CredentialStatus{checkGhAuth(), checkGlabAuth()}
+}
+
 // checkGhAuth checks GitHub CLI authentication status
 func checkGhAuth() CredentialStatus {
 	cmd := exec.Command("gh", "auth", "status")
@@ -0,0 +1,238 @@
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// ErrNoIdentity is returned when a user has no git identity configured
+var ErrNoIdentity = errors.New("no git identity configured")
+
+// Identity is the git identity a user's executors commit and push with.
+// Without one, executors use whatever the server's global git config says.
+type Identity struct {
+	User      string    `json:"user"`
+	Name      string    `json:"name"`              // Author and committer name
+	Email     string    `json:"email"`             // Author and committer email
+	SSHKey    string    `json:"ssh_key,omitempty"` // Private key for pushes (absolute path)
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the identity has a name and email and that the SSH key,
+// if set, is a readable private key only its owner can read
+func (id *Identity) Validate() error {
+	if id.User == "" {
+		return fmt.Errorf("user is required")
+	}
+	if strings.TrimSpace(id.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !strings.Contains(id.Email, "@") || strings.ContainsAny(id.Email, " <>") {
+		return fmt.Errorf("invalid email %q", id.Email)
+	}
+	if strings.ContainsAny(id.Name, "<>\n") {
+		return fmt.Errorf("invalid name %q", id.Name)
+	}
+	if id.SSHKey == "" {
+		return nil
+	}
+	if !filepath.IsAbs(id.SSHKey) {
+		return fmt.Errorf("ssh key must be an absolute path: %s", id.SSHKey)
+	}
+	if strings.HasSuffix(id.SSHKey, ".pub") {
+		return fmt.Errorf("ssh key must be the private key, not %s", filepath.Base(id.SSHKey))
+	}
+	info, err := os.Stat(id.SSHKey)
+	if err != nil {
+		return fmt.Errorf("ssh key not found: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("ssh key is a directory: %s", id.SSHKey)
+	}
+	// ssh refuses keys other users can read
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("ssh key %s is accessible by others (run: chmod 600 %s)", id.SSHKey, id.SSHKey)
+	}
+	return nil
+}
+
+// SSHCommand returns the ssh command that pushes with the identity's key
+// (empty without a key)
+func (id *Identity) SSHCommand() string {
+	if id.SSHKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("ssh -i '%s' -o IdentitiesOnly=yes", strings.ReplaceAll(id.SSHKey, "'", `'\''`))
+}
+
+// Env returns the git environment variables for processes acting as this identity
+func (id *Identity) Env() []string {
+	env := []string{
+		"GIT_AUTHOR_NAME=" + id.Name,
+		"GIT_AUTHOR_EMAIL=" + id.Email,
+		"GIT_COMMITTER_NAME=" + id.Name,
+		"GIT_COMMITTER_EMAIL=" + id.Email,
+	}
+	if cmd := id.SSHCommand(); cmd != "" {
+		env = append(env, "GIT_SSH_COMMAND="+cmd)
+	}
+	return env
+}
+
+// ApplyToWorktree sets user.name, user.email and core.sshCommand in the
+// worktree's own config, so commits made there by hand use the identity too.
+// Worktree config is enabled on the repository if needed; it leaves the main
+// checkout and other worktrees alone.
+func (id *Identity) ApplyToWorktree(worktree string) error {
+	git := func(args ...string) error {
+		args = append([]string{"-C", worktree}, args...)
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s: %s", strings.Join(args[2:], " "), strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	if err := git("config", "extensions.worktreeConfig", "true"); err != nil {
+		return err
+	}
+	if err := git("config", "--worktree", "user.name", id.Name); err != nil {
+		return err
+	}
+	if err := git("config", "--worktree", "user.email", id.Email); err != nil {
+		return err
+	}
+	if cmd := id.SSHCommand(); cmd != "" {
+		return git("config", "--worktree", "core.sshCommand", cmd)
+	}
+	// Drop a key left over from an earlier identity (exit 5 = not set)
+	exec.Command("git", "-C", worktree, "config", "--worktree", "--unset", "core.sshCommand").Run()
+	return nil
+}
+
+// IdentityStore keeps users' git identities in <vega-dir>/.vega-hub-identities.json
+type IdentityStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// identitiesFile is the on-disk format
+type identitiesFile struct {
+	Identities []*Identity `json:"identities"`
+}
+
+// NewIdentityStore creates an identity store for the vega-missile directory
+func NewIdentityStore(vegaDir string) *IdentityStore {
+	return &IdentityStore{dir: vegaDir}
+}
+
+func (s *IdentityStore) path() string {
+	return filepath.Join(s.dir, ".vega-hub-identities.json")
+}
+
+// load reads all identities (caller holds lock)
+func (s *IdentityStore) load() ([]*Identity, error) {
+	data, err := os.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read identities: %w", err)
+	}
+	var file identitiesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse identities: %w", err)
+	}
+	return file.Identities, nil
+}
+
+// save writes all identities atomically (caller holds lock)
+func (s *IdentityStore) save(identities []*Identity) error {
+	sort.Slice(identities, func(i, j int) bool { return identities[i].User < identities[j].User })
+	data, err := json.MarshalIndent(identitiesFile{Identities: identities}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal identities: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write identities: %w", err)
+	}
+	return nil
+}
+
+// List returns all configured identities
+func (s *IdentityStore) List() ([]*Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identities, err := s.load()
+	if identities == nil && err == nil {
+		identities = []*Identity{}
+	}
+	return identities, err
+}
+
+// Get returns a user's identity (ErrNoIdentity if none is configured)
+func (s *IdentityStore) Get(user string) (*Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identities, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range identities {
+		if id.User == user {
+			return id, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNoIdentity, user)
+}
+
+// Set validates and stores a user's identity, replacing any existing one
+func (s *IdentityStore) Set(identity *Identity) error {
+	if err := identity.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identities, err := s.load()
+	if err != nil {
+		return err
+	}
+	identity.UpdatedAt = time.Now()
+	for i, id := range identities {
+		if id.User == identity.User {
+			identities[i] = identity
+			return s.save(identities)
+		}
+	}
+	return s.save(append(identities, identity))
+}
+
+// Delete removes a user's identity
+func (s *IdentityStore) Delete(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identities, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, id := range identities {
+		if id.User == user {
+			return s.save(append(identities[:i], identities[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w for %s", ErrNoIdentity, user)
+}
@@ -0,0 +1,122 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIdentityValidate(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_work")
+	os.WriteFile(key, []byte("key"), 0600)
+	openKey := filepath.Join(dir, "id_open")
+	os.WriteFile(openKey, []byte("key"), 0644)
+
+	valid := Identity{User: "alex", Name: "Alex Doe", Email: "alex@example.com", SSHKey: key}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid identity, got %v", err)
+	}
+
+	for name, id := range map[string]Identity{
+		"missing name":   {User: "alex", Email: "alex@example.com"},
+		"bad email":      {User: "alex", Name: "Alex", Email: "alex"},
+		"relative key":   {User: "alex", Name: "Alex", Email: "a@b.c", SSHKey: "id_work"},
+		"public key":     {User: "alex", Name: "Alex", Email: "a@b.c", SSHKey: key + ".pub"},
+		"missing key":    {User: "alex", Name: "Alex", Email: "a@b.c", SSHKey: filepath.Join(dir, "nope")},
+		"world readable": {User: "alex", Name: "Alex", Email: "a@b.c", SSHKey: openKey},
+	} {
+		if err := id.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestIdentityEnv(t *testing.T) {
+	id := Identity{Name: "Alex Doe", Email: "alex@example.com"}
+	env := strings.Join(id.Env(), "\n")
+	if !strings.Contains(env, "GIT_AUTHOR_NAME=Alex Doe") || !strings.Contains(env, "GIT_COMMITTER_EMAIL=alex@example.com") {
+		t.Errorf("unexpected env: %s", env)
+	}
+	if strings.Contains(env, "GIT_SSH_COMMAND") {
+		t.Error("expected no GIT_SSH_COMMAND without a key")
+	}
+
+	id.SSHKey = "/keys/it's"
+	if got, want := id.SSHCommand(), `ssh -i '/keys/it'\''s' -o IdentitiesOnly=yes`; got != want {
+		t.Errorf("SSHCommand = %q, want %q", got, want)
+	}
+}
+
+func TestIdentityStore(t *testing.T) {
+	store := NewIdentityStore(t.TempDir())
+
+	if _, err := store.Get("alex"); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("expected ErrNoIdentity, got %v", err)
+	}
+	if err := store.Set(&Identity{User: "alex", Name: "Alex", Email: "alex@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(&Identity{User: "alex", Name: "Alex Doe", Email: "alex@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := store.Get("alex")
+	if err != nil || id.Name != "Alex Doe" {
+		t.Errorf("expected updated identity, got %+v (err: %v)", id, err)
+	}
+	if list, _ := store.List(); len(list) != 1 {
+		t.Errorf("expected 1 identity, got %d", len(list))
+	}
+
+	if err := store.Delete("alex"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("alex"); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("expected ErrNoIdentity, got %v", err)
+	}
+}
+
+func TestApplyToWorktree(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	worktree := filepath.Join(dir, "goal")
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-q", repo)
+	run("-C", repo, "-c", "user.email=t@t", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init")
+	run("-C", repo, "worktree", "add", "-q", "-b", "goal", worktree)
+
+	id := Identity{Name: "Alex Doe", Email: "alex@example.com", SSHKey: "/keys/id_work"}
+	if err := id.ApplyToWorktree(worktree); err != nil {
+		t.Fatal(err)
+	}
+	if got := run("-C", worktree, "config", "user.name"); got != "Alex Doe" {
+		t.Errorf("worktree user.name = %q", got)
+	}
+	if got := run("-C", worktree, "config", "core.sshCommand"); !strings.Contains(got, "/keys/id_work") {
+		t.Errorf("worktree core.sshCommand = %q", got)
+	}
+
+	// The main checkout is left alone
+	if out, err := exec.Command("git", "-C", repo, "config", "user.name").Output(); err == nil && strings.TrimSpace(string(out)) == "Alex Doe" {
+		t.Error("identity leaked into the main checkout's config")
+	}
+
+	// Re-applying without a key drops the old one
+	id.SSHKey = ""
+	if err := id.ApplyToWorktree(worktree); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "-C", worktree, "config", "--worktree", "core.sshCommand").Output(); err == nil {
+		t.Errorf("expected core.sshCommand to be unset, got %q", out)
+	}
+}
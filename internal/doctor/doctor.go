@@ -0,0 +1,476 @@
+// Package doctor diagnoses the environment vega-hub runs in and the
+// integrity of a vega-missile directory, suggesting a fix for every problem
+// it finds.
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/credentials"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn" // Works, but something should be looked at
+	StatusFail Status = "fail" // vega-hub won't work properly until fixed
+)
+
+// Check names, in the order Run performs them
+const (
+	CheckGit       = "git"
+	CheckForgeCLI  = "forge_cli"
+	CheckStructure = "structure"
+	CheckRegistry  = "registry"
+	CheckPort      = "port"
+	CheckHooks     = "hook_templates"
+	CheckLocks     = "stale_locks"
+)
+
+// MinGitVersion is the oldest git with everything vega-hub uses
+// (git sparse-checkout needs 2.25)
+var MinGitVersion = [3]int{2, 25, 0}
+
+// Fix is an actionable way to resolve a problem
+type Fix struct {
+	Command     string `json:"command,omitempty"` // Shell command to run
+	Description string `json:"description"`
+}
+
+// Check is the result of one diagnostic
+type Check struct {
+	Name    string   `json:"name"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+	Fixes   []Fix    `json:"fixes,omitempty"`
+}
+
+// Report is the result of all diagnostics
+type Report struct {
+	Status Status  `json:"status"` // Worst status of all checks
+	Dir    string  `json:"dir,omitempty"`
+	Checks []Check `json:"checks"`
+}
+
+// Problems returns the checks that didn't pass
+func (r *Report) Problems() []Check {
+	var problems []Check
+	for _, c := range r.Checks {
+		if c.Status != StatusOK {
+			problems = append(problems, c)
+		}
+	}
+	return problems
+}
+
+// Options configures Run
+type Options struct {
+	// VegaDir is the directory to diagnose. When empty, only the
+	// environment is checked.
+	VegaDir string
+	// PortStart and PortEnd bound the ports vega-hub start picks from
+	// (default 8080-8089)
+	PortStart, PortEnd int
+}
+
+// Run performs every diagnostic
+func Run(opts Options) *Report {
+	if opts.PortStart == 0 {
+		opts.PortStart, opts.PortEnd = 8080, 8089
+	}
+
+	report := &Report{Status: StatusOK, Dir: opts.VegaDir}
+	report.Checks = append(report.Checks, checkGit(), checkForgeCLI(opts.VegaDir))
+	if opts.VegaDir == "" {
+		report.Checks = append(report.Checks, Check{
+			Name:    CheckStructure,
+			Status:  StatusFail,
+			Message: "No vega-missile directory found",
+			Fixes: []Fix{
+				{Command: "vega-hub init <dir>", Description: "Create a new vega-missile directory"},
+				{Command: "vega-hub doctor --dir <dir>", Description: "Point at an existing directory"},
+			},
+		})
+	} else if isMissing(opts.VegaDir) {
+		// Nothing else to look at
+		report.Checks = append(report.Checks, checkStructure(opts.VegaDir))
+	} else {
+		report.Checks = append(report.Checks,
+			checkStructure(opts.VegaDir),
+			checkRegistry(opts.VegaDir),
+			checkPort(opts.VegaDir, opts.PortStart, opts.PortEnd),
+			checkHookTemplates(opts.VegaDir),
+			checkLocks(opts.VegaDir),
+		)
+	}
+
+	for _, c := range report.Checks {
+		if c.Status == StatusFail {
+			report.Status = StatusFail
+		} else if c.Status == StatusWarn && report.Status == StatusOK {
+			report.Status = StatusWarn
+		}
+	}
+	return report
+}
+
+func isMissing(path string) bool {
+	info, err := os.Stat(path)
+	return err != nil || !info.IsDir()
+}
+
+var gitVersionRe = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// parseGitVersion extracts major.minor.patch from `git --version` output
+func parseGitVersion(output string) ([3]int, bool) {
+	var v [3]int
+	m := gitVersionRe.FindStringSubmatch(output)
+	if m == nil {
+		return v, false
+	}
+	for i := 0; i < 3; i++ {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, true
+}
+
+func versionAtLeast(v, min [3]int) bool {
+	for i := range v {
+		if v[i] != min[i] {
+			return v[i] > min[i]
+		}
+	}
+	return true
+}
+
+func checkGit() Check {
+	check := Check{Name: CheckGit}
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = "git not found on PATH"
+		check.Fixes = []Fix{{Description: "Install git 2.25 or newer"}}
+		return check
+	}
+	output := strings.TrimSpace(string(out))
+	version, ok := parseGitVersion(output)
+	if !ok {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("Could not parse git version from %q", output)
+		return check
+	}
+	if !versionAtLeast(version, MinGitVersion) {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("git %d.%d.%d is too old (need %d.%d or newer)",
+			version[0], version[1], version[2], MinGitVersion[0], MinGitVersion[1])
+		check.Fixes = []Fix{{Description: "Upgrade git to 2.25 or newer"}}
+		return check
+	}
+	check.Status = StatusOK
+	check.Message = output
+	return check
+}
+
+// checkForgeCLI reports on gh and glab. A missing or logged-out CLI only
+// matters when a project is hosted on that forge.
+func checkForgeCLI(vegaDir string) Check {
+	check := Check{Name: CheckForgeCLI, Status: StatusOK}
+
+	needed := map[string]bool{} // gh_auth / glab_auth
+	if vegaDir != "" {
+		projects, _ := goals.ParseProjects(vegaDir)
+		for _, p := range projects {
+			remote := p.GitRemote
+			if remote == "" {
+				remote = p.Upstream
+			}
+			service, err := credentials.ParseGitService(remote)
+			if err != nil {
+				continue
+			}
+			switch service.Name {
+			case "github":
+				needed["gh_auth"] = true
+			case "gitlab":
+				needed["glab_auth"] = true
+			}
+		}
+	}
+
+	var available []string
+	for _, status := range credentials.CheckCLIAuth() {
+		check.Details = append(check.Details, status.Message)
+		if status.Available {
+			available = append(available, status.Source)
+			continue
+		}
+		if !needed[status.Source] {
+			continue
+		}
+		check.Status = StatusWarn
+		switch status.Source {
+		case "gh_auth":
+			check.Fixes = append(check.Fixes, Fix{Command: "gh auth login", Description: "Log in to GitHub for projects hosted there"})
+		case "glab_auth":
+			check.Fixes = append(check.Fixes, Fix{Command: "glab auth login", Description: "Log in to GitLab for projects hosted there"})
+		}
+	}
+
+	switch {
+	case check.Status == StatusWarn:
+		check.Message = "A forge CLI your projects need is missing or logged out"
+	case len(available) > 0:
+		check.Message = "Logged in: " + strings.Join(available, ", ")
+	default:
+		check.Message = "No forge CLI logged in (not needed by any project)"
+	}
+	return check
+}
+
+// requiredPaths are the parts of a vega-missile directory vega-hub relies on
+var requiredPaths = []string{
+	".claude",
+	"goals",
+	"goals/active",
+	"goals/iced",
+	"goals/history",
+	"projects",
+	"projects/index.md",
+	"workspaces",
+}
+
+func checkStructure(vegaDir string) Check {
+	check := Check{Name: CheckStructure, Status: StatusOK}
+	if info, err := os.Stat(vegaDir); err != nil || !info.IsDir() {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("%s is not a directory", vegaDir)
+		check.Fixes = []Fix{{Command: "vega-hub init " + vegaDir, Description: "Create the vega-missile directory"}}
+		return check
+	}
+	for _, path := range requiredPaths {
+		if _, err := os.Stat(filepath.Join(vegaDir, path)); err != nil {
+			check.Details = append(check.Details, "missing "+path)
+		}
+	}
+	if len(check.Details) == 0 {
+		check.Message = "All expected directories and files are present"
+		return check
+	}
+
+	check.Status = StatusWarn
+	if _, err := os.Stat(filepath.Join(vegaDir, "goals")); err != nil {
+		check.Status = StatusFail
+	}
+	check.Message = fmt.Sprintf("%d expected path(s) missing", len(check.Details))
+	check.Fixes = []Fix{{Command: "vega-hub init " + vegaDir, Description: "Restore the missing paths (existing files are kept)"}}
+	return check
+}
+
+// checkRegistry cross-checks goals/registry.jsonl with the goal files
+func checkRegistry(vegaDir string) Check {
+	check := Check{Name: CheckRegistry, Status: StatusOK}
+	entries, err := goals.NewRegistry(vegaDir).Load()
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = err.Error()
+		check.Fixes = []Fix{{Description: "Fix or remove the malformed line in goals/registry.jsonl"}}
+		return check
+	}
+
+	parser := goals.NewParser(vegaDir)
+	projects := map[string]bool{}
+	if names, err := parser.ListProjects(); err == nil {
+		for _, name := range names {
+			projects[name] = true
+		}
+	}
+
+	registered := map[string]bool{}
+	missingFile, wrongStatus, unknownProject := 0, 0, 0
+	for _, e := range entries {
+		registered[e.ID] = true
+		path, status := parser.GoalFile(e.ID)
+		switch {
+		case path == "":
+			missingFile++
+			check.Details = append(check.Details, fmt.Sprintf("goal %s is registered as %s but has no goal file", e.ID, e.Status))
+		case status != e.Status:
+			wrongStatus++
+			check.Details = append(check.Details, fmt.Sprintf("goal %s is registered as %s but its file is in the %s folder", e.ID, e.Status, status))
+		}
+		for _, project := range e.Projects {
+			if len(projects) > 0 && !projects[project] {
+				unknownProject++
+				check.Details = append(check.Details, fmt.Sprintf("goal %s references unknown project %s", e.ID, project))
+			}
+		}
+	}
+
+	unregistered := 0
+	for _, folder := range []string{"active", "iced"} {
+		files, _ := os.ReadDir(filepath.Join(vegaDir, "goals", folder))
+		for _, f := range files {
+			id := strings.TrimSuffix(f.Name(), ".md")
+			if (!f.IsDir() && id == f.Name()) || registered[id] {
+				continue
+			}
+			unregistered++
+			check.Details = append(check.Details, fmt.Sprintf("goals/%s/%s is not in the registry", folder, f.Name()))
+		}
+	}
+
+	if len(check.Details) == 0 {
+		check.Message = fmt.Sprintf("%d registered goal(s) match their goal files", len(entries))
+		return check
+	}
+	check.Status = StatusWarn
+	check.Message = fmt.Sprintf("%d mismatch(es) between the registry and goal files", len(check.Details))
+	if missingFile > 0 || wrongStatus > 0 {
+		check.Fixes = append(check.Fixes, Fix{Description: "Move each goal file to the folder matching its registry status (active, iced or history), or update its status in goals/registry.jsonl"})
+	}
+	if unknownProject > 0 {
+		check.Fixes = append(check.Fixes, Fix{Command: "vega-hub project add <name> <git-url>", Description: "Add the missing project"})
+	}
+	if unregistered > 0 {
+		check.Fixes = append(check.Fixes, Fix{Description: "Register the goal in goals/registry.jsonl or move its file to goals/history"})
+	}
+	return check
+}
+
+// checkPort reports the running hub, or whether vega-hub start can find a port
+func checkPort(vegaDir string, start, end int) Check {
+	check := Check{Name: CheckPort, Status: StatusOK}
+
+	if data, err := os.ReadFile(filepath.Join(vegaDir, ".vega-hub.port")); err == nil {
+		if port, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+			client := &http.Client{Timeout: 2 * time.Second}
+			if resp, err := client.Get(fmt.Sprintf("http://localhost:%d/api/health", port)); err == nil {
+				resp.Body.Close()
+				check.Message = fmt.Sprintf("vega-hub is running on port %d", port)
+				return check
+			}
+			check.Status = StatusWarn
+			check.Details = append(check.Details, fmt.Sprintf(".vega-hub.port points at %d but nothing answers there", port))
+			check.Fixes = append(check.Fixes, Fix{Command: "vega-hub status", Description: "Clean up the stale port and pid files"})
+		}
+	}
+
+	var busy []string
+	free := 0
+	for port := start; port <= end; port++ {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			busy = append(busy, strconv.Itoa(port))
+			continue
+		}
+		listener.Close()
+		free++
+	}
+	if free == 0 {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("No free port in %d-%d", start, end)
+		check.Fixes = append(check.Fixes,
+			Fix{Description: fmt.Sprintf("Stop whatever is listening on ports %d-%d", start, end)},
+			Fix{Command: "vega-hub serve --port <port>", Description: "Serve on another port"})
+		return check
+	}
+	if check.Message == "" {
+		check.Message = fmt.Sprintf("%d of ports %d-%d free", free, start, end)
+	}
+	if len(busy) > 0 {
+		check.Details = append(check.Details, "in use: "+strings.Join(busy, ", "))
+	}
+	return check
+}
+
+// hookTemplates are the project-init files every project's executors need
+var hookTemplates = []string{
+	"templates/project-init/.claude/hooks/vega-hub-ask.sh",
+	"templates/project-init/.claude/hooks/on-session-start.sh",
+	"templates/project-init/.claude/hooks/on-stop.sh",
+	"templates/project-init/.claude/settings.local.json",
+}
+
+func checkHookTemplates(vegaDir string) Check {
+	check := Check{Name: CheckHooks, Status: StatusOK}
+	var missing, notExecutable []string
+	for _, path := range hookTemplates {
+		info, err := os.Stat(filepath.Join(vegaDir, path))
+		if err != nil {
+			missing = append(missing, path)
+			continue
+		}
+		if strings.HasSuffix(path, ".sh") && info.Mode().Perm()&0111 == 0 {
+			notExecutable = append(notExecutable, path)
+		}
+	}
+	for _, path := range missing {
+		check.Details = append(check.Details, "missing "+path)
+	}
+	for _, path := range notExecutable {
+		check.Details = append(check.Details, "not executable: "+path)
+	}
+
+	var tools []string
+	for _, tool := range []string{"jq", "curl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			tools = append(tools, tool)
+			check.Details = append(check.Details, tool+" not found on PATH (the hooks need it)")
+		}
+	}
+
+	if len(check.Details) == 0 {
+		check.Message = "Executor hook templates are in place"
+		return check
+	}
+	check.Status = StatusWarn
+	check.Message = "Executors may not reach vega-hub"
+	if len(missing) > 0 {
+		check.Fixes = append(check.Fixes, Fix{Command: "vega-hub init " + vegaDir, Description: "Restore the default hook templates"})
+	}
+	if len(notExecutable) > 0 {
+		check.Fixes = append(check.Fixes, Fix{Command: "chmod +x " + filepath.Join(vegaDir, "templates/project-init/.claude/hooks/*.sh"), Description: "Make the hooks executable"})
+	}
+	if len(tools) > 0 {
+		check.Fixes = append(check.Fixes, Fix{Description: "Install " + strings.Join(tools, " and ")})
+	}
+	return check
+}
+
+func checkLocks(vegaDir string) Check {
+	check := Check{Name: CheckLocks, Status: StatusOK}
+	locks, err := hub.NewLockManager(vegaDir).ListLocks()
+	if err != nil {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("Failed to read locks: %v", err)
+		return check
+	}
+	for _, lock := range locks {
+		if lock.IsStale() {
+			check.Details = append(check.Details, fmt.Sprintf("%s/%s held by pid %d since %s",
+				lock.LockType, lock.Resource, lock.PID, lock.AcquiredAt.Format(time.RFC3339)))
+		}
+	}
+	if len(check.Details) == 0 {
+		check.Message = fmt.Sprintf("No stale locks (%d held)", len(locks))
+		return check
+	}
+	check.Status = StatusWarn
+	check.Message = fmt.Sprintf("%d stale lock(s)", len(check.Details))
+	check.Fixes = []Fix{{Command: "vega-hub lock clean", Description: "Remove stale locks"}}
+	return check
+}
@@ -0,0 +1,166 @@
+package doctor
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/operations"
+)
+
+// newVegaDir scaffolds a fresh vega-missile directory
+func newVegaDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if result, _ := operations.Init(operations.InitOptions{Dir: dir}); !result.Success {
+		t.Fatalf("init failed: %+v", result.Error)
+	}
+	return dir
+}
+
+func findCheck(t *testing.T, report *Report, name string) Check {
+	t.Helper()
+	for _, c := range report.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %s check in report", name)
+	return Check{}
+}
+
+func TestRunFreshDir(t *testing.T) {
+	dir := newVegaDir(t)
+	report := Run(Options{VegaDir: dir})
+
+	for _, name := range []string{CheckGit, CheckStructure, CheckRegistry, CheckLocks} {
+		if c := findCheck(t, report, name); c.Status != StatusOK {
+			t.Errorf("expected %s ok on a fresh directory, got %+v", name, c)
+		}
+	}
+}
+
+func TestRunMissingDir(t *testing.T) {
+	report := Run(Options{VegaDir: filepath.Join(t.TempDir(), "nope")})
+	if report.Status != StatusFail {
+		t.Errorf("expected a failing report, got %s", report.Status)
+	}
+	c := findCheck(t, report, CheckStructure)
+	if len(c.Fixes) == 0 || !strings.HasPrefix(c.Fixes[0].Command, "vega-hub init") {
+		t.Errorf("expected an init fix, got %+v", c.Fixes)
+	}
+	for _, c := range report.Checks {
+		if c.Name == CheckRegistry || c.Name == CheckHooks {
+			t.Errorf("expected directory checks skipped for a missing directory, got %s", c.Name)
+		}
+	}
+}
+
+func TestCheckStructure(t *testing.T) {
+	dir := newVegaDir(t)
+	os.RemoveAll(filepath.Join(dir, "goals", "iced"))
+
+	c := checkStructure(dir)
+	if c.Status != StatusWarn || len(c.Details) != 1 || c.Details[0] != "missing goals/iced" {
+		t.Errorf("expected a warning about goals/iced, got %+v", c)
+	}
+
+	os.RemoveAll(filepath.Join(dir, "goals"))
+	if c := checkStructure(dir); c.Status != StatusFail {
+		t.Errorf("expected a failure without goals/, got %+v", c)
+	}
+}
+
+func TestCheckRegistry(t *testing.T) {
+	dir := newVegaDir(t)
+	registry := goals.NewRegistry(dir)
+	write := func(path string) {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("# Goal\n"), 0644)
+	}
+
+	// Consistent
+	registry.Add(goals.RegistryEntry{ID: "aaa1111", Title: "Fine", Status: "active"})
+	write(filepath.Join(dir, "goals", "active", "aaa1111.md"))
+	// Registered, no file
+	registry.Add(goals.RegistryEntry{ID: "bbb2222", Title: "Lost", Status: "active"})
+	// Registered active, file iced
+	registry.Add(goals.RegistryEntry{ID: "ccc3333", Title: "Moved", Status: "active"})
+	write(filepath.Join(dir, "goals", "iced", "ccc3333.md"))
+	// File, not registered (folder structure)
+	write(filepath.Join(dir, "goals", "active", "ddd4444", "ddd4444.md"))
+
+	c := checkRegistry(dir)
+	if c.Status != StatusWarn || len(c.Details) != 3 {
+		t.Fatalf("expected 3 problems, got %+v", c)
+	}
+	joined := strings.Join(c.Details, "\n")
+	for _, want := range []string{"bbb2222", "ccc3333", "ddd4444"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %s reported, got:\n%s", want, joined)
+		}
+	}
+	if len(c.Fixes) == 0 {
+		t.Error("expected fixes")
+	}
+
+	os.WriteFile(filepath.Join(dir, "goals", "registry.jsonl"), []byte("{not json\n"), 0644)
+	if c := checkRegistry(dir); c.Status != StatusFail {
+		t.Errorf("expected a failure for a malformed registry, got %+v", c)
+	}
+}
+
+func TestCheckPort(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	c := checkPort(t.TempDir(), port, port)
+	if c.Status != StatusFail || len(c.Fixes) == 0 {
+		t.Errorf("expected a failure when the only port is taken, got %+v", c)
+	}
+}
+
+func TestCheckHookTemplates(t *testing.T) {
+	dir := newVegaDir(t)
+	os.Chmod(filepath.Join(dir, "templates/project-init/.claude/hooks/on-stop.sh"), 0644)
+	os.Remove(filepath.Join(dir, "templates/project-init/.claude/settings.local.json"))
+
+	c := checkHookTemplates(dir)
+	if c.Status != StatusWarn {
+		t.Fatalf("expected a warning, got %+v", c)
+	}
+	joined := strings.Join(c.Details, "\n")
+	if !strings.Contains(joined, "not executable: templates/project-init/.claude/hooks/on-stop.sh") ||
+		!strings.Contains(joined, "missing templates/project-init/.claude/settings.local.json") {
+		t.Errorf("unexpected details:\n%s", joined)
+	}
+}
+
+func TestParseGitVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   [3]int
+		ok     bool
+	}{
+		{"git version 2.39.5", [3]int{2, 39, 5}, true},
+		{"git version 2.24.3 (Apple Git-128)", [3]int{2, 24, 3}, true},
+		{"git version 2.40.windows.1", [3]int{2, 40, 0}, true},
+		{"nonsense", [3]int{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGitVersion(tt.output)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseGitVersion(%q) = %v, %v; want %v, %v", tt.output, got, ok, tt.want, tt.ok)
+		}
+	}
+	if versionAtLeast([3]int{2, 24, 9}, MinGitVersion) || !versionAtLeast([3]int{2, 25, 0}, MinGitVersion) {
+		t.Error("unexpected minimum version comparison")
+	}
+}
@@ -0,0 +1,455 @@
+package gitsvc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/pathguard"
+)
+
+// Version control systems a project can use, as set by goals.Project.VCS
+const (
+	VCSGit = "git"
+	VCSJJ  = "jj" // Jujutsu, in a repository colocated with git
+)
+
+// Backend performs the write operations behind a goal's lifecycle: a
+// workspace per goal on its own branch, merged back into the base branch on
+// completion. repo is the project checkout (worktree-base); workspace is the
+// goal's checkout next to it.
+type Backend interface {
+	// Name returns the VCS name (VCSGit, VCSJJ)
+	Name() string
+	// AddWorkspace creates a workspace at path on a new branch started from base
+	AddWorkspace(repo, path, branch, base string) error
+	// RestoreWorkspace recreates a workspace at path for an existing branch
+	RestoreWorkspace(repo, path, branch string) error
+	// RemoveWorkspace deletes the workspace at path, keeping its branch
+	RemoveWorkspace(repo, path string) error
+	// CheckClean fails when the workspace has uncommitted changes outside ignore
+	CheckClean(workspace string, ignore []string) error
+	// Merge merges branch into target using a goals.MergeStrategy* strategy
+	Merge(repo, workspace, branch, target, strategy, message string) error
+	// CherryPick applies commits onto target, in order. A commit that doesn't
+	// apply cleanly aborts the whole pick with a *CherryPickError.
+	CherryPick(repo, target string, commits []string) error
+	// DeleteBranch deletes branch, merged or not
+	DeleteBranch(repo, branch string) error
+	// RenameBranch renames branch; workspaces on it stay on it
+	RenameBranch(repo, branch, newBranch string) error
+	// MoveWorkspace moves the workspace at path to newPath
+	MoveWorkspace(repo, path, newPath string) error
+	// Retarget moves branch, checked out in workspace, from base from onto
+	// base onto: goals.MergeStrategyRebase replays the commits it made since
+	// it forked from from, goals.MergeStrategyMerge merges onto into it. A
+	// conflict leaves the branch as it was.
+	Retarget(repo, workspace, branch, from, onto, strategy string) error
+}
+
+// ErrUnsupported is returned for operations a backend can't perform
+var ErrUnsupported = errors.New("not supported by this VCS")
+
+// CherryPickError reports a commit that conflicted with the target branch.
+// The target is left as it was before the cherry-pick.
+type CherryPickError struct {
+	Commit    string   // Commit that didn't apply
+	Conflicts []string // Conflicting paths
+	Output    string
+}
+
+func (e *CherryPickError) Error() string {
+	if len(e.Conflicts) > 0 {
+		return fmt.Sprintf("cherry-pick %s conflicts in %s", e.Commit, strings.Join(e.Conflicts, ", "))
+	}
+	return fmt.Sprintf("cherry-pick %s: %s", e.Commit, e.Output)
+}
+
+// BackendFor returns the backend for a VCS name ("" is git)
+func BackendFor(vcs string) (Backend, error) {
+	switch vcs {
+	case "", VCSGit:
+		return GitBackend{}, nil
+	case VCSJJ:
+		return JJBackend{}, nil
+	}
+	return nil, fmt.Errorf("unknown VCS %q (valid: %s, %s)", vcs, VCSGit, VCSJJ)
+}
+
+// removeWorkspaceDir deletes a workspace directory left behind by the VCS.
+// Workspaces live next to the project checkout; nothing else is ever deleted.
+func removeWorkspaceDir(repo, path string) error {
+	if err := pathguard.Within(filepath.Dir(repo), path); err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// relativeTo returns path relative to repo, or path itself if that fails
+func relativeTo(repo, path string) string {
+	if rel, err := filepath.Rel(repo, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// GitBackend implements Backend with git worktrees and branches
+type GitBackend struct{}
+
+// Name implements Backend
+func (GitBackend) Name() string { return VCSGit }
+
+// AddWorkspace implements Backend
+func (GitBackend) AddWorkspace(repo, path, branch, base string) error {
+	cmd := exec.Command("git", "-C", repo, "worktree", "add", "-b", branch, relativeTo(repo, path), base)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree add: %s", string(output))
+	}
+	return nil
+}
+
+// RestoreWorkspace implements Backend
+func (GitBackend) RestoreWorkspace(repo, path, branch string) error {
+	cmd := exec.Command("git", "-C", repo, "worktree", "add", relativeTo(repo, path), branch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree add: %s", string(output))
+	}
+	return nil
+}
+
+// RemoveWorkspace implements Backend
+func (GitBackend) RemoveWorkspace(repo, path string) error {
+	if err := exec.Command("git", "-C", repo, "worktree", "remove", relativeTo(repo, path), "--force").Run(); err == nil {
+		return nil
+	}
+	if err := removeWorkspaceDir(repo, path); err != nil {
+		return err
+	}
+	exec.Command("git", "-C", repo, "worktree", "prune").Run()
+	return nil
+}
+
+// CheckClean implements Backend
+func (GitBackend) CheckClean(workspace string, ignore []string) error {
+	args := []string{"-C", workspace, "status", "--porcelain", "--", "."}
+	for _, path := range ignore {
+		args = append(args, ":!"+path)
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return fmt.Errorf("git status failed: %w", err)
+	}
+	if len(strings.TrimSpace(string(output))) > 0 {
+		return fmt.Errorf("uncommitted changes:\n%s", string(output))
+	}
+	return nil
+}
+
+// Merge implements Backend. The target is checked out in repo; rebasing
+// happens in the workspace, where branch is checked out.
+func (GitBackend) Merge(repo, workspace, branch, target, strategy, message string) error {
+	cmd := exec.Command("git", "-C", repo, "checkout", target)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("checkout %s: %s", target, string(output))
+	}
+
+	var steps [][]string
+	switch strategy {
+	case "", goals.MergeStrategyMerge:
+		steps = [][]string{{"-C", repo, "merge", branch, "-m", message}}
+	case goals.MergeStrategySquash:
+		steps = [][]string{
+			{"-C", repo, "merge", "--squash", branch},
+			{"-C", repo, "commit", "-m", message},
+		}
+	case goals.MergeStrategyFFOnly:
+		steps = [][]string{{"-C", repo, "merge", "--ff-only", branch}}
+	case goals.MergeStrategyRebase:
+		cmd = exec.Command("git", "-C", workspace, "rebase", target)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			exec.Command("git", "-C", workspace, "rebase", "--abort").Run()
+			return fmt.Errorf("rebase onto %s: %s", target, string(output))
+		}
+		steps = [][]string{{"-C", repo, "merge", "--ff-only", branch}}
+	default:
+		return fmt.Errorf("unknown merge strategy: %s", strategy)
+	}
+
+	for _, args := range steps {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %s", args[2], string(output))
+		}
+	}
+	return nil
+}
+
+// CherryPick implements Backend. The target is checked out in repo.
+func (GitBackend) CherryPick(repo, target string, commits []string) error {
+	if output, err := exec.Command("git", "-C", repo, "checkout", target).CombinedOutput(); err != nil {
+		return fmt.Errorf("checkout %s: %s", target, string(output))
+	}
+	start, err := git(repo, "rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("rev-parse %s: %w", target, err)
+	}
+	for _, commit := range commits {
+		output, err := exec.Command("git", "-C", repo, "cherry-pick", "-x", commit).CombinedOutput()
+		if err == nil {
+			continue
+		}
+		pickErr := &CherryPickError{Commit: commit, Output: strings.TrimSpace(string(output))}
+		if conflicts, err := git(repo, "diff", "--name-only", "--diff-filter=U"); err == nil && conflicts != "" {
+			pickErr.Conflicts = strings.Split(conflicts, "\n")
+		}
+		exec.Command("git", "-C", repo, "cherry-pick", "--abort").Run()
+		// Commits picked before the conflicting one are dropped too
+		exec.Command("git", "-C", repo, "reset", "--hard", start).Run()
+		return pickErr
+	}
+	return nil
+}
+
+// DeleteBranch implements Backend
+func (GitBackend) DeleteBranch(repo, branch string) error {
+	if err := exec.Command("git", "-C", repo, "branch", "-d", branch).Run(); err != nil {
+		if err := exec.Command("git", "-C", repo, "branch", "-D", branch).Run(); err != nil {
+			return fmt.Errorf("could not delete branch: %w", err)
+		}
+	}
+	return nil
+}
+
+// RenameBranch implements Backend
+func (GitBackend) RenameBranch(repo, branch, newBranch string) error {
+	if output, err := exec.Command("git", "-C", repo, "branch", "-m", branch, newBranch).CombinedOutput(); err != nil {
+		return fmt.Errorf("git branch -m: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MoveWorkspace implements Backend
+func (GitBackend) MoveWorkspace(repo, path, newPath string) error {
+	cmd := exec.Command("git", "-C", repo, "worktree", "move", relativeTo(repo, path), relativeTo(repo, newPath))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree move: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Retarget implements Backend
+func (GitBackend) Retarget(repo, workspace, branch, from, onto, strategy string) error {
+	switch strategy {
+	case "", goals.MergeStrategyRebase:
+		forkPoint, err := git(workspace, "merge-base", "HEAD", from)
+		if err != nil {
+			return fmt.Errorf("merge-base %s: %w", from, err)
+		}
+		if output, err := exec.Command("git", "-C", workspace, "rebase", "--onto", onto, forkPoint).CombinedOutput(); err != nil {
+			exec.Command("git", "-C", workspace, "rebase", "--abort").Run()
+			return fmt.Errorf("rebase onto %s: %s", onto, strings.TrimSpace(string(output)))
+		}
+	case goals.MergeStrategyMerge:
+		if output, err := exec.Command("git", "-C", workspace, "merge", "--no-edit", onto).CombinedOutput(); err != nil {
+			exec.Command("git", "-C", workspace, "merge", "--abort").Run()
+			return fmt.Errorf("merge %s: %s", onto, strings.TrimSpace(string(output)))
+		}
+	default:
+		return fmt.Errorf("unknown retarget strategy: %s", strategy)
+	}
+	return nil
+}
+
+// JJBackend implements Backend with Jujutsu workspaces and bookmarks. The
+// project checkout must be colocated (jj git init --colocate), so bookmarks
+// show up as git branches and the read-only git queries keep working there.
+//
+// A goal's workspace is named after its bookmark. Executors commit with
+// "jj commit", which leaves the working-copy commit empty on top; the
+// bookmark is moved to its parent when merging.
+type JJBackend struct{}
+
+// jj runs a jj command against the repository or workspace at dir
+func jj(dir string, args ...string) (string, error) {
+	output, err := exec.Command("jj", append([]string{"-R", dir, "--no-pager"}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("jj %s: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// revset quotes a bookmark name for use in a revset
+func revset(name string) string {
+	return strconv.Quote(name)
+}
+
+// Name implements Backend
+func (JJBackend) Name() string { return VCSJJ }
+
+// AddWorkspace implements Backend
+func (JJBackend) AddWorkspace(repo, path, branch, base string) error {
+	if _, err := jj(repo, "workspace", "add", "--name", branch, "-r", revset(base), path); err != nil {
+		return err
+	}
+	_, err := jj(path, "bookmark", "create", branch, "-r", "@")
+	return err
+}
+
+// RestoreWorkspace implements Backend
+func (JJBackend) RestoreWorkspace(repo, path, branch string) error {
+	_, err := jj(repo, "workspace", "add", "--name", branch, "-r", revset(branch), path)
+	return err
+}
+
+// RemoveWorkspace implements Backend
+func (JJBackend) RemoveWorkspace(repo, path string) error {
+	jj(repo, "workspace", "forget", filepath.Base(path))
+	return removeWorkspaceDir(repo, path)
+}
+
+// CheckClean implements Backend. jj snapshots edits into the working-copy
+// commit, so uncommitted changes are the files that commit touches.
+func (JJBackend) CheckClean(workspace string, ignore []string) error {
+	output, err := jj(workspace, "diff", "--summary", "-r", "@")
+	if err != nil {
+		return err
+	}
+	var changes []string
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		ignored := false
+		for _, path := range ignore {
+			ignored = ignored || line[2:] == path
+		}
+		if !ignored {
+			changes = append(changes, line)
+		}
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("uncommitted changes:\n%s", strings.Join(changes, "\n"))
+	}
+	return nil
+}
+
+// Merge implements Backend. The merged result is created in repo's default
+// workspace and the target bookmark moved onto it.
+func (JJBackend) Merge(repo, workspace, branch, target, strategy, message string) error {
+	if workspace != "" {
+		if _, err := jj(workspace, "bookmark", "set", branch, "-r", "@-", "--allow-backwards"); err != nil {
+			return err
+		}
+	}
+
+	rebase := func() error {
+		if _, err := jj(repo, "rebase", "-b", revset(branch), "-d", revset(target)); err != nil {
+			return err
+		}
+		conflicts, err := jj(repo, "log", "--no-graph", "-T", `commit_id ++ "\n"`, "-r", fmt.Sprintf("conflicts() & (::%s ~ ::%s)", revset(branch), revset(target)))
+		if err != nil {
+			return err
+		}
+		if conflicts != "" {
+			return fmt.Errorf("rebase onto %s: conflicts in %s", target, branch)
+		}
+		return nil
+	}
+
+	var steps [][]string
+	switch strategy {
+	case "", goals.MergeStrategyMerge:
+		steps = [][]string{
+			{"new", revset(target), revset(branch), "-m", message},
+			{"bookmark", "set", target, "-r", "@"},
+			{"new"},
+		}
+	case goals.MergeStrategySquash:
+		if err := rebase(); err != nil {
+			return err
+		}
+		steps = [][]string{
+			{"new", revset(target), "-m", message},
+			{"restore", "--from", revset(branch)},
+			{"bookmark", "set", target, "-r", "@"},
+			{"new"},
+		}
+	case goals.MergeStrategyFFOnly:
+		diverged, err := jj(repo, "log", "--no-graph", "-T", `commit_id ++ "\n"`, "-r", fmt.Sprintf("%s ~ ::%s", revset(target), revset(branch)))
+		if err != nil {
+			return err
+		}
+		if diverged != "" {
+			return fmt.Errorf("merge: %s can't be fast-forwarded to %s", target, branch)
+		}
+		steps = [][]string{{"bookmark", "set", target, "-r", revset(branch)}}
+	case goals.MergeStrategyRebase:
+		if err := rebase(); err != nil {
+			return err
+		}
+		steps = [][]string{{"bookmark", "set", target, "-r", revset(branch)}}
+	default:
+		return fmt.Errorf("unknown merge strategy: %s", strategy)
+	}
+
+	for _, args := range steps {
+		if _, err := jj(repo, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CherryPick implements Backend. Picking commits isn't supported for jj
+// projects; complete with a merge strategy instead.
+func (JJBackend) CherryPick(repo, target string, commits []string) error {
+	return fmt.Errorf("cherry-pick: %w", ErrUnsupported)
+}
+
+// DeleteBranch implements Backend
+func (JJBackend) DeleteBranch(repo, branch string) error {
+	if _, err := jj(repo, "bookmark", "delete", branch); err != nil {
+		return fmt.Errorf("could not delete branch: %w", err)
+	}
+	return nil
+}
+
+// RenameBranch implements Backend
+func (JJBackend) RenameBranch(repo, branch, newBranch string) error {
+	_, err := jj(repo, "bookmark", "rename", branch, newBranch)
+	return err
+}
+
+// MoveWorkspace implements Backend. jj workspaces can't be moved; the
+// workspace keeps its directory.
+func (JJBackend) MoveWorkspace(repo, path, newPath string) error {
+	return fmt.Errorf("move workspace: %w", ErrUnsupported)
+}
+
+// Retarget implements Backend. Only rebasing is supported; the workspace's
+// working copy is recorded on the bookmark first, as for Merge.
+func (JJBackend) Retarget(repo, workspace, branch, from, onto, strategy string) error {
+	if strategy != "" && strategy != goals.MergeStrategyRebase {
+		return fmt.Errorf("retarget with %s: %w", strategy, ErrUnsupported)
+	}
+	if _, err := jj(workspace, "bookmark", "set", branch, "-r", "@-", "--allow-backwards"); err != nil {
+		return err
+	}
+	if _, err := jj(repo, "rebase", "-s", fmt.Sprintf("roots(%s..%s)", revset(from), revset(branch)), "-d", revset(onto)); err != nil {
+		return err
+	}
+	conflicts, err := jj(repo, "log", "--no-graph", "-T", `commit_id ++ "\n"`, "-r", fmt.Sprintf("conflicts() & (::%s ~ ::%s)", revset(branch), revset(onto)))
+	if err != nil {
+		return err
+	}
+	if conflicts != "" {
+		jj(repo, "undo")
+		return fmt.Errorf("rebase onto %s: conflicts in %s", onto, branch)
+	}
+	_, err = jj(workspace, "workspace", "update-stale")
+	return err
+}
@@ -0,0 +1,132 @@
+package gitsvc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestBackendFor(t *testing.T) {
+	for vcs, want := range map[string]string{"": VCSGit, "git": VCSGit, "jj": VCSJJ} {
+		backend, err := BackendFor(vcs)
+		if err != nil || backend.Name() != want {
+			t.Errorf("BackendFor(%q) = %v, %v; want %s", vcs, backend, err, want)
+		}
+	}
+	if _, err := BackendFor("hg"); err == nil {
+		t.Error("expected an error for an unknown VCS")
+	}
+}
+
+// testBackend runs a goal's lifecycle against a backend: add a workspace,
+// commit in it, remove and restore it, merge and delete the branch
+func testBackend(t *testing.T, backend Backend, repo string, commit func(dir, file, msg string)) {
+	t.Helper()
+	workspace := filepath.Join(filepath.Dir(repo), "goal-abc1234-fix")
+	if err := backend.AddWorkspace(repo, workspace, "goal-abc1234-fix", "main"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	commit(workspace, "fix.go", "Fix")
+
+	os.WriteFile(filepath.Join(workspace, "wip.go"), []byte("package main\n"), 0644)
+	if err := backend.CheckClean(workspace, nil); err == nil {
+		t.Error("expected uncommitted changes to be reported")
+	}
+	if err := backend.CheckClean(workspace, []string{"wip.go"}); err != nil {
+		t.Errorf("ignored paths should not count: %v", err)
+	}
+	os.Remove(filepath.Join(workspace, "wip.go"))
+
+	if err := backend.RemoveWorkspace(repo, workspace); err != nil {
+		t.Fatalf("RemoveWorkspace: %v", err)
+	}
+	if _, err := os.Stat(workspace); !os.IsNotExist(err) {
+		t.Fatal("workspace should be gone")
+	}
+	if err := backend.RestoreWorkspace(repo, workspace, "goal-abc1234-fix"); err != nil {
+		t.Fatalf("RestoreWorkspace: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "fix.go")); err != nil {
+		t.Fatal("restored workspace should have the branch's commits")
+	}
+
+	if err := backend.Merge(repo, workspace, "goal-abc1234-fix", "main", goals.MergeStrategyMerge, "Merge goal abc1234: Fix"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if err := backend.RemoveWorkspace(repo, workspace); err != nil {
+		t.Fatalf("RemoveWorkspace: %v", err)
+	}
+	if err := backend.DeleteBranch(repo, "goal-abc1234-fix"); err != nil {
+		t.Fatalf("DeleteBranch: %v", err)
+	}
+	// Colocated or not, the merged base is visible to git
+	output, _ := exec.Command("git", "-C", repo, "ls-tree", "-r", "--name-only", "main").Output()
+	if !strings.Contains(string(output), "fix.go") {
+		t.Errorf("main should contain the goal's work, got %q", output)
+	}
+	if output, _ := exec.Command("git", "-C", repo, "branch", "--list", "goal-abc1234-fix").Output(); len(output) > 0 {
+		t.Error("goal branch should be deleted")
+	}
+}
+
+func TestGitBackend(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "worktree-base")
+	os.MkdirAll(repo, 0755)
+	run := func(dir string, args ...string) {
+		t.Helper()
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	commit := func(dir, file, msg string) {
+		os.WriteFile(filepath.Join(dir, file), []byte("package main\n"), 0644)
+		run(dir, "add", ".")
+		run(dir, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", msg)
+	}
+	run(repo, "init", "-b", "main")
+	commit(repo, "README.md", "Initial commit")
+	run(repo, "config", "user.email", "test@example.com")
+	run(repo, "config", "user.name", "Test")
+
+	testBackend(t, GitBackend{}, repo, commit)
+
+	// Nothing outside the workspaces directory is ever deleted
+	outside := t.TempDir()
+	if err := (GitBackend{}).RemoveWorkspace(repo, outside); err == nil {
+		t.Error("expected a path outside the workspaces to be refused")
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Error("directory outside the workspaces should be kept")
+	}
+}
+
+func TestJJBackend(t *testing.T) {
+	if _, err := exec.LookPath("jj"); err != nil {
+		t.Skip("jj not installed")
+	}
+	repo := filepath.Join(t.TempDir(), "worktree-base")
+	os.MkdirAll(repo, 0755)
+	t.Setenv("JJ_USER", "Test")
+	t.Setenv("JJ_EMAIL", "test@example.com")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		if output, err := exec.Command("jj", append([]string{"-R", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("jj %v: %v\n%s", args, err, output)
+		}
+	}
+	commit := func(dir, file, msg string) {
+		os.WriteFile(filepath.Join(dir, file), []byte("package main\n"), 0644)
+		run(dir, "commit", "-m", msg)
+	}
+	if output, err := exec.Command("jj", "git", "init", "--colocate", repo).CombinedOutput(); err != nil {
+		t.Fatalf("jj git init: %v\n%s", err, output)
+	}
+	commit(repo, "README.md", "Initial commit")
+	run(repo, "bookmark", "create", "main", "-r", "@-")
+
+	testBackend(t, JJBackend{}, repo, commit)
+}
@@ -0,0 +1,161 @@
+package gitsvc
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL bounds how stale a cached answer can get. Git metadata changes
+// invalidate entries right away; the TTL covers working tree edits, which
+// the file watcher doesn't see.
+const DefaultTTL = 5 * time.Second
+
+// Cached is a Service that memoizes another Service's answers per repository.
+// Concurrent requests for the same answer share a single git call.
+type Cached struct {
+	svc Service
+	ttl time.Duration
+	now func() time.Time // Overridable in tests
+
+	mu       sync.Mutex
+	entries  map[cacheKey]cacheEntry
+	inflight map[cacheKey]*call
+}
+
+type cacheKey struct {
+	repo string
+	op   string // Method name plus arguments other than repo
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// call is an in-progress lookup that other callers wait on
+type call struct {
+	done  chan struct{}
+	value interface{}
+}
+
+// NewCached wraps svc with a cache whose entries live at most ttl
+func NewCached(svc Service, ttl time.Duration) *Cached {
+	return &Cached{
+		svc:      svc,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[cacheKey]cacheEntry),
+		inflight: make(map[cacheKey]*call),
+	}
+}
+
+// get returns the cached value for key, computing it with fn on a miss
+func (c *Cached) get(key cacheKey, fn func() interface{}) interface{} {
+	key.repo = filepath.Clean(key.repo)
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.value
+	}
+	if inflight, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-inflight.done
+		return inflight.value
+	}
+	cl := &call{done: make(chan struct{})}
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	cl.value = fn()
+
+	c.mu.Lock()
+	// An invalidation during the lookup drops the in-flight marker: don't cache
+	// an answer that may predate it
+	if c.inflight[key] == cl {
+		delete(c.inflight, key)
+		c.entries[key] = cacheEntry{value: cl.value, expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	close(cl.done)
+	return cl.value
+}
+
+// Invalidate drops cached answers for repositories at or below path
+func (c *Cached) Invalidate(path string) {
+	path = filepath.Clean(path)
+	prefix := path + string(filepath.Separator)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.repo == path || strings.HasPrefix(key.repo, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.inflight {
+		if key.repo == path || strings.HasPrefix(key.repo, prefix) {
+			delete(c.inflight, key)
+		}
+	}
+}
+
+// InvalidateAll drops every cached answer
+func (c *Cached) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]cacheEntry)
+	c.inflight = make(map[cacheKey]*call)
+}
+
+// Len returns the number of cached answers (including expired ones not yet replaced)
+func (c *Cached) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+type aheadBehind struct{ ahead, behind int }
+
+type commit struct{ hash, subject string }
+
+// CurrentBranch implements Service
+func (c *Cached) CurrentBranch(repo string) string {
+	return c.get(cacheKey{repo, "branch"}, func() interface{} {
+		return c.svc.CurrentBranch(repo)
+	}).(string)
+}
+
+// AheadBehind implements Service
+func (c *Cached) AheadBehind(repo, base string) (int, int) {
+	v := c.get(cacheKey{repo, "ahead-behind:" + base}, func() interface{} {
+		ahead, behind := c.svc.AheadBehind(repo, base)
+		return aheadBehind{ahead, behind}
+	}).(aheadBehind)
+	return v.ahead, v.behind
+}
+
+// UncommittedFiles implements Service. Callers must not modify the returned slice.
+func (c *Cached) UncommittedFiles(repo string) []string {
+	return c.get(cacheKey{repo, "status"}, func() interface{} {
+		return c.svc.UncommittedFiles(repo)
+	}).([]string)
+}
+
+// LastCommit implements Service
+func (c *Cached) LastCommit(repo string) (string, string) {
+	v := c.get(cacheKey{repo, "last-commit"}, func() interface{} {
+		hash, subject := c.svc.LastCommit(repo)
+		return commit{hash, subject}
+	}).(commit)
+	return v.hash, v.subject
+}
+
+// BranchExists implements Service
+func (c *Cached) BranchExists(repo, branch string) string {
+	return c.get(cacheKey{repo, "branch-exists:" + branch}, func() interface{} {
+		return c.svc.BranchExists(repo, branch)
+	}).(string)
+}
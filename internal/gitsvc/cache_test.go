@@ -0,0 +1,181 @@
+package gitsvc
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingService returns fixed answers and counts how often it's asked
+type countingService struct {
+	calls   int64
+	release chan struct{} // If set, calls block until it's closed
+}
+
+func (s *countingService) wait() {
+	atomic.AddInt64(&s.calls, 1)
+	if s.release != nil {
+		<-s.release
+	}
+}
+
+func (s *countingService) CurrentBranch(repo string) string { s.wait(); return "goal/" + repo }
+func (s *countingService) AheadBehind(repo, base string) (int, int) {
+	s.wait()
+	return 2, 1
+}
+func (s *countingService) UncommittedFiles(repo string) []string {
+	s.wait()
+	return []string{"a.go"}
+}
+func (s *countingService) LastCommit(repo string) (string, string) {
+	s.wait()
+	return "abc123", "Fix it"
+}
+func (s *countingService) BranchExists(repo, branch string) string {
+	s.wait()
+	return BranchLocal
+}
+
+func (s *countingService) count() int64 {
+	return atomic.LoadInt64(&s.calls)
+}
+
+func TestCachedHit(t *testing.T) {
+	svc := &countingService{}
+	c := NewCached(svc, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if got := c.CurrentBranch("/repo"); got != "goal//repo" {
+			t.Fatalf("CurrentBranch = %q", got)
+		}
+	}
+	if svc.count() != 1 {
+		t.Errorf("expected 1 git call, got %d", svc.count())
+	}
+
+	// Different arguments are cached separately
+	c.AheadBehind("/repo", "main")
+	c.AheadBehind("/repo", "develop")
+	ahead, behind := c.AheadBehind("/repo", "main")
+	if ahead != 2 || behind != 1 {
+		t.Errorf("AheadBehind = %d, %d", ahead, behind)
+	}
+	if svc.count() != 3 {
+		t.Errorf("expected 3 git calls, got %d", svc.count())
+	}
+
+	// Equivalent paths share an entry
+	c.CurrentBranch("/repo/")
+	if svc.count() != 3 {
+		t.Errorf("expected cleaned path to hit the cache, got %d calls", svc.count())
+	}
+}
+
+func TestCachedTTL(t *testing.T) {
+	svc := &countingService{}
+	c := NewCached(svc, 5*time.Second)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.LastCommit("/repo")
+	now = now.Add(4 * time.Second)
+	c.LastCommit("/repo")
+	if svc.count() != 1 {
+		t.Errorf("expected cached answer before TTL, got %d calls", svc.count())
+	}
+
+	now = now.Add(2 * time.Second)
+	hash, subject := c.LastCommit("/repo")
+	if hash != "abc123" || subject != "Fix it" {
+		t.Errorf("LastCommit = %q, %q", hash, subject)
+	}
+	if svc.count() != 2 {
+		t.Errorf("expected refresh after TTL, got %d calls", svc.count())
+	}
+}
+
+func TestCachedInvalidate(t *testing.T) {
+	svc := &countingService{}
+	c := NewCached(svc, time.Minute)
+
+	c.UncommittedFiles("/ws/api/worktree-base")
+	c.UncommittedFiles("/ws/api/goal-1")
+	c.UncommittedFiles("/ws/api-v2/goal-2")
+	if c.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", c.Len())
+	}
+
+	c.Invalidate("/ws/api")
+	if c.Len() != 1 {
+		t.Errorf("expected only the api-v2 entry to survive, got %d entries", c.Len())
+	}
+
+	c.UncommittedFiles("/ws/api-v2/goal-2")
+	if svc.count() != 3 {
+		t.Errorf("api-v2 should still be cached, got %d calls", svc.count())
+	}
+	c.UncommittedFiles("/ws/api/goal-1")
+	if svc.count() != 4 {
+		t.Errorf("api/goal-1 should be refetched, got %d calls", svc.count())
+	}
+
+	c.InvalidateAll()
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache, got %d entries", c.Len())
+	}
+}
+
+func TestCachedCoalescesConcurrentCalls(t *testing.T) {
+	svc := &countingService{release: make(chan struct{})}
+	c := NewCached(svc, time.Minute)
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.BranchExists("/repo", "main")
+		}(i)
+	}
+
+	// Let the first lookup start, then let it finish
+	for svc.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(svc.release)
+	wg.Wait()
+
+	if svc.count() != 1 {
+		t.Errorf("expected 1 git call, got %d", svc.count())
+	}
+	for i, r := range results {
+		if r != BranchLocal {
+			t.Errorf("result %d = %q", i, r)
+		}
+	}
+}
+
+func TestCachedInvalidateDuringLookup(t *testing.T) {
+	svc := &countingService{release: make(chan struct{})}
+	c := NewCached(svc, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		c.CurrentBranch("/repo")
+		close(done)
+	}()
+	for svc.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Invalidate("/repo")
+	close(svc.release)
+	<-done
+
+	if c.Len() != 0 {
+		t.Error("answer started before an invalidation should not be cached")
+	}
+}
@@ -0,0 +1,158 @@
+// Package gitsvc answers read-only questions about git repositories (branch,
+// ahead/behind, uncommitted files, last commit) for API handlers.
+//
+// Exec shells out to git; Cached wraps any Service, memoizes results per
+// repository and is invalidated by the hub's file watcher when git metadata
+// changes.
+//
+// Write operations (workspace add, merge, ...) go through a Backend, chosen
+// per project: GitBackend for worktrees and branches, JJBackend for Jujutsu
+// workspaces and bookmarks in a colocated repository.
+package gitsvc
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Branch existence, as returned by Service.BranchExists
+const (
+	BranchLocal      = "local"
+	BranchRemoteOnly = "remote_only"
+	BranchMissing    = "missing"
+)
+
+// Service answers read-only questions about git repositories.
+// Methods return zero values when repo isn't a git repository.
+type Service interface {
+	// CurrentBranch returns the checked out branch ("" when detached)
+	CurrentBranch(repo string) string
+	// AheadBehind counts commits on HEAD but not base, and on base but not HEAD
+	AheadBehind(repo, base string) (ahead, behind int)
+	// UncommittedFiles lists paths with staged, unstaged or untracked changes
+	UncommittedFiles(repo string) []string
+	// LastCommit returns the hash and subject of HEAD
+	LastCommit(repo string) (hash, subject string)
+	// BranchExists reports whether branch is BranchLocal, BranchRemoteOnly or BranchMissing
+	BranchExists(repo, branch string) string
+}
+
+// Exec implements Service by running git
+type Exec struct{}
+
+// NewExec returns a Service that runs git for every call
+func NewExec() Exec {
+	return Exec{}
+}
+
+// git runs a git command in repo and returns its trimmed stdout
+func git(repo string, args ...string) (string, error) {
+	output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CurrentBranch implements Service
+func (Exec) CurrentBranch(repo string) string {
+	branch, _ := git(repo, "branch", "--show-current")
+	return branch
+}
+
+// AheadBehind implements Service
+func (Exec) AheadBehind(repo, base string) (int, int) {
+	output, err := git(repo, "rev-list", "--left-right", "--count", base+"...HEAD")
+	if err != nil {
+		return 0, 0
+	}
+	var ahead, behind int
+	if n, _ := fmt.Sscanf(output, "%d %d", &behind, &ahead); n != 2 {
+		return 0, 0
+	}
+	return ahead, behind
+}
+
+// UncommittedFiles implements Service
+func (Exec) UncommittedFiles(repo string) []string {
+	output, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) > 3 {
+			files = append(files, strings.TrimSpace(line[3:]))
+		}
+	}
+	return files
+}
+
+// LastCommit implements Service
+func (Exec) LastCommit(repo string) (string, string) {
+	output, err := git(repo, "log", "-1", "--format=%H|%s")
+	if err != nil {
+		return "", ""
+	}
+	hash, subject, ok := strings.Cut(output, "|")
+	if !ok {
+		return "", ""
+	}
+	return hash, subject
+}
+
+// BranchExists implements Service
+func (Exec) BranchExists(repo, branch string) string {
+	if output, err := git(repo, "branch", "--list", branch); err == nil && output != "" {
+		return BranchLocal
+	}
+	if output, err := git(repo, "ls-remote", "--heads", "origin", branch); err == nil && output != "" {
+		return BranchRemoteOnly
+	}
+	return BranchMissing
+}
+
+// InvalidCommitError reports a commit that isn't one of a branch's own commits
+type InvalidCommitError struct {
+	Commit string
+	Branch string
+}
+
+func (e *InvalidCommitError) Error() string {
+	return fmt.Sprintf("commit %s is not on %s", e.Commit, e.Branch)
+}
+
+// BranchCommits resolves selected commits (full or abbreviated hashes) among
+// the commits on branch but not base and returns their full hashes in the
+// order they were made on branch. Commits that aren't on branch, or already
+// on base, are reported with an *InvalidCommitError.
+func BranchCommits(repo, base, branch string, selected []string) ([]string, error) {
+	output, err := git(repo, "rev-list", "--reverse", "--no-merges", base+".."+branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of %s: %w", branch, err)
+	}
+	onBranch := strings.Fields(output)
+	picked := make(map[string]bool)
+	for _, commit := range selected {
+		full, err := git(repo, "rev-parse", "--verify", "--quiet", commit+"^{commit}")
+		if err != nil {
+			return nil, &InvalidCommitError{Commit: commit, Branch: branch}
+		}
+		found := false
+		for _, c := range onBranch {
+			found = found || c == full
+		}
+		if !found {
+			return nil, &InvalidCommitError{Commit: commit, Branch: branch}
+		}
+		picked[full] = true
+	}
+	var commits []string
+	for _, c := range onBranch {
+		if picked[c] {
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
@@ -0,0 +1,135 @@
+package gitsvc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// initRepo creates a repository with one commit on main and a goal branch one commit ahead
+func initRepo(t testing.TB) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# test\n"), 0644)
+	run("add", ".")
+	run("commit", "-m", "Initial commit")
+	run("checkout", "-b", "goal-1")
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	run("add", ".")
+	run("commit", "-m", "Add main")
+	return dir
+}
+
+func TestExec(t *testing.T) {
+	repo := initRepo(t)
+	git := NewExec()
+
+	if got := git.CurrentBranch(repo); got != "goal-1" {
+		t.Errorf("CurrentBranch = %q, want goal-1", got)
+	}
+	if ahead, behind := git.AheadBehind(repo, "main"); ahead != 1 || behind != 0 {
+		t.Errorf("AheadBehind = %d, %d, want 1, 0", ahead, behind)
+	}
+	hash, subject := git.LastCommit(repo)
+	if len(hash) != 40 || subject != "Add main" {
+		t.Errorf("LastCommit = %q, %q", hash, subject)
+	}
+	if got := git.BranchExists(repo, "main"); got != BranchLocal {
+		t.Errorf("BranchExists(main) = %q, want %q", got, BranchLocal)
+	}
+	if got := git.BranchExists(repo, "nope"); got != BranchMissing {
+		t.Errorf("BranchExists(nope) = %q, want %q", got, BranchMissing)
+	}
+
+	if files := git.UncommittedFiles(repo); len(files) != 0 {
+		t.Errorf("expected clean repo, got %v", files)
+	}
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("changed\n"), 0644)
+	if files := git.UncommittedFiles(repo); len(files) != 2 {
+		t.Errorf("expected 2 uncommitted files, got %v", files)
+	}
+}
+
+func TestExecNotARepo(t *testing.T) {
+	dir := t.TempDir()
+	git := NewExec()
+
+	if got := git.CurrentBranch(dir); got != "" {
+		t.Errorf("CurrentBranch = %q", got)
+	}
+	if ahead, behind := git.AheadBehind(dir, "main"); ahead != 0 || behind != 0 {
+		t.Errorf("AheadBehind = %d, %d", ahead, behind)
+	}
+	if files := git.UncommittedFiles(dir); files != nil {
+		t.Errorf("UncommittedFiles = %v", files)
+	}
+	if got := git.BranchExists(dir, "main"); got != BranchMissing {
+		t.Errorf("BranchExists = %q", got)
+	}
+}
+
+// benchmarkBranchInfo runs the queries behind a goal detail response
+func benchmarkBranchInfo(b *testing.B, git Service) {
+	repo := initRepo(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		git.CurrentBranch(repo)
+		git.AheadBehind(repo, "main")
+		git.UncommittedFiles(repo)
+		git.LastCommit(repo)
+	}
+}
+
+func BenchmarkExec(b *testing.B) {
+	benchmarkBranchInfo(b, NewExec())
+}
+
+func BenchmarkCached(b *testing.B) {
+	benchmarkBranchInfo(b, NewCached(NewExec(), DefaultTTL))
+}
+
+func BenchmarkCachedExpiring(b *testing.B) {
+	// Every lookup misses: measures the cache's overhead over Exec
+	benchmarkBranchInfo(b, NewCached(NewExec(), time.Nanosecond))
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	output := `worktree /ws/worktree-base
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /ws/goal-abc1234-fix
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/goal-abc1234-fix
+prunable gitdir file points to non-existent location
+
+worktree /ws/.pool/warm-1
+HEAD 1111111111111111111111111111111111111111
+detached
+locked
+
+`
+	worktrees := parseWorktreeList(output)
+	if len(worktrees) != 3 {
+		t.Fatalf("expected 3 worktrees, got %+v", worktrees)
+	}
+	if wt := worktrees[1]; wt.Path != "/ws/goal-abc1234-fix" || wt.Branch != "goal-abc1234-fix" || !wt.Prunable {
+		t.Errorf("unexpected goal worktree: %+v", wt)
+	}
+	if wt := worktrees[2]; wt.Branch != "" || !wt.Detached || !wt.Locked {
+		t.Errorf("unexpected pooled worktree: %+v", wt)
+	}
+}
@@ -0,0 +1,66 @@
+package gitsvc
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Worktree is an entry of "git worktree list --porcelain"
+type Worktree struct {
+	Path     string `json:"path"`
+	Head     string `json:"head,omitempty"`
+	Branch   string `json:"branch,omitempty"` // Short name, "" when detached
+	Bare     bool   `json:"bare,omitempty"`
+	Detached bool   `json:"detached,omitempty"`
+	Locked   bool   `json:"locked,omitempty"`
+	Prunable bool   `json:"prunable,omitempty"` // Its directory is gone (moved or deleted without git)
+}
+
+// ListWorktrees returns the worktrees registered in repo, the main one first
+func ListWorktrees(repo string) ([]Worktree, error) {
+	output, err := exec.Command("git", "-C", repo, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktreeList(string(output)), nil
+}
+
+// parseWorktreeList parses "git worktree list --porcelain": one block of
+// "<attribute> <value>" lines per worktree, separated by blank lines
+func parseWorktreeList(output string) []Worktree {
+	var worktrees []Worktree
+	var current *Worktree
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+		case "HEAD":
+			if current != nil {
+				current.Head = value
+			}
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "bare":
+			if current != nil {
+				current.Bare = true
+			}
+		case "detached":
+			if current != nil {
+				current.Detached = true
+			}
+		case "locked":
+			if current != nil {
+				current.Locked = true
+			}
+		case "prunable":
+			if current != nil {
+				current.Prunable = true
+			}
+		}
+	}
+	return worktrees
+}
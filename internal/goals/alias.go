@@ -0,0 +1,91 @@
+package goals
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// ErrAliasTaken is returned when another goal already has the alias
+var ErrAliasTaken = errors.New("alias is already used by another goal")
+
+var aliasRe = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
+
+// NormalizeAlias lowercases and trims a goal alias and checks it is a slug:
+// letters, digits and dashes, starting with a letter, at most 48 characters.
+// Aliases that could be read as a goal ID are refused. An empty alias (no
+// alias) is valid.
+func NormalizeAlias(alias string) (string, error) {
+	alias = strings.ToLower(strings.TrimSpace(alias))
+	if alias == "" {
+		return "", nil
+	}
+	if len(alias) > 48 || !aliasRe.MatchString(alias) {
+		return "", fmt.Errorf("invalid alias %q: use a slug of letters, digits and dashes starting with a letter (at most 48)", alias)
+	}
+	if validateGoalID(alias) == nil {
+		return "", fmt.Errorf("invalid alias %q: it looks like a goal ID", alias)
+	}
+	return alias, nil
+}
+
+// SetAlias gives a goal an alias in the registry, or removes it when alias
+// is empty. Aliases are unique: ErrAliasTaken if another goal has it.
+func (r *Registry) SetAlias(id, alias string) error {
+	alias, err := NormalizeAlias(alias)
+	if err != nil {
+		return err
+	}
+	if alias != "" {
+		entries, err := r.Load()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.Alias == alias && e.ID != id {
+				return fmt.Errorf("%w: %s has alias %q", ErrAliasTaken, e.ID, alias)
+			}
+		}
+	}
+	return r.Update(id, func(e *RegistryEntry) {
+		e.Alias = alias
+		e.UpdatedAt = time.Now().Format(time.RFC3339)
+	})
+}
+
+// ResolveGoalID returns the goal ID ref stands for: ref itself when it is a
+// goal's ID, the ID of the goal with alias ref, or ref unchanged when it is
+// neither (callers report the unknown goal as they would any other)
+func ResolveGoalID(vegaDir, ref string) string {
+	alias := strings.ToLower(strings.TrimSpace(ref))
+	if ref == "" || !aliasRe.MatchString(alias) || validateGoalID(alias) == nil {
+		return ref
+	}
+	if path, _ := layout.New(vegaDir).FindGoalFile(ref); path != "" {
+		return ref
+	}
+	entries, err := NewRegistry(vegaDir).Load()
+	if err != nil {
+		return ref
+	}
+	for _, e := range entries {
+		if e.ID == ref {
+			return ref
+		}
+	}
+	for _, e := range entries {
+		if e.Alias == alias {
+			return e.ID
+		}
+	}
+	return ref
+}
+
+// ResolveGoalID resolves a goal ID or alias (see ResolveGoalID)
+func (p *Parser) ResolveGoalID(ref string) string {
+	return ResolveGoalID(p.dir, ref)
+}
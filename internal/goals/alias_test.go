@@ -0,0 +1,69 @@
+package goals
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeAlias(t *testing.T) {
+	for alias, want := range map[string]string{
+		" OAuth-Login ": "oauth-login",
+		"":              "",
+		"v2-api":        "v2-api",
+	} {
+		got, err := NormalizeAlias(alias)
+		if err != nil || got != want {
+			t.Errorf("NormalizeAlias(%q) = %q, %v; want %q", alias, got, err, want)
+		}
+	}
+	for _, alias := range []string{"abc1234", "12", "-x", "x-", "has space", "a", "under_score", "abc1234.1"} {
+		if _, err := NormalizeAlias(alias); err == nil {
+			t.Errorf("NormalizeAlias(%q) should fail", alias)
+		}
+	}
+}
+
+func TestGoalAliases(t *testing.T) {
+	dir := setupTestDir(t)
+	registry := NewRegistry(dir)
+	for _, id := range []string{"abc1234", "def5678"} {
+		if err := registry.Add(RegistryEntry{ID: id, Title: "Goal " + id, Status: "active"}); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, "goals", "active", id+".md"), "# Goal #"+id+": Goal\n")
+	}
+
+	if err := registry.SetAlias("abc1234", "Login-Fix"); err != nil {
+		t.Fatalf("SetAlias: %v", err)
+	}
+	if err := registry.SetAlias("def5678", "login-fix"); !errors.Is(err, ErrAliasTaken) {
+		t.Errorf("expected ErrAliasTaken, got %v", err)
+	}
+	if err := registry.SetAlias("abc1234", "login-fix"); err != nil {
+		t.Errorf("re-setting a goal's own alias failed: %v", err)
+	}
+	if err := registry.SetAlias("fff0000", "other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	for ref, want := range map[string]string{
+		"login-fix": "abc1234",
+		"LOGIN-FIX": "abc1234",
+		"abc1234":   "abc1234",
+		"def5678":   "def5678",
+		"unknown":   "unknown",
+		"":          "",
+	} {
+		if got := NewParser(dir).ResolveGoalID(ref); got != want {
+			t.Errorf("ResolveGoalID(%q) = %q, want %q", ref, got, want)
+		}
+	}
+
+	if err := registry.SetAlias("abc1234", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveGoalID(dir, "login-fix"); got != "login-fix" {
+		t.Errorf("cleared alias still resolves to %q", got)
+	}
+}
@@ -0,0 +1,86 @@
+package goals
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// CompletedGoal is a goal in goals/history or the archive
+type CompletedGoal struct {
+	Goal
+	Archived bool `json:"archived,omitempty"` // Moved to goals/history/archive by cleanup
+}
+
+// CompletedGoalFilter narrows ListCompletedGoals
+type CompletedGoalFilter struct {
+	Project string
+	Query   string // Case-insensitive match on ID or title
+}
+
+// ListCompletedGoals returns completed goals, most recently completed first.
+// Goals are taken from both the registry and the goal files in goals/history
+// and the archive, so goals missing from one of them are still listed.
+func (p *Parser) ListCompletedGoals(filter CompletedGoalFilter) ([]CompletedGoal, error) {
+	entries, err := NewRegistry(p.dir).List(func(e RegistryEntry) bool { return e.Status == "completed" })
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*CompletedGoal, len(entries))
+	for _, e := range entries {
+		byID[e.ID] = &CompletedGoal{Goal: e}
+	}
+
+	for _, dir := range []string{layout.HistoryDir, layout.ArchiveDir} {
+		for id, path := range p.layout.GoalFiles(dir) {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			goal, ok := byID[id]
+			if !ok {
+				goal = &CompletedGoal{
+					Goal: Goal{ID: id, Status: "completed", CompletedAt: info.ModTime().Format("2006-01-02")},
+				}
+				if detail, err := p.ParseGoalDetail(id); err == nil {
+					goal.Title = detail.Title
+					goal.Projects = detail.Projects
+					goal.Phase = detail.Phase
+				}
+				byID[id] = goal
+			}
+			goal.Archived = dir == layout.ArchiveDir
+		}
+	}
+
+	query := strings.ToLower(filter.Query)
+	result := make([]CompletedGoal, 0, len(byID))
+	for _, goal := range byID {
+		if filter.Project != "" && !containsString(goal.Projects, filter.Project) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(goal.ID), query) && !strings.Contains(strings.ToLower(goal.Title), query) {
+			continue
+		}
+		result = append(result, *goal)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CompletedAt != result[j].CompletedAt {
+			return result[i].CompletedAt > result[j].CompletedAt
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
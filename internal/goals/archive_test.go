@@ -0,0 +1,95 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setupArchive(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, sub := range []string{"active", "history", "history/archive", "history/def5678"} {
+		os.MkdirAll(filepath.Join(dir, "goals", sub), 0755)
+	}
+
+	goal := func(path, id, title, project string) {
+		content := "# Goal #" + id + ": " + title + "\n\n## Project(s)\n\n- **" + project + "**: work\n\n" +
+			"## Worktree\n- **Branch**: goal-" + id + "-x\n- **Project**: " + project + "\n- **Base Branch**: main\n"
+		os.WriteFile(filepath.Join(dir, "goals", path), []byte(content), 0644)
+	}
+	goal("active/aaa1111.md", "aaa1111", "Still running", "api")
+	goal("history/abc1234.md", "abc1234", "Fix login", "api")
+	goal("history/def5678/def5678.md", "def5678", "Add search", "web")
+	goal("history/archive/0ld0001.md", "0ld0001", "Old cleanup", "api")
+
+	registry := NewRegistry(dir)
+	registry.Add(RegistryEntry{ID: "aaa1111", Title: "Still running", Projects: []string{"api"}, Status: "active"})
+	registry.Add(RegistryEntry{ID: "abc1234", Title: "Fix login", Projects: []string{"api"}, Status: "completed", CompletedAt: "2026-03-02"})
+	registry.Add(RegistryEntry{ID: "0ld0001", Title: "Old cleanup", Projects: []string{"api"}, Status: "completed", CompletedAt: "2025-01-10"})
+	// def5678 is missing from the registry; its file date is used instead
+	old := time.Date(2026, 1, 15, 12, 0, 0, 0, time.Local)
+	os.Chtimes(filepath.Join(dir, "goals", "history", "def5678", "def5678.md"), old, old)
+	return dir
+}
+
+func TestListCompletedGoals(t *testing.T) {
+	p := NewParser(setupArchive(t))
+
+	completed, err := p.ListCompletedGoals(CompletedGoalFilter{})
+	if err != nil {
+		t.Fatalf("ListCompletedGoals failed: %v", err)
+	}
+	var ids []string
+	for _, g := range completed {
+		ids = append(ids, g.ID)
+	}
+	if len(ids) != 3 || ids[0] != "abc1234" || ids[1] != "def5678" || ids[2] != "0ld0001" {
+		t.Fatalf("expected completed goals newest first, got %v", ids)
+	}
+	if completed[1].Title != "Add search" || completed[1].CompletedAt != "2026-01-15" {
+		t.Errorf("expected unregistered goal read from its file, got %+v", completed[1])
+	}
+	if !completed[2].Archived || completed[0].Archived {
+		t.Errorf("expected only 0ld0001 archived, got %+v", completed)
+	}
+
+	if web, _ := p.ListCompletedGoals(CompletedGoalFilter{Project: "web"}); len(web) != 1 || web[0].ID != "def5678" {
+		t.Errorf("expected project filter to keep def5678, got %+v", web)
+	}
+	if found, _ := p.ListCompletedGoals(CompletedGoalFilter{Query: "LOGIN"}); len(found) != 1 || found[0].ID != "abc1234" {
+		t.Errorf("expected title search to find abc1234, got %+v", found)
+	}
+}
+
+func TestParseGoalDetailArchived(t *testing.T) {
+	p := NewParser(setupArchive(t))
+
+	detail, err := p.ParseGoalDetail("0ld0001")
+	if err != nil {
+		t.Fatalf("archived goal should be found: %v", err)
+	}
+	if detail.Status != "completed" || !detail.Archived || detail.CompletedAt != "2025-01-10" {
+		t.Errorf("unexpected archived detail: %+v", detail.Goal)
+	}
+	if detail.Worktree == nil || detail.Worktree.Branch != "goal-0ld0001-x" {
+		t.Errorf("expected worktree metadata for archived goal, got %+v", detail.Worktree)
+	}
+
+	detail, err = p.ParseGoalDetail("abc1234")
+	if err != nil {
+		t.Fatalf("completed goal should be found: %v", err)
+	}
+	if detail.Archived || detail.CompletedAt != "2026-03-02" {
+		t.Errorf("unexpected completed detail: archived=%v completed_at=%q", detail.Archived, detail.CompletedAt)
+	}
+
+	// State files moved to the archive are read too
+	os.WriteFile(filepath.Join(p.Dir(), "goals", "history", "archive", "0ld0001.state.jsonl"),
+		[]byte(`{"ts":"2025-01-10T10:00:00Z","state":"done","reason":"Goal completed"}`+"\n"), 0644)
+	state, err := NewStateManager(p.Dir()).GetState("0ld0001")
+	if err != nil || state != StateDone {
+		t.Errorf("expected archived state done, got %q (%v)", state, err)
+	}
+}
@@ -0,0 +1,33 @@
+package goals
+
+import (
+	"sort"
+	"time"
+)
+
+// StatusDraft is the registry status of backlog goals: created without a
+// branch or worktree, with their file in goals/backlog until activated
+const StatusDraft = "draft"
+
+// Backlog returns the draft goals, highest priority first, then oldest first
+func (r *Registry) Backlog() ([]RegistryEntry, error) {
+	entries, err := r.List(func(e RegistryEntry) bool { return e.Status == StatusDraft })
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Priority != entries[j].Priority {
+			return entries[i].Priority > entries[j].Priority
+		}
+		return entries[i].CreatedAt < entries[j].CreatedAt
+	})
+	return entries, nil
+}
+
+// SetPriority records a goal's backlog priority in the registry
+func (r *Registry) SetPriority(id string, priority int) error {
+	return r.Update(id, func(e *RegistryEntry) {
+		e.Priority = priority
+		e.UpdatedAt = time.Now().Format(time.RFC3339)
+	})
+}
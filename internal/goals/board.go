@@ -0,0 +1,160 @@
+package goals
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// boardMu serializes read-modify-write updates of board.json
+var boardMu sync.Mutex
+
+// Board stores the manual order of goals on kanban boards in goals/board.json.
+// Every project has its own board (the "" project is the board of all goals)
+// with one column per goal status. Goals that were never placed sort after
+// placed ones, in the order they are listed.
+type Board struct {
+	path string
+}
+
+// boardFile is the on-disk format: project -> status -> goal IDs
+type boardFile struct {
+	Boards map[string]map[string][]string `json:"boards"`
+}
+
+// NewBoard creates a Board for the vega-missile directory
+func NewBoard(vegaDir string) *Board {
+	return &Board{path: layout.New(vegaDir).Board()}
+}
+
+func (b *Board) load() (*boardFile, error) {
+	file := &boardFile{}
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			file.Boards = make(map[string]map[string][]string)
+			return file, nil
+		}
+		return nil, fmt.Errorf("failed to read board: %w", err)
+	}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse board: %w", err)
+	}
+	if file.Boards == nil {
+		file.Boards = make(map[string]map[string][]string)
+	}
+	return file, nil
+}
+
+func (b *Board) save(file *boardFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal board: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(b.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write board: %w", err)
+	}
+	return nil
+}
+
+// Columns returns a project's stored columns (status -> goal IDs)
+func (b *Board) Columns(project string) (map[string][]string, error) {
+	boardMu.Lock()
+	defer boardMu.Unlock()
+
+	file, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	columns := file.Boards[project]
+	if columns == nil {
+		columns = make(map[string][]string)
+	}
+	return columns, nil
+}
+
+// Move places goalID at index in a project's status column and removes it from
+// the project's other columns. members lists the goals currently in the column
+// (in display order); it decides where unplaced goals go and drops goals that
+// left the column. The index is clamped to the column. Returns the new order.
+func (b *Board) Move(project, status, goalID string, index int, members []string) ([]string, error) {
+	boardMu.Lock()
+	defer boardMu.Unlock()
+
+	file, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	columns := file.Boards[project]
+	if columns == nil {
+		columns = make(map[string][]string)
+		file.Boards[project] = columns
+	}
+
+	for s, ids := range columns {
+		if s != status {
+			columns[s] = removeID(ids, goalID)
+			if len(columns[s]) == 0 {
+				delete(columns, s)
+			}
+		}
+	}
+
+	order := removeID(SortColumn(columns[status], members), goalID)
+	if index < 0 {
+		index = 0
+	}
+	if index > len(order) {
+		index = len(order)
+	}
+	order = append(order[:index], append([]string{goalID}, order[index:]...)...)
+	columns[status] = order
+
+	if err := b.save(file); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// SortColumn orders members by their position in stored. Members missing from
+// stored keep their relative order after the placed ones.
+func SortColumn(stored, members []string) []string {
+	inColumn := make(map[string]bool, len(members))
+	for _, id := range members {
+		inColumn[id] = true
+	}
+
+	result := make([]string, 0, len(members))
+	placed := make(map[string]bool, len(stored))
+	for _, id := range stored {
+		if inColumn[id] && !placed[id] {
+			result = append(result, id)
+			placed[id] = true
+		}
+	}
+	for _, id := range members {
+		if !placed[id] {
+			result = append(result, id)
+			placed[id] = true
+		}
+	}
+	return result
+}
+
+func removeID(ids []string, id string) []string {
+	result := make([]string, 0, len(ids))
+	for _, existing := range ids {
+		if existing != id {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package goals
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortColumn(t *testing.T) {
+	got := SortColumn([]string{"c", "gone", "a"}, []string{"a", "b", "c", "d"})
+	want := []string{"c", "a", "b", "d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortColumn = %v, want %v", got, want)
+	}
+}
+
+func TestBoardMove(t *testing.T) {
+	board := NewBoard(t.TempDir())
+	members := []string{"a", "b", "c"}
+
+	order, err := board.Move("web", "active", "c", 0, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	// Out-of-range indexes are clamped
+	order, _ = board.Move("web", "active", "a", 99, members)
+	if want := []string{"c", "b", "a"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	// Moving to another column drops the goal from its old one
+	if _, err := board.Move("web", "iced", "b", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	columns, err := board.Columns("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"c", "a"}; !reflect.DeepEqual(columns["active"], want) {
+		t.Errorf("active = %v, want %v", columns["active"], want)
+	}
+	if want := []string{"b"}; !reflect.DeepEqual(columns["iced"], want) {
+		t.Errorf("iced = %v, want %v", columns["iced"], want)
+	}
+
+	// Boards are per project
+	columns, _ = board.Columns("")
+	if len(columns) != 0 {
+		t.Errorf("expected an empty all-goals board, got %v", columns)
+	}
+}
@@ -0,0 +1,68 @@
+package goals
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validCloneFilterRe matches the partial clone filters vega-hub accepts
+var validCloneFilterRe = regexp.MustCompile(`^(blob:none|tree:0|blob:limit=[0-9]+[kmg]?)$`)
+
+// CloneOptions controls how much of a large repository is fetched and checked
+// out, stored in projects/<name>.md as:
+//
+//	**Clone Depth**: `1`
+//	**Clone Filter**: `blob:none`
+//	**Sparse Paths**: `services/api`, `libs/common`
+//
+// Depth and filter apply when worktree-base is cloned; sparse paths apply to
+// worktree-base and every goal worktree.
+type CloneOptions struct {
+	CloneDepth  int      `json:"clone_depth,omitempty"`  // Shallow clone depth (0 = full history)
+	CloneFilter string   `json:"clone_filter,omitempty"` // Partial clone filter, e.g. "blob:none"
+	SparsePaths []string `json:"sparse_paths,omitempty"` // Directories to check out (cone mode)
+}
+
+// Validate checks the depth, filter and sparse paths
+func (c CloneOptions) Validate() error {
+	if c.CloneDepth < 0 {
+		return fmt.Errorf("clone depth must not be negative")
+	}
+	if c.CloneFilter != "" && !validCloneFilterRe.MatchString(c.CloneFilter) {
+		return fmt.Errorf("invalid clone filter %q (valid: blob:none, tree:0, blob:limit=<size>)", c.CloneFilter)
+	}
+	for _, p := range c.SparsePaths {
+		cleaned, err := CleanPathInRepo(p)
+		if err != nil {
+			return fmt.Errorf("invalid sparse path: %w", err)
+		}
+		if cleaned == "" || strings.HasPrefix(cleaned, "-") {
+			return fmt.Errorf("invalid sparse path: %q", p)
+		}
+	}
+	return nil
+}
+
+// IsSparse returns true if worktrees only check out SparsePaths
+func (c CloneOptions) IsSparse() bool {
+	return len(c.SparsePaths) > 0
+}
+
+// CloneArgs returns the git clone flags for these options
+func (c CloneOptions) CloneArgs() []string {
+	var args []string
+	if c.CloneDepth > 0 {
+		// Keep all branches so any base branch can be checked out
+		args = append(args, "--depth", strconv.Itoa(c.CloneDepth), "--no-single-branch")
+	}
+	if c.CloneFilter != "" {
+		args = append(args, "--filter="+c.CloneFilter)
+	}
+	if c.IsSparse() {
+		// Only top-level files are checked out until the sparse paths are set
+		args = append(args, "--sparse")
+	}
+	return args
+}
@@ -0,0 +1,70 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCloneOptionsValidate(t *testing.T) {
+	valid := []CloneOptions{
+		{},
+		{CloneDepth: 1, CloneFilter: "blob:none", SparsePaths: []string{"services/api", "libs/common"}},
+		{CloneFilter: "blob:limit=1m"},
+		{CloneFilter: "tree:0"},
+	}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid: %v", c, err)
+		}
+	}
+
+	invalid := []CloneOptions{
+		{CloneDepth: -1},
+		{CloneFilter: "blob:all"},
+		{SparsePaths: []string{"../other"}},
+		{SparsePaths: []string{"."}},
+		{SparsePaths: []string{"--no-cone"}},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", c)
+		}
+	}
+}
+
+func TestCloneOptionsCloneArgs(t *testing.T) {
+	if args := (CloneOptions{}).CloneArgs(); len(args) != 0 {
+		t.Errorf("expected no args for a full clone, got %v", args)
+	}
+	c := CloneOptions{CloneDepth: 5, CloneFilter: "blob:none", SparsePaths: []string{"services/api"}}
+	want := []string{"--depth", "5", "--no-single-branch", "--filter=blob:none", "--sparse"}
+	if args := c.CloneArgs(); !reflect.DeepEqual(args, want) {
+		t.Errorf("expected %v, got %v", want, args)
+	}
+}
+
+func TestParseProjectCloneOptions(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	config := strings.Join([]string{
+		"# Project: mono",
+		"",
+		"**Base Branch**: `main`",
+		"**Clone Depth**: `1`",
+		"**Clone Filter**: `blob:none`",
+		"**Sparse Paths**: `services/api`, `libs/common`",
+	}, "\n")
+	os.WriteFile(filepath.Join(dir, "projects", "mono.md"), []byte(config), 0644)
+
+	p, err := ParseProject(dir, "mono")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CloneOptions{CloneDepth: 1, CloneFilter: "blob:none", SparsePaths: []string{"services/api", "libs/common"}}
+	if !reflect.DeepEqual(p.CloneOptions, want) {
+		t.Errorf("expected %+v, got %+v", want, p.CloneOptions)
+	}
+}
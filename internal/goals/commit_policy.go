@@ -0,0 +1,166 @@
+package goals
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// When the commit policy is checked
+const (
+	CommitCheckComplete = "complete" // Only when the goal is completed (default)
+	CommitCheckSession  = "session"  // Also after every executor session
+)
+
+// CommitConventionConventional requires Conventional Commits subjects
+// ("type(scope)!: description")
+const CommitConventionConventional = "conventional"
+
+// DefaultCommitTypes are the commit types allowed by the conventional
+// convention unless the project lists its own
+var DefaultCommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// Commit policy rules reported in violations
+const (
+	CommitRuleUnsigned     = "unsigned"
+	CommitRuleBadSignature = "bad_signature"
+	CommitRuleConvention   = "convention"
+)
+
+var (
+	commitTypeRe         = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	conventionalCommitRe = regexp.MustCompile(`^([a-z][a-z0-9-]*)(\([^()]+\))?!?: \S`)
+)
+
+// CommitPolicy holds a project's rules for commits on goal branches, stored in
+// projects/<name>.md as:
+//
+//	**Require Signed Commits**: `true`
+//	**Commit Convention**: `conventional`
+//	**Commit Types**: `feat`, `fix`, `docs`
+//	**Commit Check**: `session`
+type CommitPolicy struct {
+	RequireSignedCommits bool     `json:"require_signed_commits,omitempty"` // GPG or SSH signatures
+	CommitConvention     string   `json:"commit_convention,omitempty"`      // "conventional" or empty for none
+	CommitTypes          []string `json:"commit_types,omitempty"`           // Allowed types (default: DefaultCommitTypes)
+	CommitCheck          string   `json:"commit_check,omitempty"`           // "complete" (default) or "session"
+}
+
+// CommitViolation is a goal branch commit that breaks the commit policy
+type CommitViolation struct {
+	Commit  string `json:"commit"` // Abbreviated hash
+	Subject string `json:"subject"`
+	Rule    string `json:"rule"` // "unsigned", "bad_signature", "convention"
+	Message string `json:"message"`
+}
+
+// String describes the violation as "<commit> <subject>: <message>"
+func (v CommitViolation) String() string {
+	return fmt.Sprintf("%s %q: %s", v.Commit, v.Subject, v.Message)
+}
+
+// Validate checks the convention, commit types and check mode
+func (p CommitPolicy) Validate() error {
+	if p.CommitConvention != "" && p.CommitConvention != CommitConventionConventional {
+		return fmt.Errorf("invalid commit convention %q (valid: %s)", p.CommitConvention, CommitConventionConventional)
+	}
+	for _, t := range p.CommitTypes {
+		if !commitTypeRe.MatchString(t) {
+			return fmt.Errorf("invalid commit type %q", t)
+		}
+	}
+	switch p.CommitCheck {
+	case "", CommitCheckComplete, CommitCheckSession:
+	default:
+		return fmt.Errorf("invalid commit check %q (valid: %s, %s)", p.CommitCheck, CommitCheckComplete, CommitCheckSession)
+	}
+	return nil
+}
+
+// Enabled returns true if the policy has any rule to enforce
+func (p CommitPolicy) Enabled() bool {
+	return p.RequireSignedCommits || p.CommitConvention != ""
+}
+
+// ChecksSessions returns true if commits are checked after every executor session
+func (p CommitPolicy) ChecksSessions() bool {
+	return p.Enabled() && p.CommitCheck == CommitCheckSession
+}
+
+// Types returns the allowed conventional commit types
+func (p CommitPolicy) Types() []string {
+	if len(p.CommitTypes) == 0 {
+		return DefaultCommitTypes
+	}
+	return p.CommitTypes
+}
+
+// CheckMessage returns why a commit subject breaks the convention, or "" if it
+// follows it
+func (p CommitPolicy) CheckMessage(subject string) string {
+	if p.CommitConvention != CommitConventionConventional {
+		return ""
+	}
+	matches := conventionalCommitRe.FindStringSubmatch(subject)
+	if matches == nil {
+		return `subject must look like "type(scope): description"`
+	}
+	for _, t := range p.Types() {
+		if matches[1] == t {
+			return ""
+		}
+	}
+	return fmt.Sprintf("type %q is not allowed (allowed: %s)", matches[1], strings.Join(p.Types(), ", "))
+}
+
+// CheckCommits checks the commits in worktree that are not on base and
+// returns the violations, oldest commit first. Merge commits are exempt from
+// the message convention. Signatures git can't verify on this host (unknown
+// key) count as signed; missing, bad and revoked signatures don't.
+func (p CommitPolicy) CheckCommits(worktree, base string) ([]CommitViolation, error) {
+	violations := []CommitViolation{}
+	if !p.Enabled() {
+		return violations, nil
+	}
+
+	format := "--format=%h%x1f%G?%x1f%P%x1f%s"
+	cmd := exec.Command("git", "-C", worktree, "log", "--reverse", format, base+"..HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits since %s: %w", base, err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		hash, signature, parents, subject := fields[0], fields[1], fields[2], fields[3]
+
+		if p.RequireSignedCommits {
+			switch signature {
+			case "N":
+				violations = append(violations, CommitViolation{hash, subject, CommitRuleUnsigned, "commit is not signed"})
+			case "B", "R":
+				violations = append(violations, CommitViolation{hash, subject, CommitRuleBadSignature, "commit signature is bad or its key is revoked"})
+			}
+		}
+		if len(strings.Fields(parents)) > 1 {
+			continue
+		}
+		if msg := p.CheckMessage(subject); msg != "" {
+			violations = append(violations, CommitViolation{hash, subject, CommitRuleConvention, msg})
+		}
+	}
+	return violations, nil
+}
+
+// SummarizeViolations describes violations one per line
+func SummarizeViolations(violations []CommitViolation) string {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,109 @@
+package goals
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProjectCommitPolicy(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	config := "# Project: my-api\n\n**Base Branch**: `main`\n\n## Commit Policy\n\n" +
+		"**Require Signed Commits**: `yes`\n**Commit Convention**: `conventional`\n" +
+		"**Commit Types**: `feat`, `fix`\n**Commit Check**: `session`\n"
+	os.WriteFile(filepath.Join(dir, "projects", "my-api.md"), []byte(config), 0644)
+
+	project, err := ParseProject(dir, "my-api")
+	if err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+	policy := project.CommitPolicy
+	if !policy.RequireSignedCommits || policy.CommitConvention != CommitConventionConventional || len(policy.CommitTypes) != 2 {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+	if err := policy.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	if !policy.ChecksSessions() {
+		t.Error("expected session checks")
+	}
+
+	if (CommitPolicy{}).Enabled() {
+		t.Error("empty policy should not be enabled")
+	}
+	if err := (CommitPolicy{CommitConvention: "gitmoji"}).Validate(); err == nil {
+		t.Error("expected error for unknown convention")
+	}
+	if err := (CommitPolicy{CommitCheck: "always"}).Validate(); err == nil {
+		t.Error("expected error for unknown check mode")
+	}
+}
+
+func TestCommitPolicyCheckMessage(t *testing.T) {
+	policy := CommitPolicy{CommitConvention: CommitConventionConventional}
+	for subject, ok := range map[string]bool{
+		"feat: add login":              true,
+		"fix(auth): handle expiry":     true,
+		"refactor(api)!: drop v1":      true,
+		"Add login":                    false,
+		"feat:add login":               false,
+		"feature: add login":           false,
+		"feat(): add login":            false,
+		"Merge branch 'main' into foo": false,
+	} {
+		if got := policy.CheckMessage(subject) == ""; got != ok {
+			t.Errorf("CheckMessage(%q) passed = %v, want %v", subject, got, ok)
+		}
+	}
+
+	custom := CommitPolicy{CommitConvention: CommitConventionConventional, CommitTypes: []string{"feat"}}
+	if custom.CheckMessage("fix: typo") == "" {
+		t.Error("expected fix to be rejected when only feat is allowed")
+	}
+	if (CommitPolicy{}).CheckMessage("anything goes") != "" {
+		t.Error("expected no convention check without a convention")
+	}
+}
+
+func TestCommitPolicyCheckCommits(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-C", dir, "-c", "user.email=t@t", "-c", "user.name=t", "-c", "commit.gpgsign=false"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "Initial")
+	git("checkout", "-q", "-b", "goal")
+	git("commit", "-q", "--allow-empty", "-m", "feat: add login")
+	git("commit", "-q", "--allow-empty", "-m", "Fix typo")
+
+	policy := CommitPolicy{CommitConvention: CommitConventionConventional}
+	violations, err := policy.CheckCommits(dir, "main")
+	if err != nil {
+		t.Fatalf("CheckCommits failed: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Subject != "Fix typo" || violations[0].Rule != CommitRuleConvention {
+		t.Fatalf("unexpected violations: %+v", violations)
+	}
+
+	policy.RequireSignedCommits = true
+	violations, _ = policy.CheckCommits(dir, "main")
+	unsigned := 0
+	for _, v := range violations {
+		if v.Rule == CommitRuleUnsigned {
+			unsigned++
+		}
+	}
+	if unsigned != 2 || len(violations) != 3 {
+		t.Errorf("expected 2 unsigned commits and 3 violations, got %+v", violations)
+	}
+
+	if _, err := policy.CheckCommits(dir, "no-such-branch"); err == nil {
+		t.Error("expected error for unknown base branch")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // CompletionSignalType defines the type of completion signal detected
@@ -38,11 +40,12 @@ type CompletionStatus struct {
 // CompletionChecker provides methods for checking goal completion
 type CompletionChecker struct {
 	baseDir string
+	layout  *layout.Layout
 }
 
 // NewCompletionChecker creates a new CompletionChecker for the given base directory
 func NewCompletionChecker(baseDir string) *CompletionChecker {
-	return &CompletionChecker{baseDir: baseDir}
+	return &CompletionChecker{baseDir: baseDir, layout: layout.New(baseDir)}
 }
 
 // CheckGoal returns the comprehensive completion status for a goal
@@ -274,27 +277,10 @@ func (c *CompletionChecker) calculateCompletion(status *CompletionStatus) {
 	status.Complete = len(status.MissingTasks) == 0 && (hasStrongSignal || confidence >= 0.5)
 }
 
-// findGoalFile locates the goal markdown file
-// Checks both flat structure (goals/active/<id>.md) and folder structure (goals/active/<id>/<id>.md)
+// findGoalFile locates the goal markdown file in either layout style
 func (c *CompletionChecker) findGoalFile(goalID string) string {
-	// Check all goal directories with both flat and folder structures
-	dirs := []string{"active", "iced", "history"}
-
-	for _, dir := range dirs {
-		// Flat structure: goals/<dir>/<id>.md
-		flatPath := filepath.Join(c.baseDir, "goals", dir, goalID+".md")
-		if fileExists(flatPath) {
-			return flatPath
-		}
-
-		// Folder structure: goals/<dir>/<id>/<id>.md
-		folderPath := filepath.Join(c.baseDir, "goals", dir, goalID, goalID+".md")
-		if fileExists(folderPath) {
-			return folderPath
-		}
-	}
-
-	return ""
+	path, _ := c.layout.FindGoalFile(goalID)
+	return path
 }
 
 // Legacy functions for backwards compatibility
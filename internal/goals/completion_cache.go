@@ -0,0 +1,133 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// CompletionCache memoizes CheckGoal results. An entry stays valid while the
+// goal file and its worktree's HEAD reflog (touched by every commit, checkout
+// and reset) keep the same modification times, so repeated goal listings only
+// re-check goals that changed.
+type CompletionCache struct {
+	baseDir string
+
+	mu      sync.Mutex
+	entries map[string]completionEntry
+}
+
+type completionEntry struct {
+	fingerprint completionFingerprint
+	status      *CompletionStatus
+}
+
+// completionFingerprint captures the files CheckGoal reads
+type completionFingerprint struct {
+	goalFile    string
+	goalModTime time.Time
+	goalSize    int64
+	headModTime time.Time // Zero if the goal has no worktree
+}
+
+// NewCompletionCache creates a cache for goals under baseDir
+func NewCompletionCache(baseDir string) *CompletionCache {
+	return &CompletionCache{
+		baseDir: baseDir,
+		entries: make(map[string]completionEntry),
+	}
+}
+
+// CheckGoal returns the goal's completion status, reusing the last result
+// while the goal file and worktree HEAD are unchanged. Callers must not
+// modify the returned status.
+func (c *CompletionCache) CheckGoal(goalID string) (*CompletionStatus, error) {
+	checker := NewCompletionChecker(c.baseDir)
+	fp, ok := c.fingerprint(checker, goalID)
+	if ok {
+		c.mu.Lock()
+		entry, hit := c.entries[goalID]
+		c.mu.Unlock()
+		if hit && entry.fingerprint == fp {
+			return entry.status, nil
+		}
+	}
+
+	status, err := checker.CheckGoal(goalID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		c.mu.Lock()
+		c.entries[goalID] = completionEntry{fingerprint: fp, status: status}
+		c.mu.Unlock()
+	}
+	return status, nil
+}
+
+// Invalidate drops the cached status of a goal
+func (c *CompletionCache) Invalidate(goalID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, goalID)
+}
+
+// fingerprint stats the files a completion check depends on. It returns false
+// when the goal file can't be found, in which case nothing is cached.
+func (c *CompletionCache) fingerprint(checker *CompletionChecker, goalID string) (completionFingerprint, bool) {
+	goalFile := checker.findGoalFile(goalID)
+	if goalFile == "" {
+		return completionFingerprint{}, false
+	}
+	info, err := os.Stat(goalFile)
+	if err != nil {
+		return completionFingerprint{}, false
+	}
+	fp := completionFingerprint{
+		goalFile:    goalFile,
+		goalModTime: info.ModTime(),
+		goalSize:    info.Size(),
+	}
+
+	// Worktrees live at workspaces/<project>/goal-<id>-<slug>; a goal file
+	// edit (which changes the path) already changes the fingerprint
+	matches, _ := filepath.Glob(filepath.Join(layout.New(c.baseDir).WorkspacesDir(), "*", "goal-"+goalID+"-*"))
+	for _, wt := range matches {
+		if t, ok := headModTime(wt); ok {
+			fp.headModTime = t
+			break
+		}
+	}
+	return fp, true
+}
+
+// headModTime returns the modification time of a worktree's HEAD reflog,
+// following the .git file of linked worktrees to their git directory
+func headModTime(worktree string) (time.Time, bool) {
+	gitDir := filepath.Join(worktree, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(gitDir)
+		if err != nil {
+			return time.Time{}, false
+		}
+		dir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(worktree, dir)
+		}
+		gitDir = dir
+	}
+	for _, name := range []string{filepath.Join("logs", "HEAD"), "HEAD"} {
+		if info, err := os.Stat(filepath.Join(gitDir, name)); err == nil {
+			return info.ModTime(), true
+		}
+	}
+	return time.Time{}, false
+}
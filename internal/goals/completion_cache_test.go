@@ -0,0 +1,98 @@
+package goals
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const cachedGoalContent = `# Goal #abc1234: Cached Goal
+
+## Phases
+
+### Phase 1: Setup
+- [x] Initialize project
+- **Status:** complete
+`
+
+func TestCompletionCache_ReusesUntilGoalFileChanges(t *testing.T) {
+	dir := setupCompletionTestDir(t)
+	writeGoalFile(t, dir, "abc1234", cachedGoalContent)
+	cache := NewCompletionCache(dir)
+
+	first, err := cache.CheckGoal("abc1234")
+	if err != nil {
+		t.Fatalf("CheckGoal failed: %v", err)
+	}
+	if !first.Complete {
+		t.Fatalf("expected goal to be complete")
+	}
+	second, _ := cache.CheckGoal("abc1234")
+	if second != first {
+		t.Error("expected the cached status to be reused")
+	}
+
+	// Reopen a phase; bump the mtime in case the write lands in the same tick
+	writeGoalFile(t, dir, "abc1234", cachedGoalContent+"\n### Phase 2: More\n- [ ] Another task\n")
+	path := filepath.Join(dir, "goals", "active", "abc1234.md")
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+
+	third, _ := cache.CheckGoal("abc1234")
+	if third == first {
+		t.Fatal("expected a fresh status after the goal file changed")
+	}
+	if third.Complete {
+		t.Error("expected goal to be incomplete after adding a pending phase")
+	}
+}
+
+func TestCompletionCache_InvalidatedByWorktreeCommit(t *testing.T) {
+	dir := setupCompletionTestDir(t)
+	writeGoalFile(t, dir, "abc1234", cachedGoalContent+`
+## Worktree
+- **Branch**: goal-abc1234-cached
+- **Project**: test-project
+- **Path**: workspaces/test-project/goal-abc1234-cached
+`)
+	worktree := filepath.Join(dir, "workspaces", "test-project", "goal-abc1234-cached")
+	os.MkdirAll(worktree, 0755)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", worktree, "-c", "user.email=test@example.com", "-c", "user.name=Test"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	git("init")
+	git("commit", "--allow-empty", "-m", "Start work")
+
+	cache := NewCompletionCache(dir)
+	first, _ := cache.CheckGoal("abc1234")
+
+	git("commit", "--allow-empty", "-m", "Finished goal")
+	later := time.Now().Add(time.Second)
+	os.Chtimes(filepath.Join(worktree, ".git", "logs", "HEAD"), later, later)
+
+	second, _ := cache.CheckGoal("abc1234")
+	if second == first {
+		t.Fatal("expected a fresh status after a new commit")
+	}
+
+	cache.Invalidate("abc1234")
+	third, _ := cache.CheckGoal("abc1234")
+	if third == second {
+		t.Error("expected Invalidate to drop the cached status")
+	}
+}
+
+func TestCompletionCache_MissingGoal(t *testing.T) {
+	dir := setupCompletionTestDir(t)
+	cache := NewCompletionCache(dir)
+
+	if _, err := cache.CheckGoal("nonexistent"); err == nil {
+		t.Error("expected error for missing goal")
+	}
+}
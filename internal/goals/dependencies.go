@@ -4,8 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // DependencyType represents the type of dependency relationship
@@ -39,35 +40,25 @@ type GoalMetadata struct {
 	NextChildIndex int          `json:"next_child_index,omitempty"` // Next index for child goals
 }
 
+// metadataSuffix names a goal's metadata file, e.g. abc123.metadata.json
+const metadataSuffix = ".metadata.json"
+
 // DependencyManager handles goal dependency operations
 type DependencyManager struct {
-	dir string // vega-missile directory
-	mu  sync.RWMutex
+	dir    string // vega-missile directory
+	layout *layout.Layout
+	mu     sync.RWMutex
 }
 
 // NewDependencyManager creates a new DependencyManager
 func NewDependencyManager(dir string) *DependencyManager {
-	return &DependencyManager{dir: dir}
+	return &DependencyManager{dir: dir, layout: layout.New(dir)}
 }
 
-// metadataFilePath returns the path to a goal's metadata file
+// metadataFilePath returns the path to a goal's metadata file, next to its
+// goal file (active for new goals)
 func (m *DependencyManager) metadataFilePath(goalID string) (string, error) {
-	// Check active, then iced, then history
-	locations := []string{
-		filepath.Join(m.dir, "goals", "active"),
-		filepath.Join(m.dir, "goals", "iced"),
-		filepath.Join(m.dir, "goals", "history"),
-	}
-
-	for _, loc := range locations {
-		goalFile := filepath.Join(loc, goalID+".md")
-		if _, err := os.Stat(goalFile); err == nil {
-			return filepath.Join(loc, goalID+".metadata.json"), nil
-		}
-	}
-
-	// Default to active for new goals
-	return filepath.Join(m.dir, "goals", "active", goalID+".metadata.json"), nil
+	return m.layout.GoalSidecar(goalID, metadataSuffix), nil
 }
 
 // readMetadata reads the metadata file for a goal
@@ -110,14 +101,8 @@ func (m *DependencyManager) writeMetadata(goalID string, meta *GoalMetadata) err
 
 // goalExists checks if a goal exists in any status
 func (m *DependencyManager) goalExists(goalID string) bool {
-	locations := []string{
-		filepath.Join(m.dir, "goals", "active", goalID+".md"),
-		filepath.Join(m.dir, "goals", "iced", goalID+".md"),
-		filepath.Join(m.dir, "goals", "history", goalID+".md"),
-	}
-
-	for _, path := range locations {
-		if _, err := os.Stat(path); err == nil {
+	for _, dir := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+		if m.layout.FindGoalFileIn(dir, goalID) != "" {
 			return true
 		}
 	}
@@ -276,38 +261,13 @@ func (m *DependencyManager) findDependents(goalID string) ([]Dependency, error)
 	var dependents []Dependency
 
 	// Scan all goal directories
-	dirs := []string{
-		filepath.Join(m.dir, "goals", "active"),
-		filepath.Join(m.dir, "goals", "iced"),
-		filepath.Join(m.dir, "goals", "history"),
-	}
-
-	for _, dir := range dirs {
-		entries, err := os.ReadDir(dir)
-		if os.IsNotExist(err) {
-			continue
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		for _, entry := range entries {
-			if filepath.Ext(entry.Name()) != ".json" {
-				continue
-			}
-			if entry.Name() == goalID+".metadata.json" {
+	for _, dir := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+		for otherID, metaPath := range m.layout.Sidecars(dir, metadataSuffix) {
+			if otherID == goalID {
 				continue // Skip self
 			}
 
-			// Extract goal ID from filename (e.g., "abc123.metadata.json" -> "abc123")
-			name := entry.Name()
-			if len(name) < 15 { // ".metadata.json" = 14 chars + at least 1 char for ID
-				continue
-			}
-			otherID := name[:len(name)-14] // Remove ".metadata.json"
-
 			// Read metadata and check if it depends on goalID
-			metaPath := filepath.Join(dir, entry.Name())
 			data, err := os.ReadFile(metaPath)
 			if err != nil {
 				continue
@@ -557,13 +517,5 @@ func (m *DependencyManager) MoveMetadataFile(goalID, fromDir, toDir string) erro
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	fromPath := filepath.Join(m.dir, "goals", fromDir, goalID+".metadata.json")
-	toPath := filepath.Join(m.dir, "goals", toDir, goalID+".metadata.json")
-
-	// Only move if source exists
-	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
-		return nil // Nothing to move
-	}
-
-	return os.Rename(fromPath, toPath)
+	return m.layout.MoveSidecar(goalID, metadataSuffix, fromDir, toDir)
 }
@@ -0,0 +1,102 @@
+package goals
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Goal is the goal model: the registry stores one per line of registry.jsonl
+// (as RegistryEntry) and every API endpoint that returns goals uses it, alone
+// or embedded. Fields are only ever added, so clients of the older, smaller
+// shape keep working.
+type Goal struct {
+	ID          string   `json:"id"` // Can be numeric ("10"), hash ("4fd584d"), or hierarchical ("4fd584d.1")
+	Title       string   `json:"title"`
+	Projects    []string `json:"projects"`
+	Status      string   `json:"status"`                 // "draft", "active", "iced", "completed"
+	Phase       string   `json:"phase"`                  // e.g., "1/4" or "?"
+	ParentID    string   `json:"parent_id,omitempty"`    // Parent goal ID for hierarchical goals
+	BlockedBy   []string `json:"blocked_by,omitempty"`   // Goals that must complete first
+	Reason      string   `json:"reason,omitempty"`       // For iced goals
+	IcedBy      string   `json:"iced_by,omitempty"`      // User who iced the goal
+	CompletedAt string   `json:"completed_at,omitempty"` // YYYY-MM-DD
+	CompletedBy string   `json:"completed_by,omitempty"` // User who completed the goal
+	IssueURL    string   `json:"issue_url,omitempty"`    // Linked external issue
+	TrackerID   string   `json:"tracker_id,omitempty"`   // e.g. "ENG-123" or "#42"
+	Priority    int      `json:"priority,omitempty"`     // Backlog order of drafts, highest first
+	CreatedAt   string   `json:"created_at"`             // RFC 3339
+	UpdatedAt   string   `json:"updated_at"`             // RFC 3339
+	CreatedBy   string   `json:"created_by,omitempty"`   // User who created the goal
+	Tags        []string `json:"tags,omitempty"`         // Normalized by NormalizeTags
+	Alias       string   `json:"alias,omitempty"`        // Unique human name, see NormalizeAlias
+
+	// Child goal IDs, filled in when listing; not stored in the registry
+	Children []string `json:"children,omitempty"`
+}
+
+// withFile combines a registry entry with what was read from the goal file.
+// The file is authoritative for the status (its directory), and for the
+// title, projects and phase it declares.
+func (e *Goal) withFile(file Goal) Goal {
+	goal := *e
+	goal.ID, goal.Status = file.ID, file.Status
+	if file.Title != "" {
+		goal.Title = file.Title
+	}
+	if len(file.Projects) > 0 {
+		goal.Projects = file.Projects
+	}
+	if file.Phase != "" {
+		goal.Phase = file.Phase
+	}
+	if goal.Status != "completed" {
+		goal.CompletedAt = ""
+	}
+	return goal
+}
+
+var tagRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+// NormalizeTags lowercases and trims tags and drops empty and duplicate ones,
+// keeping their order. Tags are letters, digits and . _ / - (e.g. "area/auth").
+func NormalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > 64 || !tagRe.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q: use letters, digits and . _ / - (at most 64)", tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// HasTag reports whether the goal carries tag
+func (e *Goal) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces a goal's tags in the registry
+func (r *Registry) SetTags(id string, tags []string) error {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	return r.Update(id, func(e *RegistryEntry) {
+		e.Tags = tags
+		e.UpdatedAt = time.Now().Format(time.RFC3339)
+	})
+}
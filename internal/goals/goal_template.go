@@ -0,0 +1,64 @@
+package goals
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// runtimeSections are goal file sections written while a goal runs; they
+// don't carry over when a goal is used as a template
+var runtimeSections = []string{
+	"## Worktree",
+	"## Status",
+	"## Current Phase",
+	"## Executor Questions",
+	"## Executor Activity",
+}
+
+var (
+	goalTitleRe    = regexp.MustCompile(`^# Goal #?[0-9a-f.]+: `)
+	checkedTaskRe  = regexp.MustCompile(`^(\s*- )\[[xX]\]`)
+	phaseHeadingRe = regexp.MustCompile(`^### Phase \d+:`)
+)
+
+// GoalTemplate turns a goal file into the body of a new goal: the title line
+// and runtime sections (worktree, status, executor Q&A and activity) are
+// dropped, tasks and acceptance criteria are unchecked, and a fresh status
+// section starting at phase 1 is appended.
+func GoalTemplate(content string) string {
+	var kept []string
+	skipping := false
+	titleDone := false
+	phases := 0
+
+	for _, line := range strings.Split(content, "\n") {
+		if !titleDone && goalTitleRe.MatchString(line) {
+			titleDone = true
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			skipping = false
+			for _, section := range runtimeSections {
+				if strings.HasPrefix(line, section) {
+					skipping = true
+					break
+				}
+			}
+		}
+		if skipping {
+			continue
+		}
+		if phaseHeadingRe.MatchString(line) {
+			phases++
+		}
+		kept = append(kept, checkedTaskRe.ReplaceAllString(line, "$1[ ]"))
+	}
+
+	total := "?"
+	if phases > 0 {
+		total = fmt.Sprint(phases)
+	}
+	body := strings.Trim(strings.Join(kept, "\n"), "\n")
+	return fmt.Sprintf("\n%s\n\n## Status\n\nCurrent Phase: 1/%s\n", body, total)
+}
@@ -0,0 +1,60 @@
+package goals
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoalTemplate(t *testing.T) {
+	content := `# Goal #abc1234: Rotate TLS certificates
+
+## Overview
+
+Renew the certificates before they expire.
+
+## Phases
+
+### Phase 1: Inventory
+- [x] List expiring certificates
+- [ ] Check owners
+
+### Phase 2: Rotate
+- [X] Issue new certificates
+
+## Acceptance Criteria
+
+- [x] No certificate expires within 30 days
+
+## Worktree
+- **Branch**: goal-abc1234-rotate-tls-certificates
+- **Project**: infra
+
+## Executor Questions
+
+**Q**: Which CA?
+
+## Status
+
+Current Phase: 2/2
+`
+	body := GoalTemplate(content)
+
+	for _, gone := range []string{"# Goal", "## Worktree", "## Executor Questions", "Which CA?", "[x]", "[X]", "Current Phase: 2/2"} {
+		if strings.Contains(body, gone) {
+			t.Errorf("template should not contain %q:\n%s", gone, body)
+		}
+	}
+	for _, kept := range []string{
+		"Renew the certificates before they expire.",
+		"### Phase 1: Inventory\n- [ ] List expiring certificates\n- [ ] Check owners",
+		"### Phase 2: Rotate\n- [ ] Issue new certificates",
+		"## Acceptance Criteria\n\n- [ ] No certificate expires within 30 days",
+	} {
+		if !strings.Contains(body, kept) {
+			t.Errorf("template should contain %q:\n%s", kept, body)
+		}
+	}
+	if !strings.HasSuffix(body, "## Status\n\nCurrent Phase: 1/2\n") {
+		t.Errorf("expected a fresh status section, got:\n%s", body)
+	}
+}
@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // MaxHierarchyDepth is the maximum allowed nesting depth for goals
@@ -21,13 +22,17 @@ const MaxHierarchyDepth = 3
 
 // HierarchyManager handles parent-child relationships between goals
 type HierarchyManager struct {
-	dir string // vega-missile directory
-	mu  sync.RWMutex
+	dir    string // vega-missile directory
+	layout *layout.Layout
+	mu     sync.RWMutex
 }
 
+// hierarchySuffix names a goal's hierarchy metadata file, e.g. abc123.hierarchy.json
+const hierarchySuffix = ".hierarchy.json"
+
 // NewHierarchyManager creates a new HierarchyManager
 func NewHierarchyManager(dir string) *HierarchyManager {
-	return &HierarchyManager{dir: dir}
+	return &HierarchyManager{dir: dir, layout: layout.New(dir)}
 }
 
 // HierarchyMetadata stores hierarchy-specific metadata for a goal
@@ -47,22 +52,8 @@ type GoalWithHierarchy struct {
 
 // hierarchyMetadataPath returns the path to a goal's hierarchy metadata file
 func (m *HierarchyManager) hierarchyMetadataPath(goalID string) (string, error) {
-	// Find goal location
-	locations := []string{
-		filepath.Join(m.dir, "goals", "active"),
-		filepath.Join(m.dir, "goals", "iced"),
-		filepath.Join(m.dir, "goals", "history"),
-	}
-
-	for _, loc := range locations {
-		goalFile := filepath.Join(loc, goalID+".md")
-		if _, err := os.Stat(goalFile); err == nil {
-			return filepath.Join(loc, goalID+".hierarchy.json"), nil
-		}
-	}
-
-	// Default to active for new goals
-	return filepath.Join(m.dir, "goals", "active", goalID+".hierarchy.json"), nil
+	// Next to the goal file; active for new goals
+	return m.layout.GoalSidecar(goalID, hierarchySuffix), nil
 }
 
 // readHierarchyMetadata reads the hierarchy metadata for a goal
@@ -137,31 +128,9 @@ func (m *HierarchyManager) GetChildren(parentID string) ([]string, error) {
 	var children []string
 
 	// Scan all goal directories
-	dirs := []string{
-		filepath.Join(m.dir, "goals", "active"),
-		filepath.Join(m.dir, "goals", "iced"),
-		filepath.Join(m.dir, "goals", "history"),
-	}
-
-	for _, dir := range dirs {
-		entries, err := os.ReadDir(dir)
-		if os.IsNotExist(err) {
-			continue
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		for _, entry := range entries {
-			if !strings.HasSuffix(entry.Name(), ".hierarchy.json") {
-				continue
-			}
-
-			// Extract goal ID
-			goalID := strings.TrimSuffix(entry.Name(), ".hierarchy.json")
-
+	for _, dir := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+		for goalID, metaPath := range m.layout.Sidecars(dir, hierarchySuffix) {
 			// Read hierarchy metadata
-			metaPath := filepath.Join(dir, entry.Name())
 			data, err := os.ReadFile(metaPath)
 			if err != nil {
 				continue
@@ -512,8 +481,12 @@ func renderTreeNode(sb *strings.Builder, node *GoalTreeNode, prefix string, isLa
 		title = title[:37] + "..."
 	}
 
-	// Format: goal-id  Title (status)
-	sb.WriteString(fmt.Sprintf("%s%s%s %s  %s\n", prefix, connector, statusIcon, node.Goal.ID, title))
+	// Format: goal-id (alias)  Title
+	id := node.Goal.ID
+	if node.Goal.Alias != "" {
+		id += " (" + node.Goal.Alias + ")"
+	}
+	sb.WriteString(fmt.Sprintf("%s%s%s %s  %s\n", prefix, connector, statusIcon, id, title))
 
 	// Prepare prefix for children
 	childPrefix := prefix
@@ -620,15 +593,7 @@ func (m *HierarchyManager) MoveHierarchyFile(goalID, fromDir, toDir string) erro
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	fromPath := filepath.Join(m.dir, "goals", fromDir, goalID+".hierarchy.json")
-	toPath := filepath.Join(m.dir, "goals", toDir, goalID+".hierarchy.json")
-
-	// Only move if source exists
-	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
-		return nil
-	}
-
-	return os.Rename(fromPath, toPath)
+	return m.layout.MoveSidecar(goalID, hierarchySuffix, fromDir, toDir)
 }
 
 // ParseHierarchicalID parses a hierarchical goal ID into its components
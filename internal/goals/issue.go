@@ -0,0 +1,129 @@
+package goals
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Issue trackers recognized from issue URLs
+const (
+	TrackerGitHub = "github"
+	TrackerGitLab = "gitlab"
+	TrackerJira   = "jira"
+	TrackerLinear = "linear"
+	TrackerOther  = "other"
+)
+
+// maxTrackerID bounds a tracker ID, which also prefixes branch names
+const maxTrackerID = 40
+
+var (
+	// Jira and Linear keys: ENG-123
+	issueKeyRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-[0-9]+$`)
+	// GitHub /owner/repo/issues/42 and GitLab /group/repo/-/issues/42
+	issueNumberRe = regexp.MustCompile(`/issues/([0-9]+)/?$`)
+)
+
+// IssueLink is the external issue a goal is tracked in
+type IssueLink struct {
+	URL       string `json:"issue_url,omitempty"`
+	TrackerID string `json:"tracker_id,omitempty"` // e.g. "ENG-123" or "#42"
+}
+
+// NormalizeIssueLink validates a goal's issue link and fills in the tracker
+// ID from well-known URL shapes (GitHub and GitLab issues, Jira, Linear) when
+// it isn't given. Either field may be empty; both empty clears the link.
+func NormalizeIssueLink(link IssueLink) (IssueLink, error) {
+	link.URL = strings.TrimSpace(link.URL)
+	link.TrackerID = strings.TrimSpace(link.TrackerID)
+	if link.URL != "" {
+		u, err := url.Parse(link.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return link, fmt.Errorf("invalid issue URL %q: must be an http(s) URL", link.URL)
+		}
+		if link.TrackerID == "" {
+			link.TrackerID = TrackerIDFromURL(link.URL)
+		}
+	}
+	if len(link.TrackerID) > maxTrackerID {
+		return link, fmt.Errorf("tracker ID is too long (max %d characters)", maxTrackerID)
+	}
+	if strings.ContainsAny(link.TrackerID, " \t\r\n") {
+		return link, fmt.Errorf("invalid tracker ID %q: must not contain whitespace", link.TrackerID)
+	}
+	return link, nil
+}
+
+// TrackerIDFromURL derives a tracker ID from an issue URL: "#42" for GitHub
+// and GitLab issues, the issue key for Jira and Linear ("" if unrecognized)
+func TrackerIDFromURL(issueURL string) string {
+	u, err := url.Parse(issueURL)
+	if err != nil {
+		return ""
+	}
+	if m := issueNumberRe.FindStringSubmatch(u.Path); m != nil {
+		return "#" + m[1]
+	}
+	if key := u.Query().Get("selectedIssue"); issueKeyRe.MatchString(key) {
+		return strings.ToUpper(key)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		// Jira /browse/ENG-123, Linear /team/issue/ENG-123/title
+		if (segment == "browse" || segment == "issue") && i+1 < len(segments) && issueKeyRe.MatchString(segments[i+1]) {
+			return strings.ToUpper(segments[i+1])
+		}
+	}
+	return ""
+}
+
+// Tracker names the issue tracker of an issue URL
+func Tracker(issueURL string) string {
+	u, err := url.Parse(issueURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "github.com" || strings.HasSuffix(host, ".github.com"):
+		return TrackerGitHub
+	case strings.Contains(host, "gitlab") && strings.Contains(u.Path, "/-/issues/"):
+		return TrackerGitLab
+	case strings.HasSuffix(host, "linear.app"):
+		return TrackerLinear
+	case strings.Contains(host, "jira") || strings.HasSuffix(host, ".atlassian.net"):
+		return TrackerJira
+	}
+	return TrackerOther
+}
+
+// IssueBranch prefixes a goal branch with the tracker ID ("ENG-123/goal-...",
+// "issue-42/goal-..." for "#42"). The branch is unchanged without a usable ID.
+func IssueBranch(trackerID, branch string) string {
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, strings.TrimPrefix(trackerID, "#"))
+	id = strings.Trim(id, "-")
+	if id == "" || branch == "" {
+		return branch
+	}
+	if strings.HasPrefix(trackerID, "#") {
+		id = "issue-" + id
+	}
+	return id + "/" + branch
+}
+
+// SetIssueLink records a goal's issue link in the registry
+func (r *Registry) SetIssueLink(id string, link IssueLink) error {
+	return r.Update(id, func(e *RegistryEntry) {
+		e.IssueURL = link.URL
+		e.TrackerID = link.TrackerID
+		e.UpdatedAt = time.Now().Format(time.RFC3339)
+	})
+}
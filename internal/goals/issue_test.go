@@ -0,0 +1,53 @@
+package goals
+
+import "testing"
+
+func TestTrackerIDFromURL(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"https://github.com/acme/api/issues/42", "#42"},
+		{"https://gitlab.example.com/group/api/-/issues/7/", "#7"},
+		{"https://acme.atlassian.net/browse/ENG-123", "ENG-123"},
+		{"https://acme.atlassian.net/jira/software/projects/ENG/boards/1?selectedIssue=ENG-9", "ENG-9"},
+		{"https://linear.app/acme/issue/eng-12/fix-login", "ENG-12"},
+		{"https://example.com/tickets/12", ""},
+	}
+	for _, tt := range tests {
+		if got := TrackerIDFromURL(tt.url); got != tt.want {
+			t.Errorf("TrackerIDFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeIssueLink(t *testing.T) {
+	link, err := NormalizeIssueLink(IssueLink{URL: " https://github.com/acme/api/issues/42 "})
+	if err != nil || link.URL != "https://github.com/acme/api/issues/42" || link.TrackerID != "#42" {
+		t.Errorf("NormalizeIssueLink = %+v, %v", link, err)
+	}
+	if link, _ := NormalizeIssueLink(IssueLink{URL: "https://github.com/acme/api/issues/42", TrackerID: "API-1"}); link.TrackerID != "API-1" {
+		t.Errorf("an explicit tracker ID should be kept, got %q", link.TrackerID)
+	}
+	for _, bad := range []IssueLink{{URL: "javascript:alert(1)"}, {URL: "github.com/acme"}, {TrackerID: "ENG 1"}} {
+		if _, err := NormalizeIssueLink(bad); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestIssueBranch(t *testing.T) {
+	tests := []struct {
+		trackerID, want string
+	}{
+		{"ENG-123", "ENG-123/goal-abc1234-fix"},
+		{"#42", "issue-42/goal-abc1234-fix"},
+		{"a b..c", "a-b--c/goal-abc1234-fix"},
+		{"", "goal-abc1234-fix"},
+		{"#", "goal-abc1234-fix"},
+	}
+	for _, tt := range tests {
+		if got := IssueBranch(tt.trackerID, "goal-abc1234-fix"); got != tt.want {
+			t.Errorf("IssueBranch(%q) = %q, want %q", tt.trackerID, got, tt.want)
+		}
+	}
+}
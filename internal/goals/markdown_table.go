@@ -0,0 +1,340 @@
+package goals
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// MarkdownDoc is a markdown file with its pipe tables parsed, for editing
+// tables by column name instead of splicing lines. Text outside the edited
+// tables is written back unchanged.
+type MarkdownDoc struct {
+	lines   []string
+	tables  []*MarkdownTable
+	newline bool // Content ended with a newline
+}
+
+// MarkdownTable is a pipe table: a header row, a separator row and the rows
+// under it. Cells are unescaped (\| becomes |) and trimmed.
+type MarkdownTable struct {
+	Section string // Text of the closest heading above the table, without #s
+	Header  []string
+	Rows    [][]string
+
+	separator string // Separator row as written, kept when the header is unchanged
+	start     int    // Index of the header line in the document
+	end       int    // Index after the last row
+	dirty     bool
+}
+
+// ParseMarkdown parses a markdown document's tables
+func ParseMarkdown(content string) *MarkdownDoc {
+	doc := &MarkdownDoc{newline: strings.HasSuffix(content, "\n")}
+	doc.lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	section := ""
+	inFence := false
+	for i := 0; i < len(doc.lines); i++ {
+		line := strings.TrimSpace(doc.lines[i])
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			section = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+		if !isTableRow(line) || i+1 >= len(doc.lines) || !isTableSeparator(doc.lines[i+1]) {
+			continue
+		}
+
+		table := &MarkdownTable{
+			Section:   section,
+			Header:    splitTableRow(line),
+			separator: doc.lines[i+1],
+			start:     i,
+		}
+		j := i + 2
+		for ; j < len(doc.lines) && isTableRow(strings.TrimSpace(doc.lines[j])); j++ {
+			table.Rows = append(table.Rows, splitTableRow(strings.TrimSpace(doc.lines[j])))
+		}
+		table.end = j
+		doc.tables = append(doc.tables, table)
+		i = j - 1
+	}
+	return doc
+}
+
+// ReadMarkdownFile parses a markdown file
+func ReadMarkdownFile(path string) (*MarkdownDoc, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMarkdown(string(content)), nil
+}
+
+// UpdateMarkdownFile parses a markdown file, applies fn and writes the result
+// back atomically. Nothing is written if fn fails.
+func UpdateMarkdownFile(path string, fn func(*MarkdownDoc) error) error {
+	doc, err := ReadMarkdownFile(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(doc); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, []byte(doc.String()), 0644)
+}
+
+// Tables returns the document's tables in order
+func (d *MarkdownDoc) Tables() []*MarkdownTable {
+	return d.tables
+}
+
+// Table returns the first table under a heading starting with section
+// (e.g. "Active Goals"), or nil
+func (d *MarkdownDoc) Table(section string) *MarkdownTable {
+	for _, t := range d.tables {
+		if strings.HasPrefix(t.Section, section) {
+			return t
+		}
+	}
+	return nil
+}
+
+// AddSection appends a "## <title>" section holding an empty table with
+// header, and returns the table
+func (d *MarkdownDoc) AddSection(title string, header ...string) *MarkdownTable {
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) == "" {
+		d.lines = d.lines[:len(d.lines)-1]
+	}
+	d.lines = append(d.lines, "", "## "+title, "")
+	table := &MarkdownTable{
+		Section: title,
+		Header:  header,
+		start:   len(d.lines),
+		end:     len(d.lines),
+		dirty:   true,
+	}
+	d.tables = append(d.tables, table)
+	d.newline = true
+	return table
+}
+
+// String renders the document, re-serializing the tables that changed
+func (d *MarkdownDoc) String() string {
+	var out []string
+	next := 0
+	for _, t := range d.tables {
+		out = append(out, d.lines[next:t.start]...)
+		if t.dirty {
+			out = append(out, t.render()...)
+		} else {
+			out = append(out, d.lines[t.start:t.end]...)
+		}
+		next = t.end
+	}
+	out = append(out, d.lines[next:]...)
+	text := strings.Join(out, "\n")
+	if d.newline {
+		text += "\n"
+	}
+	return text
+}
+
+// Column returns the index of a column by name, or -1. Names are compared
+// ignoring case, spaces and punctuation, so "Project(s)" matches "Projects".
+func (t *MarkdownTable) Column(name string) int {
+	key := columnKey(name)
+	for i, h := range t.Header {
+		if columnKey(h) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Cell returns a row's value in the named column, or "" if the table has no
+// such column or the row is short
+func (t *MarkdownTable) Cell(row []string, column string) string {
+	i := t.Column(column)
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// Records returns the table's non-empty rows as column name -> value maps,
+// keyed by the header as written
+func (t *MarkdownTable) Records() []map[string]string {
+	var records []map[string]string
+	for _, row := range t.Rows {
+		if isBlankRow(row) {
+			continue
+		}
+		record := make(map[string]string, len(t.Header))
+		for i, h := range t.Header {
+			if i < len(row) {
+				record[h] = row[i]
+			} else {
+				record[h] = ""
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// AddRow appends a row from column name -> value. Columns the table doesn't
+// have are an error, so a differently shaped table is never filled with
+// misplaced cells; columns missing from values are left empty. Placeholder
+// rows (all cells empty) are dropped.
+func (t *MarkdownTable) AddRow(values map[string]string) error {
+	row := make([]string, len(t.Header))
+	for name, value := range values {
+		i := t.Column(name)
+		if i < 0 {
+			return fmt.Errorf("table %q has no %q column", t.Section, name)
+		}
+		row[i] = value
+	}
+	t.RemoveRows(isBlankRow)
+	t.Rows = append(t.Rows, row)
+	t.dirty = true
+	return nil
+}
+
+// RemoveRows removes the rows match returns true for and returns how many
+func (t *MarkdownTable) RemoveRows(match func(row []string) bool) int {
+	kept := t.Rows[:0]
+	removed := 0
+	for _, row := range t.Rows {
+		if match(row) {
+			removed++
+			continue
+		}
+		kept = append(kept, row)
+	}
+	t.Rows = kept
+	if removed > 0 {
+		t.dirty = true
+	}
+	return removed
+}
+
+// render serializes the table with one space of padding around each cell,
+// keeping the separator row as written unless the columns changed
+func (t *MarkdownTable) render() []string {
+	lines := []string{renderTableRow(t.Header)}
+	if t.separator != "" && len(splitTableRow(strings.TrimSpace(t.separator))) == len(t.Header) {
+		lines = append(lines, t.separator)
+	} else {
+		cells := make([]string, len(t.Header))
+		for i, h := range t.Header {
+			cells[i] = strings.Repeat("-", max(len(h)+2, 3))
+		}
+		lines = append(lines, "|"+strings.Join(cells, "|")+"|")
+	}
+	for _, row := range t.Rows {
+		cells := make([]string, len(t.Header))
+		copy(cells, row)
+		lines = append(lines, renderTableRow(cells))
+	}
+	return lines
+}
+
+// renderTableRow renders cells as "| a | b |"; empty cells are "| |"
+func renderTableRow(cells []string) string {
+	var b strings.Builder
+	b.WriteString("|")
+	for _, c := range cells {
+		if c = escapeTableCell(c); c != "" {
+			b.WriteString(" " + c)
+		}
+		b.WriteString(" |")
+	}
+	return b.String()
+}
+
+// escapeTableCell keeps a value on one line and inside its cell
+func escapeTableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func isTableRow(line string) bool {
+	return strings.HasPrefix(line, "|")
+}
+
+// isTableSeparator matches |---|:--:|---| style rows
+func isTableSeparator(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "|") || !strings.Contains(line, "-") {
+		return false
+	}
+	for _, r := range line {
+		if r != '|' && r != '-' && r != ':' && r != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitTableRow splits a table row into trimmed cells, honoring \| escapes
+// and pipes inside `code spans`
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	inCode := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			inCode = !inCode
+			cell.WriteByte(c)
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func isBlankRow(row []string) bool {
+	for _, c := range row {
+		if c != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// columnKey normalizes a column name for matching
+func columnKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package goals
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the .golden files under testdata")
+
+// checkGolden compares got with testdata/markdown/<name>.golden
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "markdown", name+".golden")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s (run with -update to create it): %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("testdata", "markdown", name))
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	return string(content)
+}
+
+func TestMarkdownDoc_RoundTrip(t *testing.T) {
+	for _, name := range []string{"standard.md", "reordered.md", "legacy.md", "active_only.md", "index.md"} {
+		content := readTestdata(t, name)
+		if got := ParseMarkdown(content).String(); got != content {
+			t.Errorf("%s changed without edits:\n%s", name, got)
+		}
+	}
+}
+
+func TestMarkdownDoc_RegistryGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		golden string
+		edit   func(*MarkdownDoc) error
+	}{
+		{
+			name:   "complete a goal",
+			input:  "standard.md",
+			golden: "standard_complete",
+			edit: func(d *MarkdownDoc) error {
+				return d.SetRegistryGoal(Goal{ID: "abc1234", Title: "Fix OAuth login", Projects: []string{"my-api"}, Status: "completed", CompletedAt: "2026-02-01"})
+			},
+		},
+		{
+			name:   "ice a goal with a pipe in the reason",
+			input:  "standard.md",
+			golden: "standard_ice",
+			edit: func(d *MarkdownDoc) error {
+				return d.SetRegistryGoal(Goal{ID: "def5678", Title: "Add dark mode", Projects: []string{"web-ui", "my-api"}, Status: "iced", Reason: "Blocked | design review"})
+			},
+		},
+		{
+			name:   "reordered and extra columns",
+			input:  "reordered.md",
+			golden: "reordered_complete",
+			edit: func(d *MarkdownDoc) error {
+				return d.SetRegistryGoal(Goal{ID: "def5678", Title: "Add dark mode", Projects: []string{"web-ui"}, Status: "completed", CompletedAt: "2026-02-01"})
+			},
+		},
+		{
+			name:   "legacy IDs and placeholder rows",
+			input:  "legacy.md",
+			golden: "legacy_ice",
+			edit: func(d *MarkdownDoc) error {
+				return d.SetRegistryGoal(Goal{ID: "14", Title: "Split the monolith", Projects: []string{"core"}, Status: "iced", Reason: "Paused"})
+			},
+		},
+		{
+			name:   "missing sections are added",
+			input:  "active_only.md",
+			golden: "active_only_complete",
+			edit: func(d *MarkdownDoc) error {
+				return d.SetRegistryGoal(Goal{ID: "abc1234", Title: "Fix OAuth login", Projects: []string{"my-api"}, Status: "completed", CompletedAt: "2026-02-01"})
+			},
+		},
+		{
+			name:   "remove a goal",
+			input:  "legacy.md",
+			golden: "legacy_remove",
+			edit: func(d *MarkdownDoc) error {
+				if n := d.RemoveRegistryGoal("15"); n != 1 {
+					t.Errorf("removed %d rows, want 1", n)
+				}
+				return nil
+			},
+		},
+		{
+			name:   "add and remove index projects",
+			input:  "index.md",
+			golden: "index_edit",
+			edit: func(d *MarkdownDoc) error {
+				d.RemoveIndexProject("web-ui")
+				if err := d.AddIndexProject("my-api"); err != nil {
+					return err
+				}
+				return d.AddIndexProject("billing")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseMarkdown(readTestdata(t, tt.input))
+			if err := tt.edit(doc); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			checkGolden(t, tt.golden, doc.String())
+		})
+	}
+}
+
+func TestParseRegistryMarkdown_Formats(t *testing.T) {
+	reordered := ParseRegistryMarkdown(readTestdata(t, "reordered.md"))
+	if len(reordered) != 3 {
+		t.Fatalf("expected 3 goals, got %d: %+v", len(reordered), reordered)
+	}
+	want := Goal{ID: "abc1234", Title: "Fix OAuth login", Projects: []string{"my-api"}, Status: "active", Phase: "2/4"}
+	if !reflect.DeepEqual(reordered[0], want) {
+		t.Errorf("got %+v, want %+v", reordered[0], want)
+	}
+	if reordered[2].CompletedAt != "2026-01-05" || reordered[2].Title != "Bootstrap CI" {
+		t.Errorf("completed goal parsed wrong: %+v", reordered[2])
+	}
+
+	legacy := ParseRegistryMarkdown(readTestdata(t, "legacy.md"))
+	if len(legacy) != 2 {
+		t.Fatalf("expected 2 goals (placeholders and fenced rows skipped), got %d: %+v", len(legacy), legacy)
+	}
+	if legacy[0].ID != "14" || legacy[1].Title != "Title with a | pipe" {
+		t.Errorf("legacy goals parsed wrong: %+v", legacy)
+	}
+}
+
+func TestMarkdownTable_AddRowUnknownColumn(t *testing.T) {
+	doc := ParseMarkdown(readTestdata(t, "reordered.md"))
+	table := doc.Table("Completed Goals")
+	if err := table.AddRow(map[string]string{"ID": "x", "Reason": "nope"}); err == nil {
+		t.Error("expected an error for a column the table doesn't have")
+	}
+	if got := doc.String(); got != readTestdata(t, "reordered.md") {
+		t.Errorf("failed AddRow changed the document:\n%s", got)
+	}
+}
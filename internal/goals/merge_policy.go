@@ -0,0 +1,101 @@
+package goals
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Merge strategies used when completing a goal
+const (
+	MergeStrategyMerge  = "merge"   // Merge commit (default)
+	MergeStrategySquash = "squash"  // Squash the goal branch into one commit
+	MergeStrategyRebase = "rebase"  // Rebase the goal branch, then fast-forward
+	MergeStrategyFFOnly = "ff-only" // Fast-forward only, fail otherwise
+)
+
+// MergeStrategies returns all valid merge strategies
+func MergeStrategies() []string {
+	return []string{MergeStrategyMerge, MergeStrategySquash, MergeStrategyRebase, MergeStrategyFFOnly}
+}
+
+// IsValidMergeStrategy checks if s is a known merge strategy
+func IsValidMergeStrategy(s string) bool {
+	for _, valid := range MergeStrategies() {
+		if s == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// MergePolicy holds a project's branch protection and merge settings,
+// stored in projects/<name>.md as:
+//
+//	**Protected Branches**: `main`, `release/*`
+//	**Require MR**: `true`
+//	**Merge Strategy**: `squash`
+//	**Require Review**: `true`
+type MergePolicy struct {
+	ProtectedBranches []string `json:"protected_branches,omitempty"` // Branch patterns (path.Match syntax)
+	RequireMR         bool     `json:"require_mr,omitempty"`         // Every goal must go through an MR
+	MergeStrategy     string   `json:"merge_strategy,omitempty"`     // Default strategy for direct merges
+	RequireReview     bool     `json:"require_review,omitempty"`     // Goals must be approved before completing
+}
+
+// Validate checks branch patterns and the merge strategy
+func (p MergePolicy) Validate() error {
+	for _, pattern := range p.ProtectedBranches {
+		if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("invalid protected branch pattern: %q", pattern)
+		}
+	}
+	if p.MergeStrategy != "" && !IsValidMergeStrategy(p.MergeStrategy) {
+		return fmt.Errorf("invalid merge strategy %q (valid: %s)", p.MergeStrategy, strings.Join(MergeStrategies(), ", "))
+	}
+	return nil
+}
+
+// IsProtected returns true if branch matches one of the protected patterns
+func (p MergePolicy) IsProtected(branch string) bool {
+	for _, pattern := range p.ProtectedBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresMR returns true if merging into target must go through an MR
+func (p MergePolicy) RequiresMR(target string) bool {
+	return p.RequireMR || p.IsProtected(target)
+}
+
+// Strategy returns the merge strategy, defaulting to a merge commit
+func (p MergePolicy) Strategy() string {
+	if p.MergeStrategy == "" {
+		return MergeStrategyMerge
+	}
+	return p.MergeStrategy
+}
+
+// parseConfigList parses a config list like "`main`, `release/*`"
+func parseConfigList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.Trim(strings.TrimSpace(part), "`")
+		if part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// parseBool parses the yes/no values used in project configs
+func parseBool(s string) bool {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(s), "`")) {
+	case "true", "yes", "y", "1", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProjectMergePolicy(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	config := "# Project: my-api\n\n**Base Branch**: `main`\n\n## Merge Policy\n\n" +
+		"**Protected Branches**: `main`, `release/*`\n**Require MR**: `false`\n**Merge Strategy**: `squash`\n**Require Review**: `true`\n"
+	os.WriteFile(filepath.Join(dir, "projects", "my-api.md"), []byte(config), 0644)
+
+	project, err := ParseProject(dir, "my-api")
+	if err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+	policy := project.MergePolicy
+	if len(policy.ProtectedBranches) != 2 || policy.RequireMR || policy.Strategy() != MergeStrategySquash || !policy.RequireReview {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+	if err := policy.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	for branch, want := range map[string]bool{
+		"main":          true,
+		"release/1.2":   true,
+		"develop":       false,
+		"release/1/hot": false,
+	} {
+		if got := policy.RequiresMR(branch); got != want {
+			t.Errorf("RequiresMR(%q) = %v, want %v", branch, got, want)
+		}
+	}
+
+	if !(MergePolicy{RequireMR: true}).RequiresMR("develop") {
+		t.Error("RequireMR should apply to every branch")
+	}
+	if (MergePolicy{}).Strategy() != MergeStrategyMerge {
+		t.Error("expected merge commits by default")
+	}
+	if err := (MergePolicy{MergeStrategy: "octopus"}).Validate(); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+	if err := (MergePolicy{ProtectedBranches: []string{"release/["}}).Validate(); err == nil {
+		t.Error("expected error for bad pattern")
+	}
+}
@@ -0,0 +1,65 @@
+package goals
+
+import "strings"
+
+// Project config sections holding message templates
+const (
+	mergeMessageSection  = "## Merge Message Template"
+	mrDescriptionSection = "## MR Description Template"
+)
+
+// MessageTemplates holds a project's Go text/template templates for the
+// merge commit message and MR description of its goals, stored in
+// projects/<name>.md as fenced code blocks under their own sections:
+//
+//	## Merge Message Template
+//
+//	```
+//	Merge goal {{.GoalID}}: {{.Title}}
+//	```
+//
+// Empty templates keep the defaults.
+type MessageTemplates struct {
+	MergeMessageTemplate  string `json:"merge_message_template,omitempty"`
+	MRDescriptionTemplate string `json:"mr_description_template,omitempty"`
+}
+
+// templateSection collects the fenced code block of a template section
+type templateSection struct {
+	target  *string
+	inFence bool
+	done    bool
+	lines   []string
+}
+
+// startTemplateSection returns the collector for a template section heading,
+// or nil if line isn't one
+func (t *MessageTemplates) startTemplateSection(line string) *templateSection {
+	heading := strings.TrimSpace(line)
+	switch {
+	case strings.EqualFold(heading, mergeMessageSection):
+		return &templateSection{target: &t.MergeMessageTemplate}
+	case strings.EqualFold(heading, mrDescriptionSection):
+		return &templateSection{target: &t.MRDescriptionTemplate}
+	}
+	return nil
+}
+
+// add takes the next line of the section; text outside the first fenced
+// block is ignored
+func (s *templateSection) add(line string) {
+	if s.done {
+		return
+	}
+	if strings.HasPrefix(strings.TrimSpace(line), "```") {
+		if s.inFence {
+			s.done = true
+			*s.target = strings.Join(s.lines, "\n")
+		}
+		s.inFence = !s.inFence
+		return
+	}
+	if s.inFence {
+		s.lines = append(s.lines, line)
+	}
+}
@@ -0,0 +1,32 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProjectMessageTemplates(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	config := "# Project: my-api\n\n**Base Branch**: `main`\n\n" +
+		"## Merge Message Template\n\nUsed when completing goals.\n\n```\n{{.Title}} ({{.GoalID}})\n\nBase Branch: {{.BaseBranch}}\n```\n\n" +
+		"## MR Description Template\n\n```text\n## Summary\n{{.Overview}}\n```\n\n" +
+		"## Notes\n\n**Upstream**: `https://github.com/acme/api.git`\n"
+	os.WriteFile(filepath.Join(dir, "projects", "my-api.md"), []byte(config), 0644)
+
+	project, err := ParseProject(dir, "my-api")
+	if err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+	if project.MergeMessageTemplate != "{{.Title}} ({{.GoalID}})\n\nBase Branch: {{.BaseBranch}}" {
+		t.Errorf("unexpected merge template %q", project.MergeMessageTemplate)
+	}
+	if project.MRDescriptionTemplate != "## Summary\n{{.Overview}}" {
+		t.Errorf("unexpected MR template %q", project.MRDescriptionTemplate)
+	}
+	// Template lines aren't config fields, and fields after the templates are still read
+	if project.BaseBranch != "main" || project.Upstream != "https://github.com/acme/api.git" {
+		t.Errorf("unexpected config: base %q, upstream %q", project.BaseBranch, project.Upstream)
+	}
+}
@@ -0,0 +1,39 @@
+package goals
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CleanPathInRepo validates a monorepo subdirectory and returns it in
+// canonical form ("" and "." both mean the whole repository). The path must
+// be relative and stay inside the repository.
+func CleanPathInRepo(p string) (string, error) {
+	p = strings.TrimSpace(filepath.ToSlash(p))
+	if p == "" {
+		return "", nil
+	}
+	if path.IsAbs(p) {
+		return "", fmt.Errorf("path in repo must be relative: %q", p)
+	}
+	p = path.Clean(p)
+	if p == "." {
+		return "", nil
+	}
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("path in repo must stay inside the repository: %q", p)
+	}
+	return p, nil
+}
+
+// ScopeDir returns the directory goals of this project work in: the
+// project's subdirectory of worktree for monorepo projects, worktree itself
+// otherwise. Worktrees always check out the whole repository.
+func (p *Project) ScopeDir(worktree string) string {
+	if p.PathInRepo == "" {
+		return worktree
+	}
+	return filepath.Join(worktree, filepath.FromSlash(p.PathInRepo))
+}
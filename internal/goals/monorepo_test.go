@@ -0,0 +1,57 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanPathInRepo(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{".", "", false},
+		{"services/api", "services/api", false},
+		{"./services/api/", "services/api", false},
+		{"services/../web", "web", false},
+		{"/srv/api", "", true},
+		{"..", "", true},
+		{"../other", "", true},
+		{"services/../../other", "", true},
+	}
+	for _, tt := range tests {
+		got, err := CleanPathInRepo(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("CleanPathInRepo(%q) = %q, %v; want %q, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseProjectPathInRepo(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(dir, "projects", "api.md"), []byte("# Project: api\n\n**Base Branch**: `main`\n**Path In Repo**: `./services/api/`\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "projects", "bad.md"), []byte("# Project: bad\n\n**Path In Repo**: `../escape`\n"), 0644)
+
+	p, err := ParseProject(dir, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PathInRepo != "services/api" {
+		t.Errorf("expected services/api, got %q", p.PathInRepo)
+	}
+	if got := p.ScopeDir("/wt"); got != filepath.Join("/wt", "services", "api") {
+		t.Errorf("unexpected scope dir %s", got)
+	}
+
+	p, err = ParseProject(dir, "bad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PathInRepo != "" || p.ScopeDir("/wt") != "/wt" {
+		t.Errorf("escaping path should be ignored, got %q", p.PathInRepo)
+	}
+}
@@ -2,34 +2,36 @@ package goals
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
-)
 
-// Goal represents a goal from the registry
-type Goal struct {
-	ID       string   `json:"id"` // Can be numeric ("10"), hash ("4fd584d"), or hierarchical ("4fd584d.1")
-	Title    string   `json:"title"`
-	Projects []string `json:"projects"`
-	Status   string   `json:"status"` // "active", "iced", "completed"
-	Phase    string   `json:"phase"`  // e.g., "1/4" or "?"
-	Reason   string   `json:"reason,omitempty"` // For iced goals
-	ParentID string   `json:"parent_id,omitempty"` // Parent goal ID for hierarchical goals
-	Children []string `json:"children,omitempty"` // Child goal IDs (populated dynamically)
-}
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
 
 // Parser handles parsing of goal registry and detail files
 type Parser struct {
-	dir string
+	dir    string
+	layout *layout.Layout
 }
 
 // NewParser creates a parser for the given vega-missile directory
 func NewParser(dir string) *Parser {
-	return &Parser{dir: dir}
+	return NewParserWithLayout(layout.New(dir))
+}
+
+// NewParserWithLayout creates a parser resolving paths through l
+func NewParserWithLayout(l *layout.Layout) *Parser {
+	return &Parser{dir: l.Root(), layout: l}
+}
+
+// Layout returns the layout of the vega-missile directory
+func (p *Parser) Layout() *layout.Layout {
+	return p.layout
 }
 
 // Dir returns the vega-missile directory path
@@ -37,29 +39,26 @@ func (p *Parser) Dir() string {
 	return p.dir
 }
 
-// ParseRegistry reads and parses the registry.jsonl file
+// ParseRegistry reads and parses the registry.jsonl file. Vega directories
+// that were never migrated have only goals/REGISTRY.md, which is read instead.
 func (p *Parser) ParseRegistry() ([]Goal, error) {
+	if _, err := os.Stat(p.layout.Registry()); os.IsNotExist(err) {
+		content, err := os.ReadFile(p.layout.RegistryMarkdown())
+		if err == nil {
+			return ParseRegistryMarkdown(string(content)), nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read REGISTRY.md: %w", err)
+		}
+	}
+
 	registry := NewRegistry(p.dir)
 	entries, err := registry.Load()
 	if err != nil {
 		return nil, err
 	}
 
-	goals := make([]Goal, 0, len(entries))
-	for _, entry := range entries {
-		goal := Goal{
-			ID:       entry.ID,
-			Title:    entry.Title,
-			Projects: entry.Projects,
-			Status:   entry.Status,
-			Phase:    entry.Phase,
-			ParentID: entry.ParentID,
-			Reason:   entry.Reason,
-		}
-		goals = append(goals, goal)
-	}
-
-	return goals, nil
+	return entries, nil
 }
 
 // WorktreeInfo contains worktree metadata stored in goal file
@@ -79,6 +78,7 @@ type GoalDetail struct {
 	Acceptance []string      `json:"acceptance,omitempty"`
 	Notes      []string      `json:"notes,omitempty"`
 	Worktree   *WorktreeInfo `json:"worktree,omitempty"`
+	Archived   bool          `json:"archived,omitempty"` // Moved to goals/history/archive by cleanup
 }
 
 // PhaseDetail describes a phase within a goal
@@ -95,33 +95,16 @@ type Task struct {
 	Completed   bool   `json:"completed"`
 }
 
-// findGoalFile locates the goal markdown file, checking both flat and folder structures
-// Returns (path, status) where status is "active", "iced", or "completed"
+// findGoalFile locates the goal markdown file in either layout style
+// Returns (path, status) where status is "draft", "active", "iced", or "completed"
 func (p *Parser) findGoalFile(id string) (string, string) {
-	dirs := []struct {
-		name   string
-		status string
-	}{
-		{"active", "active"},
-		{"iced", "iced"},
-		{"history", "completed"},
-	}
-
-	for _, dir := range dirs {
-		// Flat structure: goals/<dir>/<id>.md
-		flatPath := filepath.Join(p.dir, "goals", dir.name, id+".md")
-		if _, err := os.Stat(flatPath); err == nil {
-			return flatPath, dir.status
-		}
-
-		// Folder structure: goals/<dir>/<id>/<id>.md
-		folderPath := filepath.Join(p.dir, "goals", dir.name, id, id+".md")
-		if _, err := os.Stat(folderPath); err == nil {
-			return folderPath, dir.status
-		}
-	}
+	return p.layout.FindGoalFile(id)
+}
 
-	return "", ""
+// GoalFile returns the path of a goal's markdown file and its status
+// ("draft", "active", "iced" or "completed"), or empty strings if there is none
+func (p *Parser) GoalFile(id string) (string, string) {
+	return p.findGoalFile(id)
 }
 
 // ParseGoalDetail reads and parses a specific goal file
@@ -147,7 +130,7 @@ func (p *Parser) ParseGoalDetail(id string) (*GoalDetail, error) {
 	phaseNum := 0
 
 	// Regex patterns
-	titleRe := regexp.MustCompile(`^# Goal #?([0-9a-f]+): (.+)$`)
+	titleRe := regexp.MustCompile(`^# Goal #?([0-9a-f]+(?:\.[0-9]+)*): (.+)$`)
 	phaseRe := regexp.MustCompile(`^### Phase (\d+): (.+)$`)
 	taskRe := regexp.MustCompile(`^- \[([ x])\] (.+)$`)
 
@@ -307,24 +290,68 @@ func (p *Parser) ParseGoalDetail(id string) (*GoalDetail, error) {
 	detail.Acceptance = acceptanceLines
 	detail.Notes = noteLines
 
+	if entry, err := NewRegistry(p.dir).Get(id); err == nil {
+		detail.Goal = entry.withFile(detail.Goal)
+	}
+	if goalStatus == "completed" {
+		detail.Archived = strings.HasPrefix(goalPath, p.layout.GoalDir(layout.ArchiveDir)+string(filepath.Separator))
+	}
+
 	return detail, scanner.Err()
 }
 
+// Project types: git projects get a worktree and branch per goal, plain
+// projects (documentation or research folders) are worked on in place
+const (
+	ProjectTypeGit   = "git"
+	ProjectTypePlain = "plain"
+)
+
 // Project represents a managed project from projects/<name>.md
 type Project struct {
 	Name            string `json:"name"`
+	Type            string `json:"type"`          // ProjectTypeGit or ProjectTypePlain
+	VCS             string `json:"vcs,omitempty"` // Version control backend for git projects ("" is git, "jj" for colocated Jujutsu)
 	Workspace       string `json:"workspace"`
 	BaseBranch      string `json:"base_branch"`
-	Upstream        string `json:"upstream"`         // Git remote URL or local path
-	GitRemote       string `json:"git_remote"`       // Resolved git remote URL (from upstream or repo)
-	WorkspaceStatus string `json:"workspace_status"` // "ready", "missing", "error"
+	Upstream        string `json:"upstream"`               // Git remote URL or local path
+	GitRemote       string `json:"git_remote"`             // Resolved git remote URL (from upstream or repo)
+	PathInRepo      string `json:"path_in_repo,omitempty"` // Subdirectory the project lives in (monorepos)
+	WorkspaceStatus string `json:"workspace_status"`       // "ready", "missing", "error"
 	WorkspaceError  string `json:"workspace_error,omitempty"`
+
+	// Container isolation settings (see hub.ContainerOptions)
+	ContainerImage  string `json:"container_image,omitempty"`  // Image for containerized executors
+	ContainerCPUs   string `json:"container_cpus,omitempty"`   // CPU limit, e.g. "2" or "1.5"
+	ContainerMemory string `json:"container_memory,omitempty"` // Memory limit, e.g. "4g"
+
+	// Branch protection and merge settings
+	MergePolicy
+
+	// Signed commit and commit message rules for goal branches
+	CommitPolicy
+
+	// Shallow, partial and sparse clone settings for large repositories
+	CloneOptions
+
+	// Prewarmed goal worktrees to keep ready (0 disables pooling)
+	WorktreePool int `json:"worktree_pool,omitempty"`
+
+	// Commits the base branch may be behind origin before it counts as stale
+	// (0 uses the default)
+	StaleBaseThreshold int `json:"stale_base_threshold,omitempty"`
+
+	// Don't fetch the base branch from origin before creating or merging goals
+	NoBaseFetch bool `json:"no_base_fetch,omitempty"`
+
+	// Merge commit message and MR description templates
+	MessageTemplates
 }
 
 // ParseProject reads and parses a project configuration file
 // Returns project details including git remote for credential validation
 func (p *Parser) ParseProject(name string) (*Project, error) {
-	projectPath := filepath.Join(p.dir, "projects", name+".md")
+	projectPath := p.layout.ProjectConfig(name)
 	file, err := os.Open(projectPath)
 	if err != nil {
 		return nil, err
@@ -341,10 +368,54 @@ func (p *Parser) ParseProject(name string) (*Project, error) {
 	baseBranchRe := regexp.MustCompile(`(?i)(?:\*\*)?Base Branch(?:\*\*)?:?\s*` + "`?" + `([a-zA-Z0-9_/-]+)` + "`?")
 	// Matches: **Upstream**: `https://github.com/...` or Upstream: /local/path
 	upstreamRe := regexp.MustCompile(`(?:\*\*)?Upstream(?:\*\*)?:?\s*` + "`?" + `([^` + "`" + `\s]+)` + "`?")
-
+	// Matches: **Path In Repo**: `services/api`
+	pathInRepoRe := regexp.MustCompile(`(?i)(?:\*\*)?Path In Repo(?:\*\*)?:?\s*` + "`?" + `([^` + "`" + `\s]+)` + "`?")
+	// Matches: **Container Image**: `node:20`, **Container CPUs**: `2`, **Container Memory**: `4g`
+	containerImageRe := regexp.MustCompile(`(?i)(?:\*\*)?Container Image(?:\*\*)?:?\s*` + "`?" + `([^` + "`" + `\s]+)` + "`?")
+	containerCPUsRe := regexp.MustCompile(`(?i)(?:\*\*)?Container CPUs(?:\*\*)?:?\s*` + "`?" + `([0-9.]+)` + "`?")
+	containerMemoryRe := regexp.MustCompile(`(?i)(?:\*\*)?Container Memory(?:\*\*)?:?\s*` + "`?" + `([0-9]+[bkmgBKMG]?)` + "`?")
+	// Matches: **Protected Branches**: `main`, `release/*`, **Require MR**: `true`, **Merge Strategy**: `squash`,
+	// **Require Review**: `true`
+	protectedBranchesRe := regexp.MustCompile(`(?i)(?:\*\*)?Protected Branches(?:\*\*)?:\s*(.+)$`)
+	requireMRRe := regexp.MustCompile(`(?i)(?:\*\*)?Require MR(?:\*\*)?:\s*(.+)$`)
+	requireReviewRe := regexp.MustCompile(`(?i)(?:\*\*)?Require Review(?:\*\*)?:\s*(.+)$`)
+	mergeStrategyRe := regexp.MustCompile(`(?i)(?:\*\*)?Merge Strategy(?:\*\*)?:\s*` + "`?" + `([a-z-]+)` + "`?")
+	// Matches: **Require Signed Commits**: `true`, **Commit Convention**: `conventional`,
+	// **Commit Types**: `feat`, `fix`, **Commit Check**: `session`
+	requireSignedRe := regexp.MustCompile(`(?i)(?:\*\*)?Require Signed Commits(?:\*\*)?:\s*(.+)$`)
+	commitConventionRe := regexp.MustCompile(`(?i)(?:\*\*)?Commit Convention(?:\*\*)?:\s*` + "`?" + `([a-z-]+)` + "`?")
+	commitTypesRe := regexp.MustCompile(`(?i)(?:\*\*)?Commit Types(?:\*\*)?:\s*(.+)$`)
+	commitCheckRe := regexp.MustCompile(`(?i)(?:\*\*)?Commit Check(?:\*\*)?:\s*` + "`?" + `([a-z-]+)` + "`?")
+	// Matches: **Clone Depth**: `1`, **Clone Filter**: `blob:none`, **Sparse Paths**: `services/api`, `libs/common`
+	cloneDepthRe := regexp.MustCompile(`(?i)(?:\*\*)?Clone Depth(?:\*\*)?:\s*` + "`?" + `([0-9]+)` + "`?")
+	cloneFilterRe := regexp.MustCompile(`(?i)(?:\*\*)?Clone Filter(?:\*\*)?:\s*` + "`?" + `([^` + "`" + `\s]+)` + "`?")
+	sparsePathsRe := regexp.MustCompile(`(?i)(?:\*\*)?Sparse Paths(?:\*\*)?:\s*(.+)$`)
+	// Matches: **Worktree Pool**: `2`
+	worktreePoolRe := regexp.MustCompile(`(?i)(?:\*\*)?Worktree Pool(?:\*\*)?:\s*` + "`?" + `([0-9]+)` + "`?")
+	// Matches: **Stale Base Threshold**: `50`
+	staleBaseRe := regexp.MustCompile(`(?i)(?:\*\*)?Stale Base Threshold(?:\*\*)?:\s*` + "`?" + `([0-9]+)` + "`?")
+	// Matches: **Type**: `plain`
+	typeRe := regexp.MustCompile(`(?i)^[-*\s]*(?:\*\*)?(?:Project )?Type(?:\*\*)?:\s*` + "`?" + `([a-z]+)` + "`?")
+	// Matches: **VCS**: `jj`
+	vcsRe := regexp.MustCompile(`(?i)(?:\*\*)?VCS(?:\*\*)?:\s*` + "`?" + `([a-z]+)` + "`?")
+	// Matches: **Fetch Base**: `false`
+	fetchBaseRe := regexp.MustCompile(`(?i)(?:\*\*)?Fetch Base(?:\*\*)?:\s*(.+)$`)
+
+	// Template sections run until the next heading; their lines aren't config fields
+	var section *templateSection
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		if section != nil && (section.inFence || !strings.HasPrefix(line, "#")) {
+			section.add(line)
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if section = project.startTemplateSection(line); section != nil {
+				continue
+			}
+		}
+
 		if matches := workspaceRe.FindStringSubmatch(line); matches != nil {
 			project.Workspace = strings.TrimSpace(matches[1])
 		}
@@ -354,6 +425,69 @@ func (p *Parser) ParseProject(name string) (*Project, error) {
 		if matches := upstreamRe.FindStringSubmatch(line); matches != nil {
 			project.Upstream = strings.TrimSpace(matches[1])
 		}
+		if matches := pathInRepoRe.FindStringSubmatch(line); matches != nil {
+			// Paths escaping the repository are ignored: the project covers the whole repo
+			if cleaned, err := CleanPathInRepo(matches[1]); err == nil {
+				project.PathInRepo = cleaned
+			}
+		}
+		if matches := containerImageRe.FindStringSubmatch(line); matches != nil {
+			project.ContainerImage = strings.TrimSpace(matches[1])
+		}
+		if matches := containerCPUsRe.FindStringSubmatch(line); matches != nil {
+			project.ContainerCPUs = matches[1]
+		}
+		if matches := containerMemoryRe.FindStringSubmatch(line); matches != nil {
+			project.ContainerMemory = matches[1]
+		}
+		if matches := protectedBranchesRe.FindStringSubmatch(line); matches != nil {
+			project.ProtectedBranches = parseConfigList(matches[1])
+		}
+		if matches := requireMRRe.FindStringSubmatch(line); matches != nil {
+			project.RequireMR = parseBool(matches[1])
+		}
+		if matches := requireReviewRe.FindStringSubmatch(line); matches != nil {
+			project.RequireReview = parseBool(matches[1])
+		}
+		if matches := mergeStrategyRe.FindStringSubmatch(line); matches != nil {
+			project.MergeStrategy = strings.ToLower(matches[1])
+		}
+		if matches := requireSignedRe.FindStringSubmatch(line); matches != nil {
+			project.RequireSignedCommits = parseBool(matches[1])
+		}
+		if matches := commitConventionRe.FindStringSubmatch(line); matches != nil {
+			project.CommitConvention = strings.ToLower(matches[1])
+		}
+		if matches := commitTypesRe.FindStringSubmatch(line); matches != nil {
+			project.CommitTypes = parseConfigList(matches[1])
+		}
+		if matches := commitCheckRe.FindStringSubmatch(line); matches != nil {
+			project.CommitCheck = strings.ToLower(matches[1])
+		}
+		if matches := cloneDepthRe.FindStringSubmatch(line); matches != nil {
+			project.CloneDepth, _ = strconv.Atoi(matches[1])
+		}
+		if matches := cloneFilterRe.FindStringSubmatch(line); matches != nil {
+			project.CloneFilter = matches[1]
+		}
+		if matches := sparsePathsRe.FindStringSubmatch(line); matches != nil {
+			project.SparsePaths = parseConfigList(matches[1])
+		}
+		if matches := worktreePoolRe.FindStringSubmatch(line); matches != nil {
+			project.WorktreePool, _ = strconv.Atoi(matches[1])
+		}
+		if matches := staleBaseRe.FindStringSubmatch(line); matches != nil {
+			project.StaleBaseThreshold, _ = strconv.Atoi(matches[1])
+		}
+		if matches := typeRe.FindStringSubmatch(line); matches != nil {
+			project.Type = strings.ToLower(matches[1])
+		}
+		if matches := vcsRe.FindStringSubmatch(line); matches != nil {
+			project.VCS = strings.ToLower(matches[1])
+		}
+		if matches := fetchBaseRe.FindStringSubmatch(line); matches != nil {
+			project.NoBaseFetch = !parseBool(matches[1])
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -365,7 +499,7 @@ func (p *Parser) ParseProject(name string) (*Project, error) {
 	if project.Upstream != "" {
 		if strings.HasPrefix(project.Upstream, "/") || strings.HasPrefix(project.Upstream, "~") {
 			// Local path - try to get remote from the workspace
-			workspacePath := filepath.Join(p.dir, "workspaces", name, "worktree-base")
+			workspacePath := p.layout.WorktreeBase(name)
 			if remote, err := getGitRemote(workspacePath); err == nil {
 				project.GitRemote = remote
 			} else {
@@ -380,21 +514,31 @@ func (p *Parser) ParseProject(name string) (*Project, error) {
 		}
 	} else {
 		// No upstream - try to get remote from workspace
-		workspacePath := filepath.Join(p.dir, "workspaces", name, "worktree-base")
+		workspacePath := p.layout.WorktreeBase(name)
 		if remote, err := getGitRemote(workspacePath); err == nil {
 			project.GitRemote = remote
 		}
 	}
 
+	if project.Type != ProjectTypePlain {
+		project.Type = ProjectTypeGit
+	}
+
 	// Check workspace status
-	project.WorkspaceStatus, project.WorkspaceError = checkWorkspaceStatus(p.dir, name)
+	project.WorkspaceStatus, project.WorkspaceError = checkWorkspaceStatus(p.layout, name, project.IsPlain())
 
 	return project, nil
 }
 
-// checkWorkspaceStatus checks if a project's workspace is properly set up
-func checkWorkspaceStatus(vegaDir, projectName string) (status, errorMsg string) {
-	worktreeBase := filepath.Join(vegaDir, "workspaces", projectName, "worktree-base")
+// IsPlain reports whether the project is a plain folder without git
+func (p *Project) IsPlain() bool {
+	return p.Type == ProjectTypePlain
+}
+
+// checkWorkspaceStatus checks if a project's workspace is properly set up.
+// Plain projects only need the directory.
+func checkWorkspaceStatus(l *layout.Layout, projectName string, plain bool) (status, errorMsg string) {
+	worktreeBase := l.WorktreeBase(projectName)
 
 	// Check if worktree-base directory exists
 	info, err := os.Stat(worktreeBase)
@@ -408,6 +552,10 @@ func checkWorkspaceStatus(vegaDir, projectName string) (status, errorMsg string)
 		return "error", "Workspace path exists but is not a directory"
 	}
 
+	if plain {
+		return "ready", ""
+	}
+
 	// Check if it's a valid git repository
 	gitDir := filepath.Join(worktreeBase, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
@@ -454,7 +602,7 @@ func getGitRemote(repoPath string) (string, error) {
 
 // ListProjects returns all project names from projects/index.md
 func (p *Parser) ListProjects() ([]string, error) {
-	indexPath := filepath.Join(p.dir, "projects", "index.md")
+	indexPath := p.layout.ProjectIndex()
 	file, err := os.Open(indexPath)
 	if err != nil {
 		return nil, err
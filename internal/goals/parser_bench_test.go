@@ -0,0 +1,57 @@
+package goals
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBenchGoals creates n active goals with four phases each
+func writeBenchGoals(b *testing.B, n int) *Parser {
+	b.Helper()
+	dir := b.TempDir()
+	os.MkdirAll(filepath.Join(dir, "goals", "active"), 0755)
+
+	entries := make([]RegistryEntry, 0, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("%07x", 0xb000000+i)
+		var content strings.Builder
+		fmt.Fprintf(&content, "# Goal #%s: Goal %d\n\n## Overview\n\nBenchmark goal.\n\n## Project(s)\n\n- **api**: Main project\n\n## Phases\n\n", id, i)
+		for phase := 1; phase <= 4; phase++ {
+			fmt.Fprintf(&content, "### Phase %d: Step %d\n", phase, phase)
+			for task := 1; task <= 5; task++ {
+				fmt.Fprintf(&content, "- [ ] Task %d.%d\n", phase, task)
+			}
+			content.WriteString("- **Status:** pending\n\n")
+		}
+		content.WriteString("## Acceptance Criteria\n\n- [ ] Done\n\n## Status\n\n**Current Phase**: 1\n")
+		os.WriteFile(filepath.Join(dir, "goals", "active", id+".md"), []byte(content.String()), 0644)
+		entries = append(entries, RegistryEntry{ID: id, Title: fmt.Sprintf("Goal %d", i), Projects: []string{"api"}, Status: "active", Phase: "1/4"})
+	}
+	if err := NewRegistry(dir).Save(entries); err != nil {
+		b.Fatalf("failed to write registry: %v", err)
+	}
+	return NewParser(dir)
+}
+
+func BenchmarkParseRegistry(b *testing.B) {
+	p := writeBenchGoals(b, 500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseRegistry(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseGoalDetail(b *testing.B) {
+	p := writeBenchGoals(b, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseGoalDetail(fmt.Sprintf("%07x", 0xb000000+i%50)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -6,33 +6,35 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // PlanningFilesManager handles storage and retrieval of project planning files
 // Planning files are stored in: goals/active/<goal-id>/project-plans/<project>/
 type PlanningFilesManager struct {
 	baseDir string
+	layout  *layout.Layout
 }
 
 // NewPlanningFilesManager creates a new PlanningFilesManager
 func NewPlanningFilesManager(baseDir string) *PlanningFilesManager {
-	return &PlanningFilesManager{baseDir: baseDir}
+	return &PlanningFilesManager{baseDir: baseDir, layout: layout.New(baseDir)}
 }
 
 // getPlanningDir returns the planning files directory for a goal/project
 func (m *PlanningFilesManager) getPlanningDir(goalID, project string) string {
-	return filepath.Join(m.baseDir, "goals", "active", goalID, "project-plans", project)
+	return filepath.Join(m.getGoalPlanningDir(goalID), project)
 }
 
 // getGoalPlanningDir returns the root planning directory for a goal
 func (m *PlanningFilesManager) getGoalPlanningDir(goalID string) string {
-	return filepath.Join(m.baseDir, "goals", "active", goalID, "project-plans")
+	return filepath.Join(m.layout.GoalFolder(layout.ActiveDir, goalID), "project-plans")
 }
 
 // ensureGoalDir ensures the goal directory exists (creates if needed)
 func (m *PlanningFilesManager) ensureGoalDir(goalID string) error {
-	goalDir := filepath.Join(m.baseDir, "goals", "active", goalID)
-	return os.MkdirAll(goalDir, 0755)
+	return os.MkdirAll(m.layout.GoalFolder(layout.ActiveDir, goalID), 0755)
 }
 
 // SavePlanningFile saves a planning file for a project
@@ -0,0 +1,232 @@
+package goals
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// Markers around the goal lists SyncProjectGoals generates in a project config
+const (
+	projectGoalsBegin = "<!-- vega-hub:goals - generated from goals/registry.jsonl, edits are overwritten -->"
+	projectGoalsEnd   = "<!-- /vega-hub:goals -->"
+)
+
+// projectGoalSections are the hand-maintained sections of older project
+// configs that the generated goal lists replace
+var projectGoalSections = []string{"## Active Goals", "## Iced Goals", "## Completed Goals"}
+
+// ProjectGoal is a registry goal with the path of its goal file
+type ProjectGoal struct {
+	Goal
+	File string `json:"file,omitempty"` // Relative to the vega-missile directory; empty if missing
+}
+
+// ProjectGoals is the registry as one project sees it: the project's goals by
+// status. Active and iced goals are in creation order, completed goals newest
+// first and drafts in backlog order.
+type ProjectGoals struct {
+	Project   string        `json:"project"`
+	Active    []ProjectGoal `json:"active"`
+	Iced      []ProjectGoal `json:"iced"`
+	Completed []ProjectGoal `json:"completed"`
+	Drafts    []ProjectGoal `json:"drafts"`
+}
+
+// LoadProjectGoals builds a project's view of the registry, with each goal's
+// file
+func LoadProjectGoals(vegaDir, project string) (*ProjectGoals, error) {
+	entries, err := NewRegistry(vegaDir).List(func(e RegistryEntry) bool {
+		for _, p := range e.Projects {
+			if p == project {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l := layout.New(vegaDir)
+	files := make(map[string]string)
+	for _, dir := range l.GoalDirs() {
+		for id, path := range l.GoalFiles(dir.Name) {
+			if rel, ok := l.Rel(path); ok {
+				files[id] = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	view := &ProjectGoals{
+		Project:   project,
+		Active:    []ProjectGoal{},
+		Iced:      []ProjectGoal{},
+		Completed: []ProjectGoal{},
+		Drafts:    []ProjectGoal{},
+	}
+	for _, e := range entries {
+		goal := ProjectGoal{Goal: e, File: files[e.ID]}
+		switch e.Status {
+		case "active":
+			view.Active = append(view.Active, goal)
+		case "iced":
+			view.Iced = append(view.Iced, goal)
+		case "completed":
+			view.Completed = append(view.Completed, goal)
+		case StatusDraft:
+			view.Drafts = append(view.Drafts, goal)
+		}
+	}
+	sort.SliceStable(view.Completed, func(i, j int) bool {
+		return view.Completed[i].CompletedAt > view.Completed[j].CompletedAt
+	})
+	sort.SliceStable(view.Drafts, func(i, j int) bool {
+		if view.Drafts[i].Priority != view.Drafts[j].Priority {
+			return view.Drafts[i].Priority > view.Drafts[j].Priority
+		}
+		return view.Drafts[i].CreatedAt < view.Drafts[j].CreatedAt
+	})
+	return view, nil
+}
+
+// Markdown renders the Active, Iced and Completed Goals sections of a project
+// config, between the markers SyncProjectGoals replaces
+func (v *ProjectGoals) Markdown() string {
+	var b strings.Builder
+	b.WriteString(projectGoalsBegin + "\n## Active Goals\n\n")
+	if len(v.Active) == 0 {
+		b.WriteString("_None currently active_\n")
+	}
+	for _, g := range v.Active {
+		fmt.Fprintf(&b, "- %s: %s", g.ID, g.Title)
+		if g.File != "" {
+			fmt.Fprintf(&b, " ([goal](../%s))", g.File)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Iced Goals\n\n")
+	if len(v.Iced) == 0 {
+		b.WriteString("_None_\n")
+	} else {
+		b.WriteString("| ID | Title | Reason |\n|----|-------|--------|\n")
+		for _, g := range v.Iced {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", g.ID, tableCell(g.Title), tableCell(g.Reason))
+		}
+	}
+
+	b.WriteString("\n## Completed Goals\n\n")
+	if len(v.Completed) == 0 {
+		b.WriteString("_None_\n")
+	} else {
+		b.WriteString("| ID | Title | Completed |\n|----|-------|-----------|\n")
+		for _, g := range v.Completed {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", g.ID, tableCell(g.Title), g.CompletedAt)
+		}
+	}
+	b.WriteString(projectGoalsEnd + "\n")
+	return b.String()
+}
+
+// tableCell keeps text from breaking out of a markdown table cell
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// SyncProjectGoals regenerates the goal lists in the configs of projects
+// (projects/<name>.md) from the registry. The generated block replaces the
+// previous one, or the hand-maintained goal sections of older configs, in
+// place; configs without either get it appended. Missing configs are skipped.
+// Callers serialize syncs with registry updates (the registry lock).
+func SyncProjectGoals(vegaDir string, projects ...string) error {
+	var errs []string
+	for _, project := range projects {
+		if project == "" {
+			continue
+		}
+		if err := syncProjectGoals(vegaDir, project); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", project, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to sync project goals: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func syncProjectGoals(vegaDir, project string) error {
+	path := layout.New(vegaDir).ProjectConfig(project)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	view, err := LoadProjectGoals(vegaDir, project)
+	if err != nil {
+		return err
+	}
+	updated := ReplaceProjectGoals(string(content), view.Markdown())
+	if updated == string(content) {
+		return nil
+	}
+	return atomicfile.WriteFile(path, []byte(updated), 0644)
+}
+
+// ReplaceProjectGoals puts the generated goal lists into a project config:
+// over the previous generated block, or in place of the first hand-maintained
+// goal section (dropping all of them), or appended
+func ReplaceProjectGoals(config, block string) string {
+	if start := strings.Index(config, projectGoalsBegin); start >= 0 {
+		if end := strings.Index(config[start:], projectGoalsEnd); end >= 0 {
+			end += start + len(projectGoalsEnd)
+			if end < len(config) && config[end] == '\n' {
+				end++
+			}
+			return config[:start] + block + config[end:]
+		}
+	}
+
+	lines := strings.Split(config, "\n")
+	var kept []string
+	insertAt := -1
+	inSection := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			inSection = isProjectGoalSection(line)
+			if inSection && insertAt < 0 {
+				insertAt = len(kept)
+			}
+		}
+		if !inSection {
+			kept = append(kept, line)
+		}
+	}
+	blockLines := strings.Split(block, "\n") // Ends with "": a blank line after the block
+	if insertAt < 0 {
+		body := strings.TrimRight(config, "\n")
+		if body == "" {
+			return block
+		}
+		return body + "\n\n" + block
+	}
+	kept = append(kept[:insertAt], append(blockLines, kept[insertAt:]...)...)
+	return strings.Join(kept, "\n")
+}
+
+func isProjectGoalSection(line string) bool {
+	for _, heading := range projectGoalSections {
+		if strings.HasPrefix(line, heading) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package goals
+
+import (
+	"fmt"
+	"strings"
+)
+
+// projectIndexTable returns the table of projects/index.md that lists the
+// projects: the first one with a Project column
+func (d *MarkdownDoc) projectIndexTable() *MarkdownTable {
+	for _, t := range d.Tables() {
+		if t.Column("Project") >= 0 {
+			return t
+		}
+	}
+	return nil
+}
+
+// AddIndexProject lists a project in the projects table of projects/index.md,
+// filling the columns the table has. A project already listed is left alone.
+func (d *MarkdownDoc) AddIndexProject(name string) error {
+	table := d.projectIndexTable()
+	if table == nil {
+		return fmt.Errorf("project index has no table with a Project column")
+	}
+	for _, row := range table.Rows {
+		if indexProjectName(table.Cell(row, "Project")) == name {
+			return nil
+		}
+	}
+
+	values := map[string]string{
+		"Project":      fmt.Sprintf("[%s](%s.md)", name, name),
+		"Path":         fmt.Sprintf("`workspaces/%s/worktree-base/`", name),
+		"Active Goals": "-",
+		"Description":  "_Add description_",
+	}
+	for column := range values {
+		if table.Column(column) < 0 {
+			delete(values, column)
+		}
+	}
+	return table.AddRow(values)
+}
+
+// RemoveIndexProject removes a project's rows from the projects table of
+// projects/index.md. Returns how many rows were removed.
+func (d *MarkdownDoc) RemoveIndexProject(name string) int {
+	table := d.projectIndexTable()
+	if table == nil {
+		return 0
+	}
+	return table.RemoveRows(func(row []string) bool {
+		return indexProjectName(table.Cell(row, "Project")) == name
+	})
+}
+
+// indexProjectName returns the project name of a Project cell, which links
+// the project config: "[name](name.md)"
+func indexProjectName(cell string) string {
+	if strings.HasPrefix(cell, "[") {
+		if end := strings.Index(cell, "]"); end > 0 {
+			return cell[1:end]
+		}
+	}
+	return strings.TrimSpace(cell)
+}
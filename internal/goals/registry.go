@@ -8,22 +8,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
-// RegistryEntry represents one goal in the JSONL registry
-type RegistryEntry struct {
-	ID          string   `json:"id"`
-	Title       string   `json:"title"`
-	Projects    []string `json:"projects"`
-	Status      string   `json:"status"` // active, iced, completed
-	Phase       string   `json:"phase"`
-	ParentID    string   `json:"parent_id,omitempty"`
-	BlockedBy   []string `json:"blocked_by,omitempty"`
-	Reason      string   `json:"reason,omitempty"` // for iced goals
-	CompletedAt string   `json:"completed_at,omitempty"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
-}
+// RegistryEntry is a goal as stored in registry.jsonl, one JSON line per
+// goal. It is the same type as Goal; the name is kept for registry code.
+type RegistryEntry = Goal
 
 // Registry handles JSONL registry operations
 type Registry struct {
@@ -37,7 +29,7 @@ var ErrNotFound = errors.New("goal not found")
 // path = vegaDir/goals/registry.jsonl
 func NewRegistry(vegaDir string) *Registry {
 	return &Registry{
-		path: filepath.Join(vegaDir, "goals", "registry.jsonl"),
+		path: layout.New(vegaDir).Registry(),
 	}
 }
 
@@ -89,54 +81,19 @@ func (r *Registry) Save(entries []RegistryEntry) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Create temp file in same directory for atomic rename
-	tmpFile, err := os.CreateTemp(dir, "registry-*.jsonl.tmp")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-
-	// Clean up temp file on error
-	success := false
-	defer func() {
-		if !success {
-			os.Remove(tmpPath)
-		}
-	}()
-
 	// Write all entries
-	for _, entry := range entries {
-		data, err := json.Marshal(entry)
-		if err != nil {
-			tmpFile.Close()
-			return fmt.Errorf("failed to marshal entry %s: %w", entry.ID, err)
-		}
-		if _, err := tmpFile.Write(data); err != nil {
-			tmpFile.Close()
-			return fmt.Errorf("failed to write entry %s: %w", entry.ID, err)
+	return atomicfile.Write(r.path, 0644, func(f *os.File) error {
+		for _, entry := range entries {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("failed to marshal entry %s: %w", entry.ID, err)
+			}
+			if _, err := f.Write(append(data, '\n')); err != nil {
+				return fmt.Errorf("failed to write entry %s: %w", entry.ID, err)
+			}
 		}
-		if _, err := tmpFile.WriteString("\n"); err != nil {
-			tmpFile.Close()
-			return fmt.Errorf("failed to write newline: %w", err)
-		}
-	}
-
-	// Sync and close before rename
-	if err := tmpFile.Sync(); err != nil {
-		tmpFile.Close()
-		return fmt.Errorf("failed to sync temp file: %w", err)
-	}
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, r.path); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
-	}
-
-	success = true
-	return nil
+		return nil
+	})
 }
 
 // Add appends a single entry to the file (fast path for new goals)
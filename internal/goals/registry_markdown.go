@@ -0,0 +1,137 @@
+package goals
+
+import (
+	"fmt"
+	"strings"
+)
+
+// registrySection is a status section of goals/REGISTRY.md, the markdown
+// registry kept for humans next to registry.jsonl (and the only registry of
+// vega directories that were never migrated)
+type registrySection struct {
+	Status string
+	Title  string
+	Header []string
+}
+
+var registrySections = []registrySection{
+	{"active", "Active Goals", []string{"ID", "Title", "Project(s)", "Status", "Phase"}},
+	{"iced", "Iced Goals", []string{"ID", "Title", "Project(s)", "Reason"}},
+	{"completed", "Completed Goals", []string{"ID", "Title", "Project(s)", "Completed"}},
+}
+
+// ParseRegistryMarkdown reads the goals listed in REGISTRY.md content. Columns
+// are found by name, so tables with extra, missing or reordered columns parse;
+// rows without an ID are skipped.
+func ParseRegistryMarkdown(content string) []Goal {
+	doc := ParseMarkdown(content)
+	var goals []Goal
+	for _, section := range registrySections {
+		for _, t := range doc.Tables() {
+			if !strings.HasPrefix(t.Section, section.Title) {
+				continue
+			}
+			for _, row := range t.Rows {
+				id := registryID(t.Cell(row, "ID"))
+				if id == "" {
+					continue
+				}
+				goal := Goal{
+					ID:       id,
+					Title:    t.Cell(row, "Title"),
+					Projects: parseProjects(t.Cell(row, "Project(s)")),
+					Status:   section.Status,
+				}
+				switch section.Status {
+				case "active":
+					goal.Phase = t.Cell(row, "Phase")
+					if goal.Phase == "" {
+						goal.Phase = "1/?"
+					}
+				case "iced":
+					goal.Reason = t.Cell(row, "Reason")
+				case "completed":
+					goal.CompletedAt = t.Cell(row, "Completed")
+				}
+				goals = append(goals, goal)
+			}
+		}
+	}
+	return goals
+}
+
+// RemoveRegistryGoal removes a goal's rows from every table with an ID
+// column. Returns how many rows were removed.
+func (d *MarkdownDoc) RemoveRegistryGoal(goalID string) int {
+	removed := 0
+	for _, t := range d.Tables() {
+		if t.Column("ID") < 0 {
+			continue
+		}
+		removed += t.RemoveRows(func(row []string) bool {
+			return registryID(t.Cell(row, "ID")) == goalID
+		})
+	}
+	return removed
+}
+
+// SetRegistryGoal lists a goal in the section of its status ("active",
+// "iced" or "completed"), removing it from the others. The section is added
+// with the standard columns if the document doesn't have it; columns the
+// section's table lacks are left out of the row.
+func (d *MarkdownDoc) SetRegistryGoal(goal Goal) error {
+	var section *registrySection
+	for i := range registrySections {
+		if registrySections[i].Status == goal.Status {
+			section = &registrySections[i]
+		}
+	}
+	if section == nil {
+		return fmt.Errorf("REGISTRY.md has no section for status %q", goal.Status)
+	}
+
+	d.RemoveRegistryGoal(goal.ID)
+	table := d.Table(section.Title)
+	if table == nil {
+		table = d.AddSection(section.Title, section.Header...)
+	}
+	if table.Column("ID") < 0 {
+		return fmt.Errorf("the %s table of REGISTRY.md has no ID column", section.Title)
+	}
+
+	values := map[string]string{
+		"ID":         goal.ID,
+		"Title":      goal.Title,
+		"Project(s)": strings.Join(goal.Projects, ", "),
+	}
+	switch goal.Status {
+	case "active":
+		values["Status"], values["Phase"] = "Active", goal.Phase
+	case "iced":
+		values["Reason"] = goal.Reason
+	case "completed":
+		values["Completed"] = goal.CompletedAt
+	}
+	for name := range values {
+		if table.Column(name) < 0 {
+			delete(values, name)
+		}
+	}
+	return table.AddRow(values)
+}
+
+// registryID returns the goal ID of an ID cell ("#14" and "14" are the same)
+func registryID(cell string) string {
+	return strings.TrimPrefix(strings.TrimSpace(cell), "#")
+}
+
+// parseProjects splits a comma-separated Project(s) cell
+func parseProjects(cell string) []string {
+	projects := []string{}
+	for _, p := range strings.Split(cell, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
@@ -0,0 +1,114 @@
+package goals
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Review decisions, as accepted by ReviewState
+const (
+	ReviewApprove        = "approve"
+	ReviewRequestChanges = "request_changes"
+)
+
+var (
+	// ErrChangesRequested is returned when completing a goal a reviewer sent back
+	ErrChangesRequested = errors.New("a reviewer requested changes")
+
+	// ErrReviewRequired is returned when the project requires an approved review
+	// before a goal can complete
+	ErrReviewRequired = errors.New("goal must be approved by a reviewer")
+)
+
+// Review is a reviewer's decision on a goal. Reviews are state transitions
+// (changes_requested or approved); the reviewer is the transition's user and
+// the comment is kept in its details.
+type Review struct {
+	State    GoalState `json:"state"`
+	Reviewer string    `json:"reviewer,omitempty"`
+	Comment  string    `json:"comment,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// ReviewState maps a review decision to the goal state it leads to
+func ReviewState(decision string) (GoalState, error) {
+	switch decision {
+	case ReviewApprove:
+		return StateApproved, nil
+	case ReviewRequestChanges:
+		return StateChangesRequested, nil
+	default:
+		return "", fmt.Errorf("invalid review decision %q (valid: %s, %s)", decision, ReviewApprove, ReviewRequestChanges)
+	}
+}
+
+// IsReviewState returns true for the states a review leaves a goal in
+func (s GoalState) IsReviewState() bool {
+	return s == StateChangesRequested || s == StateApproved
+}
+
+// Review records a reviewer's decision on a goal as a state transition
+func (m *StateManager) Review(goalID string, state GoalState, reviewer, comment string) (*Review, error) {
+	if !state.IsReviewState() {
+		return nil, fmt.Errorf("invalid review state: %s", state)
+	}
+	comment = strings.TrimSpace(comment)
+	if state == StateChangesRequested && comment == "" {
+		return nil, errors.New("a comment is required when requesting changes")
+	}
+
+	var details map[string]string
+	if comment != "" {
+		details = map[string]string{"comment": comment}
+	}
+	reason := "Approved"
+	if state == StateChangesRequested {
+		reason = "Changes requested"
+	}
+	if reviewer != "" {
+		reason += " by " + reviewer
+	}
+	if err := m.TransitionWithUser(goalID, state, reason, reviewer, details); err != nil {
+		return nil, err
+	}
+	return m.LatestReview(goalID)
+}
+
+// LatestReview returns the most recent review of a goal (nil if never reviewed)
+func (m *StateManager) LatestReview(goalID string) (*Review, error) {
+	events, err := m.GetHistory(goalID)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if !e.State.IsReviewState() || e.Details["forced"] == "true" {
+			continue
+		}
+		return &Review{
+			State:    e.State,
+			Reviewer: e.User,
+			Comment:  e.Details["comment"],
+			At:       e.Timestamp,
+		}, nil
+	}
+	return nil, nil
+}
+
+// CheckReview reports whether a goal in state may complete: never while
+// changes are requested, and only once approved if the policy requires review
+func CheckReview(state GoalState, policy MergePolicy) error {
+	if state == StateChangesRequested {
+		return ErrChangesRequested
+	}
+	if policy.RequireReview && state != StateApproved {
+		return ErrReviewRequired
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package goals
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateManagerReview(t *testing.T) {
+	dir := t.TempDir()
+	goalsDir := filepath.Join(dir, "goals", "active")
+	if err := os.MkdirAll(goalsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(goalsDir, "abc1234.md"), []byte("# Goal"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sm := NewStateManager(dir)
+
+	if review, err := sm.LatestReview("abc1234"); err != nil || review != nil {
+		t.Fatalf("expected no review before any, got %+v (%v)", review, err)
+	}
+	if err := sm.Transition("abc1234", StateWorking, "Goal ready", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sm.Review("abc1234", StateChangesRequested, "bob", "  "); err == nil {
+		t.Error("expected requesting changes without a comment to fail")
+	}
+	review, err := sm.Review("abc1234", StateChangesRequested, "bob", "Add tests for the parser")
+	if err != nil {
+		t.Fatalf("Review: %v", err)
+	}
+	if review.State != StateChangesRequested || review.Reviewer != "bob" || review.Comment != "Add tests for the parser" || review.At.IsZero() {
+		t.Errorf("unexpected review %+v", review)
+	}
+
+	// The review stays the latest while the goal is worked on again
+	if err := sm.Transition("abc1234", StateWorking, "Executor started", nil); err != nil {
+		t.Fatal(err)
+	}
+	if review, _ := sm.LatestReview("abc1234"); review == nil || review.State != StateChangesRequested {
+		t.Errorf("expected the changes request kept, got %+v", review)
+	}
+
+	if _, err := sm.Review("abc1234", StateApproved, "bob", ""); err != nil {
+		t.Fatalf("Review: %v", err)
+	}
+	if review, _ := sm.LatestReview("abc1234"); review.State != StateApproved || review.Comment != "" {
+		t.Errorf("expected the approval, got %+v", review)
+	}
+	if _, err := sm.Review("abc1234", StateWorking, "bob", ""); err == nil {
+		t.Error("expected a non-review state refused")
+	}
+}
+
+func TestReviewState(t *testing.T) {
+	if s, err := ReviewState(ReviewApprove); err != nil || s != StateApproved {
+		t.Errorf("ReviewState(approve) = %s, %v", s, err)
+	}
+	if s, err := ReviewState(ReviewRequestChanges); err != nil || s != StateChangesRequested {
+		t.Errorf("ReviewState(request_changes) = %s, %v", s, err)
+	}
+	if _, err := ReviewState("lgtm"); err == nil {
+		t.Error("expected an unknown decision refused")
+	}
+}
+
+func TestCheckReview(t *testing.T) {
+	tests := []struct {
+		state  GoalState
+		policy MergePolicy
+		want   error
+	}{
+		{StateWorking, MergePolicy{}, nil},
+		{StateApproved, MergePolicy{}, nil},
+		{StateChangesRequested, MergePolicy{}, ErrChangesRequested},
+		{StateWorking, MergePolicy{RequireReview: true}, ErrReviewRequired},
+		{StateChangesRequested, MergePolicy{RequireReview: true}, ErrChangesRequested},
+		{StateApproved, MergePolicy{RequireReview: true}, nil},
+	}
+	for _, tt := range tests {
+		if err := CheckReview(tt.state, tt.policy); !errors.Is(err, tt.want) {
+			t.Errorf("CheckReview(%s, %+v) = %v, want %v", tt.state, tt.policy, err, tt.want)
+		}
+	}
+}
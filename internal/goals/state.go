@@ -3,12 +3,15 @@ package goals
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // GoalState represents the lifecycle state of a goal
@@ -22,8 +25,12 @@ const (
 	StateMerging   GoalState = "merging"   // Merging to target branch
 	StateDone      GoalState = "done"      // Successfully completed
 	StateIced      GoalState = "iced"      // Paused/frozen
+	StatePaused    GoalState = "paused"    // Executor suspended by a user
 	StateFailed    GoalState = "failed"    // Failed (recoverable)
 	StateConflict  GoalState = "conflict"  // Merge conflict detected
+
+	StateChangesRequested GoalState = "changes_requested" // Reviewer asked for changes
+	StateApproved         GoalState = "approved"          // Reviewer approved; ready to complete
 )
 
 // AllStates returns all valid goal states
@@ -36,8 +43,11 @@ func AllStates() []GoalState {
 		StateMerging,
 		StateDone,
 		StateIced,
+		StatePaused,
 		StateFailed,
 		StateConflict,
+		StateChangesRequested,
+		StateApproved,
 	}
 }
 
@@ -64,7 +74,8 @@ func (s GoalState) NeedsAttention() bool {
 // ToHumanStatus converts state to human-readable status for markdown
 func (s GoalState) ToHumanStatus() string {
 	switch s {
-	case StatePending, StateBranching, StateWorking, StatePushing, StateMerging:
+	case StatePending, StateBranching, StateWorking, StatePushing, StateMerging, StatePaused,
+		StateChangesRequested, StateApproved:
 		return "Active"
 	case StateIced:
 		return "Iced"
@@ -80,15 +91,23 @@ func (s GoalState) ToHumanStatus() string {
 // validTransitions defines the allowed state transitions
 // Key: from state, Value: list of allowed to states
 var validTransitions = map[GoalState][]GoalState{
-	StatePending:   {StateBranching, StateFailed},
-	StateBranching: {StateWorking, StateFailed},
-	StateWorking:   {StatePushing, StateIced, StateFailed},
-	StatePushing:   {StateMerging, StateFailed, StateWorking}, // Can go back to working if push fails non-fatally
-	StateMerging:   {StateDone, StateConflict, StateFailed},
-	StateConflict:  {StateMerging, StateFailed, StateWorking}, // After resolution, retry merge or go back to working
-	StateIced:      {StateWorking},                            // Resume
-	StateFailed:    {StatePending, StateWorking, StateBranching}, // Retry from various points
-	StateDone:      {}, // Terminal, no transitions out
+	StatePending:          {StateBranching, StateFailed},
+	StateBranching:        {StateWorking, StateFailed},
+	StateWorking:          {StatePushing, StateIced, StateFailed, StatePaused, StateChangesRequested, StateApproved},
+	StatePushing:          {StateMerging, StateFailed, StateWorking}, // Can go back to working if push fails non-fatally
+	StateMerging:          {StateDone, StateConflict, StateFailed},
+	StateConflict:         {StateMerging, StateFailed, StateWorking},                                    // After resolution, retry merge or go back to working
+	StateIced:             {StateWorking},                                                               // Resume
+	StatePaused:           {StateWorking, StateIced, StateFailed, StateChangesRequested, StateApproved}, // Resume executor, give up, or review
+	StateChangesRequested: {StateWorking, StateApproved, StateIced, StateFailed},                        // Next executor run picks up the comments
+	StateApproved:         {StatePushing, StateWorking, StateChangesRequested, StateIced, StateFailed},  // Complete, or reopen
+	StateFailed:           {StatePending, StateWorking, StateBranching},                                 // Retry from various points
+	StateDone:             {},                                                                           // Terminal, no transitions out
+}
+
+// AllowedTransitions returns the states a goal can move to from the given state
+func AllowedTransitions(from GoalState) []GoalState {
+	return append([]GoalState{}, validTransitions[from]...)
 }
 
 // CanTransition checks if a transition from one state to another is valid
@@ -117,59 +136,32 @@ type StateEvent struct {
 
 // StateManager handles goal state persistence and transitions
 type StateManager struct {
-	dir string // vega-missile directory
-	mu  sync.RWMutex
+	dir    string // vega-missile directory
+	layout *layout.Layout
+	mu     sync.RWMutex
+
+	hooks      *StateHooks           // External hooks run on transitions
+	validators []TransitionValidator // In-process transition checks
 }
 
 // NewStateManager creates a new StateManager
 func NewStateManager(dir string) *StateManager {
-	return &StateManager{dir: dir}
+	return &StateManager{dir: dir, layout: layout.New(dir), hooks: NewStateHooks(dir)}
 }
 
-// stateFilePath returns the path to a goal's state file
+// stateFilePath returns the path to a goal's state file: the existing one,
+// or where it belongs next to the goal file
 func (m *StateManager) stateFilePath(goalID string) string {
-	// Check folder structure first (goals/active/<id>/<id>.state.jsonl)
-	// Then fall back to flat structure (goals/active/<id>.state.jsonl)
-	
-	dirs := []string{"active", "iced", "history"}
-	for _, dir := range dirs {
-		// Folder structure
-		folderPath := filepath.Join(m.dir, "goals", dir, goalID, goalID+".state.jsonl")
-		if _, err := os.Stat(folderPath); err == nil {
-			return folderPath
-		}
-		// Flat structure (legacy)
-		flatPath := filepath.Join(m.dir, "goals", dir, goalID+".state.jsonl")
-		if _, err := os.Stat(flatPath); err == nil {
-			return flatPath
-		}
-	}
-	
-	// Default to folder structure in active for new goals
-	return filepath.Join(m.dir, "goals", "active", goalID, goalID+".state.jsonl")
+	return m.layout.GoalSidecar(goalID, ".state.jsonl")
 }
 
 // stateFilePathForWrite returns the path for writing (always in the goal's current location)
 func (m *StateManager) stateFilePathForWrite(goalID string) (string, error) {
-	// Check where the goal file is to determine where state should go
-	// Supports both folder structure (goals/<dir>/<id>/<id>.md) and flat (goals/<dir>/<id>.md)
-	
-	dirs := []string{"active", "iced", "history"}
-	for _, dir := range dirs {
-		// Folder structure (preferred)
-		folderGoal := filepath.Join(m.dir, "goals", dir, goalID, goalID+".md")
-		if _, err := os.Stat(folderGoal); err == nil {
-			return filepath.Join(m.dir, "goals", dir, goalID, goalID+".state.jsonl"), nil
-		}
-		// Flat structure (legacy)
-		flatGoal := filepath.Join(m.dir, "goals", dir, goalID+".md")
-		if _, err := os.Stat(flatGoal); err == nil {
-			return filepath.Join(m.dir, "goals", dir, goalID+".state.jsonl"), nil
-		}
+	if goalFile, _ := m.layout.FindGoalFile(goalID); goalFile != "" {
+		return layout.Sidecar(goalFile, ".state.jsonl"), nil
 	}
-	
-	// Goal doesn't exist yet, default to folder structure in active
-	return filepath.Join(m.dir, "goals", "active", goalID, goalID+".state.jsonl"), nil
+	// Goal doesn't exist yet: next to the file it will get in active
+	return layout.Sidecar(m.layout.GoalFile(layout.ActiveDir, goalID), ".state.jsonl"), nil
 }
 
 // GetState returns the current state of a goal
@@ -223,67 +215,128 @@ func (m *StateManager) Transition(goalID string, newState GoalState, reason stri
 	return m.TransitionWithUser(goalID, newState, reason, "", details)
 }
 
-// TransitionWithUser changes the state of a goal with validation and user tracking
+// maxTransitionAttempts bounds how often a transition is re-checked when the
+// goal's state changes while its veto hooks run
+const maxTransitionAttempts = 3
+
+// TransitionWithUser changes the state of a goal with validation and user tracking.
+// Validators and veto hooks run without holding the lock, so a slow hook only
+// delays its own transition; if the goal's state changed meanwhile, the
+// transition is checked again from the new state.
 func (m *StateManager) TransitionWithUser(goalID string, newState GoalState, reason, user string, details map[string]string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	
 	if !newState.IsValid() {
 		return fmt.Errorf("invalid state: %s", newState)
 	}
-	
-	// Get current state
+
+	for attempt := 1; ; attempt++ {
+		m.mu.Lock()
+		currentState, err := m.transitionFrom(goalID, newState)
+		validators := append([]TransitionValidator(nil), m.validators...)
+		m.mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		// Run validators and veto hooks (they may annotate details)
+		check := TransitionEvent{
+			GoalID:    goalID,
+			From:      currentState,
+			To:        newState,
+			Reason:    reason,
+			User:      user,
+			Details:   copyDetails(details),
+			Timestamp: time.Now().UTC(),
+		}
+		if err := m.checkTransition(&check, validators); err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		if state := m.lastState(goalID); state != currentState {
+			m.mu.Unlock()
+			if attempt < maxTransitionAttempts {
+				continue
+			}
+			return fmt.Errorf("goal %s changed state (%s → %s) while its transition to %s was checked", goalID, currentState, state, newState)
+		}
+
+		// Create event
+		event := StateEvent{
+			Timestamp: check.Timestamp,
+			State:     newState,
+			PrevState: currentState,
+			Reason:    reason,
+			User:      user,
+			Details:   check.Details,
+		}
+
+		// Append to file
+		if err := m.appendEvent(goalID, event); err != nil {
+			m.mu.Unlock()
+			return err
+		}
+
+		// Sync goal markdown file status (best effort - don't fail transition on sync error)
+		// Skip sync for pending state as goal file may not exist yet.
+		// The state file is the source of truth; the markdown is for humans.
+		if newState != StatePending {
+			_ = m.syncGoalFileInternal(goalID, newState)
+		}
+		m.mu.Unlock()
+
+		m.notifyHooks(check)
+		return nil
+	}
+}
+
+// transitionFrom returns the goal's current state after checking it may move
+// to newState (caller holds lock)
+func (m *StateManager) transitionFrom(goalID string, newState GoalState) (GoalState, error) {
 	events, err := m.readEvents(goalID)
 	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("reading current state: %w", err)
+		return "", fmt.Errorf("reading current state: %w", err)
 	}
-	
-	var currentState GoalState
+
 	if len(events) == 0 {
 		// No previous state - only allow pending or working (for legacy/backfill)
 		if newState != StatePending && newState != StateWorking {
-			return fmt.Errorf("first state must be 'pending' or 'working', got '%s'", newState)
+			return "", fmt.Errorf("%w, got '%s'", ErrInvalidInitialState, newState)
 		}
-		currentState = "" // No previous state
-	} else {
-		currentState = events[len(events)-1].State
-		
-		// Validate transition
-		if !CanTransition(currentState, newState) {
-			return &InvalidTransitionError{
-				GoalID: goalID,
-				From:   currentState,
-				To:     newState,
-			}
+		return "", nil // No previous state
+	}
+
+	currentState := events[len(events)-1].State
+	if !CanTransition(currentState, newState) {
+		return "", &InvalidTransitionError{
+			GoalID: goalID,
+			From:   currentState,
+			To:     newState,
 		}
 	}
-	
-	// Create event
-	event := StateEvent{
-		Timestamp: time.Now().UTC(),
-		State:     newState,
-		PrevState: currentState,
-		Reason:    reason,
-		User:      user,
-		Details:   details,
+	return currentState, nil
+}
+
+// lastState returns the state of the goal's last event, empty if it has none
+// (caller holds lock)
+func (m *StateManager) lastState(goalID string) GoalState {
+	events, _ := m.readEvents(goalID)
+	if len(events) == 0 {
+		return ""
 	}
-	
-	// Append to file
-	if err := m.appendEvent(goalID, event); err != nil {
-		return err
+	return events[len(events)-1].State
+}
+
+// copyDetails copies a details map, so hook annotations of a check that is
+// retried don't leak into the caller's map
+func copyDetails(details map[string]string) map[string]string {
+	if details == nil {
+		return nil
 	}
-	
-	// Sync goal markdown file status (best effort - don't fail transition on sync error)
-	// Skip sync for pending state as goal file may not exist yet
-	if newState != StatePending {
-		if syncErr := m.syncGoalFileInternal(goalID, newState); syncErr != nil {
-			// Log but don't fail - state file is the source of truth
-			// The markdown file sync is for human readability
-			_ = syncErr // Silently ignore sync errors
-		}
+	result := make(map[string]string, len(details))
+	for k, v := range details {
+		result[k] = v
 	}
-	
-	return nil
+	return result
 }
 
 // ForceState sets a goal's state without validation (escape hatch)
@@ -320,6 +373,18 @@ func (m *StateManager) ForceStateWithUser(goalID string, newState GoalState, rea
 		return err
 	}
 	
+	// Forced transitions skip veto hooks but are still reported
+	m.notifyHooks(TransitionEvent{
+		GoalID:    goalID,
+		From:      currentState,
+		To:        newState,
+		Reason:    event.Reason,
+		User:      user,
+		Details:   event.Details,
+		Forced:    true,
+		Timestamp: event.Timestamp,
+	})
+	
 	// Sync goal markdown file status (best effort)
 	_ = m.syncGoalFileInternal(goalID, newState)
 	
@@ -334,21 +399,7 @@ func (m *StateManager) GoalsInState(state GoalState) ([]string, error) {
 	var result []string
 	
 	// Scan active goals
-	activeDir := filepath.Join(m.dir, "goals", "active")
-	entries, err := os.ReadDir(activeDir)
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
-	}
-	
-	for _, entry := range entries {
-		if filepath.Ext(entry.Name()) != ".jsonl" {
-			continue
-		}
-		goalID := entry.Name()[:len(entry.Name())-len(".state.jsonl")]
-		if !isValidGoalID(goalID) {
-			continue
-		}
-		
+	for _, goalID := range m.activeGoalsWithState() {
 		goalState, err := m.getStateUnsafe(goalID)
 		if err != nil {
 			continue
@@ -361,32 +412,28 @@ func (m *StateManager) GoalsInState(state GoalState) ([]string, error) {
 	return result, nil
 }
 
+// StuckStates are the transient states a goal can get stuck in
+var StuckStates = []GoalState{StateBranching, StatePushing, StateMerging}
+
 // StuckGoals returns goals that have been in a non-terminal state for longer than maxAge
 func (m *StateManager) StuckGoals(maxAge time.Duration) ([]StuckGoal, error) {
+	thresholds := make(map[GoalState]time.Duration, len(StuckStates))
+	for _, state := range StuckStates {
+		thresholds[state] = maxAge
+	}
+	return m.StuckGoalsByState(thresholds)
+}
+
+// StuckGoalsByState returns goals that have been in one of the states in
+// thresholds for longer than its threshold
+func (m *StateManager) StuckGoalsByState(thresholds map[GoalState]time.Duration) ([]StuckGoal, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
 	var result []StuckGoal
-	cutoff := time.Now().UTC().Add(-maxAge)
-	
-	// States that can be "stuck"
-	stuckStates := []GoalState{StateBranching, StatePushing, StateMerging}
+	now := time.Now().UTC()
 	
-	activeDir := filepath.Join(m.dir, "goals", "active")
-	entries, err := os.ReadDir(activeDir)
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
-	}
-	
-	for _, entry := range entries {
-		if filepath.Ext(entry.Name()) != ".jsonl" {
-			continue
-		}
-		goalID := entry.Name()[:len(entry.Name())-len(".state.jsonl")]
-		if !isValidGoalID(goalID) {
-			continue
-		}
-		
+	for _, goalID := range m.activeGoalsWithState() {
 		events, err := m.readEvents(goalID)
 		if err != nil || len(events) == 0 {
 			continue
@@ -394,31 +441,48 @@ func (m *StateManager) StuckGoals(maxAge time.Duration) ([]StuckGoal, error) {
 		
 		lastEvent := events[len(events)-1]
 		
-		// Check if in a stuck-able state and older than cutoff
-		for _, stuckState := range stuckStates {
-			if lastEvent.State == stuckState && lastEvent.Timestamp.Before(cutoff) {
-				result = append(result, StuckGoal{
-					GoalID:    goalID,
-					State:     lastEvent.State,
-					Since:     lastEvent.Timestamp,
-					Duration:  time.Since(lastEvent.Timestamp),
-				})
-				break
-			}
+		// Check if in a stuck-able state and older than its threshold
+		threshold, ok := thresholds[lastEvent.State]
+		if ok && lastEvent.Timestamp.Before(now.Add(-threshold)) {
+			result = append(result, StuckGoal{
+				GoalID:    goalID,
+				State:     lastEvent.State,
+				Since:     lastEvent.Timestamp,
+				Duration:  time.Since(lastEvent.Timestamp),
+				Threshold: threshold,
+			})
 		}
 	}
 	
 	return result, nil
 }
 
+// activeGoalsWithState returns the IDs of active goals that have a state
+// file, in either layout style, sorted
+func (m *StateManager) activeGoalsWithState() []string {
+	var ids []string
+	for goalID := range m.layout.GoalFiles(layout.ActiveDir) {
+		if isValidGoalID(goalID) && m.layout.FindSidecarIn(layout.ActiveDir, goalID, ".state.jsonl") != "" {
+			ids = append(ids, goalID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // StuckGoal represents a goal that appears to be stuck
 type StuckGoal struct {
-	GoalID   string        `json:"goal_id"`
-	State    GoalState     `json:"state"`
-	Since    time.Time     `json:"since"`
-	Duration time.Duration `json:"duration"`
+	GoalID    string        `json:"goal_id"`
+	State     GoalState     `json:"state"`
+	Since     time.Time     `json:"since"`
+	Duration  time.Duration `json:"duration"`
+	Threshold time.Duration `json:"threshold"` // How long it may stay in State
 }
 
+// ErrInvalidInitialState is returned when a goal without state history is moved
+// to a state other than pending or working
+var ErrInvalidInitialState = errors.New("first state must be 'pending' or 'working'")
+
 // InvalidTransitionError is returned when an invalid state transition is attempted
 type InvalidTransitionError struct {
 	GoalID string
@@ -427,7 +491,7 @@ type InvalidTransitionError struct {
 }
 
 func (e *InvalidTransitionError) Error() string {
-	allowed := validTransitions[e.From]
+	allowed := AllowedTransitions(e.From)
 	return fmt.Sprintf("invalid state transition for goal %s: %s → %s (allowed: %v)", 
 		e.GoalID, e.From, e.To, allowed)
 }
@@ -519,30 +583,8 @@ func isValidGoalID(s string) bool {
 func (m *StateManager) MoveStateFile(goalID, fromDir, toDir string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	
-	// Check folder structure first, then flat
-	fromFolderPath := filepath.Join(m.dir, "goals", fromDir, goalID, goalID+".state.jsonl")
-	fromFlatPath := filepath.Join(m.dir, "goals", fromDir, goalID+".state.jsonl")
-	
-	var fromPath string
-	if _, err := os.Stat(fromFolderPath); err == nil {
-		fromPath = fromFolderPath
-	} else if _, err := os.Stat(fromFlatPath); err == nil {
-		fromPath = fromFlatPath
-	} else {
-		return nil // Nothing to move
-	}
-	
-	// Determine target path (prefer folder structure if goal folder exists)
-	toFolderDir := filepath.Join(m.dir, "goals", toDir, goalID)
-	var toPath string
-	if _, err := os.Stat(toFolderDir); err == nil {
-		toPath = filepath.Join(toFolderDir, goalID+".state.jsonl")
-	} else {
-		toPath = filepath.Join(m.dir, "goals", toDir, goalID+".state.jsonl")
-	}
-	
-	return os.Rename(fromPath, toPath)
+
+	return m.layout.MoveSidecar(goalID, ".state.jsonl", fromDir, toDir)
 }
 
 // SyncGoalFile updates the goal markdown file's Status field to match the current state
@@ -567,20 +609,11 @@ func (m *StateManager) syncGoalFileInternal(goalID string, state GoalState) erro
 	return m.updateGoalFileStatus(goalPath, state.ToHumanStatus())
 }
 
-// findGoalFile locates the goal markdown file in active, iced, or history
+// findGoalFile locates the goal markdown file wherever it is
 func (m *StateManager) findGoalFile(goalID string) (string, error) {
-	locations := []string{
-		filepath.Join(m.dir, "goals", "active", goalID+".md"),
-		filepath.Join(m.dir, "goals", "iced", goalID+".md"),
-		filepath.Join(m.dir, "goals", "history", goalID+".md"),
-	}
-	
-	for _, path := range locations {
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		}
+	if path, _ := m.layout.FindGoalFile(goalID); path != "" {
+		return path, nil
 	}
-	
 	return "", fmt.Errorf("goal file not found for %s", goalID)
 }
 
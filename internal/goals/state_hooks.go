@@ -0,0 +1,388 @@
+package goals
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// DefaultHookTimeout bounds how long a transition hook may run
+const DefaultHookTimeout = 10 * time.Second
+
+// maxHookOutput caps the hook output kept as a veto reason
+const maxHookOutput = 500
+
+var (
+	// ErrTransitionVetoed is returned when a hook or validator rejects a transition
+	ErrTransitionVetoed = errors.New("transition vetoed")
+
+	// ErrHookNotFound is returned when a transition hook doesn't exist
+	ErrHookNotFound = errors.New("hook not found")
+)
+
+// TransitionHook runs an external command or HTTP callback on matching state transitions.
+// Veto hooks run before the transition is recorded and can reject or annotate it;
+// other hooks are notified asynchronously after it is recorded.
+type TransitionHook struct {
+	ID      string      `json:"id"`
+	From    []GoalState `json:"from,omitempty"` // Source states to match (any if empty)
+	To      []GoalState `json:"to,omitempty"`   // Target states to match (any if empty)
+	Command string      `json:"command,omitempty"`
+	URL     string      `json:"url,omitempty"`
+	Veto    bool        `json:"veto,omitempty"`
+	Timeout int         `json:"timeout_seconds,omitempty"` // Defaults to 10s
+
+	Disabled  bool      `json:"disabled,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the hook is well-formed
+func (h *TransitionHook) Validate() error {
+	if (h.Command == "") == (h.URL == "") {
+		return fmt.Errorf("exactly one of command or url is required")
+	}
+	if h.URL != "" && !strings.HasPrefix(h.URL, "http://") && !strings.HasPrefix(h.URL, "https://") {
+		return fmt.Errorf("url must be http or https: %s", h.URL)
+	}
+	for _, s := range append(append([]GoalState{}, h.From...), h.To...) {
+		if !s.IsValid() {
+			return fmt.Errorf("invalid state: %s", s)
+		}
+	}
+	if h.Timeout < 0 {
+		return fmt.Errorf("timeout_seconds must not be negative")
+	}
+	return nil
+}
+
+// Matches returns true if the hook applies to a from → to transition
+func (h *TransitionHook) Matches(from, to GoalState) bool {
+	if h.Disabled {
+		return false
+	}
+	return matchState(h.From, from) && matchState(h.To, to)
+}
+
+func matchState(states []GoalState, s GoalState) bool {
+	if len(states) == 0 {
+		return true
+	}
+	for _, candidate := range states {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionEvent describes a transition for hooks and validators.
+// Hooks receive it as JSON (stdin for commands, POST body for URLs).
+type TransitionEvent struct {
+	GoalID    string            `json:"goal_id"`
+	From      GoalState         `json:"from,omitempty"`
+	To        GoalState         `json:"to"`
+	Reason    string            `json:"reason,omitempty"`
+	User      string            `json:"user,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	Forced    bool              `json:"forced,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// HookResult is an optional JSON reply from a hook (command stdout or HTTP body).
+// Allow=false vetoes the transition; Details are added to the state event.
+type HookResult struct {
+	Allow   *bool             `json:"allow,omitempty"`
+	Reason  string            `json:"reason,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// TransitionValidator is an in-process check run before a transition is recorded.
+// Returning an error rejects the transition.
+type TransitionValidator func(TransitionEvent) error
+
+// TransitionVetoedError is returned when a hook or validator rejects a transition
+type TransitionVetoedError struct {
+	GoalID string
+	From   GoalState
+	To     GoalState
+	HookID string // Empty for in-process validators
+	Reason string
+}
+
+func (e *TransitionVetoedError) Error() string {
+	by := "validator"
+	if e.HookID != "" {
+		by = "hook " + e.HookID
+	}
+	return fmt.Sprintf("transition for goal %s: %s → %s vetoed by %s: %s", e.GoalID, e.From, e.To, by, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrTransitionVetoed) work
+func (e *TransitionVetoedError) Is(target error) bool {
+	return target == ErrTransitionVetoed
+}
+
+// StateHooks stores transition hooks in <vega-dir>/.vega-hub-state-hooks.json
+type StateHooks struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// stateHooksFile is the on-disk format
+type stateHooksFile struct {
+	Hooks []*TransitionHook `json:"hooks"`
+}
+
+// NewStateHooks creates a hook store for the vega-missile directory
+func NewStateHooks(dir string) *StateHooks {
+	return &StateHooks{dir: dir}
+}
+
+func (s *StateHooks) path() string {
+	return filepath.Join(s.dir, ".vega-hub-state-hooks.json")
+}
+
+// load reads hooks, skipping invalid ones (caller holds lock)
+func (s *StateHooks) load() ([]*TransitionHook, error) {
+	data, err := os.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state hooks: %w", err)
+	}
+	var file stateHooksFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse state hooks: %w", err)
+	}
+	hooks := make([]*TransitionHook, 0, len(file.Hooks))
+	for _, h := range file.Hooks {
+		if err := h.Validate(); err != nil {
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, nil
+}
+
+// save writes hooks atomically (caller holds lock)
+func (s *StateHooks) save(hooks []*TransitionHook) error {
+	data, err := json.MarshalIndent(stateHooksFile{Hooks: hooks}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state hooks: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write state hooks: %w", err)
+	}
+	return nil
+}
+
+// List returns all hooks
+func (s *StateHooks) List() ([]*TransitionHook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Add validates and stores a new hook, assigning an ID if needed
+func (s *StateHooks) Add(hook *TransitionHook) error {
+	if err := hook.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hooks, err := s.load()
+	if err != nil {
+		return err
+	}
+	if hook.ID == "" {
+		hook.ID = fmt.Sprintf("hook-%d", time.Now().UnixNano())
+	}
+	for _, existing := range hooks {
+		if existing.ID == hook.ID {
+			return fmt.Errorf("hook %s already exists", hook.ID)
+		}
+	}
+	if hook.CreatedAt.IsZero() {
+		hook.CreatedAt = time.Now().UTC()
+	}
+	return s.save(append(hooks, hook))
+}
+
+// Delete removes a hook by ID
+func (s *StateHooks) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hooks, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, h := range hooks {
+		if h.ID == id {
+			return s.save(append(hooks[:i], hooks[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrHookNotFound, id)
+}
+
+// matching returns enabled hooks for a transition, filtered by veto mode
+func (s *StateHooks) matching(from, to GoalState, veto bool) []*TransitionHook {
+	hooks, err := s.List()
+	if err != nil {
+		log.Printf("[STATE] Failed to load state hooks: %v", err)
+		return nil
+	}
+	var result []*TransitionHook
+	for _, h := range hooks {
+		if h.Veto == veto && h.Matches(from, to) {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// Run executes the hook for an event and returns its parsed reply. Commands run
+// in dir. A failing command (non-zero exit) or non-2xx response is returned as an error.
+func (h *TransitionHook) Run(dir string, event TransitionEvent) (*HookResult, error) {
+	timeout := DefaultHookTimeout
+	if h.Timeout > 0 {
+		timeout = time.Duration(h.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []byte
+	if h.Command != "" {
+		cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+		cmd.Dir = dir
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Env = append(os.Environ(),
+			"VEGA_GOAL_ID="+event.GoalID,
+			"VEGA_STATE_FROM="+string(event.From),
+			"VEGA_STATE_TO="+string(event.To),
+			"VEGA_STATE_REASON="+event.Reason,
+			"VEGA_USER="+event.User,
+		)
+		output, err = cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %v", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s", hookOutput(output, err.Error()))
+		}
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		output, _ = io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("%s", hookOutput(output, resp.Status))
+		}
+	}
+
+	var result HookResult
+	if trimmed := bytes.TrimSpace(output); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &result); err != nil {
+			return nil, fmt.Errorf("invalid hook reply: %w", err)
+		}
+	}
+	return &result, nil
+}
+
+// hookOutput returns the trimmed output as a reason, or fallback if empty
+func hookOutput(output []byte, fallback string) string {
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return fallback
+	}
+	if len(text) > maxHookOutput {
+		text = text[:maxHookOutput] + "..."
+	}
+	return text
+}
+
+// Hooks returns the transition hook store
+func (m *StateManager) Hooks() *StateHooks {
+	return m.hooks
+}
+
+// AddValidator registers an in-process check run before every validated transition
+func (m *StateManager) AddValidator(v TransitionValidator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validators = append(m.validators, v)
+}
+
+// checkTransition runs validators and veto hooks, merging hook annotations into
+// event.Details. Veto hooks fail closed: a hook that errors or times out rejects
+// the transition (use ForceState to bypass). Caller must not hold m.mu: hooks
+// can take up to their timeout.
+func (m *StateManager) checkTransition(event *TransitionEvent, validators []TransitionValidator) error {
+	for _, v := range validators {
+		if err := v(*event); err != nil {
+			return &TransitionVetoedError{GoalID: event.GoalID, From: event.From, To: event.To, Reason: err.Error()}
+		}
+	}
+
+	for _, hook := range m.hooks.matching(event.From, event.To, true) {
+		result, err := hook.Run(m.dir, *event)
+		if err != nil {
+			return &TransitionVetoedError{GoalID: event.GoalID, From: event.From, To: event.To, HookID: hook.ID, Reason: err.Error()}
+		}
+		if result.Allow != nil && !*result.Allow {
+			reason := result.Reason
+			if reason == "" {
+				reason = "rejected"
+			}
+			return &TransitionVetoedError{GoalID: event.GoalID, From: event.From, To: event.To, HookID: hook.ID, Reason: reason}
+		}
+		if len(result.Details) > 0 {
+			if event.Details == nil {
+				event.Details = make(map[string]string)
+			}
+			for k, v := range result.Details {
+				event.Details[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// notifyHooks runs non-veto hooks for a recorded transition in the background
+func (m *StateManager) notifyHooks(event TransitionEvent) {
+	for _, hook := range m.hooks.matching(event.From, event.To, false) {
+		go func(hook *TransitionHook) {
+			if _, err := hook.Run(m.dir, event); err != nil {
+				log.Printf("[STATE] Hook %s failed for goal %s (%s → %s): %v", hook.ID, event.GoalID, event.From, event.To, err)
+			}
+		}(hook)
+	}
+}
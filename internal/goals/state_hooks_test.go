@@ -0,0 +1,197 @@
+package goals
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupHookGoal creates a goal in the merge-ready pushing state
+func setupHookGoal(t *testing.T) (*StateManager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	goalsDir := filepath.Join(dir, "goals", "active")
+	os.MkdirAll(goalsDir, 0755)
+	os.WriteFile(filepath.Join(goalsDir, "abc1234.md"), []byte("# Goal"), 0644)
+
+	sm := NewStateManager(dir)
+	if err := sm.Transition("abc1234", StateWorking, "start", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Transition("abc1234", StatePushing, "push", nil); err != nil {
+		t.Fatal(err)
+	}
+	return sm, dir
+}
+
+func TestTransitionHookValidate(t *testing.T) {
+	for _, bad := range []TransitionHook{
+		{},
+		{Command: "true", URL: "http://x"},
+		{URL: "ftp://x"},
+		{Command: "true", To: []GoalState{"bogus"}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestStateHooks_CommandVeto(t *testing.T) {
+	sm, _ := setupHookGoal(t)
+
+	err := sm.Hooks().Add(&TransitionHook{
+		ID:      "freeze",
+		To:      []GoalState{StateMerging},
+		Command: `echo "merge freeze until Monday"; exit 1`,
+		Veto:    true,
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	err = sm.Transition("abc1234", StateMerging, "merge", nil)
+	var vetoed *TransitionVetoedError
+	if !errors.As(err, &vetoed) || !errors.Is(err, ErrTransitionVetoed) {
+		t.Fatalf("expected veto, got %v", err)
+	}
+	if vetoed.HookID != "freeze" || vetoed.Reason != "merge freeze until Monday" {
+		t.Errorf("unexpected veto: %+v", vetoed)
+	}
+	if state, _ := sm.GetState("abc1234"); state != StatePushing {
+		t.Errorf("expected state unchanged, got %s", state)
+	}
+
+	// Hooks only apply to matching transitions
+	if err := sm.Transition("abc1234", StateWorking, "back to work", nil); err != nil {
+		t.Errorf("unmatched transition should pass: %v", err)
+	}
+
+	// ForceState bypasses veto hooks
+	if err := sm.ForceState("abc1234", StateMerging, "hotfix"); err != nil {
+		t.Errorf("ForceState should bypass hooks: %v", err)
+	}
+
+	if err := sm.Hooks().Delete("freeze"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := sm.Hooks().Delete("freeze"); !errors.Is(err, ErrHookNotFound) {
+		t.Errorf("expected ErrHookNotFound, got %v", err)
+	}
+}
+
+func TestStateHooks_CommandAnnotates(t *testing.T) {
+	sm, _ := setupHookGoal(t)
+
+	sm.Hooks().Add(&TransitionHook{
+		Command: `echo "{\"details\":{\"ticket\":\"JIRA-$VEGA_GOAL_ID\"}}"`,
+		Veto:    true,
+	})
+
+	if err := sm.Transition("abc1234", StateMerging, "merge", map[string]string{"branch": "main"}); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	event, _ := sm.GetLastEvent("abc1234")
+	if event.Details["ticket"] != "JIRA-abc1234" || event.Details["branch"] != "main" {
+		t.Errorf("expected hook annotation merged into details, got %v", event.Details)
+	}
+}
+
+func TestStateHooks_HTTP(t *testing.T) {
+	sm, _ := setupHookGoal(t)
+
+	notified := make(chan TransitionEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event TransitionEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		switch r.URL.Path {
+		case "/validate":
+			fmt.Fprint(w, `{"allow":false,"reason":"tests not green"}`)
+		case "/sync":
+			notified <- event
+		}
+	}))
+	defer server.Close()
+
+	sm.Hooks().Add(&TransitionHook{ID: "ci", To: []GoalState{StateMerging}, URL: server.URL + "/validate", Veto: true})
+	sm.Hooks().Add(&TransitionHook{ID: "tracker", URL: server.URL + "/sync"})
+
+	err := sm.Transition("abc1234", StateMerging, "merge", nil)
+	var vetoed *TransitionVetoedError
+	if !errors.As(err, &vetoed) || vetoed.Reason != "tests not green" {
+		t.Fatalf("expected veto from HTTP hook, got %v", err)
+	}
+
+	if err := sm.TransitionWithUser("abc1234", StateWorking, "rework", "alice", nil); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	select {
+	case event := <-notified:
+		if event.GoalID != "abc1234" || event.From != StatePushing || event.To != StateWorking || event.User != "alice" {
+			t.Errorf("unexpected notification: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("notify hook was not called")
+	}
+}
+
+func TestStateManager_Validator(t *testing.T) {
+	sm, _ := setupHookGoal(t)
+
+	sm.AddValidator(func(e TransitionEvent) error {
+		if e.To == StateMerging && e.User == "" {
+			return errors.New("merges need a user")
+		}
+		return nil
+	})
+
+	if err := sm.Transition("abc1234", StateMerging, "merge", nil); !errors.Is(err, ErrTransitionVetoed) {
+		t.Errorf("expected validator veto, got %v", err)
+	}
+	if err := sm.TransitionWithUser("abc1234", StateMerging, "merge", "alice", nil); err != nil {
+		t.Errorf("expected transition with user to pass, got %v", err)
+	}
+}
+
+func TestStateHooks_SlowVetoRunsUnlocked(t *testing.T) {
+	sm, _ := setupHookGoal(t)
+
+	called := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"allow":true}`)
+	}))
+	defer server.Close()
+	defer close(release)
+	sm.Hooks().Add(&TransitionHook{ID: "slow", To: []GoalState{StateMerging}, URL: server.URL, Veto: true})
+
+	done := make(chan error, 1)
+	go func() { done <- sm.Transition("abc1234", StateMerging, "merge", nil) }()
+	<-called
+
+	// Other transitions go ahead while the hook runs
+	if err := sm.Transition("def5678", StateWorking, "start", nil); err != nil {
+		t.Fatalf("expected another goal's transition not blocked, got %v", err)
+	}
+	if err := sm.ForceState("abc1234", StateWorking, "rework"); err != nil {
+		t.Fatal(err)
+	}
+	release <- struct{}{}
+
+	// The goal moved on meanwhile, so the checked transition no longer applies
+	var invalid *InvalidTransitionError
+	if err := <-done; !errors.As(err, &invalid) || invalid.From != StateWorking {
+		t.Errorf("expected the transition re-checked from the new state, got %v", err)
+	}
+	if state, _ := sm.GetState("abc1234"); state != StateWorking {
+		t.Errorf("expected the forced state kept, got %s", state)
+	}
+}
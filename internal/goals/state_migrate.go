@@ -0,0 +1,292 @@
+package goals
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// ErrStateExists is returned when backfilling a goal that already has state history
+var ErrStateExists = errors.New("goal already has state history")
+
+// StateMigration describes the initial state inferred for a legacy goal
+type StateMigration struct {
+	GoalID   string    `json:"goal_id"`
+	Title    string    `json:"title,omitempty"`
+	State    GoalState `json:"state,omitempty"`
+	Since    time.Time `json:"since,omitempty"`
+	Evidence []string  `json:"evidence,omitempty"` // Why this state was inferred
+	Skipped  string    `json:"skipped,omitempty"`  // Why the goal was left alone
+	Written  bool      `json:"written"`
+}
+
+// MigrateStatesOptions controls MigrateStates
+type MigrateStatesOptions struct {
+	GoalIDs []string // Limit the migration to these goals (default: all)
+	DryRun  bool     // Infer states without writing .state.jsonl files
+}
+
+// MigrateStates backfills .state.jsonl files for goals created before the
+// state machine. The initial state is inferred from the registry status,
+// worktree and branch existence and the goal's executor history. Goals that
+// already have state history are skipped.
+func MigrateStates(dir string, opts MigrateStatesOptions) ([]StateMigration, error) {
+	entries, err := NewRegistry(dir).Load()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*RegistryEntry, len(entries))
+	for i := range entries {
+		byID[entries[i].ID] = &entries[i]
+	}
+
+	ids := opts.GoalIDs
+	if len(ids) == 0 {
+		ids = listGoalIDs(dir, entries)
+	}
+
+	sm := NewStateManager(dir)
+	var results []StateMigration
+	for _, id := range ids {
+		m := inferInitialState(dir, id, byID[id])
+		if m.Skipped == "" {
+			if events, _ := sm.GetHistory(id); len(events) > 0 {
+				m.Skipped = "already has state history"
+			}
+		}
+		if m.Skipped == "" && !opts.DryRun {
+			err := sm.Backfill(id, StateEvent{
+				Timestamp: m.Since,
+				State:     m.State,
+				Reason:    "[MIGRATED] " + strings.Join(m.Evidence, "; "),
+				Details:   map[string]string{"migrated": "true"},
+			})
+			if err != nil {
+				return results, fmt.Errorf("goal %s: %w", id, err)
+			}
+			m.Written = true
+		}
+		results = append(results, m)
+	}
+	return results, nil
+}
+
+// Backfill writes the first state event for a goal without running
+// validators or hooks. It fails with ErrStateExists if the goal has history.
+func (m *StateManager) Backfill(goalID string, event StateEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !event.State.IsValid() {
+		return fmt.Errorf("invalid state: %s", event.State)
+	}
+	if events, _ := m.readEvents(goalID); len(events) > 0 {
+		return ErrStateExists
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	event.Timestamp = event.Timestamp.UTC()
+	event.PrevState = ""
+	return m.appendEvent(goalID, event)
+}
+
+// inferInitialState works out which state a legacy goal is in
+func inferInitialState(dir, goalID string, entry *RegistryEntry) StateMigration {
+	m := StateMigration{GoalID: goalID}
+
+	p := NewParser(dir)
+	goalFile, location := p.findGoalFile(goalID)
+	if goalFile == "" {
+		m.Skipped = "goal file not found"
+		return m
+	}
+	detail, err := p.ParseGoalDetail(goalID)
+	if err != nil {
+		m.Skipped = "failed to parse goal file: " + err.Error()
+		return m
+	}
+	m.Title = detail.Title
+
+	status, source := location, "goal file in "+location
+	if entry != nil {
+		if entry.Title != "" {
+			m.Title = entry.Title
+		}
+		if entry.Status != location {
+			m.Evidence = append(m.Evidence, fmt.Sprintf("registry says %s, goal file is in %s", entry.Status, location))
+		}
+		status, source = entry.Status, "registry status "+entry.Status
+	}
+
+	history := readHistorySummary(dir, goalID)
+	m.Since = history.last
+	if m.Since.IsZero() {
+		if info, err := os.Stat(goalFile); err == nil {
+			m.Since = info.ModTime()
+		}
+	}
+
+	switch status {
+	case "completed":
+		m.State = StateDone
+		m.Evidence = append(m.Evidence, source)
+		if entry != nil {
+			if t, ok := parseRegistryTime(entry.CompletedAt); ok {
+				m.Since = t
+			}
+		}
+		return m
+	case "iced":
+		m.State = StateIced
+		m.Evidence = append(m.Evidence, source)
+		return m
+	}
+
+	m.Evidence = append(m.Evidence, source)
+
+	if wt := findGoalWorktree(dir, detail); wt != "" {
+		m.State = StateWorking
+		m.Evidence = append(m.Evidence, "worktree exists at "+wt)
+		return m
+	}
+
+	if branch := findGoalBranchName(dir, detail); branch != "" {
+		m.State = StateFailed
+		m.Evidence = append(m.Evidence, fmt.Sprintf("branch %s exists but worktree is missing", branch))
+		return m
+	}
+
+	if history.sessions > 0 {
+		m.State = StateFailed
+		m.Evidence = append(m.Evidence, fmt.Sprintf("%d executor session(s) recorded but no worktree or branch", history.sessions))
+		return m
+	}
+
+	m.State = StatePending
+	m.Evidence = append(m.Evidence, "no worktree, branch or executor history")
+	return m
+}
+
+// listGoalIDs returns registry goals plus any goal files missing from the registry
+func listGoalIDs(dir string, entries []RegistryEntry) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, e := range entries {
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			ids = append(ids, e.ID)
+		}
+	}
+
+	var extra []string
+	l := layout.New(dir)
+	for _, sub := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+		for id := range l.GoalFiles(sub) {
+			if !seen[id] {
+				seen[id] = true
+				extra = append(extra, id)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(ids, extra...)
+}
+
+// findGoalWorktree returns the goal's worktree path if it exists on disk
+func findGoalWorktree(dir string, detail *GoalDetail) string {
+	if detail.Worktree != nil && detail.Worktree.Path != "" {
+		if fileExists(filepath.Join(dir, detail.Worktree.Path)) {
+			return detail.Worktree.Path
+		}
+	}
+	for _, project := range detail.Projects {
+		matches, _ := filepath.Glob(layout.New(dir).Workspace(project, "goal-"+detail.ID+"-*"))
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				rel, _ := filepath.Rel(dir, match)
+				return rel
+			}
+		}
+	}
+	return ""
+}
+
+// findGoalBranchName returns the goal's branch if it exists in a project's worktree-base
+func findGoalBranchName(dir string, detail *GoalDetail) string {
+	pattern := "goal-" + detail.ID + "-*"
+	if detail.Worktree != nil && detail.Worktree.Branch != "" {
+		pattern = detail.Worktree.Branch
+	}
+	for _, project := range detail.Projects {
+		base := layout.New(dir).WorktreeBase(project)
+		if !fileExists(base) {
+			continue
+		}
+		output, err := exec.Command("git", "-C", base, "branch", "--list", pattern).Output()
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if branch := strings.TrimSpace(strings.TrimLeft(line, "*+ ")); branch != "" {
+				return branch
+			}
+		}
+	}
+	return ""
+}
+
+// historySummary is what the migration needs from a goal's executor history
+type historySummary struct {
+	sessions int
+	last     time.Time
+}
+
+// readHistorySummary reads <vega-dir>/.vega-hub-history/goal-<id>.jsonl
+func readHistorySummary(dir, goalID string) historySummary {
+	var summary historySummary
+
+	file, err := os.Open(filepath.Join(dir, ".vega-hub-history", "goal-"+goalID+".jsonl"))
+	if err != nil {
+		return summary
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Timestamp time.Time `json:"timestamp"`
+			Type      string    `json:"type"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Type == "session_start" {
+			summary.sessions++
+		}
+		if entry.Timestamp.After(summary.last) {
+			summary.last = entry.Timestamp
+		}
+	}
+	return summary
+}
+
+// parseRegistryTime parses the timestamps and dates used in registry entries
+func parseRegistryTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,88 @@
+package goals
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateStates(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"active", "iced", "history"} {
+		os.MkdirAll(filepath.Join(dir, "goals", sub), 0755)
+	}
+	writeGoal := func(sub, id string) {
+		content := "# Goal #" + id + ": Goal " + id + "\n\n## Project(s)\n\n- **my-api**: backend\n"
+		os.WriteFile(filepath.Join(dir, "goals", sub, id+".md"), []byte(content), 0644)
+	}
+	writeGoal("active", "aaa0001") // worktree exists
+	writeGoal("active", "aaa0002") // nothing yet
+	writeGoal("active", "aaa0003") // sessions but no worktree
+	writeGoal("iced", "aaa0004")
+	writeGoal("history", "aaa0005")
+	writeGoal("active", "aaa0006") // already migrated
+
+	os.MkdirAll(filepath.Join(dir, "workspaces", "my-api", "goal-aaa0001-login"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".vega-hub-history"), 0755)
+	os.WriteFile(filepath.Join(dir, ".vega-hub-history", "goal-aaa0003.jsonl"),
+		[]byte(`{"timestamp":"2025-01-02T03:04:05Z","goal_id":"aaa0003","type":"session_start"}`+"\n"), 0644)
+
+	NewRegistry(dir).Save([]RegistryEntry{
+		{ID: "aaa0005", Title: "Shipped", Status: "completed", CompletedAt: "2025-02-01"},
+	})
+
+	sm := NewStateManager(dir)
+	sm.Transition("aaa0006", StateWorking, "start", nil)
+
+	dry, err := MigrateStates(dir, MigrateStatesOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("MigrateStates failed: %v", err)
+	}
+	if len(dry) != 6 || dry[0].GoalID != "aaa0005" {
+		t.Fatalf("expected registry goals first, then files: %+v", dry)
+	}
+	if events, _ := sm.GetHistory("aaa0001"); len(events) != 0 {
+		t.Fatal("dry run should not write state files")
+	}
+
+	results, err := MigrateStates(dir, MigrateStatesOptions{})
+	if err != nil {
+		t.Fatalf("MigrateStates failed: %v", err)
+	}
+
+	want := map[string]GoalState{
+		"aaa0001": StateWorking,
+		"aaa0002": StatePending,
+		"aaa0003": StateFailed,
+		"aaa0004": StateIced,
+		"aaa0005": StateDone,
+	}
+	for _, m := range results {
+		if m.GoalID == "aaa0006" {
+			if m.Skipped == "" || m.Written {
+				t.Errorf("expected goal with history to be skipped: %+v", m)
+			}
+			continue
+		}
+		if m.State != want[m.GoalID] || !m.Written || len(m.Evidence) == 0 {
+			t.Errorf("%s: expected %s, got %+v", m.GoalID, want[m.GoalID], m)
+		}
+		if state, _ := sm.GetState(m.GoalID); state != want[m.GoalID] {
+			t.Errorf("%s: state file has %s", m.GoalID, state)
+		}
+	}
+
+	last, _ := sm.GetLastEvent("aaa0005")
+	if last == nil || last.Timestamp.Format("2006-01-02") != "2025-02-01" || last.Details["migrated"] != "true" {
+		t.Errorf("unexpected backfilled event: %+v", last)
+	}
+
+	// Migrated goals keep moving through the normal state machine
+	if err := sm.Transition("aaa0002", StateBranching, "branch", nil); err != nil {
+		t.Errorf("transition after migration failed: %v", err)
+	}
+	if err := sm.Backfill("aaa0002", StateEvent{State: StateWorking}); !errors.Is(err, ErrStateExists) {
+		t.Errorf("expected ErrStateExists, got %v", err)
+	}
+}
@@ -127,9 +127,18 @@ func TestCanTransition(t *testing.T) {
 		{StateConflict, StateFailed, true},
 		{StateConflict, StateWorking, true},
 		{StateIced, StateWorking, true}, // Resume
+		{StateWorking, StatePaused, true},
+		{StatePaused, StateWorking, true}, // Resume executor
+		{StatePaused, StateIced, true},
 		{StateFailed, StatePending, true}, // Retry
 		{StateFailed, StateWorking, true},
 		{StateFailed, StateBranching, true},
+		{StateWorking, StateChangesRequested, true},
+		{StateWorking, StateApproved, true},
+		{StateChangesRequested, StateWorking, true}, // Next executor run
+		{StateChangesRequested, StateApproved, true},
+		{StateApproved, StatePushing, true}, // Complete
+		{StateApproved, StateChangesRequested, true},
 
 		// Invalid transitions
 		{StatePending, StateWorking, false},      // Must go through branching
@@ -141,6 +150,10 @@ func TestCanTransition(t *testing.T) {
 		{StateDone, StatePending, false},
 		{StateIced, StateDone, false},
 		{StateIced, StatePending, false},
+		{StatePaused, StateDone, false},
+		{StatePending, StatePaused, false},
+		{StateChangesRequested, StatePushing, false}, // Changes must be addressed first
+		{StatePending, StateApproved, false},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,104 @@
+package goals
+
+import "fmt"
+
+// ChildProgress is one child's progress within an umbrella goal
+type ChildProgress struct {
+	GoalID          string    `json:"goal_id"`
+	Title           string    `json:"title"`
+	Status          string    `json:"status"` // "active", "iced", "completed"
+	State           GoalState `json:"state,omitempty"`
+	CompletedPhases int       `json:"completed_phases"`
+	TotalPhases     int       `json:"total_phases"`
+	Done            bool      `json:"done"`
+}
+
+// ChildrenStatus aggregates the completion of an umbrella goal's direct
+// children, so a release goal reads as a checklist of its parts
+type ChildrenStatus struct {
+	Total    int             `json:"total"`
+	Done     int             `json:"done"`
+	Active   int             `json:"active"`
+	Iced     int             `json:"iced"`
+	AllDone  bool            `json:"all_done"`
+	Progress float64         `json:"progress"` // 0.0-1.0: done children count fully, others by completed phases
+	Children []ChildProgress `json:"children"`
+}
+
+// ChildrenStatus returns the aggregated status of a goal's children (nil if
+// it has none). Children whose goal file can't be read count as active.
+func (m *HierarchyManager) ChildrenStatus(parentID string) (*ChildrenStatus, error) {
+	children, err := m.GetChildren(parentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(children) == 0 {
+		return nil, nil
+	}
+
+	parser := NewParser(m.dir)
+	sm := NewStateManager(m.dir)
+	status := &ChildrenStatus{Total: len(children)}
+	var progress float64
+	for _, id := range children {
+		child := ChildProgress{GoalID: id, Status: "active"}
+		if detail, err := parser.ParseGoalDetail(id); err == nil {
+			child.Title = detail.Title
+			child.Status = detail.Status
+			child.TotalPhases = len(detail.Phases)
+			for _, phase := range detail.Phases {
+				if phase.Status == "complete" {
+					child.CompletedPhases++
+				}
+			}
+		}
+		if event, err := sm.GetLastEvent(id); err == nil && event != nil {
+			child.State = event.State
+		}
+		child.Done = child.Status == "completed" || child.State == StateDone
+
+		switch {
+		case child.Done:
+			status.Done++
+			progress++
+		case child.Status == "iced":
+			status.Iced++
+		default:
+			status.Active++
+		}
+		if !child.Done && child.TotalPhases > 0 {
+			progress += float64(child.CompletedPhases) / float64(child.TotalPhases)
+		}
+		status.Children = append(status.Children, child)
+	}
+	status.AllDone = status.Done == status.Total
+	status.Progress = progress / float64(status.Total)
+	return status, nil
+}
+
+// CheckChildren returns an error naming the active children that keep an
+// umbrella goal from completing (iced children don't block)
+func (m *HierarchyManager) CheckChildren(parentID string) error {
+	status, err := m.ChildrenStatus(parentID)
+	if err != nil || status == nil || status.Active == 0 {
+		return err
+	}
+	var active []string
+	for _, child := range status.Children {
+		if !child.Done && child.Status != "iced" {
+			active = append(active, child.GoalID)
+		}
+	}
+	return &ActiveChildrenError{GoalID: parentID, Children: active}
+}
+
+// ActiveChildrenError is returned when completing a goal whose children are
+// still active
+type ActiveChildrenError struct {
+	GoalID   string
+	Children []string
+}
+
+func (e *ActiveChildrenError) Error() string {
+	return fmt.Sprintf("goal %s has %d active child goal(s): %v", e.GoalID, len(e.Children), e.Children)
+}
@@ -0,0 +1,54 @@
+package goals
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChildrenStatus(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"active", "iced", "history"} {
+		os.MkdirAll(filepath.Join(dir, "goals", sub), 0755)
+	}
+	hm := NewHierarchyManager(dir)
+
+	createTestGoal(t, dir, "abc1234", "active")
+	if status, err := hm.ChildrenStatus("abc1234"); err != nil || status != nil {
+		t.Fatalf("expected no status without children, got %+v (%v)", status, err)
+	}
+	if err := hm.CheckChildren("abc1234"); err != nil {
+		t.Errorf("expected a goal without children completable, got %v", err)
+	}
+
+	// One child halfway through, one done, one iced
+	halfway := "# Goal #abc1234.1: Backend\n\n## Phases\n\n### Phase 1: API\n- [x] Routes\n\n### Phase 2: Docs\n- [ ] README\n"
+	os.WriteFile(filepath.Join(dir, "goals", "active", "abc1234.1.md"), []byte(halfway), 0644)
+	createTestGoal(t, dir, "abc1234.2", "completed")
+	createTestGoal(t, dir, "abc1234.3", "iced")
+	for _, id := range []string{"abc1234.1", "abc1234.2", "abc1234.3"} {
+		if err := hm.CreateChildGoal(id, "abc1234"); err != nil {
+			t.Fatalf("CreateChildGoal(%s): %v", id, err)
+		}
+	}
+
+	status, err := hm.ChildrenStatus("abc1234")
+	if err != nil {
+		t.Fatalf("ChildrenStatus: %v", err)
+	}
+	if status.Total != 3 || status.Done != 1 || status.Active != 1 || status.Iced != 1 || status.AllDone {
+		t.Errorf("unexpected counts %+v", status)
+	}
+	if len(status.Children) != 3 || status.Children[0].Title != "Backend" || status.Children[0].CompletedPhases != 1 || status.Children[0].TotalPhases != 2 {
+		t.Errorf("unexpected per-child progress %+v", status.Children)
+	}
+	if want := 1.5 / 3; status.Progress != want {
+		t.Errorf("Progress = %v, want %v", status.Progress, want)
+	}
+
+	var active *ActiveChildrenError
+	if err := hm.CheckChildren("abc1234"); !errors.As(err, &active) || len(active.Children) != 1 || active.Children[0] != "abc1234.1" {
+		t.Errorf("expected the active child to block completion, got %v", err)
+	}
+}
@@ -0,0 +1,354 @@
+package goals
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// Validation checks, in the order Validate runs them
+const (
+	CheckGoalFiles = "goal_files" // Every goal file has a "# Goal #<id>: <title>" heading for its ID
+	CheckGoalIDs   = "goal_ids"   // IDs and aliases are well-formed and unique, each goal has one file
+	CheckRegistry  = "registry"   // Registry rows match the goal files
+	CheckHierarchy = "hierarchy"  // Parent links exist, are acyclic and within MaxHierarchyDepth
+	CheckStates    = "states"     // State files hold valid states and transitions
+)
+
+// Severity of a validation finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The vega directory is inconsistent
+	SeverityWarning Severity = "warning" // Suspicious, but vega-hub copes
+)
+
+// Finding is one problem Validate found
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	GoalID   string   `json:"goal_id,omitempty"`
+	Path     string   `json:"path,omitempty"` // Relative to the vega-missile directory
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport is the result of Validate. Valid is false when any
+// finding is an error; warnings alone leave it valid.
+type ValidationReport struct {
+	Valid    bool      `json:"valid"`
+	Goals    int       `json:"goals"` // Goal files checked
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Findings []Finding `json:"findings"`
+}
+
+var goalHeadingRe = regexp.MustCompile(`^# Goal #?([0-9a-f]+(?:\.[0-9]+)*): (.+)$`)
+
+// validator collects findings for one Validate run
+type validator struct {
+	layout *layout.Layout
+	report *ValidationReport
+	files  map[string][]goalFileRef // Goal files by ID
+}
+
+type goalFileRef struct {
+	Path   string
+	Status string
+}
+
+// Validate checks the integrity of a vega-missile directory without changing
+// it: goal files, goal IDs, the registry, hierarchy metadata and state files.
+// Findings are sorted by check, then goal and path.
+func Validate(vegaDir string) *ValidationReport {
+	v := &validator{
+		layout: layout.New(vegaDir),
+		report: &ValidationReport{Findings: []Finding{}},
+		files:  make(map[string][]goalFileRef),
+	}
+	v.checkGoalFiles()
+	v.checkGoalIDs()
+	v.checkRegistry(vegaDir)
+	v.checkHierarchy()
+	v.checkStates()
+
+	sort.SliceStable(v.report.Findings, func(i, j int) bool {
+		a, b := v.report.Findings[i], v.report.Findings[j]
+		if a.Check != b.Check {
+			return checkOrder(a.Check) < checkOrder(b.Check)
+		}
+		if a.GoalID != b.GoalID {
+			return a.GoalID < b.GoalID
+		}
+		return a.Path < b.Path
+	})
+	v.report.Valid = v.report.Errors == 0
+	return v.report
+}
+
+func checkOrder(check string) int {
+	for i, c := range []string{CheckGoalFiles, CheckGoalIDs, CheckRegistry, CheckHierarchy, CheckStates} {
+		if c == check {
+			return i
+		}
+	}
+	return -1
+}
+
+func (v *validator) add(f Finding) {
+	if rel, ok := v.layout.Rel(f.Path); ok {
+		f.Path = filepath.ToSlash(rel)
+	}
+	if f.Severity == SeverityError {
+		v.report.Errors++
+	} else {
+		v.report.Warnings++
+	}
+	v.report.Findings = append(v.report.Findings, f)
+}
+
+// checkGoalFiles reads every goal file's heading
+func (v *validator) checkGoalFiles() {
+	for _, dir := range v.layout.GoalDirs() {
+		for id, path := range v.layout.GoalFiles(dir.Name) {
+			v.files[id] = append(v.files[id], goalFileRef{Path: path, Status: dir.Status})
+			v.report.Goals++
+
+			heading, err := goalHeading(path)
+			switch {
+			case err != nil:
+				v.add(Finding{Check: CheckGoalFiles, Severity: SeverityError, GoalID: id, Path: path,
+					Message: fmt.Sprintf("cannot read goal file: %v", err)})
+			case heading == nil:
+				v.add(Finding{Check: CheckGoalFiles, Severity: SeverityError, GoalID: id, Path: path,
+					Message: `no "# Goal #<id>: <title>" heading`})
+			case heading[1] != id:
+				v.add(Finding{Check: CheckGoalFiles, Severity: SeverityError, GoalID: id, Path: path,
+					Message: fmt.Sprintf("heading names goal %s, but the file is for goal %s", heading[1], id)})
+			}
+		}
+	}
+}
+
+// goalHeading returns the goalHeadingRe match of a goal file's first
+// level-one heading, or nil if it has none or it doesn't match
+func goalHeading(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "# ") {
+			return goalHeadingRe.FindStringSubmatch(line), nil
+		}
+	}
+	return nil, scanner.Err()
+}
+
+// checkGoalIDs checks the IDs of goal files
+func (v *validator) checkGoalIDs() {
+	for id, refs := range v.files {
+		if err := validateGoalID(id); err != nil {
+			v.add(Finding{Check: CheckGoalIDs, Severity: SeverityError, GoalID: id, Path: refs[0].Path,
+				Message: err.Error()})
+		}
+		for _, ref := range refs[1:] {
+			v.add(Finding{Check: CheckGoalIDs, Severity: SeverityError, GoalID: id, Path: ref.Path,
+				Message: fmt.Sprintf("duplicate goal file; goal %s is also in %s", id, v.rel(refs[0].Path))})
+		}
+	}
+}
+
+// validateGoalID accepts hash IDs, their hierarchical children and legacy
+// numeric IDs
+func validateGoalID(id string) error {
+	if !strings.Contains(id, ".") && isValidGoalID(id) {
+		return nil
+	}
+	return ValidateHierarchicalID(id)
+}
+
+func (v *validator) rel(path string) string {
+	if rel, ok := v.layout.Rel(path); ok {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// checkRegistry matches registry rows with goal files. Only active and iced
+// goal files must be registered: completed ones may predate the registry.
+func (v *validator) checkRegistry(vegaDir string) {
+	entries, err := NewParser(vegaDir).ParseRegistry()
+	if err != nil {
+		v.add(Finding{Check: CheckRegistry, Severity: SeverityError, Path: v.layout.Registry(), Message: err.Error()})
+		return
+	}
+
+	registered := make(map[string]bool)
+	aliases := make(map[string]string)
+	for _, e := range entries {
+		if e.Alias != "" {
+			if other, ok := aliases[e.Alias]; ok {
+				v.add(Finding{Check: CheckGoalIDs, Severity: SeverityError, GoalID: e.ID, Path: v.layout.Registry(),
+					Message: fmt.Sprintf("alias %q is also used by goal %s", e.Alias, other)})
+			} else if _, err := NormalizeAlias(e.Alias); err != nil {
+				v.add(Finding{Check: CheckGoalIDs, Severity: SeverityError, GoalID: e.ID, Path: v.layout.Registry(),
+					Message: err.Error()})
+			}
+			aliases[e.Alias] = e.ID
+		}
+		if registered[e.ID] {
+			v.add(Finding{Check: CheckRegistry, Severity: SeverityError, GoalID: e.ID, Path: v.layout.Registry(),
+				Message: fmt.Sprintf("goal %s is registered more than once", e.ID)})
+			continue
+		}
+		registered[e.ID] = true
+		if err := validateGoalID(e.ID); err != nil {
+			v.add(Finding{Check: CheckGoalIDs, Severity: SeverityError, GoalID: e.ID, Path: v.layout.Registry(),
+				Message: err.Error()})
+		}
+
+		refs := v.files[e.ID]
+		switch {
+		case len(refs) == 0:
+			v.add(Finding{Check: CheckRegistry, Severity: SeverityError, GoalID: e.ID, Path: v.layout.Registry(),
+				Message: fmt.Sprintf("goal %s is registered as %s but has no goal file", e.ID, e.Status)})
+		case refs[0].Status != e.Status:
+			v.add(Finding{Check: CheckRegistry, Severity: SeverityError, GoalID: e.ID, Path: refs[0].Path,
+				Message: fmt.Sprintf("goal %s is registered as %s but its file is in the %s folder", e.ID, e.Status, refs[0].Status)})
+		}
+	}
+
+	for id, refs := range v.files {
+		if registered[id] || (refs[0].Status != "active" && refs[0].Status != "iced") {
+			continue
+		}
+		v.add(Finding{Check: CheckRegistry, Severity: SeverityWarning, GoalID: id, Path: refs[0].Path,
+			Message: fmt.Sprintf("goal %s has a goal file but is not in the registry", id)})
+	}
+}
+
+// checkHierarchy follows each goal's parent links
+func (v *validator) checkHierarchy() {
+	parents := make(map[string]string)
+	paths := make(map[string]string)
+	for _, dir := range v.layout.GoalDirs() {
+		for id, path := range v.layout.Sidecars(dir.Name, hierarchySuffix) {
+			paths[id] = path
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var meta HierarchyMetadata
+			if err := json.Unmarshal(data, &meta); err != nil {
+				v.add(Finding{Check: CheckHierarchy, Severity: SeverityError, GoalID: id, Path: path,
+					Message: fmt.Sprintf("malformed hierarchy metadata: %v", err)})
+				continue
+			}
+			if meta.ParentID != "" {
+				parents[id] = meta.ParentID
+			}
+		}
+	}
+
+	for id, parent := range parents {
+		path := paths[id]
+		if len(v.files[parent]) == 0 {
+			v.add(Finding{Check: CheckHierarchy, Severity: SeverityWarning, GoalID: id, Path: path,
+				Message: fmt.Sprintf("parent goal %s has no goal file", parent)})
+		}
+		if !strings.HasPrefix(id, parent+".") {
+			v.add(Finding{Check: CheckHierarchy, Severity: SeverityWarning, GoalID: id, Path: path,
+				Message: fmt.Sprintf("goal %s is not a child ID of its parent %s", id, parent)})
+		}
+
+		// Walk up to the root; revisiting a goal is a cycle
+		seen := map[string]bool{id: true}
+		depth := 0
+		for p := parent; p != ""; p = parents[p] {
+			if seen[p] {
+				v.add(Finding{Check: CheckHierarchy, Severity: SeverityError, GoalID: id, Path: path,
+					Message: fmt.Sprintf("parent links of goal %s form a cycle through %s", id, p)})
+				break
+			}
+			seen[p] = true
+			depth++
+		}
+		if depth > MaxHierarchyDepth {
+			v.add(Finding{Check: CheckHierarchy, Severity: SeverityError, GoalID: id, Path: path,
+				Message: fmt.Sprintf("goal %s is nested %d levels deep (maximum %d)", id, depth, MaxHierarchyDepth)})
+		}
+	}
+}
+
+// checkStates replays every state file. Forced events are exempt from the
+// transition rules, as ForceState is.
+func (v *validator) checkStates() {
+	for _, dir := range v.layout.GoalDirs() {
+		for id, path := range v.layout.Sidecars(dir.Name, ".state.jsonl") {
+			v.checkStateFile(id, path)
+		}
+	}
+}
+
+func (v *validator) checkStateFile(id, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		v.add(Finding{Check: CheckStates, Severity: SeverityError, GoalID: id, Path: path,
+			Message: fmt.Sprintf("cannot read state file: %v", err)})
+		return
+	}
+	defer file.Close()
+
+	var current GoalState
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		invalid := func(format string, args ...interface{}) {
+			v.add(Finding{Check: CheckStates, Severity: SeverityError, GoalID: id, Path: path, Line: lineNum,
+				Message: fmt.Sprintf(format, args...)})
+		}
+
+		var event StateEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			invalid("malformed state event: %v", err)
+			continue
+		}
+		if !event.State.IsValid() {
+			invalid("unknown state %q", event.State)
+			continue
+		}
+		forced := event.Details["forced"] == "true"
+		switch {
+		case forced:
+		case current == "" && event.State != StatePending && event.State != StateWorking:
+			invalid("goal starts in state %s; the first state must be pending or working", event.State)
+		case current != "" && !CanTransition(current, event.State):
+			invalid("invalid transition from %s to %s", current, event.State)
+		}
+		if event.PrevState != "" && event.PrevState != current && current != "" {
+			v.add(Finding{Check: CheckStates, Severity: SeverityWarning, GoalID: id, Path: path, Line: lineNum,
+				Message: fmt.Sprintf("event says the previous state was %s, but it was %s", event.PrevState, current)})
+		}
+		current = event.State
+	}
+	if err := scanner.Err(); err != nil {
+		v.add(Finding{Check: CheckStates, Severity: SeverityError, GoalID: id, Path: path,
+			Message: fmt.Sprintf("cannot read state file: %v", err)})
+	}
+}
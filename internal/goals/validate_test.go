@@ -0,0 +1,92 @@
+package goals
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := setupTestDir(t)
+	registry := NewRegistry(dir)
+	for _, e := range []RegistryEntry{
+		{ID: "abc1234", Title: "Good goal", Status: "active"},
+		{ID: "abc1234.1", Title: "Child goal", Status: "active"},
+		{ID: "def5678", Title: "Registered, no file", Status: "active"},
+		{ID: "1234567", Title: "Iced in the registry", Status: "iced"},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	active := filepath.Join(dir, "goals", "active")
+	writeFile(t, filepath.Join(active, "abc1234.md"), "# Goal #abc1234: Good goal\n")
+	writeFile(t, filepath.Join(active, "abc1234.1.md"), "# Goal #abc1234.1: Child goal\n")
+	writeFile(t, filepath.Join(active, "abc1234.1.hierarchy.json"), `{"parent_id":"abc1234"}`)
+	writeFile(t, filepath.Join(active, "1234567.md"), "# Goal #7654321: Wrong heading\n")
+	writeFile(t, filepath.Join(active, "bad_id.md"), "no heading\n")
+	writeFile(t, filepath.Join(dir, "goals", "iced", "abc1234.md"), "# Goal #abc1234: Good goal\n")
+	writeFile(t, filepath.Join(active, "abc1234.state.jsonl"),
+		`{"ts":"2026-01-01T00:00:00Z","state":"pending"}`+"\n"+
+			`{"ts":"2026-01-01T00:01:00Z","state":"done","prev_state":"pending"}`+"\n"+
+			`{"ts":"2026-01-01T00:02:00Z","state":"working","prev_state":"done","details":{"forced":"true"}}`+"\n"+
+			`{"ts":"2026-01-01T00:03:00Z","state":"bogus"}`+"\n")
+	// A cycle: two goals naming each other as parent
+	writeFile(t, filepath.Join(active, "fff0000.1.hierarchy.json"), `{"parent_id":"fff0000.1.1"}`)
+	writeFile(t, filepath.Join(active, "fff0000.1.1.hierarchy.json"), `{"parent_id":"fff0000.1"}`)
+
+	report := Validate(dir)
+	if report.Valid {
+		t.Fatal("expected the directory to be invalid")
+	}
+
+	want := []struct {
+		check, goalID, contains string
+		severity                Severity
+	}{
+		{CheckGoalFiles, "1234567", "heading names goal 7654321", SeverityError},
+		{CheckGoalFiles, "bad_id", "no \"# Goal", SeverityError},
+		{CheckGoalIDs, "bad_id", "invalid base goal ID", SeverityError},
+		{CheckGoalIDs, "abc1234", "duplicate goal file", SeverityError},
+		{CheckRegistry, "def5678", "has no goal file", SeverityError},
+		{CheckRegistry, "1234567", "registered as iced but its file is in the active folder", SeverityError},
+		{CheckRegistry, "bad_id", "not in the registry", SeverityWarning},
+		{CheckHierarchy, "fff0000.1", "form a cycle", SeverityError},
+		{CheckStates, "abc1234", "invalid transition from pending to done", SeverityError},
+		{CheckStates, "abc1234", "unknown state \"bogus\"", SeverityError},
+	}
+	for _, w := range want {
+		found := false
+		for _, f := range report.Findings {
+			if f.Check == w.check && f.GoalID == w.goalID && f.Severity == w.severity && contains(f.Message, w.contains) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing %s %s finding for %s containing %q; findings: %+v", w.severity, w.check, w.goalID, w.contains, report.Findings)
+		}
+	}
+
+	for _, f := range report.Findings {
+		if f.GoalID == "abc1234.1" {
+			t.Errorf("unexpected finding for a valid child goal: %+v", f)
+		}
+		if f.Check == CheckStates && f.Line == 3 {
+			t.Errorf("forced transition reported: %+v", f)
+		}
+	}
+}
+
+func TestValidate_Clean(t *testing.T) {
+	dir := setupTestDir(t)
+	if err := NewRegistry(dir).Add(RegistryEntry{ID: "abc1234", Title: "Good goal", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "goals", "active", "abc1234.md"), "# Goal #abc1234: Good goal\n")
+	os.MkdirAll(filepath.Join(dir, "goals", "history"), 0755)
+
+	report := Validate(dir)
+	if !report.Valid || len(report.Findings) != 0 || report.Goals != 1 {
+		t.Errorf("expected a clean report, got %+v", report)
+	}
+}
@@ -0,0 +1,73 @@
+package goals
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetWorktreeField sets "- **<key>**: <value>" in the Worktree section of a
+// goal file (key as written there, e.g. "Path" or "Branch"). The line is
+// replaced if present, otherwise added to the end of the section. Goals
+// without a Worktree section are left alone.
+func SetWorktreeField(goalFile, key, value string) error {
+	content, err := os.ReadFile(goalFile)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	field := fmt.Sprintf("- **%s**:", key)
+	entry := fmt.Sprintf("%s %s", field, value)
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## Worktree") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	last := start // Last field line of the section
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+		if strings.HasPrefix(strings.ToLower(line), strings.ToLower(field)) {
+			if line == entry {
+				return nil
+			}
+			lines[i] = entry
+			return os.WriteFile(goalFile, []byte(strings.Join(lines, "\n")), 0644)
+		}
+		if strings.HasPrefix(line, "- **") {
+			last = i
+		}
+	}
+
+	lines = append(lines[:last+1], append([]string{entry}, lines[last+1:]...)...)
+	return os.WriteFile(goalFile, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// RemoveWorktreeSection drops the Worktree section of a goal file, e.g. before
+// the goal gets a worktree in another project
+func RemoveWorktreeSection(goalFile string) error {
+	content, err := os.ReadFile(goalFile)
+	if err != nil {
+		return err
+	}
+	var kept []string
+	inSection := false
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "## ") {
+			inSection = strings.HasPrefix(line, "## Worktree")
+		}
+		if !inSection {
+			kept = append(kept, line)
+		}
+	}
+	return os.WriteFile(goalFile, []byte(strings.Join(kept, "\n")), 0644)
+}
@@ -0,0 +1,160 @@
+package hub
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Activity kinds derived from transcripts
+const (
+	ActivityFileEdit = "file_edit" // A file was created or modified
+	ActivityCommand  = "command"   // A shell command was run
+	ActivityTestRun  = "test_run"  // A shell command that ran tests
+)
+
+// Activity statuses for commands and test runs
+const (
+	ActivityOK     = "ok"
+	ActivityFailed = "failed"
+)
+
+// maxActivityOutput caps the command output tail kept per activity
+const maxActivityOutput = 500
+
+// testCommandPattern recognizes common test runners
+var testCommandPattern = regexp.MustCompile(`\b(go test|npm (run )?test|yarn test|pnpm (run )?test|pytest|python -m pytest|cargo test|jest|vitest|make test|mvn test|gradle test|rspec|phpunit)\b`)
+
+// testFailurePattern recognizes failures in test output
+var testFailurePattern = regexp.MustCompile(`(?m)^(FAIL|--- FAIL)|\b[1-9]\d* (failed|failing)\b|^FAILED`)
+
+// SessionActivity is a structured record of something an executor did
+type SessionActivity struct {
+	Kind      string    `json:"kind"` // "file_edit", "command", "test_run"
+	SessionID string    `json:"session_id"`
+	Timestamp time.Time `json:"timestamp,omitempty"` // When it happened (from the transcript)
+
+	// file_edit
+	File  string `json:"file,omitempty"`
+	Tool  string `json:"tool,omitempty"`  // Last tool used on the file
+	Edits int    `json:"edits,omitempty"` // Number of edit calls on the file
+
+	// command / test_run
+	Command string `json:"command,omitempty"`
+	Status  string `json:"status,omitempty"` // "ok" or "failed" (empty if no result)
+	Output  string `json:"output,omitempty"` // Tail of the command output
+}
+
+// ExtractActivities derives file edits, commands and test runs from a transcript.
+// File edits are merged per file; commands and test runs are kept in order.
+func ExtractActivities(sessionID string, entries []TranscriptEntry) []SessionActivity {
+	results := make(map[string]*TranscriptEntry)
+	for i := range entries {
+		if entries[i].Kind == TranscriptToolResult {
+			results[entries[i].ToolID] = &entries[i]
+		}
+	}
+
+	var activities []SessionActivity
+	fileIndex := make(map[string]int) // file -> index in activities
+
+	for _, e := range entries {
+		if e.Kind != TranscriptToolCall {
+			continue
+		}
+
+		switch {
+		case fileEditTools[e.ToolName] && e.FilePath != "":
+			if i, ok := fileIndex[e.FilePath]; ok {
+				activities[i].Edits++
+				activities[i].Tool = e.ToolName
+				continue
+			}
+			fileIndex[e.FilePath] = len(activities)
+			activities = append(activities, SessionActivity{
+				Kind:      ActivityFileEdit,
+				SessionID: sessionID,
+				Timestamp: e.Timestamp,
+				File:      e.FilePath,
+				Tool:      e.ToolName,
+				Edits:     1,
+			})
+
+		case e.ToolName == "Bash":
+			command := strings.TrimSpace(e.Command)
+			a := SessionActivity{
+				Kind:      ActivityCommand,
+				SessionID: sessionID,
+				Timestamp: e.Timestamp,
+				Command:   command,
+			}
+			if testCommandPattern.MatchString(command) {
+				a.Kind = ActivityTestRun
+			}
+			if result, ok := results[e.ToolID]; ok {
+				a.Status = ActivityOK
+				if result.IsError || (a.Kind == ActivityTestRun && testFailurePattern.MatchString(result.Text)) {
+					a.Status = ActivityFailed
+				}
+				a.Output = tailText(result.Text, maxActivityOutput)
+			}
+			activities = append(activities, a)
+		}
+	}
+
+	return activities
+}
+
+// RecordSessionActivities records derived activities as "activity" history entries
+func (h *SessionHistory) RecordSessionActivities(goalID, sessionID string, activities []SessionActivity) error {
+	for _, a := range activities {
+		if err := h.RecordActivity(goalID, sessionID, "activity", a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetGoalActivity returns the activities recorded for a goal, oldest first
+func (h *Hub) GetGoalActivity(goalID string) ([]SessionActivity, error) {
+	entries, err := h.history.GetGoalHistory(goalID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []SessionActivity
+	for _, entry := range entries {
+		if entry.Type != "activity" || entry.Data == nil {
+			continue
+		}
+		data, err := json.Marshal(entry.Data)
+		if err != nil {
+			continue
+		}
+		var a SessionActivity
+		if err := json.Unmarshal(data, &a); err != nil || a.Kind == "" {
+			continue
+		}
+		activities = append(activities, a)
+	}
+	return activities, nil
+}
+
+// tailText returns the last max bytes of text, starting at a line boundary when possible
+func tailText(text string, max int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= max {
+		return text
+	}
+	start := len(text) - max
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	tail := text[start:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		return tail[i+1:]
+	}
+	return tail
+}
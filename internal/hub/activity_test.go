@@ -0,0 +1,74 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractActivities(t *testing.T) {
+	transcript := sampleTranscript + `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"tu-4","name":"Edit","input":{"file_path":"/src/login.go"}},{"type":"tool_use","id":"tu-5","name":"Bash","input":{"command":"go test ./internal/..."}},{"type":"tool_use","id":"tu-6","name":"Bash","input":{"command":"git status"}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tu-5","content":"ok  \tpkg/login\t0.1s"}]}}
+`
+	entries, err := ParseTranscript(strings.NewReader(transcript))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	activities := ExtractActivities("session-001", entries)
+	if len(activities) != 4 {
+		t.Fatalf("expected 4 activities, got %d: %+v", len(activities), activities)
+	}
+
+	edit := activities[0]
+	if edit.Kind != ActivityFileEdit || edit.File != "/src/login.go" || edit.Edits != 2 {
+		t.Errorf("expected merged file edit with 2 edits, got %+v", edit)
+	}
+	if a := activities[1]; a.Kind != ActivityTestRun || a.Status != ActivityFailed || a.Output != "FAIL" {
+		t.Errorf("expected failed test run, got %+v", a)
+	}
+	if a := activities[2]; a.Kind != ActivityTestRun || a.Status != ActivityOK || a.Command != "go test ./internal/..." {
+		t.Errorf("expected passing test run, got %+v", a)
+	}
+	if a := activities[3]; a.Kind != ActivityCommand || a.Status != "" || a.SessionID != "session-001" {
+		t.Errorf("expected command without result, got %+v", a)
+	}
+}
+
+func TestTailText(t *testing.T) {
+	if got := tailText("  short  ", 10); got != "short" {
+		t.Errorf("expected 'short', got %q", got)
+	}
+	if got := tailText("line one\nline two\nline three", 15); got != "line three" {
+		t.Errorf("expected tail to start at a line boundary, got %q", got)
+	}
+}
+
+func TestStopExecutorRecordsActivity(t *testing.T) {
+	h := setupTestHub(t)
+
+	transcriptPath := filepath.Join(t.TempDir(), "claude.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte(sampleTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+	h.StopExecutorWithClaudeInfo(StopExecutorRequest{
+		GoalID:         "abc1234",
+		SessionID:      "session-001",
+		TranscriptPath: transcriptPath,
+		Reason:         "completed",
+	})
+
+	activities, err := h.GetGoalActivity("abc1234")
+	if err != nil {
+		t.Fatalf("GetGoalActivity failed: %v", err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("expected 2 activities, got %d: %+v", len(activities), activities)
+	}
+	if activities[0].Kind != ActivityFileEdit || activities[1].Kind != ActivityTestRun {
+		t.Errorf("unexpected activities: %+v", activities)
+	}
+}
@@ -0,0 +1,182 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Attachment types accepted in structured answers
+const (
+	AttachmentSnippet = "snippet" // Inline file/code snippet
+	AttachmentLink    = "link"    // URL reference
+	AttachmentFile    = "file"    // Uploaded file (see SaveAttachment)
+)
+
+// MaxSnippetBytes caps the size of a single snippet attachment
+const MaxSnippetBytes = 64 * 1024
+
+var (
+	// ErrQuestionNotFound is returned when answering a question that isn't pending
+	ErrQuestionNotFound = errors.New("question not found")
+
+	// ErrInvalidAnswer is returned when an answer doesn't fit the question
+	ErrInvalidAnswer = errors.New("invalid answer")
+)
+
+// Attachment is a file snippet, link or uploaded file attached to an answer
+// or message
+type Attachment struct {
+	Type     string `json:"type"`               // "snippet", "link" or "file"
+	Name     string `json:"name,omitempty"`     // File name or link title
+	URL      string `json:"url,omitempty"`      // For links; download URL for files
+	Content  string `json:"content,omitempty"`  // For snippets
+	Language string `json:"language,omitempty"` // Syntax hint for snippets
+
+	// Uploaded files, filled in from the ID by ResolveAttachments
+	ID          string `json:"id,omitempty"`
+	Path        string `json:"path,omitempty"` // Where the executor reads it
+	Size        int64  `json:"size,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// StructuredAnswer is an answer to a question: selected options, free text and attachments.
+// A plain string answer is a StructuredAnswer with only Text set.
+type StructuredAnswer struct {
+	OptionIDs   []string     `json:"option_ids,omitempty"`
+	Text        string       `json:"text,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// IsEmpty returns true if the answer carries no content at all
+func (a *StructuredAnswer) IsEmpty() bool {
+	return len(a.OptionIDs) == 0 && strings.TrimSpace(a.Text) == "" && len(a.Attachments) == 0
+}
+
+// IsPlainText returns true if the answer is only free text (legacy string answer)
+func (a *StructuredAnswer) IsPlainText() bool {
+	return len(a.OptionIDs) == 0 && len(a.Attachments) == 0
+}
+
+// ValidateAnswer checks an answer against the question's options
+func (q *Question) ValidateAnswer(a *StructuredAnswer) error {
+	if a == nil || a.IsEmpty() {
+		return fmt.Errorf("%w: answer is empty", ErrInvalidAnswer)
+	}
+
+	if len(a.OptionIDs) > 1 && !q.MultiSelect {
+		return fmt.Errorf("%w: question accepts a single option, got %d", ErrInvalidAnswer, len(a.OptionIDs))
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range a.OptionIDs {
+		if seen[id] {
+			return fmt.Errorf("%w: option %q selected twice", ErrInvalidAnswer, id)
+		}
+		seen[id] = true
+		if q.findOption(id) == nil {
+			return fmt.Errorf("%w: unknown option %q", ErrInvalidAnswer, id)
+		}
+	}
+
+	return validateAttachments(a.Attachments)
+}
+
+// validateAttachments checks the attachments of an answer or message
+func validateAttachments(atts []Attachment) error {
+	for i, att := range atts {
+		switch att.Type {
+		case AttachmentLink:
+			u, err := url.Parse(att.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("%w: attachment %d: link must be an http(s) URL", ErrInvalidAnswer, i)
+			}
+		case AttachmentSnippet:
+			if att.Content == "" {
+				return fmt.Errorf("%w: attachment %d: snippet content is empty", ErrInvalidAnswer, i)
+			}
+			if len(att.Content) > MaxSnippetBytes {
+				return fmt.Errorf("%w: attachment %d: snippet exceeds %d bytes", ErrInvalidAnswer, i, MaxSnippetBytes)
+			}
+		case AttachmentFile:
+			if att.Path == "" {
+				return fmt.Errorf("%w: attachment %d: file %q wasn't uploaded", ErrInvalidAnswer, i, att.ID)
+			}
+		default:
+			return fmt.Errorf("%w: attachment %d: unknown type %q", ErrInvalidAnswer, i, att.Type)
+		}
+	}
+
+	return nil
+}
+
+// Render formats the answer as text for the executor and the goal markdown
+func (a *StructuredAnswer) Render(q *Question) string {
+	var parts []string
+
+	if len(a.OptionIDs) > 0 {
+		labels := make([]string, 0, len(a.OptionIDs))
+		for _, id := range a.OptionIDs {
+			if opt := q.findOption(id); opt != nil {
+				labels = append(labels, opt.Label)
+			} else {
+				labels = append(labels, id)
+			}
+		}
+		parts = append(parts, strings.Join(labels, ", "))
+	}
+
+	if a.Text != "" {
+		parts = append(parts, a.Text)
+	}
+
+	for _, att := range a.Attachments {
+		if rendered := att.Render(); rendered != "" {
+			parts = append(parts, rendered)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// findOption returns the option with the given ID, or nil
+func (q *Question) findOption(id string) *Option {
+	for i := range q.Options {
+		if q.Options[i].ID == id {
+			return &q.Options[i]
+		}
+	}
+	return nil
+}
+
+// assignOptionIDs gives every option a stable ID ("opt-1", "opt-2", ...) if the
+// executor didn't provide one, so answers can reference options unambiguously
+func (q *Question) assignOptionIDs() {
+	for i := range q.Options {
+		if q.Options[i].ID == "" {
+			q.Options[i].ID = fmt.Sprintf("opt-%d", i+1)
+		}
+	}
+}
+
+// Render formats the attachment as text for the executor. Uploaded files are
+// given by path so the executor can read them.
+func (att Attachment) Render() string {
+	switch att.Type {
+	case AttachmentLink:
+		if att.Name != "" {
+			return fmt.Sprintf("[Link] %s: %s", att.Name, att.URL)
+		}
+		return "[Link] " + att.URL
+	case AttachmentSnippet:
+		header := "[Snippet]"
+		if att.Name != "" {
+			header = fmt.Sprintf("[Snippet] %s", att.Name)
+		}
+		return fmt.Sprintf("%s\n```%s\n%s\n```", header, att.Language, strings.TrimRight(att.Content, "\n"))
+	case AttachmentFile:
+		return fmt.Sprintf("[File] %s (%s, %d bytes): %s", att.Name, att.ContentType, att.Size, att.Path)
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Answer delivery statuses
+const (
+	AnswerUnanswered = "unanswered" // Waiting for an answer (or the answer was retracted)
+	AnswerScheduled  = "scheduled"  // Answered, held for edits until DeliverAt
+	AnswerDelivered  = "delivered"  // Handed to the executor
+)
+
+// ErrAnswerDelivered is returned when editing or retracting an answer the
+// executor already received
+var ErrAnswerDelivered = errors.New("answer already delivered")
+
+// ErrNoAnswer is returned when editing or retracting a question that has no
+// scheduled answer
+var ErrNoAnswer = errors.New("question has no answer to change")
+
+// AnswerDraft is an answer replaced or retracted before delivery
+type AnswerDraft struct {
+	Answer    string    `json:"answer"`
+	User      string    `json:"user,omitempty"`
+	At        time.Time `json:"at"`
+	Retracted bool      `json:"retracted,omitempty"`
+}
+
+// AnswerRecord tracks a question's answer from first draft to delivery
+type AnswerRecord struct {
+	QuestionID  string            `json:"question_id"`
+	GoalID      string            `json:"goal_id"`
+	Question    string            `json:"question"`
+	Status      string            `json:"status"`           // "unanswered", "scheduled", "delivered"
+	Answer      string            `json:"answer,omitempty"` // Current or delivered answer, rendered
+	Structured  *StructuredAnswer `json:"structured,omitempty"`
+	User        string            `json:"user,omitempty"`
+	AnsweredAt  *time.Time        `json:"answered_at,omitempty"`
+	DeliverAt   *time.Time        `json:"deliver_at,omitempty"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+	Drafts      []AnswerDraft     `json:"drafts,omitempty"` // Earlier answers, oldest first
+
+	answer     *StructuredAnswer
+	generation int // Bumped on every change so stale delivery timers do nothing
+}
+
+// SetAnswerGrace sets how long answers are held before delivery, during which
+// they can be edited or retracted. Zero delivers answers immediately.
+func (h *Hub) SetAnswerGrace(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.answerGrace = d
+}
+
+// GetAnswer returns the answer record for a question: pending, scheduled or
+// recently delivered
+func (h *Hub) GetAnswer(id string) (*AnswerRecord, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if rec, ok := h.answers[id]; ok {
+		copied := *rec
+		copied.Drafts = append([]AnswerDraft(nil), rec.Drafts...)
+		return &copied, nil
+	}
+	if q, ok := h.questions[id]; ok {
+		return &AnswerRecord{QuestionID: id, GoalID: q.GoalID, Question: q.Question, Status: AnswerUnanswered}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrQuestionNotFound, id)
+}
+
+// EditAnswer replaces a scheduled answer before it is delivered and restarts
+// the grace period. Only the user who answered can edit it.
+func (h *Hub) EditAnswer(id, user string, answer *StructuredAnswer) error {
+	return h.answerAs(id, user, answer, true)
+}
+
+// answerAs answers a pending question, or with edit set replaces its
+// scheduled answer, then delivers or schedules the answer
+func (h *Hub) answerAs(id, user string, answer *StructuredAnswer, edit bool) error {
+	now := time.Now()
+
+	h.mu.Lock()
+	if edit {
+		if _, err := h.scheduledAnswer(id, user); err != nil {
+			h.mu.Unlock()
+			return err
+		}
+	}
+	q, exists := h.questions[id]
+	if !exists {
+		err := h.missingQuestionError(id)
+		h.mu.Unlock()
+		return err
+	}
+	if rec, ok := h.answers[id]; ok && rec.Status == AnswerScheduled && rec.User != user {
+		h.mu.Unlock()
+		return &ConflictError{Reason: ConflictAnswered, User: rec.User, At: *rec.AnsweredAt}
+	}
+	if claim := q.activeClaim(now); claim != nil && claim.User != user {
+		h.mu.Unlock()
+		return &ConflictError{Reason: ConflictClaimed, User: claim.User, At: claim.ClaimedAt}
+	}
+	if answer != nil {
+		if err := h.ResolveAttachments(q.GoalID, answer.Attachments); err != nil {
+			h.mu.Unlock()
+			return fmt.Errorf("%w: %v", ErrInvalidAnswer, err)
+		}
+	}
+	if err := q.ValidateAnswer(answer); err != nil {
+		h.mu.Unlock()
+		return err
+	}
+
+	rec := h.recordAnswer(q, user, answer, now)
+	if h.answerGrace <= 0 {
+		h.deliverAnswer(q, rec, now)
+		return nil
+	}
+	h.scheduleDelivery(id, rec, now)
+	data := map[string]interface{}{
+		"id":         id,
+		"goal_id":    q.GoalID,
+		"answer":     rec.Answer,
+		"deliver_at": *rec.DeliverAt,
+		"edited":     len(rec.Drafts) > 0,
+	}
+	if user != "" {
+		data["user"] = user
+	}
+	h.mu.Unlock()
+
+	h.broadcast(Event{Type: EventAnswerScheduled, Data: data})
+	return nil
+}
+
+// RetractAnswer withdraws a scheduled answer before it is delivered; the
+// question is pending again. Only the user who answered can retract it.
+func (h *Hub) RetractAnswer(id, user string) error {
+	now := time.Now()
+
+	h.mu.Lock()
+	rec, err := h.scheduledAnswer(id, user)
+	if err != nil {
+		h.mu.Unlock()
+		return err
+	}
+	rec.Drafts = append(rec.Drafts, AnswerDraft{Answer: rec.Answer, User: rec.User, At: now, Retracted: true})
+	rec.Status = AnswerUnanswered
+	rec.Answer, rec.Structured, rec.answer = "", nil, nil
+	rec.User, rec.AnsweredAt, rec.DeliverAt = "", nil, nil
+	rec.generation++
+	goalID := rec.GoalID
+	h.mu.Unlock()
+
+	h.broadcast(Event{
+		Type: EventAnswerRetracted,
+		Data: map[string]interface{}{"id": id, "goal_id": goalID, "user": user},
+	})
+	return nil
+}
+
+// scheduledAnswer returns a question's scheduled answer if user may change it (caller holds h.mu)
+func (h *Hub) scheduledAnswer(id, user string) (*AnswerRecord, error) {
+	rec, ok := h.answers[id]
+	if !ok {
+		if _, pending := h.questions[id]; pending {
+			return nil, fmt.Errorf("%w: %s", ErrNoAnswer, id)
+		}
+		return nil, h.missingQuestionError(id)
+	}
+	switch rec.Status {
+	case AnswerDelivered:
+		return nil, fmt.Errorf("%w: %s", ErrAnswerDelivered, id)
+	case AnswerUnanswered:
+		return nil, fmt.Errorf("%w: %s", ErrNoAnswer, id)
+	}
+	if rec.User != user {
+		return nil, &ConflictError{Reason: ConflictAnswered, User: rec.User, At: *rec.AnsweredAt}
+	}
+	return rec, nil
+}
+
+// recordAnswer sets the current answer on a question's record, keeping the
+// previous one as a draft (caller holds h.mu)
+func (h *Hub) recordAnswer(q *Question, user string, answer *StructuredAnswer, now time.Time) *AnswerRecord {
+	rec, ok := h.answers[q.ID]
+	if !ok {
+		rec = &AnswerRecord{QuestionID: q.ID, GoalID: q.GoalID, Question: q.Question}
+		h.answers[q.ID] = rec
+	}
+	if rec.Status == AnswerScheduled {
+		rec.Drafts = append(rec.Drafts, AnswerDraft{Answer: rec.Answer, User: rec.User, At: *rec.AnsweredAt})
+	}
+	rec.Answer = answer.Render(q)
+	rec.Structured = nil
+	if !answer.IsPlainText() {
+		rec.Structured = answer
+	}
+	rec.answer = answer
+	rec.User = user
+	rec.AnsweredAt = &now
+	rec.generation++
+	return rec
+}
+
+// scheduleDelivery holds an answer for the grace period (caller holds h.mu)
+func (h *Hub) scheduleDelivery(id string, rec *AnswerRecord, now time.Time) {
+	deliverAt := now.Add(h.answerGrace)
+	rec.Status = AnswerScheduled
+	rec.DeliverAt = &deliverAt
+	generation := rec.generation
+	time.AfterFunc(h.answerGrace, func() {
+		h.mu.Lock()
+		q, pending := h.questions[id]
+		if !pending || h.answers[id] != rec || rec.generation != generation || rec.Status != AnswerScheduled {
+			h.mu.Unlock()
+			return
+		}
+		h.deliverAnswer(q, rec, time.Now())
+	})
+}
+
+// deliverAnswer hands a question's answer to the executor, records it and
+// broadcasts it (caller holds h.mu, which is released)
+func (h *Hub) deliverAnswer(q *Question, rec *AnswerRecord, now time.Time) {
+	// Remove while still locked so concurrent answers can't both be delivered
+	delete(h.questions, q.ID)
+	h.markAnswered(q.ID, rec.User, now)
+	h.rememberAnswer(q.Fingerprint, rec.answer, now)
+	for id, old := range h.answers {
+		if old.Status == AnswerDelivered && now.Sub(*old.DeliveredAt) > answeredRetention {
+			delete(h.answers, id)
+		} else if _, pending := h.questions[id]; !pending && old.Status != AnswerDelivered && id != q.ID {
+			delete(h.answers, id) // Question went away undelivered (e.g. executor stopped)
+		}
+	}
+	rec.Status = AnswerDelivered
+	rec.DeliverAt = nil
+	rec.DeliveredAt = &now
+	answer, user, rendered, structured := rec.answer, rec.User, rec.Answer, rec.Structured
+	drafts := append([]AnswerDraft(nil), rec.Drafts...)
+	waiters := q.waiters
+	h.mu.Unlock()
+
+	// Write to markdown
+	if err := h.mdWriter.WriteQA(q.GoalID, q.SessionID, q.Question, rendered); err != nil {
+		// Log error but don't fail
+		// TODO: proper logging
+	}
+
+	// Record in persistent history (structure preserved for non-text answers)
+	if err := h.history.RecordStructuredQuestion(q.GoalID, q.SessionID, user, q.Question, rendered, q.Options, structured, drafts); err != nil {
+		// Log error but don't fail
+		// TODO: proper logging
+	}
+	h.recordDecision(q.GoalID, q.SessionID, q.Question, rendered, user, "")
+
+	// Send answer to waiting goroutines
+	q.answerCh <- answer
+	for _, ch := range waiters {
+		ch <- answer
+	}
+
+	// Broadcast answered event
+	data := map[string]interface{}{
+		"id":     q.ID,
+		"answer": rendered,
+	}
+	if user != "" {
+		data["user"] = user
+	}
+	if structured != nil {
+		data["structured"] = structured
+	}
+	if len(drafts) > 0 {
+		data["drafts"] = len(drafts)
+	}
+	h.broadcast(Event{
+		Type: EventAnswered,
+		Data: data,
+	})
+	h.EmitGoalUpdated(q.GoalID, EventAnswered, nil)
+}
@@ -0,0 +1,142 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnswerGraceEditAndDeliver(t *testing.T) {
+	h := setupTestHub(t)
+	h.SetAnswerGrace(100 * time.Millisecond)
+
+	got := make(chan string, 1)
+	go func() {
+		got <- h.Ask(&Question{ID: "q-1", GoalID: "abc1234", SessionID: "s1", Question: "Which database?"})
+	}()
+	waitForPending(t, h, 1)
+
+	if err := h.AnswerStructuredAs("q-1", "alice", &StructuredAnswer{Text: "MySQL"}); err != nil {
+		t.Fatalf("answer failed: %v", err)
+	}
+	rec, err := h.GetAnswer("q-1")
+	if err != nil {
+		t.Fatalf("GetAnswer failed: %v", err)
+	}
+	if rec.Status != AnswerScheduled || rec.Answer != "MySQL" || rec.DeliverAt == nil {
+		t.Fatalf("expected scheduled MySQL answer, got %+v", rec)
+	}
+
+	// Someone else can't edit, answer or retract it
+	var conflict *ConflictError
+	if err := h.EditAnswer("q-1", "bob", &StructuredAnswer{Text: "SQLite"}); !errors.As(err, &conflict) {
+		t.Fatalf("expected conflict editing another user's answer, got %v", err)
+	}
+	if err := h.AnswerStructuredAs("q-1", "bob", &StructuredAnswer{Text: "SQLite"}); !errors.As(err, &conflict) {
+		t.Fatalf("expected conflict answering a scheduled question, got %v", err)
+	}
+	if err := h.RetractAnswer("q-1", "bob"); !errors.As(err, &conflict) {
+		t.Fatalf("expected conflict retracting another user's answer, got %v", err)
+	}
+
+	if err := h.EditAnswer("q-1", "alice", &StructuredAnswer{Text: "PostgreSQL"}); err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+
+	select {
+	case answer := <-got:
+		if answer != "PostgreSQL" {
+			t.Errorf("executor got %q, want the edited answer", answer)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("answer was not delivered after the grace period")
+	}
+
+	rec, err = h.GetAnswer("q-1")
+	if err != nil {
+		t.Fatalf("GetAnswer after delivery failed: %v", err)
+	}
+	if rec.Status != AnswerDelivered || rec.DeliveredAt == nil || rec.Answer != "PostgreSQL" {
+		t.Errorf("expected delivered PostgreSQL answer, got %+v", rec)
+	}
+	if len(rec.Drafts) != 1 || rec.Drafts[0].Answer != "MySQL" {
+		t.Errorf("expected the first answer kept as a draft, got %+v", rec.Drafts)
+	}
+
+	if err := h.EditAnswer("q-1", "alice", &StructuredAnswer{Text: "Redis"}); !errors.Is(err, ErrAnswerDelivered) {
+		t.Errorf("expected ErrAnswerDelivered editing a delivered answer, got %v", err)
+	}
+	if err := h.RetractAnswer("q-1", "alice"); !errors.Is(err, ErrAnswerDelivered) {
+		t.Errorf("expected ErrAnswerDelivered retracting a delivered answer, got %v", err)
+	}
+
+	// History records the delivered answer along with the drafts
+	entries, err := h.history.GetGoalHistory("abc1234", 0)
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+	var found bool
+	for _, e := range entries {
+		if e.Type != "question" {
+			continue
+		}
+		found = true
+		if e.Answer != "PostgreSQL" {
+			t.Errorf("history answer = %q, want PostgreSQL", e.Answer)
+		}
+		if data, ok := e.Data.(map[string]interface{}); !ok || data["drafts"] == nil {
+			t.Errorf("expected drafts in history data, got %+v", e.Data)
+		}
+	}
+	if !found {
+		t.Error("expected a question entry in history")
+	}
+}
+
+func TestAnswerGraceRetract(t *testing.T) {
+	h := setupTestHub(t)
+	h.SetAnswerGrace(50 * time.Millisecond)
+
+	got := make(chan string, 1)
+	go func() {
+		got <- h.Ask(&Question{ID: "q-1", GoalID: "abc1234", SessionID: "s1", Question: "Delete the cache?"})
+	}()
+	waitForPending(t, h, 1)
+
+	if err := h.RetractAnswer("q-1", "alice"); !errors.Is(err, ErrNoAnswer) {
+		t.Fatalf("expected ErrNoAnswer retracting an unanswered question, got %v", err)
+	}
+	if err := h.AnswerStructuredAs("q-1", "alice", &StructuredAnswer{Text: "Yes"}); err != nil {
+		t.Fatalf("answer failed: %v", err)
+	}
+	if err := h.RetractAnswer("q-1", "alice"); err != nil {
+		t.Fatalf("retract failed: %v", err)
+	}
+
+	// The retracted answer must never reach the executor
+	select {
+	case answer := <-got:
+		t.Fatalf("retracted answer %q was delivered", answer)
+	case <-time.After(150 * time.Millisecond):
+	}
+	rec, err := h.GetAnswer("q-1")
+	if err != nil {
+		t.Fatalf("GetAnswer failed: %v", err)
+	}
+	if rec.Status != AnswerUnanswered || len(rec.Drafts) != 1 || !rec.Drafts[0].Retracted {
+		t.Fatalf("expected unanswered question with a retracted draft, got %+v", rec)
+	}
+
+	// Anyone can answer it again
+	if err := h.AnswerStructuredAs("q-1", "bob", &StructuredAnswer{Text: "No"}); err != nil {
+		t.Fatalf("re-answer failed: %v", err)
+	}
+	select {
+	case answer := <-got:
+		if answer != "No" {
+			t.Errorf("executor got %q, want No", answer)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("answer was not delivered")
+	}
+}
@@ -0,0 +1,156 @@
+package hub
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newChoiceQuestion(multi bool) *Question {
+	q := &Question{
+		ID:          "q-001",
+		GoalID:      "abc1234",
+		SessionID:   "session-001",
+		Question:    "Which databases?",
+		MultiSelect: multi,
+		Options: []Option{
+			{Label: "Postgres"},
+			{ID: "sqlite", Label: "SQLite"},
+		},
+	}
+	q.assignOptionIDs()
+	return q
+}
+
+func TestAssignOptionIDs(t *testing.T) {
+	q := newChoiceQuestion(false)
+
+	if q.Options[0].ID != "opt-1" {
+		t.Errorf("expected generated ID 'opt-1', got '%s'", q.Options[0].ID)
+	}
+	if q.Options[1].ID != "sqlite" {
+		t.Errorf("expected provided ID 'sqlite' to be kept, got '%s'", q.Options[1].ID)
+	}
+}
+
+func TestValidateAnswer(t *testing.T) {
+	single := newChoiceQuestion(false)
+	multi := newChoiceQuestion(true)
+
+	tests := []struct {
+		name    string
+		q       *Question
+		answer  *StructuredAnswer
+		wantErr bool
+	}{
+		{"empty", single, &StructuredAnswer{}, true},
+		{"text only", single, &StructuredAnswer{Text: "neither"}, false},
+		{"single option", single, &StructuredAnswer{OptionIDs: []string{"opt-1"}}, false},
+		{"unknown option", single, &StructuredAnswer{OptionIDs: []string{"opt-9"}}, true},
+		{"multi on single", single, &StructuredAnswer{OptionIDs: []string{"opt-1", "sqlite"}}, true},
+		{"multi on multi", multi, &StructuredAnswer{OptionIDs: []string{"opt-1", "sqlite"}}, false},
+		{"duplicate option", multi, &StructuredAnswer{OptionIDs: []string{"opt-1", "opt-1"}}, true},
+		{"valid link", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentLink, URL: "https://example.com/doc"}}}, false},
+		{"bad link", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentLink, URL: "file:///etc/passwd"}}}, true},
+		{"valid snippet", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentSnippet, Content: "x := 1"}}}, false},
+		{"empty snippet", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentSnippet}}}, true},
+		{"huge snippet", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentSnippet, Content: strings.Repeat("x", MaxSnippetBytes+1)}}}, true},
+		{"uploaded file", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentFile, ID: "f1", Path: "/vega/f1-notes.txt"}}}, false},
+		{"file not uploaded", single, &StructuredAnswer{Attachments: []Attachment{{Type: AttachmentFile, ID: "f1"}}}, true},
+		{"unknown attachment", single, &StructuredAnswer{Attachments: []Attachment{{Type: "image"}}}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.q.ValidateAnswer(tt.answer)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tt.name, tt.wantErr, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidAnswer) {
+			t.Errorf("%s: expected ErrInvalidAnswer, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestStructuredAnswerRender(t *testing.T) {
+	q := newChoiceQuestion(true)
+	answer := &StructuredAnswer{
+		OptionIDs: []string{"opt-1", "sqlite"},
+		Text:      "Use both for now",
+		Attachments: []Attachment{
+			{Type: AttachmentLink, Name: "Design doc", URL: "https://example.com/design"},
+			{Type: AttachmentSnippet, Name: "config.yaml", Language: "yaml", Content: "db: postgres\n"},
+		},
+	}
+
+	rendered := answer.Render(q)
+
+	for _, want := range []string{
+		"Postgres, SQLite",
+		"Use both for now",
+		"[Link] Design doc: https://example.com/design",
+		"[Snippet] config.yaml\n```yaml\ndb: postgres\n```",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("rendered answer missing %q:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestAnswerStructured(t *testing.T) {
+	h := setupTestHub(t)
+	q := &Question{
+		ID:          "q-001",
+		GoalID:      "abc1234",
+		SessionID:   "session-001",
+		Question:    "Which databases?",
+		MultiSelect: true,
+		Options:     []Option{{Label: "Postgres"}, {Label: "SQLite"}},
+	}
+
+	var rendered string
+	var structured *StructuredAnswer
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		rendered, structured = h.AskStructured(q)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Invalid answers are rejected and the question stays pending
+	err := h.AnswerStructured("q-001", &StructuredAnswer{OptionIDs: []string{"opt-3"}})
+	if !errors.Is(err, ErrInvalidAnswer) {
+		t.Fatalf("expected ErrInvalidAnswer, got %v", err)
+	}
+	if len(h.GetPendingQuestions()) != 1 {
+		t.Fatal("question should still be pending after invalid answer")
+	}
+
+	if err := h.AnswerStructured("q-001", &StructuredAnswer{OptionIDs: []string{"opt-1", "opt-2"}}); err != nil {
+		t.Fatalf("AnswerStructured failed: %v", err)
+	}
+	wg.Wait()
+
+	if rendered != "Postgres, SQLite" {
+		t.Errorf("expected rendered 'Postgres, SQLite', got '%s'", rendered)
+	}
+	if structured == nil || len(structured.OptionIDs) != 2 {
+		t.Errorf("expected structured answer with 2 options, got %+v", structured)
+	}
+
+	// Structure is preserved in history
+	entries, err := h.GetGoalHistory("abc1234", 0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 history entry, got %d (err: %v)", len(entries), err)
+	}
+	data, ok := entries[0].Data.(map[string]interface{})
+	if !ok || data["structured_answer"] == nil || data["options"] == nil {
+		t.Errorf("expected options and structured_answer in history data, got %v", entries[0].Data)
+	}
+
+	if err := h.AnswerStructured("q-001", &StructuredAnswer{Text: "late"}); !errors.Is(err, ErrAnswerConflict) {
+		t.Errorf("expected ErrAnswerConflict after answer, got %v", err)
+	}
+}
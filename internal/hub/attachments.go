@@ -0,0 +1,156 @@
+package hub
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxAttachmentBytes caps the size of an uploaded file attachment
+const MaxAttachmentBytes = 10 * 1024 * 1024
+
+// maxAttachmentNameLength bounds stored attachment file names
+const maxAttachmentNameLength = 100
+
+var (
+	// ErrAttachmentNotFound is returned for unknown attachment IDs
+	ErrAttachmentNotFound = errors.New("attachment not found")
+
+	// ErrAttachmentTooLarge is returned when an upload exceeds MaxAttachmentBytes
+	ErrAttachmentTooLarge = fmt.Errorf("attachment exceeds %d bytes", MaxAttachmentBytes)
+)
+
+// attachmentsDir returns the directory holding a goal's uploaded files
+// (.vega-hub-history/attachments/<goal>/<id>-<name>)
+func (h *Hub) attachmentsDir(goalID string) string {
+	return filepath.Join(h.dir, ".vega-hub-history", "attachments", goalID)
+}
+
+// SaveAttachment stores a file uploaded for a goal and returns it as a file
+// attachment that answers and messages can reference by ID
+func (h *Hub) SaveAttachment(goalID, name, user string, r io.Reader) (*Attachment, error) {
+	dir := h.attachmentsDir(goalID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create attachments dir: %w", err)
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+	path := filepath.Join(dir, id+"-"+attachmentFileName(name))
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	size, err := io.Copy(f, io.LimitReader(r, MaxAttachmentBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > MaxAttachmentBytes {
+		err = ErrAttachmentTooLarge
+	}
+	if err != nil {
+		os.Remove(path)
+		if errors.Is(err, ErrAttachmentTooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	att, err := h.GetAttachment(goalID, id)
+	if err != nil {
+		return nil, err
+	}
+	h.history.RecordActivity(goalID, "", "attachment_uploaded", map[string]interface{}{
+		"attachment_id": id,
+		"name":          att.Name,
+		"size":          size,
+		"user":          user,
+	})
+	return att, nil
+}
+
+// GetAttachment returns a goal's uploaded file by ID
+func (h *Hub) GetAttachment(goalID, id string) (*Attachment, error) {
+	if id == "" || strings.ContainsAny(id, `-/\*?[`) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+	matches, _ := filepath.Glob(filepath.Join(h.attachmentsDir(goalID), id+"-*"))
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+	path := matches[0]
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+
+	contentType := "application/octet-stream"
+	if f, err := os.Open(path); err == nil {
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(f, head)
+		f.Close()
+		contentType = http.DetectContentType(head[:n])
+	}
+
+	return &Attachment{
+		Type:        AttachmentFile,
+		ID:          id,
+		Name:        strings.TrimPrefix(filepath.Base(path), id+"-"),
+		URL:         AttachmentURL(goalID, id),
+		Path:        path,
+		Size:        info.Size(),
+		ContentType: contentType,
+	}, nil
+}
+
+// ResolveAttachments fills in the uploaded files that file attachments
+// reference by ID, so clients only need to send {"type": "file", "id": ...}
+func (h *Hub) ResolveAttachments(goalID string, atts []Attachment) error {
+	for i, att := range atts {
+		if att.Type != AttachmentFile {
+			continue
+		}
+		stored, err := h.GetAttachment(goalID, att.ID)
+		if err != nil {
+			return fmt.Errorf("attachment %d: %w", i, err)
+		}
+		atts[i] = *stored
+	}
+	return nil
+}
+
+// AttachmentURL is where the web UI downloads an uploaded file
+func AttachmentURL(goalID, id string) string {
+	return fmt.Sprintf("/api/goals/%s/attachments/%s", goalID, id)
+}
+
+// attachmentFileName makes an uploaded file's name safe to store: the base
+// name only, without characters that need quoting in a shell or a path
+func attachmentFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	safe := strings.TrimLeft(b.String(), ".")
+	if len(safe) > maxAttachmentNameLength {
+		safe = safe[len(safe)-maxAttachmentNameLength:]
+	}
+	if safe == "" {
+		safe = "file"
+	}
+	return safe
+}
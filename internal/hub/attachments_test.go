@@ -0,0 +1,72 @@
+package hub
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAttachment(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir)
+
+	att, err := h.SaveAttachment("abc1234", "../../screen shot.png", "alice", bytes.NewReader([]byte("\x89PNG\r\n\x1a\nrest")))
+	if err != nil {
+		t.Fatalf("SaveAttachment: %v", err)
+	}
+	if att.Type != AttachmentFile || att.Name != "screen_shot.png" || att.ContentType != "image/png" || att.Size != 12 {
+		t.Errorf("unexpected attachment %+v", att)
+	}
+	if filepath.Dir(att.Path) != filepath.Join(dir, ".vega-hub-history", "attachments", "abc1234") {
+		t.Errorf("expected the file stored under the goal's attachments, got %s", att.Path)
+	}
+	if att.URL != "/api/goals/abc1234/attachments/"+att.ID {
+		t.Errorf("unexpected URL %s", att.URL)
+	}
+
+	got, err := h.GetAttachment("abc1234", att.ID)
+	if err != nil || got.Path != att.Path {
+		t.Errorf("expected the stored attachment, got %+v (%v)", got, err)
+	}
+	if _, err := h.GetAttachment("def5678", att.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected another goal not to see it, got %v", err)
+	}
+	if _, err := h.GetAttachment("abc1234", "*"); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected a wildcard ID refused, got %v", err)
+	}
+
+	_, err = h.SaveAttachment("abc1234", "big.bin", "alice", strings.NewReader(strings.Repeat("x", MaxAttachmentBytes+1)))
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(att.Path)); len(entries) != 1 {
+		t.Errorf("expected the oversized upload removed, got %d files", len(entries))
+	}
+}
+
+func TestMessageWithAttachment(t *testing.T) {
+	h := New(t.TempDir())
+	att, err := h.SaveAttachment("abc1234", "notes.txt", "alice", strings.NewReader("use the v2 API"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.SendUserMessageWithAttachments("abc1234", "see notes", "alice", []Attachment{{Type: AttachmentFile, ID: "missing"}}); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected an unknown upload refused, got %v", err)
+	}
+
+	msg, err := h.SendUserMessageWithAttachments("abc1234", "see notes", "alice", []Attachment{{Type: AttachmentFile, ID: att.ID}})
+	if err != nil {
+		t.Fatalf("SendUserMessageWithAttachments: %v", err)
+	}
+	delivered := h.DeliverUserMessages("abc1234", "s1")
+	if len(delivered) != 1 || len(delivered[0].Attachments) != 1 || delivered[0].Attachments[0].Path != att.Path {
+		t.Fatalf("expected the message delivered with the file path, got %+v", delivered)
+	}
+	if rendered := msg.Attachments[0].Render(); !strings.Contains(rendered, att.Path) {
+		t.Errorf("expected the executor pointed at the file, got %q", rendered)
+	}
+}
@@ -0,0 +1,158 @@
+package hub
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published on the bus. Handlers may publish other types through
+// EmitEvent; these are the ones the hub itself produces.
+const (
+	EventQuestion             = "question"
+	EventQuestionAdded        = "question_added"
+	EventAnswered             = "answered"
+	EventAnswerScheduled      = "answer_scheduled"
+	EventAnswerRetracted      = "answer_retracted"
+	EventExecutorStarted      = "executor_started"
+	EventExecutorStopped      = "executor_stopped"
+	EventExecutorProgress     = "executor_progress"
+	EventGoalUpdated          = "goal_updated"
+	EventGoalStateChanged     = "goal_state_changed"
+	EventRegistryUpdated      = "registry_updated"
+	EventFilesChanged         = "files_changed"
+	EventUserMessage          = "user_message"
+	EventUserMessageStatus    = "user_message_status"
+	EventStuckGoalsDetected   = "stuck_goals_detected"
+	EventSessionActivity      = "session_activity"
+	EventQuestionReleased     = "question_released"
+	EventQuestionReasked      = "question_reasked"
+	EventQuestionAutoAnswered = "question_auto_answered"
+	EventCommentAdded         = "comment_added"
+	EventCommitPolicyViolated = "commit_policy_violation"
+	EventGoalNotesUpdated     = "goal_notes_updated"
+	EventGoalFocusUpdated     = "goal_focus_updated"
+	EventShareLinkCreated     = "share_link_created"
+	EventShareLinkRevoked     = "share_link_revoked"
+	EventGoalReviewed         = "goal_reviewed"
+)
+
+// Consumer receives every event published on the bus. Consume is called in
+// publish order while the bus is locked, so it must not block or publish;
+// slow work (network calls) belongs on the consumer's own goroutine.
+type Consumer interface {
+	Name() string
+	Consume(Event)
+}
+
+// EventBus assigns events increasing IDs and timestamps and hands them to its
+// consumers: SSE clients, the persistent event log, metrics and webhooks.
+type EventBus struct {
+	mu        sync.Mutex
+	lastID    uint64
+	consumers []Consumer
+}
+
+// NewEventBus creates a bus whose first event gets ID lastID+1
+func NewEventBus(lastID uint64) *EventBus {
+	return &EventBus{lastID: lastID}
+}
+
+// Subscribe adds a consumer for events published from now on
+func (b *EventBus) Subscribe(c Consumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consumers = append(b.consumers, c)
+}
+
+// Consumers returns the names of the bus's consumers
+func (b *EventBus) Consumers() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, len(b.consumers))
+	for i, c := range b.consumers {
+		names[i] = c.Name()
+	}
+	return names
+}
+
+// Publish stamps the event and delivers it to every consumer
+func (b *EventBus) Publish(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	event.ID = b.lastID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	for _, c := range b.consumers {
+		c.Consume(event)
+	}
+	return event
+}
+
+// sseConsumer fans events out to the hub's SSE subscribers and keeps the
+// replay buffer used for Last-Event-ID
+type sseConsumer struct {
+	h *Hub
+}
+
+func (c sseConsumer) Name() string { return "sse" }
+
+func (c sseConsumer) Consume(event Event) {
+	h := c.h
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+
+	h.lastEventID = event.ID
+	h.remember(event)
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Skip slow subscribers
+		}
+	}
+}
+
+// EventStats counts published events of one type
+type EventStats struct {
+	Count int64     `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// eventMetrics counts events by type
+type eventMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*EventStats
+}
+
+func newEventMetrics() *eventMetrics {
+	return &eventMetrics{stats: make(map[string]*EventStats)}
+}
+
+func (m *eventMetrics) Name() string { return "metrics" }
+
+func (m *eventMetrics) Consume(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.stats[event.Type]
+	if !ok {
+		s = &EventStats{}
+		m.stats[event.Type] = s
+	}
+	s.Count++
+	if event.Time.After(s.Last) {
+		s.Last = event.Time
+	}
+}
+
+func (m *eventMetrics) snapshot() map[string]EventStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]EventStats, len(m.stats))
+	for t, s := range m.stats {
+		out[t] = *s
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package hub
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordingConsumer remembers the events it receives
+type recordingConsumer struct {
+	events []Event
+}
+
+func (c *recordingConsumer) Name() string    { return "recorder" }
+func (c *recordingConsumer) Consume(e Event) { c.events = append(c.events, e) }
+
+func TestEventBusPublish(t *testing.T) {
+	bus := NewEventBus(41)
+	first, second := &recordingConsumer{}, &recordingConsumer{}
+	bus.Subscribe(first)
+	bus.Subscribe(second)
+
+	e := bus.Publish(Event{Type: EventGoalUpdated, Data: map[string]string{"goal_id": "abc"}})
+	bus.Publish(Event{Type: EventRegistryUpdated})
+
+	if e.ID != 42 || e.Time.IsZero() {
+		t.Errorf("expected ID 42 with a timestamp, got %d at %v", e.ID, e.Time)
+	}
+	for _, c := range []*recordingConsumer{first, second} {
+		if len(c.events) != 2 || c.events[0].ID != 42 || c.events[1].ID != 43 {
+			t.Errorf("expected events 42 and 43 in order, got %+v", c.events)
+		}
+	}
+	if names := bus.Consumers(); len(names) != 2 || names[0] != "recorder" {
+		t.Errorf("unexpected consumers: %v", names)
+	}
+}
+
+func TestHubEventStats(t *testing.T) {
+	h := New(t.TempDir())
+	h.EmitEvent("goal_created", nil)
+	h.EmitEvent("goal_created", nil)
+	h.EmitEvent("goal_deleted", nil)
+
+	stats := h.EventStats()
+	if stats["goal_created"].Count != 2 || stats["goal_deleted"].Count != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan Event, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vega-Event") == "" {
+			t.Error("expected X-Vega-Event header")
+		}
+		body, _ := io.ReadAll(r.Body)
+		var e Event
+		json.Unmarshal(body, &e)
+		received <- e
+	}))
+	defer srv.Close()
+
+	h := New(t.TempDir())
+	h.Bus().Subscribe(NewWebhook(srv.URL, []string{"goal_created"}))
+	h.EmitEvent("goal_deleted", nil)
+	h.EmitEvent("goal_created", map[string]string{"goal_id": "abc"})
+
+	select {
+	case e := <-received:
+		if e.Type != "goal_created" || e.ID != 2 {
+			t.Errorf("expected goal_created event 2, got %s %d", e.Type, e.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for webhook delivery")
+	}
+	select {
+	case e := <-received:
+		t.Errorf("unexpected delivery of %s", e.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
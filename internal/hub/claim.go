@@ -0,0 +1,166 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ClaimTTL is how long a claim lasts without being renewed
+const ClaimTTL = 2 * time.Minute
+
+// answeredRetention is how long answered questions are remembered for conflict reporting
+const answeredRetention = time.Hour
+
+// Conflict reasons
+const (
+	ConflictClaimed  = "claimed"  // Another user holds the claim
+	ConflictAnswered = "answered" // The question was already answered
+)
+
+// ErrAnswerConflict is matched (via errors.Is) by every ConflictError
+var ErrAnswerConflict = errors.New("answer conflict")
+
+// ConflictError is returned when a claim or answer loses to another user
+type ConflictError struct {
+	Reason string    `json:"reason"` // "claimed" or "answered"
+	User   string    `json:"user,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+func (e *ConflictError) Error() string {
+	who := e.User
+	if who == "" {
+		who = "another user"
+	}
+	if e.Reason == ConflictAnswered {
+		return fmt.Sprintf("question already answered by %s at %s", who, e.At.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("question claimed by %s since %s", who, e.At.Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, ErrAnswerConflict) match
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrAnswerConflict
+}
+
+// Claim marks a user as answering a question
+type Claim struct {
+	User      string    `json:"user"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Draft     string    `json:"draft,omitempty"` // Answer being typed, shared with other viewers
+}
+
+// answeredMarker remembers who answered a question that is no longer pending
+type answeredMarker struct {
+	User string
+	At   time.Time
+}
+
+// activeClaim returns the question's claim if it hasn't expired (caller holds h.mu)
+func (q *Question) activeClaim(now time.Time) *Claim {
+	if q.Claim == nil || now.After(q.Claim.ExpiresAt) {
+		return nil
+	}
+	return q.Claim
+}
+
+// ClaimQuestion claims a pending question for a user, or renews the user's claim
+// and updates the shared draft. Returns a ConflictError if another user holds it.
+func (h *Hub) ClaimQuestion(id, user, draft string) (*Claim, error) {
+	if user == "" {
+		return nil, fmt.Errorf("user is required to claim a question")
+	}
+
+	now := time.Now()
+
+	h.mu.Lock()
+	q, exists := h.questions[id]
+	if !exists {
+		err := h.missingQuestionError(id)
+		h.mu.Unlock()
+		return nil, err
+	}
+
+	current := q.activeClaim(now)
+	if current != nil && current.User != user {
+		h.mu.Unlock()
+		return nil, &ConflictError{Reason: ConflictClaimed, User: current.User, At: current.ClaimedAt}
+	}
+
+	renewed := current != nil
+	claim := &Claim{User: user, ClaimedAt: now, ExpiresAt: now.Add(ClaimTTL), Draft: draft}
+	if renewed {
+		claim.ClaimedAt = current.ClaimedAt
+	}
+	q.Claim = claim
+	goalID := q.GoalID
+	h.mu.Unlock()
+
+	eventType := "question_claimed"
+	if renewed {
+		eventType = "question_typing"
+	}
+	h.broadcast(Event{
+		Type: eventType,
+		Data: map[string]interface{}{
+			"id":         id,
+			"goal_id":    goalID,
+			"user":       user,
+			"draft":      draft,
+			"expires_at": claim.ExpiresAt,
+		},
+	})
+
+	return claim, nil
+}
+
+// ReleaseClaim releases a user's claim on a question
+func (h *Hub) ReleaseClaim(id, user string) error {
+	h.mu.Lock()
+	q, exists := h.questions[id]
+	if !exists {
+		err := h.missingQuestionError(id)
+		h.mu.Unlock()
+		return err
+	}
+
+	current := q.activeClaim(time.Now())
+	if current != nil && current.User != user {
+		h.mu.Unlock()
+		return &ConflictError{Reason: ConflictClaimed, User: current.User, At: current.ClaimedAt}
+	}
+	q.Claim = nil
+	goalID := q.GoalID
+	h.mu.Unlock()
+
+	h.broadcast(Event{
+		Type: EventQuestionReleased,
+		Data: map[string]interface{}{
+			"id":      id,
+			"goal_id": goalID,
+			"user":    user,
+		},
+	})
+
+	return nil
+}
+
+// missingQuestionError reports why a question isn't pending (caller holds h.mu)
+func (h *Hub) missingQuestionError(id string) error {
+	if done, ok := h.answered[id]; ok {
+		return &ConflictError{Reason: ConflictAnswered, User: done.User, At: done.At}
+	}
+	return fmt.Errorf("%w: %s", ErrQuestionNotFound, id)
+}
+
+// markAnswered remembers who answered a question and prunes old markers (caller holds h.mu)
+func (h *Hub) markAnswered(id, user string, now time.Time) {
+	for qid, done := range h.answered {
+		if now.Sub(done.At) > answeredRetention {
+			delete(h.answered, qid)
+		}
+	}
+	h.answered[id] = answeredMarker{User: user, At: now}
+}
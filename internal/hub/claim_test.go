@@ -0,0 +1,108 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// addPendingQuestion registers a question without blocking on Ask
+func addPendingQuestion(h *Hub, id string) *Question {
+	q := &Question{ID: id, GoalID: "abc1234", SessionID: "s1", Question: "Proceed?", answerCh: make(chan *StructuredAnswer, 1)}
+	h.mu.Lock()
+	h.questions[id] = q
+	h.mu.Unlock()
+	return q
+}
+
+func TestClaimQuestion(t *testing.T) {
+	h := setupTestHub(t)
+	q := addPendingQuestion(h, "q-1")
+
+	events := h.Subscribe()
+	defer h.Unsubscribe(events)
+
+	claim, err := h.ClaimQuestion("q-1", "alice", "")
+	if err != nil {
+		t.Fatalf("ClaimQuestion failed: %v", err)
+	}
+	if ev := <-events; ev.Type != "question_claimed" {
+		t.Errorf("expected question_claimed event, got %s", ev.Type)
+	}
+
+	// Renewing keeps the original claim time and broadcasts typing
+	renewed, err := h.ClaimQuestion("q-1", "alice", "Yes, but")
+	if err != nil {
+		t.Fatalf("renew failed: %v", err)
+	}
+	if !renewed.ClaimedAt.Equal(claim.ClaimedAt) || renewed.Draft != "Yes, but" {
+		t.Errorf("unexpected renewed claim: %+v", renewed)
+	}
+	if ev := <-events; ev.Type != "question_typing" {
+		t.Errorf("expected question_typing event, got %s", ev.Type)
+	}
+
+	_, err = h.ClaimQuestion("q-1", "bob", "")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Reason != ConflictClaimed || conflict.User != "alice" {
+		t.Fatalf("expected claimed conflict, got %v", err)
+	}
+	if err := h.ReleaseClaim("q-1", "bob"); !errors.Is(err, ErrAnswerConflict) {
+		t.Errorf("expected bob's release to conflict, got %v", err)
+	}
+
+	// Expired claims can be taken over
+	h.mu.Lock()
+	q.Claim.ExpiresAt = time.Now().Add(-time.Second)
+	h.mu.Unlock()
+	if _, err := h.ClaimQuestion("q-1", "bob", ""); err != nil {
+		t.Errorf("expected bob to take expired claim, got %v", err)
+	}
+	<-events
+
+	if err := h.ReleaseClaim("q-1", "bob"); err != nil {
+		t.Fatalf("ReleaseClaim failed: %v", err)
+	}
+	if ev := <-events; ev.Type != "question_released" {
+		t.Errorf("expected question_released event, got %s", ev.Type)
+	}
+	if q.Claim != nil {
+		t.Error("expected claim to be cleared")
+	}
+
+	if _, err := h.ClaimQuestion("q-1", "", ""); err == nil {
+		t.Error("expected error for claim without user")
+	}
+	if _, err := h.ClaimQuestion("missing", "alice", ""); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+}
+
+func TestAnswerStructuredAs_Conflicts(t *testing.T) {
+	h := setupTestHub(t)
+	addPendingQuestion(h, "q-1")
+
+	if _, err := h.ClaimQuestion("q-1", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	err := h.AnswerStructuredAs("q-1", "bob", &StructuredAnswer{Text: "no"})
+	if !errors.Is(err, ErrAnswerConflict) {
+		t.Fatalf("expected conflict while claimed, got %v", err)
+	}
+
+	if err := h.AnswerStructuredAs("q-1", "alice", &StructuredAnswer{Text: "yes"}); err != nil {
+		t.Fatalf("claimant answer failed: %v", err)
+	}
+
+	err = h.AnswerStructuredAs("q-1", "bob", &StructuredAnswer{Text: "late"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Reason != ConflictAnswered || conflict.User != "alice" {
+		t.Errorf("expected answered conflict, got %v", err)
+	}
+
+	entries, _ := h.GetGoalHistory("abc1234", 0)
+	if len(entries) != 1 || entries[0].User != "alice" {
+		t.Errorf("expected answer recorded with user alice, got %+v", entries)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // OrphanedWorktree represents a worktree that exists on disk but isn't properly registered
@@ -29,19 +32,19 @@ type CleanupResult struct {
 // CleanupManager handles cleanup operations for worktrees and goals
 type CleanupManager struct {
 	vegaDir string
+	layout  *layout.Layout
 }
 
 // NewCleanupManager creates a new cleanup manager
 func NewCleanupManager(vegaDir string) *CleanupManager {
-	return &CleanupManager{vegaDir: vegaDir}
+	return &CleanupManager{vegaDir: vegaDir, layout: layout.New(vegaDir)}
 }
 
 // PruneStaleWorktrees runs git worktree prune on all project worktree-bases
 func (c *CleanupManager) PruneStaleWorktrees() *CleanupResult {
 	result := &CleanupResult{}
 
-	workspacesDir := filepath.Join(c.vegaDir, "workspaces")
-	entries, err := os.ReadDir(workspacesDir)
+	entries, err := os.ReadDir(c.layout.WorkspacesDir())
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("Failed to read workspaces: %v", err))
 		return result
@@ -53,9 +56,9 @@ func (c *CleanupManager) PruneStaleWorktrees() *CleanupResult {
 		}
 
 		projectName := entry.Name()
-		worktreeBase := filepath.Join(workspacesDir, projectName, "worktree-base")
+		worktreeBase := c.layout.WorktreeBase(projectName)
 
-		if _, err := os.Stat(worktreeBase); os.IsNotExist(err) {
+		if !isGitWorkspace(worktreeBase) {
 			continue
 		}
 
@@ -77,8 +80,7 @@ func (c *CleanupManager) PruneStaleWorktrees() *CleanupResult {
 func (c *CleanupManager) FindOrphanedWorktrees() *CleanupResult {
 	result := &CleanupResult{}
 
-	workspacesDir := filepath.Join(c.vegaDir, "workspaces")
-	entries, err := os.ReadDir(workspacesDir)
+	entries, err := os.ReadDir(c.layout.WorkspacesDir())
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("Failed to read workspaces: %v", err))
 		return result
@@ -90,10 +92,11 @@ func (c *CleanupManager) FindOrphanedWorktrees() *CleanupResult {
 		}
 
 		projectName := entry.Name()
-		projectWorkspaceDir := filepath.Join(workspacesDir, projectName)
-		worktreeBase := filepath.Join(projectWorkspaceDir, "worktree-base")
+		projectWorkspaceDir := c.layout.ProjectWorkspace(projectName)
+		worktreeBase := c.layout.WorktreeBase(projectName)
 
-		if _, err := os.Stat(worktreeBase); os.IsNotExist(err) {
+		// Plain projects link goal workspaces instead of registering worktrees
+		if !isGitWorkspace(worktreeBase) {
 			continue
 		}
 
@@ -143,7 +146,7 @@ func (c *CleanupManager) FindOrphanedWorktrees() *CleanupResult {
 // RemoveOrphanedWorktree removes an orphaned worktree directory
 func (c *CleanupManager) RemoveOrphanedWorktree(path string, force bool) error {
 	// Safety check: must be under workspaces directory
-	workspacesDir := filepath.Join(c.vegaDir, "workspaces")
+	workspacesDir := c.layout.WorkspacesDir()
 	if !strings.HasPrefix(path, workspacesDir) {
 		return fmt.Errorf("path is not under workspaces directory: %s", path)
 	}
@@ -176,65 +179,38 @@ func (c *CleanupManager) RemoveOrphanedWorktree(path string, force bool) error {
 func (c *CleanupManager) ArchiveCompletedGoals(olderThan time.Duration, dryRun bool) *CleanupResult {
 	result := &CleanupResult{}
 
-	historyDir := filepath.Join(c.vegaDir, "goals", "history")
-	entries, err := os.ReadDir(historyDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return result // No history directory, nothing to archive
-		}
-		result.Errors = append(result.Errors, fmt.Sprintf("Failed to read history: %v", err))
-		return result
-	}
-
 	cutoff := time.Now().Add(-olderThan)
 
-	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".md") {
+	for goalID, goalFile := range c.layout.GoalFiles(layout.HistoryDir) {
+		info, err := os.Stat(goalFile)
+		if err != nil || !info.ModTime().Before(cutoff) {
 			continue
 		}
 
-		goalFile := filepath.Join(historyDir, entry.Name())
-		info, err := os.Stat(goalFile)
-		if err != nil {
+		if dryRun {
+			result.ArchivedGoals = append(result.ArchivedGoals, goalID+" (dry-run)")
 			continue
 		}
 
-		if info.ModTime().Before(cutoff) {
-			goalID := strings.TrimSuffix(entry.Name(), ".md")
-
-			if dryRun {
-				result.ArchivedGoals = append(result.ArchivedGoals, goalID+" (dry-run)")
-			} else {
-				// Archive: move to archive subdirectory
-				archiveDir := filepath.Join(historyDir, "archive")
-				if err := os.MkdirAll(archiveDir, 0755); err != nil {
-					result.Errors = append(result.Errors, fmt.Sprintf("Failed to create archive dir: %v", err))
-					continue
-				}
-
-				archivePath := filepath.Join(archiveDir, entry.Name())
-				if err := os.Rename(goalFile, archivePath); err != nil {
-					result.Errors = append(result.Errors, fmt.Sprintf("Failed to archive %s: %v", goalID, err))
-				} else {
-					result.ArchivedGoals = append(result.ArchivedGoals, goalID)
-				}
-
-				// Also archive state file if exists
-				stateFile := filepath.Join(historyDir, goalID+".state.jsonl")
-				if _, err := os.Stat(stateFile); err == nil {
-					os.Rename(stateFile, filepath.Join(archiveDir, goalID+".state.jsonl"))
-				}
-			}
+		// Archive: move to archive subdirectory
+		if _, err := c.layout.MoveGoal(goalID, layout.HistoryDir, layout.ArchiveDir); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to archive %s: %v", goalID, err))
+		} else {
+			result.ArchivedGoals = append(result.ArchivedGoals, goalID)
 		}
+
+		// Also archive state file if exists
+		c.layout.MoveSidecar(goalID, ".state.jsonl", layout.HistoryDir, layout.ArchiveDir)
 	}
+	sort.Strings(result.ArchivedGoals)
 
 	return result
 }
 
 // CleanupGoal performs cleanup for a specific goal (remove worktree, optionally delete branch)
 func (c *CleanupManager) CleanupGoal(goalID, project string, deleteBranch, force bool) error {
-	workspacesDir := filepath.Join(c.vegaDir, "workspaces", project)
-	worktreeBase := filepath.Join(workspacesDir, "worktree-base")
+	workspacesDir := c.layout.ProjectWorkspace(project)
+	worktreeBase := c.layout.WorktreeBase(project)
 
 	// Find the worktree for this goal
 	worktreePath := ""
@@ -254,6 +230,14 @@ func (c *CleanupManager) CleanupGoal(goalID, project string, deleteBranch, force
 		return fmt.Errorf("worktree not found for goal %s", goalID)
 	}
 
+	// A plain project's goal workspace is only a link to the project folder
+	if !isGitWorkspace(worktreeBase) {
+		if info, err := os.Lstat(worktreePath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return os.Remove(worktreePath)
+		}
+		return fmt.Errorf("%s is not a git project", project)
+	}
+
 	// Check for uncommitted changes
 	if !force {
 		cmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
@@ -340,22 +324,11 @@ func (c *CleanupManager) hasGoalFile(goalID string) bool {
 		return false
 	}
 
-	// Check active goals
-	activePath := filepath.Join(c.vegaDir, "goals", "active", goalID+".md")
-	if _, err := os.Stat(activePath); err == nil {
-		return true
-	}
-
-	// Check iced goals
-	icedPath := filepath.Join(c.vegaDir, "goals", "iced", goalID+".md")
-	if _, err := os.Stat(icedPath); err == nil {
-		return true
-	}
-
-	// Check history
-	historyPath := filepath.Join(c.vegaDir, "goals", "history", goalID+".md")
-	if _, err := os.Stat(historyPath); err == nil {
-		return true
+	// Check active goals, iced goals and history
+	for _, dir := range []string{layout.ActiveDir, layout.IcedDir, layout.HistoryDir} {
+		if c.layout.FindGoalFileIn(dir, goalID) != "" {
+			return true
+		}
 	}
 
 	return false
@@ -375,3 +348,10 @@ func extractGoalIDFromPath(path string) string {
 	}
 	return ""
 }
+
+// isGitWorkspace reports whether a project's worktree-base is a git checkout
+// (plain projects are folders without git)
+func isGitWorkspace(worktreeBase string) bool {
+	_, err := os.Stat(filepath.Join(worktreeBase, ".git"))
+	return err == nil
+}
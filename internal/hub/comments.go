@@ -0,0 +1,186 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrCommentNotFound is returned when replying to a comment that doesn't exist
+var ErrCommentNotFound = errors.New("comment not found")
+
+// mentionPattern matches @user mentions in comment text
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// Comment is a user-to-user discussion message on a goal.
+// Comments are separate from executor Q&A and user messages to the executor.
+type Comment struct {
+	ID        string    `json:"id"`
+	GoalID    string    `json:"goal_id"`
+	ParentID  string    `json:"parent_id,omitempty"` // Set for replies
+	User      string    `json:"user,omitempty"`
+	Content   string    `json:"content"`
+	Mentions  []string  `json:"mentions,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentThread is a comment with its nested replies
+type CommentThread struct {
+	*Comment
+	Replies []*CommentThread `json:"replies,omitempty"`
+}
+
+// ParseMentions returns the unique @user mentions in text, in order of appearance
+func ParseMentions(text string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		user := strings.TrimRight(m[1], ".-")
+		if user != "" && !seen[user] {
+			seen[user] = true
+			mentions = append(mentions, user)
+		}
+	}
+	return mentions
+}
+
+// AddComment adds a comment (or a reply when parentID is set) to a goal's discussion.
+// Mentioned users are notified with a "mention" event.
+func (h *Hub) AddComment(goalID, parentID, user, content string) (*Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("comment content is required")
+	}
+
+	if parentID != "" {
+		existing, err := h.GetComments(goalID)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, c := range existing {
+			if c.ID == parentID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, parentID)
+		}
+	}
+
+	comment := &Comment{
+		ID:        fmt.Sprintf("cmt-%d", time.Now().UnixNano()),
+		GoalID:    goalID,
+		ParentID:  parentID,
+		User:      user,
+		Content:   content,
+		Mentions:  ParseMentions(content),
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.history.RecordComment(comment); err != nil {
+		return nil, err
+	}
+
+	h.broadcast(Event{
+		Type: EventCommentAdded,
+		Data: comment,
+	})
+
+	for _, mentioned := range comment.Mentions {
+		if mentioned == user {
+			continue
+		}
+		h.broadcast(Event{
+			Type: "mention",
+			Data: map[string]interface{}{
+				"user":       mentioned,
+				"from":       user,
+				"goal_id":    goalID,
+				"comment_id": comment.ID,
+				"content":    content,
+			},
+		})
+	}
+
+	return comment, nil
+}
+
+// GetComments returns all comments on a goal in chronological order
+func (h *Hub) GetComments(goalID string) ([]*Comment, error) {
+	entries, err := h.history.GetGoalHistory(goalID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*Comment
+	for _, entry := range entries {
+		if entry.Type != "comment" {
+			continue
+		}
+		if c := commentFromEntry(entry); c != nil {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+// GetCommentThreads returns a goal's comments nested by reply
+func (h *Hub) GetCommentThreads(goalID string) ([]*CommentThread, error) {
+	comments, err := h.GetComments(goalID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCommentThreads(comments), nil
+}
+
+// BuildCommentThreads nests comments under their parents. Replies to missing
+// parents are kept as top-level threads.
+func BuildCommentThreads(comments []*Comment) []*CommentThread {
+	nodes := make(map[string]*CommentThread, len(comments))
+	for _, c := range comments {
+		nodes[c.ID] = &CommentThread{Comment: c}
+	}
+
+	threads := make([]*CommentThread, 0)
+	for _, c := range comments {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID]; ok && c.ParentID != "" {
+			parent.Replies = append(parent.Replies, node)
+		} else {
+			threads = append(threads, node)
+		}
+	}
+	return threads
+}
+
+// commentFromEntry rebuilds a comment from its history entry
+func commentFromEntry(entry HistoryEntry) *Comment {
+	data, ok := entry.Data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	c := &Comment{
+		GoalID:    entry.GoalID,
+		User:      entry.User,
+		CreatedAt: entry.Timestamp,
+	}
+	c.ID, _ = data["comment_id"].(string)
+	c.ParentID, _ = data["parent_id"].(string)
+	c.Content, _ = data["content"].(string)
+	if mentions, ok := data["mentions"].([]interface{}); ok {
+		for _, m := range mentions {
+			if s, ok := m.(string); ok {
+				c.Mentions = append(c.Mentions, s)
+			}
+		}
+	}
+	if c.ID == "" {
+		return nil
+	}
+	return c
+}
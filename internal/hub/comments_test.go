@@ -0,0 +1,71 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"no mentions here", nil},
+		{"@alice can you look?", []string{"alice"}},
+		{"cc @bob and @alice, also @bob again", []string{"bob", "alice"}},
+		{"ping @jean-luc.", []string{"jean-luc"}},
+		{"email me at dev@example.com", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ParseMentions(tt.text); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseMentions(%q): expected %v, got %v", tt.text, tt.expected, got)
+		}
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	h := setupTestHub(t)
+	events := h.Subscribe()
+	defer h.Unsubscribe(events)
+
+	root, err := h.AddComment("abc1234", "", "alice", "Should we split this goal? @bob")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if ev := <-events; ev.Type != "comment_added" {
+		t.Errorf("expected comment_added event, got %s", ev.Type)
+	}
+	ev := <-events
+	data, _ := ev.Data.(map[string]interface{})
+	if ev.Type != "mention" || data["user"] != "bob" || data["from"] != "alice" {
+		t.Errorf("expected mention event for bob, got %s %v", ev.Type, ev.Data)
+	}
+
+	reply, err := h.AddComment("abc1234", root.ID, "bob", "Yes, into two phases")
+	if err != nil {
+		t.Fatalf("reply failed: %v", err)
+	}
+
+	if _, err := h.AddComment("abc1234", "cmt-missing", "bob", "orphan"); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+	if _, err := h.AddComment("abc1234", "", "bob", "   "); err == nil {
+		t.Error("expected error for empty comment")
+	}
+
+	threads, err := h.GetCommentThreads("abc1234")
+	if err != nil {
+		t.Fatalf("GetCommentThreads failed: %v", err)
+	}
+	if len(threads) != 1 || threads[0].ID != root.ID {
+		t.Fatalf("expected 1 thread rooted at %s, got %+v", root.ID, threads)
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Errorf("expected reply nested under root, got %+v", threads[0].Replies)
+	}
+	if !reflect.DeepEqual(threads[0].Mentions, []string{"bob"}) || threads[0].User != "alice" {
+		t.Errorf("comment not restored from history: %+v", threads[0].Comment)
+	}
+}
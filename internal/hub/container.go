@@ -0,0 +1,198 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// DefaultContainerImage is used when neither the request nor the project sets an image.
+// The image must provide the claude CLI.
+var DefaultContainerImage = "vega-executor:latest"
+
+// containerRuntimes are the supported container CLIs, in detection order
+var containerRuntimes = []string{"docker", "podman"}
+
+var (
+	cpusPattern   = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	memoryPattern = regexp.MustCompile(`^[0-9]+[bkmgBKMG]?$`)
+)
+
+// ErrNoContainerRuntime is returned when neither docker nor podman is installed
+var ErrNoContainerRuntime = errors.New("no container runtime found (install docker or podman)")
+
+// ContainerOptions requests that an executor runs inside a container.
+// Empty fields fall back to the project's settings (projects/<name>.md:
+// Container Image, Container CPUs, Container Memory), then to defaults.
+type ContainerOptions struct {
+	Runtime string `json:"runtime,omitempty"` // "docker" or "podman" (auto-detected if empty)
+	Image   string `json:"image,omitempty"`
+	CPUs    string `json:"cpus,omitempty"`   // CPU limit, e.g. "2" or "1.5"
+	Memory  string `json:"memory,omitempty"` // Memory limit, e.g. "4g"
+}
+
+// Validate checks runtime and resource limits
+func (o *ContainerOptions) Validate() error {
+	if o.Runtime != "" && !containsString(containerRuntimes, o.Runtime) {
+		return fmt.Errorf("invalid container runtime: %s (valid: docker, podman)", o.Runtime)
+	}
+	if o.CPUs != "" && !cpusPattern.MatchString(o.CPUs) {
+		return fmt.Errorf("invalid cpus: %s", o.CPUs)
+	}
+	if o.Memory != "" && !memoryPattern.MatchString(o.Memory) {
+		return fmt.Errorf("invalid memory: %s", o.Memory)
+	}
+	return nil
+}
+
+// containerRun identifies a running executor container
+type containerRun struct {
+	Runtime string
+	Name    string
+}
+
+// run invokes the container runtime for this container
+func (c *containerRun) run(args ...string) error {
+	out, err := exec.Command(c.Runtime, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %v: %s", c.Runtime, args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// remove force-removes the container (no-op if --rm already removed it)
+func (c *containerRun) remove() {
+	exec.Command(c.Runtime, "rm", "-f", c.Name).Run()
+}
+
+// resolveContainerOptions fills unset options from the project config and defaults
+func (h *Hub) resolveContainerOptions(opts ContainerOptions, project string) (ContainerOptions, error) {
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+
+	if project != "" {
+		if p, err := goals.ParseProject(h.dir, project); err == nil {
+			if opts.Image == "" {
+				opts.Image = p.ContainerImage
+			}
+			if opts.CPUs == "" {
+				opts.CPUs = p.ContainerCPUs
+			}
+			if opts.Memory == "" {
+				opts.Memory = p.ContainerMemory
+			}
+		}
+	}
+	if opts.Image == "" {
+		opts.Image = DefaultContainerImage
+	}
+
+	if opts.Runtime == "" {
+		for _, rt := range containerRuntimes {
+			if _, err := exec.LookPath(rt); err == nil {
+				opts.Runtime = rt
+				break
+			}
+		}
+		if opts.Runtime == "" {
+			return opts, ErrNoContainerRuntime
+		}
+	}
+	return opts, nil
+}
+
+// containerCommand builds the command that runs claude in a container with the
+// working directory mounted at the same path. cwd is where claude starts (a
+// subdirectory of workDir for monorepo projects). The container is named after
+// the session so it can be paused and cleaned up.
+func (h *Hub) containerCommand(opts ContainerOptions, sessionID, workDir, cwd string, args, vegaEnv []string) (*exec.Cmd, *containerRun) {
+	c := &containerRun{
+		Runtime: opts.Runtime,
+		Name:    "vega-executor-" + sessionID,
+	}
+
+	runArgs := []string{
+		"run", "--rm",
+		"--name", c.Name,
+		"--label", "vega-hub.session=" + sessionID,
+		// Run as the host user so files written to the worktree keep their owner
+		"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+		"-v", workDir + ":" + workDir,
+		"-w", cwd,
+	}
+	// Git worktrees point at the main repository's .git directory
+	if gitDir := worktreeCommonDir(workDir); gitDir != "" {
+		runArgs = append(runArgs, "-v", gitDir+":"+gitDir)
+	}
+	if opts.CPUs != "" {
+		runArgs = append(runArgs, "--cpus", opts.CPUs)
+	}
+	if opts.Memory != "" {
+		runArgs = append(runArgs, "--memory", opts.Memory)
+	}
+
+	// Let executor hooks reach vega-hub on the host
+	if h.port > 0 {
+		if runtime.GOOS == "linux" {
+			runArgs = append(runArgs, "--network", "host")
+		} else {
+			vegaEnv = append(vegaEnv, "VEGA_HUB_HOST=host.docker.internal")
+		}
+		vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_HUB_PORT=%d", h.port))
+	}
+	for _, kv := range vegaEnv {
+		runArgs = append(runArgs, "-e", kv)
+	}
+	// Pass credentials through by name so values don't show up in the process list
+	runArgs = append(runArgs, "-e", "ANTHROPIC_API_KEY")
+	// The host user usually has no home directory in the image
+	runArgs = append(runArgs, "-e", "HOME=/tmp")
+
+	runArgs = append(runArgs, opts.Image, "claude")
+	runArgs = append(runArgs, args...)
+
+	cmd := exec.Command(opts.Runtime, runArgs...)
+	cmd.Dir = workDir
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd, c
+}
+
+// worktreeCommonDir returns the shared .git directory of a git worktree, or ""
+// if dir isn't a linked worktree
+func worktreeCommonDir(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return "" // Not a worktree (or .git is a directory)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	// <repo>/.git/worktrees/<name> -> <repo>/.git
+	if filepath.Base(filepath.Dir(gitDir)) == "worktrees" {
+		return filepath.Dir(filepath.Dir(gitDir))
+	}
+	return gitDir
+}
+
+// cleanupContainer removes an executor's container after its process exits
+func cleanupContainer(c *containerRun) {
+	if c == nil {
+		return
+	}
+	c.remove()
+	log.Printf("[EXECUTOR] Removed container %s", c.Name)
+}
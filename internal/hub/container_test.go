@@ -0,0 +1,117 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContainerOptionsValidate(t *testing.T) {
+	valid := ContainerOptions{Runtime: "podman", CPUs: "1.5", Memory: "4g"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid options, got %v", err)
+	}
+
+	for _, bad := range []ContainerOptions{
+		{Runtime: "lxc"},
+		{CPUs: "two"},
+		{Memory: "4 GB"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestResolveContainerOptions(t *testing.T) {
+	h := setupTestHub(t)
+	os.MkdirAll(filepath.Join(h.dir, "projects"), 0755)
+	project := "# my-api\n\n**Container Image**: `node:20`\n**Container CPUs**: `2`\n**Container Memory**: `4g`\n"
+	os.WriteFile(filepath.Join(h.dir, "projects", "my-api.md"), []byte(project), 0644)
+
+	opts, err := h.resolveContainerOptions(ContainerOptions{Runtime: "docker", Memory: "8g"}, "my-api")
+	if err != nil {
+		t.Fatalf("resolveContainerOptions failed: %v", err)
+	}
+	if opts.Image != "node:20" || opts.CPUs != "2" || opts.Memory != "8g" {
+		t.Errorf("expected project defaults with request override, got %+v", opts)
+	}
+
+	opts, _ = h.resolveContainerOptions(ContainerOptions{Runtime: "docker"}, "")
+	if opts.Image != DefaultContainerImage {
+		t.Errorf("expected default image, got %s", opts.Image)
+	}
+}
+
+func TestContainerCommand(t *testing.T) {
+	h := setupTestHub(t)
+	h.SetPort(8080)
+
+	// A linked worktree whose .git file points into the base repository
+	base := filepath.Join(h.dir, "workspaces", "api", "worktree-base", ".git")
+	os.MkdirAll(filepath.Join(base, "worktrees", "goal-abc1234-fix"), 0755)
+	workDir := filepath.Join(h.dir, "workspaces", "api", "goal-abc1234-fix")
+	os.MkdirAll(workDir, 0755)
+	os.WriteFile(filepath.Join(workDir, ".git"), []byte("gitdir: "+filepath.Join(base, "worktrees", "goal-abc1234-fix")+"\n"), 0644)
+
+	opts := ContainerOptions{Runtime: "podman", Image: "node:20", CPUs: "2", Memory: "4g"}
+	cmd, c := h.containerCommand(opts, "session-001", workDir, workDir, []string{"-p", "go"}, []string{"VEGA_GOAL_ID=abc1234"})
+	if c.Name != "vega-executor-session-001" || c.Runtime != "podman" {
+		t.Errorf("unexpected container: %+v", c)
+	}
+
+	args := strings.Join(cmd.Args, " ")
+	for _, want := range []string{
+		"podman run --rm",
+		"-v " + workDir + ":" + workDir,
+		"-v " + base + ":" + base,
+		"--cpus 2", "--memory 4g",
+		"-e VEGA_GOAL_ID=abc1234", "-e VEGA_HUB_PORT=8080",
+		"node:20 claude -p go",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in container args: %s", want, args)
+		}
+	}
+}
+
+func TestSpawnExecutor_Container(t *testing.T) {
+	h := setupTestHub(t)
+
+	// Fake docker records its calls and prints for "run"
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\necho \"$1 $2 $3\" >> "+calls+"\n[ \"$1\" = run ] && echo executor output\nexit 0\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	goalDir := filepath.Join(h.dir, "goals", "active", "abc1234")
+	os.MkdirAll(goalDir, 0755)
+
+	if result := h.SpawnExecutor(SpawnRequest{GoalID: "abc1234", Meta: true, Worker: "build", Container: &ContainerOptions{}}); result.Success {
+		t.Error("expected worker and container to be mutually exclusive")
+	}
+
+	result := h.SpawnExecutor(SpawnRequest{GoalID: "abc1234", Meta: true, Container: &ContainerOptions{Runtime: "docker"}})
+	if !result.Success || result.Container == "" {
+		t.Fatalf("spawn failed: %+v", result)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(h.GetActiveExecutors()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("container executor did not exit")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	output, _ := os.ReadFile(filepath.Join(goalDir, ".executor-output.log"))
+	if !strings.Contains(string(output), "executor output") {
+		t.Errorf("unexpected executor output: %q", output)
+	}
+	recorded, _ := os.ReadFile(calls)
+	if !strings.Contains(string(recorded), "rm -f "+result.Container) {
+		t.Errorf("expected container cleanup after exit, got calls:\n%s", recorded)
+	}
+}
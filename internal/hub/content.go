@@ -0,0 +1,203 @@
+package hub
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Chat content contract: question, answer, message and comment text is
+// markdown (CommonMark with GitHub fenced code blocks). NormalizeContent
+// makes it safe to render: raw HTML is stripped outside code, links can't
+// use script or data URLs, and the size is bounded. Clients render Text with
+// raw HTML disabled and may use CodeBlocks for highlighting or copy buttons.
+
+// ContentFormat is the format of normalized chat content
+const ContentFormat = "markdown"
+
+// MaxContentBytes bounds normalized chat content; longer text is truncated
+const MaxContentBytes = 32 * 1024
+
+// CodeBlock is a fenced code block extracted from chat content, in the
+// order it appears in the text
+type CodeBlock struct {
+	Language string `json:"language,omitempty"`
+	Code     string `json:"code"`
+}
+
+// Content is chat text after NormalizeContent
+type Content struct {
+	Text       string      `json:"text"`
+	CodeBlocks []CodeBlock `json:"code_blocks,omitempty"`
+	Truncated  bool        `json:"truncated,omitempty"` // Cut at MaxContentBytes
+}
+
+var (
+	// Elements removed together with their content
+	dangerousElements   = []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "textarea", "title"}
+	dangerousElementRes = func() []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, tag := range dangerousElements {
+			res = append(res,
+				regexp.MustCompile(`(?is)<`+tag+`\b[^>]*>.*?</`+tag+`\s*>`),
+				regexp.MustCompile(`(?is)<`+tag+`\b.*$`)) // Never closed
+		}
+		return res
+	}()
+
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?(-->|$)`)
+	htmlTagRe     = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9-]*(\s[^<>]*)?/?>`)
+	autolinkRe    = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9+.-]*:[^<>\s]*)>`)
+	linkDefRe     = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*)<?(\S+?)>?(\s|$)`)
+	inlineCodeRe  = regexp.MustCompile("(`+)[^`]+?(`+)")
+	fenceRe       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+)
+
+// NormalizeContent normalizes chat text to the content contract
+func NormalizeContent(text string) Content {
+	var c Content
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || (r < 0x20 && r != '\n' && r != '\t') || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+	if len(text) > MaxContentBytes {
+		cut := MaxContentBytes
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+		c.Truncated = true
+	}
+
+	var out, prose []string
+	flushProse := func() {
+		if len(prose) > 0 {
+			out = append(out, sanitizeProse(strings.Join(prose, "\n")))
+			prose = nil
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		m := fenceRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			prose = append(prose, lines[i])
+			continue
+		}
+		flushProse()
+
+		// Collect the block up to its closing fence (or the end)
+		fence := m[1]
+		block := CodeBlock{Language: m[2]}
+		var code []string
+		j := i + 1
+		for ; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				break
+			}
+			code = append(code, lines[j])
+		}
+		block.Code = strings.Join(code, "\n")
+		c.CodeBlocks = append(c.CodeBlocks, block)
+		out = append(out, fence+block.Language)
+		out = append(out, code...)
+		out = append(out, fence) // Closes blocks cut off by truncation too
+		i = j
+	}
+	flushProse()
+
+	c.Text = strings.Join(out, "\n")
+	if c.Truncated {
+		c.Text += fmt.Sprintf("\n\n*[truncated at %d KB]*", MaxContentBytes/1024)
+	}
+	return c
+}
+
+// sanitizeProse strips HTML and unsafe link targets from markdown outside
+// fenced code blocks. Inline code spans are left as written.
+func sanitizeProse(text string) string {
+	var spans []string
+	text = inlineCodeRe.ReplaceAllStringFunc(text, func(span string) string {
+		spans = append(spans, span)
+		return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+	})
+
+	for _, re := range dangerousElementRes {
+		text = re.ReplaceAllString(text, "")
+	}
+	text = htmlCommentRe.ReplaceAllString(text, "")
+	text = autolinkRe.ReplaceAllStringFunc(text, func(link string) string {
+		if unsafeURL(link[1 : len(link)-1]) {
+			return ""
+		}
+		return link
+	})
+	text = htmlTagRe.ReplaceAllString(text, "")
+	text = sanitizeLinkTargets(text)
+	text = linkDefRe.ReplaceAllStringFunc(text, func(def string) string {
+		m := linkDefRe.FindStringSubmatch(def)
+		if unsafeURL(m[2]) {
+			return m[1] + "#" + m[3]
+		}
+		return def
+	})
+
+	for i, span := range spans {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return text
+}
+
+// sanitizeLinkTargets replaces unsafe inline link and image targets,
+// "[text](target)", with "#". Targets may contain balanced parentheses.
+func sanitizeLinkTargets(text string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(text, "](")
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:i+2])
+		text = text[i+2:]
+
+		end, depth := 0, 0
+		for end < len(text) {
+			c := text[end]
+			if c == '(' {
+				depth++
+			} else if c == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			} else if (c == ' ' || c == '\n' || c == '\t') && end > 0 && depth == 0 {
+				break // Start of a link title
+			}
+			end++
+		}
+		if unsafeURL(strings.Trim(text[:end], "<> \t\n")) {
+			b.WriteString("#")
+			text = text[end:]
+		}
+	}
+}
+
+// unsafeURL reports whether a link target runs script or embeds content.
+// Renderers decode entities in link targets, so they are decoded first.
+func unsafeURL(target string) bool {
+	target = strings.ToLower(strings.Join(strings.Fields(html.UnescapeString(target)), ""))
+	for _, scheme := range []string{"javascript:", "vbscript:", "data:", "file:"} {
+		if strings.HasPrefix(target, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package hub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeContentSanitizes(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain markdown", "Use **v2**, see [docs](https://example.com/doc)", "Use **v2**, see [docs](https://example.com/doc)"},
+		{"script element", "before<script>alert(1)</script>after", "beforeafter"},
+		{"unclosed script", "ok <script>alert(1)", "ok "},
+		{"tags keep text", `<b onclick="x()">bold</b> <img src=x onerror=alert(1)>`, "bold "},
+		{"comment", "a<!-- hidden -->b", "ab"},
+		{"javascript link", "[click](javascript:alert(1))", "[click](#)"},
+		{"entity-encoded scheme", "[click](javascript&#58;alert(1))", "[click](#)"},
+		{"data image", "![x](data:image/svg+xml;base64,AAA)", "![x](#)"},
+		{"unsafe autolink", "<javascript:alert(1)> <https://example.com>", " <https://example.com>"},
+		{"unsafe link definition", "[x]: javascript:alert(1)", "[x]: #"},
+		{"inline code kept", "run `<script>` here", "run `<script>` here"},
+		{"comparison kept", "if a < b and c > d", "if a < b and c > d"},
+		{"control characters", "a\x00b\r\nc\x1b", "ab\nc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeContent(tt.in); got.Text != tt.want {
+				t.Errorf("NormalizeContent(%q) = %q, want %q", tt.in, got.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeContentCodeBlocks(t *testing.T) {
+	in := "Try this:\n```go\nfmt.Println(\"<script>\")\n```\nthen\n~~~\nplain\n~~~"
+	c := NormalizeContent(in)
+	if c.Text != in {
+		t.Errorf("expected code blocks left as written, got %q", c.Text)
+	}
+	if len(c.CodeBlocks) != 2 {
+		t.Fatalf("expected 2 code blocks, got %+v", c.CodeBlocks)
+	}
+	if c.CodeBlocks[0].Language != "go" || c.CodeBlocks[0].Code != `fmt.Println("<script>")` {
+		t.Errorf("unexpected first block %+v", c.CodeBlocks[0])
+	}
+	if c.CodeBlocks[1].Language != "" || c.CodeBlocks[1].Code != "plain" {
+		t.Errorf("unexpected second block %+v", c.CodeBlocks[1])
+	}
+
+	// An unclosed fence is closed
+	if c := NormalizeContent("```sh\nls"); c.Text != "```sh\nls\n```" || c.CodeBlocks[0].Code != "ls" {
+		t.Errorf("expected the fence closed, got %q", c.Text)
+	}
+}
+
+func TestNormalizeContentTruncates(t *testing.T) {
+	c := NormalizeContent("```\n" + strings.Repeat("é", MaxContentBytes))
+	if !c.Truncated {
+		t.Fatal("expected oversized content truncated")
+	}
+	if len(c.CodeBlocks) != 1 || !strings.HasSuffix(c.CodeBlocks[0].Code, "é") {
+		t.Errorf("expected the cut on a rune boundary inside the block")
+	}
+	if !strings.Contains(c.Text, "\n```\n\n*[truncated") {
+		t.Errorf("expected the block closed before the truncation note, got ...%q", c.Text[len(c.Text)-40:])
+	}
+}
@@ -0,0 +1,420 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// Context pack sections, in the order they appear
+const (
+	ContextSession   = "session"    // Session header and executor reminders
+	ContextOverview  = "overview"   // Goal title, overview and acceptance criteria
+	ContextReview    = "review"     // Outstanding changes requested by a reviewer
+	ContextFocus     = "focus"      // Queued instructions to work through first (see GoalFocus)
+	ContextTasks     = "tasks"      // Open tasks from the goal's phases
+	ContextQA        = "qa"         // Recent answered questions
+	ContextDecisions = "decisions"  // Decisions made on other goals of the same projects
+	ContextDocs      = "docs"       // Project configuration docs
+	ContextSessions  = "sessions"   // Summaries of prior executor sessions
+	ContextGoalNotes = "goal_notes" // The goal's notes document (see GoalNotes)
+	ContextNotes     = "notes"      // Per-goal instructions from overrides
+)
+
+// ContextSections lists all sections in render order
+var ContextSections = []string{ContextSession, ContextOverview, ContextReview, ContextFocus, ContextTasks, ContextQA, ContextDecisions, ContextDocs, ContextSessions, ContextGoalNotes, ContextNotes}
+
+// Limits applied when building a context pack
+const (
+	DefaultContextQA        = 5
+	DefaultContextSessions  = 3
+	DefaultContextDecisions = 5
+	maxContextTasks         = 20
+	maxContextDoc           = 4000 // Bytes per project doc
+	maxContextNotes         = 8000 // Bytes of the goal's notes
+)
+
+// ContextSection is one titled block of an executor context pack
+type ContextSection struct {
+	Name       string `json:"name"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	Overridden bool   `json:"overridden,omitempty"` // Content replaced by a per-goal override
+}
+
+// ContextPack is the context handed to an executor at spawn/registration
+type ContextPack struct {
+	GoalID    string            `json:"goal_id"`
+	Sections  []ContextSection  `json:"sections"`
+	Text      string            `json:"text"` // Rendered pack as given to the executor
+	Overrides *ContextOverrides `json:"overrides,omitempty"`
+}
+
+// ContextOverrides customizes the context pack for one goal.
+// Stored in <vega-dir>/.vega-hub-context/goal-<id>.json.
+type ContextOverrides struct {
+	Exclude     []string          `json:"exclude,omitempty"`      // Sections to leave out
+	Sections    map[string]string `json:"sections,omitempty"`     // Replace a section's content
+	Notes       string            `json:"notes,omitempty"`        // Extra instructions (the "notes" section)
+	MaxQA       int               `json:"max_qa,omitempty"`       // Recent Q&A to include (default 5)
+	MaxSessions int               `json:"max_sessions,omitempty"` // Prior sessions to include (default 3)
+}
+
+// Validate checks section names and limits
+func (o *ContextOverrides) Validate() error {
+	for _, name := range o.Exclude {
+		if !containsString(ContextSections, name) {
+			return fmt.Errorf("unknown context section: %s", name)
+		}
+	}
+	for name := range o.Sections {
+		if !containsString(ContextSections, name) {
+			return fmt.Errorf("unknown context section: %s", name)
+		}
+	}
+	if o.MaxQA < 0 || o.MaxSessions < 0 {
+		return fmt.Errorf("max_qa and max_sessions must not be negative")
+	}
+	return nil
+}
+
+// contextOverridesFile returns the overrides path for a goal
+func (h *Hub) contextOverridesFile(goalID string) string {
+	return filepath.Join(h.dir, ".vega-hub-context", fmt.Sprintf("goal-%s.json", goalID))
+}
+
+// GetContextOverrides returns a goal's context overrides (nil if none are set)
+func (h *Hub) GetContextOverrides(goalID string) (*ContextOverrides, error) {
+	data, err := os.ReadFile(h.contextOverridesFile(goalID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read context overrides: %w", err)
+	}
+	var o ContextOverrides
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("failed to parse context overrides: %w", err)
+	}
+	return &o, nil
+}
+
+// SetContextOverrides validates and stores a goal's context overrides.
+// A nil overrides value removes them.
+func (h *Hub) SetContextOverrides(goalID string, o *ContextOverrides) error {
+	path := h.contextOverridesFile(goalID)
+	if o == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove context overrides: %w", err)
+		}
+		return nil
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create context directory: %w", err)
+	}
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal context overrides: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write context overrides: %w", err)
+	}
+	return nil
+}
+
+// BuildContextPack assembles the executor context for a goal: session reminders,
+// goal overview, open tasks, recent Q&A, project docs and prior session summaries,
+// adjusted by the goal's overrides. Missing sources are skipped.
+func (h *Hub) BuildContextPack(goalID, sessionID, cwd string) *ContextPack {
+	pack := &ContextPack{GoalID: goalID}
+
+	overrides, err := h.GetContextOverrides(goalID)
+	if err != nil {
+		overrides = nil // Broken overrides shouldn't block executors
+	}
+	pack.Overrides = overrides
+	o := overrides
+	if o == nil {
+		o = &ContextOverrides{}
+	}
+	maxQA := DefaultContextQA
+	if o.MaxQA > 0 {
+		maxQA = o.MaxQA
+	}
+	maxSessions := DefaultContextSessions
+	if o.MaxSessions > 0 {
+		maxSessions = o.MaxSessions
+	}
+
+	detail, _ := goals.NewParser(h.dir).ParseGoalDetail(goalID)
+
+	builders := map[string]func() (string, string){
+		ContextSession: func() (string, string) {
+			return "Session", h.sessionContext(goalID, cwd)
+		},
+		ContextOverview: func() (string, string) {
+			return "Goal Overview", overviewContext(detail)
+		},
+		ContextReview: func() (string, string) {
+			return "Requested Changes", h.reviewContext(goalID)
+		},
+		ContextFocus: func() (string, string) {
+			return "Focus", h.focusContext(goalID, sessionID)
+		},
+		ContextTasks: func() (string, string) {
+			return "Open Tasks", tasksContext(detail)
+		},
+		ContextQA: func() (string, string) {
+			return "Recent Questions & Answers", h.qaContext(goalID, maxQA)
+		},
+		ContextDecisions: func() (string, string) {
+			return "Earlier Project Decisions", h.decisionsContext(goalID, detail, DefaultContextDecisions)
+		},
+		ContextDocs: func() (string, string) {
+			return "Project Docs", h.docsContext(detail)
+		},
+		ContextSessions: func() (string, string) {
+			return "Previous Sessions", h.sessionsContext(goalID, sessionID, maxSessions)
+		},
+		ContextGoalNotes: func() (string, string) {
+			return "Goal Notes", h.goalNotesContext(goalID)
+		},
+		ContextNotes: func() (string, string) {
+			return "Additional Instructions", strings.TrimSpace(o.Notes)
+		},
+	}
+
+	var text strings.Builder
+	text.WriteString("[EXECUTOR SESSION START]\n")
+	for _, name := range ContextSections {
+		if containsString(o.Exclude, name) {
+			continue
+		}
+		title, content := builders[name]()
+		section := ContextSection{Name: name, Title: title, Content: content}
+		if replacement, ok := o.Sections[name]; ok {
+			section.Content = strings.TrimSpace(replacement)
+			section.Overridden = true
+		}
+		if section.Content == "" {
+			continue
+		}
+		pack.Sections = append(pack.Sections, section)
+		fmt.Fprintf(&text, "\n## %s\n\n%s\n", section.Title, section.Content)
+	}
+	pack.Text = text.String()
+	return pack
+}
+
+// sessionContext is the fixed session header with executor reminders
+func (h *Hub) sessionContext(goalID, cwd string) string {
+	scope := ""
+	if sub := worktreeSubdir(h.dir, cwd); sub != "" {
+		scope = "Scope: " + sub + " (monorepo project: keep changes inside this directory)\n"
+	}
+	return "Working on Goal #" + goalID + "\n" +
+		"Directory: " + cwd + "\n" +
+		scope +
+		"vega-hub: connected\n\n" +
+		"IMPORTANT REMINDERS:\n" +
+		"1. Load 'planning-with-files' skill if not already loaded\n" +
+		"2. Planning files go at worktree root: task_plan.md, findings.md, progress.md\n" +
+		"3. You can use AskUserQuestion to ask the human questions directly (via vega-hub)\n" +
+		"4. Before completing, you MUST:\n" +
+		"   - Archive planning files to docs/planning/history/goal-" + goalID + "/\n" +
+		"   - Commit the archive\n" +
+		"   - Report to manager for approval\n" +
+		"5. Commit messages must include 'Goal: #" + goalID + "'"
+}
+
+// worktreeSubdir returns cwd's path inside its goal worktree
+// (workspaces/<project>/goal-<id>-<slug>/...), or "" at the worktree root
+func worktreeSubdir(vegaDir, cwd string) string {
+	rel, err := filepath.Rel(vegaDir, cwd)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 4 || parts[0] != "workspaces" || !strings.HasPrefix(parts[2], "goal-") {
+		return ""
+	}
+	return strings.Join(parts[3:], "/")
+}
+
+// overviewContext renders the goal title, overview and acceptance criteria
+func overviewContext(detail *goals.GoalDetail) string {
+	if detail == nil {
+		return ""
+	}
+	var b strings.Builder
+	if detail.Title != "" {
+		fmt.Fprintf(&b, "%s\n", detail.Title)
+	}
+	if detail.Overview != "" {
+		fmt.Fprintf(&b, "\n%s\n", detail.Overview)
+	}
+	if len(detail.Acceptance) > 0 {
+		b.WriteString("\nAcceptance criteria:\n")
+		for _, a := range detail.Acceptance {
+			fmt.Fprintf(&b, "- %s\n", a)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// tasksContext lists unchecked tasks grouped by phase
+func tasksContext(detail *goals.GoalDetail) string {
+	if detail == nil {
+		return ""
+	}
+	var b strings.Builder
+	count := 0
+	for _, phase := range detail.Phases {
+		header := false
+		for _, task := range phase.Tasks {
+			if task.Completed {
+				continue
+			}
+			if count == maxContextTasks {
+				b.WriteString("- ...\n")
+				return strings.TrimSpace(b.String())
+			}
+			if !header {
+				fmt.Fprintf(&b, "Phase %d: %s\n", phase.Number, phase.Title)
+				header = true
+			}
+			fmt.Fprintf(&b, "- [ ] %s\n", task.Description)
+			count++
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// qaContext renders the most recent answered questions for the goal
+func (h *Hub) qaContext(goalID string, limit int) string {
+	entries, err := h.history.GetGoalHistory(goalID, 0)
+	if err != nil {
+		return ""
+	}
+
+	var qa []string
+	for i := len(entries) - 1; i >= 0 && len(qa) < limit; i-- {
+		e := entries[i]
+		if e.Type != "question" || e.Answer == "" {
+			continue
+		}
+		qa = append(qa, fmt.Sprintf("Q: %s\nA: %s", strings.TrimSpace(e.Question), strings.TrimSpace(e.Answer)))
+	}
+	// Oldest first reads naturally
+	for i, j := 0, len(qa)-1; i < j; i, j = i+1, j-1 {
+		qa[i], qa[j] = qa[j], qa[i]
+	}
+	return strings.Join(qa, "\n\n")
+}
+
+// goalNotesContext includes the goal's notes, trimmed to maxContextNotes
+func (h *Hub) goalNotesContext(goalID string) string {
+	notes, err := h.GetGoalNotes(goalID)
+	if err != nil {
+		return ""
+	}
+	content := strings.TrimSpace(notes.Content)
+	if len(content) > maxContextNotes {
+		content = strings.TrimSpace(truncateUTF8(content, maxContextNotes)) + "\n..."
+	}
+	return content
+}
+
+// docsContext includes the configuration docs of the goal's projects
+func (h *Hub) docsContext(detail *goals.GoalDetail) string {
+	if detail == nil {
+		return ""
+	}
+	var docs []string
+	for _, project := range detail.Projects {
+		data, err := os.ReadFile(h.Layout().ProjectConfig(project))
+		if err != nil {
+			continue
+		}
+		doc := strings.TrimSpace(string(data))
+		if len(doc) > maxContextDoc {
+			doc = strings.TrimSpace(truncateUTF8(doc, maxContextDoc)) + "\n..."
+		}
+		docs = append(docs, fmt.Sprintf("### %s (projects/%s.md)\n\n%s", project, project, doc))
+	}
+	return strings.Join(docs, "\n\n")
+}
+
+// sessionsContext summarizes the goal's most recent prior sessions
+func (h *Hub) sessionsContext(goalID, currentSession string, limit int) string {
+	sessions, err := h.history.GetGoalSessions(goalID)
+	if err != nil {
+		return ""
+	}
+	activities, _ := h.GetGoalActivity(goalID)
+
+	var prior []*ExecutorSession
+	for _, s := range sessions {
+		if s.SessionID != currentSession && s.StoppedAt != nil {
+			prior = append(prior, s)
+		}
+	}
+	if len(prior) > limit {
+		prior = prior[len(prior)-limit:]
+	}
+
+	var lines []string
+	for _, s := range prior {
+		line := fmt.Sprintf("- %s", s.StartedAt.Format("2006-01-02 15:04"))
+		if s.User != "" {
+			line += " by " + s.User
+		}
+		if s.StopReason != "" {
+			line += " (" + s.StopReason + ")"
+		}
+
+		var files []string
+		testStatus := ""
+		for _, a := range activities {
+			if a.SessionID != s.SessionID {
+				continue
+			}
+			switch a.Kind {
+			case ActivityFileEdit:
+				files = append(files, a.File)
+			case ActivityTestRun:
+				if a.Status != "" {
+					testStatus = a.Status // Last run wins
+				}
+			}
+		}
+		if len(files) > 0 {
+			line += "; edited " + strings.Join(files, ", ")
+		}
+		if testStatus != "" {
+			line += "; last test run " + testStatus
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PreviewContextPack builds the context pack a new executor for the goal would
+// receive, using the goal's worktree (or goal folder) as the directory
+func (h *Hub) PreviewContextPack(goalID string) *ContextPack {
+	cwd, err := h.findWorktree(goalID)
+	if err != nil {
+		cwd = h.Layout().GoalFolder(layout.ActiveDir, goalID)
+	}
+	return h.BuildContextPack(goalID, "", cwd)
+}
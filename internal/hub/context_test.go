@@ -0,0 +1,116 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const contextGoal = `# Goal #abc1234: Fix login
+
+## Overview
+
+Users can't log in after the session refactor.
+
+## Project(s)
+
+- **my-api**: backend
+
+## Phases
+
+### Phase 1: Investigate
+- [x] Reproduce the bug
+- [ ] Find the root cause
+
+### Phase 2: Fix
+- [ ] Patch the session check
+
+## Acceptance Criteria
+
+- [ ] Login works again
+`
+
+func setupContextGoal(t *testing.T) *Hub {
+	t.Helper()
+	h := setupTestHub(t)
+	os.MkdirAll(filepath.Join(h.dir, "goals", "active"), 0755)
+	os.WriteFile(filepath.Join(h.dir, "goals", "active", "abc1234.md"), []byte(contextGoal), 0644)
+	os.MkdirAll(filepath.Join(h.dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(h.dir, "projects", "my-api.md"), []byte("# my-api\n\nRun `make test` before committing.\n"), 0644)
+	return h
+}
+
+func TestBuildContextPack(t *testing.T) {
+	h := setupContextGoal(t)
+
+	h.history.RecordQuestion("abc1234", "session-000", "Which database?", "Postgres")
+	h.RegisterExecutor("abc1234", "session-000", t.TempDir(), "alice")
+	h.history.RecordSessionActivities("abc1234", "session-000", []SessionActivity{
+		{Kind: ActivityFileEdit, SessionID: "session-000", File: "/src/session.go"},
+		{Kind: ActivityTestRun, SessionID: "session-000", Command: "go test ./...", Status: ActivityFailed},
+	})
+	h.StopExecutor("abc1234", "session-000", "completed")
+
+	pack := h.BuildContextPack("abc1234", "session-001", "/work")
+
+	var names []string
+	for _, s := range pack.Sections {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "session,overview,tasks,qa,docs,sessions" {
+		t.Fatalf("unexpected sections: %s", got)
+	}
+
+	for _, want := range []string{
+		"Working on Goal #abc1234",
+		"Users can't log in",
+		"- Login works again",
+		"- [ ] Find the root cause",
+		"Q: Which database?\nA: Postgres",
+		"Run `make test`",
+		"by alice (completed); edited /src/session.go; last test run failed",
+	} {
+		if !strings.Contains(pack.Text, want) {
+			t.Errorf("expected %q in context:\n%s", want, pack.Text)
+		}
+	}
+	if strings.Contains(pack.Text, "Reproduce the bug") {
+		t.Error("completed tasks should not be listed")
+	}
+}
+
+func TestContextOverrides(t *testing.T) {
+	h := setupContextGoal(t)
+
+	if err := h.SetContextOverrides("abc1234", &ContextOverrides{Exclude: []string{"bogus"}}); err == nil {
+		t.Error("expected error for unknown section")
+	}
+
+	err := h.SetContextOverrides("abc1234", &ContextOverrides{
+		Exclude:  []string{ContextDocs},
+		Sections: map[string]string{ContextTasks: "Only fix the session check."},
+		Notes:    "Don't touch the schema.",
+	})
+	if err != nil {
+		t.Fatalf("SetContextOverrides failed: %v", err)
+	}
+
+	pack := h.PreviewContextPack("abc1234")
+	if pack.Overrides == nil || strings.Contains(pack.Text, "make test") {
+		t.Errorf("expected docs excluded:\n%s", pack.Text)
+	}
+	if !strings.Contains(pack.Text, "Only fix the session check.") || strings.Contains(pack.Text, "Find the root cause") {
+		t.Errorf("expected tasks replaced:\n%s", pack.Text)
+	}
+	if !strings.Contains(pack.Text, "## Additional Instructions\n\nDon't touch the schema.") {
+		t.Errorf("expected notes section:\n%s", pack.Text)
+	}
+
+	if err := h.SetContextOverrides("abc1234", nil); err != nil {
+		t.Fatalf("removing overrides failed: %v", err)
+	}
+	if o, _ := h.GetContextOverrides("abc1234"); o != nil {
+		t.Errorf("expected overrides removed, got %+v", o)
+	}
+}
@@ -0,0 +1,217 @@
+package hub
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/pathguard"
+)
+
+// Decision is an answered executor question kept in the decisions index.
+// The goal's title and projects are captured when the answer is given, so
+// the record outlives the goal's files and session history.
+type Decision struct {
+	GoalID       string    `json:"goal_id"`
+	GoalTitle    string    `json:"goal_title,omitempty"`
+	Projects     []string  `json:"projects,omitempty"`
+	SessionID    string    `json:"session_id,omitempty"`
+	Question     string    `json:"question"`
+	Answer       string    `json:"answer"`
+	User         string    `json:"user,omitempty"`          // Who answered
+	AutoAnswered string    `json:"auto_answered,omitempty"` // Rule that answered, if any
+	DecidedAt    time.Time `json:"decided_at"`
+}
+
+// DecisionFilter narrows GetDecisions; empty fields match everything
+type DecisionFilter struct {
+	Project string
+	GoalID  string
+	Query   string // Case-insensitive match on question or answer
+	Since   time.Time
+	Limit   int
+}
+
+// decisionsFile is the decisions index: one JSON decision per line, oldest first
+func (h *Hub) decisionsFile() string {
+	return filepath.Join(h.dir, ".vega-hub-decisions.jsonl")
+}
+
+// recordDecision appends an answered question to the decisions index. The
+// answer must already be in the goal's history: an index built now from
+// history includes it.
+func (h *Hub) recordDecision(goalID, sessionID, question, answer, user, rule string) {
+	d := h.newDecision(goalID, sessionID, question, answer, user, rule, time.Now())
+
+	h.decisionsMu.Lock()
+	defer h.decisionsMu.Unlock()
+	if _, err := os.Stat(h.decisionsFile()); os.IsNotExist(err) {
+		h.ensureDecisions()
+		return
+	}
+	f, err := os.OpenFile(h.decisionsFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if data, err := json.Marshal(d); err == nil {
+		f.Write(append(data, '\n'))
+	}
+}
+
+func (h *Hub) newDecision(goalID, sessionID, question, answer, user, rule string, at time.Time) Decision {
+	d := Decision{
+		GoalID:       goalID,
+		SessionID:    sessionID,
+		Question:     strings.TrimSpace(question),
+		Answer:       strings.TrimSpace(answer),
+		User:         user,
+		AutoAnswered: rule,
+		DecidedAt:    at,
+	}
+	if detail, err := goals.NewParser(h.dir).ParseGoalDetail(goalID); err == nil {
+		d.GoalTitle = detail.Title
+		d.Projects = detail.Projects
+	}
+	return d
+}
+
+// GetDecisions returns decisions matching the filter, newest first. The
+// index is built from Q&A history the first time it is read.
+func (h *Hub) GetDecisions(filter DecisionFilter) ([]Decision, error) {
+	h.decisionsMu.Lock()
+	defer h.decisionsMu.Unlock()
+	if err := h.ensureDecisions(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(h.decisionsFile())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decisions: %w", err)
+	}
+	defer f.Close()
+
+	query := strings.ToLower(filter.Query)
+	var result []Decision
+	scanner := newHistoryScanner(f)
+	for scanner.Scan() {
+		var d Decision
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			continue
+		}
+		if filter.GoalID != "" && d.GoalID != filter.GoalID {
+			continue
+		}
+		if filter.Project != "" && !containsString(d.Projects, filter.Project) {
+			continue
+		}
+		if !filter.Since.IsZero() && d.DecidedAt.Before(filter.Since) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(d.Question), query) && !strings.Contains(strings.ToLower(d.Answer), query) {
+			continue
+		}
+		result = append(result, d)
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// ensureDecisions builds the index from every goal's Q&A history if it
+// doesn't exist yet (caller holds decisionsMu)
+func (h *Hub) ensureDecisions() error {
+	path := h.decisionsFile()
+	if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(h.history.historyDir(), "goal-*.jsonl*"))
+	seen := make(map[string]bool)
+	var decisions []Decision
+	for _, match := range matches {
+		goalID := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), "goal-"), ".gz"), ".jsonl")
+		if seen[goalID] || pathguard.ValidateID("goal ID", goalID) != nil {
+			continue
+		}
+		seen[goalID] = true
+		entries, err := h.history.GetGoalHistory(goalID, 0)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.Type != "question" || strings.TrimSpace(e.Answer) == "" {
+				continue
+			}
+			rule := ""
+			if data, ok := e.Data.(map[string]interface{}); ok {
+				rule, _ = data["auto_answered_by"].(string)
+			}
+			decisions = append(decisions, h.newDecision(goalID, e.SessionID, e.Question, e.Answer, e.User, rule, e.Timestamp))
+		}
+	}
+	sort.SliceStable(decisions, func(i, j int) bool { return decisions[i].DecidedAt.Before(decisions[j].DecidedAt) })
+
+	err := atomicfile.Write(path, 0644, func(f *os.File) error {
+		w := bufio.NewWriter(f)
+		enc := json.NewEncoder(w)
+		for _, d := range decisions {
+			enc.Encode(d)
+		}
+		return w.Flush()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write decisions index: %w", err)
+	}
+	return nil
+}
+
+// decisionsContext lists recent decisions made on other goals of the same
+// projects, so executors don't reopen questions already settled
+func (h *Hub) decisionsContext(goalID string, detail *goals.GoalDetail, limit int) string {
+	if detail == nil || len(detail.Projects) == 0 {
+		return ""
+	}
+	var lines []string
+	seen := make(map[string]bool)
+	for _, project := range detail.Projects {
+		decisions, err := h.GetDecisions(DecisionFilter{Project: project})
+		if err != nil {
+			return ""
+		}
+		for _, d := range decisions {
+			if len(lines) == limit {
+				break
+			}
+			key := d.GoalID + "\x00" + d.Question
+			if d.GoalID == goalID || seen[key] {
+				continue
+			}
+			seen[key] = true
+			title := d.GoalID
+			if d.GoalTitle != "" {
+				title = fmt.Sprintf("%s (%s)", d.GoalTitle, d.GoalID)
+			}
+			lines = append(lines, fmt.Sprintf("- %s, %s\n  Q: %s\n  A: %s",
+				title, d.DecidedAt.Format("2006-01-02"), oneLine(d.Question), oneLine(d.Answer)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// oneLine collapses whitespace so multi-line text fits a list item
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,79 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDecisionGoal(t *testing.T, dir, id, title, project string) {
+	t.Helper()
+	goalDir := filepath.Join(dir, "goals", "active", id)
+	if err := os.MkdirAll(goalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "# Goal #" + id + ": " + title + "\n\n## Project(s)\n\n- **" + project + "**\n"
+	if err := os.WriteFile(filepath.Join(goalDir, id+".md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecisions(t *testing.T) {
+	dir := t.TempDir()
+	writeDecisionGoal(t, dir, "aaa1111", "Pick a database", "api")
+	writeDecisionGoal(t, dir, "bbb2222", "Add caching", "api")
+	writeDecisionGoal(t, dir, "ccc3333", "Redo the docs", "docs")
+	h := New(dir)
+
+	// Answers given before the index existed are backfilled from history
+	h.history.RecordQuestion("aaa1111", "s1", "Postgres or SQLite?", "Postgres, we need concurrent writers")
+	h.history.RecordAutoAnsweredQuestion("ccc3333", "s2", "Use mkdocs?", "yes", "docs-rule")
+
+	h.history.RecordStructuredQuestion("bbb2222", "s3", "alice", "Cache in Redis?", "No, in-process LRU", nil, nil, nil)
+	h.recordDecision("bbb2222", "s3", "Cache in Redis?", "No, in-process LRU", "alice", "")
+
+	all, err := h.GetDecisions(DecisionFilter{})
+	if err != nil {
+		t.Fatalf("GetDecisions: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 decisions, got %+v", all)
+	}
+	if all[0].GoalID != "bbb2222" || all[0].User != "alice" || all[0].GoalTitle != "Add caching" {
+		t.Errorf("expected the newest decision first, got %+v", all[0])
+	}
+
+	api, _ := h.GetDecisions(DecisionFilter{Project: "api"})
+	if len(api) != 2 {
+		t.Errorf("expected 2 api decisions, got %+v", api)
+	}
+	docs, _ := h.GetDecisions(DecisionFilter{Project: "docs"})
+	if len(docs) != 1 || docs[0].AutoAnswered != "docs-rule" {
+		t.Errorf("expected the auto-answered docs decision, got %+v", docs)
+	}
+	found, _ := h.GetDecisions(DecisionFilter{Query: "POSTGRES"})
+	if len(found) != 1 || found[0].GoalID != "aaa1111" {
+		t.Errorf("expected the database decision, got %+v", found)
+	}
+	if limited, _ := h.GetDecisions(DecisionFilter{Limit: 1}); len(limited) != 1 {
+		t.Errorf("expected the limit applied, got %d", len(limited))
+	}
+
+	// The index outlives the goal's history
+	os.RemoveAll(filepath.Join(dir, ".vega-hub-history"))
+	os.RemoveAll(filepath.Join(dir, "goals", "active", "aaa1111"))
+	if got, _ := h.GetDecisions(DecisionFilter{GoalID: "aaa1111"}); len(got) != 1 || got[0].GoalTitle != "Pick a database" {
+		t.Errorf("expected the decision kept after archival, got %+v", got)
+	}
+
+	// Executors of other goals in the project see it; their own and other
+	// projects' decisions are left out
+	got := h.BuildContextPack("bbb2222", "s4", dir).Text
+	if !strings.Contains(got, "Postgres, we need concurrent writers") || !strings.Contains(got, "Pick a database (aaa1111)") {
+		t.Errorf("expected the database decision in the context, got %q", got)
+	}
+	if strings.Contains(got, "A: No, in-process LRU") || strings.Contains(got, "mkdocs") {
+		t.Errorf("expected only other goals' api decisions, got %q", got)
+	}
+}
@@ -0,0 +1,400 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+const (
+	DefaultDigestInterval = 24 * time.Hour
+	digestCheckInterval   = time.Minute // How often the scheduler checks whether a digest is due
+)
+
+// sendMail delivers an email (overridden in tests)
+var sendMail = smtp.SendMail
+
+// ErrDigestNotConfigured is returned when sending without SMTP settings
+var ErrDigestNotConfigured = errors.New("digest SMTP settings are not configured")
+
+// SMTPConfig holds the mail server digests are sent through
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"` // Defaults to 587
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"` // Falls back to VEGA_HUB_SMTP_PASSWORD
+	From     string `json:"from"`
+}
+
+// DigestUser is a user who opted in to digest emails
+type DigestUser struct {
+	Name       string `json:"name"` // Matches question assignees (see QuestionRule.AssignTo)
+	Email      string `json:"email"`
+	Subscribed bool   `json:"subscribed"`
+}
+
+// DigestConfig is the on-disk format of <vega-dir>/.vega-hub-digest.json
+type DigestConfig struct {
+	SMTP     SMTPConfig   `json:"smtp"`
+	Interval string       `json:"interval,omitempty"` // Go duration, defaults to 24h
+	HubURL   string       `json:"hub_url,omitempty"`  // Base URL for links in emails
+	Users    []DigestUser `json:"users,omitempty"`
+}
+
+// interval returns the configured digest interval
+func (c *DigestConfig) interval() time.Duration {
+	if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+		return d
+	}
+	return DefaultDigestInterval
+}
+
+// Validate checks the interval and users
+func (c *DigestConfig) Validate() error {
+	if c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid interval %q", c.Interval)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, u := range c.Users {
+		if u.Name == "" {
+			return fmt.Errorf("user name is required")
+		}
+		if !strings.Contains(u.Email, "@") {
+			return fmt.Errorf("user %s: invalid email %q", u.Name, u.Email)
+		}
+		if seen[u.Name] {
+			return fmt.Errorf("duplicate user: %s", u.Name)
+		}
+		seen[u.Name] = true
+	}
+	return nil
+}
+
+// digestConfigPath returns the digest config path
+func digestConfigPath(dir string) string {
+	return filepath.Join(dir, ".vega-hub-digest.json")
+}
+
+// LoadDigestConfig reads <vega-dir>/.vega-hub-digest.json.
+// A missing file means digests are not configured.
+func LoadDigestConfig(dir string) (*DigestConfig, error) {
+	cfg := &DigestConfig{}
+	data, err := os.ReadFile(digestConfigPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read digest config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse digest config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// digestConfigMu serializes read-modify-write updates of the digest config
+var digestConfigMu sync.Mutex
+
+// SetDigestSubscription opts a user in or out of digest emails, adding them to
+// the config if needed. An empty email keeps the user's current address.
+func (h *Hub) SetDigestSubscription(name, email string, subscribed bool) (*DigestUser, error) {
+	digestConfigMu.Lock()
+	defer digestConfigMu.Unlock()
+
+	cfg, err := LoadDigestConfig(h.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *DigestUser
+	for i := range cfg.Users {
+		if cfg.Users[i].Name == name {
+			user = &cfg.Users[i]
+			break
+		}
+	}
+	if user == nil {
+		cfg.Users = append(cfg.Users, DigestUser{Name: name})
+		user = &cfg.Users[len(cfg.Users)-1]
+	}
+	if email != "" {
+		user.Email = email
+	}
+	user.Subscribed = subscribed
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal digest config: %w", err)
+	}
+	path := digestConfigPath(h.dir)
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil { // May hold the SMTP password
+		return nil, fmt.Errorf("failed to write digest config: %w", err)
+	}
+	result := *user
+	return &result, nil
+}
+
+// DigestGoal is a goal waiting on the digest's user
+type DigestGoal struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Questions int    `json:"questions"` // Pending questions for the user
+}
+
+// Digest summarizes what needs a user's attention
+type Digest struct {
+	User         string                `json:"user"`
+	Since        time.Time             `json:"since"`
+	GeneratedAt  time.Time             `json:"generated_at"`
+	Questions    []*Question           `json:"questions"`     // Assigned to the user or unassigned
+	WaitingGoals []DigestGoal          `json:"waiting_goals"` // Goals blocked on those questions
+	StuckGoals   []goals.StuckGoal     `json:"stuck_goals"`
+	Completed    []goals.RegistryEntry `json:"completed"` // Completed on or after Since
+}
+
+// Empty returns true if the digest has nothing to report
+func (d *Digest) Empty() bool {
+	return len(d.Questions) == 0 && len(d.StuckGoals) == 0 && len(d.Completed) == 0
+}
+
+// BuildDigest collects a user's pending questions and the goals waiting on
+// them, plus the hub's stuck goals and goals completed since the given time.
+// Unassigned questions are included for every user.
+func (h *Hub) BuildDigest(user string, since time.Time) (*Digest, error) {
+	d := &Digest{
+		User:         user,
+		Since:        since,
+		GeneratedAt:  time.Now(),
+		Questions:    []*Question{},
+		WaitingGoals: []DigestGoal{},
+		StuckGoals:   []goals.StuckGoal{},
+		Completed:    []goals.RegistryEntry{},
+	}
+
+	registry := goals.NewRegistry(h.dir)
+	waiting := make(map[string]int)
+	for _, q := range h.GetPendingQuestions() {
+		if len(q.AssignedTo) > 0 && !q.IsAssignedTo(user) {
+			continue
+		}
+		d.Questions = append(d.Questions, q)
+		if waiting[q.GoalID] == 0 {
+			d.WaitingGoals = append(d.WaitingGoals, DigestGoal{ID: q.GoalID})
+		}
+		waiting[q.GoalID]++
+	}
+	for i := range d.WaitingGoals {
+		g := &d.WaitingGoals[i]
+		g.Questions = waiting[g.ID]
+		if entry, err := registry.Get(g.ID); err == nil {
+			g.Title = entry.Title
+		}
+	}
+
+	stuck, err := h.FindStuckGoals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stuck goals: %w", err)
+	}
+	d.StuckGoals = append(d.StuckGoals, stuck...)
+
+	// CompletedAt is a date, so compare at day granularity
+	sinceDay := since.Format("2006-01-02")
+	completed, err := registry.List(func(e goals.RegistryEntry) bool {
+		return e.Status == "completed" && e.CompletedAt >= sinceDay
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].CompletedAt > completed[j].CompletedAt
+	})
+	d.Completed = append(d.Completed, completed...)
+	return d, nil
+}
+
+// RenderDigest formats a digest as a plain-text email
+func RenderDigest(d *Digest, hubURL string) (subject, body string) {
+	subject = fmt.Sprintf("vega-hub digest: %d pending question(s), %d stuck goal(s)", len(d.Questions), len(d.StuckGoals))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nHere's what happened since %s.\n", d.User, d.Since.Format("Mon Jan 2 15:04"))
+
+	goalLink := func(id string) string {
+		if hubURL == "" {
+			return ""
+		}
+		return " " + strings.TrimSuffix(hubURL, "/") + "/goals/" + id
+	}
+
+	if len(d.Questions) > 0 {
+		fmt.Fprintf(&b, "\nPending questions (%d)\n", len(d.Questions))
+		for _, q := range d.Questions {
+			fmt.Fprintf(&b, "  - [%s] %s (asked %s)\n", q.GoalID, q.Question, q.CreatedAt.Format("Jan 2 15:04"))
+		}
+	}
+	if len(d.WaitingGoals) > 0 {
+		fmt.Fprintf(&b, "\nGoals waiting on you (%d)\n", len(d.WaitingGoals))
+		for _, g := range d.WaitingGoals {
+			fmt.Fprintf(&b, "  - %s %s: %d question(s)%s\n", g.ID, g.Title, g.Questions, goalLink(g.ID))
+		}
+	}
+	if len(d.StuckGoals) > 0 {
+		fmt.Fprintf(&b, "\nStuck goals (%d)\n", len(d.StuckGoals))
+		for _, g := range d.StuckGoals {
+			fmt.Fprintf(&b, "  - %s: %s for %s%s\n", g.GoalID, g.State, g.Duration.Round(time.Minute), goalLink(g.GoalID))
+		}
+	}
+	if len(d.Completed) > 0 {
+		fmt.Fprintf(&b, "\nRecently completed (%d)\n", len(d.Completed))
+		for _, g := range d.Completed {
+			fmt.Fprintf(&b, "  - %s %s (%s)\n", g.ID, g.Title, g.CompletedAt)
+		}
+	}
+
+	b.WriteString("\nTo stop these emails, unsubscribe in vega-hub or set \"subscribed\": false in .vega-hub-digest.json.\n")
+	return subject, b.String()
+}
+
+// sendDigestEmail delivers one email through the configured SMTP server
+func sendDigestEmail(cfg SMTPConfig, to, subject, body string) error {
+	if cfg.Host == "" || cfg.From == "" {
+		return ErrDigestNotConfigured
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	password := cfg.Password
+	if password == "" {
+		password = os.Getenv("VEGA_HUB_SMTP_PASSWORD")
+	}
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, password, cfg.Host)
+	}
+
+	msg := "From: " + cfg.From + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + strings.ReplaceAll(body, "\n", "\r\n")
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	return sendMail(addr, auth, cfg.From, []string{to}, []byte(msg))
+}
+
+// DigestResult reports one digest send
+type DigestResult struct {
+	User    string `json:"user"`
+	Email   string `json:"email"`
+	Sent    bool   `json:"sent"`
+	Skipped bool   `json:"skipped,omitempty"` // Nothing to report
+	Error   string `json:"error,omitempty"`
+}
+
+// SendDigests emails a digest covering the given time to every subscribed
+// user (or only to the named user if user is set). Empty digests are skipped.
+func (h *Hub) SendDigests(user string, since time.Time) ([]DigestResult, error) {
+	cfg, err := LoadDigestConfig(h.dir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SMTP.Host == "" {
+		return nil, ErrDigestNotConfigured
+	}
+
+	results := []DigestResult{}
+	for _, u := range cfg.Users {
+		if !u.Subscribed || (user != "" && u.Name != user) {
+			continue
+		}
+		result := DigestResult{User: u.Name, Email: u.Email}
+		d, err := h.BuildDigest(u.Name, since)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case d.Empty():
+			result.Skipped = true
+		default:
+			subject, body := RenderDigest(d, cfg.HubURL)
+			if err := sendDigestEmail(cfg.SMTP, u.Email, subject, body); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Sent = true
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// DigestPeriodStart returns the start of the period the next digest covers
+func (h *Hub) DigestPeriodStart() time.Time {
+	cfg, err := LoadDigestConfig(h.dir)
+	if err != nil {
+		cfg = &DigestConfig{}
+	}
+	return time.Now().Add(-cfg.interval())
+}
+
+// StartDigestScheduler sends digests every configured interval until stop is
+// closed. The config is re-read on every check, so digests can be set up or
+// changed without restarting.
+func (h *Hub) StartDigestScheduler(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+
+			cfg, err := LoadDigestConfig(h.dir)
+			if err != nil {
+				log.Printf("[DIGEST] %v", err)
+				continue
+			}
+			if cfg.SMTP.Host == "" || time.Since(last) < cfg.interval() {
+				continue
+			}
+			results, err := h.SendDigests("", last)
+			last = time.Now()
+			if err != nil {
+				log.Printf("[DIGEST] %v", err)
+				continue
+			}
+			for _, r := range results {
+				if r.Error != "" {
+					log.Printf("[DIGEST] %s <%s>: %s", r.User, r.Email, r.Error)
+				} else if r.Sent {
+					log.Printf("[DIGEST] Sent to %s <%s>", r.User, r.Email)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,185 @@
+package hub
+
+import (
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func writeDigestConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".vega-hub-digest.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDigestConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadDigestConfig(dir)
+	if err != nil || cfg.SMTP.Host != "" || cfg.interval() != DefaultDigestInterval {
+		t.Fatalf("expected empty config without a file, got %+v (err: %v)", cfg, err)
+	}
+
+	writeDigestConfig(t, dir, `{"smtp":{"host":"mail.lan","from":"hub@lan"},"interval":"12h",
+		"users":[{"name":"alex","email":"alex@lan","subscribed":true}]}`)
+	cfg, err = LoadDigestConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadDigestConfig failed: %v", err)
+	}
+	if cfg.interval() != 12*time.Hour || len(cfg.Users) != 1 || !cfg.Users[0].Subscribed {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	for _, bad := range []string{
+		`{"interval":"daily"}`,
+		`{"users":[{"name":"alex","email":"nope"}]}`,
+		`{"users":[{"name":"alex","email":"a@lan"},{"name":"alex","email":"b@lan"}]}`,
+	} {
+		writeDigestConfig(t, dir, bad)
+		if _, err := LoadDigestConfig(dir); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestSetDigestSubscription(t *testing.T) {
+	h := setupTestHub(t)
+
+	if _, err := h.SetDigestSubscription("alex", "", true); err == nil {
+		t.Error("expected an email to be required for a new user")
+	}
+	if _, err := h.SetDigestSubscription("alex", "alex@lan", true); err != nil {
+		t.Fatal(err)
+	}
+	// Opting out keeps the address
+	user, err := h.SetDigestSubscription("alex", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "alex@lan" || user.Subscribed {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	cfg, err := LoadDigestConfig(h.dir)
+	if err != nil || len(cfg.Users) != 1 || cfg.Users[0].Subscribed {
+		t.Errorf("expected saved opt-out, got %+v (err: %v)", cfg, err)
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	h := setupTestHub(t)
+	os.MkdirAll(filepath.Join(h.dir, "goals"), 0755)
+	registry := goals.NewRegistry(h.dir)
+	today := time.Now().Format("2006-01-02")
+	for _, e := range []goals.RegistryEntry{
+		{ID: "aaa1111", Title: "Deploy pipeline", Status: "active"},
+		{ID: "bbb2222", Title: "Fix login", Status: "completed", CompletedAt: today},
+		{ID: "ccc3333", Title: "Old work", Status: "completed", CompletedAt: "2020-01-01"},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Rules().Add(&QuestionRule{ID: "deploy", Pattern: "(?i)deploy", AssignTo: []string{"ops"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, q := range []*Question{
+		{ID: "q1", GoalID: "aaa1111", Question: "Deploy to staging?"},
+		{ID: "q2", GoalID: "aaa1111", Question: "Which region?"},
+	} {
+		wg.Add(1)
+		go func(q *Question) {
+			defer wg.Done()
+			h.Ask(q)
+		}(q)
+	}
+	time.Sleep(50 * time.Millisecond)
+	defer func() {
+		h.Answer("q1", "yes")
+		h.Answer("q2", "eu")
+		wg.Wait()
+	}()
+
+	ops, err := h.BuildDigest("ops", time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops.Questions) != 2 {
+		t.Errorf("expected assigned and unassigned questions for ops, got %d", len(ops.Questions))
+	}
+	if len(ops.WaitingGoals) != 1 || ops.WaitingGoals[0].Title != "Deploy pipeline" || ops.WaitingGoals[0].Questions != 2 {
+		t.Errorf("unexpected waiting goals: %+v", ops.WaitingGoals)
+	}
+	if len(ops.Completed) != 1 || ops.Completed[0].ID != "bbb2222" {
+		t.Errorf("expected only recently completed goals, got %+v", ops.Completed)
+	}
+
+	// Questions routed to someone else are left out
+	dev, err := h.BuildDigest("dev", time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.Questions) != 1 || dev.Questions[0].ID != "q2" {
+		t.Errorf("expected only the unassigned question for dev, got %+v", dev.Questions)
+	}
+
+	_, body := RenderDigest(ops, "https://hub.lan/")
+	for _, want := range []string{"Pending questions (2)", "https://hub.lan/goals/aaa1111", "Recently completed (1)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in digest body:\n%s", want, body)
+		}
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	h := setupTestHub(t)
+	os.MkdirAll(filepath.Join(h.dir, "goals"), 0755)
+	registry := goals.NewRegistry(h.dir)
+	if err := registry.Add(goals.RegistryEntry{ID: "bbb2222", Title: "Fix login", Status: "completed", CompletedAt: time.Now().Format("2006-01-02")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.SendDigests("", time.Now()); err != ErrDigestNotConfigured {
+		t.Errorf("expected ErrDigestNotConfigured, got %v", err)
+	}
+
+	var sent []string
+	orig := sendMail
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "mail.lan:2525" || from != "hub@lan" {
+			t.Errorf("unexpected server %s or sender %s", addr, from)
+		}
+		sent = append(sent, to[0])
+		return nil
+	}
+	defer func() { sendMail = orig }()
+
+	writeDigestConfig(t, h.dir, `{"smtp":{"host":"mail.lan","port":2525,"from":"hub@lan"},"users":[
+		{"name":"alex","email":"alex@lan","subscribed":true},
+		{"name":"sam","email":"sam@lan","subscribed":false}]}`)
+	results, err := h.SendDigests("", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Sent || len(sent) != 1 || sent[0] != "alex@lan" {
+		t.Errorf("expected one digest to alex, got %+v (sent: %v)", results, sent)
+	}
+
+	// Nothing completed since tomorrow: the digest is skipped
+	results, err = h.SendDigests("alex", time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Skipped || len(sent) != 1 {
+		t.Errorf("expected empty digest to be skipped, got %+v", results)
+	}
+}
@@ -0,0 +1,130 @@
+package hub
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// maxEventLogSize is the size at which events.jsonl is rotated to events.jsonl.1
+const maxEventLogSize = 50 << 20
+
+// EventLog is a bus consumer that appends every event to
+// .vega-hub-history/events.jsonl, one JSON object per line, so external tools
+// can tail the event history and the hub can restore its state on restart
+type EventLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewEventLog creates an event log under the vega directory
+func NewEventLog(dir string) *EventLog {
+	return &EventLog{path: EventLogPath(dir)}
+}
+
+// EventLogPath returns the path of the event log for a vega directory
+func EventLogPath(dir string) string {
+	return filepath.Join(dir, ".vega-hub-history", "events.jsonl")
+}
+
+// Name implements Consumer
+func (l *EventLog) Name() string { return "event_log" }
+
+// Consume implements Consumer
+func (l *EventLog) Consume(event Event) {
+	if err := l.append(event); err != nil {
+		log.Printf("[EVENTS] Failed to log event %d (%s): %v", event.ID, event.Type, err)
+	}
+}
+
+func (l *EventLog) append(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if info, err := os.Stat(l.path); err == nil && info.Size() >= maxEventLogSize {
+		os.Rename(l.path, l.path+".1")
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history dir: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// ReadEventLog returns logged events with an ID above sinceID, oldest first,
+// including the rotated log. With limit > 0 only the newest limit events are
+// returned.
+func ReadEventLog(path string, sinceID uint64, limit int) ([]Event, error) {
+	var events []Event
+	err := scanEventLog(path, func(event Event) {
+		if event.ID <= sinceID {
+			return
+		}
+		events = append(events, event)
+		if limit > 0 && len(events) > 2*limit {
+			events = append(events[:0], events[len(events)-limit:]...)
+		}
+	})
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, err
+}
+
+// scanEventLog calls fn for each logged event, oldest first. Unreadable lines
+// (e.g. a partial write before a crash) are skipped.
+func scanEventLog(path string, fn func(Event)) error {
+	for _, p := range []string{path + ".1", path} {
+		file, err := os.Open(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to open event log: %w", err)
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			var event Event
+			if err := json.Unmarshal(scanner.Bytes(), &event); err == nil && event.ID > 0 {
+				fn(event)
+			}
+		}
+		file.Close()
+	}
+	return nil
+}
+
+// restoreFromEventLog rebuilds state derived from past events after a restart
+// or crash: the SSE replay buffer is refilled and per-type metrics recounted.
+// It returns the last logged event ID so new IDs continue from there.
+func (h *Hub) restoreFromEventLog() uint64 {
+	var lastID uint64
+	err := scanEventLog(EventLogPath(h.dir), func(event Event) {
+		h.metrics.Consume(event)
+		h.remember(event)
+		if event.ID > lastID {
+			lastID = event.ID
+		}
+	})
+	if err != nil {
+		log.Printf("[EVENTS] Failed to read event log: %v", err)
+	}
+	h.lastEventID = lastID
+	return lastID
+}
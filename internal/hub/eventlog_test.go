@@ -0,0 +1,90 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEventLog(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir)
+	h.EmitEvent("goal_created", map[string]string{"goal_id": "abc"})
+	h.EmitEvent("goal_completed", map[string]string{"goal_id": "abc"})
+	h.EmitEvent("goal_created", map[string]string{"goal_id": "def"})
+
+	events, err := ReadEventLog(EventLogPath(dir), 0, 0)
+	if err != nil {
+		t.Fatalf("ReadEventLog failed: %v", err)
+	}
+	if len(events) != 3 || events[0].Type != "goal_created" || events[2].ID != 3 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	data, ok := events[1].Data.(map[string]interface{})
+	if !ok || data["goal_id"] != "abc" {
+		t.Errorf("expected event data to round-trip, got %#v", events[1].Data)
+	}
+
+	since, _ := ReadEventLog(EventLogPath(dir), 1, 0)
+	if len(since) != 2 || since[0].ID != 2 {
+		t.Errorf("expected events after 1, got %+v", since)
+	}
+	newest, _ := ReadEventLog(EventLogPath(dir), 0, 1)
+	if len(newest) != 1 || newest[0].ID != 3 {
+		t.Errorf("expected only the newest event, got %+v", newest)
+	}
+}
+
+func TestEventLog_RestoreAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir)
+	for i := 0; i < 3; i++ {
+		h.EmitEvent("goal_updated", nil)
+	}
+
+	// Simulate a crash mid-write
+	f, _ := os.OpenFile(EventLogPath(dir), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"id":4,"type":"goal_upd`)
+	f.Close()
+
+	restarted := New(dir)
+	if restarted.LastEventID() != 3 {
+		t.Errorf("expected last event ID 3 after restart, got %d", restarted.LastEventID())
+	}
+	if restarted.EventStats()["goal_updated"].Count != 3 {
+		t.Errorf("expected restored metrics, got %+v", restarted.EventStats())
+	}
+
+	// Clients can resume across the restart
+	ch, missed, complete := restarted.SubscribeSince(1)
+	defer restarted.Unsubscribe(ch)
+	if !complete || len(missed) != 2 {
+		t.Errorf("expected to replay 2 events, got %d (complete=%v)", len(missed), complete)
+	}
+
+	restarted.EmitEvent("goal_updated", nil)
+	if e := <-ch; e.ID != 4 {
+		t.Errorf("expected IDs to continue at 4, got %d", e.ID)
+	}
+}
+
+func TestEventLog_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := EventLogPath(dir)
+	os.MkdirAll(filepath.Dir(path), 0755)
+
+	// An oversized log is rotated on the next append
+	big := make([]byte, maxEventLogSize)
+	os.WriteFile(path, big, 0644)
+
+	l := NewEventLog(dir)
+	l.Consume(Event{ID: 1, Type: "goal_created"})
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated log: %v", err)
+	}
+	events, _ := ReadEventLog(path, 0, 0)
+	if len(events) != 1 || events[0].ID != 1 {
+		t.Errorf("expected the new event after rotation, got %d events", len(events))
+	}
+}
@@ -0,0 +1,45 @@
+package hub
+
+// eventBufferSize is how many recent events are kept for clients that
+// reconnect with Last-Event-ID
+const eventBufferSize = 1000
+
+// remember appends an event to the replay buffer, dropping the oldest once
+// full. Callers hold subMu.
+func (h *Hub) remember(event Event) {
+	if len(h.recent) >= eventBufferSize {
+		copy(h.recent, h.recent[1:])
+		h.recent = h.recent[:len(h.recent)-1]
+	}
+	h.recent = append(h.recent, event)
+}
+
+// SubscribeSince subscribes like Subscribe and also returns the buffered
+// events broadcast after lastID, with no gap or overlap between the two.
+// complete is false if some of those events were already dropped from the
+// buffer, in which case the client should reload its state.
+func (h *Hub) SubscribeSince(lastID uint64) (ch chan Event, missed []Event, complete bool) {
+	ch = make(chan Event, 10)
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+	h.subscribers[ch] = true
+
+	if lastID > h.lastEventID {
+		// An ID from before a restart: the events since are gone
+		return ch, nil, false
+	}
+	for _, e := range h.recent {
+		if e.ID > lastID {
+			missed = append(missed, e)
+		}
+	}
+	complete = lastID == h.lastEventID || (len(missed) > 0 && missed[0].ID == lastID+1)
+	return ch, missed, complete
+}
+
+// LastEventID returns the ID of the most recently broadcast event
+func (h *Hub) LastEventID() uint64 {
+	h.subMu.RLock()
+	defer h.subMu.RUnlock()
+	return h.lastEventID
+}
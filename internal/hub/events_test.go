@@ -0,0 +1,102 @@
+package hub
+
+import (
+	"testing"
+)
+
+func TestBroadcastAssignsIncreasingIDs(t *testing.T) {
+	h := New(t.TempDir())
+	ch := h.Subscribe()
+	defer h.Unsubscribe(ch)
+
+	h.broadcast(Event{Type: "a"})
+	h.broadcast(Event{Type: "b"})
+
+	first, second := <-ch, <-ch
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("expected IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+	if h.LastEventID() != 2 {
+		t.Errorf("expected last event ID 2, got %d", h.LastEventID())
+	}
+}
+
+func TestSubscribeSince(t *testing.T) {
+	h := New(t.TempDir())
+	for i := 0; i < 5; i++ {
+		h.broadcast(Event{Type: "goal_updated"})
+	}
+
+	ch, missed, complete := h.SubscribeSince(3)
+	defer h.Unsubscribe(ch)
+	if !complete {
+		t.Error("expected a complete replay")
+	}
+	if len(missed) != 2 || missed[0].ID != 4 || missed[1].ID != 5 {
+		t.Errorf("expected events 4 and 5, got %+v", missed)
+	}
+
+	// Events after subscribing arrive on the channel, not in the replay
+	h.broadcast(Event{Type: "goal_updated"})
+	if e := <-ch; e.ID != 6 {
+		t.Errorf("expected event 6 on the channel, got %d", e.ID)
+	}
+
+	up, missed, complete := h.SubscribeSince(6)
+	defer h.Unsubscribe(up)
+	if !complete || len(missed) != 0 {
+		t.Errorf("expected nothing missed, got %d events (complete=%v)", len(missed), complete)
+	}
+}
+
+func TestSubscribeSince_Gap(t *testing.T) {
+	h := New(t.TempDir())
+	for i := 0; i < eventBufferSize+10; i++ {
+		h.broadcast(Event{Type: "goal_updated"})
+	}
+
+	ch, missed, complete := h.SubscribeSince(5)
+	defer h.Unsubscribe(ch)
+	if complete {
+		t.Error("expected an incomplete replay once events fell out of the buffer")
+	}
+	if len(missed) != eventBufferSize {
+		t.Errorf("expected %d buffered events, got %d", eventBufferSize, len(missed))
+	}
+
+	// An ID from before a restart
+	restarted, missed, complete := h.SubscribeSince(999999)
+	defer h.Unsubscribe(restarted)
+	if complete || len(missed) != 0 {
+		t.Errorf("expected an incomplete, empty replay for an unknown ID, got %d events (complete=%v)", len(missed), complete)
+	}
+}
+
+func TestEmitGoalUpdated(t *testing.T) {
+	h := New(t.TempDir())
+	ch := h.Subscribe()
+	defer h.Unsubscribe(ch)
+
+	// Without a summarizer the event only names the goal
+	h.EmitGoalUpdated("abc1234", "file_changed", map[string]interface{}{"file": "goals/active/abc1234.md"})
+	data := (<-ch).Data.(map[string]interface{})
+	if data["goal_id"] != "abc1234" || data["cause"] != "file_changed" || data["file"] != "goals/active/abc1234.md" || data["goal"] != nil {
+		t.Errorf("unexpected event data: %+v", data)
+	}
+
+	h.SetGoalSummarizer(func(goalID string) (interface{}, error) {
+		return map[string]interface{}{"id": goalID, "active_executors": len(h.GetActiveExecutors())}, nil
+	})
+	h.RegisterExecutor("abc1234", "session-1", t.TempDir(), "")
+	var e Event
+	for e = range ch {
+		if e.Type == EventGoalUpdated {
+			break
+		}
+	}
+	data = e.Data.(map[string]interface{})
+	goal, _ := data["goal"].(map[string]interface{})
+	if data["cause"] != EventExecutorStarted || goal["id"] != "abc1234" || goal["active_executors"] != 1 {
+		t.Errorf("expected the goal as it is after the executor started, got %+v", data)
+	}
+}
@@ -0,0 +1,341 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// Focus queue limits
+const (
+	MaxFocusItems     = 20   // Open instructions per goal
+	MaxFocusText      = 1000 // Bytes per instruction
+	maxFocusDoneItems = 50   // Done instructions kept per goal
+)
+
+var (
+	// ErrFocusInvalid is returned for empty, oversized or too many instructions
+	ErrFocusInvalid = errors.New("invalid focus instruction")
+
+	// ErrFocusNotFound is returned for an instruction the goal doesn't have
+	ErrFocusNotFound = errors.New("focus instruction not found")
+
+	// ErrFocusConflict is returned when the queue changed since the revision
+	// the editor started from
+	ErrFocusConflict = errors.New("focus queue was changed by someone else")
+)
+
+// FocusItem is one instruction queued for a goal's executors
+type FocusItem struct {
+	ID        string     `json:"id"` // "f-1", "f-2", ... never reused within a goal
+	Text      string     `json:"text"`
+	User      string     `json:"user,omitempty"` // Who queued it
+	CreatedAt time.Time  `json:"created_at"`
+	SessionID string     `json:"session_id,omitempty"` // Last session whose context pack had it
+	Done      bool       `json:"done"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+	DoneBy    string     `json:"done_by,omitempty"` // Session that reported it done, or the user
+}
+
+// GoalFocus is a goal's ordered instruction queue: what the next executor
+// session works on first. Open instructions go into every context pack until
+// an executor reports them done. Stored in <vega-dir>/.vega-hub-focus/goal-<id>.json.
+type GoalFocus struct {
+	GoalID   string      `json:"goal_id"`
+	Revision int         `json:"revision"`
+	Items    []FocusItem `json:"items"` // Open instructions in order, then done ones (newest first)
+	NextID   int         `json:"next_id"`
+}
+
+// FocusEdit is an open instruction in a replacement queue: an existing one
+// (by ID, optionally with new text) or a new one (no ID)
+type FocusEdit struct {
+	ID   string `json:"id,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// Open returns the instructions not done yet, in order
+func (f *GoalFocus) Open() []FocusItem {
+	var open []FocusItem
+	for _, item := range f.Items {
+		if !item.Done {
+			open = append(open, item)
+		}
+	}
+	return open
+}
+
+func (h *Hub) focusFile(goalID string) string {
+	return filepath.Join(h.dir, ".vega-hub-focus", fmt.Sprintf("goal-%s.json", goalID))
+}
+
+// GetGoalFocus returns a goal's focus queue (empty if none was queued)
+func (h *Hub) GetGoalFocus(goalID string) (*GoalFocus, error) {
+	h.focusMu.Lock()
+	defer h.focusMu.Unlock()
+	return h.readGoalFocus(goalID)
+}
+
+func (h *Hub) readGoalFocus(goalID string) (*GoalFocus, error) {
+	focus := &GoalFocus{GoalID: goalID, Items: []FocusItem{}, NextID: 1}
+	data, err := os.ReadFile(h.focusFile(goalID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return focus, nil
+		}
+		return nil, fmt.Errorf("failed to read focus queue: %w", err)
+	}
+	if err := json.Unmarshal(data, focus); err != nil {
+		return nil, fmt.Errorf("failed to parse focus queue: %w", err)
+	}
+	return focus, nil
+}
+
+// updateGoalFocus applies fn to a goal's queue and saves it as the next
+// revision. With baseRevision >= 0 the update fails with ErrFocusConflict
+// unless the queue is still at that revision.
+func (h *Hub) updateGoalFocus(goalID, user string, baseRevision int, fn func(*GoalFocus) error) (*GoalFocus, error) {
+	h.focusMu.Lock()
+	focus, err := h.readGoalFocus(goalID)
+	if err == nil && baseRevision >= 0 && baseRevision != focus.Revision {
+		err = fmt.Errorf("%w (now at revision %d)", ErrFocusConflict, focus.Revision)
+	}
+	if err == nil {
+		err = fn(focus)
+	}
+	if err == nil {
+		focus.Revision++
+		err = h.writeGoalFocus(focus)
+	}
+	h.focusMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	h.broadcast(Event{
+		Type: EventGoalFocusUpdated,
+		Data: map[string]interface{}{
+			"goal_id":  goalID,
+			"revision": focus.Revision,
+			"open":     len(focus.Open()),
+			"user":     user,
+		},
+	})
+	return focus, nil
+}
+
+// AddFocus queues an instruction after the goal's open ones
+func (h *Hub) AddFocus(goalID, text, user string) (*GoalFocus, error) {
+	text, err := normalizeFocusText(text)
+	if err != nil {
+		return nil, err
+	}
+	return h.updateGoalFocus(goalID, user, -1, func(f *GoalFocus) error {
+		open := f.Open()
+		if len(open) >= MaxFocusItems {
+			return fmt.Errorf("%w: at most %d open instructions", ErrFocusInvalid, MaxFocusItems)
+		}
+		f.Items = append(append(open, f.newItem(text, user)), f.done()...)
+		return nil
+	})
+}
+
+// SetFocus replaces a goal's open instructions with edits, in order: edits
+// with an ID keep that instruction (changing its text if given), edits
+// without one queue a new instruction, and open instructions left out are
+// dropped. Done instructions are kept.
+func (h *Hub) SetFocus(goalID string, edits []FocusEdit, user string, baseRevision int) (*GoalFocus, error) {
+	if len(edits) > MaxFocusItems {
+		return nil, fmt.Errorf("%w: at most %d open instructions", ErrFocusInvalid, MaxFocusItems)
+	}
+	return h.updateGoalFocus(goalID, user, baseRevision, func(f *GoalFocus) error {
+		existing := make(map[string]FocusItem)
+		for _, item := range f.Open() {
+			existing[item.ID] = item
+		}
+		open := make([]FocusItem, 0, len(edits))
+		for _, edit := range edits {
+			if edit.ID == "" {
+				text, err := normalizeFocusText(edit.Text)
+				if err != nil {
+					return err
+				}
+				open = append(open, f.newItem(text, user))
+				continue
+			}
+			item, ok := existing[edit.ID]
+			if !ok {
+				return fmt.Errorf("%w: %s is not an open instruction", ErrFocusNotFound, edit.ID)
+			}
+			delete(existing, edit.ID)
+			if edit.Text != "" {
+				text, err := normalizeFocusText(edit.Text)
+				if err != nil {
+					return err
+				}
+				item.Text = text
+			}
+			open = append(open, item)
+		}
+		f.Items = append(open, f.done()...)
+		return nil
+	})
+}
+
+// CompleteFocus marks a goal's instructions done, by sessionID (an executor
+// reporting progress) or a user. Unknown IDs fail without changing anything.
+func (h *Hub) CompleteFocus(goalID string, ids []string, by string) (*GoalFocus, error) {
+	return h.updateGoalFocus(goalID, by, -1, func(f *GoalFocus) error {
+		now := time.Now()
+		var done []FocusItem
+		for _, id := range ids {
+			i := f.indexOf(id)
+			if i < 0 {
+				return fmt.Errorf("%w: %s", ErrFocusNotFound, id)
+			}
+			if f.Items[i].Done {
+				continue
+			}
+			f.Items[i].Done = true
+			f.Items[i].DoneAt = &now
+			f.Items[i].DoneBy = by
+			done = append(done, f.Items[i])
+		}
+		// Newly done instructions go first among the done ones
+		earlier := make([]FocusItem, 0, len(f.Items))
+		for _, item := range f.done() {
+			if !containsFocus(done, item.ID) {
+				earlier = append(earlier, item)
+			}
+		}
+		open := f.Open()
+		f.Items = append(append(open, done...), earlier...)
+		if len(f.Items)-len(open) > maxFocusDoneItems {
+			f.Items = f.Items[:len(open)+maxFocusDoneItems]
+		}
+		return nil
+	})
+}
+
+// RemoveFocus drops an instruction from a goal's queue
+func (h *Hub) RemoveFocus(goalID, id, user string) (*GoalFocus, error) {
+	return h.updateGoalFocus(goalID, user, -1, func(f *GoalFocus) error {
+		i := f.indexOf(id)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrFocusNotFound, id)
+		}
+		f.Items = append(f.Items[:i], f.Items[i+1:]...)
+		return nil
+	})
+}
+
+// consumeFocus records that a session's context pack has the goal's open
+// instructions, and returns them
+func (h *Hub) consumeFocus(goalID, sessionID string) []FocusItem {
+	h.focusMu.Lock()
+	defer h.focusMu.Unlock()
+	focus, err := h.readGoalFocus(goalID)
+	if err != nil {
+		return nil
+	}
+	changed := false
+	for i := range focus.Items {
+		if !focus.Items[i].Done && focus.Items[i].SessionID != sessionID {
+			focus.Items[i].SessionID = sessionID
+			changed = true
+		}
+	}
+	if changed {
+		h.writeGoalFocus(focus)
+	}
+	return focus.Open()
+}
+
+// focusContext lists the goal's open instructions for the context pack.
+// Building a pack for a session (not a preview) marks them consumed by it.
+func (h *Hub) focusContext(goalID, sessionID string) string {
+	var open []FocusItem
+	if sessionID != "" {
+		open = h.consumeFocus(goalID, sessionID)
+	} else if focus, err := h.GetGoalFocus(goalID); err == nil {
+		open = focus.Open()
+	}
+	if len(open) == 0 {
+		return ""
+	}
+	lines := []string{"Work through these instructions in order before anything else:"}
+	for i, item := range open {
+		lines = append(lines, fmt.Sprintf("%d. [%s] %s", i+1, item.ID, item.Text))
+	}
+	lines = append(lines, "",
+		`Report each one done with POST /api/executor/progress (with "Authorization: Bearer $VEGA_HUB_TOKEN"), e.g. {"goal_id": "`+goalID+`", "session_id": "$VEGA_SESSION_ID", "status": "...", "focus_done": ["`+open[0].ID+`"]}.`)
+	return strings.Join(lines, "\n")
+}
+
+func (f *GoalFocus) newItem(text, user string) FocusItem {
+	item := FocusItem{ID: fmt.Sprintf("f-%d", f.NextID), Text: text, User: user, CreatedAt: time.Now()}
+	f.NextID++
+	return item
+}
+
+func (f *GoalFocus) indexOf(id string) int {
+	for i, item := range f.Items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// done returns the instructions done already, newest first
+func (f *GoalFocus) done() []FocusItem {
+	var done []FocusItem
+	for _, item := range f.Items {
+		if item.Done {
+			done = append(done, item)
+		}
+	}
+	return done
+}
+
+func containsFocus(items []FocusItem, id string) bool {
+	for _, item := range items {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeFocusText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "":
+		return "", fmt.Errorf("%w: text is required", ErrFocusInvalid)
+	case len(text) > MaxFocusText:
+		return "", fmt.Errorf("%w: text exceeds %d bytes", ErrFocusInvalid, MaxFocusText)
+	}
+	return text, nil
+}
+
+// writeGoalFocus stores a focus queue atomically (caller holds focusMu)
+func (h *Hub) writeGoalFocus(focus *GoalFocus) error {
+	path := h.focusFile(focus.GoalID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create focus directory: %w", err)
+	}
+	data, err := json.MarshalIndent(focus, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal focus queue: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write focus queue: %w", err)
+	}
+	return nil
+}
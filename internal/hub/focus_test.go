@@ -0,0 +1,84 @@
+package hub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGoalFocus(t *testing.T) {
+	h := New(t.TempDir())
+
+	if _, err := h.AddFocus("abc1234", "Fix the failing auth test", "alice"); err != nil {
+		t.Fatalf("AddFocus: %v", err)
+	}
+	focus, err := h.AddFocus("abc1234", "Continue with phase 3", "alice")
+	if err != nil {
+		t.Fatalf("AddFocus: %v", err)
+	}
+	if focus.Revision != 2 || len(focus.Items) != 2 || focus.Items[0].ID != "f-1" || focus.Items[1].ID != "f-2" {
+		t.Fatalf("unexpected queue %+v", focus)
+	}
+	if _, err := h.AddFocus("abc1234", "  ", "alice"); !errors.Is(err, ErrFocusInvalid) {
+		t.Errorf("expected ErrFocusInvalid for empty text, got %v", err)
+	}
+
+	// Edit before the spawn: put phase 3 first, reword the test fix, add a step
+	focus, err = h.SetFocus("abc1234", []FocusEdit{{ID: "f-2"}, {ID: "f-1", Text: "Fix the flaky auth test"}, {Text: "Update the changelog"}}, "bob", 2)
+	if err != nil {
+		t.Fatalf("SetFocus: %v", err)
+	}
+	var texts []string
+	for _, item := range focus.Open() {
+		texts = append(texts, item.ID+" "+item.Text)
+	}
+	if got := strings.Join(texts, "; "); got != "f-2 Continue with phase 3; f-1 Fix the flaky auth test; f-3 Update the changelog" {
+		t.Errorf("unexpected open instructions: %s", got)
+	}
+	if _, err := h.SetFocus("abc1234", nil, "carol", 2); !errors.Is(err, ErrFocusConflict) {
+		t.Errorf("expected ErrFocusConflict for a stale revision, got %v", err)
+	}
+	if _, err := h.SetFocus("abc1234", []FocusEdit{{ID: "f-9"}}, "carol", -1); !errors.Is(err, ErrFocusNotFound) {
+		t.Errorf("expected ErrFocusNotFound, got %v", err)
+	}
+
+	// Previews don't consume; a session's context pack does
+	if pack := h.PreviewContextPack("abc1234"); !strings.Contains(pack.Text, "1. [f-2] Continue with phase 3") {
+		t.Errorf("expected the focus section in the preview:\n%s", pack.Text)
+	}
+	if focus, _ := h.GetGoalFocus("abc1234"); focus.Items[0].SessionID != "" {
+		t.Errorf("expected a preview not to consume the queue, got %+v", focus.Items[0])
+	}
+	h.BuildContextPack("abc1234", "s1", t.TempDir())
+	if focus, _ := h.GetGoalFocus("abc1234"); focus.Items[0].SessionID != "s1" {
+		t.Errorf("expected the instructions consumed by s1, got %+v", focus.Items[0])
+	}
+
+	focus, err = h.CompleteFocus("abc1234", []string{"f-2"}, "s1")
+	if err != nil {
+		t.Fatalf("CompleteFocus: %v", err)
+	}
+	if open := focus.Open(); len(open) != 2 || open[0].ID != "f-1" {
+		t.Errorf("expected f-1 next, got %+v", open)
+	}
+	if done := focus.done(); len(done) != 1 || done[0].DoneBy != "s1" || done[0].DoneAt == nil {
+		t.Errorf("expected f-2 done by s1, got %+v", done)
+	}
+	if _, err := h.CompleteFocus("abc1234", []string{"f-1", "f-9"}, "s1"); !errors.Is(err, ErrFocusNotFound) {
+		t.Errorf("expected ErrFocusNotFound, got %v", err)
+	}
+	if focus, _ := h.GetGoalFocus("abc1234"); len(focus.Open()) != 2 {
+		t.Errorf("expected a failed completion to change nothing, got %+v", focus.Items)
+	}
+
+	// Done instructions stay out of the next pack
+	pack := New(h.dir).BuildContextPack("abc1234", "s2", t.TempDir())
+	if strings.Contains(pack.Text, "phase 3") || !strings.Contains(pack.Text, "1. [f-1]") {
+		t.Errorf("expected only open instructions in the pack:\n%s", pack.Text)
+	}
+
+	focus, err = h.RemoveFocus("abc1234", "f-3", "bob")
+	if err != nil || len(focus.Open()) != 1 {
+		t.Errorf("expected f-3 removed, got %+v (%v)", focus, err)
+	}
+}
@@ -0,0 +1,43 @@
+package hub
+
+import "log"
+
+// GoalSummarizer renders one goal as the goal list returns it (the API's
+// GoalSummary), for goal_updated events
+type GoalSummarizer func(goalID string) (interface{}, error)
+
+// SetGoalSummarizer sets how goal_updated events render their goal. Without
+// one the events only name the goal and clients refetch it.
+func (h *Hub) SetGoalSummarizer(fn GoalSummarizer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.summarizer = fn
+}
+
+// EmitGoalUpdated publishes a goal_updated event for a goal whose summary
+// changed. The event carries the goal's current summary as "goal", so
+// clients can replace their copy instead of refetching the list, and cause
+// says what changed it: an event type such as executor_started, file_changed
+// for an edited goal file or updated for a PATCH. Fields are added as given.
+func (h *Hub) EmitGoalUpdated(goalID, cause string, fields map[string]interface{}) {
+	data := map[string]interface{}{
+		"goal_id": goalID,
+		"cause":   cause,
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+
+	h.mu.RLock()
+	summarize := h.summarizer
+	h.mu.RUnlock()
+	if summarize != nil {
+		if goal, err := summarize(goalID); err == nil {
+			data["goal"] = goal
+		} else {
+			log.Printf("[EVENTS] Could not summarize goal %s: %v", goalID, err)
+		}
+	}
+
+	h.broadcast(Event{Type: EventGoalUpdated, Data: data})
+}
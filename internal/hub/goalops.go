@@ -0,0 +1,93 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrOperationInProgress is matched (via errors.Is) by every OperationInProgressError
+var ErrOperationInProgress = errors.New("operation in progress")
+
+// GoalOperation is a mutating operation (complete, ice, delete...) running on a goal
+type GoalOperation struct {
+	GoalID    string    `json:"goal_id"`
+	Operation string    `json:"operation"`
+	Holder    string    `json:"holder,omitempty"` // User who started it
+	StartedAt time.Time `json:"started_at"`
+}
+
+// OperationInProgressError is returned when a goal already has a mutating
+// operation running
+type OperationInProgressError struct {
+	Current GoalOperation
+}
+
+func (e *OperationInProgressError) Error() string {
+	who := e.Current.Holder
+	if who == "" {
+		who = "another user"
+	}
+	return fmt.Sprintf("operation in progress: %s of goal %s by %s since %s",
+		e.Current.Operation, e.Current.GoalID, who, e.Current.StartedAt.Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, ErrOperationInProgress) match
+func (e *OperationInProgressError) Is(target error) bool {
+	return target == ErrOperationInProgress
+}
+
+// goalOperations tracks the operation running on each goal. It only guards
+// this process; the file locks in lock.go still protect git and REGISTRY.md
+// against the CLI.
+type goalOperations struct {
+	mu      sync.Mutex
+	running map[string]*GoalOperation // goal ID → operation
+}
+
+func newGoalOperations() *goalOperations {
+	return &goalOperations{running: make(map[string]*GoalOperation)}
+}
+
+// BeginGoalOperation marks operation as running on a goal so a second
+// complete, ice or delete started meanwhile fails fast instead of racing it.
+// It returns an OperationInProgressError if the goal is busy; otherwise the
+// caller must call the returned release when the operation finishes.
+func (h *Hub) BeginGoalOperation(goalID, operation, holder string) (func(), error) {
+	h.goalOps.mu.Lock()
+	defer h.goalOps.mu.Unlock()
+
+	if current, busy := h.goalOps.running[goalID]; busy {
+		return nil, &OperationInProgressError{Current: *current}
+	}
+	op := &GoalOperation{
+		GoalID:    goalID,
+		Operation: operation,
+		Holder:    holder,
+		StartedAt: time.Now(),
+	}
+	h.goalOps.running[goalID] = op
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.goalOps.mu.Lock()
+			defer h.goalOps.mu.Unlock()
+			if h.goalOps.running[goalID] == op {
+				delete(h.goalOps.running, goalID)
+			}
+		})
+	}, nil
+}
+
+// GoalOperationInProgress returns the operation running on a goal, or nil
+func (h *Hub) GoalOperationInProgress(goalID string) *GoalOperation {
+	h.goalOps.mu.Lock()
+	defer h.goalOps.mu.Unlock()
+	if op, busy := h.goalOps.running[goalID]; busy {
+		current := *op
+		return &current
+	}
+	return nil
+}
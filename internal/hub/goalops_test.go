@@ -0,0 +1,70 @@
+package hub
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestBeginGoalOperation(t *testing.T) {
+	h := New(t.TempDir())
+
+	release, err := h.BeginGoalOperation("abc1234", "complete", "alice")
+	if err != nil {
+		t.Fatalf("BeginGoalOperation: %v", err)
+	}
+
+	_, err = h.BeginGoalOperation("abc1234", "delete", "bob")
+	var busy *OperationInProgressError
+	if !errors.As(err, &busy) || !errors.Is(err, ErrOperationInProgress) {
+		t.Fatalf("expected OperationInProgressError, got %v", err)
+	}
+	if busy.Current.Operation != "complete" || busy.Current.Holder != "alice" {
+		t.Errorf("expected the running complete by alice, got %+v", busy.Current)
+	}
+
+	// Other goals aren't affected
+	other, err := h.BeginGoalOperation("def5678", "ice", "bob")
+	if err != nil {
+		t.Fatalf("expected another goal free, got %v", err)
+	}
+	other()
+
+	release()
+	release() // Releasing twice is harmless
+	if op := h.GoalOperationInProgress("abc1234"); op != nil {
+		t.Errorf("expected no operation after release, got %+v", op)
+	}
+	again, err := h.BeginGoalOperation("abc1234", "delete", "bob")
+	if err != nil {
+		t.Fatalf("expected the goal free after release, got %v", err)
+	}
+	again()
+}
+
+func TestBeginGoalOperationConcurrent(t *testing.T) {
+	h := New(t.TempDir())
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	won := 0
+	start := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := h.BeginGoalOperation("abc1234", "complete", "user"); err == nil {
+				mu.Lock()
+				won++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if won != 1 {
+		t.Errorf("expected exactly one operation to start, got %d", won)
+	}
+}
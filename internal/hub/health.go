@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // HealthStatus represents the overall health status
@@ -153,7 +154,7 @@ func (h *HealthChecker) RunAllChecks() *HealthResult {
 
 // CheckWorktreeBases verifies all worktree bases are clean and fetchable
 func (h *HealthChecker) CheckWorktreeBases() HealthCheck {
-	workspacesDir := filepath.Join(h.vegaDir, "workspaces")
+	workspacesDir := layout.New(h.vegaDir).WorkspacesDir()
 	entries, err := os.ReadDir(workspacesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -168,9 +169,9 @@ func (h *HealthChecker) CheckWorktreeBases() HealthCheck {
 			continue
 		}
 		projectName := entry.Name()
-		worktreeBase := filepath.Join(workspacesDir, projectName, "worktree-base")
+		worktreeBase := filepath.Join(workspacesDir, projectName, layout.WorktreeBaseName)
 
-		if _, err := os.Stat(worktreeBase); os.IsNotExist(err) {
+		if !isGitWorkspace(worktreeBase) {
 			continue
 		}
 
@@ -201,7 +202,7 @@ func (h *HealthChecker) CheckWorktreeBases() HealthCheck {
 
 // CheckStuckGoals finds goals stuck in intermediate states
 func (h *HealthChecker) CheckStuckGoals() HealthCheck {
-	stuckGoals, err := h.stateManager.StuckGoals(1 * time.Hour)
+	stuckGoals, err := h.stateManager.StuckGoalsByState(stuckThresholds(h.vegaDir))
 	if err != nil {
 		return HealthCheck{Status: HealthHealthy, Message: "No stuck goals (unable to check)"}
 	}
@@ -249,7 +250,7 @@ func (h *HealthChecker) CheckDiskSpace() HealthCheck {
 
 // CheckGitCredentials verifies git credentials for all projects
 func (h *HealthChecker) CheckGitCredentials() HealthCheck {
-	workspacesDir := filepath.Join(h.vegaDir, "workspaces")
+	workspacesDir := layout.New(h.vegaDir).WorkspacesDir()
 	entries, err := os.ReadDir(workspacesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -264,7 +265,7 @@ func (h *HealthChecker) CheckGitCredentials() HealthCheck {
 			continue
 		}
 		projectName := entry.Name()
-		worktreeBase := filepath.Join(workspacesDir, projectName, "worktree-base")
+		worktreeBase := filepath.Join(workspacesDir, projectName, layout.WorktreeBaseName)
 
 		if _, err := os.Stat(worktreeBase); os.IsNotExist(err) {
 			continue
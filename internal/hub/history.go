@@ -2,8 +2,10 @@ package hub
 
 import (
 	"bufio"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -16,6 +18,10 @@ type SessionHistory struct {
 	mu       sync.RWMutex
 	dir      string                       // vega-missile directory
 	sessions map[string][]*ExecutorSession // goal_id -> sessions
+
+	// Held exclusively while retention compresses or removes history files,
+	// shared by appends and reads
+	fileMu sync.RWMutex
 }
 
 // ExecutorSession represents a completed or active executor session
@@ -29,7 +35,8 @@ type ExecutorSession struct {
 	StartedAt       time.Time  `json:"started_at"`
 	StoppedAt       *time.Time `json:"stopped_at,omitempty"`
 	StopReason      string     `json:"stop_reason,omitempty"`
-	Activities      []Activity `json:"activities,omitempty"` // In-memory only, not persisted per-session
+	ResumedFrom     string     `json:"resumed_from,omitempty"` // Session whose Claude session this one continues
+	Activities      []Activity `json:"activities,omitempty"`   // In-memory only, not persisted per-session
 }
 
 // Activity represents an executor activity event
@@ -46,7 +53,7 @@ type HistoryEntry struct {
 	SessionID       string      `json:"session_id"`
 	ClaudeSessionID string      `json:"claude_session_id,omitempty"`
 	TranscriptPath  string      `json:"transcript_path,omitempty"`
-	Type            string      `json:"type"` // "session_start", "session_stop", "question", "answer", "activity"
+	Type            string      `json:"type"` // "session_start", "session_resumed", "session_stop", "question", "answer", "activity"
 	User            string      `json:"user,omitempty"`
 	CWD             string      `json:"cwd,omitempty"`
 	StopReason      string      `json:"stop_reason,omitempty"`
@@ -73,6 +80,73 @@ func (h *SessionHistory) historyFile(goalID string) string {
 	return filepath.Join(h.historyDir(), fmt.Sprintf("goal-%s.jsonl", goalID))
 }
 
+// newHistoryScanner reads a goal's history file line by line. Session summaries
+// and activity data can exceed bufio.Scanner's default 64KB line limit, which
+// would silently end the scan and drop every later session and message.
+func newHistoryScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return scanner
+}
+
+// openHistory opens a goal's history for reading: the part compressed by
+// retention (goal-<id>.jsonl.gz) followed by the file still being appended to.
+// Returns a not-exist error when the goal has neither. Callers hold fileMu.
+func (h *SessionHistory) openHistory(goalID string) (io.ReadCloser, error) {
+	path := h.historyFile(goalID)
+	rc := &multiReadCloser{}
+
+	if gz, err := os.Open(path + ".gz"); err == nil {
+		zr, err := gzip.NewReader(gz)
+		if err != nil {
+			gz.Close()
+			return nil, fmt.Errorf("failed to read compressed history: %w", err)
+		}
+		rc.readers = append(rc.readers, zr)
+		rc.closers = append(rc.closers, gz)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) || len(rc.readers) == 0 {
+			rc.Close()
+			return nil, err
+		}
+	} else {
+		rc.readers = append(rc.readers, file)
+		rc.closers = append(rc.closers, file)
+	}
+	return rc, nil
+}
+
+// multiReadCloser reads several files back to back
+type multiReadCloser struct {
+	readers []io.Reader
+	closers []io.Closer
+	r       io.Reader
+}
+
+func (m *multiReadCloser) Read(p []byte) (int, error) {
+	if m.r == nil {
+		m.r = io.MultiReader(m.readers...)
+	}
+	return m.r.Read(p)
+}
+
+func (m *multiReadCloser) Close() error {
+	for _, c := range m.closers {
+		c.Close()
+	}
+	return nil
+}
+
+// forget drops a goal's cached sessions after its history file is removed
+func (h *SessionHistory) forget(goalID string) {
+	h.mu.Lock()
+	delete(h.sessions, goalID)
+	h.mu.Unlock()
+}
+
 // ensureHistoryDir creates the history directory if it doesn't exist
 func (h *SessionHistory) ensureHistoryDir() error {
 	dir := h.historyDir()
@@ -88,6 +162,8 @@ func (h *SessionHistory) appendEntry(entry HistoryEntry) error {
 		return err
 	}
 
+	h.fileMu.RLock()
+	defer h.fileMu.RUnlock()
 	file, err := os.OpenFile(h.historyFile(entry.GoalID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to open history file: %w", err)
@@ -180,6 +256,81 @@ func (h *SessionHistory) RecordQuestion(goalID, sessionID, question, answer stri
 	return h.appendEntry(entry)
 }
 
+// RecordStructuredQuestion records a Q&A exchange, preserving the options offered
+// and the structured answer (selected option IDs, attachments) in the entry data.
+// user is who answered (may be empty).
+func (h *SessionHistory) RecordStructuredQuestion(goalID, sessionID, user, question, answer string, options []Option, structured *StructuredAnswer, drafts []AnswerDraft) error {
+	entry := HistoryEntry{
+		Timestamp: time.Now(),
+		GoalID:    goalID,
+		SessionID: sessionID,
+		Type:      "question",
+		User:      user,
+		Question:  question,
+		Answer:    answer,
+	}
+	if len(options) > 0 || structured != nil || len(drafts) > 0 {
+		data := map[string]interface{}{}
+		if len(options) > 0 {
+			data["options"] = options
+		}
+		if structured != nil {
+			data["structured_answer"] = structured
+		}
+		if len(drafts) > 0 {
+			data["drafts"] = drafts
+		}
+		entry.Data = data
+	}
+	return h.appendEntry(entry)
+}
+
+// RecordAutoAnsweredQuestion records a Q&A exchange answered by a question rule
+func (h *SessionHistory) RecordAutoAnsweredQuestion(goalID, sessionID, question, answer, ruleID string) error {
+	return h.appendEntry(HistoryEntry{
+		Timestamp: time.Now(),
+		GoalID:    goalID,
+		SessionID: sessionID,
+		Type:      "question",
+		Question:  question,
+		Answer:    answer,
+		Data:      map[string]interface{}{"auto_answered_by": ruleID},
+	})
+}
+
+// RecordComment records a user comment on a goal
+func (h *SessionHistory) RecordComment(c *Comment) error {
+	data := map[string]interface{}{
+		"comment_id": c.ID,
+		"content":    c.Content,
+	}
+	if c.ParentID != "" {
+		data["parent_id"] = c.ParentID
+	}
+	if len(c.Mentions) > 0 {
+		data["mentions"] = c.Mentions
+	}
+	return h.appendEntry(HistoryEntry{
+		Timestamp: c.CreatedAt,
+		GoalID:    c.GoalID,
+		Type:      "comment",
+		User:      c.User,
+		Data:      data,
+	})
+}
+
+// RecordGoalAction records a change made to a goal (e.g. goal_completed,
+// goal_iced) by user
+func (h *SessionHistory) RecordGoalAction(goalID, action, user string, data map[string]interface{}) error {
+	return h.appendEntry(HistoryEntry{
+		Timestamp: time.Now(),
+		GoalID:    goalID,
+		Type:      action,
+		User:      user,
+		Data:      data,
+	})
+}
+
 // RecordActivity records a generic activity
 func (h *SessionHistory) RecordActivity(goalID, sessionID, activityType string, data interface{}) error {
 	entry := HistoryEntry{
@@ -222,7 +373,9 @@ func (h *SessionHistory) GetGoalSessions(goalID string) ([]*ExecutorSession, err
 
 // loadGoalHistory loads session history from file
 func (h *SessionHistory) loadGoalHistory(goalID string) ([]*ExecutorSession, error) {
-	file, err := os.Open(h.historyFile(goalID))
+	h.fileMu.RLock()
+	defer h.fileMu.RUnlock()
+	file, err := h.openHistory(goalID)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -232,7 +385,7 @@ func (h *SessionHistory) loadGoalHistory(goalID string) ([]*ExecutorSession, err
 	defer file.Close()
 
 	sessionMap := make(map[string]*ExecutorSession)
-	scanner := bufio.NewScanner(file)
+	scanner := newHistoryScanner(file)
 
 	for scanner.Scan() {
 		var entry HistoryEntry
@@ -249,6 +402,14 @@ func (h *SessionHistory) loadGoalHistory(goalID string) ([]*ExecutorSession, err
 				User:      entry.User,
 				StartedAt: entry.Timestamp,
 			}
+		case "session_resumed":
+			if s, ok := sessionMap[entry.SessionID]; ok {
+				if data, ok := entry.Data.(map[string]interface{}); ok {
+					s.ResumedFrom, _ = data["resumed_from"].(string)
+				}
+				s.ClaudeSessionID = entry.ClaudeSessionID
+				s.TranscriptPath = entry.TranscriptPath
+			}
 		case "session_stop":
 			if s, ok := sessionMap[entry.SessionID]; ok {
 				s.StoppedAt = &entry.Timestamp
@@ -276,26 +437,42 @@ func (h *SessionHistory) loadGoalHistory(goalID string) ([]*ExecutorSession, err
 	return sessions, nil
 }
 
-// GetGoalHistory returns all history entries for a goal (for detailed view)
-func (h *SessionHistory) GetGoalHistory(goalID string, limit int) ([]HistoryEntry, error) {
-	file, err := os.Open(h.historyFile(goalID))
+// ScanGoalHistory calls fn with a goal's history entries, oldest first,
+// until fn returns false. The history isn't read past that point.
+func (h *SessionHistory) ScanGoalHistory(goalID string, fn func(entry HistoryEntry) bool) error {
+	h.fileMu.RLock()
+	defer h.fileMu.RUnlock()
+	file, err := h.openHistory(goalID)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			return nil
 		}
-		return nil, fmt.Errorf("failed to open history file: %w", err)
+		return fmt.Errorf("failed to open history file: %w", err)
 	}
 	defer file.Close()
 
-	var entries []HistoryEntry
-	scanner := bufio.NewScanner(file)
-
+	scanner := newHistoryScanner(file)
 	for scanner.Scan() {
 		var entry HistoryEntry
 		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
 			continue
 		}
+		if !fn(entry) {
+			break
+		}
+	}
+	return nil
+}
+
+// GetGoalHistory returns all history entries for a goal (for detailed view)
+func (h *SessionHistory) GetGoalHistory(goalID string, limit int) ([]HistoryEntry, error) {
+	var entries []HistoryEntry
+	err := h.ScanGoalHistory(goalID, func(entry HistoryEntry) bool {
 		entries = append(entries, entry)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Return last N entries if limit specified
@@ -308,27 +485,15 @@ func (h *SessionHistory) GetGoalHistory(goalID string, limit int) ([]HistoryEntr
 
 // GetSessionHistory returns history for a specific session
 func (h *SessionHistory) GetSessionHistory(goalID, sessionID string) ([]HistoryEntry, error) {
-	file, err := os.Open(h.historyFile(goalID))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to open history file: %w", err)
-	}
-	defer file.Close()
-
 	var entries []HistoryEntry
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		var entry HistoryEntry
-		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
-			continue
-		}
+	err := h.ScanGoalHistory(goalID, func(entry HistoryEntry) bool {
 		if entry.SessionID == sessionID {
 			entries = append(entries, entry)
 		}
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return entries, nil
 }
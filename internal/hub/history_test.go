@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -221,6 +222,36 @@ func TestSessionHistory_LoadFromFile(t *testing.T) {
 	}
 }
 
+func TestSessionHistory_LongEntries(t *testing.T) {
+	dir := t.TempDir()
+	h1 := NewSessionHistory(dir)
+
+	// A session summary far beyond bufio.Scanner's default 64KB line limit
+	h1.RecordSessionStart("goal-123", "session-1", "/wt", "testuser")
+	h1.RecordSessionStop("goal-123", "session-1", "", "", "completed", strings.Repeat("x", 200*1024))
+	h1.RecordSessionStart("goal-123", "session-2", "/wt", "testuser")
+	h1.RecordQuestion("goal-123", "session-2", "Continue?", "Yes")
+
+	h2 := NewSessionHistory(dir)
+	sessions, err := h2.GetGoalSessions("goal-123")
+	if err != nil {
+		t.Fatalf("GetGoalSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Errorf("expected 2 sessions after a long entry, got %d", len(sessions))
+	}
+	entries, err := h2.GetGoalHistory("goal-123", 0)
+	if err != nil {
+		t.Fatalf("GetGoalHistory failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("expected 4 entries after a long entry, got %d", len(entries))
+	}
+	if entries, _ := h2.GetSessionHistory("goal-123", "session-2"); len(entries) != 2 {
+		t.Errorf("expected 2 entries for session-2, got %d", len(entries))
+	}
+}
+
 func TestSessionHistory_NonExistentGoal(t *testing.T) {
 	dir := t.TempDir()
 	h := NewSessionHistory(dir)
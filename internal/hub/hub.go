@@ -2,6 +2,7 @@ package hub
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,28 +11,56 @@ import (
 	"sync"
 	"time"
 
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
 	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/jobs"
+	"github.com/lasmarois/vega-hub/internal/layout"
 	"github.com/lasmarois/vega-hub/internal/markdown"
 )
 
 // Hub manages the state of pending questions and executor sessions
 type Hub struct {
 	dir       string
+	startedAt time.Time
+	layout    *layout.Layout
 	port      int // Port vega-hub is running on (for executor env injection)
 	questions map[string]*Question
+	answered  map[string]answeredMarker // Recently answered question IDs (for conflict reporting)
 	executors map[string]*Executor
 	mu        sync.RWMutex
 
-	// Pending user messages (user → executor communication)
-	userMessages map[string][]*UserMessage // goal_id -> messages
+	// Recent answers by question fingerprint, returned to re-asks (guarded by mu)
+	recentAnswers map[string]recentAnswer
+
+	// Answers from first draft to delivery by question ID, and how long they
+	// are held for edits before delivery (guarded by mu)
+	answers     map[string]*AnswerRecord
+	answerGrace time.Duration
+
+	// User messages (user → executor communication) with their delivery status
+	userMessages map[string][]*UserMessage // goal_id -> messages, oldest first
 	msgMu        sync.RWMutex
 
+	// Serializes goal notes saves (see notes.go)
+	notesMu sync.Mutex
+
+	// Serializes goal focus queue updates (see focus.go)
+	focusMu sync.Mutex
+
+	// Serializes share link and access log updates (see share.go)
+	sharesMu sync.Mutex
+
+	// Guards the decisions index (see decisions.go)
+	decisionsMu sync.Mutex
+
 	// Spawn lock - prevents concurrent spawns for same goal
 	spawnMu sync.Mutex
 
 	// Channels for SSE broadcasting
 	subscribers map[chan Event]bool
 	subMu       sync.RWMutex
+	lastEventID uint64  // Guarded by subMu
+	recent      []Event // Recently broadcast events for replay, guarded by subMu
 
 	mdWriter *markdown.Writer
 
@@ -40,6 +69,56 @@ type Hub struct {
 
 	// Goal state machine manager
 	stateManager *goals.StateManager
+
+	// Question routing and auto-answer rules
+	rules *QuestionRules
+
+	// Users' saved goal filters
+	views *SavedViews
+
+	// Users' UI preferences
+	preferences *PreferenceStore
+
+	// Past answers mined from history, for suggestions
+	answerLibrary *AnswerLibrary
+
+	// Scores existing goals against new ones (see similar.go)
+	similarity SimilarityProvider
+
+	// Renders the goal carried by goal_updated events (see goal_events.go)
+	summarizer GoalSummarizer
+
+	// Cached read-only git queries, invalidated by the file watcher
+	git *gitsvc.Cached
+
+	// File watcher settings and counters
+	watch watcherState
+
+	// Event bus feeding SSE, the event log, metrics and webhooks
+	bus     *EventBus
+	metrics *eventMetrics
+
+	// Goal completion results, reused while goal files and worktrees are unchanged
+	completion *goals.CompletionCache
+
+	// Serializes retention runs and guards the last run's result
+	retentionMu    sync.Mutex
+	lastCompaction *CompactionResult
+
+	// Tokens scoping spawned executors to their goal's endpoints
+	tokens *executorTokens
+
+	// Mutating operations running per goal (complete, ice, delete...)
+	goalOps *goalOperations
+
+	// Users with open event streams and the goals they have open
+	presence *presenceTracker
+
+	// Background work such as worktree provisioning, merges and MR creation
+	jobs *jobs.Manager
+
+	// Requests that failed with a panic (see incidents.go)
+	incidents incidentLog
 }
 
 // UserMessage represents a message from a user to an executor
@@ -49,6 +128,15 @@ type UserMessage struct {
 	Content   string    `json:"content"`
 	User      string    `json:"user,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Uploaded files, links and snippets sent with the message
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Delivery tracking (see messages.go)
+	Status      string     `json:"status"`               // "pending", "delivered", "processed", "undelivered"
+	SessionID   string     `json:"session_id,omitempty"` // Executor session it was delivered to
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
 }
 
 // Executor represents an active executor session
@@ -63,45 +151,110 @@ type Executor struct {
 	LogFile          string    `json:"log_file,omitempty"`
 	User             string    `json:"user,omitempty"`      // Username who spawned this executor
 	StopReason       string    `json:"stop_reason,omitempty"`
+	PID              int        `json:"pid,omitempty"`       // Set for executors spawned by vega-hub
+	Paused           bool       `json:"paused,omitempty"`
+	PausedAt         *time.Time `json:"paused_at,omitempty"`
+	Worker           string     `json:"worker,omitempty"`    // Remote worker (empty for local executors)
+	Container        string     `json:"container,omitempty"` // Container name (empty unless containerized)
+	Progress         *ExecutorProgress `json:"progress,omitempty"` // Latest status report (see ReportProgress)
+	ResumedFrom      string     `json:"resumed_from,omitempty"` // Session whose Claude session this one continues
+
+	process       *os.Process   // Spawned process (nil for hook-registered executors)
+	done          chan struct{} // Closed when the spawned process exits
+	container     *containerRun // Set for containerized executors
+	killRequested bool          // Set by KillExecutor so the exit is recorded as "killed"
+	switching     bool          // A pause or resume is running (see setPaused)
 }
 
 // Question represents a pending question from an executor
 type Question struct {
-	ID        string    `json:"id"`
-	GoalID    string    `json:"goal_id"`
-	SessionID string    `json:"session_id"`
-	Question  string    `json:"question"`
-	Options   []Option  `json:"options,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	GoalID      string    `json:"goal_id"`
+	SessionID   string    `json:"session_id"`
+	Question    string    `json:"question"`
+	Options     []Option  `json:"options,omitempty"`
+	MultiSelect bool      `json:"multi_select,omitempty"` // Allow selecting more than one option
+	CreatedAt   time.Time `json:"created_at"`
+	Fingerprint string    `json:"fingerprint,omitempty"` // Same for re-asks of the question
+	Asks        int       `json:"asks,omitempty"`        // Times asked, counting re-asks
+
+	// Set by the asker; a question rule's priority wins
+	Priority string `json:"priority,omitempty"` // "low", "normal", "high"
+	Group    string `json:"group,omitempty"`    // Related questions of the goal, answerable together
+
+	// Set by question rules
+	Category     string   `json:"category,omitempty"`      // Rule-assigned category
+	AssignedTo   []string `json:"assigned_to,omitempty"`   // Users this question is routed to
+	MatchedRules []string `json:"matched_rules,omitempty"` // IDs of rules that matched
+
+	// Set while a user is answering (see ClaimQuestion)
+	Claim *Claim `json:"claim,omitempty"`
 
 	// Answer channel - blocks until answered
-	answerCh chan string
+	answerCh chan *StructuredAnswer
+	// Re-asks waiting for the same answer
+	waiters []chan *StructuredAnswer
 }
 
 // Option represents a choice for the question
 type Option struct {
+	ID          string `json:"id,omitempty"` // Assigned on Ask if not provided
 	Label       string `json:"label"`
 	Description string `json:"description,omitempty"`
 }
 
 // Event represents an SSE event
 type Event struct {
+	ID   uint64      `json:"id,omitempty"` // Assigned on publish, increasing
+	Time time.Time   `json:"time"`
 	Type string      `json:"type"`
 	Data interface{} `json:"data"`
 }
 
 // New creates a new Hub instance
 func New(dir string) *Hub {
-	return &Hub{
-		dir:          dir,
-		questions:    make(map[string]*Question),
-		executors:    make(map[string]*Executor),
-		userMessages: make(map[string][]*UserMessage),
-		subscribers:  make(map[chan Event]bool),
-		mdWriter:     markdown.NewWriter(dir),
-		history:      NewSessionHistory(dir),
-		stateManager: goals.NewStateManager(dir),
+	history := NewSessionHistory(dir)
+	h := &Hub{
+		dir:           dir,
+		startedAt:     time.Now(),
+		layout:        layout.New(dir),
+		questions:     make(map[string]*Question),
+		answered:      make(map[string]answeredMarker),
+		recentAnswers: make(map[string]recentAnswer),
+		answers:       make(map[string]*AnswerRecord),
+		executors:     make(map[string]*Executor),
+		userMessages:  make(map[string][]*UserMessage),
+		subscribers:   make(map[chan Event]bool),
+		mdWriter:      markdown.NewWriter(dir),
+		history:       history,
+		stateManager:  goals.NewStateManager(dir),
+		rules:         NewQuestionRules(dir),
+		views:         NewSavedViews(dir),
+		preferences:   NewPreferenceStore(dir),
+		answerLibrary: NewAnswerLibrary(history),
+		similarity:    TokenSimilarity{},
+		git:           gitsvc.NewCached(gitsvc.NewExec(), gitsvc.DefaultTTL),
+		completion:    goals.NewCompletionCache(dir),
+		watch:         watcherState{ignore: DefaultWatchIgnore},
+		metrics:       newEventMetrics(),
+		tokens:        newExecutorTokens(),
+		goalOps:       newGoalOperations(),
+		presence:      newPresenceTracker(),
+		jobs:          jobs.NewManager(dir, jobs.DefaultConcurrency),
+	}
+
+	var lastID uint64
+	if dir != "" {
+		lastID = h.restoreFromEventLog()
 	}
+	h.bus = NewEventBus(lastID)
+	h.jobs.SetListener(func(event string, job jobs.Job) { h.EmitEvent(event, job) })
+	h.bus.Subscribe(sseConsumer{h})
+	h.bus.Subscribe(h.metrics)
+	if dir != "" {
+		h.bus.Subscribe(NewEventLog(dir))
+	}
+	return h
 }
 
 // StateManager returns the goal state manager
@@ -109,29 +262,63 @@ func (h *Hub) StateManager() *goals.StateManager {
 	return h.stateManager
 }
 
+// Jobs returns the background job manager
+func (h *Hub) Jobs() *jobs.Manager {
+	return h.jobs
+}
+
+// Git returns the cached git query service
+func (h *Hub) Git() gitsvc.Service {
+	return h.git
+}
+
+// Completion returns the goal completion cache
+func (h *Hub) Completion() *goals.CompletionCache {
+	return h.completion
+}
+
+// InvalidateGit drops cached git answers for repositories at or below path
+func (h *Hub) InvalidateGit(path string) {
+	h.git.Invalidate(path)
+}
+
+// Rules returns the question rules store
+func (h *Hub) Rules() *QuestionRules {
+	return h.rules
+}
+
+// Views returns the saved views store
+func (h *Hub) Views() *SavedViews {
+	return h.views
+}
+
+// Preferences returns the user preferences store
+func (h *Hub) Preferences() *PreferenceStore {
+	return h.preferences
+}
+
 // StuckGoalsInfo contains information about stuck goals for health checks
 type StuckGoalsInfo struct {
-	Count     int               `json:"count"`
-	Goals     []goals.StuckGoal `json:"goals,omitempty"`
-	Threshold time.Duration     `json:"-"`
+	Count      int                               `json:"count"`
+	Goals      []goals.StuckGoal                 `json:"goals,omitempty"`
+	Thresholds map[goals.GoalState]time.Duration `json:"-"`
 }
 
 // RecoverStuckGoals checks for goals stuck in transient states and logs warnings.
 // This should be called on startup to detect goals that may have failed mid-operation.
 // Returns info about stuck goals for health reporting.
 func (h *Hub) RecoverStuckGoals() *StuckGoalsInfo {
-	const stuckThreshold = 1 * time.Hour
-
-	stuck, err := h.stateManager.StuckGoals(stuckThreshold)
+	thresholds := h.StuckThresholds()
+	stuck, err := h.stateManager.StuckGoalsByState(thresholds)
 	if err != nil {
 		log.Printf("[RECOVERY] Error checking for stuck goals: %v", err)
-		return &StuckGoalsInfo{Count: 0, Threshold: stuckThreshold}
+		return &StuckGoalsInfo{Count: 0, Thresholds: thresholds}
 	}
 
 	info := &StuckGoalsInfo{
-		Count:     len(stuck),
-		Goals:     stuck,
-		Threshold: stuckThreshold,
+		Count:      len(stuck),
+		Goals:      stuck,
+		Thresholds: thresholds,
 	}
 
 	if len(stuck) == 0 {
@@ -140,10 +327,10 @@ func (h *Hub) RecoverStuckGoals() *StuckGoalsInfo {
 	}
 
 	// Log warnings for each stuck goal
-	log.Printf("[RECOVERY] WARNING: Found %d stuck goals (in transient state for >%v):", len(stuck), stuckThreshold)
+	log.Printf("[RECOVERY] WARNING: Found %d stuck goals (in transient state for longer than its threshold):", len(stuck))
 	for _, sg := range stuck {
-		log.Printf("[RECOVERY]   - Goal %s: stuck in '%s' since %s (duration: %v)",
-			sg.GoalID, sg.State, sg.Since.Format(time.RFC3339), sg.Duration.Round(time.Minute))
+		log.Printf("[RECOVERY]   - Goal %s: stuck in '%s' since %s (duration: %v, threshold: %v)",
+			sg.GoalID, sg.State, sg.Since.Format(time.RFC3339), sg.Duration.Round(time.Minute), sg.Threshold)
 
 		// TODO: Add webhook/notification integration here
 		// Example: h.notifyStuckGoal(sg)
@@ -151,7 +338,7 @@ func (h *Hub) RecoverStuckGoals() *StuckGoalsInfo {
 
 	// Broadcast event for stuck goals detected
 	h.broadcast(Event{
-		Type: "stuck_goals_detected",
+		Type: EventStuckGoalsDetected,
 		Data: map[string]interface{}{
 			"count": len(stuck),
 			"goals": stuck,
@@ -205,7 +392,7 @@ func (h *Hub) RegisterExecutor(goalID string, sessionID, cwd, user string) strin
 
 	// Broadcast executor started event
 	h.broadcast(Event{
-		Type: "executor_started",
+		Type: EventExecutorStarted,
 		Data: map[string]interface{}{
 			"session_id": sessionID,
 			"goal_id":    goalID,
@@ -213,6 +400,9 @@ func (h *Hub) RegisterExecutor(goalID string, sessionID, cwd, user string) strin
 			"user":       user,
 		},
 	})
+	h.EmitGoalUpdated(goalID, EventExecutorStarted, nil)
+
+	h.resumeAfterReview(goalID, user)
 
 	// Build context for the executor
 	context := h.buildExecutorContext(goalID, sessionID, cwd)
@@ -243,6 +433,13 @@ func (h *Hub) StopExecutorWithClaudeInfo(req StopExecutorRequest) {
 	h.mu.Lock()
 	executor := h.executors[req.SessionID]
 	if executor != nil {
+		// Fall back to the Claude session reported earlier by hooks (or resumed)
+		if req.ClaudeSessionID == "" {
+			req.ClaudeSessionID = executor.ClaudeSessionID
+		}
+		if req.TranscriptPath == "" {
+			req.TranscriptPath = executor.TranscriptPath
+		}
 		// Update with Claude's session info
 		now := time.Now()
 		executor.ClaudeSessionID = req.ClaudeSessionID
@@ -251,8 +448,21 @@ func (h *Hub) StopExecutorWithClaudeInfo(req StopExecutorRequest) {
 		executor.StopReason = req.Reason
 	}
 	delete(h.executors, req.SessionID)
+	goalActive := false
+	for _, e := range h.executors {
+		if e.GoalID == req.GoalID {
+			goalActive = true
+			break
+		}
+	}
 	h.mu.Unlock()
 
+	// Messages the last executor never pulled wait for the next one
+	var undelivered []*UserMessage
+	if !goalActive {
+		undelivered = h.markUserMessagesUndelivered(req.GoalID)
+	}
+
 	// Read output summary from log file
 	var outputSummary string
 	if executor != nil && executor.LogFile != "" {
@@ -264,6 +474,31 @@ func (h *Hub) StopExecutorWithClaudeInfo(req StopExecutorRequest) {
 		// Log error but don't fail
 	}
 
+	// Store a normalized copy of Claude's transcript for browsing
+	if req.TranscriptPath != "" {
+		if entries, err := h.history.IngestTranscript(req.GoalID, req.SessionID, req.TranscriptPath); err != nil {
+			log.Printf("[TRANSCRIPT] Failed to ingest transcript for session %s: %v", req.SessionID, err)
+		} else {
+			log.Printf("[TRANSCRIPT] Ingested %d entries for session %s", len(entries), req.SessionID)
+
+			// Surface what the executor did as activity entries
+			activities := ExtractActivities(req.SessionID, entries)
+			if err := h.history.RecordSessionActivities(req.GoalID, req.SessionID, activities); err != nil {
+				log.Printf("[TRANSCRIPT] Failed to record activity for session %s: %v", req.SessionID, err)
+			}
+			if len(activities) > 0 {
+				h.broadcast(Event{
+					Type: EventSessionActivity,
+					Data: map[string]interface{}{
+						"goal_id":    req.GoalID,
+						"session_id": req.SessionID,
+						"count":      len(activities),
+					},
+				})
+			}
+		}
+	}
+
 	// Write to markdown
 	if err := h.mdWriter.WriteExecutorEvent(req.GoalID, req.SessionID, "Stopped", req.Reason); err != nil {
 		// Log error but don't fail
@@ -271,7 +506,7 @@ func (h *Hub) StopExecutorWithClaudeInfo(req StopExecutorRequest) {
 
 	// Broadcast executor stopped event with output
 	h.broadcast(Event{
-		Type: "executor_stopped",
+		Type: EventExecutorStopped,
 		Data: map[string]interface{}{
 			"session_id":        req.SessionID,
 			"claude_session_id": req.ClaudeSessionID,
@@ -279,8 +514,10 @@ func (h *Hub) StopExecutorWithClaudeInfo(req StopExecutorRequest) {
 			"goal_id":           req.GoalID,
 			"reason":            req.Reason,
 			"output":            outputSummary,
+			"undelivered":       len(undelivered),
 		},
 	})
+	h.EmitGoalUpdated(req.GoalID, EventExecutorStopped, nil)
 
 	// Send desktop notification
 	h.sendDesktopNotification(req.GoalID, req.Reason)
@@ -300,19 +537,7 @@ func (h *Hub) GetActiveExecutors() []*Executor {
 
 // buildExecutorContext builds the context string for an executor
 func (h *Hub) buildExecutorContext(goalID string, sessionID, cwd string) string {
-	return "[EXECUTOR SESSION START]\n" +
-		"Working on Goal #" + goalID + "\n" +
-		"Directory: " + cwd + "\n" +
-		"vega-hub: connected\n\n" +
-		"IMPORTANT REMINDERS:\n" +
-		"1. Load 'planning-with-files' skill if not already loaded\n" +
-		"2. Planning files go at worktree root: task_plan.md, findings.md, progress.md\n" +
-		"3. You can use AskUserQuestion to ask the human questions directly (via vega-hub)\n" +
-		"4. Before completing, you MUST:\n" +
-		"   - Archive planning files to docs/planning/history/goal-" + goalID + "/\n" +
-		"   - Commit the archive\n" +
-		"   - Report to manager for approval\n" +
-		"5. Commit messages must include 'Goal: #" + goalID + "'"
+	return h.BuildContextPack(goalID, sessionID, cwd).Text
 }
 
 // sendDesktopNotification sends a desktop notification (Linux/macOS)
@@ -343,6 +568,14 @@ func (h *Hub) Dir() string {
 	return h.dir
 }
 
+// Layout returns the paths in the vega-missile directory
+func (h *Hub) Layout() *layout.Layout {
+	if h.layout == nil {
+		return layout.New(h.dir) // Hubs not made by New
+	}
+	return h.layout
+}
+
 // execCommand runs a command and returns any error
 func execCommand(name string, args ...string) ([]byte, error) {
 	cmd := exec.Command(name, args...)
@@ -351,68 +584,164 @@ func execCommand(name string, args ...string) ([]byte, error) {
 
 // Ask registers a new question and blocks until answered
 func (h *Hub) Ask(q *Question) string {
-	q.answerCh = make(chan string, 1)
+	rendered, _ := h.AskStructured(q)
+	return rendered
+}
+
+// AskStructured registers a new question and blocks until answered.
+// Returns the answer rendered as text along with its structured form.
+//
+// Executors retry asks that time out. A re-ask of a pending question (same
+// goal, session, text and options) waits for that question's answer instead
+// of adding a duplicate, and a re-ask of a question answered in the last
+// reaskWindow gets its answer right away.
+func (h *Hub) AskStructured(q *Question) (string, *StructuredAnswer) {
+	rendered, answer, _ := h.AskContext(context.Background(), q)
+	return rendered, answer
+}
+
+// AskContext is AskStructured that stops waiting when ctx ends (the asker
+// disconnected or its deadline passed). The question stays pending and the
+// returned *PendingAnswerError names it for WaitForAnswer.
+func (h *Hub) AskContext(ctx context.Context, q *Question) (string, *StructuredAnswer, error) {
+	q.answerCh = make(chan *StructuredAnswer, 1)
 	q.CreatedAt = time.Now()
+	q.assignOptionIDs()
+	q.Fingerprint = q.fingerprint()
+	q.Asks = 1
+
+	// Apply question rules: auto-answer, routing, priority
+	match := h.rules.Evaluate(q)
+	if match.AutoAnswer != nil && q.ValidateAnswer(match.AutoAnswer) == nil {
+		rendered, answer := h.autoAnswer(q, match)
+		return rendered, answer, nil
+	}
+	q.MatchedRules = match.RuleIDs
+	q.AssignedTo = match.AssignTo
+	if match.Priority != "" {
+		q.Priority = match.Priority
+	}
+	q.Category = match.Category
 
 	h.mu.Lock()
+	if answer := h.recentAnswerFor(q.Fingerprint, q.CreatedAt); answer != nil {
+		h.mu.Unlock()
+		return answer.Render(q), answer, nil
+	}
+	if pending := h.pendingByFingerprint(q.Fingerprint); pending != nil {
+		return h.waitForReask(ctx, pending)
+	}
 	h.questions[q.ID] = q
+	// Re-asks update the question while consumers encode the event
+	snapshot := *q
 	h.mu.Unlock()
 
-	// Broadcast new question event
+	// Broadcast new question event. question_added carries the same payload
+	// for clients patching their state; question is kept for older ones.
 	h.broadcast(Event{
-		Type: "question",
-		Data: q,
+		Type: EventQuestion,
+		Data: &snapshot,
 	})
+	h.broadcast(Event{
+		Type: EventQuestionAdded,
+		Data: &snapshot,
+	})
+	h.EmitGoalUpdated(snapshot.GoalID, EventQuestionAdded, nil)
+
+	// Block until answer received; deliverAnswer removes the question, and
+	// answerCh is buffered so an abandoned ask doesn't block it
+	select {
+	case answer := <-q.answerCh:
+		return answer.Render(q), answer, nil
+	case <-ctx.Done():
+		return "", nil, &PendingAnswerError{QuestionID: q.ID, Err: ctx.Err()}
+	}
+}
 
-	// Block until answer received
-	answer := <-q.answerCh
-
-	// Clean up
-	h.mu.Lock()
-	delete(h.questions, q.ID)
+// waitForReask attaches a re-ask to its pending question and blocks until the
+// question is answered or ctx ends (caller holds h.mu, which is released)
+func (h *Hub) waitForReask(ctx context.Context, pending *Question) (string, *StructuredAnswer, error) {
+	pending.Asks++
+	data := map[string]interface{}{
+		"id":      pending.ID,
+		"goal_id": pending.GoalID,
+		"asks":    pending.Asks,
+	}
+	ch := h.addWaiter(pending)
 	h.mu.Unlock()
 
-	return answer
-}
+	h.broadcast(Event{Type: EventQuestionReasked, Data: data})
 
-// Answer provides an answer to a pending question
-func (h *Hub) Answer(id string, answer string) bool {
-	h.mu.RLock()
-	q, exists := h.questions[id]
-	h.mu.RUnlock()
+	return h.awaitAnswer(ctx, pending, ch)
+}
 
-	if !exists {
-		return false
-	}
+// autoAnswer answers a question from a rule without waiting for a human
+func (h *Hub) autoAnswer(q *Question, match *RuleMatch) (string, *StructuredAnswer) {
+	answer := match.AutoAnswer
+	rendered := answer.Render(q)
 
-	// Write to markdown
-	if err := h.mdWriter.WriteQA(q.GoalID, q.SessionID, q.Question, answer); err != nil {
+	if err := h.mdWriter.WriteQA(q.GoalID, q.SessionID, q.Question, rendered); err != nil {
 		// Log error but don't fail
-		// TODO: proper logging
 	}
-
-	// Record in persistent history
-	if err := h.history.RecordQuestion(q.GoalID, q.SessionID, q.Question, answer); err != nil {
+	if err := h.history.RecordAutoAnsweredQuestion(q.GoalID, q.SessionID, q.Question, rendered, match.AnswerRule); err != nil {
 		// Log error but don't fail
-		// TODO: proper logging
 	}
+	h.recordDecision(q.GoalID, q.SessionID, q.Question, rendered, "", match.AnswerRule)
 
-	// Send answer to waiting goroutine
-	q.answerCh <- answer
-
-	// Broadcast answered event
 	h.broadcast(Event{
-		Type: "answered",
+		Type: EventQuestionAutoAnswered,
 		Data: map[string]interface{}{
-			"id":     id,
-			"answer": answer,
+			"id":       q.ID,
+			"goal_id":  q.GoalID,
+			"question": q.Question,
+			"answer":   rendered,
+			"rule_id":  match.AnswerRule,
 		},
 	})
 
-	return true
+	return rendered, answer
 }
 
-// GetPendingQuestions returns all pending questions
+// Answer provides a free-text answer to a pending question
+func (h *Hub) Answer(id string, answer string) bool {
+	return h.AnswerStructured(id, &StructuredAnswer{Text: answer}) == nil
+}
+
+// AnswerStructured provides a structured answer to a pending question.
+// The answer is validated against the question's options before delivery.
+// Returns ErrQuestionNotFound, a ConflictError, or an error wrapping ErrInvalidAnswer.
+func (h *Hub) AnswerStructured(id string, answer *StructuredAnswer) error {
+	return h.AnswerStructuredAs(id, "", answer)
+}
+
+// AnswerStructuredAs answers a pending question on behalf of a user.
+// Answers are rejected with a ConflictError if another user holds an active
+// claim on the question or if it was already answered. With an answer grace
+// period (see SetAnswerGrace) the answer is delivered once the period ends
+// and can be edited or retracted until then.
+func (h *Hub) AnswerStructuredAs(id, user string, answer *StructuredAnswer) error {
+	return h.answerAs(id, user, answer, false)
+}
+
+// GetQuestion returns a pending question by ID
+func (h *Hub) GetQuestion(id string) (*Question, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	q, ok := h.questions[id]
+	return q, ok
+}
+
+// SuggestAnswers returns past answers to questions similar to a pending question
+func (h *Hub) SuggestAnswers(id string, limit int) ([]AnswerSuggestion, error) {
+	q, ok := h.GetQuestion(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrQuestionNotFound, id)
+	}
+	return h.answerLibrary.Suggest(q, limit)
+}
+
+// GetPendingQuestions returns all pending questions, most urgent first with
+// the questions of a group together (see SortQuestions)
 func (h *Hub) GetPendingQuestions() []*Question {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -421,72 +750,84 @@ func (h *Hub) GetPendingQuestions() []*Question {
 	for _, q := range h.questions {
 		questions = append(questions, q)
 	}
-	return questions
+	return SortQuestions(questions)
 }
 
 // SendUserMessage sends a message from a user to an executor
 // The message is stored until the executor's Stop hook retrieves it
 func (h *Hub) SendUserMessage(goalID, content, user string) *UserMessage {
+	msg, _ := h.SendUserMessageWithAttachments(goalID, content, user, nil)
+	return msg
+}
+
+// SendUserMessageWithAttachments sends a message with attachments; uploaded
+// files are referenced by ID (see SaveAttachment)
+func (h *Hub) SendUserMessageWithAttachments(goalID, content, user string, attachments []Attachment) (*UserMessage, error) {
+	if err := h.ResolveAttachments(goalID, attachments); err != nil {
+		return nil, err
+	}
+	if err := validateAttachments(attachments); err != nil {
+		return nil, err
+	}
+
 	msg := &UserMessage{
-		ID:        fmt.Sprintf("msg-%d", time.Now().UnixNano()),
-		GoalID:    goalID,
-		Content:   content,
-		User:      user,
-		CreatedAt: time.Now(),
+		ID:          fmt.Sprintf("msg-%d", time.Now().UnixNano()),
+		GoalID:      goalID,
+		Content:     content,
+		User:        user,
+		CreatedAt:   time.Now(),
+		Attachments: attachments,
+		Status:      MessagePending,
 	}
 
 	h.msgMu.Lock()
 	h.userMessages[goalID] = append(h.userMessages[goalID], msg)
+	h.trimUserMessagesLocked(goalID)
 	h.msgMu.Unlock()
 
 	// Record to history (pending message)
-	h.history.RecordActivity(goalID, "", "user_message", map[string]interface{}{
-		"content": content,
-		"user":    user,
-		"pending": true,
-	})
+	data := map[string]interface{}{
+		"message_id": msg.ID,
+		"content":    content,
+		"user":       user,
+		"pending":    true,
+	}
+	if len(attachments) > 0 {
+		data["attachments"] = attachments
+	}
+	h.history.RecordActivity(goalID, "", "user_message", data)
 
 	// Broadcast event
 	h.broadcast(Event{
-		Type: "user_message",
+		Type: EventUserMessage,
 		Data: map[string]interface{}{
-			"goal_id": goalID,
-			"content": content,
-			"user":    user,
+			"goal_id":    goalID,
+			"message_id": msg.ID,
+			"content":    content,
+			"user":       user,
 		},
 	})
 
-	return msg
+	return msg, nil
 }
 
-// GetPendingUserMessages returns and clears pending user messages for a goal
+// GetPendingUserMessages returns the messages waiting for a goal's executor
+// and marks them delivered
 // Called by the Stop hook to check if there are messages to inject
 func (h *Hub) GetPendingUserMessages(goalID string) []*UserMessage {
-	h.msgMu.Lock()
-	defer h.msgMu.Unlock()
-
-	messages := h.userMessages[goalID]
-	if len(messages) > 0 {
-		// Clear pending messages after retrieval
-		delete(h.userMessages, goalID)
-
-		// Record delivery in history
-		for _, msg := range messages {
-			h.history.RecordActivity(goalID, "", "user_message_delivered", map[string]interface{}{
-				"content": msg.Content,
-				"user":    msg.User,
-			})
-		}
-	}
-
-	return messages
+	return h.DeliverUserMessages(goalID, "")
 }
 
 // HasPendingUserMessages checks if there are pending messages without consuming them
 func (h *Hub) HasPendingUserMessages(goalID string) bool {
 	h.msgMu.RLock()
 	defer h.msgMu.RUnlock()
-	return len(h.userMessages[goalID]) > 0
+	for _, msg := range h.userMessages[goalID] {
+		if msg.awaitingDelivery() {
+			return true
+		}
+	}
+	return false
 }
 
 // Subscribe returns a channel for receiving events
@@ -506,18 +847,20 @@ func (h *Hub) Unsubscribe(ch chan Event) {
 	close(ch)
 }
 
-// broadcast sends an event to all subscribers
+// broadcast publishes an event on the bus (SSE subscribers, event log, ...)
 func (h *Hub) broadcast(event Event) {
-	h.subMu.RLock()
-	defer h.subMu.RUnlock()
-
-	for ch := range h.subscribers {
-		select {
-		case ch <- event:
-		default:
-			// Skip slow subscribers
-		}
-	}
+	h.bus.Publish(event)
+}
+
+// Bus returns the hub's event bus, for adding consumers
+func (h *Hub) Bus() *EventBus {
+	return h.bus
+}
+
+// EventStats returns per-type counts of published events, including those
+// restored from the event log
+func (h *Hub) EventStats() map[string]EventStats {
+	return h.metrics.snapshot()
 }
 
 // EmitEvent broadcasts a custom event to all subscribers
@@ -528,6 +871,22 @@ func (h *Hub) EmitEvent(eventType string, data interface{}) {
 	})
 }
 
+// RecordGoalAction records a change a user made to a goal (goal_completed,
+// goal_iced, goal_resumed, goal_cleanup, goal_deleted) in the goal's history
+// and broadcasts it as an event of the same type, with the goal ID and user
+// added to data
+func (h *Hub) RecordGoalAction(goalID, action, user string, data map[string]interface{}) {
+	if err := h.history.RecordGoalAction(goalID, action, user, data); err != nil {
+		log.Printf("[HISTORY] Failed to record %s for goal %s: %v", action, goalID, err)
+	}
+
+	event := map[string]interface{}{"goal_id": goalID, "user": user}
+	for k, v := range data {
+		event[k] = v
+	}
+	h.broadcast(Event{Type: action, Data: event})
+}
+
 // readLastLines reads the last N lines from a file
 func (h *Hub) readLastLines(filePath string, n int) string {
 	file, err := os.Open(filePath)
@@ -608,11 +967,21 @@ func (h *Hub) GetGoalSessions(goalID string) ([]*ExecutorSession, error) {
 	return h.history.GetGoalSessions(goalID)
 }
 
+// GetSessionTranscript returns the ingested transcript of a session
+func (h *Hub) GetSessionTranscript(goalID, sessionID string) ([]TranscriptEntry, error) {
+	return h.history.GetTranscript(goalID, sessionID)
+}
+
 // GetGoalHistory returns detailed history entries for a goal
 func (h *Hub) GetGoalHistory(goalID string, limit int) ([]HistoryEntry, error) {
 	return h.history.GetGoalHistory(goalID, limit)
 }
 
+// ScanGoalHistory calls fn with a goal's history entries, oldest first, until it returns false
+func (h *Hub) ScanGoalHistory(goalID string, fn func(entry HistoryEntry) bool) error {
+	return h.history.ScanGoalHistory(goalID, fn)
+}
+
 // GetSessionHistory returns history for a specific session
 func (h *Hub) GetSessionHistory(goalID, sessionID string) ([]HistoryEntry, error) {
 	return h.history.GetSessionHistory(goalID, sessionID)
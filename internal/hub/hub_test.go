@@ -1,6 +1,7 @@
 package hub
 
 import (
+	"fmt"
 	"os"
 	"sync"
 	"testing"
@@ -430,3 +431,21 @@ func TestStateManager(t *testing.T) {
 		t.Error("StateManager should not be nil")
 	}
 }
+
+func BenchmarkAskAnswer(b *testing.B) {
+	h := New(b.TempDir())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := fmt.Sprintf("q-%d", i)
+		done := make(chan string)
+		go func() {
+			done <- h.Ask(&Question{ID: id, GoalID: "abc1234", SessionID: "s1", Question: fmt.Sprintf("Question %d?", i)})
+		}()
+		for !h.Answer(id, "yes") {
+			// Wait for the question to be registered
+			time.Sleep(time.Microsecond)
+		}
+		<-done
+	}
+}
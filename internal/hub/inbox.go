@@ -0,0 +1,191 @@
+package hub
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// Why a question is in a user's inbox, most relevant first
+const (
+	InboxAssigned   = "assigned"   // Assigned to the user by a question rule
+	InboxOwnedGoal  = "owned_goal" // Unassigned, on a goal the user created
+	InboxUnassigned = "unassigned" // Unassigned, on someone else's goal
+)
+
+// InboxQuestion is a pending question and why it is in the inbox
+type InboxQuestion struct {
+	*Question
+	Reason    string `json:"reason"` // InboxAssigned, InboxOwnedGoal or InboxUnassigned
+	GoalTitle string `json:"goal_title,omitempty"`
+}
+
+// InboxMention is a comment mentioning the user that they haven't read
+type InboxMention struct {
+	*Comment
+	GoalTitle string `json:"goal_title,omitempty"`
+}
+
+// InboxGoal is a goal waiting on a reviewer
+type InboxGoal struct {
+	ID     string          `json:"id"`
+	Title  string          `json:"title"`
+	Owner  string          `json:"owner,omitempty"`
+	State  goals.GoalState `json:"state"`
+	Since  time.Time       `json:"since,omitempty"`
+	Status string          `json:"status"` // "unreviewed" or "changes_done"
+}
+
+// Inbox is what needs a user's attention right now
+type Inbox struct {
+	User           string            `json:"user"`
+	GeneratedAt    time.Time         `json:"generated_at"`
+	Questions      []InboxQuestion   `json:"questions"` // Most relevant first, oldest first within each reason
+	Mentions       []InboxMention    `json:"mentions"`  // Unread, newest first
+	MentionsReadAt *time.Time        `json:"mentions_read_at,omitempty"`
+	Review         []InboxGoal       `json:"review"`      // Goals of projects requiring review, with no executor running
+	StuckGoals     []goals.StuckGoal `json:"stuck_goals"` // Stuck goals the user created
+}
+
+// Count returns the number of items in the inbox
+func (in *Inbox) Count() int {
+	return len(in.Questions) + len(in.Mentions) + len(in.Review) + len(in.StuckGoals)
+}
+
+// BuildInbox collects what needs a user: pending questions (assigned to them,
+// then unassigned ones on their goals, then other unassigned ones), comments
+// mentioning them since they last marked mentions read, active goals waiting
+// for a review in projects that require one, and the stuck goals they created.
+func (h *Hub) BuildInbox(user string) (*Inbox, error) {
+	in := &Inbox{
+		User:        user,
+		GeneratedAt: time.Now(),
+		Questions:   []InboxQuestion{},
+		Mentions:    []InboxMention{},
+		Review:      []InboxGoal{},
+		StuckGoals:  []goals.StuckGoal{},
+	}
+
+	active, err := goals.NewRegistry(h.dir).List(func(e goals.RegistryEntry) bool {
+		return e.Status == "active"
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	byID := make(map[string]goals.RegistryEntry, len(active))
+	for _, e := range active {
+		byID[e.ID] = e
+	}
+
+	rank := map[string]int{InboxAssigned: 0, InboxOwnedGoal: 1, InboxUnassigned: 2}
+	for _, q := range h.GetPendingQuestions() {
+		reason := InboxUnassigned
+		switch {
+		case q.IsAssignedTo(user):
+			reason = InboxAssigned
+		case len(q.AssignedTo) > 0:
+			continue
+		case user != "" && byID[q.GoalID].CreatedBy == user:
+			reason = InboxOwnedGoal
+		}
+		in.Questions = append(in.Questions, InboxQuestion{Question: q, Reason: reason, GoalTitle: byID[q.GoalID].Title})
+	}
+	sort.SliceStable(in.Questions, func(i, j int) bool {
+		return rank[in.Questions[i].Reason] < rank[in.Questions[j].Reason]
+	})
+
+	if user != "" {
+		prefs, err := h.preferences.Get(user)
+		if err != nil {
+			return nil, err
+		}
+		in.MentionsReadAt = prefs.MentionsReadAt
+		for _, e := range active {
+			comments, err := h.GetComments(e.ID)
+			if err != nil {
+				continue
+			}
+			for _, c := range comments {
+				if c.User == user || !containsString(c.Mentions, user) {
+					continue
+				}
+				if in.MentionsReadAt != nil && !c.CreatedAt.After(*in.MentionsReadAt) {
+					continue
+				}
+				in.Mentions = append(in.Mentions, InboxMention{Comment: c, GoalTitle: e.Title})
+			}
+		}
+		sort.SliceStable(in.Mentions, func(i, j int) bool {
+			return in.Mentions[i].CreatedAt.After(in.Mentions[j].CreatedAt)
+		})
+	}
+
+	running := make(map[string]bool)
+	for _, e := range h.GetActiveExecutors() {
+		running[e.GoalID] = true
+	}
+	requiresReview := make(map[string]bool)
+	for _, e := range active {
+		if running[e.ID] || !anyRequiresReview(h.dir, e.Projects, requiresReview) {
+			continue
+		}
+		// Goals still being worked on, and reviewed ones, aren't waiting
+		state, err := h.stateManager.GetState(e.ID)
+		if err != nil || state != goals.StateWorking {
+			continue
+		}
+		goal := InboxGoal{ID: e.ID, Title: e.Title, Owner: e.CreatedBy, State: state, Status: "unreviewed"}
+		// Reviewed before and back to working: the requested changes are in
+		if review, err := h.stateManager.LatestReview(e.ID); err == nil && review != nil {
+			goal.Status = "changes_done"
+		}
+		if last, err := h.stateManager.GetLastEvent(e.ID); err == nil && last != nil {
+			goal.Since = last.Timestamp
+		}
+		in.Review = append(in.Review, goal)
+	}
+
+	if user != "" {
+		stuck, err := h.FindStuckGoals()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check stuck goals: %w", err)
+		}
+		for _, s := range stuck {
+			if e, ok := byID[s.GoalID]; ok && e.CreatedBy == user {
+				in.StuckGoals = append(in.StuckGoals, s)
+			}
+		}
+	}
+	return in, nil
+}
+
+// anyRequiresReview returns true if one of the projects requires an approved
+// review before completing. Results are cached in cache by project name.
+func anyRequiresReview(dir string, projects []string, cache map[string]bool) bool {
+	for _, name := range projects {
+		required, ok := cache[name]
+		if !ok {
+			if p, err := goals.ParseProject(dir, name); err == nil {
+				required = p.RequireReview
+			}
+			cache[name] = required
+		}
+		if required {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkMentionsRead marks the mentions of a user up to at as read, dropping
+// them from the user's inbox
+func (h *Hub) MarkMentionsRead(user string, at time.Time) error {
+	if user == "" {
+		return fmt.Errorf("user is required")
+	}
+	patch := fmt.Sprintf(`{"mentions_read_at": %q}`, at.UTC().Format(time.RFC3339Nano))
+	_, err := h.preferences.Update(user, []byte(patch))
+	return err
+}
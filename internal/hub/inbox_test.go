@@ -0,0 +1,101 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestBuildInbox(t *testing.T) {
+	h := setupTestHub(t)
+	for _, id := range []string{"aaa1111", "bbb2222", "ccc3333"} {
+		os.MkdirAll(filepath.Join(h.dir, "goals", "active", id), 0755)
+		os.WriteFile(filepath.Join(h.dir, "goals", "active", id, id+".md"), []byte("# Goal #"+id+": "+id+"\n"), 0644)
+	}
+	os.MkdirAll(filepath.Join(h.dir, "projects"), 0755)
+	os.WriteFile(filepath.Join(h.dir, "projects", "api.md"), []byte("# Project: api\n\n**Require Review**: `true`\n"), 0644)
+	registry := goals.NewRegistry(h.dir)
+	for _, e := range []goals.RegistryEntry{
+		{ID: "aaa1111", Title: "Deploy pipeline", Status: "active", CreatedBy: "alice"},
+		{ID: "bbb2222", Title: "Fix login", Status: "active", CreatedBy: "bob", Projects: []string{"api"}},
+		{ID: "ccc3333", Title: "Add search", Status: "active", CreatedBy: "bob", Projects: []string{"api"}},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Rules().Add(&QuestionRule{ID: "deploy", Pattern: "(?i)deploy", AssignTo: []string{"ops"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, q := range []*Question{
+		{ID: "q1", GoalID: "bbb2222", Question: "Which provider?"},
+		{ID: "q2", GoalID: "aaa1111", Question: "Which region?"},
+		{ID: "q3", GoalID: "aaa1111", Question: "Deploy to staging?"},
+	} {
+		wg.Add(1)
+		go func(q *Question) {
+			defer wg.Done()
+			h.Ask(q)
+		}(q)
+	}
+	time.Sleep(50 * time.Millisecond)
+	defer func() {
+		h.Answer("q1", "github")
+		h.Answer("q2", "eu")
+		h.Answer("q3", "yes")
+		wg.Wait()
+	}()
+
+	if _, err := h.AddComment("aaa1111", "", "bob", "@alice can you check the runner?"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.AddComment("aaa1111", "", "alice", "@alice note to self"); err != nil {
+		t.Fatal(err)
+	}
+
+	// bbb2222 is done working and waits for review; ccc3333's executor is still running
+	sm := h.StateManager()
+	sm.Transition("bbb2222", goals.StateWorking, "Goal ready", nil)
+	sm.Transition("ccc3333", goals.StateWorking, "Goal ready", nil)
+	h.RegisterExecutor("ccc3333", "s1", t.TempDir(), "bob")
+
+	inbox, err := h.BuildInbox("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inbox.Questions) != 2 || inbox.Questions[0].ID != "q2" || inbox.Questions[0].Reason != InboxOwnedGoal ||
+		inbox.Questions[1].ID != "q1" || inbox.Questions[1].Reason != InboxUnassigned {
+		t.Errorf("expected alice's goal's question before the other unassigned one, got %+v", inbox.Questions)
+	}
+	if len(inbox.Mentions) != 1 || inbox.Mentions[0].User != "bob" || inbox.Mentions[0].GoalTitle != "Deploy pipeline" {
+		t.Errorf("expected bob's mention, got %+v", inbox.Mentions)
+	}
+	if len(inbox.Review) != 1 || inbox.Review[0].ID != "bbb2222" || inbox.Review[0].Status != "unreviewed" {
+		t.Errorf("expected bbb2222 waiting for review, got %+v", inbox.Review)
+	}
+
+	ops, err := h.BuildInbox("ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops.Questions) != 3 || ops.Questions[0].ID != "q3" || ops.Questions[0].Reason != InboxAssigned {
+		t.Errorf("expected the assigned question first for ops, got %+v", ops.Questions)
+	}
+
+	if err := h.MarkMentionsRead("alice", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	inbox, err = h.BuildInbox("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inbox.Mentions) != 0 || inbox.MentionsReadAt == nil {
+		t.Errorf("expected mentions read, got %+v", inbox.Mentions)
+	}
+}
@@ -0,0 +1,53 @@
+package hub
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRecentIncidents is how many incidents are kept in memory for /api/health
+const maxRecentIncidents = 20
+
+// Incident is a request that failed with a panic. Its ID is returned to the
+// client and logged with the stack, so reports can be matched to the log.
+type Incident struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Error  string    `json:"error"`
+}
+
+// ErrorStats counts requests that failed with a panic since the hub started
+type ErrorStats struct {
+	Panics int64      `json:"panics"`
+	Recent []Incident `json:"recent,omitempty"` // Newest first
+}
+
+// incidentLog holds the error counter and the latest incidents
+type incidentLog struct {
+	mu     sync.Mutex
+	panics int64
+	recent []Incident
+}
+
+// RecordIncident counts a request that failed with a panic
+func (h *Hub) RecordIncident(incident Incident) {
+	h.incidents.mu.Lock()
+	defer h.incidents.mu.Unlock()
+	h.incidents.panics++
+	h.incidents.recent = append([]Incident{incident}, h.incidents.recent...)
+	if len(h.incidents.recent) > maxRecentIncidents {
+		h.incidents.recent = h.incidents.recent[:maxRecentIncidents]
+	}
+}
+
+// ErrorStats returns the panic count and the latest incidents
+func (h *Hub) ErrorStats() ErrorStats {
+	h.incidents.mu.Lock()
+	defer h.incidents.mu.Unlock()
+	return ErrorStats{
+		Panics: h.incidents.panics,
+		Recent: append([]Incident(nil), h.incidents.recent...),
+	}
+}
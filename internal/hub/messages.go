@@ -0,0 +1,155 @@
+package hub
+
+import (
+	"time"
+)
+
+// User message delivery statuses
+const (
+	MessagePending     = "pending"     // Waiting for the executor's Stop hook
+	MessageDelivered   = "delivered"   // Pulled by the Stop hook and injected
+	MessageProcessed   = "processed"   // The executor finished the turn that read it, or acknowledged it
+	MessageUndelivered = "undelivered" // The executor stopped before pulling it; the next one gets it
+)
+
+// maxUserMessagesPerGoal bounds the delivered messages kept per goal for
+// status queries. Messages still waiting for delivery are never dropped.
+const maxUserMessagesPerGoal = 100
+
+// awaitingDelivery reports whether the next Stop hook should receive msg
+func (m *UserMessage) awaitingDelivery() bool {
+	return m.Status == MessagePending || m.Status == MessageUndelivered
+}
+
+// DeliverUserMessages hands the messages waiting for a goal's executor to
+// its Stop hook and marks them delivered to sessionID (which may be empty
+// for hooks that don't send it). Messages delivered by the previous call are
+// marked processed first: the hook only runs again once the executor
+// finished the turn that read them.
+func (h *Hub) DeliverUserMessages(goalID, sessionID string) []*UserMessage {
+	now := time.Now()
+	var processed, delivered []*UserMessage
+
+	h.msgMu.Lock()
+	for _, msg := range h.userMessages[goalID] {
+		switch {
+		case msg.Status == MessageDelivered:
+			msg.Status = MessageProcessed
+			msg.ProcessedAt = &now
+			processed = append(processed, msg)
+		case msg.awaitingDelivery():
+			msg.Status = MessageDelivered
+			msg.DeliveredAt = &now
+			msg.SessionID = sessionID
+			delivered = append(delivered, msg)
+		}
+	}
+	processedCopies := copyUserMessages(processed)
+	deliveredCopies := copyUserMessages(delivered)
+	h.msgMu.Unlock()
+
+	h.recordMessageStatus(goalID, "user_message_processed", processedCopies)
+	h.recordMessageStatus(goalID, "user_message_delivered", deliveredCopies)
+	return deliveredCopies
+}
+
+// AckUserMessages records the executor's acknowledgement that it processed
+// delivered messages. With no IDs, every delivered message of the goal is
+// acknowledged. It returns the messages marked processed.
+func (h *Hub) AckUserMessages(goalID string, ids []string) []*UserMessage {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	now := time.Now()
+	var processed []*UserMessage
+
+	h.msgMu.Lock()
+	for _, msg := range h.userMessages[goalID] {
+		if msg.Status == MessageDelivered && (len(ids) == 0 || want[msg.ID]) {
+			msg.Status = MessageProcessed
+			msg.ProcessedAt = &now
+			processed = append(processed, msg)
+		}
+	}
+	copies := copyUserMessages(processed)
+	h.msgMu.Unlock()
+
+	h.recordMessageStatus(goalID, "user_message_processed", copies)
+	return copies
+}
+
+// markUserMessagesUndelivered flags the messages a goal's executor never
+// pulled when it stopped. They stay queued for the goal's next executor.
+func (h *Hub) markUserMessagesUndelivered(goalID string) []*UserMessage {
+	var undelivered []*UserMessage
+
+	h.msgMu.Lock()
+	for _, msg := range h.userMessages[goalID] {
+		if msg.Status == MessagePending {
+			msg.Status = MessageUndelivered
+			undelivered = append(undelivered, msg)
+		}
+	}
+	copies := copyUserMessages(undelivered)
+	h.msgMu.Unlock()
+
+	h.recordMessageStatus(goalID, "user_message_undelivered", copies)
+	return copies
+}
+
+// GetUserMessages returns a goal's recent messages with their delivery
+// status, oldest first
+func (h *Hub) GetUserMessages(goalID string) []*UserMessage {
+	h.msgMu.RLock()
+	defer h.msgMu.RUnlock()
+	return copyUserMessages(h.userMessages[goalID])
+}
+
+// recordMessageStatus records status changes in history and broadcasts them
+func (h *Hub) recordMessageStatus(goalID, activity string, messages []*UserMessage) {
+	for _, msg := range messages {
+		h.history.RecordActivity(goalID, msg.SessionID, activity, map[string]interface{}{
+			"message_id": msg.ID,
+			"content":    msg.Content,
+			"user":       msg.User,
+		})
+		h.broadcast(Event{
+			Type: EventUserMessageStatus,
+			Data: map[string]interface{}{
+				"goal_id":    goalID,
+				"message_id": msg.ID,
+				"status":     msg.Status,
+				"session_id": msg.SessionID,
+			},
+		})
+	}
+}
+
+// trimUserMessagesLocked drops the oldest delivered messages beyond
+// maxUserMessagesPerGoal (caller holds msgMu)
+func (h *Hub) trimUserMessagesLocked(goalID string) {
+	messages := h.userMessages[goalID]
+	excess := len(messages) - maxUserMessagesPerGoal
+	if excess <= 0 {
+		return
+	}
+	kept := messages[:0]
+	for _, msg := range messages {
+		if excess > 0 && !msg.awaitingDelivery() {
+			excess--
+			continue
+		}
+		kept = append(kept, msg)
+	}
+	h.userMessages[goalID] = kept
+}
+
+func copyUserMessages(messages []*UserMessage) []*UserMessage {
+	copies := make([]*UserMessage, len(messages))
+	for i, msg := range messages {
+		c := *msg
+		copies[i] = &c
+	}
+	return copies
+}
@@ -0,0 +1,98 @@
+package hub
+
+import (
+	"testing"
+)
+
+func messageStatuses(h *Hub, goalID string) map[string]string {
+	statuses := make(map[string]string)
+	for _, msg := range h.GetUserMessages(goalID) {
+		statuses[msg.ID] = msg.Status
+	}
+	return statuses
+}
+
+func TestUserMessageDelivery(t *testing.T) {
+	h := New(t.TempDir())
+
+	first := h.SendUserMessage("abc1234", "use the v2 API", "alice")
+	if first.Status != MessagePending || !h.HasPendingUserMessages("abc1234") {
+		t.Fatalf("expected a pending message, got %+v", first)
+	}
+
+	delivered := h.DeliverUserMessages("abc1234", "s1")
+	if len(delivered) != 1 || delivered[0].Status != MessageDelivered || delivered[0].SessionID != "s1" || delivered[0].DeliveredAt == nil {
+		t.Fatalf("expected the message delivered to s1, got %+v", delivered)
+	}
+	if h.HasPendingUserMessages("abc1234") {
+		t.Error("expected nothing pending after delivery")
+	}
+
+	// The next Stop hook means the executor finished the turn that read it
+	second := h.SendUserMessage("abc1234", "and add tests", "bob")
+	delivered = h.DeliverUserMessages("abc1234", "s1")
+	if len(delivered) != 1 || delivered[0].ID != second.ID {
+		t.Fatalf("expected only the new message delivered, got %+v", delivered)
+	}
+	statuses := messageStatuses(h, "abc1234")
+	if statuses[first.ID] != MessageProcessed || statuses[second.ID] != MessageDelivered {
+		t.Errorf("expected the first processed and the second delivered, got %v", statuses)
+	}
+
+	// Explicit acknowledgement
+	acked := h.AckUserMessages("abc1234", []string{second.ID})
+	if len(acked) != 1 || acked[0].ProcessedAt == nil {
+		t.Fatalf("expected the acknowledged message processed, got %+v", acked)
+	}
+	if acked := h.AckUserMessages("abc1234", nil); len(acked) != 0 {
+		t.Errorf("expected nothing left to acknowledge, got %+v", acked)
+	}
+
+	history, _ := h.GetGoalHistory("abc1234", 0)
+	counts := make(map[string]int)
+	for _, entry := range history {
+		counts[entry.Type]++
+	}
+	if counts["user_message"] != 2 || counts["user_message_delivered"] != 2 || counts["user_message_processed"] != 2 {
+		t.Errorf("expected each status change recorded, got %v", counts)
+	}
+}
+
+func TestUserMessagesUndeliveredWhenExecutorStops(t *testing.T) {
+	h := New(t.TempDir())
+	events := h.Subscribe()
+	defer h.Unsubscribe(events)
+
+	h.RegisterExecutor("abc1234", "s1", t.TempDir(), "alice")
+	h.RegisterExecutor("abc1234", "s2", t.TempDir(), "alice")
+	msg := h.SendUserMessage("abc1234", "stop and rebase", "alice")
+
+	// Another executor of the goal can still pull it
+	h.StopExecutor("abc1234", "s1", "completed")
+	if status := messageStatuses(h, "abc1234")[msg.ID]; status != MessagePending {
+		t.Errorf("expected the message still pending, got %s", status)
+	}
+
+	h.StopExecutor("abc1234", "s2", "completed")
+	if status := messageStatuses(h, "abc1234")[msg.ID]; status != MessageUndelivered {
+		t.Errorf("expected the message undelivered, got %s", status)
+	}
+
+	var stopped []Event
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventExecutorStopped {
+			stopped = append(stopped, e)
+		}
+	}
+	if len(stopped) != 2 || stopped[1].Data.(map[string]interface{})["undelivered"] != 1 {
+		t.Errorf("expected the last stop to report the undelivered message, got %+v", stopped)
+	}
+
+	// The goal's next executor receives it
+	if !h.HasPendingUserMessages("abc1234") {
+		t.Error("expected the undelivered message to wait for the next executor")
+	}
+	if delivered := h.DeliverUserMessages("abc1234", "s3"); len(delivered) != 1 || delivered[0].ID != msg.ID {
+		t.Errorf("expected the next executor to get the message, got %+v", delivered)
+	}
+}
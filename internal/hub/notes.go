@@ -0,0 +1,140 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// MaxNotesBytes bounds a goal's notes document
+const MaxNotesBytes = 64 * 1024
+
+// maxNoteRevisions is how many earlier versions of a goal's notes are kept
+const maxNoteRevisions = 50
+
+var (
+	// ErrNotesTooLarge is returned when notes exceed MaxNotesBytes
+	ErrNotesTooLarge = fmt.Errorf("notes exceed %d bytes", MaxNotesBytes)
+
+	// ErrNotesConflict is returned when notes changed since the revision the
+	// editor started from
+	ErrNotesConflict = errors.New("notes were changed by someone else")
+)
+
+// NoteRevision is one saved version of a goal's notes
+type NoteRevision struct {
+	Revision  int       `json:"revision"`
+	Content   string    `json:"content"`
+	User      string    `json:"user,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GoalNotes is a goal's freeform scratchpad: context humans park for the
+// executor's next session, separate from the goal markdown. Stored in
+// <vega-dir>/.vega-hub-notes/goal-<id>.json with its earlier revisions.
+type GoalNotes struct {
+	GoalID string `json:"goal_id"`
+	NoteRevision
+	Revisions []NoteRevision `json:"revisions,omitempty"` // Earlier versions, newest first
+}
+
+// notesFile returns the notes path for a goal
+func (h *Hub) notesFile(goalID string) string {
+	return filepath.Join(h.dir, ".vega-hub-notes", fmt.Sprintf("goal-%s.json", goalID))
+}
+
+// GetGoalNotes returns a goal's notes; revision 0 means none were written
+func (h *Hub) GetGoalNotes(goalID string) (*GoalNotes, error) {
+	h.notesMu.Lock()
+	defer h.notesMu.Unlock()
+	return h.readGoalNotes(goalID)
+}
+
+func (h *Hub) readGoalNotes(goalID string) (*GoalNotes, error) {
+	data, err := os.ReadFile(h.notesFile(goalID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &GoalNotes{GoalID: goalID}, nil
+		}
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+	var notes GoalNotes
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("failed to parse notes: %w", err)
+	}
+	return &notes, nil
+}
+
+// SaveGoalNotes replaces a goal's notes, keeping the previous version in its
+// revisions. With baseRevision >= 0 the save fails with ErrNotesConflict
+// unless the notes are still at that revision, so concurrent editors don't
+// overwrite each other.
+func (h *Hub) SaveGoalNotes(goalID, content, user string, baseRevision int) (*GoalNotes, error) {
+	if len(content) > MaxNotesBytes {
+		return nil, ErrNotesTooLarge
+	}
+
+	h.notesMu.Lock()
+	notes, err := h.readGoalNotes(goalID)
+	if err != nil {
+		h.notesMu.Unlock()
+		return nil, err
+	}
+	if baseRevision >= 0 && baseRevision != notes.Revision {
+		h.notesMu.Unlock()
+		return nil, fmt.Errorf("%w (now at revision %d)", ErrNotesConflict, notes.Revision)
+	}
+
+	if notes.Revision > 0 {
+		notes.Revisions = append([]NoteRevision{notes.NoteRevision}, notes.Revisions...)
+		if len(notes.Revisions) > maxNoteRevisions {
+			notes.Revisions = notes.Revisions[:maxNoteRevisions]
+		}
+	}
+	notes.NoteRevision = NoteRevision{
+		Revision:  notes.Revision + 1,
+		Content:   content,
+		User:      user,
+		UpdatedAt: time.Now(),
+	}
+	err = h.writeGoalNotes(notes)
+	h.notesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	h.history.RecordActivity(goalID, "", "notes_updated", map[string]interface{}{
+		"revision": notes.Revision,
+		"user":     user,
+	})
+	h.broadcast(Event{
+		Type: EventGoalNotesUpdated,
+		Data: map[string]interface{}{
+			"goal_id":  goalID,
+			"revision": notes.Revision,
+			"user":     user,
+		},
+	})
+	return notes, nil
+}
+
+// writeGoalNotes stores notes atomically (caller holds notesMu)
+func (h *Hub) writeGoalNotes(notes *GoalNotes) error {
+	path := h.notesFile(notes.GoalID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notes: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package hub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGoalNotes(t *testing.T) {
+	h := New(t.TempDir())
+
+	notes, err := h.GetGoalNotes("abc1234")
+	if err != nil || notes.Revision != 0 || notes.Content != "" {
+		t.Fatalf("expected empty notes, got %+v (%v)", notes, err)
+	}
+
+	if _, err := h.SaveGoalNotes("abc1234", "Staging DB is read-only", "alice", 0); err != nil {
+		t.Fatalf("SaveGoalNotes: %v", err)
+	}
+	saved, err := h.SaveGoalNotes("abc1234", "Staging DB is read-only until Friday", "bob", 1)
+	if err != nil {
+		t.Fatalf("SaveGoalNotes: %v", err)
+	}
+	if saved.Revision != 2 || saved.User != "bob" || len(saved.Revisions) != 1 || saved.Revisions[0].User != "alice" {
+		t.Errorf("expected revision 2 with the first kept, got %+v", saved)
+	}
+
+	// An editor starting from revision 1 would overwrite bob's change
+	if _, err := h.SaveGoalNotes("abc1234", "stale", "carol", 1); !errors.Is(err, ErrNotesConflict) {
+		t.Errorf("expected ErrNotesConflict, got %v", err)
+	}
+	if _, err := h.SaveGoalNotes("abc1234", strings.Repeat("x", MaxNotesBytes+1), "carol", -1); !errors.Is(err, ErrNotesTooLarge) {
+		t.Errorf("expected ErrNotesTooLarge, got %v", err)
+	}
+
+	// Reloaded from disk and handed to the next executor
+	notes, _ = New(h.dir).GetGoalNotes("abc1234")
+	if notes.Content != "Staging DB is read-only until Friday" {
+		t.Errorf("expected the notes persisted, got %+v", notes)
+	}
+	pack := h.BuildContextPack("abc1234", "s1", t.TempDir())
+	if !strings.Contains(pack.Text, "## Goal Notes\n\nStaging DB is read-only until Friday") {
+		t.Errorf("expected the notes in the context pack, got:\n%s", pack.Text)
+	}
+}
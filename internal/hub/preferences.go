@@ -0,0 +1,186 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// maxPreferenceExtras bounds the free-form settings a user can store
+const maxPreferenceExtras = 64
+
+// NotificationPreferences controls how the web UI alerts a user
+type NotificationPreferences struct {
+	Desktop   bool `json:"desktop"`              // Browser notifications for new questions
+	Sound     bool `json:"sound"`                // Play a sound for new questions
+	OnlyMine  bool `json:"only_mine,omitempty"`  // Only questions assigned to the user
+	StuckGoal bool `json:"stuck_goal,omitempty"` // Alert when goals get stuck
+}
+
+// Preferences are a user's UI settings
+type Preferences struct {
+	Theme               string                     `json:"theme,omitempty"` // "light", "dark", "system"
+	DefaultProject      string                     `json:"default_project,omitempty"`
+	DefaultExecutorMode string                     `json:"default_executor_mode,omitempty"` // One of ValidModes
+	Notifications       NotificationPreferences    `json:"notifications"`
+	Extra               map[string]json.RawMessage `json:"extra,omitempty"`            // Other UI settings, stored as-is
+	MentionsReadAt      *time.Time                 `json:"mentions_read_at,omitempty"` // Inbox mentions up to here are read
+	UpdatedAt           time.Time                  `json:"updated_at,omitempty"`
+}
+
+// defaultPreferences are returned for users who haven't saved any
+func defaultPreferences() *Preferences {
+	return &Preferences{
+		Theme:         "system",
+		Notifications: NotificationPreferences{Desktop: true, Sound: true},
+	}
+}
+
+// Validate checks the theme, executor mode and extras
+func (p *Preferences) Validate() error {
+	switch p.Theme {
+	case "", "light", "dark", "system":
+	default:
+		return fmt.Errorf("invalid theme %q", p.Theme)
+	}
+	if p.DefaultExecutorMode != "" && !ValidModes[p.DefaultExecutorMode] {
+		return fmt.Errorf("invalid executor mode %q", p.DefaultExecutorMode)
+	}
+	if len(p.Extra) > maxPreferenceExtras {
+		return fmt.Errorf("too many extra settings (max %d)", maxPreferenceExtras)
+	}
+	return nil
+}
+
+// PreferenceStore keeps users' preferences in <vega-dir>/.vega-hub-preferences.json
+type PreferenceStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewPreferenceStore creates a preference store for the vega-missile directory
+func NewPreferenceStore(dir string) *PreferenceStore {
+	return &PreferenceStore{dir: dir}
+}
+
+func (s *PreferenceStore) path() string {
+	return filepath.Join(s.dir, ".vega-hub-preferences.json")
+}
+
+// load reads all users' preferences (caller holds lock)
+func (s *PreferenceStore) load() (map[string]*Preferences, error) {
+	all := make(map[string]*Preferences)
+	data, err := os.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return all, nil
+		}
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse preferences: %w", err)
+	}
+	return all, nil
+}
+
+// save writes all users' preferences atomically (caller holds lock)
+func (s *PreferenceStore) save(all map[string]*Preferences) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+	return nil
+}
+
+// Get returns a user's preferences, or the defaults if they saved none
+func (s *PreferenceStore) Get(user string) (*Preferences, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := all[user]; ok {
+		return p, nil
+	}
+	return defaultPreferences(), nil
+}
+
+// Update applies a JSON patch to a user's preferences: fields present in
+// patch replace the stored ones, others are kept. Extra settings are merged
+// key by key; a null value removes a key.
+func (s *PreferenceStore) Update(user string, patch []byte) (*Preferences, error) {
+	return s.apply(user, patch, false)
+}
+
+// Replace sets a user's preferences to the defaults overlaid with prefs
+func (s *PreferenceStore) Replace(user string, prefs []byte) (*Preferences, error) {
+	return s.apply(user, prefs, true)
+}
+
+func (s *PreferenceStore) apply(user string, patch []byte, replace bool) (*Preferences, error) {
+	if user == "" {
+		return nil, fmt.Errorf("user is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	prefs, ok := all[user]
+	if !ok || replace {
+		prefs = defaultPreferences()
+	}
+
+	// Unmarshalling onto the stored value keeps fields the patch leaves out
+	updated := *prefs
+	updated.Extra = make(map[string]json.RawMessage, len(prefs.Extra))
+	for k, v := range prefs.Extra {
+		updated.Extra[k] = v
+	}
+	if err := json.Unmarshal(patch, &updated); err != nil {
+		return nil, fmt.Errorf("invalid preferences: %w", err)
+	}
+	for k, v := range updated.Extra {
+		if string(v) == "null" {
+			delete(updated.Extra, k)
+		}
+	}
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = time.Now()
+	all[user] = &updated
+	if err := s.save(all); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Reset removes a user's saved preferences
+func (s *PreferenceStore) Reset(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[user]; !ok {
+		return nil
+	}
+	delete(all, user)
+	return s.save(all)
+}
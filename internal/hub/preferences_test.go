@@ -0,0 +1,59 @@
+package hub
+
+import (
+	"testing"
+)
+
+func TestPreferenceStore(t *testing.T) {
+	store := NewPreferenceStore(t.TempDir())
+
+	prefs, err := store.Get("alex")
+	if err != nil || prefs.Theme != "system" || !prefs.Notifications.Desktop {
+		t.Fatalf("expected defaults, got %+v (err: %v)", prefs, err)
+	}
+
+	prefs, err = store.Update("alex", []byte(`{"theme":"dark","default_project":"api","extra":{"sidebar":"collapsed","density":2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Theme != "dark" || prefs.DefaultProject != "api" || len(prefs.Extra) != 2 {
+		t.Errorf("unexpected preferences: %+v", prefs)
+	}
+
+	// Patches keep fields they leave out, merge extras and drop null extras
+	prefs, err = store.Update("alex", []byte(`{"notifications":{"sound":false},"extra":{"density":null}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Theme != "dark" || prefs.Notifications.Sound || !prefs.Notifications.Desktop {
+		t.Errorf("expected merged preferences, got %+v", prefs)
+	}
+	if _, ok := prefs.Extra["density"]; ok || string(prefs.Extra["sidebar"]) != `"collapsed"` {
+		t.Errorf("unexpected extras: %v", prefs.Extra)
+	}
+
+	for _, bad := range []string{`{"theme":"neon"}`, `{"default_executor_mode":"yolo"}`, `not json`} {
+		if _, err := store.Update("alex", []byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	if prefs, _ := store.Get("alex"); prefs.Theme != "dark" {
+		t.Errorf("failed updates must not change stored preferences, got %+v", prefs)
+	}
+
+	// Replace starts from the defaults
+	prefs, err = store.Replace("alex", []byte(`{"default_executor_mode":"plan"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Theme != "system" || prefs.DefaultProject != "" || prefs.DefaultExecutorMode != "plan" {
+		t.Errorf("unexpected replaced preferences: %+v", prefs)
+	}
+
+	if err := store.Reset("alex"); err != nil {
+		t.Fatal(err)
+	}
+	if prefs, _ := store.Get("alex"); prefs.DefaultExecutorMode != "" {
+		t.Errorf("expected defaults after reset, got %+v", prefs)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // PreflightCheck represents a single pre-flight check result
@@ -51,8 +53,46 @@ func (p *PreflightChecker) SetMinDiskMB(mb int64) {
 	p.minDiskMB = mb
 }
 
+// Pre-flight check names, as used in PreflightResult.Checks
+const (
+	PreflightWorktreeClean   = "worktree_clean"
+	PreflightWorktreeSynced  = "worktree_synced"
+	PreflightDiskSpace       = "disk_space"
+	PreflightCredentials     = "credentials"
+	PreflightBranchAvailable = "branch_available"
+	PreflightNoInProgressOps = "no_in_progress_ops"
+)
+
+// Check sets for each goal operation
+var (
+	// CreateChecks run on the project checkout before a goal worktree is created
+	CreateChecks = []string{PreflightWorktreeClean, PreflightWorktreeSynced, PreflightDiskSpace,
+		PreflightCredentials, PreflightBranchAvailable, PreflightNoInProgressOps}
+	// SpawnChecks run on the goal worktree before an executor is spawned
+	SpawnChecks = []string{PreflightDiskSpace, PreflightNoInProgressOps}
+	// CompleteChecks run on the project checkout before a goal is merged into it
+	CompleteChecks = []string{PreflightWorktreeClean, PreflightNoInProgressOps}
+)
+
+// IsPreflightCheck returns true if name is a known check
+func IsPreflightCheck(name string) bool {
+	for _, c := range CreateChecks {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
 // RunAll executes all pre-flight checks and returns a consolidated result
 func (p *PreflightChecker) RunAll() *PreflightResult {
+	return p.RunChecks(CreateChecks)
+}
+
+// RunChecks executes the named checks in order and returns a consolidated
+// result. The branch check is skipped when the checker has no branch name;
+// unknown names are ignored.
+func (p *PreflightChecker) RunChecks(names []string) *PreflightResult {
 	result := &PreflightResult{
 		Ready:          true,
 		Checks:         make(map[string]PreflightCheck),
@@ -60,75 +100,46 @@ func (p *PreflightChecker) RunAll() *PreflightResult {
 		FixCommands:    []string{},
 	}
 
-	// Check worktree cleanliness
-	cleanCheck := p.CheckWorktreeClean()
-	result.Checks["worktree_clean"] = cleanCheck
-	if !cleanCheck.Passed {
-		result.Ready = false
-		result.BlockingIssues = append(result.BlockingIssues, "worktree_clean")
-		result.FixCommands = append(result.FixCommands,
-			fmt.Sprintf("cd %s && git stash", p.worktreeBase),
-			fmt.Sprintf("cd %s && git checkout -- .", p.worktreeBase),
-		)
-	}
-
-	// Check worktree sync with remote
-	syncCheck := p.CheckWorktreeSync()
-	result.Checks["worktree_synced"] = syncCheck
-	if !syncCheck.Passed {
-		result.Ready = false
-		result.BlockingIssues = append(result.BlockingIssues, "worktree_synced")
-		result.FixCommands = append(result.FixCommands,
-			fmt.Sprintf("cd %s && git pull origin %s", p.worktreeBase, p.baseBranch),
-		)
-	}
-
-	// Check disk space
-	diskCheck := p.CheckDiskSpace()
-	result.Checks["disk_space"] = diskCheck
-	if !diskCheck.Passed {
-		result.Ready = false
-		result.BlockingIssues = append(result.BlockingIssues, "disk_space")
-		result.FixCommands = append(result.FixCommands,
-			"Free up disk space (at least 1GB required)",
-		)
-	}
-
-	// Check git credentials
-	credCheck := p.CheckCredentials()
-	result.Checks["credentials"] = credCheck
-	if !credCheck.Passed {
-		result.Ready = false
-		result.BlockingIssues = append(result.BlockingIssues, "credentials")
-		result.FixCommands = append(result.FixCommands,
-			"Configure git credentials: gh auth login OR glab auth login",
-		)
-	}
+	for _, name := range names {
+		var check PreflightCheck
+		var fixes []string
+		switch name {
+		case PreflightWorktreeClean:
+			check = p.CheckWorktreeClean()
+			fixes = []string{
+				fmt.Sprintf("cd %s && git stash", p.worktreeBase),
+				fmt.Sprintf("cd %s && git checkout -- .", p.worktreeBase),
+			}
+		case PreflightWorktreeSynced:
+			check = p.CheckWorktreeSync()
+			fixes = []string{fmt.Sprintf("cd %s && git pull origin %s", p.worktreeBase, p.baseBranch)}
+		case PreflightDiskSpace:
+			check = p.CheckDiskSpace()
+			fixes = []string{fmt.Sprintf("Free up disk space (at least %dMB required)", p.minDiskMB)}
+		case PreflightCredentials:
+			check = p.CheckCredentials()
+			fixes = []string{"Configure git credentials: gh auth login OR glab auth login"}
+		case PreflightBranchAvailable:
+			if p.branchName == "" {
+				continue
+			}
+			check = p.CheckBranchAvailable()
+			fixes = []string{fmt.Sprintf("git branch -d %s (delete existing branch)", p.branchName)}
+		case PreflightNoInProgressOps:
+			check = p.CheckNoInProgressOps()
+			fixes = []string{fmt.Sprintf("cd %s && git rebase --abort OR git merge --abort", p.worktreeBase)}
+		default:
+			continue
+		}
 
-	// Check branch availability
-	if p.branchName != "" {
-		branchCheck := p.CheckBranchAvailable()
-		result.Checks["branch_available"] = branchCheck
-		if !branchCheck.Passed {
+		result.Checks[name] = check
+		if !check.Passed {
 			result.Ready = false
-			result.BlockingIssues = append(result.BlockingIssues, "branch_available")
-			result.FixCommands = append(result.FixCommands,
-				fmt.Sprintf("git branch -d %s (delete existing branch)", p.branchName),
-			)
+			result.BlockingIssues = append(result.BlockingIssues, name)
+			result.FixCommands = append(result.FixCommands, fixes...)
 		}
 	}
 
-	// Check for in-progress operations (rebase/merge)
-	opCheck := p.CheckNoInProgressOps()
-	result.Checks["no_in_progress_ops"] = opCheck
-	if !opCheck.Passed {
-		result.Ready = false
-		result.BlockingIssues = append(result.BlockingIssues, "no_in_progress_ops")
-		result.FixCommands = append(result.FixCommands,
-			fmt.Sprintf("cd %s && git rebase --abort OR git merge --abort", p.worktreeBase),
-		)
-	}
-
 	return result
 }
 
@@ -269,7 +280,11 @@ func (p *PreflightChecker) CheckBranchAvailable() PreflightCheck {
 
 // CheckNoInProgressOps verifies no rebase/merge is in progress
 func (p *PreflightChecker) CheckNoInProgressOps() PreflightCheck {
+	// Linked worktrees keep their state outside the checkout (.git is a file)
 	gitDir := filepath.Join(p.worktreeBase, ".git")
+	if out, err := exec.Command("git", "-C", p.worktreeBase, "rev-parse", "--absolute-git-dir").Output(); err == nil {
+		gitDir = strings.TrimSpace(string(out))
+	}
 
 	// Check for rebase in progress
 	rebaseMerge := filepath.Join(gitDir, "rebase-merge")
@@ -303,14 +318,14 @@ func (p *PreflightChecker) CheckNoInProgressOps() PreflightCheck {
 // RunPreflightForProject runs preflight checks for a project
 func RunPreflightForProject(vegaDir, projectName string) (*PreflightResult, error) {
 	// Load project config
-	projectPath := filepath.Join(vegaDir, "projects", projectName+".md")
+	l := layout.New(vegaDir)
+	projectPath := l.ProjectConfig(projectName)
 	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("project not found: %s", projectName)
 	}
 
 	// Get worktree base path
-	workspacesDir := filepath.Join(vegaDir, "workspaces", projectName)
-	worktreeBase := filepath.Join(workspacesDir, "worktree-base")
+	worktreeBase := l.WorktreeBase(projectName)
 
 	if _, err := os.Stat(worktreeBase); os.IsNotExist(err) {
 		return nil, fmt.Errorf("worktree-base not found: %s (run 'vega-hub project setup %s' first)", worktreeBase, projectName)
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -270,3 +271,42 @@ func TestPreflightResult_JSONSerialization(t *testing.T) {
 		t.Error("Expected AvailMB to be preserved")
 	}
 }
+
+func TestPreflightChecker_RunChecks(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := filepath.Join(tmpDir, "repo")
+	worktree := filepath.Join(tmpDir, "goal")
+	git := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test User"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", repo)
+	git("-C", repo, "commit", "-q", "--allow-empty", "-m", "initial")
+	git("-C", repo, "worktree", "add", "-q", "-b", "goal", worktree)
+
+	checker := NewPreflightChecker(worktree, "", "")
+	checker.SetMinDiskMB(1)
+
+	result := checker.RunChecks(SpawnChecks)
+	if !result.Ready || len(result.Checks) != len(SpawnChecks) {
+		t.Fatalf("expected only the spawn checks to run and pass, got %+v", result)
+	}
+
+	// A merge in progress in a linked worktree lives in its own git dir
+	out, _ := exec.Command("git", "-C", worktree, "rev-parse", "--absolute-git-dir").Output()
+	os.WriteFile(filepath.Join(strings.TrimSpace(string(out)), "MERGE_HEAD"), []byte("x"), 0644)
+	result = checker.RunChecks(SpawnChecks)
+	if result.Ready || result.Checks[PreflightNoInProgressOps].Passed {
+		t.Errorf("expected merge in progress to be detected, got %+v", result)
+	}
+
+	if result := checker.RunChecks([]string{"bogus"}); !result.Ready || len(result.Checks) != 0 {
+		t.Errorf("expected unknown checks to be ignored, got %+v", result)
+	}
+	if !IsPreflightCheck(PreflightCredentials) || IsPreflightCheck("bogus") {
+		t.Error("IsPreflightCheck mismatch")
+	}
+}
@@ -0,0 +1,113 @@
+package hub
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Presence event types
+const (
+	EventPresenceJoined  = "presence_joined"  // A user's first connection opened
+	EventPresenceLeft    = "presence_left"    // A user's last connection closed
+	EventPresenceChanged = "presence_changed" // A user started or stopped watching a goal
+)
+
+// UserPresence is a user with at least one open event stream
+type UserPresence struct {
+	User        string    `json:"user"`
+	Connections int       `json:"connections"`
+	Since       time.Time `json:"since"`              // When the first open connection opened
+	Watching    string    `json:"watching,omitempty"` // Goal the user has open
+}
+
+// presenceTracker counts the open event streams of each user. Presence only
+// lives in memory: after a restart clients reconnect and are counted again.
+type presenceTracker struct {
+	mu    sync.Mutex
+	users map[string]*UserPresence
+}
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{users: make(map[string]*UserPresence)}
+}
+
+// Connect records an open connection (an SSE stream) of a user and returns
+// the function to call when it closes. A user's first connection publishes
+// presence_joined and the last one closing publishes presence_left.
+func (h *Hub) Connect(user string) func() {
+	h.presence.mu.Lock()
+	p, online := h.presence.users[user]
+	if !online {
+		p = &UserPresence{User: user, Since: time.Now()}
+		h.presence.users[user] = p
+	}
+	p.Connections++
+	snapshot := *p
+	h.presence.mu.Unlock()
+
+	if !online {
+		h.broadcast(Event{Type: EventPresenceJoined, Data: snapshot})
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.presence.mu.Lock()
+			p.Connections--
+			left := p.Connections == 0 && h.presence.users[user] == p
+			if left {
+				delete(h.presence.users, user)
+			}
+			snapshot := *p
+			h.presence.mu.Unlock()
+
+			if left {
+				h.broadcast(Event{Type: EventPresenceLeft, Data: snapshot})
+			}
+		})
+	}
+}
+
+// SetWatching records the goal a user has open ("" for none) and publishes
+// presence_changed. It reports false if the user has no open connection.
+func (h *Hub) SetWatching(user, goalID string) (*UserPresence, bool) {
+	h.presence.mu.Lock()
+	p, online := h.presence.users[user]
+	if !online {
+		h.presence.mu.Unlock()
+		return nil, false
+	}
+	changed := p.Watching != goalID
+	p.Watching = goalID
+	snapshot := *p
+	h.presence.mu.Unlock()
+
+	if changed {
+		h.broadcast(Event{Type: EventPresenceChanged, Data: snapshot})
+	}
+	return &snapshot, true
+}
+
+// Presence returns the users online, by name
+func (h *Hub) Presence() []UserPresence {
+	h.presence.mu.Lock()
+	defer h.presence.mu.Unlock()
+	users := make([]UserPresence, 0, len(h.presence.users))
+	for _, p := range h.presence.users {
+		users = append(users, *p)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].User < users[j].User })
+	return users
+}
+
+// Watchers returns the users online who have a goal open, by name
+func (h *Hub) Watchers(goalID string) []string {
+	var watchers []string
+	for _, p := range h.Presence() {
+		if p.Watching == goalID {
+			watchers = append(watchers, p.User)
+		}
+	}
+	return watchers
+}
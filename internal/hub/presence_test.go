@@ -0,0 +1,47 @@
+package hub
+
+import "testing"
+
+func TestPresence(t *testing.T) {
+	h := New(t.TempDir())
+	ch := h.Subscribe()
+	defer h.Unsubscribe(ch)
+
+	leave := h.Connect("alice")
+	if e := <-ch; e.Type != EventPresenceJoined {
+		t.Fatalf("expected presence_joined, got %s", e.Type)
+	}
+	second := h.Connect("alice")
+	h.Connect("bob")
+	<-ch // bob joined
+
+	if _, online := h.SetWatching("carol", "abc1234"); online {
+		t.Error("a user without a connection shouldn't be able to watch")
+	}
+	h.SetWatching("alice", "abc1234")
+	if e := <-ch; e.Type != EventPresenceChanged {
+		t.Errorf("expected presence_changed, got %s", e.Type)
+	}
+
+	users := h.Presence()
+	if len(users) != 2 || users[0].User != "alice" || users[0].Connections != 2 || users[0].Watching != "abc1234" {
+		t.Fatalf("unexpected presence: %+v", users)
+	}
+	if watchers := h.Watchers("abc1234"); len(watchers) != 1 || watchers[0] != "alice" {
+		t.Errorf("watchers = %v", watchers)
+	}
+
+	// Alice stays online until her last connection closes
+	leave()
+	leave() // Leaving twice is harmless
+	if users := h.Presence(); users[0].Connections != 1 {
+		t.Errorf("expected one connection left, got %+v", users[0])
+	}
+	second()
+	if e := <-ch; e.Type != EventPresenceLeft {
+		t.Errorf("expected presence_left, got %s", e.Type)
+	}
+	if watchers := h.Watchers("abc1234"); len(watchers) != 0 {
+		t.Errorf("expected no watchers after alice left, got %v", watchers)
+	}
+}
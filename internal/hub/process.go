@@ -0,0 +1,256 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// KillGracePeriod is how long a killed executor gets to exit after SIGTERM before SIGKILL
+var KillGracePeriod = 10 * time.Second
+
+var (
+	// ErrExecutorNotFound is returned when no active executor matches the goal/session
+	ErrExecutorNotFound = errors.New("executor not found")
+
+	// ErrNoProcess is returned for executors not spawned by this hub (registered via hooks)
+	ErrNoProcess = errors.New("executor process not managed by vega-hub")
+
+	// ErrExecutorPaused is returned when pausing an executor that is already paused
+	ErrExecutorPaused = errors.New("executor already paused")
+
+	// ErrExecutorNotPaused is returned when resuming an executor that isn't paused
+	ErrExecutorNotPaused = errors.New("executor not paused")
+
+	// ErrExecutorSwitching is returned while another pause or resume of the executor runs
+	ErrExecutorSwitching = errors.New("executor is being paused or resumed")
+)
+
+// attachProcess records the OS process of a spawned executor.
+// done must be closed when the process exits.
+func (h *Hub) attachProcess(sessionID string, process *os.Process, done chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if e, ok := h.executors[sessionID]; ok {
+		e.PID = process.Pid
+		e.process = process
+		e.done = done
+	}
+}
+
+// attachWorker records the remote worker a spawned executor runs on
+func (h *Hub) attachWorker(sessionID, worker string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if e, ok := h.executors[sessionID]; ok {
+		e.Worker = worker
+	}
+}
+
+// attachContainer records the container a spawned executor runs in
+func (h *Hub) attachContainer(sessionID string, c *containerRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if e, ok := h.executors[sessionID]; ok {
+		e.Container = c.Name
+		e.container = c
+	}
+}
+
+// exitReason returns the stop reason for a spawned executor whose process exited
+func (h *Hub) exitReason(sessionID string) string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if e, ok := h.executors[sessionID]; ok && e.killRequested {
+		return "killed"
+	}
+	return "completed"
+}
+
+// managedExecutor returns the executor for a goal/session if vega-hub owns its process
+// (caller holds h.mu)
+func (h *Hub) managedExecutor(goalID, sessionID string) (*Executor, error) {
+	e, ok := h.executors[sessionID]
+	if !ok || e.GoalID != goalID {
+		return nil, fmt.Errorf("%w: %s", ErrExecutorNotFound, sessionID)
+	}
+	if e.process == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoProcess, sessionID)
+	}
+	return e, nil
+}
+
+// signalGroup sends a signal to the executor's process group (the executor and its children)
+func signalGroup(pid int, sig syscall.Signal) error {
+	if err := syscall.Kill(-pid, sig); err != nil {
+		// Fall back to the process itself if it isn't a group leader
+		return syscall.Kill(pid, sig)
+	}
+	return nil
+}
+
+// suspendProcess stops an executor's processes (container pause or SIGSTOP)
+func suspendProcess(pid int, c *containerRun) error {
+	if c != nil {
+		return c.run("pause", c.Name)
+	}
+	return signalGroup(pid, syscall.SIGSTOP)
+}
+
+// continueProcess continues a suspended executor (container unpause or SIGCONT)
+func continueProcess(pid int, c *containerRun) error {
+	if c != nil {
+		return c.run("unpause", c.Name)
+	}
+	return signalGroup(pid, syscall.SIGCONT)
+}
+
+// KillExecutor terminates a spawned executor: SIGTERM, then SIGKILL if it hasn't
+// exited after KillGracePeriod. Returns once SIGTERM is sent.
+func (h *Hub) KillExecutor(goalID, sessionID, user string) error {
+	h.mu.Lock()
+	e, err := h.managedExecutor(goalID, sessionID)
+	if err != nil {
+		h.mu.Unlock()
+		return err
+	}
+	pid, done, wasPaused, container := e.PID, e.done, e.Paused, e.container
+	e.killRequested = true
+	e.Paused = false
+	h.mu.Unlock()
+
+	// A stopped process only acts on SIGTERM once continued
+	if wasPaused {
+		continueProcess(pid, container)
+		h.transitionExecutorState(goalID, goals.StateWorking, "Executor killed while paused", user)
+	}
+
+	if err := signalGroup(pid, syscall.SIGTERM); err != nil {
+		return fmt.Errorf("failed to terminate executor: %w", err)
+	}
+	log.Printf("[EXECUTOR] Sent SIGTERM to executor %s (goal %s, pid %d, user %s)", sessionID, goalID, pid, user)
+
+	grace := KillGracePeriod
+	go func() {
+		select {
+		case <-done:
+		case <-time.After(grace):
+			log.Printf("[EXECUTOR] Executor %s did not exit after %v, sending SIGKILL", sessionID, grace)
+			signalGroup(pid, syscall.SIGKILL)
+		}
+	}()
+
+	h.recordExecutorControl(goalID, sessionID, "executor_killed", user)
+	return nil
+}
+
+// PauseExecutor suspends a spawned executor with SIGSTOP
+func (h *Hub) PauseExecutor(goalID, sessionID, user string) error {
+	if err := h.setPaused(goalID, sessionID, true); err != nil {
+		return err
+	}
+	h.transitionExecutorState(goalID, goals.StatePaused, "Executor paused", user)
+	h.recordExecutorControl(goalID, sessionID, "executor_paused", user)
+	return nil
+}
+
+// ResumeExecutor continues a paused executor with SIGCONT
+func (h *Hub) ResumeExecutor(goalID, sessionID, user string) error {
+	if err := h.setPaused(goalID, sessionID, false); err != nil {
+		return err
+	}
+	h.transitionExecutorState(goalID, goals.StateWorking, "Executor resumed", user)
+	h.recordExecutorControl(goalID, sessionID, "executor_resumed", user)
+	return nil
+}
+
+// setPaused suspends or continues a spawned executor. The signal or
+// container command runs without h.mu held (docker can take a while); the
+// executor is marked as switching meanwhile so a second pause or resume is
+// refused.
+func (h *Hub) setPaused(goalID, sessionID string, pause bool) error {
+	h.mu.Lock()
+	e, err := h.managedExecutor(goalID, sessionID)
+	if err != nil {
+		h.mu.Unlock()
+		return err
+	}
+	switch {
+	case e.Worker != "":
+		// SIGSTOP would only stop the local ssh client
+		h.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRemoteExecutor, e.Worker)
+	case e.switching:
+		h.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrExecutorSwitching, sessionID)
+	case pause && e.Paused:
+		h.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrExecutorPaused, sessionID)
+	case !pause && !e.Paused:
+		h.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrExecutorNotPaused, sessionID)
+	}
+	e.switching = true
+	pid, container := e.PID, e.container
+	h.mu.Unlock()
+
+	action, run := "resume", continueProcess
+	if pause {
+		action, run = "pause", suspendProcess
+	}
+	err = run(pid, container)
+
+	h.mu.Lock()
+	e.switching = false
+	if err != nil {
+		h.mu.Unlock()
+		return fmt.Errorf("failed to %s executor: %w", action, err)
+	}
+	if e.killRequested {
+		// Killed while the command ran: leave it running so it acts on SIGTERM
+		h.mu.Unlock()
+		if pause {
+			continueProcess(pid, container)
+		}
+		return fmt.Errorf("%w: %s", ErrExecutorNotFound, sessionID)
+	}
+	e.Paused = pause
+	e.PausedAt = nil
+	if pause {
+		now := time.Now()
+		e.PausedAt = &now
+	}
+	h.mu.Unlock()
+	return nil
+}
+
+// transitionExecutorState updates the goal state (best effort - goals without
+// state tracking or in other states are left alone)
+func (h *Hub) transitionExecutorState(goalID string, state goals.GoalState, reason, user string) {
+	if err := h.stateManager.TransitionWithUser(goalID, state, reason, user, nil); err != nil {
+		log.Printf("[EXECUTOR] Goal %s state not changed to %s: %v", goalID, state, err)
+	}
+}
+
+// recordExecutorControl records a user action on an executor in history and broadcasts it
+func (h *Hub) recordExecutorControl(goalID, sessionID, eventType, user string) {
+	if err := h.history.RecordActivity(goalID, sessionID, eventType, map[string]interface{}{
+		"user": user,
+	}); err != nil {
+		// Log error but don't fail
+	}
+
+	h.broadcast(Event{
+		Type: eventType,
+		Data: map[string]interface{}{
+			"goal_id":    goalID,
+			"session_id": sessionID,
+			"user":       user,
+		},
+	})
+}
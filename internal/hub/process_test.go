@@ -0,0 +1,196 @@
+package hub
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// startManagedProcess registers an executor backed by a real sleep process,
+// mirroring what SpawnExecutor does for claude
+func startManagedProcess(t *testing.T, h *Hub, goalID, sessionID string) chan struct{} {
+	t.Helper()
+
+	cmd := exec.Command("sleep", "30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+
+	h.RegisterExecutor(goalID, sessionID, t.TempDir(), "user")
+	done := make(chan struct{})
+	h.attachProcess(sessionID, cmd.Process, done)
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+		h.StopExecutor(goalID, sessionID, h.exitReason(sessionID))
+		close(exited)
+	}()
+	// Wait for the exit goroutine so it doesn't write history during TempDir cleanup
+	t.Cleanup(func() {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-exited
+	})
+	return exited
+}
+
+func TestKillExecutor(t *testing.T) {
+	h := setupTestHub(t)
+	exited := startManagedProcess(t, h, "abc1234", "session-001")
+
+	if err := h.KillExecutor("abc1234", "wrong-session", "alice"); !errors.Is(err, ErrExecutorNotFound) {
+		t.Errorf("expected ErrExecutorNotFound, got %v", err)
+	}
+
+	if err := h.KillExecutor("abc1234", "session-001", "alice"); err != nil {
+		t.Fatalf("KillExecutor failed: %v", err)
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("executor did not exit after kill")
+	}
+
+	sessions, _ := h.GetGoalSessions("abc1234")
+	if len(sessions) != 1 || sessions[0].StopReason != "killed" {
+		t.Errorf("expected session stopped with reason 'killed', got %+v", sessions)
+	}
+}
+
+func TestKillExecutor_EscalatesToSIGKILL(t *testing.T) {
+	h := setupTestHub(t)
+
+	// A process that ignores SIGTERM
+	cmd := exec.Command("sh", "-c", "trap '' TERM; sleep 30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	t.Cleanup(func() { syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) })
+
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+	done := make(chan struct{})
+	h.attachProcess("session-001", cmd.Process, done)
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	orig := KillGracePeriod
+	KillGracePeriod = 100 * time.Millisecond
+	defer func() { KillGracePeriod = orig }()
+
+	time.Sleep(50 * time.Millisecond) // Let the trap install
+	if err := h.KillExecutor("abc1234", "session-001", "alice"); err != nil {
+		t.Fatalf("KillExecutor failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("executor ignoring SIGTERM was not killed")
+	}
+}
+
+func TestPauseResumeExecutor(t *testing.T) {
+	h := setupTestHub(t)
+	goalDir := filepath.Join(h.dir, "goals", "active", "abc1234")
+	os.MkdirAll(goalDir, 0755)
+	os.WriteFile(filepath.Join(goalDir, "abc1234.md"), []byte("# Goal #abc1234: Test\n"), 0644)
+
+	sm := h.StateManager()
+	if err := sm.Transition("abc1234", goals.StateWorking, "test", nil); err != nil {
+		t.Fatal(err)
+	}
+	startManagedProcess(t, h, "abc1234", "session-001")
+
+	if err := h.ResumeExecutor("abc1234", "session-001", "alice"); !errors.Is(err, ErrExecutorNotPaused) {
+		t.Errorf("expected ErrExecutorNotPaused, got %v", err)
+	}
+
+	if err := h.PauseExecutor("abc1234", "session-001", "alice"); err != nil {
+		t.Fatalf("PauseExecutor failed: %v", err)
+	}
+	if err := h.PauseExecutor("abc1234", "session-001", "alice"); !errors.Is(err, ErrExecutorPaused) {
+		t.Errorf("expected ErrExecutorPaused, got %v", err)
+	}
+	if state, _ := sm.GetState("abc1234"); state != goals.StatePaused {
+		t.Errorf("expected state paused, got %s", state)
+	}
+
+	if err := h.ResumeExecutor("abc1234", "session-001", "alice"); err != nil {
+		t.Fatalf("ResumeExecutor failed: %v", err)
+	}
+	if state, _ := sm.GetState("abc1234"); state != goals.StateWorking {
+		t.Errorf("expected state working after resume, got %s", state)
+	}
+
+	entries, _ := h.GetGoalHistory("abc1234", 0)
+	var controls []string
+	for _, e := range entries {
+		if e.Type == "executor_paused" || e.Type == "executor_resumed" {
+			controls = append(controls, e.Type)
+		}
+	}
+	if len(controls) != 2 {
+		t.Errorf("expected pause and resume in history, got %v", controls)
+	}
+}
+
+func TestExecutorControl_HookRegistered(t *testing.T) {
+	h := setupTestHub(t)
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+
+	if err := h.PauseExecutor("abc1234", "session-001", "alice"); !errors.Is(err, ErrNoProcess) {
+		t.Errorf("expected ErrNoProcess for hook-registered executor, got %v", err)
+	}
+}
+
+func TestPauseExecutor_SlowContainerRunsUnlocked(t *testing.T) {
+	h := setupTestHub(t)
+	startManagedProcess(t, h, "abc1234", "session-001")
+
+	// Fake runtime whose "pause" blocks until the release file exists
+	bin := t.TempDir()
+	started, release := filepath.Join(bin, "started"), filepath.Join(bin, "release")
+	script := "#!/bin/sh\ntouch " + started + "\nwhile [ ! -e " + release + " ]; do sleep 0.01; done\n"
+	os.WriteFile(filepath.Join(bin, "docker"), []byte(script), 0755)
+	h.attachContainer("session-001", &containerRun{Runtime: filepath.Join(bin, "docker"), Name: "vega-test"})
+
+	paused := make(chan error, 1)
+	go func() { paused <- h.PauseExecutor("abc1234", "session-001", "alice") }()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			os.WriteFile(release, nil, 0644)
+			t.Fatal("docker pause never ran")
+		}
+	}
+
+	// The hub stays usable while docker runs, and a second pause is refused
+	if err := h.PauseExecutor("abc1234", "session-001", "alice"); !errors.Is(err, ErrExecutorSwitching) {
+		t.Errorf("expected ErrExecutorSwitching, got %v", err)
+	}
+	if executors := h.GetActiveExecutors(); len(executors) != 1 {
+		t.Errorf("executors while pausing = %+v", executors)
+	}
+
+	os.WriteFile(release, nil, 0644)
+	if err := <-paused; err != nil {
+		t.Fatalf("PauseExecutor failed: %v", err)
+	}
+	if executors := h.GetActiveExecutors(); len(executors) != 1 || !executors[0].Paused {
+		t.Errorf("executor not paused: %+v", executors)
+	}
+}
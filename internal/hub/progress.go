@@ -0,0 +1,67 @@
+package hub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxProgressLength caps an executor's status report, in characters
+const MaxProgressLength = 120
+
+// ExecutorProgress is what an executor last reported it is doing
+type ExecutorProgress struct {
+	Status string    `json:"status"` // Short and human-readable, e.g. "running tests"
+	At     time.Time `json:"at"`
+}
+
+// ReportProgress records the status an executor reports ("running tests",
+// "editing auth.go") and publishes executor_progress. An empty status clears
+// it. Progress is only kept on the running executor, and is too frequent for
+// goal_updated events: clients patch the goal's executor_progress themselves.
+func (h *Hub) ReportProgress(goalID, sessionID, status string) (*ExecutorProgress, error) {
+	status = strings.Join(strings.Fields(status), " ")
+	if utf8.RuneCountInString(status) > MaxProgressLength {
+		status = string([]rune(status)[:MaxProgressLength-1]) + "…"
+	}
+
+	h.mu.Lock()
+	e, ok := h.executors[sessionID]
+	if !ok || e.GoalID != goalID {
+		h.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrExecutorNotFound, sessionID)
+	}
+	var progress *ExecutorProgress
+	if status != "" {
+		progress = &ExecutorProgress{Status: status, At: time.Now()}
+	}
+	e.Progress = progress
+	h.mu.Unlock()
+
+	data := map[string]interface{}{
+		"goal_id":    goalID,
+		"session_id": sessionID,
+		"progress":   progress,
+	}
+	h.broadcast(Event{Type: EventExecutorProgress, Data: data})
+	return progress, nil
+}
+
+// GoalProgress returns the latest progress reported by each goal's running
+// executors, by goal ID
+func (h *Hub) GoalProgress() map[string]ExecutorProgress {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	latest := make(map[string]ExecutorProgress)
+	for _, e := range h.executors {
+		if e.Progress == nil {
+			continue
+		}
+		if p, ok := latest[e.GoalID]; !ok || e.Progress.At.After(p.At) {
+			latest[e.GoalID] = *e.Progress
+		}
+	}
+	return latest
+}
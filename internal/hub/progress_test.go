@@ -0,0 +1,55 @@
+package hub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestReportProgress(t *testing.T) {
+	h := setupTestHub(t)
+	h.RegisterExecutor("abc1234", "session-001", "/tmp", "testuser")
+
+	events := h.Subscribe()
+	defer h.Unsubscribe(events)
+
+	p, err := h.ReportProgress("abc1234", "session-001", "  running\n tests ")
+	if err != nil {
+		t.Fatalf("ReportProgress failed: %v", err)
+	}
+	if p.Status != "running tests" {
+		t.Errorf("expected normalized status, got %q", p.Status)
+	}
+	if ev := <-events; ev.Type != EventExecutorProgress {
+		t.Errorf("expected executor_progress event, got %s", ev.Type)
+	}
+	if got := h.GoalProgress()["abc1234"]; got.Status != "running tests" {
+		t.Errorf("goal progress not recorded: %+v", got)
+	}
+	if e := h.GetActiveExecutors()[0]; e.Progress == nil || e.Progress.Status != "running tests" {
+		t.Errorf("executor progress not recorded: %+v", e.Progress)
+	}
+
+	p, err = h.ReportProgress("abc1234", "session-001", strings.Repeat("x", 500))
+	if err != nil {
+		t.Fatalf("ReportProgress failed: %v", err)
+	}
+	if n := utf8.RuneCountInString(p.Status); n != MaxProgressLength || !strings.HasSuffix(p.Status, "…") {
+		t.Errorf("expected status truncated to %d characters, got %d", MaxProgressLength, n)
+	}
+
+	if p, err := h.ReportProgress("abc1234", "session-001", ""); err != nil || p != nil {
+		t.Fatalf("clearing progress: %+v %v", p, err)
+	}
+	if _, ok := h.GoalProgress()["abc1234"]; ok {
+		t.Error("expected cleared progress to be dropped")
+	}
+
+	if _, err := h.ReportProgress("abc1234", "unknown", "x"); !errors.Is(err, ErrExecutorNotFound) {
+		t.Errorf("unknown session: expected ErrExecutorNotFound, got %v", err)
+	}
+	if _, err := h.ReportProgress("def5678", "session-001", "x"); !errors.Is(err, ErrExecutorNotFound) {
+		t.Errorf("session of another goal: expected ErrExecutorNotFound, got %v", err)
+	}
+}
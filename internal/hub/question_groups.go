@@ -0,0 +1,151 @@
+package hub
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// QuestionGroup is a burst of related questions an executor asked under one
+// group name, or a single ungrouped question (Group empty)
+type QuestionGroup struct {
+	GoalID    string      `json:"goal_id"`
+	Group     string      `json:"group,omitempty"`
+	Priority  string      `json:"priority,omitempty"` // Highest priority of its questions
+	Questions []*Question `json:"questions"`
+}
+
+// priorityRank orders priorities, most urgent first; no priority counts as normal
+func priorityRank(priority string) int {
+	switch priority {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// groupKey identifies the group of a question: group names are per goal
+func (q *Question) groupKey() string {
+	if q.Group == "" {
+		return "\x00" + q.ID
+	}
+	return q.GoalID + "\x00" + q.Group
+}
+
+// GroupQuestions groups questions for triage: by the highest priority in the
+// group, then by when the group's first question was asked. Questions within
+// a group are in the order they were asked.
+func GroupQuestions(questions []*Question) []QuestionGroup {
+	var groups []QuestionGroup
+	index := make(map[string]int)
+	first := make(map[string]time.Time)
+	for _, q := range questions {
+		key := q.groupKey()
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, QuestionGroup{GoalID: q.GoalID, Group: q.Group, Priority: q.Priority})
+			first[key] = q.CreatedAt
+		}
+		g := &groups[i]
+		g.Questions = append(g.Questions, q)
+		if priorityRank(q.Priority) < priorityRank(g.Priority) {
+			g.Priority = q.Priority
+		}
+		if q.CreatedAt.Before(first[key]) {
+			first[key] = q.CreatedAt
+		}
+	}
+
+	for i := range groups {
+		qs := groups[i].Questions
+		sort.SliceStable(qs, func(a, b int) bool { return qs[a].CreatedAt.Before(qs[b].CreatedAt) })
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		ra, rb := priorityRank(groups[a].Priority), priorityRank(groups[b].Priority)
+		if ra != rb {
+			return ra < rb
+		}
+		return first[groups[a].Questions[0].groupKey()].Before(first[groups[b].Questions[0].groupKey()])
+	})
+	return groups
+}
+
+// SortQuestions orders questions as GroupQuestions does, flattened: the
+// questions of a group stay together
+func SortQuestions(questions []*Question) []*Question {
+	sorted := make([]*Question, 0, len(questions))
+	for _, g := range GroupQuestions(questions) {
+		sorted = append(sorted, g.Questions...)
+	}
+	return sorted
+}
+
+// GroupAnswerError is returned by AnswerGroupAs when answering one of the
+// group's questions failed; the questions before it were answered
+type GroupAnswerError struct {
+	QuestionID string
+	Answered   []string
+	Err        error
+}
+
+func (e *GroupAnswerError) Error() string {
+	return fmt.Sprintf("answering question %s: %v", e.QuestionID, e.Err)
+}
+
+func (e *GroupAnswerError) Unwrap() error {
+	return e.Err
+}
+
+// AnswerGroupAs answers every pending question of a goal's group in one go:
+// each question gets its entry in answers, or fallback when it has none. All
+// answers are checked before any is given, so an invalid or missing answer
+// answers nothing. Returns the IDs of the questions answered, in order.
+func (h *Hub) AnswerGroupAs(goalID, group, user string, answers map[string]*StructuredAnswer, fallback *StructuredAnswer) ([]string, error) {
+	var questions []*Question
+	for _, q := range h.GetPendingQuestions() {
+		if q.GoalID == goalID && q.Group == group && group != "" {
+			questions = append(questions, q)
+		}
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("%w: no pending questions in group %q of goal %s", ErrQuestionNotFound, group, goalID)
+	}
+	byID := make(map[string]bool, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = true
+	}
+	for id := range answers {
+		if !byID[id] {
+			return nil, fmt.Errorf("%w: question %s is not pending in group %q", ErrInvalidAnswer, id, group)
+		}
+	}
+
+	given := make([]*StructuredAnswer, len(questions))
+	for i, q := range questions {
+		answer, ok := answers[q.ID]
+		if !ok {
+			answer = fallback
+		}
+		if answer == nil {
+			return nil, fmt.Errorf("%w: no answer for question %s", ErrInvalidAnswer, q.ID)
+		}
+		if err := q.ValidateAnswer(answer); err != nil {
+			return nil, fmt.Errorf("question %s: %w", q.ID, err)
+		}
+		given[i] = answer
+	}
+
+	var answered []string
+	for i, q := range questions {
+		if err := h.AnswerStructuredAs(q.ID, user, given[i]); err != nil {
+			return answered, &GroupAnswerError{QuestionID: q.ID, Answered: answered, Err: err}
+		}
+		answered = append(answered, q.ID)
+	}
+	return answered, nil
+}
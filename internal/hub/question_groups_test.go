@@ -0,0 +1,85 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroupQuestions(t *testing.T) {
+	now := time.Now()
+	questions := []*Question{
+		{ID: "q1", GoalID: "abc1234", Question: "Plain", CreatedAt: now},
+		{ID: "q2", GoalID: "abc1234", Group: "db", Question: "Schema?", CreatedAt: now.Add(1 * time.Second)},
+		{ID: "q3", GoalID: "abc1234", Group: "db", Question: "Index?", Priority: PriorityHigh, CreatedAt: now.Add(3 * time.Second)},
+		{ID: "q4", GoalID: "def5678", Group: "db", Question: "Other goal", CreatedAt: now.Add(2 * time.Second)},
+		{ID: "q5", GoalID: "abc1234", Question: "Later", Priority: PriorityLow, CreatedAt: now.Add(-time.Second)},
+	}
+
+	groups := GroupQuestions(questions)
+	var got [][]string
+	for _, g := range groups {
+		var ids []string
+		for _, q := range g.Questions {
+			ids = append(ids, q.ID)
+		}
+		got = append(got, ids)
+	}
+	// The high priority question lifts its whole group; group names are per goal
+	want := [][]string{{"q2", "q3"}, {"q1"}, {"q4"}, {"q5"}}
+	if len(got) != len(want) {
+		t.Fatalf("groups = %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) || got[i][0] != want[i][0] {
+			t.Errorf("groups = %v, want %v", got, want)
+			break
+		}
+	}
+	if groups[0].Priority != PriorityHigh || groups[0].Group != "db" || groups[0].GoalID != "abc1234" {
+		t.Errorf("first group = %+v", groups[0])
+	}
+
+	sorted := SortQuestions(questions)
+	if sorted[0].ID != "q2" || sorted[1].ID != "q3" || sorted[4].ID != "q5" {
+		t.Errorf("SortQuestions order: %s %s ... %s", sorted[0].ID, sorted[1].ID, sorted[4].ID)
+	}
+}
+
+func TestAnswerGroupAs(t *testing.T) {
+	h := setupTestHub(t)
+
+	answers := make(chan string, 3)
+	for _, q := range []*Question{
+		{ID: "q1", GoalID: "abc1234", SessionID: "s1", Group: "db", Question: "Schema?", Options: []Option{{ID: "a", Label: "A"}, {ID: "b", Label: "B"}}},
+		{ID: "q2", GoalID: "abc1234", SessionID: "s1", Group: "db", Question: "Index?"},
+		{ID: "q3", GoalID: "abc1234", SessionID: "s1", Question: "Unrelated?"},
+	} {
+		go func(q *Question) { answers <- h.Ask(q) }(q)
+	}
+	waitForPending(t, h, 3)
+
+	// Nothing is answered when one of the answers doesn't fit
+	_, err := h.AnswerGroupAs("abc1234", "db", "alice", map[string]*StructuredAnswer{"q1": {OptionIDs: []string{"z"}}}, &StructuredAnswer{Text: "Yes"})
+	if !errors.Is(err, ErrInvalidAnswer) || len(h.GetPendingQuestions()) != 3 {
+		t.Fatalf("expected an invalid answer and nothing answered, got %v", err)
+	}
+	if _, err := h.AnswerGroupAs("abc1234", "db", "alice", nil, nil); !errors.Is(err, ErrInvalidAnswer) {
+		t.Errorf("expected an error for missing answers, got %v", err)
+	}
+	if _, err := h.AnswerGroupAs("def5678", "db", "alice", nil, &StructuredAnswer{Text: "Yes"}); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("expected no group for another goal, got %v", err)
+	}
+
+	answered, err := h.AnswerGroupAs("abc1234", "db", "alice", map[string]*StructuredAnswer{"q1": {OptionIDs: []string{"b"}}}, &StructuredAnswer{Text: "Yes"})
+	if err != nil || len(answered) != 2 {
+		t.Fatalf("AnswerGroupAs = %v, %v", answered, err)
+	}
+	got := map[string]bool{<-answers: true, <-answers: true}
+	if !got["B"] || !got["Yes"] {
+		t.Errorf("answers delivered: %v", got)
+	}
+	if pending := h.GetPendingQuestions(); len(pending) != 1 || pending[0].ID != "q3" {
+		t.Errorf("ungrouped question should still be pending: %+v", pending)
+	}
+}
@@ -0,0 +1,86 @@
+package hub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// Dependencies checked by Readiness
+const (
+	DependencyVegaDir = "vega_dir"
+	DependencyGit     = "git"
+	DependencyStore   = "store"
+	DependencyWatcher = "watcher"
+)
+
+// Dependency statuses
+const (
+	DependencyOK   = "ok"
+	DependencyDown = "down"
+)
+
+// DependencyStatus is the state of one thing the hub needs to serve requests
+type DependencyStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // "ok" or "down"
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessReport tells whether the hub can serve requests, and if not, what
+// it is missing
+type ReadinessReport struct {
+	Ready        bool               `json:"ready"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	CheckedAt    time.Time          `json:"checked_at"`
+}
+
+// StartedAt returns when the hub was created
+func (h *Hub) StartedAt() time.Time {
+	return h.startedAt
+}
+
+// Readiness checks what the hub depends on: the vega directory (readable,
+// with its goals directory), git on the PATH, the goal registry and the file
+// watcher. The hub is ready only when all of them are ok.
+func (h *Hub) Readiness() *ReadinessReport {
+	report := &ReadinessReport{Ready: true, CheckedAt: time.Now()}
+	check := func(name string, err error) {
+		status := DependencyStatus{Name: name, Status: DependencyOK}
+		if err != nil {
+			status.Status = DependencyDown
+			status.Error = err.Error()
+			report.Ready = false
+		}
+		report.Dependencies = append(report.Dependencies, status)
+	}
+
+	check(DependencyVegaDir, h.checkVegaDir())
+	_, err := exec.LookPath("git")
+	check(DependencyGit, err)
+	_, err = goals.NewRegistry(h.dir).Load()
+	check(DependencyStore, err)
+	err = nil
+	if !h.WatcherStatus().Running {
+		err = fmt.Errorf("file watcher is not running")
+	}
+	check(DependencyWatcher, err)
+	return report
+}
+
+// checkVegaDir makes sure the vega directory and its goals directory can be read
+func (h *Hub) checkVegaDir() error {
+	if h.dir == "" {
+		return fmt.Errorf("no vega directory configured")
+	}
+	if _, err := os.ReadDir(h.dir); err != nil {
+		return err
+	}
+	if _, err := os.ReadDir(h.layout.GoalsDir()); err != nil {
+		return err
+	}
+	return nil
+}
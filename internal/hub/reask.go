@@ -0,0 +1,125 @@
+package hub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reaskWindow is how long an answer is handed back to executors re-asking
+// the question it answered (e.g. after their request timed out)
+const reaskWindow = 15 * time.Minute
+
+// recentAnswer is an answer kept for re-asks of its question
+type recentAnswer struct {
+	Answer *StructuredAnswer
+	At     time.Time
+}
+
+// fingerprint identifies a question across re-asks: the same goal, session,
+// question text and options give the same fingerprint
+func (q *Question) fingerprint() string {
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%s\x00%s\x00%s\x00%t", q.GoalID, q.SessionID, strings.TrimSpace(q.Question), q.MultiSelect)
+	for _, o := range q.Options {
+		fmt.Fprintf(sum, "\x00%s\x00%s", o.Label, o.Description)
+	}
+	return hex.EncodeToString(sum.Sum(nil))[:16]
+}
+
+// pendingByFingerprint returns the pending question with a fingerprint (caller holds h.mu)
+func (h *Hub) pendingByFingerprint(fingerprint string) *Question {
+	for _, q := range h.questions {
+		if q.Fingerprint == fingerprint {
+			return q
+		}
+	}
+	return nil
+}
+
+// rememberAnswer keeps an answer for re-asks and prunes expired ones (caller holds h.mu)
+func (h *Hub) rememberAnswer(fingerprint string, answer *StructuredAnswer, now time.Time) {
+	for fp, recent := range h.recentAnswers {
+		if now.Sub(recent.At) > reaskWindow {
+			delete(h.recentAnswers, fp)
+		}
+	}
+	if fingerprint != "" {
+		h.recentAnswers[fingerprint] = recentAnswer{Answer: answer, At: now}
+	}
+}
+
+// recentAnswerFor returns the answer to a recently answered question with a
+// fingerprint (caller holds h.mu)
+func (h *Hub) recentAnswerFor(fingerprint string, now time.Time) *StructuredAnswer {
+	recent, ok := h.recentAnswers[fingerprint]
+	if !ok || now.Sub(recent.At) > reaskWindow {
+		return nil
+	}
+	return recent.Answer
+}
+
+// PendingAnswerError is returned when the wait for an answer ends (the
+// context's deadline passed or the asker went away) before the question is
+// answered. The question stays pending: WaitForAnswer with QuestionID picks
+// the wait up again.
+type PendingAnswerError struct {
+	QuestionID string
+	Err        error // The context's error
+}
+
+func (e *PendingAnswerError) Error() string {
+	return fmt.Sprintf("question %s not answered yet: %v", e.QuestionID, e.Err)
+}
+
+func (e *PendingAnswerError) Unwrap() error {
+	return e.Err
+}
+
+// WaitForAnswer waits for the answer to a question of a goal asked before,
+// typically by an ask that gave up with a PendingAnswerError. A question
+// answered since returns its answer right away.
+func (h *Hub) WaitForAnswer(ctx context.Context, goalID, id string) (string, *StructuredAnswer, error) {
+	h.mu.Lock()
+	if q, ok := h.questions[id]; ok && q.GoalID == goalID {
+		ch := h.addWaiter(q)
+		h.mu.Unlock()
+		return h.awaitAnswer(ctx, q, ch)
+	}
+	rec, ok := h.answers[id]
+	h.mu.Unlock()
+	if !ok || rec.GoalID != goalID || rec.Status != AnswerDelivered {
+		return "", nil, fmt.Errorf("%w: %s", ErrQuestionNotFound, id)
+	}
+	return rec.Answer, rec.answer, nil
+}
+
+// addWaiter registers a channel for a pending question's answer (caller holds h.mu)
+func (h *Hub) addWaiter(q *Question) chan *StructuredAnswer {
+	ch := make(chan *StructuredAnswer, 1)
+	q.waiters = append(q.waiters, ch)
+	return ch
+}
+
+// awaitAnswer blocks until ch gets the question's answer or ctx ends, in
+// which case the waiter is dropped so nothing is kept for it
+func (h *Hub) awaitAnswer(ctx context.Context, q *Question, ch chan *StructuredAnswer) (string, *StructuredAnswer, error) {
+	select {
+	case answer := <-ch:
+		return answer.Render(q), answer, nil
+	case <-ctx.Done():
+	}
+
+	h.mu.Lock()
+	for i, waiter := range q.waiters {
+		if waiter == ch {
+			q.waiters = append(q.waiters[:i:i], q.waiters[i+1:]...)
+			break
+		}
+	}
+	h.mu.Unlock()
+	return "", nil, &PendingAnswerError{QuestionID: q.ID, Err: ctx.Err()}
+}
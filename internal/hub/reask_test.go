@@ -0,0 +1,150 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForPending polls until n questions are pending
+func waitForPending(t *testing.T, h *Hub, n int) []*Question {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if pending := h.GetPendingQuestions(); len(pending) == n {
+			return pending
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d pending questions, got %d", n, len(h.GetPendingQuestions()))
+	return nil
+}
+
+func TestAskReaskAttachesToPendingQuestion(t *testing.T) {
+	h := setupTestHub(t)
+
+	var wg sync.WaitGroup
+	answers := make([]string, 3)
+	for i := range answers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			answers[i] = h.Ask(&Question{ID: fmt.Sprintf("q-%d", i), GoalID: "abc1234", SessionID: "s1", Question: "Proceed?"})
+		}(i)
+	}
+
+	// Let every ask register before answering
+	pending := waitForPending(t, h, 1)
+	asks := func() int {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		return pending[0].Asks
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for asks() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := asks(); n != 3 {
+		t.Fatalf("expected 3 asks on one question, got %d", n)
+	}
+
+	if err := h.AnswerStructured(pending[0].ID, &StructuredAnswer{Text: "Yes"}); err != nil {
+		t.Fatalf("answer failed: %v", err)
+	}
+	wg.Wait()
+	for i, a := range answers {
+		if a != "Yes" {
+			t.Errorf("ask %d got %q, want Yes", i, a)
+		}
+	}
+
+	// A re-ask after the answer gets it immediately
+	done := make(chan string, 1)
+	go func() {
+		done <- h.Ask(&Question{ID: "q-late", GoalID: "abc1234", SessionID: "s1", Question: "Proceed? "})
+	}()
+	select {
+	case a := <-done:
+		if a != "Yes" {
+			t.Errorf("late re-ask got %q, want Yes", a)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("late re-ask blocked instead of returning the prior answer")
+	}
+}
+
+func TestAskDifferentSessionIsNotDeduplicated(t *testing.T) {
+	h := setupTestHub(t)
+
+	for _, session := range []string{"s1", "s2"} {
+		go h.Ask(&Question{ID: "q-" + session, GoalID: "abc1234", SessionID: session, Question: "Proceed?"})
+	}
+	pending := waitForPending(t, h, 2)
+	if pending[0].Fingerprint == pending[1].Fingerprint {
+		t.Error("questions from different sessions should have different fingerprints")
+	}
+
+	for _, q := range pending {
+		h.Answer(q.ID, "ok")
+	}
+}
+
+func TestAskContextLeavesQuestionPending(t *testing.T) {
+	h := setupTestHub(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err := h.AskContext(ctx, &Question{ID: "q-1", GoalID: "abc1234", SessionID: "s1", Question: "Proceed?"})
+	var pending *PendingAnswerError
+	if !errors.As(err, &pending) || pending.QuestionID != "q-1" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a pending answer error for q-1, got %v", err)
+	}
+	waitForPending(t, h, 1)
+
+	// A waiter that gives up is dropped from the question
+	canceled, cancelWait := context.WithCancel(context.Background())
+	cancelWait()
+	if _, _, err := h.WaitForAnswer(canceled, "abc1234", "q-1"); !errors.As(err, &pending) {
+		t.Fatalf("expected a pending answer error, got %v", err)
+	}
+	h.mu.RLock()
+	waiters := len(h.questions["q-1"].waiters)
+	h.mu.RUnlock()
+	if waiters != 0 {
+		t.Errorf("abandoned waiter kept: %d", waiters)
+	}
+
+	if _, _, err := h.WaitForAnswer(context.Background(), "def5678", "q-1"); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("another goal's question: %v", err)
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		answer, _, _ := h.WaitForAnswer(context.Background(), "abc1234", "q-1")
+		done <- answer
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		h.mu.RLock()
+		n := len(h.questions["q-1"].waiters)
+		h.mu.RUnlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := h.AnswerStructured("q-1", &StructuredAnswer{Text: "Yes"}); err != nil {
+		t.Fatalf("answer failed: %v", err)
+	}
+	if answer := <-done; answer != "Yes" {
+		t.Errorf("waiter got %q, want Yes", answer)
+	}
+
+	// Once delivered, the answer is returned right away
+	if answer, _, err := h.WaitForAnswer(context.Background(), "abc1234", "q-1"); err != nil || answer != "Yes" {
+		t.Errorf("WaitForAnswer after delivery = %q, %v", answer, err)
+	}
+}
@@ -0,0 +1,104 @@
+package hub
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ResumeLast resumes the goal's most recent session that can be resumed
+const ResumeLast = "last"
+
+// resumableSession finds the stopped session of a goal that a new executor in
+// workDir continues with claude --resume. ref is a vega-hub or Claude session
+// ID, or ResumeLast. Claude keeps sessions per working directory, so only
+// sessions that ran in workDir can be resumed.
+func (h *Hub) resumableSession(goalID, ref, workDir string) (*ExecutorSession, error) {
+	sessions, err := h.history.GetGoalSessions(goalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session history: %w", err)
+	}
+	if ref != ResumeLast && findSession(sessions, ref) == nil {
+		// The cache only holds sessions since the hub started
+		if sessions, err = h.history.loadGoalHistory(goalID); err != nil {
+			return nil, fmt.Errorf("failed to read session history: %w", err)
+		}
+	}
+
+	if ref == ResumeLast {
+		for i := len(sessions) - 1; i >= 0; i-- {
+			s := sessions[i]
+			if s.StoppedAt != nil && s.ClaudeSessionID != "" && s.CWD == workDir {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("goal %s has no stopped session with a Claude session ID in %s", goalID, workDir)
+	}
+
+	s := findSession(sessions, ref)
+	switch {
+	case s == nil:
+		return nil, fmt.Errorf("session %s not found for goal %s", ref, goalID)
+	case s.StoppedAt == nil:
+		return nil, fmt.Errorf("session %s is still running", s.SessionID)
+	case s.ClaudeSessionID == "":
+		return nil, fmt.Errorf("session %s has no Claude session ID recorded", s.SessionID)
+	case s.CWD != workDir:
+		return nil, fmt.Errorf("session %s ran in %s, not %s", s.SessionID, s.CWD, workDir)
+	}
+	return s, nil
+}
+
+// findSession returns the session with a vega-hub or Claude session ID of ref
+func findSession(sessions []*ExecutorSession, ref string) *ExecutorSession {
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if sessions[i].SessionID == ref || sessions[i].ClaudeSessionID == ref {
+			return sessions[i]
+		}
+	}
+	return nil
+}
+
+// linkResumedSession records that a new session continues prior's Claude
+// session. The executor starts with prior's Claude session, so its stop keeps
+// the link even if hooks never report one.
+func (h *Hub) linkResumedSession(goalID, sessionID string, prior *ExecutorSession) {
+	h.mu.Lock()
+	if executor, ok := h.executors[sessionID]; ok {
+		executor.ResumedFrom = prior.SessionID
+		executor.ClaudeSessionID = prior.ClaudeSessionID
+		executor.TranscriptPath = prior.TranscriptPath
+	}
+	h.mu.Unlock()
+
+	if err := h.history.RecordSessionResume(goalID, sessionID, prior); err != nil {
+		log.Printf("[SPAWN] Failed to record resumed session %s: %v", sessionID, err)
+	}
+}
+
+// RecordSessionResume records that a session continues an earlier one's
+// Claude session
+func (h *SessionHistory) RecordSessionResume(goalID, sessionID string, prior *ExecutorSession) error {
+	entry := HistoryEntry{
+		Timestamp:       time.Now(),
+		GoalID:          goalID,
+		SessionID:       sessionID,
+		ClaudeSessionID: prior.ClaudeSessionID,
+		TranscriptPath:  prior.TranscriptPath,
+		Type:            "session_resumed",
+		Data:            map[string]interface{}{"resumed_from": prior.SessionID},
+	}
+
+	h.mu.Lock()
+	for _, s := range h.sessions[goalID] {
+		if s.SessionID == sessionID {
+			s.ResumedFrom = prior.SessionID
+			s.ClaudeSessionID = prior.ClaudeSessionID
+			s.TranscriptPath = prior.TranscriptPath
+			break
+		}
+	}
+	h.mu.Unlock()
+
+	return h.appendEntry(entry)
+}
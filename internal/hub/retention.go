@@ -0,0 +1,454 @@
+package hub
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+const (
+	DefaultRetentionInterval = 6 * time.Hour
+	retentionCheckInterval   = time.Minute // How often the compactor checks whether a run is due
+)
+
+// Storage categories reported by GetStorage and limited by RetentionConfig
+const (
+	StorageHistory     = "history"     // .vega-hub-history/goal-<id>.jsonl
+	StorageTranscripts = "transcripts" // .vega-hub-history/transcripts/<goal>/<session>.jsonl
+	StorageEvents      = "events"      // .vega-hub-history/events.jsonl and its rotated copy
+	StorageOutputs     = "outputs"     // .executor-output.log in worktrees and goal folders
+)
+
+// StorageCategories lists the storage categories in report order
+var StorageCategories = []string{StorageHistory, StorageTranscripts, StorageEvents, StorageOutputs}
+
+// RetentionRule limits one storage category. Empty fields keep files forever.
+type RetentionRule struct {
+	MaxAge        string `json:"max_age,omitempty"`        // Go duration; delete files not modified for this long
+	MaxSizeMB     int64  `json:"max_size_mb,omitempty"`    // Delete the oldest files while the category is larger
+	CompressAfter string `json:"compress_after,omitempty"` // Go duration; gzip files not modified for this long (history and transcripts only)
+}
+
+// RetentionConfig is the on-disk format of <vega-dir>/.vega-hub-retention.json
+type RetentionConfig struct {
+	Interval    string        `json:"interval,omitempty"` // Go duration, defaults to 6h
+	History     RetentionRule `json:"history"`
+	Transcripts RetentionRule `json:"transcripts"`
+	Events      RetentionRule `json:"events"`
+	Outputs     RetentionRule `json:"outputs"`
+}
+
+// Rule returns the rule for a storage category
+func (c *RetentionConfig) Rule(category string) RetentionRule {
+	switch category {
+	case StorageHistory:
+		return c.History
+	case StorageTranscripts:
+		return c.Transcripts
+	case StorageEvents:
+		return c.Events
+	case StorageOutputs:
+		return c.Outputs
+	}
+	return RetentionRule{}
+}
+
+// interval returns the configured compaction interval
+func (c *RetentionConfig) interval() time.Duration {
+	if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+		return d
+	}
+	return DefaultRetentionInterval
+}
+
+// Configured reports whether any category has a limit
+func (c *RetentionConfig) Configured() bool {
+	for _, category := range StorageCategories {
+		if c.Rule(category) != (RetentionRule{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the interval and every rule's durations and sizes
+func (c *RetentionConfig) Validate() error {
+	if c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid interval %q", c.Interval)
+		}
+	}
+	for _, category := range StorageCategories {
+		rule := c.Rule(category)
+		for name, value := range map[string]string{"max_age": rule.MaxAge, "compress_after": rule.CompressAfter} {
+			if value == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+				return fmt.Errorf("%s: invalid %s %q", category, name, value)
+			}
+		}
+		if rule.MaxSizeMB < 0 {
+			return fmt.Errorf("%s: max_size_mb must not be negative", category)
+		}
+		if rule.CompressAfter != "" && category != StorageHistory && category != StorageTranscripts {
+			return fmt.Errorf("%s: compress_after is only supported for history and transcripts", category)
+		}
+	}
+	return nil
+}
+
+// retentionConfigPath returns the retention config path
+func retentionConfigPath(dir string) string {
+	return filepath.Join(dir, ".vega-hub-retention.json")
+}
+
+// LoadRetentionConfig reads <vega-dir>/.vega-hub-retention.json.
+// A missing file means everything is kept.
+func LoadRetentionConfig(dir string) (*RetentionConfig, error) {
+	cfg := &RetentionConfig{}
+	data, err := os.ReadFile(retentionConfigPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read retention config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse retention config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// StorageUsage is the disk usage of one storage category
+type StorageUsage struct {
+	Category   string        `json:"category"`
+	Files      int           `json:"files"`
+	Bytes      int64         `json:"bytes"`
+	Compressed int           `json:"compressed"` // Files gzipped by retention
+	Oldest     *time.Time    `json:"oldest,omitempty"`
+	Retention  RetentionRule `json:"retention"`
+}
+
+// StorageReport is the response of GET /api/storage
+type StorageReport struct {
+	Categories     []StorageUsage    `json:"categories"`
+	TotalBytes     int64             `json:"total_bytes"`
+	Interval       string            `json:"interval,omitempty"` // Set when retention is configured
+	LastCompaction *CompactionResult `json:"last_compaction,omitempty"`
+}
+
+// CompactionResult summarizes one retention run
+type CompactionResult struct {
+	StartedAt  time.Time `json:"started_at"`
+	Compressed int       `json:"compressed"`
+	Pruned     int       `json:"pruned"`
+	FreedBytes int64     `json:"freed_bytes"`
+	Errors     []string  `json:"errors,omitempty"`
+}
+
+// storageFile is one file counted in a storage category
+type storageFile struct {
+	path    string
+	goalID  string // Empty for the event log
+	size    int64
+	modTime time.Time
+	active  bool // Still written to; counted but never compressed or pruned
+}
+
+// GetStorage reports disk usage per storage category along with the
+// retention rules that apply to it
+func (h *Hub) GetStorage() (*StorageReport, error) {
+	cfg, err := LoadRetentionConfig(h.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StorageReport{Categories: make([]StorageUsage, 0, len(StorageCategories))}
+	if cfg.Configured() {
+		report.Interval = cfg.interval().String()
+	}
+	for _, category := range StorageCategories {
+		usage := StorageUsage{Category: category, Retention: cfg.Rule(category)}
+		for _, f := range h.storageFiles(category) {
+			usage.Files++
+			usage.Bytes += f.size
+			if strings.HasSuffix(f.path, ".gz") {
+				usage.Compressed++
+			}
+			if usage.Oldest == nil || f.modTime.Before(*usage.Oldest) {
+				t := f.modTime
+				usage.Oldest = &t
+			}
+		}
+		report.TotalBytes += usage.Bytes
+		report.Categories = append(report.Categories, usage)
+	}
+
+	h.retentionMu.Lock()
+	report.LastCompaction = h.lastCompaction
+	h.retentionMu.Unlock()
+	return report, nil
+}
+
+// CompactStorage applies the retention config once: files past compress_after
+// are gzipped, files past max_age are deleted, then the oldest files are
+// deleted until each category fits max_size_mb. Files of running executors
+// and the live event log are never touched.
+func (h *Hub) CompactStorage() (*CompactionResult, error) {
+	cfg, err := LoadRetentionConfig(h.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	h.retentionMu.Lock()
+	defer h.retentionMu.Unlock()
+
+	result := &CompactionResult{StartedAt: time.Now()}
+	for _, category := range StorageCategories {
+		h.applyRetention(category, cfg.Rule(category), result)
+	}
+	h.lastCompaction = result
+	return result, nil
+}
+
+// applyRetention enforces one category's rule
+func (h *Hub) applyRetention(category string, rule RetentionRule, result *CompactionResult) {
+	now := time.Now()
+	fail := func(f storageFile, err error) {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", f.path, err))
+	}
+
+	if d, err := time.ParseDuration(rule.CompressAfter); err == nil {
+		for _, f := range h.storageFiles(category) {
+			if f.active || strings.HasSuffix(f.path, ".gz") || now.Sub(f.modTime) < d {
+				continue
+			}
+			freed, err := h.compressStorageFile(category, f)
+			if err != nil {
+				fail(f, err)
+				continue
+			}
+			result.Compressed++
+			result.FreedBytes += freed
+		}
+	}
+
+	if d, err := time.ParseDuration(rule.MaxAge); err == nil {
+		for _, f := range h.storageFiles(category) {
+			if f.active || now.Sub(f.modTime) < d {
+				continue
+			}
+			if err := h.removeStorageFile(category, f); err != nil {
+				fail(f, err)
+				continue
+			}
+			result.Pruned++
+			result.FreedBytes += f.size
+		}
+	}
+
+	if rule.MaxSizeMB > 0 {
+		files := h.storageFiles(category)
+		var total int64
+		for _, f := range files {
+			total += f.size
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+		for _, f := range files {
+			if total <= rule.MaxSizeMB*1024*1024 {
+				break
+			}
+			if f.active {
+				continue
+			}
+			if err := h.removeStorageFile(category, f); err != nil {
+				fail(f, err)
+				continue
+			}
+			total -= f.size
+			result.Pruned++
+			result.FreedBytes += f.size
+		}
+	}
+}
+
+// storageFiles lists the files of a storage category
+func (h *Hub) storageFiles(category string) []storageFile {
+	// Goals and working directories of running executors
+	runningGoals := make(map[string]bool)
+	runningDirs := make(map[string]bool)
+	h.mu.RLock()
+	for _, e := range h.executors {
+		runningGoals[e.GoalID] = true
+		runningDirs[filepath.Clean(e.CWD)] = true
+	}
+	h.mu.RUnlock()
+
+	historyDir := h.history.historyDir()
+	var patterns []string
+	switch category {
+	case StorageHistory:
+		patterns = []string{filepath.Join(historyDir, "goal-*.jsonl"), filepath.Join(historyDir, "goal-*.jsonl.gz")}
+	case StorageTranscripts:
+		dir := filepath.Join(historyDir, "transcripts", "*")
+		patterns = []string{filepath.Join(dir, "*.jsonl"), filepath.Join(dir, "*.jsonl.gz")}
+	case StorageEvents:
+		patterns = []string{EventLogPath(h.dir), EventLogPath(h.dir) + ".1"}
+	case StorageOutputs:
+		patterns = []string{
+			filepath.Join(h.Layout().WorkspacesDir(), "*", "*", ".executor-output.log"),
+			filepath.Join(h.Layout().GoalDir(layout.ActiveDir), "*", ".executor-output.log"),
+		}
+	}
+
+	var files []storageFile
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			f := storageFile{path: path, size: info.Size(), modTime: info.ModTime()}
+			switch category {
+			case StorageHistory:
+				name := strings.TrimSuffix(filepath.Base(path), ".gz")
+				f.goalID = strings.TrimSuffix(strings.TrimPrefix(name, "goal-"), ".jsonl")
+				f.active = runningGoals[f.goalID]
+			case StorageTranscripts:
+				f.goalID = filepath.Base(filepath.Dir(path))
+				f.active = runningGoals[f.goalID]
+			case StorageEvents:
+				f.active = path == EventLogPath(h.dir)
+			case StorageOutputs:
+				f.active = runningDirs[filepath.Dir(path)]
+			}
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// compressStorageFile gzips a file in place and returns the bytes saved
+func (h *Hub) compressStorageFile(category string, f storageFile) (int64, error) {
+	if category != StorageHistory {
+		return compressFile(f.path)
+	}
+
+	// History may already have a compressed part and gets appended to while
+	// the goal runs, so new content is added as another gzip member
+	h.history.fileMu.Lock()
+	defer h.history.fileMu.Unlock()
+	return compressFile(f.path)
+}
+
+// compressFile gzips path into path.gz, appending to an existing .gz as a
+// further gzip member, and removes path. The .gz keeps path's modification
+// time so ages stay comparable.
+func compressFile(path string) (int64, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	gzPath := path + ".gz"
+	var before, after int64
+	err = atomicfile.Write(gzPath, 0644, func(out *os.File) error {
+		if old, err := os.Open(gzPath); err == nil {
+			before, err = io.Copy(out, old)
+			old.Close()
+			if err != nil {
+				return err
+			}
+		}
+		zw := gzip.NewWriter(out)
+		if _, err := io.Copy(zw, src); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		after, err = out.Seek(0, io.SeekCurrent)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	os.Chtimes(gzPath, info.ModTime(), info.ModTime())
+	if err := os.Remove(path); err != nil {
+		return 0, err
+	}
+	return info.Size() - (after - before), nil
+}
+
+// removeStorageFile deletes a file, dropping cached sessions for history
+func (h *Hub) removeStorageFile(category string, f storageFile) error {
+	if category == StorageHistory {
+		h.history.fileMu.Lock()
+		defer h.history.fileMu.Unlock()
+		defer h.history.forget(f.goalID)
+	}
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// StartRetentionCompactor applies the retention config every configured
+// interval until stop is closed. The config is re-read on every check, so
+// retention can be set up or changed without restarting.
+func (h *Hub) StartRetentionCompactor(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(retentionCheckInterval)
+		defer ticker.Stop()
+		var last time.Time
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+
+			cfg, err := LoadRetentionConfig(h.dir)
+			if err != nil {
+				log.Printf("[RETENTION] %v", err)
+				continue
+			}
+			if !cfg.Configured() || time.Since(last) < cfg.interval() {
+				continue
+			}
+			result, err := h.CompactStorage()
+			last = time.Now()
+			if err != nil {
+				log.Printf("[RETENTION] %v", err)
+				continue
+			}
+			for _, e := range result.Errors {
+				log.Printf("[RETENTION] %s", e)
+			}
+			if result.Compressed > 0 || result.Pruned > 0 {
+				log.Printf("[RETENTION] Compressed %d and pruned %d file(s), freed %d bytes",
+					result.Compressed, result.Pruned, result.FreedBytes)
+			}
+		}
+	}()
+}
@@ -0,0 +1,165 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRetentionConfig(t *testing.T, dir, config string) {
+	t.Helper()
+	if err := os.WriteFile(retentionConfigPath(dir), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write retention config: %v", err)
+	}
+}
+
+func age(t *testing.T, path string, d time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-d)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("failed to age %s: %v", path, err)
+	}
+}
+
+func TestRetentionConfigValidate(t *testing.T) {
+	for _, config := range []string{
+		`{"interval":"soon"}`,
+		`{"history":{"max_age":"30d"}}`,
+		`{"transcripts":{"max_size_mb":-1}}`,
+		`{"outputs":{"compress_after":"24h"}}`,
+	} {
+		dir := t.TempDir()
+		writeRetentionConfig(t, dir, config)
+		if _, err := LoadRetentionConfig(dir); err == nil {
+			t.Errorf("expected %s to be rejected", config)
+		}
+	}
+
+	cfg, err := LoadRetentionConfig(t.TempDir())
+	if err != nil || cfg.Configured() {
+		t.Errorf("expected a missing config to keep everything, got %+v (%v)", cfg, err)
+	}
+}
+
+func TestCompactStorageCompressesHistory(t *testing.T) {
+	h := setupTestHub(t)
+	writeRetentionConfig(t, h.dir, `{"history":{"compress_after":"24h"}}`)
+
+	h.history.RecordSessionStart("abc1234", "s1", "/tmp", "alice")
+	h.history.RecordQuestion("abc1234", "s1", "Which database?", "PostgreSQL")
+	age(t, h.history.historyFile("abc1234"), 48*time.Hour)
+
+	result, err := h.CompactStorage()
+	if err != nil {
+		t.Fatalf("CompactStorage failed: %v", err)
+	}
+	if result.Compressed != 1 || len(result.Errors) > 0 {
+		t.Fatalf("expected one compressed file, got %+v", result)
+	}
+	if _, err := os.Stat(h.history.historyFile("abc1234")); !os.IsNotExist(err) {
+		t.Error("expected the plain history file to be removed")
+	}
+
+	// New entries go to a fresh file and both parts are read back in order
+	h.history.RecordSessionStart("abc1234", "s2", "/tmp", "alice")
+	entries, err := h.history.GetGoalHistory("abc1234", 0)
+	if err != nil {
+		t.Fatalf("GetGoalHistory failed: %v", err)
+	}
+	if len(entries) != 3 || entries[1].Answer != "PostgreSQL" || entries[2].SessionID != "s2" {
+		t.Fatalf("expected compressed and new entries, got %+v", entries)
+	}
+
+	// A second compaction appends to the existing archive
+	age(t, h.history.historyFile("abc1234"), 48*time.Hour)
+	if result, _ := h.CompactStorage(); result.Compressed != 1 {
+		t.Fatalf("expected the new part to be compressed, got %+v", result)
+	}
+	if sessions, _ := h.history.loadGoalHistory("abc1234"); len(sessions) != 2 {
+		t.Errorf("expected 2 sessions after recompression, got %d", len(sessions))
+	}
+}
+
+func TestCompactStoragePrunes(t *testing.T) {
+	h := setupTestHub(t)
+	writeRetentionConfig(t, h.dir, `{
+		"transcripts": {"max_size_mb": 1},
+		"events": {"max_age": "24h"},
+		"outputs": {"max_age": "24h"}
+	}`)
+
+	transcripts := filepath.Join(h.history.historyDir(), "transcripts", "abc1234")
+	os.MkdirAll(transcripts, 0755)
+	big := make([]byte, 700*1024)
+	for i, session := range []string{"old", "new"} {
+		path := filepath.Join(transcripts, session+".jsonl")
+		os.WriteFile(path, big, 0644)
+		age(t, path, time.Duration(2-i)*time.Hour)
+	}
+
+	events := EventLogPath(h.dir)
+	os.WriteFile(events, []byte("{}\n"), 0644)
+	os.WriteFile(events+".1", []byte("{}\n"), 0644)
+	age(t, events, 48*time.Hour)
+	age(t, events+".1", 48*time.Hour)
+
+	idle := filepath.Join(h.dir, "workspaces", "my-api", "goal-abc1234-fix")
+	running := filepath.Join(h.dir, "workspaces", "my-api", "goal-def5678-add")
+	for _, dir := range []string{idle, running} {
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, ".executor-output.log"), []byte("output\n"), 0644)
+		age(t, filepath.Join(dir, ".executor-output.log"), 48*time.Hour)
+	}
+	h.RegisterExecutor("def5678", "s1", running, "alice")
+
+	result, err := h.CompactStorage()
+	if err != nil {
+		t.Fatalf("CompactStorage failed: %v", err)
+	}
+	if result.Pruned != 3 || len(result.Errors) > 0 {
+		t.Fatalf("expected 3 pruned files, got %+v", result)
+	}
+	for path, kept := range map[string]bool{
+		filepath.Join(transcripts, "old.jsonl"):        false,
+		filepath.Join(transcripts, "new.jsonl"):        true,
+		events:                                         true, // live event log
+		events + ".1":                                  false,
+		filepath.Join(idle, ".executor-output.log"):    false,
+		filepath.Join(running, ".executor-output.log"): true, // running executor
+	} {
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Errorf("%s: expected kept=%v", path, kept)
+		}
+	}
+
+	report, err := h.GetStorage()
+	if err != nil {
+		t.Fatalf("GetStorage failed: %v", err)
+	}
+	if report.LastCompaction != result || len(report.Categories) != len(StorageCategories) {
+		t.Fatalf("unexpected storage report: %+v", report)
+	}
+	transcriptUsage := report.Categories[1]
+	if transcriptUsage.Category != StorageTranscripts || transcriptUsage.Files != 1 || transcriptUsage.Bytes != int64(len(big)) {
+		t.Errorf("unexpected transcript usage: %+v", transcriptUsage)
+	}
+}
+
+func TestGetTranscriptCompressed(t *testing.T) {
+	h := setupTestHub(t)
+	path := h.history.transcriptFile("abc1234", "s1")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"role":"user","text":"hello"}`+"\n"), 0644)
+
+	if _, err := compressFile(path); err != nil {
+		t.Fatalf("compressFile failed: %v", err)
+	}
+	entries, err := h.history.GetTranscript("abc1234", "s1")
+	if err != nil {
+		t.Fatalf("GetTranscript failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Text != "hello" {
+		t.Errorf("expected the compressed transcript, got %+v", entries)
+	}
+}
@@ -0,0 +1,76 @@
+package hub
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// ReviewGoal records a reviewer's decision ("approve" or "request_changes")
+// on a goal. The decision moves the goal to approved or changes_requested;
+// its comment is posted to the goal's discussion and, when changes are
+// requested, handed to the next executor in its context pack.
+func (h *Hub) ReviewGoal(goalID, decision, comment, reviewer string) (*goals.Review, error) {
+	state, err := goals.ReviewState(decision)
+	if err != nil {
+		return nil, err
+	}
+	review, err := h.stateManager.Review(goalID, state, reviewer, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Comment != "" {
+		label := "Approved"
+		if review.State == goals.StateChangesRequested {
+			label = "Changes requested"
+		}
+		if _, err := h.AddComment(goalID, "", reviewer, fmt.Sprintf("**%s:** %s", label, review.Comment)); err != nil {
+			log.Printf("[REVIEW] Failed to post review comment on goal %s: %v", goalID, err)
+		}
+	}
+
+	h.history.RecordActivity(goalID, "", "goal_reviewed", map[string]interface{}{
+		"state":    string(review.State),
+		"reviewer": reviewer,
+		"comment":  review.Comment,
+	})
+	h.broadcast(Event{
+		Type: EventGoalReviewed,
+		Data: map[string]interface{}{
+			"goal_id":  goalID,
+			"state":    string(review.State),
+			"reviewer": reviewer,
+			"comment":  review.Comment,
+		},
+	})
+	return review, nil
+}
+
+// GetReview returns the latest review of a goal (nil if never reviewed)
+func (h *Hub) GetReview(goalID string) (*goals.Review, error) {
+	return h.stateManager.LatestReview(goalID)
+}
+
+// resumeAfterReview moves a goal sent back for changes to working when an
+// executor starts on it
+func (h *Hub) resumeAfterReview(goalID, user string) {
+	if state, err := h.stateManager.GetState(goalID); err == nil && state == goals.StateChangesRequested {
+		h.transitionExecutorState(goalID, goals.StateWorking, "Executor started on requested changes", user)
+	}
+}
+
+// reviewContext includes outstanding review comments for the executor
+func (h *Hub) reviewContext(goalID string) string {
+	review, err := h.GetReview(goalID)
+	if err != nil || review == nil || review.State != goals.StateChangesRequested {
+		return ""
+	}
+	by := ""
+	if review.Reviewer != "" {
+		by = " by " + review.Reviewer
+	}
+	return fmt.Sprintf("Changes were requested%s on %s. Address them before completing the goal:\n\n%s",
+		by, review.At.Format("2006-01-02 15:04"), review.Comment)
+}
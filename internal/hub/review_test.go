@@ -0,0 +1,59 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestReviewGoal(t *testing.T) {
+	dir := t.TempDir()
+	goalDir := filepath.Join(dir, "goals", "active", "abc1234")
+	if err := os.MkdirAll(goalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(goalDir, "abc1234.md"), []byte("# Goal 1: Review me\n"), 0644)
+	h := New(dir)
+	sm := h.StateManager()
+	if err := sm.Transition("abc1234", goals.StateWorking, "Goal ready", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ReviewGoal("abc1234", "lgtm", "", "bob"); err == nil {
+		t.Error("expected an unknown decision refused")
+	}
+	review, err := h.ReviewGoal("abc1234", goals.ReviewRequestChanges, "Handle the empty config case", "bob")
+	if err != nil {
+		t.Fatalf("ReviewGoal: %v", err)
+	}
+	if review.State != goals.StateChangesRequested || review.Reviewer != "bob" {
+		t.Errorf("unexpected review %+v", review)
+	}
+
+	comments, _ := h.GetComments("abc1234")
+	if len(comments) != 1 || comments[0].User != "bob" || !strings.Contains(comments[0].Content, "Handle the empty config case") {
+		t.Errorf("expected the review comment in the discussion, got %+v", comments)
+	}
+	if got := h.reviewContext("abc1234"); !strings.Contains(got, "Handle the empty config case") || !strings.Contains(got, "by bob") {
+		t.Errorf("expected the requested changes in the context, got %q", got)
+	}
+
+	// The next executor picks the goal back up
+	h.RegisterExecutor("abc1234", "s1", t.TempDir(), "alice")
+	if state, _ := sm.GetState("abc1234"); state != goals.StateWorking {
+		t.Errorf("expected the goal back to working, got %s", state)
+	}
+
+	if _, err := h.ReviewGoal("abc1234", goals.ReviewApprove, "", "bob"); err != nil {
+		t.Fatalf("ReviewGoal: %v", err)
+	}
+	if got := h.reviewContext("abc1234"); got != "" {
+		t.Errorf("expected no requested changes once approved, got %q", got)
+	}
+	if state, _ := sm.GetState("abc1234"); state != goals.StateApproved {
+		t.Errorf("expected approved, got %s", state)
+	}
+}
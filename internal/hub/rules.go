@@ -0,0 +1,367 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// Question priorities
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// ValidPriorities defines the allowed question priorities
+var ValidPriorities = map[string]bool{
+	PriorityLow:    true,
+	PriorityNormal: true,
+	PriorityHigh:   true,
+}
+
+// ErrRuleNotFound is returned when a question rule doesn't exist
+var ErrRuleNotFound = errors.New("rule not found")
+
+// QuestionRule matches executor questions and auto-answers, routes or prioritizes them.
+// A rule applies when Pattern matches the question text and the optional
+// GoalID/Project scope matches the asking goal.
+type QuestionRule struct {
+	ID      string `json:"id"`
+	Pattern string `json:"pattern"`           // Regex matched against question text
+	GoalID  string `json:"goal_id,omitempty"` // Only apply to this goal
+	Project string `json:"project,omitempty"` // Only apply to goals in this project
+
+	// Actions (any combination)
+	Answer       string   `json:"answer,omitempty"`        // Auto-answer with this text
+	AnswerOption string   `json:"answer_option,omitempty"` // Auto-answer by selecting this option (ID or label)
+	AssignTo     []string `json:"assign_to,omitempty"`     // Route to these users
+	Priority     string   `json:"priority,omitempty"`      // "low", "normal", "high"
+	Category     string   `json:"category,omitempty"`      // Free-form category label
+
+	Disabled  bool      `json:"disabled,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	re *regexp.Regexp
+}
+
+// AutoAnswers returns true if the rule answers questions on its own
+func (r *QuestionRule) AutoAnswers() bool {
+	return r.Answer != "" || r.AnswerOption != ""
+}
+
+// Validate checks the rule is well-formed and compiles its pattern
+func (r *QuestionRule) Validate() error {
+	if r.Pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	if r.Priority != "" && !ValidPriorities[r.Priority] {
+		return fmt.Errorf("invalid priority: %s (valid: low, normal, high)", r.Priority)
+	}
+	if !r.AutoAnswers() && len(r.AssignTo) == 0 && r.Priority == "" && r.Category == "" {
+		return fmt.Errorf("rule has no action (answer, answer_option, assign_to, priority or category)")
+	}
+	r.re = re
+	return nil
+}
+
+// RuleMatch is the combined outcome of all rules matching a question
+type RuleMatch struct {
+	RuleIDs    []string          // All matching rules, in evaluation order
+	AutoAnswer *StructuredAnswer // Set if a rule auto-answers the question
+	AnswerRule string            // ID of the rule that auto-answered
+	AssignTo   []string
+	Priority   string
+	Category   string
+}
+
+// QuestionRules stores question rules in <vega-dir>/.vega-hub-rules.json
+type QuestionRules struct {
+	mu      sync.Mutex
+	dir     string
+	rules   []*QuestionRule
+	invalid []invalidRule // Entries that didn't parse, written back as they were
+	modTime time.Time     // mtime of the rules file when last loaded
+}
+
+// invalidRule is a rules file entry that doesn't parse or validate. It isn't
+// evaluated, but stays in the file until it is fixed or deleted.
+type invalidRule struct {
+	id  string
+	raw json.RawMessage
+}
+
+// questionRulesFile is the on-disk format
+type questionRulesFile struct {
+	Rules []json.RawMessage `json:"rules"`
+}
+
+// NewQuestionRules creates a rules store for the vega-missile directory
+func NewQuestionRules(dir string) *QuestionRules {
+	return &QuestionRules{dir: dir}
+}
+
+// path returns the rules file path
+func (s *QuestionRules) path() string {
+	return filepath.Join(s.dir, ".vega-hub-rules.json")
+}
+
+// reloadIfChanged reloads the rules file if it was edited on disk (caller holds write lock)
+func (s *QuestionRules) reloadIfChanged() error {
+	if s.dir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.rules, s.invalid = nil, nil
+			s.modTime = time.Time{}
+			return nil
+		}
+		return err
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path())
+	if err != nil {
+		return fmt.Errorf("failed to read rules: %w", err)
+	}
+	var file questionRulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	// Broken rules are skipped rather than disabling the whole file
+	rules := make([]*QuestionRule, 0, len(file.Rules))
+	var invalid []invalidRule
+	for i, raw := range file.Rules {
+		var r QuestionRule
+		err := json.Unmarshal(raw, &r)
+		if err == nil {
+			err = r.Validate()
+		}
+		if err != nil {
+			log.Printf("[RULES] Skipping rule %d (%q) in %s: %v", i+1, r.ID, s.path(), err)
+			invalid = append(invalid, invalidRule{id: r.ID, raw: raw})
+			continue
+		}
+		rules = append(rules, &r)
+	}
+	s.rules, s.invalid = rules, invalid
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// save writes the rules atomically (caller holds write lock)
+func (s *QuestionRules) save() error {
+	file := questionRulesFile{Rules: make([]json.RawMessage, 0, len(s.rules)+len(s.invalid))}
+	for _, r := range s.rules {
+		raw, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rules: %w", err)
+		}
+		file.Rules = append(file.Rules, raw)
+	}
+	for _, r := range s.invalid {
+		file.Rules = append(file.Rules, r.raw)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(s.path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write rules: %w", err)
+	}
+
+	if info, err := os.Stat(s.path()); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// List returns all rules
+func (s *QuestionRules) List() ([]*QuestionRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+	result := make([]*QuestionRule, len(s.rules))
+	copy(result, s.rules)
+	return result, nil
+}
+
+// Add validates and stores a new rule, assigning an ID if needed
+func (s *QuestionRules) Add(rule *QuestionRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return err
+	}
+	if rule.ID == "" {
+		rule.ID = fmt.Sprintf("rule-%d", time.Now().UnixNano())
+	}
+	for _, existing := range s.rules {
+		if existing.ID == rule.ID {
+			return fmt.Errorf("rule %s already exists", rule.ID)
+		}
+	}
+	for _, existing := range s.invalid {
+		if existing.id == rule.ID {
+			return fmt.Errorf("rule %s already exists (and is invalid)", rule.ID)
+		}
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = time.Now()
+	}
+
+	s.rules = append(s.rules, rule)
+	return s.save()
+}
+
+// Delete removes a rule by ID, including one that doesn't parse
+func (s *QuestionRules) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return err
+	}
+	for i, r := range s.rules {
+		if r.ID == id {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return s.save()
+		}
+	}
+	for i, r := range s.invalid {
+		if r.id == id {
+			s.invalid = append(s.invalid[:i], s.invalid[i+1:]...)
+			return s.save()
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrRuleNotFound, id)
+}
+
+// Evaluate runs all enabled rules against a question. The first matching rule
+// wins for each action (auto-answer, routing, priority, category).
+func (s *QuestionRules) Evaluate(q *Question) *RuleMatch {
+	s.mu.Lock()
+	if err := s.reloadIfChanged(); err != nil {
+		s.mu.Unlock()
+		return &RuleMatch{}
+	}
+	rules := make([]*QuestionRule, len(s.rules))
+	copy(rules, s.rules)
+	s.mu.Unlock()
+
+	match := &RuleMatch{}
+	var projects []string
+	projectsLoaded := false
+
+	for _, r := range rules {
+		if r.Disabled || !r.re.MatchString(q.Question) {
+			continue
+		}
+		if r.GoalID != "" && r.GoalID != q.GoalID {
+			continue
+		}
+		if r.Project != "" {
+			if !projectsLoaded {
+				projects = s.goalProjects(q.GoalID)
+				projectsLoaded = true
+			}
+			if !containsString(projects, r.Project) {
+				continue
+			}
+		}
+
+		match.RuleIDs = append(match.RuleIDs, r.ID)
+
+		if match.AutoAnswer == nil && r.AutoAnswers() {
+			if answer := r.buildAnswer(q); answer != nil {
+				match.AutoAnswer = answer
+				match.AnswerRule = r.ID
+			}
+		}
+		if len(match.AssignTo) == 0 && len(r.AssignTo) > 0 {
+			match.AssignTo = r.AssignTo
+		}
+		if match.Priority == "" && r.Priority != "" {
+			match.Priority = r.Priority
+		}
+		if match.Category == "" && r.Category != "" {
+			match.Category = r.Category
+		}
+	}
+
+	return match
+}
+
+// buildAnswer creates the auto-answer for a question, or nil if the rule's
+// option doesn't exist on this question
+func (r *QuestionRule) buildAnswer(q *Question) *StructuredAnswer {
+	answer := &StructuredAnswer{Text: r.Answer}
+	if r.AnswerOption != "" {
+		var found *Option
+		for i := range q.Options {
+			opt := &q.Options[i]
+			if opt.ID == r.AnswerOption || strings.EqualFold(opt.Label, r.AnswerOption) {
+				found = opt
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		answer.OptionIDs = []string{found.ID}
+	}
+	return answer
+}
+
+// goalProjects returns the projects a goal belongs to (from the registry)
+func (s *QuestionRules) goalProjects(goalID string) []string {
+	entry, err := goals.NewRegistry(s.dir).Get(goalID)
+	if err != nil {
+		return nil
+	}
+	return entry.Projects
+}
+
+// IsAssignedTo returns true if a rule routed the question to the user
+func (q *Question) IsAssignedTo(user string) bool {
+	return containsString(q.AssignedTo, user)
+}
+
+// containsString checks if a slice contains a string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
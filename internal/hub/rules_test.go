@@ -0,0 +1,194 @@
+package hub
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQuestionRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    QuestionRule
+		wantErr bool
+	}{
+		{"auto-answer", QuestionRule{Pattern: "(?i)run tests", Answer: "yes"}, false},
+		{"route", QuestionRule{Pattern: "deploy", AssignTo: []string{"alice"}}, false},
+		{"priority", QuestionRule{Pattern: "lint", Priority: PriorityLow}, false},
+		{"missing pattern", QuestionRule{Answer: "yes"}, true},
+		{"bad regex", QuestionRule{Pattern: "([", Answer: "yes"}, true},
+		{"bad priority", QuestionRule{Pattern: "x", Priority: "urgent"}, true},
+		{"no action", QuestionRule{Pattern: "x"}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.rule.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestQuestionRulesPersistence(t *testing.T) {
+	dir := t.TempDir()
+	rules := NewQuestionRules(dir)
+
+	if err := rules.Add(&QuestionRule{ID: "tests", Pattern: "run the tests", Answer: "Yes"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := rules.Add(&QuestionRule{ID: "tests", Pattern: "x", Answer: "dup"}); err == nil {
+		t.Error("expected duplicate ID to be rejected")
+	}
+
+	// A fresh store reads the same file
+	list, err := NewQuestionRules(dir).List()
+	if err != nil || len(list) != 1 || list[0].ID != "tests" {
+		t.Fatalf("expected persisted rule, got %v (err: %v)", list, err)
+	}
+
+	if err := rules.Delete("tests"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := rules.Delete("tests"); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("expected ErrRuleNotFound, got %v", err)
+	}
+}
+
+func TestQuestionRulesReloadOnEdit(t *testing.T) {
+	dir := t.TempDir()
+	rules := NewQuestionRules(dir)
+
+	content := `{"rules": [
+		{"id": "lint", "pattern": "lint", "priority": "low"},
+		{"id": "broken", "pattern": "([", "answer": "x"}
+	]}`
+	path := filepath.Join(dir, ".vega-hub-rules.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := rules.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "lint" {
+		t.Errorf("expected only the valid rule to load, got %v", list)
+	}
+
+	// Saving keeps the broken rule as it was, and it can still be deleted
+	if err := rules.Add(&QuestionRule{ID: "tests", Pattern: "tests", Answer: "yes"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"pattern": "(["`) {
+		t.Errorf("broken rule dropped on save:\n%s", data)
+	}
+	if err := rules.Delete("broken"); err != nil {
+		t.Fatalf("Delete of the broken rule failed: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "broken") {
+		t.Errorf("broken rule still saved after delete:\n%s", data)
+	}
+}
+
+func TestQuestionRulesEvaluate(t *testing.T) {
+	rules := NewQuestionRules(t.TempDir())
+	for _, r := range []*QuestionRule{
+		{ID: "other-goal", Pattern: "commit", GoalID: "zzz9999", Answer: "no"},
+		{ID: "commit", Pattern: "(?i)should I commit", Answer: "Yes, commit"},
+		{ID: "route", Pattern: "(?i)commit", AssignTo: []string{"alice"}, Priority: PriorityLow, Category: "git"},
+		{ID: "later", Pattern: "commit", Answer: "ignored", Priority: PriorityHigh},
+		{ID: "off", Pattern: "commit", Category: "disabled", Disabled: true},
+	} {
+		if err := rules.Add(r); err != nil {
+			t.Fatalf("Add %s failed: %v", r.ID, err)
+		}
+	}
+
+	match := rules.Evaluate(&Question{GoalID: "abc1234", Question: "Should I commit these changes?"})
+
+	if match.AutoAnswer == nil || match.AutoAnswer.Text != "Yes, commit" || match.AnswerRule != "commit" {
+		t.Errorf("expected auto-answer from 'commit' rule, got %+v", match)
+	}
+	if len(match.AssignTo) != 1 || match.AssignTo[0] != "alice" {
+		t.Errorf("expected routing to alice, got %v", match.AssignTo)
+	}
+	if match.Priority != PriorityLow || match.Category != "git" {
+		t.Errorf("expected first matching priority/category, got %q/%q", match.Priority, match.Category)
+	}
+	if len(match.RuleIDs) != 3 {
+		t.Errorf("expected 3 matching rules, got %v", match.RuleIDs)
+	}
+
+	if match := rules.Evaluate(&Question{GoalID: "abc1234", Question: "Which library?"}); len(match.RuleIDs) != 0 {
+		t.Errorf("expected no matches, got %v", match.RuleIDs)
+	}
+}
+
+func TestQuestionRulesAnswerOption(t *testing.T) {
+	rules := NewQuestionRules(t.TempDir())
+	if err := rules.Add(&QuestionRule{ID: "pg", Pattern: "database", AnswerOption: "postgres"}); err != nil {
+		t.Fatal(err)
+	}
+
+	q := newChoiceQuestion(false)
+	q.Question = "Which database?"
+	match := rules.Evaluate(q)
+	if match.AutoAnswer == nil || len(match.AutoAnswer.OptionIDs) != 1 || match.AutoAnswer.OptionIDs[0] != "opt-1" {
+		t.Errorf("expected option opt-1 selected by label, got %+v", match.AutoAnswer)
+	}
+
+	// Option not offered: rule doesn't auto-answer
+	q.Options = []Option{{ID: "opt-1", Label: "MySQL"}}
+	if match := rules.Evaluate(q); match.AutoAnswer != nil {
+		t.Errorf("expected no auto-answer without matching option, got %+v", match.AutoAnswer)
+	}
+}
+
+func TestAskAppliesRules(t *testing.T) {
+	h := setupTestHub(t)
+	if err := h.Rules().Add(&QuestionRule{ID: "tests", Pattern: "(?i)run the tests", Answer: "Always run them"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Rules().Add(&QuestionRule{ID: "deploy", Pattern: "(?i)deploy", AssignTo: []string{"ops"}, Priority: PriorityHigh}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Auto-answered questions return immediately and never become pending
+	answer := h.Ask(&Question{ID: "q-auto", GoalID: "abc1234", SessionID: "s1", Question: "Should I run the tests?"})
+	if answer != "Always run them" {
+		t.Errorf("expected auto-answer, got %q", answer)
+	}
+	entries, err := h.GetGoalHistory("abc1234", 0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 history entry, got %d (err: %v)", len(entries), err)
+	}
+	if data, ok := entries[0].Data.(map[string]interface{}); !ok || data["auto_answered_by"] != "tests" {
+		t.Errorf("expected auto_answered_by in history, got %v", entries[0].Data)
+	}
+
+	// Routed questions stay pending with assignment and priority
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.Ask(&Question{ID: "q-deploy", GoalID: "abc1234", SessionID: "s1", Question: "Deploy to staging?"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	pending := h.GetPendingQuestions()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending question, got %d", len(pending))
+	}
+	if !pending[0].IsAssignedTo("ops") || pending[0].Priority != PriorityHigh {
+		t.Errorf("expected routed high-priority question, got %+v", pending[0])
+	}
+
+	h.Answer("q-deploy", "yes")
+	wg.Wait()
+}
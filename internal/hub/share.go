@@ -0,0 +1,383 @@
+package hub
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// Share link lifetimes
+const (
+	DefaultShareTTL = 7 * 24 * time.Hour
+	MaxShareTTL     = 90 * 24 * time.Hour
+	maxShareLabel   = 200
+)
+
+var (
+	// ErrShareInvalid is returned for malformed, forged or unknown share tokens
+	ErrShareInvalid = errors.New("invalid share link")
+
+	// ErrShareExpired is returned for share links past their expiry
+	ErrShareExpired = errors.New("share link has expired")
+
+	// ErrShareRevoked is returned for share links revoked by a user
+	ErrShareRevoked = errors.New("share link was revoked")
+
+	// ErrShareNotFound is returned for a link the goal doesn't have
+	ErrShareNotFound = errors.New("share link not found")
+)
+
+// ShareLink lets someone without a hub account follow one goal read-only
+// (its detail and chat). Its token is signed with the hub's share secret and
+// only returned when the link is created.
+type ShareLink struct {
+	ID        string     `json:"id"`
+	GoalID    string     `json:"goal_id"`
+	Label     string     `json:"label,omitempty"` // Who or what it was shared with
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy string     `json:"revoked_by,omitempty"`
+
+	// From the access log, filled in by GetShareLinks
+	Accesses     int        `json:"accesses"`
+	LastAccessAt *time.Time `json:"last_access_at,omitempty"`
+}
+
+// ShareAccess is one request made with a share link
+type ShareAccess struct {
+	LinkID     string    `json:"link_id"`
+	GoalID     string    `json:"goal_id"`
+	At         time.Time `json:"at"`
+	View       string    `json:"view"` // "detail" or "chat"
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+// Share links live in <vega-dir>/.vega-hub-shares: the signing secret, the
+// links, and an append-only access log
+func (h *Hub) sharesDir() string {
+	return filepath.Join(h.dir, ".vega-hub-shares")
+}
+
+// CreateShareLink mints a read-only link to a goal expiring after ttl (the
+// default when 0). It returns the link and its token.
+func (h *Hub) CreateShareLink(goalID, label, user string, ttl time.Duration) (*ShareLink, string, error) {
+	switch {
+	case ttl == 0:
+		ttl = DefaultShareTTL
+	case ttl < 0 || ttl > MaxShareTTL:
+		return nil, "", fmt.Errorf("expiry must be between 0 and %s", MaxShareTTL)
+	}
+	label = strings.TrimSpace(label)
+	if len(label) > maxShareLabel {
+		return nil, "", fmt.Errorf("label exceeds %d bytes", maxShareLabel)
+	}
+	b := make([]byte, 6)
+	rand.Read(b)
+
+	now := time.Now()
+	link := ShareLink{
+		ID:        "s-" + hex.EncodeToString(b),
+		GoalID:    goalID,
+		Label:     label,
+		CreatedBy: user,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+	}
+
+	h.sharesMu.Lock()
+	secret, err := h.shareSecret()
+	var links []ShareLink
+	if err == nil {
+		links, err = h.readShareLinks()
+	}
+	if err == nil {
+		err = h.writeShareLinks(append(links, link))
+	}
+	h.sharesMu.Unlock()
+	if err != nil {
+		return nil, "", err
+	}
+
+	h.broadcast(Event{
+		Type: EventShareLinkCreated,
+		Data: map[string]interface{}{
+			"goal_id":    goalID,
+			"link_id":    link.ID,
+			"expires_at": link.ExpiresAt,
+			"user":       user,
+		},
+	})
+	return &link, signShareToken(secret, &link), nil
+}
+
+// GetShareLinks returns a goal's share links, newest first, with their
+// access counts. Expired and revoked links are kept for the record.
+func (h *Hub) GetShareLinks(goalID string) ([]ShareLink, error) {
+	h.sharesMu.Lock()
+	links, err := h.readShareLinks()
+	h.sharesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	accesses, err := h.GetShareAccessLog(goalID, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []ShareLink{}
+	for i := len(links) - 1; i >= 0; i-- {
+		if links[i].GoalID != goalID {
+			continue
+		}
+		link := links[i]
+		for _, a := range accesses {
+			if a.LinkID != link.ID {
+				continue
+			}
+			if link.LastAccessAt == nil {
+				at := a.At
+				link.LastAccessAt = &at // Log is read newest first
+			}
+			link.Accesses++
+		}
+		result = append(result, link)
+	}
+	return result, nil
+}
+
+// RevokeShareLink stops a goal's share link from granting access
+func (h *Hub) RevokeShareLink(goalID, id, user string) (*ShareLink, error) {
+	h.sharesMu.Lock()
+	links, err := h.readShareLinks()
+	if err != nil {
+		h.sharesMu.Unlock()
+		return nil, err
+	}
+	var link *ShareLink
+	for i := range links {
+		if links[i].ID == id && links[i].GoalID == goalID {
+			link = &links[i]
+		}
+	}
+	switch {
+	case link == nil:
+		err = fmt.Errorf("%w: %s", ErrShareNotFound, id)
+	case link.RevokedAt != nil:
+		// Already revoked: keep who did it first
+	default:
+		now := time.Now()
+		link.RevokedAt = &now
+		link.RevokedBy = user
+		err = h.writeShareLinks(links)
+	}
+	h.sharesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	h.broadcast(Event{
+		Type: EventShareLinkRevoked,
+		Data: map[string]interface{}{
+			"goal_id": goalID,
+			"link_id": id,
+			"user":    user,
+		},
+	})
+	revoked := *link
+	return &revoked, nil
+}
+
+// ResolveShareToken returns the link a token was issued for if it still
+// grants access: the signature matches, and it is neither revoked nor expired
+func (h *Hub) ResolveShareToken(token string) (*ShareLink, error) {
+	id, _, ok := strings.Cut(token, ".")
+	if !ok || id == "" {
+		return nil, ErrShareInvalid
+	}
+
+	h.sharesMu.Lock()
+	secret, err := h.shareSecret()
+	var links []ShareLink
+	if err == nil {
+		links, err = h.readShareLinks()
+	}
+	h.sharesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range links {
+		link := links[i]
+		if link.ID != id {
+			continue
+		}
+		if !hmac.Equal([]byte(token), []byte(signShareToken(secret, &link))) {
+			return nil, ErrShareInvalid
+		}
+		switch {
+		case link.RevokedAt != nil:
+			return nil, ErrShareRevoked
+		case !time.Now().Before(link.ExpiresAt):
+			return nil, ErrShareExpired
+		}
+		return &link, nil
+	}
+	return nil, ErrShareInvalid
+}
+
+// RecordShareAccess appends a request made with a share link to the access log
+func (h *Hub) RecordShareAccess(link *ShareLink, view, remoteAddr, userAgent string) error {
+	data, err := json.Marshal(ShareAccess{
+		LinkID:     link.ID,
+		GoalID:     link.GoalID,
+		At:         time.Now(),
+		View:       view,
+		RemoteAddr: remoteAddr,
+		UserAgent:  userAgent,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.sharesMu.Lock()
+	defer h.sharesMu.Unlock()
+	if err := os.MkdirAll(h.sharesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create shares directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(h.sharesDir(), "access.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open share access log: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// GetShareAccessLog returns the requests made with a goal's share links (one
+// link's when linkID is set), newest first. limit <= 0 returns all of them.
+func (h *Hub) GetShareAccessLog(goalID, linkID string, limit int) ([]ShareAccess, error) {
+	h.sharesMu.Lock()
+	defer h.sharesMu.Unlock()
+
+	result := []ShareAccess{}
+	f, err := os.Open(filepath.Join(h.sharesDir(), "access.jsonl"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to read share access log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a ShareAccess
+		if json.Unmarshal(scanner.Bytes(), &a) != nil {
+			continue
+		}
+		if a.GoalID == goalID && (linkID == "" || a.LinkID == linkID) {
+			result = append(result, a)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read share access log: %w", err)
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// signShareToken returns a link's token: its ID and expiry, signed together
+// with its goal so a token can't be replayed for another goal or a later expiry
+func signShareToken(secret []byte, link *ShareLink) string {
+	payload := link.ID + "." + strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload + "." + link.GoalID))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// shareSecret returns the key share tokens are signed with, creating it on
+// first use. Deleting it invalidates every share link. Caller holds sharesMu.
+func (h *Hub) shareSecret() ([]byte, error) {
+	path := filepath.Join(h.sharesDir(), "secret")
+	if data, err := os.ReadFile(path); err == nil {
+		if secret, err := hex.DecodeString(strings.TrimSpace(string(data))); err == nil && len(secret) >= 32 {
+			return secret, nil
+		}
+		return nil, fmt.Errorf("share secret %s is malformed", path)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read share secret: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate share secret: %w", err)
+	}
+	if err := os.MkdirAll(h.sharesDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create shares directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(secret)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save share secret: %w", err)
+	}
+	return secret, nil
+}
+
+// readShareLinks returns every goal's share links, oldest first (caller holds sharesMu)
+func (h *Hub) readShareLinks() ([]ShareLink, error) {
+	var links []ShareLink
+	data, err := os.ReadFile(filepath.Join(h.sharesDir(), "links.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return links, nil
+		}
+		return nil, fmt.Errorf("failed to read share links: %w", err)
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("failed to parse share links: %w", err)
+	}
+	return links, nil
+}
+
+// writeShareLinks stores the share links atomically (caller holds sharesMu)
+func (h *Hub) writeShareLinks(links []ShareLink) error {
+	if err := os.MkdirAll(h.sharesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create shares directory: %w", err)
+	}
+	// Access counts come from the log, not the stored links
+	stored := make([]ShareLink, len(links))
+	for i, link := range links {
+		link.Accesses = 0
+		link.LastAccessAt = nil
+		stored[i] = link
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal share links: %w", err)
+	}
+	path := filepath.Join(h.sharesDir(), "links.json")
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write share links: %w", err)
+	}
+	return nil
+}
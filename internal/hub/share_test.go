@@ -0,0 +1,82 @@
+package hub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareLinks(t *testing.T) {
+	h := New(t.TempDir())
+
+	link, token, err := h.CreateShareLink("abc1234", "Product team", "alice", 0)
+	if err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+	if d := time.Until(link.ExpiresAt); d < DefaultShareTTL-time.Minute || d > DefaultShareTTL {
+		t.Errorf("expected the default expiry, got %s", d)
+	}
+	if _, _, err := h.CreateShareLink("abc1234", "", "alice", MaxShareTTL+time.Hour); err == nil {
+		t.Error("expected an error for an expiry past MaxShareTTL")
+	}
+
+	resolved, err := h.ResolveShareToken(token)
+	if err != nil || resolved.ID != link.ID || resolved.GoalID != "abc1234" {
+		t.Fatalf("ResolveShareToken = %+v, %v", resolved, err)
+	}
+
+	// Tampering with the expiry or signature, or guessing, doesn't work
+	parts := strings.Split(token, ".")
+	forged := []string{
+		parts[0] + ".9999999999." + parts[2],
+		parts[0] + "." + parts[1] + ".AAAA",
+		"s-000000000000." + parts[1] + "." + parts[2],
+		"",
+	}
+	for _, f := range forged {
+		if _, err := h.ResolveShareToken(f); !errors.Is(err, ErrShareInvalid) {
+			t.Errorf("ResolveShareToken(%q): expected ErrShareInvalid, got %v", f, err)
+		}
+	}
+
+	h.RecordShareAccess(resolved, "detail", "10.0.0.1:5000", "curl/8")
+	h.RecordShareAccess(resolved, "chat", "10.0.0.1:5000", "curl/8")
+	accesses, err := h.GetShareAccessLog("abc1234", link.ID, 0)
+	if err != nil || len(accesses) != 2 || accesses[0].View != "chat" {
+		t.Fatalf("GetShareAccessLog = %+v, %v", accesses, err)
+	}
+
+	other, _, _ := h.CreateShareLink("def5678", "", "bob", time.Hour)
+	links, err := h.GetShareLinks("abc1234")
+	if err != nil || len(links) != 1 || links[0].Accesses != 2 || links[0].LastAccessAt == nil {
+		t.Fatalf("GetShareLinks = %+v, %v", links, err)
+	}
+	if _, err := h.RevokeShareLink("abc1234", other.ID, "alice"); !errors.Is(err, ErrShareNotFound) {
+		t.Errorf("expected ErrShareNotFound revoking another goal's link, got %v", err)
+	}
+
+	revoked, err := h.RevokeShareLink("abc1234", link.ID, "alice")
+	if err != nil || revoked.RevokedAt == nil || revoked.RevokedBy != "alice" {
+		t.Fatalf("RevokeShareLink = %+v, %v", revoked, err)
+	}
+	if _, err := h.ResolveShareToken(token); !errors.Is(err, ErrShareRevoked) {
+		t.Errorf("expected ErrShareRevoked after revoking, got %v", err)
+	}
+
+	// A new hub on the same directory keeps the secret and the links
+	h2 := New(h.dir)
+	expiring, token, _ := h2.CreateShareLink("abc1234", "", "alice", time.Hour)
+	if _, err := h.ResolveShareToken(token); err != nil {
+		t.Fatalf("ResolveShareToken from the first hub: %v", err)
+	}
+
+	// Past its expiry the link is refused, even with a correctly signed token
+	all, _ := h.readShareLinks()
+	all[len(all)-1].ExpiresAt = time.Now().Add(-time.Minute).Truncate(time.Second)
+	h.writeShareLinks(all)
+	secret, _ := h.shareSecret()
+	if _, err := h.ResolveShareToken(signShareToken(secret, &all[len(all)-1])); !errors.Is(err, ErrShareExpired) {
+		t.Errorf("expected ErrShareExpired for %s, got %v", expiring.ID, err)
+	}
+}
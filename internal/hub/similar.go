@@ -0,0 +1,185 @@
+package hub
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// DefaultSimilarGoalLimit is the number of similar goals returned when no limit is given
+const DefaultSimilarGoalLimit = 5
+
+// minSimilarGoalScore is the lowest similarity reported as a possible duplicate
+const minSimilarGoalScore = 0.5
+
+// SimilarityProvider scores how alike each candidate text is to a query,
+// from 0 (unrelated) to 1 (same). TokenSimilarity is the default; an
+// embedding model can be plugged in with EmbeddingSimilarity.
+type SimilarityProvider interface {
+	Similarity(query string, candidates []string) ([]float64, error)
+}
+
+// TokenSimilarity compares the significant words of two texts (Jaccard)
+type TokenSimilarity struct{}
+
+// Similarity implements SimilarityProvider
+func (TokenSimilarity) Similarity(query string, candidates []string) ([]float64, error) {
+	tokens := tokenize(query)
+	normalized := normalizeText(query)
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		if normalizeText(c) == normalized {
+			scores[i] = 1
+			continue
+		}
+		scores[i] = jaccard(tokens, tokenize(c))
+	}
+	return scores, nil
+}
+
+// EmbeddingSimilarity scores texts by the cosine similarity of their
+// embeddings. Embed returns one vector per text, in order.
+type EmbeddingSimilarity struct {
+	Embed func(texts []string) ([][]float64, error)
+}
+
+// Similarity implements SimilarityProvider
+func (e EmbeddingSimilarity) Similarity(query string, candidates []string) ([]float64, error) {
+	vectors, err := e.Embed(append([]string{query}, candidates...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed goals: %w", err)
+	}
+	if len(vectors) != len(candidates)+1 {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(candidates)+1, len(vectors))
+	}
+	scores := make([]float64, len(candidates))
+	for i := range candidates {
+		scores[i] = math.Max(0, cosine(vectors[0], vectors[i+1]))
+	}
+	return scores, nil
+}
+
+// cosine returns the cosine similarity of two vectors
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// SetSimilarityProvider replaces how goals are compared when looking for
+// duplicates. A nil provider restores TokenSimilarity.
+func (h *Hub) SetSimilarityProvider(p SimilarityProvider) {
+	if p == nil {
+		p = TokenSimilarity{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.similarity = p
+}
+
+// SimilarGoal is an existing goal that may duplicate a new one
+type SimilarGoal struct {
+	GoalID   string   `json:"goal_id"`
+	Title    string   `json:"title"`
+	Projects []string `json:"projects,omitempty"`
+	Status   string   `json:"status"` // "active", "iced" or "completed"
+	Archived bool     `json:"archived,omitempty"`
+	Score    float64  `json:"score"` // 0-1
+}
+
+// FindSimilarGoals returns active, iced and completed goals whose title or
+// overview resembles text, most similar first. Goals in exclude (such as the
+// new goal's parent) are skipped.
+func (h *Hub) FindSimilarGoals(text string, limit int, exclude ...string) ([]SimilarGoal, error) {
+	if limit <= 0 {
+		limit = DefaultSimilarGoalLimit
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	parser := goals.NewParser(h.dir)
+	registered, err := parser.ParseRegistry()
+	if err != nil {
+		return nil, err
+	}
+	completed, err := parser.ListCompletedGoals(goals.CompletedGoalFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*SimilarGoal)
+	var order []string
+	add := func(g goals.Goal, archived bool) {
+		if containsString(exclude, g.ID) {
+			return
+		}
+		if existing, ok := byID[g.ID]; ok {
+			existing.Archived = existing.Archived || archived
+			return
+		}
+		byID[g.ID] = &SimilarGoal{GoalID: g.ID, Title: g.Title, Projects: g.Projects, Status: g.Status, Archived: archived}
+		order = append(order, g.ID)
+	}
+	for _, g := range registered {
+		add(g, false)
+	}
+	for _, g := range completed {
+		add(g.Goal, g.Archived)
+	}
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	// Compare against the title alone and with the overview, so a short
+	// title isn't diluted by a long overview
+	titles := make([]string, len(order))
+	full := make([]string, len(order))
+	for i, id := range order {
+		titles[i] = byID[id].Title
+		full[i] = titles[i]
+		if detail, err := parser.ParseGoalDetail(id); err == nil && detail.Overview != "" {
+			full[i] += "\n" + detail.Overview
+		}
+	}
+
+	h.mu.RLock()
+	provider := h.similarity
+	h.mu.RUnlock()
+	scores, err := provider.Similarity(text, append(titles, full...))
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) != 2*len(order) {
+		return nil, fmt.Errorf("similarity provider returned %d scores for %d goals", len(scores), 2*len(order))
+	}
+
+	var similar []SimilarGoal
+	for i, id := range order {
+		score := math.Max(scores[i], scores[len(order)+i])
+		if score < minSimilarGoalScore {
+			continue
+		}
+		g := *byID[id]
+		g.Score = math.Round(score*100) / 100
+		similar = append(similar, g)
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
@@ -0,0 +1,61 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestFindSimilarGoals(t *testing.T) {
+	dir := t.TempDir()
+	registry := goals.NewRegistry(dir)
+	for _, e := range []goals.RegistryEntry{
+		{ID: "aaa1111", Title: "Add caching layer to the API", Projects: []string{"api"}, Status: "active"},
+		{ID: "bbb2222", Title: "Rewrite the docs site", Projects: []string{"docs"}, Status: "iced"},
+		{ID: "ccc3333", Title: "Speed up requests", Projects: []string{"api"}, Status: "active"},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Archived goal missing from the registry, matched on its overview
+	archived := filepath.Join(dir, "goals", "history", "archive", "ddd4444")
+	os.MkdirAll(archived, 0755)
+	os.WriteFile(filepath.Join(archived, "ddd4444.md"), []byte("# Goal #ddd4444: Response caching\n\n## Overview\n\nCache API responses in memory\n"), 0644)
+	h := New(dir)
+
+	similar, err := h.FindSimilarGoals("API caching layer", 0)
+	if err != nil {
+		t.Fatalf("FindSimilarGoals: %v", err)
+	}
+	if len(similar) != 1 || similar[0].GoalID != "aaa1111" || similar[0].Status != "active" || similar[0].Score < 0.5 {
+		t.Fatalf("expected the caching goal, got %+v", similar)
+	}
+
+	similar, _ = h.FindSimilarGoals("Cache API responses in memory", 0)
+	if len(similar) != 1 || similar[0].GoalID != "ddd4444" || !similar[0].Archived || similar[0].Status != "completed" {
+		t.Errorf("expected the archived goal matched on its overview, got %+v", similar)
+	}
+
+	if similar, _ := h.FindSimilarGoals("Add caching layer to the API", 0, "aaa1111"); len(similar) != 0 {
+		t.Errorf("expected excluded goals skipped, got %+v", similar)
+	}
+
+	// A pluggable provider replaces the token comparison
+	h.SetSimilarityProvider(EmbeddingSimilarity{Embed: func(texts []string) ([][]float64, error) {
+		vectors := make([][]float64, len(texts))
+		for i, text := range texts {
+			vectors[i] = []float64{0, 1}
+			if text == "Speed up requests" || text == "Make the API faster" {
+				vectors[i] = []float64{1, 0}
+			}
+		}
+		return vectors, nil
+	}})
+	similar, _ = h.FindSimilarGoals("Make the API faster", 0)
+	if len(similar) != 1 || similar[0].GoalID != "ccc3333" || similar[0].Score != 1 {
+		t.Errorf("expected the embedding match, got %+v", similar)
+	}
+}
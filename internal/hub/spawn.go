@@ -3,11 +3,17 @@ package hub
 import (
 	"crypto/rand"
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
 	"strings"
+	"syscall"
+
+	"github.com/lasmarois/vega-hub/internal/credentials"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // ValidModes defines the allowed executor modes
@@ -35,6 +41,11 @@ type SpawnRequest struct {
 	Mode    string `json:"mode,omitempty"`    // Executor mode: plan, implement, review, test, security, quick
 	Meta    bool   `json:"meta,omitempty"`    // If true, spawn as meta-executor in goal folder
 	Project string `json:"project,omitempty"` // Project name for project executor (required if not meta)
+	Worker  string `json:"worker,omitempty"`  // Remote worker to run on (see .vega-hub-workers.json)
+	Resume  string `json:"resume,omitempty"`  // Session to continue with claude --resume: a session ID or ResumeLast
+
+	// Run the executor in a container (nil runs it directly on the host)
+	Container *ContainerOptions `json:"container,omitempty"`
 }
 
 // SpawnResult contains the result of spawning an executor
@@ -46,6 +57,12 @@ type SpawnResult struct {
 	SessionID    string `json:"session_id,omitempty"`
 	User         string `json:"user,omitempty"`          // Username who spawned this executor
 	ExecutorType string `json:"executor_type,omitempty"` // "meta" or "project"
+	Worker       string `json:"worker,omitempty"`        // Remote worker the executor runs on
+	Container    string `json:"container,omitempty"`     // Container name for containerized executors
+	ResumedFrom  string `json:"resumed_from,omitempty"`  // Session whose Claude session the executor continues
+
+	// Failed pre-flight checks on the goal worktree; they don't block the spawn
+	Preflight *PreflightResult `json:"preflight,omitempty"`
 }
 
 // SpawnExecutor spawns a new Claude executor for a goal.
@@ -64,6 +81,26 @@ func (h *Hub) SpawnExecutor(req SpawnRequest) SpawnResult {
 		}
 	}
 
+	if req.Worker != "" && req.Container != nil {
+		return SpawnResult{
+			Success: false,
+			Message: "worker and container are mutually exclusive",
+		}
+	}
+
+	// Resolve the remote worker up front
+	var worker *Worker
+	if req.Worker != "" {
+		w, err := h.GetWorker(req.Worker)
+		if err != nil {
+			return SpawnResult{
+				Success: false,
+				Message: "Failed to resolve worker: " + err.Error(),
+			}
+		}
+		worker = w
+	}
+
 	// Lock spawn to prevent concurrent spawns for same goal
 	h.spawnMu.Lock()
 	defer h.spawnMu.Unlock()
@@ -114,6 +151,25 @@ func (h *Hub) SpawnExecutor(req SpawnRequest) SpawnResult {
 		}
 	}
 
+	// Continue an earlier session's conversation instead of starting fresh
+	var prior *ExecutorSession
+	if req.Resume != "" {
+		prior, err = h.resumableSession(req.GoalID, req.Resume, workDir)
+		if err != nil {
+			return SpawnResult{
+				Success: false,
+				Message: "Cannot resume session: " + err.Error(),
+			}
+		}
+	}
+
+	// Monorepo projects work in their subdirectory of the worktree
+	execDir := workDir
+	var pathInRepo string
+	if !req.Meta {
+		execDir, pathInRepo = h.projectScopeDir(req.Project, workDir)
+	}
+
 	// Generate session ID for tracking
 	sessionID := generateSessionID()
 
@@ -131,40 +187,98 @@ func (h *Hub) SpawnExecutor(req SpawnRequest) SpawnResult {
 		prompt = req.Context
 	}
 
+	// Hand the executor its context pack (goal, tasks, Q&A, docs, prior sessions)
+	pack := h.BuildContextPack(req.GoalID, sessionID, execDir)
+
 	// Build the command
 	args := []string{
 		"--allowedTools", "Read,Write,Edit,Bash,Skill,Glob,Grep,Task,AskUserQuestion",
 		"--permission-mode", "dontAsk",
-		"-p", prompt,
+		"--append-system-prompt", pack.Text,
 	}
-
-	// Spawn Claude in the background
-	// exec.Command inherits environment from vega-hub process,
-	// so executor runs as the same user with same PATH/HOME/etc.
-	cmd := exec.Command("claude", args...)
-	cmd.Dir = workDir
-
-	// Build environment: inherit current env + inject vega-hub vars
-	// This allows executor hooks to communicate with vega-hub and know their role/mode
-	env := os.Environ()
-	if h.port > 0 {
-		env = append(env, fmt.Sprintf("VEGA_HUB_PORT=%d", h.port))
+	if prior != nil {
+		args = append(args, "--resume", prior.ClaudeSessionID)
 	}
+	args = append(args, "-p", prompt)
+
+	// Build vega-hub vars: executor hooks use them to communicate with
+	// vega-hub and know their role/mode
+	var vegaEnv []string
 	// Inject executor type (meta/project) for hook role detection
-	env = append(env, fmt.Sprintf("VEGA_EXECUTOR_TYPE=%s", executorType))
+	vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_EXECUTOR_TYPE=%s", executorType))
 	// Inject goal ID
-	env = append(env, fmt.Sprintf("VEGA_GOAL_ID=%s", req.GoalID))
+	vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_GOAL_ID=%s", req.GoalID))
+	// Inject the session ID the hooks report as
+	vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_SESSION_ID=%s", sessionID))
+	// Scope the executor's hook requests to its own goal and session
+	token := h.IssueExecutorToken(req.GoalID, sessionID)
+	vegaEnv = append(vegaEnv, "VEGA_HUB_TOKEN="+token.Token)
+	started := false
+	defer func() {
+		if !started {
+			h.RevokeExecutorTokens(sessionID)
+		}
+	}()
 	// Inject mode if specified (validated before spawn)
 	if req.Mode != "" {
-		env = append(env, fmt.Sprintf("VEGA_EXECUTOR_MODE=%s", req.Mode))
+		vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_EXECUTOR_MODE=%s", req.Mode))
 		// Also keep legacy VEGA_HUB_MODE for backwards compatibility
-		env = append(env, fmt.Sprintf("VEGA_HUB_MODE=%s", req.Mode))
+		vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_HUB_MODE=%s", req.Mode))
 	}
 	// Inject project for project executors
 	if req.Project != "" {
-		env = append(env, fmt.Sprintf("VEGA_PROJECT=%s", req.Project))
+		vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_PROJECT=%s", req.Project))
+	}
+	// Inject the project's subdirectory for monorepo projects
+	if pathInRepo != "" {
+		vegaEnv = append(vegaEnv, fmt.Sprintf("VEGA_PROJECT_PATH=%s", pathInRepo))
+	}
+	// Commit and push as the spawning user rather than the server's git config
+	if identity, err := credentials.NewIdentityStore(h.dir).Get(username); err == nil {
+		vegaEnv = append(vegaEnv, identity.Env()...)
+		if !req.Meta {
+			if err := identity.ApplyToWorktree(workDir); err != nil {
+				log.Printf("[SPAWN] Warning: could not set git identity in %s: %v", workDir, err)
+			}
+		}
+	}
+
+	var cmd *exec.Cmd
+	var container *containerRun
+	if req.Container != nil {
+		// Isolate the executor in a container with the working directory mounted
+		opts, err := h.resolveContainerOptions(*req.Container, req.Project)
+		if err != nil {
+			return SpawnResult{
+				Success: false,
+				Message: "Invalid container options: " + err.Error(),
+			}
+		}
+		cmd, container = h.containerCommand(opts, sessionID, workDir, execDir, args, vegaEnv)
+	} else if worker != nil {
+		// Run on the worker over SSH; output and hook traffic come back through the connection
+		cmd, err = h.remoteCommand(worker, execDir, args, vegaEnv)
+		if err != nil {
+			return SpawnResult{
+				Success: false,
+				Message: "Failed to build remote command: " + err.Error(),
+			}
+		}
+	} else {
+		// Spawn Claude in the background
+		// exec.Command inherits environment from vega-hub process,
+		// so executor runs as the same user with same PATH/HOME/etc.
+		cmd = exec.Command("claude", args...)
+		cmd.Dir = execDir
+		// Own process group so kill/pause reach the executor's child processes too
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+		env := os.Environ()
+		if h.port > 0 {
+			env = append(env, fmt.Sprintf("VEGA_HUB_PORT=%d", h.port))
+		}
+		cmd.Env = append(env, vegaEnv...)
 	}
-	cmd.Env = env
 
 	// Redirect output to log file
 	logFile := filepath.Join(workDir, ".executor-output.log")
@@ -188,15 +302,35 @@ func (h *Hub) SpawnExecutor(req SpawnRequest) SpawnResult {
 		}
 	}
 
+	started = true
+
 	// Register executor with vega-hub (don't rely on hooks)
 	h.RegisterExecutor(req.GoalID, sessionID, workDir, username)
+	if prior != nil {
+		h.linkResumedSession(req.GoalID, sessionID, prior)
+	}
+	done := make(chan struct{})
+	h.attachProcess(sessionID, cmd.Process, done)
+	if worker != nil {
+		h.attachWorker(sessionID, worker.Name)
+	}
+	if container != nil {
+		h.attachContainer(sessionID, container)
+	}
 
 	// Monitor process and notify when done
 	go func() {
 		cmd.Wait()
+		close(done)
 		outFile.Close()
+		// Containers are started with --rm, but a killed client can leave one behind
+		cleanupContainer(container)
 		// Notify vega-hub that executor stopped
-		h.StopExecutor(req.GoalID, sessionID, "completed")
+		h.StopExecutor(req.GoalID, sessionID, h.exitReason(sessionID))
+		h.RevokeExecutorTokens(sessionID)
+		if !req.Meta {
+			h.checkSessionCommits(req.GoalID, sessionID, req.Project, workDir)
+		}
 	}()
 
 	// Build result
@@ -207,11 +341,26 @@ func (h *Hub) SpawnExecutor(req SpawnRequest) SpawnResult {
 		User:         username,
 		ExecutorType: executorType,
 	}
+	if worker != nil {
+		result.Message = fmt.Sprintf("%s executor spawned for Goal #%s on worker %s (ssh PID: %d)", executorType, req.GoalID, worker.Name, cmd.Process.Pid)
+		result.Worker = worker.Name
+	}
+	if container != nil {
+		result.Message = fmt.Sprintf("%s executor spawned for Goal #%s in container %s (PID: %d)", executorType, req.GoalID, container.Name, cmd.Process.Pid)
+		result.Container = container.Name
+	}
+	if prior != nil {
+		result.ResumedFrom = prior.SessionID
+		result.Message += fmt.Sprintf(", resuming session %s", prior.SessionID)
+	}
 
 	if req.Meta {
 		result.GoalFolder = workDir
 	} else {
 		result.Worktree = workDir
+		if preflight := NewPreflightChecker(workDir, "", "").RunChecks(SpawnChecks); !preflight.Ready {
+			result.Preflight = preflight
+		}
 	}
 
 	return result
@@ -226,7 +375,7 @@ func generateSessionID() string {
 
 // findWorktree finds any worktree directory for a goal (legacy behavior)
 func (h *Hub) findWorktree(goalID string) (string, error) {
-	workspacesDir := filepath.Join(h.dir, "workspaces")
+	workspacesDir := h.Layout().WorkspacesDir()
 
 	// List all project directories
 	projects, err := os.ReadDir(workspacesDir)
@@ -248,7 +397,7 @@ func (h *Hub) findWorktree(goalID string) (string, error) {
 		}
 
 		for _, entry := range entries {
-			if entry.IsDir() && strings.HasPrefix(entry.Name(), goalPrefix) {
+			if strings.HasPrefix(entry.Name(), goalPrefix) && isDirEntry(projectPath, entry) {
 				return filepath.Join(projectPath, entry.Name()), nil
 			}
 		}
@@ -259,7 +408,7 @@ func (h *Hub) findWorktree(goalID string) (string, error) {
 
 // findWorktreeForProject finds the worktree directory for a specific goal+project combination
 func (h *Hub) findWorktreeForProject(goalID, project string) (string, error) {
-	projectPath := filepath.Join(h.dir, "workspaces", project)
+	projectPath := h.Layout().ProjectWorkspace(project)
 
 	entries, err := os.ReadDir(projectPath)
 	if err != nil {
@@ -269,7 +418,7 @@ func (h *Hub) findWorktreeForProject(goalID, project string) (string, error) {
 	goalPrefix := fmt.Sprintf("goal-%s-", goalID)
 
 	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), goalPrefix) {
+		if strings.HasPrefix(entry.Name(), goalPrefix) && isDirEntry(projectPath, entry) {
 			return filepath.Join(projectPath, entry.Name()), nil
 		}
 	}
@@ -277,18 +426,78 @@ func (h *Hub) findWorktreeForProject(goalID, project string) (string, error) {
 	return "", fmt.Errorf("no worktree found for goal %s in project %s", goalID, project)
 }
 
+// isDirEntry reports whether entry of dir is a directory or a link to one
+// (plain projects link goal workspaces to the project folder)
+func isDirEntry(dir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+// projectScopeDir returns where a project executor starts in worktree and the
+// project's path in the repository. Monorepo projects start in their
+// subdirectory; others (or a subdirectory missing on the goal branch) start at
+// the worktree root. project defaults to the worktree's workspace.
+func (h *Hub) projectScopeDir(project, worktree string) (string, string) {
+	if project == "" {
+		project = filepath.Base(filepath.Dir(worktree))
+	}
+	p, err := goals.ParseProject(h.dir, project)
+	if err != nil || p.PathInRepo == "" {
+		return worktree, ""
+	}
+	dir := p.ScopeDir(worktree)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return worktree, ""
+	}
+	return dir, p.PathInRepo
+}
+
+// checkSessionCommits checks the goal branch against the project's commit
+// policy after an executor session, for projects that ask for session checks,
+// and broadcasts any violations so they are fixed before completion
+func (h *Hub) checkSessionCommits(goalID, sessionID, project, worktree string) {
+	if project == "" {
+		project = filepath.Base(filepath.Dir(worktree))
+	}
+	p, err := goals.ParseProject(h.dir, project)
+	if err != nil || !p.ChecksSessions() || p.BaseBranch == "" || p.IsPlain() {
+		return
+	}
+	violations, err := p.CheckCommits(worktree, p.BaseBranch)
+	if err != nil {
+		log.Printf("[COMMIT-POLICY] Failed to check commits for goal %s: %v", goalID, err)
+		return
+	}
+	if len(violations) == 0 {
+		return
+	}
+
+	log.Printf("[COMMIT-POLICY] Goal %s: %d violation(s) after session %s", goalID, len(violations), sessionID)
+	h.broadcast(Event{
+		Type: EventCommitPolicyViolated,
+		Data: map[string]interface{}{
+			"goal_id":    goalID,
+			"session_id": sessionID,
+			"project":    project,
+			"violations": violations,
+		},
+	})
+}
+
 // findGoalFolder finds the goal folder for a meta-executor
 // Goal folders are in: goals/active/<goal-id>/
 func (h *Hub) findGoalFolder(goalID string) (string, error) {
 	// Check folder structure first: goals/active/<goal-id>/
-	folderPath := filepath.Join(h.dir, "goals", "active", goalID)
+	folderPath := h.Layout().GoalFolder(layout.ActiveDir, goalID)
 	if info, err := os.Stat(folderPath); err == nil && info.IsDir() {
 		return folderPath, nil
 	}
 
 	// Check if flat file exists (goals/active/<goal-id>.md) - create folder structure
-	flatFilePath := filepath.Join(h.dir, "goals", "active", goalID+".md")
-	if _, err := os.Stat(flatFilePath); err == nil {
+	if h.Layout().FindGoalFileIn(layout.ActiveDir, goalID) != "" {
 		// Goal exists as flat file - need to create folder structure for meta-executor
 		if err := os.MkdirAll(folderPath, 0755); err != nil {
 			return "", fmt.Errorf("failed to create goal folder: %w", err)
@@ -3,6 +3,7 @@ package hub
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -173,3 +174,71 @@ func TestValidExecutorTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectScopeDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	h := &Hub{dir: tmpDir}
+	worktree := filepath.Join(tmpDir, "workspaces", "mono", "goal-abc1234-fix")
+	os.MkdirAll(filepath.Join(worktree, "services", "api"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "projects", "mono.md"), []byte("# Project: mono\n\n**Path In Repo**: `services/api`\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "projects", "gone.md"), []byte("# Project: gone\n\n**Path In Repo**: `services/gone`\n"), 0644)
+
+	dir, sub := h.projectScopeDir("", worktree)
+	if dir != filepath.Join(worktree, "services", "api") || sub != "services/api" {
+		t.Errorf("expected services/api scope, got %s (%s)", dir, sub)
+	}
+	if !strings.Contains(h.sessionContext("abc1234", dir), "Scope: services/api") {
+		t.Error("session context should mention the scope")
+	}
+
+	// Subdirectory missing on the goal branch: fall back to the worktree root
+	if dir, sub := h.projectScopeDir("gone", worktree); dir != worktree || sub != "" {
+		t.Errorf("expected worktree root, got %s (%s)", dir, sub)
+	}
+	if strings.Contains(h.sessionContext("abc1234", worktree), "Scope:") {
+		t.Error("worktree root should have no scope line")
+	}
+}
+
+func TestResumableSession(t *testing.T) {
+	h := setupTestHub(t)
+	workDir := t.TempDir()
+
+	h.RegisterExecutor("abc1234", "s1", workDir, "alice")
+	h.StopExecutorWithClaudeInfo(StopExecutorRequest{GoalID: "abc1234", SessionID: "s1", ClaudeSessionID: "claude-1", Reason: "completed"})
+	h.RegisterExecutor("abc1234", "s2", workDir, "alice")
+	h.StopExecutor("abc1234", "s2", "killed")
+	h.RegisterExecutor("abc1234", "s3", t.TempDir(), "alice")
+	h.StopExecutorWithClaudeInfo(StopExecutorRequest{GoalID: "abc1234", SessionID: "s3", ClaudeSessionID: "claude-3", Reason: "completed"})
+
+	prior, err := h.resumableSession("abc1234", ResumeLast, workDir)
+	if err != nil || prior.SessionID != "s1" {
+		t.Fatalf("expected s1 as the last resumable session in the worktree, got %+v (err: %v)", prior, err)
+	}
+	if prior, err := h.resumableSession("abc1234", "claude-1", workDir); err != nil || prior.SessionID != "s1" {
+		t.Errorf("expected s1 by its Claude session ID, got %+v (err: %v)", prior, err)
+	}
+	for _, ref := range []string{"s2", "s3", "missing"} {
+		if _, err := h.resumableSession("abc1234", ref, workDir); err == nil {
+			t.Errorf("expected %s refused", ref)
+		}
+	}
+
+	// The resumed session keeps the Claude session when it stops without hooks
+	h.RegisterExecutor("abc1234", "s4", workDir, "alice")
+	h.linkResumedSession("abc1234", "s4", prior)
+	h.StopExecutor("abc1234", "s4", "completed")
+
+	sessions, err := h.history.loadGoalHistory("abc1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s4 := findSession(sessions, "s4")
+	if s4 == nil || s4.ResumedFrom != "s1" || s4.ClaudeSessionID != "claude-1" {
+		t.Errorf("expected s4 linked to s1 in history, got %+v", s4)
+	}
+	if prior, err := h.resumableSession("abc1234", ResumeLast, workDir); err != nil || prior.SessionID != "s4" {
+		t.Errorf("expected s4 as the new last session, got %+v (err: %v)", prior, err)
+	}
+}
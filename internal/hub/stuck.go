@@ -0,0 +1,119 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// DefaultStuckThreshold is how long a goal can stay in a transient state
+// (branching, pushing, merging) before it counts as stuck
+const DefaultStuckThreshold = time.Hour
+
+// StuckConfig is the on-disk format of <vega-dir>/.vega-hub-stuck.json
+type StuckConfig struct {
+	Default string            `json:"default,omitempty"` // Go duration, defaults to 1h
+	States  map[string]string `json:"states,omitempty"`  // Go duration by state, e.g. "merging": "15m"
+}
+
+// Validate checks the durations and that only transient states are given
+func (c *StuckConfig) Validate() error {
+	if c.Default != "" {
+		if d, err := time.ParseDuration(c.Default); err != nil || d <= 0 {
+			return fmt.Errorf("invalid default %q", c.Default)
+		}
+	}
+	for state, value := range c.States {
+		if !isStuckState(goals.GoalState(state)) {
+			return fmt.Errorf("%s: goals can only be stuck in %v", state, goals.StuckStates)
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid threshold %q", state, value)
+		}
+	}
+	return nil
+}
+
+// Thresholds returns the threshold of each state a goal can get stuck in
+func (c *StuckConfig) Thresholds() map[goals.GoalState]time.Duration {
+	def := DefaultStuckThreshold
+	if d, err := time.ParseDuration(c.Default); err == nil && d > 0 {
+		def = d
+	}
+	thresholds := make(map[goals.GoalState]time.Duration, len(goals.StuckStates))
+	for _, state := range goals.StuckStates {
+		thresholds[state] = def
+		if d, err := time.ParseDuration(c.States[string(state)]); err == nil && d > 0 {
+			thresholds[state] = d
+		}
+	}
+	return thresholds
+}
+
+func isStuckState(state goals.GoalState) bool {
+	for _, s := range goals.StuckStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// stuckConfigPath returns the stuck threshold config path
+func stuckConfigPath(dir string) string {
+	return filepath.Join(dir, ".vega-hub-stuck.json")
+}
+
+// LoadStuckConfig reads <vega-dir>/.vega-hub-stuck.json.
+// A missing file means every state uses DefaultStuckThreshold.
+func LoadStuckConfig(dir string) (*StuckConfig, error) {
+	cfg := &StuckConfig{}
+	data, err := os.ReadFile(stuckConfigPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read stuck threshold config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse stuck threshold config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("stuck threshold config: %w", err)
+	}
+	return cfg, nil
+}
+
+// stuckThresholds returns the configured thresholds of dir. A broken config
+// is logged and the defaults are used, so stuck goals are still found.
+func stuckThresholds(dir string) map[goals.GoalState]time.Duration {
+	cfg, err := LoadStuckConfig(dir)
+	if err != nil {
+		log.Printf("[STUCK] %v; using the default threshold of %v", err, DefaultStuckThreshold)
+		cfg = &StuckConfig{}
+	}
+	return cfg.Thresholds()
+}
+
+// StuckThresholds returns how long goals may stay in each transient state
+// before they count as stuck (see LoadStuckConfig)
+func (h *Hub) StuckThresholds() map[goals.GoalState]time.Duration {
+	return stuckThresholds(h.dir)
+}
+
+// FindStuckGoals returns goals that have been in a transient state for
+// longer than its configured threshold
+func (h *Hub) FindStuckGoals() ([]goals.StuckGoal, error) {
+	return h.stateManager.StuckGoalsByState(h.StuckThresholds())
+}
+
+// GetStuckGoalsByState returns goals that have been in one of the states in
+// thresholds for longer than its threshold
+func (h *Hub) GetStuckGoalsByState(thresholds map[goals.GoalState]time.Duration) ([]goals.StuckGoal, error) {
+	return h.stateManager.StuckGoalsByState(thresholds)
+}
@@ -0,0 +1,257 @@
+package hub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/lasmarois/vega-hub/internal/pathguard"
+)
+
+// DefaultSuggestionLimit is the number of suggestions returned when no limit is given
+const DefaultSuggestionLimit = 5
+
+// minSuggestionScore is the lowest similarity considered a match
+const minSuggestionScore = 0.2
+
+// AnswerSuggestion is a previously-given answer to a similar question
+type AnswerSuggestion struct {
+	Answer     string    `json:"answer"`
+	OptionIDs  []string  `json:"option_ids,omitempty"` // Options on the current question matching this answer
+	Score      float64   `json:"score"`                // Similarity of the closest past question (0-1)
+	Count      int       `json:"count"`                // How many times this answer was given to similar questions
+	Question   string    `json:"question"`             // Closest past question
+	GoalID     string    `json:"goal_id"`              // Goal of the closest past question
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// libraryEntry is a past Q&A exchange mined from history
+type libraryEntry struct {
+	goalID    string
+	question  string
+	answer    string
+	tokens    map[string]bool
+	timestamp time.Time
+}
+
+// libraryFile caches the Q&A entries of one goal's history
+type libraryFile struct {
+	stamp   string // Size and modification time of the goal's history files
+	entries []libraryEntry
+}
+
+// AnswerLibrary is the library of past answers mined from session history.
+// History files are re-read only when they change.
+type AnswerLibrary struct {
+	mu      sync.Mutex
+	history *SessionHistory
+	files   map[string]*libraryFile // goal ID -> cached entries
+}
+
+// NewAnswerLibrary creates an answer library backed by session history
+func NewAnswerLibrary(history *SessionHistory) *AnswerLibrary {
+	return &AnswerLibrary{
+		history: history,
+		files:   make(map[string]*libraryFile),
+	}
+}
+
+// entries returns all answered questions across all goals, refreshing changed
+// files. A goal's history may be split into the part compressed by retention
+// (goal-<id>.jsonl.gz) and the live file; both are read.
+func (l *AnswerLibrary) entries() ([]libraryEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(l.history.historyDir(), "goal-*.jsonl*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history files: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen := make(map[string]bool, len(paths))
+	var all []libraryEntry
+	for _, path := range paths {
+		goalID := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "goal-"), ".gz"), ".jsonl")
+		if seen[goalID] || pathguard.ValidateID("goal ID", goalID) != nil {
+			continue
+		}
+		seen[goalID] = true
+
+		stamp := l.stamp(goalID)
+		cached, ok := l.files[goalID]
+		if !ok || cached.stamp != stamp {
+			cached = &libraryFile{stamp: stamp, entries: l.load(goalID)}
+			l.files[goalID] = cached
+		}
+		all = append(all, cached.entries...)
+	}
+
+	// Drop goals whose history was removed
+	for goalID := range l.files {
+		if !seen[goalID] {
+			delete(l.files, goalID)
+		}
+	}
+
+	return all, nil
+}
+
+// stamp identifies the current version of a goal's history files
+func (l *AnswerLibrary) stamp(goalID string) string {
+	path := l.history.historyFile(goalID)
+	var parts []string
+	for _, p := range []string{path + ".gz", path} {
+		if info, err := os.Stat(p); err == nil {
+			parts = append(parts, fmt.Sprintf("%d@%d", info.Size(), info.ModTime().UnixNano()))
+		} else {
+			parts = append(parts, "-")
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// load reads the answered questions from a goal's history
+func (l *AnswerLibrary) load(goalID string) []libraryEntry {
+	history, err := l.history.GetGoalHistory(goalID, 0)
+	if err != nil {
+		return nil
+	}
+
+	var entries []libraryEntry
+	for _, e := range history {
+		if e.Type != "question" || e.Question == "" || strings.TrimSpace(e.Answer) == "" {
+			continue
+		}
+		entries = append(entries, libraryEntry{
+			goalID:    e.GoalID,
+			question:  e.Question,
+			answer:    e.Answer,
+			tokens:    tokenize(e.Question),
+			timestamp: e.Timestamp,
+		})
+	}
+	return entries
+}
+
+// Suggest returns the best past answers for a question, most relevant first
+func (l *AnswerLibrary) Suggest(q *Question, limit int) ([]AnswerSuggestion, error) {
+	if limit <= 0 {
+		limit = DefaultSuggestionLimit
+	}
+
+	entries, err := l.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := tokenize(q.Question)
+	normalized := normalizeText(q.Question)
+
+	// Group matches by answer so repeated answers rank higher
+	byAnswer := make(map[string]*AnswerSuggestion)
+	for _, e := range entries {
+		score := jaccard(tokens, e.tokens)
+		if normalizeText(e.question) == normalized {
+			score = 1
+		}
+		if score < minSuggestionScore {
+			continue
+		}
+
+		key := normalizeText(e.answer)
+		s, ok := byAnswer[key]
+		if !ok {
+			s = &AnswerSuggestion{Answer: e.answer}
+			byAnswer[key] = s
+		}
+		s.Count++
+		if score > s.Score || (score == s.Score && e.timestamp.After(s.LastUsedAt)) {
+			s.Score = score
+			s.Question = e.question
+			s.GoalID = e.goalID
+		}
+		if e.timestamp.After(s.LastUsedAt) {
+			s.LastUsedAt = e.timestamp
+		}
+	}
+
+	suggestions := make([]AnswerSuggestion, 0, len(byAnswer))
+	for _, s := range byAnswer {
+		if opt := q.findOptionByLabel(s.Answer); opt != nil {
+			s.OptionIDs = []string{opt.ID}
+		}
+		suggestions = append(suggestions, *s)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.LastUsedAt.After(b.LastUsedAt)
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// findOptionByLabel returns the option whose label matches text (case-insensitive), or nil
+func (q *Question) findOptionByLabel(text string) *Option {
+	text = strings.TrimSpace(text)
+	for i := range q.Options {
+		if strings.EqualFold(q.Options[i].Label, text) {
+			return &q.Options[i]
+		}
+	}
+	return nil
+}
+
+// stopWords are ignored when comparing questions
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "in": true, "on": true,
+	"for": true, "and": true, "or": true, "is": true, "it": true, "i": true, "we": true,
+	"should": true, "do": true, "does": true, "can": true, "be": true, "this": true,
+	"that": true, "with": true, "you": true, "what": true, "which": true,
+}
+
+// tokenize splits text into a set of lowercase significant words
+func tokenize(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !stopWords[word] {
+			tokens[word] = true
+		}
+	}
+	return tokens
+}
+
+// normalizeText lowercases and collapses whitespace for exact comparisons
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// jaccard returns the Jaccard similarity of two token sets
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
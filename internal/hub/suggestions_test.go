@@ -0,0 +1,115 @@
+package hub
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenizeAndJaccard(t *testing.T) {
+	a := tokenize("Should I run the unit tests?")
+	b := tokenize("Run unit tests now")
+
+	if !a["run"] || !a["unit"] || !a["tests"] || a["the"] || a["should"] {
+		t.Errorf("unexpected tokens: %v", a)
+	}
+	if got := jaccard(a, b); got != 0.75 {
+		t.Errorf("expected similarity 0.75, got %v", got)
+	}
+	if got := jaccard(a, map[string]bool{}); got != 0 {
+		t.Errorf("expected 0 similarity with empty set, got %v", got)
+	}
+}
+
+func TestAnswerLibrarySuggest(t *testing.T) {
+	h := setupTestHub(t)
+
+	h.history.RecordQuestion("goal001", "s1", "Should I run the unit tests?", "Yes")
+	h.history.RecordQuestion("goal002", "s2", "Run unit tests before commit?", "yes")
+	h.history.RecordQuestion("goal002", "s2", "Run the unit tests with coverage?", "Only for CI")
+	h.history.RecordQuestion("goal003", "s3", "Which color for the button?", "Blue")
+
+	q := &Question{
+		Question: "Should I run the unit tests?",
+		Options:  []Option{{ID: "opt-1", Label: "Yes"}, {ID: "opt-2", Label: "No"}},
+	}
+	suggestions, err := h.answerLibrary.Suggest(q, 0)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+
+	if len(suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions, got %d: %+v", len(suggestions), suggestions)
+	}
+	top := suggestions[0]
+	if top.Answer != "Yes" || top.Count != 2 || top.Score != 1 || top.GoalID != "goal001" {
+		t.Errorf("unexpected top suggestion: %+v", top)
+	}
+	if len(top.OptionIDs) != 1 || top.OptionIDs[0] != "opt-1" {
+		t.Errorf("expected top suggestion to map to opt-1, got %v", top.OptionIDs)
+	}
+	if suggestions[1].Answer != "Only for CI" {
+		t.Errorf("unexpected second suggestion: %+v", suggestions[1])
+	}
+
+	// New history is picked up on the next call
+	h.history.RecordQuestion("goal004", "s4", "Should I run the unit tests again?", "No")
+	suggestions, _ = h.answerLibrary.Suggest(q, 1)
+	if len(suggestions) != 1 || suggestions[0].Answer != "Yes" {
+		t.Errorf("expected limit to apply, got %+v", suggestions)
+	}
+}
+
+func TestAnswerLibraryReadsCompressedHistory(t *testing.T) {
+	h := setupTestHub(t)
+	writeRetentionConfig(t, h.dir, `{"history":{"compress_after":"24h"}}`)
+
+	h.history.RecordQuestion("goal001", "s1", "Should I run the unit tests?", "Yes")
+	q := &Question{Question: "Should I run the unit tests?"}
+	if suggestions, _ := h.answerLibrary.Suggest(q, 0); len(suggestions) != 1 {
+		t.Fatalf("expected 1 suggestion, got %+v", suggestions)
+	}
+
+	// Compacted to goal-goal001.jsonl.gz, then a new answer in the live file
+	age(t, h.history.historyFile("goal001"), 48*time.Hour)
+	if result, _ := h.CompactStorage(); result.Compressed != 1 {
+		t.Fatalf("expected the history compressed, got %+v", result)
+	}
+	if suggestions, _ := h.answerLibrary.Suggest(q, 0); len(suggestions) != 1 || suggestions[0].Answer != "Yes" {
+		t.Fatalf("expected the compressed answer still suggested, got %+v", suggestions)
+	}
+	h.history.RecordQuestion("goal001", "s2", "Should I run the unit tests first?", "Only for CI")
+	suggestions, _ := h.answerLibrary.Suggest(q, 0)
+	if len(suggestions) != 2 {
+		t.Errorf("expected answers from both history parts, got %+v", suggestions)
+	}
+}
+
+func TestSuggestAnswers(t *testing.T) {
+	h := setupTestHub(t)
+	h.history.RecordQuestion("goal001", "s1", "Deploy to staging?", "Go ahead")
+
+	if _, err := h.SuggestAnswers("missing", 5); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.Ask(&Question{ID: "q-1", GoalID: "goal002", SessionID: "s2", Question: "Deploy to staging now?"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	suggestions, err := h.SuggestAnswers("q-1", 5)
+	if err != nil {
+		t.Fatalf("SuggestAnswers failed: %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Answer != "Go ahead" {
+		t.Errorf("unexpected suggestions: %+v", suggestions)
+	}
+
+	h.Answer("q-1", "Go ahead")
+	wg.Wait()
+}
@@ -0,0 +1,160 @@
+package hub
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultExecutorTokenTTL is how long an executor token stays valid without
+// being used. Every request made with it extends it.
+const DefaultExecutorTokenTTL = 2 * time.Hour
+
+var (
+	// ErrTokenInvalid is returned for missing, unknown, revoked or expired tokens
+	ErrTokenInvalid = errors.New("invalid or expired executor token")
+
+	// ErrTokenScope is returned when a token is used for another goal or session
+	ErrTokenScope = errors.New("executor token is not valid for this goal or session")
+)
+
+// ExecutorAuthMode decides which executor requests need a token
+type ExecutorAuthMode string
+
+const (
+	// ExecutorAuthSpawned requires a token for goals with an executor vega-hub
+	// spawned, so nothing else can act for it (the default)
+	ExecutorAuthSpawned ExecutorAuthMode = "spawned"
+	// ExecutorAuthAll requires a token on every executor request
+	ExecutorAuthAll ExecutorAuthMode = "all"
+	// ExecutorAuthOff ignores tokens, for legacy executors whose hooks don't
+	// send them
+	ExecutorAuthOff ExecutorAuthMode = "off"
+)
+
+// ValidExecutorAuthModes lists the accepted executor auth modes
+var ValidExecutorAuthModes = map[ExecutorAuthMode]bool{
+	ExecutorAuthSpawned: true,
+	ExecutorAuthAll:     true,
+	ExecutorAuthOff:     true,
+}
+
+// ExecutorToken is issued to an executor vega-hub spawns. It is injected as
+// VEGA_HUB_TOKEN and only grants the executor endpoints (ask, stop, pending
+// messages) for its own goal and session.
+type ExecutorToken struct {
+	Token     string    `json:"-"`
+	GoalID    string    `json:"goal_id"`
+	SessionID string    `json:"session_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// executorTokens holds the tokens of running executors
+type executorTokens struct {
+	mu     sync.Mutex
+	tokens map[string]*ExecutorToken // token → scope
+	ttl    time.Duration
+	mode   ExecutorAuthMode
+}
+
+func newExecutorTokens() *executorTokens {
+	return &executorTokens{
+		tokens: make(map[string]*ExecutorToken),
+		ttl:    DefaultExecutorTokenTTL,
+		mode:   ExecutorAuthSpawned,
+	}
+}
+
+// SetExecutorAuth sets which executor requests need a valid token
+func (h *Hub) SetExecutorAuth(mode ExecutorAuthMode) {
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+	h.tokens.mode = mode
+}
+
+// ExecutorAuth returns the executor auth mode
+func (h *Hub) ExecutorAuth() ExecutorAuthMode {
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+	return h.tokens.mode
+}
+
+// ExecutorAuthRequired reports whether executor requests for goalID need a
+// token: always with ExecutorAuthAll, and with ExecutorAuthSpawned while an
+// executor vega-hub spawned for the goal holds a live token
+func (h *Hub) ExecutorAuthRequired(goalID string) bool {
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+	switch h.tokens.mode {
+	case ExecutorAuthAll:
+		return true
+	case ExecutorAuthSpawned:
+		now := time.Now()
+		for _, t := range h.tokens.tokens {
+			if t.GoalID == goalID && now.Before(t.ExpiresAt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetExecutorTokenTTL sets how long an unused executor token stays valid
+func (h *Hub) SetExecutorTokenTTL(ttl time.Duration) {
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+	h.tokens.ttl = ttl
+}
+
+// IssueExecutorToken creates a token scoped to one executor session
+func (h *Hub) IssueExecutorToken(goalID, sessionID string) *ExecutorToken {
+	b := make([]byte, 32)
+	rand.Read(b)
+
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+	token := &ExecutorToken{
+		Token:     hex.EncodeToString(b),
+		GoalID:    goalID,
+		SessionID: sessionID,
+		ExpiresAt: time.Now().Add(h.tokens.ttl),
+	}
+	h.tokens.tokens[token.Token] = token
+	return token
+}
+
+// ValidateExecutorToken checks that a token is live and scoped to goalID and
+// sessionID, and extends it. It returns the token's scope.
+func (h *Hub) ValidateExecutorToken(token, goalID, sessionID string) (*ExecutorToken, error) {
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+
+	t, ok := h.tokens.tokens[token]
+	if !ok || token == "" {
+		return nil, ErrTokenInvalid
+	}
+	now := time.Now()
+	if now.After(t.ExpiresAt) {
+		delete(h.tokens.tokens, token)
+		return nil, ErrTokenInvalid
+	}
+	if t.GoalID != goalID || t.SessionID != sessionID {
+		return nil, ErrTokenScope
+	}
+	t.ExpiresAt = now.Add(h.tokens.ttl)
+	scope := *t
+	return &scope, nil
+}
+
+// RevokeExecutorTokens invalidates the tokens issued to a session
+func (h *Hub) RevokeExecutorTokens(sessionID string) {
+	h.tokens.mu.Lock()
+	defer h.tokens.mu.Unlock()
+	for token, t := range h.tokens.tokens {
+		if t.SessionID == sessionID {
+			delete(h.tokens.tokens, token)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecutorTokens(t *testing.T) {
+	h := New(t.TempDir())
+	token := h.IssueExecutorToken("abc1234", "session-1")
+
+	scope, err := h.ValidateExecutorToken(token.Token, "abc1234", "session-1")
+	if err != nil || scope.SessionID != "session-1" {
+		t.Fatalf("expected the token valid for its goal, got %+v (%v)", scope, err)
+	}
+	if _, err := h.ValidateExecutorToken(token.Token, "def5678", "session-1"); !errors.Is(err, ErrTokenScope) {
+		t.Errorf("expected ErrTokenScope for another goal, got %v", err)
+	}
+	if _, err := h.ValidateExecutorToken(token.Token, "abc1234", "session-2"); !errors.Is(err, ErrTokenScope) {
+		t.Errorf("expected ErrTokenScope for another session of the goal, got %v", err)
+	}
+	if _, err := h.ValidateExecutorToken("", "abc1234", "session-1"); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected ErrTokenInvalid without a token, got %v", err)
+	}
+
+	if !h.ExecutorAuthRequired("abc1234") || h.ExecutorAuthRequired("def5678") {
+		t.Error("expected a token required only for the goal with a spawned executor")
+	}
+	h.SetExecutorAuth(ExecutorAuthAll)
+	if !h.ExecutorAuthRequired("def5678") {
+		t.Error("expected a token required for every goal")
+	}
+	h.SetExecutorAuth(ExecutorAuthOff)
+	if h.ExecutorAuthRequired("abc1234") {
+		t.Error("expected no token required with executor auth off")
+	}
+	h.SetExecutorAuth(ExecutorAuthSpawned)
+
+	h.RevokeExecutorTokens("session-1")
+	if _, err := h.ValidateExecutorToken(token.Token, "abc1234", "session-1"); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected a revoked token rejected, got %v", err)
+	}
+	if h.ExecutorAuthRequired("abc1234") {
+		t.Error("expected no token required once the executor's token is revoked")
+	}
+}
+
+func TestExecutorTokenExpiry(t *testing.T) {
+	h := New(t.TempDir())
+	h.SetExecutorTokenTTL(50 * time.Millisecond)
+	token := h.IssueExecutorToken("abc1234", "session-1")
+
+	// Each use extends the token
+	for i := 0; i < 3; i++ {
+		time.Sleep(30 * time.Millisecond)
+		if _, err := h.ValidateExecutorToken(token.Token, "abc1234", "session-1"); err != nil {
+			t.Fatalf("expected the token extended by use, got %v", err)
+		}
+	}
+	time.Sleep(80 * time.Millisecond)
+	if _, err := h.ValidateExecutorToken(token.Token, "abc1234", "session-1"); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected an idle token expired, got %v", err)
+	}
+}
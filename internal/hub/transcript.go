@@ -0,0 +1,383 @@
+package hub
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// maxTranscriptText caps the text stored per transcript entry (tool output can be huge)
+const maxTranscriptText = 8 * 1024
+
+// ErrTranscriptNotFound is returned when a session has no ingested transcript
+var ErrTranscriptNotFound = errors.New("transcript not found")
+
+// Transcript entry kinds
+const (
+	TranscriptText       = "text"        // User or assistant message text
+	TranscriptToolCall   = "tool_call"   // Assistant tool invocation
+	TranscriptToolResult = "tool_result" // Result returned to the assistant
+)
+
+// fileEditTools are tools whose calls modify the file in input.file_path
+var fileEditTools = map[string]bool{
+	"Edit":         true,
+	"MultiEdit":    true,
+	"Write":        true,
+	"NotebookEdit": true,
+}
+
+// TranscriptEntry is one normalized item from a Claude transcript
+type TranscriptEntry struct {
+	Index     int       `json:"index"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Role      string    `json:"role"` // "user" or "assistant"
+	Kind      string    `json:"kind"` // "text", "tool_call", "tool_result"
+	Text      string    `json:"text,omitempty"`
+	Truncated bool      `json:"truncated,omitempty"`
+	ToolName  string    `json:"tool_name,omitempty"`
+	ToolID    string    `json:"tool_id,omitempty"`
+	Summary   string    `json:"summary,omitempty"`   // One-line description of a tool call
+	FilePath  string    `json:"file_path,omitempty"` // File touched by a tool call
+	Command   string    `json:"command,omitempty"`   // Full shell command for Bash calls
+	IsError   bool      `json:"is_error,omitempty"`
+}
+
+// TranscriptSummary aggregates a session transcript
+type TranscriptSummary struct {
+	Entries           int            `json:"entries"`
+	UserMessages      int            `json:"user_messages"`
+	AssistantMessages int            `json:"assistant_messages"`
+	ToolCalls         int            `json:"tool_calls"`
+	ToolErrors        int            `json:"tool_errors"`
+	ToolCounts        map[string]int `json:"tool_counts,omitempty"`
+	FilesEdited       []string       `json:"files_edited,omitempty"`
+	StartedAt         *time.Time     `json:"started_at,omitempty"`
+	EndedAt           *time.Time     `json:"ended_at,omitempty"`
+}
+
+// rawTranscriptLine is the subset of a Claude transcript JSONL line we read
+type rawTranscriptLine struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// rawContentBlock is one block of a message's content array
+type rawContentBlock struct {
+	Type      string                 `json:"type"`
+	Text      string                 `json:"text"`
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Input     map[string]interface{} `json:"input"`
+	ToolUseID string                 `json:"tool_use_id"`
+	Content   json.RawMessage        `json:"content"`
+	IsError   bool                   `json:"is_error"`
+}
+
+// transcriptFile returns the normalized transcript path for a session
+func (h *SessionHistory) transcriptFile(goalID, sessionID string) string {
+	return filepath.Join(h.historyDir(), "transcripts", goalID, sessionID+".jsonl")
+}
+
+// IngestTranscript parses a Claude transcript JSONL and stores a normalized copy
+// for the session. Returns the stored entries.
+func (h *SessionHistory) IngestTranscript(goalID, sessionID, transcriptPath string) ([]TranscriptEntry, error) {
+	src, err := os.Open(transcriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer src.Close()
+
+	entries, err := ParseTranscript(src)
+	if err != nil {
+		return nil, err
+	}
+
+	path := h.transcriptFile(goalID, sessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create transcript dir: %w", err)
+	}
+
+	err = atomicfile.Write(path, 0644, func(file *os.File) error {
+		w := bufio.NewWriter(file)
+		enc := json.NewEncoder(w)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write transcript: %w", err)
+	}
+	// Drop a copy compressed by retention before the re-ingest
+	os.Remove(path + ".gz")
+	return entries, nil
+}
+
+// GetTranscript returns the normalized transcript entries for a session
+func (h *SessionHistory) GetTranscript(goalID, sessionID string) ([]TranscriptEntry, error) {
+	path := h.transcriptFile(goalID, sessionID)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// Transcripts compressed by retention
+		file, err = os.Open(path + ".gz")
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrTranscriptNotFound, sessionID)
+		}
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(file.Name(), ".gz") {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript: %w", err)
+		}
+		r = zr
+	}
+
+	var entries []TranscriptEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e TranscriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return entries, nil
+}
+
+// ParseTranscript normalizes a Claude transcript JSONL stream.
+// Lines that aren't user/assistant messages (summaries, metadata) are skipped.
+func ParseTranscript(r io.Reader) ([]TranscriptEntry, error) {
+	var entries []TranscriptEntry
+	add := func(e TranscriptEntry) {
+		e.Index = len(entries)
+		e.Text, e.Truncated = truncateTranscriptText(e.Text)
+		entries = append(entries, e)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line rawTranscriptLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Type != "user" && line.Type != "assistant" {
+			continue
+		}
+		role := line.Message.Role
+		if role == "" {
+			role = line.Type
+		}
+
+		// Content is either a plain string or an array of blocks
+		var text string
+		if err := json.Unmarshal(line.Message.Content, &text); err == nil {
+			if strings.TrimSpace(text) != "" {
+				add(TranscriptEntry{Timestamp: line.Timestamp, Role: role, Kind: TranscriptText, Text: text})
+			}
+			continue
+		}
+
+		var blocks []rawContentBlock
+		if err := json.Unmarshal(line.Message.Content, &blocks); err != nil {
+			continue
+		}
+		for _, b := range blocks {
+			switch b.Type {
+			case "text":
+				if strings.TrimSpace(b.Text) != "" {
+					add(TranscriptEntry{Timestamp: line.Timestamp, Role: role, Kind: TranscriptText, Text: b.Text})
+				}
+			case "tool_use":
+				filePath, _ := b.Input["file_path"].(string)
+				if filePath == "" {
+					filePath, _ = b.Input["notebook_path"].(string)
+				}
+				command, _ := b.Input["command"].(string)
+				add(TranscriptEntry{
+					Timestamp: line.Timestamp,
+					Role:      role,
+					Kind:      TranscriptToolCall,
+					ToolName:  b.Name,
+					ToolID:    b.ID,
+					Summary:   summarizeToolCall(b.Name, b.Input),
+					FilePath:  filePath,
+					Command:   command,
+				})
+			case "tool_result":
+				add(TranscriptEntry{
+					Timestamp: line.Timestamp,
+					Role:      role,
+					Kind:      TranscriptToolResult,
+					ToolID:    b.ToolUseID,
+					Text:      toolResultText(b.Content),
+					IsError:   b.IsError,
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	// Attach tool names to results so they can be filtered/displayed on their own
+	names := make(map[string]string)
+	for _, e := range entries {
+		if e.Kind == TranscriptToolCall {
+			names[e.ToolID] = e.ToolName
+		}
+	}
+	for i := range entries {
+		if entries[i].Kind == TranscriptToolResult {
+			entries[i].ToolName = names[entries[i].ToolID]
+		}
+	}
+
+	return entries, nil
+}
+
+// SummarizeTranscript aggregates tool usage and edited files
+func SummarizeTranscript(entries []TranscriptEntry) *TranscriptSummary {
+	s := &TranscriptSummary{Entries: len(entries), ToolCounts: make(map[string]int)}
+	edited := make(map[string]bool)
+
+	for i := range entries {
+		e := &entries[i]
+		if !e.Timestamp.IsZero() {
+			if s.StartedAt == nil || e.Timestamp.Before(*s.StartedAt) {
+				s.StartedAt = &e.Timestamp
+			}
+			if s.EndedAt == nil || e.Timestamp.After(*s.EndedAt) {
+				s.EndedAt = &e.Timestamp
+			}
+		}
+
+		switch e.Kind {
+		case TranscriptText:
+			if e.Role == "assistant" {
+				s.AssistantMessages++
+			} else {
+				s.UserMessages++
+			}
+		case TranscriptToolCall:
+			s.ToolCalls++
+			s.ToolCounts[e.ToolName]++
+			if fileEditTools[e.ToolName] && e.FilePath != "" && !edited[e.FilePath] {
+				edited[e.FilePath] = true
+				s.FilesEdited = append(s.FilesEdited, e.FilePath)
+			}
+		case TranscriptToolResult:
+			if e.IsError {
+				s.ToolErrors++
+			}
+		}
+	}
+
+	sort.Strings(s.FilesEdited)
+	return s
+}
+
+// summarizeToolCall describes a tool call in one line
+func summarizeToolCall(name string, input map[string]interface{}) string {
+	str := func(key string) string {
+		v, _ := input[key].(string)
+		return v
+	}
+
+	var detail string
+	switch name {
+	case "Bash":
+		detail = str("command")
+	case "Read", "Edit", "MultiEdit", "Write":
+		detail = str("file_path")
+	case "NotebookEdit":
+		detail = str("notebook_path")
+	case "Grep", "Glob":
+		detail = str("pattern")
+	case "WebFetch":
+		detail = str("url")
+	case "WebSearch":
+		detail = str("query")
+	case "Task":
+		detail = str("description")
+	}
+
+	detail = strings.Join(strings.Fields(detail), " ")
+	if len(detail) > 120 {
+		detail = detail[:117] + "..."
+	}
+	if detail == "" {
+		return name
+	}
+	return name + ": " + detail
+}
+
+// toolResultText flattens a tool_result content (string or text blocks)
+func toolResultText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var blocks []rawContentBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// truncateTranscriptText caps entry text at maxTranscriptText bytes
+func truncateTranscriptText(text string) (string, bool) {
+	if len(text) <= maxTranscriptText {
+		return text, false
+	}
+	return truncateUTF8(text, maxTranscriptText), true
+}
+
+// truncateUTF8 cuts text to at most max bytes without splitting a character
+func truncateUTF8(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
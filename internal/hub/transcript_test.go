@@ -0,0 +1,103 @@
+package hub
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleTranscript = `{"type":"summary","summary":"Fix login"}
+{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"Fix the login bug"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Looking at the handler."},{"type":"tool_use","id":"tu-1","name":"Read","input":{"file_path":"/src/login.go"}}]}}
+{"type":"user","timestamp":"2026-01-02T10:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tu-1","content":"package login"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:10Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"tu-2","name":"Edit","input":{"file_path":"/src/login.go","old_string":"a","new_string":"b"}},{"type":"tool_use","id":"tu-3","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-01-02T10:00:20Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tu-2","content":[{"type":"text","text":"ok"}]},{"type":"tool_result","tool_use_id":"tu-3","content":"FAIL","is_error":true}]}}
+not json
+`
+
+func TestParseTranscript(t *testing.T) {
+	entries, err := ParseTranscript(strings.NewReader(sampleTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	if len(entries) != 8 {
+		t.Fatalf("expected 8 entries, got %d: %+v", len(entries), entries)
+	}
+
+	if entries[0].Kind != TranscriptText || entries[0].Role != "user" || entries[0].Text != "Fix the login bug" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if e := entries[2]; e.Kind != TranscriptToolCall || e.Summary != "Read: /src/login.go" || e.FilePath != "/src/login.go" {
+		t.Errorf("unexpected tool call entry: %+v", e)
+	}
+	if e := entries[3]; e.Kind != TranscriptToolResult || e.ToolName != "Read" || e.Text != "package login" {
+		t.Errorf("unexpected tool result entry: %+v", e)
+	}
+	if e := entries[6]; e.Text != "ok" {
+		t.Errorf("expected text blocks flattened, got %+v", e)
+	}
+	for i, e := range entries {
+		if e.Index != i {
+			t.Errorf("entry %d has index %d", i, e.Index)
+		}
+	}
+
+	summary := SummarizeTranscript(entries)
+	if summary.ToolCalls != 3 || summary.ToolErrors != 1 || summary.AssistantMessages != 1 || summary.UserMessages != 1 {
+		t.Errorf("unexpected summary counts: %+v", summary)
+	}
+	if len(summary.FilesEdited) != 1 || summary.FilesEdited[0] != "/src/login.go" {
+		t.Errorf("expected login.go edited, got %v", summary.FilesEdited)
+	}
+	if summary.ToolCounts["Bash"] != 1 || summary.StartedAt == nil || summary.EndedAt == nil {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestParseTranscript_Truncates(t *testing.T) {
+	long := strings.Repeat("é", maxTranscriptText)
+	line := `{"type":"user","message":{"role":"user","content":"` + long + `"}}`
+
+	entries, err := ParseTranscript(strings.NewReader(line))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d (err: %v)", len(entries), err)
+	}
+	if !entries[0].Truncated || len(entries[0].Text) > maxTranscriptText {
+		t.Errorf("expected truncated text, got %d bytes (truncated=%v)", len(entries[0].Text), entries[0].Truncated)
+	}
+	if !strings.HasSuffix(entries[0].Text, "é") {
+		t.Error("truncation split a multi-byte character")
+	}
+}
+
+func TestStopExecutorIngestsTranscript(t *testing.T) {
+	h := setupTestHub(t)
+
+	transcriptPath := filepath.Join(t.TempDir(), "claude.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte(sampleTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.GetSessionTranscript("abc1234", "session-001"); !errors.Is(err, ErrTranscriptNotFound) {
+		t.Errorf("expected ErrTranscriptNotFound before ingest, got %v", err)
+	}
+
+	h.RegisterExecutor("abc1234", "session-001", t.TempDir(), "user")
+	h.StopExecutorWithClaudeInfo(StopExecutorRequest{
+		GoalID:          "abc1234",
+		SessionID:       "session-001",
+		ClaudeSessionID: "claude-1",
+		TranscriptPath:  transcriptPath,
+		Reason:          "completed",
+	})
+
+	entries, err := h.GetSessionTranscript("abc1234", "session-001")
+	if err != nil {
+		t.Fatalf("GetSessionTranscript failed: %v", err)
+	}
+	if len(entries) != 8 || entries[4].ToolName != "Edit" {
+		t.Errorf("unexpected stored transcript: %+v", entries)
+	}
+}
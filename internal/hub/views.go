@@ -0,0 +1,290 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// ErrViewNotFound is returned when a saved view doesn't exist for the user
+var ErrViewNotFound = errors.New("view not found")
+
+// View sort orders. Only newest and oldest apply outside the web UI, which
+// knows executor status and pending questions.
+var validViewSorts = map[string]bool{"": true, "newest": true, "oldest": true, "status": true, "questions": true, "position": true}
+
+// ViewFilter selects goals. Empty fields match everything.
+type ViewFilter struct {
+	Statuses []string `json:"statuses,omitempty"` // "active", "iced", "completed"
+	Projects []string `json:"projects,omitempty"`
+	Search   string   `json:"search,omitempty"` // Case-insensitive match on ID or title
+}
+
+// Matches returns true if the goal passes the filter
+func (f ViewFilter) Matches(g goals.Goal) bool {
+	if len(f.Statuses) > 0 && !containsString(f.Statuses, g.Status) {
+		return false
+	}
+	if len(f.Projects) > 0 {
+		found := false
+		for _, p := range g.Projects {
+			if containsString(f.Projects, p) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(g.Title), search) && !strings.Contains(strings.ToLower(g.ID), search) {
+			return false
+		}
+	}
+	return true
+}
+
+// SavedView is a named filter and sort order owned by a user
+type SavedView struct {
+	ID        string     `json:"id"`
+	User      string     `json:"user"`
+	Name      string     `json:"name"`
+	Filter    ViewFilter `json:"filter"`
+	Sort      string     `json:"sort,omitempty"`    // "newest", "oldest", "status", "questions", "position"
+	Default   bool       `json:"default,omitempty"` // Opened by default for the user
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate checks a view has a name and a known sort order
+func (v *SavedView) Validate() error {
+	if v.User == "" {
+		return fmt.Errorf("user is required")
+	}
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !validViewSorts[v.Sort] {
+		return fmt.Errorf("invalid sort %q", v.Sort)
+	}
+	for _, s := range v.Filter.Statuses {
+		switch s {
+		case "active", "iced", "completed":
+		default:
+			return fmt.Errorf("invalid status %q", s)
+		}
+	}
+	return nil
+}
+
+// SavedViews stores users' saved views in <vega-dir>/.vega-hub-views.json,
+// shared by the web UI and the CLI
+type SavedViews struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// savedViewsFile is the on-disk format
+type savedViewsFile struct {
+	Views []*SavedView `json:"views"`
+}
+
+// NewSavedViews creates a view store for the vega-missile directory
+func NewSavedViews(dir string) *SavedViews {
+	return &SavedViews{dir: dir}
+}
+
+func (s *SavedViews) path() string {
+	return filepath.Join(s.dir, ".vega-hub-views.json")
+}
+
+// load reads all views (caller holds lock)
+func (s *SavedViews) load() ([]*SavedView, error) {
+	data, err := os.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read views: %w", err)
+	}
+	var file savedViewsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse views: %w", err)
+	}
+	return file.Views, nil
+}
+
+// save writes the views atomically (caller holds lock)
+func (s *SavedViews) save(views []*SavedView) error {
+	data, err := json.MarshalIndent(savedViewsFile{Views: views}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal views: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write views: %w", err)
+	}
+	return nil
+}
+
+// List returns a user's views sorted by name
+func (s *SavedViews) List(user string) ([]*SavedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	result := []*SavedView{}
+	for _, v := range views {
+		if v.User == user {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+// Get returns a user's view by ID or name
+func (s *SavedViews) Get(user, idOrName string) (*SavedView, error) {
+	views, err := s.List(user)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if v.ID == idOrName {
+			return v, nil
+		}
+	}
+	for _, v := range views {
+		if strings.EqualFold(v.Name, idOrName) {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrViewNotFound, idOrName)
+}
+
+// Default returns the user's default view (ErrViewNotFound if none is set)
+func (s *SavedViews) Default(user string) (*SavedView, error) {
+	views, err := s.List(user)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if v.Default {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no default view for %s", ErrViewNotFound, user)
+}
+
+// Add validates and stores a new view, assigning an ID. View names are
+// unique per user.
+func (s *SavedViews) Add(view *SavedView) error {
+	if err := view.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views, err := s.load()
+	if err != nil {
+		return err
+	}
+	for _, v := range views {
+		if v.User == view.User && strings.EqualFold(v.Name, view.Name) {
+			return fmt.Errorf("view %q already exists", view.Name)
+		}
+	}
+
+	view.ID = fmt.Sprintf("view-%d", time.Now().UnixNano())
+	view.CreatedAt = time.Now()
+	view.UpdatedAt = view.CreatedAt
+	if view.Default {
+		clearDefault(views, view.User)
+	}
+	return s.save(append(views, view))
+}
+
+// Update replaces the name, filter, sort and default flag of a user's view
+func (s *SavedViews) Update(user, id string, update *SavedView) (*SavedView, error) {
+	update.User = user
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var view *SavedView
+	for _, v := range views {
+		if v.User != user {
+			continue
+		}
+		if v.ID == id {
+			view = v
+		} else if strings.EqualFold(v.Name, update.Name) {
+			return nil, fmt.Errorf("view %q already exists", update.Name)
+		}
+	}
+	if view == nil {
+		return nil, fmt.Errorf("%w: %s", ErrViewNotFound, id)
+	}
+
+	if update.Default {
+		clearDefault(views, user)
+	}
+	view.Name = update.Name
+	view.Filter = update.Filter
+	view.Sort = update.Sort
+	view.Default = update.Default
+	view.UpdatedAt = time.Now()
+	if err := s.save(views); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// Delete removes a user's view
+func (s *SavedViews) Delete(user, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, v := range views {
+		if v.User == user && v.ID == id {
+			return s.save(append(views[:i], views[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrViewNotFound, id)
+}
+
+// clearDefault unsets the default flag on a user's views
+func clearDefault(views []*SavedView, user string) {
+	for _, v := range views {
+		if v.User == user {
+			v.Default = false
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestViewFilterMatches(t *testing.T) {
+	g := goals.Goal{ID: "abc1234", Title: "Backend auth refactor", Projects: []string{"api"}, Status: "active"}
+
+	tests := []struct {
+		filter ViewFilter
+		want   bool
+	}{
+		{ViewFilter{}, true},
+		{ViewFilter{Statuses: []string{"active", "iced"}}, true},
+		{ViewFilter{Statuses: []string{"completed"}}, false},
+		{ViewFilter{Projects: []string{"web", "api"}}, true},
+		{ViewFilter{Projects: []string{"web"}}, false},
+		{ViewFilter{Search: "AUTH"}, true},
+		{ViewFilter{Search: "abc12"}, true},
+		{ViewFilter{Search: "frontend"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Matches(g); got != tt.want {
+			t.Errorf("%+v.Matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestSavedViews(t *testing.T) {
+	views := NewSavedViews(t.TempDir())
+
+	backend := &SavedView{User: "alex", Name: "My backend", Filter: ViewFilter{Projects: []string{"api"}}, Default: true}
+	if err := views.Add(backend); err != nil {
+		t.Fatal(err)
+	}
+	if err := views.Add(&SavedView{User: "alex", Name: "my BACKEND"}); err == nil {
+		t.Error("expected duplicate names to be rejected")
+	}
+	if err := views.Add(&SavedView{User: "alex", Name: "Bad", Sort: "random"}); err == nil {
+		t.Error("expected invalid sort to be rejected")
+	}
+	if err := views.Add(&SavedView{User: "sam", Name: "My backend"}); err != nil {
+		t.Errorf("expected names to be unique per user only: %v", err)
+	}
+
+	// Making another view the default clears the old one
+	iced := &SavedView{User: "alex", Name: "Iced", Filter: ViewFilter{Statuses: []string{"iced"}}, Default: true}
+	if err := views.Add(iced); err != nil {
+		t.Fatal(err)
+	}
+	def, err := views.Default("alex")
+	if err != nil || def.ID != iced.ID {
+		t.Errorf("expected Iced to be the default, got %+v (err: %v)", def, err)
+	}
+
+	got, err := views.Get("alex", "my backend")
+	if err != nil || got.ID != backend.ID || got.Default {
+		t.Errorf("expected lookup by name without default flag, got %+v (err: %v)", got, err)
+	}
+	if _, err := views.Get("sam", backend.ID); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("expected other users' views to be hidden, got %v", err)
+	}
+
+	updated, err := views.Update("alex", backend.ID, &SavedView{Name: "Backend", Sort: "newest", Default: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Backend" || updated.Sort != "newest" || updated.CreatedAt.IsZero() {
+		t.Errorf("unexpected update: %+v", updated)
+	}
+	if def, _ := views.Default("alex"); def.ID != backend.ID {
+		t.Errorf("expected Backend to be the default after update, got %+v", def)
+	}
+
+	list, err := views.List("alex")
+	if err != nil || len(list) != 2 || list[0].Name != "Backend" {
+		t.Errorf("expected 2 views sorted by name, got %+v (err: %v)", list, err)
+	}
+
+	if err := views.Delete("alex", iced.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := views.Delete("alex", iced.ID); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("expected ErrViewNotFound, got %v", err)
+	}
+}
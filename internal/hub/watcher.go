@@ -4,53 +4,114 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// Watcher tuning. Changes are collected until the tree has been quiet for
+// watchDebounce (capped at watchMaxDelay during event storms), then reported
+// together: repeated writes to a path collapse into one change.
+const (
+	watchDebounce = 300 * time.Millisecond
+	watchMaxDelay = 2 * time.Second
 )
 
-// StartFileWatcher starts watching the goals directory for changes
-// and broadcasts SSE events when files are modified
+// DefaultWatchIgnore lists directory names the file watcher never descends into
+var DefaultWatchIgnore = []string{"node_modules", "vendor", ".venv", "venv", "__pycache__", "dist", "build", "target", ".next", ".cache"}
+
+// WatcherStatus describes the file watcher, for debugging
+type WatcherStatus struct {
+	Running        bool       `json:"running"`
+	Watched        []string   `json:"watched"` // Directories, relative to the vega dir
+	Ignore         []string   `json:"ignore"`
+	Debounce       string     `json:"debounce"`
+	EventsSeen     int64      `json:"events_seen"`
+	EventsIgnored  int64      `json:"events_ignored"`
+	Batches        int64      `json:"batches"` // Coalesced change events broadcast
+	Pending        int        `json:"pending"` // Paths waiting for the debounce window
+	LastEvent      *time.Time `json:"last_event,omitempty"`
+	LastBatch      *time.Time `json:"last_batch,omitempty"`
+	Errors         int64      `json:"errors"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastBatchGoals []string   `json:"last_batch_goals,omitempty"`
+}
+
+// watcherState is the file watcher's bookkeeping, shared with WatcherStatus
+type watcherState struct {
+	mu      sync.Mutex
+	ignore  []string
+	watched map[string]bool
+	status  WatcherStatus
+}
+
+// SetWatchIgnore replaces the directory name patterns (filepath.Match syntax)
+// the file watcher skips. Must be called before StartFileWatcher.
+func (h *Hub) SetWatchIgnore(patterns []string) {
+	h.watch.mu.Lock()
+	defer h.watch.mu.Unlock()
+	h.watch.ignore = append([]string(nil), patterns...)
+}
+
+// WatcherStatus returns a snapshot of the file watcher's state
+func (h *Hub) WatcherStatus() WatcherStatus {
+	h.watch.mu.Lock()
+	defer h.watch.mu.Unlock()
+	status := h.watch.status
+	status.Ignore = append([]string{}, h.watch.ignore...)
+	status.Debounce = watchDebounce.String()
+	status.Watched = []string{}
+	for dir := range h.watch.watched {
+		if rel, err := filepath.Rel(h.dir, dir); err == nil {
+			dir = rel
+		}
+		status.Watched = append(status.Watched, dir)
+	}
+	sort.Strings(status.Watched)
+	return status
+}
+
+// StartFileWatcher starts watching the goals and projects directories for
+// changes and broadcasts SSE events when files are modified
 func (h *Hub) StartFileWatcher() error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return err
 	}
 
-	// Watch goals directories
-	goalsDir := filepath.Join(h.dir, "goals")
-	dirsToWatch := []string{
-		goalsDir,
-		filepath.Join(goalsDir, "active"),
-		filepath.Join(goalsDir, "iced"),
-		filepath.Join(goalsDir, "history"),
-	}
-
-	for _, dir := range dirsToWatch {
+	// Watch goals directories (including folder-structure goals) and project configs
+	for _, dir := range []string{h.Layout().GoalsDir(), h.Layout().ProjectsDir()} {
 		if _, err := os.Stat(dir); err == nil {
-			if err := watcher.Add(dir); err != nil {
-				log.Printf("[WATCHER] Failed to watch %s: %v", dir, err)
-			} else {
-				log.Printf("[WATCHER] Watching %s", dir)
-			}
+			h.watchTree(watcher, dir)
+			log.Printf("[WATCHER] Watching %s", dir)
 		}
 	}
 
-	// Also watch REGISTRY.md specifically
-	registryPath := filepath.Join(goalsDir, "REGISTRY.md")
-	if _, err := os.Stat(registryPath); err == nil {
-		if err := watcher.Add(registryPath); err != nil {
-			log.Printf("[WATCHER] Failed to watch registry: %v", err)
-		}
-	}
+	// Watch git metadata so cached git answers are dropped when it changes
+	h.watchGitMetadata(watcher)
 
-	// Debounce map to avoid multiple events for same file
-	lastEvent := make(map[string]time.Time)
-	debounceWindow := 500 * time.Millisecond
+	h.watch.mu.Lock()
+	h.watch.status.Running = true
+	h.watch.mu.Unlock()
 
 	go func() {
 		defer watcher.Close()
+		defer func() {
+			h.watch.mu.Lock()
+			h.watch.status.Running = false
+			h.watch.mu.Unlock()
+		}()
+
+		// Changes waiting to be reported, by path
+		pending := make(map[string]fsnotify.Op)
+		var firstPending time.Time
+		var timer *time.Timer
+		var timerC <-chan time.Time
 
 		for {
 			select {
@@ -58,50 +119,270 @@ func (h *Hub) StartFileWatcher() error {
 				if !ok {
 					return
 				}
+				now := time.Now()
+				h.watch.mu.Lock()
+				h.watch.status.EventsSeen++
+				h.watch.status.LastEvent = &now
+				h.watch.mu.Unlock()
 
-				// Only process write/create events on .md files
-				if !isRelevantEvent(event) {
+				// Git metadata changed: drop cached answers for the project's worktrees
+				if workspace := gitEventWorkspace(h.dir, event.Name); workspace != "" {
+					h.git.Invalidate(workspace)
+					// Follow worktrees added after startup
+					if event.Op&fsnotify.Create != 0 && filepath.Base(filepath.Dir(event.Name)) == "worktrees" {
+						if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+							h.addWatch(watcher, event.Name)
+						}
+					}
+					h.ignoredEvent()
 					continue
 				}
 
-				// Debounce - skip if we just saw this file
-				now := time.Now()
-				if last, exists := lastEvent[event.Name]; exists {
-					if now.Sub(last) < debounceWindow {
-						continue
+				// Follow directories created after startup (new folder-structure goals)
+				if event.Op&fsnotify.Create != 0 && !h.ignoredPath(event.Name) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						h.watchTree(watcher, event.Name)
 					}
 				}
-				lastEvent[event.Name] = now
 
-				// Determine what changed
-				goalID := extractGoalID(event.Name)
-				eventType := determineEventType(event.Name)
+				// Only process write/create events on .md files
+				if !isRelevantEvent(event) || h.ignoredPath(event.Name) {
+					h.ignoredEvent()
+					continue
+				}
+
+				if len(pending) == 0 {
+					firstPending = now
+				}
+				pending[event.Name] |= event.Op
 
-				log.Printf("[WATCHER] File changed: %s (goal: %s, type: %s)", event.Name, goalID, eventType)
+				// Wait for a quiet period, but don't hold changes back forever
+				delay := watchDebounce
+				if remaining := watchMaxDelay - now.Sub(firstPending); remaining < delay {
+					delay = remaining
+				}
+				if timer == nil {
+					timer = time.NewTimer(delay)
+				} else {
+					if !timer.Stop() {
+						select {
+						case <-timer.C:
+						default:
+						}
+					}
+					timer.Reset(delay)
+				}
+				timerC = timer.C
+				h.setPending(len(pending))
 
-				// Broadcast the change
-				h.broadcast(Event{
-					Type: eventType,
-					Data: map[string]interface{}{
-						"file":    event.Name,
-						"goal_id": goalID,
-						"action":  event.Op.String(),
-					},
-				})
+			case <-timerC:
+				timerC = nil
+				h.flushChanges(pending)
+				pending = make(map[string]fsnotify.Op)
+				h.setPending(0)
 
 			case err, ok := <-watcher.Errors:
 				if !ok {
 					return
 				}
 				log.Printf("[WATCHER] Error: %v", err)
+				h.watch.mu.Lock()
+				h.watch.status.Errors++
+				h.watch.status.LastError = err.Error()
+				h.watch.mu.Unlock()
 			}
 		}
 	}()
 
-	log.Printf("[WATCHER] File watcher started for %s", goalsDir)
+	log.Printf("[WATCHER] File watcher started for %s", h.Layout().GoalsDir())
 	return nil
 }
 
+// flushChanges broadcasts the collected changes: one event per changed goal
+// (or registry), then a files_changed event listing every affected goal and
+// project so clients can refresh once per batch
+func (h *Hub) flushChanges(pending map[string]fsnotify.Op) {
+	if len(pending) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(pending))
+	for path := range pending {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	seen := make(map[string]bool)
+	goalSet := make(map[string]bool)
+	projectSet := make(map[string]bool)
+	for _, path := range paths {
+		if project := projectFromPath(h.dir, path); project != "" {
+			projectSet[project] = true
+			continue
+		}
+
+		goalID := goalIDFromPath(path)
+		eventType := determineEventType(path)
+		if goalID != "" {
+			goalSet[goalID] = true
+		}
+		key := eventType + "\x00" + goalID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		log.Printf("[WATCHER] File changed: %s (goal: %s, type: %s)", path, goalID, eventType)
+		if eventType == EventGoalUpdated && goalID != "" {
+			h.EmitGoalUpdated(goalID, "file_changed", map[string]interface{}{
+				"file":   path,
+				"action": pending[path].String(),
+			})
+			continue
+		}
+		h.broadcast(Event{
+			Type: eventType,
+			Data: map[string]interface{}{
+				"file":    path,
+				"goal_id": goalID,
+				"action":  pending[path].String(),
+			},
+		})
+	}
+
+	goalIDs := sortedKeys(goalSet)
+	projects := sortedKeys(projectSet)
+	h.broadcast(Event{
+		Type: EventFilesChanged,
+		Data: map[string]interface{}{
+			"goal_ids": goalIDs,
+			"projects": projects,
+			"files":    len(paths),
+		},
+	})
+
+	now := time.Now()
+	h.watch.mu.Lock()
+	h.watch.status.Batches++
+	h.watch.status.LastBatch = &now
+	h.watch.status.LastBatchGoals = goalIDs
+	h.watch.mu.Unlock()
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// watchTree watches dir and its subdirectories, skipping ignored ones
+func (h *Hub) watchTree(watcher *fsnotify.Watcher, root string) {
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && h.ignoredPath(path) {
+			return filepath.SkipDir
+		}
+		h.addWatch(watcher, path)
+		return nil
+	})
+}
+
+// addWatch watches a single directory and records it for WatcherStatus
+func (h *Hub) addWatch(watcher *fsnotify.Watcher, dir string) {
+	if err := watcher.Add(dir); err != nil {
+		log.Printf("[WATCHER] Failed to watch %s: %v", dir, err)
+		return
+	}
+	h.watch.mu.Lock()
+	if h.watch.watched == nil {
+		h.watch.watched = make(map[string]bool)
+	}
+	h.watch.watched[dir] = true
+	h.watch.mu.Unlock()
+}
+
+// ignoredPath returns true if any directory in path (below the vega dir)
+// matches an ignore pattern
+func (h *Hub) ignoredPath(path string) bool {
+	rel, err := filepath.Rel(h.dir, path)
+	if err != nil {
+		rel = path
+	}
+	h.watch.mu.Lock()
+	defer h.watch.mu.Unlock()
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		for _, pattern := range h.watch.ignore {
+			if ok, _ := filepath.Match(pattern, part); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *Hub) ignoredEvent() {
+	h.watch.mu.Lock()
+	h.watch.status.EventsIgnored++
+	h.watch.mu.Unlock()
+}
+
+func (h *Hub) setPending(n int) {
+	h.watch.mu.Lock()
+	h.watch.status.Pending = n
+	h.watch.mu.Unlock()
+}
+
+// watchGitMetadata watches each project's worktree-base .git directory, its
+// branch refs and per-worktree metadata (HEAD, index). Working tree edits
+// aren't watched; cached answers about them expire after gitsvc.DefaultTTL.
+func (h *Hub) watchGitMetadata(watcher *fsnotify.Watcher) {
+	gitDirs, _ := filepath.Glob(filepath.Join(h.Layout().WorkspacesDir(), "*", layout.WorktreeBaseName, ".git"))
+	for _, gitDir := range gitDirs {
+		if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+			continue
+		}
+		dirs := []string{gitDir, filepath.Join(gitDir, "refs", "heads"), filepath.Join(gitDir, "worktrees")}
+		worktrees, _ := filepath.Glob(filepath.Join(gitDir, "worktrees", "*"))
+		for _, dir := range append(dirs, worktrees...) {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				h.addWatch(watcher, dir)
+			}
+		}
+	}
+}
+
+// gitEventWorkspace returns the project workspace (workspaces/<project>) whose
+// worktree-base .git directory contains path, or "" for other paths
+func gitEventWorkspace(vegaDir, path string) string {
+	rel, err := filepath.Rel(vegaDir, path)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 4 || parts[0] != "workspaces" || parts[2] != layout.WorktreeBaseName || parts[3] != ".git" {
+		return ""
+	}
+	return layout.New(vegaDir).ProjectWorkspace(parts[1])
+}
+
+// projectFromPath returns the project whose config file is path
+// (projects/<name>.md), or "" for other paths
+func projectFromPath(vegaDir, path string) string {
+	rel, err := filepath.Rel(vegaDir, path)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 2 || parts[0] != "projects" || parts[1] == "index.md" {
+		return ""
+	}
+	return strings.TrimSuffix(parts[1], ".md")
+}
+
 // isRelevantEvent checks if the event is for a markdown file we care about
 func isRelevantEvent(event fsnotify.Event) bool {
 	// Only care about write and create operations
@@ -109,14 +390,25 @@ func isRelevantEvent(event fsnotify.Event) bool {
 		return false
 	}
 
-	// Only care about .md files
-	if !strings.HasSuffix(event.Name, ".md") {
+	// Only care about .md files and the goal registry
+	if !strings.HasSuffix(event.Name, ".md") && filepath.Base(event.Name) != "registry.jsonl" {
 		return false
 	}
 
 	return true
 }
 
+// goalIDFromPath extracts the goal ID from a goal file or a file in a
+// folder-structure goal, e.g. "goals/active/abc1234/task_plan.md" -> "abc1234"
+func goalIDFromPath(path string) string {
+	dir := filepath.Dir(path)
+	switch filepath.Base(filepath.Dir(dir)) {
+	case "active", "iced", "history":
+		return filepath.Base(dir)
+	}
+	return extractGoalID(path)
+}
+
 // extractGoalID extracts the goal ID from a file path
 // e.g., "/path/goals/active/abc1234.md" -> "abc1234"
 func extractGoalID(path string) string {
@@ -137,19 +429,19 @@ func extractGoalID(path string) string {
 
 // determineEventType determines the SSE event type based on the file path
 func determineEventType(path string) string {
-	if strings.Contains(path, "REGISTRY.md") {
+	if strings.Contains(path, "REGISTRY.md") || filepath.Base(path) == "registry.jsonl" {
 		return "registry_updated"
 	}
 
-	if strings.Contains(path, filepath.Join("goals", "active")) {
+	if strings.Contains(path, filepath.Join("goals", layout.ActiveDir)) {
 		return "goal_updated"
 	}
 
-	if strings.Contains(path, filepath.Join("goals", "iced")) {
+	if strings.Contains(path, filepath.Join("goals", layout.IcedDir)) {
 		return "goal_iced"
 	}
 
-	if strings.Contains(path, filepath.Join("goals", "history")) {
+	if strings.Contains(path, filepath.Join("goals", layout.HistoryDir)) {
 		return "goal_completed"
 	}
 
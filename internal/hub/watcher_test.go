@@ -28,6 +28,87 @@ func TestExtractGoalID(t *testing.T) {
 	}
 }
 
+func TestGoalIDFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/vega/goals/active/abc1234.md", "abc1234"},
+		{"/vega/goals/active/abc1234/task_plan.md", "abc1234"},
+		{"/vega/goals/history/abc1234/abc1234.md", "abc1234"},
+		{"/vega/goals/REGISTRY.md", ""},
+		{"/vega/goals/registry.jsonl", ""},
+	}
+
+	for _, tt := range tests {
+		result := goalIDFromPath(tt.path)
+		if result != tt.expected {
+			t.Errorf("goalIDFromPath(%q) = %q, want %q", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestProjectFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/vega/projects/api.md", "api"},
+		{"/vega/projects/index.md", ""},
+		{"/vega/goals/active/api.md", ""},
+	}
+
+	for _, tt := range tests {
+		result := projectFromPath("/vega", tt.path)
+		if result != tt.expected {
+			t.Errorf("projectFromPath(%q) = %q, want %q", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestIgnoredPath(t *testing.T) {
+	h := New("/vega")
+	h.SetWatchIgnore([]string{"node_modules", ".venv*"})
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/vega/goals/active/abc1234/node_modules/pkg/README.md", true},
+		{"/vega/goals/active/abc1234/.venv-3.12", true},
+		{"/vega/goals/active/abc1234/task_plan.md", false},
+		{"/vega/projects/node.md", false},
+	}
+
+	for _, tt := range tests {
+		if result := h.ignoredPath(tt.path); result != tt.expected {
+			t.Errorf("ignoredPath(%q) = %v, want %v", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestGitEventWorkspace(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/vega/workspaces/api/worktree-base/.git/HEAD", "/vega/workspaces/api"},
+		{"/vega/workspaces/api/worktree-base/.git/refs/heads/main", "/vega/workspaces/api"},
+		{"/vega/workspaces/api/worktree-base/.git/worktrees/goal-1/index", "/vega/workspaces/api"},
+		{"/vega/workspaces/api/worktree-base/README.md", ""},
+		{"/vega/workspaces/api/goal-1/.git", ""},
+		{"/vega/goals/active/abc1234.md", ""},
+		{"/elsewhere/workspaces/api/worktree-base/.git/HEAD", ""},
+	}
+
+	for _, tt := range tests {
+		result := gitEventWorkspace("/vega", tt.path)
+		if result != tt.expected {
+			t.Errorf("gitEventWorkspace(%q) = %q, want %q", tt.path, result, tt.expected)
+		}
+	}
+}
+
 func TestDetermineEventType(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -205,3 +286,74 @@ func TestStartFileWatcher_IgnoresNonMarkdown(t *testing.T) {
 		// Expected - no event
 	}
 }
+
+func TestStartFileWatcher_CoalescesChanges(t *testing.T) {
+	dir := t.TempDir()
+
+	goalsDir := filepath.Join(dir, "goals")
+	os.MkdirAll(filepath.Join(goalsDir, "active", "folder1"), 0755)
+	os.MkdirAll(filepath.Join(goalsDir, "active", "folder1", "node_modules"), 0755)
+	os.MkdirAll(filepath.Join(dir, "projects"), 0755)
+
+	h := New(dir)
+	eventCh := h.Subscribe()
+	defer h.Unsubscribe(eventCh)
+
+	if err := h.StartFileWatcher(); err != nil {
+		t.Fatalf("StartFileWatcher failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// A burst of changes across goals and a project, plus ignored noise
+	for i := 0; i < 3; i++ {
+		os.WriteFile(filepath.Join(goalsDir, "active", "goal1.md"), []byte("# Update\n"), 0644)
+		os.WriteFile(filepath.Join(goalsDir, "active", "folder1", "task_plan.md"), []byte("# Plan\n"), 0644)
+		os.WriteFile(filepath.Join(goalsDir, "active", "folder1", "node_modules", "x.md"), []byte("x\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "projects", "api.md"), []byte("# api\n"), 0644)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var batches []Event
+	goalEvents := map[string]int{}
+	timeout := time.After(1500 * time.Millisecond)
+loop:
+	for {
+		select {
+		case event := <-eventCh:
+			if event.Type == "files_changed" {
+				batches = append(batches, event)
+			} else {
+				data := event.Data.(map[string]interface{})
+				goalEvents[data["goal_id"].(string)]++
+			}
+		case <-timeout:
+			break loop
+		}
+	}
+
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 files_changed event, got %d", len(batches))
+	}
+	data := batches[0].Data.(map[string]interface{})
+	goalIDs := data["goal_ids"].([]string)
+	if len(goalIDs) != 2 || goalIDs[0] != "folder1" || goalIDs[1] != "goal1" {
+		t.Errorf("expected goal_ids [folder1 goal1], got %v", goalIDs)
+	}
+	projects := data["projects"].([]string)
+	if len(projects) != 1 || projects[0] != "api" {
+		t.Errorf("expected projects [api], got %v", projects)
+	}
+	if goalEvents["goal1"] != 1 || goalEvents["folder1"] != 1 {
+		t.Errorf("expected one event per goal, got %v", goalEvents)
+	}
+
+	status := h.WatcherStatus()
+	if !status.Running || status.Batches != 1 {
+		t.Errorf("unexpected watcher status: %+v", status)
+	}
+	for _, w := range status.Watched {
+		if filepath.Base(w) == "node_modules" {
+			t.Errorf("ignored directory is watched: %s", w)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// webhookQueueSize bounds how many events may wait for delivery to one webhook
+const webhookQueueSize = 256
+
+// Webhook is a bus consumer that POSTs events as JSON to a URL. Deliveries
+// run on their own goroutine; when the endpoint falls behind, events are
+// dropped rather than slowing the hub down.
+type Webhook struct {
+	url    string
+	types  map[string]bool // Empty means every event
+	client *http.Client
+	queue  chan Event
+}
+
+// NewWebhook creates a webhook for url, limited to the given event types
+// (all events if none), and starts its delivery goroutine
+func NewWebhook(url string, types []string) *Webhook {
+	w := &Webhook{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan Event, webhookQueueSize),
+	}
+	if len(types) > 0 {
+		w.types = make(map[string]bool)
+		for _, t := range types {
+			w.types[t] = true
+		}
+	}
+	go w.deliver()
+	return w
+}
+
+// Name implements Consumer
+func (w *Webhook) Name() string { return "webhook:" + w.url }
+
+// Consume implements Consumer
+func (w *Webhook) Consume(event Event) {
+	if w.types != nil && !w.types[event.Type] {
+		return
+	}
+	select {
+	case w.queue <- event:
+	default:
+		log.Printf("[WEBHOOK] %s: queue full, dropping event %d (%s)", w.url, event.ID, event.Type)
+	}
+}
+
+func (w *Webhook) deliver() {
+	for event := range w.queue {
+		if err := w.post(event); err != nil {
+			log.Printf("[WEBHOOK] %s: event %d (%s): %v", w.url, event.ID, event.Type, err)
+		}
+	}
+}
+
+func (w *Webhook) post(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vega-Event", event.Type)
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// sshCommand is the ssh binary used to reach remote workers (overridden in tests)
+var sshCommand = "ssh"
+
+// Remote ports used for the reverse tunnel back to vega-hub, one per spawned executor
+const (
+	tunnelPortMin = 20000
+	tunnelPortMax = 30000
+)
+
+var (
+	// ErrWorkerNotFound is returned when a spawn targets an unknown worker
+	ErrWorkerNotFound = errors.New("worker not found")
+
+	// ErrRemoteExecutor is returned for controls that can't reach an executor on a remote worker
+	ErrRemoteExecutor = errors.New("executor runs on a remote worker")
+)
+
+// Worker is a remote machine executors can be spawned on over SSH.
+// The worker's VegaDir must hold the same vega-missile layout as the hub's
+// directory (shared mount or synced checkout): goal folders and worktrees are
+// resolved on the hub and mapped to the same relative path under VegaDir.
+type Worker struct {
+	Name    string `json:"name"`
+	Host    string `json:"host"`
+	User    string `json:"user,omitempty"`     // SSH user (defaults to ssh config)
+	Port    int    `json:"port,omitempty"`     // SSH port (defaults to ssh config)
+	KeyFile string `json:"key_file,omitempty"` // Identity file; ~ is expanded
+	VegaDir string `json:"vega_dir"`           // vega-missile directory on the worker
+
+	// HubHost is how the worker reaches vega-hub directly. If empty, executor
+	// hooks reach vega-hub through an SSH reverse tunnel.
+	HubHost string `json:"hub_host,omitempty"`
+}
+
+// Validate checks the worker has everything needed to spawn on it
+func (w *Worker) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if w.Host == "" {
+		return fmt.Errorf("worker %s: host is required", w.Name)
+	}
+	if w.VegaDir == "" || !path.IsAbs(w.VegaDir) {
+		return fmt.Errorf("worker %s: vega_dir must be an absolute path", w.Name)
+	}
+	if w.Port < 0 || w.Port > 65535 {
+		return fmt.Errorf("worker %s: invalid port %d", w.Name, w.Port)
+	}
+	return nil
+}
+
+// target returns the ssh destination (user@host)
+func (w *Worker) target() string {
+	if w.User != "" {
+		return w.User + "@" + w.Host
+	}
+	return w.Host
+}
+
+// workersFile is the on-disk format of <vega-dir>/.vega-hub-workers.json
+type workersFile struct {
+	Workers []*Worker `json:"workers"`
+}
+
+// LoadWorkers reads the worker configuration from <vega-dir>/.vega-hub-workers.json.
+// A missing file means no workers are configured.
+func LoadWorkers(dir string) ([]*Worker, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".vega-hub-workers.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read workers: %w", err)
+	}
+
+	var file workersFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse workers: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, w := range file.Workers {
+		if err := w.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[w.Name] {
+			return nil, fmt.Errorf("duplicate worker: %s", w.Name)
+		}
+		seen[w.Name] = true
+	}
+
+	sort.Slice(file.Workers, func(i, j int) bool {
+		return file.Workers[i].Name < file.Workers[j].Name
+	})
+	return file.Workers, nil
+}
+
+// GetWorkers returns the configured remote workers
+func (h *Hub) GetWorkers() ([]*Worker, error) {
+	return LoadWorkers(h.dir)
+}
+
+// GetWorker returns a configured worker by name
+func (h *Hub) GetWorker(name string) (*Worker, error) {
+	workers, err := h.GetWorkers()
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workers {
+		if w.Name == name {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrWorkerNotFound, name)
+}
+
+// remoteCommand builds the ssh command that runs claude on a worker.
+// workDir is the hub-side directory; it's mapped to the same path under the
+// worker's VegaDir. vegaEnv holds the VEGA_* variables for the executor.
+//
+// The environment (with the executor's hub token) and claude's arguments
+// (with the prompt and context pack) are sent over the ssh session's stdin,
+// so they don't show up in ps on either machine. The executor is terminated
+// when the ssh connection closes, so killing the local ssh process stops the
+// remote executor too.
+func (h *Hub) remoteCommand(w *Worker, workDir string, args, vegaEnv []string) (*exec.Cmd, error) {
+	rel, err := filepath.Rel(h.dir, workDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is outside the vega-missile directory", workDir)
+	}
+	remoteDir := path.Join(w.VegaDir, filepath.ToSlash(rel))
+
+	sshArgs := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ServerAliveInterval=30",
+	}
+	if w.KeyFile != "" {
+		sshArgs = append(sshArgs, "-i", expandHome(w.KeyFile))
+	}
+	if w.Port > 0 {
+		sshArgs = append(sshArgs, "-p", fmt.Sprintf("%d", w.Port))
+	}
+
+	// Point executor hooks back at vega-hub
+	if h.port > 0 {
+		if w.HubHost != "" {
+			vegaEnv = append(vegaEnv, "VEGA_HUB_HOST="+w.HubHost, fmt.Sprintf("VEGA_HUB_PORT=%d", h.port))
+		} else {
+			tunnelPort := tunnelPortMin + rand.Intn(tunnelPortMax-tunnelPortMin)
+			sshArgs = append(sshArgs,
+				"-o", "ExitOnForwardFailure=yes",
+				"-R", fmt.Sprintf("%d:localhost:%d", tunnelPort, h.port))
+			vegaEnv = append(vegaEnv, "VEGA_HUB_HOST=localhost", fmt.Sprintf("VEGA_HUB_PORT=%d", tunnelPort))
+		}
+	}
+	vegaEnv = append(vegaEnv, "VEGA_WORKER="+w.Name)
+
+	end := fmt.Sprintf("VEGA_SETUP_END_%016x", rand.Uint64())
+	sshArgs = append(sshArgs, w.target(), "sh -c "+shellQuote(remoteScript(remoteDir, end)))
+
+	cmd := exec.Command(sshCommand, sshArgs...)
+	cmd.Dir = workDir
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	// ssh must keep stdin open: the remote executor is stopped when it closes
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ssh stdin: %w", err)
+	}
+	setup := remoteSetup(args, vegaEnv, end)
+	go io.WriteString(stdin, setup) // Fails once ssh exits if it never read it
+	return cmd, nil
+}
+
+// remoteSetup is the script sent over stdin ahead of the session: it exports
+// the executor's environment and sets claude's arguments, then ends with the
+// end line the remote script reads up to
+func remoteSetup(args, env []string, end string) string {
+	var b strings.Builder
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		b.WriteString("export " + name + "=" + shellQuote(value) + "\n")
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	b.WriteString("set -- " + strings.Join(quoted, " ") + "\n")
+	b.WriteString(end + "\n")
+	return b.String()
+}
+
+// remoteScript is the shell script run on the worker. It reads and runs the
+// setup from stdin up to the end line; claude then runs in the background
+// and is terminated when stdin (the ssh session) closes.
+func remoteScript(dir, end string) string {
+	// read takes one byte at a time from a pipe, so the rest of stdin stays
+	// for the watcher. Background jobs get /dev/null as stdin, so the
+	// watcher reads the session's stdin through fd 3.
+	return strings.Join([]string{
+		"cd " + shellQuote(dir) + " || exit 1",
+		"setup=",
+		"while IFS= read -r line && [ \"$line\" != " + end + " ]; do setup=\"$setup$line",
+		"\"; done",
+		"eval \"$setup\"",
+		"unset setup line",
+		"exec 3<&0",
+		"claude \"$@\" </dev/null 3<&- &",
+		"pid=$!",
+		"(cat <&3 >/dev/null; kill -TERM $pid 2>/dev/null) &",
+		"watcher=$!",
+		"wait $pid",
+		"status=$?",
+		"kill $watcher 2>/dev/null",
+		"exit $status",
+	}, "\n")
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// expandHome expands a leading ~/ to the current user's home directory
+func expandHome(p string) string {
+	if !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[2:])
+}
@@ -0,0 +1,161 @@
+package hub
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeWorkers(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".vega-hub-workers.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadWorkers(t *testing.T) {
+	dir := t.TempDir()
+
+	workers, err := LoadWorkers(dir)
+	if err != nil || workers != nil {
+		t.Fatalf("expected no workers without config, got %v (err: %v)", workers, err)
+	}
+
+	writeWorkers(t, dir, `{"workers":[
+		{"name":"gpu","host":"gpu.lan","vega_dir":"/data/vega"},
+		{"name":"build","host":"build.lan","user":"ci","port":2222,"vega_dir":"/srv/vega"}
+	]}`)
+	workers, err = LoadWorkers(dir)
+	if err != nil {
+		t.Fatalf("LoadWorkers failed: %v", err)
+	}
+	if len(workers) != 2 || workers[0].Name != "build" || workers[0].target() != "ci@build.lan" {
+		t.Errorf("unexpected workers: %+v", workers)
+	}
+
+	for _, bad := range []string{
+		`{"workers":[{"name":"build","vega_dir":"/srv/vega"}]}`,
+		`{"workers":[{"name":"build","host":"build.lan","vega_dir":"relative"}]}`,
+		`{"workers":[{"name":"a","host":"x","vega_dir":"/v"},{"name":"a","host":"y","vega_dir":"/v"}]}`,
+	} {
+		writeWorkers(t, dir, bad)
+		if _, err := LoadWorkers(dir); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestRemoteCommand(t *testing.T) {
+	h := setupTestHub(t)
+	h.SetPort(8080)
+	workDir := filepath.Join(h.dir, "workspaces", "api", "goal-abc1234-fix")
+
+	w := &Worker{Name: "build", Host: "build.lan", User: "ci", Port: 2222, KeyFile: "/keys/id", VegaDir: "/srv/vega"}
+	cmd, err := h.remoteCommand(w, workDir, []string{"-p", "it's done"}, []string{"VEGA_GOAL_ID=abc1234"})
+	if err != nil {
+		t.Fatalf("remoteCommand failed: %v", err)
+	}
+
+	args := strings.Join(cmd.Args, " ")
+	for _, want := range []string{"-i /keys/id", "-p 2222", ":localhost:8080", "ci@build.lan"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in ssh args: %s", want, args)
+		}
+	}
+	script := cmd.Args[len(cmd.Args)-1]
+	if !strings.Contains(script, "/srv/vega/workspaces/api/goal-abc1234-fix") {
+		t.Errorf("expected worktree mapped under worker vega dir: %s", script)
+	}
+
+	// The environment and arguments go over stdin, not the command line
+	if strings.Contains(args, "VEGA_GOAL_ID") || strings.Contains(args, "s done") {
+		t.Errorf("environment or arguments on the ssh command line: %s", args)
+	}
+	setup := readRemoteSetup(t, cmd)
+	for _, want := range []string{"export VEGA_GOAL_ID='abc1234'", `set -- '-p' 'it'\''s done'`, "VEGA_HUB_PORT="} {
+		if !strings.Contains(setup, want) {
+			t.Errorf("expected %q in setup: %s", want, setup)
+		}
+	}
+
+	// A worker that reaches the hub directly doesn't need a tunnel
+	w.HubHost = "hub.lan"
+	cmd, _ = h.remoteCommand(w, workDir, nil, nil)
+	if args := strings.Join(cmd.Args, " "); strings.Contains(args, "-R") || !strings.Contains(readRemoteSetup(t, cmd), "VEGA_HUB_HOST='hub.lan'") {
+		t.Errorf("expected direct hub host without tunnel: %s", args)
+	}
+
+	if _, err := h.remoteCommand(w, t.TempDir(), nil, nil); err == nil {
+		t.Error("expected error for directory outside the vega dir")
+	}
+}
+
+// readRemoteSetup reads the setup remoteCommand sends over the ssh session's
+// stdin, up to its end line
+func readRemoteSetup(t *testing.T, cmd *exec.Cmd) string {
+	t.Helper()
+	var setup strings.Builder
+	scanner := bufio.NewScanner(cmd.Stdin.(*os.File))
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "VEGA_SETUP_END_") {
+			return setup.String()
+		}
+		setup.WriteString(scanner.Text() + "\n")
+	}
+	t.Fatalf("setup not terminated: %s", setup.String())
+	return ""
+}
+
+func TestSpawnExecutor_Worker(t *testing.T) {
+	h := setupTestHub(t)
+
+	if result := h.SpawnExecutor(SpawnRequest{GoalID: "abc1234", Meta: true, Worker: "missing"}); result.Success {
+		t.Fatal("expected spawn on unknown worker to fail")
+	}
+
+	// Fake ssh runs the remote command locally; fake claude reports its environment
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "ssh"), []byte("#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"), 0755)
+	os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\necho \"goal=$VEGA_GOAL_ID worker=$VEGA_WORKER pwd=$(pwd) first=$1\"\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	orig := sshCommand
+	sshCommand = filepath.Join(bin, "ssh")
+	defer func() { sshCommand = orig }()
+
+	goalDir := filepath.Join(h.dir, "goals", "active", "abc1234")
+	os.MkdirAll(goalDir, 0755)
+	writeWorkers(t, h.dir, `{"workers":[{"name":"build","host":"build.lan","vega_dir":"`+h.dir+`"}]}`)
+
+	result := h.SpawnExecutor(SpawnRequest{GoalID: "abc1234", Meta: true, Worker: "build"})
+	if !result.Success || result.Worker != "build" {
+		t.Fatalf("spawn failed: %+v", result)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(h.GetActiveExecutors()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("remote executor did not exit")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	output, _ := os.ReadFile(filepath.Join(goalDir, ".executor-output.log"))
+	if !strings.Contains(string(output), "goal=abc1234 worker=build pwd="+goalDir+" first=--allowedTools") {
+		t.Errorf("unexpected executor output: %q", output)
+	}
+}
+
+func TestPauseExecutor_RemoteWorker(t *testing.T) {
+	h := setupTestHub(t)
+	startManagedProcess(t, h, "abc1234", "session-001")
+	h.attachWorker("session-001", "build")
+
+	if err := h.PauseExecutor("abc1234", "session-001", "alice"); !errors.Is(err, ErrRemoteExecutor) {
+		t.Errorf("expected ErrRemoteExecutor, got %v", err)
+	}
+}
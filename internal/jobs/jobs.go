@@ -0,0 +1,402 @@
+// Package jobs runs long operations (worktree provisioning, clones, merges,
+// MR creation) in the background. Jobs report their current step, can be
+// canceled, and are kept in <vega-dir>/.vega-hub-jobs.json so their outcome
+// survives a restart.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+// Job statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// Job events passed to the Manager's listener
+const (
+	EventProgress  = "job_progress"  // Queued, started or moved to a new step
+	EventCompleted = "job_completed" // Succeeded
+	EventFailed    = "job_failed"    // Failed or canceled
+)
+
+// DefaultConcurrency is how many jobs run at once; the rest wait queued.
+// Worktree provisioning and merges are disk and network heavy.
+const DefaultConcurrency = 2
+
+// Retention is how long finished jobs stay queryable
+const Retention = 24 * time.Hour
+
+// ErrNotFound is returned for unknown or expired job IDs
+var ErrNotFound = errors.New("job not found")
+
+// ErrFinished is returned when canceling a job that already finished
+var ErrFinished = errors.New("job already finished")
+
+// ErrInterrupted is the error of jobs that were queued or running when
+// vega-hub stopped. Their work isn't resumed; the operation has to be retried.
+var ErrInterrupted = errors.New("interrupted by a vega-hub restart")
+
+// Job is background work started by a request that returned early
+type Job struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"` // e.g. "provision_worktree", "complete_goal"
+	GoalID     string      `json:"goal_id,omitempty"`
+	User       string      `json:"user,omitempty"` // Who started it
+	Status     string      `json:"status"`
+	Step       string      `json:"step,omitempty"` // Current step, e.g. "creating worktree"
+	Error      string      `json:"error,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job succeeded, failed or was canceled
+func (j *Job) Finished() bool {
+	return j.FinishedAt != nil
+}
+
+// Func does a job's work. It should stop early when ctx is canceled and
+// report each step through progress. The result is kept with the job, even
+// on error.
+type Func func(ctx context.Context, progress func(step string)) (interface{}, error)
+
+// Spec describes a job to start
+type Spec struct {
+	Type   string
+	GoalID string
+	User   string
+}
+
+// Listener is told about every job change, e.g. to broadcast it over SSE
+type Listener func(event string, job Job)
+
+// entry is a job with its runtime state
+type entry struct {
+	job    *Job
+	run    Func
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{} // Closed when the job finishes
+}
+
+// Manager runs jobs in the order they were started and keeps their records
+type Manager struct {
+	dir         string // vega-missile directory; empty keeps jobs in memory only
+	mu          sync.Mutex
+	jobs        map[string]*entry
+	queue       []*entry // Jobs waiting for a slot, oldest first
+	running     int
+	concurrency int
+	listener    Listener
+}
+
+// NewManager creates a job manager for the vega-missile directory and loads
+// the jobs of the previous run. Jobs that were queued or running then are
+// marked failed with ErrInterrupted.
+func NewManager(dir string, concurrency int) *Manager {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	m := &Manager{
+		dir:         dir,
+		jobs:        make(map[string]*entry),
+		concurrency: concurrency,
+	}
+	if err := m.load(); err != nil {
+		log.Printf("[JOBS] Could not load jobs: %v", err)
+	}
+	return m
+}
+
+// SetListener sets the function told about job changes
+func (m *Manager) SetListener(l Listener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listener = l
+}
+
+// Start queues run in the background and returns the job right away
+func (m *Manager) Start(spec Spec, run Func) *Job {
+	b := make([]byte, 8)
+	rand.Read(b)
+	now := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &entry{
+		job: &Job{
+			ID:        hex.EncodeToString(b),
+			Type:      spec.Type,
+			GoalID:    spec.GoalID,
+			User:      spec.User,
+			Status:    StatusQueued,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		run:    run,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.pruneLocked(now)
+	m.jobs[e.job.ID] = e
+	m.queue = append(m.queue, e)
+	snapshot := *e.job
+	m.mu.Unlock()
+	m.changed(EventProgress, snapshot)
+
+	m.dispatch()
+	return &snapshot
+}
+
+// dispatch starts queued jobs while there are free slots
+func (m *Manager) dispatch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for m.running < m.concurrency && len(m.queue) > 0 {
+		e := m.queue[0]
+		m.queue = m.queue[1:]
+		m.running++
+		go m.run(e)
+	}
+}
+
+func (m *Manager) run(e *entry) {
+	defer func() {
+		e.cancel()
+		m.mu.Lock()
+		m.running--
+		m.mu.Unlock()
+		m.dispatch()
+	}()
+
+	m.update(e, func(j *Job) { j.Status = StatusRunning })
+	result, err := e.run(e.ctx, func(step string) {
+		m.update(e, func(j *Job) { j.Step = step })
+	})
+	m.finish(e, result, err, err != nil && e.ctx.Err() != nil)
+}
+
+// finish records a job's outcome. A job that fails after being canceled
+// counts as canceled; one that completes anyway counts as succeeded.
+func (m *Manager) finish(e *entry, result interface{}, err error, canceled bool) {
+	event := EventCompleted
+	m.update(e, func(j *Job) {
+		now := time.Now()
+		j.FinishedAt = &now
+		j.Result = result
+		switch {
+		case canceled:
+			j.Status = StatusCanceled
+			j.Error = "canceled"
+			event = EventFailed
+		case err != nil:
+			j.Status = StatusFailed
+			j.Error = err.Error()
+			event = EventFailed
+		default:
+			j.Status = StatusSucceeded
+		}
+	})
+
+	job, _ := m.Get(e.job.ID)
+	if err != nil {
+		log.Printf("[JOBS] %s job %s (goal %s) %s: %v", job.Type, job.ID, job.GoalID, job.Status, err)
+	}
+	m.changed(event, *job)
+	close(e.done)
+}
+
+// update applies change to a job, saves the records and broadcasts the
+// job's progress
+func (m *Manager) update(e *entry, change func(j *Job)) {
+	m.mu.Lock()
+	change(e.job)
+	e.job.UpdatedAt = time.Now()
+	snapshot := *e.job
+	if err := m.saveLocked(); err != nil {
+		log.Printf("[JOBS] Could not save jobs: %v", err)
+	}
+	m.mu.Unlock()
+	if !snapshot.Finished() {
+		m.changed(EventProgress, snapshot)
+	}
+}
+
+func (m *Manager) changed(event string, job Job) {
+	m.mu.Lock()
+	l := m.listener
+	m.mu.Unlock()
+	if l != nil {
+		l(event, job)
+	}
+}
+
+// Get returns a job by ID
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	snapshot := *e.job
+	return &snapshot, nil
+}
+
+// Filter selects jobs in List. Empty fields match everything.
+type Filter struct {
+	GoalID string
+	Type   string
+	Status string
+}
+
+// List returns the jobs matching f, newest first
+func (m *Manager) List(f Filter) []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		j := e.job
+		if (f.GoalID != "" && j.GoalID != f.GoalID) || (f.Type != "" && j.Type != f.Type) || (f.Status != "" && j.Status != f.Status) {
+			continue
+		}
+		jobs = append(jobs, *j)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// Cancel asks a job to stop. A queued job never starts; a running one has
+// its context canceled and stops at its next check.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return ErrNotFound
+	}
+	if e.job.Finished() || e.cancel == nil {
+		m.mu.Unlock()
+		return ErrFinished
+	}
+	queued := false
+	for i, q := range m.queue {
+		if q == e {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			queued = true
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	e.cancel()
+	if queued {
+		m.finish(e, nil, context.Canceled, true)
+	}
+	return nil
+}
+
+// Wait blocks until a job finishes or ctx is done, and returns the job
+func (m *Manager) Wait(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	select {
+	case <-e.done:
+		return m.Get(id)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pruneLocked drops jobs finished more than Retention ago (caller holds m.mu)
+func (m *Manager) pruneLocked(now time.Time) {
+	for id, e := range m.jobs {
+		if e.job.Finished() && now.Sub(*e.job.FinishedAt) > Retention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func (m *Manager) path() string {
+	return filepath.Join(m.dir, ".vega-hub-jobs.json")
+}
+
+// load reads the previous run's jobs, failing the unfinished ones
+func (m *Manager) load() error {
+	if m.dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read jobs: %w", err)
+	}
+	var saved []*Job
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse jobs: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, j := range saved {
+		if !j.Finished() {
+			j.Status = StatusFailed
+			j.Error = ErrInterrupted.Error()
+			j.FinishedAt = &now
+			j.UpdatedAt = now
+		}
+		done := make(chan struct{})
+		close(done)
+		m.jobs[j.ID] = &entry{job: j, done: done}
+	}
+	m.pruneLocked(now)
+	return m.saveLocked()
+}
+
+// saveLocked writes the job records atomically (caller holds m.mu)
+func (m *Manager) saveLocked() error {
+	if m.dir == "" {
+		return nil
+	}
+	saved := make([]*Job, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		saved = append(saved, e.job)
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].CreatedAt.Before(saved[j].CreatedAt) })
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal jobs: %w", err)
+	}
+	if err := atomicfile.WriteFile(m.path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, m *Manager, id string) *Job {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := m.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("job %s didn't finish: %v", id, err)
+	}
+	return job
+}
+
+func TestManagerRunsJobs(t *testing.T) {
+	m := NewManager(t.TempDir(), DefaultConcurrency)
+	var mu sync.Mutex
+	var events []string
+	m.SetListener(func(event string, job Job) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event+":"+job.Status+":"+job.Step)
+	})
+
+	job := m.Start(Spec{Type: "provision_worktree", GoalID: "abc1234", User: "alice"}, func(ctx context.Context, progress func(string)) (interface{}, error) {
+		progress("creating worktree")
+		return "done", nil
+	})
+	if job.Status != StatusQueued || job.GoalID != "abc1234" {
+		t.Errorf("expected a queued job, got %+v", job)
+	}
+
+	finished := waitFor(t, m, job.ID)
+	if finished.Status != StatusSucceeded || finished.Step != "creating worktree" || finished.Result != "done" {
+		t.Errorf("expected a succeeded job with its last step, got %+v", finished)
+	}
+
+	mu.Lock()
+	want := []string{
+		"job_progress:queued:",
+		"job_progress:running:",
+		"job_progress:running:creating worktree",
+		"job_completed:succeeded:creating worktree",
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], events[i])
+		}
+	}
+	mu.Unlock()
+
+	failed := waitFor(t, m, m.Start(Spec{Type: "complete_goal", GoalID: "def5678"}, func(context.Context, func(string)) (interface{}, error) {
+		return nil, errors.New("merge failed")
+	}).ID)
+	if failed.Status != StatusFailed || failed.Error != "merge failed" {
+		t.Errorf("expected a failed job, got %+v", failed)
+	}
+
+	if jobs := m.List(Filter{}); len(jobs) != 2 || jobs[0].GoalID != "def5678" {
+		t.Errorf("expected both jobs newest first, got %+v", jobs)
+	}
+	if jobs := m.List(Filter{Status: StatusFailed}); len(jobs) != 1 || jobs[0].Type != "complete_goal" {
+		t.Errorf("expected the failed job only, got %+v", jobs)
+	}
+	if _, err := m.Get("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestManagerConcurrency(t *testing.T) {
+	m := NewManager("", 1)
+	block := make(chan struct{})
+	running := m.Start(Spec{Type: "a"}, func(context.Context, func(string)) (interface{}, error) {
+		<-block
+		return nil, nil
+	})
+	queued := m.Start(Spec{Type: "b"}, func(context.Context, func(string)) (interface{}, error) {
+		return nil, nil
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	if job, _ := m.Get(queued.ID); job.Status != StatusQueued {
+		t.Errorf("expected the second job queued behind the first, got %s", job.Status)
+	}
+	close(block)
+	waitFor(t, m, running.ID)
+	if job := waitFor(t, m, queued.ID); job.Status != StatusSucceeded {
+		t.Errorf("expected the queued job to run, got %+v", job)
+	}
+}
+
+func TestManagerCancel(t *testing.T) {
+	m := NewManager("", 1)
+	started := make(chan struct{})
+	running := m.Start(Spec{Type: "a"}, func(ctx context.Context, progress func(string)) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ran := false
+	queued := m.Start(Spec{Type: "b"}, func(context.Context, func(string)) (interface{}, error) {
+		ran = true
+		return nil, nil
+	})
+	<-started
+
+	if err := m.Cancel(queued.ID); err != nil {
+		t.Fatalf("Cancel queued: %v", err)
+	}
+	if job := waitFor(t, m, queued.ID); job.Status != StatusCanceled || ran {
+		t.Errorf("expected the queued job canceled without running, got %+v (ran=%v)", job, ran)
+	}
+
+	if err := m.Cancel(running.ID); err != nil {
+		t.Fatalf("Cancel running: %v", err)
+	}
+	if job := waitFor(t, m, running.ID); job.Status != StatusCanceled {
+		t.Errorf("expected the running job canceled, got %+v", job)
+	}
+
+	if err := m.Cancel(running.ID); !errors.Is(err, ErrFinished) {
+		t.Errorf("expected ErrFinished canceling a finished job, got %v", err)
+	}
+	if err := m.Cancel("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestManagerPersistsJobs(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir, DefaultConcurrency)
+	done := waitFor(t, m, m.Start(Spec{Type: "complete_goal", GoalID: "abc1234"}, func(context.Context, func(string)) (interface{}, error) {
+		return map[string]string{"merged_to": "main"}, nil
+	}).ID)
+
+	block := make(chan struct{})
+	interrupted := m.Start(Spec{Type: "clone_project"}, func(context.Context, func(string)) (interface{}, error) {
+		<-block
+		return nil, nil
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	// A restart loads the records; the unfinished job can't resume
+	restarted := NewManager(dir, DefaultConcurrency)
+	job, err := restarted.Get(done.ID)
+	if err != nil || job.Status != StatusSucceeded || job.GoalID != "abc1234" {
+		t.Errorf("expected the finished job kept, got %+v (%v)", job, err)
+	}
+	job, err = restarted.Get(interrupted.ID)
+	if err != nil || job.Status != StatusFailed || job.Error != ErrInterrupted.Error() {
+		t.Errorf("expected the running job marked interrupted, got %+v (%v)", job, err)
+	}
+	if err := restarted.Cancel(interrupted.ID); !errors.Is(err, ErrFinished) {
+		t.Errorf("expected ErrFinished canceling an interrupted job, got %v", err)
+	}
+	if _, err := restarted.Wait(context.Background(), interrupted.ID); err != nil {
+		t.Errorf("expected Wait to return at once for a loaded job, got %v", err)
+	}
+
+	close(block)
+	waitFor(t, m, interrupted.ID)
+}
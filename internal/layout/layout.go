@@ -0,0 +1,406 @@
+// Package layout knows where everything lives in a vega-missile directory:
+// goal files and their sidecars, the registry, project configs, workspaces
+// and templates. Other packages build paths through a Layout rather than
+// joining directory names themselves.
+//
+// Goal files come in two styles, both always readable:
+//
+//	flat:   goals/<dir>/<id>.md
+//	folder: goals/<dir>/<id>/<id>.md
+//
+// New goal files are written in the style chosen by .vega-hub-layout.json
+// ({"goals": "folder"}); flat by default. A goal's sidecar files
+// (<id>.state.jsonl, <id>.metadata.json, <id>.hierarchy.json) sit next to its
+// goal file.
+package layout
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Goal directories under goals/
+const (
+	BacklogDir = "backlog"         // Drafts
+	ActiveDir  = "active"          // Active goals
+	IcedDir    = "iced"            // Iced goals
+	HistoryDir = "history"         // Completed goals
+	ArchiveDir = "history/archive" // Completed goals moved out of history by cleanup
+)
+
+// WorktreeBaseName is the directory of a project's main checkout under
+// workspaces/<project>
+const WorktreeBaseName = "worktree-base"
+
+// ConfigFile in the vega-missile directory sets the goal file style
+const ConfigFile = ".vega-hub-layout.json"
+
+// GoalStyle is how goal files are laid out
+type GoalStyle string
+
+const (
+	Flat   GoalStyle = "flat"   // goals/<dir>/<id>.md
+	Folder GoalStyle = "folder" // goals/<dir>/<id>/<id>.md
+)
+
+// GoalDir is a directory under goals/ and the status of the goals in it
+type GoalDir struct {
+	Name   string // e.g. "active" or "history/archive"
+	Status string // "draft", "active", "iced" or "completed"
+}
+
+// goalDirs are searched in this order when looking for a goal
+var goalDirs = []GoalDir{
+	{BacklogDir, "draft"},
+	{ActiveDir, "active"},
+	{IcedDir, "iced"},
+	{HistoryDir, "completed"},
+	{ArchiveDir, "completed"},
+}
+
+// Layout resolves paths in one vega-missile directory
+type Layout struct {
+	root  string
+	goals GoalStyle
+}
+
+// New returns the layout of the vega-missile directory root, with the goal
+// style from its ConfigFile (flat when there is none)
+func New(root string) *Layout {
+	l := &Layout{root: root, goals: Flat}
+	if data, err := os.ReadFile(filepath.Join(root, ConfigFile)); err == nil {
+		var config struct {
+			Goals GoalStyle `json:"goals"`
+		}
+		if json.Unmarshal(data, &config) == nil && config.Goals == Folder {
+			l.goals = Folder
+		}
+	}
+	return l
+}
+
+// WithGoalStyle returns a copy of the layout writing goal files in style
+func (l *Layout) WithGoalStyle(style GoalStyle) *Layout {
+	c := *l
+	c.goals = style
+	return &c
+}
+
+// Root returns the vega-missile directory
+func (l *Layout) Root() string {
+	return l.root
+}
+
+// GoalStyle returns the style new goal files are written in
+func (l *Layout) GoalStyle() GoalStyle {
+	return l.goals
+}
+
+// Rel returns path relative to the vega-missile directory, and false if it
+// is outside it
+func (l *Layout) Rel(path string) (string, bool) {
+	rel, err := filepath.Rel(l.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// GoalDirs returns the directories under goals/ in lookup order
+func (l *Layout) GoalDirs() []GoalDir {
+	return append([]GoalDir(nil), goalDirs...)
+}
+
+// GoalsDir returns goals/
+func (l *Layout) GoalsDir() string {
+	return filepath.Join(l.root, "goals")
+}
+
+// GoalDir returns a directory under goals/, e.g. GoalDir(ActiveDir)
+func (l *Layout) GoalDir(name string) string {
+	return filepath.Join(l.root, "goals", filepath.FromSlash(name))
+}
+
+// StatusDir returns the directory holding goals with status ("draft",
+// "active", "iced" or "completed"), or "" for an unknown status
+func (l *Layout) StatusDir(status string) string {
+	for _, dir := range goalDirs {
+		if dir.Status == status {
+			return l.GoalDir(dir.Name)
+		}
+	}
+	return ""
+}
+
+// GoalFile returns the path a new goal file gets in dir, in the layout's style
+func (l *Layout) GoalFile(dir, id string) string {
+	if l.goals == Folder {
+		return filepath.Join(l.GoalDir(dir), id, id+".md")
+	}
+	return filepath.Join(l.GoalDir(dir), id+".md")
+}
+
+// GoalFolder returns goals/<dir>/<id>, the folder of a folder-style goal,
+// which also holds per-goal files such as planning files
+func (l *Layout) GoalFolder(dir, id string) string {
+	return filepath.Join(l.GoalDir(dir), id)
+}
+
+// goalFileCandidates returns where a goal file can be in dir, the layout's
+// own style first
+func (l *Layout) goalFileCandidates(dir, id string) []string {
+	flat := filepath.Join(l.GoalDir(dir), id+".md")
+	folder := filepath.Join(l.GoalDir(dir), id, id+".md")
+	if l.goals == Folder {
+		return []string{folder, flat}
+	}
+	return []string{flat, folder}
+}
+
+// FindGoalFileIn returns a goal's file in dir in either style, or ""
+func (l *Layout) FindGoalFileIn(dir, id string) string {
+	for _, path := range l.goalFileCandidates(dir, id) {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// FindGoalFile returns a goal's file and status, searching every goal
+// directory, or empty strings if there is none
+func (l *Layout) FindGoalFile(id string) (string, string) {
+	for _, dir := range goalDirs {
+		if path := l.FindGoalFileIn(dir.Name, id); path != "" {
+			return path, dir.Status
+		}
+	}
+	return "", ""
+}
+
+// FindGoalDir returns the directory under goals/ holding a goal (e.g.
+// ActiveDir), or ""
+func (l *Layout) FindGoalDir(id string) string {
+	for _, dir := range goalDirs {
+		if l.FindGoalFileIn(dir.Name, id) != "" {
+			return dir.Name
+		}
+	}
+	return ""
+}
+
+// GoalFiles returns the goal files in dir by goal ID, in both styles
+func (l *Layout) GoalFiles(dir string) map[string]string {
+	files := make(map[string]string)
+	entries, err := os.ReadDir(l.GoalDir(dir))
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			path := filepath.Join(l.GoalDir(dir), name, name+".md")
+			if _, err := os.Stat(path); err == nil {
+				files[name] = path
+			}
+		} else if strings.HasSuffix(name, ".md") {
+			files[strings.TrimSuffix(name, ".md")] = filepath.Join(l.GoalDir(dir), name)
+		}
+	}
+	return files
+}
+
+// Sidecar returns the path of a file kept next to a goal file, e.g.
+// Sidecar(goalFile, ".state.jsonl")
+func Sidecar(goalFile, suffix string) string {
+	return strings.TrimSuffix(goalFile, ".md") + suffix
+}
+
+// GoalSidecar returns where a goal's sidecar file belongs: next to its goal
+// file, or next to the goal file it would get in active. An existing
+// sidecar in the other style is returned as is.
+func (l *Layout) GoalSidecar(id, suffix string) string {
+	if path := l.FindSidecar(id, suffix); path != "" {
+		return path
+	}
+	if goalFile, _ := l.FindGoalFile(id); goalFile != "" {
+		return Sidecar(goalFile, suffix)
+	}
+	return Sidecar(l.GoalFile(ActiveDir, id), suffix)
+}
+
+// FindSidecar returns an existing sidecar file of a goal, in either style,
+// or ""
+func (l *Layout) FindSidecar(id, suffix string) string {
+	if goalFile, _ := l.FindGoalFile(id); goalFile != "" {
+		if path := Sidecar(goalFile, suffix); fileExists(path) {
+			return path
+		}
+	}
+	for _, dir := range goalDirs {
+		if path := l.FindSidecarIn(dir.Name, id, suffix); path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// FindSidecarIn returns an existing sidecar file of a goal in dir, in
+// either style, or ""
+func (l *Layout) FindSidecarIn(dir, id, suffix string) string {
+	for _, goalFile := range l.goalFileCandidates(dir, id) {
+		if path := Sidecar(goalFile, suffix); fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// MoveGoal moves a goal from dir to toDir and returns its new goal file. A
+// folder-style goal moves with its folder (and so its sidecars and planning
+// files); for a flat goal only the goal file moves, its sidecars are moved
+// with MoveSidecar.
+func (l *Layout) MoveGoal(id, dir, toDir string) (string, error) {
+	goalFile := l.FindGoalFileIn(dir, id)
+	if goalFile == "" {
+		return "", os.ErrNotExist
+	}
+	src, dst := goalFile, filepath.Join(l.GoalDir(toDir), filepath.Base(goalFile))
+	if filepath.Base(filepath.Dir(goalFile)) == id {
+		src, dst = filepath.Dir(goalFile), l.GoalFolder(toDir, id)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return "", err
+	}
+	return dst + goalFile[len(src):], nil
+}
+
+// Sidecars returns the sidecar files with suffix in dir by goal ID, in both
+// styles, including those of goals without a goal file
+func (l *Layout) Sidecars(dir, suffix string) map[string]string {
+	files := make(map[string]string)
+	entries, err := os.ReadDir(l.GoalDir(dir))
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			path := filepath.Join(l.GoalDir(dir), name, name+suffix)
+			if fileExists(path) {
+				files[name] = path
+			}
+		} else if id := strings.TrimSuffix(name, suffix); id != name && id != "" {
+			files[id] = filepath.Join(l.GoalDir(dir), name)
+		}
+	}
+	return files
+}
+
+// MoveSidecar moves a goal's sidecar file from dir to toDir, next to the
+// goal file there (or where it will be). A missing sidecar is not an error.
+func (l *Layout) MoveSidecar(id, suffix, fromDir, toDir string) error {
+	fromPath := l.FindSidecarIn(fromDir, id, suffix)
+	if fromPath == "" {
+		return nil
+	}
+	goalFile := l.FindGoalFileIn(toDir, id)
+	if goalFile == "" {
+		goalFile = l.GoalFile(toDir, id)
+	}
+	toPath := Sidecar(goalFile, suffix)
+	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+		return err
+	}
+	return os.Rename(fromPath, toPath)
+}
+
+// Registry returns goals/registry.jsonl
+func (l *Layout) Registry() string {
+	return filepath.Join(l.root, "goals", "registry.jsonl")
+}
+
+// RegistryMarkdown returns goals/REGISTRY.md, the registry's older format
+func (l *Layout) RegistryMarkdown() string {
+	return filepath.Join(l.root, "goals", "REGISTRY.md")
+}
+
+// Board returns goals/board.json
+func (l *Layout) Board() string {
+	return filepath.Join(l.root, "goals", "board.json")
+}
+
+// ProjectsDir returns projects/
+func (l *Layout) ProjectsDir() string {
+	return filepath.Join(l.root, "projects")
+}
+
+// ProjectConfig returns projects/<name>.md
+func (l *Layout) ProjectConfig(name string) string {
+	return filepath.Join(l.root, "projects", name+".md")
+}
+
+// ProjectIndex returns projects/index.md
+func (l *Layout) ProjectIndex() string {
+	return filepath.Join(l.root, "projects", "index.md")
+}
+
+// WorkspacesDir returns workspaces/
+func (l *Layout) WorkspacesDir() string {
+	return filepath.Join(l.root, "workspaces")
+}
+
+// ProjectWorkspace returns workspaces/<project>
+func (l *Layout) ProjectWorkspace(project string) string {
+	return filepath.Join(l.root, "workspaces", project)
+}
+
+// WorktreeBase returns workspaces/<project>/worktree-base
+func (l *Layout) WorktreeBase(project string) string {
+	return filepath.Join(l.root, "workspaces", project, WorktreeBaseName)
+}
+
+// Workspace returns workspaces/<project>/<name>, e.g. a goal's worktree
+func (l *Layout) Workspace(project, name string) string {
+	return filepath.Join(l.root, "workspaces", project, name)
+}
+
+// ParseWorkspacePath splits a path under workspaces/ (absolute, or relative
+// to the vega-missile directory) into the project and the workspace in it
+// ("" for the project directory itself)
+func (l *Layout) ParseWorkspacePath(path string) (project, name string, ok bool) {
+	if filepath.IsAbs(path) {
+		if path, ok = l.Rel(path); !ok {
+			return "", "", false
+		}
+	}
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	if len(parts) < 2 || parts[0] != "workspaces" {
+		return "", "", false
+	}
+	if len(parts) > 2 {
+		name = parts[2]
+	}
+	return parts[1], name, true
+}
+
+// TemplatesDir returns templates/
+func (l *Layout) TemplatesDir() string {
+	return filepath.Join(l.root, "templates")
+}
+
+// ProjectInitTemplate returns templates/project-init, copied into new
+// projects and worktrees
+func (l *Layout) ProjectInitTemplate() string {
+	return filepath.Join(l.root, "templates", "project-init")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,130 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# Goal\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoalStyle(t *testing.T) {
+	dir := t.TempDir()
+	if l := New(dir); l.GoalStyle() != Flat || l.GoalFile(ActiveDir, "abc1234") != filepath.Join(dir, "goals", "active", "abc1234.md") {
+		t.Errorf("default layout: style %s, goal file %s", l.GoalStyle(), l.GoalFile(ActiveDir, "abc1234"))
+	}
+
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte(`{"goals": "folder"}`), 0644)
+	l := New(dir)
+	if l.GoalStyle() != Folder || l.GoalFile(IcedDir, "abc1234") != filepath.Join(dir, "goals", "iced", "abc1234", "abc1234.md") {
+		t.Errorf("folder layout: style %s, goal file %s", l.GoalStyle(), l.GoalFile(IcedDir, "abc1234"))
+	}
+	if got := l.GoalFile(ArchiveDir, "abc1234"); got != filepath.Join(dir, "goals", "history", "archive", "abc1234", "abc1234.md") {
+		t.Errorf("archived goal file = %s", got)
+	}
+}
+
+func TestFindGoalFile(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir)
+	flat := filepath.Join(dir, "goals", "active", "abc1234.md")
+	folder := filepath.Join(dir, "goals", "iced", "def5678", "def5678.md")
+	writeFile(t, flat)
+	writeFile(t, folder)
+	writeFile(t, filepath.Join(dir, "goals", "active", "abc1234", "project-plans", "notes.md"))
+
+	if path, status := l.FindGoalFile("abc1234"); path != flat || status != "active" {
+		t.Errorf("flat goal: %s %s", path, status)
+	}
+	if path, status := l.FindGoalFile("def5678"); path != folder || status != "iced" {
+		t.Errorf("folder goal: %s %s", path, status)
+	}
+	if path, _ := l.FindGoalFile("0000000"); path != "" {
+		t.Errorf("missing goal found at %s", path)
+	}
+	if dir := l.FindGoalDir("def5678"); dir != IcedDir {
+		t.Errorf("FindGoalDir = %q", dir)
+	}
+
+	files := l.GoalFiles(ActiveDir)
+	if len(files) != 1 || files["abc1234"] != flat {
+		t.Errorf("GoalFiles = %v", files)
+	}
+}
+
+func TestSidecars(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir)
+	writeFile(t, filepath.Join(dir, "goals", "active", "abc1234", "abc1234.md"))
+
+	// Next to the goal file, whatever its style
+	want := filepath.Join(dir, "goals", "active", "abc1234", "abc1234.state.jsonl")
+	if got := l.GoalSidecar("abc1234", ".state.jsonl"); got != want {
+		t.Errorf("GoalSidecar = %s, want %s", got, want)
+	}
+	// A goal without a file gets the active location
+	if got := l.GoalSidecar("def5678", ".state.jsonl"); got != filepath.Join(dir, "goals", "active", "def5678.state.jsonl") {
+		t.Errorf("GoalSidecar for a new goal = %s", got)
+	}
+
+	writeFile(t, want)
+	writeFile(t, filepath.Join(dir, "goals", "active", "def5678.state.jsonl"))
+	if got := l.Sidecars(ActiveDir, ".state.jsonl"); len(got) != 2 || got["abc1234"] != want {
+		t.Errorf("Sidecars = %v", got)
+	}
+
+	// Moving the goal moves its folder, so the sidecar goes along
+	moved, err := l.MoveGoal("abc1234", ActiveDir, HistoryDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != filepath.Join(dir, "goals", "history", "abc1234", "abc1234.md") {
+		t.Errorf("moved goal file = %s", moved)
+	}
+	if got := l.FindSidecar("abc1234", ".state.jsonl"); got != Sidecar(moved, ".state.jsonl") {
+		t.Errorf("sidecar after move = %s", got)
+	}
+
+	// A flat goal's sidecar moves separately
+	writeFile(t, filepath.Join(dir, "goals", "active", "def5678.md"))
+	if _, err := l.MoveGoal("def5678", ActiveDir, IcedDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.MoveSidecar("def5678", ".state.jsonl", ActiveDir, IcedDir); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.FindSidecar("def5678", ".state.jsonl"); got != filepath.Join(dir, "goals", "iced", "def5678.state.jsonl") {
+		t.Errorf("flat sidecar after move = %s", got)
+	}
+}
+
+func TestParseWorkspacePath(t *testing.T) {
+	l := New("/vega")
+	tests := []struct {
+		path, project, name string
+		ok                  bool
+	}{
+		{"/vega/workspaces/my-api/goal-abc1234-fix", "my-api", "goal-abc1234-fix", true},
+		{"workspaces/my-api/worktree-base/src", "my-api", "worktree-base", true},
+		{"workspaces/my-api", "my-api", "", true},
+		{"/vega/goals/active/abc1234.md", "", "", false},
+		{"/elsewhere/workspaces/my-api", "", "", false},
+	}
+	for _, tt := range tests {
+		project, name, ok := l.ParseWorkspacePath(tt.path)
+		if project != tt.project || name != tt.name || ok != tt.ok {
+			t.Errorf("ParseWorkspacePath(%q) = %q, %q, %v", tt.path, project, name, ok)
+		}
+	}
+	if got := l.WorktreeBase("my-api"); got != filepath.Join("/vega", "workspaces", "my-api", "worktree-base") {
+		t.Errorf("WorktreeBase = %s", got)
+	}
+}
@@ -0,0 +1,123 @@
+package loadtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// fixtureProjects is how many projects the generated goals are spread over
+const fixtureProjects = 5
+
+// WriteFixture fills dir with a vega-missile layout of n active goals spread
+// over a few projects. Each goal gets a goal file with several phases and
+// historyEntries session history entries (sessions, questions and activity)
+// for the chat view. Returns the goal IDs.
+func WriteFixture(dir string, n, historyEntries int) ([]string, error) {
+	for _, sub := range []string{"active", "iced", "history"} {
+		if err := os.MkdirAll(filepath.Join(dir, "goals", sub), 0755); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "projects"), 0755); err != nil {
+		return nil, err
+	}
+	for i := 0; i < fixtureProjects; i++ {
+		content := fmt.Sprintf("# Project: %s\n\n**Base Branch**: `main`\n", fixtureProject(i))
+		if err := os.WriteFile(filepath.Join(dir, "projects", fixtureProject(i)+".md"), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	history := hub.NewSessionHistory(dir)
+	now := time.Now().Format("2006-01-02")
+	ids := make([]string, 0, n)
+	entries := make([]goals.RegistryEntry, 0, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("%07x", 0xb000000+i)
+		project := fixtureProject(i)
+		title := fmt.Sprintf("Load test goal %d", i+1)
+		if err := os.WriteFile(filepath.Join(dir, "goals", "active", id+".md"), []byte(fixtureGoal(id, title, project)), 0644); err != nil {
+			return nil, err
+		}
+		entries = append(entries, goals.RegistryEntry{
+			ID:        id,
+			Title:     title,
+			Projects:  []string{project},
+			Status:    "active",
+			Phase:     "2/4",
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err := writeFixtureHistory(history, id, historyEntries); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := goals.NewRegistry(dir).Save(entries); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func fixtureProject(i int) string {
+	return fmt.Sprintf("project-%d", i%fixtureProjects+1)
+}
+
+// fixtureGoal renders a goal file in the layout executors maintain
+func fixtureGoal(id, title, project string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Goal #%s: %s\n\n", id, title)
+	b.WriteString("## Overview\n\nGenerated by the vega-hub load test.\n\n")
+	fmt.Fprintf(&b, "## Project(s)\n\n- **%s**: Main project\n\n", project)
+	b.WriteString("## Phases\n\n")
+	for phase := 1; phase <= 4; phase++ {
+		fmt.Fprintf(&b, "### Phase %d: Step %d\n", phase, phase)
+		for task := 1; task <= 5; task++ {
+			mark := " "
+			if phase == 1 {
+				mark = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] Task %d.%d\n", mark, phase, task)
+		}
+		status := "pending"
+		switch {
+		case phase == 1:
+			status = "complete"
+		case phase == 2:
+			status = "in_progress"
+		}
+		fmt.Fprintf(&b, "- **Status:** %s\n\n", status)
+	}
+	b.WriteString("## Acceptance Criteria\n\n- [ ] Everything works\n- [ ] Tests pass\n\n")
+	b.WriteString("## Notes\n\n- Generated\n\n")
+	b.WriteString("## Status\n\n**Current Phase**: 2\n**Status**: Active\n")
+	return b.String()
+}
+
+// writeFixtureHistory records sessions of ten entries each: a start, then
+// alternating questions and activity
+func writeFixtureHistory(history *hub.SessionHistory, goalID string, n int) error {
+	var sessionID string
+	for i := 0; i < n; i++ {
+		var err error
+		switch {
+		case i%10 == 0:
+			sessionID = fmt.Sprintf("%s-session-%d", goalID, i/10+1)
+			err = history.RecordSessionStart(goalID, sessionID, "/tmp", "loadtest")
+		case i%2 == 0:
+			err = history.RecordQuestion(goalID, sessionID, fmt.Sprintf("Question %d?", i), fmt.Sprintf("Answer %d", i))
+		default:
+			err = history.RecordActivity(goalID, sessionID, "tool_use", map[string]string{"tool": "Edit", "file": fmt.Sprintf("file%d.go", i)})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
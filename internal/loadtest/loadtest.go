@@ -0,0 +1,341 @@
+// Package loadtest drives a running hub over HTTP with simulated executors,
+// SSE clients and readers, and reports request latencies per path.
+package loadtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// Paths reported by Run
+const (
+	PathListGoals  = "GET /api/goals"
+	PathGoalDetail = "GET /api/goals/{id}"
+	PathGoalChat   = "GET /api/goals/{id}/chat"
+	PathQuestions  = "GET /api/questions"
+	PathAnswer     = "POST /api/answer/{id}"
+	PathAskAnswer  = "ask → answer" // From POST /api/ask until the executor gets the answer
+)
+
+// Config describes the simulated load
+type Config struct {
+	Executors  int           // Executors asking questions back to back, each on its own goal
+	SSEClients int           // Clients subscribed to /api/events for the whole run
+	Readers    int           // Clients cycling through the goal list, detail and chat
+	Duration   time.Duration // How long to apply load; with Cycles, a timeout
+	Cycles     int           // Ask → answer round trips per executor, ending the run instead of Duration (0 = until Duration)
+}
+
+// PathStats summarizes the latencies of one path
+type PathStats struct {
+	Path     string        `json:"path"`
+	Requests int           `json:"requests"`
+	Errors   int           `json:"errors"`
+	P50      time.Duration `json:"p50"`
+	P95      time.Duration `json:"p95"`
+	P99      time.Duration `json:"p99"`
+	Max      time.Duration `json:"max"`
+}
+
+// Report is the outcome of a load test
+type Report struct {
+	Duration  time.Duration `json:"duration"`
+	Paths     []PathStats   `json:"paths"`
+	SSEEvents int64         `json:"sse_events"` // Events received across all SSE clients
+}
+
+// Print writes the report as a table
+func (r *Report) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "PATH\tREQUESTS\tERRORS\tP50\tP95\tP99\tMAX\t")
+	for _, p := range r.Paths {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t\n", p.Path, p.Requests, p.Errors,
+			round(p.P50), round(p.P95), round(p.P99), round(p.Max))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d SSE events received in %s\n", r.SSEEvents, round(r.Duration))
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
+
+// recorder collects request latencies by path
+type recorder struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+	errors  map[string]int
+}
+
+func (r *recorder) record(path string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errors[path]++
+		return
+	}
+	r.samples[path] = append(r.samples[path], d)
+}
+
+func (r *recorder) stats() []PathStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	paths := make(map[string]bool)
+	for p := range r.samples {
+		paths[p] = true
+	}
+	for p := range r.errors {
+		paths[p] = true
+	}
+
+	var stats []PathStats
+	for path := range paths {
+		samples := r.samples[path]
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		s := PathStats{Path: path, Requests: len(samples) + r.errors[path], Errors: r.errors[path]}
+		if len(samples) > 0 {
+			s.P50 = percentile(samples, 50)
+			s.P95 = percentile(samples, 95)
+			s.P99 = percentile(samples, 99)
+			s.Max = samples[len(samples)-1]
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Path < stats[j].Path })
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// Run applies the configured load to the hub at baseURL for cfg.Duration, or
+// until every executor completed cfg.Cycles round trips. Each reader makes at
+// least one request of each kind. goalIDs are the goals executors and readers
+// work on (see WriteFixture). Questions still pending when the run ends are
+// answered before Run returns, so no request is left blocked on the hub.
+func Run(ctx context.Context, baseURL string, goalIDs []string, cfg Config) (*Report, error) {
+	if len(goalIDs) == 0 {
+		return nil, fmt.Errorf("no goals to load test")
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	rec := &recorder{samples: make(map[string][]time.Duration), errors: make(map[string]int)}
+	client := &http.Client{}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+	start := time.Now()
+
+	// SSE clients count events until the run ends. Load starts once they
+	// are connected, so they see it from the first question.
+	var sseEvents int64
+	var sseWG, sseConnected sync.WaitGroup
+	for i := 0; i < cfg.SSEClients; i++ {
+		sseWG.Add(1)
+		sseConnected.Add(1)
+		go func() {
+			defer sseWG.Done()
+			subscribe(ctx, client, baseURL+"/api/events", &sseEvents, sseConnected.Done)
+		}()
+	}
+	sseConnected.Wait()
+
+	// Executors ask questions back to back. An ask in flight when the run
+	// ends is still waited for, since the hub holds it until it's answered.
+	var execWG sync.WaitGroup
+	for i := 0; i < cfg.Executors; i++ {
+		execWG.Add(1)
+		go func(i int) {
+			defer execWG.Done()
+			goalID := goalIDs[i%len(goalIDs)]
+			sessionID := fmt.Sprintf("loadtest-%d", i+1)
+			if err := postJSON(client, baseURL+"/api/executor/register",
+				map[string]string{"goal_id": goalID, "session_id": sessionID, "cwd": "/tmp"}, nil); err != nil {
+				rec.record(PathAskAnswer, 0, err)
+				return
+			}
+			for n := 1; ctx.Err() == nil && (cfg.Cycles <= 0 || n <= cfg.Cycles); n++ {
+				began := time.Now()
+				err := postJSON(client, baseURL+"/api/ask", map[string]string{
+					"goal_id":    goalID,
+					"session_id": sessionID,
+					"question":   fmt.Sprintf("Load test question %d from %s?", n, sessionID),
+				}, nil)
+				rec.record(PathAskAnswer, time.Since(began), err)
+				if err != nil {
+					return
+				}
+			}
+			postJSON(client, baseURL+"/api/executor/stop",
+				map[string]string{"goal_id": goalID, "session_id": sessionID, "reason": "completed"}, nil)
+		}(i)
+	}
+
+	// One answerer polls for questions until every executor is done. With
+	// Cycles, that also ends the run.
+	executorsDone := make(chan struct{})
+	go func() {
+		execWG.Wait()
+		close(executorsDone)
+		if cfg.Cycles > 0 {
+			cancel()
+		}
+	}()
+	var answerWG sync.WaitGroup
+	if cfg.Executors > 0 {
+		answerWG.Add(1)
+		go func() {
+			defer answerWG.Done()
+			answerQuestions(client, baseURL, rec, executorsDone)
+		}()
+	}
+
+	// Readers cycle through the paths the UI hits most
+	var readWG sync.WaitGroup
+	for i := 0; i < cfg.Readers; i++ {
+		readWG.Add(1)
+		go func(i int) {
+			defer readWG.Done()
+			for n := i; ctx.Err() == nil || n < i+3; n++ {
+				goalID := goalIDs[n%len(goalIDs)]
+				var path, url string
+				switch n % 3 {
+				case 0:
+					path, url = PathListGoals, baseURL+"/api/goals"
+				case 1:
+					path, url = PathGoalDetail, baseURL+"/api/goals/"+goalID
+				default:
+					path, url = PathGoalChat, baseURL+"/api/goals/"+goalID+"/chat"
+				}
+				// The first round of each kind is never cut off
+				reqCtx := ctx
+				if n < i+3 {
+					reqCtx = context.WithoutCancel(ctx)
+				}
+				began := time.Now()
+				err := get(reqCtx, client, url, nil)
+				if reqCtx.Err() != nil {
+					return // Cut off by the end of the run
+				}
+				rec.record(path, time.Since(began), err)
+			}
+		}(i)
+	}
+
+	readWG.Wait()
+	execWG.Wait()
+	answerWG.Wait()
+	sseWG.Wait()
+
+	return &Report{
+		Duration:  time.Since(start),
+		Paths:     rec.stats(),
+		SSEEvents: atomic.LoadInt64(&sseEvents),
+	}, nil
+}
+
+// answerQuestions answers pending questions until done is closed and no
+// question is left
+func answerQuestions(client *http.Client, baseURL string, rec *recorder, done <-chan struct{}) {
+	for {
+		var questions []struct {
+			ID string `json:"id"`
+		}
+		began := time.Now()
+		err := get(context.Background(), client, baseURL+"/api/questions", &questions)
+		rec.record(PathQuestions, time.Since(began), err)
+
+		for _, q := range questions {
+			began := time.Now()
+			err := postJSON(client, baseURL+"/api/answer/"+q.ID, map[string]string{"answer": "yes", "user": "loadtest"}, nil)
+			rec.record(PathAnswer, time.Since(began), err)
+		}
+
+		if len(questions) == 0 {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}
+}
+
+// subscribe reads the SSE stream at url until ctx is done, counting events.
+// connected is called once the stream is open (or failed to open).
+func subscribe(ctx context.Context, client *http.Client, url string, events *int64, connected func()) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		connected()
+		return
+	}
+	resp, err := client.Do(req)
+	connected()
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "event: ") {
+			atomic.AddInt64(events, 1)
+		}
+	}
+}
+
+// get fetches url, decoding a JSON response into out when it's not nil
+func get(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return readResponse(resp, out)
+}
+
+// postJSON posts body as JSON, decoding the response into out when it's not nil
+func postJSON(client *http.Client, url string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return readResponse(resp, out)
+}
+
+func readResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
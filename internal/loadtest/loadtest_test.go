@@ -0,0 +1,79 @@
+package loadtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/api"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	ids, err := WriteFixture(dir, 10, 20)
+	if err != nil {
+		t.Fatalf("WriteFixture failed: %v", err)
+	}
+	if len(ids) != 10 {
+		t.Fatalf("expected 10 goals, got %d", len(ids))
+	}
+
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux, hub.New(dir), goals.NewParser(dir))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	report, err := Run(context.Background(), server.URL, ids, Config{
+		Executors:  3,
+		SSEClients: 2,
+		Readers:    2,
+		Cycles:     2,
+		Duration:   time.Minute, // Only a timeout: the run ends after the cycles
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	byPath := make(map[string]PathStats)
+	for _, p := range report.Paths {
+		byPath[p.Path] = p
+		if p.Errors > 0 {
+			t.Errorf("%s: %d errors", p.Path, p.Errors)
+		}
+	}
+	for _, path := range []string{PathListGoals, PathGoalDetail, PathGoalChat, PathAskAnswer, PathAnswer} {
+		if byPath[path].Requests == 0 {
+			t.Errorf("expected requests for %s, got %+v", path, report.Paths)
+		}
+	}
+	if report.SSEEvents == 0 {
+		t.Error("expected SSE clients to receive events")
+	}
+
+	var out strings.Builder
+	report.Print(&out)
+	if !strings.Contains(out.String(), PathAskAnswer) {
+		t.Errorf("expected the ask path in the printed report:\n%s", out.String())
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 100)
+	for i := range samples {
+		samples[i] = time.Duration(i+1) * time.Millisecond
+	}
+	if p := percentile(samples, 50); p != 50*time.Millisecond {
+		t.Errorf("p50 = %s, want 50ms", p)
+	}
+	if p := percentile(samples, 99); p != 99*time.Millisecond {
+		t.Errorf("p99 = %s, want 99ms", p)
+	}
+	if p := percentile(samples[:1], 95); p != time.Millisecond {
+		t.Errorf("p95 of one sample = %s, want 1ms", p)
+	}
+}
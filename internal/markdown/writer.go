@@ -3,9 +3,10 @@ package markdown
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
 )
 
 // Writer handles writing Q&A to goal markdown files
@@ -29,11 +30,10 @@ func (w *Writer) WriteQA(goalID string, sessionID, question, answer string) erro
 	defer w.mu.Unlock()
 
 	// Find the goal file
-	goalFile := filepath.Join(w.dir, "goals", "active", goalID+".md")
-
-	// Check if file exists
-	if _, err := os.Stat(goalFile); os.IsNotExist(err) {
-		return fmt.Errorf("goal file not found: %s", goalFile)
+	l := layout.New(w.dir)
+	goalFile := l.FindGoalFileIn(layout.ActiveDir, goalID)
+	if goalFile == "" {
+		return fmt.Errorf("goal file not found: %s", l.GoalFile(layout.ActiveDir, goalID))
 	}
 
 	// Read existing content
@@ -83,11 +83,10 @@ func (w *Writer) WriteExecutorEvent(goalID string, sessionID, eventType, detail
 	defer w.mu.Unlock()
 
 	// Find the goal file
-	goalFile := filepath.Join(w.dir, "goals", "active", goalID+".md")
-
-	// Check if file exists
-	if _, err := os.Stat(goalFile); os.IsNotExist(err) {
-		return fmt.Errorf("goal file not found: %s", goalFile)
+	l := layout.New(w.dir)
+	goalFile := l.FindGoalFileIn(layout.ActiveDir, goalID)
+	if goalFile == "" {
+		return fmt.Errorf("goal file not found: %s", l.GoalFile(layout.ActiveDir, goalID))
 	}
 
 	// Read existing content
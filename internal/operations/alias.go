@@ -0,0 +1,78 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// SetGoalAlias gives a goal an alias usable wherever its ID is (see
+// goals.NormalizeAlias), or removes it when alias is empty. Returns the alias
+// as stored.
+func SetGoalAlias(vegaDir, goalID, alias string) (*Result, string) {
+	if errResult := checkInputs(idInput("goal ID", goalID)); errResult != nil {
+		return errResult, ""
+	}
+	alias, err := goals.NormalizeAlias(alias)
+	if err != nil {
+		return invalidAliasResult(err), ""
+	}
+
+	lockMgr := hub.NewLockManager(vegaDir)
+	err = lockMgr.WithRegistryLock("set-goal-alias", func() error {
+		return goals.NewRegistry(vegaDir).SetAlias(goalID, alias)
+	})
+	switch {
+	case errors.Is(err, goals.ErrNotFound):
+		return goalNotFoundResult(goalID), ""
+	case errors.Is(err, goals.ErrAliasTaken):
+		return aliasTakenResult(alias, err), ""
+	case err != nil:
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, ""
+	}
+	return &Result{Success: true}, alias
+}
+
+// checkAliasFree reports an alias another goal already has, before a goal
+// is created with it
+func checkAliasFree(vegaDir, alias string) *Result {
+	if alias == "" {
+		return nil
+	}
+	if id := goals.ResolveGoalID(vegaDir, alias); id != alias {
+		return aliasTakenResult(alias, fmt.Errorf("%w: %s has alias %q", goals.ErrAliasTaken, id, alias))
+	}
+	return nil
+}
+
+// invalidAliasResult reports an alias that can't be used
+func invalidAliasResult(err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "invalid_input",
+			Message: err.Error(),
+			Details: map[string]string{"field": "alias"},
+		},
+	}
+}
+
+func aliasTakenResult(alias string, err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "alias_taken",
+			Message: fmt.Sprintf("Alias '%s' is already used by another goal", alias),
+			Details: map[string]string{"alias": alias, "error": err.Error()},
+		},
+	}
+}
@@ -0,0 +1,202 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// ActivateOptions contains options for activating a draft goal
+type ActivateOptions struct {
+	GoalID     string
+	BaseBranch string // Optional override of the project's base branch
+	NoWorktree bool   // Leave the goal for ProvisionGoalWorktree
+	User       string // Recorded on the goal's state transitions
+	VegaDir    string
+
+	// SkipWorktree activates a goal that never gets a worktree: it goes
+	// straight to working
+	SkipWorktree bool
+}
+
+// ActivateGoal moves a draft goal out of the backlog: its file goes to
+// goals/active, its registry status to active, and its branch and worktree
+// are created as for a new goal. The branch name uses the goal's title and
+// tracker ID at activation.
+func ActivateGoal(opts ActivateOptions) (*Result, *CreateResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), refInput("base branch", opts.BaseBranch)); errResult != nil {
+		return errResult, nil
+	}
+
+	goalFile, status := goals.NewParser(opts.VegaDir).GoalFile(opts.GoalID)
+	registry := goals.NewRegistry(opts.VegaDir)
+	entry, err := registry.Get(opts.GoalID)
+	if goalFile == "" || err != nil {
+		return goalNotFoundResult(opts.GoalID), nil
+	}
+	if status != goals.StatusDraft {
+		return notDraftResult(opts.GoalID, status), nil
+	}
+	if len(entry.Projects) == 0 {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "project_required",
+				Message: fmt.Sprintf("Draft goal '%s' has no project", opts.GoalID),
+				Details: map[string]string{"goal_id": opts.GoalID},
+			},
+		}, nil
+	}
+	projectName := entry.Projects[0]
+	project, err := goals.ParseProject(opts.VegaDir, projectName)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "project_not_found",
+				Message: fmt.Sprintf("Project '%s' not found", projectName),
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+
+	baseBranch := opts.BaseBranch
+	if baseBranch == "" {
+		baseBranch = project.BaseBranch
+	}
+	if baseBranch == "" {
+		baseBranch = "main"
+	}
+	title := getGoalTitle(goalFile, opts.GoalID)
+	branchName := goals.IssueBranch(entry.TrackerID, fmt.Sprintf("goal-%s-%s", opts.GoalID, slugify(title)))
+	if project.IsPlain() {
+		baseBranch, branchName = "", ""
+	}
+
+	// Move the goal file (or its folder) to goals/active
+	l := layout.New(opts.VegaDir)
+	goalFile, err = l.MoveGoal(opts.GoalID, layout.BacklogDir, layout.ActiveDir)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "file_move_failed",
+				Message: "Could not move the goal file out of the backlog",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+
+	lockMgr := hub.NewLockManager(opts.VegaDir)
+	if err := lockMgr.WithRegistryLock("activate-goal", func() error {
+		return registry.Update(opts.GoalID, func(e *goals.RegistryEntry) {
+			e.Status = "active"
+			e.Title = title
+			e.UpdatedAt = time.Now().Format(time.RFC3339)
+		})
+	}); err != nil {
+		l.MoveGoal(opts.GoalID, layout.ActiveDir, layout.BacklogDir)
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+	SyncProjectGoals(opts.VegaDir, entry.Projects...)
+
+	result := &CreateResult{
+		GoalID:     opts.GoalID,
+		Title:      title,
+		Project:    projectName,
+		BaseBranch: baseBranch,
+		GoalBranch: branchName,
+		GoalFile:   goalFile,
+		ParentID:   entry.ParentID,
+		IssueURL:   entry.IssueURL,
+		TrackerID:  entry.TrackerID,
+	}
+	if warning := StaleBaseWarning(opts.VegaDir, projectName, baseBranch); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+	switch {
+	case opts.SkipWorktree:
+		sm := goals.NewStateManager(opts.VegaDir)
+		sm.TransitionWithUser(opts.GoalID, goals.StatePending, "Goal activated", opts.User, nil)
+		if err := readyWithoutWorktree(sm, opts.GoalID, opts.User); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+	case !opts.NoWorktree:
+		if errResult := ProvisionGoalWorktree(opts.VegaDir, result, ProvisionOptions{User: opts.User}); errResult != nil {
+			return errResult, result
+		}
+	}
+	return &Result{Success: true}, result
+}
+
+// SetGoalPriority sets a draft goal's place in the backlog (highest first)
+func SetGoalPriority(vegaDir, goalID string, priority int) *Result {
+	if errResult := checkInputs(idInput("goal ID", goalID)); errResult != nil {
+		return errResult
+	}
+
+	var status string
+	lockMgr := hub.NewLockManager(vegaDir)
+	err := lockMgr.WithRegistryLock("set-goal-priority", func() error {
+		registry := goals.NewRegistry(vegaDir)
+		entry, err := registry.Get(goalID)
+		if err != nil {
+			return err
+		}
+		if status = entry.Status; status != goals.StatusDraft {
+			return nil
+		}
+		return registry.SetPriority(goalID, priority)
+	})
+	switch {
+	case errors.Is(err, goals.ErrNotFound):
+		return goalNotFoundResult(goalID)
+	case err != nil:
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}
+	case status != goals.StatusDraft:
+		return notDraftResult(goalID, status)
+	}
+	return &Result{Success: true}
+}
+
+// notDraftResult is the failure returned for backlog operations on goals
+// that were already activated
+func notDraftResult(goalID, status string) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "not_draft",
+			Message: fmt.Sprintf("Goal '%s' is not a draft (status: %s)", goalID, status),
+			Details: map[string]string{"goal_id": goalID, "status": status},
+		},
+	}
+}
+
+func goalNotFoundResult(goalID string) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "goal_not_found",
+			Message: fmt.Sprintf("Goal '%s' not found", goalID),
+			Details: map[string]string{"goal_id": goalID},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestDraftGoalBacklog(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+
+	result, low := CreateGoal(CreateOptions{Title: "Add caching", Project: "my-api", Draft: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	if !low.Draft || low.GoalBranch != "" || low.WorktreePath != "" {
+		t.Errorf("a draft shouldn't get a branch or worktree: %+v", low)
+	}
+	if _, err := os.Stat(filepath.Join(vegaDir, "goals", "backlog", low.GoalID+".md")); err != nil {
+		t.Errorf("expected the goal file in the backlog: %v", err)
+	}
+	_, high := CreateGoal(CreateOptions{Title: "Fix login", Project: "my-api", Draft: true, Priority: 1, VegaDir: vegaDir})
+
+	backlog, err := goals.NewRegistry(vegaDir).Backlog()
+	if err != nil || len(backlog) != 2 || backlog[0].ID != high.GoalID || backlog[0].Status != goals.StatusDraft {
+		t.Fatalf("expected the higher priority draft first: %+v, %v", backlog, err)
+	}
+	if result := SetGoalPriority(vegaDir, low.GoalID, 5); !result.Success {
+		t.Fatalf("SetGoalPriority: %+v", result.Error)
+	}
+	if backlog, _ := goals.NewRegistry(vegaDir).Backlog(); backlog[0].ID != low.GoalID {
+		t.Errorf("expected the reprioritized draft first: %+v", backlog)
+	}
+
+	result, activated := ActivateGoal(ActivateOptions{GoalID: low.GoalID, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("ActivateGoal: %+v", result.Error)
+	}
+	if activated.GoalBranch != "goal-"+low.GoalID+"-add-caching" || activated.WorktreePath == "" {
+		t.Errorf("expected a branch and worktree: %+v", activated)
+	}
+	if _, err := os.Stat(activated.WorktreePath); err != nil {
+		t.Errorf("worktree not created: %v", err)
+	}
+	detail, err := goals.NewParser(vegaDir).ParseGoalDetail(low.GoalID)
+	if err != nil || detail.Status != "active" || detail.Worktree == nil {
+		t.Errorf("expected an active goal with its worktree recorded: %+v, %v", detail, err)
+	}
+	if entry, _ := goals.NewRegistry(vegaDir).Get(low.GoalID); entry.Status != "active" {
+		t.Errorf("registry status = %s", entry.Status)
+	}
+
+	if result, _ := ActivateGoal(ActivateOptions{GoalID: low.GoalID, VegaDir: vegaDir}); result.Success || result.Error.Code != "not_draft" {
+		t.Errorf("expected not_draft, got %+v", result.Error)
+	}
+	if result := SetGoalPriority(vegaDir, low.GoalID, 1); result.Success || result.Error.Code != "not_draft" {
+		t.Errorf("expected not_draft, got %+v", result.Error)
+	}
+}
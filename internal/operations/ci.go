@@ -0,0 +1,376 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// The CI monitor polls the git provider for the checks (GitHub) or latest
+// pipeline (GitLab) of each active goal's pushed branch, so a failing build is
+// visible before anyone completes the goal. Results are kept in
+// .vega-hub-ci-status.json.
+const DefaultCICheckInterval = 5 * time.Minute
+
+// CI states of a goal branch
+const (
+	CIPassed  = "passed"
+	CIFailed  = "failed"
+	CIPending = "pending"
+	CINone    = "none" // No checks or pipeline for the branch (yet)
+)
+
+// CICheck is one check run or pipeline of a goal branch
+type CICheck struct {
+	Name  string `json:"name"`
+	State string `json:"state"` // passed, failed or pending
+	URL   string `json:"url,omitempty"`
+}
+
+// CIStatus is the CI state of a goal branch at its last check
+type CIStatus struct {
+	GoalID    string    `json:"goal_id"`
+	Project   string    `json:"project"`
+	Branch    string    `json:"branch"`
+	Provider  string    `json:"provider"` // "github" or "gitlab"
+	State     string    `json:"state"`    // passed, failed, pending or none
+	Commit    string    `json:"commit,omitempty"`
+	URL       string    `json:"url,omitempty"` // Pipeline or first failing check
+	Checks    []CICheck `json:"checks,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	Error     string    `json:"error,omitempty"` // Provider error from the last check; State is the previous one
+}
+
+// ciFetcher asks a provider for the CI state of branch, running its CLI in
+// the goal's worktree (replaced in tests)
+var ciFetcher = fetchCIStatus
+
+// ciMu serializes writes to the CI status file
+var ciMu sync.Mutex
+
+// ciStatusPath returns the file holding the last CI check of each goal
+func ciStatusPath(vegaDir string) string {
+	return filepath.Join(vegaDir, ".vega-hub-ci-status.json")
+}
+
+// LoadCIStatus returns the last recorded CI check of each goal, by goal ID.
+// A missing file yields an empty map.
+func LoadCIStatus(vegaDir string) (map[string]CIStatus, error) {
+	records := make(map[string]CIStatus)
+	data, err := os.ReadFile(ciStatusPath(vegaDir))
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(ciStatusPath(vegaDir)), err)
+	}
+	return records, nil
+}
+
+// GetCIStatus returns the last recorded CI check of a goal, or nil if it
+// hasn't been checked yet
+func GetCIStatus(vegaDir, goalID string) *CIStatus {
+	records, err := LoadCIStatus(vegaDir)
+	if err != nil {
+		return nil
+	}
+	if s, ok := records[goalID]; ok {
+		return &s
+	}
+	return nil
+}
+
+// updateCIStatus records the checks of the given goals and forgets goals
+// that are no longer monitored (nil keep keeps all others)
+func updateCIStatus(vegaDir string, checked []CIStatus, keep map[string]bool) error {
+	ciMu.Lock()
+	defer ciMu.Unlock()
+	records, err := LoadCIStatus(vegaDir)
+	if err != nil {
+		records = make(map[string]CIStatus)
+	}
+	if keep != nil {
+		for id := range records {
+			if !keep[id] {
+				delete(records, id)
+			}
+		}
+	}
+	for _, s := range checked {
+		records[s.GoalID] = s
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := ciStatusPath(vegaDir)
+	return atomicfile.WriteFile(path, data, 0644)
+}
+
+// ciProvider names the provider of a git remote ("" if CI can't be polled)
+func ciProvider(remote string) string {
+	lower := strings.ToLower(remote)
+	switch {
+	case strings.Contains(lower, "github.com"):
+		return "github"
+	case strings.Contains(lower, "gitlab"):
+		return "gitlab"
+	}
+	return ""
+}
+
+// CheckGoalCI asks the provider for the CI state of a goal's branch and
+// records it. changed is true when the state differs from the last check. A
+// provider error is recorded in Error and keeps the previous state.
+func CheckGoalCI(vegaDir, goalID, project string) (status *CIStatus, changed bool, err error) {
+	s, err := checkGoalCI(vegaDir, goalID, project)
+	if err != nil {
+		return nil, false, err
+	}
+	previous := GetCIStatus(vegaDir, goalID)
+	changed = ciStateChanged(previous, s)
+	if err := updateCIStatus(vegaDir, []CIStatus{*s}, nil); err != nil {
+		return s, changed, fmt.Errorf("failed to record CI status: %w", err)
+	}
+	return s, changed, nil
+}
+
+// checkGoalCI fetches the CI state of a goal's branch without recording it
+func checkGoalCI(vegaDir, goalID, project string) (*CIStatus, error) {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return nil, fmt.Errorf("project %s not found: %w", project, err)
+	}
+	provider := ciProvider(p.GitRemote)
+	if provider == "" || p.IsPlain() {
+		return nil, fmt.Errorf("project %s is not hosted on GitHub or GitLab", project)
+	}
+	worktree, err := FindGoalWorktree(vegaDir, project, goalID)
+	if err != nil {
+		return nil, err
+	}
+	branch := gitsvc.NewExec().CurrentBranch(worktree)
+	if branch == "" {
+		return nil, fmt.Errorf("goal %s has no branch checked out", goalID)
+	}
+
+	s := &CIStatus{GoalID: goalID, Project: project, Branch: branch, Provider: provider, State: CINone, CheckedAt: time.Now()}
+	fetched, err := ciFetcher(provider, worktree, branch)
+	if err != nil {
+		s.Error = err.Error()
+		if previous := GetCIStatus(vegaDir, goalID); previous != nil && previous.Branch == branch {
+			s.State, s.Commit, s.URL, s.Checks = previous.State, previous.Commit, previous.URL, previous.Checks
+		}
+		return s, nil
+	}
+	s.State, s.Commit, s.URL, s.Checks = fetched.State, fetched.Commit, fetched.URL, fetched.Checks
+	return s, nil
+}
+
+// ciStateChanged reports whether a check moved a goal to another CI state
+func ciStateChanged(previous, current *CIStatus) bool {
+	if previous == nil {
+		return current.State != CINone
+	}
+	return previous.State != current.State
+}
+
+// MaintainGoalCI checks the CI state of every active goal whose project is
+// hosted on GitHub or GitLab and returns the goals whose state changed.
+// Goals that are no longer active are dropped from the records.
+func MaintainGoalCI(vegaDir string) (changed []CIStatus) {
+	entries, err := goals.NewRegistry(vegaDir).List(func(e goals.RegistryEntry) bool { return e.Status == "active" })
+	if err != nil {
+		return nil
+	}
+	previous, _ := LoadCIStatus(vegaDir)
+	hosted := make(map[string]bool)
+	keep := make(map[string]bool)
+	var checked []CIStatus
+	for _, e := range entries {
+		if len(e.Projects) == 0 {
+			continue
+		}
+		project := e.Projects[0]
+		if _, ok := hosted[project]; !ok {
+			p, err := goals.ParseProject(vegaDir, project)
+			hosted[project] = err == nil && !p.IsPlain() && ciProvider(p.GitRemote) != ""
+		}
+		if !hosted[project] {
+			continue
+		}
+		s, err := checkGoalCI(vegaDir, e.ID, project)
+		if err != nil {
+			continue // No worktree: nothing pushed from here to check
+		}
+		keep[e.ID] = true
+		checked = append(checked, *s)
+		if s.Error != "" {
+			log.Printf("[CI] %s: %s", e.ID, s.Error)
+		}
+		var last *CIStatus
+		if p, ok := previous[e.ID]; ok {
+			last = &p
+		}
+		if ciStateChanged(last, s) {
+			changed = append(changed, *s)
+		}
+	}
+	if err := updateCIStatus(vegaDir, checked, keep); err != nil {
+		log.Printf("[CI] Failed to record CI status: %v", err)
+	}
+	return changed
+}
+
+// StartCIMonitor checks goal CI states in the background, once right away
+// and then every interval, until stop is closed. onChange, if set, is called
+// for each goal whose state changed.
+func StartCIMonitor(vegaDir string, interval time.Duration, onChange func(CIStatus), stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, s := range MaintainGoalCI(vegaDir) {
+				log.Printf("[CI] %s: %s is %s", s.GoalID, s.Branch, s.State)
+				if onChange != nil {
+					onChange(s)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// fetchCIStatus asks gh or glab, run in worktree, for the CI state of branch
+func fetchCIStatus(provider, worktree, branch string) (*CIStatus, error) {
+	var cmd *exec.Cmd
+	switch provider {
+	case "github":
+		// {owner}/{repo} are filled in by gh from the worktree's remote
+		cmd = exec.Command("gh", "api", "repos/{owner}/{repo}/commits/"+url.PathEscape(branch)+"/check-runs?per_page=100")
+	case "gitlab":
+		cmd = exec.Command("glab", "api", "projects/:id/pipelines?per_page=1&ref="+url.QueryEscape(branch))
+	default:
+		return nil, fmt.Errorf("unsupported CI provider %q", provider)
+	}
+	cmd.Dir = worktree
+	output, err := cmd.Output()
+	if err != nil {
+		detail := err.Error()
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			detail = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		if provider == "github" && strings.Contains(detail, "No commit found") {
+			return &CIStatus{State: CINone}, nil // Branch not pushed yet
+		}
+		return nil, fmt.Errorf("%s api failed: %s", cmd.Args[0], detail)
+	}
+	if provider == "github" {
+		return parseGitHubChecks(output)
+	}
+	return parseGitLabPipelines(output)
+}
+
+// parseGitHubChecks summarizes a GitHub check-runs response: failed if any
+// run failed, pending while any is running, passed once all succeeded
+func parseGitHubChecks(data []byte) (*CIStatus, error) {
+	var resp struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			HeadSHA    string `json:"head_sha"`
+			Status     string `json:"status"`     // queued, in_progress, completed
+			Conclusion string `json:"conclusion"` // success, failure, neutral, cancelled, skipped, timed_out, action_required
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid check-runs response: %w", err)
+	}
+	s := &CIStatus{State: CINone}
+	for _, run := range resp.CheckRuns {
+		check := CICheck{Name: run.Name, URL: run.HTMLURL, State: CIPassed}
+		switch {
+		case run.Status != "completed":
+			check.State = CIPending
+		case run.Conclusion == "failure" || run.Conclusion == "timed_out" || run.Conclusion == "cancelled" || run.Conclusion == "action_required":
+			check.State = CIFailed
+		}
+		s.Checks = append(s.Checks, check)
+		s.Commit = run.HeadSHA
+	}
+	s.State = combineCIStates(s.Checks)
+	for _, c := range s.Checks {
+		if c.State == s.State && s.URL == "" {
+			s.URL = c.URL
+		}
+	}
+	return s, nil
+}
+
+// parseGitLabPipelines reads the state of the latest pipeline of a GitLab
+// pipelines response
+func parseGitLabPipelines(data []byte) (*CIStatus, error) {
+	var pipelines []struct {
+		ID     int    `json:"id"`
+		SHA    string `json:"sha"`
+		Status string `json:"status"` // created, pending, running, success, failed, canceled, skipped, manual, ...
+		WebURL string `json:"web_url"`
+	}
+	if err := json.Unmarshal(data, &pipelines); err != nil {
+		return nil, fmt.Errorf("invalid pipelines response: %w", err)
+	}
+	if len(pipelines) == 0 {
+		return &CIStatus{State: CINone}, nil
+	}
+	p := pipelines[0]
+	state := CIPending
+	switch p.Status {
+	case "success", "skipped":
+		state = CIPassed
+	case "failed", "canceled":
+		state = CIFailed
+	}
+	return &CIStatus{
+		State:  state,
+		Commit: p.SHA,
+		URL:    p.WebURL,
+		Checks: []CICheck{{Name: fmt.Sprintf("pipeline #%d", p.ID), State: state, URL: p.WebURL}},
+	}, nil
+}
+
+// combineCIStates is the state of a set of checks: failed beats pending
+// beats passed, none without checks
+func combineCIStates(checks []CICheck) string {
+	if len(checks) == 0 {
+		return CINone
+	}
+	state := CIPassed
+	for _, c := range checks {
+		switch c.State {
+		case CIFailed:
+			return CIFailed
+		case CIPending:
+			state = CIPending
+		}
+	}
+	return state
+}
@@ -0,0 +1,83 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestParseGitHubChecks(t *testing.T) {
+	data := []byte(`{"check_runs": [
+		{"name": "lint", "head_sha": "abc", "status": "completed", "conclusion": "success", "html_url": "https://ci/lint"},
+		{"name": "test", "head_sha": "abc", "status": "in_progress", "conclusion": null, "html_url": "https://ci/test"},
+		{"name": "docs", "head_sha": "abc", "status": "completed", "conclusion": "skipped", "html_url": "https://ci/docs"}
+	]}`)
+	s, err := parseGitHubChecks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State != CIPending || s.Commit != "abc" || len(s.Checks) != 3 || s.URL != "https://ci/test" {
+		t.Errorf("expected pending on test: %+v", s)
+	}
+
+	s, _ = parseGitHubChecks([]byte(`{"check_runs": [{"name": "test", "status": "completed", "conclusion": "failure", "html_url": "https://ci/test"}]}`))
+	if s.State != CIFailed || s.URL != "https://ci/test" {
+		t.Errorf("expected failed: %+v", s)
+	}
+	if s, _ := parseGitHubChecks([]byte(`{"check_runs": []}`)); s.State != CINone {
+		t.Errorf("expected none without checks, got %s", s.State)
+	}
+}
+
+func TestParseGitLabPipelines(t *testing.T) {
+	tests := map[string]string{"success": CIPassed, "failed": CIFailed, "running": CIPending, "manual": CIPending}
+	for status, want := range tests {
+		s, err := parseGitLabPipelines([]byte(`[{"id": 7, "sha": "abc", "status": "` + status + `", "web_url": "https://gitlab/p/7"}]`))
+		if err != nil || s.State != want || s.URL != "https://gitlab/p/7" {
+			t.Errorf("%s: got %+v, %v; want %s", status, s, err, want)
+		}
+	}
+	if s, _ := parseGitLabPipelines([]byte(`[]`)); s.State != CINone {
+		t.Errorf("expected none without pipelines, got %s", s.State)
+	}
+}
+
+func TestMaintainGoalCI(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	os.WriteFile(filepath.Join(vegaDir, "projects", "my-api.md"), []byte("# Project: my-api\n\n**Upstream**: `https://github.com/acme/api.git`\n**Base Branch**: `main`\n"), 0644)
+	registry := goals.NewRegistry(vegaDir)
+	registry.Add(goals.RegistryEntry{ID: "abc1234", Title: "Fix login", Projects: []string{"my-api"}, Status: "active"})
+
+	state := CIPending
+	old := ciFetcher
+	ciFetcher = func(provider, worktree, branch string) (*CIStatus, error) {
+		if provider != "github" || branch != "goal-abc1234-fix" {
+			t.Errorf("unexpected fetch %s %s", provider, branch)
+		}
+		return &CIStatus{State: state, Checks: []CICheck{{Name: "test", State: state}}}, nil
+	}
+	defer func() { ciFetcher = old }()
+
+	changed := MaintainGoalCI(vegaDir)
+	if len(changed) != 1 || changed[0].State != CIPending {
+		t.Fatalf("expected the first check to report pending: %+v", changed)
+	}
+	if changed := MaintainGoalCI(vegaDir); len(changed) != 0 {
+		t.Errorf("an unchanged state shouldn't be reported: %+v", changed)
+	}
+	state = CIFailed
+	if changed := MaintainGoalCI(vegaDir); len(changed) != 1 || changed[0].State != CIFailed {
+		t.Errorf("expected a change to failed: %+v", changed)
+	}
+	if s := GetCIStatus(vegaDir, "abc1234"); s == nil || s.State != CIFailed || s.Branch != "goal-abc1234-fix" {
+		t.Errorf("expected the failed state recorded: %+v", s)
+	}
+
+	registry.Update("abc1234", func(e *goals.RegistryEntry) { e.Status = "completed" })
+	MaintainGoalCI(vegaDir)
+	if s := GetCIStatus(vegaDir, "abc1234"); s != nil {
+		t.Errorf("completed goals should be dropped: %+v", s)
+	}
+}
@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// cloneProject clones url into dest using the project's clone options and
+// narrows the checkout to its sparse paths
+func cloneProject(url, dest string, clone goals.CloneOptions) ([]byte, error) {
+	args := append([]string{"clone"}, clone.CloneArgs()...)
+	args = append(args, url, dest)
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return output, err
+	}
+	return output, ApplySparseCheckout(dest, clone)
+}
+
+// ApplySparseCheckout limits the checkout in dir to the sparse paths
+// (no-op for projects without sparse paths)
+func ApplySparseCheckout(dir string, clone goals.CloneOptions) error {
+	if !clone.IsSparse() {
+		return nil
+	}
+	args := append([]string{"-C", dir, "sparse-checkout", "set", "--cone"}, clone.SparsePaths...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git sparse-checkout: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SparseCheckoutWorktree applies a project's sparse paths to a goal worktree.
+// Projects without a readable config are checked out in full.
+func SparseCheckoutWorktree(vegaDir, project, worktree string) error {
+	return ApplySparseCheckout(worktree, getProjectCloneOptions(vegaDir, project))
+}
+
+// getProjectCloneOptions returns the project's clone options (empty if the config can't be read)
+func getProjectCloneOptions(vegaDir, project string) goals.CloneOptions {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return goals.CloneOptions{}
+	}
+	return p.CloneOptions
+}
+
+// formatCloneOptions renders the Clone Options section of a project config (empty if unset)
+func formatCloneOptions(clone goals.CloneOptions) string {
+	var lines []string
+	if clone.CloneDepth > 0 {
+		lines = append(lines, fmt.Sprintf("**Clone Depth**: `%d`", clone.CloneDepth))
+	}
+	if clone.CloneFilter != "" {
+		lines = append(lines, "**Clone Filter**: `"+clone.CloneFilter+"`")
+	}
+	if clone.IsSparse() {
+		quoted := make([]string, len(clone.SparsePaths))
+		for i, p := range clone.SparsePaths {
+			quoted[i] = "`" + p + "`"
+		}
+		lines = append(lines, "**Sparse Paths**: "+strings.Join(quoted, ", "))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n## Clone Options\n\n" + strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,115 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// CloneGoalOptions contains options for starting a new goal from an existing one
+type CloneGoalOptions struct {
+	SourceID   string
+	Title      string // Optional, defaults to the source goal's title
+	Project    string // Optional, defaults to the source goal's first project
+	BaseBranch string // Optional override
+	FromBranch bool   // Start from the source goal's branch instead of the base branch
+	NoWorktree bool
+	VegaDir    string
+}
+
+// CloneGoal creates a goal with a new ID and worktree whose phases,
+// acceptance criteria and notes are copied from the source goal (active,
+// iced or completed), with every task unchecked
+func CloneGoal(opts CloneGoalOptions) (*Result, *CreateResult) {
+	if errResult := checkInputs(
+		idInput("goal ID", opts.SourceID),
+		idInput("project", opts.Project),
+		refInput("base branch", opts.BaseBranch),
+	); errResult != nil {
+		return errResult, nil
+	}
+
+	parser := goals.NewParser(opts.VegaDir)
+	goalFile, _ := parser.GoalFile(opts.SourceID)
+	if goalFile == "" {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "goal_not_found",
+				Message: fmt.Sprintf("Goal '%s' not found", opts.SourceID),
+				Details: map[string]string{"goal_id": opts.SourceID},
+			},
+		}, nil
+	}
+	detail, err := parser.ParseGoalDetail(opts.SourceID)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "goal_parse_failed",
+				Message: fmt.Sprintf("Could not read goal '%s'", opts.SourceID),
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+	content, err := os.ReadFile(goalFile)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "goal_parse_failed",
+				Message: fmt.Sprintf("Could not read goal '%s'", opts.SourceID),
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = detail.Title
+	}
+	project := opts.Project
+	if project == "" && len(detail.Projects) > 0 {
+		project = detail.Projects[0]
+	}
+
+	baseBranch := opts.BaseBranch
+	if opts.FromBranch {
+		if project == "" {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "project_required",
+					Message: "Project is required to start from the source goal's branch",
+				},
+			}, nil
+		}
+		projectBase := layout.New(opts.VegaDir).WorktreeBase(project)
+		baseBranch, err = findGoalBranch(projectBase, opts.SourceID)
+		if err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "source_branch_not_found",
+					Message: fmt.Sprintf("Goal '%s' has no branch in project '%s' to start from", opts.SourceID, project),
+					Details: map[string]string{"error": err.Error()},
+				},
+			}, nil
+		}
+	}
+
+	result, created := CreateGoal(CreateOptions{
+		Title:      title,
+		Project:    project,
+		BaseBranch: baseBranch,
+		NoWorktree: opts.NoWorktree,
+		Body:       goals.GoalTemplate(string(content)),
+		VegaDir:    opts.VegaDir,
+	})
+	if created != nil {
+		created.ClonedFrom = opts.SourceID
+	}
+	return result, created
+}
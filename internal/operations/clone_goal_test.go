@@ -0,0 +1,78 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCloneGoal(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	source := `# Goal #abc1234: Fix login
+
+## Overview
+
+Fix the login flow.
+
+## Project(s)
+
+- **my-api**: auth endpoints
+
+## Phases
+
+### Phase 1: Reproduce
+- [x] Write a failing test
+
+## Acceptance Criteria
+
+- [x] Login works
+
+## Worktree
+- **Branch**: goal-abc1234-fix
+
+## Status
+
+Current Phase: 1/1
+`
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "abc1234.md"), []byte(source), 0644)
+
+	result, created := CloneGoal(CloneGoalOptions{SourceID: "abc1234", Title: "Fix signup", FromBranch: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("clone failed: %+v", result.Error)
+	}
+	if created.GoalID == "abc1234" || created.ClonedFrom != "abc1234" || created.Project != "my-api" {
+		t.Errorf("unexpected result: %+v", created)
+	}
+	if created.BaseBranch != "goal-abc1234-fix" {
+		t.Errorf("expected the source branch as base, got %q", created.BaseBranch)
+	}
+
+	// The worktree starts from the source goal's commits
+	if _, err := os.Stat(filepath.Join(created.WorktreePath, "a.txt")); err != nil {
+		t.Errorf("expected the source branch's files in the new worktree: %v", err)
+	}
+
+	data, err := os.ReadFile(created.GoalFile)
+	if err != nil {
+		t.Fatalf("failed to read cloned goal file: %v", err)
+	}
+	goal := string(data)
+	if !strings.HasPrefix(goal, "# Goal "+created.GoalID+": Fix signup\n") {
+		t.Errorf("expected new title line, got:\n%s", goal)
+	}
+	if !strings.Contains(goal, "- [ ] Write a failing test") || !strings.Contains(goal, "- [ ] Login works") {
+		t.Errorf("expected unchecked phases and criteria, got:\n%s", goal)
+	}
+	if strings.Contains(goal, "goal-abc1234-fix\n- **Project**") || strings.Count(goal, "## Worktree") != 1 {
+		t.Errorf("expected only the new worktree section, got:\n%s", goal)
+	}
+
+	// Unknown goals and missing branches are reported
+	if result, _ := CloneGoal(CloneGoalOptions{SourceID: "fffffff", VegaDir: vegaDir}); result.Success || result.Error.Code != "goal_not_found" {
+		t.Errorf("expected goal_not_found, got %+v", result.Error)
+	}
+	if result, _ := CloneGoal(CloneGoalOptions{SourceID: created.GoalID, Project: "my-api", FromBranch: true, VegaDir: vegaDir}); !result.Success {
+		t.Errorf("expected cloning the clone from its branch to work, got %+v", result.Error)
+	}
+}
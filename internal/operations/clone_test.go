@@ -0,0 +1,90 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestAddProjectFromURLSparseShallow(t *testing.T) {
+	vegaDir := t.TempDir()
+	upstream := filepath.Join(t.TempDir(), "upstream")
+
+	git := func(args ...string) string {
+		t.Helper()
+		args = append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test"}, args...)
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-b", "main", upstream)
+	for _, f := range []string{"README.md", "services/api/main.go", "web/index.html"} {
+		os.MkdirAll(filepath.Dir(filepath.Join(upstream, f)), 0755)
+		os.WriteFile(filepath.Join(upstream, f), []byte(f+"\n"), 0644)
+	}
+	git("-C", upstream, "add", ".")
+	git("-C", upstream, "commit", "-m", "Initial")
+	git("-C", upstream, "commit", "--allow-empty", "-m", "Second")
+	os.MkdirAll(filepath.Join(vegaDir, "projects"), 0755)
+
+	clone := goals.CloneOptions{CloneDepth: 1, SparsePaths: []string{"services/api"}}
+	result, data := AddProjectFromURL(AddProjectURLOptions{
+		Name:    "mono",
+		URL:     "file://" + upstream, // --depth is ignored for plain local paths
+		Clone:   clone,
+		VegaDir: vegaDir,
+	})
+	if !result.Success {
+		t.Fatalf("AddProjectFromURL failed: %+v", result.Error)
+	}
+	if data.CloneDepth != 1 {
+		t.Errorf("expected clone options in result: %+v", data)
+	}
+
+	base := data.WorktreePath
+	if git("-C", base, "rev-parse", "--is-shallow-repository") != "true" {
+		t.Error("worktree-base should be a shallow clone")
+	}
+	if _, err := os.Stat(filepath.Join(base, "services", "api", "main.go")); err != nil {
+		t.Errorf("sparse path should be checked out: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "web")); !os.IsNotExist(err) {
+		t.Error("paths outside the sparse set should not be checked out")
+	}
+
+	// The options are recorded in the project config and reach goal worktrees
+	project, err := goals.ParseProject(vegaDir, "mono")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.CloneDepth != 1 || len(project.SparsePaths) != 1 {
+		t.Errorf("expected clone options in project config: %+v", project.CloneOptions)
+	}
+	worktree := filepath.Join(vegaDir, "workspaces", "mono", "goal-abc1234-fix")
+	if err := createWorktree(gitsvc.GitBackend{}, base, worktree, "goal-abc1234-fix", "main", project.CloneOptions); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "web")); !os.IsNotExist(err) {
+		t.Error("goal worktree should inherit the sparse checkout")
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "services", "api", "main.go")); err != nil {
+		t.Errorf("goal worktree should check out the sparse path: %v", err)
+	}
+
+	result, _ = AddProjectFromURL(AddProjectURLOptions{
+		Name:    "bad",
+		URL:     upstream,
+		Clone:   goals.CloneOptions{CloneFilter: "everything"},
+		VegaDir: vegaDir,
+	})
+	if result.Success || result.Error.Code != "invalid_clone_options" {
+		t.Errorf("expected invalid_clone_options, got %+v", result)
+	}
+}
@@ -0,0 +1,85 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/pathguard"
+)
+
+// CommitPolicyReport is the result of checking a goal branch against its
+// project's commit policy
+type CommitPolicyReport struct {
+	GoalID     string                  `json:"goal_id"`
+	Project    string                  `json:"project"`
+	Branch     string                  `json:"branch"`
+	BaseBranch string                  `json:"base_branch"`
+	Policy     goals.CommitPolicy      `json:"policy"`
+	Passed     bool                    `json:"passed"`
+	Violations []goals.CommitViolation `json:"violations"`
+}
+
+// CheckCommitPolicy checks a goal's branch against its project's commit
+// policy without completing the goal
+func CheckCommitPolicy(vegaDir, project, goalID string) (*CommitPolicyReport, error) {
+	if err := pathguard.ValidateID("goal ID", goalID); err != nil {
+		return nil, err
+	}
+	if err := pathguard.ValidateID("project", project); err != nil {
+		return nil, err
+	}
+	baseBranch, err := GoalBaseBranch(vegaDir, goalID, project)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine base branch for project '%s': %w", project, err)
+	}
+	worktreeDir, err := FindGoalWorktree(vegaDir, project, goalID)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := getWorktreeBranch(worktreeDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine branch name from worktree: %w", err)
+	}
+
+	policy := getProjectCommitPolicy(vegaDir, project)
+	violations, err := policy.CheckCommits(worktreeDir, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitPolicyReport{
+		GoalID:     goalID,
+		Project:    project,
+		Branch:     branch,
+		BaseBranch: baseBranch,
+		Policy:     policy,
+		Passed:     len(violations) == 0,
+		Violations: violations,
+	}, nil
+}
+
+// getProjectCommitPolicy returns the project's commit policy (empty if the config can't be read)
+func getProjectCommitPolicy(vegaDir, project string) goals.CommitPolicy {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return goals.CommitPolicy{}
+	}
+	return p.CommitPolicy
+}
+
+// commitPolicyViolationResult is the failure returned when a goal branch
+// breaks its project's commit policy; Data carries the violations
+func commitPolicyViolationResult(project, branch string, violations []goals.CommitViolation) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "commit_policy_violation",
+			Message: fmt.Sprintf("%d commit policy violation(s) on branch '%s'; fix the commits (e.g. git rebase -i) before completing", len(violations), branch),
+			Details: map[string]string{
+				"project":    project,
+				"branch":     branch,
+				"violations": goals.SummarizeViolations(violations),
+			},
+		},
+		Data: violations,
+	}
+}
@@ -0,0 +1,41 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestCompleteGoalCommitPolicy(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "**Commit Convention**: `conventional`\n")
+
+	report, err := CheckCommitPolicy(vegaDir, "my-api", "abc1234")
+	if err != nil {
+		t.Fatalf("CheckCommitPolicy failed: %v", err)
+	}
+	if report.Passed || len(report.Violations) != 2 || report.Branch != "goal-abc1234-fix" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	result, _ := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "commit_policy_violation" {
+		t.Fatalf("expected commit_policy_violation, got %+v", result.Error)
+	}
+	if violations, ok := result.Data.([]goals.CommitViolation); !ok || len(violations) != 2 {
+		t.Errorf("expected violations in result data, got %+v", result.Data)
+	}
+	if _, err := os.Stat(filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")); err != nil {
+		t.Error("worktree should be untouched when the commit policy fails")
+	}
+}
+
+func TestCompleteGoalWithoutCommitPolicy(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+
+	report, err := CheckCommitPolicy(vegaDir, "my-api", "abc1234")
+	if err != nil || !report.Passed {
+		t.Fatalf("expected an empty policy to pass, got %+v (err: %v)", report, err)
+	}
+}
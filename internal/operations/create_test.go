@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestCreateGoalStatesAndRollback(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+
+	result, created := CreateGoal(CreateOptions{Title: "Fix login", Project: "my-api", CreatedBy: "alice", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	history, _ := goals.NewStateManager(vegaDir).GetHistory(created.GoalID)
+	var states []goals.GoalState
+	for _, event := range history {
+		states = append(states, event.State)
+	}
+	if len(states) != 3 || states[0] != goals.StatePending || states[2] != goals.StateWorking || history[0].User != "alice" {
+		t.Errorf("state history = %v", states)
+	}
+
+	// A dirty project checkout fails the pre-flight checks: nothing is left behind
+	os.WriteFile(filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base", "README.md"), []byte("edited\n"), 0644)
+	result, _ = CreateGoal(CreateOptions{Title: "Add cache", Project: "my-api", Preflight: true, VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "preflight_failed" {
+		t.Fatalf("expected preflight_failed, got %+v", result.Error)
+	}
+	entries, _ := goals.NewRegistry(vegaDir).Load()
+	if len(entries) != 1 || entries[0].ID != created.GoalID {
+		t.Errorf("registry after failed create = %+v", entries)
+	}
+	files, _ := filepath.Glob(filepath.Join(vegaDir, "goals", "active", "*"))
+	for _, file := range files {
+		if filepath.Base(file) != created.GoalID+".md" && filepath.Base(file) != created.GoalID+".state.jsonl" {
+			t.Errorf("left behind: %s", file)
+		}
+	}
+}
+
+func TestProvisionGoalWorktreeStates(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+	sm := goals.NewStateManager(vegaDir)
+
+	// No worktree at all: straight to working
+	result, research := CreateGoal(CreateOptions{Title: "Research caching", Project: "my-api", SkipWorktree: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	if state, _ := sm.GetState(research.GoalID); state != goals.StateWorking || research.WorktreePath != "" {
+		t.Errorf("skipped worktree: state %s, worktree %q", state, research.WorktreePath)
+	}
+
+	// Deferred worktree, provisioned later with the same pre-flight checks
+	result, deferred := CreateGoal(CreateOptions{Title: "Add cache", Project: "my-api", NoWorktree: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	if state, _ := sm.GetState(deferred.GoalID); state != goals.StatePending {
+		t.Errorf("deferred goal state = %s, want pending", state)
+	}
+	os.WriteFile(filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base", "README.md"), []byte("edited\n"), 0644)
+	errResult := ProvisionGoalWorktree(vegaDir, deferred, ProvisionOptions{Preflight: true, User: "bob"})
+	if errResult == nil || errResult.Error.Code != "preflight_failed" {
+		t.Fatalf("expected preflight_failed, got %+v", errResult)
+	}
+	last, _ := sm.GetLastEvent(deferred.GoalID)
+	if last == nil || last.State != goals.StateFailed || last.User != "bob" {
+		t.Errorf("last event after failed provisioning = %+v", last)
+	}
+}
@@ -0,0 +1,228 @@
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// maxGoalDiff caps the size of a diff returned by GoalDiff
+const maxGoalDiff = 1 << 20
+
+// GoalDiffOptions contains options for inspecting a goal's changes
+type GoalDiffOptions struct {
+	GoalID  string
+	Project string // Defaults to the first goal project with a worktree
+	VegaDir string
+}
+
+// ChangedFile is one file changed on a goal branch
+type ChangedFile struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`             // "added", "modified", "deleted", "renamed", "untracked"
+	OldPath string `json:"old_path,omitempty"` // Previous path of renamed files
+}
+
+// GoalDiffResult describes a goal's changes against its base branch.
+// Committed and uncommitted changes are included; for monorepo projects only
+// changes inside PathInRepo are reported.
+type GoalDiffResult struct {
+	GoalID     string        `json:"goal_id"`
+	Project    string        `json:"project"`
+	Worktree   string        `json:"worktree"`
+	BaseBranch string        `json:"base_branch"`
+	MergeBase  string        `json:"merge_base"`
+	PathInRepo string        `json:"path_in_repo,omitempty"`
+	Files      []ChangedFile `json:"files"`
+	Diff       string        `json:"diff,omitempty"`
+	Truncated  bool          `json:"truncated,omitempty"` // Diff was cut at maxGoalDiff bytes
+}
+
+// GoalChangedFiles lists the files a goal changed since it branched off its base branch
+func GoalChangedFiles(opts GoalDiffOptions) (*Result, *GoalDiffResult) {
+	return goalChanges(opts, false)
+}
+
+// GoalDiff returns the goal's changed files and their unified diff against the base branch
+func GoalDiff(opts GoalDiffOptions) (*Result, *GoalDiffResult) {
+	return goalChanges(opts, true)
+}
+
+func goalChanges(opts GoalDiffOptions, withDiff bool) (*Result, *GoalDiffResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+	project, worktree, errResult := resolveGoalWorktree(opts)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result := &GoalDiffResult{
+		GoalID:     opts.GoalID,
+		Project:    project.Name,
+		Worktree:   worktree,
+		PathInRepo: project.PathInRepo,
+		Files:      []ChangedFile{},
+	}
+	result.BaseBranch, _ = GoalBaseBranch(opts.VegaDir, opts.GoalID, project.Name)
+	if result.BaseBranch == "" {
+		result.BaseBranch = "main"
+	}
+
+	mergeBase, err := goalMergeBase(worktree, result.BaseBranch)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "merge_base_failed",
+				Message: fmt.Sprintf("Failed to find where the goal branched off %s", result.BaseBranch),
+				Details: map[string]string{"base_branch": result.BaseBranch, "error": err.Error()},
+			},
+		}, nil
+	}
+	result.MergeBase = mergeBase
+
+	// Restrict to the project's subdirectory in monorepos
+	pathspec := []string{"--"}
+	if project.PathInRepo != "" {
+		pathspec = append(pathspec, project.PathInRepo)
+	}
+
+	// Diffing against the working tree covers committed, staged and unstaged changes
+	args := append([]string{"-C", worktree, "diff", "--name-status", "-M", mergeBase}, pathspec...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return gitFailed("diff", err), nil
+	}
+	result.Files = append(result.Files, parseNameStatus(string(output))...)
+
+	args = append([]string{"-C", worktree, "ls-files", "--others", "--exclude-standard"}, pathspec...)
+	output, err = exec.Command("git", args...).Output()
+	if err != nil {
+		return gitFailed("ls-files", err), nil
+	}
+	var untracked []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			untracked = append(untracked, line)
+			result.Files = append(result.Files, ChangedFile{Path: line, Status: "untracked"})
+		}
+	}
+
+	if !withDiff {
+		return &Result{Success: true}, result
+	}
+
+	var diff bytes.Buffer
+	args = append([]string{"-C", worktree, "diff", "-M", mergeBase}, pathspec...)
+	output, err = exec.Command("git", args...).Output()
+	if err != nil {
+		return gitFailed("diff", err), nil
+	}
+	diff.Write(output)
+	for _, path := range untracked {
+		// --no-index exits 1 when the files differ, which they always do here
+		output, _ := exec.Command("git", "-C", worktree, "diff", "--no-index", "--", "/dev/null", path).Output()
+		diff.Write(output)
+	}
+
+	result.Diff = diff.String()
+	if len(result.Diff) > maxGoalDiff {
+		result.Diff = result.Diff[:maxGoalDiff]
+		result.Truncated = true
+	}
+	return &Result{Success: true}, result
+}
+
+// resolveGoalWorktree finds the project and worktree a goal's changes live in
+func resolveGoalWorktree(opts GoalDiffOptions) (*goals.Project, string, *Result) {
+	projects := []string{opts.Project}
+	if opts.Project == "" {
+		detail, err := goals.NewParser(opts.VegaDir).ParseGoalDetail(opts.GoalID)
+		if err != nil {
+			return nil, "", &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "goal_not_found",
+					Message: fmt.Sprintf("Goal '%s' not found", opts.GoalID),
+					Details: map[string]string{"goal_id": opts.GoalID},
+				},
+			}
+		}
+		projects = detail.Projects
+	}
+
+	for _, name := range projects {
+		worktree, err := FindGoalWorktree(opts.VegaDir, name, opts.GoalID)
+		if err != nil {
+			continue
+		}
+		project, err := goals.ParseProject(opts.VegaDir, name)
+		if err != nil {
+			project = &goals.Project{Name: name}
+		}
+		return project, worktree, nil
+	}
+
+	return nil, "", &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "worktree_not_found",
+			Message: fmt.Sprintf("No worktree found for goal %s", opts.GoalID),
+			Details: map[string]string{"goal_id": opts.GoalID, "project": opts.Project},
+		},
+	}
+}
+
+// goalMergeBase returns the commit the goal branch forked from, preferring the
+// local base branch and falling back to its remote-tracking branch
+func goalMergeBase(worktree, baseBranch string) (string, error) {
+	var lastErr error
+	for _, ref := range []string{baseBranch, "origin/" + baseBranch} {
+		output, err := exec.Command("git", "-C", worktree, "merge-base", "HEAD", ref).Output()
+		if err == nil {
+			return strings.TrimSpace(string(output)), nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+// parseNameStatus parses git diff --name-status output
+func parseNameStatus(output string) []ChangedFile {
+	var files []ChangedFile
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+		file := ChangedFile{Path: fields[len(fields)-1]}
+		switch fields[0][0] {
+		case 'A':
+			file.Status = "added"
+		case 'D':
+			file.Status = "deleted"
+		case 'R':
+			file.Status = "renamed"
+			file.OldPath = fields[1]
+		default:
+			file.Status = "modified"
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+func gitFailed(command string, err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "git_failed",
+			Message: fmt.Sprintf("git %s failed", command),
+			Details: map[string]string{"error": err.Error()},
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupMonorepoGoal creates a monorepo project whose goal worktree changed
+// files both inside and outside the project's subdirectory
+func setupMonorepoGoal(t *testing.T, pathInRepo string) (vegaDir string) {
+	t.Helper()
+	vegaDir = t.TempDir()
+	base := filepath.Join(vegaDir, "workspaces", "mono", "worktree-base")
+	worktree := filepath.Join(vegaDir, "workspaces", "mono", "goal-abc1234-fix")
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		args = append([]string{"-C", dir, "-c", "user.email=test@test.com", "-c", "user.name=Test"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.MkdirAll(base, 0755)
+	git(base, "init", "-b", "main")
+	write(filepath.Join(base, "services", "api", "main.go"), "package main\n")
+	write(filepath.Join(base, "web", "index.html"), "<html>\n")
+	git(base, "add", ".")
+	git(base, "commit", "-m", "Initial")
+	git(base, "worktree", "add", worktree, "-b", "goal-abc1234-fix")
+
+	// Committed change in scope, committed change out of scope
+	write(filepath.Join(worktree, "services", "api", "main.go"), "package main\n\nfunc main() {}\n")
+	write(filepath.Join(worktree, "web", "index.html"), "<html></html>\n")
+	git(worktree, "commit", "-am", "Change both")
+	// Uncommitted new files in and out of scope
+	write(filepath.Join(worktree, "services", "api", "handler.go"), "package main\n")
+	write(filepath.Join(worktree, "README.md"), "# mono\n")
+
+	config := "# Project: mono\n\n**Base Branch**: `main`\n"
+	if pathInRepo != "" {
+		config += "**Path In Repo**: `" + pathInRepo + "`\n"
+	}
+	write(filepath.Join(vegaDir, "projects", "mono.md"), config)
+	write(filepath.Join(vegaDir, "goals", "active", "abc1234.md"), "# Goal #abc1234: Fix\n\n## Project(s)\n\n- **mono**\n")
+	return vegaDir
+}
+
+func changedPaths(files []ChangedFile) string {
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path+":"+f.Status)
+	}
+	return strings.Join(paths, ",")
+}
+
+func TestGoalChangedFilesMonorepo(t *testing.T) {
+	vegaDir := setupMonorepoGoal(t, "services/api")
+
+	result, data := GoalChangedFiles(GoalDiffOptions{GoalID: "abc1234", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("GoalChangedFiles failed: %+v", result.Error)
+	}
+	if data.Project != "mono" || data.PathInRepo != "services/api" || data.MergeBase == "" {
+		t.Errorf("unexpected result: %+v", data)
+	}
+	want := "services/api/main.go:modified,services/api/handler.go:untracked"
+	if got := changedPaths(data.Files); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if data.Diff != "" {
+		t.Error("changed files should not include the diff")
+	}
+
+	result, data = GoalDiff(GoalDiffOptions{GoalID: "abc1234", Project: "mono", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("GoalDiff failed: %+v", result.Error)
+	}
+	for _, want := range []string{"services/api/main.go", "+func main() {}", "services/api/handler.go"} {
+		if !strings.Contains(data.Diff, want) {
+			t.Errorf("expected %q in diff:\n%s", want, data.Diff)
+		}
+	}
+	if strings.Contains(data.Diff, "web/index.html") || strings.Contains(data.Diff, "README.md") {
+		t.Errorf("diff should be limited to services/api:\n%s", data.Diff)
+	}
+}
+
+func TestGoalChangedFilesWholeRepo(t *testing.T) {
+	vegaDir := setupMonorepoGoal(t, "")
+
+	result, data := GoalChangedFiles(GoalDiffOptions{GoalID: "abc1234", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("GoalChangedFiles failed: %+v", result.Error)
+	}
+	if len(data.Files) != 4 {
+		t.Errorf("expected all 4 changes, got %s", changedPaths(data.Files))
+	}
+
+	result, _ = GoalChangedFiles(GoalDiffOptions{GoalID: "fff0000", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "goal_not_found" {
+		t.Errorf("expected goal_not_found, got %+v", result)
+	}
+	result, _ = GoalChangedFiles(GoalDiffOptions{GoalID: "abc1234", Project: "other", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "worktree_not_found" {
+		t.Errorf("expected worktree_not_found, got %+v", result)
+	}
+}
@@ -0,0 +1,177 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// FindGoalWorktree locates a goal's worktree in a project.
+//
+// Git worktrees are looked up in "git worktree list" of the project checkout
+// by the branch recorded in the goal file (goal-<id>-* when none is), so a
+// worktree moved with "git worktree move" is still found. One moved without
+// git is found by its branch among the project's workspaces and repaired.
+// When the worktree isn't where the goal file says, the recorded path (and a
+// missing branch) is updated. Workspaces git doesn't list (plain and jj
+// projects) are matched by their goal-<id>-* directory name.
+func FindGoalWorktree(vegaDir, project, goalID string) (string, error) {
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
+	parser := goals.NewParser(vegaDir)
+	goalFile, _ := parser.GoalFile(goalID)
+	var recorded *goals.WorktreeInfo
+	if detail, err := parser.ParseGoalDetail(goalID); err == nil && detail.Worktree != nil {
+		if detail.Worktree.Project == "" || detail.Worktree.Project == project {
+			recorded = detail.Worktree
+		}
+	}
+
+	if !isPlainProject(vegaDir, project) {
+		if worktrees, err := gitsvc.ListWorktrees(projectBase); err == nil {
+			branch := ""
+			if recorded != nil {
+				branch = recorded.Branch
+			}
+			path, found, err := matchGoalWorktree(vegaDir, project, projectBase, goalID, branch, worktrees)
+			if err != nil {
+				return "", err
+			}
+			if found != "" {
+				if goalFile != "" && recorded != nil {
+					recordWorktreeLocation(vegaDir, goalFile, recorded, path, found)
+				}
+				return path, nil
+			}
+		}
+	}
+	return globGoalWorktree(vegaDir, project, goalID)
+}
+
+// matchGoalWorktree picks the goal's worktree from a "git worktree list" of
+// projectBase and returns its path and branch ("" if there is none). Listed
+// worktrees whose directory is gone are looked for among the workspaces.
+func matchGoalWorktree(vegaDir, project, projectBase, goalID, branch string, worktrees []gitsvc.Worktree) (string, string, error) {
+	matches := func(wt gitsvc.Worktree) bool {
+		if wt.Bare || wt.Branch == "" {
+			return false
+		}
+		if branch != "" {
+			return wt.Branch == branch
+		}
+		return strings.HasPrefix(goalWorkspaceName(wt.Branch), "goal-"+goalID+"-")
+	}
+
+	var found []gitsvc.Worktree
+	for _, wt := range worktrees {
+		if matches(wt) && !sameDir(wt.Path, projectBase) {
+			found = append(found, wt)
+		}
+	}
+	if len(found) > 1 {
+		return "", "", fmt.Errorf("multiple worktrees found for goal %s", goalID)
+	}
+	if len(found) == 0 {
+		return "", "", nil
+	}
+
+	wt := found[0]
+	if info, err := os.Stat(wt.Path); err == nil && info.IsDir() {
+		return underWorkspaces(vegaDir, project, wt.Path), wt.Branch, nil
+	}
+	// Moved without git: find it by branch and point git at it again
+	if moved := findMovedWorktree(vegaDir, project, wt.Branch); moved != "" {
+		exec.Command("git", "-C", projectBase, "worktree", "repair", moved).Run()
+		return moved, wt.Branch, nil
+	}
+	return "", "", nil
+}
+
+// findMovedWorktree returns the workspace of a project that is a git worktree
+// on branch, or ""
+func findMovedWorktree(vegaDir, project, branch string) string {
+	dir := layout.New(vegaDir).ProjectWorkspace(project)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	git := gitsvc.NewExec()
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == layout.WorktreeBaseName || entry.Name() == poolDirName {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			continue
+		}
+		if git.CurrentBranch(path) == branch {
+			return path
+		}
+	}
+	return ""
+}
+
+// underWorkspaces returns path as found under the project's workspaces when
+// git reports the same directory through another name (symlinks), so callers
+// can keep comparing paths under vegaDir
+func underWorkspaces(vegaDir, project, path string) string {
+	candidate := layout.New(vegaDir).Workspace(project, filepath.Base(path))
+	if candidate != path && sameDir(candidate, path) {
+		return candidate
+	}
+	return path
+}
+
+// sameDir reports whether a and b are the same existing directory
+func sameDir(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	return err == nil && os.SameFile(ia, ib)
+}
+
+// recordWorktreeLocation updates the goal file when the worktree was found
+// somewhere other than recorded
+func recordWorktreeLocation(vegaDir, goalFile string, recorded *goals.WorktreeInfo, path, branch string) {
+	rel, err := filepath.Rel(vegaDir, path)
+	if err != nil {
+		rel = path
+	}
+	if recorded.Path != rel && !sameDir(filepath.Join(vegaDir, recorded.Path), path) {
+		goals.SetWorktreeField(goalFile, "Path", rel)
+	}
+	if recorded.Branch == "" {
+		goals.SetWorktreeField(goalFile, "Branch", branch)
+	}
+}
+
+// globGoalWorktree finds a goal's workspace by its goal-<id>-* directory name
+func globGoalWorktree(vegaDir, project, goalID string) (string, error) {
+	pattern := layout.New(vegaDir).Workspace(project, fmt.Sprintf("goal-%s-*", goalID))
+	matches, _ := filepath.Glob(pattern)
+
+	var dirs []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.IsDir() {
+			dirs = append(dirs, m)
+		}
+	}
+
+	if len(dirs) == 0 {
+		return "", fmt.Errorf("no worktree found matching pattern: goal-%s-*", goalID)
+	}
+	if len(dirs) > 1 {
+		return "", fmt.Errorf("multiple worktrees found for goal %s", goalID)
+	}
+	return dirs[0], nil
+}
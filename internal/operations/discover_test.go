@@ -0,0 +1,60 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestFindGoalWorktree(t *testing.T) {
+	vegaDir, worktree := setupRepairProject(t)
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+	goalFile := filepath.Join(vegaDir, "goals", "active", "abc1234.md")
+	os.WriteFile(goalFile, []byte("# Goal abc1234: Fix\n\n## Worktree\n- **Branch**: goal-abc1234-fix\n- **Project**: my-api\n- **Path**: workspaces/my-api/goal-abc1234-fix\n- **Base Branch**: main\n\n## Status\n"), 0644)
+	recordedPath := func() string {
+		detail, err := goals.NewParser(vegaDir).ParseGoalDetail("abc1234")
+		if err != nil || detail.Worktree == nil {
+			t.Fatalf("goal metadata: %v", err)
+		}
+		return detail.Worktree.Path
+	}
+
+	if path, err := FindGoalWorktree(vegaDir, "my-api", "abc1234"); err != nil || path != worktree {
+		t.Fatalf("FindGoalWorktree = %q, %v; want %s", path, err, worktree)
+	}
+
+	// Moved with git to a name without the goal prefix
+	moved := filepath.Join(vegaDir, "workspaces", "my-api", "fix-login")
+	if out, err := exec.Command("git", "-C", base, "worktree", "move", worktree, moved).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree move: %v\n%s", err, out)
+	}
+	if path, err := FindGoalWorktree(vegaDir, "my-api", "abc1234"); err != nil || path != moved {
+		t.Fatalf("FindGoalWorktree = %q, %v; want %s", path, err, moved)
+	}
+	if got := recordedPath(); got != "workspaces/my-api/fix-login" {
+		t.Errorf("expected recorded path updated, got %s", got)
+	}
+
+	// Moved without git: found by branch and repaired
+	renamed := filepath.Join(vegaDir, "workspaces", "my-api", "login")
+	os.Rename(moved, renamed)
+	if path, err := FindGoalWorktree(vegaDir, "my-api", "abc1234"); err != nil || path != renamed {
+		t.Fatalf("FindGoalWorktree = %q, %v; want %s", path, err, renamed)
+	}
+	if got := recordedPath(); got != "workspaces/my-api/login" {
+		t.Errorf("expected recorded path updated, got %s", got)
+	}
+	list, _ := exec.Command("git", "-C", base, "worktree", "list").Output()
+	if !strings.Contains(string(list), renamed) {
+		t.Errorf("expected the worktree repaired at %s:\n%s", renamed, list)
+	}
+
+	if _, err := FindGoalWorktree(vegaDir, "my-api", "fff0000"); err == nil {
+		t.Error("expected an error for a goal without a worktree")
+	}
+}
@@ -0,0 +1,313 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// The freshness monitor fetches origin in each project's worktree-base and
+// records how many commits the local base branch is behind, so stale bases
+// are visible before goals branch off them. Results are kept in
+// .vega-hub-base-freshness.json.
+const (
+	DefaultBaseCheckInterval  = 15 * time.Minute // How often the monitor fetches
+	DefaultStaleBaseThreshold = 20               // Commits behind before the base counts as stale
+)
+
+// BaseFreshness is how far a project's local base branch is behind origin
+type BaseFreshness struct {
+	Project    string    `json:"project"`
+	BaseBranch string    `json:"base_branch"`
+	Behind     int       `json:"behind"`          // Commits on origin/<base> missing locally
+	Stale      bool      `json:"stale"`           // Behind more than the project's threshold
+	Threshold  int       `json:"threshold"`       // Commits behind allowed before Stale
+	CheckedAt  time.Time `json:"checked_at"`      // Last check, successful or not
+	FetchedAt  time.Time `json:"fetched_at"`      // Last successful fetch
+	Error      string    `json:"error,omitempty"` // Fetch error from the last check
+}
+
+// freshnessMu serializes writes to the freshness file
+var freshnessMu sync.Mutex
+
+// freshnessPath returns the file holding the last check of each project
+func freshnessPath(vegaDir string) string {
+	return filepath.Join(vegaDir, ".vega-hub-base-freshness.json")
+}
+
+// LoadBaseFreshness returns the last recorded check of each project, by name.
+// A missing file yields an empty map.
+func LoadBaseFreshness(vegaDir string) (map[string]BaseFreshness, error) {
+	records := make(map[string]BaseFreshness)
+	data, err := os.ReadFile(freshnessPath(vegaDir))
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(freshnessPath(vegaDir)), err)
+	}
+	return records, nil
+}
+
+// GetBaseFreshness returns the last recorded check of a project, or nil if
+// it hasn't been checked yet
+func GetBaseFreshness(vegaDir, project string) *BaseFreshness {
+	records, err := LoadBaseFreshness(vegaDir)
+	if err != nil {
+		return nil
+	}
+	if f, ok := records[project]; ok {
+		return &f
+	}
+	return nil
+}
+
+// saveBaseFreshness records a project's check, keeping the others
+func saveBaseFreshness(vegaDir string, f BaseFreshness) error {
+	freshnessMu.Lock()
+	defer freshnessMu.Unlock()
+	records, err := LoadBaseFreshness(vegaDir)
+	if err != nil {
+		records = make(map[string]BaseFreshness)
+	}
+	records[f.Project] = f
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := freshnessPath(vegaDir)
+	return atomicfile.WriteFile(path, data, 0644)
+}
+
+// CheckBaseFreshness fetches the project's base branch from origin in
+// worktree-base and records how far the local branch is behind. A failed
+// fetch is recorded in Error and the count is taken against the last
+// fetched origin ref.
+func CheckBaseFreshness(vegaDir, project string) (*BaseFreshness, error) {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return nil, fmt.Errorf("project %s not found: %w", project, err)
+	}
+	if p.IsPlain() {
+		return nil, fmt.Errorf("project %s is not a git project", project)
+	}
+	if p.WorkspaceStatus != "ready" {
+		return nil, fmt.Errorf("workspace not ready: %s", p.WorkspaceError)
+	}
+	baseBranch := p.BaseBranch
+	if baseBranch == "" {
+		baseBranch = "main"
+	}
+	threshold := p.StaleBaseThreshold
+	if threshold <= 0 {
+		threshold = DefaultStaleBaseThreshold
+	}
+	f := BaseFreshness{Project: project, BaseBranch: baseBranch, Threshold: threshold, CheckedAt: time.Now()}
+	if previous := GetBaseFreshness(vegaDir, project); previous != nil {
+		f.FetchedAt = previous.FetchedAt
+	}
+
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
+	lock, err := hub.NewLockManager(vegaDir).AcquireWorktreeBase(project, "base-freshness")
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire worktree-base lock: %w", err)
+	}
+	output, fetchErr := exec.Command("git", "-C", projectBase, "fetch", "origin", baseBranch).CombinedOutput()
+	lock.Release()
+	if fetchErr != nil {
+		f.Error = fmt.Sprintf("git fetch origin %s: %s", baseBranch, strings.TrimSpace(string(output)))
+	} else {
+		f.FetchedAt = f.CheckedAt
+	}
+
+	count, err := exec.Command("git", "-C", projectBase, "rev-list", "--count",
+		"refs/heads/"+baseBranch+"..refs/remotes/origin/"+baseBranch).Output()
+	if err == nil {
+		f.Behind, _ = strconv.Atoi(strings.TrimSpace(string(count)))
+	}
+	f.Stale = f.Behind > threshold
+
+	if err := saveBaseFreshness(vegaDir, f); err != nil {
+		return &f, fmt.Errorf("failed to record base freshness: %w", err)
+	}
+	return &f, nil
+}
+
+// StaleBaseWarning returns a warning when baseBranch is the project's
+// monitored base branch and was last seen behind origin by more than its
+// threshold, or "" otherwise
+func StaleBaseWarning(vegaDir, project, baseBranch string) string {
+	f := GetBaseFreshness(vegaDir, project)
+	if f == nil || !f.Stale || f.BaseBranch != baseBranch {
+		return ""
+	}
+	return fmt.Sprintf("Base branch %s of project %s is %d commit(s) behind origin (checked %s); pull it in worktree-base to branch off the latest code",
+		f.BaseBranch, project, f.Behind, f.CheckedAt.Format(time.RFC3339))
+}
+
+// MaintainBaseFreshness checks every git project with a ready workspace
+func MaintainBaseFreshness(vegaDir string) []BaseFreshness {
+	projects, err := ListProjects(vegaDir)
+	if err != nil {
+		return nil
+	}
+	var checked []BaseFreshness
+	for _, p := range projects {
+		if p.WorkspaceStatus != "ready" || p.IsPlain() {
+			continue
+		}
+		f, err := CheckBaseFreshness(vegaDir, p.Name)
+		if f == nil {
+			f = &BaseFreshness{Project: p.Name, CheckedAt: time.Now(), Error: err.Error()}
+		} else if err != nil && f.Error == "" {
+			f.Error = err.Error()
+		}
+		checked = append(checked, *f)
+	}
+	return checked
+}
+
+// StartBaseFreshnessMonitor checks project base branches in the background,
+// once right away and then every interval, until stop is closed
+func StartBaseFreshnessMonitor(vegaDir string, interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, f := range MaintainBaseFreshness(vegaDir) {
+				if f.Error != "" {
+					log.Printf("[FRESHNESS] %s: %s", f.Project, f.Error)
+				}
+				if f.Stale {
+					log.Printf("[FRESHNESS] %s: %s is %d commit(s) behind origin", f.Project, f.BaseBranch, f.Behind)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// BaseSyncError is returned when a base branch couldn't be brought up to
+// date with origin before branching off or merging into it
+type BaseSyncError struct {
+	Branch   string
+	Diverged bool   // Local and origin both have commits the other lacks
+	Output   string // git output
+}
+
+func (e *BaseSyncError) Error() string {
+	if e.Diverged {
+		return fmt.Sprintf("local %s and origin/%s have diverged: %s", e.Branch, e.Branch, e.Output)
+	}
+	return fmt.Sprintf("could not fetch %s from origin: %s", e.Branch, e.Output)
+}
+
+// baseSyncResult reports a failed SyncBaseBranch as an operation result
+func baseSyncResult(project string, err error) *Result {
+	var syncErr *BaseSyncError
+	if !errors.As(err, &syncErr) {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "base_fetch_failed",
+				Message: "Could not update the base branch from origin",
+				Details: map[string]string{"project": project, "error": err.Error()},
+			},
+		}
+	}
+	details := map[string]string{"project": project, "branch": syncErr.Branch, "error": syncErr.Output}
+	if syncErr.Diverged {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "base_diverged",
+				Message: fmt.Sprintf("Local %s has diverged from origin/%s; reconcile them in worktree-base", syncErr.Branch, syncErr.Branch),
+				Details: details,
+			},
+		}
+	}
+	details["fix"] = "Check the network and credentials for origin, or set **Fetch Base**: `false` in the project config"
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "base_fetch_failed",
+			Message: fmt.Sprintf("Could not fetch %s from origin; is the remote reachable?", syncErr.Branch),
+			Details: details,
+		},
+	}
+}
+
+// FetchBaseBranch fetches baseBranch from origin in projectBase and
+// fast-forwards the local branch to it. A local branch ahead of origin (goals
+// merged but not pushed) is left alone; one that has diverged is a
+// *BaseSyncError. Repositories without an origin remote are skipped. The
+// caller holds the worktree-base lock.
+func FetchBaseBranch(projectBase, baseBranch string) error {
+	if err := exec.Command("git", "-C", projectBase, "remote", "get-url", "origin").Run(); err != nil {
+		return nil
+	}
+	if output, err := exec.Command("git", "-C", projectBase, "fetch", "origin", baseBranch).CombinedOutput(); err != nil {
+		return &BaseSyncError{Branch: baseBranch, Output: strings.TrimSpace(string(output))}
+	}
+
+	local := "refs/heads/" + baseBranch
+	remote := "refs/remotes/origin/" + baseBranch
+	if exec.Command("git", "-C", projectBase, "rev-parse", "--verify", "--quiet", local).Run() != nil {
+		// Only on origin so far: track it
+		if output, err := exec.Command("git", "-C", projectBase, "branch", baseBranch, remote).CombinedOutput(); err != nil {
+			return &BaseSyncError{Branch: baseBranch, Output: strings.TrimSpace(string(output))}
+		}
+		return nil
+	}
+	if exec.Command("git", "-C", projectBase, "merge-base", "--is-ancestor", remote, local).Run() == nil {
+		return nil // Up to date or ahead
+	}
+	if exec.Command("git", "-C", projectBase, "merge-base", "--is-ancestor", local, remote).Run() != nil {
+		return &BaseSyncError{Branch: baseBranch, Diverged: true, Output: "fast-forward not possible"}
+	}
+
+	// The checked out branch is fast-forwarded through the working tree
+	args := []string{"-C", projectBase, "fetch", ".", remote + ":" + local}
+	if current, _ := exec.Command("git", "-C", projectBase, "branch", "--show-current").Output(); strings.TrimSpace(string(current)) == baseBranch {
+		args = []string{"-C", projectBase, "merge", "--ff-only", remote}
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return &BaseSyncError{Branch: baseBranch, Output: strings.TrimSpace(string(output))}
+	}
+	return nil
+}
+
+// SyncBaseBranch runs FetchBaseBranch in the project's worktree-base under
+// its lock, unless the project sets **Fetch Base**: `false` or is plain
+func SyncBaseBranch(vegaDir, project, baseBranch, owner string) error {
+	if p, err := goals.ParseProject(vegaDir, project); err == nil && (p.NoBaseFetch || p.IsPlain()) {
+		return nil
+	}
+	lock, err := hub.NewLockManager(vegaDir).AcquireWorktreeBase(project, owner)
+	if err != nil {
+		return fmt.Errorf("failed to acquire worktree-base lock: %w", err)
+	}
+	defer lock.Release()
+	return FetchBaseBranch(layout.New(vegaDir).WorktreeBase(project), baseBranch)
+}
@@ -0,0 +1,120 @@
+package operations
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestCheckBaseFreshness(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	config := filepath.Join(vegaDir, "projects", "my-api.md")
+	content, _ := os.ReadFile(config)
+	os.WriteFile(config, append(content, []byte("**Stale Base Threshold**: `2`\n")...), 0644)
+	os.WriteFile(filepath.Join(vegaDir, "projects", "index.md"), []byte("| Project |\n|---|\n| [my-api](my-api.md) |\n"), 0644)
+	project, _ := goals.ParseProject(vegaDir, "my-api")
+	upstream := project.Upstream
+
+	f, err := CheckBaseFreshness(vegaDir, "my-api")
+	if err != nil {
+		t.Fatalf("CheckBaseFreshness: %v", err)
+	}
+	if f.Behind != 0 || f.Stale || f.Error != "" || f.Threshold != 2 || f.FetchedAt.IsZero() {
+		t.Fatalf("fresh clone should be up to date: %+v", f)
+	}
+
+	// Origin moves on by three commits
+	for i := 0; i < 3; i++ {
+		exec.Command("git", "-C", upstream, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", "Upstream").Run()
+	}
+	checked := MaintainBaseFreshness(vegaDir)
+	if len(checked) != 1 || checked[0].Behind != 3 || !checked[0].Stale {
+		t.Fatalf("expected base 3 commits behind and stale: %+v", checked)
+	}
+	if f := GetBaseFreshness(vegaDir, "my-api"); f == nil || f.Behind != 3 {
+		t.Errorf("expected the check to be recorded: %+v", f)
+	}
+	if warning := StaleBaseWarning(vegaDir, "my-api", "main"); !strings.Contains(warning, "3 commit(s) behind") {
+		t.Errorf("expected stale base warning, got %q", warning)
+	}
+	if warning := StaleBaseWarning(vegaDir, "my-api", "dev"); warning != "" {
+		t.Errorf("other base branches should not be warned about, got %q", warning)
+	}
+
+	// An unreachable origin is reported, keeping the last known count
+	os.RemoveAll(upstream)
+	f, err = CheckBaseFreshness(vegaDir, "my-api")
+	if err != nil {
+		t.Fatalf("CheckBaseFreshness: %v", err)
+	}
+	if f.Error == "" || f.Behind != 3 || f.FetchedAt.Equal(f.CheckedAt) {
+		t.Errorf("expected fetch error with last known count: %+v", f)
+	}
+}
+
+func TestSyncBaseBranch(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	project, _ := goals.ParseProject(vegaDir, "my-api")
+	upstream := project.Upstream
+	commit := func(repo, msg string) {
+		exec.Command("git", "-C", repo, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", msg).Run()
+	}
+	rev := func(repo, ref string) string {
+		out, _ := exec.Command("git", "-C", repo, "rev-parse", ref).Output()
+		return strings.TrimSpace(string(out))
+	}
+
+	// Behind origin: fast-forwarded
+	commit(upstream, "Upstream")
+	if err := SyncBaseBranch(vegaDir, "my-api", "main", "test"); err != nil {
+		t.Fatalf("SyncBaseBranch: %v", err)
+	}
+	if rev(base, "main") != rev(upstream, "main") {
+		t.Error("expected local main fast-forwarded to origin")
+	}
+
+	// Ahead of origin (merged, not pushed): left alone
+	commit(base, "Local merge")
+	local := rev(base, "main")
+	if err := SyncBaseBranch(vegaDir, "my-api", "main", "test"); err != nil {
+		t.Fatalf("SyncBaseBranch: %v", err)
+	}
+	if rev(base, "main") != local {
+		t.Error("local commits should be kept")
+	}
+
+	// Diverged
+	commit(upstream, "Upstream again")
+	var syncErr *BaseSyncError
+	err := SyncBaseBranch(vegaDir, "my-api", "main", "test")
+	if !errors.As(err, &syncErr) || !syncErr.Diverged {
+		t.Fatalf("expected diverged error, got %v", err)
+	}
+	if result := baseSyncResult("my-api", err); result.Error.Code != "base_diverged" {
+		t.Errorf("expected base_diverged, got %s", result.Error.Code)
+	}
+
+	// Unreachable origin
+	os.RemoveAll(upstream)
+	err = SyncBaseBranch(vegaDir, "my-api", "main", "test")
+	if !errors.As(err, &syncErr) || syncErr.Diverged {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+	if result := baseSyncResult("my-api", err); result.Error.Code != "base_fetch_failed" {
+		t.Errorf("expected base_fetch_failed, got %s", result.Error.Code)
+	}
+
+	// Fetching can be turned off per project
+	config := filepath.Join(vegaDir, "projects", "my-api.md")
+	content, _ := os.ReadFile(config)
+	os.WriteFile(config, append(content, []byte("**Fetch Base**: `false`\n")...), 0644)
+	if err := SyncBaseBranch(vegaDir, "my-api", "main", "test"); err != nil {
+		t.Errorf("disabled fetch should not fail: %v", err)
+	}
+}
@@ -0,0 +1,553 @@
+package operations
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// goalBundleFormat is the version of the goal bundle layout written by ExportGoal
+const goalBundleFormat = 1
+
+// maxBundleFile caps each file read from a goal bundle
+const maxBundleFile = 256 << 20
+
+// goalSidecars are the per-goal files kept next to a flat goal file
+var goalSidecars = []string{".state.jsonl", ".metadata.json", ".hierarchy.json"}
+
+// GoalBundleManifest describes a goal bundle: a gzipped tarball holding
+// manifest.json, the goal's files under goal/, its session history as
+// history.jsonl and, optionally, its branch as branch.patch
+type GoalBundleManifest struct {
+	Format     int                 `json:"format"`
+	GoalID     string              `json:"goal_id"`
+	Title      string              `json:"title"`
+	Dir        string              `json:"dir"`    // Directory under goals/ it was in, e.g. "active"
+	Folder     bool                `json:"folder"` // Folder-style goal (with its planning files)
+	Entry      goals.RegistryEntry `json:"registry_entry"`
+	Children   []string            `json:"children,omitempty"` // Not included; listed for reference
+	Files      []string            `json:"files"`              // Under goal/
+	History    int                 `json:"history_entries"`
+	Patch      *GoalPatchResult    `json:"patch,omitempty"` // Without the patch itself (branch.patch)
+	ExportedAt time.Time           `json:"exported_at"`
+}
+
+// ExportGoalOptions contains options for exporting a goal as a bundle
+type ExportGoalOptions struct {
+	GoalID       string
+	IncludePatch bool   // Add the goal branch's commits as branch.patch
+	Project      string // Project whose branch to include; defaults to the first with a worktree
+	VegaDir      string
+}
+
+// ExportGoal writes a goal (active, iced, completed or draft) to w as a
+// portable bundle for ImportGoal in another vega-missile directory: its goal
+// file with its state, metadata and hierarchy sidecars (the whole folder of
+// folder-style goals), its registry entry and its session history
+func ExportGoal(opts ExportGoalOptions, w io.Writer) (*Result, *GoalBundleManifest) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+	l := layout.New(opts.VegaDir)
+	goalFile, _ := l.FindGoalFile(opts.GoalID)
+	entry, err := goals.NewRegistry(opts.VegaDir).Get(opts.GoalID)
+	if goalFile == "" || err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "goal_not_found",
+				Message: fmt.Sprintf("Goal '%s' not found", opts.GoalID),
+				Details: map[string]string{"goal_id": opts.GoalID},
+			},
+		}, nil
+	}
+
+	manifest := &GoalBundleManifest{
+		Format:     goalBundleFormat,
+		GoalID:     opts.GoalID,
+		Title:      entry.Title,
+		Dir:        l.FindGoalDir(opts.GoalID),
+		Folder:     filepath.Base(filepath.Dir(goalFile)) == opts.GoalID,
+		Entry:      *entry,
+		ExportedAt: time.Now(),
+	}
+	manifest.Children, _ = goals.NewHierarchyManager(opts.VegaDir).GetChildren(opts.GoalID)
+
+	files := make(map[string][]byte)
+	if manifest.Folder {
+		root := filepath.Dir(goalFile)
+		err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(p)
+			files[filepath.ToSlash(rel)] = data
+			return err
+		})
+	} else {
+		for _, suffix := range append([]string{".md"}, goalSidecars...) {
+			data, readErr := os.ReadFile(layout.Sidecar(goalFile, suffix))
+			if readErr == nil {
+				files[opts.GoalID+suffix] = data
+			} else if !os.IsNotExist(readErr) {
+				err = readErr
+			}
+		}
+	}
+	if err != nil {
+		return bundleFailed("Could not read the goal's files", err), nil
+	}
+
+	history, err := readGoalHistory(opts.VegaDir, opts.GoalID)
+	if err != nil {
+		return bundleFailed("Could not read the goal's session history", err), nil
+	}
+	manifest.History = bytes.Count(history, []byte("\n"))
+
+	var patch []byte
+	if opts.IncludePatch {
+		result, exported := GoalPatch(GoalDiffOptions{GoalID: opts.GoalID, Project: opts.Project, VegaDir: opts.VegaDir})
+		if !result.Success {
+			return result, nil
+		}
+		manifest.Patch, patch = exported, exported.Patch
+	}
+
+	for name := range files {
+		manifest.Files = append(manifest.Files, name)
+	}
+	sort.Strings(manifest.Files)
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return bundleFailed("Could not write the bundle", err), nil
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: manifest.ExportedAt}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	err = add("manifest.json", manifestData)
+	for _, name := range manifest.Files {
+		if err == nil {
+			err = add("goal/"+name, files[name])
+		}
+	}
+	if err == nil && len(history) > 0 {
+		err = add("history.jsonl", history)
+	}
+	if err == nil && patch != nil {
+		err = add("branch.patch", patch)
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		return bundleFailed("Could not write the bundle", err), nil
+	}
+	return &Result{Success: true}, manifest
+}
+
+// ImportGoalOptions contains options for importing a goal bundle
+type ImportGoalOptions struct {
+	Bundle  io.Reader
+	NewID   string // Import under this ID instead of the bundle's
+	Rename  bool   // Pick a new ID if the bundle's is taken, instead of failing
+	VegaDir string
+}
+
+// ImportGoalResult reports a goal loaded from a bundle
+type ImportGoalResult struct {
+	GoalID     string   `json:"goal_id"`
+	OriginalID string   `json:"original_id"`
+	Renamed    bool     `json:"renamed,omitempty"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	GoalFile   string   `json:"goal_file"`
+	History    int      `json:"history_entries"`
+	PatchFile  string   `json:"patch_file,omitempty"` // The bundle's branch.patch, to apply once the goal has a worktree
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// ImportGoal loads a bundle written by ExportGoal into the vega-missile
+// directory, in the same status. A goal ID already in use (by a goal or its
+// history) fails with goal_exists unless NewID or Rename picks another one;
+// the goal file heading and history then use the new ID. Links to a parent,
+// dependencies or an alias that don't carry over are dropped with a
+// warning. The goal gets no worktree: its branch comes along as a patch.
+func ImportGoal(opts ImportGoalOptions) (*Result, *ImportGoalResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.NewID)); errResult != nil {
+		return errResult, nil
+	}
+	manifest, entries, err := readGoalBundle(opts.Bundle)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_bundle",
+				Message: "Not a goal bundle written by vega-hub goal export",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+
+	l := layout.New(opts.VegaDir)
+	knownDir := false
+	for _, dir := range l.GoalDirs() {
+		knownDir = knownDir || dir.Name == manifest.Dir
+	}
+	if !knownDir {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_bundle",
+				Message: fmt.Sprintf("Unknown goal directory %q in the bundle", manifest.Dir),
+				Details: map[string]string{"dir": manifest.Dir},
+			},
+		}, nil
+	}
+	registry := goals.NewRegistry(opts.VegaDir)
+	taken := func(id string) bool {
+		if path, _ := l.FindGoalFile(id); path != "" {
+			return true
+		}
+		if _, err := registry.Get(id); !errors.Is(err, goals.ErrNotFound) {
+			return true
+		}
+		_, err := os.Stat(goalHistoryFile(opts.VegaDir, id))
+		return err == nil
+	}
+
+	result := &ImportGoalResult{OriginalID: manifest.GoalID, GoalID: manifest.GoalID, Title: manifest.Title}
+	if opts.NewID != "" {
+		result.GoalID = opts.NewID
+	}
+	if taken(result.GoalID) {
+		if !opts.Rename || opts.NewID != "" {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "goal_exists",
+					Message: fmt.Sprintf("Goal '%s' already exists in this directory", result.GoalID),
+					Details: map[string]string{"goal_id": result.GoalID},
+				},
+			}, nil
+		}
+		if result.GoalID, err = uniqueGoalID(opts.VegaDir); err != nil {
+			return bundleFailed("Failed to generate unique goal ID", err), nil
+		}
+	}
+	result.Renamed = result.GoalID != manifest.GoalID
+	oldID, newID := manifest.GoalID, result.GoalID
+
+	exists := func(id string) bool { return id != "" && taken(id) }
+	entry := manifest.Entry
+	entry.ID, entry.Children = newID, nil
+	entry.UpdatedAt = time.Now().Format(time.RFC3339)
+	if entry.ParentID != "" && !exists(entry.ParentID) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("parent goal %s is not in this directory; imported as a root goal", entry.ParentID))
+		entry.ParentID = ""
+	}
+	var blockedBy []string // Dropped ones are reported from the metadata file
+	for _, id := range entry.BlockedBy {
+		if exists(id) {
+			blockedBy = append(blockedBy, id)
+		}
+	}
+	entry.BlockedBy = blockedBy
+	if entry.Alias != "" && goals.ResolveGoalID(opts.VegaDir, entry.Alias) != entry.Alias {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("alias %s dropped: another goal has it", entry.Alias))
+		entry.Alias = ""
+	}
+	for _, project := range entry.Projects {
+		if _, err := os.Stat(l.ProjectConfig(project)); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("project %s is not configured in this directory", project))
+		}
+	}
+
+	// Goal files go where they were (active, iced, ...), renamed to the new ID
+	folder := manifest.Folder || l.GoalStyle() == layout.Folder
+	var written []string
+	for _, name := range manifest.Files {
+		data := entries["goal/"+name]
+		base := path.Base(name)
+		isOwn := path.Dir(name) == "." && strings.HasPrefix(base, oldID+".")
+		if isOwn {
+			base = newID + strings.TrimPrefix(base, oldID)
+			name = path.Join(path.Dir(name), base)
+		} else if !folder {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("file %s skipped: only folder-style goals keep other files", name))
+			continue
+		}
+		switch {
+		case isOwn && strings.HasSuffix(base, ".md"):
+			data = renameGoalHeading(data, oldID, newID)
+		case isOwn && strings.HasSuffix(base, ".metadata.json"):
+			data = pruneGoalLinks(data, exists, &result.Warnings)
+		case isOwn && strings.HasSuffix(base, ".hierarchy.json"):
+			data = pruneGoalLinks(data, exists, nil)
+		}
+
+		target := filepath.Join(l.GoalDir(manifest.Dir), filepath.FromSlash(name))
+		if folder {
+			target = filepath.Join(l.GoalFolder(manifest.Dir, newID), filepath.FromSlash(name))
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+			err = os.WriteFile(target, data, 0644)
+		}
+		if err != nil {
+			removeFiles(written)
+			return bundleFailed("Could not write the goal's files", err), nil
+		}
+		written = append(written, target)
+		if isOwn && strings.HasSuffix(base, ".md") {
+			result.GoalFile = target
+		}
+	}
+	if result.GoalFile == "" {
+		removeFiles(written)
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_bundle",
+				Message: "The bundle has no goal file",
+				Details: map[string]string{"goal_id": oldID},
+			},
+		}, nil
+	}
+
+	if history := entries["history.jsonl"]; len(history) > 0 {
+		history = renameHistoryGoal(history, oldID, newID)
+		file := goalHistoryFile(opts.VegaDir, newID)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err == nil {
+			err = os.WriteFile(file, history, 0644)
+		}
+		if err != nil {
+			removeFiles(written)
+			return bundleFailed("Could not write the goal's session history", err), nil
+		}
+		written = append(written, file)
+		result.History = bytes.Count(history, []byte("\n"))
+	}
+	if patch := entries["branch.patch"]; len(patch) > 0 {
+		result.PatchFile = layout.Sidecar(result.GoalFile, ".patch")
+		if err := os.WriteFile(result.PatchFile, patch, 0644); err != nil {
+			removeFiles(written)
+			return bundleFailed("Could not write the goal's branch patch", err), nil
+		}
+		written = append(written, result.PatchFile)
+	}
+
+	if err := hub.NewLockManager(opts.VegaDir).WithRegistryLock("import-goal", func() error {
+		return registry.Add(entry)
+	}); err != nil {
+		removeFiles(written)
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+	result.Status = entry.Status
+	SyncProjectGoals(opts.VegaDir, entry.Projects...)
+	return &Result{Success: true}, result
+}
+
+// readGoalBundle reads a bundle's manifest and files. Entries with paths
+// that would leave the bundle are refused.
+func readGoalBundle(r io.Reader) (*GoalBundleManifest, map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !filepath.IsLocal(header.Name) || strings.Contains(header.Name, `\`) {
+			return nil, nil, fmt.Errorf("unsafe path %q in bundle", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFile+1))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(data) > maxBundleFile {
+			return nil, nil, fmt.Errorf("%s is larger than %d bytes", header.Name, maxBundleFile)
+		}
+		entries[path.Clean(header.Name)] = data
+	}
+
+	var manifest GoalBundleManifest
+	data, ok := entries["manifest.json"]
+	if !ok {
+		return nil, nil, fmt.Errorf("manifest.json is missing")
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("manifest.json: %w", err)
+	}
+	if manifest.Format != goalBundleFormat {
+		return nil, nil, fmt.Errorf("unsupported bundle format %d", manifest.Format)
+	}
+	if errResult := checkInputs(idInput("goal ID", manifest.GoalID)); errResult != nil || manifest.GoalID == "" {
+		return nil, nil, fmt.Errorf("invalid goal ID %q", manifest.GoalID)
+	}
+	for _, name := range manifest.Files {
+		if _, ok := entries["goal/"+name]; !ok || !filepath.IsLocal(name) {
+			return nil, nil, fmt.Errorf("goal file %s is missing", name)
+		}
+	}
+	return &manifest, entries, nil
+}
+
+// goalHistoryFile is a goal's session history in the hub's history directory
+func goalHistoryFile(vegaDir, goalID string) string {
+	return filepath.Join(vegaDir, ".vega-hub-history", "goal-"+goalID+".jsonl")
+}
+
+// readGoalHistory returns a goal's session history, including entries the
+// retention policy compressed
+func readGoalHistory(vegaDir, goalID string) ([]byte, error) {
+	var history []byte
+	file := goalHistoryFile(vegaDir, goalID)
+	if f, err := os.Open(file + ".gz"); err == nil {
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		if history, err = io.ReadAll(gz); err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return append(history, data...), nil
+}
+
+// goalHeadingRe matches the ID in a goal file's "# Goal #<id>: <title>" heading
+var goalHeadingRe = regexp.MustCompile(`(?m)^(# Goal #?)(\S+?)(:)`)
+
+// renameGoalHeading gives a goal file's heading the goal's new ID
+func renameGoalHeading(data []byte, oldID, newID string) []byte {
+	if oldID == newID {
+		return data
+	}
+	done := false
+	return goalHeadingRe.ReplaceAllFunc(data, func(m []byte) []byte {
+		sub := goalHeadingRe.FindSubmatch(m)
+		if done || string(sub[2]) != oldID {
+			return m
+		}
+		done = true
+		return []byte(string(sub[1]) + newID + string(sub[3]))
+	})
+}
+
+// renameHistoryGoal rewrites the goal ID of session history entries
+func renameHistoryGoal(history []byte, oldID, newID string) []byte {
+	if oldID == newID {
+		return history
+	}
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(history))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBundleFile)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var entry map[string]interface{}
+		if json.Unmarshal(line, &entry) == nil && entry["goal_id"] == oldID {
+			entry["goal_id"] = newID
+			if data, err := json.Marshal(entry); err == nil {
+				line = data
+			}
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// pruneGoalLinks drops the parent and dependencies of a metadata or
+// hierarchy sidecar that point at goals that don't exist, adding a warning
+// for each dropped dependency when warnings is set
+func pruneGoalLinks(data []byte, exists func(string) bool, warnings *[]string) []byte {
+	var meta goals.GoalMetadata
+	if json.Unmarshal(data, &meta) != nil {
+		return data
+	}
+	if meta.ParentID != "" && !exists(meta.ParentID) {
+		meta.ParentID = ""
+	}
+	var deps []goals.Dependency
+	for _, dep := range meta.Dependencies {
+		if exists(dep.GoalID) {
+			deps = append(deps, dep)
+		} else if warnings != nil {
+			*warnings = append(*warnings, fmt.Sprintf("dependency on goal %s dropped: it is not in this directory", dep.GoalID))
+		}
+	}
+	meta.Dependencies = deps
+	out, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func removeFiles(paths []string) {
+	for _, p := range paths {
+		os.Remove(p)
+	}
+}
+
+func bundleFailed(message string, err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "bundle_failed",
+			Message: message,
+			Details: map[string]string{"error": err.Error()},
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestExportImportGoal(t *testing.T) {
+	setupGoalDir := func(t *testing.T) string {
+		dir := t.TempDir()
+		for _, d := range []string{"goals/active", "goals/iced", "goals/history", "projects"} {
+			os.MkdirAll(filepath.Join(dir, d), 0755)
+		}
+		return dir
+	}
+
+	src := setupGoalDir(t)
+	os.WriteFile(filepath.Join(src, "goals", "iced", "abc1234.md"), []byte("# Goal #abc1234: Move me\n\n## Overview\n\nPortable.\n"), 0644)
+	os.WriteFile(filepath.Join(src, "goals", "iced", "abc1234.metadata.json"), []byte(`{"dependencies":[{"goal_id":"def5678","type":"blocks"}]}`), 0644)
+	os.MkdirAll(filepath.Join(src, ".vega-hub-history"), 0755)
+	os.WriteFile(filepath.Join(src, ".vega-hub-history", "goal-abc1234.jsonl"),
+		[]byte(`{"goal_id":"abc1234","type":"executor_started"}`+"\n"+`{"goal_id":"abc1234","type":"executor_stopped"}`+"\n"), 0644)
+	goals.NewRegistry(src).Add(goals.RegistryEntry{ID: "abc1234", Title: "Move me", Projects: []string{"my-api"}, Status: "iced", BlockedBy: []string{"def5678"}})
+
+	var bundle bytes.Buffer
+	result, manifest := ExportGoal(ExportGoalOptions{GoalID: "abc1234", VegaDir: src}, &bundle)
+	if !result.Success {
+		t.Fatalf("export failed: %+v", result.Error)
+	}
+	if manifest.Dir != "iced" || manifest.History != 2 || len(manifest.Files) != 2 {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+	if result, _ := ExportGoal(ExportGoalOptions{GoalID: "fffffff", VegaDir: src}, &bytes.Buffer{}); result.Error == nil || result.Error.Code != "goal_not_found" {
+		t.Errorf("expected goal_not_found, got %+v", result.Error)
+	}
+
+	// Into an empty directory the goal keeps its ID and status
+	dst := setupGoalDir(t)
+	result, imported := ImportGoal(ImportGoalOptions{Bundle: bytes.NewReader(bundle.Bytes()), VegaDir: dst})
+	if !result.Success {
+		t.Fatalf("import failed: %+v", result.Error)
+	}
+	if imported.GoalID != "abc1234" || imported.Status != "iced" || imported.History != 2 || imported.Renamed {
+		t.Errorf("unexpected import %+v", imported)
+	}
+	if imported.GoalFile != filepath.Join(dst, "goals", "iced", "abc1234.md") {
+		t.Errorf("unexpected goal file %s", imported.GoalFile)
+	}
+	entry, err := goals.NewRegistry(dst).Get("abc1234")
+	if err != nil || entry.Title != "Move me" || len(entry.BlockedBy) != 0 {
+		t.Errorf("unexpected registry entry %+v (%v)", entry, err)
+	}
+	if len(imported.Warnings) != 2 {
+		t.Errorf("expected warnings for the dependency and the project, got %v", imported.Warnings)
+	}
+
+	// A second import collides unless renamed
+	result, _ = ImportGoal(ImportGoalOptions{Bundle: bytes.NewReader(bundle.Bytes()), VegaDir: dst})
+	if result.Success || result.Error.Code != "goal_exists" {
+		t.Fatalf("expected goal_exists, got %+v", result.Error)
+	}
+	result, renamed := ImportGoal(ImportGoalOptions{Bundle: bytes.NewReader(bundle.Bytes()), Rename: true, VegaDir: dst})
+	if !result.Success {
+		t.Fatalf("renamed import failed: %+v", result.Error)
+	}
+	if !renamed.Renamed || renamed.GoalID == "abc1234" || renamed.OriginalID != "abc1234" {
+		t.Errorf("unexpected renamed import %+v", renamed)
+	}
+	data, _ := os.ReadFile(renamed.GoalFile)
+	if !strings.HasPrefix(string(data), "# Goal #"+renamed.GoalID+": Move me\n") {
+		t.Errorf("expected the new ID in the heading, got:\n%s", data)
+	}
+	history, _ := os.ReadFile(filepath.Join(dst, ".vega-hub-history", "goal-"+renamed.GoalID+".jsonl"))
+	if strings.Contains(string(history), "abc1234") || strings.Count(string(history), renamed.GoalID) != 2 {
+		t.Errorf("expected the history under the new ID, got:\n%s", history)
+	}
+
+	if result, _ := ImportGoal(ImportGoalOptions{Bundle: strings.NewReader("not a bundle"), VegaDir: dst}); result.Success || result.Error.Code != "invalid_bundle" {
+		t.Errorf("expected invalid_bundle, got %+v", result.Error)
+	}
+}
@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"github.com/lasmarois/vega-hub/internal/pathguard"
+)
+
+// guardedInput is a caller-supplied value that ends up in a path or a git
+// command
+type guardedInput struct {
+	Field string
+	Value string
+	Ref   bool // A branch name rather than an ID or name
+}
+
+func idInput(field, value string) guardedInput {
+	return guardedInput{Field: field, Value: value}
+}
+
+func refInput(field, value string) guardedInput {
+	return guardedInput{Field: field, Value: value, Ref: true}
+}
+
+// checkInputs rejects goal IDs, project names and branches that could escape
+// the vega-missile directory or be read as git options. Empty values are
+// skipped; whether they're required is up to each operation.
+func checkInputs(inputs ...guardedInput) *Result {
+	for _, in := range inputs {
+		if in.Value == "" {
+			continue
+		}
+		check := pathguard.ValidateID
+		if in.Ref {
+			check = pathguard.ValidateRef
+		}
+		if err := check(in.Field, in.Value); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "invalid_input",
+					Message: err.Error(),
+					Details: map[string]string{"field": in.Field, "value": in.Value},
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// workspacePath returns a path under workspaces/, or an error if the
+// elements would lead outside the vega-missile directory
+func workspacePath(vegaDir string, elems ...string) (string, error) {
+	return pathguard.Join(vegaDir, append([]string{"workspaces"}, elems...)...)
+}
@@ -0,0 +1,34 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOperationsRejectUnsafeInputs(t *testing.T) {
+	vegaDir := t.TempDir()
+	outside := filepath.Join(vegaDir, "..", "victim")
+	os.MkdirAll(filepath.Join(outside, "worktree-base"), 0755)
+	t.Cleanup(func() { os.RemoveAll(outside) })
+
+	results := map[string]*Result{}
+	results["remove"], _ = RemoveProject(RemoveProjectOptions{Name: "../victim", Force: true, VegaDir: vegaDir})
+	results["create"], _ = CreateGoal(CreateOptions{Title: "x", Project: "../../etc", VegaDir: vegaDir})
+	results["branch"], _ = CreateGoal(CreateOptions{Title: "x", Project: "my-api", BaseBranch: "--orphan", VegaDir: vegaDir})
+	results["complete"], _ = CompleteGoal(CompleteOptions{GoalID: "abc/../../x", Project: "my-api", VegaDir: vegaDir})
+	results["diff"], _ = GoalDiff(GoalDiffOptions{GoalID: "*", VegaDir: vegaDir})
+	results["clone"], _ = AddProjectFromURL(AddProjectURLOptions{Name: "ok", URL: "--upload-pack=touch /tmp/x", VegaDir: vegaDir})
+
+	for name, result := range results {
+		if result.Success || result.Error.Code != "invalid_input" {
+			t.Errorf("%s: expected invalid_input, got %+v", name, result.Error)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "worktree-base")); err != nil {
+		t.Errorf("expected nothing outside the vega dir touched: %v", err)
+	}
+	if _, err := CheckCommitPolicy(vegaDir, "my-api", "../x"); err == nil {
+		t.Error("expected CheckCommitPolicy to reject the goal ID")
+	}
+}
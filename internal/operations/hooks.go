@@ -0,0 +1,524 @@
+package operations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// hookManifestName is the manifest's file name inside a worktree's git dir,
+// where it stays out of git status
+const hookManifestName = "vega-hooks.json"
+
+// HubSocketFile is the unix socket vega-hub serve --socket listens on, in the
+// vega-missile directory
+const HubSocketFile = ".vega-hub.sock"
+
+// DefaultHubURL is where hooks reach the hub when it hasn't recorded a port
+const DefaultHubURL = "http://localhost:8080"
+
+// HookManifest records the template files synced into a worktree's .claude
+type HookManifest struct {
+	Version  string            `json:"version"`             // Hash of all template files
+	SyncedAt string            `json:"synced_at,omitempty"` // RFC3339
+	Files    map[string]string `json:"files"`               // Path under .claude → sha256 of the installed file
+}
+
+// HookVars are filled into the hook templates installed in a worktree, so
+// each worktree's hooks reach the hub that created it
+type HookVars struct {
+	HubURL    string // {{VEGA_HUB_URL}}
+	HubSocket string // {{VEGA_HUB_SOCKET}}, empty unless the hub listens on a socket
+	GoalID    string // {{GOAL_ID}}, empty for pooled worktrees
+}
+
+// render fills the variables into a template file
+func (v HookVars) render(content []byte) []byte {
+	return []byte(strings.NewReplacer(
+		"{{VEGA_HUB_URL}}", v.HubURL,
+		"{{VEGA_HUB_SOCKET}}", v.HubSocket,
+		"{{GOAL_ID}}", v.GoalID,
+	).Replace(string(content)))
+}
+
+// hubAddress returns where the hub managing vegaDir listens: the port
+// recorded by vega-hub start or serve, and its unix socket if it has one
+func hubAddress(vegaDir string) (url, socket string) {
+	url = DefaultHubURL
+	if data, err := os.ReadFile(filepath.Join(vegaDir, ".vega-hub.port")); err == nil {
+		if port, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && port > 0 {
+			url = fmt.Sprintf("http://localhost:%d", port)
+		}
+	}
+	path := filepath.Join(vegaDir, HubSocketFile)
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		socket = path
+	}
+	return url, socket
+}
+
+// hookTemplateDir is where the files copied into every worktree's .claude live
+func hookTemplateDir(vegaDir string) string {
+	return filepath.Join(layout.New(vegaDir).ProjectInitTemplate(), ".claude")
+}
+
+// isHookTemplateFile reports whether a path under the template .claude is
+// synced into worktrees: hooks, rules and settings.local.json
+func isHookTemplateFile(rel string) bool {
+	return rel == "settings.local.json" ||
+		strings.HasPrefix(rel, "hooks/") ||
+		strings.HasPrefix(rel, "rules/")
+}
+
+// HookTemplateManifest hashes the current hook templates. Its Version changes
+// whenever any template file is added, removed or edited.
+func HookTemplateManifest(vegaDir string) (*HookManifest, error) {
+	root := hookTemplateDir(vegaDir)
+	manifest := &HookManifest{Files: map[string]string{}}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if !isHookTemplateFile(rel) {
+			return nil
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		manifest.Files[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(manifest.Files))
+	for p := range manifest.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", p, manifest.Files[p])
+	}
+	manifest.Version = hex.EncodeToString(h.Sum(nil))[:12]
+	return manifest, nil
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return hashBytes(data), nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hookManifestPath returns where a worktree's manifest is kept, or "" when
+// the worktree isn't a git checkout
+func hookManifestPath(worktreePath string) string {
+	out, err := exec.Command("git", "-C", worktreePath, "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(strings.TrimSpace(string(out)), hookManifestName)
+}
+
+// readHookManifest returns the manifest recorded for a worktree, or nil for
+// worktrees synced before manifests existed
+func readHookManifest(worktreePath string) *HookManifest {
+	path := hookManifestPath(worktreePath)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var manifest HookManifest
+	if json.Unmarshal(data, &manifest) != nil {
+		return nil
+	}
+	return &manifest
+}
+
+// HookChange is one file a hook upgrade changes (or would change)
+type HookChange struct {
+	Path   string `json:"path"`             // Under .claude
+	Action string `json:"action"`           // "add", "update", "remove", "skip"
+	Reason string `json:"reason,omitempty"` // Why a file was skipped
+	Diff   string `json:"diff,omitempty"`   // Unified diff, when requested
+}
+
+// WorktreeHookUpgrade is the outcome of a hook upgrade for one worktree
+type WorktreeHookUpgrade struct {
+	GoalID      string       `json:"goal_id,omitempty"` // Empty for pooled worktrees
+	Project     string       `json:"project"`
+	Path        string       `json:"path"`
+	FromVersion string       `json:"from_version,omitempty"` // Empty when no manifest was recorded
+	Status      string       `json:"status"`                 // "up_to_date", "upgraded", "would_upgrade", "failed"
+	Changes     []HookChange `json:"changes,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// hookSyncOptions controls how template files are written into a worktree
+type hookSyncOptions struct {
+	DryRun bool // Report changes without writing
+	Force  bool // Overwrite files edited in the worktree since the last sync
+	Diff   bool // Include a unified diff for each change
+}
+
+// syncWorktreeHooks brings a worktree's .claude hooks, rules and settings in
+// line with the templates, rendered with the worktree's HookVars. Files
+// edited in the worktree since the last sync are kept unless opts.Force is
+// set, and files dropped from the templates are removed if they weren't
+// edited.
+func syncWorktreeHooks(vegaDir, worktreePath string, tmpl *HookManifest, opts hookSyncOptions) WorktreeHookUpgrade {
+	upgrade := WorktreeHookUpgrade{Path: worktreePath, Status: "up_to_date"}
+	previous := readHookManifest(worktreePath)
+	if previous != nil {
+		upgrade.FromVersion = previous.Version
+	}
+	recorded := func(rel string) (string, bool) {
+		if previous == nil {
+			return "", false
+		}
+		sum, ok := previous.Files[rel]
+		return sum, ok
+	}
+
+	vars := HookVars{GoalID: goalIDFromWorktree(filepath.Base(worktreePath))}
+	vars.HubURL, vars.HubSocket = hubAddress(vegaDir)
+
+	srcRoot := hookTemplateDir(vegaDir)
+	dstRoot := filepath.Join(worktreePath, ".claude")
+
+	paths := make([]string, 0, len(tmpl.Files))
+	for rel := range tmpl.Files {
+		paths = append(paths, rel)
+	}
+	if previous != nil {
+		for rel := range previous.Files {
+			if _, ok := tmpl.Files[rel]; !ok {
+				paths = append(paths, rel)
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	var failed []string
+	installed := map[string]string{} // What each template file renders to here
+	for _, rel := range paths {
+		dst := filepath.Join(dstRoot, filepath.FromSlash(rel))
+		_, inTemplate := tmpl.Files[rel]
+		var content []byte
+		if inTemplate {
+			raw, err := os.ReadFile(filepath.Join(srcRoot, filepath.FromSlash(rel)))
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", rel, err))
+				continue
+			}
+			content = vars.render(raw)
+			installed[rel] = hashBytes(content)
+		}
+		want := installed[rel]
+		have, err := hashFile(dst)
+		exists := err == nil
+		last, wasSynced := recorded(rel)
+		editedLocally := exists && wasSynced && have != last
+
+		change := HookChange{Path: rel}
+		switch {
+		case inTemplate && !exists:
+			change.Action = "add"
+		case inTemplate && have == want:
+			continue
+		case !inTemplate && !exists:
+			continue
+		case editedLocally && !opts.Force:
+			change.Action = "skip"
+			change.Reason = "edited in the worktree since the last sync"
+		case inTemplate:
+			change.Action = "update"
+		default:
+			change.Action = "remove"
+		}
+
+		if opts.Diff && change.Action != "skip" {
+			change.Diff = hookDiff(rel, dst, exists, content, inTemplate)
+		}
+		upgrade.Changes = append(upgrade.Changes, change)
+
+		if opts.DryRun || change.Action == "skip" {
+			continue
+		}
+		if change.Action == "remove" {
+			if err := os.Remove(dst); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", rel, err))
+			}
+			continue
+		}
+		if err := writeHookFile(dst, rel, content); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", rel, err))
+		}
+	}
+
+	changed := upgrade.FromVersion != tmpl.Version
+	for _, c := range upgrade.Changes {
+		if c.Action != "skip" {
+			changed = true
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		upgrade.Status = "failed"
+		upgrade.Error = strings.Join(failed, "; ")
+		return upgrade
+	case opts.DryRun:
+		if changed {
+			upgrade.Status = "would_upgrade"
+		}
+		return upgrade
+	case changed:
+		upgrade.Status = "upgraded"
+	}
+
+	// Record what the worktree now holds. Skipped files keep their old
+	// recorded hash, so they're still recognized as edited next time.
+	manifest := HookManifest{
+		Version:  tmpl.Version,
+		SyncedAt: time.Now().Format(time.RFC3339),
+		Files:    installed,
+	}
+	for _, c := range upgrade.Changes {
+		if c.Action != "skip" {
+			continue
+		}
+		if last, ok := recorded(c.Path); ok {
+			manifest.Files[c.Path] = last
+		} else {
+			delete(manifest.Files, c.Path)
+		}
+	}
+	if path := hookManifestPath(worktreePath); path != "" {
+		data, _ := json.MarshalIndent(manifest, "", "  ")
+		// Holds the session token
+		os.WriteFile(path, data, 0600)
+	}
+	return upgrade
+}
+
+// writeHookFile writes a rendered template into a worktree; hooks are
+// executable
+func writeHookFile(dst, rel string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if strings.HasPrefix(rel, "hooks/") {
+		mode = 0755
+	}
+	if err := os.WriteFile(dst, content, mode); err != nil {
+		return err
+	}
+	return os.Chmod(dst, mode)
+}
+
+// hookDiff returns a unified diff from a worktree's copy of a file to the
+// rendered template. Both sides are staged under a temp dir so the diff
+// header shows the path under .claude.
+func hookDiff(rel, dst string, exists bool, content []byte, inTemplate bool) string {
+	tmp, err := os.MkdirTemp("", "vega-hooks-diff-")
+	if err != nil {
+		return ""
+	}
+	defer os.RemoveAll(tmp)
+
+	from, to := os.DevNull, os.DevNull
+	if exists {
+		if current, err := os.ReadFile(dst); err == nil {
+			from = filepath.Join("worktree", filepath.FromSlash(rel))
+			os.MkdirAll(filepath.Join(tmp, filepath.Dir(from)), 0755)
+			os.WriteFile(filepath.Join(tmp, from), current, 0644)
+		}
+	}
+	if inTemplate {
+		to = filepath.Join("template", filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Join(tmp, filepath.Dir(to)), 0755)
+		os.WriteFile(filepath.Join(tmp, to), content, 0644)
+	}
+	cmd := exec.Command("git", "diff", "--no-index", "--no-color", "--", from, to)
+	cmd.Dir = tmp
+	// Exits 1 when the files differ
+	out, _ := cmd.Output()
+	return string(out)
+}
+
+// UpgradeHooksOptions contains options for re-syncing hooks into worktrees
+type UpgradeHooksOptions struct {
+	GoalID  string // Only this goal's worktrees; all goal and pooled worktrees when empty
+	DryRun  bool   // Report what would change without writing
+	Force   bool   // Overwrite hook files edited in a worktree
+	Diff    bool   // Include unified diffs
+	VegaDir string
+}
+
+// UpgradeHooksResult reports a hook upgrade across worktrees
+type UpgradeHooksResult struct {
+	TemplateVersion string                `json:"template_version"`
+	DryRun          bool                  `json:"dry_run"`
+	Worktrees       []WorktreeHookUpgrade `json:"worktrees"`
+	Upgraded        int                   `json:"upgraded"` // Upgraded, or would be in a dry run
+	UpToDate        int                   `json:"up_to_date"`
+	Failed          int                   `json:"failed"`
+	Skipped         int                   `json:"skipped"` // Files kept because they were edited locally
+}
+
+// UpgradeHooks re-syncs the project-init hooks, rules and settings into
+// existing worktrees, which otherwise keep the templates they were created
+// with
+func UpgradeHooks(opts UpgradeHooksOptions) (*Result, *UpgradeHooksResult) {
+	tmpl, err := HookTemplateManifest(opts.VegaDir)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "template_read_failed",
+				Message: "Failed to read the hook templates",
+				Details: map[string]string{"path": hookTemplateDir(opts.VegaDir), "error": err.Error()},
+			},
+		}, nil
+	}
+	if len(tmpl.Files) == 0 {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "no_templates",
+				Message: "No hook templates found",
+				Details: map[string]string{"path": hookTemplateDir(opts.VegaDir)},
+			},
+		}, nil
+	}
+
+	worktrees := listHookWorktrees(opts.VegaDir, opts.GoalID)
+	if opts.GoalID != "" && len(worktrees) == 0 {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "worktree_not_found",
+				Message: fmt.Sprintf("No worktree found for goal %s", opts.GoalID),
+				Details: map[string]string{"goal_id": opts.GoalID},
+			},
+		}, nil
+	}
+
+	result := &UpgradeHooksResult{TemplateVersion: tmpl.Version, DryRun: opts.DryRun, Worktrees: []WorktreeHookUpgrade{}}
+	sync := hookSyncOptions{DryRun: opts.DryRun, Force: opts.Force, Diff: opts.Diff}
+	for _, wt := range worktrees {
+		upgrade := syncWorktreeHooks(opts.VegaDir, wt.Path, tmpl, sync)
+		upgrade.GoalID = wt.GoalID
+		upgrade.Project = wt.Project
+		switch upgrade.Status {
+		case "upgraded", "would_upgrade":
+			result.Upgraded++
+		case "failed":
+			result.Failed++
+		default:
+			result.UpToDate++
+		}
+		for _, c := range upgrade.Changes {
+			if c.Action == "skip" {
+				result.Skipped++
+			}
+		}
+		result.Worktrees = append(result.Worktrees, upgrade)
+	}
+	return &Result{Success: true}, result
+}
+
+// hookWorktree is a worktree that receives hook templates
+type hookWorktree struct {
+	GoalID  string
+	Project string
+	Path    string
+}
+
+// listHookWorktrees finds goal worktrees (workspaces/<project>/goal-<id>-*)
+// and, when goalID is empty, ready pooled worktrees
+func listHookWorktrees(vegaDir, goalID string) []hookWorktree {
+	var worktrees []hookWorktree
+	projects, _ := filepath.Glob(filepath.Join(layout.New(vegaDir).WorkspacesDir(), "*"))
+	for _, projectDir := range projects {
+		project := filepath.Base(projectDir)
+		pattern := "goal-*"
+		if goalID != "" {
+			pattern = "goal-" + goalID + "-*"
+		}
+		matches, _ := filepath.Glob(filepath.Join(projectDir, pattern))
+		if goalID == "" {
+			matches = append(matches, pooledWorktrees(vegaDir, project, poolReadyPrefix)...)
+		}
+		for _, path := range matches {
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				continue
+			}
+			worktrees = append(worktrees, hookWorktree{
+				GoalID:  goalIDFromWorktree(filepath.Base(path)),
+				Project: project,
+				Path:    path,
+			})
+		}
+	}
+	return worktrees
+}
+
+// goalIDFromWorktree extracts the goal ID from a "goal-<id>-<slug>" directory
+// name, or "" for other worktrees
+func goalIDFromWorktree(name string) string {
+	if !strings.HasPrefix(name, "goal-") {
+		return ""
+	}
+	id := strings.TrimPrefix(name, "goal-")
+	if i := strings.Index(id, "-"); i >= 0 {
+		id = id[:i]
+	}
+	return id
+}
+
+// CopyHooksToWorktree installs the hook templates into a worktree, filled in
+// with the hub's address, the goal ID and a session token, and records their
+// version so later template changes can be upgraded into it
+func CopyHooksToWorktree(vegaDir, worktreePath string) error {
+	tmpl, err := HookTemplateManifest(vegaDir)
+	if err != nil {
+		return err
+	}
+	upgrade := syncWorktreeHooks(vegaDir, worktreePath, tmpl, hookSyncOptions{Force: true})
+	if upgrade.Status == "failed" {
+		return fmt.Errorf("installing hooks: %s", upgrade.Error)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package operations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupHookWorktree scaffolds a vega dir and a goal worktree with the
+// current hook templates installed
+func setupHookWorktree(t *testing.T) (vegaDir, worktree string) {
+	t.Helper()
+	vegaDir = t.TempDir()
+	if result, _ := Init(InitOptions{Dir: vegaDir}); !result.Success {
+		t.Fatalf("init failed: %+v", result.Error)
+	}
+	worktree = filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-add-auth")
+	os.MkdirAll(worktree, 0755)
+	if out, err := exec.Command("git", "-C", worktree, "init").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	CopyHooksToWorktree(vegaDir, worktree)
+	return vegaDir, worktree
+}
+
+func TestCopyHooksToWorktreeRecordsManifest(t *testing.T) {
+	vegaDir, worktree := setupHookWorktree(t)
+
+	hook, err := os.Stat(filepath.Join(worktree, ".claude", "hooks", "vega-hub-ask.sh"))
+	if err != nil || hook.Mode().Perm()&0100 == 0 {
+		t.Fatalf("expected an executable hook in the worktree, got %v (%v)", hook, err)
+	}
+	tmpl, _ := HookTemplateManifest(vegaDir)
+	manifest := readHookManifest(worktree)
+	if manifest == nil || manifest.Version != tmpl.Version {
+		t.Fatalf("expected manifest version %s, got %+v", tmpl.Version, manifest)
+	}
+	// The manifest lives in the git dir, so the worktree's status is unaffected
+	if out, _ := exec.Command("git", "-C", worktree, "status", "--porcelain").Output(); strings.Contains(string(out), "vega-hooks") {
+		t.Errorf("expected the manifest outside the worktree, got status:\n%s", out)
+	}
+
+	result, data := UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir})
+	if !result.Success || data.UpToDate != 1 || data.Upgraded != 0 {
+		t.Errorf("expected a fresh worktree up to date, got %+v", data)
+	}
+}
+
+func TestUpgradeHooks(t *testing.T) {
+	vegaDir, worktree := setupHookWorktree(t)
+	tmplDir := hookTemplateDir(vegaDir)
+	wtDir := filepath.Join(worktree, ".claude")
+
+	// Template changes: a fixed hook, a new rule, a dropped setting file
+	os.WriteFile(filepath.Join(tmplDir, "hooks", "on-stop.sh"), []byte("#!/bin/sh\necho fixed\n"), 0755)
+	os.WriteFile(filepath.Join(tmplDir, "rules", "new-rule.md"), []byte("# New\n"), 0644)
+	os.Remove(filepath.Join(tmplDir, "settings.local.json"))
+	// An executor tweaked its copy of the ask hook, and the template changed too
+	os.WriteFile(filepath.Join(wtDir, "hooks", "vega-hub-ask.sh"), []byte("#!/bin/sh\necho local\n"), 0755)
+	os.WriteFile(filepath.Join(tmplDir, "hooks", "vega-hub-ask.sh"), []byte("#!/bin/sh\necho upstream\n"), 0755)
+
+	result, data := UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir, DryRun: true, Diff: true})
+	if !result.Success || data.Upgraded != 1 || data.Skipped != 1 {
+		t.Fatalf("unexpected dry run: %+v", data)
+	}
+	wt := data.Worktrees[0]
+	if wt.GoalID != "abc1234" || wt.Project != "my-api" || wt.Status != "would_upgrade" {
+		t.Errorf("unexpected worktree: %+v", wt)
+	}
+	actions := map[string]string{}
+	for _, c := range wt.Changes {
+		actions[c.Path] = c.Action
+		if c.Action == "update" && !strings.Contains(c.Diff, "+echo fixed") {
+			t.Errorf("expected a diff for %s, got %q", c.Path, c.Diff)
+		}
+	}
+	want := map[string]string{
+		"hooks/on-stop.sh":      "update",
+		"rules/new-rule.md":     "add",
+		"settings.local.json":   "remove",
+		"hooks/vega-hub-ask.sh": "skip",
+	}
+	for path, action := range want {
+		if actions[path] != action {
+			t.Errorf("expected %s to %s, got %q", path, action, actions[path])
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(wtDir, "hooks", "on-stop.sh")); strings.Contains(string(data), "fixed") {
+		t.Error("expected a dry run not to write")
+	}
+
+	result, data = UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir})
+	if !result.Success || data.Upgraded != 1 {
+		t.Fatalf("unexpected upgrade: %+v", data)
+	}
+	if content, _ := os.ReadFile(filepath.Join(wtDir, "hooks", "on-stop.sh")); string(content) != "#!/bin/sh\necho fixed\n" {
+		t.Errorf("expected the fixed hook, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(wtDir, "rules", "new-rule.md")); err != nil {
+		t.Errorf("expected the new rule: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(wtDir, "settings.local.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the dropped settings removed, got %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(wtDir, "hooks", "vega-hub-ask.sh")); !strings.Contains(string(content), "local") {
+		t.Errorf("expected the local edit kept, got %q", content)
+	}
+
+	// The edit is still recognized on the next run, until forced
+	_, data = UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir})
+	if data.Skipped != 1 || data.Worktrees[0].Status != "up_to_date" {
+		t.Errorf("expected the edited hook still skipped, got %+v", data.Worktrees[0])
+	}
+	_, data = UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir, GoalID: "abc1234", Force: true})
+	if content, _ := os.ReadFile(filepath.Join(wtDir, "hooks", "vega-hub-ask.sh")); !strings.Contains(string(content), "upstream") {
+		t.Errorf("expected --force to overwrite the edit, got %q", content)
+	}
+}
+
+func TestUpgradeHooksUnversionedWorktree(t *testing.T) {
+	vegaDir, worktree := setupHookWorktree(t)
+	// A worktree created before manifests existed, with an outdated hook
+	os.Remove(hookManifestPath(worktree))
+	os.WriteFile(filepath.Join(worktree, ".claude", "hooks", "on-stop.sh"), []byte("old"), 0755)
+
+	_, data := UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir})
+	if data.Upgraded != 1 || data.Worktrees[0].FromVersion != "" {
+		t.Fatalf("expected the unversioned worktree upgraded, got %+v", data)
+	}
+	if content, _ := os.ReadFile(filepath.Join(worktree, ".claude", "hooks", "on-stop.sh")); string(content) == "old" {
+		t.Error("expected the outdated hook replaced")
+	}
+	if readHookManifest(worktree) == nil {
+		t.Error("expected a manifest recorded")
+	}
+}
+
+func TestUpgradeHooksErrors(t *testing.T) {
+	vegaDir, _ := setupHookWorktree(t)
+	if result, _ := UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir, GoalID: "fff0000"}); result.Success || result.Error.Code != "worktree_not_found" {
+		t.Errorf("expected worktree_not_found, got %+v", result)
+	}
+	if result, _ := UpgradeHooks(UpgradeHooksOptions{VegaDir: t.TempDir()}); result.Success || result.Error.Code != "no_templates" {
+		t.Errorf("expected no_templates, got %+v", result)
+	}
+}
+
+func TestCopyHooksToWorktreeRendersVariables(t *testing.T) {
+	vegaDir, worktree := setupHookWorktree(t)
+	askHook := filepath.Join(worktree, ".claude", "hooks", "vega-hub-ask.sh")
+
+	content, _ := os.ReadFile(askHook)
+	for _, want := range []string{
+		`VEGA_HUB_URL="` + DefaultHubURL + `"`,
+		`GOAL_ID="abc1234"`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %s in the installed hook", want)
+		}
+	}
+
+	// A hub on another port: upgrading regenerates the hooks
+	os.WriteFile(filepath.Join(vegaDir, ".vega-hub.port"), []byte("9123\n"), 0644)
+	_, data := UpgradeHooks(UpgradeHooksOptions{VegaDir: vegaDir})
+	if data.Upgraded != 1 {
+		t.Fatalf("expected the worktree upgraded for the new port, got %+v", data)
+	}
+	content, _ = os.ReadFile(askHook)
+	if !strings.Contains(string(content), `VEGA_HUB_URL="http://localhost:9123"`) {
+		t.Error("expected the hook pointed at the new port")
+	}
+}
+
+func TestGeneratedStopHookReachesHub(t *testing.T) {
+	for _, tool := range []string{"bash", "jq", "curl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+
+	var received map[string]string
+	var auth string
+	pending := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/goals/abc1234/messages/pending" && pending != "" {
+			json.NewEncoder(w).Encode(map[string]string{"decision": "block", "reason": pending})
+		}
+		if r.URL.Path == "/api/executor/stop" {
+			auth = r.Header.Get("Authorization")
+			json.NewDecoder(r.Body).Decode(&received)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	vegaDir, worktree := setupHookWorktree(t)
+	os.WriteFile(filepath.Join(vegaDir, ".vega-hub.port"), []byte(u.Port()), 0644)
+	if err := CopyHooksToWorktree(vegaDir, worktree); err != nil {
+		t.Fatal(err)
+	}
+
+	runHook := func(env ...string) string {
+		t.Helper()
+		cmd := exec.Command("bash", filepath.Join(worktree, ".claude", "hooks", "on-stop.sh"))
+		cmd.Stdin = strings.NewReader(`{"cwd": "/elsewhere", "session_id": "s1"}`)
+		cmd.Env = append(os.Environ(), append([]string{"VEGA_HUB_PORT=", "VEGA_HUB_TOKEN=", "VEGA_SESSION_ID="}, env...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("hook failed: %v\n%s", err, out)
+		}
+		return string(out)
+	}
+
+	runHook()
+	if received["goal_id"] != "abc1234" || received["session_id"] != "s1" {
+		t.Errorf("expected the stop reported for goal abc1234, got %v", received)
+	}
+	if auth != "" {
+		t.Errorf("expected no token sent outside a spawned executor, got %q", auth)
+	}
+
+	// Executors spawned by the hub send the token it issued them
+	runHook("VEGA_HUB_TOKEN=issued", "VEGA_SESSION_ID=vega-1")
+	if auth != "Bearer issued" {
+		t.Errorf("expected the issued token sent, got %q", auth)
+	}
+	if received["session_id"] != "vega-1" || received["claude_session_id"] != "s1" {
+		t.Errorf("expected the stop reported as the spawned session, got %v", received)
+	}
+
+	// Pending user messages block the stop instead of reporting it
+	received = nil
+	pending = "[User alice]: use the v2 API"
+	var decision map[string]string
+	if err := json.Unmarshal([]byte(runHook()), &decision); err != nil || decision["decision"] != "block" || decision["reason"] != pending {
+		t.Errorf("expected the stop blocked with the messages, got %v (%v)", decision, err)
+	}
+	if received != nil {
+		t.Errorf("expected no stop reported while messages are pending, got %v", received)
+	}
+}
@@ -0,0 +1,199 @@
+package operations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// scaffoldFS mirrors the layout of a fresh vega-missile directory
+//
+//go:embed all:scaffold
+var scaffoldFS embed.FS
+
+// scaffoldDirs are created even though they start out empty
+var scaffoldDirs = []string{
+	"goals/active",
+	"goals/iced",
+	"goals/history",
+	"workspaces",
+}
+
+// InitOptions contains options for bootstrapping a vega-missile directory
+type InitOptions struct {
+	Dir string
+
+	// Optional first project, cloned from ProjectURL
+	ProjectName   string
+	ProjectURL    string
+	ProjectBranch string
+}
+
+// InitResult reports what Init created
+type InitResult struct {
+	Dir      string            `json:"dir"`
+	Created  []string          `json:"created"`           // Paths relative to Dir
+	Skipped  []string          `json:"skipped,omitempty"` // Already present, left untouched
+	Git      string            `json:"git"`               // git --version
+	Warnings []string          `json:"warnings,omitempty"`
+	Project  *AddProjectResult `json:"project,omitempty"`
+}
+
+// Init scaffolds a vega-missile directory at opts.Dir: the goal folders and
+// registry, projects/index.md, and templates/project-init with the default
+// executor hooks. Files that already exist are left alone, so running it on
+// an existing directory only fills in what's missing. When ProjectName is
+// set, the project is cloned from ProjectURL afterwards.
+func Init(opts InitOptions) (*Result, *InitResult) {
+	gitVersion, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "git_not_found",
+				Message: "git is required but was not found on PATH",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+
+	if opts.ProjectName != "" || opts.ProjectURL != "" {
+		if opts.ProjectName == "" || opts.ProjectURL == "" {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "invalid_project",
+					Message: "A first project needs both a name and a git URL",
+				},
+			}, nil
+		}
+		if !isValidProjectName(opts.ProjectName) {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "invalid_name",
+					Message: "Invalid project name",
+					Details: map[string]string{
+						"name":    opts.ProjectName,
+						"allowed": "alphanumeric, dash, underscore",
+					},
+				},
+			}, nil
+		}
+	}
+
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		dir = opts.Dir
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "not_a_directory",
+				Message: fmt.Sprintf("%s exists and is not a directory", dir),
+				Details: map[string]string{"path": dir},
+			},
+		}, nil
+	}
+
+	result := &InitResult{
+		Dir:     dir,
+		Created: []string{},
+		Git:     strings.TrimSpace(string(gitVersion)),
+	}
+
+	fail := func(err error) (*Result, *InitResult) {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "scaffold_failed",
+				Message: "Failed to create the vega-missile directory",
+				Details: map[string]string{"path": dir, "error": err.Error()},
+			},
+		}, result
+	}
+
+	for _, sub := range scaffoldDirs {
+		target := filepath.Join(dir, sub)
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return fail(err)
+		}
+		result.Created = append(result.Created, sub+"/")
+	}
+
+	err = fs.WalkDir(scaffoldFS, "scaffold", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel := strings.TrimPrefix(name, "scaffold/")
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if _, err := os.Stat(target); err == nil {
+			result.Skipped = append(result.Skipped, rel)
+			return nil
+		}
+		content, err := scaffoldFS.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0644)
+		if path.Ext(name) == ".sh" {
+			mode = 0755
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, mode); err != nil {
+			return err
+		}
+		result.Created = append(result.Created, rel)
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	registryPath := layout.New(dir).Registry()
+	if _, err := os.Stat(registryPath); err != nil {
+		if err := os.WriteFile(registryPath, nil, 0644); err != nil {
+			return fail(err)
+		}
+		result.Created = append(result.Created, "goals/registry.jsonl")
+	}
+
+	// The default hooks shell out to these; executors run without them, but
+	// questions won't reach the hub
+	for _, tool := range []string{"jq", "curl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s not found on PATH; the executor hooks need it to reach vega-hub", tool))
+		}
+	}
+
+	if opts.ProjectName != "" {
+		projectResult, project := AddProjectFromURL(AddProjectURLOptions{
+			Name:       opts.ProjectName,
+			URL:        opts.ProjectURL,
+			BaseBranch: opts.ProjectBranch,
+			Policy:     goals.MergePolicy{},
+			VegaDir:    dir,
+		})
+		if !projectResult.Success {
+			// The directory itself is usable; report the project failure
+			return projectResult, result
+		}
+		result.Project = project
+	}
+
+	return &Result{Success: true}, result
+}
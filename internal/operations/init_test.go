@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestInit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "vega-missile")
+
+	result, data := Init(InitOptions{Dir: dir})
+	if !result.Success {
+		t.Fatalf("Init failed: %+v", result.Error)
+	}
+
+	for _, path := range []string{
+		"goals/active", "goals/iced", "goals/history", "workspaces",
+		"goals/REGISTRY.md", "goals/registry.jsonl", "projects/index.md",
+		".claude/CLAUDE.md",
+		"templates/project-init/.claude/settings.local.json",
+		"templates/project-init/.claude/rules/vega-missile/executor-workflow.md",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+	hook, err := os.Stat(filepath.Join(dir, "templates/project-init/.claude/hooks/vega-hub-ask.sh"))
+	if err != nil || hook.Mode().Perm()&0100 == 0 {
+		t.Errorf("expected an executable ask hook, got %v (%v)", hook, err)
+	}
+	if data.Git == "" {
+		t.Error("expected the git version reported")
+	}
+
+	// The scaffold is what the rest of vega-hub expects to find
+	if projects, err := goals.NewParser(dir).ListProjects(); err != nil || len(projects) != 0 {
+		t.Errorf("expected an empty project index, got %v (%v)", projects, err)
+	}
+
+	// Running it again fills in what's missing and keeps edits
+	index := filepath.Join(dir, "projects", "index.md")
+	os.WriteFile(index, []byte("edited"), 0644)
+	os.Remove(filepath.Join(dir, "goals", "REGISTRY.md"))
+
+	result, data = Init(InitOptions{Dir: dir})
+	if !result.Success {
+		t.Fatalf("second Init failed: %+v", result.Error)
+	}
+	if len(data.Created) != 1 || data.Created[0] != "goals/REGISTRY.md" {
+		t.Errorf("expected only REGISTRY.md recreated, got %v", data.Created)
+	}
+	if content, _ := os.ReadFile(index); string(content) != "edited" {
+		t.Errorf("expected the edited index kept, got %q", content)
+	}
+}
+
+func TestInitWithProject(t *testing.T) {
+	tempDir := t.TempDir()
+	repoDir := filepath.Join(tempDir, "my-api")
+	os.MkdirAll(repoDir, 0755)
+	exec.Command("git", "-C", repoDir, "init", "-b", "main").Run()
+	exec.Command("git", "-C", repoDir, "config", "user.email", "test@test.com").Run()
+	exec.Command("git", "-C", repoDir, "config", "user.name", "Test").Run()
+	os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# Test"), 0644)
+	exec.Command("git", "-C", repoDir, "add", ".").Run()
+	exec.Command("git", "-C", repoDir, "commit", "-m", "Initial").Run()
+
+	dir := filepath.Join(tempDir, "vega-missile")
+	result, data := Init(InitOptions{Dir: dir, ProjectName: "my-api", ProjectURL: repoDir})
+	if !result.Success {
+		t.Fatalf("Init failed: %+v", result.Error)
+	}
+	if data.Project == nil || data.Project.Name != "my-api" {
+		t.Fatalf("expected my-api added, got %+v", data.Project)
+	}
+
+	projects, _ := goals.NewParser(dir).ListProjects()
+	if len(projects) != 1 || projects[0] != "my-api" {
+		t.Errorf("expected my-api in the index, got %v", projects)
+	}
+	// The project picks up the default hooks from the template
+	if _, err := os.Stat(filepath.Join(data.Project.Path, ".claude", "hooks", "on-stop.sh")); err != nil {
+		t.Errorf("expected the template hooks in the project: %v", err)
+	}
+}
+
+func TestInitRequiresProjectURL(t *testing.T) {
+	result, _ := Init(InitOptions{Dir: t.TempDir(), ProjectName: "my-api"})
+	if result.Success || result.Error.Code != "invalid_project" {
+		t.Errorf("expected invalid_project, got %+v", result)
+	}
+}
@@ -0,0 +1,99 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// errIssueCommentUnsupported is returned for issues of trackers without a CLI
+// to comment with
+var errIssueCommentUnsupported = errors.New("commenting is only supported on GitHub and GitLab issues")
+
+// issueCommenter posts a comment on an external issue (replaced in tests)
+var issueCommenter = commentOnIssue
+
+// SetGoalIssue links a goal to an external issue, or unlinks it when both
+// fields are empty. The tracker ID is derived from the URL when not given.
+// Existing branches keep their name; only worktrees created afterwards are
+// prefixed with the new tracker ID.
+func SetGoalIssue(vegaDir, goalID string, link goals.IssueLink) (*Result, *goals.IssueLink) {
+	if errResult := checkInputs(idInput("goal ID", goalID)); errResult != nil {
+		return errResult, nil
+	}
+	link, err := goals.NormalizeIssueLink(link)
+	if err != nil {
+		return invalidIssueResult(err), nil
+	}
+
+	lockMgr := hub.NewLockManager(vegaDir)
+	err = lockMgr.WithRegistryLock("set-goal-issue", func() error {
+		return goals.NewRegistry(vegaDir).SetIssueLink(goalID, link)
+	})
+	if errors.Is(err, goals.ErrNotFound) {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "goal_not_found",
+				Message: fmt.Sprintf("Goal '%s' not found", goalID),
+				Details: map[string]string{"goal_id": goalID},
+			},
+		}, nil
+	}
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+	return &Result{Success: true}, &link
+}
+
+// invalidIssueResult reports an issue URL or tracker ID that can't be used
+func invalidIssueResult(err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "invalid_issue",
+			Message: err.Error(),
+		},
+	}
+}
+
+// completionComment is posted on a goal's issue when the goal completes
+func completionComment(result *CompleteResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Completed in vega-hub goal %s: %s", result.GoalID, result.Title)
+	switch {
+	case len(result.CherryPicked) > 0:
+		fmt.Fprintf(&b, "\n\nCherry-picked %d commit(s) from `%s` onto `%s`.", len(result.CherryPicked), result.MergedFrom, result.MergedTo)
+	case result.Merged:
+		fmt.Fprintf(&b, "\n\nMerged `%s` into `%s`.", result.MergedFrom, result.MergedTo)
+	}
+	return b.String()
+}
+
+// commentOnIssue posts body on a GitHub issue with gh or a GitLab issue with glab
+func commentOnIssue(issueURL, body string) error {
+	var cmd *exec.Cmd
+	switch goals.Tracker(issueURL) {
+	case goals.TrackerGitHub:
+		cmd = exec.Command("gh", "issue", "comment", issueURL, "--body", body)
+	case goals.TrackerGitLab:
+		cmd = exec.Command("glab", "issue", "note", issueURL, "--message", body)
+	default:
+		return errIssueCommentUnsupported
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %s", cmd.Args[0], strings.TrimSpace(string(output)))
+	}
+	return nil
+}
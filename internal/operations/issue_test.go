@@ -0,0 +1,82 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestGoalIssueLink(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+
+	result, _ := CreateGoal(CreateOptions{Title: "Fix login", Project: "my-api", Issue: goals.IssueLink{URL: "ftp://example.com/1"}, VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_issue" {
+		t.Fatalf("expected invalid_issue, got %+v", result.Error)
+	}
+
+	result, created := CreateGoal(CreateOptions{
+		Title:   "Fix login",
+		Project: "my-api",
+		Issue:   goals.IssueLink{URL: "https://github.com/acme/api/issues/42"},
+		VegaDir: vegaDir,
+	})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	workspace := "goal-" + created.GoalID + "-fix-login"
+	if created.TrackerID != "#42" || created.GoalBranch != "issue-42/"+workspace {
+		t.Errorf("expected the branch prefixed with the tracker ID: %+v", created)
+	}
+	if filepath.Base(created.WorktreePath) != workspace {
+		t.Errorf("the worktree directory shouldn't include the prefix: %s", created.WorktreePath)
+	}
+	if path, err := FindGoalWorktree(vegaDir, "my-api", created.GoalID); err != nil || path != created.WorktreePath {
+		t.Errorf("FindGoalWorktree = %q, %v", path, err)
+	}
+	branch, err := findGoalBranch(filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base"), created.GoalID)
+	if err != nil || branch != created.GoalBranch {
+		t.Errorf("findGoalBranch = %q, %v", branch, err)
+	}
+
+	result, link := SetGoalIssue(vegaDir, created.GoalID, goals.IssueLink{URL: "https://acme.atlassian.net/browse/eng-7"})
+	if !result.Success || link.TrackerID != "ENG-7" {
+		t.Fatalf("SetGoalIssue: %+v %+v", result.Error, link)
+	}
+	detail, _ := goals.NewParser(vegaDir).ParseGoalDetail(created.GoalID)
+	if detail.IssueURL != "https://acme.atlassian.net/browse/eng-7" || detail.TrackerID != "ENG-7" {
+		t.Errorf("expected the link in the goal detail: %+v", detail.Goal)
+	}
+	if result, _ := SetGoalIssue(vegaDir, "fff0000", goals.IssueLink{}); result.Success || result.Error.Code != "goal_not_found" {
+		t.Errorf("expected goal_not_found, got %+v", result.Error)
+	}
+}
+
+func TestCompleteGoalCommentIssue(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	registry := goals.NewRegistry(vegaDir)
+	registry.Add(goals.RegistryEntry{ID: "abc1234", Title: "Fix login", Projects: []string{"my-api"}, Status: "active"})
+	registry.SetIssueLink("abc1234", goals.IssueLink{URL: "https://github.com/acme/api/issues/42", TrackerID: "#42"})
+
+	var commented, body string
+	old := issueCommenter
+	issueCommenter = func(issueURL, comment string) error {
+		commented, body = issueURL, comment
+		return nil
+	}
+	defer func() { issueCommenter = old }()
+
+	result, data := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", CommentIssue: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CompleteGoal: %+v", result.Error)
+	}
+	if !data.IssueCommented || commented != "https://github.com/acme/api/issues/42" {
+		t.Errorf("expected a comment on the issue: %+v", data)
+	}
+	if !strings.Contains(body, "goal abc1234") || !strings.Contains(body, "Merged `goal-abc1234-fix` into `main`") {
+		t.Errorf("unexpected comment %q", body)
+	}
+}
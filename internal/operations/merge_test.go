@@ -0,0 +1,242 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// setupCompleteGoal creates a project repo with goal abc1234 worked on in its own worktree
+func setupCompleteGoal(t *testing.T, policy string) string {
+	t.Helper()
+	vegaDir := t.TempDir()
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	worktree := filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")
+	os.MkdirAll(base, 0755)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+	os.MkdirAll(filepath.Join(vegaDir, "projects"), 0755)
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		args = append([]string{"-C", dir}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(base, "init", "-b", "main")
+	git(base, "config", "user.email", "test@test.com")
+	git(base, "config", "user.name", "Test")
+	os.WriteFile(filepath.Join(base, "README.md"), []byte("# Test\n"), 0644)
+	git(base, "add", ".")
+	git(base, "commit", "-m", "Initial")
+	git(base, "worktree", "add", worktree, "-b", "goal-abc1234-fix")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		os.WriteFile(filepath.Join(worktree, name), []byte(name), 0644)
+		git(worktree, "add", name)
+		git(worktree, "commit", "-m", "Add "+name)
+	}
+
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "abc1234.md"), []byte("# Goal #abc1234: Fix login\n"), 0644)
+	config := "# Project: my-api\n\n**Base Branch**: `main`\n" + policy
+	os.WriteFile(filepath.Join(vegaDir, "projects", "my-api.md"), []byte(config), 0644)
+	return vegaDir
+}
+
+func TestCompleteGoalProtectedBranch(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "**Protected Branches**: `main`\n")
+
+	result, _ := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "mr_required" {
+		t.Fatalf("expected mr_required, got %+v", result.Error)
+	}
+	if _, err := os.Stat(filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")); err != nil {
+		t.Error("worktree should be untouched when the merge is refused")
+	}
+
+	// Completing without merging (MR opened elsewhere) is allowed
+	result, data := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", NoMerge: true, VegaDir: vegaDir})
+	if !result.Success || data.Merged {
+		t.Fatalf("expected no-merge completion, got %+v", result.Error)
+	}
+}
+
+func TestCompleteGoalReviewGate(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "**Require Review**: `true`\n")
+	sm := goals.NewStateManager(vegaDir)
+	sm.Transition("abc1234", goals.StateWorking, "Goal ready", nil)
+
+	result, _ := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "review_required" {
+		t.Fatalf("expected review_required, got %+v", result.Error)
+	}
+
+	if _, err := sm.Review("abc1234", goals.StateChangesRequested, "bob", "Split the commit"); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "changes_requested" {
+		t.Fatalf("expected changes_requested, got %+v", result.Error)
+	}
+
+	if _, err := sm.Review("abc1234", goals.StateApproved, "bob", ""); err != nil {
+		t.Fatal(err)
+	}
+	result, data := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !result.Success || !data.Merged {
+		t.Fatalf("expected the approved goal completed, got %+v", result.Error)
+	}
+}
+
+func TestCompleteGoalActiveChildren(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "abc1234.1.md"), []byte("# Goal #abc1234.1: Fix logout\n"), 0644)
+	if err := goals.NewHierarchyManager(vegaDir).CreateChildGoal("abc1234.1", "abc1234"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "children_active" || result.Error.Details["children"] != "abc1234.1" {
+		t.Fatalf("expected children_active, got %+v", result.Error)
+	}
+
+	result, _ = CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", Force: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("expected a forced completion, got %+v", result.Error)
+	}
+}
+
+func TestCompleteGoalSquash(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "**Merge Strategy**: `squash`\n")
+
+	result, data := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CompleteGoal failed: %+v", result.Error)
+	}
+	if !data.Merged || data.MergeStrategy != goals.MergeStrategySquash {
+		t.Errorf("unexpected result: %+v", data)
+	}
+
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	out, _ := exec.Command("git", "-C", base, "log", "--format=%s", "main").Output()
+	if got := strings.Split(strings.TrimSpace(string(out)), "\n"); len(got) != 2 || got[0] != "Merge goal abc1234: Fix login" {
+		t.Errorf("expected one squashed commit on main, got %q", got)
+	}
+}
+
+func TestAddProjectMergePolicy(t *testing.T) {
+	vegaDir := t.TempDir()
+	repo := t.TempDir()
+	os.MkdirAll(filepath.Join(vegaDir, "projects"), 0755)
+	exec.Command("git", "-C", repo, "init", "-b", "main").Run()
+
+	result, _ := AddProjectFromPath(AddProjectOptions{
+		Name:    "my-api",
+		Path:    repo,
+		Policy:  goals.MergePolicy{MergeStrategy: "octopus"},
+		VegaDir: vegaDir,
+	})
+	if result.Success || result.Error.Code != "invalid_merge_policy" {
+		t.Fatalf("expected invalid_merge_policy, got %+v", result)
+	}
+
+	policy := goals.MergePolicy{ProtectedBranches: []string{"main", "release/*"}, RequireMR: true, MergeStrategy: "rebase", RequireReview: true}
+	result, _ = AddProjectFromPath(AddProjectOptions{
+		Name:       "my-api",
+		Path:       repo,
+		BaseBranch: "main",
+		Policy:     policy,
+		VegaDir:    vegaDir,
+	})
+	if !result.Success {
+		t.Fatalf("AddProjectFromPath failed: %+v", result.Error)
+	}
+
+	project, err := goals.ParseProject(vegaDir, "my-api")
+	if err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+	got := project.MergePolicy
+	if strings.Join(got.ProtectedBranches, ",") != "main,release/*" || !got.RequireMR || got.MergeStrategy != "rebase" || !got.RequireReview {
+		t.Errorf("policy did not round-trip: %+v", got)
+	}
+}
+
+func TestCompleteGoalIgnoresExecutorOutput(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	worktree := filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")
+	os.WriteFile(filepath.Join(worktree, ".executor-output.log"), []byte("done\n"), 0644)
+
+	result, data := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("the executor output log should not block completion: %+v", result.Error)
+	}
+	if !data.Merged {
+		t.Errorf("expected the goal branch merged, got %+v", data)
+	}
+}
+
+func TestCompleteGoalPreflight(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	os.WriteFile(filepath.Join(base, "README.md"), []byte("edited\n"), 0644)
+
+	result, _ := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "preflight_failed" {
+		t.Fatalf("expected preflight_failed, got %+v", result.Error)
+	}
+	if !strings.Contains(result.Error.Details["blocking_issues"], "worktree_clean") {
+		t.Errorf("expected worktree_clean to block, got %+v", result.Error.Details)
+	}
+
+	// Nothing is merged into the checkout without a merge
+	result, _ = CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", NoMerge: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("expected no-merge completion, got %+v", result.Error)
+	}
+}
+
+func TestCompleteGoalCherryPick(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	git := func(args ...string) string {
+		out, _ := exec.Command("git", append([]string{"-C", base}, args...)...).Output()
+		return strings.TrimSpace(string(out))
+	}
+	pick := git("rev-parse", "--short", "goal-abc1234-fix")
+	initial := git("rev-parse", "main")
+
+	result, _ := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", Commits: []string{initial}, VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_commits" {
+		t.Fatalf("base commits can't be picked, got %+v", result.Error)
+	}
+
+	// The base changed the same file: nothing is picked
+	os.WriteFile(filepath.Join(base, "b.txt"), []byte("conflict"), 0644)
+	git("add", "b.txt")
+	git("commit", "-m", "Add b.txt on main")
+	head := git("rev-parse", "main")
+	result, _ = CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", Commits: []string{pick}, VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "cherry_pick_conflict" || result.Error.Details["conflicts"] != "b.txt" {
+		t.Fatalf("expected cherry_pick_conflict on b.txt, got %+v", result.Error)
+	}
+	if git("rev-parse", "main") != head || git("status", "--porcelain") != "" {
+		t.Error("a conflicting pick should leave the base branch untouched")
+	}
+
+	// Only the selected commit lands on the base
+	git("reset", "--hard", initial)
+	result, data := CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", Commits: []string{pick}, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CompleteGoal: %+v", result.Error)
+	}
+	if !data.Merged || len(data.CherryPicked) != 1 || !strings.HasPrefix(data.CherryPicked[0], pick) {
+		t.Errorf("expected one picked commit: %+v", data)
+	}
+	if files := git("ls-tree", "--name-only", "main"); !strings.Contains(files, "b.txt") || strings.Contains(files, "a.txt") {
+		t.Errorf("expected only b.txt picked onto main, got %q", files)
+	}
+}
@@ -0,0 +1,275 @@
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// Message kinds a project can template
+const (
+	MessageMerge         = "merge_message"
+	MessageMRDescription = "mr_description"
+)
+
+// Templates used when a project doesn't configure its own
+const (
+	DefaultMergeMessageTemplate = `Merge goal {{.GoalID}}: {{.Title}}`
+
+	DefaultMRDescriptionTemplate = `{{with .Overview}}{{.}}
+
+{{end}}{{with .CompletedTasks}}## Completed
+{{range .}}- {{.}}
+{{end}}
+{{end}}{{with .Commits}}## Commits
+{{range .}}- {{.Short}} {{.Subject}}
+{{end}}{{end}}`
+)
+
+// MessageCommit is a goal branch commit as seen by message templates
+type MessageCommit struct {
+	Hash    string `json:"hash"`
+	Short   string `json:"short"`
+	Subject string `json:"subject"`
+	Author  string `json:"author"`
+}
+
+// MessageSession is an executor session as seen by message templates
+type MessageSession struct {
+	ID          string     `json:"id"`
+	User        string     `json:"user,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	StoppedAt   *time.Time `json:"stopped_at,omitempty"`
+	StopReason  string     `json:"stop_reason,omitempty"`
+	ToolCalls   int        `json:"tool_calls"`             // From the ingested transcript, if any
+	FilesEdited []string   `json:"files_edited,omitempty"` // From the ingested transcript, if any
+}
+
+// MessageData is what merge message and MR description templates render
+type MessageData struct {
+	GoalID         string           `json:"goal_id"`
+	Title          string           `json:"title"`
+	Project        string           `json:"project"`
+	Branch         string           `json:"branch"`
+	BaseBranch     string           `json:"base_branch"`
+	IssueURL       string           `json:"issue_url,omitempty"`
+	TrackerID      string           `json:"tracker_id,omitempty"`
+	Overview       string           `json:"overview,omitempty"`
+	CompletedTasks []string         `json:"completed_tasks,omitempty"`
+	Commits        []MessageCommit  `json:"commits,omitempty"` // Oldest first
+	Sessions       []MessageSession `json:"sessions,omitempty"`
+}
+
+// goalMessageData gathers a goal's template data. Commits are read from the
+// worktree when it's a git checkout; a missing goal file or session history
+// leaves the matching fields empty.
+func goalMessageData(vegaDir, goalID, project, worktree, branch, baseBranch string) *MessageData {
+	data := &MessageData{
+		GoalID:     goalID,
+		Title:      "goal-" + goalID,
+		Project:    project,
+		Branch:     branch,
+		BaseBranch: baseBranch,
+	}
+
+	if detail, err := goals.NewParser(vegaDir).ParseGoalDetail(goalID); err == nil {
+		if detail.Title != "" {
+			data.Title = detail.Title
+		}
+		data.IssueURL = detail.IssueURL
+		data.TrackerID = detail.TrackerID
+		data.Overview = detail.Overview
+		for _, phase := range detail.Phases {
+			for _, task := range phase.Tasks {
+				if task.Completed {
+					data.CompletedTasks = append(data.CompletedTasks, task.Description)
+				}
+			}
+		}
+	}
+
+	if worktree != "" {
+		data.Commits = goalCommits(worktree, baseBranch)
+	}
+
+	history := hub.NewSessionHistory(vegaDir)
+	sessions, _ := history.GetGoalSessions(goalID)
+	for _, s := range sessions {
+		session := MessageSession{
+			ID:         s.SessionID,
+			User:       s.User,
+			StartedAt:  s.StartedAt,
+			StoppedAt:  s.StoppedAt,
+			StopReason: s.StopReason,
+		}
+		if entries, err := history.GetTranscript(goalID, s.SessionID); err == nil {
+			summary := hub.SummarizeTranscript(entries)
+			session.ToolCalls = summary.ToolCalls
+			session.FilesEdited = summary.FilesEdited
+		}
+		data.Sessions = append(data.Sessions, session)
+	}
+	return data
+}
+
+// goalCommits lists the non-merge commits the worktree's HEAD made since it
+// branched off baseBranch, oldest first
+func goalCommits(worktree, baseBranch string) []MessageCommit {
+	mergeBase, err := goalMergeBase(worktree, baseBranch)
+	if err != nil {
+		return nil
+	}
+	output, err := exec.Command("git", "-C", worktree, "log", "--reverse", "--no-merges",
+		"--format=%H%x1f%h%x1f%s%x1f%an", mergeBase+"..HEAD").Output()
+	if err != nil {
+		return nil
+	}
+	var commits []MessageCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, MessageCommit{Hash: fields[0], Short: fields[1], Subject: fields[2], Author: fields[3]})
+	}
+	return commits
+}
+
+// RenderGoalMessage renders a merge message or MR description template.
+// Unknown fields are errors rather than "<no value>".
+func RenderGoalMessage(name, text string, data *MessageData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// projectMessageTemplates returns the project's templates with the defaults
+// filled in
+func projectMessageTemplates(vegaDir, project string) goals.MessageTemplates {
+	var templates goals.MessageTemplates
+	if p, err := goals.ParseProject(vegaDir, project); err == nil {
+		templates = p.MessageTemplates
+	}
+	if strings.TrimSpace(templates.MergeMessageTemplate) == "" {
+		templates.MergeMessageTemplate = DefaultMergeMessageTemplate
+	}
+	if strings.TrimSpace(templates.MRDescriptionTemplate) == "" {
+		templates.MRDescriptionTemplate = DefaultMRDescriptionTemplate
+	}
+	return templates
+}
+
+// invalidTemplateResult is the failure returned when a message template
+// doesn't parse or render
+func invalidTemplateResult(project, kind string, err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "invalid_template",
+			Message: fmt.Sprintf("The %s template of project '%s' failed to render", strings.ReplaceAll(kind, "_", " "), project),
+			Details: map[string]string{"project": project, "kind": kind, "error": err.Error()},
+		},
+	}
+}
+
+// MessagePreviewOptions contains options for previewing a goal's messages
+type MessagePreviewOptions struct {
+	GoalID  string
+	Project string // Defaults to the first goal project with a worktree
+	Kind    string // MessageMerge or MessageMRDescription, with Template
+
+	// Template to render for Kind instead of the project's, to try a
+	// template before saving it
+	Template string
+	VegaDir  string
+}
+
+// MessagePreviewResult holds a goal's rendered merge message and MR description
+type MessagePreviewResult struct {
+	GoalID        string       `json:"goal_id"`
+	Project       string       `json:"project"`
+	MergeMessage  string       `json:"merge_message"`
+	MRDescription string       `json:"mr_description"`
+	Data          *MessageData `json:"data"`
+}
+
+// PreviewGoalMessages renders the merge message and MR description a goal
+// would get now
+func PreviewGoalMessages(opts MessagePreviewOptions) (*Result, *MessagePreviewResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+	project, worktree, errResult := resolveGoalWorktree(GoalDiffOptions{GoalID: opts.GoalID, Project: opts.Project, VegaDir: opts.VegaDir})
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	templates := projectMessageTemplates(opts.VegaDir, project.Name)
+	if opts.Template != "" {
+		switch opts.Kind {
+		case MessageMerge:
+			templates.MergeMessageTemplate = opts.Template
+		case MessageMRDescription:
+			templates.MRDescriptionTemplate = opts.Template
+		default:
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "invalid_input",
+					Message: fmt.Sprintf("Unknown message kind '%s' (use %s or %s)", opts.Kind, MessageMerge, MessageMRDescription),
+					Details: map[string]string{"kind": opts.Kind},
+				},
+			}, nil
+		}
+	}
+
+	baseBranch, _ := GoalBaseBranch(opts.VegaDir, opts.GoalID, project.Name)
+	if baseBranch == "" {
+		baseBranch = "main"
+	}
+	var branch, gitWorktree string
+	if project.Type != goals.ProjectTypePlain {
+		gitWorktree, branch = worktree, worktreeBranch(worktree)
+	}
+	data := goalMessageData(opts.VegaDir, opts.GoalID, project.Name, gitWorktree, branch, baseBranch)
+
+	result := &MessagePreviewResult{GoalID: opts.GoalID, Project: project.Name, Data: data}
+	var err error
+	if result.MergeMessage, err = RenderGoalMessage(MessageMerge, templates.MergeMessageTemplate, data); err != nil {
+		return invalidTemplateResult(project.Name, MessageMerge, err), nil
+	}
+	if result.MRDescription, err = RenderGoalMessage(MessageMRDescription, templates.MRDescriptionTemplate, data); err != nil {
+		return invalidTemplateResult(project.Name, MessageMRDescription, err), nil
+	}
+	return &Result{Success: true}, result
+}
+
+// GoalMRDescription renders the project's MR description template for a
+// goal's git worktree, against the branch the MR targets
+func GoalMRDescription(vegaDir, goalID, project, worktree, targetBranch string) (string, error) {
+	templates := projectMessageTemplates(vegaDir, project)
+	data := goalMessageData(vegaDir, goalID, project, worktree, worktreeBranch(worktree), targetBranch)
+	return RenderGoalMessage(MessageMRDescription, templates.MRDescriptionTemplate, data)
+}
+
+// worktreeBranch returns the branch checked out in a worktree, or "" if it
+// can't be read
+func worktreeBranch(worktree string) string {
+	output, err := exec.Command("git", "-C", worktree, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoalMessageTemplates(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "**Merge Strategy**: `squash`\n\n## Merge Message Template\n\n```\n{{.Title}} ({{.GoalID}})\n\n{{range .Commits}}* {{.Subject}}\n{{end}}\n```\n")
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "abc1234.md"), []byte("# Goal #abc1234: Fix login\n\n## Overview\nUsers can't log in.\n\n## Phases\n\n### Phase 1: Fix\n- [x] Find the bug\n- [ ] Add a test\n"), 0644)
+
+	result, preview := PreviewGoalMessages(MessagePreviewOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("PreviewGoalMessages: %+v", result.Error)
+	}
+	wantMerge := "Fix login (abc1234)\n\n* Add a.txt\n* Add b.txt"
+	if preview.MergeMessage != wantMerge {
+		t.Errorf("merge message = %q, want %q", preview.MergeMessage, wantMerge)
+	}
+	for _, want := range []string{"Users can't log in.", "- Find the bug", "Add b.txt"} {
+		if !strings.Contains(preview.MRDescription, want) {
+			t.Errorf("default MR description should contain %q:\n%s", want, preview.MRDescription)
+		}
+	}
+	if strings.Contains(preview.MRDescription, "Add a test") {
+		t.Error("open tasks shouldn't be listed")
+	}
+
+	result, _ = PreviewGoalMessages(MessagePreviewOptions{GoalID: "abc1234", Project: "my-api", Kind: MessageMerge, Template: "{{.Nope}}", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_template" {
+		t.Errorf("expected invalid_template, got %+v", result.Error)
+	}
+
+	result, _ = CompleteGoal(CompleteOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CompleteGoal: %+v", result.Error)
+	}
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	output, _ := exec.Command("git", "-C", base, "log", "-1", "--format=%B").Output()
+	if strings.TrimSpace(string(output)) != wantMerge {
+		t.Errorf("merge commit message = %q", output)
+	}
+}
@@ -0,0 +1,252 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// MoveProjectOptions contains options for moving a goal to another project
+type MoveProjectOptions struct {
+	GoalID     string
+	Project    string // Target project
+	BaseBranch string // Optional override of the target project's base branch
+	Transplant bool   // Apply the goal's commits to the new branch as patches
+	Force      bool   // Archive the old worktree even with uncommitted changes
+	VegaDir    string
+}
+
+// MoveProjectResult contains the result of moving a goal to another project
+type MoveProjectResult struct {
+	GoalID           string `json:"goal_id"`
+	Title            string `json:"title"`
+	FromProject      string `json:"from_project"`
+	Project          string `json:"project"`
+	BaseBranch       string `json:"base_branch,omitempty"`
+	Branch           string `json:"branch,omitempty"`
+	WorktreePath     string `json:"worktree_path"`
+	Transplanted     int    `json:"transplanted"`              // Commits applied to the new branch
+	ArchivedBranch   string `json:"archived_branch,omitempty"` // Old branch, kept in the old project
+	WorktreeArchived bool   `json:"worktree_archived"`
+}
+
+// MoveGoalProject migrates an active goal to another project. The goal gets
+// a new branch and worktree in the target project, named as for a new goal;
+// with Transplant its commits in the old project are exported with git
+// format-patch and applied there. The goal then leaves the old project's
+// config and registry entry, and its old worktree is removed with the branch
+// kept (recorded as Moved From in the goal file), as when icing a goal. A
+// failed transplant removes the new worktree and leaves the goal where it was.
+func MoveGoalProject(opts MoveProjectOptions) (*Result, *MoveProjectResult) {
+	if errResult := checkInputs(
+		idInput("goal ID", opts.GoalID),
+		idInput("project", opts.Project),
+		refInput("base branch", opts.BaseBranch),
+	); errResult != nil {
+		return errResult, nil
+	}
+
+	goalFile, status := goals.NewParser(opts.VegaDir).GoalFile(opts.GoalID)
+	registry := goals.NewRegistry(opts.VegaDir)
+	entry, err := registry.Get(opts.GoalID)
+	if goalFile == "" || err != nil {
+		return goalNotFoundResult(opts.GoalID), nil
+	}
+	if status != "active" {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "not_active",
+				Message: fmt.Sprintf("Only active goals can move projects (goal '%s' is %s)", opts.GoalID, status),
+				Details: map[string]string{"goal_id": opts.GoalID, "status": status},
+			},
+		}, nil
+	}
+	var source string
+	if len(entry.Projects) > 0 {
+		source = entry.Projects[0]
+	}
+	if source == opts.Project {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_input",
+				Message: fmt.Sprintf("Goal '%s' is already in project '%s'", opts.GoalID, opts.Project),
+				Details: map[string]string{"field": "project", "value": opts.Project},
+			},
+		}, nil
+	}
+	target, err := goals.ParseProject(opts.VegaDir, opts.Project)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "project_not_found",
+				Message: fmt.Sprintf("Project '%s' not found", opts.Project),
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+
+	title := getGoalTitle(goalFile, opts.GoalID)
+	result := &MoveProjectResult{GoalID: opts.GoalID, Title: title, FromProject: source, Project: opts.Project}
+
+	// The old worktree is archived, so it must not hold unsaved work
+	var oldWorktree string
+	if source != "" {
+		oldWorktree, _ = FindGoalWorktree(opts.VegaDir, source, opts.GoalID)
+	}
+	oldPlain := source != "" && isPlainProject(opts.VegaDir, source)
+	if oldWorktree != "" && !oldPlain && !opts.Force {
+		if err := checkWorktreeClean(projectBackendOrGit(opts.VegaDir, source), oldWorktree); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "uncommitted_changes",
+					Message: "Worktree has uncommitted changes",
+					Details: map[string]string{"worktree": oldWorktree, "details": err.Error()},
+				},
+			}, nil
+		}
+	}
+
+	var patch *GoalPatchResult
+	if opts.Transplant {
+		if errResult := patchSupported(opts.VegaDir, opts.Project); errResult != nil {
+			return errResult, nil
+		}
+		if oldWorktree != "" {
+			var exported *Result
+			if exported, patch = GoalPatch(GoalDiffOptions{GoalID: opts.GoalID, Project: source, VegaDir: opts.VegaDir}); !exported.Success {
+				return exported, nil
+			}
+		}
+	}
+
+	baseBranch := opts.BaseBranch
+	if baseBranch == "" {
+		baseBranch = target.BaseBranch
+	}
+	if baseBranch == "" {
+		baseBranch = "main"
+	}
+	branchName := goals.IssueBranch(entry.TrackerID, fmt.Sprintf("goal-%s-%s", opts.GoalID, slugify(title)))
+	if target.IsPlain() {
+		baseBranch, branchName = "", ""
+	}
+
+	// The goal file gets the new project's Worktree section
+	original, err := os.ReadFile(goalFile)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "file_read_failed",
+				Message: "Could not read the goal file",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+	goals.RemoveWorktreeSection(goalFile)
+	goal := &CreateResult{
+		GoalID:     opts.GoalID,
+		Title:      title,
+		Project:    opts.Project,
+		BaseBranch: baseBranch,
+		GoalBranch: branchName,
+		GoalFile:   goalFile,
+		IssueURL:   entry.IssueURL,
+		TrackerID:  entry.TrackerID,
+	}
+	if errResult := provisionGoalWorktree(opts.VegaDir, goal, false, nil); errResult != nil {
+		os.WriteFile(goalFile, original, 0644)
+		return errResult, nil
+	}
+	result.BaseBranch, result.Branch, result.WorktreePath = baseBranch, branchName, goal.WorktreePath
+
+	if patch != nil && patch.Commits > 0 {
+		applied, data := ApplyGoalPatch(ApplyPatchOptions{GoalID: opts.GoalID, Project: opts.Project, Patch: patch.Patch, VegaDir: opts.VegaDir})
+		if !applied.Success {
+			backend := projectBackendOrGit(opts.VegaDir, opts.Project)
+			projectBase := layout.New(opts.VegaDir).WorktreeBase(opts.Project)
+			backend.RemoveWorkspace(projectBase, goal.WorktreePath)
+			backend.DeleteBranch(projectBase, branchName)
+			os.WriteFile(goalFile, original, 0644)
+			return applied, nil
+		}
+		result.Transplanted = data.Applied
+	}
+
+	// Archive the old worktree, keeping its branch
+	if oldWorktree != "" {
+		if oldPlain {
+			unlinkPlainWorkspace(oldWorktree)
+		} else {
+			result.ArchivedBranch, _ = getWorktreeBranch(oldWorktree)
+			projectBackendOrGit(opts.VegaDir, source).RemoveWorkspace(layout.New(opts.VegaDir).WorktreeBase(source), oldWorktree)
+		}
+		result.WorktreeArchived = true
+	}
+	if source != "" {
+		movedFrom := source
+		if result.ArchivedBranch != "" {
+			movedFrom = fmt.Sprintf("%s (branch %s)", source, result.ArchivedBranch)
+		}
+		goals.SetWorktreeField(goalFile, "Moved From", movedFrom)
+		setGoalFileProject(goalFile, source, opts.Project)
+	}
+
+	lockMgr := hub.NewLockManager(opts.VegaDir)
+	if err := lockMgr.WithRegistryLock("move-goal-project", func() error {
+		return registry.Update(opts.GoalID, func(e *goals.RegistryEntry) {
+			projects := []string{opts.Project}
+			for _, p := range e.Projects {
+				if p != source && p != opts.Project {
+					projects = append(projects, p)
+				}
+			}
+			e.Projects = projects
+			e.UpdatedAt = time.Now().Format(time.RFC3339)
+		})
+	}); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, result
+	}
+	SyncProjectGoals(opts.VegaDir, source, opts.Project)
+	return &Result{Success: true}, result
+}
+
+// setGoalFileProject renames a project in the Project(s) section of a goal
+// file ("- **<project>**: <role>")
+func setGoalFileProject(goalFile, from, to string) error {
+	content, err := os.ReadFile(goalFile)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	entry := regexp.MustCompile(fmt.Sprintf(`^- \*\*%s\*\*`, regexp.QuoteMeta(from)))
+	inSection := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			inSection = strings.HasPrefix(line, "## Project")
+			continue
+		}
+		if inSection && entry.MatchString(line) {
+			lines[i] = fmt.Sprintf("- **%s**%s", to, line[len(from)+6:])
+			return os.WriteFile(goalFile, []byte(strings.Join(lines, "\n")), 0644)
+		}
+	}
+	return nil
+}
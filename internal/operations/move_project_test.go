@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestMoveGoalProject(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "\n## Active Goals\n- abc1234: Fix login\n")
+	goalFile := filepath.Join(vegaDir, "goals", "active", "abc1234.md")
+	os.WriteFile(goalFile, []byte("# Goal #abc1234: Fix login\n\n## Project(s)\n\n- **my-api**: the login endpoint\n\n## Worktree\n- **Branch**: goal-abc1234-fix\n- **Project**: my-api\n- **Path**: workspaces/my-api/goal-abc1234-fix\n- **Base Branch**: main\n\n## Status\n\nCurrent Phase: 1/?\n"), 0644)
+	if err := goals.NewRegistry(vegaDir).Add(goals.RegistryEntry{ID: "abc1234", Title: "Fix login", Projects: []string{"my-api"}, Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The target project has the same starting point, so the patches apply
+	webBase := filepath.Join(vegaDir, "workspaces", "web-app", "worktree-base")
+	os.MkdirAll(webBase, 0755)
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+	} {
+		exec.Command("git", append([]string{"-C", webBase}, args...)...).Run()
+	}
+	os.WriteFile(filepath.Join(webBase, "README.md"), []byte("# Test\n"), 0644)
+	exec.Command("git", "-C", webBase, "add", ".").Run()
+	exec.Command("git", "-C", webBase, "commit", "-m", "Initial").Run()
+	os.WriteFile(filepath.Join(vegaDir, "projects", "web-app.md"), []byte("# Project: web-app\n\n**Base Branch**: `main`\n\n## Active Goals\n"), 0644)
+
+	result, _ := MoveGoalProject(MoveProjectOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_input" {
+		t.Fatalf("expected invalid_input for the goal's own project, got %+v", result.Error)
+	}
+
+	result, data := MoveGoalProject(MoveProjectOptions{GoalID: "abc1234", Project: "web-app", Transplant: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("move failed: %+v", result.Error)
+	}
+	if data.FromProject != "my-api" || data.Branch != "goal-abc1234-fix-login" || data.Transplanted != 2 || data.ArchivedBranch != "goal-abc1234-fix" {
+		t.Fatalf("unexpected result: %+v", data)
+	}
+
+	worktree := filepath.Join(vegaDir, "workspaces", "web-app", "goal-abc1234-fix-login")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(worktree, name)); err != nil {
+			t.Errorf("%s not transplanted: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")); !os.IsNotExist(err) {
+		t.Errorf("old worktree not archived: %v", err)
+	}
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	if err := exec.Command("git", "-C", base, "rev-parse", "--verify", "goal-abc1234-fix").Run(); err != nil {
+		t.Errorf("old branch not kept: %v", err)
+	}
+
+	detail, err := goals.NewParser(vegaDir).ParseGoalDetail("abc1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Worktree == nil || detail.Worktree.Project != "web-app" || detail.Worktree.Branch != "goal-abc1234-fix-login" {
+		t.Errorf("worktree metadata not updated: %+v", detail.Worktree)
+	}
+	if len(detail.Projects) != 1 || detail.Projects[0] != "web-app" {
+		t.Errorf("goal file projects = %v", detail.Projects)
+	}
+	content, _ := os.ReadFile(goalFile)
+	if strings.Count(string(content), "## Worktree") != 1 || !strings.Contains(string(content), "- **Moved From**: my-api (branch goal-abc1234-fix)") {
+		t.Errorf("goal file:\n%s", content)
+	}
+
+	oldConfig, _ := os.ReadFile(filepath.Join(vegaDir, "projects", "my-api.md"))
+	newConfig, _ := os.ReadFile(filepath.Join(vegaDir, "projects", "web-app.md"))
+	if strings.Contains(string(oldConfig), "abc1234") || !strings.Contains(string(newConfig), "- abc1234: Fix login") {
+		t.Errorf("project configs not updated:\n%s\n---\n%s", oldConfig, newConfig)
+	}
+	if entry, _ := goals.NewRegistry(vegaDir).Get("abc1234"); entry == nil || len(entry.Projects) != 1 || entry.Projects[0] != "web-app" {
+		t.Errorf("registry not updated: %+v", entry)
+	}
+}
@@ -3,16 +3,25 @@ package operations
 
 import (
 	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
 	"github.com/lasmarois/vega-hub/internal/goals"
 	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+	"github.com/lasmarois/vega-hub/internal/pathguard"
+	"log"
 )
 
 // Result is the standard result format for operations
@@ -31,11 +40,18 @@ type ErrorInfo struct {
 
 // CompleteOptions contains options for completing a goal
 type CompleteOptions struct {
-	GoalID   string
-	Project  string
-	NoMerge  bool
-	Force    bool
-	VegaDir  string
+	GoalID        string
+	Project       string
+	NoMerge       bool
+	Force         bool
+	MergeStrategy string   // Overrides the project's merge strategy
+	Commits       []string // Cherry-pick only these goal branch commits onto the base instead of merging
+	CommentIssue  bool     // Comment on the goal's linked issue once completed
+	User          string   // Who completes the goal, recorded in the registry
+	VegaDir       string
+
+	// Progress, if set, is called before each step (background jobs report it)
+	Progress func(step string)
 }
 
 // CompleteResult contains the result of completing a goal
@@ -46,10 +62,14 @@ type CompleteResult struct {
 	Merged          bool   `json:"merged"`
 	MergedTo        string `json:"merged_to,omitempty"`
 	MergedFrom      string `json:"merged_from,omitempty"`
-	WorktreeRemoved bool   `json:"worktree_removed"`
+	MergeStrategy   string   `json:"merge_strategy,omitempty"`
+	CherryPicked    []string `json:"cherry_picked,omitempty"` // Commits picked onto the base, in order
+	WorktreeRemoved bool     `json:"worktree_removed"`
 	BranchDeleted   bool   `json:"branch_deleted"`
 	GoalArchived    bool   `json:"goal_archived"`
 	HistoryFile     string `json:"history_file"`
+	IssueCommented  bool   `json:"issue_commented,omitempty"`
+	IssueError      string `json:"issue_error,omitempty"` // Why the issue comment failed; the goal is still completed
 }
 
 // IceOptions contains options for icing a goal
@@ -59,6 +79,7 @@ type IceOptions struct {
 	Reason          string
 	RemoveWorktree  bool // If true, remove worktree (default: keep it)
 	Force           bool // If true, ignore uncommitted changes when removing worktree
+	User            string // Who ices the goal, recorded in the registry
 	VegaDir         string
 }
 
@@ -111,28 +132,61 @@ type CreateOptions struct {
 	Title      string
 	Project    string
 	BaseBranch string // Optional override
-	NoWorktree bool
-	ParentID   string // Parent goal ID for hierarchical goals
+	NoWorktree bool            // Leave the goal pending for ProvisionGoalWorktree
+	ParentID   string          // Parent goal ID for hierarchical goals
+	Body       string          // Goal file content below the title (default: blank template)
+	Issue      goals.IssueLink // External issue; the tracker ID prefixes the branch
+	Draft      bool            // Backlog goal: no branch or worktree until ActivateGoal
+	Priority   int             // Backlog order of drafts, highest first
+	CreatedBy  string          // User creating the goal, recorded in the registry
+	Tags       []string        // See goals.NormalizeTags
+	Alias      string          // See goals.NormalizeAlias
+	Preflight  bool            // Run hub.CreateChecks on the project checkout before branching
 	VegaDir    string
+
+	// SkipWorktree makes a goal that never gets a worktree (research,
+	// planning): it goes straight from pending to working
+	SkipWorktree bool
 }
 
 // CreateResult contains the result of creating a goal
 type CreateResult struct {
-	GoalID       string `json:"goal_id"`
-	Title        string `json:"title"`
-	Project      string `json:"project"`
-	BaseBranch   string `json:"base_branch"`
-	GoalBranch   string `json:"goal_branch"`
-	WorktreePath string `json:"worktree_path"`
-	GoalFile     string `json:"goal_file"`
-	ParentID     string `json:"parent_id,omitempty"`
+	GoalID       string   `json:"goal_id"`
+	Title        string   `json:"title"`
+	Project      string   `json:"project"`
+	BaseBranch   string   `json:"base_branch"`
+	GoalBranch   string   `json:"goal_branch"`
+	WorktreePath string   `json:"worktree_path"`
+	FromPool     bool     `json:"from_pool,omitempty"` // Worktree was claimed from the prewarmed pool
+	GoalFile     string   `json:"goal_file"`
+	ParentID     string   `json:"parent_id,omitempty"`
+	ClonedFrom   string   `json:"cloned_from,omitempty"` // Source goal ID (CloneGoal)
+	IssueURL     string   `json:"issue_url,omitempty"`
+	TrackerID    string   `json:"tracker_id,omitempty"`
+	Draft        bool     `json:"draft,omitempty"` // In the backlog, not yet activated
+	Priority     int      `json:"priority,omitempty"`
+	CreatedBy    string   `json:"created_by,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Alias        string   `json:"alias,omitempty"`
+
+	// Non-fatal problems, such as a base branch far behind origin
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // CompleteGoal completes a goal (merge, cleanup, archive)
 func CompleteGoal(opts CompleteOptions) (*Result, *CompleteResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string) {}
+	}
+
 	// Validate goal exists and is active
-	goalFile := filepath.Join(opts.VegaDir, "goals", "active", opts.GoalID+".md")
-	if _, err := os.Stat(goalFile); os.IsNotExist(err) {
+	l := layout.New(opts.VegaDir)
+	goalFile := l.FindGoalFileIn(layout.ActiveDir, opts.GoalID)
+	if goalFile == "" {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -147,7 +201,7 @@ func CompleteGoal(opts CompleteOptions) (*Result, *CompleteResult) {
 	goalTitle := getGoalTitle(goalFile, opts.GoalID)
 
 	// Get project base folder
-	projectBase := filepath.Join(opts.VegaDir, "workspaces", opts.Project, "worktree-base")
+	projectBase := l.WorktreeBase(opts.Project)
 	if _, err := os.Stat(projectBase); os.IsNotExist(err) {
 		return &Result{
 			Success: false,
@@ -159,8 +213,18 @@ func CompleteGoal(opts CompleteOptions) (*Result, *CompleteResult) {
 		}, nil
 	}
 
-	// Get base branch
-	baseBranch, err := getProjectBaseBranch(opts.VegaDir, opts.Project)
+	// Plain projects are edited in place: there is no branch to merge
+	plain := isPlainProject(opts.VegaDir, opts.Project)
+	if plain {
+		opts.NoMerge = true
+	}
+	backend, err := ProjectBackend(opts.VegaDir, opts.Project)
+	if err != nil {
+		return vcsResult(opts.Project, err), nil
+	}
+
+	// Get base branch (the goal's, if it was retargeted)
+	baseBranch, err := GoalBaseBranch(opts.VegaDir, opts.GoalID, opts.Project)
 	if err != nil {
 		return &Result{
 			Success: false,
@@ -172,35 +236,95 @@ func CompleteGoal(opts CompleteOptions) (*Result, *CompleteResult) {
 		}, nil
 	}
 
-	// Find worktree
-	worktreeDir, err := findWorktreeDir(opts.VegaDir, opts.Project, opts.GoalID)
-	if err != nil {
+	// Enforce the project's merge policy: protected branches only take MRs
+	policy := getProjectMergePolicy(opts.VegaDir, opts.Project)
+	if !opts.NoMerge && policy.RequiresMR(baseBranch) {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "worktree_not_found",
-				Message: err.Error(),
+				Code:    "mr_required",
+				Message: fmt.Sprintf("Project '%s' does not allow direct merges to '%s'; open an MR and complete with no_merge", opts.Project, baseBranch),
+				Details: map[string]string{"project": opts.Project, "target": baseBranch},
+			},
+		}, nil
+	}
+	// Reviews gate completion: never while changes are requested, and only
+	// once approved if the project requires review
+	if state, err := goals.NewStateManager(opts.VegaDir).GetState(opts.GoalID); err == nil {
+		if err := goals.CheckReview(state, policy); err != nil {
+			return reviewGateResult(opts.GoalID, err), nil
+		}
+	}
+	// An umbrella goal completes after its children, unless forced
+	if !opts.Force {
+		var active *goals.ActiveChildrenError
+		if err := goals.NewHierarchyManager(opts.VegaDir).CheckChildren(opts.GoalID); errors.As(err, &active) {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "children_active",
+					Message: fmt.Sprintf("Goal '%s' has active child goals; complete or ice them first, or force", opts.GoalID),
+					Details: map[string]string{"goal_id": opts.GoalID, "children": strings.Join(active.Children, ", ")},
+				},
+			}, nil
+		}
+	}
+	strategy := opts.MergeStrategy
+	if strategy == "" {
+		strategy = policy.Strategy()
+	}
+	if !goals.IsValidMergeStrategy(strategy) {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_merge_strategy",
+				Message: fmt.Sprintf("Invalid merge strategy '%s'", strategy),
+				Details: map[string]string{"valid": strings.Join(goals.MergeStrategies(), ", ")},
+			},
+		}, nil
+	}
+	if len(opts.Commits) > 0 && opts.NoMerge {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_commits",
+				Message: "Selected commits are cherry-picked onto the base branch and can't be combined with no_merge",
 				Details: map[string]string{"goal_id": opts.GoalID},
 			},
 		}, nil
 	}
 
-	// Get branch name
-	branchName, err := getWorktreeBranch(worktreeDir)
-	if err != nil {
+	// Find worktree (a plain goal's workspace link may already be gone)
+	worktreeDir, err := FindGoalWorktree(opts.VegaDir, opts.Project, opts.GoalID)
+	if err != nil && !plain {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "branch_detection_failed",
-				Message: "Could not determine branch name from worktree",
-				Details: map[string]string{"error": err.Error()},
+				Code:    "worktree_not_found",
+				Message: err.Error(),
+				Details: map[string]string{"goal_id": opts.GoalID},
 			},
 		}, nil
 	}
 
+	// Get branch name (plain goals have none)
+	var branchName string
+	if !plain {
+		if branchName, err = getWorktreeBranch(worktreeDir); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "branch_detection_failed",
+					Message: "Could not determine branch name from worktree",
+					Details: map[string]string{"error": err.Error()},
+				},
+			}, nil
+		}
+	}
+
 	// Safety check
-	if !opts.Force {
-		if err := checkWorktreeClean(worktreeDir); err != nil {
+	if !opts.Force && !plain {
+		if err := checkWorktreeClean(backend, worktreeDir); err != nil {
 			return &Result{
 				Success: false,
 				Error: &ErrorInfo{
@@ -212,45 +336,132 @@ func CompleteGoal(opts CompleteOptions) (*Result, *CompleteResult) {
 		}
 	}
 
+	// The project checkout must be able to take the merge
+	progress("checking")
+	if !opts.NoMerge {
+		if err := SyncBaseBranch(opts.VegaDir, opts.Project, baseBranch, "goal-complete-"+opts.GoalID); err != nil {
+			return baseSyncResult(opts.Project, err), nil
+		}
+		if preflight := hub.NewPreflightChecker(projectBase, baseBranch, "").RunChecks(hub.CompleteChecks); !preflight.Ready {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "preflight_failed",
+					Message: fmt.Sprintf("Project checkout %s is not ready for the merge", projectBase),
+					Details: map[string]string{
+						"blocking_issues": strings.Join(preflight.BlockingIssues, ", "),
+						"fix_commands":    strings.Join(preflight.FixCommands, "\n"),
+					},
+				},
+				Data: preflight,
+			}, nil
+		}
+	}
+
+	// Resolve the commits to cherry-pick, in the order they were made
+	var picks []string
+	if len(opts.Commits) > 0 {
+		if picks, err = gitsvc.BranchCommits(projectBase, baseBranch, branchName, opts.Commits); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "invalid_commits",
+					Message: fmt.Sprintf("Can't cherry-pick from goal '%s': %v", opts.GoalID, err),
+					Details: map[string]string{"branch": branchName, "target": baseBranch, "error": err.Error()},
+				},
+			}, nil
+		}
+	}
+
+	// Enforce the project's commit policy (signatures, message convention)
+	if commitPolicy := getProjectCommitPolicy(opts.VegaDir, opts.Project); commitPolicy.Enabled() && !plain {
+		violations, err := commitPolicy.CheckCommits(worktreeDir, baseBranch)
+		if err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "commit_policy_check_failed",
+					Message: "Could not check commits against the project's commit policy",
+					Details: map[string]string{"branch": branchName, "error": err.Error()},
+				},
+			}, nil
+		}
+		if picks != nil {
+			violations = pickedViolations(violations, picks)
+		}
+		if len(violations) > 0 {
+			return commitPolicyViolationResult(opts.Project, branchName, violations), nil
+		}
+	}
+
+	// Render the merge message from the project's template before touching anything
+	var mergeMsg string
+	if len(picks) == 0 && !opts.NoMerge {
+		var gitWorktree string
+		if !plain {
+			gitWorktree = worktreeDir
+		}
+		data := goalMessageData(opts.VegaDir, opts.GoalID, opts.Project, gitWorktree, branchName, baseBranch)
+		data.Title = goalTitle
+		templates := projectMessageTemplates(opts.VegaDir, opts.Project)
+		if mergeMsg, err = RenderGoalMessage(MessageMerge, templates.MergeMessageTemplate, data); err != nil {
+			return invalidTemplateResult(opts.Project, MessageMerge, err), nil
+		}
+	}
+
 	result := &CompleteResult{
 		GoalID:  opts.GoalID,
 		Title:   goalTitle,
 		Project: opts.Project,
 	}
 
-	// Step 1: Merge branch (unless --no-merge)
-	if !opts.NoMerge {
-		if err := mergeBranch(projectBase, branchName, baseBranch, opts.GoalID, goalTitle); err != nil {
+	// Step 1: Merge branch, or pick the selected commits (unless --no-merge)
+	if len(picks) > 0 {
+		progress("cherry-picking")
+		if err := backend.CherryPick(projectBase, baseBranch, picks); err != nil {
+			return cherryPickResult(branchName, baseBranch, err), nil
+		}
+		result.Merged = true
+		result.MergedTo = baseBranch
+		result.MergedFrom = branchName
+		result.CherryPicked = picks
+	} else if !opts.NoMerge {
+		progress("merging")
+		if err := backend.Merge(projectBase, worktreeDir, branchName, baseBranch, strategy, mergeMsg); err != nil {
 			return &Result{
 				Success: false,
 				Error: &ErrorInfo{
 					Code:    "merge_failed",
 					Message: "Merge failed",
-					Details: map[string]string{"source": branchName, "target": baseBranch, "error": err.Error()},
+					Details: map[string]string{"source": branchName, "target": baseBranch, "strategy": strategy, "error": err.Error()},
 				},
 			}, nil
 		}
 		result.Merged = true
 		result.MergedTo = baseBranch
 		result.MergedFrom = branchName
+		result.MergeStrategy = strategy
 	}
 
 	// Step 2: Remove worktree
-	removeWorktree(projectBase, worktreeDir)
-	result.WorktreeRemoved = true
+	progress("removing worktree")
+	if plain {
+		result.WorktreeRemoved = worktreeDir != "" && unlinkPlainWorkspace(worktreeDir) == nil
+	} else {
+		backend.RemoveWorkspace(projectBase, worktreeDir)
+		result.WorktreeRemoved = true
+	}
 
 	// Step 3: Delete branch (unless --no-merge)
 	if !opts.NoMerge {
-		if err := deleteBranch(projectBase, branchName); err == nil {
+		if err := backend.DeleteBranch(projectBase, branchName); err == nil {
 			result.BranchDeleted = true
 		}
 	}
 
 	// Step 4: Move goal file to history
-	historyDir := filepath.Join(opts.VegaDir, "goals", "history")
-	os.MkdirAll(historyDir, 0755)
-	historyFile := filepath.Join(historyDir, opts.GoalID+".md")
-	if err := os.Rename(goalFile, historyFile); err == nil {
+	progress("archiving goal")
+	if historyFile, err := l.MoveGoal(opts.GoalID, layout.ActiveDir, layout.HistoryDir); err == nil {
 		result.GoalArchived = true
 		result.HistoryFile = historyFile
 	}
@@ -259,21 +470,39 @@ func CompleteGoal(opts CompleteOptions) (*Result, *CompleteResult) {
 	// Registry now uses JSONL
 	lockMgr := hub.NewLockManager(opts.VegaDir)
 	lockMgr.WithRegistryLock("complete-goal", func() error {
-		return completeGoalInRegistry(opts.VegaDir, opts.GoalID, goalTitle, opts.Project)
+		return completeGoalInRegistry(opts.VegaDir, opts.GoalID, opts.User)
 	})
 
-	// Step 6: Update project config
-	projectConfig := filepath.Join(opts.VegaDir, "projects", opts.Project+".md")
-	completeGoalInProjectConfig(projectConfig, opts.GoalID, goalTitle)
+	// Step 6: Regenerate the goal lists of the goal's projects
+	SyncProjectGoals(opts.VegaDir, goalProjects(opts.VegaDir, opts.GoalID, opts.Project)...)
+
+	// Step 7: Tell the linked issue
+	if opts.CommentIssue {
+		if entry, err := goals.NewRegistry(opts.VegaDir).Get(opts.GoalID); err != nil || entry.IssueURL == "" {
+			result.IssueError = "goal has no linked issue"
+		} else {
+			progress("commenting on issue")
+			if err := issueCommenter(entry.IssueURL, completionComment(result)); err != nil {
+				result.IssueError = err.Error()
+			} else {
+				result.IssueCommented = true
+			}
+		}
+	}
 
 	return &Result{Success: true}, result
 }
 
 // IceGoal pauses a goal for later
 func IceGoal(opts IceOptions) (*Result, *IceResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+
 	// Validate goal exists and is active
-	goalFile := filepath.Join(opts.VegaDir, "goals", "active", opts.GoalID+".md")
-	if _, err := os.Stat(goalFile); os.IsNotExist(err) {
+	l := layout.New(opts.VegaDir)
+	goalFile := l.FindGoalFileIn(layout.ActiveDir, opts.GoalID)
+	if goalFile == "" {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -288,7 +517,7 @@ func IceGoal(opts IceOptions) (*Result, *IceResult) {
 	goalTitle := getGoalTitle(goalFile, opts.GoalID)
 
 	// Get project base folder
-	projectBase := filepath.Join(opts.VegaDir, "workspaces", opts.Project, "worktree-base")
+	projectBase := l.WorktreeBase(opts.Project)
 	if _, err := os.Stat(projectBase); os.IsNotExist(err) {
 		return &Result{
 			Success: false,
@@ -301,7 +530,7 @@ func IceGoal(opts IceOptions) (*Result, *IceResult) {
 	}
 
 	// Find worktree
-	worktreeDir, err := findWorktreeDir(opts.VegaDir, opts.Project, opts.GoalID)
+	worktreeDir, err := FindGoalWorktree(opts.VegaDir, opts.Project, opts.GoalID)
 	if err != nil {
 		return &Result{
 			Success: false,
@@ -313,12 +542,17 @@ func IceGoal(opts IceOptions) (*Result, *IceResult) {
 		}, nil
 	}
 
-	// Get branch name
-	branchName, _ := getWorktreeBranch(worktreeDir)
+	// Get branch name (plain goals have none)
+	plain := isPlainProject(opts.VegaDir, opts.Project)
+	var branchName string
+	if !plain {
+		branchName, _ = getWorktreeBranch(worktreeDir)
+	}
 
 	// Safety check - only needed if removing worktree
-	if opts.RemoveWorktree && !opts.Force {
-		if err := checkWorktreeClean(worktreeDir); err != nil {
+	backend := projectBackendOrGit(opts.VegaDir, opts.Project)
+	if opts.RemoveWorktree && !opts.Force && !plain {
+		if err := checkWorktreeClean(backend, worktreeDir); err != nil {
 			return &Result{
 				Success: false,
 				Error: &ErrorInfo{
@@ -339,7 +573,11 @@ func IceGoal(opts IceOptions) (*Result, *IceResult) {
 
 	// Step 1: Optionally remove worktree (branch always preserved)
 	if opts.RemoveWorktree {
-		removeWorktree(projectBase, worktreeDir)
+		if plain {
+			unlinkPlainWorkspace(worktreeDir)
+		} else {
+			backend.RemoveWorkspace(projectBase, worktreeDir)
+		}
 		result.WorktreeRemoved = true
 	} else {
 		result.WorktreePreserved = worktreeDir
@@ -347,29 +585,32 @@ func IceGoal(opts IceOptions) (*Result, *IceResult) {
 	result.BranchPreserved = branchName
 
 	// Step 2: Move goal file to iced
-	icedDir := filepath.Join(opts.VegaDir, "goals", "iced")
-	os.MkdirAll(icedDir, 0755)
-	icedFile := filepath.Join(icedDir, opts.GoalID+".md")
-	os.Rename(goalFile, icedFile)
-
-	// Step 3: Update goal file with iced status
-	updateGoalStatus(icedFile, "iced", opts.Reason)
+	if icedFile, err := l.MoveGoal(opts.GoalID, layout.ActiveDir, layout.IcedDir); err == nil {
+		// Step 3: Update goal file with iced status
+		updateGoalStatus(icedFile, "iced", opts.Reason)
+	}
 
 	// Step 4: Update registry (with lock to prevent race conditions)
 	// Registry now uses JSONL
 	lockMgr := hub.NewLockManager(opts.VegaDir)
 	lockMgr.WithRegistryLock("ice-goal", func() error {
-		return iceGoalInRegistry(opts.VegaDir, opts.GoalID, opts.Reason)
+		return iceGoalInRegistry(opts.VegaDir, opts.GoalID, opts.Reason, opts.User)
 	})
+	SyncProjectGoals(opts.VegaDir, goalProjects(opts.VegaDir, opts.GoalID, opts.Project)...)
 
 	return &Result{Success: true}, result
 }
 
 // ResumeGoal resumes an iced goal
 func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+
 	// Validate goal exists and is iced
-	icedFile := filepath.Join(opts.VegaDir, "goals", "iced", opts.GoalID+".md")
-	if _, err := os.Stat(icedFile); os.IsNotExist(err) {
+	l := layout.New(opts.VegaDir)
+	icedFile := l.FindGoalFileIn(layout.IcedDir, opts.GoalID)
+	if icedFile == "" {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -396,7 +637,7 @@ func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
 		}, nil
 	}
 
-	projectBase := filepath.Join(opts.VegaDir, "workspaces", opts.Project, "worktree-base")
+	projectBase := l.WorktreeBase(opts.Project)
 
 	result := &ResumeResult{
 		GoalID:  opts.GoalID,
@@ -405,10 +646,7 @@ func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
 	}
 
 	// Step 1: Move goal file back to active
-	activeDir := filepath.Join(opts.VegaDir, "goals", "active")
-	os.MkdirAll(activeDir, 0755)
-	activeFile := filepath.Join(activeDir, opts.GoalID+".md")
-	if err := os.Rename(icedFile, activeFile); err != nil {
+	if _, err := l.MoveGoal(opts.GoalID, layout.IcedDir, layout.ActiveDir); err != nil {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -425,10 +663,27 @@ func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
 	lockMgr.WithRegistryLock("resume-goal", func() error {
 		return resumeGoalInRegistry(opts.VegaDir, opts.GoalID, goalTitle, opts.Project)
 	})
+	SyncProjectGoals(opts.VegaDir, goalProjects(opts.VegaDir, opts.GoalID, opts.Project)...)
 
 	// Step 3: Check if worktree exists, recreate if needed
-	worktreeDir, err := findWorktreeDir(opts.VegaDir, opts.Project, opts.GoalID)
-	if err != nil {
+	worktreeDir, err := FindGoalWorktree(opts.VegaDir, opts.Project, opts.GoalID)
+	if err != nil && project.IsPlain() {
+		// Plain goals only need their workspace link back
+		worktreePath := l.Workspace(opts.Project, plainWorkspaceName(opts.GoalID, goalTitle))
+		if err := linkPlainWorkspace(projectBase, worktreePath); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "worktree_create_failed",
+					Message: "Could not link the goal workspace to the project folder",
+					Details: map[string]string{"error": err.Error()},
+				},
+			}, nil
+		}
+		result.WorktreeCreated = true
+		result.WorktreePath = worktreePath
+		CopyHooksToWorktree(opts.VegaDir, worktreePath)
+	} else if err != nil {
 		// Worktree doesn't exist, need to recreate it
 		// Find the branch
 		branchName, err := findGoalBranch(projectBase, opts.GoalID)
@@ -448,9 +703,13 @@ func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
 		if baseBranch == "" {
 			baseBranch = "main"
 		}
-		worktreePath := filepath.Join(opts.VegaDir, "workspaces", opts.Project, branchName)
+		worktreePath := l.Workspace(opts.Project, goalWorkspaceName(branchName))
 
-		if err := recreateWorktree(projectBase, worktreePath, branchName); err != nil {
+		backend, err := gitsvc.BackendFor(project.VCS)
+		if err != nil {
+			return vcsResult(opts.Project, err), nil
+		}
+		if err := recreateWorktree(backend, projectBase, worktreePath, branchName, project.CloneOptions); err != nil {
 			return &Result{
 				Success: false,
 				Error: &ErrorInfo{
@@ -465,7 +724,7 @@ func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
 		result.WorktreePath = worktreePath
 
 		// Copy hooks to worktree
-		copyHooksToWorktree(opts.VegaDir, worktreePath)
+		CopyHooksToWorktree(opts.VegaDir, worktreePath)
 	} else {
 		// Worktree already exists
 		result.WorktreeExisted = true
@@ -477,12 +736,13 @@ func ResumeGoal(opts ResumeOptions) (*Result, *ResumeResult) {
 
 // CleanupGoal deletes a completed goal's branch
 func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
-	// Check goal is in history
-	historyFile := filepath.Join(opts.VegaDir, "goals", "history", opts.GoalID+".md")
-	activeFile := filepath.Join(opts.VegaDir, "goals", "active", opts.GoalID+".md")
-	icedFile := filepath.Join(opts.VegaDir, "goals", "iced", opts.GoalID+".md")
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
 
-	if _, err := os.Stat(activeFile); err == nil {
+	// Check goal is in history
+	l := layout.New(opts.VegaDir)
+	if l.FindGoalFileIn(layout.ActiveDir, opts.GoalID) != "" {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -493,7 +753,7 @@ func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
 		}, nil
 	}
 
-	if _, err := os.Stat(icedFile); err == nil {
+	if l.FindGoalFileIn(layout.IcedDir, opts.GoalID) != "" {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -504,7 +764,7 @@ func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
 		}, nil
 	}
 
-	if _, err := os.Stat(historyFile); os.IsNotExist(err) {
+	if l.FindGoalFileIn(layout.HistoryDir, opts.GoalID) == "" {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -516,7 +776,7 @@ func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
 	}
 
 	// Get project base folder
-	projectBase := filepath.Join(opts.VegaDir, "workspaces", opts.Project, "worktree-base")
+	projectBase := l.WorktreeBase(opts.Project)
 	if _, err := os.Stat(projectBase); os.IsNotExist(err) {
 		return &Result{
 			Success: false,
@@ -528,6 +788,11 @@ func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
 		}, nil
 	}
 
+	// Plain goals leave no branch behind
+	if isPlainProject(opts.VegaDir, opts.Project) {
+		return &Result{Success: true}, &CleanupResult{GoalID: opts.GoalID, Project: opts.Project}
+	}
+
 	// Find branch
 	branchName, err := findGoalBranch(projectBase, opts.GoalID)
 	if err != nil {
@@ -549,7 +814,7 @@ func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
 	}
 
 	// Delete branch
-	if err := deleteBranch(projectBase, branchName); err != nil {
+	if err := projectBackendOrGit(opts.VegaDir, opts.Project).DeleteBranch(projectBase, branchName); err != nil {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -566,6 +831,29 @@ func CleanupGoal(opts CleanupOptions) (*Result, *CleanupResult) {
 
 // CreateGoal creates a new goal with worktree
 func CreateGoal(opts CreateOptions) (*Result, *CreateResult) {
+	if errResult := checkInputs(
+		idInput("project", opts.Project),
+		idInput("parent goal ID", opts.ParentID),
+		refInput("base branch", opts.BaseBranch),
+	); errResult != nil {
+		return errResult, nil
+	}
+	issue, err := goals.NormalizeIssueLink(opts.Issue)
+	if err != nil {
+		return invalidIssueResult(err), nil
+	}
+	tags, err := goals.NormalizeTags(opts.Tags)
+	if err != nil {
+		return invalidTagsResult(err), nil
+	}
+	alias, err := goals.NormalizeAlias(opts.Alias)
+	if err != nil {
+		return invalidAliasResult(err), nil
+	}
+	if errResult := checkAliasFree(opts.VegaDir, alias); errResult != nil {
+		return errResult, nil
+	}
+
 	hm := goals.NewHierarchyManager(opts.VegaDir)
 
 	// Handle parent goal (hierarchical creation)
@@ -655,16 +943,41 @@ func CreateGoal(opts CreateOptions) (*Result, *CreateResult) {
 		}
 	} else {
 		// Generate unique root goal ID
-		goalID = generateGoalID()
+		goalID, err = uniqueGoalID(opts.VegaDir)
+		if err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "id_generation_failed",
+					Message: "Failed to generate unique goal ID",
+					Details: map[string]string{"error": err.Error()},
+				},
+			}, nil
+		}
 	}
 
 	slug := slugify(opts.Title)
 	_ = parentDetail // Used for hierarchy setup later
-	branchName := fmt.Sprintf("goal-%s-%s", goalID, slug)
-
-	// Create goal file
-	goalFile := filepath.Join(opts.VegaDir, "goals", "active", goalID+".md")
-	if err := createGoalFile(goalFile, goalID, opts.Title, effectiveProject); err != nil {
+	branchName := goals.IssueBranch(issue.TrackerID, fmt.Sprintf("goal-%s-%s", goalID, slug))
+	if project.IsPlain() {
+		// Worked on in place: no branch to create or merge
+		baseBranch, branchName = "", ""
+	}
+
+	// Create goal file (drafts wait in the backlog, branched when activated)
+	l := layout.New(opts.VegaDir)
+	goalFile := l.GoalFile(layout.ActiveDir, goalID)
+	if opts.Draft {
+		branchName = ""
+		goalFile = l.GoalFile(layout.BacklogDir, goalID)
+	}
+	os.MkdirAll(filepath.Dir(goalFile), 0755)
+	if opts.Body != "" {
+		err = os.WriteFile(goalFile, []byte(fmt.Sprintf("# Goal %s: %s\n%s", goalID, opts.Title, opts.Body)), 0644)
+	} else {
+		err = createGoalFile(goalFile, goalID, opts.Title, effectiveProject)
+	}
+	if err != nil {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -679,7 +992,28 @@ func CreateGoal(opts CreateOptions) (*Result, *CreateResult) {
 	// Registry now uses JSONL
 	lockMgr := hub.NewLockManager(opts.VegaDir)
 	if err := lockMgr.WithRegistryLock("create-goal", func() error {
-		return addGoalToRegistry(opts.VegaDir, goalID, opts.Title, effectiveProject)
+		if err := addGoalToRegistry(opts.VegaDir, goalID, opts.Title, effectiveProject); err != nil {
+			return err
+		}
+		registry := goals.NewRegistry(opts.VegaDir)
+		if err := registry.Update(goalID, func(e *goals.RegistryEntry) {
+			e.CreatedBy, e.Tags = opts.CreatedBy, tags
+			if opts.Draft {
+				e.Status = goals.StatusDraft
+				e.Priority = opts.Priority
+			}
+		}); err != nil {
+			return err
+		}
+		if alias != "" {
+			if err := registry.SetAlias(goalID, alias); err != nil {
+				return err
+			}
+		}
+		if issue == (goals.IssueLink{}) {
+			return nil
+		}
+		return registry.SetIssueLink(goalID, issue)
 	}); err != nil {
 		return &Result{
 			Success: false,
@@ -707,49 +1041,273 @@ func CreateGoal(opts CreateOptions) (*Result, *CreateResult) {
 		GoalBranch: branchName,
 		GoalFile:   goalFile,
 		ParentID:   opts.ParentID,
+		IssueURL:   issue.URL,
+		TrackerID:  issue.TrackerID,
+		CreatedBy:  opts.CreatedBy,
+		Tags:       tags,
+		Alias:      alias,
+	}
+	if opts.Draft {
+		result.Draft, result.Priority = true, opts.Priority
+		return &Result{Success: true}, result
+	}
+	SyncProjectGoals(opts.VegaDir, effectiveProject)
+	if warning := StaleBaseWarning(opts.VegaDir, effectiveProject, baseBranch); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	// The goal waits in pending until its worktree is provisioned
+	sm := goals.NewStateManager(opts.VegaDir)
+	if err := sm.TransitionWithUser(goalID, goals.StatePending, "Goal created", opts.CreatedBy, map[string]string{
+		"title":   opts.Title,
+		"project": effectiveProject,
+	}); err != nil {
+		discardGoal(opts.VegaDir, goalID, goalFile)
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "state_transition_failed",
+				Message: "Failed to initialize goal state",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
 	}
 
-	// Create worktree (unless --no-worktree)
-	if !opts.NoWorktree {
-		projectBase := filepath.Join(opts.VegaDir, "workspaces", effectiveProject, "worktree-base")
-		worktreePath := filepath.Join(opts.VegaDir, "workspaces", effectiveProject, branchName)
+	// Create worktree (unless deferred or skipped); a goal that can't get one is removed again
+	switch {
+	case opts.SkipWorktree:
+		if err := readyWithoutWorktree(sm, goalID, opts.CreatedBy); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+	case !opts.NoWorktree:
+		if errResult := ProvisionGoalWorktree(opts.VegaDir, result, ProvisionOptions{Preflight: opts.Preflight, User: opts.CreatedBy}); errResult != nil {
+			discardGoal(opts.VegaDir, goalID, goalFile)
+			return errResult, nil
+		}
+	}
+
+	return &Result{Success: true}, result
+}
+
+// readyWithoutWorktree moves a goal that won't get a worktree on to working
+func readyWithoutWorktree(sm *goals.StateManager, goalID, user string) error {
+	if err := sm.TransitionWithUser(goalID, goals.StateBranching, "No worktree requested", user, nil); err != nil {
+		return fmt.Errorf("failed to transition to branching state: %w", err)
+	}
+	if err := sm.TransitionWithUser(goalID, goals.StateWorking, "Goal ready (no worktree)", user, nil); err != nil {
+		return fmt.Errorf("failed to transition to working state: %w", err)
+	}
+	return nil
+}
 
-		if err := createWorktree(projectBase, worktreePath, branchName, baseBranch); err != nil {
+// uniqueGoalID generates a root goal ID that isn't in the registry yet
+func uniqueGoalID(vegaDir string) (string, error) {
+	registry := goals.NewRegistry(vegaDir)
+	const maxAttempts = 10
+	for i := 0; i < maxAttempts; i++ {
+		id := generateGoalID()
+		if _, err := registry.Get(id); errors.Is(err, goals.ErrNotFound) {
+			return id, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no unused goal ID after %d attempts", maxAttempts)
+}
+
+// discardGoal removes a goal whose creation failed: its file (the folder of
+// a folder-style goal), sidecars and registry entry
+func discardGoal(vegaDir, goalID, goalFile string) {
+	if layout.New(vegaDir).GoalStyle() == layout.Folder {
+		os.RemoveAll(filepath.Dir(goalFile))
+	} else {
+		os.Remove(goalFile)
+		for _, suffix := range []string{".state.jsonl", ".metadata.json", ".hierarchy.json"} {
+			os.Remove(layout.Sidecar(goalFile, suffix))
+		}
+	}
+	projects := goalProjects(vegaDir, goalID, "")
+	hub.NewLockManager(vegaDir).WithRegistryLock("discard-goal", func() error {
+		return goals.NewRegistry(vegaDir).Delete(goalID)
+	})
+	SyncProjectGoals(vegaDir, projects...)
+}
+
+// ProvisionOptions contains options for provisioning a goal's worktree
+type ProvisionOptions struct {
+	Preflight bool              // Run hub.CreateChecks on the project checkout before branching
+	User      string            // Recorded on the goal's state transitions
+	Progress  func(step string) // Called before each step, if set
+
+	// Context, if set and canceled before the worktree is made, fails the goal
+	Context context.Context
+}
+
+// ProvisionGoalWorktree creates the worktree of a new or activated goal,
+// moving it from pending through branching to working (or failed): it
+// claims a prewarmed worktree or creates one (links the project folder for
+// plain projects), copies the hooks, and records the worktree in the goal
+// file and project config. It fills in goal.WorktreePath and goal.FromPool.
+func ProvisionGoalWorktree(vegaDir string, goal *CreateResult, opts ProvisionOptions) *Result {
+	sm := goals.NewStateManager(vegaDir)
+	// New goals are pending since CreateGoal; activated drafts have no state yet
+	if last, _ := sm.GetLastEvent(goal.GoalID); last == nil {
+		sm.TransitionWithUser(goal.GoalID, goals.StatePending, "Goal activated", opts.User, nil)
+	}
+	sm.TransitionWithUser(goal.GoalID, goals.StateBranching, "Creating worktree", opts.User, map[string]string{
+		"branch":      goal.GoalBranch,
+		"base_branch": goal.BaseBranch,
+	})
+
+	var errResult *Result
+	if opts.Context != nil && opts.Context.Err() != nil {
+		errResult = &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "canceled",
+				Message: "Provisioning canceled",
+				Details: map[string]string{"error": opts.Context.Err().Error()},
+			},
+		}
+	} else {
+		errResult = provisionGoalWorktree(vegaDir, goal, opts.Preflight, opts.Progress)
+	}
+	if errResult != nil {
+		sm.TransitionWithUser(goal.GoalID, goals.StateFailed, errResult.Error.Message, opts.User, map[string]string{"code": errResult.Error.Code})
+		return errResult
+	}
+	sm.TransitionWithUser(goal.GoalID, goals.StateWorking, "Worktree ready", opts.User, map[string]string{"worktree": goal.WorktreePath})
+	return nil
+}
+
+// provisionGoalWorktree creates the worktree without touching the goal's
+// state, optionally running the pre-flight checks on the project checkout
+// once the base is up to date
+func provisionGoalWorktree(vegaDir string, goal *CreateResult, preflight bool, progress func(step string)) *Result {
+	if progress == nil {
+		progress = func(string) {}
+	}
+	projectBase := layout.New(vegaDir).WorktreeBase(goal.Project)
+	var worktreeName string
+	if isPlainProject(vegaDir, goal.Project) {
+		worktreeName = plainWorkspaceName(goal.GoalID, goal.Title)
+		progress("linking workspace")
+		if err := linkPlainWorkspace(projectBase, layout.New(vegaDir).Workspace(goal.Project, worktreeName)); err != nil {
 			return &Result{
 				Success: false,
 				Error: &ErrorInfo{
 					Code:    "worktree_create_failed",
-					Message: "Could not create worktree",
+					Message: "Could not link the goal workspace to the project folder",
 					Details: map[string]string{"error": err.Error()},
 				},
-			}, nil
+			}
 		}
-		result.WorktreePath = worktreePath
+	} else {
+		worktreeName = goalWorkspaceName(goal.GoalBranch)
+		worktreePath := layout.New(vegaDir).Workspace(goal.Project, worktreeName)
 
-		// Copy hooks to worktree
-		copyHooksToWorktree(opts.VegaDir, worktreePath)
+		// Branch off the latest base from origin
+		progress("fetching base branch")
+		if err := SyncBaseBranch(vegaDir, goal.Project, goal.BaseBranch, "goal-create-"+goal.GoalID); err != nil {
+			return baseSyncResult(goal.Project, err)
+		}
+		if preflight {
+			progress("checking")
+			if result := hub.NewPreflightChecker(projectBase, goal.BaseBranch, goal.GoalBranch).RunChecks(hub.CreateChecks); !result.Ready {
+				details := map[string]string{
+					"blocking_issues": strings.Join(result.BlockingIssues, ", "),
+					"fix_commands":    strings.Join(result.FixCommands, "\n"),
+				}
+				return &Result{
+					Success: false,
+					Error: &ErrorInfo{
+						Code:    "preflight_failed",
+						Message: fmt.Sprintf("Project checkout %s is not ready for a new goal branch", projectBase),
+						Details: details,
+					},
+					Data: result,
+				}
+			}
+		}
 
-		// Write worktree metadata to goal file
-		worktreeSection := fmt.Sprintf("\n## Worktree\n- **Branch**: %s\n- **Project**: %s\n- **Path**: workspaces/%s/%s\n- **Base Branch**: %s\n- **Created**: %s\n",
-			branchName, effectiveProject, effectiveProject, branchName, baseBranch, time.Now().Format("2006-01-02"))
+		backend, err := ProjectBackend(vegaDir, goal.Project)
+		if err != nil {
+			return vcsResult(goal.Project, err)
+		}
 
-		// Read existing content and insert before Status section
-		if content, err := os.ReadFile(goalFile); err == nil {
-			contentStr := string(content)
-			if idx := strings.Index(contentStr, "## Status"); idx != -1 {
-				contentStr = contentStr[:idx] + worktreeSection + "\n" + contentStr[idx:]
-			} else {
-				contentStr += worktreeSection
+		// Hold worktree-base and the goal's branch while the branch is made
+		lockMgr := hub.NewLockManager(vegaDir)
+		baseLock, err := lockMgr.AcquireWorktreeBase(goal.Project, "goal-create-"+goal.GoalID)
+		if err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "lock_failed",
+					Message: "Failed to acquire worktree-base lock",
+					Details: map[string]string{"project": goal.Project, "error": err.Error()},
+				},
+			}
+		}
+		defer baseLock.Release()
+		branchLock, err := lockMgr.AcquireBranch(goal.Project, goal.GoalID, "goal-create-"+goal.GoalID)
+		if err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "lock_failed",
+					Message: "Failed to acquire branch lock",
+					Details: map[string]string{"project": goal.Project, "goal_id": goal.GoalID, "error": err.Error()},
+				},
 			}
-			os.WriteFile(goalFile, []byte(contentStr), 0644)
 		}
+		defer branchLock.Release()
 
-		// Update project config
-		projectConfig := filepath.Join(opts.VegaDir, "projects", effectiveProject+".md")
-		addGoalToProjectConfig(projectConfig, goalID, opts.Title)
+		// Prefer a prewarmed worktree; an empty pool or failed claim falls back to a fresh one
+		if backend.Name() == gitsvc.VCSGit {
+			progress("claiming pooled worktree")
+			goal.FromPool, _ = ClaimPooledWorktree(vegaDir, goal.Project, worktreePath, goal.GoalBranch, goal.BaseBranch)
+		}
+		if !goal.FromPool {
+			progress("creating worktree")
+			if err := createWorktree(backend, projectBase, worktreePath, goal.GoalBranch, goal.BaseBranch, getProjectCloneOptions(vegaDir, goal.Project)); err != nil {
+				return &Result{
+					Success: false,
+					Error: &ErrorInfo{
+						Code:    "worktree_create_failed",
+						Message: "Could not create worktree",
+						Details: map[string]string{"error": err.Error()},
+					},
+				}
+			}
+		}
 	}
+	worktreePath := layout.New(vegaDir).Workspace(goal.Project, worktreeName)
+	goal.WorktreePath = worktreePath
 
-	return &Result{Success: true}, result
+	// Copy hooks to worktree
+	progress("installing hooks")
+	CopyHooksToWorktree(vegaDir, worktreePath)
+
+	// Write worktree metadata to goal file
+	progress("recording worktree")
+	worktreeSection := fmt.Sprintf("\n## Worktree\n- **Branch**: %s\n- **Project**: %s\n- **Path**: workspaces/%s/%s\n- **Base Branch**: %s\n- **Created**: %s\n",
+		goal.GoalBranch, goal.Project, goal.Project, worktreeName, goal.BaseBranch, time.Now().Format("2006-01-02"))
+	if goal.GoalBranch == "" {
+		worktreeSection = fmt.Sprintf("\n## Worktree\n- **Project**: %s\n- **Path**: workspaces/%s/%s\n- **Created**: %s\n",
+			goal.Project, goal.Project, worktreeName, time.Now().Format("2006-01-02"))
+	}
+
+	// Read existing content and insert before Status section
+	if content, err := os.ReadFile(goal.GoalFile); err == nil {
+		contentStr := string(content)
+		if idx := strings.Index(contentStr, "## Status"); idx != -1 {
+			contentStr = contentStr[:idx] + worktreeSection + "\n" + contentStr[idx:]
+		} else {
+			contentStr += worktreeSection
+		}
+		os.WriteFile(goal.GoalFile, []byte(contentStr), 0644)
+	}
+	return nil
 }
 
 // ListProjects returns all registered projects
@@ -787,26 +1345,6 @@ func getProjectBaseBranch(vegaDir, project string) (string, error) {
 	return p.BaseBranch, nil
 }
 
-func findWorktreeDir(vegaDir, project, goalID string) (string, error) {
-	pattern := filepath.Join(vegaDir, "workspaces", project, fmt.Sprintf("goal-%s-*", goalID))
-	matches, _ := filepath.Glob(pattern)
-
-	var dirs []string
-	for _, m := range matches {
-		if info, err := os.Stat(m); err == nil && info.IsDir() {
-			dirs = append(dirs, m)
-		}
-	}
-
-	if len(dirs) == 0 {
-		return "", fmt.Errorf("no worktree found matching pattern: goal-%s-*", goalID)
-	}
-	if len(dirs) > 1 {
-		return "", fmt.Errorf("multiple worktrees found for goal %s", goalID)
-	}
-	return dirs[0], nil
-}
-
 func getWorktreeBranch(worktreeDir string) (string, error) {
 	cmd := exec.Command("git", "-C", worktreeDir, "branch", "--show-current")
 	output, err := cmd.Output()
@@ -820,58 +1358,100 @@ func getWorktreeBranch(worktreeDir string) (string, error) {
 	return branch, nil
 }
 
-func checkWorktreeClean(worktreeDir string) error {
-	cmd := exec.Command("git", "-C", worktreeDir, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("git status failed: %w", err)
-	}
-	if len(strings.TrimSpace(string(output))) > 0 {
-		return fmt.Errorf("uncommitted changes:\n%s", string(output))
-	}
-	return nil
+// checkWorktreeClean fails when the worktree has changes, other than the
+// output log vega-hub writes there for spawned executors
+func checkWorktreeClean(backend gitsvc.Backend, worktreeDir string) error {
+	return backend.CheckClean(worktreeDir, []string{".executor-output.log"})
 }
 
-func mergeBranch(projectBase, sourceBranch, targetBranch, goalID, goalTitle string) error {
-	cmd := exec.Command("git", "-C", projectBase, "checkout", targetBranch)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("checkout %s: %s", targetBranch, string(output))
+// MergeGoalBranch merges a goal branch into the target branch in projectBase
+// with git, using one of the goals.MergeStrategy* strategies. worktreeDir is
+// the goal's worktree, where the branch is checked out (needed to rebase it).
+// Use ProjectBackend(...).Merge to honor the project's VCS.
+func MergeGoalBranch(projectBase, worktreeDir, sourceBranch, targetBranch, strategy, message string) error {
+	return gitsvc.GitBackend{}.Merge(projectBase, worktreeDir, sourceBranch, targetBranch, strategy, message)
+}
+
+// cherryPickResult reports selected commits that couldn't be picked onto the base
+func cherryPickResult(branch, target string, err error) *Result {
+	details := map[string]string{"source": branch, "target": target, "error": err.Error()}
+	var pickErr *gitsvc.CherryPickError
+	switch {
+	case errors.As(err, &pickErr):
+		details["commit"] = pickErr.Commit
+		details["conflicts"] = strings.Join(pickErr.Conflicts, ", ")
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "cherry_pick_conflict",
+				Message: fmt.Sprintf("Commit %s doesn't apply cleanly to '%s'; nothing was picked", pickErr.Commit, target),
+				Details: details,
+			},
+		}
+	case errors.Is(err, gitsvc.ErrUnsupported):
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "cherry_pick_unsupported",
+				Message: "The project's VCS can't cherry-pick commits; complete with a merge strategy",
+				Details: details,
+			},
+		}
+	}
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "merge_failed",
+			Message: "Cherry-pick failed",
+			Details: details,
+		},
 	}
+}
 
-	mergeMsg := fmt.Sprintf("Merge goal %s: %s", goalID, goalTitle)
-	cmd = exec.Command("git", "-C", projectBase, "merge", sourceBranch, "-m", mergeMsg)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("merge: %s", string(output))
+// pickedViolations keeps the commit policy violations of picked commits
+func pickedViolations(violations []goals.CommitViolation, picks []string) []goals.CommitViolation {
+	var kept []goals.CommitViolation
+	for _, v := range violations {
+		for _, pick := range picks {
+			if strings.HasPrefix(pick, v.Commit) {
+				kept = append(kept, v)
+				break
+			}
+		}
 	}
-	return nil
+	return kept
 }
 
-func removeWorktree(projectBase, worktreeDir string) {
-	// Calculate relative path from projectBase to worktreeDir
-	relPath, err := filepath.Rel(projectBase, worktreeDir)
-	if err != nil {
-		relPath = worktreeDir
+// reviewGateResult reports a completion refused by goals.CheckReview
+func reviewGateResult(goalID string, err error) *Result {
+	code := "review_required"
+	if errors.Is(err, goals.ErrChangesRequested) {
+		code = "changes_requested"
 	}
-	cmd := exec.Command("git", "-C", projectBase, "worktree", "remove", relPath, "--force")
-	if err := cmd.Run(); err != nil {
-		os.RemoveAll(worktreeDir)
-		exec.Command("git", "-C", projectBase, "worktree", "prune").Run()
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    code,
+			Message: fmt.Sprintf("Goal '%s' can't be completed: %v", goalID, err),
+			Details: map[string]string{"goal_id": goalID},
+		},
 	}
 }
 
-func deleteBranch(projectBase, branchName string) error {
-	cmd := exec.Command("git", "-C", projectBase, "branch", "-d", branchName)
-	if err := cmd.Run(); err != nil {
-		cmd = exec.Command("git", "-C", projectBase, "branch", "-D", branchName)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("could not delete branch: %w", err)
-		}
+// getProjectMergePolicy returns the project's merge policy (empty if the config can't be read)
+func getProjectMergePolicy(vegaDir, project string) goals.MergePolicy {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return goals.MergePolicy{}
 	}
-	return nil
+	return p.MergePolicy
 }
 
 func findGoalBranch(projectBase, goalID string) (string, error) {
-	cmd := exec.Command("git", "-C", projectBase, "branch", "--list", fmt.Sprintf("goal-%s-*", goalID))
+	// --format drops the "*" and "+" markers for branches checked out in worktrees.
+	// Branches of goals linked to an issue are prefixed with its tracker ID.
+	cmd := exec.Command("git", "-C", projectBase, "branch", "--list", "--format=%(refname:short)",
+		fmt.Sprintf("goal-%s-*", goalID), fmt.Sprintf("*/goal-%s-*", goalID))
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("git branch list failed: %w", err)
@@ -884,7 +1464,7 @@ func findGoalBranch(projectBase, goalID string) (string, error) {
 
 	lines := strings.Split(branches, "\n")
 	for _, line := range lines {
-		branch := strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		branch := strings.TrimSpace(line)
 		if branch != "" {
 			return branch, nil
 		}
@@ -892,9 +1472,20 @@ func findGoalBranch(projectBase, goalID string) (string, error) {
 	return "", fmt.Errorf("no valid branch found")
 }
 
+// goalWorkspaceName is the directory of a goal branch's worktree: the branch
+// without its tracker ID prefix
+func goalWorkspaceName(branch string) string {
+	return path.Base(branch)
+}
+
 func generateGoalID() string {
-	// Use first 7 chars of a UUID-like string
-	return fmt.Sprintf("%x", time.Now().UnixNano())[:7]
+	// 7 random hex chars; the leading digits of the clock only change about
+	// once a minute, so goals created back to back (e.g. clones) would collide
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%07x", time.Now().UnixNano()&0xfffffff)
+	}
+	return hex.EncodeToString(b)[:7]
 }
 
 func slugify(title string) string {
@@ -940,65 +1531,16 @@ Current Phase: 1/?
 	return os.WriteFile(goalFile, []byte(content), 0644)
 }
 
-func createWorktree(projectBase, worktreePath, branchName, baseBranch string) error {
-	// Calculate relative path from projectBase to worktreePath
-	// projectBase is like /path/workspaces/project/worktree-base
-	// worktreePath is like /path/workspaces/project/goal-xxx
-	// So relative path should be ../goal-xxx
-	relPath, err := filepath.Rel(projectBase, worktreePath)
-	if err != nil {
-		// Fall back to absolute path if relative fails
-		relPath = worktreePath
-	}
-	cmd := exec.Command("git", "-C", projectBase, "worktree", "add", "-b", branchName, relPath, baseBranch)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git worktree add: %s", string(output))
-	}
-	return nil
-}
-
-func copyHooksToWorktree(vegaDir, worktreePath string) {
-	// Copy hooks from template
-	templateHooks := filepath.Join(vegaDir, "templates", "project-init", ".claude", "hooks")
-	destHooks := filepath.Join(worktreePath, ".claude", "hooks")
-
-	os.MkdirAll(destHooks, 0755)
-
-	// Copy each hook file
-	files, _ := os.ReadDir(templateHooks)
-	for _, f := range files {
-		src := filepath.Join(templateHooks, f.Name())
-		dst := filepath.Join(destHooks, f.Name())
-		if content, err := os.ReadFile(src); err == nil {
-			os.WriteFile(dst, content, 0755)
-		}
+// createWorktree creates a goal workspace on a new branch off baseBranch.
+// Sparse paths only apply to git worktrees.
+func createWorktree(backend gitsvc.Backend, projectBase, worktreePath, branchName, baseBranch string, clone goals.CloneOptions) error {
+	if err := backend.AddWorkspace(projectBase, worktreePath, branchName, baseBranch); err != nil {
+		return err
 	}
-
-	// Also copy rules
-	templateRules := filepath.Join(vegaDir, "templates", "project-init", ".claude", "rules")
-	destRules := filepath.Join(worktreePath, ".claude", "rules")
-	os.MkdirAll(destRules, 0755)
-
-	// Recursively copy rules
-	filepath.Walk(templateRules, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
-		rel, _ := filepath.Rel(templateRules, path)
-		dst := filepath.Join(destRules, rel)
-		os.MkdirAll(filepath.Dir(dst), 0755)
-		if content, err := os.ReadFile(path); err == nil {
-			os.WriteFile(dst, content, 0644)
-		}
+	if backend.Name() != gitsvc.VCSGit {
 		return nil
-	})
-
-	// Copy settings.local.json
-	templateSettings := filepath.Join(vegaDir, "templates", "project-init", ".claude", "settings.local.json")
-	destSettings := filepath.Join(worktreePath, ".claude", "settings.local.json")
-	if content, err := os.ReadFile(templateSettings); err == nil {
-		os.WriteFile(destSettings, content, 0644)
 	}
+	return ApplySparseCheckout(worktreePath, clone)
 }
 
 func addGoalToRegistry(vegaDir, goalID, title, project string) error {
@@ -1015,71 +1557,44 @@ func addGoalToRegistry(vegaDir, goalID, title, project string) error {
 	})
 }
 
-func addGoalToProjectConfig(configPath, goalID, title string) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
+// goalProjects returns the projects the registry lists for a goal, or
+// fallback if it has none
+func goalProjects(vegaDir, goalID, fallback string) []string {
+	if entry, err := goals.NewRegistry(vegaDir).Get(goalID); err == nil && len(entry.Projects) > 0 {
+		return entry.Projects
 	}
+	return []string{fallback}
+}
 
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-
-	// Add to Active Goals section
-	for _, line := range lines {
-		newLines = append(newLines, line)
-		if strings.Contains(line, "## Active Goals") {
-			newLines = append(newLines, fmt.Sprintf("- %s: %s", goalID, title))
-		}
+// SyncProjectGoals regenerates the goal lists in the configs of projects from
+// the registry, under the registry lock. Failures are logged: the registry
+// stays authoritative and the next sync catches up.
+func SyncProjectGoals(vegaDir string, projects ...string) {
+	err := hub.NewLockManager(vegaDir).WithRegistryLock("sync-project-goals", func() error {
+		return goals.SyncProjectGoals(vegaDir, projects...)
+	})
+	if err != nil {
+		log.Printf("[PROJECTS] Could not update project goal lists: %v", err)
 	}
-
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
 }
 
-func completeGoalInRegistry(vegaDir, goalID, goalTitle, project string) error {
+func completeGoalInRegistry(vegaDir, goalID, user string) error {
 	registry := goals.NewRegistry(vegaDir)
 	today := time.Now().Format("2006-01-02")
 	return registry.Update(goalID, func(e *goals.RegistryEntry) {
 		e.Status = "completed"
 		e.CompletedAt = today
+		e.CompletedBy = user
 		e.UpdatedAt = time.Now().Format(time.RFC3339)
 	})
 }
 
-func completeGoalInProjectConfig(configPath, goalID, goalTitle string) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	today := time.Now().Format("2006-01-02")
-
-	activePattern := regexp.MustCompile(fmt.Sprintf(`^- #?%s:`, regexp.QuoteMeta(goalID)))
-
-	for i, line := range lines {
-		if activePattern.MatchString(line) {
-			continue
-		}
-		newLines = append(newLines, line)
-
-		if strings.Contains(line, "| ID | Title | Completed |") {
-			if i+1 < len(lines) && strings.Contains(lines[i+1], "|---") {
-				newLines = append(newLines, lines[i+1])
-				completedRow := fmt.Sprintf("| %s | %s | %s |", goalID, goalTitle, today)
-				newLines = append(newLines, completedRow)
-			}
-		}
-	}
-
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
-}
-
-func iceGoalInRegistry(vegaDir, goalID, reason string) error {
+func iceGoalInRegistry(vegaDir, goalID, reason, user string) error {
 	registry := goals.NewRegistry(vegaDir)
 	return registry.Update(goalID, func(e *goals.RegistryEntry) {
 		e.Status = "iced"
 		e.Reason = reason
+		e.IcedBy = user
 		e.UpdatedAt = time.Now().Format(time.RFC3339)
 	})
 }
@@ -1108,25 +1623,22 @@ func updateGoalStatus(goalFile, status, reason string) error {
 	return os.WriteFile(goalFile, []byte(strings.Join(newLines, "\n")), 0644)
 }
 
-func recreateWorktree(projectBase, worktreePath, branchName string) error {
-	// Calculate relative path from projectBase to worktreePath
-	relPath, err := filepath.Rel(projectBase, worktreePath)
-	if err != nil {
-		relPath = worktreePath
+// recreateWorktree restores a goal workspace for its existing branch
+func recreateWorktree(backend gitsvc.Backend, projectBase, worktreePath, branchName string, clone goals.CloneOptions) error {
+	if err := backend.RestoreWorkspace(projectBase, worktreePath, branchName); err != nil {
+		return err
 	}
-	// Use existing branch (don't create new one)
-	cmd := exec.Command("git", "-C", projectBase, "worktree", "add", relPath, branchName)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git worktree add: %s", string(output))
+	if backend.Name() != gitsvc.VCSGit {
+		return nil
 	}
-	return nil
+	return ApplySparseCheckout(worktreePath, clone)
 }
 
 func resumeGoalInRegistry(vegaDir, goalID, goalTitle, project string) error {
 	registry := goals.NewRegistry(vegaDir)
 	return registry.Update(goalID, func(e *goals.RegistryEntry) {
 		e.Status = "active"
-		e.Reason = ""
+		e.Reason, e.IcedBy = "", ""
 		e.UpdatedAt = time.Now().Format(time.RFC3339)
 	})
 }
@@ -1138,19 +1650,24 @@ func resumeGoalInRegistry(vegaDir, goalID, goalTitle, project string) error {
 // AddProjectOptions contains options for adding a project
 type AddProjectOptions struct {
 	Name       string
-	Path       string // Local path to the repository
+	Path       string // Local path to the repository (or folder, for plain projects)
+	Type       string // goals.ProjectTypeGit (default) or goals.ProjectTypePlain
 	BaseBranch string
+	Policy     goals.MergePolicy
 	VegaDir    string
 }
 
 // AddProjectResult contains the result of adding a project
 type AddProjectResult struct {
 	Name         string `json:"name"`
+	Type         string `json:"type,omitempty"`
 	Path         string `json:"path"`
 	BaseBranch   string `json:"base_branch"`
 	GitRemote    string `json:"git_remote,omitempty"`
 	ConfigFile   string `json:"config_file"`
 	WorktreePath string `json:"worktree_path"`
+	goals.MergePolicy
+	goals.CloneOptions
 }
 
 // RemoveProjectOptions contains options for removing a project
@@ -1174,6 +1691,8 @@ type AddProjectURLOptions struct {
 	Name       string
 	URL        string // Remote URL to clone from
 	BaseBranch string
+	Policy     goals.MergePolicy
+	Clone      goals.CloneOptions // Shallow, partial and sparse clone settings
 	VegaDir    string
 }
 
@@ -1188,14 +1707,49 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 				Message: "Invalid project name",
 				Details: map[string]string{
 					"name":    opts.Name,
-					"allowed": "alphanumeric, dash, underscore",
+					"allowed": "alphanumeric, dash, underscore; starting with a letter or digit",
 				},
 			},
 		}, nil
 	}
+	if errResult := checkInputs(refInput("base branch", opts.BaseBranch)); errResult != nil {
+		return errResult, nil
+	}
+	// git clone would read a leading dash as an option
+	if strings.HasPrefix(opts.URL, "-") {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_input",
+				Message: "Git URL can't start with '-'",
+				Details: map[string]string{"field": "url", "value": opts.URL},
+			},
+		}, nil
+	}
+
+	if err := opts.Policy.Validate(); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_merge_policy",
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	if err := opts.Clone.Validate(); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_clone_options",
+				Message: err.Error(),
+			},
+		}, nil
+	}
 
 	// Check if project already exists
-	configFile := filepath.Join(opts.VegaDir, "projects", opts.Name+".md")
+	l := layout.New(opts.VegaDir)
+	configFile := l.ProjectConfig(opts.Name)
 	if _, err := os.Stat(configFile); err == nil {
 		return &Result{
 			Success: false,
@@ -1208,8 +1762,8 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 	}
 
 	// Create workspace structure
-	workspaceDir := filepath.Join(opts.VegaDir, "workspaces", opts.Name)
-	worktreeBase := filepath.Join(workspaceDir, "worktree-base")
+	workspaceDir := l.ProjectWorkspace(opts.Name)
+	worktreeBase := filepath.Join(workspaceDir, layout.WorktreeBaseName)
 
 	// Check if worktree-base already exists
 	if _, err := os.Stat(worktreeBase); err == nil {
@@ -1234,9 +1788,8 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 		}, nil
 	}
 
-	// Clone the repository
-	cmd := exec.Command("git", "clone", opts.URL, worktreeBase)
-	output, err := cmd.CombinedOutput()
+	// Clone the repository (shallow, partial or sparse if configured)
+	output, err := cloneProject(opts.URL, worktreeBase, opts.Clone)
 	if err != nil {
 		// Clean up workspace dir on failure
 		os.RemoveAll(workspaceDir)
@@ -1257,7 +1810,7 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 	// Checkout branch if specified
 	baseBranch := opts.BaseBranch
 	if baseBranch != "" {
-		cmd = exec.Command("git", "-C", worktreeBase, "checkout", baseBranch)
+		cmd := exec.Command("git", "-C", worktreeBase, "checkout", baseBranch)
 		if output, err := cmd.CombinedOutput(); err != nil {
 			// Cleanup and return error
 			os.RemoveAll(workspaceDir)
@@ -1276,7 +1829,7 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 		}
 	} else {
 		// Detect current branch
-		cmd = exec.Command("git", "-C", worktreeBase, "branch", "--show-current")
+		cmd := exec.Command("git", "-C", worktreeBase, "branch", "--show-current")
 		if output, err := cmd.Output(); err == nil {
 			baseBranch = strings.TrimSpace(string(output))
 		}
@@ -1286,10 +1839,12 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 	}
 
 	// Set up .claude/ structure from template
-	templateDir := filepath.Join(opts.VegaDir, "templates", "project-init", ".claude")
+	templateDir := hookTemplateDir(opts.VegaDir)
 	destDir := filepath.Join(worktreeBase, ".claude")
 	if _, err := os.Stat(templateDir); err == nil {
 		copyProjectDir(templateDir, destDir)
+		// Fill in the hub address in the hooks
+		CopyHooksToWorktree(opts.VegaDir, worktreeBase)
 	}
 
 	// Create docs/planning directories
@@ -1303,12 +1858,12 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 
 	// Create project config file
 	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err == nil {
-		createProjectConfigFile(configFile, opts.Name, worktreeBase, baseBranch, opts.URL)
+		createProjectConfigFile(configFile, opts.Name, worktreeBase, baseBranch, opts.URL, opts.Policy, opts.Clone)
 	}
 
 	// Update projects/index.md
-	indexFile := filepath.Join(opts.VegaDir, "projects", "index.md")
-	addProjectToIndexFile(indexFile, opts.Name)
+	indexFile := l.ProjectIndex()
+	addProjectToIndex(indexFile, opts.Name)
 
 	return &Result{Success: true}, &AddProjectResult{
 		Name:         opts.Name,
@@ -1317,6 +1872,8 @@ func AddProjectFromURL(opts AddProjectURLOptions) (*Result, *AddProjectResult) {
 		GitRemote:    opts.URL,
 		ConfigFile:   configFile,
 		WorktreePath: worktreeBase,
+		MergePolicy:  opts.Policy,
+		CloneOptions: opts.Clone,
 	}
 }
 
@@ -1345,32 +1902,6 @@ func copyProjectDir(src, dst string) error {
 	return nil
 }
 
-// addProjectToIndexFile adds a project entry to index.md
-func addProjectToIndexFile(indexPath, name string) error {
-	content, err := os.ReadFile(indexPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	added := false
-
-	for i, line := range lines {
-		newLines = append(newLines, line)
-		if !added && strings.Contains(line, "|---") {
-			if i > 0 && strings.Contains(lines[i-1], "| Project |") {
-				projectRow := fmt.Sprintf("| [%s](%s.md) | `workspaces/%s/worktree-base/` | - | _Add description_ |",
-					name, name, name)
-				newLines = append(newLines, projectRow)
-				added = true
-			}
-		}
-	}
-
-	return os.WriteFile(indexPath, []byte(strings.Join(newLines, "\n")), 0644)
-}
-
 // AddProjectFromPath adds a project from a local path
 func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 	// Validate project name
@@ -1382,14 +1913,39 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 				Message: "Invalid project name",
 				Details: map[string]string{
 					"name":    opts.Name,
-					"allowed": "alphanumeric, dash, underscore",
+					"allowed": "alphanumeric, dash, underscore; starting with a letter or digit",
 				},
 			},
 		}, nil
 	}
+	if errResult := checkInputs(refInput("base branch", opts.BaseBranch)); errResult != nil {
+		return errResult, nil
+	}
 
-	// Validate path exists and is a git repo
-	if _, err := os.Stat(opts.Path); os.IsNotExist(err) {
+	if err := opts.Policy.Validate(); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_merge_policy",
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	plain := opts.Type == goals.ProjectTypePlain
+	if opts.Type != "" && opts.Type != goals.ProjectTypeGit && !plain {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_type",
+				Message: fmt.Sprintf("Invalid project type '%s'", opts.Type),
+				Details: map[string]string{"valid": goals.ProjectTypeGit + ", " + goals.ProjectTypePlain},
+			},
+		}, nil
+	}
+
+	// Validate path exists and is a git repo (plain projects only need the folder)
+	if info, err := os.Stat(opts.Path); err != nil || !info.IsDir() {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -1401,7 +1957,7 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 	}
 
 	gitDir := filepath.Join(opts.Path, ".git")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) && !plain {
 		return &Result{
 			Success: false,
 			Error: &ErrorInfo{
@@ -1413,7 +1969,8 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 	}
 
 	// Check if project already exists
-	configFile := filepath.Join(opts.VegaDir, "projects", opts.Name+".md")
+	l := layout.New(opts.VegaDir)
+	configFile := l.ProjectConfig(opts.Name)
 	if _, err := os.Stat(configFile); err == nil {
 		return &Result{
 			Success: false,
@@ -1427,7 +1984,9 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 
 	// Determine base branch
 	baseBranch := opts.BaseBranch
-	if baseBranch == "" {
+	if plain {
+		baseBranch = ""
+	} else if baseBranch == "" {
 		// Try to detect from repo
 		cmd := exec.Command("git", "-C", opts.Path, "branch", "--show-current")
 		if output, err := cmd.Output(); err == nil {
@@ -1440,14 +1999,16 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 
 	// Get git remote
 	gitRemote := ""
-	cmd := exec.Command("git", "-C", opts.Path, "remote", "get-url", "origin")
-	if output, err := cmd.Output(); err == nil {
-		gitRemote = strings.TrimSpace(string(output))
+	if !plain {
+		cmd := exec.Command("git", "-C", opts.Path, "remote", "get-url", "origin")
+		if output, err := cmd.Output(); err == nil {
+			gitRemote = strings.TrimSpace(string(output))
+		}
 	}
 
 	// Create workspace structure
-	workspaceDir := filepath.Join(opts.VegaDir, "workspaces", opts.Name)
-	worktreeBase := filepath.Join(workspaceDir, "worktree-base")
+	workspaceDir := l.ProjectWorkspace(opts.Name)
+	worktreeBase := filepath.Join(workspaceDir, layout.WorktreeBaseName)
 
 	if err := os.MkdirAll(workspaceDir, 0755); err != nil {
 		return &Result{
@@ -1473,7 +2034,13 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 	}
 
 	// Create project config file
-	if err := createProjectConfigFile(configFile, opts.Name, opts.Path, baseBranch, gitRemote); err != nil {
+	var err error
+	if plain {
+		err = createPlainProjectConfigFile(configFile, opts.Name, opts.Path)
+	} else {
+		err = createProjectConfigFile(configFile, opts.Name, opts.Path, baseBranch, gitRemote, opts.Policy, goals.CloneOptions{})
+	}
+	if err != nil {
 		// Cleanup on failure
 		os.RemoveAll(workspaceDir)
 		return &Result{
@@ -1487,25 +2054,31 @@ func AddProjectFromPath(opts AddProjectOptions) (*Result, *AddProjectResult) {
 	}
 
 	// Update projects/index.md
-	indexFile := filepath.Join(opts.VegaDir, "projects", "index.md")
+	indexFile := l.ProjectIndex()
 	addProjectToIndex(indexFile, opts.Name)
 
 	return &Result{Success: true}, &AddProjectResult{
 		Name:         opts.Name,
+		Type:         opts.Type,
 		Path:         opts.Path,
 		BaseBranch:   baseBranch,
 		GitRemote:    gitRemote,
 		ConfigFile:   configFile,
 		WorktreePath: worktreeBase,
+		MergePolicy:  opts.Policy,
 	}
 }
 
 // RemoveProject removes a project from management
 func RemoveProject(opts RemoveProjectOptions) (*Result, *RemoveProjectResult) {
+	if errResult := checkInputs(idInput("project", opts.Name)); errResult != nil {
+		return errResult, nil
+	}
 	result := &RemoveProjectResult{Name: opts.Name}
 
 	// Check if project config exists
-	configFile := filepath.Join(opts.VegaDir, "projects", opts.Name+".md")
+	l := layout.New(opts.VegaDir)
+	configFile := l.ProjectConfig(opts.Name)
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		return &Result{
 			Success: false,
@@ -1556,14 +2129,17 @@ func RemoveProject(opts RemoveProjectOptions) (*Result, *RemoveProjectResult) {
 	}
 
 	// Update index.md
-	indexFile := filepath.Join(opts.VegaDir, "projects", "index.md")
+	indexFile := l.ProjectIndex()
 	if err := removeProjectFromIndex(indexFile, opts.Name); err == nil {
 		result.IndexUpdated = true
 	}
 
 	// Remove workspace directory (optional, only if it's a symlink or force is set)
-	workspaceDir := filepath.Join(opts.VegaDir, "workspaces", opts.Name)
-	worktreeBase := filepath.Join(workspaceDir, "worktree-base")
+	workspaceDir, err := workspacePath(opts.VegaDir, opts.Name)
+	if err != nil {
+		return &Result{Success: true}, result
+	}
+	worktreeBase := filepath.Join(workspaceDir, layout.WorktreeBaseName)
 
 	// Check if worktree-base is a symlink (local project) - safe to remove
 	if info, err := os.Lstat(worktreeBase); err == nil {
@@ -1585,10 +2161,10 @@ func RemoveProject(opts RemoveProjectOptions) (*Result, *RemoveProjectResult) {
 
 func isValidProjectName(name string) bool {
 	re := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-	return re.MatchString(name)
+	return re.MatchString(name) && pathguard.ValidateID("project", name) == nil
 }
 
-func createProjectConfigFile(path, name, localPath, baseBranch, gitRemote string) error {
+func createProjectConfigFile(path, name, localPath, baseBranch, gitRemote string, policy goals.MergePolicy, clone goals.CloneOptions) error {
 	content := fmt.Sprintf(`# Project: %s
 
 ## Overview
@@ -1600,17 +2176,8 @@ _Brief description of the project_
 **Workspace**: %s
 **Upstream**: %s
 **Base Branch**: %s
-
-## Active Goals
-
-_None currently active_
-
-## Completed Goals
-
-| ID | Title | Completed |
-|----|-------|-----------|
-| | | |
-
+%s%s
+%s
 ## Project Context
 
 ### Key Directories
@@ -1629,6 +2196,9 @@ When working on this project:
 		fmt.Sprintf("`workspaces/%s/worktree-base/`", name),
 		fmt.Sprintf("`%s`", localPath),
 		fmt.Sprintf("`%s`", baseBranch),
+		formatMergePolicy(policy),
+		formatCloneOptions(clone),
+		(&goals.ProjectGoals{}).Markdown(),
 		"`src/`",
 		"`planning-with-files`",
 		"`Goal: <id>`")
@@ -1636,53 +2206,42 @@ When working on this project:
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
-func addProjectToIndex(indexPath, name string) error {
-	content, err := os.ReadFile(indexPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	added := false
-
-	for i, line := range lines {
-		newLines = append(newLines, line)
-
-		// Find the table and add after the header separator
-		if !added && strings.Contains(line, "|---") {
-			// Check if this is in the projects table
-			if i > 0 && strings.Contains(lines[i-1], "| Project |") {
-				// Add new project row
-				projectRow := fmt.Sprintf("| [%s](%s.md) | `workspaces/%s/worktree-base/` | - | _Add description_ |",
-					name, name, name)
-				newLines = append(newLines, projectRow)
-				added = true
-			}
+// formatMergePolicy renders the Merge Policy section of a project config (empty if unset)
+func formatMergePolicy(policy goals.MergePolicy) string {
+	var lines []string
+	if len(policy.ProtectedBranches) > 0 {
+		quoted := make([]string, len(policy.ProtectedBranches))
+		for i, b := range policy.ProtectedBranches {
+			quoted[i] = "`" + b + "`"
 		}
+		lines = append(lines, "**Protected Branches**: "+strings.Join(quoted, ", "))
+	}
+	if policy.RequireMR {
+		lines = append(lines, "**Require MR**: `true`")
+	}
+	if policy.MergeStrategy != "" {
+		lines = append(lines, "**Merge Strategy**: `"+policy.MergeStrategy+"`")
+	}
+	if policy.RequireReview {
+		lines = append(lines, "**Require Review**: `true`")
 	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n## Merge Policy\n\n" + strings.Join(lines, "\n") + "\n"
+}
 
-	return os.WriteFile(indexPath, []byte(strings.Join(newLines, "\n")), 0644)
+// addProjectToIndex lists a project in projects/index.md
+func addProjectToIndex(indexPath, name string) error {
+	return goals.UpdateMarkdownFile(indexPath, func(doc *goals.MarkdownDoc) error {
+		return doc.AddIndexProject(name)
+	})
 }
 
+// removeProjectFromIndex drops a project from projects/index.md
 func removeProjectFromIndex(indexPath, name string) error {
-	content, err := os.ReadFile(indexPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-
-	// Pattern to match project row
-	pattern := regexp.MustCompile(fmt.Sprintf(`\[%s\]\(%s\.md\)`, regexp.QuoteMeta(name), regexp.QuoteMeta(name)))
-
-	for _, line := range lines {
-		if pattern.MatchString(line) {
-			continue // Skip this line
-		}
-		newLines = append(newLines, line)
-	}
-
-	return os.WriteFile(indexPath, []byte(strings.Join(newLines, "\n")), 0644)
+	return goals.UpdateMarkdownFile(indexPath, func(doc *goals.MarkdownDoc) error {
+		doc.RemoveIndexProject(name)
+		return nil
+	})
 }
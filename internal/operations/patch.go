@@ -0,0 +1,172 @@
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+)
+
+// GoalPatchResult is a goal branch exported as a patch series
+type GoalPatchResult struct {
+	GoalID     string `json:"goal_id"`
+	Project    string `json:"project"`
+	Branch     string `json:"branch"`
+	BaseBranch string `json:"base_branch"`
+	MergeBase  string `json:"merge_base"`
+	Commits    int    `json:"commits"`
+	Patch      []byte `json:"-"` // git format-patch --stdout (mbox)
+}
+
+// GoalPatch exports the commits a goal made since it branched off its base
+// branch with git format-patch, for review or applying on another hub.
+// Uncommitted changes are not included.
+func GoalPatch(opts GoalDiffOptions) (*Result, *GoalPatchResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+	project, worktree, errResult := resolveGoalWorktree(opts)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := patchSupported(opts.VegaDir, project.Name); errResult != nil {
+		return errResult, nil
+	}
+
+	result := &GoalPatchResult{
+		GoalID:  opts.GoalID,
+		Project: project.Name,
+		Branch:  gitsvc.NewExec().CurrentBranch(worktree),
+	}
+	result.BaseBranch, _ = GoalBaseBranch(opts.VegaDir, opts.GoalID, project.Name)
+	if result.BaseBranch == "" {
+		result.BaseBranch = "main"
+	}
+	mergeBase, err := goalMergeBase(worktree, result.BaseBranch)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "merge_base_failed",
+				Message: fmt.Sprintf("Failed to find where the goal branched off %s", result.BaseBranch),
+				Details: map[string]string{"base_branch": result.BaseBranch, "error": err.Error()},
+			},
+		}, nil
+	}
+	result.MergeBase = mergeBase
+
+	output, err := exec.Command("git", "-C", worktree, "rev-list", "--count", "--no-merges", mergeBase+"..HEAD").Output()
+	if err != nil {
+		return gitFailed("rev-list", err), nil
+	}
+	result.Commits, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+
+	result.Patch, err = exec.Command("git", "-C", worktree, "format-patch", "--stdout", "--binary", mergeBase+"..HEAD").Output()
+	if err != nil {
+		return gitFailed("format-patch", err), nil
+	}
+	return &Result{Success: true}, result
+}
+
+// ApplyPatchOptions contains options for applying a patch series to a goal
+type ApplyPatchOptions struct {
+	GoalID  string
+	Project string // Defaults to the first goal project with a worktree
+	Patch   []byte // One or more patches as written by git format-patch
+	VegaDir string
+}
+
+// ApplyPatchResult reports the commits a patch series added to a goal
+type ApplyPatchResult struct {
+	GoalID   string `json:"goal_id"`
+	Project  string `json:"project"`
+	Worktree string `json:"worktree"`
+	Branch   string `json:"branch"`
+	Applied  int    `json:"applied"` // Commits added
+	Head     string `json:"head"`
+}
+
+// ApplyGoalPatch applies a patch series to the goal's worktree with git am,
+// one commit per patch. The worktree must be clean; a patch that doesn't
+// apply aborts the whole series and leaves the branch as it was.
+func ApplyGoalPatch(opts ApplyPatchOptions) (*Result, *ApplyPatchResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID), idInput("project", opts.Project)); errResult != nil {
+		return errResult, nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(opts.Patch), []byte("From ")) {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_patch",
+				Message: "Not a patch series written by git format-patch",
+			},
+		}, nil
+	}
+	project, worktree, errResult := resolveGoalWorktree(GoalDiffOptions{GoalID: opts.GoalID, Project: opts.Project, VegaDir: opts.VegaDir})
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := patchSupported(opts.VegaDir, project.Name); errResult != nil {
+		return errResult, nil
+	}
+	if err := checkWorktreeClean(gitsvc.GitBackend{}, worktree); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "uncommitted_changes",
+				Message: "Worktree has uncommitted changes",
+				Details: map[string]string{"worktree": worktree, "details": err.Error()},
+			},
+		}, nil
+	}
+
+	git := gitsvc.NewExec()
+	before, _ := git.LastCommit(worktree)
+	cmd := exec.Command("git", "-C", worktree, "am", "--3way", "--keep-cr")
+	cmd.Stdin = bytes.NewReader(opts.Patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		details := map[string]string{"output": strings.TrimSpace(string(output))}
+		if conflicts, err := exec.Command("git", "-C", worktree, "diff", "--name-only", "--diff-filter=U").Output(); err == nil && len(conflicts) > 0 {
+			details["conflicts"] = strings.Join(strings.Fields(string(conflicts)), ", ")
+		}
+		exec.Command("git", "-C", worktree, "am", "--abort").Run()
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "patch_conflict",
+				Message: "The patch doesn't apply to the goal branch; nothing was applied",
+				Details: details,
+			},
+		}, nil
+	}
+
+	result := &ApplyPatchResult{
+		GoalID:   opts.GoalID,
+		Project:  project.Name,
+		Worktree: worktree,
+		Branch:   git.CurrentBranch(worktree),
+	}
+	result.Head, _ = git.LastCommit(worktree)
+	if output, err := exec.Command("git", "-C", worktree, "rev-list", "--count", before+"..HEAD").Output(); err == nil {
+		result.Applied, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+	}
+	return &Result{Success: true}, result
+}
+
+// patchSupported refuses patches for projects whose goals aren't git worktrees
+func patchSupported(vegaDir, project string) *Result {
+	if backend := projectBackendOrGit(vegaDir, project); backend.Name() == gitsvc.VCSGit && !isPlainProject(vegaDir, project) {
+		return nil
+	}
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "patch_unsupported",
+			Message: fmt.Sprintf("Project '%s' doesn't use git worktrees; patches need git", project),
+			Details: map[string]string{"project": project},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoalPatchRoundTrip(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	other := filepath.Join(vegaDir, "workspaces", "my-api", "goal-def5678-port")
+	git := func(dir string, args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git(base, "worktree", "add", other, "-b", "goal-def5678-port")
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "def5678.md"), []byte("# Goal #def5678: Port\n"), 0644)
+
+	result, patch := GoalPatch(GoalDiffOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("GoalPatch: %+v", result.Error)
+	}
+	if patch.Commits != 2 || !bytes.Contains(patch.Patch, []byte("Subject: [PATCH 1/2] Add a.txt")) {
+		t.Fatalf("expected two patches, got %d:\n%s", patch.Commits, patch.Patch)
+	}
+
+	result, applied := ApplyGoalPatch(ApplyPatchOptions{GoalID: "def5678", Project: "my-api", Patch: patch.Patch, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("ApplyGoalPatch: %+v", result.Error)
+	}
+	if applied.Applied != 2 || applied.Branch != "goal-def5678-port" {
+		t.Errorf("expected two commits applied to goal-def5678-port: %+v", applied)
+	}
+	if log := git(other, "log", "--format=%s", "main..HEAD"); log != "Add b.txt\nAdd a.txt" {
+		t.Errorf("unexpected commits %q", log)
+	}
+
+	// Applying again conflicts and leaves the branch as it was
+	os.WriteFile(filepath.Join(other, "a.txt"), []byte("changed"), 0644)
+	git(other, "commit", "-am", "Change a.txt")
+	head := git(other, "rev-parse", "HEAD")
+	result, _ = ApplyGoalPatch(ApplyPatchOptions{GoalID: "def5678", Project: "my-api", Patch: patch.Patch, VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "patch_conflict" {
+		t.Fatalf("expected patch_conflict, got %+v", result.Error)
+	}
+	if git(other, "rev-parse", "HEAD") != head || git(other, "status", "--porcelain") != "" {
+		t.Error("a conflicting patch should leave the worktree untouched")
+	}
+
+	result, _ = ApplyGoalPatch(ApplyPatchOptions{GoalID: "def5678", Project: "my-api", Patch: []byte("not a patch\n"), VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_patch" {
+		t.Errorf("expected invalid_patch, got %+v", result.Error)
+	}
+}
@@ -0,0 +1,73 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// Plain projects (goals.ProjectTypePlain) are folders without git. A goal's
+// workspace is a symlink to the project folder, workspaces/<project>/
+// goal-<id>-<slug> -> worktree-base, so executors find it like a worktree and
+// edit the files in place. There is no branch to create, merge or delete.
+
+// isPlainProject reports whether project is configured as a plain folder
+func isPlainProject(vegaDir, project string) bool {
+	p, err := goals.ParseProject(vegaDir, project)
+	return err == nil && p.IsPlain()
+}
+
+// plainWorkspaceName returns the workspace directory name of a plain goal
+func plainWorkspaceName(goalID, title string) string {
+	return fmt.Sprintf("goal-%s-%s", goalID, slugify(title))
+}
+
+// linkPlainWorkspace points a goal workspace at the project folder
+func linkPlainWorkspace(projectBase, workspacePath string) error {
+	if _, err := os.Lstat(workspacePath); err == nil {
+		return fmt.Errorf("workspace already exists: %s", workspacePath)
+	}
+	target, err := filepath.Rel(filepath.Dir(workspacePath), projectBase)
+	if err != nil {
+		target = projectBase
+	}
+	return os.Symlink(target, workspacePath)
+}
+
+// unlinkPlainWorkspace removes a goal workspace link, never the project folder
+func unlinkPlainWorkspace(workspacePath string) error {
+	info, err := os.Lstat(workspacePath)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("not a workspace link: %s", workspacePath)
+	}
+	return os.Remove(workspacePath)
+}
+
+// createPlainProjectConfigFile writes projects/<name>.md for a plain project
+func createPlainProjectConfigFile(path, name, localPath string) error {
+	content := fmt.Sprintf(`# Project: %s
+
+## Overview
+
+_Brief description of the project_
+
+## Location
+
+**Type**: `+"`plain`"+`
+**Workspace**: `+"`workspaces/%s/worktree-base/`"+`
+**Upstream**: `+"`%s`"+`
+
+%s
+## Notes for Executors
+
+When working on this project:
+1. Files are edited in place; there is no git branch or merge
+2. Planning files go at the workspace root
+`, name, name, localPath, (&goals.ProjectGoals{}).Markdown())
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestPlainProjectGoal(t *testing.T) {
+	vegaDir := t.TempDir()
+	for _, sub := range []string{"goals/active", "goals/iced", "goals/history", "projects"} {
+		os.MkdirAll(filepath.Join(vegaDir, sub), 0755)
+	}
+	docs := filepath.Join(t.TempDir(), "docs")
+	os.MkdirAll(docs, 0755)
+	os.WriteFile(filepath.Join(docs, "index.md"), []byte("# Docs\n"), 0644)
+
+	if result, _ := AddProjectFromPath(AddProjectOptions{Name: "docs", Path: docs, Type: "svn", VegaDir: vegaDir}); result.Success || result.Error.Code != "invalid_type" {
+		t.Fatalf("expected invalid_type, got %+v", result)
+	}
+	result, added := AddProjectFromPath(AddProjectOptions{Name: "docs", Path: docs, Type: goals.ProjectTypePlain, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("AddProjectFromPath: %+v", result.Error)
+	}
+	if added.BaseBranch != "" {
+		t.Errorf("plain projects have no base branch, got %q", added.BaseBranch)
+	}
+	project, err := goals.ParseProject(vegaDir, "docs")
+	if err != nil || !project.IsPlain() || project.WorkspaceStatus != "ready" {
+		t.Fatalf("expected a ready plain project, got %+v (%v)", project, err)
+	}
+
+	result, created := CreateGoal(CreateOptions{Title: "Write the guide", Project: "docs", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	if created.GoalBranch != "" || created.BaseBranch != "" {
+		t.Errorf("plain goals have no branches: %+v", created)
+	}
+	if _, err := os.Stat(filepath.Join(created.WorktreePath, "index.md")); err != nil {
+		t.Fatalf("goal workspace should show the project folder: %v", err)
+	}
+
+	// Ice and resume drop and restore the workspace link
+	if result, _ := IceGoal(IceOptions{GoalID: created.GoalID, Project: "docs", RemoveWorktree: true, VegaDir: vegaDir}); !result.Success {
+		t.Fatalf("IceGoal: %+v", result.Error)
+	}
+	if _, err := os.Lstat(created.WorktreePath); !os.IsNotExist(err) {
+		t.Error("icing should remove the workspace link")
+	}
+	result, resumed := ResumeGoal(ResumeOptions{GoalID: created.GoalID, Project: "docs", VegaDir: vegaDir})
+	if !result.Success || !resumed.WorktreeCreated || resumed.WorktreePath != created.WorktreePath {
+		t.Fatalf("ResumeGoal: %+v %+v", result.Error, resumed)
+	}
+
+	// Edits land in the project folder; completing skips the merge
+	os.WriteFile(filepath.Join(resumed.WorktreePath, "guide.md"), []byte("# Guide\n"), 0644)
+	result, completed := CompleteGoal(CompleteOptions{GoalID: created.GoalID, Project: "docs", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("CompleteGoal: %+v", result.Error)
+	}
+	if completed.Merged || !completed.WorktreeRemoved || !completed.GoalArchived {
+		t.Errorf("expected archived without merge: %+v", completed)
+	}
+	if _, err := os.Stat(filepath.Join(docs, "guide.md")); err != nil {
+		t.Error("completing should keep the project folder and its edits")
+	}
+
+	if result, cleaned := CleanupGoal(CleanupOptions{GoalID: created.GoalID, Project: "docs", VegaDir: vegaDir}); !result.Success || cleaned.BranchExisted {
+		t.Errorf("cleanup of a plain goal should have nothing to delete: %+v %+v", result.Error, cleaned)
+	}
+}
@@ -0,0 +1,226 @@
+package operations
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// Worktree pools keep prewarmed worktrees per project so creating a goal
+// doesn't wait for a full checkout, sparse setup and hook copying. Pooled
+// worktrees live in workspaces/<project>/.pool/ on a detached HEAD at the base
+// branch and are moved into place and switched to the goal branch when claimed.
+const (
+	DefaultPoolInterval = 2 * time.Minute // How often the maintainer tops up pools
+	maxWorktreePool     = 10              // Upper bound on a project's pool size
+	poolDirName         = ".pool"
+	poolReadyPrefix     = "warm-" // Ready to be claimed
+	poolTempPrefix      = "tmp-"  // Still being created
+)
+
+// WorktreePoolStatus reports a project's pool after maintenance
+type WorktreePoolStatus struct {
+	Project string   `json:"project"`
+	Size    int      `json:"size"`    // Configured pool size
+	Ready   int      `json:"ready"`   // Prewarmed worktrees available
+	Created int      `json:"created"` // Worktrees added in this pass
+	Removed int      `json:"removed"` // Worktrees dropped (pool shrunk or stale)
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// poolDir returns the directory holding a project's prewarmed worktrees
+func poolDir(vegaDir, project string) string {
+	return layout.New(vegaDir).Workspace(project, poolDirName)
+}
+
+// pooledWorktrees lists a project's worktrees with the given prefix, oldest name first
+func pooledWorktrees(vegaDir, project, prefix string) []string {
+	matches, _ := filepath.Glob(filepath.Join(poolDir(vegaDir, project), prefix+"*"))
+	sort.Strings(matches)
+	return matches
+}
+
+// FillWorktreePool tops up a project's pool to its configured size and drops
+// extra or half-created worktrees. Worktrees are built under a temporary name
+// and renamed once ready, so a claim never picks up an unfinished one.
+func FillWorktreePool(vegaDir, project string) (*WorktreePoolStatus, error) {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return nil, fmt.Errorf("project %s not found: %w", project, err)
+	}
+	size := p.WorktreePool
+	if p.IsPlain() || (p.VCS != "" && p.VCS != gitsvc.VCSGit) {
+		size = 0 // Pools hold git worktrees
+	}
+	if size > maxWorktreePool {
+		size = maxWorktreePool
+	}
+	baseBranch := p.BaseBranch
+	if baseBranch == "" {
+		baseBranch = "main"
+	}
+	status := &WorktreePoolStatus{Project: project, Size: size}
+
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
+	lock, err := hub.NewLockManager(vegaDir).AcquireWorktreeBase(project, "worktree-pool")
+	if err != nil {
+		return status, fmt.Errorf("failed to acquire worktree-base lock: %w", err)
+	}
+	defer lock.Release()
+
+	// Leftovers from an interrupted fill
+	for _, dir := range pooledWorktrees(vegaDir, project, poolTempPrefix) {
+		removePooledWorktree(projectBase, dir)
+		status.Removed++
+	}
+
+	ready := pooledWorktrees(vegaDir, project, poolReadyPrefix)
+	for len(ready) > size {
+		removePooledWorktree(projectBase, ready[len(ready)-1])
+		ready = ready[:len(ready)-1]
+		status.Removed++
+	}
+
+	for len(ready) < size {
+		dir, err := prewarmWorktree(vegaDir, projectBase, project, baseBranch, p.CloneOptions)
+		if err != nil {
+			status.Errors = append(status.Errors, err.Error())
+			break
+		}
+		ready = append(ready, dir)
+		status.Created++
+	}
+	status.Ready = len(ready)
+
+	if size == 0 {
+		os.Remove(poolDir(vegaDir, project)) // Only succeeds once empty
+	}
+	return status, nil
+}
+
+// prewarmWorktree creates one ready-to-claim worktree in the project's pool
+func prewarmWorktree(vegaDir, projectBase, project, baseBranch string, clone goals.CloneOptions) (string, error) {
+	if err := os.MkdirAll(poolDir(vegaDir, project), 0755); err != nil {
+		return "", fmt.Errorf("failed to create pool directory: %w", err)
+	}
+	b := make([]byte, 4)
+	rand.Read(b)
+	id := fmt.Sprintf("%d-%x", time.Now().Unix(), b)
+	tmp := filepath.Join(poolDir(vegaDir, project), poolTempPrefix+id)
+	ready := filepath.Join(poolDir(vegaDir, project), poolReadyPrefix+id)
+
+	cmd := exec.Command("git", "-C", projectBase, "worktree", "add", "--detach", tmp, baseBranch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git worktree add: %s", strings.TrimSpace(string(output)))
+	}
+	if err := ApplySparseCheckout(tmp, clone); err != nil {
+		removePooledWorktree(projectBase, tmp)
+		return "", err
+	}
+	CopyHooksToWorktree(vegaDir, tmp)
+
+	cmd = exec.Command("git", "-C", projectBase, "worktree", "move", tmp, ready)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		removePooledWorktree(projectBase, tmp)
+		return "", fmt.Errorf("git worktree move: %s", strings.TrimSpace(string(output)))
+	}
+	return ready, nil
+}
+
+// removePooledWorktree removes a pooled worktree and its registration
+func removePooledWorktree(projectBase, dir string) {
+	if err := exec.Command("git", "-C", projectBase, "worktree", "remove", "--force", dir).Run(); err != nil {
+		os.RemoveAll(dir)
+		exec.Command("git", "-C", projectBase, "worktree", "prune").Run()
+	}
+}
+
+// ClaimPooledWorktree moves a prewarmed worktree to worktreePath and creates
+// branchName there from the current tip of baseBranch. It returns false (and
+// leaves worktreePath untouched) if the pool is empty or the claim failed, in
+// which case the caller creates the worktree normally.
+func ClaimPooledWorktree(vegaDir, project, worktreePath, branchName, baseBranch string) (bool, error) {
+	projectBase := layout.New(vegaDir).WorktreeBase(project)
+
+	// Renaming is atomic, so concurrent claims never get the same worktree
+	claimed := false
+	for _, dir := range pooledWorktrees(vegaDir, project, poolReadyPrefix) {
+		if err := os.Rename(dir, worktreePath); err == nil {
+			claimed = true
+			break
+		}
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	// Point worktree-base at the new location
+	if output, err := exec.Command("git", "-C", projectBase, "worktree", "repair", worktreePath).CombinedOutput(); err != nil {
+		removePooledWorktree(projectBase, worktreePath)
+		return false, fmt.Errorf("git worktree repair: %s", strings.TrimSpace(string(output)))
+	}
+	// The pooled checkout may be behind: start the goal branch at the current base
+	if output, err := exec.Command("git", "-C", worktreePath, "checkout", "-b", branchName, baseBranch).CombinedOutput(); err != nil {
+		removePooledWorktree(projectBase, worktreePath)
+		return false, fmt.Errorf("git checkout: %s", strings.TrimSpace(string(output)))
+	}
+	return true, nil
+}
+
+// MaintainWorktreePools fills the pool of every project that has one configured
+// (or still has pooled worktrees after its pool was disabled)
+func MaintainWorktreePools(vegaDir string) []WorktreePoolStatus {
+	projects, err := ListProjects(vegaDir)
+	if err != nil {
+		return nil
+	}
+	var statuses []WorktreePoolStatus
+	for _, p := range projects {
+		if p.WorktreePool == 0 {
+			if _, err := os.Stat(poolDir(vegaDir, p.Name)); err != nil {
+				continue
+			}
+		}
+		status, err := FillWorktreePool(vegaDir, p.Name)
+		if err != nil {
+			status = &WorktreePoolStatus{Project: p.Name, Errors: []string{err.Error()}}
+		}
+		statuses = append(statuses, *status)
+	}
+	return statuses
+}
+
+// StartWorktreePoolMaintainer tops up worktree pools in the background, once
+// right away and then every interval, until stop is closed
+func StartWorktreePoolMaintainer(vegaDir string, interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, status := range MaintainWorktreePools(vegaDir) {
+				if status.Created > 0 || status.Removed > 0 {
+					log.Printf("[POOL] %s: %d/%d ready (+%d, -%d)", status.Project, status.Ready, status.Size, status.Created, status.Removed)
+				}
+				for _, e := range status.Errors {
+					log.Printf("[POOL] %s: %s", status.Project, e)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePoolConfig(t *testing.T, vegaDir string, size string) {
+	t.Helper()
+	config := "# Project: my-api\n\n**Base Branch**: `main`\n**Worktree Pool**: `" + size + "`\n"
+	if err := os.WriteFile(filepath.Join(vegaDir, "projects", "my-api.md"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorktreePool(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	writePoolConfig(t, vegaDir, "2")
+	os.WriteFile(filepath.Join(vegaDir, "projects", "index.md"), []byte("| Project |\n|---|\n| [my-api](my-api.md) |\n"), 0644)
+
+	status, err := FillWorktreePool(vegaDir, "my-api")
+	if err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+	if status.Ready != 2 || status.Created != 2 || len(status.Errors) != 0 {
+		t.Fatalf("expected 2 prewarmed worktrees: %+v", status)
+	}
+
+	// Base moves on after the pool was filled: the claim starts from the new tip
+	exec.Command("git", "-C", base, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", "Newer").Run()
+	tip, _ := exec.Command("git", "-C", base, "rev-parse", "main").Output()
+
+	worktree := filepath.Join(vegaDir, "workspaces", "my-api", "goal-def5678-new")
+	claimed, err := ClaimPooledWorktree(vegaDir, "my-api", worktree, "goal-def5678-new", "main")
+	if err != nil || !claimed {
+		t.Fatalf("expected claim from pool, got %v, %v", claimed, err)
+	}
+	if branch, _ := getWorktreeBranch(worktree); branch != "goal-def5678-new" {
+		t.Errorf("expected goal branch, got %s", branch)
+	}
+	head, _ := exec.Command("git", "-C", worktree, "rev-parse", "HEAD").Output()
+	if string(head) != string(tip) {
+		t.Errorf("claimed worktree should start at the current base tip")
+	}
+	list, _ := exec.Command("git", "-C", base, "worktree", "list").Output()
+	if !strings.Contains(string(list), worktree) {
+		t.Errorf("claimed worktree should be registered at its new path:\n%s", list)
+	}
+	if n := len(pooledWorktrees(vegaDir, "my-api", poolReadyPrefix)); n != 1 {
+		t.Errorf("expected 1 worktree left in pool, got %d", n)
+	}
+
+	// The maintainer tops the pool up again
+	statuses := MaintainWorktreePools(vegaDir)
+	if len(statuses) != 1 || statuses[0].Ready != 2 || statuses[0].Created != 1 {
+		t.Errorf("expected pool refilled: %+v", statuses)
+	}
+
+	// Disabling the pool drains it
+	writePoolConfig(t, vegaDir, "0")
+	statuses = MaintainWorktreePools(vegaDir)
+	if len(statuses) != 1 || statuses[0].Ready != 0 || statuses[0].Removed != 2 {
+		t.Errorf("expected pool drained: %+v", statuses)
+	}
+	if _, err := os.Stat(poolDir(vegaDir, "my-api")); !os.IsNotExist(err) {
+		t.Error("empty pool directory should be removed")
+	}
+
+	claimed, err = ClaimPooledWorktree(vegaDir, "my-api", filepath.Join(vegaDir, "workspaces", "my-api", "goal-fff0000-x"), "goal-fff0000-x", "main")
+	if claimed || err != nil {
+		t.Errorf("empty pool should not be claimed: %v, %v", claimed, err)
+	}
+}
@@ -0,0 +1,108 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestSyncProjectGoals(t *testing.T) {
+	vegaDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vegaDir, "projects"), 0755)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "aaa1111.md"), []byte("# Goal #aaa1111: Add login\n"), 0644)
+
+	// A hand-maintained config that drifted from the registry
+	config := `# Project: web
+
+## Overview
+
+The web app.
+
+## Active Goals
+
+- fff0000: Long gone
+
+## Iced Goals
+
+| ID | Title | Reason |
+|----|-------|--------|
+
+## Completed Goals
+
+| ID | Title | Completed |
+|----|-------|-----------|
+| | | |
+
+## Project Context
+
+Notes.
+`
+	configPath := filepath.Join(vegaDir, "projects", "web.md")
+	os.WriteFile(configPath, []byte(config), 0644)
+
+	registry := goals.NewRegistry(vegaDir)
+	for _, e := range []goals.RegistryEntry{
+		{ID: "aaa1111", Title: "Add login", Projects: []string{"web"}, Status: "active"},
+		{ID: "bbb2222", Title: "Dark | light", Projects: []string{"web"}, Status: "iced", Reason: "Later"},
+		{ID: "ccc3333", Title: "Old", Projects: []string{"web"}, Status: "completed", CompletedAt: "2026-01-02"},
+		{ID: "ddd4444", Title: "Newer", Projects: []string{"web", "api"}, Status: "completed", CompletedAt: "2026-02-03"},
+		{ID: "eee5555", Title: "Other project", Projects: []string{"api"}, Status: "active"},
+		{ID: "fff6666", Title: "Someday", Projects: []string{"web"}, Status: goals.StatusDraft},
+	} {
+		if err := registry.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	view, err := goals.LoadProjectGoals(vegaDir, "web")
+	if err != nil {
+		t.Fatalf("LoadProjectGoals failed: %v", err)
+	}
+	if len(view.Active) != 1 || view.Active[0].File != "goals/active/aaa1111.md" {
+		t.Errorf("unexpected active goals: %+v", view.Active)
+	}
+	if len(view.Iced) != 1 || len(view.Drafts) != 1 {
+		t.Errorf("expected one iced goal and one draft, got %+v", view)
+	}
+	if len(view.Completed) != 2 || view.Completed[0].ID != "ddd4444" {
+		t.Errorf("expected completed goals newest first, got %+v", view.Completed)
+	}
+
+	SyncProjectGoals(vegaDir, "web", "api")
+	data, _ := os.ReadFile(configPath)
+	got := string(data)
+	for _, want := range []string{
+		"## Overview\n\nThe web app.\n\n<!-- vega-hub:goals",
+		"- aaa1111: Add login ([goal](../goals/active/aaa1111.md))",
+		`| bbb2222 | Dark \| light | Later |`,
+		"| ddd4444 | Newer | 2026-02-03 |\n| ccc3333 | Old | 2026-01-02 |",
+		"<!-- /vega-hub:goals -->\n\n## Project Context\n\nNotes.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in synced config:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"fff0000", "Other project", "Someday", "| | | |"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("unexpected %q in synced config:\n%s", unwanted, got)
+		}
+	}
+	if strings.Count(got, "## Active Goals") != 1 {
+		t.Errorf("expected a single goal list:\n%s", got)
+	}
+
+	// Later syncs replace the generated block in place
+	registry.Update("aaa1111", func(e *goals.RegistryEntry) { e.Title = "Add SSO login" })
+	SyncProjectGoals(vegaDir, "web")
+	data, _ = os.ReadFile(configPath)
+	if !strings.Contains(string(data), "- aaa1111: Add SSO login") || strings.Count(string(data), "<!-- vega-hub:goals") != 1 {
+		t.Errorf("resync did not replace the block:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(vegaDir, "projects", "api.md")); !os.IsNotExist(err) {
+		t.Error("sync should skip projects without a config")
+	}
+}
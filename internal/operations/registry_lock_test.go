@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
 )
 
 // TestConcurrentGoalCreation tests that two concurrent goal creates both succeed
@@ -31,29 +33,6 @@ func TestConcurrentGoalCreation(t *testing.T) {
 		}
 	}
 
-	// Create REGISTRY.md with Active Goals table
-	registryContent := `# Goal Registry
-
-## Active Goals
-
-| ID | Title | Project(s) | Status | Phase |
-|----|-------|------------|--------|-------|
-
-## Iced Goals
-
-| ID | Title | Project(s) | Reason |
-|----|-------|------------|--------|
-
-## Completed Goals
-
-| ID | Title | Project(s) | Completed |
-|----|-------|------------|-----------|
-`
-	registryPath := filepath.Join(tmpDir, "goals", "REGISTRY.md")
-	if err := os.WriteFile(registryPath, []byte(registryContent), 0644); err != nil {
-		t.Fatalf("Failed to create registry: %v", err)
-	}
-
 	// Create project config
 	projectContent := `# Project: test-project
 
@@ -137,38 +116,20 @@ Test project
 		}
 	}
 
-	// Verify both goals are in the registry
-	content, err := os.ReadFile(registryPath)
-	if err != nil {
-		t.Fatalf("Failed to read registry: %v", err)
-	}
-
-	registryStr := string(content)
+	// Verify both goals are in the registry, pending their worktrees
+	registry := goals.NewRegistry(tmpDir)
+	states := goals.NewStateManager(tmpDir)
 	for i, cr := range createResults {
 		if cr == nil {
 			continue
 		}
-		if !containsGoalID(registryStr, cr.GoalID) {
-			t.Errorf("Goal %d (ID: %s) not found in registry", i, cr.GoalID)
+		if _, err := registry.Get(cr.GoalID); err != nil {
+			t.Errorf("Goal %d (ID: %s) not found in registry: %v", i, cr.GoalID, err)
 		}
-	}
-
-	t.Logf("Registry content:\n%s", registryStr)
-}
-
-func containsGoalID(content, goalID string) bool {
-	return len(goalID) > 0 && len(content) > 0 && 
-		(len(goalID) <= len(content)) && 
-		(indexString(content, goalID) >= 0)
-}
-
-func indexString(s, substr string) int {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
+		if state, _ := states.GetState(cr.GoalID); state != goals.StatePending {
+			t.Errorf("Goal %d (ID: %s) state = %s, want pending", i, cr.GoalID, state)
 		}
 	}
-	return -1
 }
 
 func runCommand(name string, args ...string) error {
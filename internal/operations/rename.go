@@ -0,0 +1,256 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// RenameOptions contains options for renaming a goal
+type RenameOptions struct {
+	GoalID       string
+	Title        string
+	RenameBranch bool // Rename the goal branch to match the new title
+	MoveWorktree bool // Move the worktree directory to match the new title
+	VegaDir      string
+}
+
+// RenameResult contains the result of renaming a goal
+type RenameResult struct {
+	GoalID        string `json:"goal_id"`
+	OldTitle      string `json:"old_title"`
+	Title         string `json:"title"`
+	Project       string `json:"project,omitempty"`
+	OldBranch     string `json:"old_branch,omitempty"`
+	Branch        string `json:"branch,omitempty"`
+	OldWorktree   string `json:"old_worktree,omitempty"`
+	Worktree      string `json:"worktree,omitempty"`
+	BranchRenamed bool   `json:"branch_renamed"`
+	WorktreeMoved bool   `json:"worktree_moved"`
+}
+
+// RenameGoal changes a goal's title in its goal file, the registry and the
+// project configs. With RenameBranch and MoveWorktree the branch and the
+// worktree directory get the names a goal created with the new title would
+// have (the branch keeps its tracker ID prefix), and the Worktree section of
+// the goal file follows. Remote branches are left alone.
+func RenameGoal(opts RenameOptions) (*Result, *RenameResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID)); errResult != nil {
+		return errResult, nil
+	}
+	title := strings.TrimSpace(opts.Title)
+	if title == "" || strings.ContainsAny(title, "\r\n") {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_input",
+				Message: "Title must be a single non-empty line",
+				Details: map[string]string{"field": "title", "value": opts.Title},
+			},
+		}, nil
+	}
+
+	parser := goals.NewParser(opts.VegaDir)
+	goalFile, _ := parser.GoalFile(opts.GoalID)
+	registry := goals.NewRegistry(opts.VegaDir)
+	entry, err := registry.Get(opts.GoalID)
+	if goalFile == "" || err != nil {
+		return goalNotFoundResult(opts.GoalID), nil
+	}
+
+	result := &RenameResult{
+		GoalID:   opts.GoalID,
+		OldTitle: getGoalTitle(goalFile, opts.GoalID),
+		Title:    title,
+	}
+	var recorded *goals.WorktreeInfo
+	if detail, err := parser.ParseGoalDetail(opts.GoalID); err == nil {
+		recorded = detail.Worktree
+	}
+	if recorded != nil && recorded.Project != "" {
+		result.Project = recorded.Project
+	} else if len(entry.Projects) > 0 {
+		result.Project = entry.Projects[0]
+	}
+
+	if (opts.RenameBranch || opts.MoveWorktree) && result.Project != "" {
+		if errResult := renameGoalWorkspace(opts, entry, recorded, result); errResult != nil {
+			return errResult, result
+		}
+	}
+
+	if err := setGoalTitle(goalFile, opts.GoalID, title); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "file_write_failed",
+				Message: "Could not update the goal file",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, result
+	}
+	if result.BranchRenamed {
+		goals.SetWorktreeField(goalFile, "Branch", result.Branch)
+	}
+	if result.WorktreeMoved {
+		goals.SetWorktreeField(goalFile, "Path", fmt.Sprintf("workspaces/%s/%s", result.Project, filepath.Base(result.Worktree)))
+	}
+
+	lockMgr := hub.NewLockManager(opts.VegaDir)
+	if err := lockMgr.WithRegistryLock("rename-goal", func() error {
+		return registry.Update(opts.GoalID, func(e *goals.RegistryEntry) {
+			e.Title = title
+			e.UpdatedAt = time.Now().Format(time.RFC3339)
+		})
+	}); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, result
+	}
+
+	SyncProjectGoals(opts.VegaDir, entry.Projects...)
+	return &Result{Success: true}, result
+}
+
+// renameGoalWorkspace renames the goal branch and moves the worktree as
+// requested, filling in result. A failed move undoes the branch rename.
+// Goals without a worktree (drafts, completed goals) only get their branch
+// renamed.
+func renameGoalWorkspace(opts RenameOptions, entry *goals.RegistryEntry, recorded *goals.WorktreeInfo, result *RenameResult) *Result {
+	projectBase := layout.New(opts.VegaDir).WorktreeBase(result.Project)
+	worktree, _ := FindGoalWorktree(opts.VegaDir, result.Project, opts.GoalID)
+	result.OldWorktree, result.Worktree = worktree, worktree
+
+	if isPlainProject(opts.VegaDir, result.Project) {
+		if !opts.MoveWorktree || worktree == "" {
+			return nil
+		}
+		newPath := filepath.Join(filepath.Dir(worktree), plainWorkspaceName(opts.GoalID, result.Title))
+		if newPath == worktree {
+			return nil
+		}
+		if _, err := os.Lstat(newPath); err == nil {
+			return worktreeExistsResult(newPath)
+		}
+		if err := os.Rename(worktree, newPath); err != nil {
+			return worktreeMoveResult(err)
+		}
+		result.Worktree, result.WorktreeMoved = newPath, true
+		return nil
+	}
+
+	backend, err := ProjectBackend(opts.VegaDir, result.Project)
+	if err != nil {
+		return vcsResult(result.Project, err)
+	}
+
+	if recorded != nil {
+		result.OldBranch = recorded.Branch
+	}
+	if result.OldBranch == "" && worktree != "" {
+		result.OldBranch = worktreeBranch(worktree)
+	}
+	if result.OldBranch == "" || result.OldBranch == "HEAD" {
+		result.OldBranch, _ = findGoalBranch(projectBase, opts.GoalID)
+	}
+	result.Branch = result.OldBranch
+	newBranch := goals.IssueBranch(entry.TrackerID, fmt.Sprintf("goal-%s-%s", opts.GoalID, slugify(result.Title)))
+
+	var newPath string
+	if opts.MoveWorktree && worktree != "" {
+		newPath = layout.New(opts.VegaDir).Workspace(result.Project, goalWorkspaceName(newBranch))
+		if newPath == worktree {
+			newPath = ""
+		} else if _, err := os.Lstat(newPath); err == nil {
+			return worktreeExistsResult(newPath)
+		}
+	}
+
+	if opts.RenameBranch && result.OldBranch != "" && result.OldBranch != newBranch {
+		if err := backend.RenameBranch(projectBase, result.OldBranch, newBranch); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "branch_rename_failed",
+					Message: fmt.Sprintf("Could not rename branch %s to %s", result.OldBranch, newBranch),
+					Details: map[string]string{"error": err.Error()},
+				},
+			}
+		}
+		result.Branch, result.BranchRenamed = newBranch, true
+	}
+
+	if newPath != "" {
+		if err := backend.MoveWorkspace(projectBase, worktree, newPath); err != nil {
+			if result.BranchRenamed {
+				backend.RenameBranch(projectBase, newBranch, result.OldBranch)
+				result.Branch, result.BranchRenamed = result.OldBranch, false
+			}
+			if errors.Is(err, gitsvc.ErrUnsupported) {
+				return &Result{
+					Success: false,
+					Error: &ErrorInfo{
+						Code:    "rename_unsupported",
+						Message: fmt.Sprintf("Project '%s' can't move workspaces (vcs: %s)", result.Project, backend.Name()),
+						Details: map[string]string{"project": result.Project},
+					},
+				}
+			}
+			return worktreeMoveResult(err)
+		}
+		result.Worktree, result.WorktreeMoved = newPath, true
+	}
+	return nil
+}
+
+func worktreeExistsResult(path string) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "worktree_exists",
+			Message: fmt.Sprintf("Cannot move the worktree: %s already exists", path),
+			Details: map[string]string{"path": path},
+		},
+	}
+}
+
+func worktreeMoveResult(err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "worktree_move_failed",
+			Message: "Could not move the worktree",
+			Details: map[string]string{"error": err.Error()},
+		},
+	}
+}
+
+// setGoalTitle rewrites the "# Goal <id>: <title>" heading of a goal file
+func setGoalTitle(goalFile, goalID, title string) error {
+	content, err := os.ReadFile(goalFile)
+	if err != nil {
+		return err
+	}
+	heading := regexp.MustCompile(fmt.Sprintf(`(?m)^# Goal (#?%s): .*$`, regexp.QuoteMeta(goalID)))
+	loc := heading.FindSubmatchIndex(content)
+	if loc == nil {
+		return fmt.Errorf("no '# Goal %s:' heading in %s", goalID, goalFile)
+	}
+	line := fmt.Sprintf("# Goal %s: %s", content[loc[2]:loc[3]], title)
+	updated := append(append(append([]byte{}, content[:loc[0]]...), line...), content[loc[1]:]...)
+	return os.WriteFile(goalFile, updated, 0644)
+}
@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestRenameGoal(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "\n## Active Goals\n- abc1234: Fix login\n")
+	goalFile := filepath.Join(vegaDir, "goals", "active", "abc1234.md")
+	os.WriteFile(goalFile, []byte("# Goal #abc1234: Fix login\n\n## Worktree\n- **Branch**: goal-abc1234-fix\n- **Project**: my-api\n- **Path**: workspaces/my-api/goal-abc1234-fix\n"), 0644)
+	if err := goals.NewRegistry(vegaDir).Add(goals.RegistryEntry{ID: "abc1234", Title: "Fix login", Projects: []string{"my-api"}, Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := RenameGoal(RenameOptions{GoalID: "abc1234", Title: " ", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_input" {
+		t.Fatalf("expected invalid_input for a blank title, got %+v", result)
+	}
+
+	result, data := RenameGoal(RenameOptions{
+		GoalID:       "abc1234",
+		Title:        "Fix OAuth login",
+		RenameBranch: true,
+		MoveWorktree: true,
+		VegaDir:      vegaDir,
+	})
+	if !result.Success {
+		t.Fatalf("rename failed: %+v", result.Error)
+	}
+	if data.OldTitle != "Fix login" || !data.BranchRenamed || !data.WorktreeMoved || data.Branch != "goal-abc1234-fix-oauth-login" {
+		t.Fatalf("unexpected result: %+v", data)
+	}
+
+	worktree := filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix-oauth-login")
+	if data.Worktree != worktree {
+		t.Errorf("worktree = %s, want %s", data.Worktree, worktree)
+	}
+	out, err := exec.Command("git", "-C", worktree, "branch", "--show-current").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "goal-abc1234-fix-oauth-login" {
+		t.Errorf("moved worktree is on %q (%v)", out, err)
+	}
+
+	content, _ := os.ReadFile(goalFile)
+	for _, want := range []string{
+		"# Goal #abc1234: Fix OAuth login\n",
+		"- **Branch**: goal-abc1234-fix-oauth-login\n",
+		"- **Path**: workspaces/my-api/goal-abc1234-fix-oauth-login\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("goal file missing %q:\n%s", want, content)
+		}
+	}
+	config, _ := os.ReadFile(filepath.Join(vegaDir, "projects", "my-api.md"))
+	if !strings.Contains(string(config), "- abc1234: Fix OAuth login") {
+		t.Errorf("project config not updated:\n%s", config)
+	}
+	if entry, _ := goals.NewRegistry(vegaDir).Get("abc1234"); entry == nil || entry.Title != "Fix OAuth login" {
+		t.Errorf("registry not updated: %+v", entry)
+	}
+
+	// Found again by the recorded branch, and already consistent
+	result, data = RenameGoal(RenameOptions{GoalID: "abc1234", Title: "Fix OAuth login", RenameBranch: true, MoveWorktree: true, VegaDir: vegaDir})
+	if !result.Success || data.BranchRenamed || data.WorktreeMoved {
+		t.Fatalf("expected a no-op rename, got %+v %+v", result.Error, data)
+	}
+}
@@ -0,0 +1,214 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// RepairProjectOptions contains options for repairing a project workspace
+type RepairProjectOptions struct {
+	Name    string
+	URL     string // Clone source if the project config doesn't record one
+	VegaDir string
+}
+
+// RepairProjectResult reports what RepairProject found and fixed
+type RepairProjectResult struct {
+	Name         string           `json:"name"`
+	WorktreeBase string           `json:"worktree_base"`
+	Recloned     bool             `json:"recloned"`
+	MovedAside   string           `json:"moved_aside,omitempty"` // Where the broken worktree-base was kept
+	Fetched      bool             `json:"fetched"`
+	Worktrees    []WorktreeRepair `json:"worktrees,omitempty"`
+	Pruned       bool             `json:"pruned"`
+	Fixed        []string         `json:"fixed"`
+	Problems     []string         `json:"problems,omitempty"` // Issues that need manual attention
+}
+
+// WorktreeRepair is the repair outcome for one goal worktree
+type WorktreeRepair struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // "ok", "repaired", "failed"
+	Error  string `json:"error,omitempty"`
+}
+
+// RepairProject re-syncs a project's worktree-base: it re-clones it if it's
+// missing or its .git is broken (keeping the broken copy aside), fetches it
+// otherwise, and re-registers existing goal worktrees with git worktree repair.
+func RepairProject(opts RepairProjectOptions) (*Result, *RepairProjectResult) {
+	if errResult := checkInputs(idInput("project", opts.Name)); errResult != nil {
+		return errResult, nil
+	}
+	project, err := goals.ParseProject(opts.VegaDir, opts.Name)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "project_not_found",
+				Message: fmt.Sprintf("Project '%s' not found", opts.Name),
+				Details: map[string]string{"project": opts.Name},
+			},
+		}, nil
+	}
+
+	workspaceDir := layout.New(opts.VegaDir).ProjectWorkspace(opts.Name)
+	worktreeBase := filepath.Join(workspaceDir, layout.WorktreeBaseName)
+	result := &RepairProjectResult{Name: opts.Name, WorktreeBase: worktreeBase, Fixed: []string{}}
+
+	lock, err := hub.NewLockManager(opts.VegaDir).AcquireWorktreeBase(opts.Name, "project-repair")
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "lock_failed",
+				Message: "Failed to acquire worktree-base lock",
+				Details: map[string]string{"project": opts.Name, "error": err.Error()},
+			},
+		}, nil
+	}
+	defer lock.Release()
+
+	// Step 1: Make sure worktree-base is a usable git repository
+	if info, err := os.Lstat(worktreeBase); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		// Linked local repository: never re-clone over the user's checkout
+		target, _ := os.Readlink(worktreeBase)
+		if !isGitRepo(worktreeBase) {
+			result.Problems = append(result.Problems, fmt.Sprintf("linked repository %s is missing or not a git repository", target))
+			return &Result{Success: true}, result
+		}
+	} else if !isGitRepo(worktreeBase) {
+		source := repairCloneSource(project, opts.URL, worktreeBase)
+		if source == "" {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "no_upstream",
+					Message: "worktree-base is broken and no upstream URL is known; pass url to re-clone",
+					Details: map[string]string{"worktree_base": worktreeBase},
+				},
+			}, nil
+		}
+
+		if _, err := os.Stat(worktreeBase); err == nil {
+			aside := fmt.Sprintf("%s.broken-%s", worktreeBase, time.Now().Format("20060102-150405"))
+			if err := os.Rename(worktreeBase, aside); err != nil {
+				return &Result{
+					Success: false,
+					Error: &ErrorInfo{
+						Code:    "move_failed",
+						Message: "Failed to move broken worktree-base aside",
+						Details: map[string]string{"error": err.Error()},
+					},
+				}, nil
+			}
+			result.MovedAside = aside
+			result.Fixed = append(result.Fixed, "moved broken worktree-base to "+aside)
+		}
+
+		os.MkdirAll(workspaceDir, 0755)
+		if output, err := cloneProject(source, worktreeBase, project.CloneOptions); err != nil {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "clone_failed",
+					Message: "Failed to re-clone repository",
+					Details: map[string]string{"url": source, "error": err.Error(), "output": string(output)},
+				},
+			}, result
+		}
+		result.Recloned = true
+		result.Fixed = append(result.Fixed, "re-cloned worktree-base from "+source)
+
+		if project.BaseBranch != "" {
+			if output, err := exec.Command("git", "-C", worktreeBase, "checkout", project.BaseBranch).CombinedOutput(); err != nil {
+				result.Problems = append(result.Problems, fmt.Sprintf("checkout %s: %s", project.BaseBranch, strings.TrimSpace(string(output))))
+			}
+		}
+	}
+
+	// Step 2: Fetch so worktree-base knows about the latest remote branches
+	if !result.Recloned {
+		if err := exec.Command("git", "-C", worktreeBase, "remote", "get-url", "origin").Run(); err == nil {
+			if output, err := exec.Command("git", "-C", worktreeBase, "fetch", "origin", "--prune").CombinedOutput(); err != nil {
+				result.Problems = append(result.Problems, "fetch failed: "+strings.TrimSpace(string(output)))
+			} else {
+				result.Fetched = true
+				result.Fixed = append(result.Fixed, "fetched origin")
+			}
+		}
+	}
+
+	// Step 3: Re-register goal worktrees
+	matches, _ := filepath.Glob(filepath.Join(workspaceDir, "goal-*"))
+	for _, wt := range matches {
+		if info, err := os.Stat(wt); err != nil || !info.IsDir() {
+			continue
+		}
+		repair := WorktreeRepair{Path: wt, Status: "ok"}
+		// Fixes links in both directions; git reports each fix as "repair: ..."
+		output, err := exec.Command("git", "-C", worktreeBase, "worktree", "repair", wt).CombinedOutput()
+		switch {
+		case err != nil || !isGitRepo(wt):
+			repair.Status = "failed"
+			repair.Error = strings.TrimSpace(string(output))
+			if repair.Error == "" {
+				repair.Error = "worktree is not registered with worktree-base"
+			}
+			result.Problems = append(result.Problems, fmt.Sprintf("worktree %s could not be repaired; recreate it from its branch", filepath.Base(wt)))
+		case strings.Contains(string(output), "repair:"):
+			repair.Status = "repaired"
+			result.Fixed = append(result.Fixed, "re-registered worktree "+filepath.Base(wt))
+		}
+		result.Worktrees = append(result.Worktrees, repair)
+	}
+
+	// Drop registrations for worktrees that no longer exist
+	if err := exec.Command("git", "-C", worktreeBase, "worktree", "prune").Run(); err == nil {
+		result.Pruned = true
+	}
+
+	return &Result{Success: true}, result
+}
+
+// repairCloneSource picks the URL to re-clone a project from
+func repairCloneSource(project *goals.Project, url, worktreeBase string) string {
+	if url != "" {
+		return url
+	}
+	// Upstream may be a local path (or worktree-base itself for cloned projects)
+	if upstream := project.Upstream; upstream != "" && filepath.Clean(upstream) != filepath.Clean(worktreeBase) {
+		local := filepath.IsAbs(upstream) || strings.HasPrefix(upstream, "~")
+		if !local || isGitRepo(upstream) {
+			return upstream
+		}
+	}
+	// The broken checkout may still have its remote configured
+	output, err := exec.Command("git", "config", "--file", filepath.Join(worktreeBase, ".git", "config"), "remote.origin.url").Output()
+	if err == nil {
+		return strings.TrimSpace(string(output))
+	}
+	return ""
+}
+
+// isGitRepo checks that dir is the top level of a git checkout (not just inside
+// one, since the vega-missile directory is usually a repository itself)
+func isGitRepo(dir string) bool {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return false
+	}
+	top, err := filepath.EvalSymlinks(strings.TrimSpace(string(output)))
+	if err != nil {
+		return false
+	}
+	real, err := filepath.EvalSymlinks(dir)
+	return err == nil && top == real
+}
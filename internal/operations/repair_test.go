@@ -0,0 +1,94 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupRepairProject clones an upstream repo into worktree-base with one goal worktree
+func setupRepairProject(t *testing.T) (vegaDir, worktree string) {
+	t.Helper()
+	vegaDir = t.TempDir()
+	upstream := filepath.Join(t.TempDir(), "upstream")
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-b", "main", upstream)
+	git("-C", upstream, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", "Initial")
+
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	worktree = filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")
+	git("clone", upstream, base)
+	git("-C", base, "worktree", "add", worktree, "-b", "goal-abc1234-fix")
+
+	os.MkdirAll(filepath.Join(vegaDir, "projects"), 0755)
+	config := "# Project: my-api\n\n**Upstream**: `" + upstream + "`\n**Base Branch**: `main`\n"
+	os.WriteFile(filepath.Join(vegaDir, "projects", "my-api.md"), []byte(config), 0644)
+	return vegaDir, worktree
+}
+
+func TestRepairProjectMovedWorktree(t *testing.T) {
+	vegaDir, worktree := setupRepairProject(t)
+
+	// Moving a worktree leaves worktree-base pointing at the old path
+	moved := filepath.Join(filepath.Dir(worktree), "goal-abc1234-moved")
+	os.Rename(worktree, moved)
+
+	result, data := RepairProject(RepairProjectOptions{Name: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("RepairProject failed: %+v", result.Error)
+	}
+	if data.Recloned || !data.Fetched {
+		t.Errorf("healthy worktree-base should only be fetched: %+v", data)
+	}
+	if len(data.Worktrees) != 1 || data.Worktrees[0].Status != "repaired" {
+		t.Fatalf("expected worktree to be repaired: %+v", data.Worktrees)
+	}
+
+	out, _ := exec.Command("git", "-C", filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base"), "worktree", "list").Output()
+	if !strings.Contains(string(out), moved) {
+		t.Errorf("expected %s registered, got:\n%s", moved, out)
+	}
+
+	// Nothing left to fix on a second run
+	_, data = RepairProject(RepairProjectOptions{Name: "my-api", VegaDir: vegaDir})
+	if data.Worktrees[0].Status != "ok" || len(data.Problems) != 0 {
+		t.Errorf("expected clean second run: %+v", data)
+	}
+}
+
+func TestRepairProjectBrokenGit(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	os.RemoveAll(filepath.Join(base, ".git"))
+
+	result, data := RepairProject(RepairProjectOptions{Name: "my-api", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("RepairProject failed: %+v", result.Error)
+	}
+	if !data.Recloned || data.MovedAside == "" {
+		t.Errorf("expected re-clone with broken copy kept aside: %+v", data)
+	}
+	if !isGitRepo(base) {
+		t.Error("worktree-base should be a git repository again")
+	}
+	if _, err := os.Stat(data.MovedAside); err != nil {
+		t.Errorf("broken copy missing: %v", err)
+	}
+	// The old worktree's registration was lost with .git
+	if len(data.Worktrees) != 1 || data.Worktrees[0].Status != "failed" || len(data.Problems) == 0 {
+		t.Errorf("expected unrepairable worktree to be reported: %+v", data)
+	}
+
+	result, _ = RepairProject(RepairProjectOptions{Name: "missing", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "project_not_found" {
+		t.Errorf("expected project_not_found, got %+v", result)
+	}
+}
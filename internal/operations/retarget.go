@@ -0,0 +1,168 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/layout"
+)
+
+// RetargetOptions contains options for changing a goal's base branch
+type RetargetOptions struct {
+	GoalID     string
+	Project    string // Defaults to the first goal project with a worktree
+	BaseBranch string // The new base branch
+	Strategy   string // goals.MergeStrategyRebase (default) or goals.MergeStrategyMerge
+	VegaDir    string
+}
+
+// RetargetResult contains the result of changing a goal's base branch
+type RetargetResult struct {
+	GoalID        string `json:"goal_id"`
+	Project       string `json:"project"`
+	Branch        string `json:"branch"`
+	OldBaseBranch string `json:"old_base_branch"`
+	BaseBranch    string `json:"base_branch"`
+	Strategy      string `json:"strategy,omitempty"` // Empty when the base didn't change
+}
+
+// RetargetGoal moves a goal to another base branch: the goal branch is
+// rebased onto it (only the goal's own commits move) or it is merged into the
+// goal branch, and the goal file records it as the Base Branch that diffs,
+// branch info, and completion use from then on. The worktree must be clean;
+// a conflict leaves the branch and the recorded base as they were.
+func RetargetGoal(opts RetargetOptions) (*Result, *RetargetResult) {
+	if errResult := checkInputs(
+		idInput("goal ID", opts.GoalID),
+		idInput("project", opts.Project),
+		refInput("base branch", opts.BaseBranch),
+	); errResult != nil {
+		return errResult, nil
+	}
+	if opts.BaseBranch == "" {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_input",
+				Message: "A base branch is required",
+				Details: map[string]string{"field": "base_branch"},
+			},
+		}, nil
+	}
+	switch opts.Strategy {
+	case "":
+		opts.Strategy = goals.MergeStrategyRebase
+	case goals.MergeStrategyRebase, goals.MergeStrategyMerge:
+	default:
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_input",
+				Message: fmt.Sprintf("Unknown strategy '%s' (use %s or %s)", opts.Strategy, goals.MergeStrategyRebase, goals.MergeStrategyMerge),
+				Details: map[string]string{"field": "strategy", "value": opts.Strategy},
+			},
+		}, nil
+	}
+
+	project, worktree, errResult := resolveGoalWorktree(GoalDiffOptions{GoalID: opts.GoalID, Project: opts.Project, VegaDir: opts.VegaDir})
+	if errResult != nil {
+		return errResult, nil
+	}
+	if project.IsPlain() {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "plain_project",
+				Message: fmt.Sprintf("Project '%s' is a plain folder; its goals have no base branch", project.Name),
+				Details: map[string]string{"project": project.Name},
+			},
+		}, nil
+	}
+	backend, err := ProjectBackend(opts.VegaDir, project.Name)
+	if err != nil {
+		return vcsResult(project.Name, err), nil
+	}
+
+	oldBase, err := GoalBaseBranch(opts.VegaDir, opts.GoalID, project.Name)
+	if err != nil {
+		oldBase = "main"
+	}
+	branch, _ := getWorktreeBranch(worktree)
+	result := &RetargetResult{
+		GoalID:        opts.GoalID,
+		Project:       project.Name,
+		Branch:        branch,
+		OldBaseBranch: oldBase,
+		BaseBranch:    opts.BaseBranch,
+	}
+	if oldBase == opts.BaseBranch {
+		return &Result{Success: true}, result
+	}
+
+	projectBase := layout.New(opts.VegaDir).WorktreeBase(project.Name)
+	if err := SyncBaseBranch(opts.VegaDir, project.Name, opts.BaseBranch, "goal-retarget-"+opts.GoalID); err != nil {
+		return baseSyncResult(project.Name, err), nil
+	}
+	if err := exec.Command("git", "-C", projectBase, "rev-parse", "--verify", "--quiet", "refs/heads/"+opts.BaseBranch).Run(); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "branch_not_found",
+				Message: fmt.Sprintf("Branch '%s' not found in project '%s'", opts.BaseBranch, project.Name),
+				Details: map[string]string{"project": project.Name, "branch": opts.BaseBranch},
+			},
+		}, nil
+	}
+	if err := checkWorktreeClean(backend, worktree); err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "uncommitted_changes",
+				Message: "Worktree has uncommitted changes",
+				Details: map[string]string{"worktree": worktree, "details": err.Error()},
+			},
+		}, nil
+	}
+
+	if err := backend.Retarget(projectBase, worktree, branch, oldBase, opts.BaseBranch, opts.Strategy); err != nil {
+		if errors.Is(err, gitsvc.ErrUnsupported) {
+			return &Result{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    "retarget_unsupported",
+					Message: fmt.Sprintf("Project '%s' can't retarget with %s (vcs: %s)", project.Name, opts.Strategy, backend.Name()),
+					Details: map[string]string{"project": project.Name, "strategy": opts.Strategy},
+				},
+			}, nil
+		}
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "retarget_failed",
+				Message: fmt.Sprintf("Could not %s %s onto %s; the branch is unchanged", opts.Strategy, branch, opts.BaseBranch),
+				Details: map[string]string{"branch": branch, "base_branch": opts.BaseBranch, "strategy": opts.Strategy, "error": err.Error()},
+			},
+		}, nil
+	}
+	result.Strategy = opts.Strategy
+
+	if goalFile, _ := goals.NewParser(opts.VegaDir).GoalFile(opts.GoalID); goalFile != "" {
+		goals.SetWorktreeField(goalFile, "Base Branch", opts.BaseBranch)
+	}
+	return &Result{Success: true}, result
+}
+
+// GoalBaseBranch returns the base branch recorded in the goal file's Worktree
+// section for project, or the project's base branch for goals without one
+func GoalBaseBranch(vegaDir, goalID, project string) (string, error) {
+	if detail, err := goals.NewParser(vegaDir).ParseGoalDetail(goalID); err == nil && detail.Worktree != nil {
+		wt := detail.Worktree
+		if wt.BaseBranch != "" && (wt.Project == "" || wt.Project == project) {
+			return wt.BaseBranch, nil
+		}
+	}
+	return getProjectBaseBranch(vegaDir, project)
+}
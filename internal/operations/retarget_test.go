@@ -0,0 +1,64 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRetargetGoal(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	base := filepath.Join(vegaDir, "workspaces", "my-api", "worktree-base")
+	worktree := filepath.Join(vegaDir, "workspaces", "my-api", "goal-abc1234-fix")
+	goalFile := filepath.Join(vegaDir, "goals", "active", "abc1234.md")
+	os.WriteFile(goalFile, []byte("# Goal #abc1234: Fix login\n\n## Worktree\n- **Branch**: goal-abc1234-fix\n- **Project**: my-api\n- **Path**: workspaces/my-api/goal-abc1234-fix\n- **Base Branch**: main\n"), 0644)
+
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	// main moves on after the goal branched; release/1 forks from the start
+	git(base, "branch", "release/1", "main")
+	os.WriteFile(filepath.Join(base, "main.txt"), []byte("main"), 0644)
+	git(base, "add", ".")
+	git(base, "commit", "-m", "Main only")
+	git(worktree, "rebase", "main")
+
+	result, _ := RetargetGoal(RetargetOptions{GoalID: "abc1234", Project: "my-api", BaseBranch: "release/9", VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "branch_not_found" {
+		t.Fatalf("expected branch_not_found, got %+v", result.Error)
+	}
+
+	result, data := RetargetGoal(RetargetOptions{GoalID: "abc1234", Project: "my-api", BaseBranch: "release/1", VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("retarget failed: %+v", result.Error)
+	}
+	if data.OldBaseBranch != "main" || data.Strategy != "rebase" || data.Branch != "goal-abc1234-fix" {
+		t.Fatalf("unexpected result: %+v", data)
+	}
+	// Only the goal's commits moved
+	if log := git(worktree, "log", "--format=%s", "release/1..HEAD"); log != "Add b.txt\nAdd a.txt" {
+		t.Errorf("goal commits on release/1 = %q", log)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "main.txt")); err == nil {
+		t.Error("main's commit followed the goal branch")
+	}
+
+	content, _ := os.ReadFile(goalFile)
+	if !strings.Contains(string(content), "- **Base Branch**: release/1\n") {
+		t.Errorf("base branch not recorded:\n%s", content)
+	}
+	if baseBranch, _ := GoalBaseBranch(vegaDir, "abc1234", "my-api"); baseBranch != "release/1" {
+		t.Errorf("GoalBaseBranch = %s", baseBranch)
+	}
+	diff, changes := GoalChangedFiles(GoalDiffOptions{GoalID: "abc1234", Project: "my-api", VegaDir: vegaDir})
+	if !diff.Success || changes.BaseBranch != "release/1" || len(changes.Files) != 2 {
+		t.Errorf("diff after retarget: %+v %+v", diff.Error, changes)
+	}
+}
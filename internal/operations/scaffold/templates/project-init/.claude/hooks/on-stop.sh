@@ -0,0 +1,112 @@
+#!/usr/bin/env bash
+#
+# on-stop.sh - Hook that runs when executor stops
+#
+# This hook first collects messages users sent to the executor from the web
+# UI. If there are any, it blocks the stop so Claude reads them (vega-hub
+# marks them delivered, and processed once the executor stops again).
+# Otherwise it notifies vega-hub that the executor has stopped.
+# vega-hub handles: markdown updates, desktop notifications, SSE events.
+# All communication goes through vega-hub (single source of truth).
+#
+# Input: JSON via stdin with session info
+# Output: JSON blocking the stop with the user's messages, or allowing it
+
+set -euo pipefail
+
+# Read input
+INPUT=$(cat)
+
+# Get session info
+CWD=$(echo "$INPUT" | jq -r '.cwd // empty')
+CLAUDE_SESSION_ID=$(echo "$INPUT" | jq -r '.session_id // empty')
+TRANSCRIPT_PATH=$(echo "$INPUT" | jq -r '.transcript_path // empty')
+# Executors vega-hub spawns report as the session their token is scoped to
+SESSION_ID="${VEGA_SESSION_ID:-${CLAUDE_SESSION_ID:-unknown}}"
+
+if [[ -z "$CWD" ]]; then
+    exit 0
+fi
+
+# Goal ID, filled in by vega-hub; otherwise detected from the directory name
+# (pattern: goal-HASH-slug)
+GOAL_ID="{{GOAL_ID}}"
+if [[ -z "$GOAL_ID" || "$GOAL_ID" == "{{"* ]]; then
+    GOAL_DIR=$(basename "$CWD")
+    if [[ ! "$GOAL_DIR" =~ ^goal-([0-9a-f]+)- ]]; then
+        # Not in a goal worktree, nothing to report
+        exit 0
+    fi
+    GOAL_ID="${BASH_REMATCH[1]}"
+fi
+
+# Hub address, filled in by vega-hub when it installed this hook.
+# VEGA_HUB_HOST/VEGA_HUB_PORT from the environment win (spawned, remote and
+# container executors reach the hub through them), as does the VEGA_HUB_TOKEN
+# vega-hub issues to executors it spawns.
+VEGA_HUB_URL="{{VEGA_HUB_URL}}"
+VEGA_HUB_SOCKET="{{VEGA_HUB_SOCKET}}"
+VEGA_HUB_TOKEN="${VEGA_HUB_TOKEN:-}"
+if [[ -n "${VEGA_HUB_PORT:-}" ]]; then
+    VEGA_HUB_URL="http://${VEGA_HUB_HOST:-localhost}:${VEGA_HUB_PORT}"
+    VEGA_HUB_SOCKET=""
+elif [[ "$VEGA_HUB_URL" == "{{"* ]]; then
+    # Copied without vega-hub filling it in
+    VEGA_HUB_URL="http://localhost:8080"
+    VEGA_HUB_SOCKET=""
+fi
+CURL_ARGS=(-s -H "Content-Type: application/json")
+if [[ -n "$VEGA_HUB_SOCKET" ]]; then
+    CURL_ARGS+=(--unix-socket "$VEGA_HUB_SOCKET")
+    VEGA_HUB_URL="http://localhost"
+fi
+if [[ -n "$VEGA_HUB_TOKEN" ]]; then
+    CURL_ARGS+=(-H "Authorization: Bearer $VEGA_HUB_TOKEN")
+fi
+
+# Best effort: fetch messages waiting for this executor (empty if unavailable)
+fetch_user_messages() {
+    curl "${CURL_ARGS[@]}" \
+        "${VEGA_HUB_URL}/api/goals/${GOAL_ID}/messages/pending?session_id=${SESSION_ID}" \
+        2>/dev/null || true
+}
+
+PENDING=$(fetch_user_messages)
+DECISION=$(echo "$PENDING" | jq -r '.decision // empty' 2>/dev/null || true)
+if [[ "$DECISION" == "block" ]]; then
+    # Keep the executor running to address the messages
+    echo "$PENDING" | jq '{decision: "block", reason: .reason}'
+    exit 0
+fi
+
+# Best effort: notify vega-hub (don't fail if unavailable)
+notify_vega_hub() {
+    # Build request
+    local request
+    request=$(jq -n \
+        --arg goal_id "$GOAL_ID" \
+        --arg session_id "$SESSION_ID" \
+        --arg claude_session_id "$CLAUDE_SESSION_ID" \
+        --arg transcript_path "$TRANSCRIPT_PATH" \
+        --arg reason "completed" \
+        '{
+            goal_id: $goal_id,
+            session_id: $session_id,
+            claude_session_id: $claude_session_id,
+            transcript_path: $transcript_path,
+            reason: $reason
+        }')
+
+    # POST to vega-hub (fire and forget)
+    curl "${CURL_ARGS[@]}" -X POST \
+        -d "$request" \
+        "${VEGA_HUB_URL}/api/executor/stop" \
+        >/dev/null 2>&1 || true
+}
+
+# Notify (best effort)
+notify_vega_hub 2>/dev/null || true
+
+# Allow stop
+echo '{"decision": null}'
+exit 0
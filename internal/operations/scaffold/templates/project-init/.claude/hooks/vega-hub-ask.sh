@@ -0,0 +1,124 @@
+#!/bin/bash
+#
+# vega-hub-ask.sh - PreToolUse hook for AskUserQuestion
+#
+# This hook intercepts AskUserQuestion tool calls and routes them to vega-hub.
+# It waits until a human answers via the vega-hub web UI.
+#
+# Input: JSON from Claude Code PreToolUse hook (via stdin)
+# Output: JSON with permissionDecision: deny and answer in permissionDecisionReason
+#
+
+set -euo pipefail
+
+# Read hook input from stdin
+INPUT=$(cat)
+
+# Extract tool name - only process AskUserQuestion
+TOOL_NAME=$(echo "$INPUT" | jq -r '.tool_name // empty')
+if [[ "$TOOL_NAME" != "AskUserQuestion" ]]; then
+    # Not our tool, let it proceed normally
+    exit 0
+fi
+
+# Hub address, filled in by vega-hub when it installed this hook.
+# VEGA_HUB_HOST/VEGA_HUB_PORT from the environment win (spawned, remote and
+# container executors reach the hub through them), as does the VEGA_HUB_TOKEN
+# vega-hub issues to executors it spawns.
+VEGA_HUB_URL="{{VEGA_HUB_URL}}"
+VEGA_HUB_SOCKET="{{VEGA_HUB_SOCKET}}"
+VEGA_HUB_TOKEN="${VEGA_HUB_TOKEN:-}"
+if [[ -n "${VEGA_HUB_PORT:-}" ]]; then
+    VEGA_HUB_URL="http://${VEGA_HUB_HOST:-localhost}:${VEGA_HUB_PORT}"
+    VEGA_HUB_SOCKET=""
+elif [[ "$VEGA_HUB_URL" == "{{"* ]]; then
+    # Copied without vega-hub filling it in
+    VEGA_HUB_URL="http://localhost:8080"
+    VEGA_HUB_SOCKET=""
+fi
+CURL_ARGS=(-s -H "Content-Type: application/json")
+if [[ -n "$VEGA_HUB_SOCKET" ]]; then
+    CURL_ARGS+=(--unix-socket "$VEGA_HUB_SOCKET")
+    VEGA_HUB_URL="http://localhost"
+fi
+if [[ -n "$VEGA_HUB_TOKEN" ]]; then
+    CURL_ARGS+=(-H "Authorization: Bearer $VEGA_HUB_TOKEN")
+fi
+
+# Goal ID, filled in by vega-hub; otherwise taken from the worktree directory
+# name (.../goal-4fd584d-add-auth)
+GOAL_ID="{{GOAL_ID}}"
+if [[ -z "$GOAL_ID" || "$GOAL_ID" == "{{"* ]]; then
+    CWD=$(echo "$INPUT" | jq -r '.cwd // empty')
+    GOAL_ID=$(basename "$CWD" | grep -oP 'goal-\K[0-9a-f]+' || echo "0")
+fi
+
+# Extract session ID; executors vega-hub spawns report as the session their
+# token is scoped to
+SESSION_ID="${VEGA_SESSION_ID:-$(echo "$INPUT" | jq -r '.session_id // "unknown"')}"
+
+# Extract question details from tool_input
+TOOL_INPUT=$(echo "$INPUT" | jq -c '.tool_input // {}')
+
+# Build the first question (AskUserQuestion can have multiple, we take the first)
+QUESTION=$(echo "$TOOL_INPUT" | jq -r '.questions[0].question // empty')
+OPTIONS=$(echo "$TOOL_INPUT" | jq -c '.questions[0].options // []')
+
+if [[ -z "$QUESTION" ]]; then
+    # No question found, let it proceed
+    exit 0
+fi
+
+# Build request for vega-hub
+REQUEST=$(jq -n \
+    --arg goal_id "$GOAL_ID" \
+    --arg session_id "$SESSION_ID" \
+    --arg question "$QUESTION" \
+    --argjson options "$OPTIONS" \
+    '{
+        goal_id: $goal_id,
+        session_id: $session_id,
+        question: $question,
+        options: $options,
+        max_wait: 240
+    }')
+
+# POST to vega-hub. It answers within max_wait seconds, so proxies don't cut
+# the request; an unanswered question comes back pending with its ID, and
+# asking again with that ID keeps waiting for the same question.
+while true; do
+    RESPONSE=$(curl "${CURL_ARGS[@]}" -X POST \
+        -d "$REQUEST" \
+        "${VEGA_HUB_URL}/api/ask" \
+        2>/dev/null) || {
+        # vega-hub not available, let tool proceed normally
+        echo "Warning: vega-hub not available at ${VEGA_HUB_SOCKET:-$VEGA_HUB_URL}" >&2
+        exit 0
+    }
+    QUESTION_ID=$(echo "$RESPONSE" | jq -r 'select(.pending == true) | .question_id // empty' 2>/dev/null || true)
+    if [[ -z "$QUESTION_ID" ]]; then
+        break
+    fi
+    REQUEST=$(echo "$REQUEST" | jq -c --arg id "$QUESTION_ID" '. + {question_id: $id}')
+done
+
+# Extract answer
+ANSWER=$(echo "$RESPONSE" | jq -r '.answer // empty')
+
+if [[ -z "$ANSWER" ]]; then
+    # No answer received, let it proceed
+    exit 0
+fi
+
+# Build hook response - deny the tool but provide the answer
+cat <<EOF
+{
+    "hookSpecificOutput": {
+        "hookEventName": "PreToolUse",
+        "permissionDecision": "deny",
+        "permissionDecisionReason": "[vega-hub] User answered your question. Response: '$ANSWER'. Continue with this information."
+    }
+}
+EOF
+
+exit 0
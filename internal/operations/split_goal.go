@@ -0,0 +1,220 @@
+package operations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// Ways to split a goal into child goals
+const (
+	SplitByPhase = "phases" // One child per phase (default)
+	SplitByTask  = "tasks"  // One child per selected task
+)
+
+// SplitGoalOptions contains options for splitting a goal into child goals
+type SplitGoalOptions struct {
+	GoalID     string
+	Mode       string   // SplitByPhase or SplitByTask
+	Phases     []int    // Phases to split (default: every phase with open tasks)
+	Tasks      []string // Tasks to split as "<phase>.<task>", 1-based (required by SplitByTask)
+	Preview    bool     // Only plan the children, don't create them
+	NoWorktree bool
+	VegaDir    string
+
+	// Progress, if set, is called before each child is created
+	Progress func(step string)
+}
+
+// SplitChild is a child goal planned or created by SplitGoal
+type SplitChild struct {
+	GoalID       string   `json:"goal_id,omitempty"` // Empty in a preview
+	Title        string   `json:"title"`
+	Phase        int      `json:"phase"` // Source phase number
+	Tasks        []string `json:"tasks"`
+	GoalBranch   string   `json:"goal_branch,omitempty"`
+	WorktreePath string   `json:"worktree_path,omitempty"`
+}
+
+// SplitResult contains the result of splitting a goal
+type SplitResult struct {
+	GoalID   string       `json:"goal_id"`
+	Project  string       `json:"project"`
+	Mode     string       `json:"mode"`
+	Preview  bool         `json:"preview,omitempty"`
+	Children []SplitChild `json:"children"`
+}
+
+// SplitGoal decomposes a goal into child goals from its phases: one child
+// per phase, or one per selected task. Children inherit the goal's project
+// and are linked under it in the hierarchy. Completed tasks are left out.
+func SplitGoal(opts SplitGoalOptions) (*Result, *SplitResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID)); errResult != nil {
+		return errResult, nil
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string) {}
+	}
+	mode := opts.Mode
+	if mode == "" {
+		mode = SplitByPhase
+	}
+	if mode != SplitByPhase && mode != SplitByTask {
+		return splitError("invalid_split_mode", fmt.Sprintf("Invalid split mode '%s' (valid: %s, %s)", mode, SplitByPhase, SplitByTask), nil), nil
+	}
+
+	detail, err := goals.NewParser(opts.VegaDir).ParseGoalDetail(opts.GoalID)
+	if err != nil {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "goal_not_found",
+				Message: fmt.Sprintf("Goal '%s' not found", opts.GoalID),
+				Details: map[string]string{"goal_id": opts.GoalID},
+			},
+		}, nil
+	}
+	if detail.Status != "active" {
+		return splitError("goal_not_active", fmt.Sprintf("Goal '%s' is %s; only active goals can be split", opts.GoalID, detail.Status), nil), nil
+	}
+	if err := goals.NewHierarchyManager(opts.VegaDir).ValidateParentForChildCreation(opts.GoalID); err != nil {
+		return splitError("invalid_parent", err.Error(), map[string]string{"goal_id": opts.GoalID}), nil
+	}
+
+	var children []SplitChild
+	if mode == SplitByPhase {
+		children, err = planPhaseSplit(detail, opts.Phases)
+	} else {
+		children, err = planTaskSplit(detail, opts.Tasks)
+	}
+	if err != nil {
+		return splitError("invalid_selection", err.Error(), nil), nil
+	}
+	if len(children) == 0 {
+		return splitError("nothing_to_split", fmt.Sprintf("Goal '%s' has no open tasks to split", opts.GoalID), nil), nil
+	}
+
+	split := &SplitResult{GoalID: opts.GoalID, Mode: mode, Preview: opts.Preview, Children: children}
+	if len(detail.Projects) > 0 {
+		split.Project = detail.Projects[0]
+	}
+	if opts.Preview {
+		return &Result{Success: true}, split
+	}
+
+	var created []string
+	for i := range split.Children {
+		child := &split.Children[i]
+		progress(fmt.Sprintf("creating child goal %d/%d", i+1, len(split.Children)))
+		result, data := CreateGoal(CreateOptions{
+			Title:      child.Title,
+			Project:    split.Project,
+			ParentID:   opts.GoalID,
+			NoWorktree: opts.NoWorktree,
+			Body:       splitChildBody(detail, child, split.Project),
+			VegaDir:    opts.VegaDir,
+		})
+		if !result.Success {
+			// Children created so far stay; report them with the failure
+			if result.Error.Details == nil {
+				result.Error.Details = map[string]string{}
+			}
+			result.Error.Details["created"] = strings.Join(created, ", ")
+			return result, split
+		}
+		child.GoalID = data.GoalID
+		child.GoalBranch = data.GoalBranch
+		child.WorktreePath = data.WorktreePath
+		created = append(created, data.GoalID)
+	}
+	return &Result{Success: true}, split
+}
+
+// planPhaseSplit plans one child per selected phase with open tasks
+func planPhaseSplit(detail *goals.GoalDetail, selected []int) ([]SplitChild, error) {
+	want := make(map[int]bool)
+	for _, n := range selected {
+		if findPhase(detail, n) == nil {
+			return nil, fmt.Errorf("goal has no phase %d", n)
+		}
+		want[n] = true
+	}
+
+	var children []SplitChild
+	for _, phase := range detail.Phases {
+		if len(want) > 0 && !want[phase.Number] {
+			continue
+		}
+		child := SplitChild{Title: phase.Title, Phase: phase.Number}
+		for _, task := range phase.Tasks {
+			if !task.Completed {
+				child.Tasks = append(child.Tasks, task.Description)
+			}
+		}
+		if len(child.Tasks) > 0 {
+			children = append(children, child)
+		}
+	}
+	return children, nil
+}
+
+// planTaskSplit plans one child per selected task ("<phase>.<task>")
+func planTaskSplit(detail *goals.GoalDetail, refs []string) ([]SplitChild, error) {
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("select the tasks to split as <phase>.<task>")
+	}
+	var children []SplitChild
+	for _, ref := range refs {
+		p, t, ok := strings.Cut(ref, ".")
+		phaseNum, err1 := strconv.Atoi(p)
+		taskNum, err2 := strconv.Atoi(t)
+		if !ok || err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid task %q, expected <phase>.<task>", ref)
+		}
+		phase := findPhase(detail, phaseNum)
+		if phase == nil || taskNum < 1 || taskNum > len(phase.Tasks) {
+			return nil, fmt.Errorf("goal has no task %s", ref)
+		}
+		task := phase.Tasks[taskNum-1]
+		if task.Completed {
+			continue
+		}
+		children = append(children, SplitChild{Title: task.Description, Phase: phaseNum, Tasks: []string{task.Description}})
+	}
+	return children, nil
+}
+
+func findPhase(detail *goals.GoalDetail, number int) *goals.PhaseDetail {
+	for i := range detail.Phases {
+		if detail.Phases[i].Number == number {
+			return &detail.Phases[i]
+		}
+	}
+	return nil
+}
+
+// splitChildBody is the goal file body of a child goal: a pointer back to
+// the parent and a single phase with the child's tasks
+func splitChildBody(parent *goals.GoalDetail, child *SplitChild, project string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## Overview\n\nPart of goal %s: %s (phase %d).\n", parent.ID, parent.Title, child.Phase)
+	if project != "" {
+		fmt.Fprintf(&b, "\n## Project(s)\n\n- **%s**\n", project)
+	}
+	fmt.Fprintf(&b, "\n## Phases\n\n### Phase 1: %s\n", child.Title)
+	for _, task := range child.Tasks {
+		fmt.Fprintf(&b, "- [ ] %s\n", task)
+	}
+	b.WriteString("\n## Status\n\nCurrent Phase: 1/1\n")
+	return b.String()
+}
+
+func splitError(code, message string, details map[string]string) *Result {
+	return &Result{
+		Success: false,
+		Error:   &ErrorInfo{Code: code, Message: message, Details: details},
+	}
+}
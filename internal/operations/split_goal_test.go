@@ -0,0 +1,82 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestSplitGoal(t *testing.T) {
+	vegaDir := setupCompleteGoal(t, "")
+	source := `# Goal #abc1234: Release 2.0
+
+## Project(s)
+
+- **my-api**: API changes
+
+## Phases
+
+### Phase 1: Prepare
+- [x] Freeze features
+
+### Phase 2: Backend
+- [x] Add routes
+- [ ] Migrate the schema
+- [ ] Update clients
+
+### Phase 3: Docs
+- [ ] Write the changelog
+`
+	os.WriteFile(filepath.Join(vegaDir, "goals", "active", "abc1234.md"), []byte(source), 0644)
+
+	// A preview plans one child per phase with open tasks and creates nothing
+	result, split := SplitGoal(SplitGoalOptions{GoalID: "abc1234", Preview: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("preview failed: %+v", result.Error)
+	}
+	if len(split.Children) != 2 || split.Children[0].Title != "Backend" || len(split.Children[0].Tasks) != 2 || split.Children[0].GoalID != "" {
+		t.Fatalf("unexpected plan %+v", split.Children)
+	}
+	if children, _ := goals.NewHierarchyManager(vegaDir).GetChildren("abc1234"); len(children) != 0 {
+		t.Fatalf("expected no children from a preview, got %v", children)
+	}
+
+	result, split = SplitGoal(SplitGoalOptions{GoalID: "abc1234", Phases: []int{2}, NoWorktree: true, VegaDir: vegaDir})
+	if !result.Success {
+		t.Fatalf("split failed: %+v", result.Error)
+	}
+	if len(split.Children) != 1 || split.Children[0].GoalID != "abc1234.1" || split.Project != "my-api" {
+		t.Fatalf("unexpected split %+v", split)
+	}
+	if parent, _ := goals.NewHierarchyManager(vegaDir).GetParentID("abc1234.1"); parent != "abc1234" {
+		t.Errorf("expected the child linked to the goal, got parent %q", parent)
+	}
+	data, _ := os.ReadFile(filepath.Join(vegaDir, "goals", "active", "abc1234.1.md"))
+	child := string(data)
+	if !strings.Contains(child, "### Phase 1: Backend\n- [ ] Migrate the schema\n- [ ] Update clients\n") || strings.Contains(child, "Add routes") {
+		t.Errorf("expected the open tasks of phase 2, got:\n%s", child)
+	}
+
+	// One child per selected task; completed tasks are skipped
+	result, split = SplitGoal(SplitGoalOptions{GoalID: "abc1234", Mode: SplitByTask, Tasks: []string{"2.1", "3.1"}, NoWorktree: true, VegaDir: vegaDir})
+	if !result.Success || len(split.Children) != 1 || split.Children[0].Title != "Write the changelog" || split.Children[0].GoalID != "abc1234.2" {
+		t.Fatalf("unexpected task split %+v (%+v)", split, result.Error)
+	}
+
+	for _, opts := range []SplitGoalOptions{
+		{GoalID: "abc1234", Phases: []int{9}},
+		{GoalID: "abc1234", Mode: SplitByTask, Tasks: []string{"2"}},
+		{GoalID: "abc1234", Mode: SplitByTask},
+	} {
+		opts.VegaDir = vegaDir
+		if result, _ := SplitGoal(opts); result.Success || result.Error.Code != "invalid_selection" {
+			t.Errorf("expected invalid_selection for %+v, got %+v", opts, result.Error)
+		}
+	}
+	if result, _ := SplitGoal(SplitGoalOptions{GoalID: "abc1234", Phases: []int{1}, VegaDir: vegaDir}); result.Success || result.Error.Code != "nothing_to_split" {
+		t.Errorf("expected nothing_to_split for a finished phase, got %+v", result.Error)
+	}
+}
@@ -0,0 +1,51 @@
+package operations
+
+import (
+	"errors"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// SetGoalTags replaces a goal's tags (see goals.NormalizeTags) and returns
+// them as stored
+func SetGoalTags(vegaDir, goalID string, tags []string) (*Result, []string) {
+	if errResult := checkInputs(idInput("goal ID", goalID)); errResult != nil {
+		return errResult, nil
+	}
+	tags, err := goals.NormalizeTags(tags)
+	if err != nil {
+		return invalidTagsResult(err), nil
+	}
+
+	lockMgr := hub.NewLockManager(vegaDir)
+	err = lockMgr.WithRegistryLock("set-goal-tags", func() error {
+		return goals.NewRegistry(vegaDir).SetTags(goalID, tags)
+	})
+	switch {
+	case errors.Is(err, goals.ErrNotFound):
+		return goalNotFoundResult(goalID), nil
+	case err != nil:
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "registry_update_failed",
+				Message: "Could not update registry",
+				Details: map[string]string{"error": err.Error()},
+			},
+		}, nil
+	}
+	return &Result{Success: true}, tags
+}
+
+// invalidTagsResult reports a tag that can't be used
+func invalidTagsResult(err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "invalid_input",
+			Message: err.Error(),
+			Details: map[string]string{"field": "tags"},
+		},
+	}
+}
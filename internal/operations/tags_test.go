@@ -0,0 +1,54 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+func TestGoalTags(t *testing.T) {
+	vegaDir, _ := setupRepairProject(t)
+	os.MkdirAll(filepath.Join(vegaDir, "goals", "active"), 0755)
+
+	result, _ := CreateGoal(CreateOptions{Title: "Bad tags", Project: "my-api", Draft: true, Tags: []string{"no spaces"}, VegaDir: vegaDir})
+	if result.Success || result.Error.Code != "invalid_input" {
+		t.Fatalf("expected invalid_input, got %+v", result.Error)
+	}
+
+	result, data := CreateGoal(CreateOptions{
+		Title:     "Fix login",
+		Project:   "my-api",
+		Draft:     true,
+		CreatedBy: "alice",
+		Tags:      []string{" Bug ", "area/auth", "bug"},
+		VegaDir:   vegaDir,
+	})
+	if !result.Success {
+		t.Fatalf("CreateGoal: %+v", result.Error)
+	}
+	if len(data.Tags) != 2 || data.Tags[0] != "bug" || data.Tags[1] != "area/auth" {
+		t.Errorf("tags not normalized: %v", data.Tags)
+	}
+
+	// The goal detail carries the registry's metadata
+	detail, err := goals.NewParser(vegaDir).ParseGoalDetail(data.GoalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.CreatedBy != "alice" || detail.CreatedAt == "" || !detail.HasTag("BUG") || detail.Status != goals.StatusDraft {
+		t.Errorf("unexpected goal: %+v", detail.Goal)
+	}
+
+	result, tags := SetGoalTags(vegaDir, data.GoalID, []string{"perf"})
+	if !result.Success || len(tags) != 1 {
+		t.Fatalf("SetGoalTags: %+v, %v", result.Error, tags)
+	}
+	if entry, _ := goals.NewRegistry(vegaDir).Get(data.GoalID); entry.HasTag("bug") || !entry.HasTag("perf") {
+		t.Errorf("tags not replaced: %v", entry.Tags)
+	}
+	if result, _ := SetGoalTags(vegaDir, "nope123", nil); result.Success || result.Error.Code != "goal_not_found" {
+		t.Errorf("expected goal_not_found, got %+v", result.Error)
+	}
+}
@@ -0,0 +1,179 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// Timeline bucket sizes
+const (
+	TimelineHour = "hour"
+	TimelineDay  = "day"
+)
+
+// GoalTimelineOptions contains options for bucketing a goal's activity
+type GoalTimelineOptions struct {
+	GoalID  string
+	VegaDir string
+	Bucket  string         // TimelineHour or TimelineDay (default)
+	Since   time.Time      // Activity before it is left out; zero for all
+	Loc     *time.Location // Buckets start at midnight or the hour in it; UTC if nil
+}
+
+// TimelineBucket counts a goal's activity in one hour or day
+type TimelineBucket struct {
+	Start        time.Time `json:"start"`
+	StateChanges int       `json:"state_changes"`
+	Sessions     int       `json:"sessions"` // Executor sessions started
+	Questions    int       `json:"questions"`
+	Commits      int       `json:"commits"`
+}
+
+// GoalTimelineResult is a goal's activity bucketed for a timeline or heatmap.
+// Only buckets with activity are listed, oldest first; Start and End span
+// them so clients can fill the gaps.
+type GoalTimelineResult struct {
+	GoalID  string           `json:"goal_id"`
+	Bucket  string           `json:"bucket"`
+	Start   *time.Time       `json:"start,omitempty"`
+	End     *time.Time       `json:"end,omitempty"`
+	Buckets []TimelineBucket `json:"buckets"`
+	Totals  TimelineBucket   `json:"totals"` // Start is left zero
+}
+
+// GoalTimeline buckets a goal's state changes, executor sessions and questions
+// from its state file and session history, and the commits on its goal
+// branches since they branched off the base branch. Commits are only found
+// while the goal still has worktrees.
+func GoalTimeline(opts GoalTimelineOptions) (*Result, *GoalTimelineResult) {
+	if errResult := checkInputs(idInput("goal ID", opts.GoalID)); errResult != nil {
+		return errResult, nil
+	}
+	if opts.Bucket == "" {
+		opts.Bucket = TimelineDay
+	}
+	if opts.Bucket != TimelineHour && opts.Bucket != TimelineDay {
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    "invalid_bucket",
+				Message: fmt.Sprintf("Bucket must be %q or %q", TimelineHour, TimelineDay),
+				Details: map[string]string{"bucket": opts.Bucket},
+			},
+		}, nil
+	}
+	if opts.Loc == nil {
+		opts.Loc = time.UTC
+	}
+
+	entry, err := goals.NewRegistry(opts.VegaDir).Get(opts.GoalID)
+	if err != nil {
+		code := "registry_failed"
+		if errors.Is(err, goals.ErrNotFound) {
+			code = "goal_not_found"
+		}
+		return &Result{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    code,
+				Message: fmt.Sprintf("Goal '%s' not found", opts.GoalID),
+				Details: map[string]string{"goal_id": opts.GoalID, "error": err.Error()},
+			},
+		}, nil
+	}
+
+	buckets := make(map[time.Time]*TimelineBucket)
+	add := func(at time.Time, count func(*TimelineBucket)) {
+		if at.IsZero() || at.Before(opts.Since) {
+			return
+		}
+		start := timelineBucketStart(at.In(opts.Loc), opts.Bucket)
+		b, ok := buckets[start]
+		if !ok {
+			b = &TimelineBucket{Start: start}
+			buckets[start] = b
+		}
+		count(b)
+	}
+
+	events, _ := goals.NewStateManager(opts.VegaDir).GetHistory(opts.GoalID)
+	for _, e := range events {
+		add(e.Timestamp, func(b *TimelineBucket) { b.StateChanges++ })
+	}
+
+	entries, _ := hub.NewSessionHistory(opts.VegaDir).GetGoalHistory(opts.GoalID, 0)
+	for _, e := range entries {
+		switch e.Type {
+		case "session_start":
+			add(e.Timestamp, func(b *TimelineBucket) { b.Sessions++ })
+		case "question":
+			add(e.Timestamp, func(b *TimelineBucket) { b.Questions++ })
+		}
+	}
+
+	for _, project := range entry.Projects {
+		worktree, err := FindGoalWorktree(opts.VegaDir, project, opts.GoalID)
+		if err != nil {
+			continue
+		}
+		baseBranch, _ := GoalBaseBranch(opts.VegaDir, opts.GoalID, project)
+		if baseBranch == "" {
+			baseBranch = "main"
+		}
+		for _, at := range goalCommitTimes(worktree, baseBranch) {
+			add(at, func(b *TimelineBucket) { b.Commits++ })
+		}
+	}
+
+	result := &GoalTimelineResult{GoalID: opts.GoalID, Bucket: opts.Bucket, Buckets: []TimelineBucket{}}
+	for _, b := range buckets {
+		result.Buckets = append(result.Buckets, *b)
+		result.Totals.StateChanges += b.StateChanges
+		result.Totals.Sessions += b.Sessions
+		result.Totals.Questions += b.Questions
+		result.Totals.Commits += b.Commits
+	}
+	sort.Slice(result.Buckets, func(i, j int) bool { return result.Buckets[i].Start.Before(result.Buckets[j].Start) })
+	if n := len(result.Buckets); n > 0 {
+		start, end := result.Buckets[0].Start, result.Buckets[n-1].Start
+		result.Start, result.End = &start, &end
+	}
+	return &Result{Success: true}, result
+}
+
+// timelineBucketStart truncates t to the start of its hour or day, in t's
+// location (time.Truncate works in UTC, which is off for days elsewhere)
+func timelineBucketStart(t time.Time, bucket string) time.Time {
+	hour := 0
+	if bucket == TimelineHour {
+		hour = t.Hour()
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
+}
+
+// goalCommitTimes returns the author dates of the non-merge commits the
+// worktree's HEAD made since it branched off baseBranch
+func goalCommitTimes(worktree, baseBranch string) []time.Time {
+	mergeBase, err := goalMergeBase(worktree, baseBranch)
+	if err != nil {
+		return nil
+	}
+	output, err := exec.Command("git", "-C", worktree, "log", "--no-merges", "--format=%aI", mergeBase+"..HEAD").Output()
+	if err != nil {
+		return nil
+	}
+	var times []time.Time
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if at, err := time.Parse(time.RFC3339, line); err == nil {
+			times = append(times, at)
+		}
+	}
+	return times
+}
@@ -0,0 +1,62 @@
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+func TestGoalTimeline(t *testing.T) {
+	vegaDir := setupMonorepoGoal(t, "")
+	if err := goals.NewRegistry(vegaDir).Add(goals.RegistryEntry{ID: "abc1234", Title: "Fix", Projects: []string{"mono"}, Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	sm := goals.NewStateManager(vegaDir)
+	sm.Transition("abc1234", goals.StatePending, "Goal created", nil)
+	sm.Transition("abc1234", goals.StateBranching, "Creating worktree", nil)
+	history := hub.NewSessionHistory(vegaDir)
+	history.RecordSessionStart("abc1234", "s1", "/tmp", "alice")
+	history.RecordQuestion("abc1234", "s1", "Proceed?", "Yes")
+
+	result, data := GoalTimeline(GoalTimelineOptions{GoalID: "abc1234", VegaDir: vegaDir, Bucket: TimelineHour})
+	if !result.Success {
+		t.Fatalf("GoalTimeline failed: %+v", result.Error)
+	}
+	want := TimelineBucket{StateChanges: 2, Sessions: 1, Questions: 1, Commits: 1}
+	if data.Totals != want {
+		t.Errorf("expected totals %+v, got %+v", want, data.Totals)
+	}
+	if len(data.Buckets) == 0 || data.Start == nil || data.End == nil {
+		t.Fatalf("expected buckets with their span, got %+v", data)
+	}
+	for _, b := range data.Buckets {
+		if b.Start.Minute() != 0 || b.Start.Second() != 0 {
+			t.Errorf("bucket doesn't start on the hour: %v", b.Start)
+		}
+	}
+
+	_, data = GoalTimeline(GoalTimelineOptions{GoalID: "abc1234", VegaDir: vegaDir, Since: time.Now().Add(time.Hour)})
+	if len(data.Buckets) != 0 || data.Bucket != TimelineDay {
+		t.Errorf("expected no day buckets after since, got %+v", data)
+	}
+
+	if result, _ := GoalTimeline(GoalTimelineOptions{GoalID: "abc1234", VegaDir: vegaDir, Bucket: "week"}); result.Success || result.Error.Code != "invalid_bucket" {
+		t.Errorf("expected invalid_bucket, got %+v", result)
+	}
+	if result, _ := GoalTimeline(GoalTimelineOptions{GoalID: "fff0000", VegaDir: vegaDir}); result.Success || result.Error.Code != "goal_not_found" {
+		t.Errorf("expected goal_not_found, got %+v", result)
+	}
+}
+
+func TestTimelineBucketStart(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	at := time.Date(2026, 3, 4, 22, 30, 0, 0, loc)
+	if got := timelineBucketStart(at, TimelineDay); !got.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, loc)) {
+		t.Errorf("day bucket: got %v", got)
+	}
+	if got := timelineBucketStart(at, TimelineHour); !got.Equal(time.Date(2026, 3, 4, 22, 0, 0, 0, loc)) {
+		t.Errorf("hour bucket: got %v", got)
+	}
+}
@@ -0,0 +1,40 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/lasmarois/vega-hub/internal/gitsvc"
+	"github.com/lasmarois/vega-hub/internal/goals"
+)
+
+// ProjectBackend returns the VCS backend a project is configured with
+// (goals.Project.VCS). Projects whose config can't be read use git.
+func ProjectBackend(vegaDir, project string) (gitsvc.Backend, error) {
+	p, err := goals.ParseProject(vegaDir, project)
+	if err != nil {
+		return gitsvc.GitBackend{}, nil
+	}
+	return gitsvc.BackendFor(p.VCS)
+}
+
+// projectBackendOrGit returns the project's backend, falling back to git for
+// cleanup paths that shouldn't fail on a misconfigured VCS
+func projectBackendOrGit(vegaDir, project string) gitsvc.Backend {
+	backend, err := ProjectBackend(vegaDir, project)
+	if err != nil {
+		return gitsvc.GitBackend{}
+	}
+	return backend
+}
+
+// vcsResult reports a project configured with an unknown VCS
+func vcsResult(project string, err error) *Result {
+	return &Result{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "invalid_vcs",
+			Message: fmt.Sprintf("Project '%s' has an invalid VCS: %v", project, err),
+			Details: map[string]string{"project": project},
+		},
+	}
+}
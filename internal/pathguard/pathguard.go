@@ -0,0 +1,102 @@
+// Package pathguard validates the IDs and names that end up in file paths
+// and git arguments, and keeps paths built from them inside the vega-missile
+// directory.
+package pathguard
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// MaxIDLength bounds goal IDs, project names and session IDs
+const MaxIDLength = 128
+
+var (
+	// ErrInvalidID is returned for IDs and names that aren't safe in a path
+	ErrInvalidID = errors.New("invalid identifier")
+
+	// ErrOutsideRoot is returned for paths that resolve outside their root
+	ErrOutsideRoot = errors.New("path escapes the vega-missile directory")
+)
+
+// ValidateID checks that s can be used as a single path element and as a
+// git argument: letters, digits, '-', '_' and '.', starting with a letter or
+// digit (so it's never read as a git option or a hidden file) and without
+// "..". kind names the value in the error ("goal ID", "project").
+func ValidateID(kind, s string) error {
+	if s == "" {
+		return fmt.Errorf("%w: %s is empty", ErrInvalidID, kind)
+	}
+	if len(s) > MaxIDLength {
+		return fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidID, kind, MaxIDLength)
+	}
+	if !isAlnum(s[0]) {
+		return fmt.Errorf("%w: %s %q must start with a letter or digit", ErrInvalidID, kind, s)
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isAlnum(c) && c != '-' && c != '_' && c != '.' {
+			return fmt.Errorf("%w: %s %q contains %q", ErrInvalidID, kind, s, c)
+		}
+	}
+	if strings.Contains(s, "..") {
+		return fmt.Errorf("%w: %s %q contains \"..\"", ErrInvalidID, kind, s)
+	}
+	return nil
+}
+
+// ValidateRef checks a user-supplied branch name before it's passed to git:
+// it can't start with '-' (read as an option), contain ".." or whitespace and
+// control characters. git check-ref-format rules still apply on top.
+func ValidateRef(kind, ref string) error {
+	if ref == "" {
+		return fmt.Errorf("%w: %s is empty", ErrInvalidID, kind)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("%w: %s %q starts with '-'", ErrInvalidID, kind, ref)
+	}
+	if strings.Contains(ref, "..") {
+		return fmt.Errorf("%w: %s %q contains \"..\"", ErrInvalidID, kind, ref)
+	}
+	for _, r := range ref {
+		if r <= ' ' || r == 0x7f {
+			return fmt.Errorf("%w: %s %q contains whitespace or control characters", ErrInvalidID, kind, ref)
+		}
+	}
+	return nil
+}
+
+// Within checks that path is root or inside it. Both are cleaned and made
+// absolute first; symlinks aren't followed, so a project symlinked in from
+// elsewhere still counts as inside.
+func Within(root, path string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s", ErrOutsideRoot, path)
+	}
+	return nil
+}
+
+// Join joins elems onto root like filepath.Join, failing if the result
+// escapes root
+func Join(root string, elems ...string) (string, error) {
+	path := filepath.Join(append([]string{root}, elems...)...)
+	if err := Within(root, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func isAlnum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,57 @@
+package pathguard
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"abc1234", "my-api", "my_api", "v1.2", "42"} {
+		if err := ValidateID("goal ID", id); err != nil {
+			t.Errorf("expected %q valid, got %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "..", "../etc", "a/b", `a\b`, "a..b", ".hidden", "-rf", "--upload-pack=x", "goal*", "a b", "a\x00b", strings.Repeat("a", MaxIDLength+1)} {
+		if err := ValidateID("goal ID", id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected %q rejected, got %v", id, err)
+		}
+	}
+}
+
+func TestValidateRef(t *testing.T) {
+	for _, ref := range []string{"main", "feature/login", "release-1.2"} {
+		if err := ValidateRef("branch", ref); err != nil {
+			t.Errorf("expected %q valid, got %v", ref, err)
+		}
+	}
+	for _, ref := range []string{"", "-b", "--orphan", "main..evil", "a b", "a\nb"} {
+		if err := ValidateRef("branch", ref); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected %q rejected, got %v", ref, err)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	root := t.TempDir()
+	path, err := Join(root, "workspaces", "my-api", "goal-abc1234-x")
+	if err != nil || path != filepath.Join(root, "workspaces", "my-api", "goal-abc1234-x") {
+		t.Errorf("expected a path inside root, got %q (%v)", path, err)
+	}
+	for _, elems := range [][]string{
+		{"..", "etc"},
+		{"workspaces", "../../etc/passwd"},
+		{"/etc/passwd", "../../.."},
+	} {
+		if _, err := Join(root, elems...); !errors.Is(err, ErrOutsideRoot) {
+			t.Errorf("expected %v rejected, got %v", elems, err)
+		}
+	}
+	if err := Within(root, root); err != nil {
+		t.Errorf("expected root within itself, got %v", err)
+	}
+	if err := Within(root, root+"-sibling"); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("expected a sibling with the same prefix rejected, got %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+// Package selfupdate checks GitHub releases for newer vega-hub builds and
+// replaces the running binary with the release asset for this platform,
+// after verifying it against the release's checksums file.
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/atomicfile"
+)
+
+const (
+	// DefaultAPIURL is the GitHub API that serves release metadata
+	DefaultAPIURL = "https://api.github.com"
+	// DefaultRepo is where vega-hub releases are published
+	DefaultRepo = "lasmarois/vega-hub"
+	// ChecksumsAsset lists the SHA-256 of every binary in a release, in
+	// sha256sum format
+	ChecksumsAsset = "checksums.txt"
+
+	// maxBinarySize bounds a downloaded binary, so a bad asset can't fill the disk
+	maxBinarySize = 256 << 20
+)
+
+// ErrChecksumMismatch is returned when a downloaded binary doesn't match
+// the release's checksums file
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Release is a published vega-hub release
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset returns the release asset with the given name, or nil
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// AssetName is the name of the release binary for a platform, as built by
+// the release workflow and `make release`
+func AssetName(goos, goarch string) string {
+	return fmt.Sprintf("vega-hub-%s-%s", goos, goarch)
+}
+
+// CheckResult compares the running version with the latest release
+type CheckResult struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest"`
+	UpdateAvailable bool   `json:"update_available"`
+	URL             string `json:"url,omitempty"`
+	Asset           string `json:"asset"`
+	AssetAvailable  bool   `json:"asset_available"` // Whether the release has a binary for this platform
+}
+
+// Client talks to the releases API
+type Client struct {
+	APIURL string // Base URL of the GitHub API
+	Repo   string // owner/name
+	HTTP   *http.Client
+}
+
+// NewClient returns a client for the vega-hub releases. VEGA_HUB_RELEASES_API
+// overrides the API URL, e.g. for a GitHub Enterprise mirror.
+func NewClient() *Client {
+	apiURL := DefaultAPIURL
+	if v := os.Getenv("VEGA_HUB_RELEASES_API"); v != "" {
+		apiURL = v
+	}
+	return &Client{
+		APIURL: apiURL,
+		Repo:   DefaultRepo,
+		HTTP:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Latest returns the most recent published release
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	return c.getRelease(ctx, "latest")
+}
+
+// Release returns the release tagged tag ("0.4.1" and "v0.4.1" both work)
+func (c *Client) Release(ctx context.Context, tag string) (*Release, error) {
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	return c.getRelease(ctx, "tags/"+tag)
+}
+
+func (c *Client) getRelease(ctx context.Context, which string) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/%s", strings.TrimSuffix(c.APIURL, "/"), c.Repo, which)
+	body, err := c.get(ctx, url, "application/vnd.github+json", 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	return &release, nil
+}
+
+// Check compares current against the latest release for this platform
+func (c *Client) Check(ctx context.Context, current string) (*CheckResult, *Release, error) {
+	release, err := c.Latest(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	asset := AssetName(runtime.GOOS, runtime.GOARCH)
+	return &CheckResult{
+		Current:         current,
+		Latest:          release.TagName,
+		UpdateAvailable: Newer(release.TagName, current),
+		URL:             release.HTMLURL,
+		Asset:           asset,
+		AssetAvailable:  release.Asset(asset) != nil,
+	}, release, nil
+}
+
+// Download fetches the release binary for goos/goarch and verifies it
+// against the release's checksums file. Releases without a checksums file
+// are refused.
+func (c *Client) Download(ctx context.Context, release *Release, goos, goarch string) ([]byte, error) {
+	name := AssetName(goos, goarch)
+	asset := release.Asset(name)
+	if asset == nil {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s", release.TagName, goos, goarch)
+	}
+	sums := release.Asset(ChecksumsAsset)
+	if sums == nil {
+		return nil, fmt.Errorf("release %s has no %s to verify the download against", release.TagName, ChecksumsAsset)
+	}
+
+	sumsData, err := c.get(ctx, sums.URL, "application/octet-stream", 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsAsset, err)
+	}
+	want, ok := parseChecksums(sumsData)[name]
+	if !ok {
+		return nil, fmt.Errorf("%s has no entry for %s", ChecksumsAsset, name)
+	}
+
+	data, err := c.get(ctx, asset.URL, "application/octet-stream", maxBinarySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, name, want, got)
+	}
+	return data, nil
+}
+
+// get fetches url, failing on error statuses and bodies over limit bytes
+func (c *Client) get(ctx context.Context, url, accept string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(url, c.APIURL) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("response larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+// parseChecksums reads sha256sum output ("<hex>  <name>" per line) into a
+// map from file name to lowercase hex digest
+func parseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks binary mode with a leading '*'
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// Replace atomically swaps the binary at exe for data. The new binary is
+// written next to the old one and renamed over it, so a failed update
+// leaves the old binary in place. Symlinks are followed.
+func Replace(exe string, data []byte) error {
+	path, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", exe, err)
+	}
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := atomicfile.WriteFile(path, data, mode); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// Newer reports whether version latest is newer than current. Versions are
+// compared as major.minor.patch with an optional "v" prefix; a current
+// version that doesn't parse (such as "dev") is always older.
+func Newer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring any pre-release or
+// build suffix
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,154 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeReleases serves a latest release with the given binaries (asset name →
+// content) and a checksums file listing sums
+func fakeReleases(t *testing.T, tag string, binaries map[string]string, sums map[string]string) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	release := Release{TagName: tag, HTMLURL: server.URL + "/releases/" + tag}
+	for name, content := range binaries {
+		content := content
+		release.Assets = append(release.Assets, Asset{Name: name, URL: server.URL + "/download/" + name})
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(content))
+		})
+	}
+	if sums != nil {
+		var lines []string
+		for name, sum := range sums {
+			lines = append(lines, fmt.Sprintf("%s  %s", sum, name))
+		}
+		release.Assets = append(release.Assets, Asset{Name: ChecksumsAsset, URL: server.URL + "/download/" + ChecksumsAsset})
+		mux.HandleFunc("/download/"+ChecksumsAsset, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strings.Join(lines, "\n") + "\n"))
+		})
+	}
+	mux.HandleFunc("/repos/"+DefaultRepo+"/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/repos/"+DefaultRepo+"/releases/tags/"+tag, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+
+	return &Client{APIURL: server.URL, Repo: DefaultRepo, HTTP: server.Client()}
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCheck(t *testing.T) {
+	asset := AssetName(runtime.GOOS, runtime.GOARCH)
+	client := fakeReleases(t, "v0.5.0", map[string]string{asset: "new"}, map[string]string{asset: sha("new")})
+
+	result, _, err := client.Check(context.Background(), "v0.4.1")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !result.UpdateAvailable || result.Latest != "v0.5.0" || !result.AssetAvailable {
+		t.Errorf("expected v0.5.0 available for this platform, got %+v", result)
+	}
+
+	result, _, _ = client.Check(context.Background(), "v0.5.0")
+	if result.UpdateAvailable {
+		t.Errorf("expected no update for the latest version, got %+v", result)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	client := fakeReleases(t, "v0.5.0",
+		map[string]string{"vega-hub-linux-amd64": "linux binary", "vega-hub-darwin-arm64": "darwin binary"},
+		map[string]string{"vega-hub-linux-amd64": sha("linux binary"), "vega-hub-darwin-arm64": sha("tampered")})
+	release, err := client.Release(context.Background(), "0.5.0")
+	if err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	data, err := client.Download(context.Background(), release, "linux", "amd64")
+	if err != nil || string(data) != "linux binary" {
+		t.Fatalf("expected the linux binary, got %q (%v)", data, err)
+	}
+
+	if _, err := client.Download(context.Background(), release, "darwin", "arm64"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	if _, err := client.Download(context.Background(), release, "windows", "amd64"); err == nil {
+		t.Error("expected an error for a platform without a binary")
+	}
+}
+
+func TestDownloadRequiresChecksums(t *testing.T) {
+	client := fakeReleases(t, "v0.5.0", map[string]string{"vega-hub-linux-amd64": "linux binary"}, nil)
+	release, _ := client.Latest(context.Background())
+	if _, err := client.Download(context.Background(), release, "linux", "amd64"); err == nil {
+		t.Error("expected an error for a release without checksums")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "vega-hub")
+	if err := os.WriteFile(exe, []byte("old"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "vega-hub-link")
+	if err := os.Symlink(exe, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(link, []byte("new")); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new" {
+		t.Errorf("expected the linked binary replaced, got %q", data)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm() != 0750 {
+		t.Errorf("expected the mode kept, got %v", info.Mode())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected no staging file left behind, got %d entries", len(entries))
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v0.5.0", "v0.4.1", true},
+		{"v0.4.10", "v0.4.9", true},
+		{"v1.0.0", "0.9.9", true},
+		{"v0.4.1", "v0.4.1", false},
+		{"v0.4.0", "v0.4.1", false},
+		{"v0.5.0-rc1", "v0.4.1", true},
+		{"v0.5.0", "dev", true},
+		{"nightly", "v0.4.1", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
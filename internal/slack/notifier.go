@@ -0,0 +1,147 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// notifierQueueSize bounds how many questions may wait to be posted
+const notifierQueueSize = 64
+
+// Notifier is an event bus consumer that posts new questions to Slack
+type Notifier struct {
+	cfg    Config
+	client *http.Client
+	queue  chan *hub.Question
+}
+
+// NewNotifier creates a notifier posting to cfg.WebhookURL and starts its
+// delivery goroutine
+func NewNotifier(cfg Config) *Notifier {
+	n := &Notifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan *hub.Question, notifierQueueSize),
+	}
+	go n.deliver()
+	return n
+}
+
+// Name implements hub.Consumer
+func (n *Notifier) Name() string { return "slack" }
+
+// Consume implements hub.Consumer
+func (n *Notifier) Consume(event hub.Event) {
+	if event.Type != hub.EventQuestion {
+		return
+	}
+	q, ok := event.Data.(*hub.Question)
+	if !ok {
+		return
+	}
+	select {
+	case n.queue <- q:
+	default:
+		log.Printf("[SLACK] Queue full, not posting question %s", q.ID)
+	}
+}
+
+func (n *Notifier) deliver() {
+	for q := range n.queue {
+		if err := Post(n.client, n.cfg.WebhookURL, QuestionMessage(q, n.cfg.HubURL)); err != nil {
+			log.Printf("[SLACK] Failed to post question %s: %v", q.ID, err)
+		}
+	}
+}
+
+// QuestionMessage renders a pending question with a button per option.
+// Multi-select questions and questions without options link to the hub
+// instead, since a single click can't answer them.
+func QuestionMessage(q *hub.Question, hubURL string) Message {
+	header := fmt.Sprintf("*Goal %s* asks:\n%s", escape(q.GoalID), escape(q.Question))
+	if q.Priority == "high" {
+		header = ":rotating_light: " + header
+	}
+	msg := Message{
+		Text:   fmt.Sprintf("Goal %s asks: %s", q.GoalID, q.Question),
+		Blocks: []Block{mrkdwn(header)},
+	}
+
+	var buttons []Element
+	if !q.MultiSelect {
+		for _, opt := range q.Options {
+			buttons = append(buttons, Element{
+				Type:     "button",
+				ActionID: answerActionPrefix + opt.ID,
+				Text:     &Text{Type: "plain_text", Text: truncate(opt.Label, 75)},
+				Value:    AnswerValue(q.ID, opt.ID),
+			})
+		}
+	}
+	if hubURL != "" {
+		buttons = append(buttons, Element{
+			Type:     "button",
+			ActionID: "open",
+			Text:     &Text{Type: "plain_text", Text: "Open in vega-hub"},
+			URL:      hubURL + "/goals/" + q.GoalID,
+		})
+	}
+	if len(buttons) > 0 {
+		msg.Blocks = append(msg.Blocks, Block{Type: "actions", BlockID: "question:" + q.ID, Elements: buttons})
+	}
+	if len(q.Options) == 0 || q.MultiSelect {
+		msg.Blocks = append(msg.Blocks, mrkdwn(fmt.Sprintf("_Answer in vega-hub or with_ `/vega answer %s <text>`", q.ID)))
+	}
+	return msg
+}
+
+// AnsweredMessage replaces a question message once it has been answered
+func AnsweredMessage(q *hub.Question, user, answer string) Message {
+	return Message{
+		ReplaceOriginal: true,
+		Text:            fmt.Sprintf("Answered by %s: %s", user, answer),
+		Blocks: []Block{
+			mrkdwn(fmt.Sprintf("*Goal %s* asked:\n%s", escape(q.GoalID), escape(q.Question))),
+			mrkdwn(fmt.Sprintf(":white_check_mark: Answered by %s: *%s*", escape(user), escape(answer))),
+		},
+	}
+}
+
+// EphemeralMessage is a reply only the requesting user sees
+func EphemeralMessage(text string) Message {
+	return Message{ResponseType: "ephemeral", Text: text}
+}
+
+// QuestionList renders pending questions for the /vega slash command
+func QuestionList(questions []*hub.Question) Message {
+	if len(questions) == 0 {
+		return EphemeralMessage("No pending questions.")
+	}
+	var lines []string
+	for _, q := range questions {
+		line := fmt.Sprintf("• `%s` goal %s: %s", q.ID, escape(q.GoalID), escape(truncate(q.Question, 200)))
+		if len(q.Options) > 0 {
+			labels := make([]string, len(q.Options))
+			for i, opt := range q.Options {
+				labels[i] = escape(opt.Label)
+			}
+			line += "\n    _" + strings.Join(labels, " / ") + "_"
+		}
+		lines = append(lines, line)
+	}
+	msg := EphemeralMessage(fmt.Sprintf("%d pending question(s)", len(questions)))
+	msg.Blocks = []Block{mrkdwn(strings.Join(lines, "\n"))}
+	return msg
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
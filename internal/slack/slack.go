@@ -0,0 +1,189 @@
+// Package slack lets a team triage vega-hub questions from Slack: pending
+// questions are posted to a channel with a button per option, and clicks
+// (interactivity) and the /vega slash command call back into the hub.
+//
+// Configuration comes from the environment:
+//
+//	VEGA_HUB_SLACK_SIGNING_SECRET  verifies inbound requests (required for the endpoints)
+//	VEGA_HUB_SLACK_WEBHOOK_URL     incoming webhook questions are posted to
+//	VEGA_HUB_URL                   public hub URL, linked from messages
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRequestAge is how old a signed request may be before it's rejected as a replay
+const maxRequestAge = 5 * time.Minute
+
+// Config holds the Slack integration settings
+type Config struct {
+	SigningSecret string
+	WebhookURL    string
+	HubURL        string
+}
+
+// ConfigFromEnv reads the Slack settings from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		SigningSecret: os.Getenv("VEGA_HUB_SLACK_SIGNING_SECRET"),
+		WebhookURL:    os.Getenv("VEGA_HUB_SLACK_WEBHOOK_URL"),
+		HubURL:        strings.TrimSuffix(os.Getenv("VEGA_HUB_URL"), "/"),
+	}
+}
+
+// Enabled returns true if inbound Slack requests can be verified
+func (c Config) Enabled() bool {
+	return c.SigningSecret != ""
+}
+
+// Errors returned by Verify
+var (
+	ErrMissingSignature = errors.New("missing Slack signature headers")
+	ErrStaleRequest     = errors.New("Slack request timestamp too old")
+	ErrBadSignature     = errors.New("invalid Slack signature")
+)
+
+// Verify checks a request's X-Slack-Signature against its body, as described
+// in Slack's "Verifying requests from Slack" guide
+func Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrMissingSignature
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return ErrStaleRequest
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// Sign returns the X-Slack-Signature value for a body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Message is a Slack message with Block Kit blocks
+type Message struct {
+	Text            string  `json:"text"` // Fallback for notifications
+	Blocks          []Block `json:"blocks,omitempty"`
+	ResponseType    string  `json:"response_type,omitempty"` // "ephemeral" or "in_channel" (slash commands)
+	ReplaceOriginal bool    `json:"replace_original,omitempty"`
+}
+
+// Block is a Block Kit layout block
+type Block struct {
+	Type     string    `json:"type"`
+	BlockID  string    `json:"block_id,omitempty"`
+	Text     *Text     `json:"text,omitempty"`
+	Elements []Element `json:"elements,omitempty"`
+}
+
+// Text is a Block Kit text object
+type Text struct {
+	Type string `json:"type"` // "mrkdwn" or "plain_text"
+	Text string `json:"text"`
+}
+
+// Element is a Block Kit block element (buttons only)
+type Element struct {
+	Type     string `json:"type"`
+	ActionID string `json:"action_id,omitempty"`
+	Text     *Text  `json:"text,omitempty"`
+	Value    string `json:"value,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// InteractionPayload is the part of a block_actions payload the hub uses
+type InteractionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		BlockID  string `json:"block_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// UserName returns the Slack handle to record as the answering user
+func (p *InteractionPayload) UserName() string {
+	name := p.User.Username
+	if name == "" {
+		name = p.User.Name
+	}
+	if name == "" {
+		name = p.User.ID
+	}
+	return "slack:" + name
+}
+
+// answerActionPrefix marks buttons that answer a question; the value is
+// "<question id>:<option id>"
+const answerActionPrefix = "answer:"
+
+// AnswerValue encodes a question and option in a button value
+func AnswerValue(questionID, optionID string) string {
+	return questionID + ":" + optionID
+}
+
+// ParseAnswerAction returns the question and option a clicked button answers
+func ParseAnswerAction(actionID, value string) (questionID, optionID string, ok bool) {
+	if !strings.HasPrefix(actionID, answerActionPrefix) {
+		return "", "", false
+	}
+	questionID, optionID, ok = strings.Cut(value, ":")
+	return questionID, optionID, ok && questionID != "" && optionID != ""
+}
+
+// Post sends a message to an incoming webhook or response_url
+func Post(client *http.Client, url string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	return nil
+}
+
+// mrkdwn returns a markdown section block
+func mrkdwn(text string) Block {
+	return Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: text}}
+}
+
+// escape escapes the characters Slack treats as markup
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,87 @@
+package slack
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+func signedHeader(secret string, ts time.Time, body []byte) http.Header {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", Sign(secret, timestamp, body))
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte("payload=%7B%7D")
+
+	if err := Verify("secret", signedHeader("secret", now, body), body, now); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := Verify("secret", signedHeader("other", now, body), body, now); err != ErrBadSignature {
+		t.Errorf("expected ErrBadSignature, got %v", err)
+	}
+	if err := Verify("secret", signedHeader("secret", now, body), []byte("tampered"), now); err != ErrBadSignature {
+		t.Errorf("expected ErrBadSignature for a modified body, got %v", err)
+	}
+	old := now.Add(-10 * time.Minute)
+	if err := Verify("secret", signedHeader("secret", old, body), body, now); err != ErrStaleRequest {
+		t.Errorf("expected ErrStaleRequest, got %v", err)
+	}
+	if err := Verify("secret", http.Header{}, body, now); err != ErrMissingSignature {
+		t.Errorf("expected ErrMissingSignature, got %v", err)
+	}
+}
+
+func TestParseAnswerAction(t *testing.T) {
+	q, opt, ok := ParseAnswerAction("answer:opt-2", AnswerValue("q123", "opt-2"))
+	if !ok || q != "q123" || opt != "opt-2" {
+		t.Errorf("ParseAnswerAction = %q, %q, %v", q, opt, ok)
+	}
+	if _, _, ok := ParseAnswerAction("open", "q123:opt-2"); ok {
+		t.Error("expected non-answer actions to be ignored")
+	}
+	if _, _, ok := ParseAnswerAction("answer:x", "malformed"); ok {
+		t.Error("expected malformed values to be rejected")
+	}
+}
+
+func TestQuestionMessage(t *testing.T) {
+	q := &hub.Question{
+		ID:       "q1",
+		GoalID:   "abc1234",
+		Question: "Use <Postgres> or SQLite?",
+		Options:  []hub.Option{{ID: "o1", Label: "Postgres"}, {ID: "o2", Label: "SQLite"}},
+	}
+	msg := QuestionMessage(q, "https://hub.example.com")
+
+	if !strings.Contains(msg.Blocks[0].Text.Text, "&lt;Postgres&gt;") {
+		t.Errorf("expected question text to be escaped, got %q", msg.Blocks[0].Text.Text)
+	}
+	actions := msg.Blocks[1]
+	if actions.Type != "actions" || len(actions.Elements) != 3 {
+		t.Fatalf("expected 2 option buttons and a link, got %+v", actions)
+	}
+	if actions.Elements[1].Value != "q1:o2" || actions.Elements[1].ActionID != "answer:o2" {
+		t.Errorf("unexpected option button: %+v", actions.Elements[1])
+	}
+	if actions.Elements[2].URL != "https://hub.example.com/goals/abc1234" {
+		t.Errorf("unexpected link: %+v", actions.Elements[2])
+	}
+
+	// Multi-select questions can't be answered with one click
+	q.MultiSelect = true
+	msg = QuestionMessage(q, "")
+	for _, b := range msg.Blocks {
+		if b.Type == "actions" {
+			t.Errorf("expected no buttons for a multi-select question, got %+v", b)
+		}
+	}
+}
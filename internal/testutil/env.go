@@ -0,0 +1,157 @@
+// Package testutil sets up end-to-end test environments: a temporary vega
+// dir served over HTTP, local bare git repos standing in for remotes, and a
+// scripted fake executor that talks to the hub like a real one.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lasmarois/vega-hub/internal/goals"
+	"github.com/lasmarois/vega-hub/internal/hub"
+)
+
+// WaitTimeout bounds how long WaitFor polls
+var WaitTimeout = 10 * time.Second
+
+// Env is a temporary vega-missile directory with a hub and parser on it
+type Env struct {
+	t      *testing.T
+	Dir    string
+	Hub    *hub.Hub
+	Parser *goals.Parser
+	URL    string // Base URL of the API once Serve has been called
+}
+
+// NewEnv creates an empty vega dir (goal folders, projects, workspaces)
+// and a hub managing it
+func NewEnv(t *testing.T) *Env {
+	t.Helper()
+	dir := t.TempDir()
+	for _, sub := range []string{"goals/active", "goals/iced", "goals/history", "projects", "workspaces"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", sub, err)
+		}
+	}
+	return &Env{t: t, Dir: dir, Hub: hub.New(dir), Parser: goals.NewParser(dir)}
+}
+
+// Serve starts an HTTP server with the routes set up by register (usually
+// api.RegisterRoutes) and tells the hub its port, so spawned executors can
+// reach it. The server is closed when the test ends.
+func (e *Env) Serve(register func(*http.ServeMux, *hub.Hub, *goals.Parser)) string {
+	e.t.Helper()
+	mux := http.NewServeMux()
+	register(mux, e.Hub, e.Parser)
+	server := httptest.NewServer(mux)
+	e.t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	e.Hub.SetPort(port)
+	e.URL = server.URL
+	return e.URL
+}
+
+// Do sends a JSON request to the API and decodes the response into out when
+// it's not nil. Returns the status code.
+func (e *Env) Do(method, path string, body, out interface{}) int {
+	e.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			e.t.Fatalf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, e.URL+path, reader)
+	if err != nil {
+		e.t.Fatalf("failed to build request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		e.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil && resp.StatusCode < 400 {
+			e.t.Fatalf("%s %s: failed to decode response: %v\n%s", method, path, err, data)
+		}
+	}
+	return resp.StatusCode
+}
+
+// WaitFor polls cond until it returns true, failing the test after WaitTimeout
+func (e *Env) WaitFor(what string, cond func() bool) {
+	e.t.Helper()
+	deadline := time.Now().Add(WaitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			e.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// Git runs git in dir and returns its trimmed output, failing the test on error
+func (e *Env) Git(dir string, args ...string) string {
+	e.t.Helper()
+	out, err := runGit(dir, args...)
+	if err != nil {
+		e.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(out)
+}
+
+// NewRemote creates a bare repository standing in for a project's remote,
+// with one commit on main adding files (path → content). Returns its path,
+// which git accepts as a clone URL.
+func (e *Env) NewRemote(name string, files map[string]string) string {
+	e.t.Helper()
+	root := filepath.Join(e.t.TempDir(), name)
+	seed := root + "-seed"
+	remote := root + ".git"
+
+	if err := os.MkdirAll(seed, 0755); err != nil {
+		e.t.Fatalf("failed to create seed repo: %v", err)
+	}
+	e.Git(seed, "init", "-b", "main")
+	for path, content := range files {
+		full := filepath.Join(seed, path)
+		os.MkdirAll(filepath.Dir(full), 0755)
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			e.t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	e.Git(seed, "add", "-A")
+	e.Git(seed, "commit", "-m", "Initial commit")
+	e.Git("", "clone", "--bare", seed, remote)
+	return remote
+}
+
+// runGit runs git with a fixed identity so commits work without user config
+func runGit(dir string, args ...string) (string, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	args = append([]string{"-c", "user.name=Vega Test", "-c", "user.email=test@vega.local"}, args...)
+	out, err := exec.Command("git", args...).CombinedOutput()
+	return string(out), err
+}
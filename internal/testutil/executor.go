@@ -0,0 +1,209 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fakeExecutorEnv points a test binary started as "claude" at its script
+const fakeExecutorEnv = "VEGA_FAKE_EXECUTOR_SCRIPT"
+
+// ExecutorStep is one action of a scripted fake executor. Exactly one of
+// Ask, Write, Commit, Print, Sleep or Exit is set.
+type ExecutorStep struct {
+	Ask     string   `json:"ask,omitempty"`     // Ask the hub and wait for the answer
+	Options []string `json:"options,omitempty"` // Option labels offered with Ask
+	Write   string   `json:"write,omitempty"`   // File to write, relative to the working dir
+	Content string   `json:"content,omitempty"` // Content for Write; {{answer}} is replaced by the last answer
+	Commit  string   `json:"commit,omitempty"`  // Commit every change with this message
+	Print   string   `json:"print,omitempty"`   // Line written to stdout (the executor output log)
+	Sleep   string   `json:"sleep,omitempty"`   // Go duration to pause for
+	Exit    int      `json:"exit,omitempty"`    // Exit code; ends the script
+}
+
+// ExecutorScript is what a fake executor does, in order
+type ExecutorScript struct {
+	// Register and stop through /api/executor/register and /api/executor/stop,
+	// like executors started outside vega-hub whose hooks report them.
+	// Spawned executors are tracked by the hub itself.
+	Register bool           `json:"register,omitempty"`
+	Steps    []ExecutorStep `json:"steps"`
+}
+
+// InstallFakeExecutor puts a "claude" on PATH that runs script in place of
+// Claude, so SpawnExecutor starts it like a real executor. The test package
+// must call RunFakeExecutor from TestMain.
+func (e *Env) InstallFakeExecutor(script ExecutorScript) {
+	e.t.Helper()
+	self, err := os.Executable()
+	if err != nil {
+		e.t.Fatalf("failed to find test binary: %v", err)
+	}
+	bin := e.t.TempDir()
+	data, _ := json.Marshal(script)
+	scriptPath := filepath.Join(bin, "script.json")
+	if err := os.WriteFile(scriptPath, data, 0644); err != nil {
+		e.t.Fatalf("failed to write executor script: %v", err)
+	}
+	launcher := fmt.Sprintf("#!/bin/sh\n%s=%q exec %q\n", fakeExecutorEnv, scriptPath, self)
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(launcher), 0755); err != nil {
+		e.t.Fatalf("failed to write fake claude: %v", err)
+	}
+	e.t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// RunFakeExecutor runs the fake executor and exits when the test binary was
+// started by InstallFakeExecutor's launcher; otherwise it returns at once.
+// Call it first thing in TestMain.
+func RunFakeExecutor() {
+	scriptPath := os.Getenv(fakeExecutorEnv)
+	if scriptPath == "" {
+		return
+	}
+	var script ExecutorScript
+	data, err := os.ReadFile(scriptPath)
+	if err == nil {
+		err = json.Unmarshal(data, &script)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fake executor: %v\n", err)
+		os.Exit(2)
+	}
+
+	dir, _ := os.Getwd()
+	hubURL := "http://127.0.0.1:" + os.Getenv("VEGA_HUB_PORT")
+	goalID := os.Getenv("VEGA_GOAL_ID")
+	// Like the real hooks, report as the session vega-hub spawned
+	sessionID := os.Getenv("VEGA_SESSION_ID")
+	if sessionID == "" {
+		sessionID = fmt.Sprintf("fake-%d", os.Getpid())
+	}
+	code, err := RunExecutor(hubURL, goalID, sessionID, dir, script, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fake executor: %v\n", err)
+		os.Exit(2)
+	}
+	os.Exit(code)
+}
+
+// RunExecutor plays script against the hub at hubURL as the given session,
+// working in dir and printing to out. Returns the script's exit code.
+func RunExecutor(hubURL, goalID, sessionID, dir string, script ExecutorScript, out io.Writer) (int, error) {
+	if script.Register {
+		if err := post(hubURL+"/api/executor/register", map[string]string{
+			"goal_id": goalID, "session_id": sessionID, "cwd": dir,
+		}, nil); err != nil {
+			return 0, fmt.Errorf("register: %w", err)
+		}
+	}
+
+	code, err := runSteps(hubURL, goalID, sessionID, dir, script.Steps, out)
+
+	if script.Register {
+		reason := "completed"
+		if err != nil || code != 0 {
+			reason = "error"
+		}
+		if stopErr := post(hubURL+"/api/executor/stop", map[string]string{
+			"goal_id": goalID, "session_id": sessionID, "reason": reason,
+		}, nil); stopErr != nil && err == nil {
+			err = fmt.Errorf("stop: %w", stopErr)
+		}
+	}
+	return code, err
+}
+
+func runSteps(hubURL, goalID, sessionID, dir string, steps []ExecutorStep, out io.Writer) (int, error) {
+	var answer string
+	for i, step := range steps {
+		switch {
+		case step.Ask != "":
+			options := make([]map[string]string, 0, len(step.Options))
+			for _, label := range step.Options {
+				options = append(options, map[string]string{"label": label})
+			}
+			var resp struct {
+				Answer string `json:"answer"`
+			}
+			if err := post(hubURL+"/api/ask", map[string]interface{}{
+				"goal_id":    goalID,
+				"session_id": sessionID,
+				"question":   step.Ask,
+				"options":    options,
+			}, &resp); err != nil {
+				return 0, fmt.Errorf("step %d: ask: %w", i+1, err)
+			}
+			answer = resp.Answer
+			fmt.Fprintf(out, "answer: %s\n", answer)
+
+		case step.Write != "":
+			path := filepath.Join(dir, step.Write)
+			content := strings.ReplaceAll(step.Content, "{{answer}}", answer)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return 0, fmt.Errorf("step %d: %w", i+1, err)
+			}
+
+		case step.Commit != "":
+			// Like a careful executor, leave vega-hub's output log out of the commit
+			if out, err := runGit(dir, "add", "-A", "--", ".", ":!.executor-output.log"); err != nil {
+				return 0, fmt.Errorf("step %d: git add: %v\n%s", i+1, err, out)
+			}
+			if out, err := runGit(dir, "commit", "-m", step.Commit); err != nil {
+				return 0, fmt.Errorf("step %d: git commit: %v\n%s", i+1, err, out)
+			}
+
+		case step.Print != "":
+			fmt.Fprintln(out, step.Print)
+
+		case step.Sleep != "":
+			d, err := time.ParseDuration(step.Sleep)
+			if err != nil {
+				return 0, fmt.Errorf("step %d: %w", i+1, err)
+			}
+			time.Sleep(d)
+
+		default:
+			return step.Exit, nil
+		}
+	}
+	return 0, nil
+}
+
+// post sends body as JSON, decoding the response into out when it's not nil.
+// There is no timeout: asks block until the question is answered.
+func post(url string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Like the real hooks, send the token vega-hub issued to the executor
+	if token := os.Getenv("VEGA_HUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
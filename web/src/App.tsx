@@ -7,7 +7,9 @@ import { useSSE } from '@/hooks/useSSE'
 import { useGoals } from '@/hooks/useGoals'
 import { useActivity } from '@/hooks/useActivity'
 import { useUser } from '@/hooks/useUser'
+import { useReadiness } from '@/hooks/useReadiness'
 import { toast } from '@/hooks/useToast'
+import type { ExecutorProgress, GoalSummary } from '@/lib/types'
 import { GoalSheet } from '@/components/goals/GoalSheet'
 import { ProjectSheet } from '@/components/projects/ProjectSheet'
 import { CommandPalette } from '@/components/shared/CommandPalette'
@@ -23,6 +25,8 @@ function AppContent() {
     fetchGoals,
     fetchGoalDetail,
     fetchGoalStatus,
+    patchGoal,
+    setGoalProgress,
     clearSelectedGoal,
   } = useGoals()
 
@@ -69,19 +73,18 @@ function AppContent() {
         </button>
       ),
     })
-    fetchGoals()
+    // The goal list is patched by the goal_updated event that follows
     if (selectedGoal) {
       fetchGoalDetail(selectedGoal.id)
     }
-  }, [recordQuestion, fetchGoals, selectedGoal, fetchGoalDetail])
+  }, [recordQuestion, selectedGoal, fetchGoalDetail])
 
   const handleAnswered = useCallback(() => {
     recordAnswered()
-    fetchGoals()
     if (selectedGoal) {
       fetchGoalDetail(selectedGoal.id)
     }
-  }, [recordAnswered, fetchGoals, selectedGoal, fetchGoalDetail])
+  }, [recordAnswered, selectedGoal, fetchGoalDetail])
 
   const handleExecutorStarted = useCallback((data: { goal_id: string; session_id: string }) => {
     recordExecutorStarted(data.goal_id, data.session_id)
@@ -90,11 +93,10 @@ function AppContent() {
       description: `Goal #${data.goal_id} is now running`,
       variant: 'success',
     })
-    fetchGoals()
     if (selectedGoal) {
       fetchGoalDetail(selectedGoal.id)
     }
-  }, [recordExecutorStarted, fetchGoals, selectedGoal, fetchGoalDetail])
+  }, [recordExecutorStarted, selectedGoal, fetchGoalDetail])
 
   const handleExecutorStopped = useCallback((data: { goal_id: string; session_id: string }) => {
     recordExecutorStopped(data.goal_id, data.session_id)
@@ -102,19 +104,33 @@ function AppContent() {
       title: 'Executor Stopped',
       description: `Goal #${data.goal_id} has stopped`,
     })
-    fetchGoals()
     if (selectedGoal) {
       fetchGoalDetail(selectedGoal.id)
     }
-  }, [recordExecutorStopped, fetchGoals, selectedGoal, fetchGoalDetail])
+  }, [recordExecutorStopped, selectedGoal, fetchGoalDetail])
 
-  const handleGoalUpdated = useCallback((data: { goal_id: string }) => {
-    recordGoalUpdated(data.goal_id)
-    fetchGoals()
+  const handleGoalUpdated = useCallback((data: { goal_id: string; cause?: string; goal?: GoalSummary }) => {
+    // Executor and question changes have their own events and handlers
+    const edited = !data.cause || data.cause === 'file_changed' || data.cause === 'updated'
+    if (edited) {
+      recordGoalUpdated(data.goal_id)
+    }
+    if (data.goal) {
+      patchGoal(data.goal)
+    } else {
+      fetchGoals()
+    }
+    if (edited && selectedGoal && data.goal_id === selectedGoal.id) {
+      fetchGoalDetail(selectedGoal.id)
+    }
+  }, [recordGoalUpdated, patchGoal, fetchGoals, selectedGoal, fetchGoalDetail])
+
+  const handleExecutorProgress = useCallback((data: { goal_id: string; progress: ExecutorProgress | null }) => {
+    setGoalProgress(data.goal_id, data.progress)
     if (selectedGoal && data.goal_id === selectedGoal.id) {
       fetchGoalDetail(selectedGoal.id)
     }
-  }, [recordGoalUpdated, fetchGoals, selectedGoal, fetchGoalDetail])
+  }, [setGoalProgress, selectedGoal, fetchGoalDetail])
 
   const handleGoalIced = useCallback((data: { goal_id: string }) => {
     recordGoalIced(data.goal_id)
@@ -169,6 +185,7 @@ function AppContent() {
     onAnswered: handleAnswered,
     onExecutorStarted: handleExecutorStarted,
     onExecutorStopped: handleExecutorStopped,
+    onExecutorProgress: handleExecutorProgress,
     onGoalUpdated: handleGoalUpdated,
     onGoalIced: handleGoalIced,
     onGoalCompleted: handleGoalCompleted,
@@ -176,6 +193,7 @@ function AppContent() {
     onPlanningFileReceived: handlePlanningFileReceived,
     onPhaseUpdated: handlePhaseUpdated,
   })
+  const { readiness } = useReadiness(connected)
 
   // Fetch goals on mount
   useEffect(() => {
@@ -232,6 +250,7 @@ function AppContent() {
         <Route element={
           <Layout
             connected={connected}
+            readiness={readiness}
             pendingQuestions={totalPendingQuestions}
             activities={activities}
             unreadCount={unreadCount}
@@ -15,10 +15,12 @@ import {
   Clock,
   ChevronDown,
   ChevronUp,
+  Paperclip,
+  X,
 } from 'lucide-react'
 import { cn } from '@/lib/utils'
 import { useSSE } from '@/hooks/useSSE'
-import type { ChatMessage, Question } from '@/lib/types'
+import type { Attachment, ChatMessage, Question } from '@/lib/types'
 
 interface ChatThreadProps {
   goalId: string
@@ -32,6 +34,8 @@ export function ChatThread({ goalId, pendingQuestions, onAnswerSubmit }: ChatThr
   const [answerText, setAnswerText] = useState<Record<string, string>>({})
   const [userMessage, setUserMessage] = useState('')
   const [sendingMessage, setSendingMessage] = useState(false)
+  const [files, setFiles] = useState<File[]>([])
+  const fileInputRef = useRef<HTMLInputElement>(null)
   const [historyMode, setHistoryMode] = useState<'session' | 'all'>('all')
   const [currentSessionId, setCurrentSessionId] = useState<string | null>(null)
   const [executorRunning, setExecutorRunning] = useState(false)
@@ -109,6 +113,11 @@ export function ChatThread({ goalId, pendingQuestions, onAnswerSubmit }: ChatThr
         fetchChat()
       }
     },
+    onUserMessageStatus: (data) => {
+      if (data.goal_id === goalId) {
+        fetchChat()
+      }
+    },
   })
 
   // Auto-scroll to bottom when new messages arrive
@@ -120,16 +129,30 @@ export function ChatThread({ goalId, pendingQuestions, onAnswerSubmit }: ChatThr
 
   // Send user message
   const handleSendMessage = async () => {
-    if (!userMessage.trim()) return
+    if (!userMessage.trim() && files.length === 0) return
     setSendingMessage(true)
     try {
+      // Upload files first, then reference them by id
+      let attachments: Attachment[] = []
+      if (files.length > 0) {
+        const form = new FormData()
+        files.forEach((f) => form.append('file', f))
+        const uploadRes = await fetch(`/api/goals/${goalId}/attachments`, { method: 'POST', body: form })
+        if (!uploadRes.ok) {
+          console.error('Failed to upload attachments:', await uploadRes.text())
+          return
+        }
+        const uploaded: Attachment[] = await uploadRes.json()
+        attachments = uploaded.map((a) => ({ type: 'file', id: a.id }))
+      }
       const res = await fetch(`/api/goals/${goalId}/messages`, {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ content: userMessage }),
+        body: JSON.stringify({ content: userMessage, attachments }),
       })
       if (res.ok) {
         setUserMessage('')
+        setFiles([])
         // Refetch chat to show the new message
         const chatRes = await fetch(`/api/goals/${goalId}/chat`)
         if (chatRes.ok) {
@@ -180,7 +203,7 @@ export function ChatThread({ goalId, pendingQuestions, onAnswerSubmit }: ChatThr
         <UserMessageBubble
           key={msg.id}
           message={msg}
-          delivered={msg.type === 'user_message_delivered'}
+          delivery={msg.delivery ?? (msg.type === 'user_message_delivered' ? 'delivered' : 'pending')}
         />
       )
     }
@@ -235,7 +258,38 @@ export function ChatThread({ goalId, pendingQuestions, onAnswerSubmit }: ChatThr
 
       {/* Message input */}
       <div className="pt-3 border-t">
+        {files.length > 0 && (
+          <div className="flex flex-wrap gap-1 mb-2">
+            {files.map((f, i) => (
+              <Badge key={i} variant="secondary" className="text-xs gap-1">
+                {f.name}
+                <button onClick={() => setFiles(files.filter((_, j) => j !== i))}>
+                  <X className="h-3 w-3" />
+                </button>
+              </Badge>
+            ))}
+          </div>
+        )}
         <div className="flex gap-2">
+          <input
+            ref={fileInputRef}
+            type="file"
+            multiple
+            className="hidden"
+            onChange={(e) => {
+              setFiles([...files, ...Array.from(e.target.files ?? [])])
+              e.target.value = ''
+            }}
+          />
+          <Button
+            size="icon"
+            variant="ghost"
+            onClick={() => fileInputRef.current?.click()}
+            disabled={sendingMessage}
+            title="Attach files"
+          >
+            <Paperclip className="h-4 w-4" />
+          </Button>
           <Input
             placeholder={executorRunning
               ? "Send a message to the executor..."
@@ -255,7 +309,7 @@ export function ChatThread({ goalId, pendingQuestions, onAnswerSubmit }: ChatThr
           <Button
             size="icon"
             onClick={handleSendMessage}
-            disabled={!userMessage.trim() || sendingMessage}
+            disabled={(!userMessage.trim() && files.length === 0) || sendingMessage}
             variant={executorRunning ? 'default' : 'outline'}
           >
             <Send className="h-4 w-4" />
@@ -333,7 +387,9 @@ function QuestionBubble({ message, isPending }: { message: ChatMessage; isPendin
             <p className="text-sm">{message.content}</p>
             {message.answer && (
               <div className="mt-2 pt-2 border-t">
-                <p className="text-xs text-muted-foreground">Answer:</p>
+                <p className="text-xs text-muted-foreground">
+                  {message.answered_by ? `Answered by ${message.answered_by}:` : 'Answer:'}
+                </p>
                 <p className="text-sm">{message.answer}</p>
               </div>
             )}
@@ -354,7 +410,16 @@ function QuestionBubble({ message, isPending }: { message: ChatMessage; isPendin
 }
 
 // User message bubble (to executor)
-function UserMessageBubble({ message, delivered }: { message: ChatMessage; delivered?: boolean }) {
+const deliveryBadges: Record<NonNullable<ChatMessage['delivery']>, { label: string; className: string }> = {
+  pending: { label: 'Queued', className: 'text-amber-600' },
+  delivered: { label: 'Delivered', className: 'text-green-600' },
+  processed: { label: 'Read', className: 'text-green-600' },
+  undelivered: { label: 'Not delivered — executor stopped', className: 'text-red-600' },
+}
+
+function UserMessageBubble({ message, delivery }: { message: ChatMessage; delivery: NonNullable<ChatMessage['delivery']> }) {
+  const badge = deliveryBadges[delivery]
+  const delivered = delivery === 'delivered' || delivery === 'processed'
   return (
     <div className="flex gap-2 max-w-[85%] ml-auto flex-row-reverse">
       <div className="w-6 h-6 rounded-full bg-blue-500/10 flex items-center justify-center shrink-0">
@@ -367,18 +432,13 @@ function UserMessageBubble({ message, delivered }: { message: ChatMessage; deliv
         )}>
           <CardContent className="p-3">
             <p className="text-sm">{message.content}</p>
+            <AttachmentList attachments={(message.data?.attachments as Attachment[] | undefined) ?? []} />
           </CardContent>
         </Card>
         <div className="flex items-center gap-2 text-xs text-muted-foreground px-1 justify-end">
-          {delivered ? (
-            <Badge variant="outline" className="text-xs h-4 px-1 text-green-600">
-              Delivered
-            </Badge>
-          ) : (
-            <Badge variant="outline" className="text-xs h-4 px-1 text-amber-600">
-              Queued
-            </Badge>
-          )}
+          <Badge variant="outline" className={cn("text-xs h-4 px-1", badge.className)}>
+            {badge.label}
+          </Badge>
           <Clock className="h-3 w-3" />
           {new Date(message.timestamp).toLocaleTimeString()}
         </div>
@@ -387,6 +447,28 @@ function UserMessageBubble({ message, delivered }: { message: ChatMessage; deliv
   )
 }
 
+// Uploaded files of a message: images inline, other files as download links
+function AttachmentList({ attachments }: { attachments: Attachment[] }) {
+  const files = attachments.filter((a) => a.type === 'file' && a.url)
+  if (files.length === 0) return null
+  return (
+    <div className="mt-2 space-y-1">
+      {files.map((a) =>
+        a.content_type?.startsWith('image/') && a.content_type !== 'image/svg+xml' ? (
+          <a key={a.id} href={a.url} target="_blank" rel="noreferrer">
+            <img src={a.url} alt={a.name} className="max-h-48 rounded border" />
+          </a>
+        ) : (
+          <a key={a.id} href={a.url} className="flex items-center gap-1 text-xs underline">
+            <Paperclip className="h-3 w-3" />
+            {a.name}
+          </a>
+        )
+      )}
+    </div>
+  )
+}
+
 // Activity message (collapsible)
 function ActivityMessage({ message }: { message: ChatMessage }) {
   const [expanded, setExpanded] = useState(false)
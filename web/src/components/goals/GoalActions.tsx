@@ -521,7 +521,6 @@ export function StopExecutorDialog({
   sessionId,
   onSuccess,
 }: StopExecutorDialogProps) {
-  const [reason, setReason] = useState('')
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState<string | null>(null)
 
@@ -530,23 +529,15 @@ export function StopExecutorDialog({
     setError(null)
 
     try {
-      const res = await fetch('/api/executor/stop', {
+      const res = await fetch(`/api/goals/${goalId}/executors/${sessionId}/kill`, {
         method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({
-          goal_id: goalId,
-          session_id: sessionId,
-          reason: reason.trim() || 'Stopped by user',
-        }),
       })
 
-      const data = await res.json()
-      if (data.ok) {
+      if (res.ok) {
         onSuccess()
         onOpenChange(false)
-        setReason('')
       } else {
-        setError('Failed to stop executor')
+        setError((await res.text()).trim() || 'Failed to stop executor')
       }
     } catch (err) {
       setError('Network error')
@@ -564,20 +555,11 @@ export function StopExecutorDialog({
             Stop Executor
           </DialogTitle>
           <DialogDescription>
-            This will stop the running executor for goal #{goalId}.
+            This will terminate the running executor for goal #{goalId}.
           </DialogDescription>
         </DialogHeader>
 
         <div className="space-y-4 py-4">
-          <div className="space-y-2">
-            <label className="text-sm font-medium">Reason (optional)</label>
-            <Input
-              placeholder="Why are you stopping the executor?"
-              value={reason}
-              onChange={(e) => setReason(e.target.value)}
-            />
-          </div>
-
           {error && (
             <div className="text-sm text-red-500 flex items-center gap-2">
               <AlertTriangle className="h-4 w-4" />
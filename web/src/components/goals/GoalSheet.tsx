@@ -205,6 +205,38 @@ export function GoalSheet({ open, onOpenChange, goal, goalStatus, onRefresh }: G
   const [isMetaExecutor, setIsMetaExecutor] = useState(false)
   const [hasNewPlanningFiles, setHasNewPlanningFiles] = useState(false)
   const [spawningMeta, setSpawningMeta] = useState(false)
+  const [watchers, setWatchers] = useState<string[]>([])
+
+  // Tell others we have this goal open, and follow who else does
+  useEffect(() => {
+    if (!open || !goal) return
+    const goalId = goal.id
+    const setWatching = (id: string) =>
+      fetch('/api/presence', {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ goal_id: id }),
+      }).catch(() => {})
+    const fetchWatchers = async () => {
+      try {
+        const res = await fetch(`/api/presence?goal_id=${encodeURIComponent(goalId)}`)
+        if (res.ok) {
+          const data: { user: string }[] = await res.json()
+          setWatchers(data.map((p) => p.user))
+        }
+      } catch {
+        // Presence is informational only
+      }
+    }
+
+    setWatchers(goal.watchers ?? [])
+    setWatching(goalId).then(fetchWatchers)
+    window.addEventListener('presence_changed', fetchWatchers)
+    return () => {
+      window.removeEventListener('presence_changed', fetchWatchers)
+      setWatching('')
+    }
+  }, [open, goal?.id])
 
   // Listen for SSE planning_file_received events to show badge
   useEffect(() => {
@@ -348,6 +380,12 @@ export function GoalSheet({ open, onOpenChange, goal, goalStatus, onRefresh }: G
     )
   }
 
+  // Latest step any of the goal's executors reported
+  const currentStep = (goal.active_executors ?? [])
+    .map((e) => e.progress)
+    .filter((p) => !!p)
+    .sort((a, b) => b!.at.localeCompare(a!.at))[0]?.status
+
   return (
     <Sheet open={open} onOpenChange={onOpenChange}>
       <SheetContent
@@ -405,9 +443,15 @@ export function GoalSheet({ open, onOpenChange, goal, goalStatus, onRefresh }: G
             </SheetDescription>
             <div className="flex items-center gap-3 text-xs text-muted-foreground mt-1">
               <span>Phase: {goal.phase}</span>
+              {currentStep && (
+                <span title="What the executor last reported">Doing: {currentStep}</span>
+              )}
               {goal.projects.length > 0 && (
                 <span>{goal.projects.join(', ')}</span>
               )}
+              {watchers.length > 1 && (
+                <span title="Users with this goal open">Viewing: {watchers.join(', ')}</span>
+              )}
             </div>
             {/* State Machine Badge */}
             {goal.state && (
@@ -1,14 +1,16 @@
 import { Outlet } from 'react-router-dom'
-import { WifiOff, User as UserIcon } from 'lucide-react'
+import { WifiOff, AlertTriangle, User as UserIcon } from 'lucide-react'
 import { Header } from './Header'
 import { Sidebar } from './Sidebar'
 import { BottomNav } from './BottomNav'
 import { NotificationCenter } from '@/components/shared/NotificationCenter'
 import type { ActivityWithRead } from '@/hooks/useActivity'
 import type { User } from '@/hooks/useUser'
+import type { ReadinessReport } from '@/lib/types'
 
 interface LayoutProps {
   connected: boolean
+  readiness?: ReadinessReport | null
   pendingQuestions: number
   activities: ActivityWithRead[]
   unreadCount: number
@@ -20,6 +22,7 @@ interface LayoutProps {
 
 export function Layout({
   connected,
+  readiness,
   pendingQuestions,
   activities,
   unreadCount,
@@ -38,6 +41,21 @@ export function Layout({
         </div>
       )}
 
+      {/* Degraded Banner - hub is up but a dependency is down */}
+      {connected && readiness && !readiness.ready && (
+        <div className="sticky top-0 z-50 bg-yellow-500 text-black px-4 py-2 flex items-center justify-center gap-2 text-sm">
+          <AlertTriangle className="h-4 w-4" />
+          <span>
+            vega-hub is running, but{' '}
+            {readiness.dependencies
+              .filter(d => d.status === 'down')
+              .map(d => d.error ? `${d.name} (${d.error})` : d.name)
+              .join(', ')}{' '}
+            unavailable
+          </span>
+        </div>
+      )}
+
       {/* Desktop Sidebar */}
       <Sidebar pendingQuestions={pendingQuestions} />
 
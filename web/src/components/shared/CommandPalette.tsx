@@ -87,7 +87,7 @@ export function CommandPalette({ goals, onGoalSelect }: CommandPaletteProps) {
                 >
                   <span className="flex h-2 w-2 rounded-full bg-destructive mr-2" />
                   <span className="flex-1 truncate">
-                    #{goal.id}: {goal.title}
+                    #{goal.id}{goal.alias ? ` (${goal.alias})` : ''}: {goal.title}
                   </span>
                   <span className="text-xs text-destructive">
                     {goal.pending_questions} question{goal.pending_questions > 1 ? 's' : ''}
@@ -115,7 +115,7 @@ export function CommandPalette({ goals, onGoalSelect }: CommandPaletteProps) {
                     'bg-muted-foreground'
                   }`} />
                   <span className="flex-1 truncate">
-                    #{goal.id}: {goal.title}
+                    #{goal.id}{goal.alias ? ` (${goal.alias})` : ''}: {goal.title}
                   </span>
                   <span className="text-xs text-muted-foreground">
                     {goal.executor_status}
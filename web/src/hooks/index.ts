@@ -4,4 +4,5 @@ export { useMobile } from './useMobile'
 export { useActivity } from './useActivity'
 export { useToast, toast } from './useToast'
 export { useUser } from './useUser'
+export { useReadiness } from './useReadiness'
 export type { User, CredentialStatus, CredentialValidation } from './useUser'
@@ -1,6 +1,6 @@
 import { useState, useCallback } from 'react'
 import { toast } from '@/hooks/useToast'
-import type { GoalSummary, GoalDetail, GoalStatus } from '@/lib/types'
+import type { GoalSummary, GoalDetail, GoalStatus, ExecutorProgress } from '@/lib/types'
 
 export function useGoals() {
   const [goals, setGoals] = useState<GoalSummary[]>([])
@@ -70,6 +70,16 @@ export function useGoals() {
     }
   }, [])
 
+  // Replace a goal's summary with the one a goal_updated event carries
+  const patchGoal = useCallback((goal: GoalSummary) => {
+    setGoals((prev) => prev.map((g) => (g.id === goal.id ? { ...g, ...goal } : g)))
+  }, [])
+
+  // Set the progress an executor of a goal reported (executor_progress events)
+  const setGoalProgress = useCallback((goalId: string, progress: ExecutorProgress | null) => {
+    setGoals((prev) => prev.map((g) => (g.id === goalId ? { ...g, executor_progress: progress ?? undefined } : g)))
+  }, [])
+
   const clearSelectedGoal = useCallback(() => {
     setSelectedGoal(null)
     setGoalStatus(null)
@@ -87,6 +97,8 @@ export function useGoals() {
     fetchGoals,
     fetchGoalDetail,
     fetchGoalStatus,
+    patchGoal,
+    setGoalProgress,
     clearSelectedGoal,
   }
 }
@@ -0,0 +1,32 @@
+import { useState, useEffect, useCallback } from 'react'
+import type { ReadinessReport } from '@/lib/types'
+
+const POLL_INTERVAL = 30000
+
+// Polls /api/health/ready while connected, so the UI can tell a hub that is
+// up but missing a dependency from one that is down
+export function useReadiness(connected: boolean) {
+  const [readiness, setReadiness] = useState<ReadinessReport | null>(null)
+
+  const fetchReadiness = useCallback(async () => {
+    try {
+      // 503 still carries the report
+      const res = await fetch('/api/health/ready')
+      setReadiness(await res.json())
+    } catch {
+      setReadiness(null)
+    }
+  }, [])
+
+  useEffect(() => {
+    if (!connected) {
+      setReadiness(null)
+      return
+    }
+    fetchReadiness()
+    const id = setInterval(fetchReadiness, POLL_INTERVAL)
+    return () => clearInterval(id)
+  }, [connected, fetchReadiness])
+
+  return { readiness, refetch: fetchReadiness }
+}
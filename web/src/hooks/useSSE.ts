@@ -1,18 +1,23 @@
 import { useEffect, useState, useRef, useCallback } from 'react'
 import { toast } from '@/hooks/useToast'
+import type { ExecutorProgress, GoalSummary } from '@/lib/types'
+import { isSnapshot } from '@/lib/snapshot'
 
 export interface SSEHandlers {
   onQuestion?: (data: { goal_id: string; question: string }) => void
   onAnswered?: (data: { id: string }) => void
   onExecutorStarted?: (data: { goal_id: string; session_id: string }) => void
   onExecutorStopped?: (data: { goal_id: string; session_id: string; reason?: string; output?: string }) => void
-  onGoalUpdated?: (data: { goal_id: string }) => void
+  onExecutorProgress?: (data: { goal_id: string; session_id: string; progress: ExecutorProgress | null }) => void
+  onGoalUpdated?: (data: { goal_id: string; cause?: string; goal?: GoalSummary }) => void
   onRegistryUpdated?: () => void
   onGoalIced?: (data: { goal_id: string }) => void
   onGoalCompleted?: (data: { goal_id: string }) => void
   onUserMessage?: (data: { goal_id: string; content: string; user: string }) => void
+  onUserMessageStatus?: (data: { goal_id: string; message_id: string; status: string; session_id?: string }) => void
   onPlanningFileReceived?: (data: { goal_id: string; project: string; filename: string }) => void
   onPhaseUpdated?: (data: { goal_id: string; phase: number; status: string; project?: string }) => void
+  onResync?: () => void // Events were missed while disconnected; reload state
 }
 
 const RECONNECT_DELAY = 3000 // 3 seconds
@@ -23,6 +28,7 @@ export function useSSE(handlers: SSEHandlers) {
   const eventSourceRef = useRef<EventSource | null>(null)
   const reconnectTimeoutRef = useRef<number | null>(null)
   const wasConnectedRef = useRef(false)
+  const lastEventIdRef = useRef<string | null>(null)
 
   // Keep handlers ref updated
   useEffect(() => {
@@ -30,15 +36,32 @@ export function useSSE(handlers: SSEHandlers) {
   }, [handlers])
 
   const connect = useCallback(() => {
+    // Snapshots have no event stream
+    if (isSnapshot) return
+
     // Clean up existing connection
     if (eventSourceRef.current) {
       eventSourceRef.current.close()
     }
 
-    const eventSource = new EventSource('/api/events')
+    // Resume after the last event we saw so missed events are replayed
+    const url = lastEventIdRef.current
+      ? `/api/events?last_event_id=${encodeURIComponent(lastEventIdRef.current)}`
+      : '/api/events'
+    const eventSource = new EventSource(url)
     eventSourceRef.current = eventSource
 
-    eventSource.addEventListener('connected', () => {
+    const listen = (type: string, handler: (e: MessageEvent) => void) => {
+      eventSource.addEventListener(type, (e) => {
+        const me = e as MessageEvent
+        if (me.lastEventId) {
+          lastEventIdRef.current = me.lastEventId
+        }
+        handler(me)
+      })
+    }
+
+    listen('connected', () => {
       setConnected(true)
       // Show reconnected toast if we were previously connected
       if (wasConnectedRef.current) {
@@ -51,60 +74,85 @@ export function useSSE(handlers: SSEHandlers) {
       wasConnectedRef.current = true
     })
 
-    eventSource.addEventListener('question', (e) => {
+    listen('question_added', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onQuestion?.({ goal_id: data.goal_id, question: data.question })
     })
 
-    eventSource.addEventListener('answered', (e) => {
+    listen('answered', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onAnswered?.(data)
     })
 
-    eventSource.addEventListener('executor_started', (e) => {
+    listen('executor_started', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onExecutorStarted?.(data)
     })
 
-    eventSource.addEventListener('executor_stopped', (e) => {
+    listen('executor_stopped', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onExecutorStopped?.(data)
     })
 
-    eventSource.addEventListener('goal_updated', (e) => {
+    listen('executor_progress', (e) => {
+      const data = JSON.parse(e.data)
+      handlersRef.current.onExecutorProgress?.(data)
+    })
+
+    listen('goal_updated', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onGoalUpdated?.(data)
     })
 
-    eventSource.addEventListener('registry_updated', () => {
+    listen('registry_updated', () => {
       handlersRef.current.onRegistryUpdated?.()
     })
 
-    eventSource.addEventListener('goal_iced', (e) => {
+    listen('goal_iced', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onGoalIced?.(data)
     })
 
-    eventSource.addEventListener('goal_completed', (e) => {
+    listen('goal_completed', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onGoalCompleted?.(data)
     })
 
-    eventSource.addEventListener('user_message', (e) => {
+    listen('user_message', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onUserMessage?.(data)
     })
 
-    eventSource.addEventListener('planning_file_received', (e) => {
+    listen('user_message_status', (e) => {
+      const data = JSON.parse(e.data)
+      handlersRef.current.onUserMessageStatus?.(data)
+    })
+
+    listen('planning_file_received', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onPlanningFileReceived?.(data)
     })
 
-    eventSource.addEventListener('phase_updated', (e) => {
+    listen('phase_updated', (e) => {
       const data = JSON.parse(e.data)
       handlersRef.current.onPhaseUpdated?.(data)
     })
 
+    // Presence changes are broadcast to whichever component shows them
+    for (const type of ['presence_joined', 'presence_left', 'presence_changed']) {
+      listen(type, (e) => {
+        window.dispatchEvent(new CustomEvent('presence_changed', { detail: JSON.parse(e.data) }))
+      })
+    }
+
+    listen('resync', () => {
+      if (handlersRef.current.onResync) {
+        handlersRef.current.onResync()
+      } else {
+        handlersRef.current.onRegistryUpdated?.()
+      }
+    })
+
     eventSource.onerror = () => {
       setConnected(false)
       eventSource.close()
@@ -1,5 +1,5 @@
 // API client for vega-hub endpoints
-import type { GoalSummary, Dependency, PlanningFile } from './types'
+import type { GoalSummary, Dependency, PlanningFile, GoalTimeline, ProjectGoals, ValidationReport, BatchAnswerResponse, Inbox, GoalFocus, ShareLink, NewShareLink, ShareAccess } from './types'
 
 const API_BASE = '/api'
 
@@ -90,8 +90,9 @@ export async function spawnExecutor(
     context?: string
     mode?: string
     meta?: boolean
+    resume?: string  // Session ID to continue, or 'last'
   } = {}
-): Promise<{ success: boolean; session_id?: string; message?: string }> {
+): Promise<{ success: boolean; session_id?: string; message?: string; resumed_from?: string }> {
   const res = await fetch(`${API_BASE}/goals/${goalId}/spawn`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
@@ -99,3 +100,130 @@ export async function spawnExecutor(
   })
   return res.json()
 }
+
+// Goal activity timeline, bucketed per hour or day in the browser's time zone
+export async function getGoalTimeline(
+  goalId: string,
+  options: { bucket?: 'hour' | 'day'; days?: number } = {}
+): Promise<GoalTimeline> {
+  const params = new URLSearchParams({ tz: Intl.DateTimeFormat().resolvedOptions().timeZone })
+  if (options.bucket) params.set('bucket', options.bucket)
+  if (options.days) params.set('days', String(options.days))
+  const res = await fetch(`${API_BASE}/goals/${goalId}/timeline?${params}`)
+  if (!res.ok) throw new Error(`Failed to fetch goal timeline: ${res.statusText}`)
+  return res.json()
+}
+
+// A project's goals by status, from the registry
+export async function getProjectGoals(project: string): Promise<ProjectGoals> {
+  const res = await fetch(`${API_BASE}/projects/${encodeURIComponent(project)}/goals`)
+  if (!res.ok) throw new Error(`Failed to fetch project goals: ${res.statusText}`)
+  return res.json()
+}
+
+// Validation API
+export async function getValidation(): Promise<ValidationReport> {
+  const res = await fetch(`${API_BASE}/validate`)
+  if (!res.ok) throw new Error(`Failed to validate: ${res.statusText}`)
+  return res.json()
+}
+
+// Answer several questions in one request; each answer succeeds or fails on its own
+export async function answerQuestions(
+  answers: { question_id: string; answer?: string; option_ids?: string[]; text?: string }[]
+): Promise<BatchAnswerResponse> {
+  const res = await fetch(`${API_BASE}/answers`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ answers }),
+  })
+  if (!res.ok) throw new Error(`Failed to answer questions: ${res.statusText}`)
+  return res.json()
+}
+
+// What needs the current user: questions, unread mentions, reviews and stuck goals
+export async function getInbox(): Promise<Inbox> {
+  const res = await fetch(`${API_BASE}/inbox`)
+  if (!res.ok) throw new Error(`Failed to fetch inbox: ${res.statusText}`)
+  return res.json()
+}
+
+export async function markMentionsRead(at?: string): Promise<Inbox> {
+  const res = await fetch(`${API_BASE}/inbox/read`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(at ? { at } : {}),
+  })
+  if (!res.ok) throw new Error(`Failed to mark mentions read: ${res.statusText}`)
+  return res.json()
+}
+
+// Focus queue: ordered instructions for the goal's next executor sessions
+export async function getGoalFocus(goalId: string): Promise<GoalFocus> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/focus`)
+  if (!res.ok) throw new Error(`Failed to fetch focus queue: ${res.statusText}`)
+  return res.json()
+}
+
+export async function addFocus(goalId: string, text: string): Promise<GoalFocus> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/focus`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ text }),
+  })
+  if (!res.ok) throw new Error(await res.text())
+  return res.json()
+}
+
+// Replace the open instructions: existing ones by id, new ones without
+export async function setFocus(
+  goalId: string,
+  items: { id?: string; text?: string }[],
+  revision?: number
+): Promise<GoalFocus> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/focus`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ items, revision }),
+  })
+  if (!res.ok) throw new Error(await res.text())
+  return res.json()
+}
+
+export async function removeFocus(goalId: string, focusId: string): Promise<GoalFocus> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/focus/${focusId}`, { method: 'DELETE' })
+  if (!res.ok) throw new Error(await res.text())
+  return res.json()
+}
+
+// Read-only share links for people without hub access
+export async function getShareLinks(goalId: string): Promise<ShareLink[]> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/shares`)
+  if (!res.ok) throw new Error(`Failed to fetch share links: ${res.statusText}`)
+  return res.json()
+}
+
+export async function createShareLink(
+  goalId: string,
+  options: { label?: string; expires_in_hours?: number } = {}
+): Promise<NewShareLink> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/shares`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(options),
+  })
+  if (!res.ok) throw new Error(await res.text())
+  return res.json()
+}
+
+export async function revokeShareLink(goalId: string, linkId: string): Promise<ShareLink> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/shares/${linkId}`, { method: 'DELETE' })
+  if (!res.ok) throw new Error(await res.text())
+  return res.json()
+}
+
+export async function getShareAccessLog(goalId: string, linkId: string): Promise<ShareAccess[]> {
+  const res = await fetch(`${API_BASE}/goals/${goalId}/shares/${linkId}/access`)
+  if (!res.ok) throw new Error(`Failed to fetch share access log: ${res.statusText}`)
+  return res.json()
+}
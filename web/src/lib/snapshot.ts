@@ -0,0 +1,29 @@
+// Static snapshots written by `vega-hub export` set this on window in their
+// index.html. Their API responses are files at <path>/index.json, and the
+// snapshot is read-only.
+declare global {
+  interface Window {
+    __VEGA_HUB_SNAPSHOT__?: boolean
+  }
+}
+
+export const isSnapshot = typeof window !== 'undefined' && window.__VEGA_HUB_SNAPSHOT__ === true
+
+// Serve /api requests from the snapshot's files: GETs read <path>/index.json
+// (query parameters are ignored), anything else is refused
+export function installSnapshotFetch() {
+  if (!isSnapshot) return
+  const fetchFile = window.fetch.bind(window)
+  window.fetch = async (input: RequestInfo | URL, init?: RequestInit) => {
+    const url = new URL(input instanceof Request ? input.url : String(input), window.location.href)
+    const method = (init?.method ?? (input instanceof Request ? input.method : 'GET')).toUpperCase()
+    const isAPI = url.pathname === '/api' || url.pathname.startsWith('/api/')
+    if (url.origin !== window.location.origin || !isAPI) {
+      return fetchFile(input, init)
+    }
+    if (method !== 'GET') {
+      return new Response('This is a read-only snapshot', { status: 405, statusText: 'Read-only snapshot' })
+    }
+    return fetchFile(`${url.pathname.replace(/\/$/, '')}/index.json`)
+  }
+}
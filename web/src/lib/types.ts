@@ -5,6 +5,8 @@ export interface Question {
   question: string
   options?: { label: string; description?: string }[]
   created_at: string
+  priority?: 'low' | 'normal' | 'high'
+  group?: string  // Related questions of the goal, answerable together
 }
 
 // Dependencies
@@ -58,7 +60,7 @@ export interface GoalSummary {
   id: string
   title: string
   projects: string[]
-  status: 'active' | 'iced' | 'completed'
+  status: 'draft' | 'active' | 'iced' | 'completed'
   phase: string
   executor_status: 'running' | 'waiting' | 'stopped' | 'idle'
   pending_questions: number
@@ -66,12 +68,47 @@ export interface GoalSummary {
   workspace_status?: 'ready' | 'missing' | 'error'
   workspace_error?: string
   completion_status?: CompletionStatus
+  review?: Review
   // Dependency fields
   is_blocked?: boolean
   blockers?: string[]  // IDs of blocking goals
   // Hierarchy fields
   parent_id?: string
   has_children?: boolean
+  // Linked external issue
+  issue_url?: string
+  tracker_id?: string
+  ci?: CIStatus
+  executor_progress?: ExecutorProgress  // Latest status its executors reported
+  priority?: number  // Backlog order of drafts, highest first
+  // Registry metadata
+  created_at?: string  // RFC 3339
+  updated_at?: string
+  created_by?: string
+  tags?: string[]
+  alias?: string  // Unique short name usable in place of the ID
+  blocked_by?: string[]
+  completed_at?: string
+  completed_by?: string
+}
+
+export interface CICheck {
+  name: string
+  state: 'passed' | 'failed' | 'pending'
+  url?: string
+}
+
+export interface CIStatus {
+  goal_id: string
+  project: string
+  branch: string
+  provider: 'github' | 'gitlab'
+  state: 'passed' | 'failed' | 'pending' | 'none'
+  commit?: string
+  url?: string
+  checks?: CICheck[]
+  checked_at: string
+  error?: string
 }
 
 export interface PhaseDetail {
@@ -86,6 +123,14 @@ export interface Executor {
   goal_id: string
   cwd: string
   started_at: string
+  progress?: ExecutorProgress
+  resumed_from?: string  // Session whose Claude conversation it continues
+}
+
+// What an executor last reported it is doing
+export interface ExecutorProgress {
+  status: string  // e.g. "running tests"
+  at: string
 }
 
 export interface BranchInfo {
@@ -110,6 +155,39 @@ export type GoalState =
   | 'iced'       // Paused/frozen
   | 'failed'     // Failed (recoverable)
   | 'conflict'   // Merge conflict detected
+  | 'changes_requested' // Reviewer asked for changes
+  | 'approved'   // Reviewer approved; ready to complete
+
+// Review is the latest reviewer decision on a goal
+export interface Review {
+  state: 'changes_requested' | 'approved'
+  reviewer?: string
+  comment?: string
+  at: string
+}
+
+// SimilarGoal is an existing goal that may duplicate a newly created one
+export interface SimilarGoal {
+  goal_id: string
+  title: string
+  projects?: string[]
+  status: 'active' | 'iced' | 'completed'
+  archived?: boolean
+  score: number // 0-1
+}
+
+// Decision is an answered question from the cross-goal decisions index
+export interface Decision {
+  goal_id: string
+  goal_title?: string
+  projects?: string[]
+  session_id?: string
+  question: string
+  answer: string
+  user?: string
+  auto_answered?: string // Rule that answered, if any
+  decided_at: string
+}
 
 export interface StateEvent {
   ts: string
@@ -124,12 +202,26 @@ export interface GoalDetail {
   id: string
   title: string
   projects: string[]
-  status: 'active' | 'iced' | 'completed'
+  status: 'draft' | 'active' | 'iced' | 'completed'
   phase: string
   overview: string
   phases: PhaseDetail[]
   acceptance: string[]
   notes: string[]
+  issue_url?: string
+  tracker_id?: string
+  ci?: CIStatus
+  priority?: number
+  created_at?: string
+  updated_at?: string
+  created_by?: string
+  tags?: string[]
+  alias?: string
+  blocked_by?: string[]
+  completed_at?: string
+  completed_by?: string
+  iced_by?: string
+  watchers?: string[]  // Users online with the goal open
   executor_status: 'running' | 'waiting' | 'stopped' | 'idle'
   pending_questions: Question[]
   active_executors: Executor[]
@@ -143,12 +235,33 @@ export interface GoalDetail {
   state?: GoalState
   state_since?: string
   state_history?: StateEvent[]
+  review?: Review
   // Completion detection
   completion_status?: CompletionStatus
   // Dependencies
   dependencies?: DependencyInfo
   // Hierarchy
   hierarchy?: HierarchyInfo
+  children_status?: ChildrenStatus
+}
+
+// ChildrenStatus aggregates the completion of an umbrella goal's children
+export interface ChildrenStatus {
+  total: number
+  done: number
+  active: number
+  iced: number
+  all_done: boolean
+  progress: number  // 0.0-1.0
+  children: {
+    goal_id: string
+    title: string
+    status: 'active' | 'iced' | 'completed'
+    state?: GoalState
+    completed_phases: number
+    total_phases: number
+    done: boolean
+  }[]
 }
 
 export interface GoalStatus {
@@ -168,6 +281,58 @@ export interface GoalStatus {
   }[]
 }
 
+// A project's goals by status (GET /api/projects/:name/goals)
+// Registry fields only: no executor or question state
+export interface ProjectGoal extends Omit<GoalSummary, 'executor_status' | 'pending_questions' | 'active_executors'> {
+  reason?: string  // Why an iced goal was iced
+  iced_by?: string
+  file?: string  // Goal file, relative to the vega-missile directory
+}
+
+export interface ProjectGoals {
+  project: string
+  active: ProjectGoal[]
+  iced: ProjectGoal[]
+  completed: ProjectGoal[]  // Newest first
+  drafts: ProjectGoal[]  // Backlog order
+}
+
+// A goal's activity in one hour or day (GET /api/goals/:id/timeline)
+export interface TimelineBucket {
+  start: string
+  state_changes: number
+  sessions: number
+  questions: number
+  commits: number
+}
+
+export interface GoalTimeline {
+  goal_id: string
+  bucket: 'hour' | 'day'
+  start?: string  // First and last bucket; buckets without activity are left out
+  end?: string
+  buckets: TimelineBucket[]
+  totals: TimelineBucket
+}
+
+// A problem found by GET /api/validate
+export interface ValidationFinding {
+  check: 'goal_files' | 'goal_ids' | 'registry' | 'hierarchy' | 'states'
+  severity: 'error' | 'warning'
+  goal_id?: string
+  path?: string  // Relative to the vega-missile directory
+  line?: number
+  message: string
+}
+
+export interface ValidationReport {
+  valid: boolean  // No errors; warnings alone leave it valid
+  goals: number
+  errors: number
+  warnings: number
+  findings: ValidationFinding[]
+}
+
 export interface SSEEvent {
   type: string
   data?: Record<string, unknown>
@@ -184,11 +349,26 @@ export interface Activity {
 
 export interface Project {
   name: string
+  type: 'git' | 'plain'
+  vcs?: 'git' | 'jj'
   base_branch: string
   workspace?: string
   upstream?: string
   workspace_status: 'ready' | 'missing' | 'error'
   workspace_error?: string
+  base_freshness?: BaseFreshness
+}
+
+// BaseFreshness is how far a project's base branch is behind origin
+export interface BaseFreshness {
+  project: string
+  base_branch: string
+  behind: number
+  stale: boolean
+  threshold: number
+  checked_at: string
+  fetched_at: string
+  error?: string
 }
 
 // UserMessage represents a message from a user to an executor
@@ -201,11 +381,26 @@ export interface UserMessage {
   created_at: string
 }
 
+// Attachment is a file snippet, link or uploaded file sent with an answer or message
+// Files are uploaded with POST /api/goals/:id/attachments and referenced by id
+export interface Attachment {
+  type: 'snippet' | 'link' | 'file'
+  name?: string
+  url?: string               // link target, or download URL for files
+  content?: string           // snippet text
+  language?: string
+  id?: string                // uploaded file
+  path?: string
+  size?: number
+  content_type?: string
+}
+
 // ChatMessage represents a message in the chat thread
 // Returned by GET /api/goals/:id/chat
 export interface ChatMessage {
   id: string
-  type: 'session_start' | 'session_stop' | 'question' | 'answer' | 'user_message' | 'user_message_delivered' | 'activity'
+  type: 'session_start' | 'session_resumed' | 'session_stop' | 'question' | 'answer' | 'user_message' | 'user_message_delivered' | 'activity'
+    | 'goal_completed' | 'goal_iced' | 'goal_resumed' | 'goal_cleanup' | 'goal_deleted'
   timestamp: string
   session_id: string
   goal_id: string
@@ -216,5 +411,114 @@ export interface ChatMessage {
   pending?: boolean          // true for unanswered questions
   options?: { label: string; description?: string }[]
   user?: string              // who sent (executor user, answering user)
+  answered_by?: string       // for answered questions
   stop_reason?: string       // for session_stop
+  message_id?: string        // for user_message
+  delivery?: 'pending' | 'delivered' | 'processed' | 'undelivered'  // user_message status
+  format?: 'markdown'        // content is sanitized markdown: render with raw HTML disabled
+  code_blocks?: { language?: string; code: string }[]  // fenced code blocks of content, in order
+  truncated?: boolean        // content was cut at 32 KB
+}
+
+export interface BatchAnswerResult {
+  question_id: string
+  ok: boolean
+  status: number  // What POST /api/answer/:id would have returned
+  error?: string
+  conflict?: { reason: 'claimed' | 'answered'; user?: string; at: string }
+}
+
+export interface BatchAnswerResponse {
+  results: BatchAnswerResult[]
+  answered: number
+  failed: number
+}
+
+export interface InboxMention {
+  id: string
+  goal_id: string
+  goal_title?: string
+  parent_id?: string
+  user?: string
+  content: string
+  mentions?: string[]
+  created_at: string
+}
+
+export interface InboxGoal {
+  id: string
+  title: string
+  owner?: string
+  state: string
+  since?: string
+  status: 'unreviewed' | 'changes_done'
+}
+
+export interface Inbox {
+  user: string
+  generated_at: string
+  questions: (Question & { reason: 'assigned' | 'owned_goal' | 'unassigned'; goal_title?: string })[]
+  mentions: InboxMention[]  // Unread, newest first
+  mentions_read_at?: string
+  review: InboxGoal[]
+  stuck_goals: { goal_id: string; state: string; since: string; duration: number; threshold: number }[]
+}
+
+export interface FocusItem {
+  id: string  // "f-1", "f-2", ...
+  text: string
+  user?: string
+  created_at: string
+  session_id?: string  // Last session whose context pack had it
+  done: boolean
+  done_at?: string
+  done_by?: string
+}
+
+export interface GoalFocus {
+  goal_id: string
+  revision: number
+  items: FocusItem[]  // Open instructions in order, then done ones
+  next_id: number
+}
+
+export interface ShareLink {
+  id: string
+  goal_id: string
+  label?: string
+  created_by?: string
+  created_at: string
+  expires_at: string
+  revoked_at?: string
+  revoked_by?: string
+  accesses: number
+  last_access_at?: string
+}
+
+// Only returned when the link is created
+export interface NewShareLink extends ShareLink {
+  token: string
+  url: string
+}
+
+export interface ShareAccess {
+  link_id: string
+  goal_id: string
+  at: string
+  view: 'detail' | 'chat'
+  remote_addr?: string
+  user_agent?: string
+}
+
+// GET /api/health/ready
+export interface DependencyStatus {
+  name: 'vega_dir' | 'git' | 'store' | 'watcher'
+  status: 'ok' | 'down'
+  error?: string
+}
+
+export interface ReadinessReport {
+  ready: boolean
+  dependencies: DependencyStatus[]
+  checked_at: string
 }
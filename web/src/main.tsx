@@ -2,6 +2,9 @@ import React from 'react'
 import ReactDOM from 'react-dom/client'
 import App from './App'
 import './index.css'
+import { installSnapshotFetch } from './lib/snapshot'
+
+installSnapshotFetch()
 
 ReactDOM.createRoot(document.getElementById('root')!).render(
   <React.StrictMode>